@@ -86,6 +86,20 @@ func initCommands(
 
 	wd := workingDir(originalWorkingDir, os.Getenv("TF_DATA_DIR"))
 
+	var auditLog *cliconfig.ConfigAuditLog
+	if len(config.AuditLog) > 0 {
+		auditLog = config.AuditLog[0]
+	}
+
+	// The CLI configuration was already validated, so the operation timeout
+	// is either valid or unset.
+	operationTimeout, _ := config.OperationTimeoutDuration()
+
+	var policyConfig *cliconfig.ConfigPolicy
+	if len(config.Policy) > 0 {
+		policyConfig = config.Policy[0]
+	}
+
 	meta := command.Meta{
 		WorkingDir: wd,
 		Streams:    streams,
@@ -103,6 +117,12 @@ func initCommands(
 		PluginCacheDir:      config.PluginCacheDir,
 
 		PluginCacheMayBreakDependencyLockFile: config.PluginCacheMayBreakDependencyLockFile,
+		ProviderProcessReuse:                  config.ProviderProcessReuse,
+		OperationTimeout:                      operationTimeout,
+		AuditLog:                              auditLog,
+		Policy:                                policyConfig,
+		PolicyChecks:                          config.PolicyChecks,
+		Notifications:                         config.Notifications,
 
 		ShutdownCh:    makeShutdownCh(),
 		CallerContext: ctx,
@@ -127,6 +147,24 @@ func initCommands(
 			}, nil
 		},
 
+		"completion": func() (cli.Command, error) {
+			return &command.CompletionCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"config": func() (cli.Command, error) {
+			return &command.ConfigCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"config schema": func() (cli.Command, error) {
+			return &command.ConfigSchemaCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"console": func() (cli.Command, error) {
 			return &command.ConsoleCommand{
 				Meta: meta,
@@ -175,6 +213,12 @@ func initCommands(
 			}, nil
 		},
 
+		"explain": func() (cli.Command, error) {
+			return &command.ExplainCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"fmt": func() (cli.Command, error) {
 			return &command.FmtCommand{
 				Meta: meta,
@@ -199,12 +243,24 @@ func initCommands(
 			}, nil
 		},
 
+		"import discover": func() (cli.Command, error) {
+			return &command.ImportDiscoverCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"init": func() (cli.Command, error) {
 			return &command.InitCommand{
 				Meta: meta,
 			}, nil
 		},
 
+		"invoke": func() (cli.Command, error) {
+			return &command.InvokeCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"login": func() (cli.Command, error) {
 			return &command.LoginCommand{
 				Meta: meta,
@@ -229,6 +285,47 @@ func initCommands(
 			}, nil
 		},
 
+		"multi": func() (cli.Command, error) {
+			return &command.MultiCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"multi init": func() (cli.Command, error) {
+			return &command.MultiRunCommand{
+				Meta:      meta,
+				Operation: "init",
+			}, nil
+		},
+
+		"multi plan": func() (cli.Command, error) {
+			return &command.MultiRunCommand{
+				Meta:      meta,
+				Operation: "plan",
+			}, nil
+		},
+
+		"multi apply": func() (cli.Command, error) {
+			return &command.MultiRunCommand{
+				Meta:      meta,
+				Operation: "apply",
+			}, nil
+		},
+
+		"multi destroy": func() (cli.Command, error) {
+			return &command.MultiRunCommand{
+				Meta:      meta,
+				Operation: "destroy",
+			}, nil
+		},
+
+		"multi output": func() (cli.Command, error) {
+			return &command.MultiRunCommand{
+				Meta:      meta,
+				Operation: "output",
+			}, nil
+		},
+
 		"output": func() (cli.Command, error) {
 			return &command.OutputCommand{
 				Meta: meta,
@@ -247,6 +344,12 @@ func initCommands(
 			}, nil
 		},
 
+		"providers check": func() (cli.Command, error) {
+			return &command.ProvidersCheckCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"providers lock": func() (cli.Command, error) {
 			return &command.ProvidersLockCommand{
 				Meta: meta,
@@ -277,6 +380,30 @@ func initCommands(
 			}, nil
 		},
 
+		"registry": func() (cli.Command, error) {
+			return &command.RegistryCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"registry publish": func() (cli.Command, error) {
+			return &command.RegistryPublishCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"rollback": func() (cli.Command, error) {
+			return &command.RollbackCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"search": func() (cli.Command, error) {
+			return &command.SearchCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		"show": func() (cli.Command, error) {
 			return &command.ShowCommand{
 				Meta: meta,
@@ -352,6 +479,42 @@ func initCommands(
 			}, nil
 		},
 
+		"workspace update": func() (cli.Command, error) {
+			return &command.WorkspaceUpdateCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"workspace diff": func() (cli.Command, error) {
+			return &command.WorkspaceDiffCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"workspace vars": func() (cli.Command, error) {
+			return &command.WorkspaceVarsCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"workspace vars set": func() (cli.Command, error) {
+			return &command.WorkspaceVarsSetCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"workspace vars list": func() (cli.Command, error) {
+			return &command.WorkspaceVarsListCommand{
+				Meta: meta,
+			}, nil
+		},
+
+		"workspace vars rm": func() (cli.Command, error) {
+			return &command.WorkspaceVarsRmCommand{
+				Meta: meta,
+			}, nil
+		},
+
 		//-----------------------------------------------------------
 		// Plumbing
 		//-----------------------------------------------------------
@@ -27,6 +27,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/format"
 	"github.com/opentofu/opentofu/internal/didyoumean"
 	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/i18n"
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/terminal"
 	"github.com/opentofu/opentofu/version"
@@ -71,24 +72,6 @@ func realMain() int {
 
 	var err error
 
-	err = openTelemetryInit()
-	if err != nil {
-		// openTelemetryInit can only fail if OpenTofu was run with an
-		// explicit environment variable to enable telemetry collection,
-		// so in typical use we cannot get here.
-		Ui.Error(fmt.Sprintf("Could not initialize telemetry: %s", err))
-		Ui.Error(fmt.Sprintf("Unset environment variable %s if you don't intend to collect telemetry from OpenTofu.", openTelemetryExporterEnvVar))
-		return 1
-	}
-	var ctx context.Context
-	var otelSpan trace.Span
-	{
-		// At minimum we emit a span covering the entire command execution.
-		_, displayArgs := shquot.POSIXShellSplit(os.Args)
-		ctx, otelSpan = tracer.Start(context.Background(), fmt.Sprintf("tofu %s", displayArgs))
-		defer otelSpan.End()
-	}
-
 	tmpLogPath := os.Getenv(envTmpLogPath)
 	if tmpLogPath != "" {
 		f, err := os.OpenFile(tmpLogPath, os.O_RDWR|os.O_APPEND, 0666)
@@ -114,6 +97,10 @@ func realMain() int {
 		log.Printf("[INFO] This build of OpenTofu allows using experimental features")
 	}
 
+	if err := i18n.SetLanguage(os.Getenv(i18n.EnvLanguage)); err != nil {
+		log.Printf("[WARN] Ignoring %s: %s", i18n.EnvLanguage, err)
+	}
+
 	streams, err := terminal.Init()
 	if err != nil {
 		Ui.Error(fmt.Sprintf("Failed to configure the terminal: %s", err))
@@ -242,6 +229,32 @@ func realMain() int {
 		}
 	}
 
+	// Telemetry is initialized only now, because the CLI configuration can
+	// configure it differently for each working directory.
+	var telemetryConfig *cliconfig.ConfigTelemetry
+	if len(config.Telemetry) > 0 {
+		wd, _ := os.Getwd()
+		telemetryConfig = config.Telemetry[0].ForDir(wd)
+	}
+	otelShutdown, err := openTelemetryInit(telemetryConfig)
+	if err != nil {
+		// openTelemetryInit can only fail if OpenTofu was run with an
+		// explicit setting to enable telemetry collection, so in typical
+		// use we cannot get here.
+		Ui.Error(fmt.Sprintf("Could not initialize telemetry: %s", err))
+		Ui.Error(fmt.Sprintf("Unset environment variables %s and %s, and remove the telemetry block from the CLI configuration, if you don't intend to collect telemetry from OpenTofu.", openTelemetryExporterEnvVar, openTelemetryMetricsExporterEnvVar))
+		return 1
+	}
+	defer otelShutdown()
+	var ctx context.Context
+	var otelSpan trace.Span
+	{
+		// At minimum we emit a span covering the entire command execution.
+		_, displayArgs := shquot.POSIXShellSplit(os.Args)
+		ctx, otelSpan = tracer.Start(context.Background(), fmt.Sprintf("tofu %s", displayArgs))
+		defer otelSpan.End()
+	}
+
 	// In tests, Commands may already be set to provide mock commands
 	if commands == nil {
 		// Commands get to hold on to the original working directory here,
@@ -7,16 +7,21 @@ package main
 
 import (
 	"context"
+	"log"
 	"os"
+	"time"
 
-	"go.opentelemetry.io/contrib/exporters/autoexport"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
 	"go.opentelemetry.io/otel/trace"
 
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
+	"github.com/opentofu/opentofu/internal/telemetry"
 	"github.com/opentofu/opentofu/version"
 )
 
@@ -31,6 +36,13 @@ import (
 // better based on experience with this experiment.
 const openTelemetryExporterEnvVar = "OTEL_TRACES_EXPORTER"
 
+// If this environment variable is set to "otlp" when running OpenTofu CLI
+// then we'll also export metrics about the command, using the same OTLP
+// exporter configuration as for traces.
+//
+// This is subject to the same caveats as openTelemetryExporterEnvVar.
+const openTelemetryMetricsExporterEnvVar = "OTEL_METRICS_EXPORTER"
+
 // tracer is the OpenTelemetry tracer to use for traces in package main only.
 var tracer trace.Tracer
 
@@ -38,56 +50,138 @@ func init() {
 	tracer = otel.Tracer("github.com/opentofu/opentofu")
 }
 
-// openTelemetryInit initializes the optional OpenTelemetry exporter.
+// openTelemetryInit initializes the optional OpenTelemetry exporters, and
+// returns a function that flushes any telemetry not yet exported, which must
+// be called before OpenTofu exits.
 //
 // By default we don't export telemetry information at all, since OpenTofu is
 // a CLI tool and so we don't assume we're running in an environment with
 // a telemetry collector available.
 //
 // However, for those running OpenTofu in automation we allow setting
-// the standard OpenTelemetry environment variable OTEL_TRACES_EXPORTER=otlp
-// to enable an OTLP exporter, which is in turn configured by all of the
-// standard OTLP exporter environment variables:
+// the standard OpenTelemetry environment variables OTEL_TRACES_EXPORTER=otlp
+// and OTEL_METRICS_EXPORTER=otlp to enable OTLP exporters, which are in turn
+// configured by all of the standard OTLP exporter environment variables:
 //
 //	https://opentelemetry.io/docs/specs/otel/protocol/exporter/#configuration-options
 //
+// The same settings can be made in the telemetry block of the CLI
+// configuration, given here already resolved for the working directory, with
+// the environment variables taking precedence.
+//
 // We don't currently support any other telemetry export protocols, because
 // OTLP has emerged as a de-facto standard and each other exporter we support
 // means another relatively-heavy external dependency. OTLP happens to use
 // protocol buffers and gRPC, which OpenTofu would depend on for other reasons
 // anyway.
-func openTelemetryInit() error {
-	// We'll check the environment variable ourselves first, because the
-	// "autoexport" helper we're about to use is built under the assumption
-	// that exporting should always be enabled and so will expect to find
-	// an OTLP server on localhost if no environment variables are set at all.
-	if os.Getenv(openTelemetryExporterEnvVar) != "otlp" {
-		return nil // By default we just discard all telemetry calls
+func openTelemetryInit(config *cliconfig.ConfigTelemetry) (func(), error) {
+	if config == nil {
+		config = &cliconfig.ConfigTelemetry{}
+	}
+	if config.Disabled {
+		log.Printf("[DEBUG] OpenTelemetry export is disabled in the CLI configuration")
+		return func() {}, nil
+	}
+
+	// We check whether the exporters are enabled ourselves, because the
+	// OTLP exporters are built under the assumption that exporting should
+	// always be enabled and so will expect to find an OTLP server on
+	// localhost if no environment variables are set at all.
+	tracesEnabled := openTelemetryExporterEnabled(openTelemetryExporterEnvVar, config.Traces)
+	metricsEnabled := openTelemetryExporterEnabled(openTelemetryMetricsExporterEnvVar, config.Metrics)
+	if !tracesEnabled && !metricsEnabled {
+		return func() {}, nil // By default we just discard all telemetry calls
 	}
 
-	otelResource := resource.NewWithAttributes(
-		semconv.SchemaURL,
-		semconv.ServiceNameKey.String("OpenTofu CLI"),
-		semconv.ServiceVersionKey.String(version.Version),
+	// The attributes from the CLI configuration and then from the standard
+	// OTEL_RESOURCE_ATTRIBUTES environment variable override the defaults.
+	var attrs []attribute.KeyValue
+	for k, v := range config.ResourceAttributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+	otelResource, err := resource.New(context.Background(),
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithAttributes(
+			semconv.ServiceNameKey.String("OpenTofu CLI"),
+			semconv.ServiceVersionKey.String(version.Version),
+		),
+		resource.WithAttributes(attrs...),
+		resource.WithFromEnv(),
 	)
+	if err != nil {
+		return nil, err
+	}
+
+	otlpConfig := telemetry.OTLPConfig{
+		Endpoint: config.Endpoint,
+		Protocol: config.Protocol,
+		Headers:  config.Headers,
+	}
+
+	if tracesEnabled {
+		exp, err := telemetry.NewOTLPSpanExporter(context.Background(), otlpConfig)
+		if err != nil {
+			return nil, err
+		}
+		otel.SetTracerProvider(openTelemetryTracerProvider(config, exp, otelResource))
 
-	// If the environment variable was set to explicitly enable telemetry
-	// then we'll enable it, using the "autoexport" library to automatically
-	// handle the details based on the other OpenTelemetry standard environment
-	// variables.
-	exp, err := autoexport.NewSpanExporter(context.Background())
+		pgtr := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+		otel.SetTextMapPropagator(pgtr)
+	}
+
+	if !metricsEnabled {
+		return func() {}, nil
+	}
+
+	// Metrics are exported periodically as configured by the standard
+	// OTEL_METRIC_EXPORT_INTERVAL environment variable, and then once more
+	// when the provider shuts down. Most OpenTofu commands finish before
+	// the first periodic export, so usually they're only exported once.
+	exp, err := telemetry.NewOTLPMetricExporter(context.Background(), otlpConfig)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	sp := sdktrace.NewSimpleSpanProcessor(exp)
-	provider := sdktrace.NewTracerProvider(
-		sdktrace.WithSpanProcessor(sp),
-		sdktrace.WithResource(otelResource),
+	provider := sdkmetric.NewMeterProvider(
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp)),
+		sdkmetric.WithResource(otelResource),
 	)
-	otel.SetTracerProvider(provider)
+	otel.SetMeterProvider(provider)
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := provider.Shutdown(ctx); err != nil {
+			log.Printf("[WARN] Failed to export OpenTelemetry metrics: %s", err)
+		}
+	}, nil
+}
 
-	pgtr := propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
-	otel.SetTextMapPropagator(pgtr)
+// openTelemetryTracerProvider returns a tracer provider that sends spans to
+// the given exporter.
+func openTelemetryTracerProvider(config *cliconfig.ConfigTelemetry, exp sdktrace.SpanExporter, otelResource *resource.Resource) *sdktrace.TracerProvider {
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(exp)),
+		sdktrace.WithResource(otelResource),
+	}
+	// The SDK applies the standard OTEL_TRACES_SAMPLER environment variable
+	// before any options, so an explicit sampler would silently override it.
+	// We only use the sampling ratio from the CLI configuration if the
+	// environment variable is unset, so that the environment variable
+	// takes precedence as for all of the other settings.
+	if config.SamplingRatio != nil && os.Getenv("OTEL_TRACES_SAMPLER") == "" {
+		opts = append(opts, sdktrace.WithSampler(
+			sdktrace.ParentBased(sdktrace.TraceIDRatioBased(*config.SamplingRatio)),
+		))
+	}
+	return sdktrace.NewTracerProvider(opts...)
+}
 
-	return nil
+// openTelemetryExporterEnabled returns true if the given exporter environment
+// variable is set to "otlp", or if it's unset and the exporter is enabled in
+// the CLI configuration.
+func openTelemetryExporterEnabled(envVar string, configured *bool) bool {
+	if v := os.Getenv(envVar); v != "" {
+		return v == "otlp"
+	}
+	return configured != nil && *configured
 }
@@ -0,0 +1,61 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
+)
+
+func TestOpenTelemetryTracerProvider_samplingRatio(t *testing.T) {
+	zero := 0.0
+
+	tests := map[string]struct {
+		envSampler string
+		want       bool
+	}{
+		"sampling ratio from the CLI configuration": {
+			envSampler: "",
+			want:       false,
+		},
+		"environment variable takes precedence": {
+			envSampler: "always_on",
+			want:       true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// t.Setenv restores the original value after the test, even if
+			// we then unset it.
+			t.Setenv("OTEL_TRACES_SAMPLER", test.envSampler)
+			if test.envSampler == "" {
+				os.Unsetenv("OTEL_TRACES_SAMPLER")
+			}
+
+			exp := tracetest.NewInMemoryExporter()
+			config := &cliconfig.ConfigTelemetry{SamplingRatio: &zero}
+			provider := openTelemetryTracerProvider(config, exp, resource.Empty())
+			defer provider.Shutdown(context.Background())
+
+			_, span := provider.Tracer("test").Start(context.Background(), "test")
+			span.End()
+
+			if got := span.SpanContext().IsSampled(); got != test.want {
+				t.Errorf("wrong sampling decision %t; want %t", got, test.want)
+			}
+			if got := len(exp.GetSpans()) != 0; got != test.want {
+				t.Errorf("wrong export decision %t; want %t", got, test.want)
+			}
+		})
+	}
+}
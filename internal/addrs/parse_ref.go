@@ -325,7 +325,8 @@ func parseRef(traversal hcl.Traversal) (*Reference, tfdiags.Diagnostics) {
 			Remaining:   traversal[1:],
 		}, diags
 
-	case "terraform":
+	case "terraform", "tofu":
+		// "tofu" is an alias for the "terraform" object.
 		name, rng, remain, diags := parseSingleAttrRef(traversal)
 		return &Reference{
 			Subject:     TerraformAttr{Name: name},
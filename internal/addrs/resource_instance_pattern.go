@@ -0,0 +1,231 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package addrs
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// AbsResourceInstancePattern is a pattern that can match zero or more
+// absolute resource instance addresses.
+//
+// Patterns use the same syntax as resource instance addresses, except that
+// resource names, module call names, and string instance keys may contain
+// "*" wildcards, and an instance key of [*] matches any instance key,
+// including the absence of a key. For example:
+//
+//	module.workers[*].aws_instance.node
+//	aws_instance.web[*]
+//	module.app.aws_instance.worker_*["eu-*"]
+//
+// As with resource instance addresses, a pattern step written without an
+// instance key matches only an instance that has no key.
+type AbsResourceInstancePattern struct {
+	Module []moduleInstanceStepPattern
+	Mode   ResourceMode
+	Type   string
+	Name   string
+	Key    instanceKeyPattern
+
+	raw string
+}
+
+type moduleInstanceStepPattern struct {
+	Name string
+	Key  instanceKeyPattern
+}
+
+// instanceKeyPattern matches an instance key. The zero value matches only
+// NoKey.
+type instanceKeyPattern struct {
+	// Any is set for the [*] pattern, which matches any key.
+	Any bool
+
+	// Exact is set for a non-wildcard key pattern.
+	Exact InstanceKey
+
+	// Glob is set for a string key pattern which contains at least one
+	// wildcard.
+	Glob string
+}
+
+func (p instanceKeyPattern) matches(key InstanceKey) bool {
+	switch {
+	case p.Any:
+		return true
+	case p.Glob != "":
+		sk, ok := key.(StringKey)
+		if !ok {
+			return false
+		}
+		matched, _ := path.Match(p.Glob, string(sk))
+		return matched
+	default:
+		return p.Exact == key
+	}
+}
+
+// IsResourceInstancePattern returns true if the given string contains
+// wildcards, and so should be parsed using ParseAbsResourceInstancePattern
+// rather than as a single resource instance address.
+func IsResourceInstancePattern(str string) bool {
+	return strings.Contains(str, "*")
+}
+
+// ParseAbsResourceInstancePattern parses the given string as a resource
+// instance pattern, as described in the documentation for
+// AbsResourceInstancePattern.
+func ParseAbsResourceInstancePattern(str string) (AbsResourceInstancePattern, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	ret := AbsResourceInstancePattern{raw: str}
+
+	steps, err := splitPatternSteps(str)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid address pattern",
+			err.Error(),
+		))
+		return ret, diags
+	}
+
+	for len(steps) >= 2 && steps[0].name == "module" && !steps[0].hasKey {
+		ret.Module = append(ret.Module, moduleInstanceStepPattern{
+			Name: steps[1].name,
+			Key:  steps[1].key,
+		})
+		steps = steps[2:]
+	}
+
+	ret.Mode = ManagedResourceMode
+	if len(steps) == 3 && steps[0].name == "data" && !steps[0].hasKey {
+		ret.Mode = DataResourceMode
+		steps = steps[1:]
+	}
+
+	if len(steps) != 2 || steps[0].hasKey {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid address pattern",
+			"A resource instance pattern must consist of an optional module path followed by a resource type and name, like module.example[*].aws_instance.web[*].",
+		))
+		return ret, diags
+	}
+	ret.Type = steps[0].name
+	ret.Name = steps[1].name
+	ret.Key = steps[1].key
+
+	return ret, diags
+}
+
+// Matches returns true if the given resource instance address is matched by
+// the receiving pattern.
+func (p AbsResourceInstancePattern) Matches(addr AbsResourceInstance) bool {
+	if len(addr.Module) != len(p.Module) {
+		return false
+	}
+	for i, step := range addr.Module {
+		sp := p.Module[i]
+		if !globMatch(sp.Name, step.Name) || !sp.Key.matches(step.InstanceKey) {
+			return false
+		}
+	}
+
+	res := addr.Resource.Resource
+	return res.Mode == p.Mode &&
+		globMatch(p.Type, res.Type) &&
+		globMatch(p.Name, res.Name) &&
+		p.Key.matches(addr.Resource.Key)
+}
+
+// String returns the pattern as it was originally written.
+func (p AbsResourceInstancePattern) String() string {
+	return p.raw
+}
+
+func globMatch(pattern, name string) bool {
+	matched, _ := path.Match(pattern, name)
+	return matched
+}
+
+type patternStep struct {
+	name   string
+	hasKey bool
+	key    instanceKeyPattern
+}
+
+func splitPatternSteps(str string) ([]patternStep, error) {
+	var steps []patternStep
+
+	rest := str
+	for {
+		end := strings.IndexAny(rest, ".[")
+		if end == -1 {
+			end = len(rest)
+		}
+		name := rest[:end]
+		if name == "" {
+			return nil, fmt.Errorf("Expected a name at %q.", rest)
+		}
+		for _, r := range name {
+			if !(r == '_' || r == '-' || r == '*' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+				return nil, fmt.Errorf("Invalid character %q in name %q.", r, name)
+			}
+		}
+		step := patternStep{name: name}
+		rest = rest[end:]
+
+		if strings.HasPrefix(rest, "[") {
+			keyEnd := strings.Index(rest, "]")
+			if keyEnd == -1 {
+				return nil, fmt.Errorf("Missing closing bracket in %q.", rest)
+			}
+			key, err := parseInstanceKeyPattern(rest[1:keyEnd])
+			if err != nil {
+				return nil, err
+			}
+			step.hasKey = true
+			step.key = key
+			rest = rest[keyEnd+1:]
+		}
+		steps = append(steps, step)
+
+		if rest == "" {
+			return steps, nil
+		}
+		if !strings.HasPrefix(rest, ".") {
+			return nil, fmt.Errorf("Expected a dot at %q.", rest)
+		}
+		rest = rest[1:]
+	}
+}
+
+func parseInstanceKeyPattern(raw string) (instanceKeyPattern, error) {
+	switch {
+	case raw == "*":
+		return instanceKeyPattern{Any: true}, nil
+	case strings.HasPrefix(raw, `"`):
+		s, err := strconv.Unquote(raw)
+		if err != nil {
+			return instanceKeyPattern{}, fmt.Errorf("Invalid string instance key %s.", raw)
+		}
+		if strings.Contains(s, "*") {
+			return instanceKeyPattern{Glob: s}, nil
+		}
+		return instanceKeyPattern{Exact: StringKey(s)}, nil
+	default:
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return instanceKeyPattern{}, fmt.Errorf("Invalid instance key [%s]: must be a non-negative whole number, a quoted string, or *.", raw)
+		}
+		return instanceKeyPattern{Exact: IntKey(n)}, nil
+	}
+}
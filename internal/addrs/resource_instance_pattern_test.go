@@ -0,0 +1,140 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package addrs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAbsResourceInstancePattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{
+			pattern: "aws_instance.web[*]",
+			matches: []string{
+				"aws_instance.web",
+				"aws_instance.web[0]",
+				`aws_instance.web["a"]`,
+			},
+			misses: []string{
+				"aws_instance.db[0]",
+				"module.a.aws_instance.web[0]",
+				"data.aws_instance.web[0]",
+			},
+		},
+		{
+			pattern: "module.workers[*].aws_instance.node",
+			matches: []string{
+				"module.workers.aws_instance.node",
+				"module.workers[1].aws_instance.node",
+				`module.workers["x"].aws_instance.node`,
+			},
+			misses: []string{
+				"module.workers[1].aws_instance.node[0]",
+				"module.workers[1].module.child.aws_instance.node",
+				"module.other[1].aws_instance.node",
+				"aws_instance.node",
+			},
+		},
+		{
+			pattern: `module.app.aws_instance.worker_*["eu-*"]`,
+			matches: []string{
+				`module.app.aws_instance.worker_a["eu-west-1"]`,
+				`module.app.aws_instance.worker_["eu-"]`,
+			},
+			misses: []string{
+				`module.app.aws_instance.worker_a["us-east-1"]`,
+				`module.app.aws_instance.worker_a[0]`,
+				`module.app.aws_instance.web["eu-west-1"]`,
+			},
+		},
+		{
+			pattern: "module.*.data.aws_ami.*",
+			matches: []string{
+				"module.a.data.aws_ami.ubuntu",
+			},
+			misses: []string{
+				"module.a.aws_ami.ubuntu",
+				"module.a[0].data.aws_ami.ubuntu",
+				"data.aws_ami.ubuntu",
+			},
+		},
+		{
+			pattern: "aws_*.web[2]",
+			matches: []string{
+				"aws_instance.web[2]",
+			},
+			misses: []string{
+				"aws_instance.web[1]",
+				`aws_instance.web["2"]`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.pattern, func(t *testing.T) {
+			if !IsResourceInstancePattern(test.pattern) {
+				t.Fatalf("not detected as a pattern")
+			}
+			pattern, diags := ParseAbsResourceInstancePattern(test.pattern)
+			if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Err())
+			}
+			if got := pattern.String(); got != test.pattern {
+				t.Errorf("wrong string representation %q", got)
+			}
+
+			for _, s := range test.matches {
+				addr, diags := ParseAbsResourceInstanceStr(s)
+				if diags.HasErrors() {
+					t.Fatalf("invalid address %q: %s", s, diags.Err())
+				}
+				if !pattern.Matches(addr) {
+					t.Errorf("pattern doesn't match %s", addr)
+				}
+			}
+			for _, s := range test.misses {
+				addr, diags := ParseAbsResourceInstanceStr(s)
+				if diags.HasErrors() {
+					t.Fatalf("invalid address %q: %s", s, diags.Err())
+				}
+				if pattern.Matches(addr) {
+					t.Errorf("pattern unexpectedly matches %s", addr)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAbsResourceInstancePattern_invalid(t *testing.T) {
+	tests := map[string]string{
+		"module.a[*]":               "must consist of an optional module path",
+		"aws_instance[*].web":       "must consist of an optional module path",
+		"aws_instance.web[*":        "Missing closing bracket",
+		"aws_instance.web[-1]":      "Invalid instance key",
+		"aws_instance.web[*]x":      "Expected a dot",
+		"aws_instance..web":         "Expected a name",
+		"aws_instance.we/b*":        "Invalid character",
+		`aws_instance.web["a*]`:     "Invalid string instance key",
+		"module.a.b.aws_instance.*": "must consist of an optional module path",
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			_, diags := ParseAbsResourceInstancePattern(input)
+			if !diags.HasErrors() {
+				t.Fatalf("unexpected success")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, want) {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
@@ -6,7 +6,8 @@
 package addrs
 
 // TerraformAttr is the address of an attribute of the "terraform" object in
-// the interpolation scope, like "terraform.workspace".
+// the interpolation scope, like "terraform.workspace". The "tofu" object is
+// an alias for the same object, so "tofu.workspace" has the same address.
 type TerraformAttr struct {
 	referenceable
 	Name string
@@ -27,6 +27,7 @@ import (
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -251,6 +252,10 @@ type Operation struct {
 	PlanOutPath    string // PlanOutPath is the path to save the plan
 	PlanOutBackend *plans.Backend
 
+	// PlanSigningKey, if set, is used to sign the plan file saved at
+	// PlanOutPath.
+	PlanSigningKey *planfile.SigningKey
+
 	// ConfigDir is the path to the directory containing the configuration's
 	// root module.
 	ConfigDir string
@@ -283,6 +288,49 @@ type Operation struct {
 	AutoApprove  bool
 	Targets      []addrs.Targetable
 	ForceReplace []addrs.AbsResourceInstance
+
+	// ForceReplacePatterns are expanded against the prior state to find
+	// additional resource instances to add to ForceReplace.
+	ForceReplacePatterns []addrs.AbsResourceInstancePattern
+
+	// AdoptExisting, when set, plans to import existing remote objects found
+	// by providers for resource instances that have no object in the state,
+	// instead of planning to create new objects.
+	AdoptExisting bool
+
+	// StrictWarnings selects the categories of warnings that the operation
+	// should treat as errors, in addition to any selected by the "strict"
+	// setting in the root module.
+	StrictWarnings []tfdiags.WarningCategory
+
+	// SnapshotDir, if set, is a directory where an apply operation saves a
+	// snapshot of the prior state before changing anything, for use by
+	// "tofu rollback".
+	SnapshotDir string
+
+	// SelectChanges, when set for an apply operation that requires
+	// interactive approval, lets the user choose which of the planned
+	// resource instance changes to apply before confirming.
+	SelectChanges bool
+
+	// ConfigSource, if set, records that the configuration in ConfigDir was
+	// fetched from a remote source. Plan and apply operations record it in
+	// the state, along with the changes they plan.
+	ConfigSource *states.ConfigSource
+
+	// Policies check the plan of a plan or apply operation once it has been
+	// created. Any verdict with error severity makes the operation fail,
+	// and in an apply operation prevents the plan from being applied.
+	Policies []policy.Checker
+
+	// RefreshFilter, if non-empty, limits refreshing to only the managed
+	// resource instances contained in at least one of the given addresses.
+	RefreshFilter []addrs.Targetable
+
+	// RefreshParallelism optionally limits the number of concurrent calls
+	// made to read the current state of remote objects for each provider.
+	RefreshParallelism map[addrs.Provider]int
+
 	// Injected by the command creating the operation (plan/apply/refresh/etc...)
 	Variables map[string]UnparsedVariableValue
 	RootCall  configs.StaticModuleCall
@@ -297,6 +345,12 @@ type Operation struct {
 	// the variables set in the plan are used instead, and they must be valid.
 	AllowUnsetVariables bool
 
+	// StateFilter, if set, limits the resources that LocalRun reads from
+	// the state to those for which it returns true. The state manager then
+	// holds a partial state that can't be persisted, so this is only for
+	// operations that don't change the state.
+	StateFilter func(addrs.AbsResource) bool
+
 	// View implements the logic for all UI interactions.
 	View views.Operation
 
@@ -389,10 +443,29 @@ type RunningOperation struct {
 	// the exit status because the plan value is not available at that point.
 	PlanEmpty bool
 
+	// ChangesApplied is populated after an Apply operation completes to note
+	// whether any of the planned changes were applied, even if the operation
+	// failed part way through. Like PlanEmpty, this is only used in the CLI
+	// to determine the exit status.
+	ChangesApplied bool
+
 	// State is the final state after the operation completed. Persisting
 	// this state is managed by the backend. This should only be read
 	// after the operation completes to avoid read/write races.
 	State *states.State
+
+	// CheckResults is populated after a Plan or Apply operation completes
+	// with the results of the checks in the configuration, as of the plan or
+	// of the apply if it went ahead. Like PlanEmpty, this is only used in the
+	// CLI, and is nil for backends that run operations remotely.
+	CheckResults *states.CheckResults
+
+	// Drifted is populated after a Plan or Apply operation has planned
+	// with the addresses of the resource instances whose remote objects
+	// were found to have changed outside of OpenTofu. Like PlanEmpty, this
+	// is only used in the CLI, and is nil for backends that run operations
+	// remotely.
+	Drifted []addrs.AbsResourceInstance
 }
 
 // OperationResult describes the result status of an operation.
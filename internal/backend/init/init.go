@@ -8,6 +8,7 @@
 package init
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/hashicorp/terraform-svchost/disco"
@@ -93,6 +94,20 @@ func Backend(name string) backend.InitFn {
 	return backends[name]
 }
 
+// Names returns the names of all of the available backends, in lexical
+// order.
+func Names() []string {
+	backendsLock.Lock()
+	defer backendsLock.Unlock()
+
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // Set sets a new backend in the list of backends. If f is nil then the
 // backend will be removed from the map. If this backend already exists
 // then it will be overwritten.
@@ -19,7 +19,9 @@ import (
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/snapshot"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -69,7 +71,8 @@ func (b *Local) opApply(
 	}
 
 	stateHook := new(StateHook)
-	op.Hooks = append(op.Hooks, stateHook)
+	appliedHook := new(appliedHook)
+	op.Hooks = append(op.Hooks, stateHook, appliedHook)
 
 	// Get our context
 	lr, _, opState, contextDiags := b.localRun(op)
@@ -131,6 +134,9 @@ func (b *Local) opApply(
 			return
 		}
 
+		runningOp.CheckResults = plan.Checks
+		runningOp.Drifted = driftedResources(plan)
+
 		trivialPlan := !plan.CanApply()
 		hasUI := op.UIOut != nil && op.UIIn != nil
 		mustConfirm := hasUI && !op.AutoApprove && !trivialPlan
@@ -152,6 +158,17 @@ func (b *Local) opApply(
 			return
 		}
 
+		// The policies are checked before asking for approval, so that a
+		// denied plan isn't offered for approval at all.
+		if !trivialPlan {
+			moreDiags = checkPolicy(stopCtx, op, policy.StageApply, lr.Config, plan, schemas)
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				op.ReportResult(runningOp, diags)
+				return
+			}
+		}
+
 		if mustConfirm {
 			var desc, query string
 			switch op.PlanMode {
@@ -188,6 +205,64 @@ func (b *Local) opApply(
 				diags = nil // reset so we won't show the same diagnostics again later
 			}
 
+			// In refresh-only mode there are no actions to choose between,
+			// so we just ask for approval as normal.
+			changes := selectableChanges(plan)
+			if op.SelectChanges && op.PlanMode != plans.RefreshOnlyMode && len(changes) != 0 {
+				skipped, moreDiags := selectChanges(stopCtx, op, changes)
+				diags = diags.Append(moreDiags)
+				if moreDiags.HasErrors() {
+					op.ReportResult(runningOp, diags)
+					return
+				}
+				if len(skipped) == len(changes) {
+					op.View.Cancelled(op.PlanMode)
+					runningOp.Result = backend.OperationFailure
+					return
+				}
+				if len(skipped) != 0 {
+					// We plan again, targeting only the approved changes, so
+					// that OpenTofu Core can check that the result is still a
+					// consistent set of actions.
+					log.Printf("[INFO] backend/local: apply planning again with %d of %d changes approved", len(changes)-len(skipped), len(changes))
+					planOpts := *lr.PlanOpts
+					planOpts.Targets = approvedTargets(changes, skipped)
+					plan, moreDiags = lr.Core.Plan(lr.Config, lr.InputState, &planOpts)
+					diags = diags.Append(moreDiags)
+					if moreDiags.HasErrors() {
+						op.ReportResult(runningOp, diags)
+						return
+					}
+					moreDiags = checkSkippedChanges(plan, skipped)
+					diags = diags.Append(moreDiags)
+					if moreDiags.HasErrors() {
+						op.ReportResult(runningOp, diags)
+						return
+					}
+
+					// The new plan is what will be applied, so the user must
+					// see it, and it must pass the policies, before they
+					// approve it. It can differ from the changes they chose,
+					// for example if a provider plans differently when fewer
+					// objects are changing.
+					runningOp.CheckResults = plan.Checks
+					runningOp.Drifted = driftedResources(plan)
+					op.View.Plan(plan, schemas)
+					moreDiags = checkPolicy(stopCtx, op, policy.StageApply, lr.Config, plan, schemas)
+					diags = diags.Append(moreDiags)
+					if moreDiags.HasErrors() {
+						op.ReportResult(runningOp, diags)
+						return
+					}
+
+					diags = diags.Append(skippedChangesWarning(skipped))
+					op.View.Diagnostics(diags)
+					diags = nil
+					desc = "OpenTofu will perform only the approved actions, as shown in the new plan above.\n" +
+						"Only 'yes' will be accepted to approve."
+				}
+			}
+
 			v, err := op.UIIn.Input(stopCtx, &tofu.InputOpts{
 				Id:          "approve",
 				Query:       "\n" + query,
@@ -233,6 +308,7 @@ func (b *Local) opApply(
 		}
 	} else {
 		plan = lr.Plan
+		runningOp.Drifted = driftedResources(plan)
 		if plan.Errored {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
@@ -247,6 +323,27 @@ func (b *Local) opApply(
 				op.View.PlannedChange(change)
 			}
 		}
+
+		if plan.CanApply() {
+			moreDiags = checkPolicy(stopCtx, op, policy.StageApply, lr.Config, plan, schemas)
+			diags = diags.Append(moreDiags)
+			if moreDiags.HasErrors() {
+				op.ReportResult(runningOp, diags)
+				return
+			}
+		}
+	}
+
+	// The source of the configuration becomes part of the new state. A
+	// saved plan already records the source it was created from.
+	if op.PlanFile == nil && plan.PriorState != nil {
+		plan.PriorState.ConfigSource = op.ConfigSource
+	}
+
+	// Save a snapshot of the prior state before we start changing anything,
+	// so that "tofu rollback" can later propose undoing this apply.
+	if op.SnapshotDir != "" && !plan.Changes.Empty() {
+		diags = diags.Append(b.saveSnapshot(op.SnapshotDir, opState, lr.InputState, plan))
 	}
 
 	// Set up our hook for continuous state updates
@@ -264,7 +361,13 @@ func (b *Local) opApply(
 		applyState, applyDiags = lr.Core.Apply(plan, lr.Config)
 	}()
 
-	if b.opWait(doneCh, stopCtx, cancelCtx, lr.Core, opState, op.View) {
+	stopped := b.opWait(doneCh, stopCtx, cancelCtx, lr.Core, opState, op.View)
+
+	// Changes to output values aren't reported to hooks, so a successful
+	// apply of a plan with changes counts as having changed something even
+	// if no resource instance changed.
+	runningOp.ChangesApplied = appliedHook.Applied() || (!stopped && !applyDiags.HasErrors() && !plan.Changes.Empty())
+	if stopped {
 		return
 	}
 	diags = diags.Append(applyDiags)
@@ -279,6 +382,8 @@ func (b *Local) opApply(
 
 	// Store the final state
 	runningOp.State = applyState
+	runningOp.CheckResults = applyState.CheckResults
+	op.View.CheckResults(applyState.CheckResults)
 	err := statemgr.WriteAndPersist(opState, applyState, schemas)
 	if err != nil {
 		// Export the state file from the state manager and assign the new
@@ -305,6 +410,35 @@ func (b *Local) opApply(
 	op.View.Diagnostics(diags)
 }
 
+// saveSnapshot saves a snapshot of the given prior state and a summary of the
+// plan about to be applied in the given directory. Failing to save a snapshot
+// does not prevent the apply, so any problems are returned as warnings.
+func (b *Local) saveSnapshot(dir string, opState statemgr.Full, prior *states.State, plan *plans.Plan) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	// We take the lineage and serial from the state manager, but the state
+	// itself from the operation, because applying a saved plan uses the
+	// prior state recorded in the plan.
+	var lineage string
+	var serial uint64
+	if current := statemgr.Export(opState); current != nil {
+		lineage, serial = current.Lineage, current.Serial
+	}
+	file := statefile.New(prior, lineage, serial)
+
+	snap, err := snapshot.Save(dir, file, plan, b.encryption, time.Now())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to save pre-apply snapshot",
+			fmt.Sprintf("OpenTofu could not save a snapshot of the state before applying, so \"tofu rollback\" will not be able to undo this apply: %s.", err),
+		))
+		return diags
+	}
+	log.Printf("[INFO] backend/local: saved pre-apply snapshot %s", snap.ID)
+	return diags
+}
+
 // backupStateForError is called in a scenario where we're unable to persist the
 // state for some reason, and will attempt to save a backup copy of the state
 // to local disk to help the user recover. This is a "last ditch effort" sort
@@ -0,0 +1,196 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// selectableChanges returns the resource instance changes in the given plan
+// that the user can choose to include or exclude when approving an apply,
+// sorted by address so that the checklist is presented in a stable order.
+func selectableChanges(plan *plans.Plan) []*plans.ResourceInstanceChangeSrc {
+	var ret []*plans.ResourceInstanceChangeSrc
+	for _, change := range plan.Changes.Resources {
+		if change.Action == plans.NoOp && change.Importing == nil {
+			continue
+		}
+		ret = append(ret, change)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if !ret[i].Addr.Equal(ret[j].Addr) {
+			return ret[i].Addr.Less(ret[j].Addr)
+		}
+		return ret[i].DeposedKey < ret[j].DeposedKey
+	})
+	return ret
+}
+
+// selectChanges presents the user with a checklist of the planned resource
+// instance changes and asks which of them to skip.
+//
+// It returns the changes the user chose to skip, which is empty if the user
+// approved all of them.
+func selectChanges(ctx context.Context, op *backend.Operation, changes []*plans.ResourceInstanceChangeSrc) ([]*plans.ResourceInstanceChangeSrc, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	op.UIOut.Output(renderChangeChecklist(changes, nil))
+
+	v, err := op.UIIn.Input(ctx, &tofu.InputOpts{
+		Id:    "select-changes",
+		Query: "\nWhich of these changes should be skipped?",
+		Description: "Enter the numbers of any changes you don't want to apply, separated by spaces or commas.\n" +
+			"Skipped changes will remain pending. Leave blank to include every change.",
+	})
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("error asking which changes to apply: %w", err))
+		return nil, diags
+	}
+
+	skip, err := parseChangeSelection(v, len(changes))
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid change selection",
+			err.Error(),
+		))
+		return nil, diags
+	}
+	if len(skip) == 0 {
+		return nil, diags
+	}
+
+	var skipped []*plans.ResourceInstanceChangeSrc
+	for i, change := range changes {
+		if skip[i] {
+			skipped = append(skipped, change)
+		}
+	}
+	op.UIOut.Output(renderChangeChecklist(changes, skip))
+	return skipped, diags
+}
+
+// renderChangeChecklist returns a numbered checklist of the given changes,
+// with the changes whose indices are set in skip left unchecked.
+func renderChangeChecklist(changes []*plans.ResourceInstanceChangeSrc, skip map[int]bool) string {
+	var buf strings.Builder
+	buf.WriteString("\nPlanned changes:\n")
+	for i, change := range changes {
+		mark := "x"
+		if skip[i] {
+			mark = " "
+		}
+		fmt.Fprintf(&buf, "  [%s] %d. %s (%s)\n", mark, i+1, change.Addr, describeChangeAction(change))
+	}
+	return buf.String()
+}
+
+func describeChangeAction(change *plans.ResourceInstanceChangeSrc) string {
+	var action string
+	switch change.Action {
+	case plans.NoOp:
+		action = "import"
+	case plans.Create:
+		action = "create"
+	case plans.Read:
+		action = "read"
+	case plans.Update:
+		action = "update"
+	case plans.Delete:
+		action = "destroy"
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		action = "replace"
+	case plans.Forget:
+		action = "forget"
+	default:
+		action = change.Action.String()
+	}
+	if change.DeposedKey != states.NotDeposed {
+		action += fmt.Sprintf(", deposed object %s", change.DeposedKey)
+	}
+	return action
+}
+
+// parseChangeSelection parses the user's answer to the selectChanges prompt,
+// which is a list of one-based change numbers separated by spaces or commas.
+func parseChangeSelection(raw string, count int) (map[int]bool, error) {
+	fields := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+	ret := make(map[int]bool, len(fields))
+	for _, field := range fields {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > count {
+			return nil, fmt.Errorf("%q is not the number of a planned change. Enter numbers between 1 and %d.", field, count)
+		}
+		ret[n-1] = true
+	}
+	return ret, nil
+}
+
+// approvedTargets returns target addresses for each of the given changes
+// which are not also in skipped, for use in planning only the approved
+// changes.
+func approvedTargets(changes, skipped []*plans.ResourceInstanceChangeSrc) []addrs.Targetable {
+	skip := addrs.MakeSet[addrs.AbsResourceInstance]()
+	for _, change := range skipped {
+		skip.Add(change.Addr)
+	}
+	seen := addrs.MakeSet[addrs.AbsResourceInstance]()
+	var ret []addrs.Targetable
+	for _, change := range changes {
+		if skip.Has(change.Addr) || seen.Has(change.Addr) {
+			continue
+		}
+		seen.Add(change.Addr)
+		ret = append(ret, change.Addr)
+	}
+	return ret
+}
+
+// checkSkippedChanges returns an error if the given plan, created to apply
+// only the approved changes, still includes any of the skipped changes. This
+// happens when an approved change depends on a skipped one.
+func checkSkippedChanges(plan *plans.Plan, skipped []*plans.ResourceInstanceChangeSrc) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for _, change := range skipped {
+		rc := plan.Changes.ResourceInstanceDeposed(change.Addr, change.DeposedKey)
+		if rc == nil || (rc.Action == plans.NoOp && rc.Importing == nil) {
+			continue
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Skipped change is required",
+			fmt.Sprintf("The change for %s cannot be skipped, because at least one of the approved changes depends on it. Skip the dependent changes too, or approve this change.", change.Addr),
+		))
+	}
+	return diags
+}
+
+// skippedChangesWarning returns a warning listing the changes the user chose
+// not to apply, which remain pending for a future plan.
+func skippedChangesWarning(skipped []*plans.ResourceInstanceChangeSrc) tfdiags.Diagnostic {
+	var buf strings.Builder
+	for _, change := range skipped {
+		fmt.Fprintf(&buf, "\n  - %s (%s)", change.Addr, describeChangeAction(change))
+	}
+	return tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Some planned changes were skipped",
+		fmt.Sprintf("The following changes were not approved and so will not be applied. They remain pending and will be proposed again by the next plan:%s", buf.String()),
+	)
+}
@@ -10,10 +10,12 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
 
+	"github.com/mitchellh/cli"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -26,11 +28,13 @@ import (
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/initwd"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/terminal"
 	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
 )
 
 func TestLocal_applyBasic(t *testing.T) {
@@ -186,6 +190,65 @@ func TestLocal_applyEmptyDirDestroy(t *testing.T) {
 	}
 }
 
+func TestLocal_applyPolicyDenied(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of OPA")
+	}
+
+	b := TestLocal(t)
+
+	p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+	p.ApplyResourceChangeResponse = &providers.ApplyResourceChangeResponse{NewState: cty.ObjectVal(map[string]cty.Value{
+		"id":  cty.StringVal("yes"),
+		"ami": cty.StringVal("bar"),
+	})}
+
+	// The script stands in for OPA, denying any plan that creates
+	// test_instance.foo.
+	command := filepath.Join(t.TempDir(), "opa")
+	script := `#!/bin/sh
+if grep -q '"address":"test_instance.foo"'; then
+  echo '{"result":[{"expressions":[{"value":{"deny":["test_instance.foo is not allowed"],"warn":["missing tags"]}}]}]}'
+else
+  echo '{"result":[{"expressions":[{"value":{}}]}]}'
+fi
+`
+	if err := os.WriteFile(command, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	op, configCleanup, done := testOperationApply(t, "./testdata/apply")
+	defer configCleanup()
+	op.Policies = []policy.Checker{&policy.OPA{Command: command}}
+
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	if run.Result == backend.OperationSuccess {
+		t.Fatal("operation succeeded; want error")
+	}
+
+	if p.ApplyResourceChangeCalled {
+		t.Fatal("apply should not be called")
+	}
+
+	// the backend should be unlocked after a run
+	assertBackendStateUnlocked(t, b)
+
+	output := done(t)
+	if got, want := output.Stderr(), "Error: Plan denied by policy"; !strings.Contains(got, want) {
+		t.Errorf("unexpected error output:\n%s\nwant: %s", got, want)
+	}
+	if got, want := output.Stderr(), "test_instance.foo is not allowed"; !strings.Contains(got, want) {
+		t.Errorf("unexpected error output:\n%s\nwant: %s", got, want)
+	}
+	if got, want := output.Stdout(), "Warning: Policy warning"; !strings.Contains(got, want) {
+		t.Errorf("unexpected output:\n%s\nwant: %s", got, want)
+	}
+}
+
 func TestLocal_applyError(t *testing.T) {
 	b := TestLocal(t)
 
@@ -438,3 +501,106 @@ func TestApply_applyCanceledAutoApprove(t *testing.T) {
 	}
 
 }
+
+func TestLocal_applySelectChanges(t *testing.T) {
+	testCases := map[string]struct {
+		skip      string
+		approve   string
+		wantOK    bool
+		wantState string
+		wantErr   string
+	}{
+		"skip independent change": {
+			skip:    "2",
+			approve: "yes",
+			wantOK:  true,
+			wantState: `
+test_instance.a:
+  ID = yes
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+  ami = a
+test_instance.c:
+  ID = yes
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+  ami = a
+
+  Dependencies:
+    test_instance.a
+`,
+		},
+		"skip change that an approved change depends on": {
+			skip:    "1",
+			wantErr: "Skipped change is required",
+		},
+		"decline the new plan": {
+			skip:    "2",
+			approve: "no",
+			wantErr: "Apply cancelled",
+		},
+		"invalid selection": {
+			skip:    "4",
+			wantErr: "Invalid change selection",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			b := TestLocal(t)
+
+			p := TestLocalProvider(t, b, "test", applyFixtureSchema())
+			p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+				return providers.ApplyResourceChangeResponse{NewState: cty.ObjectVal(map[string]cty.Value{
+					"id":  cty.StringVal("yes"),
+					"ami": req.PlannedState.GetAttr("ami"),
+				})}
+			}
+
+			op, configCleanup, done := testOperationApply(t, "./testdata/apply-select")
+			defer configCleanup()
+			op.SelectChanges = true
+			op.UIOut = cli.NewMockUi()
+			op.UIIn = &tofu.MockUIInput{
+				InputReturnMap: map[string]string{
+					"select-changes": tc.skip,
+					"approve":        tc.approve,
+				},
+			}
+
+			run, err := b.Operation(context.Background(), op)
+			if err != nil {
+				t.Fatalf("bad: %s", err)
+			}
+			<-run.Done()
+			output := done(t)
+
+			if !tc.wantOK {
+				if run.Result == backend.OperationSuccess {
+					t.Fatal("expected operation to fail")
+				}
+				if p.ApplyResourceChangeCalled {
+					t.Error("ApplyResourceChange should not be called")
+				}
+				if got := output.All(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("expected error %q, got:\n%s", tc.wantErr, got)
+				}
+				return
+			}
+
+			if run.Result != backend.OperationSuccess {
+				t.Fatalf("operation failed\n%s", output.All())
+			}
+			checkState(t, b.StateOutPath, tc.wantState)
+			stdout := output.Stdout()
+			if got, want := stdout, "Some planned changes were skipped"; !strings.Contains(got, want) {
+				t.Errorf("output doesn't mention skipped changes\n%s", got)
+			}
+			// Both the original plan and the plan of only the approved
+			// changes must be shown, the latter before it is approved.
+			first := strings.Index(stdout, "Plan: 3 to add, 0 to change, 0 to destroy.")
+			second := strings.Index(stdout, "Plan: 2 to add, 0 to change, 0 to destroy.")
+			if first == -1 || second == -1 || second < first {
+				t.Errorf("output doesn't show the original plan and then the new plan\n%s", stdout)
+			}
+		})
+	}
+}
@@ -14,10 +14,12 @@ import (
 
 	"github.com/zclconf/go-cty/cty"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -64,7 +66,12 @@ func (b *Local) localRun(op *backend.Operation) (*backend.LocalRun, *configload.
 	}()
 
 	log.Printf("[TRACE] backend/local: reading remote state for workspace %q", op.Workspace)
-	if err := s.RefreshState(); err != nil {
+	if op.StateFilter != nil {
+		err = statemgr.RefreshPartial(s, op.StateFilter)
+	} else {
+		err = s.RefreshState()
+	}
+	if err != nil {
 		diags = diags.Append(fmt.Errorf("error loading state: %w", err))
 		return nil, nil, nil, diags
 	}
@@ -76,7 +83,12 @@ func (b *Local) localRun(op *backend.Operation) (*backend.LocalRun, *configload.
 	if v := b.ContextOpts; v != nil {
 		coreOpts = *v
 	}
-	coreOpts.UIInput = op.UIIn
+	if b.OpInput {
+		// Without this, core would prompt during the graph walk even when
+		// the user disabled input with -input=false.
+		coreOpts.UIInput = op.UIIn
+	}
+	coreOpts.AutoApprove = op.AutoApprove
 	coreOpts.Hooks = op.Hooks
 	coreOpts.Encryption = op.Encryption
 
@@ -138,6 +150,13 @@ func (b *Local) localRun(op *backend.Operation) (*backend.LocalRun, *configload.
 		}
 	}
 
+	// Strict mode turns the selected categories of warnings into errors,
+	// which then block the operation.
+	diags = diags.Strict(op.StrictWarnings)
+	if strict := ret.Config.Module.Strict; strict != nil {
+		diags = diags.Strict(strict.Categories)
+	}
+
 	return ret, configSnap, s, diags
 }
 
@@ -178,19 +197,21 @@ func (b *Local) localRunDirect(op *backend.Operation, run *backend.LocalRun, cor
 		))
 	}
 
-	var rawVariables map[string]backend.UnparsedVariableValue
+	// Values stored with the workspace have the lowest precedence, so that
+	// any value given locally overrides them.
+	rawVariables := addWorkspaceVariables(op.Variables, s.State())
 	if op.AllowUnsetVariables {
 		// Rather than prompting for input, we'll just stub out the required
 		// but unset variables with unknown values to represent that they are
 		// placeholders for values the user would need to provide for other
 		// operations.
-		rawVariables = b.stubUnsetRequiredVariables(op.Variables, config.Module.Variables)
+		rawVariables = b.stubUnsetRequiredVariables(rawVariables, config.Module.Variables)
 	} else {
 		// If interactive input is enabled, we might gather some more variable
 		// values through interactive prompts.
 		// TODO: Need to route the operation context through into here, so that
 		// the interactive prompts can be sensitive to its timeouts/etc.
-		rawVariables = b.interactiveCollectVariables(context.TODO(), op.Variables, config.Module.Variables, op.UIIn)
+		rawVariables = b.interactiveCollectVariables(context.TODO(), rawVariables, config.Module.Variables, op.UIIn)
 	}
 
 	variables, varDiags := backend.ParseVariableValues(rawVariables, config.Module.Variables)
@@ -203,6 +224,9 @@ func (b *Local) localRunDirect(op *backend.Operation, run *backend.LocalRun, cor
 		Mode:               op.PlanMode,
 		Targets:            op.Targets,
 		ForceReplace:       op.ForceReplace,
+		RefreshFilter:      op.RefreshFilter,
+		RefreshParallelism: op.RefreshParallelism,
+		AdoptExisting:      op.AdoptExisting,
 		SetVariables:       variables,
 		SkipRefresh:        op.Type != backend.OperationTypeRefresh && !op.PlanRefresh,
 		GenerateConfigPath: op.GenerateConfigOut,
@@ -222,6 +246,15 @@ func (b *Local) localRunDirect(op *backend.Operation, run *backend.LocalRun, cor
 	}
 	run.InputState = state
 
+	if len(op.ForceReplacePatterns) != 0 {
+		matched, moreDiags := expandForceReplacePatterns(op.ForceReplacePatterns, state)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			return nil, nil, diags
+		}
+		planOpts.ForceReplace = append(planOpts.ForceReplace, matched...)
+	}
+
 	tfCtx, moreDiags := tofu.NewContext(coreOpts)
 	diags = diags.Append(moreDiags)
 	if moreDiags.HasErrors() {
@@ -231,6 +264,70 @@ func (b *Local) localRunDirect(op *backend.Operation, run *backend.LocalRun, cor
 	return run, configSnap, diags
 }
 
+// expandForceReplacePatterns finds the resource instances in the given state
+// that match any of the given patterns, returning a warning that lists them
+// so that the user can review exactly which objects will be replaced.
+//
+// It is an error for a pattern to match no resource instances at all, since
+// that most likely indicates a mistake in the pattern.
+func expandForceReplacePatterns(patterns []addrs.AbsResourceInstancePattern, state *states.State) ([]addrs.AbsResourceInstance, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var ret []addrs.AbsResourceInstance
+	seen := addrs.MakeSet[addrs.AbsResourceInstance]()
+
+	var candidates []addrs.AbsResourceInstance
+	if state != nil {
+		for _, ms := range state.Modules {
+			for _, rs := range ms.Resources {
+				if rs.Addr.Resource.Mode != addrs.ManagedResourceMode {
+					continue
+				}
+				for key := range rs.Instances {
+					candidates = append(candidates, rs.Addr.Instance(key))
+				}
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Less(candidates[j])
+	})
+
+	for _, pattern := range patterns {
+		found := false
+		for _, addr := range candidates {
+			if !pattern.Matches(addr) {
+				continue
+			}
+			found = true
+			if !seen.Has(addr) {
+				seen.Add(addr)
+				ret = append(ret, addr)
+			}
+		}
+		if !found {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"No matching resource instances",
+				fmt.Sprintf("The -replace pattern %q does not match any resource instances in the current state.", pattern),
+			))
+		}
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	var buf strings.Builder
+	for _, addr := range ret {
+		fmt.Fprintf(&buf, "\n  - %s", addr)
+	}
+	diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Replacing resource instances selected by pattern",
+		fmt.Sprintf("The -replace patterns matched the following resource instances, which will be planned for replacement:%s", buf.String()),
+	))
+	return ret, diags
+}
+
 func (b *Local) localRunForPlanFile(op *backend.Operation, pf *planfile.Reader, run *backend.LocalRun, coreOpts *tofu.ContextOpts, currentStateMeta *statemgr.SnapshotMeta) (*backend.LocalRun, *configload.Snapshot, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -486,6 +583,46 @@ func (b *Local) stubUnsetRequiredVariables(existing map[string]backend.UnparsedV
 	return ret
 }
 
+// addWorkspaceVariables adds the values of the variables stored with the
+// workspace in the given state to the given map, for any variables that
+// don't already have a value in it.
+//
+// This function is guaranteed not to modify the given map, but it may return
+// the given map unchanged if no additions are required.
+func addWorkspaceVariables(existing map[string]backend.UnparsedVariableValue, state *states.State) map[string]backend.UnparsedVariableValue {
+	if state == nil || len(state.WorkspaceVariables) == 0 {
+		return existing
+	}
+
+	ret := make(map[string]backend.UnparsedVariableValue, len(existing)+len(state.WorkspaceVariables))
+	for name, v := range state.WorkspaceVariables {
+		ret[name] = unparsedWorkspaceVariableValue{Name: name, RawValue: v.Value}
+	}
+	for k, v := range existing {
+		ret[k] = v
+	}
+	return ret
+}
+
+type unparsedWorkspaceVariableValue struct {
+	Name, RawValue string
+}
+
+var _ backend.UnparsedVariableValue = unparsedWorkspaceVariableValue{}
+
+func (v unparsedWorkspaceVariableValue) ParseVariableValue(mode configs.VariableParsingMode) (*tofu.InputValue, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	val, valDiags := mode.Parse(v.Name, v.RawValue)
+	diags = diags.Append(valDiags)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return &tofu.InputValue{
+		Value:      val,
+		SourceType: tofu.ValueFromWorkspace,
+	}, diags
+}
+
 type unparsedInteractiveVariableValue struct {
 	Name, RawValue string
 }
@@ -9,16 +9,20 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/zclconf/go-cty/cty"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/initwd"
 	"github.com/opentofu/opentofu/internal/plans"
@@ -180,6 +184,7 @@ func TestLocalRun_stalePlan(t *testing.T) {
 		PreviousRunStateFile: prevStateFile,
 		StateFile:            stateFile,
 		Plan:                 plan,
+		DependencyLocks:      depsfile.NewLocks(),
 	}
 	if err := planfile.Create(planPath, planfileArgs, encryption.PlanEncryptionDisabled()); err != nil {
 		t.Fatalf("unexpected error writing planfile: %s", err)
@@ -194,11 +199,12 @@ func TestLocalRun_stalePlan(t *testing.T) {
 	stateLocker := clistate.NewLocker(0, views.NewStateLocker(arguments.ViewHuman, view))
 
 	op := &backend.Operation{
-		ConfigDir:    configDir,
-		ConfigLoader: configLoader,
-		PlanFile:     planFile,
-		Workspace:    backend.DefaultStateName,
-		StateLocker:  stateLocker,
+		ConfigDir:       configDir,
+		ConfigLoader:    configLoader,
+		PlanFile:        planFile,
+		Workspace:       backend.DefaultStateName,
+		StateLocker:     stateLocker,
+		DependencyLocks: depsfile.NewLocks(),
 	}
 
 	_, _, diags := b.LocalRun(op)
@@ -208,6 +214,7 @@ func TestLocalRun_stalePlan(t *testing.T) {
 
 	// LocalRun() unlocks the state on failure
 	assertBackendStateUnlocked(t, b)
+
 }
 
 type backendWithStateStorageThatFailsRefresh struct {
@@ -278,3 +285,74 @@ func (s *stateStorageThatFailsRefresh) RefreshState() error {
 func (s *stateStorageThatFailsRefresh) PersistState(schemas *tofu.Schemas) error {
 	return fmt.Errorf("unimplemented")
 }
+
+func TestExpandForceReplacePatterns(t *testing.T) {
+	providerAddr := addrs.AbsProviderConfig{
+		Module:   addrs.RootModule,
+		Provider: addrs.NewDefaultProvider("test"),
+	}
+	state := states.BuildState(func(s *states.SyncState) {
+		for _, raw := range []string{
+			`module.workers[0].test_instance.node`,
+			`module.workers[1].test_instance.node`,
+			`module.workers[1].test_instance.other`,
+			`test_instance.web["a"]`,
+			`data.test_data_source.node`,
+		} {
+			addr, diags := addrs.ParseAbsResourceInstanceStr(raw)
+			if diags.HasErrors() {
+				t.Fatal(diags.Err())
+			}
+			s.SetResourceInstanceCurrent(
+				addr,
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{}`),
+					Status:    states.ObjectReady,
+				},
+				providerAddr,
+			)
+		}
+	})
+
+	mustPattern := func(raw string) addrs.AbsResourceInstancePattern {
+		t.Helper()
+		p, diags := addrs.ParseAbsResourceInstancePattern(raw)
+		if diags.HasErrors() {
+			t.Fatal(diags.Err())
+		}
+		return p
+	}
+
+	got, diags := expandForceReplacePatterns([]addrs.AbsResourceInstancePattern{
+		mustPattern(`module.workers[*].test_instance.node`),
+		mustPattern(`module.workers[1].test_instance.*`),
+	}, state)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	var gotStrs []string
+	for _, addr := range got {
+		gotStrs = append(gotStrs, addr.String())
+	}
+	want := []string{
+		`module.workers[0].test_instance.node`,
+		`module.workers[1].test_instance.node`,
+		`module.workers[1].test_instance.other`,
+	}
+	if !reflect.DeepEqual(gotStrs, want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", gotStrs, want)
+	}
+	if len(diags) != 1 || diags[0].Severity() != tfdiags.Warning {
+		t.Errorf("expected a single warning listing the matches, got %#v", diags)
+	}
+
+	_, diags = expandForceReplacePatterns([]addrs.AbsResourceInstancePattern{
+		mustPattern(`test_instance.missing[*]`),
+	}, state)
+	if !diags.HasErrors() {
+		t.Fatalf("expected an error for a pattern matching nothing")
+	}
+	if got, want := diags.Err().Error(), "does not match any resource instances"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
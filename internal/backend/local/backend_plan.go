@@ -11,11 +11,13 @@ import (
 	"io"
 	"log"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/genconfig"
 	"github.com/opentofu/opentofu/internal/logging"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -135,6 +137,14 @@ func (b *Local) opPlan(
 
 	// Record whether this plan includes any side-effects that could be applied.
 	runningOp.PlanEmpty = !plan.CanApply()
+	runningOp.CheckResults = plan.Checks
+	runningOp.Drifted = driftedResources(plan)
+
+	// The source of the configuration becomes part of the new state when
+	// the plan is applied, whether now or from the saved plan.
+	if plan.PriorState != nil {
+		plan.PriorState.ConfigSource = op.ConfigSource
+	}
 
 	// Save the plan to disk
 	if path := op.PlanOutPath; path != "" {
@@ -173,6 +183,8 @@ func (b *Local) opPlan(
 			StateFile:            plannedStateFile,
 			Plan:                 plan,
 			DependencyLocks:      op.DependencyLocks,
+			Creator:              planfile.CurrentCreator(),
+			SigningKey:           op.PlanSigningKey,
 		}, op.Encryption.Plan())
 		if err != nil {
 			diags = diags.Append(tfdiags.Sourceless(
@@ -204,6 +216,13 @@ func (b *Local) opPlan(
 
 	op.View.Plan(plan, schemas)
 
+	// A plan that would fail the policies when applied fails now, so that
+	// it's caught before it's saved for review. An errored plan can't be
+	// applied anyway, so there's nothing to check.
+	if !runningOp.PlanEmpty && !plan.Errored {
+		diags = diags.Append(checkPolicy(stopCtx, op, policy.StagePlan, lr.Config, plan, schemas))
+	}
+
 	// If we've accumulated any diagnostics along the way then we'll show them
 	// here just before we show the summary and next steps. This can potentially
 	// include errors, because we intentionally try to show a partial plan
@@ -254,3 +273,15 @@ func maybeWriteGeneratedConfig(plan *plans.Plan, out string) (wroteConfig bool,
 
 	return wroteConfig, diags
 }
+
+// driftedResources returns the addresses of the resource instances whose
+// remote objects changed outside of OpenTofu since the previous run.
+func driftedResources(plan *plans.Plan) []addrs.AbsResourceInstance {
+	var ret []addrs.AbsResourceInstance
+	for _, rc := range plan.DriftedResources {
+		if rc.Action != plans.NoOp {
+			ret = append(ret, rc.Addr)
+		}
+	}
+	return ret
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/opentofu/opentofu/internal/initwd"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/terminal"
@@ -91,6 +92,64 @@ func TestLocal_planInAutomation(t *testing.T) {
 	}
 }
 
+func TestLocal_planPolicyCheck(t *testing.T) {
+	b := TestLocal(t)
+	TestLocalProvider(t, b, "test", planFixtureSchema())
+
+	checker := &testPolicyChecker{
+		result: &policy.Result{
+			Verdicts: []policy.Verdict{
+				{
+					Severity: policy.SeverityError,
+					Summary:  "Over budget",
+					Detail:   "Adds $420 per month.",
+					Address:  "test_instance.foo",
+				},
+			},
+		},
+	}
+
+	op, configCleanup, done := testOperationPlan(t, "./testdata/plan")
+	defer configCleanup()
+	op.Policies = []policy.Checker{checker}
+
+	run, err := b.Operation(context.Background(), op)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	<-run.Done()
+	if run.Result == backend.OperationSuccess {
+		t.Fatal("operation succeeded; want error")
+	}
+
+	if checker.req == nil {
+		t.Fatal("policy checker should be called")
+	}
+	if got, want := checker.req.Stage, policy.StagePlan; got != want {
+		t.Errorf("wrong stage %q; want %q", got, want)
+	}
+	if got, want := string(checker.req.Plan), `"address":"test_instance.foo"`; !strings.Contains(got, want) {
+		t.Errorf("plan doesn't include %s:\n%s", want, got)
+	}
+
+	// the backend should be unlocked after a run
+	assertBackendStateUnlocked(t, b)
+
+	if got, want := done(t).Stderr(), "test_instance.foo: Adds $420 per month."; !strings.Contains(got, want) {
+		t.Fatalf("unexpected error output:\n%s\nwant: %s", got, want)
+	}
+}
+
+type testPolicyChecker struct {
+	req    *policy.Request
+	result *policy.Result
+}
+
+func (c *testPolicyChecker) Check(ctx context.Context, req *policy.Request) (*policy.Result, error) {
+	c.req = req
+	return c.result, nil
+}
+
 func TestLocal_planNoConfig(t *testing.T) {
 	b := TestLocal(t)
 	TestLocalProvider(t, b, "test", providers.ProviderSchema{})
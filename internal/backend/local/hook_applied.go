@@ -0,0 +1,39 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"sync/atomic"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// appliedHook is a hook that records whether any change to a resource
+// instance was applied successfully, so that an apply operation that fails
+// part way through can report that it changed something.
+type appliedHook struct {
+	tofu.NilHook
+
+	applied atomic.Bool
+}
+
+var _ tofu.Hook = (*appliedHook)(nil)
+
+func (h *appliedHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (tofu.HookAction, error) {
+	if err == nil {
+		h.applied.Store(true)
+	}
+	return tofu.HookActionContinue, nil
+}
+
+// Applied returns true if at least one change was applied successfully.
+func (h *appliedHook) Applied() bool {
+	return h.applied.Load()
+}
@@ -0,0 +1,64 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package local
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/policy"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// checkPolicy checks the given plan against the policies configured for the
+// operation, returning a diagnostic for each of their verdicts.
+//
+// The policies can only allow a plan that they were able to check, so any
+// failure to check it is an error too.
+func checkPolicy(ctx context.Context, op *backend.Operation, stage policy.Stage, config *configs.Config, plan *plans.Plan, schemas *tofu.Schemas) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if len(op.Policies) == 0 {
+		return diags
+	}
+
+	// The plan is given in the same form as the output of "tofu show -json",
+	// which includes the prior state.
+	input, err := jsonplan.Marshal(config, plan, statefile.New(plan.PriorState, "", 0), schemas)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to check policies",
+			fmt.Sprintf("Could not prepare the plan for the policies: %s.", err),
+		))
+		return diags
+	}
+	req := &policy.Request{
+		Stage: stage,
+		Plan:  input,
+	}
+
+	log.Printf("[INFO] backend/local: checking the plan against %d policy checkers", len(op.Policies))
+	for _, checker := range op.Policies {
+		result, err := checker.Check(ctx, req)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to check policies",
+				fmt.Sprintf("Could not check the plan against the policies in the CLI configuration: %s. A plan that can't be checked is treated as though it had been denied.", err),
+			))
+			continue
+		}
+		diags = diags.Append(result.Diagnostics())
+	}
+	return diags
+}
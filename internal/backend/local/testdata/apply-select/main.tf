@@ -0,0 +1,11 @@
+resource "test_instance" "a" {
+    ami = "a"
+}
+
+resource "test_instance" "b" {
+    ami = "b"
+}
+
+resource "test_instance" "c" {
+    ami = test_instance.a.ami
+}
@@ -81,6 +81,51 @@ func (b *Remote) opApply(stopCtx, cancelCtx context.Context, op *backend.Operati
 		))
 	}
 
+	if op.SelectChanges {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Selecting changes is not supported",
+			`The "remote" backend does not support the -select-changes option `+
+				`at this time.`,
+		))
+	}
+
+	if len(op.ForceReplacePatterns) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Replacement patterns are not supported",
+			`The "remote" backend does not support wildcard patterns in the -replace option `+
+				`at this time.`,
+		))
+	}
+
+	if op.AdoptExisting {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Adopting existing objects is not supported",
+			`The "remote" backend does not support the -adopt-existing option `+
+				`at this time.`,
+		))
+	}
+
+	if len(op.RefreshFilter) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Selective refresh is not supported",
+			`The "remote" backend does not support the -refresh-filter option `+
+				`at this time.`,
+		))
+	}
+
+	if len(op.RefreshParallelism) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Custom refresh parallelism values are not supported",
+			`The "remote" backend does not support the -refresh-parallelism option `+
+				`at this time.`,
+		))
+	}
+
 	if !op.HasConfig() && op.PlanMode != plans.DestroyMode {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
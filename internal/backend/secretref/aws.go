@@ -0,0 +1,217 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package secretref
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsbase "github.com/hashicorp/aws-sdk-go-base/v2"
+
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/version"
+)
+
+// resolveSSM returns the decrypted value of the AWS Systems Manager
+// parameter with the given name or ARN.
+func resolveSSM(ctx context.Context, location string) (string, error) {
+	input := map[string]interface{}{
+		"Name":           location,
+		"WithDecryption": true,
+	}
+	var output struct {
+		Parameter struct {
+			Value string `json:"Value"`
+		} `json:"Parameter"`
+	}
+	err := callAWS(ctx, awsService{
+		signingName: "ssm",
+		endpointEnv: "AWS_ENDPOINT_URL_SSM",
+		target:      "AmazonSSM.GetParameter",
+	}, arnRegion(location), input, &output)
+	if err != nil {
+		return "", err
+	}
+	return output.Parameter.Value, nil
+}
+
+// resolveSecretsManager returns the value of the AWS Secrets Manager secret
+// with the given name or ARN. If the location ends with "#" and a key, the
+// secret must be a JSON object and the value of that key is returned.
+func resolveSecretsManager(ctx context.Context, location string) (string, error) {
+	id, key, hasKey := strings.Cut(location, "#")
+
+	input := map[string]interface{}{
+		"SecretId": id,
+	}
+	var output struct {
+		SecretString *string `json:"SecretString"`
+	}
+	err := callAWS(ctx, awsService{
+		signingName: "secretsmanager",
+		endpointEnv: "AWS_ENDPOINT_URL_SECRETS_MANAGER",
+		target:      "secretsmanager.GetSecretValue",
+	}, arnRegion(id), input, &output)
+	if err != nil {
+		return "", err
+	}
+	if output.SecretString == nil {
+		return "", fmt.Errorf("the secret has a binary value, but only text values are supported")
+	}
+	if !hasKey {
+		return *output.SecretString, nil
+	}
+	return jsonObjectKey(*output.SecretString, key)
+}
+
+// awsService describes how to call one operation of an AWS service that
+// uses the JSON protocol.
+type awsService struct {
+	signingName string
+	endpointEnv string
+	target      string
+}
+
+// callAWS calls the given operation with the given input, decoding the
+// response into output. The credentials and region are found in the same way
+// as for the AWS KMS key provider, from the environment and the shared
+// configuration files, unless the region is given.
+func callAWS(ctx context.Context, service awsService, region string, input, output interface{}) error {
+	cfg := &awsbase.Config{
+		CallerDocumentationURL:  "https://opentofu.org/docs/language/settings/backends/configuration",
+		CallerName:              "Backend secret reference",
+		MaxRetries:              5,
+		Region:                  region,
+		SkipCredsValidation:     true,
+		SkipRequestingAccountId: true,
+		UserAgent: awsbase.UserAgentProducts{
+			{Name: "APN", Version: "1.0"},
+			{Name: httpclient.DefaultApplicationName, Version: version.String()},
+		},
+	}
+	_, awsConfig, awsDiags := awsbase.GetAwsConfig(ctx, cfg)
+	if awsDiags.HasError() {
+		var msgs []string
+		for _, diag := range awsDiags.Errors() {
+			msgs = append(msgs, diag.Summary()+": "+diag.Detail())
+		}
+		return fmt.Errorf("invalid AWS configuration: %s", strings.Join(msgs, "; "))
+	}
+	if awsConfig.Region == "" {
+		return fmt.Errorf("no AWS region is set; set the AWS_REGION environment variable, or refer to the secret by its ARN")
+	}
+
+	creds, err := awsConfig.Credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("retrieving AWS credentials: %w", err)
+	}
+
+	body, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+	endpoint := os.Getenv(service.endpointEnv)
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://%s.%s.amazonaws.com/", service.signingName, awsConfig.Region)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", service.target)
+
+	payloadHash := sha256.Sum256(body)
+	err = v4.NewSigner().SignHTTP(ctx, creds, req, hex.EncodeToString(payloadHash[:]), service.signingName, awsConfig.Region, time.Now())
+	if err != nil {
+		return fmt.Errorf("signing AWS request: %w", err)
+	}
+
+	resp, err := awsConfig.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var awsErr struct {
+			Type        string `json:"__type"`
+			Message     string `json:"message"`
+			MessageCaps string `json:"Message"`
+		}
+		_ = json.Unmarshal(respBody, &awsErr)
+		// The type is sometimes qualified by a namespace, such as
+		// "com.amazonaws.ssm#ParameterNotFound".
+		code := awsErr.Type[strings.LastIndex(awsErr.Type, "#")+1:]
+		if code == "" {
+			code = resp.Status
+		}
+		msg := awsErr.Message
+		if msg == "" {
+			msg = awsErr.MessageCaps
+		}
+		if msg == "" {
+			return fmt.Errorf("%s %s", service.target, code)
+		}
+		return fmt.Errorf("%s %s: %s", service.target, code, msg)
+	}
+
+	if err := json.Unmarshal(respBody, output); err != nil {
+		return fmt.Errorf("invalid %s response: %w", service.target, err)
+	}
+	return nil
+}
+
+// arnRegion returns the region of the given ARN, or an empty string if it
+// isn't an ARN, so that the secret can be read from its own region.
+func arnRegion(s string) string {
+	parts := strings.SplitN(s, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[3]
+}
+
+// jsonObjectKey returns the value of the given key in the given JSON object,
+// as used for secrets that hold several values.
+func jsonObjectKey(raw, key string) (string, error) {
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &obj); err != nil {
+		return "", fmt.Errorf("the secret must be a JSON object to select the key %q", key)
+	}
+	v, ok := obj[key]
+	if !ok {
+		return "", fmt.Errorf("the secret has no key %q", key)
+	}
+	return secretString(v, key)
+}
+
+// secretString returns the given value decoded from JSON as a string,
+// accepting the primitive types that can be written as one.
+func secretString(v interface{}, key string) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case float64, bool:
+		return fmt.Sprint(v), nil
+	default:
+		return "", fmt.Errorf("the value of %q is not a string", key)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package secretref resolves references to secrets held in secret managers,
+// which can be written in place of literal values in backend configuration
+// so that access keys and tokens never appear in configuration files.
+//
+// A reference is a string made of a scheme, a colon, and the location of the
+// secret in the secret manager selected by the scheme:
+//
+//	ssm:NAME                  an AWS Systems Manager parameter, by name or ARN
+//	secretsmanager:ID[#KEY]   an AWS Secrets Manager secret, by name or ARN,
+//	                          or one key of a secret holding a JSON object
+//	vault:PATH#FIELD          a field of a Vault or OpenBao secret
+package secretref
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Resolver retrieves the secrets of one secret manager.
+type Resolver interface {
+	// Resolve returns the secret at the given location, which is a
+	// reference without its scheme.
+	Resolve(ctx context.Context, location string) (string, error)
+}
+
+// ResolverFunc is an adapter to allow the use of an ordinary function as a
+// Resolver.
+type ResolverFunc func(ctx context.Context, location string) (string, error)
+
+func (f ResolverFunc) Resolve(ctx context.Context, location string) (string, error) {
+	return f(ctx, location)
+}
+
+// defaultResolvers are the resolvers used by Resolve, by scheme.
+var defaultResolvers = map[string]Resolver{
+	"ssm":            ResolverFunc(resolveSSM),
+	"secretsmanager": ResolverFunc(resolveSecretsManager),
+	"vault":          ResolverFunc(resolveVault),
+}
+
+// Resolve returns a copy of the given configuration value with each string
+// that is a secret reference replaced by the secret it refers to. The
+// configuration is expected to be wholly known.
+//
+// Each error is reported at the path of the attribute containing the
+// reference, so the caller can use InConfigBody to associate it with the
+// configuration. Secret values are never included in diagnostics or logs.
+func Resolve(ctx context.Context, val cty.Value) (cty.Value, tfdiags.Diagnostics) {
+	return resolveWith(ctx, val, defaultResolvers)
+}
+
+func resolveWith(ctx context.Context, val cty.Value, resolvers map[string]Resolver) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// The same secret is often used by more than one attribute, such as an
+	// access key for both the state storage and the lock table, so we only
+	// retrieve each one once.
+	resolved := make(map[string]string)
+
+	ret, err := cty.Transform(val, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		if !v.IsKnown() || v.IsNull() || v.Type() != cty.String {
+			return v, nil
+		}
+		ref := v.AsString()
+		scheme, location, ok := strings.Cut(ref, ":")
+		if !ok || location == "" {
+			return v, nil
+		}
+		resolver, ok := resolvers[scheme]
+		if !ok {
+			return v, nil
+		}
+
+		if secret, ok := resolved[ref]; ok {
+			return cty.StringVal(secret), nil
+		}
+
+		log.Printf("[TRACE] secretref: resolving %s secret reference %q", scheme, location)
+		secret, err := resolver.Resolve(ctx, location)
+		if err != nil {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Failed to resolve secret reference",
+				fmt.Sprintf("OpenTofu could not retrieve the secret for the reference %q: %s.", ref, err),
+				path,
+			))
+			return v, nil
+		}
+		resolved[ref] = secret
+		return cty.StringVal(secret), nil
+	})
+	if err != nil {
+		// Our transform function never fails, so this is unreachable.
+		diags = diags.Append(err)
+		return val, diags
+	}
+	return ret, diags
+}
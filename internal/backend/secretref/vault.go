@@ -0,0 +1,78 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package secretref
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	openbao "github.com/openbao/openbao/api"
+)
+
+// resolveVault returns the value of a field of the Vault or OpenBao secret
+// at the given "PATH#FIELD" location. Secrets of version 2 of the key/value
+// secrets engine are read at their "data" path, such as "secret/data/name",
+// and the field is one of the keys stored in the secret.
+//
+// The server and token are found in the same way as for the OpenBao key
+// provider, from the BAO_ADDR and BAO_TOKEN environment variables, with
+// VAULT_ADDR and VAULT_TOKEN used if those aren't set.
+func resolveVault(ctx context.Context, location string) (string, error) {
+	path, field, ok := strings.Cut(location, "#")
+	if !ok || path == "" || field == "" {
+		return "", fmt.Errorf("a vault reference must give the path of the secret and a field of it, like \"vault:secret/data/name#field\"")
+	}
+
+	// DefaultConfig reads BAO_ADDR and some other optional env variables.
+	config := openbao.DefaultConfig()
+	if config.Error != nil {
+		return "", config.Error
+	}
+	if os.Getenv(openbao.EnvVaultAddress) == "" {
+		if addr := os.Getenv("VAULT_ADDR"); addr != "" {
+			config.Address = addr
+		}
+	}
+
+	// NewClient reads BAO_TOKEN and some other optional env variables.
+	client, err := openbao.NewClient(config)
+	if err != nil {
+		return "", fmt.Errorf("error creating OpenBao client: %w", err)
+	}
+	if client.Token() == "" {
+		if token := os.Getenv("VAULT_TOKEN"); token != "" {
+			client.SetToken(token)
+		}
+	}
+
+	secret, err := client.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	if secret == nil || secret.Data == nil {
+		return "", fmt.Errorf("there is no secret at %q", path)
+	}
+
+	data := secret.Data
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			// This is a secret of version 2 of the key/value secrets
+			// engine, which wraps the stored keys.
+			data = inner
+		}
+	}
+	v, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("the secret at %q has no field %q", path, field)
+	}
+	if n, ok := v.(interface{ String() string }); ok {
+		// Numbers are decoded as json.Number.
+		return n.String(), nil
+	}
+	return secretString(v, field)
+}
@@ -61,11 +61,12 @@ func ParseUndeclaredVariableValues(vv map[string]UnparsedVariableValue, decls ma
 			// Some users will actively ignore this warning because they use a .tfvars file
 			// across multiple configurations.
 			if seenUndeclaredInFile < 2 {
-				diags = diags.Append(tfdiags.Sourceless(
-					tfdiags.Warning,
-					"Value for undeclared variable",
-					fmt.Sprintf("The root module does not declare a variable named %q but a value was found in file %q. If you meant to use this value, add a \"variable\" block to the configuration.\n\nTo silence these warnings, use TF_VAR_... environment variables to provide certain \"global\" settings to all configurations in your organization. To reduce the verbosity of these warnings, use the -compact-warnings option.", name, val.SourceRange.Filename),
-				))
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagWarning,
+					Summary:  "Value for undeclared variable",
+					Detail:   fmt.Sprintf("The root module does not declare a variable named %q but a value was found in file %q. If you meant to use this value, add a \"variable\" block to the configuration.\n\nTo silence these warnings, use TF_VAR_... environment variables to provide certain \"global\" settings to all configurations in your organization. To reduce the verbosity of these warnings, use the -compact-warnings option.", name, val.SourceRange.Filename),
+					Extra:    tfdiags.WarningUndeclaredVarFile,
+				})
 			}
 			seenUndeclaredInFile++
 
@@ -74,6 +75,12 @@ func ParseUndeclaredVariableValues(vv map[string]UnparsedVariableValue, decls ma
 			// variables, because users will often set these globally
 			// when they are used across many (but not necessarily all)
 			// configurations.
+		case tofu.ValueFromWorkspace:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Value for undeclared variable",
+				fmt.Sprintf("A value for the variable %q is stored with the workspace, but the root module does not declare a variable of that name. To remove the stored value, run:\n  tofu workspace vars rm %s", name, name),
+			))
 		case tofu.ValueFromCLIArg:
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
@@ -97,6 +104,7 @@ func ParseUndeclaredVariableValues(vv map[string]UnparsedVariableValue, decls ma
 			Severity: hcl.DiagWarning,
 			Summary:  "Values for undeclared variables",
 			Detail:   fmt.Sprintf("In addition to the other similar warnings shown, %d other variable(s) defined without being declared.", extras),
+			Extra:    tfdiags.WarningUndeclaredVarFile,
 		})
 	}
 
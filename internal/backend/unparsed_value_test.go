@@ -110,6 +110,12 @@ func TestUnparsedValue(t *testing.T) {
 			t.Errorf("wrong summary for diagnostic 2\ngot:  %s\nwant: %s", got, want)
 		}
 
+		for i, diag := range diags {
+			if got, want := tfdiags.DiagnosticWarningCategory(diag), tfdiags.WarningUndeclaredVarFile; got != want {
+				t.Errorf("wrong warning category for diagnostic %d\ngot:  %s\nwant: %s", i, got, want)
+			}
+		}
+
 		wantVals := tofu.InputValues{
 			"undeclared0": {
 				Value:      cty.StringVal("0"),
@@ -0,0 +1,139 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tf
+
+import (
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// The terraform_shared_outputs data source reads the root output values of
+// another workspace, like terraform_remote_state, but only those that the
+// producing configuration shares with the given consumer using the
+// shared_with argument of its output blocks.
+
+func dataSourceSharedOutputsGetSchema() providers.Schema {
+	return providers.Schema{
+		Block: &configschema.Block{
+			Attributes: map[string]*configschema.Attribute{
+				"backend": {
+					Type:            cty.String,
+					Description:     "The backend that stores the state of the producing workspace, e.g. `s3` or `http`.",
+					DescriptionKind: configschema.StringMarkdown,
+					Required:        true,
+				},
+				"config": {
+					Type: cty.DynamicPseudoType,
+					Description: "The configuration of the backend. " +
+						"The object can use any arguments that would be valid " +
+						"in the equivalent `terraform { backend \"<TYPE>\" { ... } }` " +
+						"block.",
+					DescriptionKind: configschema.StringMarkdown,
+					Optional:        true,
+				},
+				"workspace": {
+					Type: cty.String,
+					Description: "The OpenTofu workspace to use, if " +
+						"the backend supports workspaces.",
+					DescriptionKind: configschema.StringMarkdown,
+					Optional:        true,
+				},
+				"consumer": {
+					Type: cty.String,
+					Description: "The name of the consumer reading the outputs, " +
+						"which is matched against the `shared_with` argument " +
+						"of each output in the producing configuration.",
+					DescriptionKind: configschema.StringMarkdown,
+					Required:        true,
+				},
+				"outputs": {
+					Type: cty.DynamicPseudoType,
+					Description: "An object containing the root-level " +
+						"outputs of the producing workspace that are shared " +
+						"with the consumer.",
+					DescriptionKind: configschema.StringMarkdown,
+					Computed:        true,
+				},
+			},
+		},
+	}
+}
+
+func dataSourceSharedOutputsValidate(cfg cty.Value) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	// The backend configuration is validated in the same way as for
+	// terraform_remote_state.
+	if cfg.GetAttr("config").IsWhollyKnown() && cfg.GetAttr("backend").IsKnown() {
+		_, _, moreDiags := getBackend(cfg, nil) // Don't need the encryption for validation here
+		diags = diags.Append(moreDiags)
+	} else {
+		configTy := cfg.GetAttr("config").Type()
+		if configTy != cty.DynamicPseudoType && !(configTy.IsObjectType() || configTy.IsMapType()) {
+			diags = diags.Append(tfdiags.AttributeValue(
+				tfdiags.Error,
+				"Invalid backend configuration",
+				"The configuration must be an object value.",
+				cty.GetAttrPath("config"),
+			))
+		}
+	}
+
+	if consumer := cfg.GetAttr("consumer"); consumer.IsKnown() && !consumer.IsNull() && consumer.AsString() == "" {
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Invalid consumer",
+			"The consumer must not be empty.",
+			cty.GetAttrPath("consumer"),
+		))
+	}
+
+	return diags
+}
+
+func dataSourceSharedOutputsRead(d cty.Value, enc encryption.StateEncryption) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	newState := make(map[string]cty.Value)
+	newState["backend"] = d.GetAttr("backend")
+	newState["config"] = d.GetAttr("config")
+	newState["workspace"] = d.GetAttr("workspace")
+	newState["consumer"] = d.GetAttr("consumer")
+
+	remoteState, moreDiags := readRemoteState(d, enc)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	if remoteState == nil {
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Unable to find remote state",
+			"No stored state was found for the given workspace in the given backend.",
+			cty.Path(nil).GetAttr("workspace"),
+		))
+		return cty.NilVal, diags
+	}
+
+	// Unlike terraform_remote_state, we only return the outputs that the
+	// producer explicitly shared with this consumer, so the rest of the
+	// state is never exposed to the consuming configuration.
+	consumer := d.GetAttr("consumer").AsString()
+	outputs := make(map[string]cty.Value)
+	if mod := remoteState.RootModule(); mod != nil {
+		for k, os := range mod.OutputValues {
+			if os.IsSharedWith(consumer) {
+				outputs[k] = os.Value
+			}
+		}
+	}
+	newState["outputs"] = cty.ObjectVal(outputs)
+
+	return cty.ObjectVal(newState), diags
+}
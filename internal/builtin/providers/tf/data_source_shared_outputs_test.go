@@ -0,0 +1,107 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tf
+
+import (
+	"testing"
+
+	"github.com/apparentlymart/go-dump/dump"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSharedOutputsSchema(t *testing.T) {
+	if err := dataSourceSharedOutputsGetSchema().Block.InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+}
+
+func TestSharedOutputs_basic(t *testing.T) {
+	config := func(consumer string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"backend": cty.StringVal("local"),
+			"config": cty.ObjectVal(map[string]cty.Value{
+				"path": cty.StringVal("./testdata/shared_outputs.tfstate"),
+			}),
+			"consumer": cty.StringVal(consumer),
+		})
+	}
+
+	var tests = map[string]struct {
+		Config      cty.Value
+		WantOutputs cty.Value
+		Err         bool
+	}{
+		"pattern": {
+			config("app-frontend"),
+			cty.ObjectVal(map[string]cty.Value{
+				"vpc_id": cty.StringVal("vpc-123"),
+			}),
+			false,
+		},
+		"name": {
+			config("billing"),
+			cty.ObjectVal(map[string]cty.Value{
+				"vpc_id":          cty.StringVal("vpc-123"),
+				"billing_account": cty.StringVal("acct-456"),
+			}),
+			false,
+		},
+		"nothing shared": {
+			config("frontend"),
+			cty.EmptyObjectVal,
+			false,
+		},
+		"empty consumer": {
+			config(""),
+			cty.NilVal,
+			true,
+		},
+		"missing state": {
+			cty.ObjectVal(map[string]cty.Value{
+				"backend": cty.StringVal("local"),
+				"config": cty.ObjectVal(map[string]cty.Value{
+					"path": cty.StringVal("./testdata/missing.tfstate"),
+				}),
+				"consumer": cty.StringVal("billing"),
+			}),
+			cty.NilVal,
+			true,
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			schema := dataSourceSharedOutputsGetSchema().Block
+			config, err := schema.CoerceValue(test.Config)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			diags := dataSourceSharedOutputsValidate(config)
+
+			var got cty.Value
+			if !diags.HasErrors() {
+				var moreDiags tfdiags.Diagnostics
+				got, moreDiags = dataSourceSharedOutputsRead(config, encryption.StateEncryptionDisabled())
+				diags = diags.Append(moreDiags)
+			}
+
+			if test.Err {
+				if !diags.HasErrors() {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if diags.HasErrors() {
+				t.Fatalf("unexpected errors: %s", diags.Err())
+			}
+
+			if gotOutputs := got.GetAttr("outputs"); !test.WantOutputs.RawEquals(gotOutputs) {
+				t.Errorf("wrong outputs\ngot:  %swant: %s", dump.Value(gotOutputs), dump.Value(test.WantOutputs))
+			}
+		})
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
 
@@ -106,45 +107,17 @@ func dataSourceRemoteStateValidate(cfg cty.Value) tfdiags.Diagnostics {
 func dataSourceRemoteStateRead(d cty.Value, enc encryption.StateEncryption) (cty.Value, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
-	b, cfg, moreDiags := getBackend(d, enc)
-	diags = diags.Append(moreDiags)
-	if moreDiags.HasErrors() {
-		return cty.NilVal, diags
-	}
-
-	configureDiags := b.Configure(cfg)
-	if configureDiags.HasErrors() {
-		diags = diags.Append(configureDiags.Err())
-		return cty.NilVal, diags
-	}
-
 	newState := make(map[string]cty.Value)
 	newState["backend"] = d.GetAttr("backend")
 	newState["config"] = d.GetAttr("config")
 
-	workspaceVal := d.GetAttr("workspace")
 	// This attribute is not computed, so we always have to store the state
 	// value, even if we implicitly use a default.
-	newState["workspace"] = workspaceVal
-
-	workspaceName := backend.DefaultStateName
-	if !workspaceVal.IsNull() {
-		workspaceName = workspaceVal.AsString()
-	}
+	newState["workspace"] = d.GetAttr("workspace")
 
-	state, err := b.StateMgr(workspaceName)
-	if err != nil {
-		diags = diags.Append(tfdiags.AttributeValue(
-			tfdiags.Error,
-			"Error loading state error",
-			fmt.Sprintf("error loading the remote state: %s", err),
-			cty.Path(nil).GetAttr("backend"),
-		))
-		return cty.NilVal, diags
-	}
-
-	if err := state.RefreshState(); err != nil {
-		diags = diags.Append(err)
+	remoteState, moreDiags := readRemoteState(d, enc)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
 		return cty.NilVal, diags
 	}
 
@@ -161,7 +134,6 @@ func dataSourceRemoteStateRead(d cty.Value, enc encryption.StateEncryption) (cty
 		newState["defaults"] = cty.NullVal(cty.DynamicPseudoType)
 	}
 
-	remoteState := state.State()
 	if remoteState == nil {
 		diags = diags.Append(tfdiags.AttributeValue(
 			tfdiags.Error,
@@ -184,6 +156,49 @@ func dataSourceRemoteStateRead(d cty.Value, enc encryption.StateEncryption) (cty
 	return cty.ObjectVal(newState), diags
 }
 
+// readRemoteState reads the latest state of the workspace that the given
+// data source configuration refers to, using its "backend", "config" and
+// "workspace" attributes. It returns a nil state without errors if the
+// workspace has no state.
+func readRemoteState(d cty.Value, enc encryption.StateEncryption) (*states.State, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	b, cfg, moreDiags := getBackend(d, enc)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+
+	configureDiags := b.Configure(cfg)
+	if configureDiags.HasErrors() {
+		diags = diags.Append(configureDiags.Err())
+		return nil, diags
+	}
+
+	workspaceName := backend.DefaultStateName
+	if workspaceVal := d.GetAttr("workspace"); !workspaceVal.IsNull() {
+		workspaceName = workspaceVal.AsString()
+	}
+
+	state, err := b.StateMgr(workspaceName)
+	if err != nil {
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Error loading state error",
+			fmt.Sprintf("error loading the remote state: %s", err),
+			cty.Path(nil).GetAttr("backend"),
+		))
+		return nil, diags
+	}
+
+	if err := state.RefreshState(); err != nil {
+		diags = diags.Append(err)
+		return nil, diags
+	}
+
+	return state.State(), diags
+}
+
 func getBackend(cfg cty.Value, enc encryption.StateEncryption) (backend.Backend, cty.Value, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
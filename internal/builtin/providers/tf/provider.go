@@ -13,6 +13,8 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/zclconf/go-cty/cty"
 )
 
 // Provider is an implementation of providers.Interface
@@ -27,7 +29,8 @@ func NewProvider() providers.Interface {
 func (p *Provider) GetProviderSchema() providers.GetProviderSchemaResponse {
 	return providers.GetProviderSchemaResponse{
 		DataSources: map[string]providers.Schema{
-			"terraform_remote_state": dataSourceRemoteStateGetSchema(),
+			"terraform_remote_state":   dataSourceRemoteStateGetSchema(),
+			"terraform_shared_outputs": dataSourceSharedOutputsGetSchema(),
 		},
 		ResourceTypes: map[string]providers.Schema{
 			"terraform_data": dataStoreResourceSchema(),
@@ -51,15 +54,16 @@ func (p *Provider) ValidateDataResourceConfig(req providers.ValidateDataResource
 	// errors in tofu validate as well as during tofu plan.
 	var res providers.ValidateDataResourceConfigResponse
 
-	// This should not happen
-	if req.TypeName != "terraform_remote_state" {
-		res.Diagnostics.Append(fmt.Errorf("Error: unsupported data source %s", req.TypeName))
-		return res
+	switch req.TypeName {
+	case "terraform_remote_state":
+		res.Diagnostics = dataSourceRemoteStateValidate(req.Config)
+	case "terraform_shared_outputs":
+		res.Diagnostics = dataSourceSharedOutputsValidate(req.Config)
+	default:
+		// This should not happen
+		res.Diagnostics = res.Diagnostics.Append(fmt.Errorf("Error: unsupported data source %s", req.TypeName))
 	}
 
-	diags := dataSourceRemoteStateValidate(req.Config)
-	res.Diagnostics = diags
-
 	return res
 }
 
@@ -80,9 +84,15 @@ func (p *Provider) ReadDataSourceEncrypted(req providers.ReadDataSourceRequest,
 	// call function
 	var res providers.ReadDataSourceResponse
 
-	// This should not happen
-	if req.TypeName != "terraform_remote_state" {
-		res.Diagnostics.Append(fmt.Errorf("Error: unsupported data source %s", req.TypeName))
+	var read func(cty.Value, encryption.StateEncryption) (cty.Value, tfdiags.Diagnostics)
+	switch req.TypeName {
+	case "terraform_remote_state":
+		read = dataSourceRemoteStateRead
+	case "terraform_shared_outputs":
+		read = dataSourceSharedOutputsRead
+	default:
+		// This should not happen
+		res.Diagnostics = res.Diagnostics.Append(fmt.Errorf("Error: unsupported data source %s", req.TypeName))
 		return res
 	}
 
@@ -91,14 +101,14 @@ func (p *Provider) ReadDataSourceEncrypted(req providers.ReadDataSourceRequest,
 
 	// data.terraform_remote_state.foo[4] -> foo[4]
 	// module.submod[1].data.terraform_remote_state.bar -> module.submod[1].bar
-	key = strings.Replace(key, "data.terraform_remote_state.", "", 1)
+	key = strings.Replace(key, "data."+req.TypeName+".", "", 1)
 
 	// module.submod[1].bar -> submod[1].bar
 	key = strings.TrimPrefix(key, "module.")
 
 	log.Printf("[DEBUG] accessing remote state at %s", key)
 
-	newState, diags := dataSourceRemoteStateRead(req.Config, enc.RemoteState(key))
+	newState, diags := read(req.Config, enc.RemoteState(key))
 
 	if diags.HasErrors() {
 		diags = diags.Append(fmt.Errorf("%s: Unable to read remote state", path.String()))
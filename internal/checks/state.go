@@ -10,6 +10,8 @@ import (
 	"sort"
 	"sync"
 
+	"github.com/hashicorp/hcl/v2"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 )
@@ -59,6 +61,10 @@ type configCheckableState struct {
 	// aren't) we can compute this only from the configuration.
 	checkTypes map[addrs.CheckRuleType]int
 
+	// ranges captures the source location of the declaration of each of the
+	// checks counted in checkTypes.
+	ranges map[addrs.CheckRuleType][]hcl.Range
+
 	// objects represents the set of dynamic checkable objects associated
 	// with this configuration construct. This is initially nil to represent
 	// that we don't know the objects yet, and is replaced by a non-nil map
@@ -273,6 +279,71 @@ func (c *State) ObjectFailureMessages(addr addrs.Checkable) []string {
 	return ret
 }
 
+// RuleResult is the result of a single check for a particular checkable
+// object.
+type RuleResult struct {
+	Rule   addrs.CheckRule
+	Status Status
+
+	// FailureMessage is the message reported for a check whose status is
+	// StatusFail, if any.
+	FailureMessage string
+
+	// DeclRange is the source location where the check was declared.
+	DeclRange hcl.Range
+}
+
+// ObjectCheckRuleResults returns the result of each of the individual checks
+// for the object with the given address, ordered by check type and then
+// by index.
+//
+// The given address must refer to a checkable object that OpenTofu Core
+// previously reported while doing a graph walk, or this method will panic.
+func (c *State) ObjectCheckRuleResults(addr addrs.Checkable) []RuleResult {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	configAddr := addr.ConfigCheckable()
+
+	st, ok := c.statuses.GetOk(configAddr)
+	if !ok {
+		panic(fmt.Sprintf("request for status of unknown object %s", addr))
+	}
+	if st.objects.Elems == nil {
+		panic(fmt.Sprintf("request for status of %s before establishing the checkable objects for %s", addr, configAddr))
+	}
+	checksByType, ok := st.objects.GetOk(addr)
+	if !ok {
+		panic(fmt.Sprintf("request for status of unknown object %s", addr))
+	}
+
+	checkTypes := make([]addrs.CheckRuleType, 0, len(checksByType))
+	for checkType := range checksByType {
+		checkTypes = append(checkTypes, checkType)
+	}
+	sort.Slice(checkTypes, func(i, j int) bool {
+		return checkTypes[i] < checkTypes[j]
+	})
+
+	var ret []RuleResult
+	for _, checkType := range checkTypes {
+		for i, status := range checksByType[checkType] {
+			result := RuleResult{
+				Rule:   addrs.NewCheckRule(addr, checkType, i),
+				Status: status,
+			}
+			if status == StatusFail {
+				result.FailureMessage = c.failureMsgs.Get(result.Rule)
+			}
+			if ranges := st.ranges[checkType]; i < len(ranges) {
+				result.DeclRange = ranges[i]
+			}
+			ret = append(ret, result)
+		}
+	}
+	return ret
+}
+
 func summarizeCheckStatuses(errorCount, failCount, unknownCount int) Status {
 	switch {
 	case errorCount > 0:
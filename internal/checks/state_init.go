@@ -6,6 +6,8 @@
 package checks
 
 import (
+	"github.com/hashicorp/hcl/v2"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 )
@@ -50,6 +52,9 @@ func collectInitialStatuses(into addrs.Map[addrs.ConfigCheckable, *configCheckab
 		st.checkTypes = map[addrs.CheckRuleType]int{
 			addrs.OutputPrecondition: ct,
 		}
+		st.ranges = map[addrs.CheckRuleType][]hcl.Range{
+			addrs.OutputPrecondition: checkRuleRanges(oc.Preconditions),
+		}
 
 		into.Put(addr, st)
 	}
@@ -61,10 +66,14 @@ func collectInitialStatuses(into addrs.Map[addrs.ConfigCheckable, *configCheckab
 			checkTypes: map[addrs.CheckRuleType]int{
 				addrs.CheckAssertion: len(c.Asserts),
 			},
+			ranges: map[addrs.CheckRuleType][]hcl.Range{
+				addrs.CheckAssertion: checkRuleRanges(c.Asserts),
+			},
 		}
 
 		if c.DataResource != nil {
 			st.checkTypes[addrs.CheckDataResource] = 1
+			st.ranges[addrs.CheckDataResource] = []hcl.Range{c.DataResource.DeclRange}
 		}
 
 		into.Put(addr, st)
@@ -82,6 +91,9 @@ func collectInitialStatuses(into addrs.Map[addrs.ConfigCheckable, *configCheckab
 		st.checkTypes = map[addrs.CheckRuleType]int{
 			addrs.InputValidation: vs,
 		}
+		st.ranges = map[addrs.CheckRuleType][]hcl.Range{
+			addrs.InputValidation: checkRuleRanges(v.Validations),
+		}
 
 		into.Put(addr, st)
 	}
@@ -101,14 +113,25 @@ func collectInitialStatusForResource(into addrs.Map[addrs.ConfigCheckable, *conf
 
 	st := &configCheckableState{
 		checkTypes: make(map[addrs.CheckRuleType]int),
+		ranges:     make(map[addrs.CheckRuleType][]hcl.Range),
 	}
 
 	if ct := len(rc.Preconditions); ct > 0 {
 		st.checkTypes[addrs.ResourcePrecondition] = ct
+		st.ranges[addrs.ResourcePrecondition] = checkRuleRanges(rc.Preconditions)
 	}
 	if ct := len(rc.Postconditions); ct > 0 {
 		st.checkTypes[addrs.ResourcePostcondition] = ct
+		st.ranges[addrs.ResourcePostcondition] = checkRuleRanges(rc.Postconditions)
 	}
 
 	into.Put(addr, st)
 }
+
+func checkRuleRanges(rules []*configs.CheckRule) []hcl.Range {
+	ret := make([]hcl.Range, len(rules))
+	for i, rule := range rules {
+		ret[i] = rule.DeclRange
+	}
+	return ret
+}
@@ -7,6 +7,7 @@ package checks
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -228,4 +229,20 @@ func TestChecksHappyPath(t *testing.T) {
 			}
 		}
 	}
+
+	{
+		results := checks.ObjectCheckRuleResults(resourceInstA)
+		var got []string
+		for _, result := range results {
+			got = append(got, fmt.Sprintf("%s %s line %d", result.Rule, result.Status, result.DeclRange.Start.Line))
+		}
+		want := []string{
+			"null_resource.a.precondition[0] StatusPass line 3",
+			"null_resource.a.precondition[1] StatusPass line 7",
+			"null_resource.a.postcondition[0] StatusPass line 11",
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("wrong check rule results for %s\n%s", resourceInstA, diff)
+		}
+	}
 }
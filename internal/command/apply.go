@@ -6,14 +6,21 @@
 package command
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"os"
 	"strings"
+	"time"
+
+	"github.com/posener/complete"
 
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -32,6 +39,7 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 
 	// Parse and apply global view arguments
 	common, rawArgs := arguments.ParseView(rawArgs)
+	rawArgs = c.Meta.processOverrideSet(rawArgs)
 	c.View.Configure(common)
 
 	// Propagate -no-color for legacy use of Ui.  The remote backend and
@@ -48,6 +56,7 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	default:
 		args, diags = arguments.ParseApply(rawArgs)
 	}
+	diags = diags.Append(common.Parse())
 
 	// Instantiate the view, even if there are flag errors, so that we render
 	// diagnostics according to the desired view
@@ -67,6 +76,11 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// Keep provider plugin processes running between the graph walks of
+	// this command, rather than starting them again for each one.
+	c.startProviderPool()
+	defer c.closeProviderPool()
+
 	// Inject variables from args into meta for static evaluation
 	c.GatherVariables(args.Vars)
 
@@ -84,6 +98,11 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		view.Diagnostics(diags)
 		return 1
 	}
+	diags = diags.Append(c.verifyPlanFile(planFile, args.VerifyKeyPath))
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
 
 	// Check for invalid combination of plan file and variable overrides
 	if planFile != nil && !args.Vars.Empty() {
@@ -96,6 +115,36 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// Refreshing happens while creating a plan, so a saved plan has already
+	// been refreshed according to whatever options were used to create it.
+	if planFile != nil && len(args.Operation.RefreshFilter) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Can't filter refreshing when applying a saved plan",
+			"The -refresh-filter option cannot be used when applying a saved plan file, because refreshing already happened when the plan was created.",
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+	if planFile != nil && args.Operation.AdoptExisting {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Can't adopt existing objects when applying a saved plan",
+			"The -adopt-existing option cannot be used when applying a saved plan file, because any objects to adopt were chosen when the plan was created.",
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+	if planFile != nil && len(args.Operation.RefreshParallelism) != 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Can't limit refresh parallelism when applying a saved plan",
+			"The -refresh-parallelism option cannot be used when applying a saved plan file, because refreshing already happened when the plan was created.",
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
 	// FIXME: the -input flag value is needed to initialize the backend and the
 	// operation, but there is no clear path to pass this value down, so we
 	// continue to mutate the Meta object state for now.
@@ -109,6 +158,32 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
 
+	// Start profiling the rest of the command, if requested
+	profileCommand := "apply"
+	if c.Destroy {
+		profileCommand = "destroy"
+	}
+	stopProfiling, profileDiags := c.startProfiling(profileCommand, args.ProfileDir)
+	diags = diags.Append(profileDiags)
+	if profileDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+	defer func() {
+		view.Diagnostics(stopProfiling())
+	}()
+
+	// Reuse the results of the previous plan, if requested
+	savePlanCache, cacheDiags := c.startPlanCache(args.Incremental, args.IncrementalReset, enc.Plan())
+	diags = diags.Append(cacheDiags)
+	if cacheDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+	defer func() {
+		view.Diagnostics(savePlanCache())
+	}()
+
 	// Prepare the backend, passing the plan file if present, and the
 	// backend-specific arguments
 	be, beDiags := c.PrepareBackend(planFile, args.State, args.ViewType, enc.State())
@@ -118,9 +193,76 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// Remote operations don't report which changes were applied.
+	if rb, isRemoteBackend := be.(BackendWithRemoteTerraformVersion); args.DetailedExitCode && isRemoteBackend && !rb.IsLocalOperations() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Detailed exit codes are not supported",
+			"The -detailed-exitcode option cannot be used when the operation runs remotely.",
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	if args.Watch {
+		// The remaining diagnostics are only warnings, so we'll show them
+		// once before the first cycle rather than with every cycle.
+		view.Diagnostics(diags)
+		return c.watch(be, view, args, enc)
+	}
+
+	return c.apply(be, view, args, planFile, enc, diags)
+}
+
+// apply runs a single apply operation, returning the exit status for the
+// command. Any diagnostics given are shown along with those from preparing
+// the operation.
+func (c *ApplyCommand) apply(be backend.Enhanced, view views.Apply, args *arguments.Apply, planFile *planfile.WrappedPlanFile, enc encryption.Encryption, diags tfdiags.Diagnostics) (status int) {
+	auditCommand := "apply"
+	if c.Destroy {
+		auditCommand = "destroy"
+	}
+	audit := c.auditBeginBackend(auditCommand, be)
+	defer audit.finish(&status)
+
+	// Fetch the configuration from the given ref, if requested
+	var configDir string
+	var configSource *states.ConfigSource
+	if args.ConfigRef.Enabled() {
+		ctx, done := c.InterruptibleContext(c.CommandContext())
+		var cleanup func()
+		var refDiags tfdiags.Diagnostics
+		configDir, configSource, cleanup, refDiags = c.fetchConfigRef(ctx, args.ConfigRef)
+		done()
+		diags = diags.Append(refDiags)
+		if refDiags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+		defer cleanup()
+	}
+
 	// Build the operation request
-	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, planFile, args.Operation, args.AutoApprove, enc)
+	// The interactive display needs to take over a terminal, so without one
+	// we show the normal output instead.
+	if args.TUI {
+		if c.View.InteractiveTerminal() {
+			view = views.NewApplyTUI(c.Destroy, c.View)
+		} else {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Interactive display unavailable",
+				"The -tui option requires a terminal for both input and output, so OpenTofu will show the normal output instead.",
+			))
+		}
+	}
+
+	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, planFile, args.Operation, args.AutoApprove, args.SelectChanges, enc)
 	diags = diags.Append(opDiags)
+	if configSource != nil && opReq != nil {
+		opReq.ConfigDir = configDir
+		opReq.ConfigSource = configSource
+	}
 
 	// Before we delegate to the backend, we'll print any warning diagnostics
 	// we've accumulated here, since the backend will start fresh with its own
@@ -132,13 +274,28 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 	diags = nil
 
 	// Run the operation
-	op, diags := c.RunOperation(be, opReq)
+	var op *backend.RunningOperation
+	notify := c.notifyBegin("apply", auditCommand)
+	defer func() {
+		notify.finish(status, op)
+	}()
+	op, diags = c.RunOperation(be, opReq)
+	diags = diags.Append(exportCheckMetrics(args.Metrics, op))
 	view.Diagnostics(diags)
+	if args.Timings {
+		view.Timings()
+	}
 	if diags.HasErrors() {
+		if args.DetailedExitCode && op != nil && op.ChangesApplied {
+			return applyExitPartial
+		}
 		return 1
 	}
 
 	if op.Result != backend.OperationSuccess {
+		if args.DetailedExitCode && op.ChangesApplied {
+			return applyExitPartial
+		}
 		return op.Result.ExitStatus()
 	}
 
@@ -157,9 +314,44 @@ func (c *ApplyCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	if args.DetailedExitCode && op.ChangesApplied {
+		return applyExitChanged
+	}
 	return 0
 }
 
+// The exit statuses of "tofu apply -detailed-exitcode", in addition to 0 when
+// there was nothing to change and 1 when the operation failed without
+// changing anything.
+const (
+	applyExitChanged = 2
+	applyExitPartial = 3
+)
+
+// watch runs an apply operation repeatedly until the command is interrupted
+// or a cycle fails, waiting for the configured interval between cycles.
+//
+// Each cycle refreshes, plans, and then either applies the plan
+// automatically, if -auto-approve is set, or asks for approval as normal.
+// Cycles that find no changes to make complete without asking.
+func (c *ApplyCommand) watch(be backend.Enhanced, view views.Apply, args *arguments.Apply, enc encryption.Encryption) int {
+	for cycle := 1; ; cycle++ {
+		view.WatchCycleStart(cycle)
+		if status := c.apply(be, view, args, nil, enc, nil); status != 0 {
+			return status
+		}
+
+		next := time.Now().Add(args.WatchInterval)
+		view.WatchCycleComplete(cycle, next)
+
+		select {
+		case <-c.ShutdownCh:
+			return 0
+		case <-time.After(time.Until(next)):
+		}
+	}
+}
+
 func (c *ApplyCommand) LoadPlanFile(path string, enc encryption.Encryption) (*planfile.WrappedPlanFile, tfdiags.Diagnostics) {
 	var planFile *planfile.WrappedPlanFile
 	var diags tfdiags.Diagnostics
@@ -204,6 +396,78 @@ func (c *ApplyCommand) LoadPlanFile(path string, enc encryption.Encryption) (*pl
 	return planFile, diags
 }
 
+// verifyPlanFile checks the signature of the given saved plan against the
+// OpenPGP public keys in the file at keyPath, if set. Signed plans that are
+// applied without verifying their signature produce a warning.
+func (c *ApplyCommand) verifyPlanFile(planFile *planfile.WrappedPlanFile, keyPath string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	lp, ok := planFile.Local()
+	if !ok {
+		if keyPath != "" && planFile != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Can't verify a cloud plan",
+				"The -verify-key option can only be used with plan files saved locally by \"tofu plan -out\".",
+			))
+		}
+		return diags
+	}
+
+	if meta, err := lp.ReadMetadata(); err == nil && meta != nil {
+		log.Printf("[INFO] command: applying plan created by %q at %s with OpenTofu v%s, from state serial %d", meta.Creator, meta.CreatedAt, meta.TofuVersion, meta.StateSerial)
+	}
+
+	if keyPath == "" {
+		if lp.Signed() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Plan signature not verified",
+				"The saved plan is signed, but its signature was not verified. Use the -verify-key option to check that it was signed by a trusted key.",
+			))
+		}
+		return diags
+	}
+
+	src, err := os.ReadFile(keyPath)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read verification key",
+			fmt.Sprintf("Could not read the plan verification key file: %s.", err),
+		))
+		return diags
+	}
+	keys, err := planfile.ParseVerifyingKeys(src)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid verification key",
+			fmt.Sprintf("The file %s does not contain valid OpenPGP public keys: %s.", keyPath, err),
+		))
+		return diags
+	}
+
+	signer, err := lp.VerifySignature(keys)
+	switch {
+	case errors.Is(err, planfile.ErrUnsigned):
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Saved plan is not signed",
+			"The -verify-key option requires a plan file signed using \"tofu plan -sign-key\".",
+		))
+	case err != nil:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Saved plan signature is not valid",
+			fmt.Sprintf("The saved plan could not be verified with the given keys, so it will not be applied: %s.", err),
+		))
+	default:
+		log.Printf("[INFO] command: plan signature verified, signed by %s", signer)
+	}
+	return diags
+}
+
 func (c *ApplyCommand) PrepareBackend(planFile *planfile.WrappedPlanFile, args *arguments.State, viewType arguments.ViewType, enc encryption.StateEncryption) (backend.Enhanced, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
@@ -264,6 +528,7 @@ func (c *ApplyCommand) OperationRequest(
 	planFile *planfile.WrappedPlanFile,
 	args *arguments.Operation,
 	autoApprove bool,
+	selectChanges bool,
 	enc encryption.Encryption,
 ) (*backend.Operation, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
@@ -276,13 +541,21 @@ func (c *ApplyCommand) OperationRequest(
 	// Build the operation
 	opReq := c.Operation(be, viewType, enc)
 	opReq.AutoApprove = autoApprove
+	opReq.SelectChanges = selectChanges
 	opReq.ConfigDir = "."
 	opReq.PlanMode = args.PlanMode
 	opReq.Hooks = view.Hooks()
 	opReq.PlanFile = planFile
 	opReq.PlanRefresh = args.Refresh
 	opReq.Targets = args.Targets
+	opReq.RefreshFilter = args.RefreshFilter
+	opReq.RefreshParallelism = args.RefreshParallelism
 	opReq.ForceReplace = args.ForceReplace
+	opReq.ForceReplacePatterns = args.ForceReplacePatterns
+	opReq.AdoptExisting = args.AdoptExisting
+	opReq.StrictWarnings = args.Strict
+	opReq.SnapshotDir = c.snapshotDir(opReq.Workspace)
+	opReq.Policies = c.policyCheckers()
 	opReq.Type = backend.OperationTypeApply
 	opReq.View = view.Operation()
 
@@ -313,6 +586,26 @@ func (c *ApplyCommand) GatherVariables(args *arguments.Vars) {
 	c.Meta.variableArgs = rawFlags{items: &items}
 }
 
+func (c *ApplyCommand) AutocompleteArgs() complete.Predictor {
+	if c.Destroy {
+		return complete.PredictNothing
+	}
+	// The optional argument is a saved plan file.
+	return complete.PredictFiles("*")
+}
+
+func (c *ApplyCommand) AutocompleteFlags() complete.Flags {
+	flags := c.completeOperationFlags()
+	flags["-auto-approve"] = complete.PredictNothing
+	flags["-detailed-exitcode"] = complete.PredictNothing
+	if c.Destroy {
+		delete(flags, "-destroy")
+	} else {
+		flags["-verify-key"] = complete.PredictFiles("*")
+	}
+	return flags
+}
+
 func (c *ApplyCommand) Help() string {
 	if c.Destroy {
 		return c.helpDestroy()
@@ -354,6 +647,22 @@ Options:
                          accompanied by errors, show them in a more compact
                          form that includes only the summary messages.
 
+  -config-ref=addr       Apply the configuration at an exact ref of a git
+                         repository, such as
+                         git::https://example.com/infra.git?ref=v1.2.3,
+                         instead of the configuration in the working
+                         directory. The ref is recorded in the state.
+
+  -verify-config-ref     Require the ref given by -config-ref to be a tag with
+                         a valid signature.
+
+  -detailed-exitcode     Return detailed exit codes when the command exits.
+                         This will change the meaning of exit codes to:
+                         0 - Succeeded, no changes were applied
+                         1 - Errored before applying any changes
+                         2 - Succeeded, changes were applied
+                         3 - Errored after applying some changes
+
   -destroy               Destroy OpenTofu-managed infrastructure.
                          The command "tofu destroy" is a convenience alias
                          for this option.
@@ -364,13 +673,65 @@ Options:
 
   -lock-timeout=0s       Duration to retry a state lock.
 
+  -incremental           When creating a plan, reuse the providers' cached
+                         responses to identical plan requests from the last
+                         incremental plan in this working directory.
+
+  -incremental-reset     Like -incremental, but ignore the cached responses
+                         so that every change is planned in full.
+
   -input=true            Ask for input for variables if not directly set.
 
+  -full-string-diff      Show changes to long and multi-line strings in full,
+                         instead of as an inline word diff with unchanged
+                         lines hidden.
+
+  -metrics-out=path      Write the status of each check in the
+                         configuration, and how many of its conditions
+                         failed, to the given path as Prometheus metrics.
+
+  -metrics-push-url=url  Push the check metrics to the given Prometheus
+                         Pushgateway URL, such as
+                         http://localhost:9091/metrics/job/tofu.
+
   -no-color              If specified, output won't contain any color.
 
+  -suppress-attribute=name
+                         Hide the values of the given resource attribute in
+                         the rendered changes, only showing whether it
+                         changed. Use "type.name" to only hide it in resources
+                         of that type. Can be used multiple times.
+
+  -summary               Show the changes grouped by module and resource type
+                         with the number of each kind of change, instead of
+                         showing every change in full.
+
+  -summary-expand=pattern
+                         With -summary, also show in full the changes to the
+                         instances of the given resource type, or of the given
+                         module, resource or instance address. Can be used
+                         multiple times.
+
+  -verify-key=path       Only apply the saved plan if it was signed with one
+                         of the OpenPGP public keys in the given file.
+
+  -override-set=name     Also load the environment-scoped override files of
+                         the given set, named like "*_override.name.tf".
+                         Defaults to the TF_OVERRIDE_SET environment variable.
+
   -parallelism=n         Limit the number of parallel resource operations.
                          Defaults to 10.
 
+  -profile=dir           Write CPU, heap, and goroutine profiles of the
+                         operation to the given directory, along with a
+                         summary of the slowest graph nodes, to include in
+                         performance bug reports.
+
+  -select-changes        Before asking for approval, show a checklist of the
+                         planned changes and let you choose which of them to
+                         skip. Skipped changes remain pending for the next
+                         plan.
+
   -state=path            Path to read and save state (unless state-out
                          is specified). Defaults to "terraform.tfstate".
 
@@ -378,9 +739,35 @@ Options:
                          "-state". This can be used to preserve the old
                          state.
 
+  -strict                Treat warnings in any of the warning categories as
+                         errors. Use -strict=category,... to select only
+                         some of the categories: deprecations,
+                         implicit_provider_inheritance, and
+                         undeclared_var_files.
+
+  -timings               After the operation, show how long OpenTofu spent
+                         refreshing, planning, and applying each resource
+                         instance, and how much of that time was spent
+                         waiting for the provider.
+
+  -tui                   Show the progress of applying in a full-screen,
+                         interactive display, with the status, timing, changes,
+                         and logs of each resource instance, instead of the
+                         scrolling text logs. The display waits for you to
+                         leave it once the operation completes.
+
+  -watch                 Keep running, repeatedly refreshing, planning, and
+                         applying to correct any drift, until interrupted.
+                         Each cycle asks for approval of any changes unless
+                         -auto-approve is also set.
+
+  -watch-interval=5m     Time to wait between the cycles of -watch. Defaults
+                         to 5m.
+
   If you don't provide a saved plan file then this command will also accept
-  all of the plan-customization options accepted by the tofu plan command.
-  For more information on those options, run:
+  all of the plan-customization options accepted by the tofu plan command,
+  such as -refresh-filter and -target. For more information on those
+  options, run:
       tofu plan -help
 `
 	return strings.TrimSpace(helpText)
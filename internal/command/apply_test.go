@@ -28,6 +28,7 @@ import (
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -474,6 +475,95 @@ func TestApply_defaultState(t *testing.T) {
 	}
 }
 
+func TestApply_detailedExitCode(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	statePath := testTempFile(t)
+	p := applyFixtureProvider()
+	run := func() int {
+		view, done := testView(t)
+		c := &ApplyCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+		code := c.Run([]string{"-state", statePath, "-auto-approve", "-detailed-exitcode"})
+		done(t)
+		return code
+	}
+
+	if code := run(); code != 2 {
+		t.Fatalf("wrong exit code %d for first apply; want 2", code)
+	}
+	if code := run(); code != 0 {
+		t.Fatalf("wrong exit code %d with no changes; want 0", code)
+	}
+}
+
+func TestApply_detailedExitCodeError(t *testing.T) {
+	testCases := map[string]struct {
+		failAll bool
+		want    int
+	}{
+		"nothing applied":   {failAll: true, want: 1},
+		"partially applied": {failAll: false, want: 3},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			td := t.TempDir()
+			testCopyDir(t, testFixturePath("apply-error"), td)
+			defer testChdir(t, td)()
+
+			p := testProvider()
+			p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+				ResourceTypes: map[string]providers.Schema{
+					"test_instance": {
+						Block: &configschema.Block{
+							Attributes: map[string]*configschema.Attribute{
+								"id":    {Type: cty.String, Optional: true, Computed: true},
+								"ami":   {Type: cty.String, Optional: true},
+								"error": {Type: cty.Bool, Optional: true},
+							},
+						},
+					},
+				},
+			}
+			p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+				s := req.ProposedNewState.AsValueMap()
+				s["id"] = cty.UnknownVal(cty.String)
+				resp.PlannedState = cty.ObjectVal(s)
+				return
+			}
+			p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) (resp providers.ApplyResourceChangeResponse) {
+				s := req.PlannedState.AsValueMap()
+				s["id"] = cty.StringVal("foo")
+				resp.NewState = cty.ObjectVal(s)
+				if tc.failAll || s["error"].True() {
+					resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("error"))
+				}
+				return
+			}
+
+			view, done := testView(t)
+			c := &ApplyCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(p),
+					View:             view,
+				},
+			}
+			code := c.Run([]string{"-state", testTempFile(t), "-auto-approve", "-detailed-exitcode"})
+			output := done(t)
+			if code != tc.want {
+				t.Fatalf("wrong exit code %d; want %d\n%s", code, tc.want, output.Stderr())
+			}
+		})
+	}
+}
+
 func TestApply_error(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -695,6 +785,7 @@ func TestApply_plan(t *testing.T) {
 
 	planPath := applyFixturePlanFile(t)
 	statePath := testTempFile(t)
+	testCwd(t)
 
 	p := applyFixtureProvider()
 	view, done := testView(t)
@@ -725,6 +816,150 @@ func TestApply_plan(t *testing.T) {
 	}
 }
 
+func TestApply_planStale(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	testStateFileDefault(t, testState())
+
+	p := applyFixtureProvider()
+	planPath := filepath.Join(td, "test.plan")
+	planView, planDone := testView(t)
+	pc := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             planView,
+		},
+	}
+	if code := pc.Run([]string{"-out", planPath}); code != 0 {
+		t.Fatalf("plan failed: %d\n\n%s", code, planDone(t).Stderr())
+	}
+	planDone(t)
+
+	// Another operation adds an object to the state after the plan was
+	// created, which the stale plan knows nothing about.
+	newerAddr := addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "newer",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	newer := testState()
+	newer.SyncWrapper().SetResourceInstanceCurrent(
+		newerAddr,
+		&states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"id":"newer"}`),
+			Status:    states.ObjectReady,
+		},
+		addrs.AbsProviderConfig{
+			Provider: addrs.NewDefaultProvider("test"),
+			Module:   addrs.RootModule,
+		},
+	)
+	f, err := os.Create(DefaultStateFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = statefile.Write(statefile.New(newer, "fake-for-testing", 1), f, encryption.StateEncryptionDisabled())
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{planPath})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Saved plan is stale"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+
+	// The stale plan must not have overwritten the newer state.
+	state := testStateRead(t, DefaultStateFilename)
+	if state.ResourceInstance(newerAddr) == nil {
+		t.Fatalf("object added after the plan was lost from the state:\n%s", state)
+	}
+}
+
+func TestApply_planVerifyKey(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	privateKey, publicKey := testPlanSigningKeys(t, "Plan Signer <signer@example.com>")
+	_, otherPublicKey := testPlanSigningKeys(t, "Someone Else <else@example.com>")
+
+	p := applyFixtureProvider()
+	planPath := filepath.Join(td, "test.plan")
+	planView, planDone := testView(t)
+	pc := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             planView,
+		},
+	}
+	if code := pc.Run([]string{"-out", planPath, "-sign-key", privateKey}); code != 0 {
+		t.Fatalf("plan failed: %d\n\n%s", code, planDone(t).Stderr())
+	}
+	planDone(t)
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{"-verify-key", otherPublicKey, planPath})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Saved plan signature is not valid"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant: %s", got, want)
+	}
+
+	view, done = testView(t)
+	c.View = view
+	code = c.Run([]string{"-verify-key", publicKey, planPath})
+	output = done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+	if !p.ApplyResourceChangeCalled {
+		t.Fatal("provider should have been called")
+	}
+}
+
+func TestApply_planVerifyKeyUnsigned(t *testing.T) {
+	planPath := applyFixturePlanFile(t)
+	_, publicKey := testPlanSigningKeys(t, "Plan Signer <signer@example.com>")
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{"-verify-key", publicKey, planPath})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Saved plan is not signed"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant: %s", got, want)
+	}
+}
+
 func TestApply_plan_backup(t *testing.T) {
 	statePath := testTempFile(t)
 	backupPath := testTempFile(t)
@@ -748,6 +983,7 @@ func TestApply_plan_backup(t *testing.T) {
 	// the plan file must contain the metadata from the prior state to be
 	// backed up
 	planPath := applyFixturePlanFileMatchState(t, fs.StateSnapshotMeta())
+	testCwd(t)
 
 	args := []string{
 		"-state", statePath,
@@ -767,6 +1003,7 @@ func TestApply_plan_backup(t *testing.T) {
 func TestApply_plan_noBackup(t *testing.T) {
 	planPath := applyFixturePlanFile(t)
 	statePath := testTempFile(t)
+	testCwd(t)
 
 	p := applyFixtureProvider()
 	view, done := testView(t)
@@ -954,6 +1191,37 @@ func TestApply_planVars(t *testing.T) {
 	}
 }
 
+func TestApply_planRefreshFilter(t *testing.T) {
+	planPath := applyFixturePlanFile(t)
+	statePath := testTempFile(t)
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-refresh-filter", "test_instance.foo",
+		planPath,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code == 0 {
+		t.Fatal("should've failed: ", output.Stdout())
+	}
+	if got, want := output.Stderr(), "Can't filter refreshing when applying a saved plan"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant substring: %s", got, want)
+	}
+	if p.ApplyResourceChangeCalled {
+		t.Fatal("should not apply the plan")
+	}
+}
+
 // we should be able to apply a plan file with no other file dependencies
 func TestApply_planNoModuleFiles(t *testing.T) {
 	// temporary data directory which we can remove between commands
@@ -7,6 +7,7 @@ package arguments
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -22,6 +23,47 @@ type Apply struct {
 	// AutoApprove skips the manual verification step for the apply operation.
 	AutoApprove bool
 
+	// SelectChanges allows choosing which of the planned changes to apply
+	// during the manual verification step.
+	SelectChanges bool
+
+	// Watch runs the apply operation repeatedly, waiting WatchInterval
+	// between the end of one cycle and the start of the next, to correct
+	// drift as it is detected.
+	Watch         bool
+	WatchInterval time.Duration
+
+	// Timings enables a report of how long OpenTofu spent on each resource
+	// instance once the operation completes.
+	Timings bool
+
+	// Metrics tells the command where to export the results of the checks
+	// in the configuration as metrics.
+	Metrics *CheckMetrics
+
+	// ProfileDir is an optional directory to write CPU, heap, and goroutine
+	// profiles to, along with a summary of the slowest graph nodes.
+	ProfileDir string
+
+	// Incremental reuses the responses of providers to plan requests from
+	// the previous incremental plan in the same working directory, when a
+	// request is identical to one they answered before.
+	Incremental bool
+
+	// IncrementalReset ignores the responses kept by earlier incremental
+	// plans, so that every change is planned in full, and keeps the new
+	// responses for the next incremental plan.
+	IncrementalReset bool
+
+	// ConfigRef optionally selects a git ref to fetch the configuration
+	// from, instead of using the configuration in the working directory.
+	ConfigRef *ConfigRef
+
+	// DetailedExitCode enables different exit codes depending on whether
+	// any changes were applied, and whether the operation failed before or
+	// after applying some of them.
+	DetailedExitCode bool
+
 	// InputEnabled is used to disable interactive input for unspecified
 	// variable and backend config values. Default is true.
 	InputEnabled bool
@@ -29,6 +71,14 @@ type Apply struct {
 	// PlanPath contains an optional path to a stored plan file
 	PlanPath string
 
+	// VerifyKeyPath contains an optional path to the OpenPGP public keys
+	// that the plan file must be signed with.
+	VerifyKeyPath string
+
+	// TUI shows the progress of the operation in a full-screen, interactive
+	// display instead of the scrolling text logs.
+	TUI bool
+
 	// ViewType specifies which output format to use
 	ViewType ViewType
 }
@@ -42,11 +92,28 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 		State:     &State{},
 		Operation: &Operation{},
 		Vars:      &Vars{},
+		Metrics:   &CheckMetrics{},
+		ConfigRef: &ConfigRef{},
 	}
 
 	cmdFlags := extendedFlagSet("apply", apply.State, apply.Operation, apply.Vars)
 	cmdFlags.BoolVar(&apply.AutoApprove, "auto-approve", false, "auto-approve")
+	cmdFlags.BoolVar(&apply.SelectChanges, "select-changes", false, "select-changes")
+	cmdFlags.BoolVar(&apply.Watch, "watch", false, "watch")
+	cmdFlags.DurationVar(&apply.WatchInterval, "watch-interval", DefaultWatchInterval, "watch-interval")
+	cmdFlags.BoolVar(&apply.Timings, "timings", false, "timings")
+	cmdFlags.StringVar(&apply.Metrics.OutPath, "metrics-out", "", "metrics-out")
+	cmdFlags.StringVar(&apply.Metrics.PushURL, "metrics-push-url", "", "metrics-push-url")
+	cmdFlags.StringVar(&apply.ProfileDir, "profile", "", "profile")
+	cmdFlags.BoolVar(&apply.Incremental, "incremental", false, "incremental")
+	cmdFlags.BoolVar(&apply.IncrementalReset, "incremental-reset", false, "incremental-reset")
+	cmdFlags.StringVar(&apply.ConfigRef.Address, "config-ref", "", "config-ref")
+	cmdFlags.BoolVar(&apply.ConfigRef.VerifySignature, "verify-config-ref", false, "verify-config-ref")
+	cmdFlags.BoolVar(&apply.DetailedExitCode, "detailed-exitcode", false, "detailed-exitcode")
 	cmdFlags.BoolVar(&apply.InputEnabled, "input", true, "input")
+	cmdFlags.StringVar(&apply.VerifyKeyPath, "verify-key", "", "verify-key")
+
+	cmdFlags.BoolVar(&apply.TUI, "tui", false, "tui")
 
 	var json bool
 	cmdFlags.BoolVar(&json, "json", false, "json")
@@ -81,6 +148,14 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 	// JSON view cannot confirm apply, so we require either a plan file or
 	// auto-approve to be specified. We intentionally fail here rather than
 	// override auto-approve, which would be dangerous.
+	if json && apply.TUI {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible output options",
+			"The -json and -tui options cannot be used together.",
+		))
+	}
+
 	if json && apply.PlanPath == "" && !apply.AutoApprove {
 		diags = diags.Append(tfdiags.Sourceless(
 			tfdiags.Error,
@@ -89,7 +164,93 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 		))
 	}
 
+	if apply.SelectChanges {
+		switch {
+		case apply.AutoApprove:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible approval options",
+				"The -select-changes option cannot be used with -auto-approve, because selecting changes happens during interactive approval.",
+			))
+		case apply.PlanPath != "":
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Can't select changes from a saved plan",
+				"The -select-changes option cannot be used when applying a saved plan file. To apply only some of the changes, create a new plan using the -target option.",
+			))
+		case !apply.InputEnabled:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Input required to select changes",
+				"The -select-changes option requires interactive input, so it cannot be used with -input=false or -json.",
+			))
+		}
+	}
+
+	if apply.PlanPath == "" {
+		if apply.VerifyKeyPath != "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Plan file required for verification",
+				"The -verify-key option verifies the signature of a saved plan file, so it can only be used when applying one.",
+			))
+		}
+	}
+
+	if apply.Watch {
+		switch {
+		case apply.PlanPath != "":
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Can't watch a saved plan",
+				"The -watch option cannot be used when applying a saved plan file, because each cycle must create a new plan.",
+			))
+		case apply.SelectChanges:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible apply options",
+				"The -watch and -select-changes options cannot be used together.",
+			))
+		case apply.DetailedExitCode:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible apply options",
+				"The -watch and -detailed-exitcode options cannot be used together.",
+			))
+		case apply.TUI:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible apply options",
+				"The -watch and -tui options cannot be used together, because each cycle would wait for you to leave the interactive display.",
+			))
+		case apply.WatchInterval < time.Second:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid watch interval",
+				"The -watch-interval option must be at least one second.",
+			))
+		}
+	}
+
 	diags = diags.Append(apply.Operation.Parse())
+	diags = diags.Append(apply.Metrics.Parse())
+	diags = diags.Append(apply.ConfigRef.Parse())
+
+	if apply.ConfigRef.Enabled() && apply.PlanPath != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Can't use a config ref with a saved plan",
+			"The -config-ref option cannot be used when applying a saved plan file, which already includes the configuration it was created from.",
+		))
+	}
+
+	if apply.Watch && apply.Operation.PlanMode == plans.DestroyMode {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible apply options",
+			"The -watch option cannot be used with -destroy.",
+		))
+	}
 
 	switch {
 	case json:
@@ -101,6 +262,10 @@ func ParseApply(args []string) (*Apply, tfdiags.Diagnostics) {
 	return apply, diags
 }
 
+// DefaultWatchInterval is the time to wait between cycles of "tofu apply -watch"
+// when no -watch-interval option is given.
+const DefaultWatchInterval = 5 * time.Minute
+
 // ParseApplyDestroy is a special case of ParseApply that deals with the
 // "tofu destroy" command, which is effectively an alias for
 // "tofu apply -destroy".
@@ -142,6 +307,14 @@ func ParseApplyDestroy(args []string) (*Apply, tfdiags.Diagnostics) {
 		))
 	}
 
+	if apply.Watch {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid watch option",
+			"The -watch option is not valid for \"tofu destroy\".",
+		))
+	}
+
 	// NOTE: It's also invalid to have apply.PlanPath set in this codepath,
 	// but we don't check that in here because we'll return a different error
 	// message depending on whether the given path seems to refer to a saved
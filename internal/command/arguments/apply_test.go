@@ -8,6 +8,7 @@ package arguments
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -23,12 +24,15 @@ func TestParseApply_basicValid(t *testing.T) {
 		"defaults": {
 			nil,
 			&Apply{
-				AutoApprove:  false,
-				InputEnabled: true,
-				PlanPath:     "",
-				ViewType:     ViewHuman,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:   false,
+				InputEnabled:  true,
+				WatchInterval: DefaultWatchInterval,
+				PlanPath:      "",
+				ViewType:      ViewHuman,
+				State:         &State{Lock: true},
+				Vars:          &Vars{},
+				Metrics:       &CheckMetrics{},
+				ConfigRef:     &ConfigRef{},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -39,12 +43,87 @@ func TestParseApply_basicValid(t *testing.T) {
 		"auto-approve, disabled input, and plan path": {
 			[]string{"-auto-approve", "-input=false", "saved.tfplan"},
 			&Apply{
-				AutoApprove:  true,
-				InputEnabled: false,
-				PlanPath:     "saved.tfplan",
-				ViewType:     ViewHuman,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:   true,
+				InputEnabled:  false,
+				WatchInterval: DefaultWatchInterval,
+				PlanPath:      "saved.tfplan",
+				ViewType:      ViewHuman,
+				State:         &State{Lock: true},
+				Vars:          &Vars{},
+				Metrics:       &CheckMetrics{},
+				ConfigRef:     &ConfigRef{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"timings": {
+			[]string{"-timings"},
+			&Apply{
+				InputEnabled:  true,
+				WatchInterval: DefaultWatchInterval,
+				Timings:       true,
+				ViewType:      ViewHuman,
+				State:         &State{Lock: true},
+				Vars:          &Vars{},
+				Metrics:       &CheckMetrics{},
+				ConfigRef:     &ConfigRef{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"profile": {
+			[]string{"-profile=profiles"},
+			&Apply{
+				InputEnabled:  true,
+				WatchInterval: DefaultWatchInterval,
+				ProfileDir:    "profiles",
+				ViewType:      ViewHuman,
+				State:         &State{Lock: true},
+				Vars:          &Vars{},
+				Metrics:       &CheckMetrics{},
+				ConfigRef:     &ConfigRef{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"incremental": {
+			[]string{"-incremental"},
+			&Apply{
+				InputEnabled:  true,
+				WatchInterval: DefaultWatchInterval,
+				Incremental:   true,
+				ViewType:      ViewHuman,
+				State:         &State{Lock: true},
+				Vars:          &Vars{},
+				Metrics:       &CheckMetrics{},
+				ConfigRef:     &ConfigRef{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"incremental reset": {
+			[]string{"-incremental-reset"},
+			&Apply{
+				InputEnabled:     true,
+				WatchInterval:    DefaultWatchInterval,
+				IncrementalReset: true,
+				ViewType:         ViewHuman,
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				Metrics:          &CheckMetrics{},
+				ConfigRef:        &ConfigRef{},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -55,12 +134,15 @@ func TestParseApply_basicValid(t *testing.T) {
 		"destroy mode": {
 			[]string{"-destroy"},
 			&Apply{
-				AutoApprove:  false,
-				InputEnabled: true,
-				PlanPath:     "",
-				ViewType:     ViewHuman,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:   false,
+				InputEnabled:  true,
+				WatchInterval: DefaultWatchInterval,
+				PlanPath:      "",
+				ViewType:      ViewHuman,
+				State:         &State{Lock: true},
+				Vars:          &Vars{},
+				Metrics:       &CheckMetrics{},
+				ConfigRef:     &ConfigRef{},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -71,12 +153,15 @@ func TestParseApply_basicValid(t *testing.T) {
 		"JSON view disables input": {
 			[]string{"-json", "-auto-approve"},
 			&Apply{
-				AutoApprove:  true,
-				InputEnabled: false,
-				PlanPath:     "",
-				ViewType:     ViewJSON,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:   true,
+				InputEnabled:  false,
+				WatchInterval: DefaultWatchInterval,
+				PlanPath:      "",
+				ViewType:      ViewJSON,
+				State:         &State{Lock: true},
+				Vars:          &Vars{},
+				Metrics:       &CheckMetrics{},
+				ConfigRef:     &ConfigRef{},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -141,6 +226,164 @@ func TestParseApply_json(t *testing.T) {
 	}
 }
 
+func TestParseApply_selectChanges(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"-select-changes": {
+			args: []string{"-select-changes"},
+		},
+		"-select-changes -auto-approve": {
+			args:    []string{"-select-changes", "-auto-approve"},
+			wantErr: "Incompatible approval options",
+		},
+		"-select-changes saved.tfplan": {
+			args:    []string{"-select-changes", "saved.tfplan"},
+			wantErr: "Can't select changes from a saved plan",
+		},
+		"-select-changes -input=false": {
+			args:    []string{"-select-changes", "-input=false"},
+			wantErr: "Input required to select changes",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParseApply(tc.args)
+			if tc.wantErr == "" {
+				if len(diags) > 0 {
+					t.Fatalf("unexpected diags: %v", diags)
+				}
+				if !got.SelectChanges {
+					t.Fatalf("SelectChanges should be set")
+				}
+				return
+			}
+			if got, want := diags.Err().Error(), tc.wantErr; !strings.Contains(got, want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestParseApply_savedPlanOptions(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"with plan file": {
+			args: []string{"-verify-key=key.asc", "saved.tfplan"},
+		},
+		"-verify-key without plan file": {
+			args:    []string{"-verify-key=key.asc"},
+			wantErr: "Plan file required for verification",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParseApply(tc.args)
+			if tc.wantErr == "" {
+				if len(diags) > 0 {
+					t.Fatalf("unexpected diags: %v", diags)
+				}
+				if got.VerifyKeyPath != "key.asc" {
+					t.Fatalf("wrong result %#v", got)
+				}
+				return
+			}
+			if got, want := diags.Err().Error(), tc.wantErr; !strings.Contains(got, want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestParseApply_watch(t *testing.T) {
+	testCases := map[string]struct {
+		args         []string
+		wantInterval time.Duration
+		wantErr      string
+	}{
+		"-watch": {
+			args:         []string{"-watch"},
+			wantInterval: DefaultWatchInterval,
+		},
+		"-watch -watch-interval=30s": {
+			args:         []string{"-watch", "-watch-interval=30s"},
+			wantInterval: 30 * time.Second,
+		},
+		"-watch -watch-interval=10ms": {
+			args:    []string{"-watch", "-watch-interval=10ms"},
+			wantErr: "Invalid watch interval",
+		},
+		"-watch saved.tfplan": {
+			args:    []string{"-watch", "saved.tfplan"},
+			wantErr: "Can't watch a saved plan",
+		},
+		"-watch -destroy": {
+			args:    []string{"-watch", "-destroy"},
+			wantErr: "The -watch option cannot be used with -destroy.",
+		},
+		"-watch -select-changes": {
+			args:    []string{"-watch", "-select-changes"},
+			wantErr: "Incompatible apply options",
+		},
+		"-watch -detailed-exitcode": {
+			args:    []string{"-watch", "-detailed-exitcode"},
+			wantErr: "The -watch and -detailed-exitcode options cannot be used together.",
+		},
+		"-watch -tui": {
+			args:    []string{"-watch", "-tui"},
+			wantErr: "The -watch and -tui options cannot be used together",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParseApply(tc.args)
+			if tc.wantErr == "" {
+				if len(diags) > 0 {
+					t.Fatalf("unexpected diags: %v", diags)
+				}
+				if !got.Watch {
+					t.Fatalf("Watch should be set")
+				}
+				if got.WatchInterval != tc.wantInterval {
+					t.Fatalf("wrong interval %s; want %s", got.WatchInterval, tc.wantInterval)
+				}
+				return
+			}
+			if got, want := diags.Err().Error(), tc.wantErr; !strings.Contains(got, want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+			}
+		})
+	}
+}
+
+func TestParseApply_tui(t *testing.T) {
+	got, diags := ParseApply([]string{"-tui"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if !got.TUI || got.ViewType != ViewHuman {
+		t.Fatalf("wrong result: TUI %t, view type %s", got.TUI, got.ViewType)
+	}
+
+	_, diags = ParseApply([]string{"-tui", "-json", "-auto-approve"})
+	if got, want := diags.Err().Error(), "The -json and -tui options cannot be used together."; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestParseApplyDestroy_watch(t *testing.T) {
+	_, diags := ParseApplyDestroy([]string{"-watch"})
+	if got, want := diags.Err().Error(), "not valid for \"tofu destroy\""; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
 func TestParseApply_invalid(t *testing.T) {
 	got, diags := ParseApply([]string{"-frob"})
 	if len(diags) == 0 {
@@ -275,6 +518,58 @@ func TestParseApply_replace(t *testing.T) {
 	}
 }
 
+func TestParseApply_replacePatterns(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		want    []string
+		wantErr string
+	}{
+		"no patterns by default": {
+			args: []string{"-replace=foo_bar.baz"},
+			want: nil,
+		},
+		"wildcard instance key": {
+			args: []string{"-replace=foo_bar.baz[*]"},
+			want: []string{"foo_bar.baz[*]"},
+		},
+		"wildcard module and name": {
+			args: []string{"-replace=foo_bar.baz", "-replace=module.workers[*].foo_bar.node_*"},
+			want: []string{"module.workers[*].foo_bar.node_*"},
+		},
+		"data resource pattern": {
+			args:    []string{"-replace=data.foo.bar[*]"},
+			wantErr: "Only managed resources can be used",
+		},
+		"invalid pattern": {
+			args:    []string{"-replace=foo_bar.*.baz"},
+			wantErr: "A resource instance pattern must consist of",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParseApply(tc.args)
+			if len(diags) > 0 {
+				if tc.wantErr == "" {
+					t.Fatalf("unexpected diags: %v", diags)
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+				return
+			} else if tc.wantErr != "" {
+				t.Fatalf("expected error %q, got none", tc.wantErr)
+			}
+			var patterns []string
+			for _, pattern := range got.Operation.ForceReplacePatterns {
+				patterns = append(patterns, pattern.String())
+			}
+			if !cmp.Equal(patterns, tc.want) {
+				t.Fatalf("unexpected result\n%s", cmp.Diff(patterns, tc.want))
+			}
+		})
+	}
+}
+
 func TestParseApply_vars(t *testing.T) {
 	testCases := map[string]struct {
 		args []string
@@ -334,11 +629,14 @@ func TestParseApplyDestroy_basicValid(t *testing.T) {
 		"defaults": {
 			nil,
 			&Apply{
-				AutoApprove:  false,
-				InputEnabled: true,
-				ViewType:     ViewHuman,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:   false,
+				InputEnabled:  true,
+				WatchInterval: DefaultWatchInterval,
+				ViewType:      ViewHuman,
+				State:         &State{Lock: true},
+				Vars:          &Vars{},
+				Metrics:       &CheckMetrics{},
+				ConfigRef:     &ConfigRef{},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -349,11 +647,14 @@ func TestParseApplyDestroy_basicValid(t *testing.T) {
 		"auto-approve and disabled input": {
 			[]string{"-auto-approve", "-input=false"},
 			&Apply{
-				AutoApprove:  true,
-				InputEnabled: false,
-				ViewType:     ViewHuman,
-				State:        &State{Lock: true},
-				Vars:         &Vars{},
+				AutoApprove:   true,
+				InputEnabled:  false,
+				WatchInterval: DefaultWatchInterval,
+				ViewType:      ViewHuman,
+				State:         &State{Lock: true},
+				Vars:          &Vars{},
+				Metrics:       &CheckMetrics{},
+				ConfigRef:     &ConfigRef{},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -392,3 +693,18 @@ func TestParseApplyDestroy_invalid(t *testing.T) {
 		}
 	})
 }
+
+func TestParseApply_configRef(t *testing.T) {
+	got, diags := ParseApply([]string{"-config-ref=git::https://example.com/infra.git?ref=v1.2.3"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if got, want := got.ConfigRef.Ref(), "v1.2.3"; got != want {
+		t.Fatalf("wrong ref %q; want %q", got, want)
+	}
+
+	_, diags = ParseApply([]string{"-config-ref=git::https://example.com/infra.git?ref=v1.2.3", "saved.tfplan"})
+	if got, want := diags.Err().Error(), "Can't use a config ref with a saved plan"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ConfigRef represents the command-line arguments for running against the
+// configuration at an exact ref of a git repository, rather than the one in
+// the working directory, which are shared by the plan and apply commands.
+type ConfigRef struct {
+	// Address is the source address of the configuration, in the same
+	// form as the source of a module call, such as
+	// "git::https://example.com/infra.git?ref=v1.2.3".
+	Address string
+
+	// VerifySignature requires the ref to be a tag with a valid signature.
+	VerifySignature bool
+}
+
+// Enabled returns true if the configuration is to be fetched from a ref.
+func (r *ConfigRef) Enabled() bool {
+	return r.Address != ""
+}
+
+// Ref returns the ref that the address selects.
+func (r *ConfigRef) Ref() string {
+	_, query, _ := strings.Cut(r.Address, "?")
+	values, _ := url.ParseQuery(query)
+	return values.Get("ref")
+}
+
+// Parse validates the arguments, after the flags have been parsed.
+func (r *ConfigRef) Parse() tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if r.Address == "" {
+		if r.VerifySignature {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Config ref required for verification",
+				"The -verify-config-ref option verifies the signature of the tag given by -config-ref, so it can only be used along with that option.",
+			))
+		}
+		return diags
+	}
+
+	if !strings.HasPrefix(r.Address, "git::") || r.Ref() == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid config ref",
+			"The -config-ref option must be a git source address with an exact ref, such as git::https://example.com/infra.git?ref=v1.2.3.",
+		))
+		r.Address = ""
+		r.VerifySignature = false
+	}
+	return diags
+}
@@ -8,6 +8,8 @@ package arguments
 import (
 	"flag"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
@@ -81,13 +83,40 @@ type Operation struct {
 	// learn a use-case for broader matching.
 	ForceReplace []addrs.AbsResourceInstance
 
+	// ForceReplacePatterns are -replace arguments containing wildcards,
+	// which must be expanded against the prior state to find the resource
+	// instances to force replacement of.
+	ForceReplacePatterns []addrs.AbsResourceInstancePattern
+
+	// AdoptExisting, if set, asks providers to search for existing remote
+	// objects matching resource instances that have no object in the state,
+	// and plans to import any objects found instead of creating new ones.
+	AdoptExisting bool
+
+	// Strict selects the categories of warnings that should be treated as
+	// errors, in addition to any selected by the configuration.
+	Strict []tfdiags.WarningCategory
+
+	// RefreshFilter, if non-empty, limits the refresh step of the operation
+	// to only the resource instances contained in at least one of the given
+	// addresses. Other resource instances are still planned, but using their
+	// prior state as-is.
+	RefreshFilter []addrs.Targetable
+
+	// RefreshParallelism optionally limits the number of concurrent calls
+	// made to each of the given providers to read the current state of
+	// remote objects while refreshing.
+	RefreshParallelism map[addrs.Provider]int
+
 	// These private fields are used only temporarily during decoding. Use
 	// method Parse to populate the exported fields from these, validating
 	// the raw values in the process.
-	targetsRaw      []string
-	forceReplaceRaw []string
-	destroyRaw      bool
-	refreshOnlyRaw  bool
+	targetsRaw            []string
+	forceReplaceRaw       []string
+	refreshFilterRaw      []string
+	refreshParallelismRaw []string
+	destroyRaw            bool
+	refreshOnlyRaw        bool
 }
 
 // Parse must be called on Operation after initial flag parse. This processes
@@ -96,33 +125,43 @@ type Operation struct {
 func (o *Operation) Parse() tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
-	o.Targets = nil
+	var moreDiags tfdiags.Diagnostics
+	o.Targets, moreDiags = parseTargetables(o.targetsRaw, "target")
+	diags = diags.Append(moreDiags)
 
-	for _, tr := range o.targetsRaw {
-		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(tr), "", hcl.Pos{Line: 1, Column: 1})
-		if syntaxDiags.HasErrors() {
-			diags = diags.Append(tfdiags.Sourceless(
-				tfdiags.Error,
-				fmt.Sprintf("Invalid target %q", tr),
-				syntaxDiags[0].Detail,
-			))
-			continue
-		}
+	o.RefreshFilter, moreDiags = parseTargetables(o.refreshFilterRaw, "refresh filter")
+	diags = diags.Append(moreDiags)
+	if len(o.RefreshFilter) != 0 && !o.Refresh {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible refresh options",
+			"The -refresh-filter option selects which resource instances to refresh, so it cannot be used at the same time as -refresh=false.",
+		))
+	}
 
-		target, targetDiags := addrs.ParseTarget(traversal)
-		if targetDiags.HasErrors() {
-			diags = diags.Append(tfdiags.Sourceless(
-				tfdiags.Error,
-				fmt.Sprintf("Invalid target %q", tr),
-				targetDiags[0].Description().Detail,
-			))
+	for _, raw := range o.forceReplaceRaw {
+		if addrs.IsResourceInstancePattern(raw) {
+			pattern, patternDiags := addrs.ParseAbsResourceInstancePattern(raw)
+			if patternDiags.HasErrors() {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					fmt.Sprintf("Invalid force-replace address %q", raw),
+					patternDiags[0].Description().Detail,
+				))
+				continue
+			}
+			if pattern.Mode != addrs.ManagedResourceMode {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					fmt.Sprintf("Invalid force-replace address %q", raw),
+					"Only managed resources can be used with the -replace=... option.",
+				))
+				continue
+			}
+			o.ForceReplacePatterns = append(o.ForceReplacePatterns, pattern)
 			continue
 		}
 
-		o.Targets = append(o.Targets, target.Subject)
-	}
-
-	for _, raw := range o.forceReplaceRaw {
 		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(raw), "", hcl.Pos{Line: 1, Column: 1})
 		if syntaxDiags.HasErrors() {
 			diags = diags.Append(tfdiags.Sourceless(
@@ -155,6 +194,41 @@ func (o *Operation) Parse() tfdiags.Diagnostics {
 		o.ForceReplace = append(o.ForceReplace, addr)
 	}
 
+	o.RefreshParallelism = nil
+	for _, raw := range o.refreshParallelismRaw {
+		providerRaw, limitRaw, ok := strings.Cut(raw, "=")
+		if !ok {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid refresh parallelism %q", raw),
+				"The -refresh-parallelism option requires a provider source address and a limit, like -refresh-parallelism=hashicorp/aws=4.",
+			))
+			continue
+		}
+		provider, providerDiags := addrs.ParseProviderSourceString(providerRaw)
+		if providerDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid refresh parallelism %q", raw),
+				providerDiags[0].Description().Detail,
+			))
+			continue
+		}
+		limit, err := strconv.Atoi(limitRaw)
+		if err != nil || limit < 1 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid refresh parallelism %q", raw),
+				"The refresh parallelism limit must be a whole number greater than zero.",
+			))
+			continue
+		}
+		if o.RefreshParallelism == nil {
+			o.RefreshParallelism = make(map[addrs.Provider]int)
+		}
+		o.RefreshParallelism[provider] = limit
+	}
+
 	// If you add a new possible value for o.PlanMode here, consider also
 	// adding a specialized error message for it in ParseApplyDestroy.
 	switch {
@@ -179,9 +253,51 @@ func (o *Operation) Parse() tfdiags.Diagnostics {
 		o.PlanMode = plans.NormalMode
 	}
 
+	if o.AdoptExisting && o.PlanMode != plans.NormalMode {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible plan mode options",
+			"The -adopt-existing option can only be used when planning in the normal planning mode, because the -destroy and -refresh-only modes never propose creating new objects.",
+		))
+	}
+
 	return diags
 }
 
+// parseTargetables parses each of the given raw address strings as a
+// targetable address. The noun is used to describe the kind of address in
+// any returned error messages.
+func parseTargetables(raws []string, noun string) ([]addrs.Targetable, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	var ret []addrs.Targetable
+
+	for _, raw := range raws {
+		traversal, syntaxDiags := hclsyntax.ParseTraversalAbs([]byte(raw), "", hcl.Pos{Line: 1, Column: 1})
+		if syntaxDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid %s %q", noun, raw),
+				syntaxDiags[0].Detail,
+			))
+			continue
+		}
+
+		target, targetDiags := addrs.ParseTarget(traversal)
+		if targetDiags.HasErrors() {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Invalid %s %q", noun, raw),
+				targetDiags[0].Description().Detail,
+			))
+			continue
+		}
+
+		ret = append(ret, target.Subject)
+	}
+
+	return ret, diags
+}
+
 // Vars describes arguments which specify non-default variable values. This
 // interfce is unfortunately obscure, because the order of the CLI arguments
 // determines the final value of the gathered variables. In future it might be
@@ -231,6 +347,10 @@ func extendedFlagSet(name string, state *State, operation *Operation, vars *Vars
 		f.BoolVar(&operation.refreshOnlyRaw, "refresh-only", false, "refresh-only")
 		f.Var((*flagStringSlice)(&operation.targetsRaw), "target", "target")
 		f.Var((*flagStringSlice)(&operation.forceReplaceRaw), "replace", "replace")
+		f.Var((*flagStringSlice)(&operation.refreshFilterRaw), "refresh-filter", "refresh-filter")
+		f.Var((*flagStringSlice)(&operation.refreshParallelismRaw), "refresh-parallelism", "refresh-parallelism")
+		f.BoolVar(&operation.AdoptExisting, "adopt-existing", false, "adopt-existing")
+		f.Var((*flagWarningCategories)(&operation.Strict), "strict", "strict")
 	}
 
 	// Gather all -var and -var-file arguments into one heterogenous structure
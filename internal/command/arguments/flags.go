@@ -8,6 +8,9 @@ package arguments
 import (
 	"flag"
 	"fmt"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 // flagStringSlice is a flag.Value implementation which allows collecting
@@ -26,6 +29,46 @@ func (v *flagStringSlice) Set(raw string) error {
 	return nil
 }
 
+// flagWarningCategories is a flag.Value implementation for the -strict flag,
+// which selects all of the warning categories when given alone, as in
+// -strict, or only some of them when given a comma-separated list, as in
+// -strict=deprecations,undeclared_var_files.
+type flagWarningCategories []tfdiags.WarningCategory
+
+var _ flag.Value = (*flagWarningCategories)(nil)
+
+func (v *flagWarningCategories) String() string {
+	return ""
+}
+
+func (v *flagWarningCategories) IsBoolFlag() bool {
+	return true
+}
+
+func (v *flagWarningCategories) Set(raw string) error {
+	switch raw {
+	case "true":
+		*v = append(*v, tfdiags.WarningCategories...)
+		return nil
+	case "false":
+		*v = nil
+		return nil
+	}
+
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if !tfdiags.ValidWarningCategory(name) {
+			names := make([]string, len(tfdiags.WarningCategories))
+			for i, category := range tfdiags.WarningCategories {
+				names[i] = string(category)
+			}
+			return fmt.Errorf("unsupported warning category %q; must be one of %s", name, strings.Join(names, ", "))
+		}
+		*v = append(*v, tfdiags.WarningCategory(name))
+	}
+	return nil
+}
+
 // flagNameValueSlice is a flag.Value implementation that appends raw flag
 // names and values to a slice. This is used to collect a sequence of flags
 // with possibly different names, preserving the overall order.
@@ -0,0 +1,49 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"net/url"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// CheckMetrics represents the command-line arguments for exporting the results
+// of the checks in the configuration as metrics, which are shared by the plan
+// and apply commands.
+type CheckMetrics struct {
+	// OutPath is the path of a file to write the metrics to, in the
+	// Prometheus text exposition format.
+	OutPath string
+
+	// PushURL is the URL of a Prometheus Pushgateway group, such as
+	// "http://localhost:9091/metrics/job/tofu", to push the metrics to.
+	PushURL string
+}
+
+// Enabled returns true if the metrics are to be written or pushed anywhere.
+func (m *CheckMetrics) Enabled() bool {
+	return m.OutPath != "" || m.PushURL != ""
+}
+
+// Parse validates the arguments, after the flags have been parsed.
+func (m *CheckMetrics) Parse() tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if m.PushURL == "" {
+		return diags
+	}
+
+	u, err := url.Parse(m.PushURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid metrics push URL",
+			"The -metrics-push-url option must be an http or https URL of a Prometheus Pushgateway, such as http://localhost:9091/metrics/job/tofu.",
+		))
+		m.PushURL = ""
+	}
+	return diags
+}
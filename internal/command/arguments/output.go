@@ -6,6 +6,8 @@
 package arguments
 
 import (
+	"fmt"
+
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -19,12 +21,39 @@ type Output struct {
 	// be loaded.
 	StatePath string
 
-	// ViewType specifies which output format to use: human, JSON, or "raw".
+	// ViewType specifies which output format to use: human, JSON, "raw", or
+	// environment variable assignments.
 	ViewType ViewType
 
+	// EnvFormat is the syntax of the environment variable assignments, when
+	// ViewType is ViewEnv.
+	EnvFormat EnvFormat
+
+	// ShowSensitive includes sensitive output values in the environment
+	// variable assignments, which otherwise omit them.
+	ShowSensitive bool
+
 	Vars *Vars
 }
 
+// EnvFormat is a syntax for rendering output values as environment variable
+// assignments.
+type EnvFormat string
+
+const (
+	// EnvFormatDotenv is the syntax of ".env" files, as read by many tools
+	// and libraries: KEY="value".
+	EnvFormatDotenv EnvFormat = "dotenv"
+
+	// EnvFormatShell is POSIX shell syntax for exporting variables, for use
+	// with eval: export KEY='value'.
+	EnvFormatShell EnvFormat = "shell"
+
+	// EnvFormatGitHubEnv is the syntax of the GITHUB_ENV file of GitHub
+	// Actions, which uses a heredoc-like syntax for multi-line values.
+	EnvFormatGitHubEnv EnvFormat = "github-env"
+)
+
 // ParseOutput processes CLI arguments, returning an Output value and errors.
 // If errors are encountered, an Output value is still returned representing
 // the best effort interpretation of the arguments.
@@ -35,11 +64,13 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 	}
 
 	var jsonOutput, rawOutput bool
-	var statePath string
+	var statePath, format string
 	cmdFlags := extendedFlagSet("output", nil, nil, output.Vars)
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.BoolVar(&rawOutput, "raw", false, "raw")
 	cmdFlags.StringVar(&statePath, "state", "", "path")
+	cmdFlags.StringVar(&format, "format", "", "format")
+	cmdFlags.BoolVar(&output.ShowSensitive, "show-sensitive", false, "show-sensitive")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -70,6 +101,47 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 		rawOutput = false
 	}
 
+	switch format {
+	case "":
+	case "human", "json", "raw":
+		// These are equivalent to the options for each format, so we just
+		// need to make sure they don't conflict with any of those options.
+		if (jsonOutput && format != "json") || (rawOutput && format != "raw") {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid output format",
+				fmt.Sprintf("The -format=%s option conflicts with the -json or -raw option.", format),
+			))
+			break
+		}
+		jsonOutput = format == "json"
+		rawOutput = format == "raw"
+	case string(EnvFormatDotenv), string(EnvFormatShell), string(EnvFormatGitHubEnv):
+		if jsonOutput || rawOutput {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid output format",
+				fmt.Sprintf("The -format=%s option conflicts with the -json or -raw option.", format),
+			))
+			break
+		}
+		output.EnvFormat = EnvFormat(format)
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			fmt.Sprintf("The -format option must be one of \"human\", \"json\", \"raw\", \"dotenv\", \"shell\", or \"github-env\", not %q.", format),
+		))
+	}
+
+	if output.ShowSensitive && output.EnvFormat == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid option",
+			"The -show-sensitive option can only be used with -format=dotenv, -format=shell, or -format=github-env.",
+		))
+	}
+
 	output.StatePath = statePath
 
 	if len(args) > 0 {
@@ -85,6 +157,8 @@ func ParseOutput(args []string) (*Output, tfdiags.Diagnostics) {
 	}
 
 	switch {
+	case output.EnvFormat != "":
+		output.ViewType = ViewEnv
 	case jsonOutput:
 		output.ViewType = ViewJSON
 	case rawOutput:
@@ -42,6 +42,30 @@ func TestParseOutput_valid(t *testing.T) {
 				StatePath: "",
 			},
 		},
+		"dotenv": {
+			[]string{"-format=dotenv"},
+			&Output{
+				Name:      "",
+				ViewType:  ViewEnv,
+				EnvFormat: EnvFormatDotenv,
+			},
+		},
+		"shell with sensitive": {
+			[]string{"-format=shell", "-show-sensitive", "foo"},
+			&Output{
+				Name:          "foo",
+				ViewType:      ViewEnv,
+				EnvFormat:     EnvFormatShell,
+				ShowSensitive: true,
+			},
+		},
+		"json format": {
+			[]string{"-format=json"},
+			&Output{
+				Name:     "",
+				ViewType: ViewJSON,
+			},
+		},
 		"state": {
 			[]string{"-state=foobar.tfstate", "-raw", "foo"},
 			&Output{
@@ -102,6 +126,50 @@ func TestParseOutput_invalid(t *testing.T) {
 				),
 			},
 		},
+		"env format with json": {
+			[]string{"-json", "-format=github-env"},
+			&Output{
+				Name:      "",
+				ViewType:  ViewJSON,
+				StatePath: "",
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					"The -format=github-env option conflicts with the -json or -raw option.",
+				),
+			},
+		},
+		"unsupported format": {
+			[]string{"-format=yaml"},
+			&Output{
+				Name:     "",
+				ViewType: ViewHuman,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					`The -format option must be one of "human", "json", "raw", "dotenv", "shell", or "github-env", not "yaml".`,
+				),
+			},
+		},
+		"show-sensitive without env format": {
+			[]string{"-show-sensitive"},
+			&Output{
+				Name:          "",
+				ViewType:      ViewHuman,
+				ShowSensitive: true,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid option",
+					"The -show-sensitive option can only be used with -format=dotenv, -format=shell, or -format=github-env.",
+				),
+			},
+		},
 		"raw with no name": {
 			[]string{"-raw"},
 			&Output{
@@ -27,11 +27,45 @@ type Plan struct {
 	// OutPath contains an optional path to store the plan file
 	OutPath string
 
+	// SignKeyPath contains an optional path to an OpenPGP private key to
+	// sign the plan file with. It requires OutPath.
+	SignKeyPath string
+
 	// GenerateConfigPath tells OpenTofu that config should be generated for
 	// unmatched import target paths and which path the generated file should
 	// be written to.
 	GenerateConfigPath string
 
+	// Timings enables a report of how long OpenTofu spent on each resource
+	// instance once the operation completes.
+	Timings bool
+
+	// Metrics tells the command where to export the results of the checks
+	// in the configuration as metrics.
+	Metrics *CheckMetrics
+
+	// ProfileDir is an optional directory to write CPU, heap, and goroutine
+	// profiles to, along with a summary of the slowest graph nodes.
+	ProfileDir string
+
+	// Incremental reuses the responses of providers to plan requests from
+	// the previous incremental plan in the same working directory, when a
+	// request is identical to one they answered before.
+	Incremental bool
+
+	// IncrementalReset ignores the responses kept by earlier incremental
+	// plans, so that every change is planned in full, and keeps the new
+	// responses for the next incremental plan.
+	IncrementalReset bool
+
+	// ConfigRef optionally selects a git ref to fetch the configuration
+	// from, instead of using the configuration in the working directory.
+	ConfigRef *ConfigRef
+
+	// TUI shows the progress of the operation in a full-screen, interactive
+	// display instead of the scrolling text logs.
+	TUI bool
+
 	// ViewType specifies which output format to use
 	ViewType ViewType
 }
@@ -45,13 +79,26 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 		State:     &State{},
 		Operation: &Operation{},
 		Vars:      &Vars{},
+		Metrics:   &CheckMetrics{},
+		ConfigRef: &ConfigRef{},
 	}
 
 	cmdFlags := extendedFlagSet("plan", plan.State, plan.Operation, plan.Vars)
 	cmdFlags.BoolVar(&plan.DetailedExitCode, "detailed-exitcode", false, "detailed-exitcode")
 	cmdFlags.BoolVar(&plan.InputEnabled, "input", true, "input")
 	cmdFlags.StringVar(&plan.OutPath, "out", "", "out")
+	cmdFlags.StringVar(&plan.SignKeyPath, "sign-key", "", "sign-key")
 	cmdFlags.StringVar(&plan.GenerateConfigPath, "generate-config-out", "", "generate-config-out")
+	cmdFlags.BoolVar(&plan.Timings, "timings", false, "timings")
+	cmdFlags.StringVar(&plan.Metrics.OutPath, "metrics-out", "", "metrics-out")
+	cmdFlags.StringVar(&plan.Metrics.PushURL, "metrics-push-url", "", "metrics-push-url")
+	cmdFlags.StringVar(&plan.ProfileDir, "profile", "", "profile")
+	cmdFlags.BoolVar(&plan.Incremental, "incremental", false, "incremental")
+	cmdFlags.BoolVar(&plan.IncrementalReset, "incremental-reset", false, "incremental-reset")
+	cmdFlags.StringVar(&plan.ConfigRef.Address, "config-ref", "", "config-ref")
+	cmdFlags.BoolVar(&plan.ConfigRef.VerifySignature, "verify-config-ref", false, "verify-config-ref")
+
+	cmdFlags.BoolVar(&plan.TUI, "tui", false, "tui")
 
 	var json bool
 	cmdFlags.BoolVar(&json, "json", false, "json")
@@ -74,7 +121,33 @@ func ParsePlan(args []string) (*Plan, tfdiags.Diagnostics) {
 		))
 	}
 
+	if plan.SignKeyPath != "" && plan.OutPath == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan file required for signing",
+			"The -sign-key option signs the saved plan file, so it requires the -out option.",
+		))
+	}
+
 	diags = diags.Append(plan.Operation.Parse())
+	diags = diags.Append(plan.Metrics.Parse())
+	diags = diags.Append(plan.ConfigRef.Parse())
+
+	if plan.ConfigRef.Enabled() && plan.GenerateConfigPath != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible plan options",
+			"The -generate-config-out option cannot be used with -config-ref, because the configuration from the ref can't be changed.",
+		))
+	}
+
+	if json && plan.TUI {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible output options",
+			"The -json and -tui options cannot be used together.",
+		))
+	}
 
 	// JSON view currently does not support input, so we disable it here
 	if json {
@@ -13,6 +13,7 @@ import (
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 func TestParsePlan_basicValid(t *testing.T) {
@@ -29,6 +30,8 @@ func TestParsePlan_basicValid(t *testing.T) {
 				ViewType:         ViewHuman,
 				State:            &State{Lock: true},
 				Vars:             &Vars{},
+				Metrics:          &CheckMetrics{},
+				ConfigRef:        &ConfigRef{},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -45,6 +48,8 @@ func TestParsePlan_basicValid(t *testing.T) {
 				ViewType:         ViewHuman,
 				State:            &State{Lock: true},
 				Vars:             &Vars{},
+				Metrics:          &CheckMetrics{},
+				ConfigRef:        &ConfigRef{},
 				Operation: &Operation{
 					PlanMode:    plans.DestroyMode,
 					Parallelism: 10,
@@ -52,6 +57,74 @@ func TestParsePlan_basicValid(t *testing.T) {
 				},
 			},
 		},
+		"timings": {
+			[]string{"-timings"},
+			&Plan{
+				InputEnabled: true,
+				Timings:      true,
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Metrics:      &CheckMetrics{},
+				ConfigRef:    &ConfigRef{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"profile": {
+			[]string{"-profile=profiles"},
+			&Plan{
+				InputEnabled: true,
+				ProfileDir:   "profiles",
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Metrics:      &CheckMetrics{},
+				ConfigRef:    &ConfigRef{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"incremental": {
+			[]string{"-incremental"},
+			&Plan{
+				InputEnabled: true,
+				Incremental:  true,
+				ViewType:     ViewHuman,
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Metrics:      &CheckMetrics{},
+				ConfigRef:    &ConfigRef{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
+		"incremental reset": {
+			[]string{"-incremental-reset"},
+			&Plan{
+				InputEnabled:     true,
+				IncrementalReset: true,
+				ViewType:         ViewHuman,
+				State:            &State{Lock: true},
+				Vars:             &Vars{},
+				Metrics:          &CheckMetrics{},
+				ConfigRef:        &ConfigRef{},
+				Operation: &Operation{
+					PlanMode:    plans.NormalMode,
+					Parallelism: 10,
+					Refresh:     true,
+				},
+			},
+		},
 		"JSON view disables input": {
 			[]string{"-json"},
 			&Plan{
@@ -61,6 +134,8 @@ func TestParsePlan_basicValid(t *testing.T) {
 				ViewType:         ViewJSON,
 				State:            &State{Lock: true},
 				Vars:             &Vars{},
+				Metrics:          &CheckMetrics{},
+				ConfigRef:        &ConfigRef{},
 				Operation: &Operation{
 					PlanMode:    plans.NormalMode,
 					Parallelism: 10,
@@ -160,6 +235,57 @@ func TestParsePlan_targets(t *testing.T) {
 	}
 }
 
+func TestParsePlan_refreshFilter(t *testing.T) {
+	foobarbaz, _ := addrs.ParseTargetStr("foo_bar.baz")
+	boop, _ := addrs.ParseTargetStr("module.boop")
+	testCases := map[string]struct {
+		args    []string
+		want    []addrs.Targetable
+		wantErr string
+	}{
+		"no refresh filter by default": {
+			args: nil,
+			want: nil,
+		},
+		"one filter": {
+			args: []string{"-refresh-filter=foo_bar.baz"},
+			want: []addrs.Targetable{foobarbaz.Subject},
+		},
+		"two filters": {
+			args: []string{"-refresh-filter=foo_bar.baz", "-refresh-filter", "module.boop"},
+			want: []addrs.Targetable{foobarbaz.Subject, boop.Subject},
+		},
+		"invalid filter": {
+			args:    []string{"-refresh-filter=data[0].foo"},
+			want:    nil,
+			wantErr: "A data source name is required",
+		},
+		"refresh disabled": {
+			args:    []string{"-refresh-filter=foo_bar.baz", "-refresh=false"},
+			want:    []addrs.Targetable{foobarbaz.Subject},
+			wantErr: "cannot be used at the same time as -refresh=false",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if len(diags) > 0 {
+				if tc.wantErr == "" {
+					t.Fatalf("unexpected diags: %v", diags)
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+			} else if tc.wantErr != "" {
+				t.Fatalf("expected diags but got none")
+			}
+			if !cmp.Equal(got.Operation.RefreshFilter, tc.want) {
+				t.Fatalf("unexpected result\n%s", cmp.Diff(got.Operation.RefreshFilter, tc.want))
+			}
+		})
+	}
+}
+
 func TestParsePlan_vars(t *testing.T) {
 	testCases := map[string]struct {
 		args []string
@@ -210,3 +336,244 @@ func TestParsePlan_vars(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePlan_refreshParallelism(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		want    map[addrs.Provider]int
+		wantErr string
+	}{
+		"no limits by default": {
+			args: nil,
+			want: nil,
+		},
+		"one provider": {
+			args: []string{"-refresh-parallelism=hashicorp/aws=4"},
+			want: map[addrs.Provider]int{
+				addrs.NewDefaultProvider("aws"): 4,
+			},
+		},
+		"two providers": {
+			args: []string{"-refresh-parallelism=aws=4", "-refresh-parallelism", "example.com/foo/bar=1"},
+			want: map[addrs.Provider]int{
+				addrs.NewDefaultProvider("aws"):                4,
+				addrs.NewProvider("example.com", "foo", "bar"): 1,
+			},
+		},
+		"missing limit": {
+			args:    []string{"-refresh-parallelism=hashicorp/aws"},
+			wantErr: "requires a provider source address and a limit",
+		},
+		"invalid limit": {
+			args:    []string{"-refresh-parallelism=hashicorp/aws=0"},
+			wantErr: "must be a whole number greater than zero",
+		},
+		"invalid provider": {
+			args:    []string{"-refresh-parallelism=a/b/c/d=2"},
+			wantErr: "Invalid refresh parallelism",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if len(diags) > 0 {
+				if tc.wantErr == "" {
+					t.Fatalf("unexpected diags: %v", diags)
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+				return
+			} else if tc.wantErr != "" {
+				t.Fatalf("expected diags but got none")
+			}
+			if !cmp.Equal(got.Operation.RefreshParallelism, tc.want) {
+				t.Fatalf("unexpected result\n%s", cmp.Diff(got.Operation.RefreshParallelism, tc.want))
+			}
+		})
+	}
+}
+
+func TestParsePlan_adoptExisting(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		want    bool
+		wantErr string
+	}{
+		"disabled by default": {
+			args: nil,
+			want: false,
+		},
+		"enabled": {
+			args: []string{"-adopt-existing"},
+			want: true,
+		},
+		"with destroy": {
+			args:    []string{"-adopt-existing", "-destroy"},
+			wantErr: "can only be used when planning in the normal planning mode",
+		},
+		"with refresh-only": {
+			args:    []string{"-adopt-existing", "-refresh-only"},
+			wantErr: "can only be used when planning in the normal planning mode",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if len(diags) > 0 {
+				if tc.wantErr == "" {
+					t.Fatalf("unexpected diags: %v", diags)
+				} else if got := diags.Err().Error(); !strings.Contains(got, tc.wantErr) {
+					t.Fatalf("wrong diags\n got: %s\nwant: %s", got, tc.wantErr)
+				}
+				return
+			} else if tc.wantErr != "" {
+				t.Fatalf("expected diags but got none")
+			}
+			if got.Operation.AdoptExisting != tc.want {
+				t.Fatalf("wrong result %t; want %t", got.Operation.AdoptExisting, tc.want)
+			}
+		})
+	}
+}
+
+func TestParsePlan_tui(t *testing.T) {
+	got, diags := ParsePlan([]string{"-tui"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if !got.TUI || got.ViewType != ViewHuman {
+		t.Fatalf("wrong result: TUI %t, view type %s", got.TUI, got.ViewType)
+	}
+
+	_, diags = ParsePlan([]string{"-tui", "-json"})
+	if got, want := diags.Err().Error(), "The -json and -tui options cannot be used together."; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestParsePlan_signKey(t *testing.T) {
+	got, diags := ParsePlan([]string{"-out=saved.tfplan", "-sign-key=key.asc"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	if got.SignKeyPath != "key.asc" {
+		t.Fatalf("wrong sign key path %q", got.SignKeyPath)
+	}
+
+	_, diags = ParsePlan([]string{"-sign-key=key.asc"})
+	if got, want := diags.Err().Error(), "Plan file required for signing"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestParsePlan_strict(t *testing.T) {
+	testCases := map[string]struct {
+		args []string
+		want []tfdiags.WarningCategory
+	}{
+		"disabled by default": {
+			args: nil,
+			want: nil,
+		},
+		"all categories": {
+			args: []string{"-strict"},
+			want: tfdiags.WarningCategories,
+		},
+		"selected categories": {
+			args: []string{"-strict=implicit_provider_inheritance"},
+			want: []tfdiags.WarningCategory{tfdiags.WarningImplicitProviderInheritance},
+		},
+		"disabled explicitly": {
+			args: []string{"-strict", "-strict=false"},
+			want: nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			}
+			if !cmp.Equal(got.Operation.Strict, tc.want) {
+				t.Fatalf("unexpected result\n%s", cmp.Diff(got.Operation.Strict, tc.want))
+			}
+		})
+	}
+}
+
+func TestParsePlan_metrics(t *testing.T) {
+	got, diags := ParsePlan([]string{"-metrics-out=checks.prom", "-metrics-push-url=http://localhost:9091/metrics/job/tofu"})
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diags: %v", diags)
+	}
+	want := &CheckMetrics{
+		OutPath: "checks.prom",
+		PushURL: "http://localhost:9091/metrics/job/tofu",
+	}
+	if diff := cmp.Diff(want, got.Metrics); diff != "" {
+		t.Fatalf("unexpected result\n%s", diff)
+	}
+
+	got, diags = ParsePlan([]string{"-metrics-push-url=localhost:9091"})
+	if got, want := diags.Err().Error(), "Invalid metrics push URL"; !strings.Contains(got, want) {
+		t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+	}
+	if got.Metrics.Enabled() {
+		t.Fatalf("metrics should be disabled after an invalid push URL")
+	}
+}
+
+func TestParsePlan_configRef(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		want    *ConfigRef
+		wantErr string
+	}{
+		"ref": {
+			args: []string{"-config-ref=git::https://example.com/infra.git?ref=v1.2.3", "-verify-config-ref"},
+			want: &ConfigRef{
+				Address:         "git::https://example.com/infra.git?ref=v1.2.3",
+				VerifySignature: true,
+			},
+		},
+		"no ref": {
+			args:    []string{"-config-ref=git::https://example.com/infra.git"},
+			want:    &ConfigRef{},
+			wantErr: "Invalid config ref",
+		},
+		"not git": {
+			args:    []string{"-config-ref=https://example.com/infra.zip?ref=v1"},
+			want:    &ConfigRef{},
+			wantErr: "Invalid config ref",
+		},
+		"verify without ref": {
+			args:    []string{"-verify-config-ref"},
+			want:    &ConfigRef{VerifySignature: true},
+			wantErr: "Config ref required for verification",
+		},
+		"with -generate-config-out": {
+			args:    []string{"-config-ref=git::https://example.com/infra.git?ref=v1", "-generate-config-out=foo.tf"},
+			want:    &ConfigRef{Address: "git::https://example.com/infra.git?ref=v1"},
+			wantErr: "Incompatible plan options",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParsePlan(tc.args)
+			if tc.wantErr == "" {
+				if len(diags) > 0 {
+					t.Fatalf("unexpected diags: %v", diags)
+				}
+			} else if got, want := diags.Err().Error(), tc.wantErr; !strings.Contains(got, want) {
+				t.Fatalf("wrong diags\n got: %s\nwant: %s", got, want)
+			}
+			if diff := cmp.Diff(tc.want, got.ConfigRef); diff != "" {
+				t.Fatalf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
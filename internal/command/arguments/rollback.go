@@ -0,0 +1,81 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Rollback represents the command-line arguments for the rollback command.
+type Rollback struct {
+	// State and Vars are the common extended flags
+	State *State
+	Vars  *Vars
+
+	// Snapshot is the id of the snapshot to roll back to. If empty, the most
+	// recent snapshot is used.
+	Snapshot string
+
+	// List, if set, lists the available snapshots instead of planning a
+	// rollback.
+	List bool
+
+	// OutPath contains an optional path to store the rollback plan file
+	OutPath string
+
+	// InputEnabled is used to disable interactive input for unspecified
+	// variable and backend config values. Default is true.
+	InputEnabled bool
+
+	// Parallelism is the limit OpenTofu places on total parallel operations
+	// as it walks the dependency graph.
+	Parallelism int
+}
+
+// ParseRollback processes CLI arguments, returning a Rollback value and
+// errors. If errors are encountered, a Rollback value is still returned
+// representing the best effort interpretation of the arguments.
+func ParseRollback(args []string) (*Rollback, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	rollback := &Rollback{
+		State: &State{},
+		Vars:  &Vars{},
+	}
+
+	cmdFlags := extendedFlagSet("rollback", rollback.State, nil, rollback.Vars)
+	cmdFlags.StringVar(&rollback.Snapshot, "snapshot", "", "snapshot")
+	cmdFlags.BoolVar(&rollback.List, "list", false, "list")
+	cmdFlags.StringVar(&rollback.OutPath, "out", "", "out")
+	cmdFlags.BoolVar(&rollback.InputEnabled, "input", true, "input")
+	cmdFlags.IntVar(&rollback.Parallelism, "parallelism", DefaultParallelism, "parallelism")
+
+	if err := cmdFlags.Parse(args); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to parse command-line flags",
+			err.Error(),
+		))
+	}
+
+	args = cmdFlags.Args()
+	if len(args) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Too many command line arguments",
+			"To specify a working directory for the rollback, use the global -chdir flag.",
+		))
+	}
+
+	if rollback.List && (rollback.Snapshot != "" || rollback.OutPath != "") {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible rollback options",
+			"The -list option only lists the available snapshots, so it cannot be used with -snapshot or -out.",
+		))
+	}
+
+	return rollback, diags
+}
@@ -0,0 +1,98 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package arguments
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestParseRollback_valid(t *testing.T) {
+	testCases := map[string]struct {
+		args []string
+		want *Rollback
+	}{
+		"defaults": {
+			nil,
+			&Rollback{
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				InputEnabled: true,
+				Parallelism:  DefaultParallelism,
+			},
+		},
+		"snapshot and out": {
+			[]string{"-snapshot=20240101T000000Z-3", "-out=rollback.tfplan", "-input=false"},
+			&Rollback{
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				Snapshot:     "20240101T000000Z-3",
+				OutPath:      "rollback.tfplan",
+				InputEnabled: false,
+				Parallelism:  DefaultParallelism,
+			},
+		},
+		"list": {
+			[]string{"-list"},
+			&Rollback{
+				State:        &State{Lock: true},
+				Vars:         &Vars{},
+				List:         true,
+				InputEnabled: true,
+				Parallelism:  DefaultParallelism,
+			},
+		},
+	}
+
+	cmpOpts := cmpopts.IgnoreUnexported(Vars{}, State{})
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			got, diags := ParseRollback(tc.args)
+			if len(diags) > 0 {
+				t.Fatalf("unexpected diags: %v", diags)
+			}
+			if diff := cmp.Diff(tc.want, got, cmpOpts); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseRollback_invalid(t *testing.T) {
+	testCases := map[string]struct {
+		args    []string
+		wantErr string
+	}{
+		"unknown flag": {
+			[]string{"-boop"},
+			"flag provided but not defined: -boop",
+		},
+		"positional argument": {
+			[]string{"foo"},
+			"Too many command line arguments",
+		},
+		"list with snapshot": {
+			[]string{"-list", "-snapshot=foo"},
+			"The -list option only lists the available snapshots",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, diags := ParseRollback(tc.args)
+			if !diags.HasErrors() {
+				t.Fatal("expected errors")
+			}
+			if got := diags.ErrWithWarnings().Error(); !strings.Contains(got, tc.wantErr) {
+				t.Fatalf("wrong error\n got: %s\nwant: %s", got, tc.wantErr)
+			}
+		})
+	}
+}
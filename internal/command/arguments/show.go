@@ -6,6 +6,8 @@
 package arguments
 
 import (
+	"fmt"
+
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -15,9 +17,17 @@ type Show struct {
 	// unspecified, show will display the latest state snapshot.
 	Path string
 
-	// ViewType specifies which output format to use: human, JSON, or "raw".
+	// ViewType specifies which output format to use: human, JSON, or HTML.
 	ViewType ViewType
 
+	// Modules is true if the installed modules should be displayed instead
+	// of a state or plan file.
+	Modules bool
+
+	// ValidateSchema is true if the JSON output should be checked against
+	// the published schema for its format before it's displayed.
+	ValidateSchema bool
+
 	Vars *Vars
 }
 
@@ -32,8 +42,12 @@ func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
 	}
 
 	var jsonOutput bool
+	var format string
 	cmdFlags := extendedFlagSet("show", nil, nil, show.Vars)
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.StringVar(&format, "format", "", "format")
+	cmdFlags.BoolVar(&show.Modules, "modules", false, "modules")
+	cmdFlags.BoolVar(&show.ValidateSchema, "validate-schema", false, "validate-schema")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -56,12 +70,63 @@ func ParseShow(args []string) (*Show, tfdiags.Diagnostics) {
 		show.Path = args[0]
 	}
 
-	switch {
-	case jsonOutput:
+	switch format {
+	case "", "human":
+		show.ViewType = ViewHuman
+	case "json":
 		show.ViewType = ViewJSON
+	case "html":
+		show.ViewType = ViewHTML
 	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			fmt.Sprintf("The -format option must be \"human\", \"json\", or \"html\", not %q.", format),
+		))
 		show.ViewType = ViewHuman
 	}
 
+	if jsonOutput {
+		if show.ViewType != ViewHuman && show.ViewType != ViewJSON {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible command-line options",
+				fmt.Sprintf("The -json option cannot be used with -format=%s.", format),
+			))
+		}
+		show.ViewType = ViewJSON
+	}
+
+	if show.Modules {
+		if show.Path != "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible command-line options",
+				"The -modules option cannot be used with the path of a state or plan file.",
+			))
+		}
+		if show.ViewType == ViewHTML {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible command-line options",
+				"The -modules option cannot be used with -format=html.",
+			))
+		}
+	} else if show.ViewType == ViewHTML && show.Path == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Plan file required",
+			"You must give the path of a saved plan file when using -format=html.",
+		))
+	}
+
+	if show.ValidateSchema && (show.ViewType != ViewJSON || show.Modules) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible command-line options",
+			"The -validate-schema option can only be used with -json, to show a state or plan file.",
+		))
+	}
+
 	return show, diags
 }
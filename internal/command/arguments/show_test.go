@@ -39,6 +39,42 @@ func TestParseShow_valid(t *testing.T) {
 				ViewType: ViewJSON,
 			},
 		},
+		"format json": {
+			[]string{"-format=json", "foo"},
+			&Show{
+				Path:     "foo",
+				ViewType: ViewJSON,
+			},
+		},
+		"format html": {
+			[]string{"-format=html", "plan.out"},
+			&Show{
+				Path:     "plan.out",
+				ViewType: ViewHTML,
+			},
+		},
+		"modules": {
+			[]string{"-modules"},
+			&Show{
+				ViewType: ViewHuman,
+				Modules:  true,
+			},
+		},
+		"modules json": {
+			[]string{"-modules", "-json"},
+			&Show{
+				ViewType: ViewJSON,
+				Modules:  true,
+			},
+		},
+		"validate schema": {
+			[]string{"-json", "-validate-schema", "plan.out"},
+			&Show{
+				Path:           "plan.out",
+				ViewType:       ViewJSON,
+				ValidateSchema: true,
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -89,6 +125,106 @@ func TestParseShow_invalid(t *testing.T) {
 				),
 			},
 		},
+		"invalid format": {
+			[]string{"-format=yaml", "foo"},
+			&Show{
+				Path:     "foo",
+				ViewType: ViewHuman,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					`The -format option must be "human", "json", or "html", not "yaml".`,
+				),
+			},
+		},
+		"json and html": {
+			[]string{"-json", "-format=html", "foo"},
+			&Show{
+				Path:     "foo",
+				ViewType: ViewJSON,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Incompatible command-line options",
+					"The -json option cannot be used with -format=html.",
+				),
+			},
+		},
+		"html without path": {
+			[]string{"-format=html"},
+			&Show{
+				Path:     "",
+				ViewType: ViewHTML,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Plan file required",
+					"You must give the path of a saved plan file when using -format=html.",
+				),
+			},
+		},
+		"modules with path": {
+			[]string{"-modules", "foo"},
+			&Show{
+				Path:     "foo",
+				ViewType: ViewHuman,
+				Modules:  true,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Incompatible command-line options",
+					"The -modules option cannot be used with the path of a state or plan file.",
+				),
+			},
+		},
+		"modules html": {
+			[]string{"-modules", "-format=html"},
+			&Show{
+				ViewType: ViewHTML,
+				Modules:  true,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Incompatible command-line options",
+					"The -modules option cannot be used with -format=html.",
+				),
+			},
+		},
+		"validate schema without json": {
+			[]string{"-validate-schema"},
+			&Show{
+				ViewType:       ViewHuman,
+				ValidateSchema: true,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Incompatible command-line options",
+					"The -validate-schema option can only be used with -json, to show a state or plan file.",
+				),
+			},
+		},
+		"validate schema modules": {
+			[]string{"-modules", "-json", "-validate-schema"},
+			&Show{
+				ViewType:       ViewJSON,
+				Modules:        true,
+				ValidateSchema: true,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Incompatible command-line options",
+					"The -validate-schema option can only be used with -json, to show a state or plan file.",
+				),
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -6,6 +6,8 @@
 package arguments
 
 import (
+	"path/filepath"
+
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -22,7 +24,7 @@ type Test struct {
 	// always be discovered.
 	TestDirectory string
 
-	// ViewType specifies which output format to use: human or JSON.
+	// ViewType specifies which output format to use: human, JSON, or SARIF.
 	ViewType ViewType
 
 	// You can specify common variables for all tests from the command line.
@@ -32,6 +34,41 @@ type Test struct {
 	// human-readable format or JSON for each run step depending on the
 	// ViewType.
 	Verbose bool
+
+	// Parallel is the number of test files, and of run blocks marked as
+	// parallel within a test file, that can execute at the same time. It
+	// defaults to 1, which executes everything sequentially.
+	Parallel int
+
+	// Record tells the test command to record the interactions with the
+	// providers for each test file into a file next to it, and Replay tells
+	// it to replay them from that file instead of calling the providers.
+	Record bool
+	Replay bool
+
+	// JUnitXMLPath is the path of a file to write the results of the tests
+	// to in JUnit XML format, if set.
+	JUnitXMLPath string
+
+	// BackendState tells the test command to start each test file from the
+	// state of the current workspace in the configured backend, instead of
+	// from an empty state. The state is never written back, so only run
+	// blocks that execute a plan are allowed against the module under test.
+	BackendState bool
+
+	// Coverage tells the test command to report which parts of the module
+	// under test were exercised by the test suite. CoverageReport is the path
+	// of a JSON or HTML file to write the report to, and CoverageThreshold is
+	// the percentage of coverage below which the test command fails. Setting
+	// either of them also enables Coverage.
+	Coverage          bool
+	CoverageReport    string
+	CoverageThreshold float64
+
+	// Quarantine contains the test files, or run blocks in the form
+	// "<file>/<run>", that are known to be flaky. Their failures are still
+	// reported, but don't fail the test suite.
+	Quarantine []string
 }
 
 func ParseTest(args []string) (*Test, tfdiags.Diagnostics) {
@@ -42,11 +79,22 @@ func ParseTest(args []string) (*Test, tfdiags.Diagnostics) {
 	}
 
 	var jsonOutput bool
+	var format string
 	cmdFlags := extendedFlagSet("test", nil, nil, test.Vars)
 	cmdFlags.Var((*flagStringSlice)(&test.Filter), "filter", "filter")
 	cmdFlags.StringVar(&test.TestDirectory, "test-directory", configs.DefaultTestDirectory, "test-directory")
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.StringVar(&format, "format", "", "format")
 	cmdFlags.BoolVar(&test.Verbose, "verbose", false, "verbose")
+	cmdFlags.IntVar(&test.Parallel, "parallel", 1, "parallel")
+	cmdFlags.BoolVar(&test.Record, "record", false, "record")
+	cmdFlags.BoolVar(&test.Replay, "replay", false, "replay")
+	cmdFlags.StringVar(&test.JUnitXMLPath, "junit-xml", "", "junit-xml")
+	cmdFlags.BoolVar(&test.BackendState, "backend-state", false, "backend-state")
+	cmdFlags.BoolVar(&test.Coverage, "coverage", false, "coverage")
+	cmdFlags.StringVar(&test.CoverageReport, "coverage-report", "", "coverage-report")
+	cmdFlags.Float64Var(&test.CoverageThreshold, "coverage-threshold", 0, "coverage-threshold")
+	cmdFlags.Var((*flagStringSlice)(&test.Quarantine), "quarantine", "quarantine")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -55,12 +103,49 @@ func ParseTest(args []string) (*Test, tfdiags.Diagnostics) {
 			err.Error()))
 	}
 
-	switch {
-	case jsonOutput:
-		test.ViewType = ViewJSON
-	default:
-		test.ViewType = ViewHuman
+	if test.Parallel < 1 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid parallel value",
+			"The -parallel option must be at least 1."))
+		test.Parallel = 1
+	}
+
+	if test.Record && test.Replay {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Incompatible test options",
+			"The -record option cannot be used with -replay."))
+		test.Replay = false
+	}
+
+	if test.CoverageThreshold < 0 || test.CoverageThreshold > 100 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid coverage threshold",
+			"The -coverage-threshold option must be a percentage between 0 and 100."))
+		test.CoverageThreshold = 0
 	}
 
+	if test.CoverageReport != "" {
+		switch filepath.Ext(test.CoverageReport) {
+		case ".json", ".html":
+		default:
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid coverage report",
+				"The -coverage-report option must be a path ending in .json or .html."))
+			test.CoverageReport = ""
+		}
+	}
+
+	if test.CoverageReport != "" || test.CoverageThreshold > 0 {
+		test.Coverage = true
+	}
+
+	viewType, formatDiags := parseDiagnosticsFormat(format, jsonOutput)
+	diags = diags.Append(formatDiags)
+	test.ViewType = viewType
+
 	return &test, diags
 }
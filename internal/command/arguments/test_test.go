@@ -78,6 +78,7 @@ func TestParseTest(t *testing.T) {
 			want: &Test{
 				Filter:        nil,
 				TestDirectory: "tests",
+				Parallel:      1,
 				ViewType:      ViewHuman,
 				Vars:          &Vars{},
 			},
@@ -88,6 +89,7 @@ func TestParseTest(t *testing.T) {
 			want: &Test{
 				Filter:        []string{"one.tftest.hcl", "two.tftest.hcl"},
 				TestDirectory: "tests",
+				Parallel:      1,
 				ViewType:      ViewHuman,
 				Vars:          &Vars{},
 			},
@@ -98,16 +100,29 @@ func TestParseTest(t *testing.T) {
 			want: &Test{
 				Filter:        nil,
 				TestDirectory: "tests",
+				Parallel:      1,
 				ViewType:      ViewJSON,
 				Vars:          &Vars{},
 			},
 			wantDiags: nil,
 		},
+		"sarif": {
+			args: []string{"-format=sarif"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				Parallel:      1,
+				ViewType:      ViewSARIF,
+				Vars:          &Vars{},
+			},
+			wantDiags: nil,
+		},
 		"test-directory": {
 			args: []string{"-test-directory=other"},
 			want: &Test{
 				Filter:        nil,
 				TestDirectory: "other",
+				Parallel:      1,
 				ViewType:      ViewHuman,
 				Vars:          &Vars{},
 			},
@@ -118,16 +133,165 @@ func TestParseTest(t *testing.T) {
 			want: &Test{
 				Filter:        nil,
 				TestDirectory: "tests",
+				Parallel:      1,
 				ViewType:      ViewHuman,
 				Verbose:       true,
 				Vars:          &Vars{},
 			},
 		},
+		"parallel": {
+			args: []string{"-parallel=4"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallel:      4,
+				Vars:          &Vars{},
+			},
+		},
+		"invalid parallel": {
+			args: []string{"-parallel=0"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallel:      1,
+				Vars:          &Vars{},
+			},
+			wantDiags: tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid parallel value",
+					"The -parallel option must be at least 1.",
+				),
+			},
+		},
+		"record": {
+			args: []string{"-record"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallel:      1,
+				Record:        true,
+				Vars:          &Vars{},
+			},
+		},
+		"record and replay": {
+			args: []string{"-record", "-replay"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallel:      1,
+				Record:        true,
+				Vars:          &Vars{},
+			},
+			wantDiags: tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Incompatible test options",
+					"The -record option cannot be used with -replay.",
+				),
+			},
+		},
+		"junit-xml": {
+			args: []string{"-junit-xml=results.xml"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallel:      1,
+				JUnitXMLPath:  "results.xml",
+				Vars:          &Vars{},
+			},
+		},
+		"backend-state": {
+			args: []string{"-backend-state"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallel:      1,
+				BackendState:  true,
+				Vars:          &Vars{},
+			},
+		},
+		"coverage": {
+			args: []string{"-coverage"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallel:      1,
+				Coverage:      true,
+				Vars:          &Vars{},
+			},
+		},
+		"coverage report and threshold": {
+			args: []string{"-coverage-report=coverage.html", "-coverage-threshold=80"},
+			want: &Test{
+				Filter:            nil,
+				TestDirectory:     "tests",
+				ViewType:          ViewHuman,
+				Parallel:          1,
+				Coverage:          true,
+				CoverageReport:    "coverage.html",
+				CoverageThreshold: 80,
+				Vars:              &Vars{},
+			},
+		},
+		"invalid coverage report": {
+			args: []string{"-coverage-report=coverage.txt"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallel:      1,
+				Vars:          &Vars{},
+			},
+			wantDiags: tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid coverage report",
+					"The -coverage-report option must be a path ending in .json or .html.",
+				),
+			},
+		},
+		"invalid coverage threshold": {
+			args: []string{"-coverage-threshold=101"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallel:      1,
+				Vars:          &Vars{},
+			},
+			wantDiags: tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid coverage threshold",
+					"The -coverage-threshold option must be a percentage between 0 and 100.",
+				),
+			},
+		},
+		"quarantine": {
+			args: []string{"-quarantine=flaky.tftest.hcl", "-quarantine=main.tftest.hcl/first"},
+			want: &Test{
+				Filter:        nil,
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Parallel:      1,
+				Quarantine:    []string{"flaky.tftest.hcl", "main.tftest.hcl/first"},
+				Vars:          &Vars{},
+			},
+		},
 		"unknown flag": {
 			args: []string{"-boop"},
 			want: &Test{
 				Filter:        nil,
 				TestDirectory: "tests",
+				Parallel:      1,
 				ViewType:      ViewHuman,
 				Vars:          &Vars{},
 			},
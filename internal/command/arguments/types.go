@@ -5,6 +5,12 @@
 
 package arguments
 
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
 // ViewType represents which view layer to use for a given command. Not all
 // commands will support all view types, and validation that the type is
 // supported should happen in the view constructor.
@@ -15,6 +21,9 @@ const (
 	ViewHuman ViewType = 'H'
 	ViewJSON  ViewType = 'J'
 	ViewRaw   ViewType = 'R'
+	ViewSARIF ViewType = 'S'
+	ViewEnv   ViewType = 'E'
+	ViewHTML  ViewType = 'W'
 )
 
 func (vt ViewType) String() string {
@@ -27,7 +36,50 @@ func (vt ViewType) String() string {
 		return "json"
 	case ViewRaw:
 		return "raw"
+	case ViewSARIF:
+		return "sarif"
+	case ViewEnv:
+		return "env"
+	case ViewHTML:
+		return "html"
 	default:
 		return "unknown"
 	}
 }
+
+// parseDiagnosticsFormat determines the view type for a command that
+// supports the -format option for choosing how to render diagnostics,
+// along with the older -json option.
+func parseDiagnosticsFormat(format string, jsonOutput bool) (ViewType, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var vt ViewType
+	switch format {
+	case "", "human":
+		vt = ViewHuman
+	case "json":
+		vt = ViewJSON
+	case "sarif":
+		vt = ViewSARIF
+	default:
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid output format",
+			fmt.Sprintf("The -format option must be \"human\", \"json\", or \"sarif\", not %q.", format),
+		))
+		return ViewHuman, diags
+	}
+
+	if jsonOutput {
+		if vt != ViewHuman && vt != ViewJSON {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Incompatible command-line options",
+				fmt.Sprintf("The -json option cannot be used with -format=%s.", format),
+			))
+		}
+		vt = ViewJSON
+	}
+
+	return vt, diags
+}
@@ -24,7 +24,15 @@ type Validate struct {
 	// included with the module.
 	NoTests bool
 
-	// ViewType specifies which output format to use: human, JSON, or "raw".
+	// DetectSecrets enables warnings about strings in the configuration that
+	// look like secrets.
+	DetectSecrets bool
+
+	// Strict selects the categories of warnings that should be treated as
+	// errors, in addition to any selected by the configuration.
+	Strict []tfdiags.WarningCategory
+
+	// ViewType specifies which output format to use: human, JSON, or SARIF.
 	ViewType ViewType
 }
 
@@ -38,10 +46,14 @@ func ParseValidate(args []string) (*Validate, tfdiags.Diagnostics) {
 	}
 
 	var jsonOutput bool
+	var format string
 	cmdFlags := defaultFlagSet("validate")
 	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.StringVar(&format, "format", "", "format")
 	cmdFlags.StringVar(&validate.TestDirectory, "test-directory", "tests", "test-directory")
 	cmdFlags.BoolVar(&validate.NoTests, "no-tests", false, "no-tests")
+	cmdFlags.BoolVar(&validate.DetectSecrets, "detect-secrets", false, "detect-secrets")
+	cmdFlags.Var((*flagWarningCategories)(&validate.Strict), "strict", "strict")
 
 	if err := cmdFlags.Parse(args); err != nil {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -64,12 +76,9 @@ func ParseValidate(args []string) (*Validate, tfdiags.Diagnostics) {
 		validate.Path = args[0]
 	}
 
-	switch {
-	case jsonOutput:
-		validate.ViewType = ViewJSON
-	default:
-		validate.ViewType = ViewHuman
-	}
+	viewType, formatDiags := parseDiagnosticsFormat(format, jsonOutput)
+	diags = diags.Append(formatDiags)
+	validate.ViewType = viewType
 
 	return validate, diags
 }
@@ -35,6 +35,22 @@ func TestParseValidate_valid(t *testing.T) {
 				ViewType:      ViewJSON,
 			},
 		},
+		"sarif": {
+			[]string{"-format=sarif"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewSARIF,
+			},
+		},
+		"json format": {
+			[]string{"-format=json"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewJSON,
+			},
+		},
 		"path": {
 			[]string{"-json", "foo"},
 			&Validate{
@@ -60,6 +76,40 @@ func TestParseValidate_valid(t *testing.T) {
 				NoTests:       true,
 			},
 		},
+		"detect-secrets": {
+			[]string{"-detect-secrets"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				DetectSecrets: true,
+			},
+		},
+		"strict": {
+			[]string{"-strict"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Strict: []tfdiags.WarningCategory{
+					tfdiags.WarningDeprecation,
+					tfdiags.WarningImplicitProviderInheritance,
+					tfdiags.WarningUndeclaredVarFile,
+				},
+			},
+		},
+		"strict categories": {
+			[]string{"-strict=deprecations,undeclared_var_files"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+				Strict: []tfdiags.WarningCategory{
+					tfdiags.WarningDeprecation,
+					tfdiags.WarningUndeclaredVarFile,
+				},
+			},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -68,7 +118,7 @@ func TestParseValidate_valid(t *testing.T) {
 			if len(diags) > 0 {
 				t.Fatalf("unexpected diags: %v", diags)
 			}
-			if *got != *tc.want {
+			if !reflect.DeepEqual(got, tc.want) {
 				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
 			}
 		})
@@ -96,6 +146,51 @@ func TestParseValidate_invalid(t *testing.T) {
 				),
 			},
 		},
+		"unknown warning category": {
+			[]string{"-strict=deprecation"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Failed to parse command-line flags",
+					`invalid boolean value "deprecation" for -strict: unsupported warning category "deprecation"; must be one of deprecations, implicit_provider_inheritance, undeclared_var_files`,
+				),
+			},
+		},
+		"unsupported format": {
+			[]string{"-format=xml"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewHuman,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid output format",
+					`The -format option must be "human", "json", or "sarif", not "xml".`,
+				),
+			},
+		},
+		"json and sarif": {
+			[]string{"-json", "-format=sarif"},
+			&Validate{
+				Path:          ".",
+				TestDirectory: "tests",
+				ViewType:      ViewJSON,
+			},
+			tfdiags.Diagnostics{
+				tfdiags.Sourceless(
+					tfdiags.Error,
+					"Incompatible command-line options",
+					"The -json option cannot be used with -format=sarif.",
+				),
+			},
+		},
 		"too many arguments": {
 			[]string{"-json", "bar", "baz"},
 			&Validate{
@@ -116,7 +211,7 @@ func TestParseValidate_invalid(t *testing.T) {
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			got, gotDiags := ParseValidate(tc.args)
-			if *got != *tc.want {
+			if !reflect.DeepEqual(got, tc.want) {
 				t.Fatalf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
 			}
 			if !reflect.DeepEqual(gotDiags, tc.wantDiags) {
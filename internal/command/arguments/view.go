@@ -5,6 +5,20 @@
 
 package arguments
 
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/jsonschema"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// SuppressAttributesEnvVar is the environment variable that gives a
+// comma-separated list of attribute patterns to suppress in rendered plans,
+// in addition to those given with the -suppress-attribute option.
+const SuppressAttributesEnvVar = "TF_SUPPRESS_ATTRIBUTES"
+
 // View represents the global command-line arguments which configure the view.
 type View struct {
 	// NoColor is used to disable the use of terminal color codes in all
@@ -19,6 +33,45 @@ type View struct {
 	// Concise is used to reduce the level of noise in the output and display
 	// only the important details.
 	Concise bool
+
+	// FullStringDiff is used to render changes to long strings by showing
+	// the full old and new values, instead of an inline diff.
+	FullStringDiff bool
+
+	// SuppressAttributes are patterns of resource attributes whose values
+	// are hidden when rendering changes, such as large encoded blobs. Each
+	// pattern is either an attribute name, or a resource type and an
+	// attribute name separated by a dot.
+	SuppressAttributes []string
+
+	// Summary is used to render planned changes grouped by module and
+	// resource type with counts, instead of rendering every change in full.
+	Summary bool
+
+	// SummaryExpand are patterns of the planned changes to render in full
+	// along with the summary. Each is a resource type, or the address of a
+	// module, resource or resource instance.
+	SummaryExpand []string
+
+	// JSONVersion is the major version of the machine-readable JSON formats
+	// selected with the -json-version option, as given. It's empty when the
+	// option isn't set, to select the latest version, and Parse checks that
+	// it's a version this OpenTofu can produce.
+	JSONVersion string
+}
+
+// Parse validates the arguments that ParseView can't check on its own,
+// returning an error diagnostic for each problem.
+func (v *View) Parse() tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if _, err := jsonschema.ParseVersion(v.JSONVersion); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid -json-version option",
+			fmt.Sprintf("The -json-version option must select a major version of the JSON output formats: %s.", err),
+		))
+	}
+	return diags
 }
 
 // ParseView processes CLI arguments, returning a View value and a
@@ -27,10 +80,29 @@ type View struct {
 func ParseView(args []string) (*View, []string) {
 	common := &View{}
 
+	for _, pattern := range strings.Split(os.Getenv(SuppressAttributesEnvVar), ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			common.SuppressAttributes = append(common.SuppressAttributes, pattern)
+		}
+	}
+
 	// Keep track of the length of the returned slice. When we find an
 	// argument we support, i will not be incremented.
 	i := 0
 	for _, v := range args {
+		if pattern, ok := strings.CutPrefix(v, "-suppress-attribute="); ok {
+			common.SuppressAttributes = append(common.SuppressAttributes, pattern)
+			continue
+		}
+		if pattern, ok := strings.CutPrefix(v, "-summary-expand="); ok {
+			common.SummaryExpand = append(common.SummaryExpand, pattern)
+			continue
+		}
+		if version, ok := strings.CutPrefix(v, "-json-version="); ok {
+			common.JSONVersion = version
+			continue
+		}
+
 		switch v {
 		case "-no-color":
 			common.NoColor = true
@@ -38,6 +110,10 @@ func ParseView(args []string) (*View, []string) {
 			common.CompactWarnings = true
 		case "-concise":
 			common.Concise = true
+		case "-full-string-diff":
+			common.FullStringDiff = true
+		case "-summary":
+			common.Summary = true
 		default:
 			// Unsupported argument: move left to the current position, and
 			// increment the index.
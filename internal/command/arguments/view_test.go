@@ -6,6 +6,7 @@
 package arguments
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -47,6 +48,26 @@ func TestParseView(t *testing.T) {
 			&View{NoColor: false, CompactWarnings: false, Concise: true},
 			[]string{"-foo", "-baz"},
 		},
+		"full-string-diff": {
+			[]string{"-foo", "-full-string-diff", "-baz"},
+			&View{FullStringDiff: true},
+			[]string{"-foo", "-baz"},
+		},
+		"suppress-attribute": {
+			[]string{"-foo", "-suppress-attribute=user_data", "-suppress-attribute=aws_s3_object.etag", "-baz"},
+			&View{SuppressAttributes: []string{"user_data", "aws_s3_object.etag"}},
+			[]string{"-foo", "-baz"},
+		},
+		"json-version": {
+			[]string{"-foo", "-json-version=1", "-baz"},
+			&View{JSONVersion: "1"},
+			[]string{"-foo", "-baz"},
+		},
+		"summary": {
+			[]string{"-foo", "-summary", "-summary-expand=module.network", "-summary-expand=aws_instance", "-baz"},
+			&View{Summary: true, SummaryExpand: []string{"module.network", "aws_instance"}},
+			[]string{"-foo", "-baz"},
+		},
 		"no-color and compact-warnings": {
 			[]string{"-foo", "-no-color", "-compact-warnings", "-baz"},
 			&View{NoColor: true, CompactWarnings: true, Concise: false},
@@ -76,8 +97,8 @@ func TestParseView(t *testing.T) {
 	for name, tc := range testCases {
 		t.Run(name, func(t *testing.T) {
 			got, gotArgs := ParseView(tc.args)
-			if *got != *tc.want {
-				t.Errorf("unexpected result\n got: %#v\nwant: %#v", got, tc.want)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("unexpected result\n%s", diff)
 			}
 			if !cmp.Equal(gotArgs, tc.wantArgs) {
 				t.Errorf("unexpected args\n got: %#v\nwant: %#v", gotArgs, tc.wantArgs)
@@ -85,3 +106,54 @@ func TestParseView(t *testing.T) {
 		})
 	}
 }
+
+func TestParseView_suppressAttributesEnv(t *testing.T) {
+	t.Setenv(SuppressAttributesEnvVar, "user_data, aws_s3_object.etag,")
+
+	got, _ := ParseView([]string{"-suppress-attribute=content"})
+	want := []string{"user_data", "aws_s3_object.etag", "content"}
+	if diff := cmp.Diff(want, got.SuppressAttributes); diff != "" {
+		t.Errorf("unexpected patterns\n%s", diff)
+	}
+}
+
+func TestView_Parse(t *testing.T) {
+	testCases := map[string]struct {
+		jsonVersion string
+		wantErr     string
+	}{
+		"default": {
+			"",
+			"",
+		},
+		"supported": {
+			"1",
+			"",
+		},
+		"unsupported": {
+			"2",
+			"version 2 is not supported",
+		},
+		"not a number": {
+			"latest",
+			`"latest" is not a whole number`,
+		},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			diags := (&View{JSONVersion: tc.jsonVersion}).Parse()
+			if tc.wantErr == "" {
+				if len(diags) > 0 {
+					t.Fatalf("unexpected diags: %v", diags)
+				}
+				return
+			}
+			if len(diags) != 1 {
+				t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+			}
+			if got := diags[0].Description().Detail; !strings.Contains(got, tc.wantErr) {
+				t.Errorf("wrong detail\ngot:  %s\nwant: message containing %q", got, tc.wantErr)
+			}
+		})
+	}
+}
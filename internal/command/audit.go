@@ -0,0 +1,306 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/user"
+	"sort"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/version"
+)
+
+// auditEntry is the JSON representation of a single entry in the audit log,
+// recorded for each command that may change the state.
+type auditEntry struct {
+	Timestamp    string       `json:"@timestamp"`
+	Command      string       `json:"command"`
+	Result       string       `json:"result"`
+	ExitStatus   int          `json:"exit_status"`
+	Version      string       `json:"tofu_version"`
+	User         auditUser    `json:"user"`
+	WorkingDir   string       `json:"working_dir"`
+	Workspace    string       `json:"workspace"`
+	Lineage      string       `json:"lineage,omitempty"`
+	SerialBefore *uint64      `json:"serial_before"`
+	SerialAfter  *uint64      `json:"serial_after"`
+	Changes      auditChanges `json:"changes"`
+}
+
+type auditUser struct {
+	Name     string `json:"name,omitempty"`
+	UID      string `json:"uid,omitempty"`
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// auditChanges summarizes the differences between the state before and after
+// a command by the addresses of the resource instances that were added,
+// changed, and removed.
+type auditChanges struct {
+	Added   []string `json:"added"`
+	Changed []string `json:"changed"`
+	Removed []string `json:"removed"`
+}
+
+// auditSession records the state before a command runs, so that the entry
+// for the audit log can be completed once it has finished. A nil session,
+// returned when there is no audit_log block in the CLI configuration, does
+// nothing.
+type auditSession struct {
+	meta      *Meta
+	command   string
+	workspace string
+	mgr       statemgr.Full
+
+	before       *states.State
+	serialBefore *uint64
+	lineage      string
+}
+
+// auditBegin starts recording an entry for the audit log for the given
+// command, which changes the state of the current workspace through the
+// given state manager.
+func (m *Meta) auditBegin(command string, mgr statemgr.Full) *auditSession {
+	if m.AuditLog == nil {
+		return nil
+	}
+
+	workspace, err := m.Workspace()
+	if err != nil {
+		log.Printf("[WARN] audit log: failed to select the workspace for %s: %s", command, err)
+	}
+	s := &auditSession{
+		meta:      m,
+		command:   command,
+		workspace: workspace,
+		mgr:       mgr,
+	}
+	if mgr != nil {
+		if err := mgr.RefreshState(); err != nil {
+			log.Printf("[WARN] audit log: failed to read the state before %s: %s", command, err)
+		}
+		s.before = mgr.State()
+		s.serialBefore, s.lineage = auditSnapshotMeta(mgr)
+	}
+	return s
+}
+
+// auditBeginBackend is like auditBegin, but uses the state manager for the
+// current workspace of the given backend.
+func (m *Meta) auditBeginBackend(command string, be backend.Backend) *auditSession {
+	if m.AuditLog == nil {
+		return nil
+	}
+
+	workspace, err := m.Workspace()
+	if err != nil {
+		log.Printf("[WARN] audit log: failed to select the workspace for %s: %s", command, err)
+	}
+	mgr, err := be.StateMgr(workspace)
+	if err != nil {
+		log.Printf("[WARN] audit log: failed to load the state for %s: %s", command, err)
+		mgr = nil
+	}
+	return m.auditBegin(command, mgr)
+}
+
+// finish completes the entry with the given exit status of the command and
+// the state after it ran, and writes it to the configured sinks. It is
+// intended to be deferred with a pointer to the command's named result.
+func (s *auditSession) finish(status *int) {
+	if s == nil {
+		return
+	}
+
+	entry := auditEntry{
+		Timestamp:    time.Now().UTC().Format(time.RFC3339Nano),
+		Command:      s.command,
+		Result:       "success",
+		ExitStatus:   *status,
+		Version:      version.String(),
+		User:         currentAuditUser(),
+		Workspace:    s.workspace,
+		Lineage:      s.lineage,
+		SerialBefore: s.serialBefore,
+	}
+	if *status != 0 {
+		entry.Result = "failure"
+	}
+	entry.WorkingDir, _ = os.Getwd()
+
+	after := s.before
+	if s.mgr != nil {
+		if err := s.mgr.RefreshState(); err != nil {
+			log.Printf("[WARN] audit log: failed to read the state after %s: %s", s.command, err)
+		}
+		after = s.mgr.State()
+		var lineage string
+		entry.SerialAfter, lineage = auditSnapshotMeta(s.mgr)
+		if lineage != "" {
+			entry.Lineage = lineage
+		}
+	}
+	entry.Changes = diffAuditStates(s.before, after)
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		// Should never happen, because the entry contains only simple types.
+		panic(fmt.Sprintf("failed to encode audit log entry: %s", err))
+	}
+
+	var diags tfdiags.Diagnostics
+	if path := s.meta.AuditLog.Path; path != "" {
+		diags = diags.Append(appendAuditLogFile(path, line))
+	}
+	if url := s.meta.AuditLog.WebhookURL; url != "" {
+		diags = diags.Append(postAuditLogWebhook(url, s.meta.AuditLog.WebhookHeaders, line))
+	}
+	s.meta.showDiagnostics(diags)
+}
+
+// auditSnapshotMeta returns the serial and lineage of the most recent state
+// snapshot read by the given state manager, if it tracks them.
+func auditSnapshotMeta(mgr statemgr.Full) (*uint64, string) {
+	pm, ok := mgr.(statemgr.PersistentMeta)
+	if !ok {
+		return nil, ""
+	}
+	meta := pm.StateSnapshotMeta()
+	if meta.Lineage == "" {
+		// There is no persisted snapshot yet.
+		return nil, ""
+	}
+	return &meta.Serial, meta.Lineage
+}
+
+func currentAuditUser() auditUser {
+	var ret auditUser
+	if u, err := user.Current(); err == nil {
+		ret.Name = u.Username
+		ret.UID = u.Uid
+	}
+	ret.Hostname, _ = os.Hostname()
+	return ret
+}
+
+// diffAuditStates returns the addresses of the resource instances that are
+// only in the after state, those that are in both with a different current
+// object or provider, and those that are only in the before state.
+func diffAuditStates(before, after *states.State) auditChanges {
+	old := auditInstances(before)
+	cur := auditInstances(after)
+
+	ret := auditChanges{
+		Added:   []string{},
+		Changed: []string{},
+		Removed: []string{},
+	}
+	for addr, obj := range cur {
+		prev, ok := old[addr]
+		switch {
+		case !ok:
+			ret.Added = append(ret.Added, addr)
+		case !bytes.Equal(prev, obj):
+			ret.Changed = append(ret.Changed, addr)
+		}
+	}
+	for addr := range old {
+		if _, ok := cur[addr]; !ok {
+			ret.Removed = append(ret.Removed, addr)
+		}
+	}
+	sort.Strings(ret.Added)
+	sort.Strings(ret.Changed)
+	sort.Strings(ret.Removed)
+	return ret
+}
+
+// auditInstances returns a map from the address of each resource instance in
+// the given state to a representation of its provider and current object
+// that can be compared to find changes.
+func auditInstances(state *states.State) map[string][]byte {
+	ret := make(map[string][]byte)
+	if state == nil {
+		return ret
+	}
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for key, is := range rs.Instances {
+				var buf bytes.Buffer
+				buf.WriteString(rs.ProviderConfig.String())
+				if obj := is.Current; obj != nil {
+					fmt.Fprintf(&buf, "\x00%s\x00", obj.Status)
+					buf.Write(obj.AttrsJSON)
+				}
+				ret[rs.Addr.Instance(key).String()] = buf.Bytes()
+			}
+		}
+	}
+	return ret
+}
+
+// appendAuditLogFile appends the given entry to the audit log file at the
+// given path as a single line, creating the file if necessary.
+func appendAuditLogFile(path string, line []byte) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err == nil {
+		_, err = f.Write(append(line, '\n'))
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to write audit log",
+			fmt.Sprintf("Could not append the entry for this command to the audit log %s: %s.", path, err),
+		))
+	}
+	return diags
+}
+
+// postAuditLogWebhook sends the given entry to the webhook at the given URL.
+func postAuditLogWebhook(url string, headers map[string]string, line []byte) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(line))
+	if err == nil {
+		req.Header.Set("Content-Type", "application/json")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+
+		var resp *http.Response
+		resp, err = httpclient.New().Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 200 || resp.StatusCode > 299 {
+				err = fmt.Errorf("the server responded with %s", resp.Status)
+			}
+		}
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to send audit log entry",
+			fmt.Sprintf("Could not send the entry for this command to the audit log webhook %s: %s.", url, err),
+		))
+	}
+	return diags
+}
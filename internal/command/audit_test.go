@@ -0,0 +1,162 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestAuditLog_taint(t *testing.T) {
+	state := states.BuildState(func(s *states.SyncState) {
+		for _, name := range []string{"foo", "bar"} {
+			s.SetResourceInstanceCurrent(
+				addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_instance",
+					Name: name,
+				}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"id":"bar"}`),
+					Status:    states.ObjectReady,
+				},
+				addrs.AbsProviderConfig{
+					Provider: addrs.NewDefaultProvider("test"),
+					Module:   addrs.RootModule,
+				},
+			)
+		}
+	})
+	statePath := testStateFile(t, state)
+	logPath := filepath.Join(t.TempDir(), "audit.log")
+
+	var posted [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			t.Errorf("wrong authorization header %q", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		posted = append(posted, body)
+	}))
+	defer server.Close()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &TaintCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+			AuditLog: &cliconfig.ConfigAuditLog{
+				Path:       logPath,
+				WebhookURL: server.URL,
+				WebhookHeaders: map[string]string{
+					"Authorization": "Bearer secret",
+				},
+			},
+		},
+	}
+
+	// Run the command twice, so that the file has more than one entry.
+	for i := 0; i < 2; i++ {
+		if code := c.Run([]string{"-state", statePath, "test_instance.foo"}); code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+		}
+	}
+
+	raw, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.Split(bytes.TrimSpace(raw), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("wrong number of entries %d\n%s", len(lines), raw)
+	}
+	if len(posted) != 2 || !bytes.Equal(posted[0], lines[0]) {
+		t.Fatalf("wrong webhook requests\n%s", posted)
+	}
+
+	var entry auditEntry
+	if err := json.Unmarshal(lines[0], &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Command != "taint" || entry.Result != "success" || entry.Workspace != "default" {
+		t.Errorf("wrong entry %s", lines[0])
+	}
+	if entry.SerialBefore == nil || entry.SerialAfter == nil || *entry.SerialAfter != *entry.SerialBefore+1 {
+		t.Errorf("wrong serials %s", lines[0])
+	}
+	want := auditChanges{
+		Added:   []string{},
+		Changed: []string{"test_instance.foo"},
+		Removed: []string{},
+	}
+	if diff := cmp.Diff(want, entry.Changes); diff != "" {
+		t.Errorf("wrong changes\n%s", diff)
+	}
+
+	// The instance is already tainted on the second run, so the entry records
+	// no changes.
+	if err := json.Unmarshal(lines[1], &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Result != "success" || len(entry.Changes.Changed) != 0 {
+		t.Errorf("wrong second entry %s", lines[1])
+	}
+}
+
+func TestDiffAuditStates(t *testing.T) {
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	instance := func(name string) addrs.AbsResourceInstance {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_instance",
+			Name: name,
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	}
+	object := func(id string) *states.ResourceInstanceObjectSrc {
+		return &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(`{"id":"` + id + `"}`),
+			Status:    states.ObjectReady,
+		}
+	}
+
+	before := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(instance("kept"), object("a"), provider)
+		s.SetResourceInstanceCurrent(instance("updated"), object("a"), provider)
+		s.SetResourceInstanceCurrent(instance("removed"), object("a"), provider)
+	})
+	after := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(instance("kept"), object("a"), provider)
+		s.SetResourceInstanceCurrent(instance("updated"), object("b"), provider)
+		s.SetResourceInstanceCurrent(instance("added"), object("a"), provider)
+	})
+
+	got := diffAuditStates(before, after)
+	want := auditChanges{
+		Added:   []string{"test_instance.added"},
+		Changed: []string{"test_instance.updated"},
+		Removed: []string{"test_instance.removed"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong changes\n%s", diff)
+	}
+}
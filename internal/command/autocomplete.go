@@ -6,7 +6,13 @@
 package command
 
 import (
+	"sort"
+
 	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/configs"
 )
 
 // This file contains some re-usable predictors for auto-complete. The
@@ -21,6 +27,12 @@ var completePredictBoolean = complete.PredictSet("true", "false")
 // we'll probably add one later.
 var completePredictModuleSource = complete.PredictAnything
 
+// For completing the value of -var-file
+var completePredictVarFile = complete.PredictOr(
+	complete.PredictFiles("*.tfvars"),
+	complete.PredictFiles("*.tfvars.json"),
+)
+
 type completePredictSequence []complete.Predictor
 
 func (s completePredictSequence) Predict(a complete.Args) []string {
@@ -35,41 +47,172 @@ func (s completePredictSequence) Predict(a complete.Args) []string {
 
 func (m *Meta) completePredictWorkspaceName() complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) []string {
-		// There are lot of things that can fail in here, so if we encounter
-		// any error then we'll just return nothing and not support autocomplete
-		// until whatever error is fixed. (The user can't actually see the error
-		// here, but other commands should produce a user-visible error before
-		// too long.)
-
-		// We assume here that we want to autocomplete for the current working
-		// directory, since we don't have enough context to know where to
-		// find any config path argument, and it might be _after_ the argument
-		// we're trying to complete here anyway.
-		configPath, err := modulePath(nil)
-		if err != nil {
+		b := m.completeBackend()
+		if b == nil {
 			return nil
 		}
 
-		backendConfig, diags := m.loadBackendConfig(configPath)
-		if diags.HasErrors() {
-			return nil
+		names, _ := b.Workspaces()
+		return names
+	})
+}
+
+// completePredictStateAddress predicts the addresses of the resources and
+// resource instances in the state of the current workspace.
+func (m *Meta) completePredictStateAddress() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		return m.completeStateAddrs()
+	})
+}
+
+// completePredictResourceAddress predicts the addresses of the resources and
+// resource instances in the state of the current workspace, along with those
+// of the resources declared in the configuration that have no instances yet,
+// as used by options such as -target and -replace.
+func (m *Meta) completePredictResourceAddress() complete.Predictor {
+	return complete.PredictFunc(func(a complete.Args) []string {
+		seen := make(map[string]bool)
+		var ret []string
+		for _, addr := range m.completeStateAddrs() {
+			seen[addr] = true
+			ret = append(ret, addr)
 		}
 
-		// Load the encryption configuration
-		enc, encDiags := m.Encryption()
-		if encDiags.HasErrors() {
-			m.showDiagnostics(encDiags)
-			return nil
+		// As for the backend, we only support the configuration in the
+		// current working directory.
+		config, diags := m.loadConfig(".")
+		if diags.HasErrors() || config == nil {
+			return ret
 		}
+		config.DeepEach(func(c *configs.Config) {
+			resources := make([]*configs.Resource, 0, len(c.Module.ManagedResources)+len(c.Module.DataResources))
+			for _, r := range c.Module.ManagedResources {
+				resources = append(resources, r)
+			}
+			for _, r := range c.Module.DataResources {
+				resources = append(resources, r)
+			}
+			for _, r := range resources {
+				addr := r.Addr().String()
+				if !c.Path.IsRoot() {
+					addr = c.Path.String() + "." + addr
+				}
+				if !seen[addr] {
+					seen[addr] = true
+					ret = append(ret, addr)
+				}
+			}
+		})
+		sort.Strings(ret)
+		return ret
+	})
+}
 
-		b, diags := m.Backend(&BackendOpts{
-			Config: backendConfig,
-		}, enc.State())
-		if diags.HasErrors() {
-			return nil
+// completeStateAddrs returns the addresses of the resources and resource
+// instances in the state of the current workspace, sorted.
+func (m *Meta) completeStateAddrs() []string {
+	b := m.completeBackend()
+	if b == nil {
+		return nil
+	}
+	workspace, err := m.Workspace()
+	if err != nil {
+		return nil
+	}
+	stateMgr, err := b.StateMgr(workspace)
+	if err != nil {
+		return nil
+	}
+	if err := stateMgr.RefreshState(); err != nil {
+		return nil
+	}
+	state := stateMgr.State()
+	if state == nil {
+		return nil
+	}
+
+	var ret []string
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for key := range rs.Instances {
+				if key != addrs.NoKey {
+					// The whole resource is a valid target too.
+					ret = append(ret, rs.Addr.String())
+				}
+				ret = append(ret, rs.Addr.Instance(key).String())
+			}
 		}
+	}
+	sort.Strings(ret)
 
-		names, _ := b.Workspaces()
-		return names
-	})
+	// Resources with several instances were added once for each of them.
+	unique := ret[:0]
+	for i, addr := range ret {
+		if i == 0 || addr != ret[i-1] {
+			unique = append(unique, addr)
+		}
+	}
+	return unique
+}
+
+// completeBackend returns the backend for the configuration in the current
+// working directory, or nil if it can't be loaded.
+func (m *Meta) completeBackend() backend.Backend {
+	// There are lot of things that can fail in here, so if we encounter
+	// any error then we'll just return nothing and not support autocomplete
+	// until whatever error is fixed. (The user can't actually see the error
+	// here, but other commands should produce a user-visible error before
+	// too long.)
+
+	// We assume here that we want to autocomplete for the current working
+	// directory, since we don't have enough context to know where to
+	// find any config path argument, and it might be _after_ the argument
+	// we're trying to complete here anyway.
+	configPath, err := modulePath(nil)
+	if err != nil {
+		return nil
+	}
+
+	backendConfig, diags := m.loadBackendConfig(configPath)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := m.Encryption()
+	if encDiags.HasErrors() {
+		m.showDiagnostics(encDiags)
+		return nil
+	}
+
+	b, diags := m.Backend(&BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	if diags.HasErrors() {
+		return nil
+	}
+	return b
+}
+
+// completeOperationFlags returns the predictors for the flags shared by the
+// commands that plan changes.
+func (m *Meta) completeOperationFlags() complete.Flags {
+	address := m.completePredictResourceAddress()
+	return complete.Flags{
+		"-compact-warnings": complete.PredictNothing,
+		"-destroy":          complete.PredictNothing,
+		"-input":            completePredictBoolean,
+		"-json":             complete.PredictNothing,
+		"-lock":             completePredictBoolean,
+		"-lock-timeout":     complete.PredictAnything,
+		"-no-color":         complete.PredictNothing,
+		"-parallelism":      complete.PredictAnything,
+		"-refresh":          completePredictBoolean,
+		"-refresh-filter":   address,
+		"-refresh-only":     complete.PredictNothing,
+		"-replace":          address,
+		"-target":           address,
+		"-var":              complete.PredictAnything,
+		"-var-file":         completePredictVarFile,
+	}
 }
@@ -12,6 +12,9 @@ import (
 
 	"github.com/mitchellh/cli"
 	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
 )
 
 func TestMetaCompletePredictWorkspaceName(t *testing.T) {
@@ -39,3 +42,65 @@ func TestMetaCompletePredictWorkspaceName(t *testing.T) {
 		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
 	}
 }
+
+func TestMetaCompletePredictResourceAddress(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	state := states.BuildState(func(s *states.SyncState) {
+		for _, key := range []addrs.InstanceKey{addrs.IntKey(0), addrs.IntKey(1)} {
+			s.SetResourceInstanceCurrent(
+				addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_instance",
+					Name: "counted",
+				}.Instance(key).Absolute(addrs.RootModuleInstance),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(`{"id":"bar"}`),
+					Status:    states.ObjectReady,
+				},
+				addrs.AbsProviderConfig{
+					Provider: addrs.NewDefaultProvider("test"),
+					Module:   addrs.RootModule,
+				},
+			)
+		}
+	})
+	testStateFileDefault(t, state)
+
+	config := `
+resource "test_instance" "counted" {
+  count = 2
+}
+
+resource "test_instance" "new" {
+}
+`
+	if err := os.WriteFile("main.tf", []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := new(cli.MockUi)
+	meta := &Meta{Ui: ui}
+
+	got := meta.completePredictStateAddress().Predict(complete.Args{})
+	want := []string{
+		"test_instance.counted",
+		"test_instance.counted[0]",
+		"test_instance.counted[1]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong state addresses\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	got = meta.completePredictResourceAddress().Predict(complete.Args{})
+	want = []string{
+		"test_instance.counted",
+		"test_instance.counted[0]",
+		"test_instance.counted[1]",
+		"test_instance.new",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("wrong resource addresses\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
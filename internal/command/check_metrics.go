@@ -0,0 +1,113 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/checkmetrics"
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// exportCheckMetrics writes the results of the checks from the given
+// operation as metrics to the file, and pushes them to the Pushgateway, given
+// in the arguments.
+func exportCheckMetrics(args *arguments.CheckMetrics, op *backend.RunningOperation) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if !args.Enabled() || op == nil {
+		return diags
+	}
+
+	if op.CheckResults == nil {
+		if op.Result == backend.OperationSuccess {
+			// Backends that run operations remotely don't return the
+			// results of the checks.
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Check results not available",
+				"The metrics for the checks were not exported, because the backend did not return the results of the checks.",
+			))
+		}
+		return diags
+	}
+
+	metrics := checkmetrics.Marshal(op.CheckResults, time.Now())
+	if args.OutPath != "" {
+		diags = diags.Append(writeCheckMetricsFile(args.OutPath, metrics))
+	}
+	if args.PushURL != "" {
+		diags = diags.Append(pushCheckMetrics(args.PushURL, metrics))
+	}
+	return diags
+}
+
+// writeCheckMetricsFile writes the given metrics to the given path. The file
+// is replaced in one step, so that collectors that read it at any time, such
+// as the Prometheus node exporter, never see a partial file.
+func writeCheckMetricsFile(path string, metrics []byte) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err == nil {
+		_, err = f.Write(metrics)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err == nil {
+			err = os.Chmod(f.Name(), 0644)
+		}
+		if err == nil {
+			err = os.Rename(f.Name(), path)
+		}
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write check metrics",
+			fmt.Sprintf("Could not write the metrics for the checks to %s: %s.", path, err),
+		))
+	}
+	return diags
+}
+
+// pushCheckMetrics pushes the given metrics to the Prometheus Pushgateway
+// group at the given URL, replacing any metrics previously pushed to it.
+func pushCheckMetrics(url string, metrics []byte) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(metrics))
+	if err == nil {
+		req.Header.Set("Content-Type", checkmetrics.ContentType)
+
+		var resp *http.Response
+		resp, err = httpclient.New().Do(req)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode < 200 || resp.StatusCode > 299 {
+				err = fmt.Errorf("the server responded with %s", resp.Status)
+			}
+		}
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to push check metrics",
+			fmt.Sprintf("Could not push the metrics for the checks to %s: %s.", url, err),
+		))
+	}
+	return diags
+}
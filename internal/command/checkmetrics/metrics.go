@@ -0,0 +1,144 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package checkmetrics implements the representation of check results as
+// metrics in the Prometheus text exposition format, so that monitoring systems
+// can alert on the checks in the configuration.
+package checkmetrics
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// ContentType is the media type of the metrics returned by Marshal.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// statuses are the check statuses in the order they are reported, each of
+// which is the value of the "status" label of a metric.
+var statuses = []struct {
+	status checks.Status
+	name   string
+}{
+	{checks.StatusPass, "pass"},
+	{checks.StatusFail, "fail"},
+	{checks.StatusError, "error"},
+	{checks.StatusUnknown, "unknown"},
+}
+
+// Marshal returns the given check results as metrics in the Prometheus text
+// exposition format, which is also understood by OpenMetrics parsers.
+//
+// The metrics describe the aggregate status of each checkable object in the
+// configuration, the number of its conditions that failed, the number of
+// checkable object instances with each status, and the given time at which
+// the checks were evaluated.
+func Marshal(results *states.CheckResults, evaluated time.Time) []byte {
+	type object struct {
+		labels   string
+		status   checks.Status
+		failures int
+	}
+
+	var objects []object
+	counts := make(map[checks.Status]int)
+	if results != nil {
+		for _, elem := range results.ConfigResults.Elems {
+			aggr := elem.Value
+			obj := object{
+				labels: fmt.Sprintf("address=%s,kind=%s", labelValue(elem.Key.String()), labelValue(checkableKind(elem.Key))),
+				status: aggr.Status,
+			}
+			for _, instance := range aggr.ObjectResults.Elems {
+				obj.failures += failureCount(instance.Value)
+				counts[instance.Value.Status]++
+			}
+			objects = append(objects, obj)
+		}
+	}
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].labels < objects[j].labels
+	})
+
+	var buf bytes.Buffer
+
+	writeHeader(&buf, "tofu_check_status", "Whether each checkable object in the configuration has the given status, across all of its instances.")
+	for _, obj := range objects {
+		for _, s := range statuses {
+			value := 0
+			if obj.status == s.status {
+				value = 1
+			}
+			fmt.Fprintf(&buf, "tofu_check_status{%s,status=%s} %d\n", obj.labels, labelValue(s.name), value)
+		}
+	}
+
+	writeHeader(&buf, "tofu_check_failures", "The number of conditions of each checkable object that failed, across all of its instances.")
+	for _, obj := range objects {
+		fmt.Fprintf(&buf, "tofu_check_failures{%s} %d\n", obj.labels, obj.failures)
+	}
+
+	writeHeader(&buf, "tofu_check_instances", "The number of instances of checkable objects with the given status.")
+	for _, s := range statuses {
+		fmt.Fprintf(&buf, "tofu_check_instances{status=%s} %d\n", labelValue(s.name), counts[s.status])
+	}
+
+	writeHeader(&buf, "tofu_check_evaluation_timestamp_seconds", "The time at which the checks were evaluated, in seconds since the Unix epoch.")
+	fmt.Fprintf(&buf, "tofu_check_evaluation_timestamp_seconds %s\n", strconv.FormatFloat(float64(evaluated.UnixMilli())/1000, 'f', -1, 64))
+
+	return buf.Bytes()
+}
+
+func writeHeader(buf *bytes.Buffer, name, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(buf, "# TYPE %s gauge\n", name)
+}
+
+// failureCount returns the number of conditions of the given checkable object
+// instance that failed.
+func failureCount(obj *states.CheckResultObject) int {
+	var count int
+	for _, rule := range obj.Rules {
+		if rule.Status == checks.StatusFail {
+			count++
+		}
+	}
+	if count == 0 && obj.Status == checks.StatusFail {
+		// The individual rules aren't preserved in state snapshots, so we
+		// may only have the failure messages.
+		count = max(len(obj.FailureMessages), 1)
+	}
+	return count
+}
+
+func checkableKind(addr addrs.ConfigCheckable) string {
+	switch addr.CheckableKind() {
+	case addrs.CheckableResource:
+		return "resource"
+	case addrs.CheckableOutputValue:
+		return "output_value"
+	case addrs.CheckableCheck:
+		return "check"
+	case addrs.CheckableInputVariable:
+		return "var"
+	default:
+		panic(fmt.Sprintf("unsupported CheckableKind %s", addr.CheckableKind()))
+	}
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// labelValue returns the given string as a quoted label value.
+func labelValue(s string) string {
+	return `"` + labelValueReplacer.Replace(s) + `"`
+}
@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package checkmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestMarshal(t *testing.T) {
+	resourceAddr := addrs.ConfigCheckable(addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test",
+		Name: "a",
+	}.InModule(addrs.RootModule))
+	resourceInstAddr := func(key string) addrs.Checkable {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test",
+			Name: "a",
+		}.Instance(addrs.StringKey(key)).Absolute(addrs.RootModuleInstance)
+	}
+	checkAddr := addrs.ConfigCheckable(addrs.Check{Name: "health"}.InModule(addrs.RootModule))
+	checkInstAddr := addrs.Checkable(addrs.Check{Name: "health"}.Absolute(addrs.RootModuleInstance))
+	outputAddr := addrs.ConfigCheckable(addrs.OutputValue{Name: "b"}.InModule(addrs.RootModule))
+
+	results := &states.CheckResults{
+		ConfigResults: addrs.MakeMap(
+			addrs.MakeMapElem(resourceAddr, &states.CheckResultAggregate{
+				Status: checks.StatusFail,
+				ObjectResults: addrs.MakeMap(
+					addrs.MakeMapElem(resourceInstAddr("foo"), &states.CheckResultObject{
+						Status: checks.StatusFail,
+						Rules: []checks.RuleResult{
+							{Status: checks.StatusFail, FailureMessage: "Not enough boops."},
+							{Status: checks.StatusPass},
+							{Status: checks.StatusFail, FailureMessage: "Too many beeps."},
+						},
+					}),
+					addrs.MakeMapElem(resourceInstAddr("bar"), &states.CheckResultObject{
+						Status: checks.StatusPass,
+					}),
+				),
+			}),
+			addrs.MakeMapElem(checkAddr, &states.CheckResultAggregate{
+				Status: checks.StatusFail,
+				ObjectResults: addrs.MakeMap(
+					// Results from a state snapshot only have the failure
+					// messages.
+					addrs.MakeMapElem(checkInstAddr, &states.CheckResultObject{
+						Status:          checks.StatusFail,
+						FailureMessages: []string{"Unhealthy."},
+					}),
+				),
+			}),
+			addrs.MakeMapElem(outputAddr, &states.CheckResultAggregate{
+				Status: checks.StatusUnknown,
+			}),
+		),
+	}
+
+	got := string(Marshal(results, time.UnixMilli(1700000000500)))
+	want := `# HELP tofu_check_status Whether each checkable object in the configuration has the given status, across all of its instances.
+# TYPE tofu_check_status gauge
+tofu_check_status{address="check.health",kind="check",status="pass"} 0
+tofu_check_status{address="check.health",kind="check",status="fail"} 1
+tofu_check_status{address="check.health",kind="check",status="error"} 0
+tofu_check_status{address="check.health",kind="check",status="unknown"} 0
+tofu_check_status{address="output.b",kind="output_value",status="pass"} 0
+tofu_check_status{address="output.b",kind="output_value",status="fail"} 0
+tofu_check_status{address="output.b",kind="output_value",status="error"} 0
+tofu_check_status{address="output.b",kind="output_value",status="unknown"} 1
+tofu_check_status{address="test.a",kind="resource",status="pass"} 0
+tofu_check_status{address="test.a",kind="resource",status="fail"} 1
+tofu_check_status{address="test.a",kind="resource",status="error"} 0
+tofu_check_status{address="test.a",kind="resource",status="unknown"} 0
+# HELP tofu_check_failures The number of conditions of each checkable object that failed, across all of its instances.
+# TYPE tofu_check_failures gauge
+tofu_check_failures{address="check.health",kind="check"} 1
+tofu_check_failures{address="output.b",kind="output_value"} 0
+tofu_check_failures{address="test.a",kind="resource"} 2
+# HELP tofu_check_instances The number of instances of checkable objects with the given status.
+# TYPE tofu_check_instances gauge
+tofu_check_instances{status="pass"} 1
+tofu_check_instances{status="fail"} 2
+tofu_check_instances{status="error"} 0
+tofu_check_instances{status="unknown"} 0
+# HELP tofu_check_evaluation_timestamp_seconds The time at which the checks were evaluated, in seconds since the Unix epoch.
+# TYPE tofu_check_evaluation_timestamp_seconds gauge
+tofu_check_evaluation_timestamp_seconds 1700000000.5
+`
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong metrics\n%s", diff)
+	}
+}
+
+func TestLabelValue(t *testing.T) {
+	got := labelValue("test.a[\"foo\\bar\"]\n")
+	want := `"test.a[\"foo\\bar\"]\n"`
+	if got != want {
+		t.Errorf("wrong label value %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ConfigAuditLog is the structure of the "audit_log" nested block within the
+// CLI configuration, which enables recording each command that changes
+// state to a local file, a webhook, or both.
+type ConfigAuditLog struct {
+	// Path is a file that an entry is appended to for each command, as a
+	// single line of JSON.
+	Path string `hcl:"path"`
+
+	// WebhookURL is an HTTP endpoint that each entry is posted to as JSON,
+	// along with the WebhookHeaders.
+	WebhookURL     string            `hcl:"webhook_url"`
+	WebhookHeaders map[string]string `hcl:"webhook_headers"`
+}
+
+// decodeAuditLogFromConfig decodes the audit_log blocks in the given file.
+func decodeAuditLogFromConfig(hclFile *hclast.File) ([]*ConfigAuditLog, tfdiags.Diagnostics) {
+	var ret []*ConfigAuditLog
+	var diags tfdiags.Diagnostics
+
+	root := hclFile.Node.(*hclast.ObjectList)
+	for _, block := range root.Filter("audit_log").Items {
+		if len(block.Keys) > 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid audit_log block",
+				fmt.Sprintf("The audit_log block at %s must not have any labels.", block.Pos()),
+			))
+			continue
+		}
+
+		auditLog := &ConfigAuditLog{}
+		if err := hcl.DecodeObject(auditLog, block.Val); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid audit_log block",
+				fmt.Sprintf("The audit_log block at %s is invalid: %s.", block.Pos(), err),
+			))
+			continue
+		}
+		auditLog.Path = os.ExpandEnv(auditLog.Path)
+		ret = append(ret, auditLog)
+	}
+
+	return ret, diags
+}
+
+// validate checks the settings in the audit_log block.
+func (c *ConfigAuditLog) validate() []error {
+	var errs []error
+	if c.Path == "" && c.WebhookURL == "" {
+		errs = append(errs, fmt.Errorf("The audit_log block must set at least one of path and webhook_url"))
+	}
+	if c.WebhookURL != "" {
+		if u, err := url.Parse(c.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("The audit_log block has an invalid webhook_url %q: must be an http or https URL", c.WebhookURL))
+		}
+	}
+	if len(c.WebhookHeaders) > 0 && c.WebhookURL == "" {
+		errs = append(errs, fmt.Errorf("The audit_log block sets webhook_headers without webhook_url"))
+	}
+	return errs
+}
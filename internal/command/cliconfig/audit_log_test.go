@@ -0,0 +1,65 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig_auditLog(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "audit-log"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if diags := got.Validate(); diags.HasErrors() {
+		t.Fatalf("unexpected validation errors: %s", diags.Err())
+	}
+
+	want := []*ConfigAuditLog{
+		{
+			Path:       "/var/log/tofu/audit.log",
+			WebhookURL: "https://audit.example.com/tofu",
+			WebhookHeaders: map[string]string{
+				"Authorization": "Bearer secret",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got.AuditLog); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestConfigAuditLog_validate(t *testing.T) {
+	config := &Config{
+		AuditLog: []*ConfigAuditLog{
+			{
+				WebhookURL: "audit.example.com",
+			},
+			{
+				WebhookHeaders: map[string]string{
+					"Authorization": "Bearer secret",
+				},
+			},
+		},
+	}
+
+	var got []string
+	for _, diag := range config.Validate() {
+		got = append(got, diag.Description().Summary)
+	}
+	want := []string{
+		"No more than one audit_log block may be specified",
+		`The audit_log block has an invalid webhook_url "audit.example.com": must be an http or https URL`,
+		"The audit_log block must set at least one of path and webhook_url",
+		"The audit_log block sets webhook_headers without webhook_url",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong errors\n%s", diff)
+	}
+}
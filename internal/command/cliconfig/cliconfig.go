@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl"
 
@@ -31,6 +32,8 @@ import (
 
 const pluginCacheDirEnvVar = "TF_PLUGIN_CACHE_DIR"
 const pluginCacheMayBreakLockFileEnvVar = "TF_PLUGIN_CACHE_MAY_BREAK_DEPENDENCY_LOCK_FILE"
+const providerProcessReuseEnvVar = "TF_PROVIDER_PROCESS_REUSE"
+const operationTimeoutEnvVar = "TF_OPERATION_TIMEOUT"
 
 // Config is the structure of the configuration for the OpenTofu CLI.
 //
@@ -55,6 +58,19 @@ type Config struct {
 	// over the requirements of the dependency lock file.
 	PluginCacheMayBreakDependencyLockFile bool `hcl:"plugin_cache_may_break_dependency_lock_file"`
 
+	// ProviderProcessReuse allows a provider plugin process that is already
+	// running to be reused by another instance of the same provider while it
+	// is still in use, if neither has been configured yet or both have
+	// identical configurations. Configurations that differ never share a
+	// process, because a plugin process can only be configured once.
+	ProviderProcessReuse bool `hcl:"provider_process_reuse"`
+
+	// OperationTimeout is the default limit on how long each call to a
+	// provider to read, create, update or delete a resource instance may
+	// take, as a duration string such as "30m". Resources can override it
+	// with the operation_timeout lifecycle argument.
+	OperationTimeout string `hcl:"operation_timeout"`
+
 	Hosts map[string]*ConfigHost `hcl:"host"`
 
 	Credentials        map[string]map[string]interface{}   `hcl:"credentials"`
@@ -65,6 +81,27 @@ type Config struct {
 	// configuration, but we decode into a slice here so that we can handle
 	// that validation at validation time rather than initial decode time.
 	ProviderInstallation []*ProviderInstallation
+
+	// Telemetry represents any telemetry blocks in the configuration. As
+	// with ProviderInstallation, only one of these is allowed across the
+	// whole configuration.
+	Telemetry []*ConfigTelemetry `hcl:"-"`
+
+	// AuditLog represents any audit_log blocks in the configuration, of
+	// which only one is allowed across the whole configuration.
+	AuditLog []*ConfigAuditLog `hcl:"-"`
+
+	// Policy represents any policy blocks in the configuration, of which
+	// only one is allowed across the whole configuration.
+	Policy []*ConfigPolicy `hcl:"-"`
+
+	// PolicyChecks represents any policy_check blocks in the configuration,
+	// each of which must have a different name.
+	PolicyChecks []*ConfigPolicyCheck `hcl:"-"`
+
+	// Notifications represents any notifications blocks in the
+	// configuration, each of which must have a different name.
+	Notifications []*ConfigNotifications `hcl:"-"`
 }
 
 // ConfigHost is the structure of the "host" nested block within the CLI
@@ -184,6 +221,28 @@ func loadConfigFile(path string) (*Config, tfdiags.Diagnostics) {
 	diags = diags.Append(moreDiags)
 	result.ProviderInstallation = providerInstBlocks
 
+	// The telemetry block is decoded separately too, because the HCL decoder
+	// would otherwise split its attributes across several blocks.
+	telemetryBlocks, moreDiags := decodeTelemetryFromConfig(obj)
+	diags = diags.Append(moreDiags)
+	result.Telemetry = telemetryBlocks
+
+	auditLogBlocks, moreDiags := decodeAuditLogFromConfig(obj)
+	diags = diags.Append(moreDiags)
+	result.AuditLog = auditLogBlocks
+
+	policyBlocks, moreDiags := decodePolicyFromConfig(obj)
+	diags = diags.Append(moreDiags)
+	result.Policy = policyBlocks
+
+	policyCheckBlocks, moreDiags := decodePolicyChecksFromConfig(obj)
+	diags = diags.Append(moreDiags)
+	result.PolicyChecks = policyCheckBlocks
+
+	notificationsBlocks, moreDiags := decodeNotificationsFromConfig(obj)
+	diags = diags.Append(moreDiags)
+	result.Notifications = notificationsBlocks
+
 	// Replace all env vars
 	for k, v := range result.Providers {
 		result.Providers[k] = os.ExpandEnv(v)
@@ -257,6 +316,17 @@ func envConfig(env map[string]string) *Config {
 		config.PluginCacheMayBreakDependencyLockFile = true
 	}
 
+	if envReuse := env[providerProcessReuseEnvVar]; envReuse != "" && envReuse != "0" {
+		// This is an environment variable analog to the
+		// provider_process_reuse setting, which saturates to "on" in
+		// the same way.
+		config.ProviderProcessReuse = true
+	}
+
+	if envTimeout := env[operationTimeoutEnvVar]; envTimeout != "" {
+		config.OperationTimeout = envTimeout
+	}
+
 	return config
 }
 
@@ -327,6 +397,74 @@ func (c *Config) Validate() tfdiags.Diagnostics {
 		)
 	}
 
+	// Should have zero or one "telemetry" blocks
+	if len(c.Telemetry) > 1 {
+		diags = diags.Append(
+			fmt.Errorf("No more than one telemetry block may be specified"),
+		)
+	}
+	for _, telemetry := range c.Telemetry {
+		for _, err := range telemetry.validate("telemetry block") {
+			diags = diags.Append(err)
+		}
+	}
+
+	// Should have zero or one "audit_log" blocks
+	if len(c.AuditLog) > 1 {
+		diags = diags.Append(
+			fmt.Errorf("No more than one audit_log block may be specified"),
+		)
+	}
+	for _, auditLog := range c.AuditLog {
+		for _, err := range auditLog.validate() {
+			diags = diags.Append(err)
+		}
+	}
+
+	// Should have zero or one "policy" blocks
+	if len(c.Policy) > 1 {
+		diags = diags.Append(
+			fmt.Errorf("No more than one policy block may be specified"),
+		)
+	}
+	for _, policy := range c.Policy {
+		for _, err := range policy.validate() {
+			diags = diags.Append(err)
+		}
+	}
+
+	policyChecks := make(map[string]bool, len(c.PolicyChecks))
+	for _, check := range c.PolicyChecks {
+		if policyChecks[check.Name] {
+			diags = diags.Append(
+				fmt.Errorf("Duplicate policy_check block %q", check.Name),
+			)
+		}
+		policyChecks[check.Name] = true
+		for _, err := range check.validate() {
+			diags = diags.Append(err)
+		}
+	}
+
+	notifications := make(map[string]bool, len(c.Notifications))
+	for _, n := range c.Notifications {
+		if notifications[n.Name] {
+			diags = diags.Append(
+				fmt.Errorf("Duplicate notifications block %q", n.Name),
+			)
+		}
+		notifications[n.Name] = true
+		for _, err := range n.validate() {
+			diags = diags.Append(err)
+		}
+	}
+
+	if c.OperationTimeout != "" {
+		if _, err := c.OperationTimeoutDuration(); err != nil {
+			diags = diags.Append(err)
+		}
+	}
+
 	if c.PluginCacheDir != "" {
 		_, err := os.Stat(c.PluginCacheDir)
 		if err != nil {
@@ -339,6 +477,19 @@ func (c *Config) Validate() tfdiags.Diagnostics {
 	return diags
 }
 
+// OperationTimeoutDuration returns the parsed value of the operation_timeout
+// setting, which is zero if it isn't set.
+func (c *Config) OperationTimeoutDuration() (time.Duration, error) {
+	if c.OperationTimeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(c.OperationTimeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("The operation_timeout setting must be a positive duration, such as \"30m\", not %q", c.OperationTimeout)
+	}
+	return timeout, nil
+}
+
 // Merge merges two configurations and returns a third entirely
 // new configuration with the two merged.
 func (c *Config) Merge(c2 *Config) *Config {
@@ -375,6 +526,15 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.PluginCacheMayBreakDependencyLockFile = true
 	}
 
+	if c.ProviderProcessReuse || c2.ProviderProcessReuse {
+		result.ProviderProcessReuse = true
+	}
+
+	result.OperationTimeout = c.OperationTimeout
+	if result.OperationTimeout == "" {
+		result.OperationTimeout = c2.OperationTimeout
+	}
+
 	if (len(c.Hosts) + len(c2.Hosts)) > 0 {
 		result.Hosts = make(map[string]*ConfigHost)
 		for name, host := range c.Hosts {
@@ -413,6 +573,31 @@ func (c *Config) Merge(c2 *Config) *Config {
 		result.ProviderInstallation = append(result.ProviderInstallation, c2.ProviderInstallation...)
 	}
 
+	if (len(c.Telemetry) + len(c2.Telemetry)) > 0 {
+		result.Telemetry = append(result.Telemetry, c.Telemetry...)
+		result.Telemetry = append(result.Telemetry, c2.Telemetry...)
+	}
+
+	if (len(c.AuditLog) + len(c2.AuditLog)) > 0 {
+		result.AuditLog = append(result.AuditLog, c.AuditLog...)
+		result.AuditLog = append(result.AuditLog, c2.AuditLog...)
+	}
+
+	if (len(c.Policy) + len(c2.Policy)) > 0 {
+		result.Policy = append(result.Policy, c.Policy...)
+		result.Policy = append(result.Policy, c2.Policy...)
+	}
+
+	if (len(c.PolicyChecks) + len(c2.PolicyChecks)) > 0 {
+		result.PolicyChecks = append(result.PolicyChecks, c.PolicyChecks...)
+		result.PolicyChecks = append(result.PolicyChecks, c2.PolicyChecks...)
+	}
+
+	if (len(c.Notifications) + len(c2.Notifications)) > 0 {
+		result.Notifications = append(result.Notifications, c.Notifications...)
+		result.Notifications = append(result.Notifications, c2.Notifications...)
+	}
+
 	return &result
 }
 
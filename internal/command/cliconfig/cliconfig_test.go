@@ -125,6 +125,22 @@ func TestEnvConfig(t *testing.T) {
 				PluginCacheMayBreakDependencyLockFile: true,
 			},
 		},
+		"TF_PROVIDER_PROCESS_REUSE=1": {
+			map[string]string{
+				"TF_PROVIDER_PROCESS_REUSE": "1",
+			},
+			&Config{
+				ProviderProcessReuse: true,
+			},
+		},
+		"TF_OPERATION_TIMEOUT": {
+			map[string]string{
+				"TF_OPERATION_TIMEOUT": "45m",
+			},
+			&Config{
+				OperationTimeout: "45m",
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -281,6 +297,24 @@ func TestConfigValidate(t *testing.T) {
 			&Config{},
 			0,
 		},
+		"operation timeout good": {
+			&Config{
+				OperationTimeout: "30m",
+			},
+			0,
+		},
+		"operation timeout invalid": {
+			&Config{
+				OperationTimeout: "soon",
+			},
+			1, // not a duration
+		},
+		"operation timeout not positive": {
+			&Config{
+				OperationTimeout: "0s",
+			},
+			1, // must be positive
+		},
 		"host good": {
 			&Config{
 				Hosts: map[string]*ConfigHost{
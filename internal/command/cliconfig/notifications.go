@@ -0,0 +1,138 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"text/template"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// NotificationEvents are the events that a notifications block can select.
+var NotificationEvents = []string{
+	"plan_started",
+	"plan_completed",
+	"apply_started",
+	"apply_completed",
+	"apply_failed",
+	"drift_detected",
+}
+
+// ConfigNotifications is the structure of the "notifications" nested block
+// within the CLI configuration, which enables posting a JSON payload to a
+// webhook when plans and applies start and finish.
+type ConfigNotifications struct {
+	// Name is the label of the block, which identifies the webhook in
+	// messages.
+	Name string `hcl:"-"`
+
+	// URL is the HTTP endpoint that each payload is posted to, along with
+	// the Headers.
+	URL     string            `hcl:"url"`
+	Headers map[string]string `hcl:"headers"`
+
+	// Events are the events to notify the webhook of, or all of the
+	// NotificationEvents if empty.
+	Events []string `hcl:"events"`
+
+	// Secret, if set, is used to sign each payload with HMAC-SHA256.
+	Secret string `hcl:"secret"`
+
+	// Template, if set, is a Go template that renders the payload from the
+	// event, instead of posting the event itself as JSON.
+	Template string `hcl:"template"`
+}
+
+// decodeNotificationsFromConfig decodes the notifications blocks in the
+// given file.
+func decodeNotificationsFromConfig(hclFile *hclast.File) ([]*ConfigNotifications, tfdiags.Diagnostics) {
+	var ret []*ConfigNotifications
+	var diags tfdiags.Diagnostics
+
+	root := hclFile.Node.(*hclast.ObjectList)
+	for _, block := range root.Filter("notifications").Items {
+		if len(block.Keys) != 1 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid notifications block",
+				fmt.Sprintf("The notifications block at %s must have a single label giving its name.", block.Pos()),
+			))
+			continue
+		}
+		name, ok := block.Keys[0].Token.Value().(string)
+		if !ok || name == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid notifications block",
+				fmt.Sprintf("The notifications block at %s has an invalid name.", block.Pos()),
+			))
+			continue
+		}
+
+		notifications := &ConfigNotifications{}
+		if err := hcl.DecodeObject(notifications, block.Val); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid notifications block",
+				fmt.Sprintf("The notifications block at %s is invalid: %s.", block.Pos(), err),
+			))
+			continue
+		}
+		notifications.Name = name
+		// The secret and the URL usually include credentials, which are
+		// best kept out of the configuration file itself.
+		notifications.URL = os.ExpandEnv(notifications.URL)
+		notifications.Secret = os.ExpandEnv(notifications.Secret)
+		ret = append(ret, notifications)
+	}
+
+	return ret, diags
+}
+
+// validate checks the settings in the notifications block.
+func (c *ConfigNotifications) validate() []error {
+	var errs []error
+	if u, err := url.Parse(c.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("The notifications block %q has an invalid url %q: must be an http or https URL", c.Name, c.URL))
+	}
+	for _, event := range c.Events {
+		if !slices.Contains(NotificationEvents, event) {
+			errs = append(errs, fmt.Errorf("The notifications block %q has an unsupported event %q", c.Name, event))
+		}
+	}
+	if c.Template != "" {
+		if _, err := c.ParseTemplate(); err != nil {
+			errs = append(errs, fmt.Errorf("The notifications block %q has an invalid template: %w", c.Name, err))
+		}
+	}
+	return errs
+}
+
+// Selects returns true if the webhook should be notified of the given event.
+func (c *ConfigNotifications) Selects(event string) bool {
+	return len(c.Events) == 0 || slices.Contains(c.Events, event)
+}
+
+// ParseTemplate parses the Template, which has an additional "json"
+// function that encodes its argument as JSON.
+func (c *ConfigNotifications) ParseTemplate() (*template.Template, error) {
+	return template.New(c.Name).Funcs(template.FuncMap{
+		"json": templateJSON,
+	}).Parse(c.Template)
+}
+
+func templateJSON(v interface{}) (string, error) {
+	raw, err := json.Marshal(v)
+	return string(raw), err
+}
@@ -0,0 +1,84 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig_notifications(t *testing.T) {
+	t.Setenv("SLACK_HOOK", "T000/B000")
+	t.Setenv("OPS_SECRET", "s3cret")
+
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "notifications"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if diags := got.Validate(); diags.HasErrors() {
+		t.Fatalf("unexpected validation errors: %s", diags.Err())
+	}
+
+	want := []*ConfigNotifications{
+		{
+			Name:     "slack",
+			URL:      "https://hooks.slack.example.com/services/T000/B000",
+			Events:   []string{"apply_completed", "apply_failed", "drift_detected"},
+			Template: `{"text": {{ printf "%s: %s" .Event .Workspace | json }}}`,
+		},
+		{
+			Name:   "ops",
+			URL:    "https://ops.example.com/tofu",
+			Secret: "s3cret",
+			Headers: map[string]string{
+				"X-Team": "platform",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got.Notifications); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	if got.Notifications[0].Selects("plan_started") {
+		t.Errorf("slack selects plan_started; want only the listed events")
+	}
+	if !got.Notifications[1].Selects("plan_started") {
+		t.Errorf("ops doesn't select plan_started; want all events")
+	}
+}
+
+func TestConfigNotifications_validate(t *testing.T) {
+	config := &Config{
+		Notifications: []*ConfigNotifications{
+			{
+				Name:   "slack",
+				URL:    "hooks.slack.example.com",
+				Events: []string{"apply_completed", "destroy_completed"},
+			},
+			{
+				Name:     "slack",
+				URL:      "https://ops.example.com/tofu",
+				Template: "{{ .Event ",
+			},
+		},
+	}
+
+	var got []string
+	for _, diag := range config.Validate() {
+		got = append(got, diag.Description().Summary)
+	}
+	want := []string{
+		`The notifications block "slack" has an invalid url "hooks.slack.example.com": must be an http or https URL`,
+		`The notifications block "slack" has an unsupported event "destroy_completed"`,
+		`Duplicate notifications block "slack"`,
+		`The notifications block "slack" has an invalid template: template: slack:1: unclosed action`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong errors\n%s", diff)
+	}
+}
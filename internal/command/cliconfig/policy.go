@@ -0,0 +1,151 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ConfigPolicy is the structure of the "policy" nested block within the CLI
+// configuration, which enables evaluating Rego policies against each plan
+// before it is applied.
+type ConfigPolicy struct {
+	// Paths are the policy and data files, or directories of them, that are
+	// loaded to evaluate the plan.
+	Paths []string `hcl:"paths"`
+
+	// Query is the Rego reference to the document whose "deny" and "warn"
+	// rules decide the outcome, defaulting to "data.tofu".
+	Query string `hcl:"query"`
+
+	// Command is the OPA executable used to evaluate the policies,
+	// defaulting to "opa" found in the PATH.
+	Command string `hcl:"command"`
+}
+
+// ConfigPolicyCheck is the structure of the "policy_check" nested block
+// within the CLI configuration, which enables checking each plan with an
+// external program that implements the policy check protocol.
+type ConfigPolicyCheck struct {
+	// Name is the label of the block, which identifies the check in
+	// messages.
+	Name string `hcl:"-"`
+
+	// Command is the executable to run, with the given arguments.
+	Command string   `hcl:"command"`
+	Args    []string `hcl:"args"`
+}
+
+// decodePolicyFromConfig decodes the policy blocks in the given file.
+func decodePolicyFromConfig(hclFile *hclast.File) ([]*ConfigPolicy, tfdiags.Diagnostics) {
+	var ret []*ConfigPolicy
+	var diags tfdiags.Diagnostics
+
+	root := hclFile.Node.(*hclast.ObjectList)
+	for _, block := range root.Filter("policy").Items {
+		if len(block.Keys) > 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid policy block",
+				fmt.Sprintf("The policy block at %s must not have any labels.", block.Pos()),
+			))
+			continue
+		}
+
+		policy := &ConfigPolicy{}
+		if err := hcl.DecodeObject(policy, block.Val); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid policy block",
+				fmt.Sprintf("The policy block at %s is invalid: %s.", block.Pos(), err),
+			))
+			continue
+		}
+		for i, path := range policy.Paths {
+			policy.Paths[i] = os.ExpandEnv(path)
+		}
+		policy.Command = os.ExpandEnv(policy.Command)
+		ret = append(ret, policy)
+	}
+
+	return ret, diags
+}
+
+// validate checks the settings in the policy block.
+func (c *ConfigPolicy) validate() []error {
+	var errs []error
+	if len(c.Paths) == 0 {
+		errs = append(errs, fmt.Errorf("The policy block must set paths to at least one policy file or directory"))
+	}
+	for _, path := range c.Paths {
+		if path == "" {
+			errs = append(errs, fmt.Errorf("The policy block has an empty path in paths"))
+		}
+	}
+	if c.Query != "" && !strings.HasPrefix(c.Query, "data.") {
+		errs = append(errs, fmt.Errorf("The policy block has an invalid query %q: must be a reference to a document under data", c.Query))
+	}
+	return errs
+}
+
+// decodePolicyChecksFromConfig decodes the policy_check blocks in the given
+// file.
+func decodePolicyChecksFromConfig(hclFile *hclast.File) ([]*ConfigPolicyCheck, tfdiags.Diagnostics) {
+	var ret []*ConfigPolicyCheck
+	var diags tfdiags.Diagnostics
+
+	root := hclFile.Node.(*hclast.ObjectList)
+	for _, block := range root.Filter("policy_check").Items {
+		if len(block.Keys) != 1 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid policy_check block",
+				fmt.Sprintf("The policy_check block at %s must have a single label giving its name.", block.Pos()),
+			))
+			continue
+		}
+		name, ok := block.Keys[0].Token.Value().(string)
+		if !ok || name == "" {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid policy_check block",
+				fmt.Sprintf("The policy_check block at %s has an invalid name.", block.Pos()),
+			))
+			continue
+		}
+
+		check := &ConfigPolicyCheck{}
+		if err := hcl.DecodeObject(check, block.Val); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid policy_check block",
+				fmt.Sprintf("The policy_check block at %s is invalid: %s.", block.Pos(), err),
+			))
+			continue
+		}
+		check.Name = name
+		check.Command = os.ExpandEnv(check.Command)
+		ret = append(ret, check)
+	}
+
+	return ret, diags
+}
+
+// validate checks the settings in the policy_check block.
+func (c *ConfigPolicyCheck) validate() []error {
+	var errs []error
+	if c.Command == "" {
+		errs = append(errs, fmt.Errorf("The policy_check block %q must set command", c.Name))
+	}
+	return errs
+}
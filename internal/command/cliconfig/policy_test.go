@@ -0,0 +1,116 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig_policy(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "policy"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if diags := got.Validate(); diags.HasErrors() {
+		t.Fatalf("unexpected validation errors: %s", diags.Err())
+	}
+
+	want := []*ConfigPolicy{
+		{
+			Paths:   []string{"/etc/tofu/policy", "/home/alice/policy/data.json"},
+			Query:   "data.tofu.deploy",
+			Command: "/usr/local/bin/opa",
+		},
+	}
+	if diff := cmp.Diff(want, got.Policy); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestConfigPolicy_validate(t *testing.T) {
+	config := &Config{
+		Policy: []*ConfigPolicy{
+			{
+				Query: "tofu.deny",
+			},
+			{
+				Paths: []string{""},
+			},
+		},
+	}
+
+	var got []string
+	for _, diag := range config.Validate() {
+		got = append(got, diag.Description().Summary)
+	}
+	want := []string{
+		"No more than one policy block may be specified",
+		"The policy block must set paths to at least one policy file or directory",
+		`The policy block has an invalid query "tofu.deny": must be a reference to a document under data`,
+		"The policy block has an empty path in paths",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong errors\n%s", diff)
+	}
+}
+
+func TestLoadConfig_policyCheck(t *testing.T) {
+	t.Setenv("HOME", "/home/alice")
+
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "policy-check"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if diags := got.Validate(); diags.HasErrors() {
+		t.Fatalf("unexpected validation errors: %s", diags.Err())
+	}
+
+	want := []*ConfigPolicyCheck{
+		{
+			Name:    "cost",
+			Command: "/usr/local/bin/cost-gate",
+			Args:    []string{"--max-monthly", "1000"},
+		},
+		{
+			Name:    "naming",
+			Command: "/home/alice/bin/check-names",
+		},
+	}
+	if diff := cmp.Diff(want, got.PolicyChecks); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestConfigPolicyCheck_validate(t *testing.T) {
+	config := &Config{
+		PolicyChecks: []*ConfigPolicyCheck{
+			{
+				Name:    "cost",
+				Command: "cost-gate",
+			},
+			{
+				Name: "cost",
+			},
+		},
+	}
+
+	var got []string
+	for _, diag := range config.Validate() {
+		got = append(got, diag.Description().Summary)
+	}
+	want := []string{
+		`Duplicate policy_check block "cost"`,
+		`The policy_check block "cost" must set command`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong errors\n%s", diff)
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl"
+	hclast "github.com/hashicorp/hcl/hcl/ast"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ConfigTelemetry is the structure of the "telemetry" nested block within the
+// CLI configuration, which configures the optional OpenTelemetry exporters
+// as an alternative to the standard OTEL_* environment variables.
+//
+// The environment variables take precedence over the settings here, so that
+// a particular run can still be customized.
+type ConfigTelemetry struct {
+	// Disabled turns off all telemetry export, even if the environment
+	// variables or the other settings would enable it.
+	Disabled bool `hcl:"disabled"`
+
+	// Traces and Metrics enable the trace and metrics exporters respectively,
+	// like setting OTEL_TRACES_EXPORTER or OTEL_METRICS_EXPORTER to "otlp".
+	Traces  *bool `hcl:"traces"`
+	Metrics *bool `hcl:"metrics"`
+
+	// Endpoint, Protocol and Headers configure the OTLP exporters, like
+	// the corresponding OTEL_EXPORTER_OTLP_* environment variables.
+	Endpoint string            `hcl:"endpoint"`
+	Protocol string            `hcl:"protocol"`
+	Headers  map[string]string `hcl:"headers"`
+
+	// SamplingRatio is the fraction of traces to sample, between 0 and 1.
+	SamplingRatio *float64 `hcl:"sampling_ratio"`
+
+	// ResourceAttributes are added to the resource that describes OpenTofu
+	// in all of the exported telemetry.
+	ResourceAttributes map[string]string `hcl:"resource_attributes"`
+
+	// Projects overrides the settings above when OpenTofu runs in a working
+	// directory that matches the key, which is a path or a glob pattern.
+	Projects map[string]*ConfigTelemetry `hcl:"project"`
+}
+
+// decodeTelemetryFromConfig decodes the telemetry blocks in the given file.
+func decodeTelemetryFromConfig(hclFile *hclast.File) ([]*ConfigTelemetry, tfdiags.Diagnostics) {
+	var ret []*ConfigTelemetry
+	var diags tfdiags.Diagnostics
+
+	root := hclFile.Node.(*hclast.ObjectList)
+	for _, block := range root.Filter("telemetry").Items {
+		if len(block.Keys) > 0 {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid telemetry block",
+				fmt.Sprintf("The telemetry block at %s must not have any labels.", block.Pos()),
+			))
+			continue
+		}
+
+		telemetry := &ConfigTelemetry{}
+		if err := hcl.DecodeObject(telemetry, block.Val); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid telemetry block",
+				fmt.Sprintf("The telemetry block at %s is invalid: %s.", block.Pos(), err),
+			))
+			continue
+		}
+		ret = append(ret, telemetry)
+	}
+
+	return ret, diags
+}
+
+// ForDir returns the telemetry settings to use when running in the given
+// working directory, with the settings from any matching project blocks
+// applied on top of the top-level settings.
+//
+// When more than one project block matches, the ones with longer patterns
+// are assumed to be more specific and so take precedence.
+func (c *ConfigTelemetry) ForDir(dir string) *ConfigTelemetry {
+	if c == nil {
+		return nil
+	}
+
+	var patterns []string
+	for pattern := range c.Projects {
+		if telemetryProjectMatches(pattern, dir) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		if len(patterns[i]) != len(patterns[j]) {
+			return len(patterns[i]) < len(patterns[j])
+		}
+		return patterns[i] < patterns[j]
+	})
+
+	result := *c
+	result.Projects = nil
+	for _, pattern := range patterns {
+		result = *result.merge(c.Projects[pattern])
+	}
+	return &result
+}
+
+// merge returns the result of overriding the receiver's settings with those
+// that are set in the given configuration.
+func (c *ConfigTelemetry) merge(override *ConfigTelemetry) *ConfigTelemetry {
+	result := *c
+	if override.Disabled {
+		result.Disabled = true
+	}
+	if override.Traces != nil {
+		result.Traces = override.Traces
+	}
+	if override.Metrics != nil {
+		result.Metrics = override.Metrics
+	}
+	if override.Endpoint != "" {
+		result.Endpoint = override.Endpoint
+	}
+	if override.Protocol != "" {
+		result.Protocol = override.Protocol
+	}
+	if override.SamplingRatio != nil {
+		result.SamplingRatio = override.SamplingRatio
+	}
+	result.Headers = mergeStringMaps(c.Headers, override.Headers)
+	result.ResourceAttributes = mergeStringMaps(c.ResourceAttributes, override.ResourceAttributes)
+	return &result
+}
+
+func mergeStringMaps(a, b map[string]string) map[string]string {
+	if len(a)+len(b) == 0 {
+		return nil
+	}
+	ret := make(map[string]string, len(a)+len(b))
+	for k, v := range a {
+		ret[k] = v
+	}
+	for k, v := range b {
+		ret[k] = v
+	}
+	return ret
+}
+
+// telemetryProjectMatches returns true if the given project pattern matches
+// the given directory, or one of its parent directories.
+func telemetryProjectMatches(pattern, dir string) bool {
+	pattern = filepath.Clean(expandHomeDir(pattern))
+	for {
+		if matched, _ := filepath.Match(pattern, dir); matched {
+			return true
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return false
+		}
+		dir = parent
+	}
+}
+
+func expandHomeDir(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := homeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// validate checks the settings in the telemetry block and its project
+// blocks, which are described by the given name in any errors.
+func (c *ConfigTelemetry) validate(name string) []error {
+	var errs []error
+	switch c.Protocol {
+	case "", "grpc", "http/protobuf":
+	default:
+		errs = append(errs, fmt.Errorf("The %s has an invalid protocol %q: must be either \"grpc\" or \"http/protobuf\"", name, c.Protocol))
+	}
+	if c.Endpoint != "" {
+		if u, err := url.Parse(c.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("The %s has an invalid endpoint %q: must be an http or https URL", name, c.Endpoint))
+		}
+	}
+	if c.SamplingRatio != nil && (*c.SamplingRatio < 0 || *c.SamplingRatio > 1) {
+		errs = append(errs, fmt.Errorf("The %s has an invalid sampling_ratio %v: must be between 0 and 1", name, *c.SamplingRatio))
+	}
+	for pattern, project := range c.Projects {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			errs = append(errs, fmt.Errorf("The telemetry project %q block has an invalid pattern: %w", pattern, err))
+		}
+		if len(project.Projects) > 0 {
+			errs = append(errs, fmt.Errorf("The telemetry project %q block cannot contain other project blocks", pattern))
+		}
+		errs = append(errs, project.validate(fmt.Sprintf("telemetry project %q block", pattern))...)
+	}
+	return errs
+}
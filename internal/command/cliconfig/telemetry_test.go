@@ -0,0 +1,175 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package cliconfig
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig_telemetry(t *testing.T) {
+	got, diags := loadConfigFile(filepath.Join(fixtureDir, "telemetry"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	if diags := got.Validate(); diags.HasErrors() {
+		t.Fatalf("unexpected validation errors: %s", diags.Err())
+	}
+
+	enabled := true
+	want := &ConfigTelemetry{
+		Traces:   &enabled,
+		Metrics:  &enabled,
+		Endpoint: "https://otel.example.com:4317",
+		Headers: map[string]string{
+			"api-key": "secret",
+		},
+		SamplingRatio: ptrFloat(0.25),
+		ResourceAttributes: map[string]string{
+			"deployment.environment": "ci",
+		},
+		Projects: map[string]*ConfigTelemetry{
+			"/work/infra": {
+				SamplingRatio: ptrFloat(1),
+				ResourceAttributes: map[string]string{
+					"team": "infra",
+				},
+			},
+			"/work/infra/sandbox-*": {
+				Disabled: true,
+			},
+		},
+	}
+	if len(got.Telemetry) != 1 {
+		t.Fatalf("wrong number of telemetry blocks %d", len(got.Telemetry))
+	}
+	if diff := cmp.Diff(want, got.Telemetry[0]); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestConfigTelemetry_ForDir(t *testing.T) {
+	config, diags := loadConfigFile(filepath.Join(fixtureDir, "telemetry"))
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	telemetry := config.Telemetry[0]
+
+	tests := map[string]struct {
+		dir          string
+		disabled     bool
+		ratio        float64
+		resourceAttr map[string]string
+	}{
+		"no project": {
+			dir:   "/work/app",
+			ratio: 0.25,
+			resourceAttr: map[string]string{
+				"deployment.environment": "ci",
+			},
+		},
+		"project": {
+			dir:   "/work/infra",
+			ratio: 1,
+			resourceAttr: map[string]string{
+				"deployment.environment": "ci",
+				"team":                   "infra",
+			},
+		},
+		"subdirectory of project": {
+			dir:   "/work/infra/modules/network",
+			ratio: 1,
+			resourceAttr: map[string]string{
+				"deployment.environment": "ci",
+				"team":                   "infra",
+			},
+		},
+		"more specific project": {
+			dir:      "/work/infra/sandbox-alice",
+			disabled: true,
+			ratio:    1,
+			resourceAttr: map[string]string{
+				"deployment.environment": "ci",
+				"team":                   "infra",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := telemetry.ForDir(filepath.FromSlash(test.dir))
+			if got.Disabled != test.disabled {
+				t.Errorf("wrong disabled %t, want %t", got.Disabled, test.disabled)
+			}
+			if *got.SamplingRatio != test.ratio {
+				t.Errorf("wrong sampling ratio %v, want %v", *got.SamplingRatio, test.ratio)
+			}
+			if diff := cmp.Diff(test.resourceAttr, got.ResourceAttributes); diff != "" {
+				t.Errorf("wrong resource attributes\n%s", diff)
+			}
+			if got.Projects != nil {
+				t.Error("result still has project blocks")
+			}
+			if got.Endpoint != "https://otel.example.com:4317" {
+				t.Errorf("wrong endpoint %q", got.Endpoint)
+			}
+		})
+	}
+
+	// The original configuration is left unchanged.
+	if *telemetry.SamplingRatio != 0.25 || len(telemetry.ResourceAttributes) != 1 {
+		t.Error("ForDir modified the original configuration")
+	}
+}
+
+func TestConfigTelemetry_validate(t *testing.T) {
+	ratio := 1.5
+	config := &Config{
+		Telemetry: []*ConfigTelemetry{
+			{
+				Protocol:      "http/json",
+				Endpoint:      "otel.example.com:4317",
+				SamplingRatio: &ratio,
+			},
+			{},
+		},
+	}
+
+	var got []string
+	for _, diag := range config.Validate() {
+		got = append(got, diag.Description().Summary)
+	}
+	want := []string{
+		"No more than one telemetry block may be specified",
+		`The telemetry block has an invalid protocol "http/json": must be either "grpc" or "http/protobuf"`,
+		`The telemetry block has an invalid endpoint "otel.example.com:4317": must be an http or https URL`,
+		"The telemetry block has an invalid sampling_ratio 1.5: must be between 0 and 1",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong errors\n%s", diff)
+	}
+
+	config = &Config{
+		Telemetry: []*ConfigTelemetry{
+			{
+				Projects: map[string]*ConfigTelemetry{
+					"[": {},
+				},
+			},
+		},
+	}
+	diags := config.Validate()
+	if len(diags) != 1 || !strings.Contains(diags[0].Description().Summary, `The telemetry project "[" block has an invalid pattern`) {
+		t.Errorf("wrong errors %s", diags.Err())
+	}
+}
+
+func ptrFloat(v float64) *float64 {
+	return &v
+}
@@ -129,11 +129,11 @@ func (l *locker) Lock(s statemgr.Locker, reason string) tfdiags.Diagnostics {
 	}, l.view.Locking)
 
 	if err != nil {
-		diags = diags.Append(tfdiags.Sourceless(
+		diags = diags.Append(tfdiags.WithCode(tfdiags.Sourceless(
 			tfdiags.Error,
 			"Error acquiring the state lock",
 			fmt.Sprintf(LockErrorMessage, err),
-		))
+		), tfdiags.CodeStateLock))
 	}
 
 	return diags
@@ -0,0 +1,152 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+)
+
+// CompletionCommand is a Command implementation that prints a script which
+// enables tab completion of OpenTofu commands in a shell.
+//
+// The scripts all work in the same way as the ones written by the
+// -install-autocomplete option: the shell runs the OpenTofu executable with
+// the command line being completed in the COMP_LINE environment variable, and
+// it prints the possible completions one per line.
+type CompletionCommand struct {
+	Meta
+}
+
+func (c *CompletionCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("completion")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("Expected a single argument: SHELL.\n")
+		return cli.RunResultHelp
+	}
+
+	tmpl, ok := completionScripts[args[0]]
+	if !ok {
+		c.Ui.Error(fmt.Sprintf("Unsupported shell %q. The supported shells are %s.\n", args[0], strings.Join(completionShells, ", ")))
+		return cli.RunResultHelp
+	}
+
+	bin, err := os.Executable()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to find the OpenTofu executable: %s", err))
+		return 1
+	}
+
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, completionScriptData{Cmd: "tofu", Bin: bin}); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to generate completion script: %s", err))
+		return 1
+	}
+	c.Ui.Output(strings.TrimSpace(buf.String()))
+	return 0
+}
+
+func (c *CompletionCommand) AutocompleteArgs() complete.Predictor {
+	return completePredictSequence{
+		complete.PredictSet(completionShells...),
+	}
+}
+
+func (c *CompletionCommand) AutocompleteFlags() complete.Flags {
+	return nil
+}
+
+func (c *CompletionCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] completion SHELL
+
+  Prints a script that enables tab completion of OpenTofu commands, options,
+  workspace names and resource addresses in the given shell, which must be
+  one of bash, zsh, fish or powershell.
+
+  To enable completion in every new shell, add the script to your shell's
+  configuration. For example, in bash:
+
+      tofu completion bash >> ~/.bashrc
+
+  In fish:
+
+      tofu completion fish > ~/.config/fish/completions/tofu.fish
+
+  In PowerShell:
+
+      tofu completion powershell >> $PROFILE
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *CompletionCommand) Synopsis() string {
+	return "Generate a shell completion script"
+}
+
+type completionScriptData struct {
+	Cmd, Bin string
+}
+
+// completionShells are the shells that completionScripts has a script for,
+// in the order they are described in.
+var completionShells = []string{"bash", "zsh", "fish", "powershell"}
+
+var completionScripts = map[string]*template.Template{
+	"bash": template.Must(template.New("bash").Parse(`
+complete -C '{{.Bin}}' {{.Cmd}}
+`)),
+	"zsh": template.Must(template.New("zsh").Parse(`
+autoload -U +X bashcompinit && bashcompinit
+complete -o nospace -C '{{.Bin}}' {{.Cmd}}
+`)),
+	"fish": template.Must(template.New("fish").Parse(`
+function __complete_{{.Cmd}}
+    set -lx COMP_LINE (commandline -cp)
+    test -z (commandline -ct)
+    and set COMP_LINE "$COMP_LINE "
+    '{{.Bin}}'
+end
+complete -f -c {{.Cmd}} -a "(__complete_{{.Cmd}})"
+`)),
+	"powershell": template.Must(template.New("powershell").Parse(`
+Register-ArgumentCompleter -Native -CommandName '{{.Cmd}}' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    # Only the part of the command line before the cursor is completed.
+    $line = $commandAst.ToString()
+    $offset = $cursorPosition - $commandAst.Extent.StartOffset
+    if ($offset -gt $line.Length) {
+        $line = $line.PadRight($offset)
+    } else {
+        $line = $line.Substring(0, $offset)
+    }
+
+    $env:COMP_LINE = $line
+    try {
+        & '{{.Bin}}' 2>$null | ForEach-Object {
+            [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+        }
+    } finally {
+        Remove-Item Env:\COMP_LINE
+    }
+}
+`)),
+}
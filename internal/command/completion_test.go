@@ -0,0 +1,50 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestCompletion(t *testing.T) {
+	tests := map[string]string{
+		"bash":       "complete -C ",
+		"zsh":        "bashcompinit",
+		"fish":       "complete -f -c tofu -a \"(__complete_tofu)\"",
+		"powershell": "Register-ArgumentCompleter -Native -CommandName 'tofu'",
+	}
+
+	for shell, want := range tests {
+		t.Run(shell, func(t *testing.T) {
+			ui := new(cli.MockUi)
+			c := &CompletionCommand{
+				Meta: Meta{Ui: ui},
+			}
+			if code := c.Run([]string{shell}); code != 0 {
+				t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+			}
+			if got := ui.OutputWriter.String(); !strings.Contains(got, want) {
+				t.Fatalf("output does not contain %q\n%s", want, got)
+			}
+		})
+	}
+}
+
+func TestCompletion_invalid(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &CompletionCommand{
+		Meta: Meta{Ui: ui},
+	}
+	if code := c.Run([]string{"tcsh"}); code != cli.RunResultHelp {
+		t.Fatalf("wrong exit status %d; want %d", code, cli.RunResultHelp)
+	}
+	if got, want := ui.ErrorWriter.String(), `Unsupported shell "tcsh"`; !strings.Contains(got, want) {
+		t.Fatalf("error does not contain %q\n%s", want, got)
+	}
+}
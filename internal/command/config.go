@@ -0,0 +1,37 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// ConfigCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type ConfigCommand struct {
+	Meta
+}
+
+func (c *ConfigCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *ConfigCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] config <subcommand> [options] [args]
+
+  This command has subcommands for inspecting the configuration in the
+  current working directory.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ConfigCommand) Synopsis() string {
+	return "Configuration related commands"
+}
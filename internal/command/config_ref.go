@@ -0,0 +1,137 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/getmodules"
+	"github.com/opentofu/opentofu/internal/initwd"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// fetchConfigRef fetches the configuration at the ref selected by the given
+// arguments into a new temporary directory, installs the modules that it
+// calls, and returns the directory of its root module along with a record of
+// where it came from.
+//
+// It also replaces the shared configuration loader with one that loads the
+// modules installed for the fetched configuration, so it must be called
+// after the backend has been prepared from the working directory and before
+// the operation is built. The caller must call the returned cleanup function
+// once the operation has finished with the configuration.
+//
+// The exact commit is found, and the signature of the tag checked, by
+// running the git command in the fetched repository.
+func (m *Meta) fetchConfigRef(ctx context.Context, ref *arguments.ConfigRef) (string, *states.ConfigSource, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	tmpDir, err := os.MkdirTemp("", "tofu-config-ref-")
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to create a directory for the configuration: %w", err))
+		return "", nil, nil, diags
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(tmpDir); err != nil {
+			log.Printf("[WARN] failed to remove the configuration fetched from %s: %s", ref.Address, err)
+		}
+	}
+
+	fail := func(summary, detail string) (string, *states.ConfigSource, func(), tfdiags.Diagnostics) {
+		cleanup()
+		diags = diags.Append(tfdiags.Sourceless(tfdiags.Error, summary, detail))
+		return "", nil, nil, diags
+	}
+
+	packageAddr, subDir, err := getmodules.NormalizePackageAddress(ref.Address)
+	if err != nil {
+		return fail("Invalid config ref", fmt.Sprintf("The -config-ref address %q is invalid: %s.", ref.Address, err))
+	}
+	pkgDir := filepath.Join(tmpDir, "src")
+	if err := getmodules.NewPackageFetcher().FetchPackage(ctx, pkgDir, packageAddr, nil); err != nil {
+		return fail("Failed to fetch configuration", fmt.Sprintf("Could not fetch the configuration from %s: %s.", ref.Address, err))
+	}
+
+	commit, err := gitOutput(ctx, pkgDir, "rev-parse", "HEAD")
+	if err != nil {
+		return fail("Failed to fetch configuration", fmt.Sprintf("Could not find the commit that %s refers to: %s.", ref.Address, err))
+	}
+	if ref.VerifySignature {
+		if _, err := gitOutput(ctx, pkgDir, "verify-tag", ref.Ref()); err != nil {
+			return fail(
+				"Invalid config ref signature",
+				fmt.Sprintf("The ref %q of %s must be a tag with a valid signature, which git could not verify: %s.", ref.Ref(), ref.Address, err),
+			)
+		}
+	}
+
+	configDir := pkgDir
+	if subDir != "" {
+		configDir, err = getmodules.ExpandSubdirGlobs(pkgDir, subDir)
+		if err != nil {
+			return fail("Failed to fetch configuration", fmt.Sprintf("Could not find the configuration in %s: %s.", ref.Address, err))
+		}
+	}
+
+	modulesDir := filepath.Join(tmpDir, "modules")
+	if err := os.MkdirAll(modulesDir, os.ModePerm); err != nil {
+		return fail("Failed to install modules", fmt.Sprintf("Could not create the modules directory: %s.", err))
+	}
+	loader, err := m.newConfigLoader(modulesDir)
+	if err != nil {
+		return fail("Failed to initialize config loader", err.Error())
+	}
+	call, callDiags := m.rootModuleCall(configDir)
+	diags = diags.Append(callDiags)
+	if callDiags.HasErrors() {
+		cleanup()
+		return "", nil, nil, diags
+	}
+	inst := initwd.NewModuleInstaller(modulesDir, loader, m.registryClient())
+	_, instDiags := inst.InstallModules(ctx, configDir, "tests", false, false, initwd.ModuleInstallHooksImpl{}, call)
+	diags = diags.Append(instDiags)
+	if instDiags.HasErrors() {
+		cleanup()
+		return "", nil, nil, diags
+	}
+
+	m.configLoader = loader
+	if m.View != nil {
+		m.View.SetConfigSources(loader.Sources)
+	}
+
+	source := &states.ConfigSource{
+		Address:  ref.Address,
+		Commit:   commit,
+		Verified: ref.VerifySignature,
+	}
+	return configDir, source, cleanup, diags
+}
+
+// gitOutput runs git with the given arguments in the given directory,
+// returning its output with surrounding whitespace removed.
+func gitOutput(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%w: %s", err, msg)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,119 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// testConfigRepo creates a git repository with the configuration from the
+// given fixture directory, tagged v1, and returns its directory and commit.
+func testConfigRepo(t *testing.T, fixture string) (string, string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	testCopyDir(t, testFixturePath(fixture), dir)
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "initial"},
+		{"tag", "v1"},
+	} {
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %s\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, strings.TrimSpace(string(out))
+}
+
+func TestApply_configRef(t *testing.T) {
+	repo, commit := testConfigRepo(t, "apply")
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	tmpPattern := filepath.Join(os.TempDir(), "tofu-config-ref-*")
+	before, err := filepath.Glob(tmpPattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statePath := testTempFile(t)
+	address := "git::file://" + filepath.ToSlash(repo) + "?ref=v1"
+	code := c.Run([]string{"-state", statePath, "-auto-approve", "-config-ref", address})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	if !p.ApplyResourceChangeCalled {
+		t.Fatal("configuration from the ref was not applied")
+	}
+	state := testStateRead(t, statePath)
+	want := &states.ConfigSource{
+		Address: address,
+		Commit:  commit,
+	}
+	if diff := cmp.Diff(want, state.ConfigSource); diff != "" {
+		t.Fatalf("wrong config source\n%s", diff)
+	}
+
+	after, err := filepath.Glob(tmpPattern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(before) {
+		t.Fatalf("temporary configuration was not removed: %v", after)
+	}
+}
+
+func TestApply_configRefUnsignedTag(t *testing.T) {
+	repo, _ := testConfigRepo(t, "apply")
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	address := "git::file://" + filepath.ToSlash(repo) + "?ref=v1"
+	code := c.Run([]string{"-state", testTempFile(t), "-auto-approve", "-config-ref", address, "-verify-config-ref"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Invalid config ref signature"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant: %s", got, want)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/opentofu/opentofu/internal/command/jsonconfig"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ConfigSchemaCommand is a Command implementation that prints out the
+// schema of the configuration in the current working directory, as seen by
+// a caller of that configuration.
+type ConfigSchemaCommand struct {
+	Meta
+}
+
+func (c *ConfigSchemaCommand) Help() string {
+	return configSchemaCommandHelp
+}
+
+func (c *ConfigSchemaCommand) Synopsis() string {
+	return "Show the variables, outputs and requirements of the configuration"
+}
+
+func (c *ConfigSchemaCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("config schema")
+	var jsonOutput bool
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	if !jsonOutput {
+		c.Ui.Error(
+			"The `tofu config schema` command requires the `-json` flag.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) > 0 {
+		c.Ui.Error("The config schema command expects no arguments.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	config, configDiags := c.loadConfig(".")
+	diags = diags.Append(configDiags)
+	if configDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	scope := &lang.Scope{}
+	funcs := make(map[string]function.Function)
+	for name, fn := range scope.Functions() {
+		if isIgnoredFunction(name) {
+			continue
+		}
+		funcs[name] = fn
+	}
+
+	jsonSchema, marshalDiags := jsonconfig.MarshalSchema(config, funcs)
+	diags = diags.Append(marshalDiags)
+	if marshalDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	c.Ui.Output(string(jsonSchema))
+
+	return 0
+}
+
+const configSchemaCommandHelp = `
+Usage: tofu [global options] config schema -json
+
+  Prints out a json representation of the schema of the configuration in
+  the current working directory: its input variables with their types,
+  defaults and validation rules, its output values, the modules it calls,
+  the providers it requires and the functions it can call.
+
+  This is intended for tools such as editors and form-based user interfaces
+  that help with calling the configuration as a module.
+`
@@ -0,0 +1,74 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+)
+
+func TestConfigSchema_error(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &ConfigSchemaCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	// This test will always error because it's missing the -json flag
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("expected error, got:\n%s", ui.OutputWriter.String())
+	}
+}
+
+func TestConfigSchema_output(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("config-schema"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	c := &ConfigSchemaCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	if code := c.Run([]string{"-json"}); code != 0 {
+		t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+	}
+
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"format_version":        `"1.0"`,
+		"variables":             `{"name":{"type":"string","required":true,"description":"The name of the instance.","nullable":true,"validations":[{"condition":{"references":["var.name"]},"error_message":{"constant_value":"The name must not be empty."}}]},"tags":{"type":["object",{"env":"string","owner":"string"},["owner"]],"default":{"env":"dev","owner":null},"required":false,"sensitive":true,"nullable":true}}`,
+		"outputs":               `{"id":{"description":"The ID of the instance."}}`,
+		"provider_requirements": `{"registry.opentofu.org/hashicorp/test":{"version_constraint":"~\u003e 1.0"}}`,
+	}
+	for key, want := range want {
+		if diff := cmp.Diff(want, string(got[key])); diff != "" {
+			t.Errorf("wrong %s\n%s", key, diff)
+		}
+	}
+
+	var funcs map[string]json.RawMessage
+	if err := json.Unmarshal(got["functions"], &funcs); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := funcs["max"]; !ok {
+		t.Error(`missing function signature for "max"`)
+	}
+	if _, ok := funcs["map"]; ok {
+		t.Error(`unexpected function signature for ignored function "map"`)
+	}
+}
@@ -28,14 +28,23 @@ type ConsoleCommand struct {
 }
 
 func (c *ConsoleCommand) Run(args []string) int {
+	var evalExpr string
+	var jsonOutput bool
+
 	args = c.Meta.process(args)
 	cmdFlags := c.Meta.extendedFlagSet("console")
 	cmdFlags.StringVar(&c.Meta.statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.StringVar(&evalExpr, "eval", "", "eval")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command line flags: %s\n", err.Error()))
 		return 1
 	}
+	if jsonOutput && evalExpr == "" {
+		c.Ui.Error("The -json option is only supported together with -eval.")
+		return 1
+	}
 
 	configPath, err := modulePath(cmdFlags.Args())
 	if err != nil {
@@ -170,19 +179,39 @@ func (c *ConsoleCommand) Run(args []string) int {
 		Scope: scope,
 	}
 
+	// An expression given on the command line takes priority over any
+	// input on stdin.
+	if evalExpr != "" {
+		return c.modeEval(session, evalExpr, jsonOutput, ui)
+	}
+
 	// Determine if stdin is a pipe. If so, we evaluate directly.
 	if c.StdinPiped() {
 		return c.modePiped(session, ui)
 	}
 
-	return c.modeInteractive(session, ui)
+	// Completion is only a convenience, so we'll just complete fewer names
+	// if we can't load the schemas.
+	schemas, _ := lr.Core.Schemas(lr.Config, lr.InputState)
+	completer := repl.NewCompleter(lr.Config, lr.InputState, schemas, scope.Functions())
+
+	return c.modeInteractive(session, completer, ui)
 }
 
 func (c *ConsoleCommand) modePiped(session *repl.Session, ui cli.Ui) int {
 	var lastResult string
+	var pending []string
 	scanner := bufio.NewScanner(os.Stdin)
 	for scanner.Scan() {
-		result, exit, diags := session.Handle(strings.TrimSpace(scanner.Text()))
+		// An expression with unclosed brackets continues on the next line.
+		pending = append(pending, scanner.Text())
+		input := strings.Join(pending, "\n")
+		if repl.IncompleteInput(input) {
+			continue
+		}
+		pending = nil
+
+		result, exit, diags := session.Handle(strings.TrimSpace(input))
 		if diags.HasErrors() {
 			// In piped mode we'll exit immediately on error.
 			c.showDiagnostics(diags)
@@ -196,12 +225,43 @@ func (c *ConsoleCommand) modePiped(session *repl.Session, ui cli.Ui) int {
 		lastResult = result
 	}
 
+	if len(pending) > 0 {
+		// We'll evaluate an incomplete expression at the end of the input
+		// anyway, so that the user sees the resulting syntax error.
+		result, _, diags := session.Handle(strings.TrimSpace(strings.Join(pending, "\n")))
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		lastResult = result
+	}
+
 	// Output the final result
 	ui.Output(lastResult)
 
 	return 0
 }
 
+func (c *ConsoleCommand) modeEval(session *repl.Session, expr string, jsonOutput bool, ui cli.Ui) int {
+	if jsonOutput {
+		result, diags := session.HandleJSON(expr)
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		ui.Output(string(result))
+		return 0
+	}
+
+	result, _, diags := session.Handle(expr)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	ui.Output(result)
+	return 0
+}
+
 func (c *ConsoleCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] console [options]
@@ -214,10 +274,23 @@ Usage: tofu [global options] console [options]
   current state. This lets you explore and test interpolations before
   using them in future configurations.
 
+  In the interactive console, press <tab> to complete the names of
+  functions and of objects in the configuration and state, and continue an
+  expression on the following lines by leaving a bracket unclosed. Type
+  ":type" followed by an expression to see the type of its result. The
+  console history is saved between sessions.
+
   This command will never modify your state.
 
 Options:
 
+  -eval=expr        Evaluate the given expression, print its result, and exit
+                    instead of starting an interactive console.
+
+  -json             With -eval, print the result as a JSON object with the
+                    properties "value", "type" and "sensitive", like
+                    "tofu output -json" does for each output value.
+
   -state=path       Legacy option for the local backend only. See the local
                     backend's documentation for more information.
 
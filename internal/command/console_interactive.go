@@ -8,21 +8,36 @@ package command
 import (
 	"fmt"
 	"io"
+	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
 	"github.com/opentofu/opentofu/internal/repl"
 
 	"github.com/chzyer/readline"
 	"github.com/mitchellh/cli"
 )
 
-func (c *ConsoleCommand) modeInteractive(session *repl.Session, ui cli.Ui) int {
+const (
+	consolePrompt             = "> "
+	consoleContinuationPrompt = "... "
+
+	// consoleHistoryFilename is the name of the file in the CLI
+	// configuration directory where the console saves its history.
+	consoleHistoryFilename = "console_history"
+)
+
+func (c *ConsoleCommand) modeInteractive(session *repl.Session, completer *repl.Completer, ui cli.Ui) int {
 	// Configure input
 	l, err := readline.NewEx(&readline.Config{
-		Prompt:            "> ",
+		Prompt:            consolePrompt,
 		InterruptPrompt:   "^C",
 		EOFPrompt:         "exit",
+		HistoryFile:       consoleHistoryFile(),
 		HistorySearchFold: true,
+		AutoComplete:      consoleAutoCompleter{completer},
 		Stdin:             os.Stdin,
 		Stdout:            os.Stdout,
 		Stderr:            os.Stderr,
@@ -35,10 +50,20 @@ func (c *ConsoleCommand) modeInteractive(session *repl.Session, ui cli.Ui) int {
 	}
 	defer l.Close()
 
+	// pending are the earlier lines of an expression that continues over
+	// multiple lines because it has unclosed brackets.
+	var pending []string
 	for {
 		// Read a line
 		line, err := l.Readline()
 		if err == readline.ErrInterrupt {
+			if len(pending) > 0 {
+				// Interrupting a multi-line expression discards it, but
+				// doesn't exit the console.
+				pending = nil
+				l.SetPrompt(consolePrompt)
+				continue
+			}
 			if len(line) == 0 {
 				break
 			} else {
@@ -48,7 +73,16 @@ func (c *ConsoleCommand) modeInteractive(session *repl.Session, ui cli.Ui) int {
 			break
 		}
 
-		out, exit, diags := session.Handle(line)
+		pending = append(pending, line)
+		input := strings.Join(pending, "\n")
+		if repl.IncompleteInput(input) {
+			l.SetPrompt(consoleContinuationPrompt)
+			continue
+		}
+		pending = nil
+		l.SetPrompt(consolePrompt)
+
+		out, exit, diags := session.Handle(input)
 		if diags.HasErrors() {
 			c.showDiagnostics(diags)
 		}
@@ -61,3 +95,43 @@ func (c *ConsoleCommand) modeInteractive(session *repl.Session, ui cli.Ui) int {
 
 	return 0
 }
+
+// consoleHistoryFile returns the path of the file where the console saves
+// its history between sessions, or an empty string if the console should
+// not save its history.
+func consoleHistoryFile() string {
+	dir, err := cliconfig.ConfigDir()
+	if err != nil {
+		log.Printf("[WARN] Not saving console history: %s", err)
+		return ""
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		log.Printf("[WARN] Not saving console history: %s", err)
+		return ""
+	}
+	return filepath.Join(dir, consoleHistoryFilename)
+}
+
+// consoleAutoCompleter adapts a repl.Completer to the interface that
+// readline expects.
+type consoleAutoCompleter struct {
+	completer *repl.Completer
+}
+
+var _ readline.AutoCompleter = consoleAutoCompleter{}
+
+func (a consoleAutoCompleter) Do(line []rune, pos int) ([][]rune, int) {
+	// The completer works with byte offsets, but readline gives us an offset
+	// in runes.
+	prefix := string(line[:pos])
+	candidates, length := a.completer.Complete(prefix, len(prefix))
+
+	// readline wants only the part of each candidate that follows what has
+	// already been typed, and the length of what has been typed in runes.
+	typed := prefix[len(prefix)-length:]
+	ret := make([][]rune, len(candidates))
+	for i, candidate := range candidates {
+		ret[i] = []rune(strings.TrimPrefix(candidate, typed))
+	}
+	return ret, len([]rune(typed))
+}
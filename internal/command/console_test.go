@@ -56,6 +56,104 @@ func TestConsole_basic(t *testing.T) {
 	}
 }
 
+func TestConsole_multiline(t *testing.T) {
+	testCwd(t)
+
+	p := testProvider()
+	ui := cli.NewMockUi()
+	view, _ := testView(t)
+	c := &ConsoleCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	var output bytes.Buffer
+	defer testStdinPipe(t, strings.NewReader("[\n  1,\n  2 + 3,\n]\n"))()
+	outCloser := testStdoutCapture(t, &output)
+
+	args := []string{}
+	code := c.Run(args)
+	outCloser()
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	actual := output.String()
+	if actual != "[\n  1,\n  5,\n]\n" {
+		t.Fatalf("bad: %q", actual)
+	}
+}
+
+func TestConsole_eval(t *testing.T) {
+	tests := map[string]struct {
+		Args []string
+		Want string
+	}{
+		"human": {
+			[]string{"-eval", `split(",", "a,b")`},
+			"tolist([\n  \"a\",\n  \"b\",\n])\n",
+		},
+		"json": {
+			[]string{"-eval", `split(",", "a,b")`, "-json"},
+			"{\n  \"sensitive\": false,\n  \"type\": [\n    \"list\",\n    \"string\"\n  ],\n  \"value\": [\n    \"a\",\n    \"b\"\n  ]\n}\n",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			testCwd(t)
+
+			p := testProvider()
+			ui := cli.NewMockUi()
+			view, _ := testView(t)
+			c := &ConsoleCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(p),
+					Ui:               ui,
+					View:             view,
+				},
+			}
+
+			// The expression from -eval takes priority over stdin.
+			var output bytes.Buffer
+			defer testStdinPipe(t, strings.NewReader("1+5\n"))()
+			outCloser := testStdoutCapture(t, &output)
+
+			code := c.Run(test.Args)
+			outCloser()
+			if code != 0 {
+				t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+			}
+
+			if got := output.String(); got != test.Want {
+				t.Fatalf("wrong output\ngot:  %q\nwant: %q", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestConsole_jsonWithoutEval(t *testing.T) {
+	testCwd(t)
+
+	ui := cli.NewMockUi()
+	c := &ConsoleCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	if code := c.Run([]string{"-json"}); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "The -json option is only supported together with -eval."; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
 func TestConsole_tfvars(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath("apply-vars"), td)
@@ -12,7 +12,9 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/e2e"
+	"github.com/opentofu/opentofu/internal/getproviders"
 )
 
 // The tests in this file are for the "tofu providers mirror" command,
@@ -41,7 +43,8 @@ func testOpenTofuProvidersMirror(t *testing.T, fixture string) {
 	fixturePath := filepath.Join("testdata", fixture)
 	tf := e2e.NewBinary(t, tofuBin, fixturePath)
 
-	stdout, stderr, err := tf.Run("providers", "mirror", "-platform=linux_amd64", "-platform=windows_386", outputDir)
+	lockFile := filepath.Join(t.TempDir(), ".terraform.lock.hcl")
+	stdout, stderr, err := tf.Run("providers", "mirror", "-platform=linux_amd64", "-platform=windows_386", "-lock-file="+lockFile, outputDir)
 	if err != nil {
 		t.Fatalf("unexpected error: %s\nstdout:\n%s\nstderr:\n%s", err, stdout, stderr)
 	}
@@ -53,10 +56,16 @@ func testOpenTofuProvidersMirror(t *testing.T, fixture string) {
 	want := []string{
 		"registry.opentofu.org/hashicorp/null/2.1.0.json",
 		"registry.opentofu.org/hashicorp/null/index.json",
+		"registry.opentofu.org/hashicorp/null/terraform-provider-null_2.1.0_SHA256SUMS",
+		"registry.opentofu.org/hashicorp/null/terraform-provider-null_2.1.0_SHA256SUMS.keys",
+		"registry.opentofu.org/hashicorp/null/terraform-provider-null_2.1.0_SHA256SUMS.sig",
 		"registry.opentofu.org/hashicorp/null/terraform-provider-null_2.1.0_linux_amd64.zip",
 		"registry.opentofu.org/hashicorp/null/terraform-provider-null_2.1.0_windows_386.zip",
 		"registry.opentofu.org/hashicorp/template/2.1.1.json",
 		"registry.opentofu.org/hashicorp/template/index.json",
+		"registry.opentofu.org/hashicorp/template/terraform-provider-template_2.1.1_SHA256SUMS",
+		"registry.opentofu.org/hashicorp/template/terraform-provider-template_2.1.1_SHA256SUMS.keys",
+		"registry.opentofu.org/hashicorp/template/terraform-provider-template_2.1.1_SHA256SUMS.sig",
 		"registry.opentofu.org/hashicorp/template/terraform-provider-template_2.1.1_linux_amd64.zip",
 		"registry.opentofu.org/hashicorp/template/terraform-provider-template_2.1.1_windows_386.zip",
 	}
@@ -83,4 +92,28 @@ func testOpenTofuProvidersMirror(t *testing.T, fixture string) {
 	if diff := cmp.Diff(want, got); diff != "" {
 		t.Errorf("unexpected files in result\n%s", diff)
 	}
+
+	// The lock file records the packages we mirrored, and the signed
+	// checksums of the packages for all of the other platforms.
+	locks, diags := depsfile.LoadLocksFromFile(lockFile)
+	if diags.HasErrors() {
+		t.Fatalf("invalid lock file: %s", diags.Err())
+	}
+	for _, lock := range locks.AllProviders() {
+		var h1, zh int
+		for _, hash := range lock.AllHashes() {
+			switch hash.Scheme() {
+			case getproviders.HashScheme1:
+				h1++
+			case getproviders.HashSchemeZip:
+				zh++
+			}
+		}
+		if h1 != 2 {
+			t.Errorf("lock for %s has %d h1: hashes; want 2", lock.Provider(), h1)
+		}
+		if zh <= 2 {
+			t.Errorf("lock for %s has %d zh: hashes; want more than 2", lock.Provider(), zh)
+		}
+	}
 }
@@ -0,0 +1,77 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ExplainCommand is a Command implementation that prints extended guidance
+// about a diagnostic code.
+type ExplainCommand struct {
+	Meta
+}
+
+func (c *ExplainCommand) Help() string {
+	return explainCommandHelp
+}
+
+func (c *ExplainCommand) Synopsis() string {
+	return "Show extended guidance for a diagnostic code"
+}
+
+func (c *ExplainCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("explain")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	switch len(args) {
+	case 0:
+		// Without a code we list all of the codes, so that users can find
+		// the one they are interested in.
+		var buf strings.Builder
+		for _, info := range tfdiags.Codes {
+			info = info.Localized()
+			fmt.Fprintf(&buf, "%s  %s\n", info.Code, info.Summary)
+		}
+		c.Ui.Output(strings.TrimSuffix(buf.String(), "\n"))
+		return 0
+	case 1:
+		// continues below
+	default:
+		c.Ui.Error("The explain command expects at most one argument: the diagnostic code to explain.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	info, ok := tfdiags.LookupCode(args[0])
+	if !ok {
+		c.Ui.Error(fmt.Sprintf("There is no diagnostic code %q. Run \"tofu explain\" without arguments to list all of the codes.", args[0]))
+		return 1
+	}
+
+	info = info.Localized()
+	c.Ui.Output(fmt.Sprintf("%s: %s\n\n%s", info.Code, info.Summary, info.Explanation))
+	return 0
+}
+
+const explainCommandHelp = `
+Usage: tofu [global options] explain [code]
+
+  Prints extended guidance about the diagnostic with the given code, such
+  as OTF2004. OpenTofu includes the code of each diagnostic that has one in
+  its machine-readable JSON output.
+
+  If no code is given, prints a list of all of the diagnostic codes.
+`
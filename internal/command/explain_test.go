@@ -0,0 +1,66 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+)
+
+func TestExplain(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &ExplainCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	if code := c.Run([]string{"otf2004"}); code != 0 {
+		t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+	}
+
+	got := ui.OutputWriter.String()
+	if want := "OTF2004: Reference to undeclared resource\n\n"; !strings.HasPrefix(got, want) {
+		t.Errorf("wrong output\ngot:  %s\nwant prefix: %s", got, want)
+	}
+}
+
+func TestExplain_list(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &ExplainCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("wrong exit status %d; want 0\nstderr: %s", code, ui.ErrorWriter.String())
+	}
+
+	got := ui.OutputWriter.String()
+	if want := "OTF1001  Duplicate variable declaration\n"; !strings.HasPrefix(got, want) {
+		t.Errorf("wrong output\ngot:  %s\nwant prefix: %s", got, want)
+	}
+}
+
+func TestExplain_unknown(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &ExplainCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	if code := c.Run([]string{"OTF0000"}); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\nstdout: %s", code, ui.OutputWriter.String())
+	}
+
+	if got, want := ui.ErrorWriter.String(), `There is no diagnostic code "OTF0000".`; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant substring: %s", got, want)
+	}
+}
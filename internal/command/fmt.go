@@ -7,6 +7,7 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
@@ -18,6 +19,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	hcljson "github.com/hashicorp/hcl/v2/json"
 	"github.com/mitchellh/cli"
 
 	"github.com/opentofu/opentofu/internal/configs"
@@ -34,18 +36,47 @@ var (
 		".tfvars",
 		".tftest.hcl",
 	}
+
+	// fmtJSONVarsExt is the extension of the JSON variable definitions files
+	// that are formatted when the -tfvars-json option is set.
+	fmtJSONVarsExt = ".tfvars.json"
+
+	// fmtMarkdownExts are the extensions of the Markdown files whose HCL
+	// code blocks are formatted when the -markdown option is set.
+	fmtMarkdownExts = []string{
+		".md",
+		".mdx",
+	}
+
+	// fmtMarkdownLangs are the info strings of the fenced code blocks in
+	// Markdown files that contain OpenTofu language code.
+	fmtMarkdownLangs = map[string]bool{
+		"hcl":       true,
+		"opentofu":  true,
+		"terraform": true,
+		"tf":        true,
+	}
+
+	// fmtTestKeywordAttrs are the attributes in test files whose values are
+	// keywords, which fmt unwraps if they are written as quoted strings.
+	fmtTestKeywordAttrs = map[string]bool{
+		"run.command":           true,
+		"run.plan_options.mode": true,
+	}
 )
 
 // FmtCommand is a Command implementation that rewrites OpenTofu config
 // files to a canonical format and style.
 type FmtCommand struct {
 	Meta
-	list      bool
-	write     bool
-	diff      bool
-	check     bool
-	recursive bool
-	input     io.Reader // STDIN if nil
+	list       bool
+	write      bool
+	diff       bool
+	check      bool
+	recursive  bool
+	markdown   bool
+	tfvarsJSON bool
+	input      io.Reader // STDIN if nil
 }
 
 func (c *FmtCommand) Run(args []string) int {
@@ -60,6 +91,8 @@ func (c *FmtCommand) Run(args []string) int {
 	cmdFlags.BoolVar(&c.diff, "diff", false, "diff")
 	cmdFlags.BoolVar(&c.check, "check", false, "check")
 	cmdFlags.BoolVar(&c.recursive, "recursive", false, "recursive")
+	cmdFlags.BoolVar(&c.markdown, "markdown", false, "markdown")
+	cmdFlags.BoolVar(&c.tfvarsJSON, "tfvars-json", false, "tfvars-json")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -137,7 +170,7 @@ func (c *FmtCommand) fmt(paths []string, stdin io.Reader, stdout io.Writer) tfdi
 			diags = diags.Append(dirDiags)
 		} else {
 			fmtd := false
-			for _, ext := range fmtSupportedExts {
+			for _, ext := range c.supportedExts() {
 				if strings.HasSuffix(path, ext) {
 					f, err := os.Open(path)
 					if err != nil {
@@ -160,7 +193,8 @@ func (c *FmtCommand) fmt(paths []string, stdin io.Reader, stdout io.Writer) tfdi
 			}
 
 			if !fmtd {
-				diags = diags.Append(fmt.Errorf("Only .tf, .tfvars, and .tftest.hcl files can be processed with tofu fmt"))
+				exts := c.supportedExts()
+				diags = diags.Append(fmt.Errorf("Only %s, and %s files can be processed with tofu fmt", strings.Join(exts[:len(exts)-1], ", "), exts[len(exts)-1]))
 				continue
 			}
 		}
@@ -184,17 +218,12 @@ func (c *FmtCommand) processFile(path string, r io.Reader, w io.Writer, isStdout
 	// diagnostic errors can include the source code snippet
 	c.registerSynthConfigSource(path, src)
 
-	// File must be parseable as HCL native syntax before we'll try to format
-	// it. If not, the formatter is likely to make drastic changes that would
-	// be hard for the user to undo.
-	_, syntaxDiags := hclsyntax.ParseConfig(src, path, hcl.Pos{Line: 1, Column: 1})
-	if syntaxDiags.HasErrors() {
-		diags = diags.Append(syntaxDiags)
+	result, formatDiags := c.formatFile(src, path)
+	diags = diags.Append(formatDiags)
+	if formatDiags.HasErrors() {
 		return diags
 	}
 
-	result := c.formatSourceCode(src, path)
-
 	if !bytes.Equal(src, result) {
 		// Something was changed
 		if c.list {
@@ -263,7 +292,7 @@ func (c *FmtCommand) processDir(path string, stdout io.Writer) tfdiags.Diagnosti
 			continue
 		}
 
-		for _, ext := range fmtSupportedExts {
+		for _, ext := range c.supportedExts() {
 			if strings.HasSuffix(name, ext) {
 				f, err := os.Open(subPath)
 				if err != nil {
@@ -286,6 +315,218 @@ func (c *FmtCommand) processDir(path string, stdout io.Writer) tfdiags.Diagnosti
 	return diags
 }
 
+// supportedExts returns the extensions of the files that fmt processes,
+// which depend on the options.
+func (c *FmtCommand) supportedExts() []string {
+	ret := make([]string, 0, len(fmtSupportedExts)+len(fmtMarkdownExts)+1)
+	ret = append(ret, fmtSupportedExts...)
+	if c.tfvarsJSON {
+		ret = append(ret, fmtJSONVarsExt)
+	}
+	if c.markdown {
+		ret = append(ret, fmtMarkdownExts...)
+	}
+	return ret
+}
+
+// formatFile returns the formatted version of the given file content, using
+// the formatting rules for the kind of file that the given path indicates.
+func (c *FmtCommand) formatFile(src []byte, path string) ([]byte, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	switch {
+	case c.tfvarsJSON && strings.HasSuffix(path, fmtJSONVarsExt):
+		return c.formatJSONSourceCode(src, path)
+	case c.markdown && hasAnySuffix(path, fmtMarkdownExts):
+		return c.formatMarkdown(src, path), diags
+	}
+
+	// File must be parseable as HCL native syntax before we'll try to format
+	// it. If not, the formatter is likely to make drastic changes that would
+	// be hard for the user to undo.
+	_, syntaxDiags := hclsyntax.ParseConfig(src, path, hcl.Pos{Line: 1, Column: 1})
+	if syntaxDiags.HasErrors() {
+		diags = diags.Append(syntaxDiags)
+		return nil, diags
+	}
+
+	return c.formatSourceCode(src, path), diags
+}
+
+// formatJSONSourceCode returns the canonical form of a JSON variables file:
+// object properties sorted by name and indented with two spaces.
+func (c *FmtCommand) formatJSONSourceCode(src []byte, filename string) ([]byte, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// We parse with the HCL JSON parser first so that syntax errors are
+	// reported in the same way as for any other configuration file.
+	_, syntaxDiags := hcljson.Parse(src, filename)
+	if syntaxDiags.HasErrors() {
+		diags = diags.Append(syntaxDiags)
+		return nil, diags
+	}
+
+	// Numbers are kept as their original text so that we don't lose
+	// precision or change their notation.
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to parse %s: %w", filename, err))
+		return nil, diags
+	}
+
+	// The standard library encoder writes object properties in
+	// lexical order, which is the canonical order we want.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(value); err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to format %s: %w", filename, err))
+		return nil, diags
+	}
+
+	return buf.Bytes(), diags
+}
+
+// formatMarkdown formats the OpenTofu code in the fenced code blocks of a
+// Markdown document, leaving the rest of the document unchanged.
+//
+// Code blocks that are not valid native syntax are left as they are, since
+// documentation often includes partial examples that aren't meant to be
+// complete configurations.
+func (c *FmtCommand) formatMarkdown(src []byte, filename string) []byte {
+	lines := strings.SplitAfter(string(src), "\n")
+
+	var out strings.Builder
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		out.WriteString(line)
+
+		indent, fence, lang, ok := markdownFenceOpen(line)
+		if !ok {
+			continue
+		}
+
+		// Find the closing fence, which must use the same fence character
+		// and be at least as long as the opening fence.
+		end := -1
+		for j := i + 1; j < len(lines); j++ {
+			if markdownFenceClose(lines[j], fence) {
+				end = j
+				break
+			}
+		}
+		if end == -1 {
+			// An unclosed code block extends to the end of the document,
+			// so there's nothing more for us to format.
+			for _, rest := range lines[i+1:] {
+				out.WriteString(rest)
+			}
+			break
+		}
+
+		body := lines[i+1 : end]
+		if fmtMarkdownLangs[lang] {
+			formatted := c.formatMarkdownCodeBlock(body, indent, fmt.Sprintf("%s:%d", filename, i+2))
+			out.WriteString(formatted)
+		} else {
+			for _, bodyLine := range body {
+				out.WriteString(bodyLine)
+			}
+		}
+		out.WriteString(lines[end])
+		i = end
+	}
+
+	return []byte(out.String())
+}
+
+// formatMarkdownCodeBlock formats the lines of a single fenced code block,
+// whose lines are all indented by the given prefix, returning the
+// original lines if they are not valid native syntax.
+func (c *FmtCommand) formatMarkdownCodeBlock(lines []string, indent string, name string) string {
+	original := strings.Join(lines, "")
+
+	var src strings.Builder
+	for _, line := range lines {
+		src.WriteString(strings.TrimPrefix(line, indent))
+	}
+
+	_, syntaxDiags := hclsyntax.ParseConfig([]byte(src.String()), name, hcl.Pos{Line: 1, Column: 1})
+	if syntaxDiags.HasErrors() {
+		log.Printf("[TRACE] tofu fmt: Skipping invalid code block at %s", name)
+		return original
+	}
+
+	result := c.formatSourceCode([]byte(src.String()), name)
+
+	var out strings.Builder
+	for _, line := range strings.SplitAfter(string(result), "\n") {
+		if line == "" {
+			continue
+		}
+		if strings.TrimSpace(line) != "" {
+			out.WriteString(indent)
+		}
+		out.WriteString(line)
+	}
+	return out.String()
+}
+
+// markdownFenceOpen determines whether the given line opens a fenced code
+// block, returning the indentation of the fence, the fence itself, and the
+// language named in its info string.
+func markdownFenceOpen(line string) (indent, fence, lang string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		// Four or more spaces is an indented code block, not a fence.
+		return "", "", "", false
+	}
+	indent = line[:len(line)-len(trimmed)]
+
+	var char byte
+	switch {
+	case strings.HasPrefix(trimmed, "```"):
+		char = '`'
+	case strings.HasPrefix(trimmed, "~~~"):
+		char = '~'
+	default:
+		return "", "", "", false
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == char {
+		n++
+	}
+	fence = trimmed[:n]
+
+	info := strings.TrimSpace(trimmed[n:])
+	if fields := strings.Fields(info); len(fields) > 0 {
+		lang = strings.ToLower(fields[0])
+	}
+	return indent, fence, lang, true
+}
+
+// markdownFenceClose determines whether the given line closes a fenced
+// code block that was opened with the given fence.
+func markdownFenceClose(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	if len(trimmed) < len(fence) {
+		return false
+	}
+	return strings.Trim(trimmed, fence[:1]) == ""
+}
+
+func hasAnySuffix(s string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
 // formatSourceCode is the formatting logic itself, applied to each file that
 // is selected (directly or indirectly) on the command line.
 func (c *FmtCommand) formatSourceCode(src []byte, filename string) []byte {
@@ -297,12 +538,12 @@ func (c *FmtCommand) formatSourceCode(src []byte, filename string) []byte {
 		return src
 	}
 
-	c.formatBody(f.Body(), nil)
+	c.formatBody(f.Body(), nil, strings.HasSuffix(filename, ".tftest.hcl"))
 
 	return f.Bytes()
 }
 
-func (c *FmtCommand) formatBody(body *hclwrite.Body, inBlocks []string) {
+func (c *FmtCommand) formatBody(body *hclwrite.Body, inBlocks []string, testFile bool) {
 	attrs := body.Attributes()
 	for name, attr := range attrs {
 		if len(inBlocks) == 1 && inBlocks[0] == "variable" && name == "type" {
@@ -310,6 +551,11 @@ func (c *FmtCommand) formatBody(body *hclwrite.Body, inBlocks []string) {
 			body.SetAttributeRaw(name, cleanedExprTokens)
 			continue
 		}
+		if testFile && fmtTestKeywordAttrs[strings.Join(append(inBlocks, name), ".")] {
+			cleanedExprTokens := c.formatKeywordExpr(attr.Expr().BuildTokens(nil))
+			body.SetAttributeRaw(name, cleanedExprTokens)
+			continue
+		}
 		cleanedExprTokens := c.formatValueExpr(attr.Expr().BuildTokens(nil))
 		body.SetAttributeRaw(name, cleanedExprTokens)
 	}
@@ -322,7 +568,7 @@ func (c *FmtCommand) formatBody(body *hclwrite.Body, inBlocks []string) {
 		block.SetLabels(block.Labels())
 
 		inBlocks := append(inBlocks, block.Type())
-		c.formatBody(block.Body(), inBlocks)
+		c.formatBody(block.Body(), inBlocks, testFile)
 	}
 }
 
@@ -528,6 +774,32 @@ func (c *FmtCommand) formatTypeExpr(tokens hclwrite.Tokens) hclwrite.Tokens {
 	}
 }
 
+// formatKeywordExpr unwraps a keyword that is written as a quoted string,
+// like "apply", into a bare keyword.
+func (c *FmtCommand) formatKeywordExpr(tokens hclwrite.Tokens) hclwrite.Tokens {
+	if len(tokens) != 3 {
+		return tokens
+	}
+	oQuote := tokens[0]
+	strTok := tokens[1]
+	cQuote := tokens[2]
+	if oQuote.Type != hclsyntax.TokenOQuote || strTok.Type != hclsyntax.TokenQuotedLit || cQuote.Type != hclsyntax.TokenCQuote {
+		// Not a quoted string sequence, then.
+		return tokens
+	}
+	if !hclsyntax.ValidIdentifier(string(strTok.Bytes)) {
+		// The string can't be written as a keyword, so we'll leave it for
+		// the user to fix.
+		return tokens
+	}
+	return hclwrite.Tokens{
+		{
+			Type:  hclsyntax.TokenIdent,
+			Bytes: strTok.Bytes,
+		},
+	}
+}
+
 func (c *FmtCommand) trimNewlines(tokens hclwrite.Tokens) hclwrite.Tokens {
 	if len(tokens) == 0 {
 		return nil
@@ -551,9 +823,10 @@ func (c *FmtCommand) Help() string {
 Usage: tofu [global options] fmt [options] [target...]
 
   Rewrites all OpenTofu configuration files to a canonical format. All
-  configuration files (.tf), variables files (.tfvars), and testing files 
-  (.tftest.hcl) are updated. JSON files (.tf.json, .tfvars.json, or 
-  .tftest.json) are not modified.
+  configuration files (.tf), variables files (.tfvars), and testing files
+  (.tftest.hcl) are updated. JSON variables files (.tfvars.json) are updated
+  only with -tfvars-json, and other JSON files (.tf.json or .tftest.json) are
+  not modified.
 
   By default, fmt scans the current directory for configuration files. If you
   provide a directory for the target argument, then fmt will scan that
@@ -561,8 +834,7 @@ Usage: tofu [global options] fmt [options] [target...]
   file. If you provide a single dash ("-"), then fmt will read from standard
   input (STDIN).
 
-  The content must be in the OpenTofu language native syntax; JSON is not
-  supported.
+  The content of STDIN must be in the OpenTofu language native syntax.
 
 Options:
 
@@ -575,7 +847,15 @@ Options:
   -diff          Display diffs of formatting changes
 
   -check         Check if the input is formatted. Exit status will be 0 if all
-                 input is properly formatted and non-zero otherwise.
+                 input is properly formatted and non-zero otherwise. Use
+                 with -diff to also show the changes that are needed.
+
+  -markdown      Also format the OpenTofu code in fenced code blocks marked
+                 as hcl, opentofu, terraform or tf in Markdown (.md and .mdx)
+                 files.
+
+  -tfvars-json   Also format JSON variables files (.tfvars.json), sorting
+                 their object properties by name.
 
   -no-color      If specified, output won't contain any color.
 
@@ -436,6 +436,33 @@ func TestFmt_check(t *testing.T) {
 	}
 }
 
+func TestFmt_checkNoList(t *testing.T) {
+	tempDir := fmtFixtureWriteDir(t)
+
+	ui := cli.NewMockUi()
+	c := &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{
+		"-list=false",
+		"-check",
+		"-diff",
+		tempDir,
+	}
+	if code := c.Run(args); code != 3 {
+		t.Fatalf("wrong exit code. expected 3, got %d", code)
+	}
+
+	// With -list=false, -check reports only through its exit status.
+	if actual := ui.OutputWriter.String(); actual != "" {
+		t.Fatalf("unexpected output:\n%s", actual)
+	}
+}
+
 func TestFmt_checkStdin(t *testing.T) {
 	input := new(bytes.Buffer)
 	input.Write(fmtFixture.input)
@@ -483,3 +510,176 @@ func fmtFixtureWriteDir(t *testing.T) string {
 
 	return dir
 }
+
+func TestFmt_tfvarsJSON(t *testing.T) {
+	tempDir := testTempDir(t)
+
+	input := `{"region": "us-east-1", "count": 1.50, "tags": {"b": "<2>", "a": "1"}}`
+	want := `{
+  "count": 1.50,
+  "region": "us-east-1",
+  "tags": {
+    "a": "1",
+    "b": "<2>"
+  }
+}
+`
+	path := filepath.Join(tempDir, "terraform.tfvars.json")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("without -tfvars-json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := &FmtCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				Ui:               ui,
+			},
+		}
+		if code := c.Run([]string{tempDir}); code != 0 {
+			t.Fatalf("wrong exit code %d\n%s", code, ui.ErrorWriter.String())
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != input {
+			t.Errorf("file was changed without -tfvars-json:\n%s", got)
+		}
+	})
+
+	t.Run("with -tfvars-json", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := &FmtCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				Ui:               ui,
+			},
+		}
+		if code := c.Run([]string{"-tfvars-json", tempDir}); code != 0 {
+			t.Fatalf("wrong exit code %d\n%s", code, ui.ErrorWriter.String())
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, string(got)); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+}
+
+func TestFmt_tfvarsJSONSyntaxError(t *testing.T) {
+	tempDir := testTempDir(t)
+
+	path := filepath.Join(tempDir, "terraform.tfvars.json")
+	if err := os.WriteFile(path, []byte(`{"region": }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ui := cli.NewMockUi()
+	c := &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+	if code := c.Run([]string{"-tfvars-json", path}); code != 2 {
+		t.Fatalf("wrong exit code. expected 2, got %d", code)
+	}
+}
+
+func TestFmt_markdown(t *testing.T) {
+	tempDir := testTempDir(t)
+
+	input := "# Example\n\n" +
+		"```hcl\nfoo  =  \"${var.bar}\"\n```\n\n" +
+		"```hcl\nthis is { not valid\n```\n\n" +
+		"```shell\nfoo  =  bar\n```\n\n" +
+		"- item\n\n  ~~~~terraform\n  a = 1\n  bb = 2\n  ~~~~\n"
+	want := "# Example\n\n" +
+		"```hcl\nfoo = var.bar\n```\n\n" +
+		"```hcl\nthis is { not valid\n```\n\n" +
+		"```shell\nfoo  =  bar\n```\n\n" +
+		"- item\n\n  ~~~~terraform\n  a  = 1\n  bb = 2\n  ~~~~\n"
+	path := filepath.Join(tempDir, "README.md")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("without -markdown", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := &FmtCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				Ui:               ui,
+			},
+		}
+		if code := c.Run([]string{tempDir}); code != 0 {
+			t.Fatalf("wrong exit code %d\n%s", code, ui.ErrorWriter.String())
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != input {
+			t.Errorf("file was changed without -markdown:\n%s", got)
+		}
+	})
+
+	t.Run("with -markdown", func(t *testing.T) {
+		ui := cli.NewMockUi()
+		c := &FmtCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				Ui:               ui,
+			},
+		}
+		if code := c.Run([]string{"-markdown", tempDir}); code != 0 {
+			t.Fatalf("wrong exit code %d\n%s", code, ui.ErrorWriter.String())
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, string(got)); diff != "" {
+			t.Errorf("wrong result\n%s", diff)
+		}
+	})
+}
+
+func TestFmt_checkDiff(t *testing.T) {
+	tempDir := fmtFixtureWriteDir(t)
+
+	ui := new(cli.MockUi)
+	c := &FmtCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	args := []string{
+		"-check",
+		"-diff",
+		tempDir,
+	}
+	if code := c.Run(args); code != 3 {
+		t.Fatalf("wrong exit code. expected 3, got %d", code)
+	}
+
+	expected := fmt.Sprintf("-%s+%s", fmtFixture.input, fmtFixture.golden)
+	if actual := ui.OutputWriter.String(); !strings.Contains(actual, expected) {
+		t.Fatalf("expected:\n%s\n\nto include: %q", actual, expected)
+	}
+
+	// The -check option must never change the files.
+	got, err := os.ReadFile(filepath.Join(tempDir, fmtFixture.filename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, fmtFixture.input) {
+		t.Fatalf("file was modified by -check:\n%s", got)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/i18n"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 
 	"github.com/mitchellh/colorstring"
@@ -57,13 +58,13 @@ func DiagnosticFromJSON(diag *viewsjson.Diagnostic, color *colorstring.Colorize,
 
 	switch diag.Severity {
 	case viewsjson.DiagnosticSeverityError:
-		buf.WriteString(color.Color("[bold][red]Error: [reset]"))
+		buf.WriteString(color.Color("[bold][red]" + i18n.T("Error: ") + "[reset]"))
 		leftRuleLine = color.Color("[red]│[reset] ")
 		leftRuleStart = color.Color("[red]╷[reset]")
 		leftRuleEnd = color.Color("[red]╵[reset]")
 		leftRuleWidth = 2
 	case viewsjson.DiagnosticSeverityWarning:
-		buf.WriteString(color.Color("[bold][yellow]Warning: [reset]"))
+		buf.WriteString(color.Color("[bold][yellow]" + i18n.T("Warning: ") + "[reset]"))
 		leftRuleLine = color.Color("[yellow]│[reset] ")
 		leftRuleStart = color.Color("[yellow]╷[reset]")
 		leftRuleEnd = color.Color("[yellow]╵[reset]")
@@ -77,14 +78,14 @@ func DiagnosticFromJSON(diag *viewsjson.Diagnostic, color *colorstring.Colorize,
 	// We don't wrap the summary, since we expect it to be terse, and since
 	// this is where we put the text of a native Go error it may not always
 	// be pure text that lends itself well to word-wrapping.
-	fmt.Fprintf(&buf, color.Color("[bold]%s[reset]\n\n"), diag.Summary)
+	fmt.Fprintf(&buf, color.Color("[bold]%s[reset]\n\n"), i18n.T(diag.Summary))
 
 	appendSourceSnippets(&buf, diag, color)
 
-	if diag.Detail != "" {
+	if detail := i18n.T(diag.Detail); detail != "" {
 		paraWidth := width - leftRuleWidth - 1 // leave room for the left rule
 		if paraWidth > 0 {
-			lines := strings.Split(diag.Detail, "\n")
+			lines := strings.Split(detail, "\n")
 			for _, line := range lines {
 				if !strings.HasPrefix(line, " ") {
 					line = wordwrap.WrapString(line, uint(paraWidth))
@@ -92,7 +93,7 @@ func DiagnosticFromJSON(diag *viewsjson.Diagnostic, color *colorstring.Colorize,
 				fmt.Fprintf(&buf, "%s\n", line)
 			}
 		} else {
-			fmt.Fprintf(&buf, "%s\n", diag.Detail)
+			fmt.Fprintf(&buf, "%s\n", detail)
 		}
 	}
 
@@ -141,9 +142,9 @@ func DiagnosticPlainFromJSON(diag *viewsjson.Diagnostic, width int) string {
 
 	switch diag.Severity {
 	case viewsjson.DiagnosticSeverityError:
-		buf.WriteString("\nError: ")
+		buf.WriteString("\n" + i18n.T("Error: "))
 	case viewsjson.DiagnosticSeverityWarning:
-		buf.WriteString("\nWarning: ")
+		buf.WriteString("\n" + i18n.T("Warning: "))
 	default:
 		buf.WriteString("\n")
 	}
@@ -151,13 +152,13 @@ func DiagnosticPlainFromJSON(diag *viewsjson.Diagnostic, width int) string {
 	// We don't wrap the summary, since we expect it to be terse, and since
 	// this is where we put the text of a native Go error it may not always
 	// be pure text that lends itself well to word-wrapping.
-	fmt.Fprintf(&buf, "%s\n\n", diag.Summary)
+	fmt.Fprintf(&buf, "%s\n\n", i18n.T(diag.Summary))
 
 	appendSourceSnippets(&buf, diag, disabledColorize)
 
-	if diag.Detail != "" {
+	if detail := i18n.T(diag.Detail); detail != "" {
 		if width > 1 {
-			lines := strings.Split(diag.Detail, "\n")
+			lines := strings.Split(detail, "\n")
 			for _, line := range lines {
 				if !strings.HasPrefix(line, " ") {
 					line = wordwrap.WrapString(line, uint(width-1))
@@ -165,7 +166,7 @@ func DiagnosticPlainFromJSON(diag *viewsjson.Diagnostic, width int) string {
 				fmt.Fprintf(&buf, "%s\n", line)
 			}
 		} else {
-			fmt.Fprintf(&buf, "%s\n", diag.Detail)
+			fmt.Fprintf(&buf, "%s\n", detail)
 		}
 	}
 
@@ -184,10 +185,10 @@ func DiagnosticPlainFromJSON(diag *viewsjson.Diagnostic, width int) string {
 // be nonsense.
 func DiagnosticWarningsCompact(diags tfdiags.Diagnostics, color *colorstring.Colorize) string {
 	var b strings.Builder
-	b.WriteString(color.Color("[bold][yellow]Warnings:[reset]\n\n"))
+	b.WriteString(color.Color("[bold][yellow]" + i18n.T("Warnings:") + "[reset]\n\n"))
 	for _, diag := range diags {
 		sources := tfdiags.WarningGroupSourceRanges(diag)
-		b.WriteString(fmt.Sprintf("- %s\n", diag.Description().Summary))
+		b.WriteString(fmt.Sprintf("- %s\n", i18n.T(diag.Description().Summary)))
 		if len(sources) > 0 {
 			mainSource := sources[0]
 			if mainSource.Subject != nil {
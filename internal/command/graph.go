@@ -28,25 +28,43 @@ func (c *GraphCommand) Run(args []string) int {
 	var diags tfdiags.Diagnostics
 
 	var drawCycles bool
+	var graphCycles bool
 	var graphTypeStr string
 	var moduleDepth int
 	var verbose bool
 	var planPath string
+	var format string
+	var resourcesOnly bool
 
 	args = c.Meta.process(args)
 	cmdFlags := c.Meta.defaultFlagSet("graph")
 	c.Meta.varFlagSet(cmdFlags)
 	cmdFlags.BoolVar(&drawCycles, "draw-cycles", false, "draw-cycles")
+	cmdFlags.BoolVar(&graphCycles, "graph-cycles", false, "graph-cycles")
 	cmdFlags.StringVar(&graphTypeStr, "type", "", "type")
 	cmdFlags.IntVar(&moduleDepth, "module-depth", -1, "module-depth")
 	cmdFlags.BoolVar(&verbose, "verbose", false, "verbose")
 	cmdFlags.StringVar(&planPath, "plan", "", "plan")
+	cmdFlags.StringVar(&format, "format", "dot", "format")
+	cmdFlags.BoolVar(&resourcesOnly, "resources-only", false, "resources-only")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
 		return 1
 	}
 
+	switch format {
+	case "dot", "mermaid", "d2", "json":
+		// valid
+	default:
+		c.Ui.Error(fmt.Sprintf("Unsupported graph format %q. The -format=... argument must be either \"dot\", \"mermaid\", \"d2\", or \"json\".", format))
+		return 1
+	}
+	if graphCycles && format != "dot" {
+		c.Ui.Error("The -graph-cycles option is only supported for the dot format.")
+		return 1
+	}
+
 	configPath, err := modulePath(cmdFlags.Args())
 	if err != nil {
 		c.Ui.Error(err.Error())
@@ -176,17 +194,45 @@ func (c *GraphCommand) Run(args []string) int {
 			`The -type=... argument must be either "plan", "plan-refresh-only", "plan-destroy", or "apply".`,
 		))
 	}
+	if graphCycles {
+		// Cycles are reported as errors, but they're what we've been asked
+		// to render here, so we only fail for other problems.
+		if cycles := tofu.GraphCycles(graphDiags); cycles != nil {
+			g, graphDiags = cycles, nil
+		} else if !graphDiags.HasErrors() {
+			// The graph is valid, so there are no cycles to render.
+			g = &tofu.Graph{}
+		}
+	}
 	diags = diags.Append(graphDiags)
 	if graphDiags.HasErrors() {
 		c.showDiagnostics(diags)
 		return 1
 	}
 
-	graphStr, err := tofu.GraphDot(g, &dag.DotOpts{
-		DrawCycles: drawCycles,
-		MaxDepth:   moduleDepth,
-		Verbose:    verbose,
-	})
+	exportOpts := &tofu.GraphExportOpts{
+		Verbose:       verbose,
+		ResourcesOnly: resourcesOnly,
+	}
+	if graphTypeStr == "apply" && lr.Plan != nil {
+		exportOpts.Changes = lr.Plan.Changes
+	}
+
+	var graphStr string
+	switch format {
+	case "mermaid":
+		graphStr, err = tofu.GraphMermaid(g, exportOpts)
+	case "d2":
+		graphStr, err = tofu.GraphD2(g, exportOpts)
+	case "json":
+		graphStr, err = tofu.GraphJSON(g, exportOpts)
+	default:
+		graphStr, err = tofu.GraphDot(g, &dag.DotOpts{
+			DrawCycles: drawCycles,
+			MaxDepth:   moduleDepth,
+			Verbose:    verbose,
+		})
+	}
 	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Error converting graph: %s", err))
 		return 1
@@ -212,17 +258,35 @@ Usage: tofu [global options] graph [options]
   Produces a representation of the dependency graph between different
   objects in the current configuration and state.
 
-  The graph is presented in the DOT language. The typical program that can
-  read this format is GraphViz, but many web services are also available
-  to read this format.
+  By default the graph is presented in the DOT language. The typical program
+  that can read this format is GraphViz, but many web services are also
+  available to read this format. Use the -format=... option to instead
+  produce a Mermaid flowchart, a D2 diagram, or JSON.
 
 Options:
 
   -plan=tfplan     Render graph using the specified plan file instead of the
                    configuration in the current directory.
 
+  -format=dot      Format of the output. Can be: dot, mermaid, d2, or json.
+                   The mermaid, d2, and json formats group the objects of
+                   each module together and, for an apply graph rendered
+                   from a plan, mark each resource instance with its
+                   planned action.
+
+  -resources-only  Include only resources in the mermaid, d2, or json
+                   output, connecting each resource directly to the
+                   resources it depends on.
+
   -draw-cycles     Highlight any cycles in the graph with colored edges.
-                   This helps when diagnosing cycle errors.
+                   This helps when diagnosing cycle errors. Only
+                   supported for the dot format.
+
+  -graph-cycles    Render only the cycles that make the graph invalid, with
+                   the objects in each of them and the dependencies
+                   between those objects, instead of failing with an
+                   error. The result is empty if there are no cycles.
+                   Only supported for the dot format.
 
   -type=plan       Type of graph to output. Can be: plan, plan-refresh-only,
                    plan-destroy, or apply. By default OpenTofu chooses
@@ -42,6 +42,133 @@ func TestGraph(t *testing.T) {
 	}
 }
 
+func TestGraph_formats(t *testing.T) {
+	tests := map[string]string{
+		"mermaid": `n0["test_instance.foo"]`,
+		"d2":      `n0: "test_instance.foo"`,
+		"json":    `"label": "test_instance.foo"`,
+	}
+
+	for format, want := range tests {
+		t.Run(format, func(t *testing.T) {
+			td := t.TempDir()
+			testCopyDir(t, testFixturePath("graph"), td)
+			defer testChdir(t, td)()
+
+			ui := new(cli.MockUi)
+			c := &GraphCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+					Ui:               ui,
+				},
+			}
+
+			args := []string{"-format=" + format, "-resources-only"}
+			if code := c.Run(args); code != 0 {
+				t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+			}
+
+			output := ui.OutputWriter.String()
+			if !strings.Contains(output, want) {
+				t.Fatalf("output does not contain %s\n%s", want, output)
+			}
+			if strings.Contains(output, "provider") {
+				t.Fatalf("output contains provider node despite -resources-only\n%s", output)
+			}
+		})
+	}
+}
+
+func TestGraph_unsupportedFormat(t *testing.T) {
+	ui := new(cli.MockUi)
+	c := &GraphCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+
+	if code := c.Run([]string{"-format=svg"}); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), `Unsupported graph format "svg"`; !strings.Contains(got, want) {
+		t.Fatalf("error output does not contain %q\n%s", want, got)
+	}
+}
+
+func TestGraph_graphCycles(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("graph-cycles"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	c := &GraphCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+
+	// Without -graph-cycles the cycle is an error, described with the
+	// references that create it.
+	if code := c.Run([]string{}); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\n%s", code, ui.OutputWriter.String())
+	}
+	for _, want := range []string{
+		"Cycle: ",
+		"refers to test_instance.bar.id at ",
+		"main.tf:2,9-26",
+		"refers to test_instance.foo.id at ",
+		"main.tf:6,9-26",
+	} {
+		if got := ui.ErrorWriter.String(); !strings.Contains(got, want) {
+			t.Fatalf("error output does not contain %q\n%s", want, got)
+		}
+	}
+
+	ui = new(cli.MockUi)
+	c = &GraphCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+	if code := c.Run([]string{"-graph-cycles"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	if !strings.Contains(output, `"[root] test_instance.foo (expand)" -> "[root] test_instance.bar (expand)"`) {
+		t.Fatalf("output doesn't include the cycle: %s", output)
+	}
+	for _, unwanted := range []string{"test_instance.baz", "provider"} {
+		if strings.Contains(output, unwanted) {
+			t.Fatalf("output includes %s, which isn't part of the cycle: %s", unwanted, output)
+		}
+	}
+}
+
+func TestGraph_graphCyclesNone(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("graph"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	c := &GraphCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               ui,
+		},
+	}
+
+	if code := c.Run([]string{"-graph-cycles"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if output := ui.OutputWriter.String(); strings.Contains(output, "test_instance.foo") {
+		t.Fatalf("output includes objects despite there being no cycles: %s", output)
+	}
+}
+
 func TestGraph_multipleArgs(t *testing.T) {
 	ui := new(cli.MockUi)
 	c := &GraphCommand{
@@ -10,6 +10,9 @@ import (
 
 	version "github.com/hashicorp/go-version"
 	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/views"
+	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
 	"github.com/opentofu/opentofu/internal/initwd"
 )
 
@@ -17,6 +20,9 @@ type uiModuleInstallHooks struct {
 	initwd.ModuleInstallHooksImpl
 	Ui             cli.Ui
 	ShowLocalPaths bool
+
+	// Downloads, if set, reports the progress of module package downloads.
+	Downloads views.DownloadProgress
 }
 
 var _ initwd.ModuleInstallHooks = uiModuleInstallHooks{}
@@ -29,10 +35,56 @@ func (h uiModuleInstallHooks) Download(modulePath, packageAddr string, v *versio
 	}
 }
 
+func (h uiModuleInstallHooks) DownloadProgress(modulePath string, received, total int64) {
+	if h.Downloads != nil {
+		h.Downloads.Progress(moduleDownloadPackage(modulePath), received, total)
+	}
+}
+
 func (h uiModuleInstallHooks) Install(modulePath string, v *version.Version, localDir string) {
+	if h.Downloads != nil {
+		h.Downloads.Done()
+	}
 	if h.ShowLocalPaths {
 		h.Ui.Info(fmt.Sprintf("- %s in %s", modulePath, localDir))
 	} else {
 		h.Ui.Info(fmt.Sprintf("- %s", modulePath))
 	}
 }
+
+// jsonModuleInstallHooks reports the progress of module installation as
+// machine-readable init events.
+type jsonModuleInstallHooks struct {
+	initwd.ModuleInstallHooksImpl
+	View      *views.JSONView
+	Downloads views.DownloadProgress
+}
+
+var _ initwd.ModuleInstallHooks = jsonModuleInstallHooks{}
+
+func (h jsonModuleInstallHooks) Download(modulePath, packageAddr string, v *version.Version) {
+	h.View.InitEvent(viewsjson.NewInitModuleDownload(modulePath, packageAddr, moduleVersionString(v)))
+}
+
+func (h jsonModuleInstallHooks) DownloadProgress(modulePath string, received, total int64) {
+	h.Downloads.Progress(moduleDownloadPackage(modulePath), received, total)
+}
+
+func (h jsonModuleInstallHooks) Install(modulePath string, v *version.Version, localDir string) {
+	h.Downloads.Done()
+	h.View.InitEvent(viewsjson.NewInitModuleInstalled(modulePath, moduleVersionString(v), localDir))
+}
+
+func moduleDownloadPackage(modulePath string) views.DownloadPackage {
+	return views.DownloadPackage{
+		Kind: viewsjson.InitDownloadModule,
+		Name: modulePath,
+	}
+}
+
+func moduleVersionString(v *version.Version) string {
+	if v == nil {
+		return ""
+	}
+	return v.String()
+}
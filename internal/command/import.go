@@ -29,7 +29,7 @@ type ImportCommand struct {
 	Meta
 }
 
-func (c *ImportCommand) Run(args []string) int {
+func (c *ImportCommand) Run(args []string) (status int) {
 	// Get the pwd since its our default -config flag value
 	pwd, err := os.Getwd()
 	if err != nil {
@@ -182,6 +182,9 @@ func (c *ImportCommand) Run(args []string) int {
 		return 1
 	}
 
+	audit := c.auditBeginBackend("import", b)
+	defer audit.finish(&status)
+
 	// We require a backend.Local to build a context.
 	// This isn't necessarily a "local.Local" backend, which provides local
 	// operations, however that is the only current implementation. A
@@ -0,0 +1,520 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/genconfig"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+	"github.com/opentofu/opentofu/version"
+)
+
+// DefaultDiscoverOutFilename is the file that "tofu import discover" writes
+// the generated configuration to by default.
+const DefaultDiscoverOutFilename = "discovered.tf"
+
+// ImportDiscoverCommand is a cli.Command implementation that asks providers
+// for the existing remote objects of some resource types, and generates
+// import blocks and configuration for them.
+type ImportDiscoverCommand struct {
+	Meta
+}
+
+func (c *ImportDiscoverCommand) Run(args []string) int {
+	var outPath string
+	filters := FlagStringKV{}
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.extendedFlagSet("import discover")
+	cmdFlags.StringVar(&c.Meta.statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.StringVar(&outPath, "out", DefaultDiscoverOutFilename, "path")
+	cmdFlags.Var(&filters, "filter", "filter")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	typeNames := cmdFlags.Args()
+	if len(typeNames) == 0 {
+		c.Ui.Error("The import discover command expects at least one resource type.")
+		cmdFlags.Usage()
+		return 1
+	}
+	for _, typeName := range typeNames {
+		if !hclsyntax.ValidIdentifier(typeName) {
+			c.Ui.Error(fmt.Sprintf("Invalid resource type %q.", typeName))
+			return 1
+		}
+	}
+
+	var diags tfdiags.Diagnostics
+	diags = diags.Append(genconfig.ValidateTargetFile(outPath))
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	configPath := c.Meta.normalizePath(".")
+
+	// Check for user-supplied plugin path
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading plugin path: %s", err))
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	backendConfig, backendDiags := c.loadBackendConfig(configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// We require a local backend
+	local, ok := b.(backend.Local)
+	if !ok {
+		c.showDiagnostics(diags) // in case of any warnings in here
+		c.Ui.Error(ErrUnsupportedLocalOp)
+		return 1
+	}
+
+	// This command only reads the state
+	c.ignoreRemoteVersionConflict(b)
+
+	// Build the operation
+	opReq := c.Operation(b, arguments.ViewHuman, enc)
+	opReq.ConfigDir = configPath
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(err)
+		c.showDiagnostics(diags)
+		return 1
+	}
+	{
+		// Setup required variables/call for operation (usually done in Meta.RunOperation)
+		var moreDiags, callDiags tfdiags.Diagnostics
+		opReq.Variables, moreDiags = c.collectVariableValues()
+		opReq.RootCall, callDiags = c.rootModuleCall(opReq.ConfigDir)
+		diags = diags.Append(moreDiags).Append(callDiags)
+		if moreDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	// Get the context
+	lr, _, ctxDiags := local.LocalRun(opReq)
+	diags = diags.Append(ctxDiags)
+	if ctxDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Successfully creating the context can result in a lock, so ensure we release it
+	defer func() {
+		diags := opReq.StateLocker.Unlock()
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+		}
+	}()
+
+	schemas, moreDiags := lr.Core.Schemas(lr.Config, lr.InputState)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// The provider configurations are evaluated in the scope of the root
+	// module, so they can refer to variables and local values.
+	evalOpts := &tofu.EvalOpts{}
+	if lr.PlanOpts != nil {
+		evalOpts.SetVariables = lr.PlanOpts.SetVariables
+	}
+	scope, moreDiags := lr.Core.Eval(lr.Config, lr.InputState, addrs.RootModuleInstance, evalOpts)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	opts, err := c.contextOpts()
+	if err != nil {
+		diags = diags.Append(err)
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	d := &discoverer{
+		config:    lr.Config,
+		state:     lr.InputState,
+		schemas:   schemas,
+		scope:     scope,
+		factories: opts.Providers,
+		instances: make(map[addrs.Provider]providers.Interface),
+		names:     make(map[string]map[string]bool),
+	}
+	defer d.close()
+
+	var changes []genconfig.Change
+	for _, typeName := range typeNames {
+		found, moreDiags := d.discover(typeName, filters)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		c.Ui.Output(fmt.Sprintf("Discovered %d new %s objects.", len(found), typeName))
+		changes = append(changes, found...)
+	}
+
+	var writer io.Writer
+	for _, change := range changes {
+		writer, _, moreDiags = change.MaybeWriteConfig(writer, outPath)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+	if closer, ok := writer.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			diags = diags.Append(fmt.Errorf("Failed to write %s: %w", outPath, err))
+		}
+	}
+
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	if len(changes) > 0 {
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+			"\n[reset][green]Wrote import blocks and configuration for %d objects to %s.[reset]\n"+
+				"Review the generated configuration, then run \"tofu plan\" to plan importing them.",
+			len(changes), outPath,
+		)))
+	}
+	return 0
+}
+
+// discoverer lists the remote objects of resource types using the providers
+// of the configuration, and generates import blocks and configuration for
+// them.
+type discoverer struct {
+	config  *configs.Config
+	state   *states.State
+	schemas *tofu.Schemas
+	scope   *lang.Scope
+
+	factories map[addrs.Provider]providers.Factory
+
+	// instances are the providers that have been configured so far.
+	instances map[addrs.Provider]providers.Interface
+
+	// names are the resource names in use for each resource type.
+	names map[string]map[string]bool
+}
+
+// discover returns the generated configuration for each remote object of
+// the given resource type that matches the filters, other than those that
+// are already in the state.
+func (d *discoverer) discover(typeName string, filters map[string]string) ([]genconfig.Change, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	localName := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: typeName}.ImpliedProvider()
+	provider := d.config.Module.ImpliedProviderForUnqualifiedType(localName)
+	schema, _ := d.schemas.ResourceTypeConfig(provider, addrs.ManagedResourceMode, typeName)
+	if schema == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported resource type",
+			fmt.Sprintf("The provider %s does not support resource type %q, or is not required by the configuration.", provider.ForDisplay(), typeName),
+		))
+		return nil, diags
+	}
+
+	p, moreDiags := d.provider(provider, localName)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+	lister, ok := providers.AsResourceLister(p)
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Provider does not support discovery",
+			fmt.Sprintf("The provider %s can't list the existing objects of resource type %q.", provider.ForDisplay(), typeName),
+		))
+		return nil, diags
+	}
+
+	resp := lister.ListResources(providers.ListResourcesRequest{
+		TypeName: typeName,
+		Filters:  filters,
+	})
+	diags = diags.Append(resp.Diagnostics)
+	if resp.Diagnostics.HasErrors() {
+		return nil, diags
+	}
+
+	// The configuration is generated in the same way as for import blocks
+	// when planning with -generate-config-out.
+	filteredSchema := schema.Filter(
+		configschema.FilterOr(
+			configschema.FilterReadOnlyAttribute,
+			configschema.FilterDeprecatedAttribute,
+			configschema.FilterHelperSchemaIdAttribute,
+		),
+		configschema.FilterDeprecatedBlock,
+	)
+	managed := d.managedIDs(typeName, schema)
+
+	var ret []genconfig.Change
+	for _, obj := range resp.Resources {
+		if obj.ID == "" || managed[obj.ID] {
+			continue
+		}
+		if obj.State != cty.NilVal {
+			if errs := obj.State.Type().TestConformance(schema.ImpliedType()); len(errs) > 0 {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Invalid object from provider",
+					fmt.Sprintf("The provider %s listed a %s object %q that doesn't conform to the resource type schema. This is a bug in the provider, which should be reported in the provider's own issue tracker.", provider.ForDisplay(), typeName, obj.ID),
+				))
+				return nil, diags
+			}
+		}
+
+		addr := addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: typeName,
+			Name: d.name(typeName, obj.Name),
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+		contents, moreDiags := genconfig.GenerateResourceContents(addr, filteredSchema, addrs.LocalProviderConfig{LocalName: localName}, obj.State)
+		diags = diags.Append(moreDiags)
+		if moreDiags.HasErrors() {
+			return nil, diags
+		}
+
+		var buf strings.Builder
+		fmt.Fprintf(&buf, "import {\n  to = %s\n  id = %q\n}\n\n", addr, obj.ID)
+		buf.WriteString(genconfig.WrapResourceContents(addr, contents))
+		ret = append(ret, genconfig.Change{
+			Addr:            addr.String(),
+			ImportID:        obj.ID,
+			GeneratedConfig: buf.String(),
+		})
+	}
+	return ret, diags
+}
+
+// provider returns the given provider, configured with the default
+// configuration for it in the root module, if any.
+func (d *discoverer) provider(provider addrs.Provider, localName string) (providers.Interface, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if p, ok := d.instances[provider]; ok {
+		return p, diags
+	}
+
+	factory, ok := d.factories[provider]
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Provider not installed",
+			fmt.Sprintf("The provider %s is not installed. Add it to the required_providers block of the configuration and run \"tofu init\".", provider.ForDisplay()),
+		))
+		return nil, diags
+	}
+	p, err := factory()
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to start provider %s: %w", provider.ForDisplay(), err))
+		return nil, diags
+	}
+	d.instances[provider] = p
+
+	body := hcl.EmptyBody()
+	if pc, ok := d.config.Module.ProviderConfigs[localName]; ok {
+		body = pc.Config
+	}
+	schema := d.schemas.ProviderConfig(provider)
+	if schema == nil {
+		schema = &configschema.Block{}
+	}
+	configVal, moreDiags := d.scope.EvalBlock(body, schema)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return nil, diags
+	}
+	configVal, _ = configVal.UnmarkDeep()
+
+	resp := p.ConfigureProvider(providers.ConfigureProviderRequest{
+		TerraformVersion: version.String(),
+		Config:           configVal,
+	})
+	diags = diags.Append(resp.Diagnostics.InConfigBody(body, provider.ForDisplay()))
+	return p, diags
+}
+
+// managedIDs returns the IDs of the objects of the given resource type that
+// are already in the state, so that they aren't imported again.
+func (d *discoverer) managedIDs(typeName string, schema *configschema.Block) map[string]bool {
+	ret := make(map[string]bool)
+	if d.state == nil {
+		return ret
+	}
+	ty := schema.ImpliedType()
+	for _, ms := range d.state.Modules {
+		for _, rs := range ms.Resources {
+			if rs.Addr.Resource.Mode != addrs.ManagedResourceMode || rs.Addr.Resource.Type != typeName {
+				continue
+			}
+			for _, is := range rs.Instances {
+				if is.Current == nil {
+					continue
+				}
+				obj, err := is.Current.Decode(ty)
+				if err != nil || !ty.HasAttribute("id") {
+					continue
+				}
+				if id := obj.Value.GetAttr("id"); id.Type() == cty.String && id.IsKnown() && !id.IsNull() {
+					ret[id.AsString()] = true
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// name returns a unique name for a new resource of the given type, based on
+// the suggested name from the provider.
+func (d *discoverer) name(typeName, suggested string) string {
+	used, ok := d.names[typeName]
+	if !ok {
+		used = make(map[string]bool)
+		for _, r := range d.config.Module.ManagedResources {
+			if r.Type == typeName {
+				used[r.Name] = true
+			}
+		}
+		d.names[typeName] = used
+	}
+
+	base := discoverResourceName(suggested)
+	name := base
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	used[name] = true
+	return name
+}
+
+func (d *discoverer) close() {
+	for _, p := range d.instances {
+		p.Close()
+	}
+}
+
+// discoverResourceName turns the given name into a valid resource name, by
+// replacing any characters that aren't allowed with underscores.
+func discoverResourceName(given string) string {
+	var buf strings.Builder
+	for _, r := range strings.ToLower(given) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			buf.WriteRune(r)
+		default:
+			buf.WriteRune('_')
+		}
+	}
+	name := strings.Trim(buf.String(), "_")
+	if name == "" {
+		return "discovered"
+	}
+	if !hclsyntax.ValidIdentifier(name) || name[0] == '-' {
+		name = "r_" + name
+	}
+	return name
+}
+
+func (c *ImportDiscoverCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] import discover [options] TYPE...
+
+  Generate import blocks and configuration for the existing remote objects
+  of the given resource types.
+
+  This asks the provider of each resource type for the objects of that type
+  that exist, and writes an import block and a resource block for each of
+  them that isn't already in the state. Review the generated configuration,
+  then run "tofu plan" to plan importing the objects.
+
+  The providers are configured with the default provider configurations in
+  the root module. Only providers that support listing existing objects can
+  be used with this command.
+
+  This command will not modify your infrastructure or your state.
+
+Options:
+
+  -filter=key=value  Only list the objects that match the given filter, whose
+                     meaning depends on the provider and resource type. This
+                     flag can be set multiple times.
+
+  -out=path          Write the generated configuration to the given file,
+                     which must not already exist. Defaults to
+                     "discovered.tf".
+
+  -state=path        Legacy option for the local backend only. See the local
+                     backend's documentation for more information.
+
+  -var 'foo=bar'     Set a variable in the OpenTofu configuration. This
+                     flag can be set multiple times.
+
+  -var-file=foo      Set variables in the OpenTofu configuration from
+                     a file. If "terraform.tfvars" or any ".auto.tfvars"
+                     files are present, they will be automatically loaded.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *ImportDiscoverCommand) Synopsis() string {
+	return "Generate configuration to import existing infrastructure"
+}
@@ -0,0 +1,173 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// resourceListerProvider is a MockProvider that lists a fixed set of
+// existing remote objects.
+type resourceListerProvider struct {
+	*tofu.MockProvider
+
+	objects []providers.ListedResource
+	request providers.ListResourcesRequest
+}
+
+func (p *resourceListerProvider) ListResources(req providers.ListResourcesRequest) providers.ListResourcesResponse {
+	p.request = req
+	return providers.ListResourcesResponse{Resources: p.objects}
+}
+
+func TestImportDiscover(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "foo",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"managed"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+	})
+	statePath := testStateFile(t, state)
+
+	p := &resourceListerProvider{
+		MockProvider: applyFixtureProvider(),
+		objects: []providers.ListedResource{
+			{
+				ID:   "i-1",
+				Name: "Web Server",
+				State: cty.ObjectVal(map[string]cty.Value{
+					"id":  cty.StringVal("i-1"),
+					"ami": cty.StringVal("ami-123"),
+				}),
+			},
+			{
+				ID:   "managed",
+				Name: "managed",
+			},
+			{
+				ID:   "i-2",
+				Name: "foo",
+			},
+		},
+	}
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &ImportDiscoverCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-state", statePath, "-filter", "env=prod", "test_instance"})
+	done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	want := providers.ListResourcesRequest{
+		TypeName: "test_instance",
+		Filters:  map[string]string{"env": "prod"},
+	}
+	if diff := cmp.Diff(want, p.request); diff != "" {
+		t.Errorf("wrong request\n%s", diff)
+	}
+	if !p.ConfigureProviderCalled {
+		t.Error("provider was not configured")
+	}
+
+	got, err := os.ReadFile(filepath.Join(td, DefaultDiscoverOutFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`# __generated__ by OpenTofu from "i-1"`,
+		"import {\n  to = test_instance.web_server\n  id = \"i-1\"\n}",
+		"resource \"test_instance\" \"web_server\" {\n  ami = \"ami-123\"\n}",
+		"import {\n  to = test_instance.foo_2\n  id = \"i-2\"\n}",
+		"resource \"test_instance\" \"foo_2\" {\n  ami = null # OPTIONAL string\n}",
+	} {
+		if !strings.Contains(string(got), want) {
+			t.Errorf("generated configuration is missing %q\n%s", want, got)
+		}
+	}
+	if strings.Contains(string(got), "managed") {
+		t.Errorf("generated configuration for an object already in the state\n%s", got)
+	}
+}
+
+func TestImportDiscover_unsupported(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &ImportDiscoverCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"test_instance"})
+	done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "Provider does not support discovery"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant: %s", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(td, DefaultDiscoverOutFilename)); !os.IsNotExist(err) {
+		t.Fatalf("configuration was written: %v", err)
+	}
+}
+
+func TestDiscoverResourceName(t *testing.T) {
+	tests := map[string]string{
+		"web":        "web",
+		"Web Server": "web_server",
+		"my-bucket":  "my-bucket",
+		"123abc":     "r_123abc",
+		"  ":         "discovered",
+		"":           "discovered",
+		"a.b/c":      "a_b_c",
+	}
+	for given, want := range tests {
+		if got := discoverResourceName(given); got != want {
+			t.Errorf("discoverResourceName(%q) = %q; want %q", given, got, want)
+		}
+	}
+}
@@ -27,10 +27,13 @@ import (
 	"github.com/opentofu/opentofu/internal/cloud"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
+	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/initwd"
 	"github.com/opentofu/opentofu/internal/providercache"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -43,11 +46,19 @@ import (
 // module and clones it to the working directory.
 type InitCommand struct {
 	Meta
+
+	// jsonView is set when the -json option is used, in which case the
+	// progress of init is reported as typed events rather than as text.
+	jsonView *views.JSONView
+
+	// downloads reports the progress of provider and module downloads. Use
+	// downloadProgress to access it.
+	downloads views.DownloadProgress
 }
 
 func (c *InitCommand) Run(args []string) int {
 	var flagFromModule, flagLockfile, testsDirectory string
-	var flagBackend, flagCloud, flagGet, flagUpgrade bool
+	var flagBackend, flagCloud, flagGet, flagUpgrade, flagInteractiveBackend bool
 	var flagPluginPath FlagStringSlice
 	flagConfigExtra := newRawFlags("-backend-config")
 
@@ -69,6 +80,7 @@ func (c *InitCommand) Run(args []string) int {
 	cmdFlags.BoolVar(&c.Meta.ignoreRemoteVersion, "ignore-remote-version", false, "continue even if remote and local OpenTofu versions are incompatible")
 	cmdFlags.StringVar(&testsDirectory, "test-directory", "tests", "test-directory")
 	cmdFlags.BoolVar(&c.outputInJSON, "json", false, "json")
+	cmdFlags.BoolVar(&flagInteractiveBackend, "interactive-backend", false, "ask for the backend configuration")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		return 1
@@ -78,9 +90,10 @@ func (c *InitCommand) Run(args []string) int {
 		c.Meta.color = false
 		c.Meta.Color = false
 		c.oldUi = c.Ui
+		c.jsonView = views.NewJSONView(c.View)
 		c.Ui = &WrappedUi{
 			cliUi:        c.oldUi,
-			jsonView:     views.NewJSONView(c.View),
+			jsonView:     c.jsonView,
 			outputInJSON: true,
 		}
 	}
@@ -98,6 +111,11 @@ func (c *InitCommand) Run(args []string) int {
 		flagBackend = flagCloud
 	}
 
+	if flagInteractiveBackend && (c.outputInJSON || !flagBackend) {
+		c.Ui.Error("The -interactive-backend option cannot be used with -json or -backend=false")
+		return 1
+	}
+
 	if c.migrateState && c.reconfigure {
 		c.Ui.Error("The -migrate-state and -reconfigure options are mutually-exclusive")
 		return 1
@@ -154,16 +172,14 @@ func (c *InitCommand) Run(args []string) int {
 		)))
 		header = true
 
-		hooks := uiModuleInstallHooks{
-			Ui:             c.Ui,
-			ShowLocalPaths: false, // since they are in a weird location for init
-		}
+		hooks := c.moduleInstallHooks(false) // since they are in a weird location for init
 
 		ctx, span := tracer.Start(ctx, "-from-module=...", trace.WithAttributes(
 			attribute.String("module_source", src),
 		))
 
 		initDirFromModuleAbort, initDirFromModuleDiags := c.initDirFromModule(ctx, path, src, hooks)
+		c.downloadProgress().Done()
 		diags = diags.Append(initDirFromModuleDiags)
 		if initDirFromModuleAbort || initDirFromModuleDiags.HasErrors() {
 			c.showDiagnostics(diags)
@@ -189,6 +205,19 @@ func (c *InitCommand) Run(args []string) int {
 		return 0
 	}
 
+	if flagInteractiveBackend {
+		configFile, wizardDiags := c.backendWizard(path)
+		diags = diags.Append(wizardDiags)
+		if wizardDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		if configFile != "" {
+			_ = flagConfigExtra.Set(configFile)
+		}
+		header = true
+	}
+
 	// Load just the root module to begin backend and module initialization
 	rootModEarly, earlyConfDiags := c.loadSingleModuleWithTests(path, testsDirectory)
 
@@ -230,6 +259,11 @@ func (c *InitCommand) Run(args []string) int {
 	}
 	if backendOutput {
 		header = true
+		if backDiags.HasErrors() {
+			c.initEvent(viewsjson.NewInitStepErrored(viewsjson.InitStepBackend))
+		} else {
+			c.initEvent(viewsjson.NewInitStepComplete(viewsjson.InitStepBackend))
+		}
 	}
 
 	var state *states.State
@@ -262,11 +296,15 @@ func (c *InitCommand) Run(args []string) int {
 		modsOutput, modsAbort, modsDiags := c.getModules(ctx, path, testsDirectory, rootModEarly, flagUpgrade)
 		diags = diags.Append(modsDiags)
 		if modsAbort || modsDiags.HasErrors() {
+			if modsOutput {
+				c.initEvent(viewsjson.NewInitStepErrored(viewsjson.InitStepModules))
+			}
 			c.showDiagnostics(diags)
 			return 1
 		}
 		if modsOutput {
 			header = true
+			c.initEvent(viewsjson.NewInitStepComplete(viewsjson.InitStepModules))
 		}
 	}
 
@@ -343,11 +381,13 @@ func (c *InitCommand) Run(args []string) int {
 	providersOutput, providersAbort, providerDiags := c.getProviders(ctx, config, state, flagUpgrade, flagPluginPath, flagLockfile)
 	diags = diags.Append(providerDiags)
 	if providersAbort || providerDiags.HasErrors() {
+		c.initEvent(viewsjson.NewInitStepErrored(viewsjson.InitStepProviders))
 		c.showDiagnostics(diags)
 		return 1
 	}
 	if providersOutput {
 		header = true
+		c.initEvent(viewsjson.NewInitStepComplete(viewsjson.InitStepProviders))
 	}
 
 	// If we outputted information, then we need to output a newline
@@ -384,7 +424,7 @@ func (c *InitCommand) Run(args []string) int {
 func (c *InitCommand) getModules(ctx context.Context, path, testsDir string, earlyRoot *configs.Module, upgrade bool) (output bool, abort bool, diags tfdiags.Diagnostics) {
 	testModules := false // We can also have modules buried in test files.
 	for _, file := range earlyRoot.Tests {
-		for _, run := range file.Runs {
+		for _, run := range file.AllRuns() {
 			if run.Module != nil {
 				testModules = true
 			}
@@ -401,18 +441,18 @@ func (c *InitCommand) getModules(ctx context.Context, path, testsDir string, ear
 	))
 	defer span.End()
 
-	if upgrade {
-		c.Ui.Output(c.Colorize().Color("[reset][bold]Upgrading modules..."))
-	} else {
-		c.Ui.Output(c.Colorize().Color("[reset][bold]Initializing modules..."))
+	if !c.initEvent(viewsjson.NewInitStepStart(viewsjson.InitStepModules)) {
+		if upgrade {
+			c.Ui.Output(c.Colorize().Color("[reset][bold]Upgrading modules..."))
+		} else {
+			c.Ui.Output(c.Colorize().Color("[reset][bold]Initializing modules..."))
+		}
 	}
 
-	hooks := uiModuleInstallHooks{
-		Ui:             c.Ui,
-		ShowLocalPaths: true,
-	}
+	hooks := c.moduleInstallHooks(true)
 
 	installAbort, installDiags := c.installModules(ctx, path, testsDir, upgrade, false, hooks)
+	c.downloadProgress().Done()
 	diags = diags.Append(installDiags)
 
 	// At this point, installModules may have generated error diags or been
@@ -440,7 +480,9 @@ func (c *InitCommand) initCloud(ctx context.Context, root *configs.Module, extra
 	_ = ctx // prevent staticcheck from complaining to avoid a maintenence hazard of having the wrong ctx in scope here
 	defer span.End()
 
-	c.Ui.Output(c.Colorize().Color("\n[reset][bold]Initializing cloud backend..."))
+	if !c.initEvent(viewsjson.NewInitStepStart(viewsjson.InitStepBackend)) {
+		c.Ui.Output(c.Colorize().Color("\n[reset][bold]Initializing cloud backend..."))
+	}
 
 	if len(extraConfig.AllItems()) != 0 {
 		diags = diags.Append(tfdiags.Sourceless(
@@ -460,6 +502,9 @@ func (c *InitCommand) initCloud(ctx context.Context, root *configs.Module, extra
 
 	back, backDiags := c.Backend(opts, enc.State())
 	diags = diags.Append(backDiags)
+	if !backDiags.HasErrors() {
+		c.initEvent(viewsjson.NewInitBackend("cloud"))
+	}
 	return back, true, diags
 }
 
@@ -468,12 +513,15 @@ func (c *InitCommand) initBackend(ctx context.Context, root *configs.Module, ext
 	_ = ctx // prevent staticcheck from complaining to avoid a maintenence hazard of having the wrong ctx in scope here
 	defer span.End()
 
-	c.Ui.Output(c.Colorize().Color("\n[reset][bold]Initializing the backend..."))
+	if !c.initEvent(viewsjson.NewInitStepStart(viewsjson.InitStepBackend)) {
+		c.Ui.Output(c.Colorize().Color("\n[reset][bold]Initializing the backend..."))
+	}
 
+	backendType := "local"
 	var backendConfig *configs.Backend
 	var backendConfigOverride hcl.Body
 	if root.Backend != nil {
-		backendType := root.Backend.Type
+		backendType = root.Backend.Type
 		if backendType == "cloud" {
 			diags = diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
@@ -543,6 +591,9 @@ the backend configuration is present and valid.
 
 	back, backDiags := c.Backend(opts, enc.State())
 	diags = diags.Append(backDiags)
+	if !backDiags.HasErrors() {
+		c.initEvent(viewsjson.NewInitBackend(backendType))
+	}
 	return back, true, diags
 }
 
@@ -641,17 +692,27 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 	// are shimming our vt100 output to the legacy console API on Windows.
 	evts := &providercache.InstallerEvents{
 		PendingProviders: func(reqs map[addrs.Provider]getproviders.VersionConstraints) {
+			if c.initEvent(viewsjson.NewInitStepStart(viewsjson.InitStepProviders)) {
+				return
+			}
 			c.Ui.Output(c.Colorize().Color(
 				"\n[reset][bold]Initializing provider plugins...",
 			))
 		},
 		ProviderAlreadyInstalled: func(provider addrs.Provider, selectedVersion getproviders.Version) {
+			if c.initEvent(viewsjson.NewInitProviderInstalled(provider.String(), selectedVersion.String(), viewsjson.InitProviderSourcePrevious, "", "")) {
+				return
+			}
 			c.Ui.Info(fmt.Sprintf("- Using previously-installed %s v%s", provider.ForDisplay(), selectedVersion))
 		},
 		BuiltInProviderAvailable: func(provider addrs.Provider) {
+			if c.initEvent(viewsjson.NewInitProviderInstalled(provider.String(), "", viewsjson.InitProviderSourceBuiltIn, "", "")) {
+				return
+			}
 			c.Ui.Info(fmt.Sprintf("- %s is built in to OpenTofu", provider.ForDisplay()))
 		},
 		BuiltInProviderFailure: func(provider addrs.Provider, err error) {
+			c.initEvent(viewsjson.NewInitProviderErrored(provider.String(), "", err))
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
 				"Invalid dependency on built-in provider",
@@ -659,6 +720,9 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 			))
 		},
 		QueryPackagesBegin: func(provider addrs.Provider, versionConstraints getproviders.VersionConstraints, locked bool) {
+			if c.initEvent(viewsjson.NewInitProviderQuery(provider.String(), getproviders.VersionConstraintsString(versionConstraints), locked)) {
+				return
+			}
 			if locked {
 				c.Ui.Info(fmt.Sprintf("- Reusing previous version of %s from the dependency lock file", provider.ForDisplay()))
 			} else {
@@ -670,12 +734,19 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 			}
 		},
 		LinkFromCacheBegin: func(provider addrs.Provider, version getproviders.Version, cacheRoot string) {
+			if c.initEvent(viewsjson.NewInitProviderInstalled(provider.String(), version.String(), viewsjson.InitProviderSourceCache, "", "")) {
+				return
+			}
 			c.Ui.Info(fmt.Sprintf("- Using %s v%s from the shared cache directory", provider.ForDisplay(), version))
 		},
 		FetchPackageBegin: func(provider addrs.Provider, version getproviders.Version, location getproviders.PackageLocation) {
+			if c.initEvent(viewsjson.NewInitProviderDownload(provider.String(), version.String())) {
+				return
+			}
 			c.Ui.Info(fmt.Sprintf("- Installing %s v%s...", provider.ForDisplay(), version))
 		},
 		QueryPackagesFailure: func(provider addrs.Provider, err error) {
+			c.initEvent(viewsjson.NewInitProviderErrored(provider.String(), "", err))
 			switch errorTy := err.(type) {
 			case getproviders.ErrProviderNotFound:
 				sources := errorTy.Sources
@@ -783,13 +854,19 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 			))
 		},
 		LinkFromCacheFailure: func(provider addrs.Provider, version getproviders.Version, err error) {
+			c.initEvent(viewsjson.NewInitProviderErrored(provider.String(), version.String(), err))
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
 				"Failed to install provider from shared cache",
 				fmt.Sprintf("Error while importing %s v%s from the shared cache directory: %s.", provider.ForDisplay(), version, err),
 			))
 		},
+		FetchPackageProgress: func(provider addrs.Provider, version getproviders.Version, received, total int64) {
+			c.downloadProgress().Progress(c.providerDownloadPackage(provider, version), received, total)
+		},
 		FetchPackageFailure: func(provider addrs.Provider, version getproviders.Version, err error) {
+			c.downloadProgress().Done()
+			c.initEvent(viewsjson.NewInitProviderErrored(provider.String(), version.String(), err))
 			const summaryIncompatible = "Incompatible provider version"
 			switch err := err.(type) {
 			case getproviders.ErrProtocolNotSupported:
@@ -869,10 +946,14 @@ func (c *InitCommand) getProviders(ctx context.Context, config *configs.Config,
 			}
 		},
 		FetchPackageSuccess: func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult) {
+			c.downloadProgress().Done()
 			var keyID string
 			if authResult != nil && authResult.Signed() {
 				keyID = authResult.KeyID
 			}
+			if c.initEvent(viewsjson.NewInitProviderInstalled(provider.String(), version.String(), viewsjson.InitProviderSourceDownload, authResult.String(), keyID)) {
+				return
+			}
 			if keyID != "" {
 				keyID = c.Colorize().Color(fmt.Sprintf(", key ID [reset][bold]%s[reset]", keyID))
 			}
@@ -1028,11 +1109,109 @@ version control system if they represent changes you intended to make.`))
 
 		moreDiags = c.replaceLockedDependencies(newLocks)
 		diags = diags.Append(moreDiags)
+		if !moreDiags.HasErrors() {
+			c.lockUpdatedEvents(previousLocks, newLocks)
+		}
 	}
 
 	return true, false, diags
 }
 
+// initEvent emits the given event if init is producing machine-readable
+// output, and reports whether it did. Callers produce the equivalent
+// human-readable output themselves when it returns false.
+func (c *InitCommand) initEvent(e viewsjson.InitEvent) bool {
+	if c.jsonView == nil {
+		return false
+	}
+	c.jsonView.InitEvent(e)
+	return true
+}
+
+// moduleInstallHooks returns the hooks that report the progress of module
+// installation in the current output format.
+func (c *InitCommand) moduleInstallHooks(showLocalPaths bool) initwd.ModuleInstallHooks {
+	if c.jsonView != nil {
+		return jsonModuleInstallHooks{
+			View:      c.jsonView,
+			Downloads: c.downloadProgress(),
+		}
+	}
+	return uiModuleInstallHooks{
+		Ui:             c.Ui,
+		ShowLocalPaths: showLocalPaths,
+		Downloads:      c.downloadProgress(),
+	}
+}
+
+// downloadProgress returns the view that reports the progress of package
+// downloads in the current output format.
+func (c *InitCommand) downloadProgress() views.DownloadProgress {
+	if c.downloads == nil {
+		switch {
+		case c.jsonView != nil:
+			c.downloads = views.NewDownloadProgressJSON(c.jsonView)
+		case c.View != nil:
+			c.downloads = views.NewDownloadProgressHuman(c.View)
+		default:
+			// A default for unit tests that don't populate Meta fully.
+			c.downloads = nullDownloadProgress{}
+		}
+	}
+	return c.downloads
+}
+
+// nullDownloadProgress is a views.DownloadProgress that reports nothing.
+type nullDownloadProgress struct{}
+
+func (nullDownloadProgress) Progress(views.DownloadPackage, int64, int64) {}
+func (nullDownloadProgress) Done()                                        {}
+
+// providerDownloadPackage identifies a provider package for the download
+// progress view, using the same form of the provider address as the rest of
+// the output.
+func (c *InitCommand) providerDownloadPackage(provider addrs.Provider, version getproviders.Version) views.DownloadPackage {
+	name := provider.ForDisplay()
+	if c.jsonView != nil {
+		name = provider.String()
+	}
+	return views.DownloadPackage{
+		Kind:    viewsjson.InitDownloadProvider,
+		Name:    name,
+		Version: version.String(),
+	}
+}
+
+// lockUpdatedEvents emits an event for each provider whose entry in the
+// dependency lock file differs between the previous and new locks.
+func (c *InitCommand) lockUpdatedEvents(previousLocks, newLocks *depsfile.Locks) {
+	if c.jsonView == nil {
+		return
+	}
+
+	newProviders := newLocks.AllProviders()
+	providers := make([]addrs.Provider, 0, len(newProviders))
+	for provider := range newProviders {
+		providers = append(providers, provider)
+	}
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].LessThan(providers[j])
+	})
+
+	for _, provider := range providers {
+		lock := newProviders[provider]
+		prev := previousLocks.Provider(provider)
+		if prev != nil && prev.Version() == lock.Version() && reflect.DeepEqual(prev.AllHashes(), lock.AllHashes()) {
+			continue
+		}
+		hashes := make([]string, len(lock.AllHashes()))
+		for i, hash := range lock.AllHashes() {
+			hashes[i] = hash.String()
+		}
+		c.initEvent(viewsjson.NewInitLockUpdated(provider.String(), lock.Version().String(), hashes))
+	}
+}
+
 // backendConfigOverrideBody interprets the raw values of -backend-config
 // arguments into a hcl Body that should override the backend settings given
 // in the configuration.
@@ -1144,20 +1323,21 @@ func (c *InitCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *InitCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
-		"-backend":        completePredictBoolean,
-		"-cloud":          completePredictBoolean,
-		"-backend-config": complete.PredictFiles("*.tfvars"), // can also be key=value, but we can't "predict" that
-		"-force-copy":     complete.PredictNothing,
-		"-from-module":    completePredictModuleSource,
-		"-get":            completePredictBoolean,
-		"-input":          completePredictBoolean,
-		"-lock":           completePredictBoolean,
-		"-lock-timeout":   complete.PredictAnything,
-		"-no-color":       complete.PredictNothing,
-		"-plugin-dir":     complete.PredictDirs(""),
-		"-reconfigure":    complete.PredictNothing,
-		"-migrate-state":  complete.PredictNothing,
-		"-upgrade":        completePredictBoolean,
+		"-backend":             completePredictBoolean,
+		"-cloud":               completePredictBoolean,
+		"-backend-config":      complete.PredictFiles("*.tfvars"), // can also be key=value, but we can't "predict" that
+		"-force-copy":          complete.PredictNothing,
+		"-from-module":         completePredictModuleSource,
+		"-get":                 completePredictBoolean,
+		"-input":               completePredictBoolean,
+		"-interactive-backend": complete.PredictNothing,
+		"-lock":                completePredictBoolean,
+		"-lock-timeout":        complete.PredictAnything,
+		"-no-color":            complete.PredictNothing,
+		"-plugin-dir":          complete.PredictDirs(""),
+		"-reconfigure":         complete.PredictNothing,
+		"-migrate-state":       complete.PredictNothing,
+		"-upgrade":             completePredictBoolean,
 	}
 }
 
@@ -1208,6 +1388,14 @@ Options:
                           require interactive prompts and will error if input is
                           disabled.
 
+  -interactive-backend    Ask for the backend type and its required arguments,
+                          validate them against the backend's schema and write
+                          them into the configuration before initializing. If
+                          the configuration already has a backend block, only
+                          the arguments it doesn't set are asked for, and they
+                          are written into a partial configuration file that
+                          is used as a -backend-config file.
+
   -lock=false             Don't hold a state lock during backend migration.
                           This is dangerous if others might concurrently run
                           commands against the same workspace.
@@ -0,0 +1,340 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	backendInit "github.com/opentofu/opentofu/internal/backend/init"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// backendWizardFilename is the file that the backend configuration wizard
+// writes a new backend block into, when the configuration doesn't already
+// have one.
+const backendWizardFilename = "backend.tf"
+
+// backendWizard asks for the type and arguments of the backend to use for the
+// configuration in the given directory, validating them against the
+// backend's schema, and writes the result into the configuration.
+//
+// If the configuration has no backend block, a new one is written into
+// backendWizardFilename. Otherwise, the wizard only asks for the arguments
+// that the existing block doesn't set, and writes them into a partial
+// configuration file whose path is returned, to be used as a -backend-config
+// file.
+func (c *InitCommand) backendWizard(path string) (string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if !c.Input() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Input is disabled",
+			"The -interactive-backend option asks questions about the backend configuration, so it cannot be used when input is disabled.",
+		))
+		return "", diags
+	}
+
+	root, modDiags := c.loadSingleModule(path)
+	diags = diags.Append(modDiags)
+	if modDiags.HasErrors() {
+		return "", diags
+	}
+	if root.CloudConfig != nil {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Cloud backend already configured",
+			Detail:   "The configuration has a cloud block, which cannot be combined with a backend block.",
+			Subject:  root.CloudConfig.DeclRange.Ptr(),
+		})
+		return "", diags
+	}
+
+	c.Ui.Output(c.Colorize().Color("\n[reset][bold]Configuring the backend..."))
+
+	backendType := ""
+	existing := map[string]bool{}
+	if root.Backend != nil {
+		backendType = root.Backend.Type
+		attrs, _ := root.Backend.Config.JustAttributes()
+		for name := range attrs {
+			existing[name] = true
+		}
+		c.Ui.Output(fmt.Sprintf("The configuration already uses the %q backend.", backendType))
+	} else {
+		var err error
+		backendType, err = c.backendWizardType()
+		if err != nil {
+			diags = diags.Append(err)
+			return "", diags
+		}
+	}
+
+	bf := backendInit.Backend(backendType)
+	if bf == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported backend type",
+			fmt.Sprintf("There is no backend type named %q.", backendType),
+		))
+		return "", diags
+	}
+	b := bf(nil) // This is only used to get the schema and validate the configuration
+	schema := b.ConfigSchema()
+
+	vals, err := c.backendWizardArguments(schema, existing)
+	if err != nil {
+		diags = diags.Append(err)
+		return "", diags
+	}
+
+	// Validate the complete configuration in the same way as it will be
+	// when the backend is initialized, so that we don't write out a
+	// configuration that can't work.
+	body := configs.SynthBody("-interactive-backend", vals)
+	if root.Backend != nil {
+		body = configs.MergeBodies(root.Backend.Config, body)
+	}
+	configVal, hclDiags := hcldec.Decode(body, schema.DecoderSpec(), nil)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return "", diags
+	}
+	_, validateDiags := b.PrepareConfig(configVal)
+	diags = diags.Append(validateDiags)
+	if validateDiags.HasErrors() {
+		return "", diags
+	}
+
+	names := make([]string, 0, len(vals))
+	for name := range vals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	f := hclwrite.NewEmptyFile()
+	target := f.Body()
+	if root.Backend == nil {
+		tfBlock := target.AppendNewBlock("terraform", nil)
+		target = tfBlock.Body().AppendNewBlock("backend", []string{backendType}).Body()
+	}
+	for _, name := range names {
+		target.SetAttributeValue(name, vals[name])
+	}
+
+	if root.Backend == nil {
+		filename := filepath.Join(path, backendWizardFilename)
+		if _, err := os.Stat(filename); err == nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Backend configuration file already exists",
+				fmt.Sprintf("Cannot write the backend block into %s because the file already exists.", filename),
+			))
+			return "", diags
+		}
+		if err := os.WriteFile(filename, f.Bytes(), 0644); err != nil {
+			diags = diags.Append(fmt.Errorf("Failed to write backend configuration: %w", err))
+			return "", diags
+		}
+		c.Ui.Output(fmt.Sprintf("Wrote the backend block to %s.", filename))
+		c.backendWizardSensitiveNote(schema, names, filename)
+		return "", diags
+	}
+
+	filename := filepath.Join(path, fmt.Sprintf("config.%s.tfbackend", backendType))
+	if _, err := os.Stat(filename); err == nil {
+		overwrite, err := c.confirm(&tofu.InputOpts{
+			Id:          "backend-config-overwrite",
+			Query:       fmt.Sprintf("Overwrite %s?", filename),
+			Description: "The partial backend configuration file already exists. Only 'yes' will be accepted to overwrite it.",
+		})
+		if err != nil {
+			diags = diags.Append(err)
+			return "", diags
+		}
+		if !overwrite {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Backend configuration not written",
+				fmt.Sprintf("The partial backend configuration file %s already exists.", filename),
+			))
+			return "", diags
+		}
+	}
+	if err := os.WriteFile(filename, f.Bytes(), 0644); err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to write backend configuration: %w", err))
+		return "", diags
+	}
+	c.Ui.Output(fmt.Sprintf(
+		"Wrote the backend arguments to %s. Use -backend-config=%s when running \"tofu init\" in future.",
+		filename, filename,
+	))
+	c.backendWizardSensitiveNote(schema, names, filename)
+	return filename, diags
+}
+
+// backendWizardType asks for the type of backend to use, until it is given
+// the name of an available backend.
+func (c *InitCommand) backendWizardType() (string, error) {
+	var names []string
+	for _, name := range backendInit.Names() {
+		// The cloud backend is configured with a cloud block instead.
+		if name != "cloud" {
+			names = append(names, name)
+		}
+	}
+
+	for {
+		v, err := c.UIInput().Input(context.Background(), &tofu.InputOpts{
+			Id:          "backend-type",
+			Query:       "Backend type",
+			Description: fmt.Sprintf("The backend stores the OpenTofu state. Available types: %s.", strings.Join(names, ", ")),
+			Default:     "local",
+		})
+		if err != nil {
+			return "", fmt.Errorf("Error asking for the backend type: %w", err)
+		}
+		v = strings.TrimSpace(v)
+		if v == "" {
+			v = "local"
+		}
+		if v != "cloud" && backendInit.Backend(v) != nil {
+			return v, nil
+		}
+
+		if msg, removed := backendInit.RemovedBackends[v]; removed {
+			c.Ui.Error(msg)
+		} else {
+			c.Ui.Error(fmt.Sprintf("There is no backend type named %q.", v))
+		}
+	}
+}
+
+// backendWizardArguments asks for values of the required arguments in the
+// given backend schema that aren't already set, followed by any number of
+// other arguments given as name=value.
+func (c *InitCommand) backendWizardArguments(schema *configschema.Block, existing map[string]bool) (map[string]cty.Value, error) {
+	vals := make(map[string]cty.Value)
+
+	var required []string
+	for name, attrS := range schema.Attributes {
+		if attrS.Required && !existing[name] {
+			required = append(required, name)
+		}
+	}
+	sort.Strings(required)
+
+	for _, name := range required {
+		attrS := schema.Attributes[name]
+		for {
+			v, err := c.UIInput().Input(context.Background(), &tofu.InputOpts{
+				Id:          "backend-" + name,
+				Query:       name,
+				Description: attrS.Description,
+				Secret:      attrS.Sensitive,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("Error asking for %s: %w", name, err)
+			}
+			if v == "" {
+				c.Ui.Error(fmt.Sprintf("The argument %q is required.", name))
+				continue
+			}
+			val, diags := backendWizardValue(name, v, attrS)
+			if diags.HasErrors() {
+				c.showDiagnostics(diags)
+				continue
+			}
+			vals[name] = val
+			break
+		}
+	}
+
+	for i := 1; ; i++ {
+		v, err := c.UIInput().Input(context.Background(), &tofu.InputOpts{
+			Id:          fmt.Sprintf("backend-argument-%d", i),
+			Query:       "Additional argument",
+			Description: "Enter another backend argument as name=value, or leave empty to finish.",
+		})
+		if err != nil {
+			return nil, fmt.Errorf("Error asking for backend arguments: %w", err)
+		}
+		v = strings.TrimSpace(v)
+		if v == "" {
+			return vals, nil
+		}
+
+		name, raw, ok := strings.Cut(v, "=")
+		name = strings.TrimSpace(name)
+		attrS := schema.Attributes[name]
+		switch {
+		case !ok:
+			c.Ui.Error("Arguments must be given as name=value.")
+		case attrS == nil:
+			c.Ui.Error(fmt.Sprintf("The backend has no argument named %q.", name))
+		case existing[name]:
+			c.Ui.Error(fmt.Sprintf("The argument %q is already set in the backend block.", name))
+		default:
+			val, diags := backendWizardValue(name, strings.TrimSpace(raw), attrS)
+			if diags.HasErrors() {
+				c.showDiagnostics(diags)
+				continue
+			}
+			vals[name] = val
+		}
+	}
+}
+
+// backendWizardValue parses the value given for a backend argument in the
+// same way as a -backend-config=name=value option.
+func backendWizardValue(name, raw string, attrS *configschema.Attribute) (cty.Value, tfdiags.Diagnostics) {
+	val, diags := configValueFromCLI(name, raw, attrS.Type)
+	if diags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+	val, err := convert.Convert(val, attrS.Type)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid backend configuration value",
+			fmt.Sprintf("Invalid value for backend argument %q: %s", name, err),
+		))
+		return cty.DynamicVal, diags
+	}
+	return val, diags
+}
+
+// backendWizardSensitiveNote warns when sensitive arguments were written
+// into the given file, since they are stored in plain text.
+func (c *InitCommand) backendWizardSensitiveNote(schema *configschema.Block, names []string, filename string) {
+	var sensitive []string
+	for _, name := range names {
+		if schema.Attributes[name].Sensitive {
+			sensitive = append(sensitive, name)
+		}
+	}
+	if len(sensitive) == 0 {
+		return
+	}
+	c.Ui.Warn(fmt.Sprintf(
+		"The sensitive arguments %s are stored in plain text in %s. Avoid committing this file to version control.",
+		strings.Join(sensitive, ", "), filename,
+	))
+}
@@ -246,6 +246,121 @@ func TestInit_backend(t *testing.T) {
 	}
 }
 
+func TestInit_interactiveBackend(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+	if err := os.WriteFile("main.tf", []byte(`output "x" { value = 1 }`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	defer testInputMap(t, map[string]string{
+		"backend-type":       "local",
+		"backend-argument-1": "nope=1",
+		"backend-argument-2": "path=custom.tfstate",
+		"backend-argument-3": "",
+	})()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	if code := c.Run([]string{"-interactive-backend"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), `The backend has no argument named "nope".`; !strings.Contains(got, want) {
+		t.Errorf("error output does not contain %q\n%s", want, got)
+	}
+
+	src, err := os.ReadFile(backendWizardFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `terraform {
+  backend "local" {
+    path = "custom.tfstate"
+  }
+}
+`
+	if diff := cmp.Diff(want, string(src)); diff != "" {
+		t.Errorf("wrong backend block\n%s", diff)
+	}
+
+	state := testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
+	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":"custom.tfstate","workspace_dir":null}`; got != want {
+		t.Errorf("wrong config\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestInit_interactiveBackendPartial(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-backend-empty"), td)
+	defer testChdir(t, td)()
+
+	defer testInputMap(t, map[string]string{
+		"backend-argument-1": "path=hello",
+		"backend-argument-2": "",
+	})()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	if code := c.Run([]string{"-interactive-backend"}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	src, err := os.ReadFile("config.local.tfbackend")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(src), "path = \"hello\"\n"; got != want {
+		t.Errorf("wrong partial configuration %q; want %q", got, want)
+	}
+	if _, err := os.Stat(backendWizardFilename); !os.IsNotExist(err) {
+		t.Errorf("unexpected %s: %v", backendWizardFilename, err)
+	}
+
+	state := testDataStateRead(t, filepath.Join(DefaultDataDir, DefaultStateFilename))
+	if got, want := normalizeJSON(t, state.Backend.ConfigRaw), `{"path":"hello","workspace_dir":null}`; got != want {
+		t.Errorf("wrong config\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestInit_interactiveBackendNoInput(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-backend-empty"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	if code := c.Run([]string{"-interactive-backend", "-input=false"}); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\n%s", code, ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), "Input is disabled"; !strings.Contains(got, want) {
+		t.Errorf("error output does not contain %q\n%s", want, got)
+	}
+}
+
 func TestInit_backendUnset(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -1312,6 +1427,137 @@ func TestInit_inputFalse(t *testing.T) {
 	}
 }
 
+func TestInit_json(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get-providers"), td)
+	defer testChdir(t, td)()
+
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"exact":        {"1.2.3"},
+		"greater-than": {"2.3.4", "2.3.3", "2.3.0"},
+		"between":      {"3.4.5", "2.3.4", "1.2.3"},
+	})
+	defer close()
+
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+			ProviderSource:   providerSource,
+		},
+	}
+
+	code := c.Run([]string{"-json"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: \n%s", output.All())
+	}
+
+	events := testInitJSONEvents(t, output.Stdout())
+	for _, want := range []string{
+		"init_step_start backend",
+		"init_backend",
+		"init_step_complete backend",
+		"init_step_start providers",
+		"init_provider_query registry.opentofu.org/hashicorp/exact",
+		"init_provider_download registry.opentofu.org/hashicorp/exact",
+		"init_provider_installed registry.opentofu.org/hashicorp/exact",
+		"init_lock_updated registry.opentofu.org/hashicorp/exact",
+		"init_step_complete providers",
+	} {
+		if _, ok := events[want]; !ok {
+			t.Errorf("missing %q event\n%s", want, output.Stdout())
+		}
+	}
+
+	installed := events["init_provider_installed registry.opentofu.org/hashicorp/between"]
+	if got, want := installed["version"], "2.3.4"; got != want {
+		t.Errorf("wrong installed version %q; want %q", got, want)
+	}
+	if got, want := installed["source"], "download"; got != want {
+		t.Errorf("wrong installed source %q; want %q", got, want)
+	}
+	backend := events["init_backend"]
+	if got, want := backend["type"], "local"; got != want {
+		t.Errorf("wrong backend type %q; want %q", got, want)
+	}
+}
+
+func TestInit_jsonProviderErrored(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("init-get-providers"), td)
+	defer testChdir(t, td)()
+
+	// The "between" provider is not available, so installing it fails.
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"exact":        {"1.2.3"},
+		"greater-than": {"2.3.4"},
+	})
+	defer close()
+
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &InitCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+			View:             view,
+			ProviderSource:   providerSource,
+		},
+	}
+
+	code := c.Run([]string{"-json", "-backend=false"})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1\n%s", code, output.All())
+	}
+
+	events := testInitJSONEvents(t, output.Stdout())
+	for _, want := range []string{
+		"init_provider_errored registry.opentofu.org/hashicorp/between",
+		"init_step_errored providers",
+	} {
+		if _, ok := events[want]; !ok {
+			t.Errorf("missing %q event\n%s", want, output.Stdout())
+		}
+	}
+	if _, ok := events["init_step_complete providers"]; ok {
+		t.Errorf("unexpected init_step_complete event for providers\n%s", output.Stdout())
+	}
+}
+
+// testInitJSONEvents decodes the init events in the given JSON output,
+// keyed by their type followed by the step or provider they describe.
+func testInitJSONEvents(t *testing.T, output string) map[string]map[string]interface{} {
+	t.Helper()
+
+	events := make(map[string]map[string]interface{})
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		var msg struct {
+			Type string                 `json:"type"`
+			Init map[string]interface{} `json:"init"`
+		}
+		if err := json.Unmarshal([]byte(line), &msg); err != nil {
+			t.Fatalf("invalid JSON message %q: %s", line, err)
+		}
+		if msg.Init == nil {
+			continue
+		}
+		key := msg.Type
+		if step, ok := msg.Init["step"].(string); ok {
+			key += " " + step
+		}
+		if provider, ok := msg.Init["provider"].(string); ok {
+			key += " " + provider
+		}
+		events[key] = msg.Init
+	}
+	return events
+}
+
 func TestInit_getProvider(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
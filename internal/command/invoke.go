@@ -0,0 +1,278 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/repl"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// invokeArgsFilename is the synthetic filename of the arguments given with
+// -arg, for diagnostic messages.
+const invokeArgsFilename = "<invoke-arguments>"
+
+// InvokeCommand is a cli.Command implementation that invokes a
+// provider-defined action.
+type InvokeCommand struct {
+	Meta
+}
+
+func (c *InvokeCommand) Run(args []string) int {
+	var autoApprove bool
+	var actionArgs FlagStringSlice
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.extendedFlagSet("invoke")
+	cmdFlags.StringVar(&c.Meta.statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.BoolVar(&autoApprove, "auto-approve", false, "auto-approve")
+	cmdFlags.Var(&actionArgs, "arg", "arg")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("The invoke command expects exactly one argument, the action type.")
+		cmdFlags.Usage()
+		return 1
+	}
+	actionType := args[0]
+	if !hclsyntax.ValidIdentifier(actionType) {
+		c.Ui.Error(fmt.Sprintf("Invalid action type %q.", actionType))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	body, moreDiags := c.parseInvokeArgs(actionArgs)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	configPath := c.Meta.normalizePath(".")
+
+	// Check for user-supplied plugin path
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading plugin path: %s", err))
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	backendConfig, backendDiags := c.loadBackendConfig(configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// We require a local backend
+	local, ok := b.(backend.Local)
+	if !ok {
+		c.showDiagnostics(diags) // in case of any warnings in here
+		c.Ui.Error(ErrUnsupportedLocalOp)
+		return 1
+	}
+
+	// This command only reads the state
+	c.ignoreRemoteVersionConflict(b)
+
+	// Build the operation
+	opReq := c.Operation(b, arguments.ViewHuman, enc)
+	opReq.ConfigDir = configPath
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(err)
+		c.showDiagnostics(diags)
+		return 1
+	}
+	{
+		// Setup required variables/call for operation (usually done in Meta.RunOperation)
+		var moreDiags, callDiags tfdiags.Diagnostics
+		opReq.Variables, moreDiags = c.collectVariableValues()
+		opReq.RootCall, callDiags = c.rootModuleCall(opReq.ConfigDir)
+		diags = diags.Append(moreDiags).Append(callDiags)
+		if moreDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	// Get the context
+	lr, _, ctxDiags := local.LocalRun(opReq)
+	diags = diags.Append(ctxDiags)
+	if ctxDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Successfully creating the context can result in a lock, so ensure we release it
+	defer func() {
+		diags := opReq.StateLocker.Unlock()
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+		}
+	}()
+
+	if !autoApprove {
+		desc := fmt.Sprintf("OpenTofu will invoke the action %s", actionType)
+		if len(actionArgs) > 0 {
+			desc += " with the arguments:\n  " + strings.Join(actionArgs, "\n  ")
+		}
+		desc += "\n\nActions may make changes to your infrastructure that OpenTofu can't undo.\nOnly 'yes' will be accepted to confirm."
+		v, err := c.confirm(&tofu.InputOpts{
+			Id:          "approve",
+			Query:       "Do you want to invoke this action?",
+			Description: desc,
+		})
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to confirm the action",
+				fmt.Sprintf("%s. To invoke the action without confirmation, use the -auto-approve option.", err),
+			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+		if !v {
+			c.showDiagnostics(diags)
+			c.Ui.Error("Action cancelled.")
+			return 1
+		}
+	}
+
+	invokeOpts := &tofu.InvokeOpts{
+		ActionType: actionType,
+		Config:     body,
+	}
+	if lr.PlanOpts != nil {
+		// the LocalRun type is built primarily to support the main operations,
+		// so the variable values end up in the "PlanOpts" even though we're
+		// not actually making a plan.
+		invokeOpts.SetVariables = lr.PlanOpts.SetVariables
+	}
+	result, moreDiags := lr.Core.Invoke(lr.Config, lr.InputState, invokeOpts)
+	diags = diags.Append(moreDiags)
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[reset][green]Action %s invoked.", actionType)))
+	if result != cty.NilVal && !result.IsNull() {
+		c.Ui.Output("\nResult: " + repl.FormatValue(result, 0))
+	}
+	return 0
+}
+
+// parseInvokeArgs parses the arguments of the action, given with -arg as
+// NAME=EXPR, into a body that sets each of them.
+func (c *InvokeCommand) parseInvokeArgs(args []string) (hcl.Body, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var src strings.Builder
+	for _, arg := range args {
+		name, expr, ok := strings.Cut(arg, "=")
+		name = strings.TrimSpace(name)
+		if !ok || !hclsyntax.ValidIdentifier(name) || strings.Contains(expr, "\n") {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid -arg option",
+				fmt.Sprintf("The given -arg option %q is not correctly specified. It must be an argument name and an expression separated by an equals sign, like -arg='name=\"value\"'.", arg),
+			))
+			continue
+		}
+		fmt.Fprintf(&src, "%s = %s\n", name, expr)
+	}
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	srcBytes := []byte(src.String())
+	c.registerSynthConfigSource(invokeArgsFilename, srcBytes) // so we can include a source snippet
+	f, hclDiags := hclsyntax.ParseConfig(srcBytes, invokeArgsFilename, hcl.InitialPos)
+	diags = diags.Append(hclDiags)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+	return f.Body, diags
+}
+
+func (c *InvokeCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] invoke [options] ACTION
+
+  Invoke an action offered by a provider, such as rotating a key or
+  rebooting an instance.
+
+  The provider that offers the action is implied by the action type, in the
+  same way as for resource types, and is configured using the default
+  configuration for it in the root module. The arguments of the action are
+  expressions that can refer to the resources, variables and local values of
+  the root module.
+
+  Actions don't change the state. If an action changes remote objects, the
+  next plan will detect those changes.
+
+Options:
+
+  -arg=name=expr    Set an argument of the action to the value of the given
+                    expression, such as -arg='id=aws_instance.web.id'. Quote
+                    strings as in the configuration, such as
+                    -arg='reason="maintenance"'. This flag can be set
+                    multiple times.
+
+  -auto-approve     Skip interactive approval before invoking the action.
+
+  -input=true       Ask for input for variables if not directly set.
+
+  -state=path       Legacy option for the local backend only. See the local
+                    backend's documentation for more information.
+
+  -var 'foo=bar'    Set a variable in the OpenTofu configuration. This
+                    flag can be set multiple times.
+
+  -var-file=foo     Set variables in the OpenTofu configuration from
+                    a file. If "terraform.tfvars" or any ".auto.tfvars"
+                    files are present, they will be automatically loaded.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *InvokeCommand) Synopsis() string {
+	return "Invoke an action offered by a provider"
+}
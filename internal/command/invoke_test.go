@@ -0,0 +1,162 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// actionProvider is a MockProvider that offers a "test_reboot" action.
+type actionProvider struct {
+	*tofu.MockProvider
+
+	invoked *providers.InvokeActionRequest
+}
+
+func (p *actionProvider) GetActionSchemas() providers.GetActionSchemasResponse {
+	return providers.GetActionSchemasResponse{
+		Actions: map[string]providers.Schema{
+			"test_reboot": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":     {Type: cty.String, Required: true},
+						"reason": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (p *actionProvider) InvokeAction(req providers.InvokeActionRequest) providers.InvokeActionResponse {
+	p.invoked = &req
+	return providers.InvokeActionResponse{
+		Result: cty.StringVal("rebooted"),
+	}
+}
+
+func TestInvoke(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "foo",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"i-abc123"}`),
+				Status:    states.ObjectReady,
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+	})
+	statePath := testStateFile(t, state)
+
+	p := &actionProvider{MockProvider: applyFixtureProvider()}
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &InvokeCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{
+		"-state", statePath,
+		"-auto-approve",
+		"-arg", "id=test_instance.foo.id",
+		"-arg", `reason="maintenance"`,
+		"test_reboot",
+	})
+	done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if p.invoked == nil {
+		t.Fatal("action was not invoked")
+	}
+	want := cty.ObjectVal(map[string]cty.Value{
+		"id":     cty.StringVal("i-abc123"),
+		"reason": cty.StringVal("maintenance"),
+	})
+	if !p.invoked.Config.RawEquals(want) {
+		t.Errorf("wrong arguments\ngot:  %#v\nwant: %#v", p.invoked.Config, want)
+	}
+	if got, want := ui.OutputWriter.String(), `Result: "rebooted"`; !strings.Contains(got, want) {
+		t.Errorf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestInvoke_cancelled(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	defer testInteractiveInput(t, []string{"no"})()
+
+	p := &actionProvider{MockProvider: applyFixtureProvider()}
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &InvokeCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-arg", `id="i-abc123"`, "test_reboot"})
+	done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1", code)
+	}
+	if p.invoked != nil {
+		t.Fatal("action was invoked without approval")
+	}
+	if got, want := ui.ErrorWriter.String(), "Action cancelled"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestInvoke_invalidArg(t *testing.T) {
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &InvokeCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+
+	code := c.Run([]string{"-arg", "id", "test_reboot"})
+	done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "Invalid -arg option"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
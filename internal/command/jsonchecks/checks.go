@@ -10,6 +10,8 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/hashicorp/hcl/v2"
+
 	"github.com/opentofu/opentofu/internal/states"
 )
 
@@ -28,20 +30,44 @@ func MarshalCheckStates(results *states.CheckResults) []byte {
 			dynamicAddr := elem.Key
 			result := elem.Value
 
-			problems := make([]checkProblem, 0, len(result.FailureMessages))
-			for _, msg := range result.FailureMessages {
-				problems = append(problems, checkProblem{
-					Message: msg,
-				})
+			var problems []checkProblem
+			var conditions []checkCondition
+			if len(result.Rules) != 0 {
+				// When we know the results of the individual checks then
+				// we can also say where each problem was declared.
+				conditions = make([]checkCondition, 0, len(result.Rules))
+				for _, rule := range result.Rules {
+					rng := makeSourceRange(rule.DeclRange)
+					conditions = append(conditions, checkCondition{
+						Address: rule.Rule.String(),
+						Status:  checkStatusForJSON(rule.Status),
+						Message: rule.FailureMessage,
+						Range:   rng,
+					})
+					if rule.FailureMessage != "" {
+						problems = append(problems, checkProblem{
+							Message: rule.FailureMessage,
+							Range:   rng,
+						})
+					}
+				}
+			} else {
+				problems = make([]checkProblem, 0, len(result.FailureMessages))
+				for _, msg := range result.FailureMessages {
+					problems = append(problems, checkProblem{
+						Message: msg,
+					})
+				}
 			}
-			sort.Slice(problems, func(i, j int) bool {
+			sort.SliceStable(problems, func(i, j int) bool {
 				return problems[i].Message < problems[j].Message
 			})
 
 			objects = append(objects, checkResultDynamic{
-				Address:  makeDynamicObjectAddr(dynamicAddr),
-				Status:   checkStatusForJSON(result.Status),
-				Problems: problems,
+				Address:    makeDynamicObjectAddr(dynamicAddr),
+				Status:     checkStatusForJSON(result.Status),
+				Problems:   problems,
+				Conditions: conditions,
 			})
 		}
 
@@ -107,6 +133,30 @@ type checkResultDynamic struct {
 	// that were invalid then status can be "error" while simultaneously
 	// returning problems in this property.
 	Problems []checkProblem `json:"problems,omitempty"`
+
+	// Conditions describes the result of each of the individual conditions
+	// that apply to this dynamic object. This is available only for results
+	// produced during the current run, and not for results loaded from a
+	// saved plan or state.
+	Conditions []checkCondition `json:"conditions,omitempty"`
+}
+
+// checkCondition describes the result of a single condition for a dynamic
+// object, such as one assertion in a check block.
+type checkCondition struct {
+	// Address is the address of the condition, such as
+	// "check.health.assert[0]".
+	Address string `json:"address"`
+
+	// Status is the status of this condition alone.
+	Status checkStatus `json:"status"`
+
+	// Message is the condition error message provided by the author, if
+	// the condition failed.
+	Message string `json:"message,omitempty"`
+
+	// Range is the source location where the condition was declared.
+	Range *sourceRange `json:"range,omitempty"`
 }
 
 // checkProblem describes one of potentially several problems that led to
@@ -115,7 +165,33 @@ type checkProblem struct {
 	// Message is the condition error message provided by the author.
 	Message string `json:"message"`
 
-	// We don't currently have any other problem-related data, but this is
-	// intentionally an object to allow us to add other data over time, such
-	// as the source location where the failing condition was defined.
+	// Range is the source location where the failing condition was
+	// declared, if known.
+	Range *sourceRange `json:"range,omitempty"`
+}
+
+// sourceRange is a range of characters in a configuration source file, in
+// the same form as the source ranges of diagnostics in the machine-readable
+// UI.
+type sourceRange struct {
+	Filename string    `json:"filename"`
+	Start    sourcePos `json:"start"`
+	End      sourcePos `json:"end"`
+}
+
+type sourcePos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+func makeSourceRange(rng hcl.Range) *sourceRange {
+	if rng.Filename == "" {
+		return nil
+	}
+	return &sourceRange{
+		Filename: rng.Filename,
+		Start:    sourcePos{Line: rng.Start.Line, Column: rng.Start.Column, Byte: rng.Start.Byte},
+		End:      sourcePos{Line: rng.End.Line, Column: rng.End.Column, Byte: rng.End.Byte},
+	}
 }
@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/states"
@@ -225,6 +226,93 @@ func TestMarshalCheckStates(t *testing.T) {
 				},
 			},
 		},
+		"conditions": {
+			&states.CheckResults{
+				ConfigResults: addrs.MakeMap(
+					addrs.MakeMapElem(checkBlockAAddr, &states.CheckResultAggregate{
+						Status: checks.StatusFail,
+						ObjectResults: addrs.MakeMap(
+							addrs.MakeMapElem(checkBlockAInstAddr, &states.CheckResultObject{
+								Status: checks.StatusFail,
+								FailureMessages: []string{
+									"Couldn't reverse the polarity.",
+								},
+								Rules: []checks.RuleResult{
+									{
+										Rule:   addrs.NewCheckRule(checkBlockAInstAddr, addrs.CheckAssertion, 0),
+										Status: checks.StatusPass,
+										DeclRange: hcl.Range{
+											Filename: "main.tf",
+											Start:    hcl.Pos{Line: 2, Column: 3, Byte: 14},
+											End:      hcl.Pos{Line: 2, Column: 9, Byte: 20},
+										},
+									},
+									{
+										Rule:           addrs.NewCheckRule(checkBlockAInstAddr, addrs.CheckAssertion, 1),
+										Status:         checks.StatusFail,
+										FailureMessage: "Couldn't reverse the polarity.",
+										DeclRange: hcl.Range{
+											Filename: "main.tf",
+											Start:    hcl.Pos{Line: 6, Column: 3, Byte: 80},
+											End:      hcl.Pos{Line: 6, Column: 9, Byte: 86},
+										},
+									},
+								},
+							}),
+						),
+					}),
+				),
+			},
+			[]any{
+				map[string]any{
+					"address": map[string]any{
+						"kind":       "check",
+						"to_display": "check.a",
+						"name":       "a",
+					},
+					"instances": []any{
+						map[string]any{
+							"address": map[string]any{
+								"to_display": `check.a`,
+							},
+							"conditions": []any{
+								map[string]any{
+									"address": "check.a.assert[0]",
+									"status":  "pass",
+									"range": map[string]any{
+										"filename": "main.tf",
+										"start":    map[string]any{"line": float64(2), "column": float64(3), "byte": float64(14)},
+										"end":      map[string]any{"line": float64(2), "column": float64(9), "byte": float64(20)},
+									},
+								},
+								map[string]any{
+									"address": "check.a.assert[1]",
+									"status":  "fail",
+									"message": "Couldn't reverse the polarity.",
+									"range": map[string]any{
+										"filename": "main.tf",
+										"start":    map[string]any{"line": float64(6), "column": float64(3), "byte": float64(80)},
+										"end":      map[string]any{"line": float64(6), "column": float64(9), "byte": float64(86)},
+									},
+								},
+							},
+							"problems": []any{
+								map[string]any{
+									"message": "Couldn't reverse the polarity.",
+									"range": map[string]any{
+										"filename": "main.tf",
+										"start":    map[string]any{"line": float64(6), "column": float64(3), "byte": float64(80)},
+										"end":      map[string]any{"line": float64(6), "column": float64(9), "byte": float64(86)},
+									},
+								},
+							},
+							"status": "fail",
+						},
+					},
+					"status": "fail",
+				},
+			},
+		},
 	}
 
 	for name, test := range tests {
@@ -0,0 +1,188 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonconfig
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/command/jsonfunction"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// SchemaFormatVersion represents the version of the json format produced by
+// MarshalSchema and will be incremented for any change to this format that
+// requires changes to a consuming parser.
+const SchemaFormatVersion = "1.0"
+
+// configSchema is the top-level object returned by MarshalSchema. It
+// describes the interface of a configuration from the perspective of
+// someone calling it as a module, rather than its full content.
+type configSchema struct {
+	FormatVersion        string                                     `json:"format_version"`
+	Variables            map[string]schemaVariable                  `json:"variables,omitempty"`
+	Outputs              map[string]schemaOutput                    `json:"outputs,omitempty"`
+	ModuleCalls          map[string]schemaModuleCall                `json:"module_calls,omitempty"`
+	ProviderRequirements map[string]schemaProviderRequirement       `json:"provider_requirements,omitempty"`
+	Functions            map[string]*jsonfunction.FunctionSignature `json:"functions,omitempty"`
+}
+
+type schemaVariable struct {
+	Type        json.RawMessage    `json:"type"`
+	Default     json.RawMessage    `json:"default,omitempty"`
+	Required    bool               `json:"required"`
+	Description string             `json:"description,omitempty"`
+	Sensitive   bool               `json:"sensitive,omitempty"`
+	Nullable    bool               `json:"nullable"`
+	Deprecated  string             `json:"deprecated,omitempty"`
+	Validations []schemaValidation `json:"validations,omitempty"`
+}
+
+type schemaValidation struct {
+	Condition    expression `json:"condition"`
+	ErrorMessage expression `json:"error_message"`
+}
+
+type schemaOutput struct {
+	Description string `json:"description,omitempty"`
+	Sensitive   bool   `json:"sensitive,omitempty"`
+	Deprecated  string `json:"deprecated,omitempty"`
+}
+
+type schemaModuleCall struct {
+	Source            string `json:"source"`
+	VersionConstraint string `json:"version_constraint,omitempty"`
+}
+
+type schemaProviderRequirement struct {
+	VersionConstraint string `json:"version_constraint,omitempty"`
+}
+
+// MarshalSchema returns the json encoding of the schema of the given
+// configuration: the input variables and output values of its root module,
+// the modules that the root module calls, the providers required anywhere in
+// the configuration, and the signatures of the given functions.
+func MarshalSchema(c *configs.Config, funcs map[string]function.Function) ([]byte, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	ret := configSchema{
+		FormatVersion: SchemaFormatVersion,
+	}
+
+	if len(c.Module.Variables) > 0 {
+		ret.Variables = make(map[string]schemaVariable, len(c.Module.Variables))
+		for name, v := range c.Module.Variables {
+			sv, err := marshalSchemaVariable(v)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					fmt.Sprintf("Failed to serialize variable %q", name),
+					err.Error(),
+				))
+				continue
+			}
+			ret.Variables[name] = sv
+		}
+	}
+
+	if len(c.Module.Outputs) > 0 {
+		ret.Outputs = make(map[string]schemaOutput, len(c.Module.Outputs))
+		for name, o := range c.Module.Outputs {
+			ret.Outputs[name] = schemaOutput{
+				Description: o.Description,
+				Sensitive:   o.Sensitive,
+				Deprecated:  o.Deprecated,
+			}
+		}
+	}
+
+	if len(c.Module.ModuleCalls) > 0 {
+		ret.ModuleCalls = make(map[string]schemaModuleCall, len(c.Module.ModuleCalls))
+		for name, mc := range c.Module.ModuleCalls {
+			ret.ModuleCalls[name] = schemaModuleCall{
+				// As in the full configuration representation, we echo back
+				// exactly what the user entered rather than the normalized
+				// source address.
+				Source:            mc.SourceAddrRaw,
+				VersionConstraint: mc.Version.Required.String(),
+			}
+		}
+	}
+
+	reqs, reqDiags := c.ProviderRequirements()
+	diags = diags.Append(reqDiags)
+	if len(reqs) > 0 {
+		ret.ProviderRequirements = make(map[string]schemaProviderRequirement, len(reqs))
+		for addr, constraints := range reqs {
+			ret.ProviderRequirements[addr.String()] = schemaProviderRequirement{
+				VersionConstraint: getproviders.VersionConstraintsString(constraints),
+			}
+		}
+	}
+
+	if len(funcs) > 0 {
+		signatures, funcDiags := jsonfunction.MarshalSignatures(funcs)
+		diags = diags.Append(funcDiags)
+		ret.Functions = signatures
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	src, err := json.Marshal(ret)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to serialize configuration schema",
+			err.Error(),
+		))
+		return nil, diags
+	}
+	return src, diags
+}
+
+func marshalSchemaVariable(v *configs.Variable) (schemaVariable, error) {
+	ret := schemaVariable{
+		Required:    v.Default == cty.NilVal,
+		Description: v.Description,
+		Sensitive:   v.Sensitive,
+		Nullable:    v.Nullable,
+		Deprecated:  v.Deprecated,
+	}
+
+	ty := v.ConstraintType
+	if ty == cty.NilType {
+		ty = cty.DynamicPseudoType
+	}
+	typeJSON, err := ctyjson.MarshalType(ty)
+	if err != nil {
+		return ret, err
+	}
+	ret.Type = typeJSON
+
+	if v.Default != cty.NilVal {
+		ret.Default, err = ctyjson.Marshal(v.Default, v.Default.Type())
+		if err != nil {
+			return ret, err
+		}
+	}
+
+	for _, rule := range v.Validations {
+		ret.Validations = append(ret.Validations, schemaValidation{
+			Condition:    marshalExpression(rule.Condition),
+			ErrorMessage: marshalExpression(rule.ErrorMessage),
+		})
+	}
+
+	return ret, nil
+}
@@ -96,6 +96,15 @@ type RenderHumanOpts struct {
 	// HideDiffActionSymbols tells the renderer not to show the '+'/'-' symbols
 	// and to skip the places where the symbols would result in an offset.
 	HideDiffActionSymbols bool
+
+	// FullStringDiff tells the renderer to show the full old and new values
+	// of long strings that changed, instead of an inline diff that
+	// highlights the changed words or lines.
+	FullStringDiff bool
+
+	// SuppressAttributes are the names of block attributes whose values the
+	// renderer should hide, showing only that they changed.
+	SuppressAttributes map[string]bool
 }
 
 // NewRenderHumanOpts creates a new RenderHumanOpts struct with the required
@@ -115,6 +124,8 @@ func (opts RenderHumanOpts) Clone() RenderHumanOpts {
 		OverrideNullSuffix:    opts.OverrideNullSuffix,
 		ShowUnchangedChildren: opts.ShowUnchangedChildren,
 		HideDiffActionSymbols: opts.HideDiffActionSymbols,
+		FullStringDiff:        opts.FullStringDiff,
+		SuppressAttributes:    opts.SuppressAttributes,
 
 		// OverrideForcesReplacement is a special case in that it doesn't
 		// cascade. So each diff should decide independently whether it's direct
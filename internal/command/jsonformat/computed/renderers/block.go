@@ -34,6 +34,20 @@ func importantAttribute(attr string) bool {
 	return false
 }
 
+// renderBlockAttribute renders the value of the given block attribute, or a
+// placeholder if the attribute is one of the suppressed attributes.
+func renderBlockAttribute(key string, attribute computed.Diff, indent int, opts computed.RenderHumanOpts) string {
+	if !opts.SuppressAttributes[key] {
+		return attribute.RenderHuman(indent, opts)
+	}
+
+	placeholder := "(suppressed value)"
+	if attribute.Action == plans.Update {
+		placeholder = "(suppressed value changed)"
+	}
+	return fmt.Sprintf("%s%s%s", placeholder, nullSuffix(attribute.Action, opts), forcesReplacement(attribute.Replace, opts))
+}
+
 func Block(attributes map[string]computed.Diff, blocks Blocks) computed.DiffRenderer {
 	return &blockRenderer{
 		attributes: attributes,
@@ -84,7 +98,7 @@ func (renderer blockRenderer) RenderHuman(diff computed.Diff, indent int, opts c
 			for _, warning := range attribute.WarningsHuman(indent+1, importantAttributeOpts) {
 				buf.WriteString(fmt.Sprintf("%s%s\n", formatIndent(indent+1), warning))
 			}
-			buf.WriteString(fmt.Sprintf("%s%s%-*s = %s\n", formatIndent(indent+1), writeDiffActionSymbol(attribute.Action, importantAttributeOpts), maximumAttributeKeyLen, key, attribute.RenderHuman(indent+1, importantAttributeOpts)))
+			buf.WriteString(fmt.Sprintf("%s%s%-*s = %s\n", formatIndent(indent+1), writeDiffActionSymbol(attribute.Action, importantAttributeOpts), maximumAttributeKeyLen, key, renderBlockAttribute(key, attribute, indent+1, importantAttributeOpts)))
 			continue
 		}
 		if attribute.Action == plans.NoOp && !opts.ShowUnchangedChildren {
@@ -95,7 +109,7 @@ func (renderer blockRenderer) RenderHuman(diff computed.Diff, indent int, opts c
 		for _, warning := range attribute.WarningsHuman(indent+1, opts) {
 			buf.WriteString(fmt.Sprintf("%s%s\n", formatIndent(indent+1), warning))
 		}
-		buf.WriteString(fmt.Sprintf("%s%s%-*s = %s\n", formatIndent(indent+1), writeDiffActionSymbol(attribute.Action, attributeOpts), maximumAttributeKeyLen, escapedAttributeKeys[key], attribute.RenderHuman(indent+1, attributeOpts)))
+		buf.WriteString(fmt.Sprintf("%s%s%-*s = %s\n", formatIndent(indent+1), writeDiffActionSymbol(attribute.Action, attributeOpts), maximumAttributeKeyLen, escapedAttributeKeys[key], renderBlockAttribute(key, attribute, indent+1, attributeOpts)))
 	}
 
 	if unchangedAttributes > 0 {
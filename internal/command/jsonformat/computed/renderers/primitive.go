@@ -152,30 +152,44 @@ func (renderer primitiveRenderer) renderStringDiff(diff computed.Diff, indent in
 		}
 
 		if !beforeString.IsMultiline && !afterString.IsMultiline {
+			if !opts.FullStringDiff && !beforeString.IsNull && !afterString.IsNull {
+				if wordDiff, ok := renderWordDiff(beforeString.String, afterString.String, opts); ok {
+					return fmt.Sprintf("%s%s", wordDiff, forcesReplacement(diff.Replace, opts))
+				}
+			}
 			return fmt.Sprintf("%s %s %s%s", beforeString.RenderSimple(), opts.Colorize.Color("[yellow]->[reset]"), afterString.RenderSimple(), forcesReplacement(diff.Replace, opts))
 		}
 
 		beforeLines := strings.Split(beforeString.String, "\n")
 		afterLines := strings.Split(afterString.String, "\n")
 
+		var diffLines []stringDiffLine
 		processIndices := func(beforeIx, afterIx int) {
 			if beforeIx < 0 || beforeIx >= len(beforeLines) {
-				lines = append(lines, fmt.Sprintf("%s%s%s", formatIndent(indent+1), writeDiffActionSymbol(plans.Create, opts), afterLines[afterIx]))
+				diffLines = append(diffLines, stringDiffLine{fmt.Sprintf("%s%s%s", formatIndent(indent+1), writeDiffActionSymbol(plans.Create, opts), afterLines[afterIx]), true})
 				return
 			}
 
 			if afterIx < 0 || afterIx >= len(afterLines) {
-				lines = append(lines, fmt.Sprintf("%s%s%s", formatIndent(indent+1), writeDiffActionSymbol(plans.Delete, opts), beforeLines[beforeIx]))
+				diffLines = append(diffLines, stringDiffLine{fmt.Sprintf("%s%s%s", formatIndent(indent+1), writeDiffActionSymbol(plans.Delete, opts), beforeLines[beforeIx]), true})
 				return
 			}
 
-			lines = append(lines, fmt.Sprintf("%s%s%s", formatIndent(indent+1), writeDiffActionSymbol(plans.NoOp, opts), beforeLines[beforeIx]))
+			diffLines = append(diffLines, stringDiffLine{fmt.Sprintf("%s%s%s", formatIndent(indent+1), writeDiffActionSymbol(plans.NoOp, opts), beforeLines[beforeIx]), false})
 		}
 		isObjType := func(_ string) bool {
 			return false
 		}
 
 		collections.ProcessSlice(beforeLines, afterLines, processIndices, isObjType)
+
+		if opts.FullStringDiff || opts.ShowUnchangedChildren {
+			for _, line := range diffLines {
+				lines = append(lines, line.text)
+			}
+		} else {
+			lines = foldUnchangedLines(diffLines, indent, opts)
+		}
 	}
 
 	// We return early if we find non-multiline strings or JSON strings, so we
@@ -233,6 +233,90 @@ null -> jsonencode(
     EOT
 `,
 		},
+		"primitive_multiline_string_update_hides_unchanged_lines": {
+			diff: computed.Diff{
+				Renderer: Primitive("a\nb\nc\nd\ne\nold\nf\ng\nh\ni\nj", "a\nb\nc\nd\ne\nnew\nf\ng\nh\ni\nj", cty.String),
+				Action:   plans.Update,
+			},
+			expected: `
+<<-EOT
+        # (2 unchanged lines hidden)
+        c
+        d
+        e
+      - old
+      + new
+        f
+        g
+        h
+        # (2 unchanged lines hidden)
+    EOT
+`,
+		},
+		"primitive_multiline_string_update_full_string_diff": {
+			diff: computed.Diff{
+				Renderer: Primitive("a\nb\nc\nd\ne\nold", "a\nb\nc\nd\ne\nnew", cty.String),
+				Action:   plans.Update,
+			},
+			opts: computed.RenderHumanOpts{
+				FullStringDiff: true,
+			},
+			expected: `
+<<-EOT
+        a
+        b
+        c
+        d
+        e
+      - old
+      + new
+    EOT
+`,
+		},
+		"primitive_long_string_update": {
+			diff: computed.Diff{
+				Renderer: Primitive(
+					"The quick brown fox jumps over the lazy dog, and then the quick brown fox jumps over it once again, just to be sure.",
+					"The quick brown cat jumps over the lazy dog, and then the quick brown cat jumps over it once again, just to be sure.",
+					cty.String),
+				Action:  plans.Update,
+				Replace: true,
+			},
+			expected: `"The quick brown [-fox-]{+cat+} jumps over the lazy dog, and then the quick brown [-fox-]{+cat+} jumps over it once again, just to be sure." # forces replacement`,
+		},
+		"primitive_long_string_update_escaped": {
+			diff: computed.Diff{
+				Renderer: Primitive(
+					`echo "hello" > /tmp/greeting && chmod 0644 /tmp/greeting && systemctl restart greeter.service --now`,
+					`echo "hello world" > /tmp/greeting && chmod 0644 /tmp/greeting && systemctl restart greeter.service --now`,
+					cty.String),
+				Action: plans.Update,
+			},
+			expected: `"echo \"hello{+ world+}\" > /tmp/greeting && chmod 0644 /tmp/greeting && systemctl restart greeter.service --now"`,
+		},
+		"primitive_long_string_update_dissimilar": {
+			diff: computed.Diff{
+				Renderer: Primitive(
+					"aGVsbG8gd29ybGQsIHRoaXMgaXMgYSBsb25nIGJhc2U2NCBlbmNvZGVkIHN0cmluZyB0aGF0IGlzIGFsbCBvbmUgd29yZA==",
+					"aGVsbG8gd29ybGQsIHRoaXMgaXMgYW5vdGhlciBsb25nIGJhc2U2NCBlbmNvZGVkIHN0cmluZyB0aGF0IGlzIG9uZSB3b3Jk",
+					cty.String),
+				Action: plans.Update,
+			},
+			expected: `"aGVsbG8gd29ybGQsIHRoaXMgaXMgYSBsb25nIGJhc2U2NCBlbmNvZGVkIHN0cmluZyB0aGF0IGlzIGFsbCBvbmUgd29yZA==" -> "aGVsbG8gd29ybGQsIHRoaXMgaXMgYW5vdGhlciBsb25nIGJhc2U2NCBlbmNvZGVkIHN0cmluZyB0aGF0IGlzIG9uZSB3b3Jk"`,
+		},
+		"primitive_long_string_update_full_string_diff": {
+			diff: computed.Diff{
+				Renderer: Primitive(
+					"The quick brown fox jumps over the lazy dog, and then the quick brown fox jumps over it once again, just to be sure.",
+					"The quick brown cat jumps over the lazy dog, and then the quick brown cat jumps over it once again, just to be sure.",
+					cty.String),
+				Action: plans.Update,
+			},
+			opts: computed.RenderHumanOpts{
+				FullStringDiff: true,
+			},
+			expected: `"The quick brown fox jumps over the lazy dog, and then the quick brown fox jumps over it once again, just to be sure." -> "The quick brown cat jumps over the lazy dog, and then the quick brown cat jumps over it once again, just to be sure."`,
+		},
 		"primitive_json_string_create": {
 			diff: computed.Diff{
 				Renderer: Primitive(nil, "{\"key_one\": \"value_one\",\"key_two\":\"value_two\"}", cty.String),
@@ -2156,6 +2240,55 @@ jsonencode(
         # (1 unchanged attribute hidden)
 
         # (2 unchanged blocks hidden)
+    }`,
+		},
+		"block_suppressed_attributes": {
+			diff: computed.Diff{
+				Renderer: Block(map[string]computed.Diff{
+					"id": {
+						Renderer: Primitive("root", "root", cty.String),
+						Action:   plans.NoOp,
+					},
+					"etag": {
+						Renderer: Primitive("abc", "def", cty.String),
+						Action:   plans.Update,
+					},
+					"content": {
+						Renderer: Primitive(nil, "aGVsbG8gd29ybGQ=", cty.String),
+						Action:   plans.Create,
+					},
+					"string": {
+						Renderer: Primitive("one", "two", cty.String),
+						Action:   plans.Update,
+					},
+				}, Blocks{
+					SingleBlocks: map[string]computed.Diff{
+						"nested_block": {
+							Renderer: Block(map[string]computed.Diff{
+								"etag": {
+									Renderer: Primitive("abc", nil, cty.String),
+									Action:   plans.Delete,
+								},
+							}, Blocks{}),
+							Action: plans.Update,
+						},
+					},
+				}),
+				Action: plans.Update,
+			},
+			opts: computed.RenderHumanOpts{
+				SuppressAttributes: map[string]bool{"etag": true, "content": true},
+			},
+			expected: `
+{
+      + content = (suppressed value)
+      ~ etag    = (suppressed value changed)
+        id      = "root"
+      ~ string  = "one" -> "two"
+
+      ~ nested_block {
+          - etag = (suppressed value) -> null
+        }
     }`,
 		},
 		"output_map_to_list": {
@@ -0,0 +1,182 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package renderers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/opentofu/opentofu/internal/command/jsonformat/collections"
+	"github.com/opentofu/opentofu/internal/command/jsonformat/computed"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+const (
+	// wordDiffMinLength is the length from which a single line string that
+	// changed is rendered as an inline word diff, rather than as the old and
+	// new values. Shorter strings usually fit side by side on one line.
+	wordDiffMinLength = 100
+
+	// wordDiffMaxCells limits the size of the table used to compute a word
+	// diff, so that very long strings with many words don't slow down
+	// rendering. Such strings are rendered in full instead.
+	wordDiffMaxCells = 1 << 20
+
+	// stringDiffContextLines is the number of unchanged lines that are shown
+	// before and after each changed line of a multiline string.
+	stringDiffContextLines = 3
+)
+
+// renderWordDiff renders a change between two single line strings as one
+// quoted string, with the removed words marked as [-removed-] and the added
+// words marked as {+added+}.
+//
+// It returns false if the strings are short, or so different that the inline
+// diff would be harder to read than the old and new values.
+func renderWordDiff(before, after string, opts computed.RenderHumanOpts) (string, bool) {
+	if len(before) < wordDiffMinLength && len(after) < wordDiffMinLength {
+		return "", false
+	}
+
+	beforeWords := splitWords(before)
+	afterWords := splitWords(after)
+	if len(beforeWords)*len(afterWords) > wordDiffMaxCells {
+		return "", false
+	}
+
+	type segment struct {
+		action plans.Action
+		text   string
+	}
+	var segments []segment
+	unchangedLen := 0
+	collections.ProcessSlice(beforeWords, afterWords, func(beforeIx, afterIx int) {
+		var seg segment
+		switch {
+		case beforeIx >= len(beforeWords):
+			seg = segment{plans.Create, afterWords[afterIx]}
+		case afterIx >= len(afterWords):
+			seg = segment{plans.Delete, beforeWords[beforeIx]}
+		default:
+			seg = segment{plans.NoOp, beforeWords[beforeIx]}
+			unchangedLen += len(seg.text)
+		}
+		if last := len(segments) - 1; last >= 0 && segments[last].action == seg.action {
+			segments[last].text += seg.text
+			return
+		}
+		segments = append(segments, seg)
+	}, func(string) bool { return false })
+
+	// If less than half of the longer string is unchanged, the old and new
+	// values are easier to compare than an inline diff.
+	if unchangedLen*2 < max(len(before), len(after)) {
+		return "", false
+	}
+
+	var buf strings.Builder
+	buf.WriteString(`"`)
+	for _, seg := range segments {
+		quoted := strconv.Quote(seg.text)
+		text := quoted[1 : len(quoted)-1]
+		switch seg.action {
+		case plans.Create:
+			buf.WriteString(opts.Colorize.Color("[green]"))
+			buf.WriteString("{+" + text + "+}")
+			buf.WriteString(opts.Colorize.Color("[reset]"))
+		case plans.Delete:
+			buf.WriteString(opts.Colorize.Color("[red]"))
+			buf.WriteString("[-" + text + "-]")
+			buf.WriteString(opts.Colorize.Color("[reset]"))
+		default:
+			buf.WriteString(text)
+		}
+	}
+	buf.WriteString(`"`)
+	return buf.String(), true
+}
+
+// splitWords splits the given string into words, runs of whitespace, and
+// individual punctuation characters, so that joining the result gives back
+// the original string.
+func splitWords(str string) []string {
+	isWord := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+
+	var words []string
+	start := -1
+	var prevWord, prevSpace bool
+	for i, r := range str {
+		word, space := isWord(r), unicode.IsSpace(r)
+		if start >= 0 && (!(word && prevWord) && !(space && prevSpace)) {
+			words = append(words, str[start:i])
+			start = -1
+		}
+		if start < 0 {
+			start = i
+		}
+		prevWord, prevSpace = word, space
+	}
+	if start >= 0 {
+		words = append(words, str[start:])
+	}
+	return words
+}
+
+// stringDiffLine is a line of a rendered diff of a multiline string.
+type stringDiffLine struct {
+	text    string
+	changed bool
+}
+
+// foldUnchangedLines returns the given lines of a multiline string diff,
+// replacing runs of unchanged lines that are more than stringDiffContextLines
+// away from any changed line with a single line saying how many were hidden.
+func foldUnchangedLines(lines []stringDiffLine, indent int, opts computed.RenderHumanOpts) []string {
+	visible := make([]bool, len(lines))
+	anyChanged := false
+	for i, line := range lines {
+		if !line.changed {
+			continue
+		}
+		anyChanged = true
+		for j := max(0, i-stringDiffContextLines); j <= min(len(lines)-1, i+stringDiffContextLines); j++ {
+			visible[j] = true
+		}
+	}
+	if !anyChanged {
+		// Only whitespace at the start or end of the string changed, so
+		// there's nothing to show context around.
+		for i := range visible {
+			visible[i] = true
+		}
+	}
+
+	var ret []string
+	for i := 0; i < len(lines); {
+		if visible[i] {
+			ret = append(ret, lines[i].text)
+			i++
+			continue
+		}
+
+		end := i
+		for end < len(lines) && !visible[end] {
+			end++
+		}
+		if end-i == 1 {
+			// Hiding a single line wouldn't make the diff any shorter.
+			ret = append(ret, lines[i].text)
+		} else {
+			ret = append(ret, fmt.Sprintf("%s%s%s", formatIndent(indent+1), writeDiffActionSymbol(plans.NoOp, opts), unchanged("line", end-i, opts)))
+		}
+		i = end
+	}
+	return ret
+}
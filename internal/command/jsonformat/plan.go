@@ -215,11 +215,15 @@ func (plan Plan) renderHuman(renderer Renderer, mode plans.Mode, opts ...plans.Q
 			renderer.Streams.Printf("\nOpenTofu will perform the following actions:\n")
 		}
 
-		for _, change := range changes {
-			diff, render := renderHumanDiff(renderer, change, proposedChange)
-			if render {
-				fmt.Fprintln(renderer.Streams.Stdout.File)
-				renderer.Streams.Println(diff)
+		if renderer.Summary {
+			renderHumanChangesSummary(renderer, changes)
+		} else {
+			for _, change := range changes {
+				diff, render := renderHumanDiff(renderer, change, proposedChange)
+				if render {
+					fmt.Fprintln(renderer.Streams.Stdout.File)
+					renderer.Streams.Println(diff)
+				}
 			}
 		}
 
@@ -274,7 +278,9 @@ func renderHumanDiffOutputs(renderer Renderer, outputs map[string]computed.Diff)
 	for _, key := range keys {
 		output := outputs[key]
 		if output.Action != plans.NoOp {
-			rendered = append(rendered, fmt.Sprintf("%s %-*s = %s", renderer.Colorize.Color(format.DiffActionSymbol(output.Action)), escapedKeyMaxLen, escapedKeys[key], output.RenderHuman(0, computed.NewRenderHumanOpts(renderer.Colorize))))
+			opts := computed.NewRenderHumanOpts(renderer.Colorize)
+			opts.FullStringDiff = renderer.FullStringDiff
+			rendered = append(rendered, fmt.Sprintf("%s %-*s = %s", renderer.Colorize.Color(format.DiffActionSymbol(output.Action)), escapedKeyMaxLen, escapedKeys[key], output.RenderHuman(0, opts)))
 		}
 	}
 	return strings.Join(rendered, "\n")
@@ -357,6 +363,8 @@ func renderHumanDiff(renderer Renderer, diff diff, cause string) (string, bool)
 	buf.WriteString(renderer.Colorize.Color(resourceChangeComment(diff.change, action, cause)))
 
 	opts := computed.NewRenderHumanOpts(renderer.Colorize)
+	opts.FullStringDiff = renderer.FullStringDiff
+	opts.SuppressAttributes = renderer.suppressedAttributes(diff.change.Type)
 
 	if action == plans.Forget {
 		opts.HideDiffActionSymbols = true
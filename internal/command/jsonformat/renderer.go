@@ -8,6 +8,7 @@ package jsonformat
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/mitchellh/colorstring"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
@@ -82,6 +83,46 @@ type Renderer struct {
 	Colorize *colorstring.Colorize
 
 	RunningInAutomation bool
+
+	// FullStringDiff renders changes to long strings by showing the full old
+	// and new values, instead of an inline diff.
+	FullStringDiff bool
+
+	// SuppressAttributes are patterns of resource attributes whose values
+	// are hidden in rendered changes. Each is either an attribute name, which
+	// matches in all resources, or a resource type and an attribute name
+	// separated by a dot.
+	SuppressAttributes []string
+
+	// Summary renders the planned changes grouped by module and resource
+	// type with the number of each kind of change, instead of rendering
+	// every change in full.
+	Summary bool
+
+	// SummaryExpand are patterns of the changes to render in full along
+	// with the summary. Each is a resource type, or the address of a module,
+	// resource or resource instance.
+	SummaryExpand []string
+}
+
+// suppressedAttributes returns the names of the attributes to hide in the
+// changes to resources of the given type, or nil if there are none.
+func (renderer Renderer) suppressedAttributes(resourceType string) map[string]bool {
+	var ret map[string]bool
+	for _, pattern := range renderer.SuppressAttributes {
+		name := pattern
+		if typeName, attr, ok := strings.Cut(pattern, "."); ok {
+			if typeName != resourceType {
+				continue
+			}
+			name = attr
+		}
+		if ret == nil {
+			ret = make(map[string]bool)
+		}
+		ret[name] = true
+	}
+	return ret
 }
 
 func (renderer Renderer) RenderHumanPlan(plan Plan, mode plans.Mode, opts ...plans.Quality) {
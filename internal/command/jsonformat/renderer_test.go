@@ -60,3 +60,20 @@ func TestIncompatibleVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestRendererSuppressedAttributes(t *testing.T) {
+	renderer := Renderer{
+		SuppressAttributes: []string{"etag", "aws_instance.user_data", "aws_s3_object.content"},
+	}
+
+	got := renderer.suppressedAttributes("aws_instance")
+	if len(got) != 2 || !got["etag"] || !got["user_data"] {
+		t.Errorf("wrong attributes for aws_instance: %v", got)
+	}
+	if got := renderer.suppressedAttributes("null_resource"); len(got) != 1 || !got["etag"] {
+		t.Errorf("wrong attributes for null_resource: %v", got)
+	}
+	if got := (Renderer{}).suppressedAttributes("aws_instance"); got != nil {
+		t.Errorf("unexpected attributes: %v", got)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// PlanReport is the human-readable description of a plan, split into parts
+// so that it can be arranged differently than in the normal plan output, such
+// as in the HTML report of "tofu show -format=html".
+type PlanReport struct {
+	// Changes has an element for each resource instance change that the
+	// normal plan output would describe, in the same order.
+	Changes []ResourceChangeReport
+
+	// Outputs describes the changes to the root module output values, or is
+	// empty if there are none.
+	Outputs string
+}
+
+// ResourceChangeReport describes the planned change for a single resource
+// instance.
+type ResourceChangeReport struct {
+	Address string
+	Action  plans.Action
+
+	// Moved and Importing are set if the change also moves the object to a
+	// new address or imports it, respectively.
+	Moved     bool
+	Importing bool
+
+	// Diff is the same description of the change as in the normal plan
+	// output, including the comment that explains the action, with any
+	// sensitive values redacted.
+	Diff string
+}
+
+// RenderPlanReport renders the given plan as a PlanReport, instead of writing
+// it to the renderer's streams.
+func (renderer Renderer) RenderPlanReport(plan Plan, mode plans.Mode) PlanReport {
+	var report PlanReport
+
+	diffs := precomputeDiffs(plan, mode)
+	for _, diff := range diffs.changes {
+		action := jsonplan.UnmarshalActions(diff.change.Change.Actions)
+		if action == plans.Delete && diff.change.Mode != jsonstate.ManagedResourceMode {
+			// Don't report anything for deleted data sources.
+			continue
+		}
+
+		text, render := renderHumanDiff(renderer, diff, proposedChange)
+		if !render {
+			continue
+		}
+
+		report.Changes = append(report.Changes, ResourceChangeReport{
+			Address:   diff.change.Address,
+			Action:    action,
+			Moved:     diff.Moved(),
+			Importing: diff.Importing(),
+			Diff:      text,
+		})
+	}
+
+	report.Outputs = renderHumanDiffOutputs(renderer, diffs.outputs)
+	return report
+}
@@ -0,0 +1,210 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// summaryCategory is one of the kinds of change counted for each group of
+// resource instances in a plan summary.
+type summaryCategory int
+
+const (
+	summaryImport summaryCategory = iota
+	summaryMove
+	summaryCreate
+	summaryUpdate
+	summaryReplace
+	summaryDelete
+	summaryRead
+	summaryForget
+	summaryCategoryCount
+)
+
+var summaryCategoryLabels = [summaryCategoryCount]string{
+	summaryImport:  "%d to import",
+	summaryMove:    "%d to move",
+	summaryCreate:  "%d to add",
+	summaryUpdate:  "%d to change",
+	summaryReplace: "[red]%d to replace[reset]",
+	summaryDelete:  "[red]%d to destroy[reset]",
+	summaryRead:    "%d to read",
+	summaryForget:  "%d to forget",
+}
+
+// summaryGroup counts the changes to the resource instances of one resource
+// type in one module.
+type summaryGroup struct {
+	module       string
+	resourceType string
+	counts       [summaryCategoryCount]int
+}
+
+// summaryCategories returns the kinds of change that the given change counts
+// as. Moves and imports are counted in addition to the action taken.
+func summaryCategories(diff diff) []summaryCategory {
+	var ret []summaryCategory
+	if diff.Importing() {
+		ret = append(ret, summaryImport)
+	}
+	if diff.Moved() {
+		ret = append(ret, summaryMove)
+	}
+	switch jsonplan.UnmarshalActions(diff.change.Change.Actions) {
+	case plans.Create:
+		ret = append(ret, summaryCreate)
+	case plans.Update:
+		ret = append(ret, summaryUpdate)
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		ret = append(ret, summaryReplace)
+	case plans.Delete:
+		ret = append(ret, summaryDelete)
+	case plans.Read:
+		ret = append(ret, summaryRead)
+	case plans.Forget:
+		ret = append(ret, summaryForget)
+	}
+	return ret
+}
+
+// summaryExpanded returns true if the full diff of the given change was
+// requested with one of the given patterns. A pattern matches a resource
+// type, or the address of a module, resource or resource instance that
+// contains the changed instance.
+func summaryExpanded(patterns []string, diff diff) bool {
+	addr := diff.change.Address
+	for _, pattern := range patterns {
+		if pattern == diff.change.Type || pattern == addr {
+			return true
+		}
+		if rest, ok := strings.CutPrefix(addr, pattern); ok && (strings.HasPrefix(rest, ".") || strings.HasPrefix(rest, "[")) {
+			return true
+		}
+	}
+	return false
+}
+
+// renderHumanChangesSummary renders the given resource changes grouped by
+// module and resource type, with the number of each kind of change in each
+// group, instead of rendering every change in full. The instances that will
+// be destroyed or replaced are still listed individually, and the full diffs
+// are rendered only for changes matching the renderer's SummaryExpand
+// patterns.
+func renderHumanChangesSummary(renderer Renderer, changes []diff) {
+	groups := make(map[[2]string]*summaryGroup)
+	var destroys, replaces, expanded []diff
+	for _, diff := range changes {
+		module := diff.change.ModuleAddress
+		resourceType := diff.change.Type
+		if diff.change.Mode == jsonstate.DataResourceMode {
+			resourceType = "data." + resourceType
+		}
+
+		key := [2]string{module, resourceType}
+		group, ok := groups[key]
+		if !ok {
+			group = &summaryGroup{module: module, resourceType: resourceType}
+			groups[key] = group
+		}
+		for _, category := range summaryCategories(diff) {
+			group.counts[category]++
+			switch category {
+			case summaryDelete:
+				destroys = append(destroys, diff)
+			case summaryReplace:
+				replaces = append(replaces, diff)
+			}
+		}
+
+		if summaryExpanded(renderer.SummaryExpand, diff) {
+			expanded = append(expanded, diff)
+		}
+	}
+
+	sorted := make([]*summaryGroup, 0, len(groups))
+	width := 0
+	for _, group := range groups {
+		sorted = append(sorted, group)
+		if len(group.resourceType) > width {
+			width = len(group.resourceType)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		// The root module has an empty address, so it sorts first.
+		if sorted[i].module != sorted[j].module {
+			return sorted[i].module < sorted[j].module
+		}
+		return sorted[i].resourceType < sorted[j].resourceType
+	})
+
+	var buf strings.Builder
+	module := "-"
+	for _, group := range sorted {
+		if group.module != module {
+			module = group.module
+			name := module
+			if name == "" {
+				name = "root module"
+			}
+			fmt.Fprintf(&buf, "\n  [bold]%s[reset]\n", name)
+		}
+
+		var counts []string
+		for category, count := range group.counts {
+			if count > 0 {
+				counts = append(counts, fmt.Sprintf(summaryCategoryLabels[category], count))
+			}
+		}
+		fmt.Fprintf(&buf, "    %-*s  %s\n", width, group.resourceType, strings.Join(counts, ", "))
+	}
+	renderer.Streams.Print(renderer.Colorize.Color(buf.String()))
+
+	renderSummaryList := func(title string, action plans.Action, diffs []diff) {
+		if len(diffs) == 0 {
+			return
+		}
+		renderer.Streams.Print(renderer.Colorize.Color(fmt.Sprintf("\n[bold]%s (%d):[reset]\n", title, len(diffs))))
+		for _, diff := range diffs {
+			addr := diff.change.Address
+			if len(diff.change.Deposed) != 0 {
+				addr = fmt.Sprintf("%s (deposed object %s)", addr, diff.change.Deposed)
+			}
+			renderer.Streams.Printf("%s %s\n", renderer.Colorize.Color(format.DiffActionSymbol(action)), addr)
+		}
+	}
+	renderSummaryList("Resource instances to destroy", plans.Delete, destroys)
+	renderSummaryList("Resource instances to replace", plans.DeleteThenCreate, replaces)
+
+	if len(renderer.SummaryExpand) == 0 {
+		renderer.Streams.Println(format.WordWrap(
+			"\nTo show the full changes for some of these resource instances, use the -summary-expand=PATTERN option, where PATTERN is a resource type or the address of a module, resource or resource instance.",
+			renderer.Streams.Stdout.Columns()))
+		return
+	}
+	if len(expanded) == 0 {
+		renderer.Streams.Println(format.WordWrap(
+			"\nNone of the planned changes match the -summary-expand patterns.",
+			renderer.Streams.Stdout.Columns()))
+		return
+	}
+
+	renderer.Streams.Println("\nFull changes for the resource instances matching -summary-expand:")
+	for _, change := range expanded {
+		diff, render := renderHumanDiff(renderer, change, proposedChange)
+		if render {
+			renderer.Streams.Println()
+			renderer.Streams.Println(diff)
+		}
+	}
+}
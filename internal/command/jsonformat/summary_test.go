@@ -0,0 +1,238 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonformat
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/colorstring"
+
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/terminal"
+)
+
+func TestRenderHuman_Summary(t *testing.T) {
+	color := &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true}
+
+	schemas := map[string]*jsonprovider.Provider{
+		"test": {
+			ResourceSchemas: map[string]*jsonprovider.Schema{
+				"test_instance": {
+					Block: &jsonprovider.Block{
+						Attributes: map[string]*jsonprovider.Attribute{
+							"id": {
+								AttributeType: marshalJson(t, "string"),
+							},
+						},
+					},
+				},
+				"test_network": {
+					Block: &jsonprovider.Block{
+						Attributes: map[string]*jsonprovider.Attribute{
+							"id": {
+								AttributeType: marshalJson(t, "string"),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	change := func(module, typeName, name string, actions ...string) jsonplan.ResourceChange {
+		addr := typeName + "." + name
+		if module != "" {
+			addr = module + "." + addr
+		}
+		var before, after map[string]interface{}
+		if actions[0] != "create" {
+			before = map[string]interface{}{"id": "old"}
+		}
+		if actions[0] != "delete" || len(actions) > 1 {
+			after = map[string]interface{}{"id": "new"}
+		}
+		return jsonplan.ResourceChange{
+			Address:       addr,
+			ModuleAddress: module,
+			Mode:          "managed",
+			Type:          typeName,
+			Name:          name,
+			ProviderName:  "test",
+			Change: jsonplan.Change{
+				Actions: actions,
+				Before:  marshalJson(t, before),
+				After:   marshalJson(t, after),
+			},
+		}
+	}
+
+	plan := Plan{
+		PlanFormatVersion:     jsonplan.FormatVersion,
+		ProviderFormatVersion: jsonprovider.FormatVersion,
+		ProviderSchemas:       schemas,
+		ResourceChanges: []jsonplan.ResourceChange{
+			change("", "test_instance", "a", "create"),
+			change("", "test_instance", "b", "create"),
+			change("", "test_instance", "c", "update"),
+			change("", "test_instance", "d", "delete"),
+			change("module.network", "test_network", "main", "delete", "create"),
+			change("module.network", "test_instance", "gateway", "create"),
+		},
+	}
+
+	tcs := map[string]struct {
+		expand []string
+		output string
+	}{
+		"summary only": {
+			output: `
+OpenTofu used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  + create
+  ~ update in-place
+  - destroy
+-/+ destroy and then create replacement
+
+OpenTofu will perform the following actions:
+
+  root module
+    test_instance  2 to add, 1 to change, 1 to destroy
+
+  module.network
+    test_instance  1 to add
+    test_network   1 to replace
+
+Resource instances to destroy (1):
+  - test_instance.d
+
+Resource instances to replace (1):
+-/+ module.network.test_network.main
+
+To show the full changes for some of these resource instances, use the
+-summary-expand=PATTERN option, where PATTERN is a resource type or the
+address of a module, resource or resource instance.
+
+Plan: 4 to add, 1 to change, 2 to destroy.
+`,
+		},
+		"expanded module": {
+			expand: []string{"module.network"},
+			output: `
+OpenTofu used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  + create
+  ~ update in-place
+  - destroy
+-/+ destroy and then create replacement
+
+OpenTofu will perform the following actions:
+
+  root module
+    test_instance  2 to add, 1 to change, 1 to destroy
+
+  module.network
+    test_instance  1 to add
+    test_network   1 to replace
+
+Resource instances to destroy (1):
+  - test_instance.d
+
+Resource instances to replace (1):
+-/+ module.network.test_network.main
+
+Full changes for the resource instances matching -summary-expand:
+
+  # module.network.test_network.main must be replaced
+-/+ resource "test_network" "main" {
+      ~ id = "old" -> "new"
+    }
+
+  # module.network.test_instance.gateway will be created
+  + resource "test_instance" "gateway" {
+      + id = "new"
+    }
+
+Plan: 4 to add, 1 to change, 2 to destroy.
+`,
+		},
+		"nothing matches": {
+			expand: []string{"test_database"},
+			output: `
+OpenTofu used the selected providers to generate the following execution
+plan. Resource actions are indicated with the following symbols:
+  + create
+  ~ update in-place
+  - destroy
+-/+ destroy and then create replacement
+
+OpenTofu will perform the following actions:
+
+  root module
+    test_instance  2 to add, 1 to change, 1 to destroy
+
+  module.network
+    test_instance  1 to add
+    test_network   1 to replace
+
+Resource instances to destroy (1):
+  - test_instance.d
+
+Resource instances to replace (1):
+-/+ module.network.test_network.main
+
+None of the planned changes match the -summary-expand patterns.
+
+Plan: 4 to add, 1 to change, 2 to destroy.
+`,
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+
+			renderer := Renderer{
+				Colorize:      color,
+				Streams:       streams,
+				Summary:       true,
+				SummaryExpand: tc.expand,
+			}
+			plan.renderHuman(renderer, plans.NormalMode)
+
+			got := done(t).Stdout()
+			if diff := cmp.Diff(tc.output, got); len(diff) > 0 {
+				t.Errorf("unexpected output\ngot:\n%s\nwant:\n%s\ndiff:\n%s", got, tc.output, diff)
+			}
+		})
+	}
+}
+
+func TestSummaryExpanded(t *testing.T) {
+	d := diff{
+		change: jsonplan.ResourceChange{
+			Address: `module.network["a"].test_instance.web[0]`,
+			Type:    "test_instance",
+		},
+	}
+
+	for pattern, want := range map[string]bool{
+		"test_instance":                             true,
+		"module.network":                            true,
+		`module.network["a"]`:                       true,
+		`module.network["a"].test_instance.web`:     true,
+		`module.network["a"].test_instance.web[0]`:  true,
+		`module.network["a"].test_instance.web[1]`:  false,
+		"module.net":                                false,
+		"test_network":                              false,
+		`module.network["a"].test_instance.website`: false,
+	} {
+		if got := summaryExpanded([]string{pattern}, d); got != want {
+			t.Errorf("wrong result for %q: got %t, want %t", pattern, got, want)
+		}
+	}
+}
@@ -54,14 +54,37 @@ func newFunctions() *functions {
 }
 
 func Marshal(f map[string]function.Function) ([]byte, tfdiags.Diagnostics) {
-	var diags tfdiags.Diagnostics
 	signatures := newFunctions()
 
+	var diags tfdiags.Diagnostics
+	signatures.Signatures, diags = MarshalSignatures(f)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ret, err := json.Marshal(signatures)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to serialize functions",
+			err.Error(),
+		))
+		return nil, diags
+	}
+	return ret, nil
+}
+
+// MarshalSignatures returns the signatures of the given functions, for
+// callers that embed them in a larger JSON document.
+func MarshalSignatures(f map[string]function.Function) (map[string]*FunctionSignature, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	signatures := make(map[string]*FunctionSignature, len(f))
+
 	for name, v := range f {
 		if name == "can" || name == lang.CoreNamespace+"can" {
-			signatures.Signatures[name] = marshalCan(v)
+			signatures[name] = marshalCan(v)
 		} else if name == "try" || name == lang.CoreNamespace+"try" {
-			signatures.Signatures[name] = marshalTry(v)
+			signatures[name] = marshalTry(v)
 		} else {
 			signature, err := marshalFunction(v)
 			if err != nil {
@@ -71,24 +94,11 @@ func Marshal(f map[string]function.Function) ([]byte, tfdiags.Diagnostics) {
 					err.Error(),
 				))
 			}
-			signatures.Signatures[name] = signature
+			signatures[name] = signature
 		}
 	}
 
-	if diags.HasErrors() {
-		return nil, diags
-	}
-
-	ret, err := json.Marshal(signatures)
-	if err != nil {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"Failed to serialize functions",
-			err.Error(),
-		))
-		return nil, diags
-	}
-	return ret, nil
+	return signatures, diags
 }
 
 func marshalFunction(f function.Function) (*FunctionSignature, error) {
@@ -188,21 +188,7 @@ func MarshalForRenderer(
 		return nil, nil, nil, nil, err
 	}
 
-	if len(p.DriftedResources) > 0 {
-		// In refresh-only mode, we render all resources marked as drifted,
-		// including those which have moved without other changes. In other plan
-		// modes, move-only changes will be included in the planned changes, so
-		// we skip them here.
-		var driftedResources []*plans.ResourceInstanceChangeSrc
-		if p.UIMode == plans.RefreshOnlyMode {
-			driftedResources = p.DriftedResources
-		} else {
-			for _, dr := range p.DriftedResources {
-				if dr.Action != plans.NoOp {
-					driftedResources = append(driftedResources, dr)
-				}
-			}
-		}
+	if driftedResources := reportedDrift(p); len(driftedResources) > 0 {
 		output.ResourceDrift, err = MarshalResourceChanges(driftedResources, schemas)
 		if err != nil {
 			return nil, nil, nil, nil, err
@@ -216,6 +202,25 @@ func MarshalForRenderer(
 	return output.OutputChanges, output.ResourceChanges, output.ResourceDrift, output.RelevantAttributes, nil
 }
 
+// reportedDrift returns the drifted resources of the given plan that are
+// included in the JSON plan.
+func reportedDrift(p *plans.Plan) []*plans.ResourceInstanceChangeSrc {
+	// In refresh-only mode, we render all resources marked as drifted,
+	// including those which have moved without other changes. In other plan
+	// modes, move-only changes will be included in the planned changes, so
+	// we skip them here.
+	if p.UIMode == plans.RefreshOnlyMode {
+		return p.DriftedResources
+	}
+	var driftedResources []*plans.ResourceInstanceChangeSrc
+	for _, dr := range p.DriftedResources {
+		if dr.Action != plans.NoOp {
+			driftedResources = append(driftedResources, dr)
+		}
+	}
+	return driftedResources
+}
+
 // MarshalForLog returns the original JSON compatible plan, ready for a logging
 // package to marshal further.
 func MarshalForLog(
@@ -241,21 +246,7 @@ func MarshalForLog(
 	}
 
 	// output.ResourceDrift
-	if len(p.DriftedResources) > 0 {
-		// In refresh-only mode, we render all resources marked as drifted,
-		// including those which have moved without other changes. In other plan
-		// modes, move-only changes will be included in the planned changes, so
-		// we skip them here.
-		var driftedResources []*plans.ResourceInstanceChangeSrc
-		if p.UIMode == plans.RefreshOnlyMode {
-			driftedResources = p.DriftedResources
-		} else {
-			for _, dr := range p.DriftedResources {
-				if dr.Action != plans.NoOp {
-					driftedResources = append(driftedResources, dr)
-				}
-			}
-		}
+	if driftedResources := reportedDrift(p); len(driftedResources) > 0 {
 		output.ResourceDrift, err = MarshalResourceChanges(driftedResources, schemas)
 		if err != nil {
 			return nil, fmt.Errorf("error in marshaling resource drift: %w", err)
@@ -379,6 +370,22 @@ func (p *Plan) marshalPlanVariables(vars map[string]plans.DynamicValue, decls ma
 func MarshalResourceChanges(resources []*plans.ResourceInstanceChangeSrc, schemas *tofu.Schemas) ([]ResourceChange, error) {
 	var ret []ResourceChange
 
+	for _, rc := range sortResourceChanges(resources) {
+		r, err := marshalResourceChange(rc, schemas)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			ret = append(ret, *r)
+		}
+	}
+
+	return ret, nil
+}
+
+// sortResourceChanges returns a copy of the given changes in the order that
+// they appear in the JSON plan.
+func sortResourceChanges(resources []*plans.ResourceInstanceChangeSrc) []*plans.ResourceInstanceChangeSrc {
 	var sortedResources []*plans.ResourceInstanceChangeSrc
 	sortedResources = append(sortedResources, resources...)
 	sort.Slice(sortedResources, func(i, j int) bool {
@@ -387,179 +394,179 @@ func MarshalResourceChanges(resources []*plans.ResourceInstanceChangeSrc, schema
 		}
 		return sortedResources[i].DeposedKey < sortedResources[j].DeposedKey
 	})
+	return sortedResources
+}
 
-	for _, rc := range sortedResources {
-		var r ResourceChange
-		addr := rc.Addr
-		r.Address = addr.String()
-		if !addr.Equal(rc.PrevRunAddr) {
-			r.PreviousAddress = rc.PrevRunAddr.String()
-		}
+// marshalResourceChange returns the JSON representation of a single resource
+// instance change, or nil if the change is omitted from the JSON plan.
+func marshalResourceChange(rc *plans.ResourceInstanceChangeSrc, schemas *tofu.Schemas) (*ResourceChange, error) {
+	var r ResourceChange
+	addr := rc.Addr
+	r.Address = addr.String()
+	if !addr.Equal(rc.PrevRunAddr) {
+		r.PreviousAddress = rc.PrevRunAddr.String()
+	}
+
+	dataSource := addr.Resource.Resource.Mode == addrs.DataResourceMode
+	// We create "delete" actions for data resources so we can clean up
+	// their entries in state, but this is an implementation detail that
+	// users shouldn't see.
+	if dataSource && rc.Action == plans.Delete {
+		return nil, nil
+	}
 
-		dataSource := addr.Resource.Resource.Mode == addrs.DataResourceMode
-		// We create "delete" actions for data resources so we can clean up
-		// their entries in state, but this is an implementation detail that
-		// users shouldn't see.
-		if dataSource && rc.Action == plans.Delete {
-			continue
-		}
+	schema, _ := schemas.ResourceTypeConfig(
+		rc.ProviderAddr.Provider,
+		addr.Resource.Resource.Mode,
+		addr.Resource.Resource.Type,
+	)
+	if schema == nil {
+		return nil, fmt.Errorf("no schema found for %s (in provider %s)", r.Address, rc.ProviderAddr.Provider)
+	}
 
-		schema, _ := schemas.ResourceTypeConfig(
-			rc.ProviderAddr.Provider,
-			addr.Resource.Resource.Mode,
-			addr.Resource.Resource.Type,
-		)
-		if schema == nil {
-			return nil, fmt.Errorf("no schema found for %s (in provider %s)", r.Address, rc.ProviderAddr.Provider)
-		}
+	changeV, err := rc.Decode(schema.ImpliedType())
+	if err != nil {
+		return nil, err
+	}
+	// We drop the marks from the change, as decoding is only an
+	// intermediate step to re-encode the values as json
+	changeV.Before, _ = changeV.Before.UnmarkDeep()
+	changeV.After, _ = changeV.After.UnmarkDeep()
 
-		changeV, err := rc.Decode(schema.ImpliedType())
+	var before, after []byte
+	var beforeSensitive, afterSensitive []byte
+	var afterUnknown cty.Value
+
+	if changeV.Before != cty.NilVal {
+		before, err = ctyjson.Marshal(changeV.Before, changeV.Before.Type())
 		if err != nil {
 			return nil, err
 		}
-		// We drop the marks from the change, as decoding is only an
-		// intermediate step to re-encode the values as json
-		changeV.Before, _ = changeV.Before.UnmarkDeep()
-		changeV.After, _ = changeV.After.UnmarkDeep()
-
-		var before, after []byte
-		var beforeSensitive, afterSensitive []byte
-		var afterUnknown cty.Value
-
-		if changeV.Before != cty.NilVal {
-			before, err = ctyjson.Marshal(changeV.Before, changeV.Before.Type())
-			if err != nil {
-				return nil, err
-			}
-			marks := rc.BeforeValMarks
-			if schema.ContainsSensitive() {
-				marks = append(marks, schema.ValueMarks(changeV.Before, nil)...)
-			}
-			bs := jsonstate.SensitiveAsBoolWithPathValueMarks(changeV.Before, marks)
-			beforeSensitive, err = ctyjson.Marshal(bs, bs.Type())
+		marks := rc.BeforeValMarks
+		if schema.ContainsSensitive() {
+			marks = append(marks, schema.ValueMarks(changeV.Before, nil)...)
+		}
+		bs := jsonstate.SensitiveAsBoolWithPathValueMarks(changeV.Before, marks)
+		beforeSensitive, err = ctyjson.Marshal(bs, bs.Type())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if changeV.After != cty.NilVal {
+		if changeV.After.IsWhollyKnown() {
+			after, err = ctyjson.Marshal(changeV.After, changeV.After.Type())
 			if err != nil {
 				return nil, err
 			}
-		}
-		if changeV.After != cty.NilVal {
-			if changeV.After.IsWhollyKnown() {
-				after, err = ctyjson.Marshal(changeV.After, changeV.After.Type())
+			afterUnknown = cty.EmptyObjectVal
+		} else {
+			filteredAfter := omitUnknowns(changeV.After)
+			if filteredAfter.IsNull() {
+				after = nil
+			} else {
+				after, err = ctyjson.Marshal(filteredAfter, filteredAfter.Type())
 				if err != nil {
 					return nil, err
 				}
-				afterUnknown = cty.EmptyObjectVal
-			} else {
-				filteredAfter := omitUnknowns(changeV.After)
-				if filteredAfter.IsNull() {
-					after = nil
-				} else {
-					after, err = ctyjson.Marshal(filteredAfter, filteredAfter.Type())
-					if err != nil {
-						return nil, err
-					}
-				}
-				afterUnknown = unknownAsBool(changeV.After)
-			}
-			marks := rc.AfterValMarks
-			if schema.ContainsSensitive() {
-				marks = append(marks, schema.ValueMarks(changeV.After, nil)...)
-			}
-			as := jsonstate.SensitiveAsBoolWithPathValueMarks(changeV.After, marks)
-			afterSensitive, err = ctyjson.Marshal(as, as.Type())
-			if err != nil {
-				return nil, err
 			}
+			afterUnknown = unknownAsBool(changeV.After)
 		}
-
-		a, err := ctyjson.Marshal(afterUnknown, afterUnknown.Type())
-		if err != nil {
-			return nil, err
+		marks := rc.AfterValMarks
+		if schema.ContainsSensitive() {
+			marks = append(marks, schema.ValueMarks(changeV.After, nil)...)
 		}
-		replacePaths, err := encodePaths(rc.RequiredReplace)
+		as := jsonstate.SensitiveAsBoolWithPathValueMarks(changeV.After, marks)
+		afterSensitive, err = ctyjson.Marshal(as, as.Type())
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		var importing *Importing
-		if rc.Importing != nil {
-			importing = &Importing{ID: rc.Importing.ID}
-		}
+	a, err := ctyjson.Marshal(afterUnknown, afterUnknown.Type())
+	if err != nil {
+		return nil, err
+	}
+	replacePaths, err := encodePaths(rc.RequiredReplace)
+	if err != nil {
+		return nil, err
+	}
 
-		r.Change = Change{
-			Actions:         actionString(rc.Action.String()),
-			Before:          json.RawMessage(before),
-			After:           json.RawMessage(after),
-			AfterUnknown:    a,
-			BeforeSensitive: json.RawMessage(beforeSensitive),
-			AfterSensitive:  json.RawMessage(afterSensitive),
-			ReplacePaths:    replacePaths,
-			Importing:       importing,
-			GeneratedConfig: rc.GeneratedConfig,
-		}
+	var importing *Importing
+	if rc.Importing != nil {
+		importing = &Importing{ID: rc.Importing.ID}
+	}
 
-		if rc.DeposedKey != states.NotDeposed {
-			r.Deposed = rc.DeposedKey.String()
-		}
+	r.Change = Change{
+		Actions:         actionString(rc.Action.String()),
+		Before:          json.RawMessage(before),
+		After:           json.RawMessage(after),
+		AfterUnknown:    a,
+		BeforeSensitive: json.RawMessage(beforeSensitive),
+		AfterSensitive:  json.RawMessage(afterSensitive),
+		ReplacePaths:    replacePaths,
+		Importing:       importing,
+		GeneratedConfig: rc.GeneratedConfig,
+	}
 
-		key := addr.Resource.Key
-		if key != nil {
-			value := key.Value()
-			if r.Index, err = ctyjson.Marshal(value, value.Type()); err != nil {
-				return nil, err
-			}
-		}
+	if rc.DeposedKey != states.NotDeposed {
+		r.Deposed = rc.DeposedKey.String()
+	}
 
-		switch addr.Resource.Resource.Mode {
-		case addrs.ManagedResourceMode:
-			r.Mode = jsonstate.ManagedResourceMode
-		case addrs.DataResourceMode:
-			r.Mode = jsonstate.DataResourceMode
-		default:
-			return nil, fmt.Errorf("resource %s has an unsupported mode %s", r.Address, addr.Resource.Resource.Mode.String())
-		}
-		r.ModuleAddress = addr.Module.String()
-		r.Name = addr.Resource.Resource.Name
-		r.Type = addr.Resource.Resource.Type
-		r.ProviderName = rc.ProviderAddr.Provider.String()
-
-		switch rc.ActionReason {
-		case plans.ResourceInstanceChangeNoReason:
-			r.ActionReason = "" // will be omitted in output
-		case plans.ResourceInstanceReplaceBecauseCannotUpdate:
-			r.ActionReason = ResourceInstanceReplaceBecauseCannotUpdate
-		case plans.ResourceInstanceReplaceBecauseTainted:
-			r.ActionReason = ResourceInstanceReplaceBecauseTainted
-		case plans.ResourceInstanceReplaceByRequest:
-			r.ActionReason = ResourceInstanceReplaceByRequest
-		case plans.ResourceInstanceReplaceByTriggers:
-			r.ActionReason = ResourceInstanceReplaceByTriggers
-		case plans.ResourceInstanceDeleteBecauseNoResourceConfig:
-			r.ActionReason = ResourceInstanceDeleteBecauseNoResourceConfig
-		case plans.ResourceInstanceDeleteBecauseWrongRepetition:
-			r.ActionReason = ResourceInstanceDeleteBecauseWrongRepetition
-		case plans.ResourceInstanceDeleteBecauseCountIndex:
-			r.ActionReason = ResourceInstanceDeleteBecauseCountIndex
-		case plans.ResourceInstanceDeleteBecauseEachKey:
-			r.ActionReason = ResourceInstanceDeleteBecauseEachKey
-		case plans.ResourceInstanceDeleteBecauseNoModule:
-			r.ActionReason = ResourceInstanceDeleteBecauseNoModule
-		case plans.ResourceInstanceDeleteBecauseNoMoveTarget:
-			r.ActionReason = ResourceInstanceDeleteBecauseNoMoveTarget
-		case plans.ResourceInstanceReadBecauseConfigUnknown:
-			r.ActionReason = ResourceInstanceReadBecauseConfigUnknown
-		case plans.ResourceInstanceReadBecauseDependencyPending:
-			r.ActionReason = ResourceInstanceReadBecauseDependencyPending
-		case plans.ResourceInstanceReadBecauseCheckNested:
-			r.ActionReason = ResourceInstanceReadBecauseCheckNested
-		default:
-			return nil, fmt.Errorf("resource %s has an unsupported action reason %s", r.Address, rc.ActionReason)
+	key := addr.Resource.Key
+	if key != nil {
+		value := key.Value()
+		if r.Index, err = ctyjson.Marshal(value, value.Type()); err != nil {
+			return nil, err
 		}
+	}
 
-		ret = append(ret, r)
-
+	switch addr.Resource.Resource.Mode {
+	case addrs.ManagedResourceMode:
+		r.Mode = jsonstate.ManagedResourceMode
+	case addrs.DataResourceMode:
+		r.Mode = jsonstate.DataResourceMode
+	default:
+		return nil, fmt.Errorf("resource %s has an unsupported mode %s", r.Address, addr.Resource.Resource.Mode.String())
+	}
+	r.ModuleAddress = addr.Module.String()
+	r.Name = addr.Resource.Resource.Name
+	r.Type = addr.Resource.Resource.Type
+	r.ProviderName = rc.ProviderAddr.Provider.String()
+
+	switch rc.ActionReason {
+	case plans.ResourceInstanceChangeNoReason:
+		r.ActionReason = "" // will be omitted in output
+	case plans.ResourceInstanceReplaceBecauseCannotUpdate:
+		r.ActionReason = ResourceInstanceReplaceBecauseCannotUpdate
+	case plans.ResourceInstanceReplaceBecauseTainted:
+		r.ActionReason = ResourceInstanceReplaceBecauseTainted
+	case plans.ResourceInstanceReplaceByRequest:
+		r.ActionReason = ResourceInstanceReplaceByRequest
+	case plans.ResourceInstanceReplaceByTriggers:
+		r.ActionReason = ResourceInstanceReplaceByTriggers
+	case plans.ResourceInstanceDeleteBecauseNoResourceConfig:
+		r.ActionReason = ResourceInstanceDeleteBecauseNoResourceConfig
+	case plans.ResourceInstanceDeleteBecauseWrongRepetition:
+		r.ActionReason = ResourceInstanceDeleteBecauseWrongRepetition
+	case plans.ResourceInstanceDeleteBecauseCountIndex:
+		r.ActionReason = ResourceInstanceDeleteBecauseCountIndex
+	case plans.ResourceInstanceDeleteBecauseEachKey:
+		r.ActionReason = ResourceInstanceDeleteBecauseEachKey
+	case plans.ResourceInstanceDeleteBecauseNoModule:
+		r.ActionReason = ResourceInstanceDeleteBecauseNoModule
+	case plans.ResourceInstanceDeleteBecauseNoMoveTarget:
+		r.ActionReason = ResourceInstanceDeleteBecauseNoMoveTarget
+	case plans.ResourceInstanceReadBecauseConfigUnknown:
+		r.ActionReason = ResourceInstanceReadBecauseConfigUnknown
+	case plans.ResourceInstanceReadBecauseDependencyPending:
+		r.ActionReason = ResourceInstanceReadBecauseDependencyPending
+	case plans.ResourceInstanceReadBecauseCheckNested:
+		r.ActionReason = ResourceInstanceReadBecauseCheckNested
+	default:
+		return nil, fmt.Errorf("resource %s has an unsupported action reason %s", r.Address, rc.ActionReason)
 	}
 
-	return ret, nil
+	return &r, nil
 }
 
 // MarshalOutputChanges converts the provided internal representation of
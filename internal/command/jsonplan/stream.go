@@ -0,0 +1,197 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/command/jsonchecks"
+	"github.com/opentofu/opentofu/internal/command/jsonconfig"
+	"github.com/opentofu/opentofu/internal/command/jsonstate"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tofu"
+	"github.com/opentofu/opentofu/version"
+)
+
+// MarshalTo writes the same JSON encoding of a tofu plan as Marshal to the
+// given writer, followed by a newline.
+//
+// Rather than building the whole representation in memory first, it writes
+// each part of the plan as soon as it has been encoded. In particular, each
+// resource change is encoded and written on its own, so the memory needed
+// for the resource changes and the resource drift doesn't grow with the
+// size of the plan.
+//
+// If an error is returned then part of the plan may already have been
+// written.
+func MarshalTo(
+	w io.Writer,
+	config *configs.Config,
+	p *plans.Plan,
+	sf *statefile.File,
+	schemas *tofu.Schemas,
+) error {
+	bw := bufio.NewWriter(w)
+	s := &planStreamer{w: bw}
+
+	// The fields are written in the same order as the fields of Plan, and
+	// with the same omitempty rules, so that the result is identical to
+	// that of Marshal.
+	s.field("format_version", FormatVersion)
+	s.field("terraform_version", version.String())
+
+	// The smaller parts are built in a Plan in the usual way, but each is
+	// written and then discarded before the next is built.
+	part := newPlan()
+	if err := part.marshalPlanVariables(p.VariableValues, config.Module.Variables); err != nil {
+		return fmt.Errorf("error in marshalPlanVariables: %w", err)
+	}
+	if len(part.Variables) > 0 {
+		s.field("variables", part.Variables)
+	}
+
+	part = newPlan()
+	if err := part.marshalPlannedValues(p.Changes, schemas); err != nil {
+		return fmt.Errorf("error in marshalPlannedValues: %w", err)
+	}
+	s.field("planned_values", part.PlannedValues)
+
+	if err := s.resourceChanges("resource_drift", reportedDrift(p), schemas); err != nil {
+		return fmt.Errorf("error in marshaling resource drift: %w", err)
+	}
+	if p.Changes != nil {
+		if err := s.resourceChanges("resource_changes", p.Changes.Resources, schemas); err != nil {
+			return fmt.Errorf("error in marshaling resource changes: %w", err)
+		}
+	}
+
+	outputChanges, err := MarshalOutputChanges(p.Changes)
+	if err != nil {
+		return fmt.Errorf("error in marshaling output changes: %w", err)
+	}
+	if len(outputChanges) > 0 {
+		s.field("output_changes", outputChanges)
+	}
+
+	if sf != nil && !sf.State.Empty() {
+		priorState, err := jsonstate.Marshal(sf, schemas)
+		if err != nil {
+			return fmt.Errorf("error marshaling prior state: %w", err)
+		}
+		s.field("prior_state", json.RawMessage(priorState))
+	}
+
+	configJSON, err := jsonconfig.Marshal(config, schemas)
+	if err != nil {
+		return fmt.Errorf("error marshaling config: %w", err)
+	}
+	if len(configJSON) > 0 {
+		s.field("configuration", json.RawMessage(configJSON))
+	}
+
+	part = newPlan()
+	if err := part.marshalRelevantAttrs(p); err != nil {
+		return fmt.Errorf("error marshaling relevant attributes for external changes: %w", err)
+	}
+	if len(part.RelevantAttributes) > 0 {
+		s.field("relevant_attributes", part.RelevantAttributes)
+	}
+
+	if p.Checks != nil && p.Checks.ConfigResults.Len() > 0 {
+		if checks := jsonchecks.MarshalCheckStates(p.Checks); len(checks) > 0 {
+			s.field("checks", json.RawMessage(checks))
+		}
+	}
+
+	s.field("timestamp", p.Timestamp.Format(time.RFC3339))
+	s.field("errored", p.Errored)
+
+	s.write([]byte("}\n"))
+	if s.err != nil {
+		return s.err
+	}
+	return bw.Flush()
+}
+
+// planStreamer writes the fields of a JSON object one at a time, keeping
+// the first error that occurs so that the caller only needs to check once.
+type planStreamer struct {
+	w      io.Writer
+	fields int
+	err    error
+}
+
+func (s *planStreamer) write(b []byte) {
+	if s.err == nil {
+		_, s.err = s.w.Write(b)
+	}
+}
+
+// key writes the separator before the next field of the object, or the
+// opening brace before the first, and then the given key.
+func (s *planStreamer) key(name string) {
+	if s.fields == 0 {
+		s.write([]byte("{"))
+	} else {
+		s.write([]byte(","))
+	}
+	s.fields++
+	s.value(name)
+	s.write([]byte(":"))
+}
+
+// value writes the JSON encoding of the given value, which is encoded with
+// json.Marshal so that it's escaped in the same way as by Marshal.
+func (s *planStreamer) value(v interface{}) {
+	if s.err != nil {
+		return
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		s.err = err
+		return
+	}
+	s.write(raw)
+}
+
+func (s *planStreamer) field(name string, v interface{}) {
+	s.key(name)
+	s.value(v)
+}
+
+// resourceChanges writes the given resource changes as an array, encoding
+// each one only when it's about to be written. The field is omitted if
+// there are no changes to include.
+func (s *planStreamer) resourceChanges(name string, resources []*plans.ResourceInstanceChangeSrc, schemas *tofu.Schemas) error {
+	written := 0
+	for _, rc := range sortResourceChanges(resources) {
+		r, err := marshalResourceChange(rc, schemas)
+		if err != nil {
+			return err
+		}
+		if r == nil {
+			continue
+		}
+		if written == 0 {
+			s.key(name)
+			s.write([]byte("["))
+		} else {
+			s.write([]byte(","))
+		}
+		written++
+		s.value(r)
+	}
+	if written > 0 {
+		s.write([]byte("]"))
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonplan
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func TestMarshalTo(t *testing.T) {
+	change := func(addr string, action plans.Action, before, after cty.Value) *plans.ResourceInstanceChangeSrc {
+		t.Helper()
+		ty := cty.Object(map[string]cty.Type{
+			"woozles": cty.String,
+			"foozles": cty.String,
+		})
+		b, err := plans.NewDynamicValue(before, ty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, err := plans.NewDynamicValue(after, ty)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &plans.ResourceInstanceChangeSrc{
+			Addr:        mustAddr(addr),
+			PrevRunAddr: mustAddr(addr),
+			ProviderAddr: addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+			ChangeSrc: plans.ChangeSrc{
+				Action: action,
+				Before: b,
+				After:  a,
+			},
+		}
+	}
+	obj := func(woozles, foozles string) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"woozles": cty.StringVal(woozles),
+			"foozles": cty.StringVal(foozles),
+		})
+	}
+	null := cty.NullVal(cty.Object(map[string]cty.Type{
+		"woozles": cty.String,
+		"foozles": cty.String,
+	}))
+
+	tests := map[string]*plans.Plan{
+		"empty": {
+			Changes: plans.NewChanges(),
+		},
+		"changes and drift": {
+			Changes: &plans.Changes{
+				Resources: []*plans.ResourceInstanceChangeSrc{
+					change("test_thing.b", plans.Update, obj("a", "<b>"), obj("a", "&c")),
+					change("test_thing.a", plans.Create, null, obj("x", "y")),
+				},
+			},
+			DriftedResources: []*plans.ResourceInstanceChangeSrc{
+				change("test_thing.b", plans.Update, obj("a", "b"), obj("a", "<b>")),
+				change("test_thing.c", plans.NoOp, obj("a", "b"), obj("a", "b")),
+			},
+			Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			Errored:   true,
+		},
+		"only move-only drift": {
+			Changes: plans.NewChanges(),
+			DriftedResources: []*plans.ResourceInstanceChangeSrc{
+				change("test_thing.c", plans.NoOp, obj("a", "b"), obj("a", "b")),
+			},
+		},
+	}
+
+	for name, plan := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := configs.NewEmptyConfig()
+			config.Module.ProviderRequirements = &configs.RequiredProviders{}
+			config.Module.Variables = map[string]*configs.Variable{
+				"greeting": {Name: "greeting", Default: cty.StringVal("<hello>")},
+			}
+			want, err := Marshal(config, plan, nil, testSchemas())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got bytes.Buffer
+			if err := MarshalTo(&got, config, plan, nil, testSchemas()); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(want)+"\n", got.String()); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package jsonschema contains the published JSON schemas for OpenTofu's
+// machine-readable plan, state and UI formats, and implements validation of
+// documents against them.
+package jsonschema
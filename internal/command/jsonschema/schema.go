@@ -0,0 +1,132 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonschema
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Format identifies one of OpenTofu's machine-readable output formats.
+type Format string
+
+const (
+	// Plan is the format of "tofu show -json" for a saved plan.
+	Plan Format = "plan"
+
+	// State is the format of "tofu show -json" for a state snapshot.
+	State Format = "state"
+
+	// UI is the format of the stream of messages that commands such as
+	// "tofu plan -json" produce, with one JSON object per line.
+	UI Format = "ui"
+)
+
+// Formats are all of the formats that have published schemas.
+var Formats = []Format{Plan, State, UI}
+
+// LatestVersion is the major version of the JSON formats that OpenTofu
+// produces when no other version is selected with -json-version.
+const LatestVersion = 1
+
+// supportedVersions are the major versions of the JSON formats that this
+// version of OpenTofu can produce. Each of them must have a schema for each
+// of the formats.
+var supportedVersions = []int{1}
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// SupportedVersions returns the major versions of the JSON formats that this
+// version of OpenTofu can produce, in increasing order.
+func SupportedVersions() []int {
+	return append([]int(nil), supportedVersions...)
+}
+
+// ParseVersion parses a major version given with the -json-version option.
+// An empty string selects LatestVersion.
+func ParseVersion(raw string) (int, error) {
+	if raw == "" {
+		return LatestVersion, nil
+	}
+	version, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a whole number", raw)
+	}
+	for _, supported := range supportedVersions {
+		if version == supported {
+			return version, nil
+		}
+	}
+	return 0, fmt.Errorf("version %d is not supported by this version of OpenTofu, which supports %s", version, versionList())
+}
+
+func versionList() string {
+	names := make([]string, len(supportedVersions))
+	for i, version := range supportedVersions {
+		names[i] = strconv.Itoa(version)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Schema returns the JSON schema document for the given major version of
+// the given format.
+func Schema(format Format, version int) ([]byte, error) {
+	src, err := schemaFiles.ReadFile(fmt.Sprintf("schemas/%s-v%d.json", format, version))
+	if err != nil {
+		return nil, fmt.Errorf("there is no schema for version %d of the %s format", version, format)
+	}
+	return src, nil
+}
+
+// Validate checks that the given document conforms to the schema for the
+// given major version of the given format. Documents in the UI format
+// contain one message per line, and each message is validated separately.
+//
+// The returned error lists every problem found, each prefixed with the
+// location of the problem within the document.
+func Validate(format Format, version int, doc []byte) error {
+	src, err := Schema(format, version)
+	if err != nil {
+		return err
+	}
+	s, err := parseSchema(src)
+	if err != nil {
+		return fmt.Errorf("invalid schema for version %d of the %s format: %w", version, format, err)
+	}
+
+	if format != UI {
+		return joinProblems(s.validateDocument(doc))
+	}
+
+	var problems []error
+	sc := bufio.NewScanner(bytes.NewReader(doc))
+	sc.Buffer(nil, 64*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		for _, problem := range s.validateDocument(sc.Bytes()) {
+			problems = append(problems, fmt.Errorf("line %d: %w", line, problem))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		problems = append(problems, err)
+	}
+	return joinProblems(problems)
+}
+
+func joinProblems(problems []error) error {
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.Join(problems...)
+}
@@ -0,0 +1,207 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonschema
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSchemas(t *testing.T) {
+	for _, version := range SupportedVersions() {
+		for _, format := range Formats {
+			src, err := Schema(format, version)
+			if err != nil {
+				t.Errorf("%s v%d: %s", format, version, err)
+				continue
+			}
+			if _, err := parseSchema(src); err != nil {
+				t.Errorf("%s v%d: invalid schema: %s", format, version, err)
+			}
+		}
+	}
+
+	if _, err := Schema(Plan, 0); err == nil {
+		t.Errorf("no error for an unsupported version")
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]struct {
+		Want int
+		Err  string
+	}{
+		"":    {LatestVersion, ``},
+		"1":   {1, ``},
+		"2":   {0, `version 2 is not supported by this version of OpenTofu, which supports 1`},
+		"0":   {0, `version 0 is not supported`},
+		"1.2": {0, `"1.2" is not a whole number`},
+	}
+
+	for raw, test := range tests {
+		t.Run(raw, func(t *testing.T) {
+			got, err := ParseVersion(raw)
+			if test.Err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %v\nwant: message containing %q", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != test.Want {
+				t.Errorf("wrong version %d; want %d", got, test.Want)
+			}
+		})
+	}
+}
+
+// TestValidate_golden checks that the expected outputs of the show command
+// tests conform to the published schemas.
+func TestValidate_golden(t *testing.T) {
+	tests := map[Format][]string{
+		Plan: {
+			"../testdata/show-json/*/output.json",
+			"../testdata/show-json-sensitive/output.json",
+		},
+		State: {
+			"../testdata/show-json-state/*/output.json",
+		},
+	}
+
+	for format, patterns := range tests {
+		for _, pattern := range patterns {
+			files, err := filepath.Glob(pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) == 0 {
+				t.Fatalf("no files match %s", pattern)
+			}
+			for _, file := range files {
+				t.Run(file, func(t *testing.T) {
+					doc, err := os.ReadFile(file)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if err := Validate(format, LatestVersion, doc); err != nil {
+						t.Errorf("invalid %s document:\n%s", format, err)
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		Format Format
+		Doc    string
+		Errs   []string
+	}{
+		"valid state": {
+			State,
+			`{"format_version":"1.0","values":{"root_module":{"resources":[{"address":"a.b","mode":"managed","type":"a","name":"b","provider_name":"p","schema_version":0,"values":{}}]}}}`,
+			nil,
+		},
+		"unknown properties are allowed": {
+			State,
+			`{"format_version":"1.9","future":{"anything":true}}`,
+			nil,
+		},
+		"wrong major version": {
+			State,
+			`{"format_version":"2.0"}`,
+			[]string{`/format_version: must match the pattern "^1\\.[0-9]+$"`},
+		},
+		"missing and mistyped properties": {
+			State,
+			`{"values":{"root_module":{"resources":[{"address":"a.b","mode":"other","type":"a","name":1,"schema_version":-1}]}}}`,
+			[]string{
+				`/: missing required property "format_version"`,
+				`/values/root_module/resources/0: missing required property "provider_name"`,
+				`/values/root_module/resources/0/mode: must be one of "managed", "data"`,
+				`/values/root_module/resources/0/name: must be string, not number`,
+				`/values/root_module/resources/0/schema_version: must be at least 0`,
+			},
+		},
+		"not an object": {
+			Plan,
+			`[]`,
+			[]string{`/: must be object, not array`},
+		},
+		"invalid JSON": {
+			Plan,
+			`{"format_version":`,
+			[]string{`invalid JSON`},
+		},
+		"trailing data": {
+			Plan,
+			`{"format_version":"1.2","errored":false} {}`,
+			[]string{`unexpected data after the top-level value`},
+		},
+		"invalid plan actions": {
+			Plan,
+			`{"format_version":"1.2","errored":false,"resource_changes":[{"address":"a.b","mode":"managed","type":"a","name":"b","change":{"actions":["explode"]}}]}`,
+			[]string{`/resource_changes/0/change/actions/0: must be one of`},
+		},
+		"valid UI stream": {
+			UI,
+			`{"@level":"info","@message":"OpenTofu 1.9.0","@module":"tofu.ui","@timestamp":"2024-01-01T00:00:00Z","type":"version","tofu":"1.9.0","ui":"1.5"}
+
+{"@level":"info","@message":"Apply complete!","@module":"tofu.ui","@timestamp":"2024-01-01T00:00:00Z","type":"change_summary","changes":{"add":1,"change":0,"import":0,"remove":0,"operation":"apply"}}
+{"@level":"info","@message":"untyped","@module":"tofu.ui","@timestamp":"2024-01-01T00:00:00Z"}
+`,
+			nil,
+		},
+		"invalid UI messages": {
+			UI,
+			`{"@level":"info","@message":"OpenTofu 1.9.0","@module":"tofu.ui","@timestamp":"2024-01-01T00:00:00Z","type":"version","tofu":"1.9.0"}
+{"@level":"loud","@message":"Error","@module":"tofu.ui","@timestamp":"2024-01-01T00:00:00Z","type":"diagnostic","diagnostic":{"severity":"fatal","summary":"x","detail":""}}
+`,
+			[]string{
+				`line 1: /: missing required property "ui"`,
+				`line 2: /@level: must be one of`,
+				`line 2: /diagnostic/severity: must be one of "error", "warning"`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := Validate(test.Format, LatestVersion, []byte(test.Doc))
+			if len(test.Errs) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("succeeded; want error")
+			}
+			got := strings.Split(err.Error(), "\n")
+			if len(got) != len(test.Errs) {
+				t.Fatalf("wrong number of problems\ngot:\n%s\nwant: %q", err, test.Errs)
+			}
+			for i, want := range test.Errs {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("wrong problem %d\ngot:  %s\nwant: message containing %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSchema_unsupportedKeyword(t *testing.T) {
+	if _, err := parseSchema([]byte(`{"type":"object","oneOf":[true]}`)); err == nil || !strings.Contains(err.Error(), `unknown field "oneOf"`) {
+		t.Errorf("wrong error for an unsupported keyword: %v", err)
+	}
+	if _, err := parseSchema([]byte(`{"$ref":"#/$defs/missing"}`)); err == nil || !strings.Contains(err.Error(), `undefined "#/$defs/missing"`) {
+		t.Errorf("wrong error for an undefined reference: %v", err)
+	}
+}
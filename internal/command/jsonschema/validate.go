@@ -0,0 +1,349 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package jsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// schema is a JSON schema using the subset of the 2020-12 draft that the
+// published schemas need. Parsing a schema that uses any other keyword
+// fails, so that a schema can't silently accept documents because of a
+// keyword that's ignored.
+type schema struct {
+	// never is set for the boolean schema false, which rejects any value.
+	// The boolean schema true is an empty schema.
+	never bool
+
+	Schema      string             `json:"$schema"`
+	ID          string             `json:"$id"`
+	Ref         string             `json:"$ref"`
+	Defs        map[string]*schema `json:"$defs"`
+	Title       string             `json:"title"`
+	Description string             `json:"description"`
+
+	Type                 schemaTypes        `json:"type"`
+	Enum                 []interface{}      `json:"enum"`
+	Const                *json.RawMessage   `json:"const"`
+	Pattern              string             `json:"pattern"`
+	Minimum              *float64           `json:"minimum"`
+	Properties           map[string]*schema `json:"properties"`
+	Required             []string           `json:"required"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	AllOf                []*schema          `json:"allOf"`
+	AnyOf                []*schema          `json:"anyOf"`
+	If                   *schema            `json:"if"`
+	Then                 *schema            `json:"then"`
+
+	pattern *regexp.Regexp
+	root    *schema
+}
+
+// schemaTypes is the value of the type keyword, which can be either a single
+// type name or a list of them.
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(src []byte) error {
+	var one string
+	if err := json.Unmarshal(src, &one); err == nil {
+		*t = schemaTypes{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(src, &many); err != nil {
+		return fmt.Errorf("type must be a string or a list of strings")
+	}
+	*t = many
+	return nil
+}
+
+func (s *schema) UnmarshalJSON(src []byte) error {
+	switch string(bytes.TrimSpace(src)) {
+	case "true":
+		*s = schema{}
+		return nil
+	case "false":
+		*s = schema{never: true}
+		return nil
+	}
+
+	// The alias type has the same fields but not this method, so decoding
+	// into it doesn't recurse.
+	type rawSchema schema
+	dec := json.NewDecoder(bytes.NewReader(src))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode((*rawSchema)(s)); err != nil {
+		return err
+	}
+	if s.Pattern != "" {
+		re, err := regexp.Compile(s.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", s.Pattern, err)
+		}
+		s.pattern = re
+	}
+	return nil
+}
+
+// parseSchema parses a schema document and checks that all of the
+// references within it can be resolved.
+func parseSchema(src []byte) (*schema, error) {
+	var root schema
+	if err := json.Unmarshal(src, &root); err != nil {
+		return nil, err
+	}
+
+	var check func(s *schema) error
+	check = func(s *schema) error {
+		if s == nil {
+			return nil
+		}
+		s.root = &root
+		if s.Ref != "" {
+			if _, err := root.resolve(s.Ref); err != nil {
+				return err
+			}
+		}
+		var children []*schema
+		for _, child := range s.Defs {
+			children = append(children, child)
+		}
+		for _, child := range s.Properties {
+			children = append(children, child)
+		}
+		children = append(children, s.AdditionalProperties, s.Items, s.If, s.Then)
+		children = append(children, s.AllOf...)
+		children = append(children, s.AnyOf...)
+		for _, child := range children {
+			if err := check(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := check(&root); err != nil {
+		return nil, err
+	}
+	return &root, nil
+}
+
+// resolve returns the definition that the given reference refers to. Only
+// references to the definitions of the same document are supported.
+func (s *schema) resolve(ref string) (*schema, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported reference %q", ref)
+	}
+	def, ok := s.Defs[name]
+	if !ok {
+		return nil, fmt.Errorf("reference to undefined %q", ref)
+	}
+	return def, nil
+}
+
+// validateDocument parses the given JSON document and returns the problems
+// with it according to the schema.
+func (s *schema) validateDocument(doc []byte) []error {
+	dec := json.NewDecoder(bytes.NewReader(doc))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return []error{fmt.Errorf("invalid JSON: %w", err)}
+	}
+	if dec.More() {
+		return []error{fmt.Errorf("invalid JSON: unexpected data after the top-level value")}
+	}
+
+	var problems []error
+	s.validate(v, "", &problems)
+	return problems
+}
+
+// validate appends to problems an error for each way in which v doesn't
+// conform to the schema, using path as the JSON pointer to v.
+func (s *schema) validate(v interface{}, path string, problems *[]error) {
+	report := func(format string, args ...interface{}) {
+		location := path
+		if location == "" {
+			location = "/"
+		}
+		*problems = append(*problems, fmt.Errorf("%s: %s", location, fmt.Sprintf(format, args...)))
+	}
+
+	if s.never {
+		report("no value is allowed here")
+		return
+	}
+	if s.Ref != "" {
+		def, err := s.root.resolve(s.Ref)
+		if err != nil {
+			report("%s", err)
+			return
+		}
+		def.validate(v, path, problems)
+	}
+
+	if len(s.Type) > 0 && !typeMatches(s.Type, v) {
+		report("must be %s, not %s", strings.Join(s.Type, " or "), typeName(v))
+		// The other keywords would only report the same problem again.
+		return
+	}
+	if s.Enum != nil {
+		found := false
+		for _, allowed := range s.Enum {
+			if jsonEqual(allowed, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			report("must be one of %s", jsonList(s.Enum))
+		}
+	}
+	if s.Const != nil {
+		var want interface{}
+		if err := json.Unmarshal(*s.Const, &want); err == nil && !jsonEqual(want, v) {
+			report("must be %s", string(*s.Const))
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		if s.pattern != nil && !s.pattern.MatchString(v) {
+			report("must match the pattern %q", s.Pattern)
+		}
+	case json.Number:
+		if s.Minimum != nil {
+			if f, err := v.Float64(); err == nil && f < *s.Minimum {
+				report("must be at least %v", *s.Minimum)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := v[name]; !ok {
+				report("missing required property %q", name)
+			}
+		}
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			propPath := path + "/" + escapePointer(name)
+			if prop, ok := s.Properties[name]; ok {
+				prop.validate(v[name], propPath, problems)
+			} else if s.AdditionalProperties != nil {
+				if s.AdditionalProperties.never {
+					report("unexpected property %q", name)
+				} else {
+					s.AdditionalProperties.validate(v[name], propPath, problems)
+				}
+			}
+		}
+	case []interface{}:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(item, fmt.Sprintf("%s/%d", path, i), problems)
+			}
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		sub.validate(v, path, problems)
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			var subProblems []error
+			sub.validate(v, path, &subProblems)
+			if len(subProblems) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			report("doesn't match any of the allowed forms")
+		}
+	}
+	if s.If != nil && s.Then != nil {
+		var ifProblems []error
+		s.If.validate(v, path, &ifProblems)
+		if len(ifProblems) == 0 {
+			s.Then.validate(v, path, problems)
+		}
+	}
+}
+
+func typeMatches(types []string, v interface{}) bool {
+	for _, typ := range types {
+		switch typ {
+		case "integer":
+			if n, ok := v.(json.Number); ok {
+				if _, err := n.Int64(); err == nil {
+					return true
+				}
+			}
+		case typeName(v):
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// jsonEqual compares a value from a schema with a value from a document,
+// which has numbers represented as json.Number rather than float64.
+func jsonEqual(want, got interface{}) bool {
+	src, err := json.Marshal(got)
+	if err != nil {
+		return false
+	}
+	var normalized interface{}
+	if err := json.Unmarshal(src, &normalized); err != nil {
+		return false
+	}
+	return reflect.DeepEqual(want, normalized)
+}
+
+func jsonList(values []interface{}) string {
+	names := make([]string, len(values))
+	for i, v := range values {
+		src, _ := json.Marshal(v)
+		names[i] = string(src)
+	}
+	return strings.Join(names, ", ")
+}
+
+// escapePointer escapes a property name for use in a JSON pointer.
+func escapePointer(name string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(name)
+}
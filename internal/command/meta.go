@@ -28,6 +28,7 @@ import (
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/backend/local"
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
 	"github.com/opentofu/opentofu/internal/command/format"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/command/webbrowser"
@@ -36,6 +37,7 @@ import (
 	"github.com/opentofu/opentofu/internal/configs/configload"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	legacy "github.com/opentofu/opentofu/internal/legacy/tofu"
+	"github.com/opentofu/opentofu/internal/policy"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/provisioners"
 	"github.com/opentofu/opentofu/internal/states"
@@ -124,6 +126,33 @@ type Meta struct {
 	// longer any compelling reasons for folks to not lock their dependencies.
 	PluginCacheMayBreakDependencyLockFile bool
 
+	// ProviderProcessReuse allows a provider plugin process to be reused by
+	// several instances of the same provider at once, if they haven't been
+	// configured or have identical configurations. It has no effect unless
+	// the command calls startProviderPool.
+	ProviderProcessReuse bool
+
+	// OperationTimeout is the default limit on how long each call to a
+	// provider to read, create, update or delete a resource instance may
+	// take. Zero means there is no limit.
+	OperationTimeout time.Duration
+
+	// AuditLog, if set, records an entry for each command that may change
+	// the state to the sinks it configures.
+	AuditLog *cliconfig.ConfigAuditLog
+
+	// Policy, if set, configures the policies that each plan is evaluated
+	// against before it is applied.
+	Policy *cliconfig.ConfigPolicy
+
+	// PolicyChecks are the external programs that each plan is checked
+	// with, in addition to any Policy.
+	PolicyChecks []*cliconfig.ConfigPolicyCheck
+
+	// Notifications are the webhooks that are notified when plans and
+	// applies start and finish.
+	Notifications []*cliconfig.ConfigNotifications
+
 	// ProviderSource allows determining the available versions of a provider
 	// and determines where a distribution package for a particular
 	// provider version can be obtained.
@@ -202,6 +231,18 @@ type Meta struct {
 	// It is initialized on first use.
 	configLoader *configload.Loader
 
+	// nodeTimings records the time spent executing each graph node when
+	// profiling with the -profile option.
+	nodeTimings *tofu.NodeTimings
+
+	// planCache reuses the responses of providers from the previous plan
+	// when planning with the -incremental option.
+	planCache *tofu.PlanCache
+
+	// providerPool keeps provider plugin processes running between the
+	// graph walks of a single command. It is initialized on first use.
+	providerPool *providerPool
+
 	// backendState is the currently active backend state
 	backendState *legacy.BackendState
 
@@ -217,6 +258,11 @@ type Meta struct {
 	color bool
 	oldUi cli.Ui
 
+	// overrideSet is the name of the set of environment-scoped override
+	// files to load, as given with the -override-set option. If it's empty
+	// then the OverrideSetEnvVar environment variable is used instead.
+	overrideSet string
+
 	// The fields below are expected to be set by the command via
 	// command line flags. See the Apply command for an example.
 	//
@@ -335,13 +381,58 @@ func (m *Meta) DataDir() string {
 	return m.WorkingDir.DataDir()
 }
 
+// snapshotDir returns the directory where apply saves snapshots of the
+// prior state for the given workspace, for use by "tofu rollback".
+func (m *Meta) snapshotDir(workspace string) string {
+	return filepath.Join(m.DataDir(), "snapshots", workspace)
+}
+
+// policyCheckers returns the checkers for the policies in the CLI
+// configuration that each plan is checked against.
+func (m *Meta) policyCheckers() []policy.Checker {
+	var ret []policy.Checker
+	if m.Policy != nil {
+		ret = append(ret, &policy.OPA{
+			Paths:   m.Policy.Paths,
+			Query:   m.Policy.Query,
+			Command: m.Policy.Command,
+		})
+	}
+	for _, check := range m.PolicyChecks {
+		ret = append(ret, &policy.Plugin{
+			Name:    check.Name,
+			Command: check.Command,
+			Args:    check.Args,
+		})
+	}
+	return ret
+}
+
 const (
 	// InputModeEnvVar is the environment variable that, if set to "false" or
 	// "0", causes tofu commands to behave as if the `-input=false` flag was
 	// specified.
 	InputModeEnvVar = "TF_INPUT"
+
+	// OverrideSetEnvVar is the environment variable that selects the set of
+	// environment-scoped override files to load when the `-override-set`
+	// option is not specified.
+	OverrideSetEnvVar = "TF_OVERRIDE_SET"
 )
 
+// OverrideSet returns the name of the set of environment-scoped override
+// files to load, or an empty string if none should be loaded.
+func (m *Meta) OverrideSet() (string, error) {
+	set := m.overrideSet
+	if set == "" {
+		set = os.Getenv(OverrideSetEnvVar)
+	}
+	if set != "" && !configs.ValidOverrideSetName(set) {
+		return "", fmt.Errorf("Invalid override set name %q: override set names must contain only letters, digits, underscores, and dashes", set)
+	}
+	return set, nil
+}
+
 // InputMode returns the type of input we should ask for in the form of
 // tofu.InputMode which is passed directly to Context.Input.
 func (m *Meta) InputMode() tofu.InputMode {
@@ -493,6 +584,7 @@ func (m *Meta) RunOperation(b backend.Enhanced, opReq *backend.Operation) (*back
 		return nil, diags
 	}
 
+	start := time.Now()
 	op, err := b.Operation(context.Background(), opReq)
 	if err != nil {
 		return nil, diags.Append(fmt.Errorf("error starting operation: %w", err))
@@ -530,6 +622,11 @@ func (m *Meta) RunOperation(b backend.Enhanced, opReq *backend.Operation) (*back
 	case <-op.Done():
 		// operation completed normally
 	}
+	recordOperationDuration(opReq, op.Result, start)
+
+	// The operation has finished with its providers, so we don't need to
+	// keep their processes running any longer.
+	m.closeProviderPool()
 
 	return op, diags
 }
@@ -546,6 +643,9 @@ func (m *Meta) contextOpts() (*tofu.ContextOpts, error) {
 
 	opts.UIInput = m.UIInput()
 	opts.Parallelism = m.parallelism
+	opts.NodeTimings = m.nodeTimings
+	opts.PlanCache = m.planCache
+	opts.OperationTimeout = m.OperationTimeout
 
 	// If testingOverrides are set, we'll skip the plugin discovery process
 	// and just work with what we've been given, thus allowing the tests
@@ -621,7 +721,8 @@ func (m *Meta) extendedFlagSet(n string) *flag.FlagSet {
 	return f
 }
 
-// process will process any -no-color entries out of the arguments. This
+// process will process any -no-color and -override-set entries out of the
+// arguments. This
 // will potentially modify the args in-place. It will return the resulting
 // slice, and update the Meta and Ui.
 func (m *Meta) process(args []string) []string {
@@ -631,6 +732,8 @@ func (m *Meta) process(args []string) []string {
 		m.Ui = m.oldUi
 	}
 
+	args = m.processOverrideSet(args)
+
 	// Set colorization
 	m.color = m.Color
 	i := 0 // output index
@@ -669,6 +772,24 @@ func (m *Meta) process(args []string) []string {
 	return args
 }
 
+// processOverrideSet will process any -override-set entries out of the
+// arguments, for commands that parse their arguments without calling
+// process. This will potentially modify the args in-place. It will return
+// the resulting slice.
+func (m *Meta) processOverrideSet(args []string) []string {
+	i := 0 // output index
+	for _, v := range args {
+		if set, ok := strings.CutPrefix(v, "-override-set="); ok {
+			m.overrideSet = set
+		} else {
+			// copy and increment index
+			args[i] = v
+			i++
+		}
+	}
+	return args[:i]
+}
+
 // uiHook returns the UiHook to use with the context.
 func (m *Meta) uiHook() *views.UiHook {
 	return views.NewUiHook(m.View)
@@ -823,10 +944,15 @@ func (m *Meta) SetWorkspace(name string) error {
 	return nil
 }
 
-// isAutoVarFile determines if the file ends with .auto.tfvars or .auto.tfvars.json
+// isAutoVarFile determines if the file ends with .auto.tfvars or .auto.tfvars.json,
+// or with .auto.tfvars.sops and a single extension, such as .auto.tfvars.sops.yaml
 func isAutoVarFile(path string) bool {
-	return strings.HasSuffix(path, ".auto.tfvars") ||
-		strings.HasSuffix(path, ".auto.tfvars.json")
+	if strings.HasSuffix(path, ".auto.tfvars") || strings.HasSuffix(path, ".auto.tfvars.json") {
+		return true
+	}
+	const sopsInfix = ".auto.tfvars.sops."
+	i := strings.LastIndex(path, sopsInfix)
+	return i >= 0 && !strings.Contains(path[i+len(sopsInfix):], ".")
 }
 
 // FIXME: as an interim refactoring step, we apply the contents of the state
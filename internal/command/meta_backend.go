@@ -20,11 +20,11 @@ import (
 	"strings"
 
 	"github.com/hashicorp/hcl/v2"
-	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/backend/secretref"
 	"github.com/opentofu/opentofu/internal/cloud"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/clistate"
@@ -323,6 +323,12 @@ func (m *Meta) BackendForLocalPlan(settings plans.Backend, enc encryption.StateE
 		return nil, diags
 	}
 
+	configVal, resolveDiags := secretref.Resolve(context.TODO(), configVal)
+	diags = diags.Append(resolveDiags)
+	if resolveDiags.HasErrors() {
+		return nil, diags
+	}
+
 	newVal, validateDiags := b.PrepareConfig(configVal)
 	diags = diags.Append(validateDiags)
 	if validateDiags.HasErrors() {
@@ -618,11 +624,11 @@ func (m *Meta) backendFromConfig(opts *BackendOpts, enc encryption.StateEncrypti
 
 		initReason := fmt.Sprintf("Unsetting the previously set backend %q", s.Backend.Type)
 		if !opts.Init {
-			diags = diags.Append(tfdiags.Sourceless(
+			diags = diags.Append(tfdiags.WithCode(tfdiags.Sourceless(
 				tfdiags.Error,
 				"Backend initialization required, please run \"tofu init\"",
 				fmt.Sprintf(strings.TrimSpace(errBackendInit), initReason),
-			))
+			), tfdiags.CodeBackendInitRequired))
 			return nil, diags
 		}
 
@@ -639,18 +645,18 @@ func (m *Meta) backendFromConfig(opts *BackendOpts, enc encryption.StateEncrypti
 		if !opts.Init {
 			if c.Type == "cloud" {
 				initReason := "Initial configuration of cloud backend"
-				diags = diags.Append(tfdiags.Sourceless(
+				diags = diags.Append(tfdiags.WithCode(tfdiags.Sourceless(
 					tfdiags.Error,
 					"Cloud backend initialization required: please run \"tofu init\"",
 					fmt.Sprintf(strings.TrimSpace(errBackendInitCloud), initReason),
-				))
+				), tfdiags.CodeBackendInitRequired))
 			} else {
 				initReason := fmt.Sprintf("Initial configuration of the requested backend %q", c.Type)
-				diags = diags.Append(tfdiags.Sourceless(
+				diags = diags.Append(tfdiags.WithCode(tfdiags.Sourceless(
 					tfdiags.Error,
 					"Backend initialization required, please run \"tofu init\"",
 					fmt.Sprintf(strings.TrimSpace(errBackendInit), initReason),
-				))
+				), tfdiags.CodeBackendInitRequired))
 			}
 			return nil, diags
 		}
@@ -756,23 +762,23 @@ func (m *Meta) determineInitReason(previousBackendType string, currentBackendTyp
 	var diags tfdiags.Diagnostics
 	switch cloudMode {
 	case cloud.ConfigChangeInPlace:
-		diags = diags.Append(tfdiags.Sourceless(
+		diags = diags.Append(tfdiags.WithCode(tfdiags.Sourceless(
 			tfdiags.Error,
 			"Cloud backend initialization required: please run \"tofu init\"",
 			fmt.Sprintf(strings.TrimSpace(errBackendInitCloud), initReason),
-		))
+		), tfdiags.CodeBackendInitRequired))
 	case cloud.ConfigMigrationIn:
-		diags = diags.Append(tfdiags.Sourceless(
+		diags = diags.Append(tfdiags.WithCode(tfdiags.Sourceless(
 			tfdiags.Error,
 			"Cloud backend initialization required: please run \"tofu init\"",
 			fmt.Sprintf(strings.TrimSpace(errBackendInitCloud), initReason),
-		))
+		), tfdiags.CodeBackendInitRequired))
 	default:
-		diags = diags.Append(tfdiags.Sourceless(
+		diags = diags.Append(tfdiags.WithCode(tfdiags.Sourceless(
 			tfdiags.Error,
 			"Backend initialization required: please run \"tofu init\"",
 			fmt.Sprintf(strings.TrimSpace(errBackendInit), initReason),
-		))
+		), tfdiags.CodeBackendInitRequired))
 	}
 
 	return diags
@@ -831,6 +837,14 @@ func (m *Meta) backendFromState(ctx context.Context, enc encryption.StateEncrypt
 		return nil, diags
 	}
 
+	// The saved configuration keeps any secret references, which we resolve
+	// each time we configure the backend.
+	configVal, resolveDiags := secretref.Resolve(ctx, configVal)
+	diags = diags.Append(resolveDiags)
+	if resolveDiags.HasErrors() {
+		return nil, diags
+	}
+
 	// Validate the config and then configure the backend
 	newVal, validDiags := b.PrepareConfig(configVal)
 	diags = diags.Append(validDiags)
@@ -1288,6 +1302,14 @@ func (m *Meta) savedBackend(sMgr *clistate.LocalState, enc encryption.StateEncry
 		return nil, diags
 	}
 
+	// The saved configuration keeps any secret references, which we resolve
+	// each time we configure the backend.
+	configVal, resolveDiags := secretref.Resolve(context.TODO(), configVal)
+	diags = diags.Append(resolveDiags)
+	if resolveDiags.HasErrors() {
+		return nil, diags
+	}
+
 	// Validate the config and then configure the backend
 	newVal, validDiags := b.PrepareConfig(configVal)
 	diags = diags.Append(validDiags)
@@ -1362,9 +1384,11 @@ func (m *Meta) backendConfigNeedsMigration(c *configs.Backend, s *legacy.Backend
 	}
 	b := f(nil) // We don't need encryption here as it's only used for config/schema
 
+	// The configuration may refer to variables and locals, so we must
+	// decode it using the module's static evaluator just as we do when
+	// initializing the backend.
 	schema := b.ConfigSchema()
-	decSpec := schema.NoneRequired().DecoderSpec()
-	givenVal, diags := hcldec.Decode(c.Config, decSpec, nil)
+	givenVal, diags := c.Decode(schema.NoneRequired())
 	if diags.HasErrors() {
 		log.Printf("[TRACE] backendConfigNeedsMigration: failed to decode given config; migration codepath must handle problem: %s", diags.Error())
 		return true // let the migration codepath deal with these errors
@@ -1430,7 +1454,16 @@ func (m *Meta) backendInitFromConfig(c *configs.Backend, enc encryption.StateEnc
 		}
 	}
 
-	newVal, validateDiags := b.PrepareConfig(configVal)
+	// Secret references are resolved only for configuring the backend, so
+	// that the configuration we return to be saved in the working directory
+	// keeps the references instead of the secrets.
+	resolvedVal, resolveDiags := secretref.Resolve(context.TODO(), configVal)
+	diags = diags.Append(resolveDiags.InConfigBody(c.Config, ""))
+	if resolveDiags.HasErrors() {
+		return nil, cty.NilVal, diags
+	}
+
+	newVal, validateDiags := b.PrepareConfig(resolvedVal)
 	diags = diags.Append(validateDiags.InConfigBody(c.Config, ""))
 	if validateDiags.HasErrors() {
 		return nil, cty.NilVal, diags
@@ -7,6 +7,9 @@ package command
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
@@ -19,6 +22,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/copy"
 	"github.com/opentofu/opentofu/internal/encryption"
@@ -239,6 +243,38 @@ func TestMetaBackend_configureInterpolation(t *testing.T) {
 	}
 }
 
+// Reinitializing a backend whose configuration refers to variables
+func TestMetaBackend_configureInterpolationUnchanged(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("backend-new-interp"), td)
+	defer testChdir(t, td)()
+
+	// Setup the meta
+	m := testMetaBackend(t, nil)
+
+	// Initialize the backend
+	_, diags := m.Backend(&BackendOpts{Init: true}, encryption.StateEncryptionDisabled())
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+
+	c, _, diags := m.backendConfig(&BackendOpts{Init: true})
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+
+	sMgr := &clistate.LocalState{Path: filepath.Join(m.DataDir(), DefaultStateFilename)}
+	if err := sMgr.RefreshState(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The cached configuration holds the evaluated path, which must match
+	// the configuration that refers to the variable.
+	if m.backendConfigNeedsMigration(c, sMgr.State().Backend) {
+		t.Fatal("unchanged backend configuration requires migration")
+	}
+}
+
 // Newly configured backend
 func TestMetaBackend_configureNew(t *testing.T) {
 	td := t.TempDir()
@@ -302,6 +338,70 @@ func TestMetaBackend_configureNew(t *testing.T) {
 	}
 }
 
+// Newly configured backend with a secret reference in its configuration
+func TestMetaBackend_configureNewSecretReference(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("backend-secret-ref"), td)
+	defer testChdir(t, td)()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/tofu" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"data":{"state_path":"secret-state.tfstate"},"metadata":{"version":1}}}`)
+	}))
+	defer server.Close()
+	t.Setenv("BAO_ADDR", server.URL)
+	t.Setenv("BAO_TOKEN", "test-token")
+
+	// Setup the meta
+	m := testMetaBackend(t, nil)
+
+	// Get the backend
+	b, diags := m.Backend(&BackendOpts{Init: true}, encryption.StateEncryptionDisabled())
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+
+	// Write some state
+	s, err := b.StateMgr(backend.DefaultStateName)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	s.WriteState(states.NewState())
+	if err := s.PersistState(nil); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// Verify the state is at the path from the secret
+	if _, err := os.Stat("secret-state.tfstate"); err != nil {
+		t.Fatalf("state not written to the resolved path: %s", err)
+	}
+
+	// Verify the reference, not the secret, is saved in the working directory
+	saved, err := os.ReadFile(filepath.Join(DefaultDataDir, DefaultStateFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(saved), "vault:secret/data/tofu#state_path") {
+		t.Errorf("saved backend configuration doesn't contain the reference:\n%s", saved)
+	}
+	if strings.Contains(string(saved), "secret-state.tfstate") {
+		t.Errorf("saved backend configuration contains the secret:\n%s", saved)
+	}
+
+	// The saved configuration is resolved again when the backend is loaded
+	m = testMetaBackend(t, nil)
+	b, diags = m.Backend(&BackendOpts{}, encryption.StateEncryptionDisabled())
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if got, want := b.(*backendLocal.Local).StatePath, "secret-state.tfstate"; got != want {
+		t.Errorf("wrong state path %q; want %q", got, want)
+	}
+}
+
 // Newly configured backend with prior local state and no remote state
 func TestMetaBackend_configureNewWithState(t *testing.T) {
 	// Create a temporary working directory that is empty
@@ -421,14 +421,10 @@ func (m *Meta) registerSynthConfigSource(filename string, src []byte) {
 // terminate.
 func (m *Meta) initConfigLoader() (*configload.Loader, error) {
 	if m.configLoader == nil {
-		loader, err := configload.NewLoader(&configload.Config{
-			ModulesDir: m.modulesDir(),
-			Services:   m.Services,
-		})
+		loader, err := m.newConfigLoader(m.modulesDir())
 		if err != nil {
 			return nil, err
 		}
-		loader.AllowLanguageExperiments(m.AllowExperimentalFeatures)
 		m.configLoader = loader
 		if m.View != nil {
 			m.View.SetConfigSources(loader.Sources)
@@ -437,6 +433,25 @@ func (m *Meta) initConfigLoader() (*configload.Loader, error) {
 	return m.configLoader, nil
 }
 
+// newConfigLoader creates a configuration loader that finds the installed
+// modules in the given directory, with the settings from the receiver.
+func (m *Meta) newConfigLoader(modulesDir string) (*configload.Loader, error) {
+	loader, err := configload.NewLoader(&configload.Config{
+		ModulesDir: modulesDir,
+		Services:   m.Services,
+	})
+	if err != nil {
+		return nil, err
+	}
+	loader.AllowLanguageExperiments(m.AllowExperimentalFeatures)
+	overrideSet, err := m.OverrideSet()
+	if err != nil {
+		return nil, err
+	}
+	loader.SetOverrideSet(overrideSet)
+	return loader, nil
+}
+
 // registryClient instantiates and returns a new Registry client.
 func (m *Meta) registryClient() *registry.Client {
 	return registry.NewClient(m.Services, nil)
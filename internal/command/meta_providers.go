@@ -15,6 +15,7 @@ import (
 	"strings"
 
 	plugin "github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	terraformProvider "github.com/opentofu/opentofu/internal/builtin/providers/tf"
@@ -323,10 +324,10 @@ func (m *Meta) providerFactories() (map[addrs.Provider]providers.Factory, error)
 				continue
 			}
 		}
-		factories[provider] = providerFactory(cached)
+		factories[provider] = m.pooledProviderFactory(provider, providerFactory(cached))
 	}
 	for provider, localDir := range devOverrideProviders {
-		factories[provider] = devOverrideProviderFactory(provider, localDir)
+		factories[provider] = m.pooledProviderFactory(provider, devOverrideProviderFactory(provider, localDir))
 	}
 	for provider, reattach := range unmanagedProviders {
 		factories[provider] = unmanagedProviderFactory(provider, reattach)
@@ -347,6 +348,38 @@ func (m *Meta) internalProviders() map[string]providers.Factory {
 	}
 }
 
+// startProviderPool makes the provider factories created for the rest of the
+// current command keep their plugin processes running for reuse. A command
+// that calls it must defer a call to closeProviderPool, so that no processes
+// are left running once it returns.
+func (m *Meta) startProviderPool() {
+	if m.providerPool == nil {
+		m.providerPool = newProviderPool(m.ProviderProcessReuse)
+	}
+}
+
+// pooledProviderFactory wraps the given factory so that the plugin processes
+// it starts are kept running and reused across the graph walks of the current
+// command, if the command called startProviderPool.
+func (m *Meta) pooledProviderFactory(provider addrs.Provider, factory providers.Factory) providers.Factory {
+	if m.providerPool == nil {
+		return factory
+	}
+	return m.providerPool.Factory(provider, factory)
+}
+
+// closeProviderPool stops any provider plugin processes that are being kept
+// running for reuse. The pool remains usable, so it can be called after each
+// operation as well as when the command returns.
+func (m *Meta) closeProviderPool() {
+	if m.providerPool == nil {
+		return
+	}
+	if err := m.providerPool.Close(); err != nil {
+		log.Printf("[WARN] failed to stop idle provider processes: %s", err)
+	}
+}
+
 // providerFactory produces a provider factory that runs up the executable
 // file in the given cache package and uses go-plugin to implement
 // providers.Interface against it.
@@ -367,6 +400,9 @@ func providerFactory(meta *providercache.CachedProvider) providers.Factory {
 			VersionedPlugins: tfplugin.VersionedPlugins,
 			SyncStdout:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stdout", meta.Provider)),
 			SyncStderr:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stderr", meta.Provider)),
+			GRPCDialOptions: []grpc.DialOption{
+				grpc.WithUnaryInterceptor(providerMetricsInterceptor(meta.Provider)),
+			},
 		}
 
 		client := plugin.NewClient(config)
@@ -436,6 +472,9 @@ func unmanagedProviderFactory(provider addrs.Provider, reattach *plugin.Reattach
 			Reattach:         reattach,
 			SyncStdout:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stdout", provider)),
 			SyncStderr:       logging.PluginOutputMonitor(fmt.Sprintf("%s:stderr", provider)),
+			GRPCDialOptions: []grpc.DialOption{
+				grpc.WithUnaryInterceptor(providerMetricsInterceptor(provider)),
+			},
 		}
 
 		if reattach.ProtocolVersion == 0 {
@@ -0,0 +1,493 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"log"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+// providerPool keeps provider plugin processes running after OpenTofu Core
+// closes them, so that later graph walks in the same command can reuse them
+// instead of starting new processes. For example, "tofu apply" without a
+// saved plan validates, plans, and applies, and each of those walks would
+// otherwise start its own process for every provider.
+//
+// A process that has already been configured is only reused for a provider
+// configuration with exactly the same wholly-known configuration value,
+// because a provider can be configured only once. This means that two
+// aliases of the same provider with identical settings can share a process,
+// while aliases with different settings cannot.
+type providerPool struct {
+	// shared allows a process to be used by more than one provider instance
+	// at the same time, rather than only once the previous instance has
+	// closed it. Provider instances that haven't been configured then all
+	// share a single process per provider, and provider configurations with
+	// identical settings share a configured process.
+	shared bool
+
+	mu    sync.Mutex
+	procs map[addrs.Provider][]*pooledProcess
+}
+
+func newProviderPool(shared bool) *providerPool {
+	return &providerPool{
+		shared: shared,
+		procs:  make(map[addrs.Provider][]*pooledProcess),
+	}
+}
+
+// Factory wraps the given factory so that the provider instances it returns
+// start their processes only on first use, and return them to the pool when
+// they are closed.
+func (p *providerPool) Factory(addr addrs.Provider, factory providers.Factory) providers.Factory {
+	return func() (providers.Interface, error) {
+		return &pooledProvider{
+			pool:    p,
+			addr:    addr,
+			factory: factory,
+		}, nil
+	}
+}
+
+// Close kills all of the idle provider processes in the pool. The pool
+// remains usable afterwards, starting new processes as needed.
+func (p *providerPool) Close() error {
+	var idle []*pooledProcess
+	p.mu.Lock()
+	for addr, procs := range p.procs {
+		var inUse []*pooledProcess
+		for _, proc := range procs {
+			if proc.users == 0 {
+				idle = append(idle, proc)
+			} else {
+				inUse = append(inUse, proc)
+			}
+		}
+		p.procs[addr] = inUse
+	}
+	p.mu.Unlock()
+
+	var firstErr error
+	for _, proc := range idle {
+		log.Printf("[TRACE] providerPool: stopping idle process for %s", proc.addr)
+		if err := proc.provider.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// acquire returns a process for the given provider from the pool, or nil if
+// there isn't a suitable one. If config is not cty.NilVal then only a
+// process that was configured with that same value is returned. Otherwise, a
+// process that hasn't been configured is preferred over one that has.
+func (p *providerPool) acquire(addr addrs.Provider, config cty.Value) *pooledProcess {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var found *pooledProcess
+	for _, proc := range p.procs[addr] {
+		if !proc.reusable || (proc.users > 0 && !p.shared) {
+			continue
+		}
+		if config != cty.NilVal {
+			if proc.configured && proc.config.RawEquals(config) {
+				found = proc
+				break
+			}
+			continue
+		}
+		if !proc.configured {
+			found = proc
+			break
+		}
+		if found == nil {
+			found = proc
+		}
+	}
+	if found != nil {
+		found.users++
+	}
+	return found
+}
+
+// start starts a new process for the given provider and adds it to the
+// pool, in use by the caller.
+func (p *providerPool) start(addr addrs.Provider, factory providers.Factory) (*pooledProcess, error) {
+	provider, err := factory()
+	if err != nil {
+		return nil, err
+	}
+	proc := &pooledProcess{
+		addr:     addr,
+		provider: provider,
+		users:    1,
+		reusable: true,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.procs[addr] = append(p.procs[addr], proc)
+	return proc, nil
+}
+
+// release records that the caller has finished with the given process. It
+// stays in the pool for reuse, unless it can't be reused and nobody else is
+// using it, in which case it's killed.
+func (p *providerPool) release(proc *pooledProcess) error {
+	p.mu.Lock()
+	proc.users--
+	if proc.users > 0 || proc.reusable {
+		p.mu.Unlock()
+		return nil
+	}
+	procs := p.procs[proc.addr]
+	for i := range procs {
+		if procs[i] == proc {
+			p.procs[proc.addr] = append(procs[:i:i], procs[i+1:]...)
+			break
+		}
+	}
+	p.mu.Unlock()
+
+	return proc.provider.Close()
+}
+
+// claim marks the given process as about to be configured with the given
+// value by its only user. It returns false if the process can't be
+// configured by the caller, because it was configured already or is being
+// shared with other provider instances.
+func (p *providerPool) claim(proc *pooledProcess, config cty.Value) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if proc.configured || proc.users != 1 {
+		return false
+	}
+	proc.configured = true
+	proc.config = config
+	proc.ready = make(chan struct{})
+	// A process configured with a value we can't compare can't be matched
+	// by any later configuration.
+	proc.reusable = config != cty.NilVal
+	return true
+}
+
+// configured records the response from configuring a process that was
+// claimed with claim.
+func (p *providerPool) configured(proc *pooledProcess, resp providers.ConfigureProviderResponse) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proc.configResp = resp
+	if resp.Diagnostics.HasErrors() {
+		proc.reusable = false
+	}
+	close(proc.ready)
+}
+
+// configuredWith returns true if the given process was configured with the
+// given value and can still be used for it.
+func (p *providerPool) configuredWith(proc *pooledProcess, config cty.Value) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return config != cty.NilVal && proc.configured && proc.reusable && proc.config.RawEquals(config)
+}
+
+// discard prevents the given process from being handed out again.
+func (p *providerPool) discard(proc *pooledProcess) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	proc.reusable = false
+}
+
+// pooledProcess is a running provider plugin process in a providerPool. Its
+// fields other than provider are protected by the pool's mutex.
+type pooledProcess struct {
+	addr     addrs.Provider
+	provider providers.Interface
+
+	// users is the number of provider instances currently using the process.
+	users int
+
+	// reusable is false once the process has been stopped, or configured
+	// in a way that can't be matched again.
+	reusable bool
+
+	configured bool
+	config     cty.Value
+
+	// ready is closed once configResp has been set.
+	ready      chan struct{}
+	configResp providers.ConfigureProviderResponse
+}
+
+// pooledProvider is the providers.Interface returned by a providerPool
+// factory. It takes a process from the pool, or starts a new one, when it is
+// first used, and returns that process to the pool when it's closed.
+type pooledProvider struct {
+	pool    *providerPool
+	addr    addrs.Provider
+	factory providers.Factory
+
+	mu   sync.Mutex
+	proc *pooledProcess
+	err  error
+}
+
+var _ providers.Interface = (*pooledProvider)(nil)
+var _ providers.Wrapper = (*pooledProvider)(nil)
+
+// process returns the process for this provider instance, taking it from
+// the pool or starting it if necessary.
+func (p *pooledProvider) process() (*pooledProcess, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.proc != nil || p.err != nil {
+		return p.proc, p.err
+	}
+	if proc := p.pool.acquire(p.addr, cty.NilVal); proc != nil {
+		log.Printf("[TRACE] providerPool: reusing process for %s", p.addr)
+		p.proc = proc
+		return p.proc, nil
+	}
+	p.proc, p.err = p.pool.start(p.addr, p.factory)
+	return p.proc, p.err
+}
+
+// releaseProcess returns the process for this provider instance to the pool.
+// It must be called with p.mu held.
+func (p *pooledProvider) releaseProcess() error {
+	proc := p.proc
+	p.proc = nil
+	if proc == nil {
+		return nil
+	}
+	return p.pool.release(proc)
+}
+
+// UnwrapProvider returns the provider of the process for this provider
+// instance, so that callers can use the optional interfaces it implements.
+func (p *pooledProvider) UnwrapProvider() providers.Interface {
+	proc, err := p.process()
+	if err != nil {
+		return nil
+	}
+	return proc.provider
+}
+
+func (p *pooledProvider) GetProviderSchema() providers.GetProviderSchemaResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.GetProviderSchemaResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.GetProviderSchema()
+}
+
+func (p *pooledProvider) ValidateProviderConfig(req providers.ValidateProviderConfigRequest) providers.ValidateProviderConfigResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.ValidateProviderConfigResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.ValidateProviderConfig(req)
+}
+
+func (p *pooledProvider) ValidateResourceConfig(req providers.ValidateResourceConfigRequest) providers.ValidateResourceConfigResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.ValidateResourceConfigResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.ValidateResourceConfig(req)
+}
+
+func (p *pooledProvider) ValidateDataResourceConfig(req providers.ValidateDataResourceConfigRequest) providers.ValidateDataResourceConfigResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.ValidateDataResourceConfigResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.ValidateDataResourceConfig(req)
+}
+
+func (p *pooledProvider) UpgradeResourceState(req providers.UpgradeResourceStateRequest) providers.UpgradeResourceStateResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.UpgradeResourceStateResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.UpgradeResourceState(req)
+}
+
+// ConfigureProvider configures the process for this provider instance. If
+// another process in the pool was already configured with the same
+// configuration then that process is used instead, and the response from
+// configuring it is returned without calling the provider again.
+func (p *pooledProvider) ConfigureProvider(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	config := cty.NilVal
+	if req.Config.IsWhollyKnown() && !req.Config.ContainsMarked() {
+		config = req.Config
+	}
+
+	if p.proc != nil {
+		if p.pool.configuredWith(p.proc, config) {
+			<-p.proc.ready
+			return p.proc.configResp
+		}
+		if p.pool.claim(p.proc, config) {
+			return p.configure(req)
+		}
+		// The process we have is configured differently or is shared, so
+		// we need another one.
+		if err := p.releaseProcess(); err != nil {
+			log.Printf("[WARN] providerPool: failed to release process for %s: %s", p.addr, err)
+		}
+	}
+
+	if config != cty.NilVal {
+		if proc := p.pool.acquire(p.addr, config); proc != nil {
+			log.Printf("[TRACE] providerPool: reusing process for %s that has the same configuration", p.addr)
+			p.proc = proc
+			<-proc.ready
+			return proc.configResp
+		}
+	}
+
+	if proc := p.pool.acquire(p.addr, cty.NilVal); proc != nil {
+		p.proc = proc
+		if p.pool.claim(proc, config) {
+			return p.configure(req)
+		}
+		if err := p.releaseProcess(); err != nil {
+			log.Printf("[WARN] providerPool: failed to release process for %s: %s", p.addr, err)
+		}
+	}
+
+	p.proc, p.err = p.pool.start(p.addr, p.factory)
+	if p.err != nil {
+		var resp providers.ConfigureProviderResponse
+		resp.Diagnostics = resp.Diagnostics.Append(p.err)
+		return resp
+	}
+	p.pool.claim(p.proc, config)
+	return p.configure(req)
+}
+
+// configure calls the provider to configure a process that this provider
+// instance has claimed.
+func (p *pooledProvider) configure(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+	resp := p.proc.provider.ConfigureProvider(req)
+	p.pool.configured(p.proc, resp)
+	return resp
+}
+
+func (p *pooledProvider) Stop() error {
+	p.mu.Lock()
+	proc := p.proc
+	p.mu.Unlock()
+	if proc == nil {
+		return nil
+	}
+	// A stopped provider may have abandoned its in-flight work, so we don't
+	// hand it to anyone else.
+	p.pool.discard(proc)
+	return proc.provider.Stop()
+}
+
+func (p *pooledProvider) ReadResource(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.ReadResourceResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.ReadResource(req)
+}
+
+func (p *pooledProvider) PlanResourceChange(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.PlanResourceChangeResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.PlanResourceChange(req)
+}
+
+func (p *pooledProvider) ApplyResourceChange(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.ApplyResourceChangeResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.ApplyResourceChange(req)
+}
+
+func (p *pooledProvider) ImportResourceState(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.ImportResourceStateResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.ImportResourceState(req)
+}
+
+func (p *pooledProvider) ReadDataSource(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.ReadDataSourceResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.ReadDataSource(req)
+}
+
+func (p *pooledProvider) GetFunctions() providers.GetFunctionsResponse {
+	proc, err := p.process()
+	if err != nil {
+		var resp providers.GetFunctionsResponse
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+	return proc.provider.GetFunctions()
+}
+
+func (p *pooledProvider) CallFunction(req providers.CallFunctionRequest) providers.CallFunctionResponse {
+	proc, err := p.process()
+	if err != nil {
+		return providers.CallFunctionResponse{Error: err}
+	}
+	return proc.provider.CallFunction(req)
+}
+
+// Close returns the process for this provider instance to the pool.
+func (p *pooledProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.releaseProcess()
+}
@@ -0,0 +1,212 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func TestProviderPool(t *testing.T) {
+	addr := addrs.NewDefaultProvider("test")
+	var started []*tofu.MockProvider
+	pool := newProviderPool(false)
+	factory := pool.Factory(addr, func() (providers.Interface, error) {
+		p := testProvider()
+		started = append(started, p)
+		return p, nil
+	})
+	configure := func(p providers.Interface, region string) {
+		t.Helper()
+		resp := p.ConfigureProvider(providers.ConfigureProviderRequest{
+			Config: cty.ObjectVal(map[string]cty.Value{"region": cty.StringVal(region)}),
+		})
+		if resp.Diagnostics.HasErrors() {
+			t.Fatal(resp.Diagnostics.Err())
+		}
+	}
+	newProvider := func() providers.Interface {
+		t.Helper()
+		p, err := factory()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	// The first walk starts a process and configures it.
+	first := newProvider()
+	first.GetProviderSchema()
+	configure(first, "a")
+	if err := first.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(started) != 1 || started[0].CloseCalled {
+		t.Fatalf("expected one running process, got %d", len(started))
+	}
+
+	// A later walk with the same configuration reuses the configured
+	// process without configuring it again.
+	second := newProvider()
+	second.GetProviderSchema()
+	started[0].ConfigureProviderCalled = false
+	configure(second, "a")
+	if len(started) != 1 {
+		t.Fatalf("expected the process to be reused, but %d were started", len(started))
+	}
+	if started[0].ConfigureProviderCalled {
+		t.Fatal("reused process was configured again")
+	}
+
+	// A provider configuration with different settings can't use the
+	// configured process while it's busy, or after it's idle.
+	third := newProvider()
+	configure(third, "b")
+	if len(started) != 2 {
+		t.Fatalf("expected a new process for a different configuration, but %d were started", len(started))
+	}
+	second.Close()
+	third.Close()
+
+	fourth := newProvider()
+	configure(fourth, "c")
+	if len(started) != 3 {
+		t.Fatalf("expected a new process for a different configuration, but %d were started", len(started))
+	}
+	fourth.Close()
+
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range started {
+		if !p.CloseCalled {
+			t.Errorf("process %d was not stopped when the pool was closed", i)
+		}
+	}
+}
+
+func TestProviderPool_stopped(t *testing.T) {
+	addr := addrs.NewDefaultProvider("test")
+	var started []*tofu.MockProvider
+	pool := newProviderPool(false)
+	factory := pool.Factory(addr, func() (providers.Interface, error) {
+		p := testProvider()
+		started = append(started, p)
+		return p, nil
+	})
+
+	p, _ := factory()
+	p.GetProviderSchema()
+	if err := p.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+	if !started[0].CloseCalled {
+		t.Fatal("stopped process was returned to the pool")
+	}
+
+	p, _ = factory()
+	p.GetProviderSchema()
+	if len(started) != 2 {
+		t.Fatalf("expected a new process, but %d were started", len(started))
+	}
+}
+
+func TestProviderPool_shared(t *testing.T) {
+	addr := addrs.NewDefaultProvider("test")
+	var started []*tofu.MockProvider
+	pool := newProviderPool(true)
+	factory := pool.Factory(addr, func() (providers.Interface, error) {
+		p := testProvider()
+		started = append(started, p)
+		return p, nil
+	})
+	config := func(region string) providers.ConfigureProviderRequest {
+		return providers.ConfigureProviderRequest{
+			Config: cty.ObjectVal(map[string]cty.Value{"region": cty.StringVal(region)}),
+		}
+	}
+
+	// Provider instances that haven't been configured share one process,
+	// even while they are all in use.
+	a, _ := factory()
+	b, _ := factory()
+	c, _ := factory()
+	a.GetProviderSchema()
+	b.GetProviderSchema()
+	c.GetProviderSchema()
+	if len(started) != 1 {
+		t.Fatalf("expected one shared process, but %d were started", len(started))
+	}
+
+	// Configuring them moves each configuration to its own process, except
+	// where the configurations are identical. The last instance to leave
+	// the shared process can configure it.
+	a.ConfigureProvider(config("a"))
+	b.ConfigureProvider(config("a"))
+	c.ConfigureProvider(config("b"))
+	if len(started) != 2 {
+		t.Fatalf("expected two processes, but %d were started", len(started))
+	}
+	configured := 0
+	for _, p := range started {
+		if p.ConfigureProviderCalled {
+			configured++
+		}
+	}
+	if configured != 2 {
+		t.Fatalf("expected two processes to be configured, but %d were", configured)
+	}
+
+	a.Close()
+	b.Close()
+	c.Close()
+	if err := pool.Close(); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range started {
+		if !p.CloseCalled {
+			t.Errorf("process %d was not stopped when the pool was closed", i)
+		}
+	}
+}
+
+func TestProviderPool_optionalInterfaces(t *testing.T) {
+	addr := addrs.NewDefaultProvider("test")
+	pool := newProviderPool(false)
+	defer pool.Close()
+	factory := pool.Factory(addr, func() (providers.Interface, error) {
+		return &testFindingProvider{MockProvider: testProvider()}, nil
+	})
+
+	p, _ := factory()
+	defer p.Close()
+	finder, ok := providers.AsResourceFinder(p)
+	if !ok {
+		t.Fatal("pooled provider hides the optional interfaces of its process")
+	}
+	if got := finder.FindResource(providers.FindResourceRequest{TypeName: "test_instance"}); got.ID != "found" {
+		t.Errorf("wrong ID %q", got.ID)
+	}
+	if _, ok := providers.AsBulkResourceReader(p); ok {
+		t.Error("pooled provider supports an optional interface that its process doesn't implement")
+	}
+}
+
+// testFindingProvider is a provider that implements one of the optional
+// provider interfaces.
+type testFindingProvider struct {
+	*tofu.MockProvider
+}
+
+func (p *testFindingProvider) FindResource(providers.FindResourceRequest) providers.FindResourceResponse {
+	return providers.FindResourceResponse{ID: "found"}
+}
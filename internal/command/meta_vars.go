@@ -178,11 +178,26 @@ func (m *Meta) addVarsFromFile(filename string, sourceType tofu.ValueSourceType,
 		return diags
 	}
 
-	// Record the file source code for snippets in diagnostic messages.
-	loader.Parser().ForceFileSource(filename, src)
+	sops := isSOPSVarFile(filename)
+	if sops {
+		src, err = decryptSOPSVarFile(filename)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to decrypt variables file",
+				fmt.Sprintf("Error while decrypting %s with SOPS: %s.", filename, err),
+			))
+			return diags
+		}
+	} else {
+		// Record the file source code for snippets in diagnostic messages.
+		// The plaintext of encrypted files is not recorded, so that it
+		// can't be shown.
+		loader.Parser().ForceFileSource(filename, src)
+	}
 
 	var f *hcl.File
-	if strings.HasSuffix(filename, ".json") {
+	if strings.HasSuffix(filename, ".json") || (sops && isYAMLVarFile(filename)) {
 		var hclDiags hcl.Diagnostics
 		f, hclDiags = hcljson.Parse(src, filename)
 		diags = diags.Append(hclDiags)
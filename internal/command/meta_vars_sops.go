@@ -0,0 +1,72 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	ctyyaml "github.com/zclconf/go-cty-yaml"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+// sopsCommand is the SOPS executable used to decrypt variables files, which
+// is looked up in the PATH.
+const sopsCommand = "sops"
+
+// isSOPSVarFile determines if the given variables file is encrypted with
+// SOPS, which is indicated by a ".sops" part in its name before the final
+// extension, such as in "secrets.sops.yaml" or "prod.auto.tfvars.sops.json".
+func isSOPSVarFile(path string) bool {
+	name := filepath.Base(path)
+	return strings.Contains(name, ".sops.")
+}
+
+// isYAMLVarFile determines if the given variables file is written in YAML,
+// which is only supported for files encrypted with SOPS.
+func isYAMLVarFile(path string) bool {
+	return strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")
+}
+
+// decryptSOPSVarFile decrypts the given variables file by running SOPS,
+// which finds the age, PGP or cloud KMS keys to use in the same way as when
+// run directly. The plaintext is only ever held in memory.
+//
+// YAML files are converted to the equivalent JSON, so that their top-level
+// properties can be read as variable values in the same way as for a
+// .tfvars.json file.
+func decryptSOPSVarFile(filename string) ([]byte, error) {
+	cmd := exec.Command(sopsCommand, "--decrypt", filename)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	src := stdout.Bytes()
+
+	if isYAMLVarFile(filename) {
+		ty, err := ctyyaml.ImpliedType(src)
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		val, err := ctyyaml.Unmarshal(src, ty)
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		if !val.Type().IsObjectType() {
+			return nil, fmt.Errorf("the YAML document must be a mapping of variable names to values")
+		}
+		return ctyjson.Marshal(val, ty)
+	}
+	return src, nil
+}
@@ -0,0 +1,472 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/command/multiroot"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// MultiCommand is a Command implementation that only shows the help of the
+// "tofu multi" commands, which run other commands across several root
+// modules declared in a manifest file.
+type MultiCommand struct {
+	Meta
+}
+
+func (c *MultiCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *MultiCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] multi <subcommand> [options] [args]
+
+  Runs an OpenTofu command in each of the root modules declared in a
+  manifest file, in the order of their dependencies.
+
+  The manifest is read from ` + multiroot.ManifestFilename + ` in the current working
+  directory, unless the -manifest option is used. It declares each root
+  module with a "root" block, and may pass the output values of some roots
+  to the input variables of others:
+
+      root "network" {
+        path = "./network"
+      }
+
+      root "app" {
+        path = "./app"
+        inputs = {
+          vpc_id = output.network.vpc_id
+        }
+      }
+
+  Any options that the subcommand doesn't recognize are passed to the
+  command that runs in each root module.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *MultiCommand) Synopsis() string {
+	return "Run a command across several root modules"
+}
+
+// MultiRunCommand is a Command implementation that runs one of the init,
+// plan, apply, destroy and output commands in each root module of a
+// "tofu multi" manifest.
+type MultiRunCommand struct {
+	Meta
+
+	// Operation is the name of the command to run in each root module.
+	Operation string
+
+	// runTofu runs OpenTofu with the given arguments in the given directory,
+	// writing its standard output to stdout, and returns its exit status.
+	// If this is nil then the running executable is started as a new
+	// process. Tests can set this to avoid starting processes.
+	runTofu func(dir string, args []string, stdout io.Writer) int
+}
+
+// multiRootStatus is the outcome of running the operation in a single root.
+type multiRootStatus struct {
+	Failed  bool
+	Skipped bool
+	Changes bool
+	Reason  string
+}
+
+func (s multiRootStatus) String() string {
+	switch {
+	case s.Failed:
+		return "failed"
+	case s.Skipped:
+		return "skipped: " + s.Reason
+	case s.Changes:
+		return "succeeded, changes present"
+	default:
+		return "succeeded"
+	}
+}
+
+func (c *MultiRunCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	manifestPath, args := multiManifestArg(args)
+
+	var diags tfdiags.Diagnostics
+	manifest, moreDiags := multiroot.LoadManifest(manifestPath)
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	order, moreDiags := manifest.Order()
+	diags = diags.Append(moreDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+	c.showDiagnostics(diags)
+
+	if c.Operation == "output" {
+		return c.runOutput(order)
+	}
+
+	if c.Operation == "destroy" {
+		// Roots must be destroyed after all of the roots that depend on
+		// them, which is the reverse of the order they are applied in.
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+
+	varsDir, err := os.MkdirTemp("", "tofu-multi")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to create temporary directory: %s", err))
+		return 1
+	}
+	defer os.RemoveAll(varsDir)
+
+	statuses := make(map[string]multiRootStatus, len(order))
+	outputs := make(map[string]map[string]cty.Value)
+	for _, root := range order {
+		if reason := c.blockedReason(manifest, root, statuses); reason != "" {
+			statuses[root.Name] = multiRootStatus{Skipped: true, Reason: reason}
+			continue
+		}
+
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[reset][bold]%s root %q (%s)...", multiOperationTitle(c.Operation), root.Name, root.Path)))
+
+		rootArgs := []string{c.Operation}
+		if c.Operation != "init" && len(root.Inputs) > 0 {
+			varFile, status := c.inputsVarFile(manifest, root, outputs, varsDir)
+			if status != nil {
+				statuses[root.Name] = *status
+				c.Ui.Output("")
+				continue
+			}
+			rootArgs = append(rootArgs, "-var-file="+varFile)
+		}
+		if !c.color {
+			rootArgs = append(rootArgs, "-no-color")
+		}
+		rootArgs = append(rootArgs, args...)
+
+		var status multiRootStatus
+		switch code := c.runRoot(root.Dir, rootArgs, c.stdout()); {
+		case code == 2 && c.Operation == "plan":
+			// The plan -detailed-exitcode option uses 2 to indicate that
+			// the plan was successful and has changes.
+			status.Changes = true
+		case code != 0:
+			status.Failed = true
+		}
+		statuses[root.Name] = status
+		c.Ui.Output("")
+	}
+
+	c.Ui.Output(c.Colorize().Color("[reset][bold]Summary:"))
+	ret := 0
+	for _, root := range order {
+		status := statuses[root.Name]
+		color := "[green]"
+		switch {
+		case status.Failed:
+			color = "[red]"
+			ret = 1
+		case status.Skipped:
+			color = "[yellow]"
+		case status.Changes && ret == 0:
+			ret = 2
+		}
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf("  %s: %s%s[reset]", root.Name, color, status)))
+	}
+	return ret
+}
+
+// blockedReason returns why the operation must not run in the given root
+// because of what happened in the roots it is related to, or an empty string
+// if it can run.
+func (c *MultiRunCommand) blockedReason(manifest *multiroot.Manifest, root *multiroot.Root, statuses map[string]multiRootStatus) string {
+	var related []string
+	switch c.Operation {
+	case "apply":
+		related = root.DependsOn
+	case "destroy":
+		related = manifest.Dependents(root.Name)
+	default:
+		// The init and plan operations don't change anything that the other
+		// roots use, so they run in every root regardless.
+		return ""
+	}
+	for _, name := range related {
+		if status := statuses[name]; status.Failed || status.Skipped {
+			return fmt.Sprintf("root %q did not succeed", name)
+		}
+	}
+	return ""
+}
+
+// inputsVarFile writes the inputs of the given root to a variable definitions
+// file in the given directory, returning its path. If the inputs can't be
+// evaluated then inputsVarFile instead returns the status of the root.
+func (c *MultiRunCommand) inputsVarFile(manifest *multiroot.Manifest, root *multiroot.Root, outputs map[string]map[string]cty.Value, dir string) (string, *multiRootStatus) {
+	for _, dep := range root.InputRoots() {
+		if _, exists := outputs[dep]; exists {
+			continue
+		}
+		values, err := c.rootOutputs(manifest.Roots[dep])
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read the outputs of root %q: %s", dep, err))
+			return "", &multiRootStatus{Failed: true}
+		}
+		outputs[dep] = values
+	}
+
+	if missing := root.MissingOutputs(outputs); len(missing) > 0 {
+		if c.Operation == "plan" {
+			// The outputs of a root only exist once it has been applied, so
+			// a new root can't be planned until the roots it depends on are.
+			c.Ui.Output(fmt.Sprintf("Skipping, because the outputs %s are not available yet.", strings.Join(missing, ", ")))
+			return "", &multiRootStatus{Skipped: true, Reason: "outputs " + strings.Join(missing, ", ") + " are not available yet"}
+		}
+		c.Ui.Error(fmt.Sprintf("The outputs %s used by the inputs of root %q are not available.", strings.Join(missing, ", "), root.Name))
+		return "", &multiRootStatus{Failed: true}
+	}
+
+	values, diags := root.EvalInputs(outputs)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return "", &multiRootStatus{Failed: true}
+	}
+
+	vars := make(map[string]json.RawMessage, len(values))
+	for name, val := range values {
+		src, err := ctyjson.Marshal(val, val.Type())
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Invalid value for input variable %q: %s", name, err))
+			return "", &multiRootStatus{Failed: true}
+		}
+		vars[name] = src
+	}
+	src, err := json.MarshalIndent(vars, "", "  ")
+	if err == nil {
+		filename := filepath.Join(dir, root.Name+".tfvars.json")
+		if err = os.WriteFile(filename, src, 0600); err == nil {
+			return filename, nil
+		}
+	}
+	c.Ui.Error(fmt.Sprintf("Failed to write the inputs of root %q: %s", root.Name, err))
+	return "", &multiRootStatus{Failed: true}
+}
+
+// multiOutput is an output value as described by "tofu output -json".
+type multiOutput struct {
+	Sensitive bool            `json:"sensitive"`
+	Type      json.RawMessage `json:"type"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// rootOutputs returns the current output values of the given root.
+func (c *MultiRunCommand) rootOutputs(root *multiroot.Root) (map[string]cty.Value, error) {
+	raw, err := c.rootOutputsJSON(root)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make(map[string]cty.Value, len(raw))
+	for name, output := range raw {
+		ty, err := ctyjson.UnmarshalType(output.Type)
+		if err != nil {
+			return nil, fmt.Errorf("invalid type for output %q: %w", name, err)
+		}
+		val, err := ctyjson.Unmarshal(output.Value, ty)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for output %q: %w", name, err)
+		}
+		ret[name] = val
+	}
+	return ret, nil
+}
+
+func (c *MultiRunCommand) rootOutputsJSON(root *multiroot.Root) (map[string]multiOutput, error) {
+	var buf bytes.Buffer
+	if code := c.runRoot(root.Dir, []string{"output", "-json"}, &buf); code != 0 {
+		return nil, fmt.Errorf("tofu output exited with status %d", code)
+	}
+	var ret map[string]multiOutput
+	if err := json.Unmarshal(buf.Bytes(), &ret); err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// runOutput prints the output values of all of the roots as a single JSON
+// object, keyed by root name.
+func (c *MultiRunCommand) runOutput(order []*multiroot.Root) int {
+	all := make(map[string]map[string]multiOutput, len(order))
+	for _, root := range order {
+		outputs, err := c.rootOutputsJSON(root)
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read the outputs of root %q: %s", root.Name, err))
+			return 1
+		}
+		all[root.Name] = outputs
+	}
+	src, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to marshal outputs to json: %s", err))
+		return 1
+	}
+	c.Ui.Output(string(src))
+	return 0
+}
+
+func (c *MultiRunCommand) runRoot(dir string, args []string, stdout io.Writer) int {
+	if c.runTofu != nil {
+		return c.runTofu(dir, args, stdout)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to find the OpenTofu executable: %s", err))
+		return 1
+	}
+	cmd := exec.Command(exe, append([]string{"-chdir=" + dir}, args...)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to run OpenTofu in %s: %s", dir, err))
+		return 1
+	}
+	return 0
+}
+
+func (c *MultiRunCommand) stdout() io.Writer {
+	if c.Streams != nil {
+		return c.Streams.Stdout.File
+	}
+	return os.Stdout
+}
+
+func (c *MultiRunCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *MultiRunCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-manifest": complete.PredictFiles("*.hcl"),
+	}
+}
+
+func (c *MultiRunCommand) Help() string {
+	var detail string
+	switch c.Operation {
+	case "init":
+		detail = `
+  Initializes each root module declared in the manifest.`
+	case "plan":
+		detail = `
+  Creates an execution plan for each root module declared in the manifest,
+  in the order of their dependencies.
+
+  The inputs of a root are taken from the current outputs of the roots it
+  depends on, so a root is skipped if those roots have not been applied
+  yet.`
+	case "apply":
+		detail = `
+  Applies each root module declared in the manifest, in the order of their
+  dependencies. The inputs of each root are taken from the outputs of the
+  roots it depends on after those have been applied.
+
+  If a root fails, the roots that depend on it are skipped.`
+	case "destroy":
+		detail = `
+  Destroys each root module declared in the manifest, in the reverse order
+  of their dependencies.
+
+  If a root fails, the roots that it depends on are skipped.`
+	case "output":
+		detail = `
+  Prints the output values of all of the root modules declared in the
+  manifest as a single JSON object, keyed by root name.`
+	}
+
+	helpText := `
+Usage: tofu [global options] multi ` + c.Operation + ` [options]
+` + detail + `
+
+Options:
+
+  -manifest=path   Path to the manifest file. Defaults to ` + multiroot.ManifestFilename + `
+                   in the current working directory.
+
+  Any other options are passed to "tofu ` + c.Operation + `" in each root module.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *MultiRunCommand) Synopsis() string {
+	return fmt.Sprintf("Run %q in each root module of a manifest", "tofu "+c.Operation)
+}
+
+// multiManifestArg removes the -manifest option from the given arguments,
+// returning its value, or the default manifest path if it isn't present, and
+// the remaining arguments.
+func multiManifestArg(args []string) (string, []string) {
+	manifest := multiroot.ManifestFilename
+	var rest []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-manifest" && i+1 < len(args):
+			manifest = args[i+1]
+			i++
+		case strings.HasPrefix(arg, "-manifest="):
+			manifest = strings.TrimPrefix(arg, "-manifest=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return manifest, rest
+}
+
+func multiOperationTitle(operation string) string {
+	switch operation {
+	case "init":
+		return "Initializing"
+	case "plan":
+		return "Planning"
+	case "apply":
+		return "Applying"
+	case "destroy":
+		return "Destroying"
+	default:
+		return operation
+	}
+}
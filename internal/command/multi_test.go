@@ -0,0 +1,299 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/cli"
+)
+
+// testMultiOutputs are the output values of the roots in the "multi" test
+// fixture once they have been applied, as "tofu output -json" prints them.
+var testMultiOutputs = map[string]string{
+	"app":     `{}`,
+	"dns":     `{"zone":{"sensitive":false,"type":"string","value":"example.com"}}`,
+	"network": `{"vpc_id":{"sensitive":false,"type":"string","value":"vpc-1"}}`,
+}
+
+// testMultiRunner is a fake for running OpenTofu in the roots of a "tofu
+// multi" manifest. It records the commands that were run, and the roots only
+// have output values while they are applied.
+type testMultiRunner struct {
+	t *testing.T
+
+	calls   []string
+	applied map[string]bool
+	fail    map[string]bool
+
+	// vars records the content of the variable definitions file passed to
+	// each root.
+	vars map[string]string
+}
+
+func newTestMultiRunner(t *testing.T) *testMultiRunner {
+	return &testMultiRunner{
+		t:       t,
+		applied: make(map[string]bool),
+		fail:    make(map[string]bool),
+		vars:    make(map[string]string),
+	}
+}
+
+func (r *testMultiRunner) run(dir string, args []string, stdout io.Writer) int {
+	root := filepath.Base(dir)
+	r.calls = append(r.calls, root+": "+strings.Join(args, " "))
+
+	for _, arg := range args {
+		if filename, ok := strings.CutPrefix(arg, "-var-file="); ok {
+			src, err := os.ReadFile(filename)
+			if err != nil {
+				r.t.Fatal(err)
+			}
+			r.vars[root] = string(src)
+		}
+	}
+
+	if r.fail[root] {
+		return 1
+	}
+	switch args[0] {
+	case "apply":
+		r.applied[root] = true
+	case "destroy":
+		delete(r.applied, root)
+	case "output":
+		if r.applied[root] {
+			fmt.Fprint(stdout, testMultiOutputs[root])
+		} else {
+			fmt.Fprint(stdout, `{}`)
+		}
+	}
+	return 0
+}
+
+func testMultiCommand(t *testing.T, operation string, runner *testMultiRunner) (*MultiRunCommand, *cli.MockUi) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("multi"), td)
+	t.Cleanup(testChdir(t, td))
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	return &MultiRunCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+		Operation: operation,
+		runTofu:   runner.run,
+	}, ui
+}
+
+func TestMultiApply(t *testing.T) {
+	runner := newTestMultiRunner(t)
+	c, ui := testMultiCommand(t, "apply", runner)
+
+	if code := c.Run([]string{"-auto-approve"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	want := []string{
+		"dns: apply -no-color -auto-approve",
+		"network: apply -no-color -auto-approve",
+		"network: output -json",
+		"app: apply -var-file=VARS -no-color -auto-approve",
+	}
+	if diff := cmp.Diff(want, testMultiCalls(runner.calls)); diff != "" {
+		t.Fatalf("wrong calls\n%s", diff)
+	}
+
+	var vars map[string]interface{}
+	if err := json.Unmarshal([]byte(runner.vars["app"]), &vars); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]interface{}{"vpc_id": "vpc-1"}, vars); diff != "" {
+		t.Fatalf("wrong variables\n%s", diff)
+	}
+
+	output := ui.OutputWriter.String()
+	for _, want := range []string{
+		`Applying root "network" (network)...`,
+		"Summary:",
+		"  app: succeeded",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestMultiApply_failed(t *testing.T) {
+	runner := newTestMultiRunner(t)
+	runner.fail["dns"] = true
+	c, ui := testMultiCommand(t, "apply", runner)
+
+	if code := c.Run([]string{"-auto-approve"}); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\n\n%s", code, ui.ErrorWriter)
+	}
+
+	want := []string{
+		"dns: apply -no-color -auto-approve",
+		"network: apply -no-color -auto-approve",
+	}
+	if diff := cmp.Diff(want, testMultiCalls(runner.calls)); diff != "" {
+		t.Fatalf("wrong calls\n%s", diff)
+	}
+
+	output := ui.OutputWriter.String()
+	for _, want := range []string{
+		"  dns: failed",
+		"  network: succeeded",
+		`  app: skipped: root "dns" did not succeed`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestMultiPlan(t *testing.T) {
+	runner := newTestMultiRunner(t)
+	c, ui := testMultiCommand(t, "plan", runner)
+
+	// Nothing has been applied yet, so the app root can't be planned.
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	want := []string{
+		"dns: plan -no-color",
+		"network: plan -no-color",
+		"network: output -json",
+	}
+	if diff := cmp.Diff(want, testMultiCalls(runner.calls)); diff != "" {
+		t.Fatalf("wrong calls\n%s", diff)
+	}
+	if got, want := ui.OutputWriter.String(), "  app: skipped: outputs network.vpc_id are not available yet"; !strings.Contains(got, want) {
+		t.Fatalf("output does not contain %q\n%s", want, got)
+	}
+
+	runner.calls = nil
+	runner.applied["network"] = true
+	ui.OutputWriter.Reset()
+	if code := c.Run([]string{"-detailed-exitcode"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	want = []string{
+		"dns: plan -no-color -detailed-exitcode",
+		"network: plan -no-color -detailed-exitcode",
+		"network: output -json",
+		"app: plan -var-file=VARS -no-color -detailed-exitcode",
+	}
+	if diff := cmp.Diff(want, testMultiCalls(runner.calls)); diff != "" {
+		t.Fatalf("wrong calls\n%s", diff)
+	}
+}
+
+func TestMultiDestroy(t *testing.T) {
+	runner := newTestMultiRunner(t)
+	runner.applied["network"] = true
+	runner.fail["app"] = true
+	c, ui := testMultiCommand(t, "destroy", runner)
+
+	if code := c.Run([]string{"-auto-approve"}); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\n\n%s", code, ui.ErrorWriter)
+	}
+
+	// The app root depends on the others, so it's destroyed first, and
+	// because it failed the others are left alone.
+	want := []string{
+		"network: output -json",
+		"app: destroy -var-file=VARS -no-color -auto-approve",
+	}
+	if diff := cmp.Diff(want, testMultiCalls(runner.calls)); diff != "" {
+		t.Fatalf("wrong calls\n%s", diff)
+	}
+}
+
+func TestMultiOutput(t *testing.T) {
+	runner := newTestMultiRunner(t)
+	runner.applied["network"] = true
+	c, ui := testMultiCommand(t, "output", runner)
+
+	if code := c.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	var got map[string]map[string]map[string]interface{}
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+		t.Fatalf("invalid output: %s\n%s", err, ui.OutputWriter)
+	}
+	want := map[string]map[string]map[string]interface{}{
+		"app": {},
+		"dns": {},
+		"network": {
+			"vpc_id": {
+				"sensitive": false,
+				"type":      "string",
+				"value":     "vpc-1",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong output\n%s", diff)
+	}
+}
+
+func TestMultiRun_manifest(t *testing.T) {
+	runner := newTestMultiRunner(t)
+	c, ui := testMultiCommand(t, "init", runner)
+
+	if err := os.Rename("tofu-multi.hcl", "stack.hcl"); err != nil {
+		t.Fatal(err)
+	}
+
+	if code := c.Run(nil); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "tofu-multi.hcl"; !strings.Contains(got, want) {
+		t.Fatalf("error does not mention %q\n%s", want, got)
+	}
+
+	if code := c.Run([]string{"-manifest", "stack.hcl", "-upgrade"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	want := []string{
+		"dns: init -no-color -upgrade",
+		"network: init -no-color -upgrade",
+		"app: init -no-color -upgrade",
+	}
+	if diff := cmp.Diff(want, testMultiCalls(runner.calls)); diff != "" {
+		t.Fatalf("wrong calls\n%s", diff)
+	}
+}
+
+// testMultiCalls replaces the temporary paths of variable definitions files
+// in the given calls, so they can be compared.
+func testMultiCalls(calls []string) []string {
+	ret := make([]string, len(calls))
+	for i, call := range calls {
+		var args []string
+		for _, arg := range strings.Fields(call) {
+			if strings.HasPrefix(arg, "-var-file=") {
+				arg = "-var-file=VARS"
+			}
+			args = append(args, arg)
+		}
+		ret[i] = strings.Join(args, " ")
+	}
+	return ret
+}
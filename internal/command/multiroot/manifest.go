@@ -0,0 +1,447 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package multiroot deals with the manifest file of the "tofu multi" commands,
+// which describes a set of root modules that are planned and applied together
+// and how the output values of some of them are passed to the input variables
+// of others.
+package multiroot
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ManifestFilename is the name of the manifest file that the "tofu multi"
+// commands look for in the current working directory by default.
+const ManifestFilename = "tofu-multi.hcl"
+
+// Manifest is the decoded content of a manifest file.
+type Manifest struct {
+	// Dir is the directory containing the manifest file. The paths of the
+	// root modules are relative to this directory.
+	Dir string
+
+	Roots map[string]*Root
+}
+
+// Root is a root module declared by a "root" block in the manifest.
+type Root struct {
+	Name string
+
+	// Path is the path of the root module directory as written in the
+	// manifest, and Dir is that same path resolved relative to the directory
+	// of the manifest.
+	Path string
+	Dir  string
+
+	// DependsOn is the sorted names of the other roots that must be applied
+	// before this one, including both those given explicitly in the
+	// "depends_on" argument and those whose outputs are used in "inputs".
+	DependsOn []string
+
+	// Inputs are the expressions for the input variables of the root module,
+	// which can refer to the outputs of other roots as output.NAME.OUTPUT.
+	Inputs map[string]hcl.Expression
+
+	DeclRange hcl.Range
+}
+
+// LoadManifest reads the manifest from the given file, or returns error
+// diagnostics explaining why that was not possible.
+func LoadManifest(filename string) (*Manifest, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	parser := hclparse.NewParser()
+	f, hclDiags := parser.ParseHCLFile(filename)
+	diags = diags.Append(hclDiags)
+	if f == nil {
+		return nil, diags
+	}
+
+	manifest := &Manifest{
+		Dir:   filepath.Dir(filename),
+		Roots: make(map[string]*Root),
+	}
+
+	content, hclDiags := f.Body.Content(manifestSchema)
+	diags = diags.Append(hclDiags)
+
+	for _, block := range content.Blocks {
+		root, moreDiags := decodeRootBlock(block, manifest.Dir)
+		diags = diags.Append(moreDiags)
+		if root == nil {
+			continue
+		}
+		if existing, exists := manifest.Roots[root.Name]; exists {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate root block",
+				Detail:   fmt.Sprintf("A root named %q was already declared at %s. Each root must have a unique name.", root.Name, existing.DeclRange),
+				Subject:  block.DefRange.Ptr(),
+			})
+			continue
+		}
+		manifest.Roots[root.Name] = root
+	}
+
+	if len(manifest.Roots) == 0 && !diags.HasErrors() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No root modules declared",
+			fmt.Sprintf("The manifest %s must contain at least one \"root\" block.", filename),
+		))
+	}
+
+	for _, name := range manifest.names() {
+		root := manifest.Roots[name]
+		for _, dep := range root.DependsOn {
+			if _, exists := manifest.Roots[dep]; !exists {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Reference to undeclared root",
+					Detail:   fmt.Sprintf("The root %q depends on a root named %q, which is not declared in the manifest.", root.Name, dep),
+					Subject:  root.DeclRange.Ptr(),
+				})
+			}
+		}
+	}
+
+	return manifest, diags
+}
+
+// Order returns the roots of the manifest in an order where every root comes
+// after all of the roots it depends on. Roots that don't depend on each other
+// are ordered by name, so the order is always the same for a given manifest.
+//
+// Order returns error diagnostics if the dependencies of the roots contain a
+// cycle.
+func (m *Manifest) Order() ([]*Root, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	done := make(map[string]bool, len(m.Roots))
+	ret := make([]*Root, 0, len(m.Roots))
+	names := m.names()
+	for len(ret) < len(names) {
+		progress := false
+		for _, name := range names {
+			if done[name] {
+				continue
+			}
+			root := m.Roots[name]
+			ready := true
+			for _, dep := range root.DependsOn {
+				if !done[dep] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				done[name] = true
+				ret = append(ret, root)
+				progress = true
+				// We start again from the beginning so that roots which were
+				// waiting for this one keep their place in name order.
+				break
+			}
+		}
+		if !progress {
+			var remaining []string
+			for _, name := range names {
+				if !done[name] {
+					remaining = append(remaining, name)
+				}
+			}
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Dependency cycle between roots",
+				fmt.Sprintf("The roots %q depend on each other, so there is no order in which they can be applied.", remaining),
+			))
+			return nil, diags
+		}
+	}
+
+	return ret, diags
+}
+
+// Dependents returns the names of the roots that depend on the given root,
+// either directly or indirectly, sorted by name.
+func (m *Manifest) Dependents(name string) []string {
+	found := make(map[string]bool)
+	var visit func(string)
+	visit = func(name string) {
+		for _, other := range m.names() {
+			if found[other] {
+				continue
+			}
+			for _, dep := range m.Roots[other].DependsOn {
+				if dep == name {
+					found[other] = true
+					visit(other)
+					break
+				}
+			}
+		}
+	}
+	visit(name)
+
+	ret := make([]string, 0, len(found))
+	for name := range found {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func (m *Manifest) names() []string {
+	ret := make([]string, 0, len(m.Roots))
+	for name := range m.Roots {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// InputRoots returns the names of the roots whose outputs are used in the
+// inputs of this root, sorted by name.
+func (r *Root) InputRoots() []string {
+	found := make(map[string]bool)
+	for _, expr := range r.Inputs {
+		for _, ref := range outputRefs(expr) {
+			found[ref.root] = true
+		}
+	}
+	ret := make([]string, 0, len(found))
+	for name := range found {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// MissingOutputs returns the references to outputs of other roots, in the
+// form NAME.OUTPUT, that the inputs of this root use but which aren't present
+// in the given outputs.
+//
+// The given outputs are keyed by root name and then by output name.
+func (r *Root) MissingOutputs(outputs map[string]map[string]cty.Value) []string {
+	var ret []string
+	for _, name := range r.inputNames() {
+		for _, ref := range outputRefs(r.Inputs[name]) {
+			if _, exists := outputs[ref.root][ref.output]; !exists {
+				ret = append(ret, ref.root+"."+ref.output)
+			}
+		}
+	}
+	return ret
+}
+
+// EvalInputs evaluates the inputs of the root, using the given output values
+// of the roots it depends on.
+//
+// The given outputs are keyed by root name and then by output name.
+func (r *Root) EvalInputs(outputs map[string]map[string]cty.Value) (map[string]cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	roots := make(map[string]cty.Value, len(r.DependsOn))
+	for _, dep := range r.DependsOn {
+		roots[dep] = cty.ObjectVal(outputs[dep])
+	}
+	ctx := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"output": cty.ObjectVal(roots),
+		},
+	}
+
+	ret := make(map[string]cty.Value, len(r.Inputs))
+	for _, name := range r.inputNames() {
+		val, hclDiags := r.Inputs[name].Value(ctx)
+		diags = diags.Append(hclDiags)
+		if hclDiags.HasErrors() {
+			continue
+		}
+		if !val.IsWhollyKnown() {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid input value",
+				Detail:   fmt.Sprintf("The value for input variable %q is not known.", name),
+				Subject:  r.Inputs[name].Range().Ptr(),
+			})
+			continue
+		}
+		ret[name] = val
+	}
+	return ret, diags
+}
+
+func (r *Root) inputNames() []string {
+	ret := make([]string, 0, len(r.Inputs))
+	for name := range r.Inputs {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+func decodeRootBlock(block *hcl.Block, baseDir string) (*Root, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	name := block.Labels[0]
+	if !hclsyntax.ValidIdentifier(name) {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid root name",
+			Detail:   "A name must start with a letter or underscore and may contain only letters, digits, underscores, and dashes.",
+			Subject:  block.LabelRanges[0].Ptr(),
+		})
+		return nil, diags
+	}
+
+	content, hclDiags := block.Body.Content(rootSchema)
+	diags = append(diags, hclDiags...)
+	if hclDiags.HasErrors() {
+		return nil, diags
+	}
+
+	root := &Root{
+		Name:      name,
+		Inputs:    make(map[string]hcl.Expression),
+		DeclRange: block.DefRange,
+	}
+
+	pathAttr := content.Attributes["path"]
+	diags = append(diags, gohcl.DecodeExpression(pathAttr.Expr, nil, &root.Path)...)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	root.Dir = root.Path
+	if !filepath.IsAbs(root.Dir) {
+		root.Dir = filepath.Join(baseDir, root.Dir)
+	}
+	if info, err := os.Stat(root.Dir); err != nil || !info.IsDir() {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid root module directory",
+			Detail:   fmt.Sprintf("The directory %s of root %q does not exist.", root.Dir, name),
+			Subject:  pathAttr.Expr.Range().Ptr(),
+		})
+	}
+
+	deps := make(map[string]bool)
+	if attr, exists := content.Attributes["depends_on"]; exists {
+		var explicit []string
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &explicit)...)
+		for _, dep := range explicit {
+			deps[dep] = true
+		}
+	}
+
+	if attr, exists := content.Attributes["inputs"]; exists {
+		pairs, hclDiags := hcl.ExprMap(attr.Expr)
+		diags = append(diags, hclDiags...)
+		for _, pair := range pairs {
+			key := hcl.ExprAsKeyword(pair.Key)
+			if key == "" {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid input variable name",
+					Detail:   "The keys of the inputs map must be the names of input variables of the root module.",
+					Subject:  pair.Key.Range().Ptr(),
+				})
+				continue
+			}
+			root.Inputs[key] = pair.Value
+			for _, traversal := range pair.Value.Variables() {
+				ref, moreDiags := decodeOutputRef(traversal)
+				diags = append(diags, moreDiags...)
+				if ref != nil {
+					deps[ref.root] = true
+				}
+			}
+		}
+	}
+
+	if deps[name] {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Self-referential root",
+			Detail:   fmt.Sprintf("The root %q cannot depend on itself.", name),
+			Subject:  block.DefRange.Ptr(),
+		})
+		delete(deps, name)
+	}
+	for dep := range deps {
+		root.DependsOn = append(root.DependsOn, dep)
+	}
+	sort.Strings(root.DependsOn)
+
+	return root, diags
+}
+
+type outputRef struct {
+	root, output string
+}
+
+// outputRefs returns the references to outputs of other roots in the given
+// expression, which must already have been validated by decodeRootBlock.
+func outputRefs(expr hcl.Expression) []outputRef {
+	var ret []outputRef
+	for _, traversal := range expr.Variables() {
+		if ref, _ := decodeOutputRef(traversal); ref != nil {
+			ret = append(ret, *ref)
+		}
+	}
+	return ret
+}
+
+func decodeOutputRef(traversal hcl.Traversal) (*outputRef, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	invalid := &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid reference",
+		Detail:   "The inputs of a root can only refer to the outputs of other roots, as output.NAME.OUTPUT.",
+		Subject:  traversal.SourceRange().Ptr(),
+	}
+	if traversal.RootName() != "output" || len(traversal) < 3 {
+		return nil, diags.Append(invalid)
+	}
+	rootStep, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return nil, diags.Append(invalid)
+	}
+	outputStep, ok := traversal[2].(hcl.TraverseAttr)
+	if !ok {
+		return nil, diags.Append(invalid)
+	}
+	return &outputRef{root: rootStep.Name, output: outputStep.Name}, diags
+}
+
+var manifestSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "root",
+			LabelNames: []string{"name"},
+		},
+	},
+}
+
+var rootSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "path", Required: true},
+		{Name: "depends_on"},
+		{Name: "inputs"},
+	},
+}
@@ -0,0 +1,216 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package multiroot
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// testManifest writes the given manifest source to a temporary directory,
+// along with a directory for each of the given roots, and returns the path
+// of the manifest file.
+func testManifest(t *testing.T, src string, roots ...string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for _, root := range roots {
+		if err := os.Mkdir(filepath.Join(dir, root), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filename := filepath.Join(dir, ManifestFilename)
+	if err := os.WriteFile(filename, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
+
+func TestLoadManifest(t *testing.T) {
+	filename := testManifest(t, `
+root "network" {
+  path = "network"
+}
+
+root "dns" {
+  path = "dns"
+}
+
+root "app" {
+  path       = "app"
+  depends_on = ["dns"]
+  inputs = {
+    vpc_id  = output.network.vpc_id
+    subnets = [for s in output.network.subnets : s.id]
+    name    = "app"
+  }
+}
+`, "network", "dns", "app")
+
+	manifest, diags := LoadManifest(filename)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+
+	app := manifest.Roots["app"]
+	if got, want := app.Dir, filepath.Join(filepath.Dir(filename), "app"); got != want {
+		t.Errorf("wrong dir %q; want %q", got, want)
+	}
+	if diff := cmp.Diff([]string{"dns", "network"}, app.DependsOn); diff != "" {
+		t.Errorf("wrong dependencies\n%s", diff)
+	}
+
+	order, diags := manifest.Order()
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	var names []string
+	for _, root := range order {
+		names = append(names, root.Name)
+	}
+	if diff := cmp.Diff([]string{"dns", "network", "app"}, names); diff != "" {
+		t.Errorf("wrong order\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"app"}, manifest.Dependents("network")); diff != "" {
+		t.Errorf("wrong dependents\n%s", diff)
+	}
+
+	outputs := map[string]map[string]cty.Value{
+		"network": {
+			"vpc_id": cty.StringVal("vpc-1"),
+		},
+	}
+	if diff := cmp.Diff([]string{"network"}, app.InputRoots()); diff != "" {
+		t.Errorf("wrong input roots\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"network.subnets"}, app.MissingOutputs(outputs)); diff != "" {
+		t.Errorf("wrong missing outputs\n%s", diff)
+	}
+
+	outputs["network"]["subnets"] = cty.TupleVal([]cty.Value{
+		cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("subnet-1")}),
+	})
+	if missing := app.MissingOutputs(outputs); len(missing) != 0 {
+		t.Errorf("unexpected missing outputs %q", missing)
+	}
+	inputs, diags := app.EvalInputs(outputs)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	want := map[string]cty.Value{
+		"name":    cty.StringVal("app"),
+		"subnets": cty.TupleVal([]cty.Value{cty.StringVal("subnet-1")}),
+		"vpc_id":  cty.StringVal("vpc-1"),
+	}
+	if len(inputs) != len(want) {
+		t.Fatalf("wrong inputs %#v", inputs)
+	}
+	for name, val := range want {
+		if !inputs[name].RawEquals(val) {
+			t.Errorf("wrong value for %s: %#v; want %#v", name, inputs[name], val)
+		}
+	}
+}
+
+func TestLoadManifest_invalid(t *testing.T) {
+	tests := map[string]struct {
+		src   string
+		roots []string
+		want  string
+	}{
+		"empty": {
+			``,
+			nil,
+			"No root modules declared",
+		},
+		"missing path": {
+			`root "a" {}`,
+			nil,
+			`The argument "path" is required`,
+		},
+		"missing directory": {
+			`root "a" { path = "nope" }`,
+			nil,
+			"Invalid root module directory",
+		},
+		"duplicate": {
+			`
+root "a" { path = "a" }
+root "a" { path = "a" }
+`,
+			[]string{"a"},
+			"Duplicate root block",
+		},
+		"undeclared dependency": {
+			`root "a" {
+  path       = "a"
+  depends_on = ["b"]
+}`,
+			[]string{"a"},
+			"Reference to undeclared root",
+		},
+		"invalid reference": {
+			`root "a" {
+  path   = "a"
+  inputs = { x = var.x }
+}`,
+			[]string{"a"},
+			"Invalid reference",
+		},
+		"self reference": {
+			`root "a" {
+  path   = "a"
+  inputs = { x = output.a.x }
+}`,
+			[]string{"a"},
+			"Self-referential root",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, diags := LoadManifest(testManifest(t, test.src, test.roots...))
+			if !diags.HasErrors() {
+				t.Fatal("expected errors")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, test.want) {
+				t.Fatalf("wrong error %q; want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestManifestOrder_cycle(t *testing.T) {
+	filename := testManifest(t, `
+root "a" {
+  path   = "a"
+  inputs = { x = output.b.x }
+}
+
+root "b" {
+  path       = "b"
+  depends_on = ["a"]
+}
+`, "a", "b")
+
+	manifest, diags := LoadManifest(filename)
+	if diags.HasErrors() {
+		t.Fatalf("unexpected errors: %s", diags.Err())
+	}
+	_, diags = manifest.Order()
+	if !diags.HasErrors() {
+		t.Fatal("expected errors")
+	}
+	if got, want := diags.Err().Error(), "Dependency cycle between roots"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,192 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
+	"github.com/opentofu/opentofu/internal/httpclient"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/version"
+)
+
+// notificationEvent is the JSON payload posted to the webhooks in the
+// notifications blocks of the CLI configuration, and the data that their
+// templates are executed with.
+type notificationEvent struct {
+	Event      string   `json:"event"`
+	Timestamp  string   `json:"timestamp"`
+	Command    string   `json:"command"`
+	Version    string   `json:"tofu_version"`
+	WorkingDir string   `json:"working_dir"`
+	Workspace  string   `json:"workspace"`
+	Result     string   `json:"result,omitempty"`
+	ExitStatus *int     `json:"exit_status,omitempty"`
+	Drifted    []string `json:"drifted,omitempty"`
+}
+
+// notifySession notifies the webhooks of the start of a plan or apply
+// operation, and then of its outcome. A nil session, returned when there
+// are no notifications blocks in the CLI configuration, does nothing.
+type notifySession struct {
+	meta *Meta
+
+	// stage is either "plan" or "apply", which the names of the events
+	// start with, while command is the name of the command that runs it.
+	stage     string
+	command   string
+	workspace string
+}
+
+// notifyBegin notifies the webhooks that the given stage, "plan" or
+// "apply", has started for the given command.
+func (m *Meta) notifyBegin(stage, command string) *notifySession {
+	if len(m.Notifications) == 0 {
+		return nil
+	}
+
+	workspace, err := m.Workspace()
+	if err != nil {
+		log.Printf("[WARN] notifications: failed to select the workspace for %s: %s", command, err)
+	}
+	s := &notifySession{
+		meta:      m,
+		stage:     stage,
+		command:   command,
+		workspace: workspace,
+	}
+	s.send(s.event(stage + "_started"))
+	return s
+}
+
+// finish notifies the webhooks of the outcome of the operation, given the
+// exit status of the command and the operation if it ran, and of any drift
+// that the operation detected.
+func (s *notifySession) finish(status int, op *backend.RunningOperation) {
+	if s == nil {
+		return
+	}
+
+	success := op != nil && op.Result == backend.OperationSuccess && status != 1
+	event := s.event(s.stage + "_completed")
+	if !success && s.stage == "apply" {
+		event.Event = "apply_failed"
+	}
+	event.Result = "success"
+	if !success {
+		event.Result = "failure"
+	}
+	event.ExitStatus = &status
+	s.send(event)
+
+	if op != nil && len(op.Drifted) > 0 {
+		event := s.event("drift_detected")
+		event.Drifted = make([]string, len(op.Drifted))
+		for i, addr := range op.Drifted {
+			event.Drifted[i] = addr.String()
+		}
+		s.send(event)
+	}
+}
+
+func (s *notifySession) event(name string) *notificationEvent {
+	ret := &notificationEvent{
+		Event:     name,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Command:   s.command,
+		Version:   version.String(),
+		Workspace: s.workspace,
+	}
+	ret.WorkingDir, _ = os.Getwd()
+	return ret
+}
+
+// send posts the given event to each of the webhooks that selects it.
+func (s *notifySession) send(event *notificationEvent) {
+	var diags tfdiags.Diagnostics
+	for _, n := range s.meta.Notifications {
+		if n.Selects(event.Event) {
+			diags = diags.Append(postNotification(n, event))
+		}
+	}
+	s.meta.showDiagnostics(diags)
+}
+
+// postNotification renders the payload for the given event and posts it to
+// the webhook of the given notifications block.
+func postNotification(n *cliconfig.ConfigNotifications, event *notificationEvent) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	payload, err := notificationPayload(n, event)
+	if err == nil {
+		var req *http.Request
+		req, err = http.NewRequest(http.MethodPost, n.URL, bytes.NewReader(payload))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Tofu-Event", event.Event)
+			if n.Secret != "" {
+				req.Header.Set("X-Tofu-Signature", "sha256="+signNotification(n.Secret, payload))
+			}
+			for k, v := range n.Headers {
+				req.Header.Set(k, v)
+			}
+
+			var resp *http.Response
+			resp, err = httpclient.New().Do(req)
+			if err == nil {
+				resp.Body.Close()
+				if resp.StatusCode < 200 || resp.StatusCode > 299 {
+					err = fmt.Errorf("the server responded with %s", resp.Status)
+				}
+			}
+		}
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Failed to send notification",
+			fmt.Sprintf("Could not send the %s notification to the %q webhook: %s.", event.Event, n.Name, err),
+		))
+	}
+	return diags
+}
+
+// notificationPayload returns the event as JSON, or rendered with the
+// template of the given notifications block if it has one.
+func notificationPayload(n *cliconfig.ConfigNotifications, event *notificationEvent) ([]byte, error) {
+	if n.Template == "" {
+		return json.Marshal(event)
+	}
+	tmpl, err := n.ParseTemplate()
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, event); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// signNotification returns the hex encoding of the HMAC-SHA256 of the given
+// payload with the given secret, which the receiver can use to check that
+// the payload was sent by someone who knows the secret.
+func signNotification(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,166 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/cliconfig"
+)
+
+// testNotificationServer records the payloads posted to it, by path.
+type testNotificationServer struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	payloads map[string][][]byte
+	headers  map[string][]http.Header
+}
+
+func newTestNotificationServer(t *testing.T) *testNotificationServer {
+	s := &testNotificationServer{
+		payloads: make(map[string][][]byte),
+		headers:  make(map[string][]http.Header),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.payloads[r.URL.Path] = append(s.payloads[r.URL.Path], body)
+		s.headers[r.URL.Path] = append(s.headers[r.URL.Path], r.Header)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// events returns the names of the events posted to the given path as JSON.
+func (s *testNotificationServer) events(t *testing.T, path string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ret []string
+	for _, payload := range s.payloads[path] {
+		var event notificationEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			t.Fatalf("invalid payload %s: %s", payload, err)
+		}
+		ret = append(ret, event.Event)
+	}
+	return ret
+}
+
+func TestNotifications_apply(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	server := newTestNotificationServer(t)
+	p := applyFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+			Notifications: []*cliconfig.ConfigNotifications{
+				{
+					Name:   "all",
+					URL:    server.URL + "/all",
+					Secret: "s3cret",
+				},
+				{
+					Name:     "chat",
+					URL:      server.URL + "/chat",
+					Events:   []string{"apply_completed", "apply_failed"},
+					Template: `{"text": {{ printf "%s %s in %s" .Command .Result .Workspace | json }}}`,
+				},
+			},
+		},
+	}
+
+	code := c.Run([]string{"-state", testTempFile(t), "-auto-approve"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	if diff := cmp.Diff([]string{"apply_started", "apply_completed"}, server.events(t, "/all")); diff != "" {
+		t.Errorf("wrong events\n%s", diff)
+	}
+	for i, payload := range server.payloads["/all"] {
+		header := server.headers["/all"][i]
+		if got, want := header.Get("X-Tofu-Event"), server.events(t, "/all")[i]; got != want {
+			t.Errorf("wrong event header %q; want %q", got, want)
+		}
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(payload)
+		if got, want := header.Get("X-Tofu-Signature"), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+			t.Errorf("wrong signature %q; want %q", got, want)
+		}
+	}
+
+	if got, want := len(server.payloads["/chat"]), 1; got != want {
+		t.Fatalf("got %d payloads for chat; want %d", got, want)
+	}
+	if got, want := string(server.payloads["/chat"][0]), `{"text": "apply success in default"}`; got != want {
+		t.Errorf("wrong payload\ngot:  %s\nwant: %s", got, want)
+	}
+	if got := server.headers["/chat"][0].Get("X-Tofu-Signature"); got != "" {
+		t.Errorf("unexpected signature %q without a secret", got)
+	}
+}
+
+func TestNotifySession_finish(t *testing.T) {
+	server := newTestNotificationServer(t)
+	view, done := testView(t)
+	m := &Meta{
+		View: view,
+		Notifications: []*cliconfig.ConfigNotifications{
+			{
+				Name: "all",
+				URL:  server.URL + "/all",
+			},
+		},
+	}
+
+	op := &backend.RunningOperation{
+		Result: backend.OperationFailure,
+		Drifted: []addrs.AbsResourceInstance{
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "foo",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+		},
+	}
+	s := m.notifyBegin("apply", "destroy")
+	s.finish(1, op)
+	done(t)
+
+	if diff := cmp.Diff([]string{"apply_started", "apply_failed", "drift_detected"}, server.events(t, "/all")); diff != "" {
+		t.Errorf("wrong events\n%s", diff)
+	}
+
+	var failed, drift notificationEvent
+	json.Unmarshal(server.payloads["/all"][1], &failed)
+	json.Unmarshal(server.payloads["/all"][2], &drift)
+	if failed.Command != "destroy" || failed.Result != "failure" || failed.ExitStatus == nil || *failed.ExitStatus != 1 {
+		t.Errorf("wrong apply_failed event: %#v", failed)
+	}
+	if diff := cmp.Diff([]string{"test_instance.foo"}, drift.Drifted); diff != "" {
+		t.Errorf("wrong drifted resources\n%s", diff)
+	}
+}
@@ -29,13 +29,19 @@ func (c *OutputCommand) Run(rawArgs []string) int {
 
 	// Parse and validate flags
 	args, diags := arguments.ParseOutput(rawArgs)
+	diags = diags.Append(common.Parse())
 	if diags.HasErrors() {
 		c.View.Diagnostics(diags)
 		c.View.HelpPrompt("output")
 		return 1
 	}
 
-	view := views.NewOutput(args.ViewType, c.View)
+	var view views.Output
+	if args.ViewType == arguments.ViewEnv {
+		view = views.NewOutputEnv(args.EnvFormat, args.ShowSensitive, c.View)
+	} else {
+		view = views.NewOutput(args.ViewType, c.View)
+	}
 
 	// Inject variables from args into meta for static evaluation
 	c.GatherVariables(args.Vars)
@@ -148,6 +154,18 @@ Options:
                    converted to a string, will print the raw
                    string directly, rather than a human-oriented
                    representation of the value.
+
+  -format=dotenv   Print the outputs as environment variable
+                   assignments. The format can be "dotenv" for
+                   .env files, "shell" for export commands, or
+                   "github-env" for the GITHUB_ENV file of GitHub
+                   Actions. Variable names are the output names in
+                   upper case, and values of complex types are
+                   printed as JSON.
+
+  -show-sensitive  Include sensitive output values when using
+                   -format=dotenv, shell or github-env. By default
+                   they are omitted.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -7,12 +7,17 @@ package command
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/posener/complete"
+
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -22,9 +27,10 @@ type PlanCommand struct {
 	Meta
 }
 
-func (c *PlanCommand) Run(rawArgs []string) int {
+func (c *PlanCommand) Run(rawArgs []string) (status int) {
 	// Parse and apply global view arguments
 	common, rawArgs := arguments.ParseView(rawArgs)
+	rawArgs = c.Meta.processOverrideSet(rawArgs)
 	c.View.Configure(common)
 
 	// Propagate -no-color for legacy use of Ui.  The remote backend and
@@ -35,6 +41,7 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 
 	// Parse and validate flags
 	args, diags := arguments.ParsePlan(rawArgs)
+	diags = diags.Append(common.Parse())
 
 	// Instantiate the view, even if there are flag errors, so that we render
 	// diagnostics according to the desired view
@@ -54,6 +61,11 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// Keep provider plugin processes running between the graph walks of
+	// this command, rather than starting them again for each one.
+	c.startProviderPool()
+	defer c.closeProviderPool()
+
 	// FIXME: the -input flag value is needed to initialize the backend and the
 	// operation, but there is no clear path to pass this value down, so we
 	// continue to mutate the Meta object state for now.
@@ -67,6 +79,17 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	// object state for now.
 	c.Meta.parallelism = args.Operation.Parallelism
 
+	// Start profiling the rest of the command, if requested
+	stopProfiling, profileDiags := c.startProfiling("plan", args.ProfileDir)
+	diags = diags.Append(profileDiags)
+	if profileDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+	defer func() {
+		view.Diagnostics(stopProfiling())
+	}()
+
 	diags = diags.Append(c.providerDevOverrideRuntimeWarnings())
 
 	// Inject variables from args into meta for static evaluation
@@ -80,6 +103,25 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// Reuse the results of the previous plan, if requested
+	savePlanCache, cacheDiags := c.startPlanCache(args.Incremental, args.IncrementalReset, enc.Plan())
+	diags = diags.Append(cacheDiags)
+	if cacheDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+	defer func() {
+		view.Diagnostics(savePlanCache())
+	}()
+
+	// Load the key to sign the plan file with, if any
+	signingKey, keyDiags := c.loadSigningKey(args.SignKeyPath)
+	diags = diags.Append(keyDiags)
+	if keyDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
 	// Prepare the backend with the backend-specific arguments
 	be, beDiags := c.PrepareBackend(args.State, args.ViewType, enc)
 	diags = diags.Append(beDiags)
@@ -88,6 +130,37 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// Fetch the configuration from the given ref, if requested
+	var configDir string
+	var configSource *states.ConfigSource
+	if args.ConfigRef.Enabled() {
+		ctx, done := c.InterruptibleContext(c.CommandContext())
+		var cleanup func()
+		var refDiags tfdiags.Diagnostics
+		configDir, configSource, cleanup, refDiags = c.fetchConfigRef(ctx, args.ConfigRef)
+		done()
+		diags = diags.Append(refDiags)
+		if refDiags.HasErrors() {
+			view.Diagnostics(diags)
+			return 1
+		}
+		defer cleanup()
+	}
+
+	// The interactive display needs to take over a terminal, so without one
+	// we show the normal output instead.
+	if args.TUI {
+		if c.View.InteractiveTerminal() {
+			view = views.NewPlanTUI(c.View)
+		} else {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Interactive display unavailable",
+				"The -tui option requires a terminal for both input and output, so OpenTofu will show the normal output instead.",
+			))
+		}
+	}
+
 	// Build the operation request
 	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, args.Operation, args.OutPath, args.GenerateConfigPath, enc)
 	diags = diags.Append(opDiags)
@@ -95,6 +168,11 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 		view.Diagnostics(diags)
 		return 1
 	}
+	opReq.PlanSigningKey = signingKey
+	if configSource != nil {
+		opReq.ConfigDir = configDir
+		opReq.ConfigSource = configSource
+	}
 
 	// Before we delegate to the backend, we'll print any warning diagnostics
 	// we've accumulated here, since the backend will start fresh with its own
@@ -103,8 +181,17 @@ func (c *PlanCommand) Run(rawArgs []string) int {
 	diags = nil
 
 	// Perform the operation
-	op, diags := c.RunOperation(be, opReq)
+	var op *backend.RunningOperation
+	notify := c.notifyBegin("plan", "plan")
+	defer func() {
+		notify.finish(status, op)
+	}()
+	op, diags = c.RunOperation(be, opReq)
+	diags = diags.Append(exportCheckMetrics(args.Metrics, op))
 	view.Diagnostics(diags)
+	if args.Timings {
+		view.Timings()
+	}
 	if diags.HasErrors() {
 		return 1
 	}
@@ -144,6 +231,35 @@ func (c *PlanCommand) PrepareBackend(args *arguments.State, viewType arguments.V
 	return be, diags
 }
 
+// loadSigningKey reads the OpenPGP private key to sign the plan file with
+// from the given path, or returns nil if the path is empty.
+func (c *PlanCommand) loadSigningKey(path string) (*planfile.SigningKey, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if path == "" {
+		return nil, diags
+	}
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read signing key",
+			fmt.Sprintf("Could not read the plan signing key file: %s.", err),
+		))
+		return nil, diags
+	}
+	key, err := planfile.ParseSigningKey(src)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid signing key",
+			fmt.Sprintf("The file %s cannot be used to sign plans: %s.", path, err),
+		))
+		return nil, diags
+	}
+	return key, diags
+}
+
 func (c *PlanCommand) OperationRequest(
 	be backend.Enhanced,
 	view views.Plan,
@@ -164,7 +280,13 @@ func (c *PlanCommand) OperationRequest(
 	opReq.PlanOutPath = planOutPath
 	opReq.GenerateConfigOut = generateConfigOut
 	opReq.Targets = args.Targets
+	opReq.RefreshFilter = args.RefreshFilter
+	opReq.RefreshParallelism = args.RefreshParallelism
 	opReq.ForceReplace = args.ForceReplace
+	opReq.ForceReplacePatterns = args.ForceReplacePatterns
+	opReq.AdoptExisting = args.AdoptExisting
+	opReq.Policies = c.policyCheckers()
+	opReq.StrictWarnings = args.Strict
 	opReq.Type = backend.OperationTypePlan
 	opReq.View = view.Operation()
 
@@ -195,6 +317,18 @@ func (c *PlanCommand) GatherVariables(args *arguments.Vars) {
 	c.Meta.variableArgs = rawFlags{items: &items}
 }
 
+func (c *PlanCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *PlanCommand) AutocompleteFlags() complete.Flags {
+	flags := c.completeOperationFlags()
+	flags["-detailed-exitcode"] = complete.PredictNothing
+	flags["-out"] = complete.PredictFiles("*")
+	flags["-sign-key"] = complete.PredictFiles("*")
+	return flags
+}
+
 func (c *PlanCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] plan [options]
@@ -212,6 +346,12 @@ Plan Customization Options:
   can also use these options when you run "tofu apply" without passing
   it a saved plan, in order to plan and apply in a single command.
 
+  -adopt-existing     For each resource instance that OpenTofu would create,
+                      ask the provider whether a matching remote object
+                      already exists. If it does, plan to import that object
+                      instead of creating a new one. Only some providers and
+                      resource types support finding existing objects.
+
   -destroy            Select the "destroy" planning mode, which creates a plan
                       to destroy all objects currently managed by this
                       OpenTofu configuration instead of the usual behavior.
@@ -226,11 +366,27 @@ Plan Customization Options:
                       planning faster, but at the expense of possibly planning
                       against a stale record of the remote system state.
 
+  -refresh-filter=resource
+                      Limit checking for external changes to only the given
+                      module, resource, or resource instance. Other objects
+                      are planned against their current record in the state.
+                      You can use this option multiple times to refresh more
+                      than one object.
+
+  -refresh-parallelism=provider=n
+                      Limit the number of concurrent requests made to the
+                      given provider, such as hashicorp/aws, to read the
+                      current state of remote objects. You can use this option
+                      multiple times to set limits for more than one provider.
+
   -replace=resource   Force replacement of a particular resource instance using
                       its resource address. If the plan would've normally
                       produced an update or no-op action for this instance,
                       OpenTofu will plan to replace it instead. You can use
                       this option multiple times to replace more than one object.
+                      The address may contain "*" wildcards, such as
+                      module.workers[*].aws_instance.node, to replace every
+                      matching instance in the current state.
 
   -target=resource    Limit the planning operation to only the given module,
                       resource, or resource instance and all of its
@@ -253,6 +409,15 @@ Other Options:
                              accompanied by errors, shows them in a more compact
                              form that includes only the summary messages.
 
+  -config-ref=addr           Plan using the configuration at an exact ref of a
+                             git repository, such as
+                             git::https://example.com/infra.git?ref=v1.2.3,
+                             instead of the configuration in the working
+                             directory. The ref is recorded in the state.
+
+  -verify-config-ref         Require the ref given by -config-ref to be a tag
+                             with a valid signature.
+
   -detailed-exitcode         Return detailed exit codes when the command exits.
                              This will change the meaning of exit codes to:
                              0 - Succeeded, diff is empty (no changes)
@@ -266,6 +431,16 @@ Other Options:
                              which must not already exist. OpenTofu may still
                              attempt to write configuration if the plan errors.
 
+  -incremental               Cache the providers' responses to plan requests
+                             in this working directory, and reuse them when a
+                             later incremental plan sends an identical
+                             request. Every resource instance is still
+                             evaluated and refreshed.
+
+  -incremental-reset         Like -incremental, but ignore the responses
+                             cached by earlier plans so that every change is
+                             planned in full, then cache the new responses.
+
   -input=true                Ask for input for variables if not directly set.
 
   -lock=false                Don't hold a state lock during the operation. This
@@ -274,20 +449,79 @@ Other Options:
 
   -lock-timeout=0s           Duration to retry a state lock.
 
+  -full-string-diff          Show changes to long and multi-line strings in full,
+                             instead of as an inline word diff with unchanged
+                             lines hidden.
+
+  -metrics-out=path          Write the status of each check in the
+                             configuration, and how many of its conditions
+                             failed, to the given path as Prometheus metrics.
+
+  -metrics-push-url=url      Push the check metrics to the given Prometheus
+                             Pushgateway URL, such as
+                             http://localhost:9091/metrics/job/tofu.
+
   -no-color                  If specified, output won't contain any color.
 
+  -override-set=name         Also load the environment-scoped override files
+                             of the given set, named like "*_override.name.tf".
+                             Defaults to the TF_OVERRIDE_SET environment
+                             variable.
+
   -concise                   Displays plan output in a concise way, skipping the
 							 refreshing log lines.
 
+  -suppress-attribute=name   Hide the values of the given resource attribute in
+                             the rendered changes, only showing whether it
+                             changed. Use "type.name" to only hide it in
+                             resources of that type. Can be used multiple times,
+                             and adds to the comma-separated list in the
+                             TF_SUPPRESS_ATTRIBUTES environment variable.
+
+  -summary                   Show the changes grouped by module and resource
+                             type with the number of each kind of change,
+                             listing the instances to destroy or replace,
+                             instead of showing every change in full.
+
+  -summary-expand=pattern    With -summary, also show in full the changes to
+                             the instances of the given resource type, or of
+                             the given module, resource or instance address.
+                             Can be used multiple times.
+
   -out=path                  Write a plan file to the given path. This can be
                              used as input to the "apply" command.
 
+  -sign-key=path             Sign the plan file with the OpenPGP private key
+                             in the given file. Requires -out.
+
   -parallelism=n             Limit the number of concurrent operations. Defaults
                              to 10.
 
+  -profile=dir               Write CPU, heap, and goroutine profiles of the
+                             operation to the given directory, along with a
+                             summary of the slowest graph nodes, to include
+                             in performance bug reports.
+
   -state=statefile           A legacy option used for the local backend only.
                              See the local backend's documentation for more
                              information.
+
+  -strict                    Treat warnings in any of the warning categories as
+                             errors. Use -strict=category,... to select only
+                             some of the categories: deprecations,
+                             implicit_provider_inheritance, and
+                             undeclared_var_files.
+
+  -timings                   After the operation, show how long OpenTofu spent
+                             refreshing and planning each resource instance,
+                             and how much of that time was spent waiting for
+                             the provider.
+
+  -tui                       Show the progress of the plan in a full-screen,
+                             interactive display, with the status, timing,
+                             changes, and logs of each resource instance,
+                             instead of the scrolling text logs. The display
+                             waits for you to leave it once the plan completes.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -0,0 +1,159 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+	"github.com/opentofu/opentofu/version"
+)
+
+// planCacheFile is the name of the file in the data directory where the
+// -incremental option keeps the responses of providers to plan requests
+// between plans.
+const planCacheFile = "plan-cache.json"
+
+// startPlanCache loads the responses of providers to plan requests from the
+// previous plan with the -incremental option, so that the plan for the
+// current command can reuse them. If incremental planning isn't enabled then
+// this does nothing. If reset is true then the responses from earlier plans
+// are ignored, so that every change is planned in full, but the new responses
+// are still kept for the next incremental plan.
+//
+// The cache contains the planned values of resource instances, so it's
+// encrypted in the same way as saved plan files.
+//
+// The returned function writes the responses that were used by this plan
+// back to the cache file. It must be called once the operation has
+// completed.
+func (m *Meta) startPlanCache(enabled, reset bool, enc encryption.PlanEncryption) (func() tfdiags.Diagnostics, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	noop := func() tfdiags.Diagnostics { return nil }
+	if !enabled && !reset {
+		return noop, diags
+	}
+
+	if len(m.ProviderDevOverrides) > 0 || len(m.UnmanagedProviders) > 0 {
+		// The cache is only valid for the exact provider releases that
+		// were used to build it, which we can't identify for these.
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Incremental planning disabled",
+			"The -incremental option has no effect while using development overrides or unmanaged providers, because the plan cache can't detect changes to those providers.",
+		))
+		return noop, diags
+	}
+
+	fingerprint, fpDiags := m.planCacheFingerprint()
+	diags = diags.Append(fpDiags)
+	if fpDiags.HasErrors() {
+		return nil, diags
+	}
+
+	path := filepath.Join(m.DataDir(), planCacheFile)
+	cache := tofu.NewPlanCache()
+	if reset {
+		log.Printf("[INFO] Ignoring plan cache %s because of -incremental-reset", path)
+	} else {
+		src, err := os.ReadFile(path)
+		if err == nil {
+			src, err = enc.DecryptPlan(src)
+		}
+		if err == nil {
+			cache, err = tofu.ReadPlanCache(bytes.NewReader(src), fingerprint)
+		}
+		if err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				// The cache is only an optimization, so we'll start over.
+				// This includes when the encryption configuration changed.
+				log.Printf("[WARN] Ignoring plan cache %s: %s", path, err)
+			}
+			cache = tofu.NewPlanCache()
+		}
+	}
+
+	// FIXME: like the parallelism, the plan cache reaches the graph walk
+	// through the ContextOpts that the backend is initialized with, so we
+	// mutate the Meta object state here.
+	m.planCache = cache
+
+	return func() tfdiags.Diagnostics {
+		var diags tfdiags.Diagnostics
+
+		hits, misses := cache.Stats()
+		log.Printf("[INFO] Plan cache: reused %d of %d planned changes", hits, hits+misses)
+		if hits+misses == 0 {
+			// The operation failed before planning anything, or didn't
+			// plan at all, so we'll leave the cache as it was.
+			return diags
+		}
+
+		var buf bytes.Buffer
+		err := cache.Write(&buf, fingerprint)
+		var src []byte
+		if err == nil {
+			src, err = enc.EncryptPlan(buf.Bytes())
+		}
+		if err == nil {
+			err = os.MkdirAll(m.DataDir(), 0755)
+		}
+		if err == nil {
+			// The cache contains the planned values of resource instances,
+			// which can be sensitive, so it's only readable by the owner
+			// even when it isn't encrypted.
+			err = os.WriteFile(path, src, 0600)
+		}
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Failed to save plan cache",
+				fmt.Sprintf("Could not write the plan cache %s for the -incremental option: %s. The next incremental plan will plan all changes in full.", path, err),
+			))
+		}
+		return diags
+	}, diags
+}
+
+// planCacheFingerprint returns a digest of the OpenTofu version and the
+// selected provider releases, which the plan cache is only valid for.
+func (m *Meta) planCacheFingerprint() (string, tfdiags.Diagnostics) {
+	locks, diags := m.lockedDependencies()
+	if diags.HasErrors() {
+		return "", diags
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n", version.String())
+	providers := locks.AllProviders()
+	sorted := make([]addrs.Provider, 0, len(providers))
+	for addr := range providers {
+		sorted = append(sorted, addr)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LessThan(sorted[j])
+	})
+	for _, addr := range sorted {
+		lock := providers[addr]
+		fmt.Fprintf(h, "%s %s", addr, lock.Version())
+		for _, hash := range lock.AllHashes() {
+			fmt.Fprintf(h, " %s", hash)
+		}
+		fmt.Fprintln(h)
+	}
+	return hex.EncodeToString(h.Sum(nil)), diags
+}
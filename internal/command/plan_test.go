@@ -9,14 +9,21 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
 	"github.com/davecgh/go-spew/spew"
 	"github.com/zclconf/go-cty/cty"
 
@@ -26,6 +33,7 @@ import (
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/plans/planfile"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -220,6 +228,265 @@ func TestPlan_noState(t *testing.T) {
 	}
 }
 
+func TestPlan_timings(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{"-timings"}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	got := output.Stdout()
+	for _, want := range []string{"Timings (2 resource instances):", "test_instance.foo", "data.test_data_source.a", "Total: "} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestPlan_tuiWithoutTerminal(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-tui"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	// Without a terminal the normal output is shown instead.
+	got := output.All()
+	for _, want := range []string{"Interactive display unavailable", "data.test_data_source.a: Reading..."} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in output:\n%s", want, got)
+		}
+	}
+}
+
+func TestPlan_profile(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{"-profile=profiles"}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	for _, name := range []string{"cpu.pprof", "heap.pprof", "goroutine.pprof"} {
+		if info, err := os.Stat(filepath.Join("profiles", name)); err != nil || info.Size() == 0 {
+			t.Errorf("missing profile %s: %v", name, err)
+		}
+	}
+	summary, err := os.ReadFile(filepath.Join("profiles", "summary.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"tofu plan", "Top ", "test_instance.foo", "data.test_data_source.a"} {
+		if !strings.Contains(string(summary), want) {
+			t.Errorf("missing %q in summary:\n%s", want, summary)
+		}
+	}
+}
+
+func TestPlan_incremental(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	p := planFixtureProvider()
+	for i := 0; i < 2; i++ {
+		view, done := testView(t)
+		c := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+
+		p.PlanResourceChangeCalled = false
+		code := c.Run([]string{"-incremental"})
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+		}
+		if got, want := p.PlanResourceChangeCalled, i == 0; got != want {
+			t.Errorf("wrong PlanResourceChangeCalled %t for plan %d", got, i+1)
+		}
+	}
+
+	info, err := os.Stat(filepath.Join(DefaultDataDir, planCacheFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("wrong plan cache permissions %s", info.Mode().Perm())
+	}
+}
+
+func TestPlan_incrementalReset(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	// -incremental-reset plans in full even though the cache has a response
+	// for the same request, and then the next incremental plan reuses the
+	// responses that it cached.
+	p := planFixtureProvider()
+	for i, args := range [][]string{{"-incremental"}, {"-incremental-reset"}, {"-incremental"}} {
+		view, done := testView(t)
+		c := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+
+		p.PlanResourceChangeCalled = false
+		code := c.Run(args)
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+		}
+		if got, want := p.PlanResourceChangeCalled, i < 2; got != want {
+			t.Errorf("wrong PlanResourceChangeCalled %t for plan %d with %s", got, i+1, args)
+		}
+	}
+}
+
+func TestPlan_incrementalEncrypted(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	t.Setenv("TF_ENCRYPTION", `
+key_provider "pbkdf2" "test" {
+  passphrase = "a passphrase for testing the plan cache"
+}
+method "aes_gcm" "test" {
+  keys = key_provider.pbkdf2.test
+}
+plan {
+  method = method.aes_gcm.test
+}
+`)
+
+	p := planFixtureProvider()
+	for i := 0; i < 2; i++ {
+		view, done := testView(t)
+		c := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+
+		p.PlanResourceChangeCalled = false
+		code := c.Run([]string{"-incremental"})
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+		}
+		if got, want := p.PlanResourceChangeCalled, i == 0; got != want {
+			t.Errorf("wrong PlanResourceChangeCalled %t for plan %d", got, i+1)
+		}
+	}
+
+	// The cache is encrypted like a saved plan, so the planned values
+	// aren't written out in plaintext.
+	src, err := os.ReadFile(filepath.Join(DefaultDataDir, planCacheFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(src), "planned_state") {
+		t.Errorf("plan cache isn't encrypted:\n%s", src)
+	}
+}
+
+func TestPlan_metrics(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-checks"), td)
+	defer testChdir(t, td)()
+
+	var pushed []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/metrics/job/tofu" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		pushed, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{"-metrics-out=checks.prom", "-metrics-push-url=" + server.URL + "/metrics/job/tofu"}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	written, err := os.ReadFile("checks.prom")
+	if err != nil {
+		t.Fatalf("metrics weren't written: %s", err)
+	}
+	for _, want := range []string{
+		`tofu_check_status{address="check.health",kind="check",status="fail"} 1`,
+		`tofu_check_status{address="output.answer",kind="output_value",status="pass"} 1`,
+		`tofu_check_failures{address="check.health",kind="check"} 1`,
+		`tofu_check_instances{status="pass"} 1`,
+		"tofu_check_evaluation_timestamp_seconds ",
+	} {
+		if !strings.Contains(string(written), want) {
+			t.Errorf("missing %q in metrics:\n%s", want, written)
+		}
+	}
+	if !bytes.Equal(written, pushed) {
+		t.Errorf("pushed metrics differ from the written metrics:\n%s", pushed)
+	}
+}
+
 func TestPlan_generatedConfigPath(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath("plan-import-config-gen"), td)
@@ -293,6 +560,126 @@ func TestPlan_outPath(t *testing.T) {
 	testReadPlan(t, outPath) // will call t.Fatal itself if the file cannot be read
 }
 
+func TestPlan_signKey(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+	t.Setenv(planfile.CreatorEnvVar, "tester")
+
+	outPath := filepath.Join(td, "test.plan")
+	privateKey, publicKey := testPlanSigningKeys(t, "Plan Signer <signer@example.com>")
+
+	p := planFixtureProvider()
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-out", outPath,
+		"-sign-key", privateKey,
+	}
+	code := c.Run(args)
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	pf, err := planfile.Open(outPath, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta, err := pf.ReadMetadata()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Creator != "tester" || meta.Backend != "local" || meta.Workspace != "default" {
+		t.Errorf("wrong metadata %#v", meta)
+	}
+
+	src, err := os.ReadFile(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys, err := planfile.ParseVerifyingKeys(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pf.VerifySignature(keys); err != nil {
+		t.Fatalf("failed to verify plan signature: %s", err)
+	}
+}
+
+func TestPlan_signKeyInvalid(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan"), td)
+	defer testChdir(t, td)()
+
+	_, publicKey := testPlanSigningKeys(t, "Plan Signer <signer@example.com>")
+
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(planFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-out", filepath.Join(td, "test.plan"), "-sign-key", publicKey})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\n\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "does not contain a private key"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant: %s", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(td, "test.plan")); !os.IsNotExist(err) {
+		t.Fatalf("plan file should not be written")
+	}
+}
+
+// testPlanSigningKeys generates a new OpenPGP key with the given identity,
+// like "Name <email>", and writes its ASCII-armored private and public keys
+// into files, returning their paths.
+func testPlanSigningKeys(t *testing.T, identity string) (privatePath, publicPath string) {
+	t.Helper()
+
+	name, email, _ := strings.Cut(strings.TrimSuffix(identity, ">"), " <")
+	entity, err := openpgp.NewEntity(name, "", email, &packet.Config{
+		Algorithm: packet.PubKeyAlgoEdDSA,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	privatePath = filepath.Join(dir, "private.asc")
+	publicPath = filepath.Join(dir, "public.asc")
+	for path, blockType := range map[string]string{privatePath: openpgp.PrivateKeyType, publicPath: openpgp.PublicKeyType} {
+		var buf bytes.Buffer
+		w, err := armor.Encode(&buf, blockType, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if blockType == openpgp.PrivateKeyType {
+			err = entity.SerializePrivate(w, nil)
+		} else {
+			err = entity.Serialize(w)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Close()
+		if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return privatePath, publicPath
+}
+
 func TestPlan_outPathNoChange(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath("plan"), td)
@@ -1089,6 +1476,119 @@ func TestPlan_varFileDefault(t *testing.T) {
 	}
 }
 
+func TestPlan_varFileSOPS(t *testing.T) {
+	testCases := map[string]struct {
+		filename  string
+		plaintext string
+		args      func(path string) []string
+	}{
+		"YAML -var-file": {
+			filename:  "secrets.sops.yaml",
+			plaintext: "foo: bar\n",
+			args:      func(path string) []string { return []string{"-var-file", path} },
+		},
+		"JSON auto file": {
+			filename:  "secrets.auto.tfvars.sops.json",
+			plaintext: `{"foo": "bar"}`,
+			args:      func(string) []string { return nil },
+		},
+		"HCL -var-file": {
+			filename:  "secrets.sops.tfvars",
+			plaintext: planVarFile,
+			args:      func(path string) []string { return []string{"-var-file", path} },
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			td := t.TempDir()
+			testCopyDir(t, testFixturePath("plan-vars"), td)
+			defer testChdir(t, td)()
+			testFakeSOPS(t)
+
+			// The fake SOPS decrypts a file by reading the plaintext from
+			// the file alongside it.
+			varFilePath := filepath.Join(td, tc.filename)
+			if err := os.WriteFile(varFilePath, []byte("encrypted"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(varFilePath+".plain", []byte(tc.plaintext), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			p := planVarsFixtureProvider()
+			view, done := testView(t)
+			c := &PlanCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(p),
+					View:             view,
+				},
+			}
+
+			actual := ""
+			p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+				actual = req.ProposedNewState.GetAttr("value").AsString()
+				resp.PlannedState = req.ProposedNewState
+				return
+			}
+
+			code := c.Run(tc.args(varFilePath))
+			output := done(t)
+			if code != 0 {
+				t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+			}
+			if actual != "bar" {
+				t.Fatalf("wrong value %q", actual)
+			}
+		})
+	}
+}
+
+func TestPlan_varFileSOPSFailure(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-vars"), td)
+	defer testChdir(t, td)()
+	testFakeSOPS(t)
+
+	// Without the plaintext alongside it, the fake SOPS fails.
+	varFilePath := filepath.Join(td, "secrets.sops.yaml")
+	if err := os.WriteFile(varFilePath, []byte("encrypted"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	view, done := testView(t)
+	c := &PlanCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(planVarsFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-var-file", varFilePath})
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\n%s", code, output.Stdout())
+	}
+	if got, want := output.Stderr(), "Failed to decrypt variables file"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot: %s\nwant: %s", got, want)
+	}
+}
+
+// testFakeSOPS puts a fake sops executable first in the PATH, which
+// "decrypts" a file by printing the file with the same name plus ".plain".
+func testFakeSOPS(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake sops is a shell script")
+	}
+	dir := t.TempDir()
+	script := "#!/bin/sh\n[ \"$1\" = \"--decrypt\" ] || exit 2\nexec cat \"$2.plain\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "sops"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
 func TestPlan_varFileWithDecls(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
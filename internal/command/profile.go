@@ -0,0 +1,154 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"text/tabwriter"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+	"github.com/opentofu/opentofu/version"
+)
+
+// profileSummaryLimit is the number of graph nodes listed in the summary
+// written by the -profile option.
+const profileSummaryLimit = 25
+
+// The names of the files written to the -profile directory.
+const (
+	profileCPUFile       = "cpu.pprof"
+	profileHeapFile      = "heap.pprof"
+	profileGoroutineFile = "goroutine.pprof"
+	profileSummaryFile   = "summary.txt"
+)
+
+// startProfiling starts a CPU profile of the rest of the given command and
+// records the time spent executing each graph node, for the -profile option.
+// If the given directory is empty then profiling is disabled.
+//
+// The returned function stops profiling and writes the heap and goroutine
+// profiles and the summary of the slowest graph nodes to the directory. It
+// must be called once the operation has completed.
+func (m *Meta) startProfiling(command, dir string) (func() tfdiags.Diagnostics, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	if dir == "" {
+		return func() tfdiags.Diagnostics { return nil }, diags
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to create profile directory",
+			fmt.Sprintf("Could not create the directory %s for the -profile option: %s.", dir, err),
+		))
+		return nil, diags
+	}
+
+	cpuPath := filepath.Join(dir, profileCPUFile)
+	cpu, err := os.Create(cpuPath)
+	if err == nil {
+		err = pprof.StartCPUProfile(cpu)
+		if err != nil {
+			cpu.Close()
+		}
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to start profiling",
+			fmt.Sprintf("Could not start the CPU profile %s: %s.", cpuPath, err),
+		))
+		return nil, diags
+	}
+
+	// FIXME: like the parallelism, the node timings reach the graph walk
+	// through the ContextOpts that the backend is initialized with, so we
+	// mutate the Meta object state here.
+	timings := tofu.NewNodeTimings()
+	m.nodeTimings = timings
+	start := time.Now()
+
+	stop := func() tfdiags.Diagnostics {
+		var diags tfdiags.Diagnostics
+
+		pprof.StopCPUProfile()
+		if err := cpu.Close(); err != nil {
+			diags = diags.Append(profileWriteError(cpuPath, err))
+		}
+		elapsed := time.Since(start)
+
+		// Collect garbage first so that the heap profile reflects the live
+		// objects at the end of the operation.
+		runtime.GC()
+		diags = diags.Append(writeProfile("heap", filepath.Join(dir, profileHeapFile)))
+		diags = diags.Append(writeProfile("goroutine", filepath.Join(dir, profileGoroutineFile)))
+
+		summaryPath := filepath.Join(dir, profileSummaryFile)
+		summary := profileSummary(command, elapsed, timings.Top(profileSummaryLimit))
+		if err := os.WriteFile(summaryPath, summary, 0644); err != nil {
+			diags = diags.Append(profileWriteError(summaryPath, err))
+		}
+		return diags
+	}
+	return stop, diags
+}
+
+// writeProfile writes the named runtime profile to the given path in the
+// compressed protocol buffer format read by "go tool pprof".
+func writeProfile(name, path string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	f, err := os.Create(path)
+	if err == nil {
+		err = pprof.Lookup(name).WriteTo(f, 0)
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		diags = diags.Append(profileWriteError(path, err))
+	}
+	return diags
+}
+
+func profileWriteError(path string, err error) tfdiags.Diagnostic {
+	return tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Failed to write profile",
+		fmt.Sprintf("Could not write the profile %s: %s.", path, err),
+	)
+}
+
+// profileSummary renders the human-readable summary of the slowest graph
+// nodes, which is intended to be attached to performance bug reports along
+// with the profiles.
+func profileSummary(command string, elapsed time.Duration, nodes []tofu.NodeTiming) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "OpenTofu v%s, tofu %s\n", version.String(), command)
+	fmt.Fprintf(&buf, "%s/%s, %s, GOMAXPROCS=%d\n", runtime.GOOS, runtime.GOARCH, runtime.Version(), runtime.GOMAXPROCS(0))
+	fmt.Fprintf(&buf, "Total time: %.3fs\n\n", elapsed.Seconds())
+
+	if len(nodes) == 0 {
+		buf.WriteString("No graph nodes were executed locally.\n")
+		return buf.Bytes()
+	}
+
+	fmt.Fprintf(&buf, "Top %d graph nodes by wall time:\n\n", len(nodes))
+	tw := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "WALL TIME\tRUNS\t\n")
+	for _, node := range nodes {
+		fmt.Fprintf(tw, "%.3fs\t%d\t  %s\n", node.Duration.Seconds(), node.Count, node.Name)
+	}
+	tw.Flush()
+	return buf.Bytes()
+}
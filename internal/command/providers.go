@@ -6,12 +6,15 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/xlab/treeprint"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -33,11 +36,13 @@ func (c *ProvidersCommand) Synopsis() string {
 
 func (c *ProvidersCommand) Run(args []string) int {
 	var testsDirectory string
+	var jsonOutput bool
 
 	args = c.Meta.process(args)
 	cmdFlags := c.Meta.defaultFlagSet("providers")
 	c.Meta.varFlagSet(cmdFlags)
 	cmdFlags.StringVar(&testsDirectory, "test-directory", "tests", "test-directory")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -133,6 +138,31 @@ func (c *ProvidersCommand) Run(args []string) int {
 		stateReqs = state.ProviderRequirements()
 	}
 
+	configReqs, configReqsDiags := config.ProviderRequirements()
+	diags = diags.Append(configReqsDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	stateOnly := providersOnlyInState(configReqs, stateReqs)
+	unused := unusedRequiredProviders(config)
+	conflicts := providerVersionConflicts(reqs)
+
+	if jsonOutput {
+		js, err := json.MarshalIndent(marshalProvidersJSON(reqs, stateReqs, stateOnly, unused, conflicts), "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal providers to json: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(js))
+		c.showDiagnostics(diags)
+		if diags.HasErrors() {
+			return 1
+		}
+		return 0
+	}
+
 	printRoot := treeprint.New()
 	c.populateTreeNode(printRoot, reqs)
 
@@ -146,6 +176,33 @@ func (c *ProvidersCommand) Run(args []string) int {
 		}
 	}
 
+	if len(stateOnly) > 0 {
+		c.Ui.Output("Providers required by state but not by configuration:\n")
+		for _, fqn := range stateOnly {
+			c.Ui.Output(fmt.Sprintf("    provider[%s]\n", fqn.String()))
+		}
+		c.Ui.Output("  These providers are only needed to destroy the objects that remain in the\n  state. They are no longer needed once those objects have been destroyed.\n")
+	}
+
+	if len(unused) > 0 {
+		c.Ui.Output("Providers declared in required_providers but not used:\n")
+		for _, u := range unused {
+			c.Ui.Output(fmt.Sprintf("    provider[%s] (%q) in %s\n", u.Provider.String(), u.LocalName, providersModuleName(u.Module)))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		c.Ui.Output("Providers with conflicting version constraints:\n")
+		for _, conflict := range conflicts {
+			c.Ui.Output(fmt.Sprintf("    provider[%s]", conflict.Provider.String()))
+			for _, req := range conflict.Requirements {
+				c.Ui.Output(fmt.Sprintf("        %s: %s", providersModuleName(req.Module), getproviders.VersionConstraintsString(req.Constraints)))
+			}
+			c.Ui.Output("")
+		}
+		c.Ui.Output("  No version of these providers can meet the constraints of all of the\n  modules, so \"tofu init\" will fail to install them.\n")
+	}
+
 	c.showDiagnostics(diags)
 	if diags.HasErrors() {
 		return 1
@@ -185,6 +242,270 @@ func (c *ProvidersCommand) populateTreeNode(tree treeprint.Tree, node *configs.M
 	}
 }
 
+// moduleProviderRequirement is the version constraints on a provider from a
+// single module.
+type moduleProviderRequirement struct {
+	Module      addrs.Module
+	Provider    addrs.Provider
+	Constraints getproviders.VersionConstraints
+}
+
+// unusedProvider is a provider declared in the required_providers block of a
+// module which isn't used by that module or any of its descendants.
+type unusedProvider struct {
+	Module    addrs.Module
+	LocalName string
+	Provider  addrs.Provider
+}
+
+// providerVersionConflict is a provider whose version constraints across all
+// modules can't be met by any single version.
+type providerVersionConflict struct {
+	Provider     addrs.Provider
+	Requirements []moduleProviderRequirement
+}
+
+// providersOnlyInState returns the providers that are required by the state
+// but not by the configuration, sorted by address.
+func providersOnlyInState(configReqs, stateReqs getproviders.Requirements) []addrs.Provider {
+	var ret []addrs.Provider
+	for fqn := range stateReqs {
+		if _, exists := configReqs[fqn]; !exists {
+			ret = append(ret, fqn)
+		}
+	}
+	sortProviders(ret)
+	return ret
+}
+
+// unusedRequiredProviders returns the providers which are declared in the
+// required_providers block of a module but not used by any resource, data
+// source, import block, provider configuration, or module call in that module
+// or its descendants, or by the tests of the root module.
+func unusedRequiredProviders(config *configs.Config) []unusedProvider {
+	var ret []unusedProvider
+	for _, c := range config.AllModules() {
+		if c.Module.ProviderRequirements == nil {
+			continue
+		}
+		used := make(map[addrs.Provider]bool)
+		c.DeepEach(func(c *configs.Config) {
+			addUsedProviders(used, c.Module)
+		})
+		if c.Path.IsRoot() {
+			for _, file := range c.Module.Tests {
+				for _, provider := range file.Providers {
+					used[c.Module.ImpliedProviderForUnqualifiedType(provider.Name)] = true
+				}
+			}
+		}
+		for localName, req := range c.Module.ProviderRequirements.RequiredProviders {
+			if !used[req.Type] {
+				ret = append(ret, unusedProvider{
+					Module:    c.Path,
+					LocalName: localName,
+					Provider:  req.Type,
+				})
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if a, b := ret[i].Module.String(), ret[j].Module.String(); a != b {
+			return a < b
+		}
+		return ret[i].LocalName < ret[j].LocalName
+	})
+	return ret
+}
+
+// addUsedProviders records the providers used directly by the given module.
+func addUsedProviders(used map[addrs.Provider]bool, mod *configs.Module) {
+	for _, rc := range mod.ManagedResources {
+		used[rc.Provider] = true
+	}
+	for _, rc := range mod.DataResources {
+		used[rc.Provider] = true
+	}
+	for _, check := range mod.Checks {
+		if check.DataResource != nil {
+			used[check.DataResource.Provider] = true
+		}
+	}
+	for _, i := range mod.Import {
+		if !i.Provider.IsZero() {
+			used[i.Provider] = true
+		}
+	}
+	for _, provider := range mod.ProviderConfigs {
+		used[mod.ImpliedProviderForUnqualifiedType(provider.Name)] = true
+	}
+	for _, mc := range mod.ModuleCalls {
+		for _, passed := range mc.Providers {
+			used[mod.ImpliedProviderForUnqualifiedType(passed.InParent.Name)] = true
+		}
+	}
+}
+
+// providerVersionConflicts returns the providers whose version constraints,
+// combined across all of the modules in the given requirements, can't be met
+// by any version, sorted by address.
+func providerVersionConflicts(reqs *configs.ModuleRequirements) []providerVersionConflict {
+	byProvider := make(map[addrs.Provider][]moduleProviderRequirement)
+	for _, req := range flattenModuleRequirements(reqs, addrs.RootModule) {
+		if len(req.Constraints) > 0 {
+			byProvider[req.Provider] = append(byProvider[req.Provider], req)
+		}
+	}
+
+	var ret []providerVersionConflict
+	for fqn, modReqs := range byProvider {
+		var all getproviders.VersionConstraints
+		for _, req := range modReqs {
+			all = append(all, req.Constraints...)
+		}
+		if !getproviders.VersionConstraintsSatisfiable(all) {
+			ret = append(ret, providerVersionConflict{
+				Provider:     fqn,
+				Requirements: modReqs,
+			})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Provider.LessThan(ret[j].Provider)
+	})
+	return ret
+}
+
+// flattenModuleRequirements returns the requirements of the module at the
+// given path and all of its descendants, sorted by module and then provider.
+// The requirements of tests are not included.
+func flattenModuleRequirements(node *configs.ModuleRequirements, path addrs.Module) []moduleProviderRequirement {
+	var ret []moduleProviderRequirement
+	var providers []addrs.Provider
+	for fqn := range node.Requirements {
+		providers = append(providers, fqn)
+	}
+	sortProviders(providers)
+	for _, fqn := range providers {
+		ret = append(ret, moduleProviderRequirement{
+			Module:      path,
+			Provider:    fqn,
+			Constraints: node.Requirements[fqn],
+		})
+	}
+
+	names := make([]string, 0, len(node.Children))
+	for name := range node.Children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		ret = append(ret, flattenModuleRequirements(node.Children[name], path.Child(name))...)
+	}
+	return ret
+}
+
+func sortProviders(providers []addrs.Provider) {
+	sort.Slice(providers, func(i, j int) bool {
+		return providers[i].LessThan(providers[j])
+	})
+}
+
+// providersModuleName returns the name of the given module for the human
+// output of the providers command.
+func providersModuleName(module addrs.Module) string {
+	if module.IsRoot() {
+		return "the root module"
+	}
+	return module.String()
+}
+
+// providersFormatVersion is the version of the JSON output of
+// "tofu providers -json".
+const providersFormatVersion = "1.0"
+
+type providersJSON struct {
+	FormatVersion    string                    `json:"format_version"`
+	Configuration    []providerRequirementJSON `json:"configuration"`
+	State            []string                  `json:"state"`
+	StateOnly        []string                  `json:"state_only"`
+	Unused           []unusedProviderJSON      `json:"unused"`
+	VersionConflicts []providerConflictJSON    `json:"version_conflicts"`
+}
+
+type providerRequirementJSON struct {
+	Module             string `json:"module"`
+	Provider           string `json:"provider"`
+	VersionConstraints string `json:"version_constraints,omitempty"`
+}
+
+type unusedProviderJSON struct {
+	Module    string `json:"module"`
+	LocalName string `json:"local_name"`
+	Provider  string `json:"provider"`
+}
+
+type providerConflictJSON struct {
+	Provider     string                    `json:"provider"`
+	Requirements []providerRequirementJSON `json:"requirements"`
+}
+
+func marshalProvidersJSON(reqs *configs.ModuleRequirements, stateReqs getproviders.Requirements, stateOnly []addrs.Provider, unused []unusedProvider, conflicts []providerVersionConflict) providersJSON {
+	ret := providersJSON{
+		FormatVersion:    providersFormatVersion,
+		Configuration:    []providerRequirementJSON{},
+		State:            []string{},
+		StateOnly:        []string{},
+		Unused:           []unusedProviderJSON{},
+		VersionConflicts: []providerConflictJSON{},
+	}
+
+	for _, req := range flattenModuleRequirements(reqs, addrs.RootModule) {
+		ret.Configuration = append(ret.Configuration, marshalProviderRequirementJSON(req))
+	}
+
+	var stateProviders []addrs.Provider
+	for fqn := range stateReqs {
+		stateProviders = append(stateProviders, fqn)
+	}
+	sortProviders(stateProviders)
+	for _, fqn := range stateProviders {
+		ret.State = append(ret.State, fqn.String())
+	}
+
+	for _, fqn := range stateOnly {
+		ret.StateOnly = append(ret.StateOnly, fqn.String())
+	}
+
+	for _, u := range unused {
+		ret.Unused = append(ret.Unused, unusedProviderJSON{
+			Module:    u.Module.String(),
+			LocalName: u.LocalName,
+			Provider:  u.Provider.String(),
+		})
+	}
+
+	for _, conflict := range conflicts {
+		conflictJSON := providerConflictJSON{
+			Provider: conflict.Provider.String(),
+		}
+		for _, req := range conflict.Requirements {
+			conflictJSON.Requirements = append(conflictJSON.Requirements, marshalProviderRequirementJSON(req))
+		}
+		ret.VersionConflicts = append(ret.VersionConflicts, conflictJSON)
+	}
+
+	return ret
+}
+
+func marshalProviderRequirementJSON(req moduleProviderRequirement) providerRequirementJSON {
+	return providerRequirementJSON{
+		Module:             req.Module.String(),
+		Provider:           req.Provider.String(),
+		VersionConstraints: getproviders.VersionConstraintsString(req.Constraints),
+	}
+}
+
 const providersCommandHelp = `
 Usage: tofu [global options] providers [options] [DIR]
 
@@ -195,8 +516,15 @@ Usage: tofu [global options] providers [options] [DIR]
   referenced modules, as an aid to understanding why particular provider
   plugins are needed and why particular versions are selected.
 
+  It also reports providers that are required by the state but no longer by
+  the configuration, providers declared in required_providers that are never
+  used, and providers whose version constraints conflict between modules.
+
 Options:
 
+  -json                 Output the provider requirements and any problems
+                        found in a machine-readable form.
+
   -test-directory=path  Set the OpenTofu test directory, defaults to "tests". When set, the
                         test command will search for test files in the current directory and
                         in the one specified by the flag.
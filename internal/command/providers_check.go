@@ -0,0 +1,207 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// ProvidersCheckCommand is a Command implementation that configures each
+// provider in the current configuration and reports whether the
+// configuration works.
+type ProvidersCheckCommand struct {
+	Meta
+}
+
+func (c *ProvidersCheckCommand) Help() string {
+	return providersCheckCommandHelp
+}
+
+func (c *ProvidersCheckCommand) Synopsis() string {
+	return "Check that the providers' credentials and endpoints work"
+}
+
+func (c *ProvidersCheckCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.extendedFlagSet("providers check")
+	cmdFlags.StringVar(&c.Meta.statePath, "state", DefaultStateFilename, "path")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The providers check command expects no positional arguments.")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	configPath := c.Meta.normalizePath(".")
+
+	// Check for user-supplied plugin path
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error loading plugin path: %s", err))
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	backendConfig, backendDiags := c.loadBackendConfig(configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// We require a local backend
+	local, ok := b.(backend.Local)
+	if !ok {
+		c.showDiagnostics(diags) // in case of any warnings in here
+		c.Ui.Error(ErrUnsupportedLocalOp)
+		return 1
+	}
+
+	// This command only reads the state
+	c.ignoreRemoteVersionConflict(b)
+
+	// Build the operation
+	opReq := c.Operation(b, arguments.ViewHuman, enc)
+	opReq.ConfigDir = configPath
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(err)
+		c.showDiagnostics(diags)
+		return 1
+	}
+	{
+		// Setup required variables/call for operation (usually done in Meta.RunOperation)
+		var moreDiags, callDiags tfdiags.Diagnostics
+		opReq.Variables, moreDiags = c.collectVariableValues()
+		opReq.RootCall, callDiags = c.rootModuleCall(opReq.ConfigDir)
+		diags = diags.Append(moreDiags).Append(callDiags)
+		if moreDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+	}
+
+	// Get the context
+	lr, _, ctxDiags := local.LocalRun(opReq)
+	diags = diags.Append(ctxDiags)
+	if ctxDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Successfully creating the context can result in a lock, so ensure we release it
+	defer func() {
+		diags := opReq.StateLocker.Unlock()
+		if diags.HasErrors() {
+			c.showDiagnostics(diags)
+		}
+	}()
+
+	checkOpts := &tofu.CheckProvidersOpts{}
+	if lr.PlanOpts != nil {
+		// the LocalRun type is built primarily to support the main operations,
+		// so the variable values end up in the "PlanOpts" even though we're
+		// not actually making a plan.
+		checkOpts.SetVariables = lr.PlanOpts.SetVariables
+	}
+	results, moreDiags := lr.Core.CheckProviders(lr.Config, lr.InputState, checkOpts)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	failed := 0
+	for _, result := range results {
+		diags = diags.Append(result.Diagnostics)
+
+		var status string
+		switch result.Status {
+		case checks.StatusPass:
+			status = "[green]ok"
+			if !result.HealthChecked {
+				status += "[reset] (configured; the provider has no health check)"
+			}
+		case checks.StatusUnknown:
+			status = "[yellow]skipped[reset] (the configuration depends on values that won't be known until apply)"
+		default:
+			status = "[red]failed"
+			failed++
+		}
+		c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+			"- %s (%s): %s", result.Addr.StringCompact(), result.Provider.ForDisplay(), status,
+		)))
+	}
+
+	c.showDiagnostics(diags)
+	if failed > 0 || diags.HasErrors() {
+		c.Ui.Error(fmt.Sprintf("\n%d of %d provider configurations failed the check.", failed, len(results)))
+		return 1
+	}
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("\n[reset][green]All %d provider configurations passed the check.", len(results))))
+	return 0
+}
+
+const providersCheckCommandHelp = `
+Usage: tofu [global options] providers check [options]
+
+  Configure each provider in the current configuration and check that its
+  configuration works, without planning or reading any data sources, so that
+  problems such as invalid credentials or unreachable endpoints are found
+  before running a plan.
+
+  Each provider configuration in the root module is checked, as is the
+  default configuration of each other provider that the configuration
+  requires. Providers that offer a health check, such as checking that their
+  credentials are accepted, are checked with it; other providers are only
+  configured.
+
+  Provider configurations that depend on values that won't be known until
+  apply, such as attributes of resources that don't exist yet, are skipped.
+
+Options:
+
+  -input=true       Ask for input for variables if not directly set.
+
+  -state=path       Legacy option for the local backend only. See the local
+                    backend's documentation for more information.
+
+  -var 'foo=bar'    Set a variable in the OpenTofu configuration. This
+                    flag can be set multiple times.
+
+  -var-file=foo     Set variables in the OpenTofu configuration from
+                    a file. If "terraform.tfvars" or any ".auto.tfvars"
+                    files are present, they will be automatically loaded.
+`
@@ -0,0 +1,106 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// healthProvider is a MockProvider with a health check that returns the
+// given diagnostics.
+type healthProvider struct {
+	*tofu.MockProvider
+
+	checked bool
+	diags   tfdiags.Diagnostics
+}
+
+func (p *healthProvider) CheckHealth(providers.CheckHealthRequest) providers.CheckHealthResponse {
+	p.checked = true
+	return providers.CheckHealthResponse{Diagnostics: p.diags}
+}
+
+func TestProvidersCheck(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	p := &healthProvider{MockProvider: applyFixtureProvider()}
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &ProvidersCheckCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if !p.ConfigureProviderCalled {
+		t.Error("provider was not configured")
+	}
+	if !p.checked {
+		t.Error("provider was not health checked")
+	}
+	if p.PlanResourceChangeCalled || p.ReadResourceCalled {
+		t.Error("provider was used to plan")
+	}
+	output := ui.OutputWriter.String()
+	if want := "- test (hashicorp/test): ok"; !strings.Contains(output, want) {
+		t.Errorf("wrong output\ngot:  %s\nwant: %s", output, want)
+	}
+}
+
+func TestProvidersCheck_failed(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	p := &healthProvider{MockProvider: applyFixtureProvider()}
+	p.diags = p.diags.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Invalid credentials",
+		"The API rejected the configured access key.",
+	))
+	ui := new(cli.MockUi)
+	view, done := testView(t)
+	c := &ProvidersCheckCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	done(t)
+	if code != 1 {
+		t.Fatalf("wrong exit code %d; want 1", code)
+	}
+
+	if got, want := ui.OutputWriter.String(), "- test (hashicorp/test): failed"; !strings.Contains(got, want) {
+		t.Errorf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+	errOutput := ui.ErrorWriter.String()
+	for _, want := range []string{"Invalid credentials", "1 of 1 provider configurations failed the check."} {
+		if !strings.Contains(errOutput, want) {
+			t.Errorf("error output doesn't contain %q\n%s", want, errOutput)
+		}
+	}
+}
@@ -11,10 +11,12 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/apparentlymart/go-versions/versions"
 	"github.com/hashicorp/go-getter"
 
+	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/getproviders"
 	"github.com/opentofu/opentofu/internal/httpclient"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -37,7 +39,9 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	cmdFlags := c.Meta.defaultFlagSet("providers mirror")
 	c.Meta.varFlagSet(cmdFlags)
 	var optPlatforms FlagStringSlice
+	var optLockFile string
 	cmdFlags.Var(&optPlatforms, "platform", "target platform")
+	cmdFlags.StringVar(&optLockFile, "lock-file", "", "lock file path")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -58,12 +62,20 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	}
 	outputDir := args[0]
 
+	// If allPlatforms is set then we mirror every platform that the origin
+	// registry has a package for, which we can only find out once we've
+	// selected a version of each provider.
 	var platforms []getproviders.Platform
+	allPlatforms := false
 	if len(optPlatforms) == 0 {
 		platforms = []getproviders.Platform{getproviders.CurrentPlatform}
 	} else {
 		platforms = make([]getproviders.Platform, 0, len(optPlatforms))
 		for _, platformStr := range optPlatforms {
+			if platformStr == "all" {
+				allPlatforms = true
+				continue
+			}
 			platform, err := getproviders.ParsePlatform(platformStr)
 			if err != nil {
 				diags = diags.Append(tfdiags.Sourceless(
@@ -111,9 +123,13 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 	// for every provider so that it can be used to update a local mirror
 	// directory without needing to first disable that local mirror
 	// in the CLI configuration.
-	source := getproviders.NewMemoizeSource(
-		getproviders.NewRegistrySource(c.Services),
-	)
+	registrySource := getproviders.NewRegistrySource(c.Services)
+	source := getproviders.NewMemoizeSource(registrySource)
+
+	// If requested, we also write a dependency lock file that records all of
+	// the hashes we learn about while mirroring, so that it can be used
+	// offline together with the mirror.
+	newLocks := depsfile.NewLocks()
 
 	// Providers from registries always use HTTP, so we don't need the full
 	// generality of go-getter but it's still handy to use the HTTP getter
@@ -171,7 +187,27 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 		} else {
 			c.Ui.Output(fmt.Sprintf("  - Selected v%s with no constraints", selected.String()))
 		}
-		for _, platform := range platforms {
+		providerPlatforms := platforms
+		if allPlatforms {
+			providerPlatforms, err = registrySource.AvailablePlatforms(ctx, provider, selected)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Provider release not available",
+					fmt.Sprintf("Failed to find the target platforms of %s v%s: %s.", provider.String(), selected.String(), err),
+				))
+				continue
+			}
+		}
+		var hashes []getproviders.Hash
+		if lock := lockedDeps.Provider(provider); lock != nil && lock.Version() == selected {
+			// As for "tofu init", we keep the hashes that are already
+			// recorded for the selected version.
+			hashes = append(hashes, lock.AllHashes()...)
+		}
+		savedChecksums := false
+		diagsBefore := len(diags)
+		for _, platform := range providerPlatforms {
 			c.Ui.Output(fmt.Sprintf("  - Downloading package for %s...", platform.String()))
 			meta, err := source.PackageMeta(ctx, provider, selected, platform)
 			if err != nil {
@@ -233,6 +269,16 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 					continue
 				}
 				c.Ui.Output(fmt.Sprintf("  - Package authenticated: %s", result))
+				if result != nil && (result.Signed() || result.SigningSkipped()) {
+					// As for "tofu init", we trust the checksums of the
+					// packages for other platforms too if they are signed.
+					hashes = append(hashes, meta.AcceptableHashes()...)
+				}
+				if !savedChecksums {
+					moreDiags := mirrorSignedChecksums(meta, filepath.Dir(targetPath))
+					diags = diags.Append(moreDiags)
+					savedChecksums = !moreDiags.HasErrors()
+				}
 			}
 			os.Remove(targetPath) // okay if it fails because we're going to try to rename over it next anyway
 			err = os.Rename(stagingPath, targetPath)
@@ -244,9 +290,27 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 				))
 				continue
 			}
+			hash, err := getproviders.PackageHashV1(getproviders.PackageLocalArchive(targetPath))
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Failed to hash provider package",
+					fmt.Sprintf("Failed to determine a hash value for %s v%s on %s: %s.", provider.String(), selected.String(), platform.String(), err),
+				))
+				continue
+			}
+			hashes = append(hashes, hash)
+		}
+		if len(diags) == diagsBefore {
+			newLocks.SetProvider(provider, selected, constraints, hashes)
 		}
 	}
 
+	if optLockFile != "" && !diags.HasErrors() {
+		c.Ui.Output(fmt.Sprintf("- Writing dependency lock file %s...", optLockFile))
+		diags = diags.Append(depsfile.SaveLocksToFile(newLocks, optLockFile))
+	}
+
 	// Now we'll generate or update the JSON index files in the directory.
 	// We do this by scanning the directory to see what is present, rather than
 	// by relying on the selections we made above, because we want to still
@@ -297,9 +361,20 @@ func (c *ProvidersMirrorCommand) Run(args []string) int {
 			if _, ok := indexArchives[version]; !ok {
 				indexArchives[version] = map[string]interface{}{}
 			}
+			// We also include the legacy "zh:" hash of the archive, which is
+			// the one that the signed checksums of the origin registry cover.
+			zipHash, err := getproviders.PackageHashLegacyZipSHA(archivePath)
+			if err != nil {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Failed to update indexes",
+					fmt.Sprintf("Failed to determine a hash value for %s v%s on %s: %s.", provider, version, platform, err),
+				))
+				continue
+			}
 			indexArchives[version][platform.String()] = map[string]interface{}{
-				"url":    archiveFilename,         // a relative URL from the index file's URL
-				"hashes": []string{hash.String()}, // an array to allow for additional hash formats in future
+				"url":    archiveFilename, // a relative URL from the index file's URL
+				"hashes": []string{hash.String(), zipHash.String()},
 			}
 		}
 		mainIndex := map[string]interface{}{
@@ -368,6 +443,11 @@ Usage: tofu [global options] providers mirror [options] <target-dir>
   a network mirror. Those index files will be ignored if the directory is
   used instead as a local filesystem mirror.
 
+  Each provider version in the mirror is also accompanied by the checksums
+  file that its origin registry signed, along with the signature and the
+  public keys to verify it with, so that the packages can be verified again
+  later without access to the registry.
+
 Options:
 
   -platform=os_arch  Choose which target platform to build a mirror for.
@@ -380,6 +460,54 @@ Options:
                      architecture. For example, "linux_amd64" selects the
                      Linux operating system running on an AMD64 or x86_64
                      CPU. Each provider is available only for a limited
-                     set of target platforms.
+                     set of target platforms. Use -platform=all to
+                     include packages for every target platform that the
+                     origin registry has.
+
+  -lock-file=path    Also write a dependency lock file to the given path,
+                     recording the selected version of each provider along
+                     with the checksums of its packages for every platform.
+                     The lock file can be used together with the mirror to
+                     initialize configurations without any network access.
 `
 }
+
+// mirrorSignedChecksums writes the signed checksums document of the given
+// package, along with its signature and the keys to verify it with, into the
+// given directory of a mirror, using the file naming convention of provider
+// releases.
+func mirrorSignedChecksums(meta getproviders.PackageMeta, dir string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	auth, ok := meta.Authentication.(getproviders.PackageAuthenticationSignedChecksums)
+	if !ok {
+		return diags
+	}
+	document, signature, keys := auth.SignedChecksums()
+	if document == nil {
+		return diags
+	}
+
+	prefix := filepath.Join(dir, fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS", meta.Provider.Type, meta.Version))
+	files := map[string][]byte{
+		prefix:          document,
+		prefix + ".sig": signature,
+	}
+	if len(keys) > 0 {
+		var armored []string
+		for _, key := range keys {
+			armored = append(armored, strings.TrimSpace(key.ASCIIArmor)+"\n")
+		}
+		files[prefix+".keys"] = []byte(strings.Join(armored, "\n"))
+	}
+	for filename, content := range files {
+		if err := os.WriteFile(filename, content, 0644); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Cannot save provider checksums",
+				fmt.Sprintf("Failed to save the signed checksums of %s v%s into the mirror directory: %s.", meta.Provider, meta.Version, err),
+			))
+		}
+	}
+	return diags
+}
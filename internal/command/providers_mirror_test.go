@@ -6,10 +6,27 @@
 package command
 
 import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/terraform-svchost/disco"
 	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/depsfile"
+	"github.com/opentofu/opentofu/internal/getproviders"
 )
 
 // More thorough tests for providers mirror can be found in the e2etest
@@ -39,3 +56,174 @@ func TestProvidersMirror(t *testing.T) {
 		}
 	})
 }
+
+func TestProvidersMirror_allPlatforms(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	config := `
+terraform {
+  required_providers {
+    happycloud = {
+      source  = "awesomesauce/happycloud"
+      version = "1.0.0"
+    }
+  }
+}
+`
+	if err := os.WriteFile("main.tf", []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(testProvidersMirrorRegistry(t))
+	defer server.Close()
+	services := disco.New()
+	services.ForceHostServices("registry.opentofu.org", map[string]interface{}{
+		"providers.v1": server.URL + "/providers/v1/",
+	})
+
+	ui := new(cli.MockUi)
+	c := &ProvidersMirrorCommand{
+		Meta: Meta{
+			Ui:       ui,
+			Services: services,
+		},
+	}
+	if code := c.Run([]string{"-platform=all", "-lock-file=mirror.lock.hcl", "mirror"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	dir := filepath.Join("mirror", "registry.opentofu.org", "awesomesauce", "happycloud")
+	var got []string
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		got = append(got, entry.Name())
+	}
+	want := []string{
+		"1.0.0.json",
+		"index.json",
+		"terraform-provider-happycloud_1.0.0_SHA256SUMS",
+		"terraform-provider-happycloud_1.0.0_SHA256SUMS.sig",
+		"terraform-provider-happycloud_1.0.0_darwin_arm64.zip",
+		"terraform-provider-happycloud_1.0.0_linux_amd64.zip",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong files in mirror\n%s", diff)
+	}
+
+	src, err := os.ReadFile(filepath.Join(dir, "1.0.0.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var index struct {
+		Archives map[string]struct {
+			Hashes []string `json:"hashes"`
+		} `json:"archives"`
+	}
+	if err := json.Unmarshal(src, &index); err != nil {
+		t.Fatal(err)
+	}
+	var indexHashes []string
+	for _, archive := range index.Archives {
+		if len(archive.Hashes) != 2 || !strings.HasPrefix(archive.Hashes[0], "h1:") || !strings.HasPrefix(archive.Hashes[1], "zh:") {
+			t.Errorf("wrong hashes in version index: %q", archive.Hashes)
+		}
+		indexHashes = append(indexHashes, archive.Hashes...)
+	}
+	sort.Strings(indexHashes)
+	if len(indexHashes) != 4 {
+		t.Fatalf("wrong number of hashes in version index: %q", indexHashes)
+	}
+
+	locks, diags := depsfile.LoadLocksFromFile("mirror.lock.hcl")
+	if diags.HasErrors() {
+		t.Fatalf("invalid lock file: %s", diags.Err())
+	}
+	lock := locks.Provider(addrs.MustParseProviderSourceString("awesomesauce/happycloud"))
+	if lock == nil {
+		t.Fatal("lock file has no entry for the provider")
+	}
+	if got, want := lock.Version().String(), "1.0.0"; got != want {
+		t.Errorf("wrong locked version %s; want %s", got, want)
+	}
+	var lockHashes []string
+	for _, hash := range lock.AllHashes() {
+		lockHashes = append(lockHashes, hash.String())
+	}
+	if diff := cmp.Diff(indexHashes, lockHashes); diff != "" {
+		t.Errorf("lock file hashes don't match the version index\n%s", diff)
+	}
+}
+
+// testProvidersMirrorRegistry returns a handler for a fake provider registry
+// that has packages of awesomesauce/happycloud v1.0.0 for two platforms.
+func testProvidersMirrorRegistry(t *testing.T) http.Handler {
+	platforms := []getproviders.Platform{
+		{OS: "darwin", Arch: "arm64"},
+		{OS: "linux", Arch: "amd64"},
+	}
+
+	packages := make(map[string][]byte)
+	var sums bytes.Buffer
+	for _, platform := range platforms {
+		filename := fmt.Sprintf("terraform-provider-happycloud_1.0.0_%s.zip", platform)
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, err := zw.Create("terraform-provider-happycloud")
+		if err != nil {
+			t.Fatal(err)
+		}
+		fmt.Fprintf(w, "happycloud for %s", platform)
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		packages[filename] = buf.Bytes()
+		sum := sha256.Sum256(buf.Bytes())
+		fmt.Fprintf(&sums, "%s  %s\n", hex.EncodeToString(sum[:]), filename)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/providers/v1/awesomesauce/happycloud/versions", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"versions":[{"version":"1.0.0","protocols":["5.0"],"platforms":[{"os":"darwin","arch":"arm64"},{"os":"linux","arch":"amd64"}]}]}`))
+	})
+	for _, platform := range platforms {
+		platform := platform
+		mux.HandleFunc(fmt.Sprintf("/providers/v1/awesomesauce/happycloud/1.0.0/download/%s/%s", platform.OS, platform.Arch), func(w http.ResponseWriter, r *http.Request) {
+			filename := fmt.Sprintf("terraform-provider-happycloud_1.0.0_%s.zip", platform)
+			sum := sha256.Sum256(packages[filename])
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"protocols":             []string{"5.0"},
+				"os":                    platform.OS,
+				"arch":                  platform.Arch,
+				"filename":              filename,
+				"shasum":                hex.EncodeToString(sum[:]),
+				"download_url":          "/pkg/" + filename,
+				"shasums_url":           "/pkg/SHA256SUMS",
+				"shasums_signature_url": "/pkg/SHA256SUMS.sig",
+				"signing_keys":          map[string]interface{}{"gpg_public_keys": []interface{}{}},
+			})
+		})
+	}
+	mux.HandleFunc("/pkg/", func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimPrefix(r.URL.Path, "/pkg/")
+		switch name {
+		case "SHA256SUMS":
+			w.Write(sums.Bytes())
+		case "SHA256SUMS.sig":
+			w.Write([]byte("not a real signature"))
+		default:
+			content, ok := packages[name]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write(content)
+		}
+	})
+	return mux
+}
@@ -6,10 +6,12 @@
 package command
 
 import (
+	"encoding/json"
 	"os"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
 )
 
@@ -204,3 +206,106 @@ func TestProviders_tests(t *testing.T) {
 		}
 	}
 }
+
+func TestProviders_problems(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("providers/problems"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	c := &ProvidersCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	args := []string{}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	wantOutput := []string{
+		"Providers required by state but not by configuration:\n\n    provider[registry.opentofu.org/hashicorp/baz]",
+		"Providers declared in required_providers but not used:\n\n    provider[registry.opentofu.org/hashicorp/unused] (\"unused\") in the root module",
+		"    provider[registry.opentofu.org/hashicorp/foo]\n        the root module: >= 2.0.0\n        module.child: < 2.0.0",
+	}
+
+	output := ui.OutputWriter.String()
+	for _, want := range wantOutput {
+		if !strings.Contains(output, want) {
+			t.Errorf("output missing %s:\n%s", want, output)
+		}
+	}
+}
+
+func TestProviders_json(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("providers/problems"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	c := &ProvidersCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+
+	args := []string{"-json"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &got); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter.String())
+	}
+
+	want := map[string]interface{}{
+		"format_version": "1.0",
+		"configuration": []interface{}{
+			map[string]interface{}{
+				"module":              "",
+				"provider":            "registry.opentofu.org/hashicorp/foo",
+				"version_constraints": ">= 2.0.0",
+			},
+			map[string]interface{}{
+				"module":   "",
+				"provider": "registry.opentofu.org/hashicorp/unused",
+			},
+			map[string]interface{}{
+				"module":              "module.child",
+				"provider":            "registry.opentofu.org/hashicorp/foo",
+				"version_constraints": "< 2.0.0",
+			},
+		},
+		"state":      []interface{}{"registry.opentofu.org/hashicorp/baz"},
+		"state_only": []interface{}{"registry.opentofu.org/hashicorp/baz"},
+		"unused": []interface{}{
+			map[string]interface{}{
+				"module":     "",
+				"local_name": "unused",
+				"provider":   "registry.opentofu.org/hashicorp/unused",
+			},
+		},
+		"version_conflicts": []interface{}{
+			map[string]interface{}{
+				"provider": "registry.opentofu.org/hashicorp/foo",
+				"requirements": []interface{}{
+					map[string]interface{}{
+						"module":              "",
+						"provider":            "registry.opentofu.org/hashicorp/foo",
+						"version_constraints": ">= 2.0.0",
+					},
+					map[string]interface{}{
+						"module":              "module.child",
+						"provider":            "registry.opentofu.org/hashicorp/foo",
+						"version_constraints": "< 2.0.0",
+					},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong output\n%s", diff)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/posener/complete"
+
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/views"
@@ -22,11 +24,12 @@ type RefreshCommand struct {
 	Meta
 }
 
-func (c *RefreshCommand) Run(rawArgs []string) int {
+func (c *RefreshCommand) Run(rawArgs []string) (status int) {
 	var diags tfdiags.Diagnostics
 
 	// Parse and apply global view arguments
 	common, rawArgs := arguments.ParseView(rawArgs)
+	rawArgs = c.Meta.processOverrideSet(rawArgs)
 	c.View.Configure(common)
 
 	// Propagate -no-color for legacy use of Ui.  The remote backend and
@@ -37,6 +40,7 @@ func (c *RefreshCommand) Run(rawArgs []string) int {
 
 	// Parse and validate flags
 	args, diags := arguments.ParseRefresh(rawArgs)
+	diags = diags.Append(common.Parse())
 
 	// Instantiate the view, even if there are flag errors, so that we render
 	// diagnostics according to the desired view
@@ -56,6 +60,11 @@ func (c *RefreshCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	// Keep provider plugin processes running between the graph walks of
+	// this command, rather than starting them again for each one.
+	c.startProviderPool()
+	defer c.closeProviderPool()
+
 	// FIXME: the -input flag value is needed to initialize the backend and the
 	// operation, but there is no clear path to pass this value down, so we
 	// continue to mutate the Meta object state for now.
@@ -88,6 +97,9 @@ func (c *RefreshCommand) Run(rawArgs []string) int {
 		return 1
 	}
 
+	audit := c.auditBeginBackend("refresh", be)
+	defer audit.finish(&status)
+
 	// Build the operation request
 	opReq, opDiags := c.OperationRequest(be, view, args.ViewType, args.Operation, enc)
 	diags = diags.Append(opDiags)
@@ -150,6 +162,9 @@ func (c *RefreshCommand) OperationRequest(be backend.Enhanced, view views.Refres
 	opReq.ConfigDir = "."
 	opReq.Hooks = view.Hooks()
 	opReq.Targets = args.Targets
+	opReq.RefreshFilter = args.RefreshFilter
+	opReq.RefreshParallelism = args.RefreshParallelism
+	opReq.StrictWarnings = args.Strict
 	opReq.Type = backend.OperationTypeRefresh
 	opReq.View = view.Operation()
 
@@ -180,6 +195,20 @@ func (c *RefreshCommand) GatherVariables(args *arguments.Vars) {
 	c.Meta.variableArgs = rawFlags{items: &items}
 }
 
+func (c *RefreshCommand) AutocompleteArgs() complete.Predictor {
+	return complete.PredictNothing
+}
+
+func (c *RefreshCommand) AutocompleteFlags() complete.Flags {
+	flags := c.completeOperationFlags()
+	// The refresh command always refreshes and never plans.
+	delete(flags, "-destroy")
+	delete(flags, "-refresh")
+	delete(flags, "-refresh-only")
+	delete(flags, "-replace")
+	return flags
+}
+
 func (c *RefreshCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] refresh [options]
@@ -207,8 +236,28 @@ Options:
 
   -no-color           If specified, output won't contain any color.
 
+  -override-set=name  Also load the environment-scoped override files of the
+                      given set, named like "*_override.name.tf". Defaults to
+                      the TF_OVERRIDE_SET environment variable.
+
   -parallelism=n      Limit the number of concurrent operations. Defaults to 10.
 
+  -refresh-filter=resource
+                      Limit refreshing to only the given module, resource, or
+                      resource instance, leaving the state of other objects
+                      unchanged. This flag can be used multiple times.
+
+  -refresh-parallelism=provider=n
+                      Limit the number of concurrent requests made to the
+                      given provider, such as hashicorp/aws, while refreshing.
+                      This flag can be used multiple times.
+
+  -strict             Treat warnings in any of the warning categories as
+                      errors. Use -strict=category,... to select only some
+                      of the categories: deprecations,
+                      implicit_provider_inheritance, and
+                      undeclared_var_files.
+
   -target=resource    Resource to target. Operation will be limited to this
                       resource and its dependencies. This flag can be used
                       multiple times.
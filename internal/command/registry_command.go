@@ -0,0 +1,36 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// RegistryCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type RegistryCommand struct {
+	Meta
+}
+
+func (c *RegistryCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *RegistryCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] registry <subcommand> [options] [args]
+
+  This command has subcommands for publishing to module and provider registries.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *RegistryCommand) Synopsis() string {
+	return "Registry related commands"
+}
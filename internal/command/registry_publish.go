@@ -0,0 +1,446 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/registry"
+	"github.com/opentofu/opentofu/internal/registry/regsrc"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// gpgCommand is the GnuPG executable used to sign the checksums of provider
+// packages, which is looked up in the PATH.
+const gpgCommand = "gpg"
+
+// RegistryPublishCommand is a Command implementation that publishes a
+// module or provider version to a registry that implements the publish API.
+type RegistryPublishCommand struct {
+	Meta
+}
+
+func (c *RegistryPublishCommand) Run(args []string) int {
+	var isProvider bool
+	var dir, signingKey, protocols string
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("registry publish")
+	cmdFlags.BoolVar(&isProvider, "provider", false, "provider")
+	cmdFlags.StringVar(&dir, "dir", "", "dir")
+	cmdFlags.StringVar(&signingKey, "signing-key", "", "signing-key")
+	cmdFlags.StringVar(&protocols, "protocols", "5.0", "protocols")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 2 {
+		c.Ui.Error("The registry publish command expects two arguments, the address and the version.")
+		cmdFlags.Usage()
+		return 1
+	}
+	addr, versionStr := args[0], strings.TrimPrefix(args[1], "v")
+
+	var diags tfdiags.Diagnostics
+	version, err := getproviders.ParseVersion(versionStr)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid version",
+			fmt.Sprintf("The version %q is not a valid semantic version, such as 1.2.3: %s.", args[1], err),
+		))
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	if isProvider {
+		if dir == "" {
+			dir = "dist"
+		}
+		diags = diags.Append(c.publishProvider(addr, version.String(), dir, signingKey, protocols))
+	} else {
+		if dir == "" {
+			dir = "."
+		}
+		diags = diags.Append(c.publishModule(addr, version.String(), dir))
+	}
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+// publishModule packages the module in the given directory and uploads it
+// as the given version of the module at the given registry address.
+func (c *RegistryPublishCommand) publishModule(addr, version, dir string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	module, err := regsrc.ParseModuleSource(addr)
+	if err != nil || module.RawSubmodule != "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid module address",
+			fmt.Sprintf("The address %q is not a valid module registry address, such as example.com/namespace/name/system.", addr),
+		))
+		return diags
+	}
+
+	// We only publish modules that we can load, to catch mistakes such as
+	// publishing the wrong directory before anyone tries to use them.
+	_, moreDiags := c.loadSingleModule(dir)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return diags
+	}
+
+	archive, err := packageModule(dir)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to package module",
+			fmt.Sprintf("Failed to package the module in %s: %s.", dir, err),
+		))
+		return diags
+	}
+	sum := sha256.Sum256(archive)
+	shasum := hex.EncodeToString(sum[:])
+
+	ctx, done := c.InterruptibleContext(c.CommandContext())
+	defer done()
+	if err := c.registryClient().PublishModule(ctx, module, version, archive, shasum); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to publish module",
+			fmt.Sprintf("Failed to publish %s %s: %s.", module.Display(), version, err),
+		))
+		return diags
+	}
+
+	c.Ui.Output(fmt.Sprintf("Published module %s %s (SHA256 %s).", module.Display(), version, shasum))
+	return diags
+}
+
+// providerPackagePattern matches the filenames of provider packages, which
+// are named terraform-provider-TYPE_VERSION_OS_ARCH.zip.
+var providerPackagePattern = regexp.MustCompile(`^terraform-provider-(.+)_([^_]+)_([a-z0-9]+)_([a-z0-9]+)\.zip$`)
+
+// publishProvider uploads the provider packages in the given directory,
+// with a signed list of their checksums, as the given version of the
+// provider at the given registry address.
+func (c *RegistryPublishCommand) publishProvider(addr, version, dir, signingKey, protocols string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	provider, moreDiags := addrs.ParseProviderSourceString(addr)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return diags
+	}
+	if signingKey == "" {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Missing signing key",
+			"Provider packages must be signed. Use the -signing-key option to give the ID of the GPG key to sign them with.",
+		))
+		return diags
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read provider packages",
+			fmt.Sprintf("Failed to read the directory %s: %s.", dir, err),
+		))
+		return diags
+	}
+
+	type packageFile struct {
+		platform registry.ProviderPlatformPublication
+		content  []byte
+	}
+	var packages []packageFile
+	for _, entry := range entries {
+		match := providerPackagePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil || match[1] != provider.Type || strings.TrimPrefix(match[2], "v") != version {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read provider package",
+				fmt.Sprintf("Failed to read %s: %s.", entry.Name(), err),
+			))
+			return diags
+		}
+		sum := sha256.Sum256(content)
+		packages = append(packages, packageFile{
+			platform: registry.ProviderPlatformPublication{
+				OS:       match[3],
+				Arch:     match[4],
+				Filename: entry.Name(),
+				SHASum:   hex.EncodeToString(sum[:]),
+			},
+			content: content,
+		})
+	}
+	if len(packages) == 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"No provider packages",
+			fmt.Sprintf("There are no packages for %s %s in %s. Each package must be named terraform-provider-%s_%s_OS_ARCH.zip.", provider.ForDisplay(), version, dir, provider.Type, version),
+		))
+		return diags
+	}
+
+	// The checksums file uses the same format as the sha256sum tool, which
+	// is what the provider installer expects to verify against.
+	var shasums bytes.Buffer
+	for _, pkg := range packages {
+		fmt.Fprintf(&shasums, "%s  %s\n", pkg.platform.SHASum, pkg.platform.Filename)
+	}
+	sig, armor, err := signProviderSHASums(shasums.Bytes(), signingKey)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to sign provider checksums",
+			fmt.Sprintf("Failed to sign the checksums of the provider packages with the GPG key %q: %s.", signingKey, err),
+		))
+		return diags
+	}
+
+	publication := &registry.ProviderVersionPublication{
+		SHASumsFilename:          fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS", provider.Type, version),
+		SHASumsSignatureFilename: fmt.Sprintf("terraform-provider-%s_%s_SHA256SUMS.sig", provider.Type, version),
+	}
+	for _, protocol := range strings.Split(protocols, ",") {
+		publication.Protocols = append(publication.Protocols, strings.TrimSpace(protocol))
+	}
+	publication.SigningKey.KeyID = signingKey
+	publication.SigningKey.ASCIIArmor = string(armor)
+
+	files := map[string][]byte{
+		publication.SHASumsFilename:          shasums.Bytes(),
+		publication.SHASumsSignatureFilename: sig,
+	}
+	for _, pkg := range packages {
+		publication.Platforms = append(publication.Platforms, pkg.platform)
+		files[pkg.platform.Filename] = pkg.content
+	}
+	filenames := make([]string, 0, len(files))
+	for filename := range files {
+		filenames = append(filenames, filename)
+	}
+	sort.Strings(filenames)
+
+	ctx, done := c.InterruptibleContext(c.CommandContext())
+	defer done()
+	client := c.registryClient()
+	for _, filename := range filenames {
+		content := files[filename]
+		sum := sha256.Sum256(content)
+		if err := client.PublishProviderFile(ctx, provider, version, filename, content, hex.EncodeToString(sum[:])); err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to publish provider",
+				fmt.Sprintf("Failed to upload %s for %s %s: %s.", filename, provider.ForDisplay(), version, err),
+			))
+			return diags
+		}
+	}
+	if err := client.PublishProviderVersion(ctx, provider, version, publication); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to publish provider",
+			fmt.Sprintf("Failed to publish %s %s: %s.", provider.ForDisplay(), version, err),
+		))
+		return diags
+	}
+
+	c.Ui.Output(fmt.Sprintf("Published provider %s %s for %d platforms.", provider.ForDisplay(), version, len(packages)))
+	return diags
+}
+
+// packageModule returns a gzip-compressed tar archive of the module in the
+// given directory, leaving out the local working directory files that are
+// never part of a module package.
+//
+// The archive doesn't record modification times or file owners, so the same
+// files always result in the same archive and checksum.
+func packageModule(dir string) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		name := d.Name()
+		if d.IsDir() && (name == DefaultDataDir || name == ".git") {
+			return filepath.SkipDir
+		}
+		if !d.Type().IsRegular() && !d.IsDir() {
+			// Symlinks and other special files can't be reproduced safely
+			// wherever the package is installed.
+			return nil
+		}
+		if strings.HasSuffix(name, ".tfstate") || strings.HasSuffix(name, ".tfstate.backup") || name == ".terraform.lock.hcl" {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr := &tar.Header{
+			Name: filepath.ToSlash(rel),
+			Mode: int64(info.Mode().Perm()),
+		}
+		if d.IsDir() {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Name += "/"
+			return tw.WriteHeader(hdr)
+		}
+		hdr.Typeflag = tar.TypeReg
+		hdr.Size = info.Size()
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// signProviderSHASums makes a detached binary signature of the given
+// checksums with the given GPG key, by running GnuPG, and returns it with
+// the ASCII-armored public key.
+func signProviderSHASums(shasums []byte, keyID string) (sig []byte, armor []byte, err error) {
+	dir, err := os.MkdirTemp("", "tofu-publish-")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	shasumsPath := filepath.Join(dir, "SHA256SUMS")
+	sigPath := shasumsPath + ".sig"
+	if err := os.WriteFile(shasumsPath, shasums, 0600); err != nil {
+		return nil, nil, err
+	}
+	if _, err := runGPG("--batch", "--yes", "--local-user", keyID, "--output", sigPath, "--detach-sign", shasumsPath); err != nil {
+		return nil, nil, err
+	}
+	sig, err = os.ReadFile(sigPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	armor, err = runGPG("--batch", "--armor", "--export", keyID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(bytes.TrimSpace(armor)) == 0 {
+		return nil, nil, fmt.Errorf("there is no public key for %q", keyID)
+	}
+	return sig, armor, nil
+}
+
+func runGPG(args ...string) ([]byte, error) {
+	cmd := exec.Command(gpgCommand, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return stdout.Bytes(), nil
+}
+
+func (c *RegistryPublishCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] registry publish [options] ADDRESS VERSION
+
+  Publish a version of a module or provider to a registry that implements
+  the OpenTofu registry publish API, such as a private registry.
+
+  For a module, ADDRESS is its registry address, such as
+  example.com/namespace/name/system. OpenTofu packages the module in the
+  directory given with -dir as a .tar.gz archive, leaving out the .terraform
+  and .git directories and any state files, and uploads the archive with its
+  SHA256 checksum.
+
+  For a provider, use -provider. ADDRESS is its source address, such as
+  example.com/namespace/type. The directory given with -dir must contain the
+  packages of the version for each platform, named
+  terraform-provider-TYPE_VERSION_OS_ARCH.zip. OpenTofu computes their
+  checksums, signs the checksums with GnuPG and uploads the packages, the
+  checksums and the signature.
+
+  To log in to the registry, use "tofu login HOSTNAME".
+
+Options:
+
+  -dir=path           The directory containing the module, or the provider
+                      packages. Defaults to the current directory for
+                      modules, and to "dist" for providers.
+
+  -provider           Publish a provider instead of a module.
+
+  -signing-key=ID     The GPG key to sign the provider checksums with.
+                      Required for providers.
+
+  -protocols=5.0      Comma-separated plugin protocol versions that the
+                      provider supports. Defaults to "5.0".
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *RegistryPublishCommand) Synopsis() string {
+	return "Publish a module or provider to a registry"
+}
@@ -0,0 +1,261 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+	"github.com/hashicorp/terraform-svchost/disco"
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/registry"
+)
+
+// testPublishRegistry starts a registry that accepts every request to its
+// publish API, and records the paths and bodies of the requests.
+func testPublishRegistry(t *testing.T) (*disco.Disco, map[string][]byte) {
+	t.Helper()
+	var mu sync.Mutex
+	requests := make(map[string][]byte)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		requests[r.Method+" "+r.URL.Path] = body
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+
+	services := disco.New()
+	services.ForceHostServices(svchost.Hostname("registry.example.com"), map[string]interface{}{
+		"publish.v1": server.URL + "/publish/",
+	})
+	return services, requests
+}
+
+func TestRegistryPublish_module(t *testing.T) {
+	td := t.TempDir()
+	if err := os.WriteFile(filepath.Join(td, "main.tf"), []byte("variable \"name\" {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(td, ".terraform"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(td, "terraform.tfstate"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	services, requests := testPublishRegistry(t)
+	ui := cli.NewMockUi()
+	c := &RegistryPublishCommand{
+		Meta: Meta{
+			Ui:       ui,
+			Services: services,
+		},
+	}
+	if code := c.Run([]string{"-dir", td, "registry.example.com/acme/network/aws", "v1.2.0"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n%s", code, ui.ErrorWriter.String())
+	}
+
+	archive, ok := requests["PUT /publish/modules/acme/network/aws/1.2.0"]
+	if !ok {
+		t.Fatalf("module wasn't uploaded; got requests for %v", requestPaths(requests))
+	}
+	want, err := packageModule(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, archive); diff != "" {
+		t.Errorf("wrong archive\n%s", diff)
+	}
+	if !strings.Contains(ui.OutputWriter.String(), "Published module registry.example.com/acme/network/aws 1.2.0") {
+		t.Errorf("wrong output\n%s", ui.OutputWriter.String())
+	}
+}
+
+func TestRegistryPublish_moduleInvalid(t *testing.T) {
+	td := t.TempDir()
+	if err := os.WriteFile(filepath.Join(td, "main.tf"), []byte("variable {\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	services, requests := testPublishRegistry(t)
+	ui := cli.NewMockUi()
+	c := &RegistryPublishCommand{
+		Meta: Meta{
+			Ui:       ui,
+			Services: services,
+		},
+	}
+	if code := c.Run([]string{"-dir", td, "registry.example.com/acme/network/aws", "1.2.0"}); code != 1 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if len(requests) != 0 {
+		t.Errorf("unexpected requests for %v", requestPaths(requests))
+	}
+}
+
+func TestPackageModule(t *testing.T) {
+	td := t.TempDir()
+	if err := os.WriteFile(filepath.Join(td, "main.tf"), []byte("variable \"name\" {}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	first, err := packageModule(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Changing the modification time must not change the archive, so that
+	// publishing the same files always results in the same checksum.
+	if err := os.Chtimes(filepath.Join(td, "main.tf"), time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	second, err := packageModule(td)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(first, second); diff != "" {
+		t.Errorf("archive is not reproducible\n%s", diff)
+	}
+}
+
+func TestRegistryPublish_provider(t *testing.T) {
+	testFakeGPG(t)
+
+	td := t.TempDir()
+	for _, name := range []string{
+		"terraform-provider-widgets_1.0.0_linux_amd64.zip",
+		"terraform-provider-widgets_1.0.0_darwin_arm64.zip",
+		"terraform-provider-widgets_0.9.0_linux_amd64.zip",
+		"terraform-provider-other_1.0.0_linux_amd64.zip",
+	} {
+		if err := os.WriteFile(filepath.Join(td, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	services, requests := testPublishRegistry(t)
+	ui := cli.NewMockUi()
+	c := &RegistryPublishCommand{
+		Meta: Meta{
+			Ui:       ui,
+			Services: services,
+		},
+	}
+	args := []string{"-provider", "-dir", td, "-signing-key", "ABCD1234", "-protocols", "5.0,6.0", "registry.example.com/acme/widgets", "1.0.0"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("unexpected exit code %d\n%s", code, ui.ErrorWriter.String())
+	}
+
+	wantPaths := []string{
+		"POST /publish/providers/acme/widgets/1.0.0",
+		"PUT /publish/providers/acme/widgets/1.0.0/terraform-provider-widgets_1.0.0_SHA256SUMS",
+		"PUT /publish/providers/acme/widgets/1.0.0/terraform-provider-widgets_1.0.0_SHA256SUMS.sig",
+		"PUT /publish/providers/acme/widgets/1.0.0/terraform-provider-widgets_1.0.0_darwin_arm64.zip",
+		"PUT /publish/providers/acme/widgets/1.0.0/terraform-provider-widgets_1.0.0_linux_amd64.zip",
+	}
+	if diff := cmp.Diff(wantPaths, requestPaths(requests)); diff != "" {
+		t.Fatalf("wrong requests\n%s", diff)
+	}
+
+	shasums := string(requests["PUT /publish/providers/acme/widgets/1.0.0/terraform-provider-widgets_1.0.0_SHA256SUMS"])
+	var wantShasums strings.Builder
+	for _, name := range []string{"terraform-provider-widgets_1.0.0_darwin_arm64.zip", "terraform-provider-widgets_1.0.0_linux_amd64.zip"} {
+		fmt.Fprintf(&wantShasums, "%x  %s\n", sha256.Sum256([]byte(name)), name)
+	}
+	if diff := cmp.Diff(wantShasums.String(), shasums); diff != "" {
+		t.Errorf("wrong checksums\n%s", diff)
+	}
+	if got, want := string(requests["PUT /publish/providers/acme/widgets/1.0.0/terraform-provider-widgets_1.0.0_SHA256SUMS.sig"]), "signed by ABCD1234\n"; got != want {
+		t.Errorf("wrong signature %q; want %q", got, want)
+	}
+
+	var publication registry.ProviderVersionPublication
+	if err := json.Unmarshal(requests["POST /publish/providers/acme/widgets/1.0.0"], &publication); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"5.0", "6.0"}, publication.Protocols); diff != "" {
+		t.Errorf("wrong protocols\n%s", diff)
+	}
+	if got, want := len(publication.Platforms), 2; got != want {
+		t.Errorf("wrong number of platforms %d; want %d", got, want)
+	}
+	if got, want := publication.SigningKey.ASCIIArmor, "public key ABCD1234\n"; got != want {
+		t.Errorf("wrong signing key %q; want %q", got, want)
+	}
+}
+
+func TestRegistryPublish_providerNoSigningKey(t *testing.T) {
+	services, requests := testPublishRegistry(t)
+	ui := cli.NewMockUi()
+	c := &RegistryPublishCommand{
+		Meta: Meta{
+			Ui:       ui,
+			Services: services,
+		},
+	}
+	if code := c.Run([]string{"-provider", "-dir", t.TempDir(), "registry.example.com/acme/widgets", "1.0.0"}); code != 1 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if !strings.Contains(ui.ErrorWriter.String(), "Missing signing key") {
+		t.Errorf("wrong error\n%s", ui.ErrorWriter.String())
+	}
+	if len(requests) != 0 {
+		t.Errorf("unexpected requests for %v", requestPaths(requests))
+	}
+}
+
+func requestPaths(requests map[string][]byte) []string {
+	paths := make([]string, 0, len(requests))
+	for p := range requests {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// testFakeGPG puts a fake gpg on the PATH, which signs files by writing
+// which key signed them and exports a fake public key for any key ID.
+func testFakeGPG(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake gpg is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+while [ $# -gt 0 ]; do
+  case "$1" in
+    --local-user) key="$2"; shift ;;
+    --output) out="$2"; shift ;;
+    --export) echo "public key $2"; exit 0 ;;
+  esac
+  shift
+done
+echo "signed by $key" > "$out"
+`
+	if err := os.WriteFile(filepath.Join(dir, "gpg"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
@@ -0,0 +1,297 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states/snapshot"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// RollbackCommand is a Command implementation that plans to return the
+// infrastructure to how it was recorded in a snapshot saved before an apply.
+type RollbackCommand struct {
+	Meta
+}
+
+func (c *RollbackCommand) Run(rawArgs []string) (status int) {
+	// Parse and apply global view arguments
+	common, rawArgs := arguments.ParseView(rawArgs)
+	c.View.Configure(common)
+
+	// Propagate -no-color for legacy use of Ui.
+	c.Meta.color = !common.NoColor
+	c.Meta.Color = c.Meta.color
+
+	// Parse and validate flags
+	args, diags := arguments.ParseRollback(rawArgs)
+	diags = diags.Append(common.Parse())
+
+	view := views.NewPlan(arguments.ViewHuman, c.View)
+
+	if diags.HasErrors() {
+		view.Diagnostics(diags)
+		view.HelpPrompt()
+		return 1
+	}
+
+	// Check for user-supplied plugin path
+	var err error
+	if c.pluginPath, err = c.loadPluginPath(); err != nil {
+		diags = diags.Append(err)
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	c.Meta.input = args.InputEnabled
+	c.Meta.parallelism = args.Parallelism
+	c.Meta.applyStateArguments(args.State)
+	c.gatherVariables(args.Vars)
+
+	// Load the encryption configuration
+	enc, encDiags := c.Encryption()
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	workspace, err := c.Workspace()
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Error selecting workspace: %w", err))
+		view.Diagnostics(diags)
+		return 1
+	}
+	dir := c.snapshotDir(workspace)
+
+	if args.List {
+		return c.list(dir, view)
+	}
+
+	snap, err := snapshot.Find(dir, args.Snapshot)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Snapshot not found",
+			fmt.Sprintf("Cannot roll back to a snapshot because %s. OpenTofu saves a snapshot of the state in this working directory each time \"tofu apply\" changes the infrastructure.", err),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+	snapFile, err := snap.State(enc.State())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read snapshot",
+			fmt.Sprintf("Cannot read the state saved in snapshot %s: %s.", snap.ID, err),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	backendConfig, beDiags := c.loadBackendConfig(".")
+	diags = diags.Append(beDiags)
+	if beDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+	be, beDiags := c.Backend(&BackendOpts{
+		Config:   backendConfig,
+		ViewType: arguments.ViewHuman,
+	}, enc.State())
+	diags = diags.Append(beDiags)
+	if beDiags.HasErrors() {
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Get the current state
+	stateMgr, err := be.StateMgr(workspace)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf(errStateLoadingState, err))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	audit := c.auditBegin("rollback", stateMgr)
+	defer audit.finish(&status)
+	if err := stateMgr.RefreshState(); err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to load state: %w", err))
+		view.Diagnostics(diags)
+		return 1
+	}
+	if current := statemgr.Export(stateMgr); current != nil && current.Lineage != "" && snap.Lineage != "" && current.Lineage != snap.Lineage {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Snapshot belongs to a different state",
+			fmt.Sprintf("Snapshot %s was taken from a state with lineage %q, but the current state has lineage %q, so it cannot be used to roll back the current state.", snap.ID, snap.Lineage, current.Lineage),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	created, manual := snapshot.Diff(snapFile.State, stateMgr.State())
+
+	c.Ui.Output(fmt.Sprintf(
+		"Rolling back to snapshot %s, taken at %s before applying %d change(s).\n",
+		snap.ID, snap.CreatedAt.Format("2006-01-02 15:04:05 MST"), len(snap.Changes),
+	))
+
+	if len(manual) != 0 {
+		var buf strings.Builder
+		for _, step := range manual {
+			fmt.Fprintf(&buf, "\n  - %s %s", step.Addr, step.Reason)
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Manual rollback steps required",
+			fmt.Sprintf("OpenTofu cannot automatically reverse the following changes made since the snapshot was taken:%s", buf.String()),
+		))
+	}
+
+	if len(created) == 0 {
+		view.Diagnostics(diags)
+		c.Ui.Output("There are no objects created since the snapshot, so there is nothing that OpenTofu can roll back automatically.")
+		return 0
+	}
+
+	// Objects created since the snapshot was taken can be rolled back by
+	// destroying them, which we plan as a targeted destroy.
+	targets := make([]addrs.Targetable, len(created))
+	for i, addr := range created {
+		targets[i] = addr
+	}
+
+	opReq := c.Operation(be, arguments.ViewHuman, enc)
+	opReq.ConfigDir = "."
+	opReq.PlanMode = plans.DestroyMode
+	opReq.Hooks = view.Hooks()
+	opReq.PlanRefresh = true
+	opReq.PlanOutPath = args.OutPath
+	opReq.Targets = targets
+	opReq.Type = backend.OperationTypePlan
+	opReq.View = view.Operation()
+	opReq.ConfigLoader, err = c.initConfigLoader()
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to initialize config loader: %w", err))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Before we delegate to the backend, we'll print any warning diagnostics
+	// we've accumulated here, since the backend will start fresh with its own
+	// diagnostics.
+	view.Diagnostics(diags)
+	diags = nil
+
+	op, diags := c.RunOperation(be, opReq)
+	view.Diagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+	return op.Result.ExitStatus()
+}
+
+// list prints a summary of each of the snapshots in the given directory.
+func (c *RollbackCommand) list(dir string, view views.Plan) int {
+	snaps, err := snapshot.List(dir)
+	if err != nil {
+		view.Diagnostics(tfdiags.Diagnostics{}.Append(err))
+		return 1
+	}
+	if len(snaps) == 0 {
+		c.Ui.Output("No snapshots have been saved in this working directory.")
+		return 0
+	}
+	for _, snap := range snaps {
+		c.Ui.Output(fmt.Sprintf(
+			"%s  %s  serial %d, %d change(s)",
+			snap.ID, snap.CreatedAt.Format("2006-01-02 15:04:05 MST"), snap.Serial, len(snap.Changes),
+		))
+	}
+	return 0
+}
+
+func (c *RollbackCommand) gatherVariables(args *arguments.Vars) {
+	// FIXME the arguments package currently trivially gathers variable related
+	// arguments in a heterogenous slice, in order to minimize the number of
+	// code paths gathering variables during the transition to this structure.
+	varArgs := args.All()
+	items := make([]rawFlag, len(varArgs))
+	for i := range varArgs {
+		items[i].Name = varArgs[i].Name
+		items[i].Value = varArgs[i].Value
+	}
+	c.Meta.variableArgs = rawFlags{items: &items}
+}
+
+func (c *RollbackCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] rollback [options]
+
+  Creates an execution plan that returns the infrastructure to how it was
+  before a recent "tofu apply".
+
+  Each time "tofu apply" is about to change the infrastructure, it saves a
+  snapshot of the current state in the working directory. This command
+  compares a snapshot with the current state and plans to destroy any
+  objects that were created since the snapshot was taken.
+
+  Rollback only undoes the creation of objects. It does not restore the
+  full prior state: objects that were updated, replaced or destroyed since
+  the snapshot was taken are listed as manual steps instead.
+
+  Save the plan with -out and pass it to "tofu apply" to perform the
+  rollback.
+
+Options:
+
+  -list               List the saved snapshots for the current workspace
+                      instead of planning a rollback.
+
+  -snapshot=id        Roll back to the snapshot with the given id, as shown
+                      by -list. Defaults to the most recent snapshot.
+
+  -out=path           Write the rollback plan to the given path, so that it
+                      can be applied with "tofu apply".
+
+  -input=true         Ask for input for variables if not directly set.
+
+  -lock=false         Don't hold a state lock during the operation. This is
+                      dangerous if others might concurrently run commands
+                      against the same workspace.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+
+  -no-color           If specified, output won't contain any color.
+
+  -parallelism=n      Limit the number of concurrent operations. Defaults
+                      to 10.
+
+  -var 'foo=bar'      Set a value for one of the input variables in the root
+                      module of the configuration. Use this option more than
+                      once to set more than one variable.
+
+  -var-file=filename  Load variable values from the given file, in addition
+                      to the default files terraform.tfvars and *.auto.tfvars.
+                      Use this option more than once to include more than one
+                      variables file.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *RollbackCommand) Synopsis() string {
+	return "Plan to destroy objects created by a recent apply"
+}
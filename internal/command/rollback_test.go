@@ -0,0 +1,96 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func TestRollback(t *testing.T) {
+	// Create a temporary working directory that is empty
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	p := applyFixtureProvider()
+
+	// Applying the configuration creates test_instance.foo, saving a
+	// snapshot of the empty prior state first.
+	view, done := testView(t)
+	apply := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	if code := apply.Run([]string{"-auto-approve"}); code != 0 {
+		t.Fatalf("apply failed: %d\n\n%s", code, done(t).Stderr())
+	}
+	done(t)
+
+	ui := cli.NewMockUi()
+	view, done = testView(t)
+	c := &RollbackCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			Ui:               ui,
+			View:             view,
+		},
+	}
+	if code := c.Run([]string{"-list"}); code != 0 {
+		t.Fatalf("rollback -list failed: %d\n\n%s", code, done(t).Stderr())
+	}
+	done(t)
+	if got := ui.OutputWriter.String(); !strings.Contains(got, "serial 0, 1 change(s)") {
+		t.Fatalf("wrong snapshot list\n%s", got)
+	}
+
+	view, done = testView(t)
+	c.View = view
+	if code := c.Run([]string{"-out=rollback.tfplan"}); code != 0 {
+		t.Fatalf("rollback failed: %d\n\n%s", code, done(t).Stderr())
+	}
+	done(t)
+
+	plan := testReadPlan(t, "rollback.tfplan")
+	addr := addrs.RootModuleInstance.ResourceInstance(addrs.ManagedResourceMode, "test_instance", "foo", addrs.NoKey)
+	change := plan.Changes.ResourceInstance(addr)
+	if change == nil {
+		t.Fatalf("no planned change for %s", addr)
+	}
+	if got, want := change.Action, plans.Delete; got != want {
+		t.Fatalf("wrong planned action %s; want %s", got, want)
+	}
+}
+
+func TestRollback_noSnapshots(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("apply"), td)
+	defer testChdir(t, td)()
+
+	view, done := testView(t)
+	c := &RollbackCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(applyFixtureProvider()),
+			Ui:               cli.NewMockUi(),
+			View:             view,
+		},
+	}
+	code := c.Run(nil)
+	output := done(t)
+	if code != 1 {
+		t.Fatalf("expected failure, got %d", code)
+	}
+	if got := output.Stderr(); !strings.Contains(got, "there are no saved snapshots") {
+		t.Fatalf("wrong error\n%s", got)
+	}
+}
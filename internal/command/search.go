@@ -0,0 +1,246 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/registry"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// SearchCommand is a Command implementation that searches registries for
+// modules and providers.
+type SearchCommand struct {
+	Meta
+}
+
+// searchResult is a module or provider found by SearchCommand, which is also
+// the JSON representation of it in the -json output.
+type searchResult struct {
+	Address     string `json:"address"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+	Source      string `json:"source,omitempty"`
+	Downloads   int    `json:"downloads"`
+	Verified    bool   `json:"verified"`
+}
+
+// searchResults is the JSON representation of the -json output of
+// SearchCommand.
+type searchResults struct {
+	FormatVersion string          `json:"format_version"`
+	Modules       []*searchResult `json:"modules"`
+	Providers     []*searchResult `json:"providers"`
+}
+
+func (c *SearchCommand) Run(args []string) int {
+	var jsonOutput bool
+	var searchType string
+	var limit int
+	var hosts FlagStringSlice
+
+	args = c.Meta.process(args)
+	cmdFlags := c.Meta.defaultFlagSet("search")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.StringVar(&searchType, "type", "all", "type")
+	cmdFlags.IntVar(&limit, "limit", 10, "limit")
+	cmdFlags.Var(&hosts, "registry", "registry")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) == 0 {
+		c.Ui.Error("The search command expects at least one argument, the search terms.")
+		cmdFlags.Usage()
+		return 1
+	}
+	query := strings.Join(args, " ")
+
+	searchModules := searchType == "all" || searchType == "module"
+	searchProviders := searchType == "all" || searchType == "provider"
+	if !searchModules && !searchProviders {
+		c.Ui.Error(fmt.Sprintf("Invalid -type %q. It must be \"module\", \"provider\" or \"all\".", searchType))
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+	if len(hosts) == 0 {
+		hosts = FlagStringSlice{addrs.DefaultProviderRegistryHost.ForDisplay()}
+	}
+	var hostnames []svchost.Hostname
+	for _, given := range hosts {
+		host, err := svchost.ForComparison(given)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Invalid registry hostname",
+				fmt.Sprintf("The given -registry %q is not a valid hostname: %s.", given, err),
+			))
+			continue
+		}
+		hostnames = append(hostnames, host)
+	}
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	ctx, done := c.InterruptibleContext(c.CommandContext())
+	defer done()
+	client := c.registryClient()
+
+	results := &searchResults{
+		FormatVersion: "1.0",
+		Modules:       []*searchResult{},
+		Providers:     []*searchResult{},
+	}
+	for _, host := range hostnames {
+		if searchModules {
+			list, err := client.SearchModules(ctx, host, query, limit)
+			if err != nil {
+				diags = diags.Append(searchError(host, "modules", err))
+			} else {
+				for _, mod := range list.Modules {
+					pkg := addrs.ModuleRegistryPackage{
+						Host:         host,
+						Namespace:    mod.Namespace,
+						Name:         mod.Name,
+						TargetSystem: mod.Provider,
+					}
+					results.Modules = append(results.Modules, &searchResult{
+						Address:     pkg.ForDisplay(),
+						Version:     mod.Version,
+						Description: mod.Description,
+						Source:      mod.Source,
+						Downloads:   mod.Downloads,
+						Verified:    mod.Verified,
+					})
+				}
+			}
+		}
+		if searchProviders {
+			list, err := client.SearchProviders(ctx, host, query, limit)
+			if err != nil {
+				diags = diags.Append(searchError(host, "providers", err))
+			} else {
+				for _, p := range list.Providers {
+					results.Providers = append(results.Providers, &searchResult{
+						Address:     addrs.NewProvider(host, p.Namespace, p.Name).ForDisplay(),
+						Version:     p.Version,
+						Description: p.Description,
+						Source:      p.Source,
+						Downloads:   p.Downloads,
+						Verified:    p.Verified,
+					})
+				}
+			}
+		}
+	}
+
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+
+	if jsonOutput {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal the results to JSON: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(out))
+		return 0
+	}
+
+	if len(results.Modules) == 0 && len(results.Providers) == 0 {
+		c.Ui.Output(fmt.Sprintf("No modules or providers match %q.", query))
+		return 0
+	}
+	c.showSearchResults("Modules", results.Modules)
+	c.showSearchResults("Providers", results.Providers)
+	return 0
+}
+
+// showSearchResults prints the given results under the given heading, or
+// nothing if there are no results.
+func (c *SearchCommand) showSearchResults(heading string, results []*searchResult) {
+	if len(results) == 0 {
+		return
+	}
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[reset][bold]%s:", heading)))
+	for _, result := range results {
+		line := fmt.Sprintf("  %s %s", result.Address, result.Version)
+		if result.Verified {
+			line += c.Colorize().Color(" [green](verified)[reset]")
+		}
+		c.Ui.Output(line)
+		if result.Description != "" {
+			c.Ui.Output("      " + result.Description)
+		}
+	}
+	c.Ui.Output("")
+}
+
+// searchError returns a diagnostic for a failed search of the given kind of
+// objects in the given registry. A registry without a search endpoint is
+// only a warning, so that searching several registries still shows the
+// results of the others.
+func searchError(host svchost.Hostname, kind string, err error) tfdiags.Diagnostic {
+	if errors.Is(err, registry.ErrSearchUnsupported) {
+		return tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Registry search not supported",
+			fmt.Sprintf("The registry at %s doesn't support searching for %s, so it was skipped.", host.ForDisplay(), kind),
+		)
+	}
+	return tfdiags.Sourceless(
+		tfdiags.Error,
+		"Failed to search registry",
+		fmt.Sprintf("Failed to search for %s in the registry at %s: %s.", kind, host.ForDisplay(), err),
+	)
+}
+
+func (c *SearchCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] search [options] QUERY
+
+  Search module and provider registries for modules and providers matching
+  the given search terms, and show the latest version, description and
+  verified status of each.
+
+  Registries can optionally offer a search endpoint. Registries that don't
+  are skipped with a warning.
+
+Options:
+
+  -json              Show the results as JSON.
+
+  -limit=10          The maximum number of modules and of providers to show
+                     from each registry. Defaults to 10.
+
+  -registry=host     The hostname of a registry to search. This flag can be
+                     set multiple times. Defaults to the public registry,
+                     registry.opentofu.org.
+
+  -type=all          Search only for "module" or "provider". Defaults to
+                     "all", to search for both.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *SearchCommand) Synopsis() string {
+	return "Search registries for modules and providers"
+}
@@ -0,0 +1,142 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	svchost "github.com/hashicorp/terraform-svchost"
+	"github.com/hashicorp/terraform-svchost/disco"
+	"github.com/mitchellh/cli"
+)
+
+// testSearchRegistry starts a registry whose search endpoints return a
+// single module and a single provider, and a registry without search
+// endpoints.
+func testSearchRegistry(t *testing.T) *disco.Disco {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/modules/search":
+			w.Write([]byte(`{"modules":[{"namespace":"acme","name":"network","provider":"aws","version":"1.2.0","description":"A network for ` + r.URL.Query().Get("q") + `","verified":true}]}`))
+		case "/v1/providers/search":
+			w.Write([]byte(`{"providers":[{"namespace":"acme","name":"widgets","version":"2.0.0","description":"Manages widgets","downloads":42}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	services := disco.New()
+	services.ForceHostServices(svchost.Hostname("registry.example.com"), map[string]interface{}{
+		"modules.v1":   server.URL + "/v1/modules/",
+		"providers.v1": server.URL + "/v1/providers/",
+	})
+	services.ForceHostServices(svchost.Hostname("nosearch.example.com"), map[string]interface{}{
+		"modules.v1": server.URL + "/nosearch/modules/",
+	})
+	return services
+}
+
+func TestSearch(t *testing.T) {
+	ui := cli.NewMockUi()
+	c := &SearchCommand{
+		Meta: Meta{
+			Ui:       ui,
+			Services: testSearchRegistry(t),
+		},
+	}
+	if code := c.Run([]string{"-registry=registry.example.com", "vpc"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n%s", code, ui.ErrorWriter.String())
+	}
+
+	output := ui.OutputWriter.String()
+	for _, want := range []string{
+		"registry.example.com/acme/network/aws 1.2.0",
+		"(verified)",
+		"A network for vpc",
+		"registry.example.com/acme/widgets 2.0.0",
+		"Manages widgets",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output doesn't contain %q\n%s", want, output)
+		}
+	}
+}
+
+func TestSearch_json(t *testing.T) {
+	ui := cli.NewMockUi()
+	c := &SearchCommand{
+		Meta: Meta{
+			Ui:       ui,
+			Services: testSearchRegistry(t),
+		},
+	}
+	if code := c.Run([]string{"-json", "-type=provider", "-registry=registry.example.com", "widgets"}); code != 0 {
+		t.Fatalf("unexpected exit code %d\n%s", code, ui.ErrorWriter.String())
+	}
+
+	var got searchResults
+	if err := json.Unmarshal([]byte(ui.OutputWriter.String()), &got); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter.String())
+	}
+	want := searchResults{
+		FormatVersion: "1.0",
+		Modules:       []*searchResult{},
+		Providers: []*searchResult{
+			{
+				Address:     "registry.example.com/acme/widgets",
+				Version:     "2.0.0",
+				Description: "Manages widgets",
+				Downloads:   42,
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong results\n%s", diff)
+	}
+}
+
+func TestSearch_unsupported(t *testing.T) {
+	ui := cli.NewMockUi()
+	c := &SearchCommand{
+		Meta: Meta{
+			Ui:       ui,
+			Services: testSearchRegistry(t),
+		},
+	}
+	args := []string{"-type=module", "-registry=nosearch.example.com", "-registry=registry.example.com", "vpc"}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("unexpected exit code %d\n%s", code, ui.ErrorWriter.String())
+	}
+
+	if got := ui.ErrorWriter.String(); !strings.Contains(got, "nosearch.example.com doesn't support searching for modules") {
+		t.Errorf("missing warning\n%s", got)
+	}
+	if got := ui.OutputWriter.String(); !strings.Contains(got, "registry.example.com/acme/network/aws") {
+		t.Errorf("missing results of the other registry\n%s", got)
+	}
+}
+
+func TestSearch_invalidType(t *testing.T) {
+	ui := cli.NewMockUi()
+	c := &SearchCommand{
+		Meta: Meta{
+			Ui: ui,
+		},
+	}
+	if code := c.Run([]string{"-type=resource", "vpc"}); code != 1 {
+		t.Fatalf("unexpected exit code %d", code)
+	}
+	if got := ui.ErrorWriter.String(); !strings.Contains(got, `Invalid -type "resource"`) {
+		t.Errorf("wrong error\n%s", got)
+	}
+}
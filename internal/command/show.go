@@ -60,6 +60,7 @@ func (c *ShowCommand) Run(rawArgs []string) int {
 
 	// Parse and validate flags
 	args, diags := arguments.ParseShow(rawArgs)
+	diags = diags.Append(common.Parse())
 	if diags.HasErrors() {
 		c.View.Diagnostics(diags)
 		c.View.HelpPrompt("show")
@@ -69,6 +70,9 @@ func (c *ShowCommand) Run(rawArgs []string) int {
 
 	// Set up view
 	view := views.NewShow(args.ViewType, c.View)
+	if jsonView, ok := view.(*views.ShowJSON); ok {
+		jsonView.ValidateSchema = args.ValidateSchema
+	}
 
 	// Check for user-supplied plugin path
 	var err error
@@ -81,6 +85,10 @@ func (c *ShowCommand) Run(rawArgs []string) int {
 	// Inject variables from args into meta for static evaluation
 	c.GatherVariables(args.Vars)
 
+	if args.Modules {
+		return c.showModules(view)
+	}
+
 	// Load the encryption configuration
 	enc, encDiags := c.Encryption()
 	diags = diags.Append(encDiags)
@@ -108,11 +116,37 @@ Usage: tofu [global options] show [options] [path]
   Reads and outputs a OpenTofu state or plan file in a human-readable
   form. If no path is specified, the current state will be shown.
 
+  With -modules, shows the modules installed in the working directory
+  instead, along with any that must be installed or reinstalled by
+  running "tofu init" because the configuration has changed.
+
 Options:
 
   -no-color           If specified, output won't contain any color.
+  -full-string-diff   Show changes to long and multi-line strings in full,
+                      instead of as an inline word diff.
+  -suppress-attribute=name
+                      Hide the values of the given resource attribute in
+                      the changes of a saved plan. Use "type.name" to only
+                      hide it in resources of that type.
+  -summary            Show the changes of a saved plan grouped by module
+                      and resource type, instead of every change in full.
+  -summary-expand=pattern
+                      With -summary, also show in full the changes to the
+                      instances of the given resource type, or of the given
+                      module, resource or instance address.
   -json               If specified, output the OpenTofu plan or state in
                       a machine-readable form.
+  -json-version=n     Produce version n of the JSON output formats. By
+                      default, the latest version is produced.
+  -validate-schema    With -json, check the output against the published
+                      schema for its format and version, and fail instead
+                      of displaying output that doesn't conform to it.
+  -format=html        Output a saved plan as a standalone HTML report,
+                      with a collapsible section for each resource change.
+                      Requires the path of a plan file.
+  -modules            Show the installed modules instead of a state or
+                      plan. Can be combined with -json.
 
 `
 	return strings.TrimSpace(helpText)
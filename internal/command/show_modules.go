@@ -0,0 +1,138 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/modsdir"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// showModules displays the modules installed in the working directory, for
+// "tofu show -modules".
+func (c *ShowCommand) showModules(view views.Show) int {
+	var diags tfdiags.Diagnostics
+
+	modulesDir := c.modulesDir()
+	manifest, err := modsdir.ReadManifestSnapshotForDir(modulesDir)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read module manifest",
+			fmt.Sprintf("Could not read the manifest of installed modules in %s: %s.", modulesDir, err),
+		))
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	// Errors about modules that aren't installed or don't match their calls
+	// are expected here, since reporting them is the point of this command,
+	// so we only stop if the root module couldn't be loaded at all.
+	config, configDiags := c.loadConfig(".")
+	if config == nil || config.Module == nil {
+		diags = diags.Append(configDiags)
+		view.Diagnostics(diags)
+		return 1
+	}
+
+	return view.DisplayModules(modulesDir, showModuleStatuses(config, manifest))
+}
+
+// showModuleStatuses compares the module calls in the given configuration
+// with the modules recorded in the given manifest, returning the status of
+// every module in either of them, sorted so that each module comes after its
+// parent.
+func showModuleStatuses(config *configs.Config, manifest modsdir.Manifest) []*views.ShowModule {
+	var ret []*views.ShowModule
+	called := make(map[string]bool)
+
+	var walk func(cfg *configs.Config)
+	walk = func(cfg *configs.Config) {
+		for name, call := range cfg.Module.ModuleCalls {
+			path := cfg.Path.Child(name)
+			key := manifest.ModuleKey(path)
+			called[key] = true
+
+			m := &views.ShowModule{Path: path}
+			ret = append(ret, m)
+
+			record, installed := manifest[key]
+			if !installed {
+				if call.SourceAddr != nil {
+					m.Source = call.SourceAddr.String()
+				}
+				m.Status = views.ModuleNotInstalled
+				m.Detail = "The module is called from the configuration but has not been installed."
+				continue
+			}
+
+			m.Source = record.SourceAddr
+			m.Dir = record.Dir
+			if record.Version != nil {
+				m.Version = record.Version.String()
+			}
+
+			required := call.Version.Required
+			switch {
+			case !dirExists(record.Dir):
+				m.Status = views.ModuleNotInstalled
+				m.Detail = fmt.Sprintf("The module directory %s does not exist.", record.Dir)
+			case call.SourceAddr != nil && call.SourceAddr.String() != record.SourceAddr:
+				m.Status = views.ModuleSourceChanged
+				m.Detail = fmt.Sprintf("The source address was changed to %q.", call.SourceAddr.String())
+			case len(required) > 0 && record.Version == nil:
+				m.Status = views.ModuleVersionChanged
+				m.Detail = fmt.Sprintf("A version constraint %q was added.", required.String())
+			case record.Version != nil && !required.Check(record.Version):
+				m.Status = views.ModuleVersionChanged
+				m.Detail = fmt.Sprintf("The installed version does not match the version constraint %q.", required.String())
+			default:
+				m.Status = views.ModuleInstalled
+			}
+
+			if child := cfg.Children[name]; child != nil {
+				walk(child)
+			}
+		}
+	}
+	walk(config)
+
+	for key, record := range manifest {
+		// The manifest also has an entry for the root module, which we
+		// don't show.
+		if key == "" || called[key] {
+			continue
+		}
+		m := &views.ShowModule{
+			Path:   strings.Split(key, "."),
+			Source: record.SourceAddr,
+			Dir:    record.Dir,
+			Status: views.ModuleUnused,
+			Detail: "The module is no longer called from the configuration.",
+		}
+		if record.Version != nil {
+			m.Version = record.Version.String()
+		}
+		ret = append(ret, m)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return slices.Compare(ret[i].Path, ret[j].Path) < 0
+	})
+	return ret
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/jsonschema"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -495,6 +496,56 @@ func TestShow_plan_json(t *testing.T) {
 	}
 }
 
+func TestShow_plan_jsonValidateSchema(t *testing.T) {
+	planPath := showFixturePlanFile(t, plans.Create)
+
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(showFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	args := []string{
+		"-json",
+		"-json-version=1",
+		"-validate-schema",
+		planPath,
+	}
+	code := c.Run(args)
+	output := done(t)
+
+	if code != 0 {
+		t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+	}
+	if err := jsonschema.Validate(jsonschema.Plan, 1, []byte(output.Stdout())); err != nil {
+		t.Fatalf("displayed plan doesn't conform to the schema: %s", err)
+	}
+}
+
+func TestShow_jsonVersionUnsupported(t *testing.T) {
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(showFixtureProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-json", "-json-version=2"})
+	output := done(t)
+
+	if code != 1 {
+		t.Fatalf("unexpected exit status %d; want 1\ngot: %s", code, output.Stdout())
+	}
+	got := output.Stderr()
+	want := "version 2 is not supported by this version of OpenTofu"
+	if !strings.Contains(got, want) {
+		t.Fatalf("unexpected error\ngot: %s\nwant: message containing %q", got, want)
+	}
+}
+
 func TestShow_state(t *testing.T) {
 	originalState := testState()
 	root := originalState.RootModule()
@@ -1215,3 +1266,82 @@ type priorState struct {
 	Values          map[string]interface{} `json:"values,omitempty"`
 	SensitiveValues map[string]bool        `json:"sensitive_values,omitempty"`
 }
+
+func TestShow_modules(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("show-modules"), td)
+	defer testChdir(t, td)()
+
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-modules", "-no-color"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+	}
+
+	want := `Modules installed in .terraform/modules:
+
+- a: ./a
+    a
+  - c: ./c
+      a/c
+- b: ./old-b
+    b
+    Needs reinstall: The source address was changed to "./b".
+- d: ./d
+    Not installed: The module is called from the configuration but has not been installed.
+- gone: ./gone
+    gone
+    Unused: The module is no longer called from the configuration.
+
+Run "tofu init" to install the modules that need it.
+`
+	if diff := cmp.Diff(want, output.Stdout()); diff != "" {
+		t.Fatalf("wrong output\n%s", diff)
+	}
+}
+
+func TestShow_modulesJSON(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("show-modules"), td)
+	defer testChdir(t, td)()
+
+	view, done := testView(t)
+	c := &ShowCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-modules", "-json"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("unexpected exit status %d; want 0\ngot: %s", code, output.Stderr())
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal([]byte(output.Stdout()), &got); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"format_version": "1.0",
+		"modules": []interface{}{
+			map[string]interface{}{"address": "module.a", "source": "./a", "dir": "a", "status": "installed"},
+			map[string]interface{}{"address": "module.a.module.c", "source": "./c", "dir": "a/c", "status": "installed"},
+			map[string]interface{}{"address": "module.b", "source": "./old-b", "dir": "b", "status": "source_changed", "detail": `The source address was changed to "./b".`},
+			map[string]interface{}{"address": "module.d", "source": "./d", "status": "not_installed", "detail": "The module is called from the configuration but has not been installed."},
+			map[string]interface{}{"address": "module.gone", "source": "./gone", "dir": "gone", "status": "unused", "detail": "The module is no longer called from the configuration."},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong output\n%s", diff)
+	}
+}
@@ -6,13 +6,18 @@
 package command
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -30,12 +35,32 @@ func (c *StateListCommand) Run(args []string) int {
 	c.Meta.varFlagSet(cmdFlags)
 	cmdFlags.StringVar(&statePath, "state", "", "path")
 	lookupId := cmdFlags.String("id", "", "Restrict output to paths with a resource having the specified ID.")
+	var jsonOutput bool
+	var format string
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
+	cmdFlags.StringVar(&format, "format", "", "format")
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
 		return cli.RunResultHelp
 	}
 	args = cmdFlags.Args()
 
+	switch format {
+	case "":
+		format = "text"
+		if jsonOutput {
+			format = "json"
+		}
+	case "text", "table", "json":
+		if jsonOutput && format != "json" {
+			c.Ui.Error("The -json option cannot be used together with -format=" + format + ".\n")
+			return cli.RunResultHelp
+		}
+	default:
+		c.Ui.Error(fmt.Sprintf("Invalid output format %q. The supported formats are text, table and json.\n", format))
+		return cli.RunResultHelp
+	}
+
 	if statePath != "" {
 		c.Meta.statePath = statePath
 	}
@@ -68,7 +93,16 @@ func (c *StateListCommand) Run(args []string) int {
 		c.Ui.Error(fmt.Sprintf(errStateLoadingState, err))
 		return 1
 	}
-	if err := stateMgr.RefreshState(); err != nil {
+
+	// When listing particular addresses, only the resources that might
+	// match them are read from the state.
+	keep := resourceFilter(args...)
+	if len(args) == 0 || keep == nil {
+		err = stateMgr.RefreshState()
+	} else {
+		err = statemgr.RefreshPartial(stateMgr, keep)
+	}
+	if err != nil {
 		c.Ui.Error(fmt.Sprintf("Failed to load state: %s", err))
 		return 1
 	}
@@ -91,14 +125,39 @@ func (c *StateListCommand) Run(args []string) int {
 		return 1
 	}
 
+	var entries []stateListEntry
 	for _, addr := range addrs {
 		if is := state.ResourceInstance(addr); is != nil {
 			if *lookupId == "" || *lookupId == states.LegacyInstanceObjectID(is.Current) {
-				c.Ui.Output(addr.String())
+				rs := state.Resource(addr.ContainingResource())
+				entries = append(entries, newStateListEntry(addr, rs.ProviderConfig, is.Current))
 			}
 		}
 	}
 
+	switch format {
+	case "json":
+		output := stateListJSON{
+			FormatVersion: stateListFormatVersion,
+			Resources:     entries,
+		}
+		if output.Resources == nil {
+			output.Resources = []stateListEntry{}
+		}
+		jsonOut, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal resources to JSON: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(jsonOut))
+	case "table":
+		c.Ui.Output(stateListTable(entries))
+	default:
+		for _, entry := range entries {
+			c.Ui.Output(entry.Address)
+		}
+	}
+
 	c.showDiagnostics(diags)
 
 	return 0
@@ -135,6 +194,15 @@ Options:
                       resource types have an attribute named "id" whose value
                       equals the given id string.
 
+  -format=FORMAT      Selects the output format. The default, "text", lists
+                      only the address of each instance. "table" adds columns
+                      for the resource type, provider, module, instance key
+                      and the id, name and tags attributes when present.
+                      "json" produces the same information in a
+                      machine-readable JSON format.
+
+  -json               Equivalent to -format=json.
+
 `
 	return strings.TrimSpace(helpText)
 }
@@ -143,6 +211,185 @@ func (c *StateListCommand) Synopsis() string {
 	return "List resources in the state"
 }
 
+// stateListFormatVersion is the version of the JSON output of
+// "tofu state list -json". The minor version is incremented for
+// backward-compatible changes, and the major version for breaking changes.
+const stateListFormatVersion = "1.0"
+
+type stateListJSON struct {
+	FormatVersion string           `json:"format_version"`
+	Resources     []stateListEntry `json:"resources"`
+}
+
+// stateListEntry describes a single resource instance listed by
+// "tofu state list" in the table and JSON formats.
+type stateListEntry struct {
+	Address string `json:"address"`
+	Mode    string `json:"mode"`
+	Type    string `json:"type"`
+	Name    string `json:"name"`
+
+	// Module is the address of the module instance containing the resource,
+	// which is empty for the root module.
+	Module string `json:"module,omitempty"`
+
+	// Index is omitted for a resource not using count or for_each.
+	Index json.RawMessage `json:"index,omitempty"`
+
+	// ProviderName is the source address of the provider, and ProviderConfig
+	// is the address of the provider configuration that manages the
+	// resource.
+	ProviderName   string `json:"provider_name"`
+	ProviderConfig string `json:"provider_config"`
+
+	// Summary contains the "id", "name" and "tags" attributes of the current
+	// object of the instance, if the resource type has them and they are not
+	// sensitive.
+	Summary stateListSummary `json:"summary"`
+
+	// providerDisplay is the short form of the provider source address, for
+	// the table format.
+	providerDisplay string
+}
+
+type stateListSummary struct {
+	ID   string            `json:"id,omitempty"`
+	Name string            `json:"name,omitempty"`
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+func newStateListEntry(addr addrs.AbsResourceInstance, provider addrs.AbsProviderConfig, obj *states.ResourceInstanceObjectSrc) stateListEntry {
+	entry := stateListEntry{
+		Address:        addr.String(),
+		Type:           addr.Resource.Resource.Type,
+		Name:           addr.Resource.Resource.Name,
+		Module:         addr.Module.String(),
+		ProviderName:   provider.Provider.String(),
+		ProviderConfig: provider.String(),
+		Summary:        stateListObjectSummary(obj),
+
+		providerDisplay: provider.Provider.ForDisplay(),
+	}
+
+	switch addr.Resource.Resource.Mode {
+	case addrs.ManagedResourceMode:
+		entry.Mode = "managed"
+	case addrs.DataResourceMode:
+		entry.Mode = "data"
+	}
+
+	switch key := addr.Resource.Key.(type) {
+	case addrs.IntKey:
+		entry.Index, _ = json.Marshal(int(key))
+	case addrs.StringKey:
+		entry.Index, _ = json.Marshal(string(key))
+	}
+
+	return entry
+}
+
+// stateListObjectSummary returns the attributes of the given object that
+// identify it to a person, without decoding it with the resource type schema.
+// Attributes that are marked as sensitive are left out.
+func stateListObjectSummary(obj *states.ResourceInstanceObjectSrc) stateListSummary {
+	var ret stateListSummary
+	if obj == nil {
+		return ret
+	}
+
+	sensitive := make(map[string]bool)
+	for _, pvm := range obj.AttrSensitivePaths {
+		if len(pvm.Path) == 0 {
+			continue
+		}
+		if step, ok := pvm.Path[0].(cty.GetAttrStep); ok {
+			sensitive[step.Name] = true
+		}
+	}
+
+	switch {
+	case obj.AttrsJSON != nil:
+		var attrs map[string]json.RawMessage
+		if err := json.Unmarshal(obj.AttrsJSON, &attrs); err != nil {
+			return ret
+		}
+		// The attributes may have other types in some resource types, in
+		// which case they are not included in the summary.
+		if !sensitive["id"] {
+			_ = json.Unmarshal(attrs["id"], &ret.ID)
+		}
+		if !sensitive["name"] {
+			_ = json.Unmarshal(attrs["name"], &ret.Name)
+		}
+		if !sensitive["tags"] {
+			var tags map[string]string
+			if err := json.Unmarshal(attrs["tags"], &tags); err == nil && len(tags) != 0 {
+				ret.Tags = tags
+			}
+		}
+	case obj.AttrsFlat != nil:
+		if !sensitive["id"] {
+			ret.ID = obj.AttrsFlat["id"]
+		}
+		if !sensitive["name"] {
+			ret.Name = obj.AttrsFlat["name"]
+		}
+		if !sensitive["tags"] {
+			for k, v := range obj.AttrsFlat {
+				if k == "tags.%" {
+					continue
+				}
+				if tag, ok := strings.CutPrefix(k, "tags."); ok {
+					if ret.Tags == nil {
+						ret.Tags = make(map[string]string)
+					}
+					ret.Tags[tag] = v
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// stateListTable formats the given entries as a table with a header row, using
+// "-" for empty cells so that each row has the same number of fields.
+func stateListTable(entries []stateListEntry) string {
+	cell := func(s string) string {
+		if s == "" {
+			return "-"
+		}
+		return s
+	}
+
+	var buf strings.Builder
+	w := tabwriter.NewWriter(&buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ADDRESS\tTYPE\tPROVIDER\tMODULE\tKEY\tID\tNAME\tTAGS")
+	for _, entry := range entries {
+		var key string
+		if entry.Index != nil {
+			key = string(entry.Index)
+		}
+		tags := make([]string, 0, len(entry.Summary.Tags))
+		for k, v := range entry.Summary.Tags {
+			tags = append(tags, k+"="+v)
+		}
+		sort.Strings(tags)
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			entry.Address,
+			entry.Type,
+			entry.providerDisplay,
+			cell(entry.Module),
+			cell(key),
+			cell(entry.Summary.ID),
+			cell(entry.Summary.Name),
+			cell(strings.Join(tags, ",")),
+		)
+	}
+	w.Flush()
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
 const errStateLoadingState = `Error loading the state: %[1]s
 
 Please ensure that your OpenTofu state exists and that you've
@@ -6,10 +6,17 @@
 package command
 
 import (
+	"encoding/json"
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/states"
 )
 
 func TestStateList(t *testing.T) {
@@ -270,6 +277,149 @@ func TestStateList_modules(t *testing.T) {
 
 }
 
+// testStateListSummaryState returns a state with resource instances that have
+// the attributes summarized by the table and JSON formats of "state list".
+func testStateListSummaryState() *states.State {
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	return states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "foo",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"i-1","name":"web","tags":{"env":"prod","team":"a"}}`),
+				Status:    states.ObjectReady,
+			},
+			provider,
+		)
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "bar",
+			}.Instance(addrs.StringKey("b")).Absolute(addrs.RootModuleInstance.Child("child", addrs.IntKey(0))),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"i-2","name":"secret","tags":null}`),
+				AttrSensitivePaths: []cty.PathValueMarks{
+					{Path: cty.GetAttrPath("name"), Marks: cty.NewValueMarks(marks.Sensitive)},
+				},
+				Status: states.ObjectReady,
+			},
+			provider,
+		)
+	})
+}
+
+func TestStateList_json(t *testing.T) {
+	statePath := testStateFile(t, testStateListSummaryState())
+
+	ui := cli.NewMockUi()
+	c := &StateListCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	if code := c.Run([]string{"-state", statePath, "-json"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+		t.Fatalf("invalid output: %s\n%s", err, ui.OutputWriter)
+	}
+	want := map[string]interface{}{
+		"format_version": "1.0",
+		"resources": []interface{}{
+			map[string]interface{}{
+				"address":         "test_instance.foo",
+				"mode":            "managed",
+				"type":            "test_instance",
+				"name":            "foo",
+				"provider_name":   "registry.opentofu.org/hashicorp/test",
+				"provider_config": `provider["registry.opentofu.org/hashicorp/test"]`,
+				"summary": map[string]interface{}{
+					"id":   "i-1",
+					"name": "web",
+					"tags": map[string]interface{}{"env": "prod", "team": "a"},
+				},
+			},
+			map[string]interface{}{
+				"address":         `module.child[0].test_instance.bar["b"]`,
+				"mode":            "managed",
+				"type":            "test_instance",
+				"name":            "bar",
+				"module":          "module.child[0]",
+				"index":           "b",
+				"provider_name":   "registry.opentofu.org/hashicorp/test",
+				"provider_config": `provider["registry.opentofu.org/hashicorp/test"]`,
+				"summary": map[string]interface{}{
+					"id": "i-2",
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong output\n%s", diff)
+	}
+}
+
+func TestStateList_table(t *testing.T) {
+	statePath := testStateFile(t, testStateListSummaryState())
+
+	ui := cli.NewMockUi()
+	c := &StateListCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(testProvider()),
+			Ui:               ui,
+		},
+	}
+
+	if code := c.Run([]string{"-state", statePath, "-format=table", "-id=i-2"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	got := strings.Split(strings.TrimSpace(ui.OutputWriter.String()), "\n")
+	want := [][]string{
+		{"ADDRESS", "TYPE", "PROVIDER", "MODULE", "KEY", "ID", "NAME", "TAGS"},
+		{`module.child[0].test_instance.bar["b"]`, "test_instance", "hashicorp/test", "module.child[0]", `"b"`, "i-2", "-", "-"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("wrong number of rows\n%s", ui.OutputWriter)
+	}
+	for i, row := range got {
+		if diff := cmp.Diff(want[i], strings.Fields(row)); diff != "" {
+			t.Errorf("wrong row %d\n%s", i, diff)
+		}
+	}
+}
+
+func TestStateList_invalidFormat(t *testing.T) {
+	statePath := testStateFile(t, testState())
+
+	for _, args := range [][]string{
+		{"-format=yaml"},
+		{"-json", "-format=table"},
+	} {
+		ui := cli.NewMockUi()
+		c := &StateListCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(testProvider()),
+				Ui:               ui,
+			},
+		}
+		if code := c.Run(append([]string{"-state", statePath}, args...)); code != cli.RunResultHelp {
+			t.Errorf("%s: wrong exit status %d; want %d", args, code, cli.RunResultHelp)
+		}
+	}
+}
+
 const testStateListOutput = `
 test_instance.foo
 `
@@ -189,6 +189,44 @@ func (c *StateMeta) lookupResourceInstanceAddrs(state *states.State, addrStrs ..
 	return ret, diags
 }
 
+// resourceFilter returns a function that reports whether a resource may
+// contain any of the instances selected by the given addresses, as accepted
+// by lookupResourceInstanceAddrs, so that a read-only command can read only
+// those resources from the state.
+//
+// It returns nil if any of the addresses is invalid, in which case the whole
+// state should be read so that the error is reported as usual.
+func resourceFilter(addrStrs ...string) func(addrs.AbsResource) bool {
+	var targets []addrs.Targetable
+	for _, addrStr := range addrStrs {
+		target, diags := addrs.ParseTargetStr(addrStr)
+		if diags.HasErrors() {
+			return nil
+		}
+		targets = append(targets, target.Subject)
+	}
+
+	return func(rs addrs.AbsResource) bool {
+		for _, target := range targets {
+			switch addr := target.(type) {
+			case addrs.ModuleInstance:
+				if addr.IsAncestor(rs.Module) || addr.TargetContains(rs) {
+					return true
+				}
+			case addrs.AbsResource:
+				if addr.Equal(rs) {
+					return true
+				}
+			case addrs.AbsResourceInstance:
+				if addr.ContainingResource().Equal(rs) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+}
+
 func (c *StateMeta) lookupAllResourceInstanceAddrs(state *states.State) ([]addrs.AbsResourceInstance, tfdiags.Diagnostics) {
 	var ret []addrs.AbsResourceInstance
 	var diags tfdiags.Diagnostics
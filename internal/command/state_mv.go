@@ -26,7 +26,7 @@ type StateMvCommand struct {
 	StateMeta
 }
 
-func (c *StateMvCommand) Run(args []string) int {
+func (c *StateMvCommand) Run(args []string) (status int) {
 	args = c.Meta.process(args)
 	// We create two metas to track the two states
 	var backupPathOut, statePathOut string
@@ -106,6 +106,9 @@ func (c *StateMvCommand) Run(args []string) int {
 		return 1
 	}
 
+	audit := c.auditBegin("state mv", stateFromMgr)
+	defer audit.finish(&status)
+
 	if c.stateLock {
 		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
 		if diags := stateLocker.Lock(stateFromMgr, "state-mv"); diags.HasErrors() {
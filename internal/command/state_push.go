@@ -29,7 +29,7 @@ type StatePushCommand struct {
 	StateMeta
 }
 
-func (c *StatePushCommand) Run(args []string) int {
+func (c *StatePushCommand) Run(args []string) (status int) {
 	args = c.Meta.process(args)
 	var flagForce bool
 	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("state push")
@@ -114,6 +114,9 @@ func (c *StatePushCommand) Run(args []string) int {
 		return 1
 	}
 
+	audit := c.auditBegin("state push", stateMgr)
+	defer audit.finish(&status)
+
 	if c.stateLock {
 		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
 		if diags := stateLocker.Lock(stateMgr, "state-push"); diags.HasErrors() {
@@ -29,7 +29,7 @@ type StateReplaceProviderCommand struct {
 	StateMeta
 }
 
-func (c *StateReplaceProviderCommand) Run(args []string) int {
+func (c *StateReplaceProviderCommand) Run(args []string) (status int) {
 	args = c.Meta.process(args)
 
 	var autoApprove bool
@@ -93,6 +93,9 @@ func (c *StateReplaceProviderCommand) Run(args []string) int {
 		return 1
 	}
 
+	audit := c.auditBegin("state replace-provider", stateMgr)
+	defer audit.finish(&status)
+
 	// Acquire lock if requested
 	if c.stateLock {
 		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
@@ -24,7 +25,7 @@ type StateRmCommand struct {
 	StateMeta
 }
 
-func (c *StateRmCommand) Run(args []string) int {
+func (c *StateRmCommand) Run(args []string) (status int) {
 	args = c.Meta.process(args)
 	var dryRun bool
 	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("state rm")
@@ -63,6 +64,9 @@ func (c *StateRmCommand) Run(args []string) int {
 		return 1
 	}
 
+	audit := c.auditBegin("state rm", stateMgr)
+	defer audit.finish(&status)
+
 	if c.stateLock {
 		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
 		if diags := stateLocker.Lock(stateMgr, "state-rm"); diags.HasErrors() {
@@ -166,6 +170,20 @@ func (c *StateRmCommand) Run(args []string) int {
 	return 0
 }
 
+func (c *StateRmCommand) AutocompleteArgs() complete.Predictor {
+	// Any number of addresses can be given.
+	return c.completePredictStateAddress()
+}
+
+func (c *StateRmCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-dry-run":      complete.PredictNothing,
+		"-lock":         completePredictBoolean,
+		"-lock-timeout": complete.PredictAnything,
+		"-state":        complete.PredictFiles("*.tfstate"),
+	}
+}
+
 func (c *StateRmCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] state (remove|rm) [options] ADDRESS...
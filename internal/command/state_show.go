@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
@@ -20,6 +21,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/jsonstate"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tofumigrate"
 )
 
@@ -94,6 +96,10 @@ func (c *StateShowCommand) Run(args []string) int {
 	opReq.AllowUnsetVariables = true
 	opReq.ConfigDir = cwd
 
+	// Only the resource being shown is read from the state.
+	keep := resourceFilter(args[0])
+	opReq.StateFilter = keep
+
 	opReq.ConfigLoader, err = c.initConfigLoader()
 	if err != nil {
 		c.Streams.Eprintf("Error initializing config loader: %s\n", err)
@@ -125,7 +131,7 @@ func (c *StateShowCommand) Run(args []string) int {
 		c.Streams.Eprintln(fmt.Sprintf(errStateLoadingState, err))
 		return 1
 	}
-	if err := stateMgr.RefreshState(); err != nil {
+	if err := statemgr.RefreshPartial(stateMgr, keep); err != nil {
 		c.Streams.Eprintf("Failed to refresh state: %s\n", err)
 		return 1
 	}
@@ -186,6 +192,18 @@ func (c *StateShowCommand) Run(args []string) int {
 	return 0
 }
 
+func (c *StateShowCommand) AutocompleteArgs() complete.Predictor {
+	return completePredictSequence{
+		c.completePredictStateAddress(),
+	}
+}
+
+func (c *StateShowCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-state": complete.PredictFiles("*.tfstate"),
+	}
+}
+
 func (c *StateShowCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] state show [options] ADDRESS
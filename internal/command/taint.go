@@ -7,8 +7,11 @@ package command
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
+	"github.com/posener/complete"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/clistate"
@@ -24,11 +27,13 @@ type TaintCommand struct {
 	Meta
 }
 
-func (c *TaintCommand) Run(args []string) int {
+func (c *TaintCommand) Run(args []string) (status int) {
 	args = c.Meta.process(args)
-	var allowMissing bool
+	var allowMissing, autoApprove bool
 	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("taint")
 	cmdFlags.BoolVar(&allowMissing, "allow-missing", false, "allow missing")
+	cmdFlags.BoolVar(&autoApprove, "auto-approve", false, "skip confirmation of pattern matches")
+	cmdFlags.BoolVar(&c.Meta.input, "input", true, "input")
 	cmdFlags.StringVar(&c.Meta.backupPath, "backup", "", "path")
 	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
 	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
@@ -50,14 +55,36 @@ func (c *TaintCommand) Run(args []string) int {
 		return 1
 	}
 
-	addr, addrDiags := addrs.ParseAbsResourceInstanceStr(args[0])
-	diags = diags.Append(addrDiags)
-	if addrDiags.HasErrors() {
-		c.showDiagnostics(diags)
-		return 1
+	// The argument is either a single resource instance address, or a pattern
+	// which can match several instances.
+	var addr addrs.AbsResourceInstance
+	var pattern *addrs.AbsResourceInstancePattern
+	var target fmt.Stringer
+	if addrs.IsResourceInstancePattern(args[0]) {
+		p, patternDiags := addrs.ParseAbsResourceInstancePattern(args[0])
+		diags = diags.Append(patternDiags)
+		if patternDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		pattern = &p
+		target = p
+	} else {
+		var addrDiags tfdiags.Diagnostics
+		addr, addrDiags = addrs.ParseAbsResourceInstanceStr(args[0])
+		diags = diags.Append(addrDiags)
+		if addrDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		target = addr
 	}
 
-	if addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+	if pattern != nil && pattern.Mode != addrs.ManagedResourceMode {
+		c.Ui.Error(fmt.Sprintf("Resource instances matching %s cannot be tainted", pattern))
+		return 1
+	}
+	if pattern == nil && addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
 		c.Ui.Error(fmt.Sprintf("Resource instance %s cannot be tainted", addr))
 		return 1
 	}
@@ -104,6 +131,9 @@ func (c *TaintCommand) Run(args []string) int {
 		return 1
 	}
 
+	audit := c.auditBegin("taint", stateMgr)
+	defer audit.finish(&status)
+
 	if c.stateLock {
 		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
 		if diags := stateLocker.Lock(stateMgr, "taint"); diags.HasErrors() {
@@ -126,7 +156,7 @@ func (c *TaintCommand) Run(args []string) int {
 	state := stateMgr.State()
 	if state.Empty() {
 		if allowMissing {
-			return c.allowMissingExit(addr)
+			return c.allowMissingExit(target)
 		}
 
 		diags = diags.Append(tfdiags.Sourceless(
@@ -148,45 +178,77 @@ func (c *TaintCommand) Run(args []string) int {
 
 	ss := state.SyncWrapper()
 
-	// Get the resource and instance we're going to taint
-	rs := ss.Resource(addr.ContainingResource())
-	is := ss.ResourceInstance(addr)
-	if is == nil {
-		if allowMissing {
-			return c.allowMissingExit(addr)
-		}
-
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"No such resource instance",
-			fmt.Sprintf("There is no resource instance in the state with the address %s. If the resource configuration has just been added, you must run \"tofu apply\" once to create the corresponding instance(s) before they can be tainted.", addr),
-		))
-		c.showDiagnostics(diags)
-		return 1
-	}
+	targets := []addrs.AbsResourceInstance{addr}
+	if pattern != nil {
+		targets = stateInstancesMatchingPattern(state, *pattern, states.ObjectReady)
+		if len(targets) == 0 {
+			if allowMissing {
+				return c.allowMissingExit(pattern)
+			}
 
-	obj := is.Current
-	if obj == nil {
-		if len(is.Deposed) != 0 {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
-				"No such resource instance",
-				fmt.Sprintf("Resource instance %s is currently part-way through a create_before_destroy replacement action. Run \"tofu apply\" to complete its replacement before tainting it.", addr),
+				"No matching resource instances",
+				fmt.Sprintf("There are no untainted resource instances in the state matching %s.", pattern),
 			))
-		} else {
-			// Don't know why we're here, but we'll produce a generic error message anyway.
+			c.showDiagnostics(diags)
+			return 1
+		}
+
+		confirmed, confirmDiags := c.confirmPatternTargets("taint", *pattern, targets, autoApprove)
+		diags = diags.Append(confirmDiags)
+		if confirmDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		if !confirmed {
+			c.Ui.Error("Taint cancelled.")
+			return 1
+		}
+	} else {
+		// Get the resource and instance we're going to taint
+		is := ss.ResourceInstance(addr)
+		if is == nil {
+			if allowMissing {
+				return c.allowMissingExit(addr)
+			}
+
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
 				"No such resource instance",
-				fmt.Sprintf("Resource instance %s does not currently have a remote object associated with it, so it cannot be tainted.", addr),
+				fmt.Sprintf("There is no resource instance in the state with the address %s. If the resource configuration has just been added, you must run \"tofu apply\" once to create the corresponding instance(s) before they can be tainted.", addr),
 			))
+			c.showDiagnostics(diags)
+			return 1
+		}
+
+		obj := is.Current
+		if obj == nil {
+			if len(is.Deposed) != 0 {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"No such resource instance",
+					fmt.Sprintf("Resource instance %s is currently part-way through a create_before_destroy replacement action. Run \"tofu apply\" to complete its replacement before tainting it.", addr),
+				))
+			} else {
+				// Don't know why we're here, but we'll produce a generic error message anyway.
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"No such resource instance",
+					fmt.Sprintf("Resource instance %s does not currently have a remote object associated with it, so it cannot be tainted.", addr),
+				))
+			}
+			c.showDiagnostics(diags)
+			return 1
 		}
-		c.showDiagnostics(diags)
-		return 1
 	}
 
-	obj.Status = states.ObjectTainted
-	ss.SetResourceInstanceCurrent(addr, obj, rs.ProviderConfig)
+	for _, addr := range targets {
+		rs := ss.Resource(addr.ContainingResource())
+		obj := ss.ResourceInstance(addr).Current
+		obj.Status = states.ObjectTainted
+		ss.SetResourceInstanceCurrent(addr, obj, rs.ProviderConfig)
+	}
 
 	if err := stateMgr.WriteState(state); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error writing state file: %s", err))
@@ -198,10 +260,28 @@ func (c *TaintCommand) Run(args []string) int {
 	}
 
 	c.showDiagnostics(diags)
-	c.Ui.Output(fmt.Sprintf("Resource instance %s has been marked as tainted.", addr))
+	for _, addr := range targets {
+		c.Ui.Output(fmt.Sprintf("Resource instance %s has been marked as tainted.", addr))
+	}
 	return 0
 }
 
+func (c *TaintCommand) AutocompleteArgs() complete.Predictor {
+	return completePredictSequence{
+		c.completePredictStateAddress(),
+	}
+}
+
+func (c *TaintCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-allow-missing": complete.PredictNothing,
+		"-auto-approve":  complete.PredictNothing,
+		"-input":         completePredictBoolean,
+		"-lock":          completePredictBoolean,
+		"-lock-timeout":  complete.PredictAnything,
+	}
+}
+
 func (c *TaintCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] taint [options] <address>
@@ -223,6 +303,13 @@ Usage: tofu [global options] taint [options] <address>
     aws_instance.bar[1]
     module.foo.module.bar.aws_instance.baz
 
+  The address can also be a pattern containing "*" wildcards, which
+  taints every matching resource instance after listing them for
+  confirmation. A [*] instance key matches any instance key:
+    aws_instance.web[*]
+    module.app[*].aws_instance.*[*]
+    module.app.aws_instance.worker["eu-*"]
+
   Use your shell's quoting or escaping syntax to ensure that the
   address will reach OpenTofu correctly, without any special
   interpretation.
@@ -232,6 +319,12 @@ Options:
   -allow-missing          If specified, the command will succeed (exit code 0)
                           even if the resource is missing.
 
+  -auto-approve           Skip the confirmation of the resource instances
+                          matched by a pattern.
+
+  -input=false            Disable the confirmation prompt. A pattern can then
+                          only be used together with -auto-approve.
+
   -lock=false             Don't hold a state lock during the operation. This is
                           dangerous if others might concurrently run commands
                           against the same workspace.
@@ -252,7 +345,7 @@ func (c *TaintCommand) Synopsis() string {
 	return "Mark a resource instance as not fully functional"
 }
 
-func (c *TaintCommand) allowMissingExit(name addrs.AbsResourceInstance) int {
+func (c *TaintCommand) allowMissingExit(name fmt.Stringer) int {
 	c.showDiagnostics(tfdiags.Sourceless(
 		tfdiags.Warning,
 		"No such resource instance",
@@ -260,3 +353,65 @@ func (c *TaintCommand) allowMissingExit(name addrs.AbsResourceInstance) int {
 	))
 	return 0
 }
+
+// stateInstancesMatchingPattern returns the addresses of the managed resource
+// instances in the given state that match the given pattern and whose
+// current object has the given status, in lexical order.
+func stateInstancesMatchingPattern(state *states.State, pattern addrs.AbsResourceInstancePattern, status states.ObjectStatus) []addrs.AbsResourceInstance {
+	var ret []addrs.AbsResourceInstance
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			if rs.Addr.Resource.Mode != addrs.ManagedResourceMode {
+				continue
+			}
+			for key, is := range rs.Instances {
+				addr := rs.Addr.Instance(key)
+				if is.Current == nil || is.Current.Status != status || !pattern.Matches(addr) {
+					continue
+				}
+				ret = append(ret, addr)
+			}
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Less(ret[j])
+	})
+	return ret
+}
+
+// confirmPatternTargets lists the resource instances that the given pattern
+// matched, and asks for confirmation before the named operation is applied to
+// them, unless autoApprove is set.
+func (m *Meta) confirmPatternTargets(operation string, pattern addrs.AbsResourceInstancePattern, targets []addrs.AbsResourceInstance, autoApprove bool) (bool, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "The pattern %s matches the following resource instances:\n", pattern)
+	for _, addr := range targets {
+		fmt.Fprintf(&buf, "  - %s\n", addr)
+	}
+	m.Ui.Output(buf.String())
+
+	if autoApprove {
+		return true, diags
+	}
+	if !m.Input() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Confirmation required",
+			fmt.Sprintf("Resource instances matched by a pattern can only be %sed after confirmation, but input is disabled. Use -auto-approve to %s them without confirmation.", operation, operation),
+		))
+		return false, diags
+	}
+
+	confirmed, err := m.confirm(&tofu.InputOpts{
+		Id:          operation + "-confirm",
+		Query:       fmt.Sprintf("Do you want to %s these %d resource instances?", operation, len(targets)),
+		Description: "Only 'yes' will be accepted to confirm.",
+	})
+	if err != nil {
+		diags = diags.Append(err)
+		return false, diags
+	}
+	return confirmed, diags
+}
@@ -6,6 +6,7 @@
 package command
 
 import (
+	"fmt"
 	"os"
 	"strings"
 	"testing"
@@ -491,6 +492,163 @@ func TestTaint_module(t *testing.T) {
 	testStateOutput(t, statePath, testTaintModuleStr)
 }
 
+// testTaintPatternState returns a state with three instances of
+// test_instance.foo, of which the given ones are tainted, and one instance of
+// test_instance.bar.
+func testTaintPatternState(tainted ...addrs.InstanceKey) *states.State {
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	return states.BuildState(func(s *states.SyncState) {
+		for _, key := range []addrs.InstanceKey{addrs.IntKey(0), addrs.IntKey(1), addrs.IntKey(2)} {
+			status := states.ObjectReady
+			for _, k := range tainted {
+				if k == key {
+					status = states.ObjectTainted
+				}
+			}
+			s.SetResourceInstanceCurrent(
+				addrs.Resource{
+					Mode: addrs.ManagedResourceMode,
+					Type: "test_instance",
+					Name: "foo",
+				}.Instance(key).Absolute(addrs.RootModuleInstance),
+				&states.ResourceInstanceObjectSrc{
+					AttrsJSON: []byte(fmt.Sprintf(`{"id":"foo%s"}`, key)),
+					Status:    status,
+				},
+				provider,
+			)
+		}
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "bar",
+			}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"bar"}`),
+				Status:    states.ObjectReady,
+			},
+			provider,
+		)
+	})
+}
+
+func TestTaint_pattern(t *testing.T) {
+	statePath := testStateFile(t, testTaintPatternState(addrs.IntKey(2)))
+
+	defer testInputMap(t, map[string]string{
+		"taint-confirm": "yes",
+	})()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &TaintCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"test_instance.foo[*]",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	// The instance that was already tainted isn't listed.
+	output := ui.OutputWriter.String()
+	for _, want := range []string{
+		"The pattern test_instance.foo[*] matches the following resource instances:\n  - test_instance.foo[0]\n  - test_instance.foo[1]\n",
+		"Resource instance test_instance.foo[0] has been marked as tainted.",
+		"Resource instance test_instance.foo[1] has been marked as tainted.",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q\n%s", want, output)
+		}
+	}
+
+	testStateOutput(t, statePath, testTaintPatternStr)
+}
+
+func TestTaint_patternCancelled(t *testing.T) {
+	statePath := testStateFile(t, testTaintPatternState())
+
+	defer testInputMap(t, map[string]string{
+		"taint-confirm": "no",
+	})()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &TaintCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"test_instance.*[*]",
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1\n\n%s", code, ui.OutputWriter.String())
+	}
+	if got, want := ui.ErrorWriter.String(), "Taint cancelled."; !strings.Contains(got, want) {
+		t.Fatalf("error output does not contain %q\n%s", want, got)
+	}
+
+	state := testStateRead(t, statePath)
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for _, is := range rs.Instances {
+				if is.Current.Status != states.ObjectReady {
+					t.Errorf("%s was tainted", rs.Addr)
+				}
+			}
+		}
+	}
+}
+
+func TestTaint_patternNoMatches(t *testing.T) {
+	statePath := testStateFile(t, testTaintPatternState())
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &TaintCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"module.*.test_instance.foo[*]",
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "No matching resource instances"; !strings.Contains(got, want) {
+		t.Fatalf("error output does not contain %q\n%s", want, got)
+	}
+
+	ui = new(cli.MockUi)
+	c.Meta.Ui = ui
+	args = []string{
+		"-state", statePath,
+		"-allow-missing",
+		"module.*.test_instance.foo[*]",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+}
+
 func TestTaint_checkRequiredVersion(t *testing.T) {
 	// Create a temporary working directory that is empty
 	td := t.TempDir()
@@ -567,3 +725,18 @@ module.child:
     ID = blah
     provider = provider["registry.opentofu.org/hashicorp/test"]
 `
+
+const testTaintPatternStr = `
+test_instance.bar:
+  ID = bar
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+test_instance.foo.0: (tainted)
+  ID = foo[0]
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+test_instance.foo.1: (tainted)
+  ID = foo[1]
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+test_instance.foo.2: (tainted)
+  ID = foo[2]
+  provider = provider["registry.opentofu.org/hashicorp/test"]
+`
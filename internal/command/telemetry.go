@@ -6,12 +6,83 @@
 package command
 
 import (
+	"context"
+	"strings"
+	"time"
+
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/backend"
 )
 
 var tracer trace.Tracer
 
+var (
+	operationDuration   metric.Float64Histogram
+	providerRPCCount    metric.Int64Counter
+	providerRPCDuration metric.Float64Histogram
+)
+
 func init() {
 	tracer = otel.Tracer("github.com/opentofu/opentofu/internal/command")
+
+	// The global meter provider discards everything unless metrics export
+	// has been enabled, and these can only fail for invalid instrument
+	// options, so the errors are ignored.
+	meter := otel.Meter("github.com/opentofu/opentofu/internal/command")
+	operationDuration, _ = meter.Float64Histogram(
+		"tofu.operation.duration",
+		metric.WithDescription("Duration of plan, apply and refresh operations."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(1, 5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600),
+	)
+	providerRPCCount, _ = meter.Int64Counter(
+		"tofu.provider.rpc.count",
+		metric.WithDescription("Number of calls made to provider plugins."),
+		metric.WithUnit("{call}"),
+	)
+	providerRPCDuration, _ = meter.Float64Histogram(
+		"tofu.provider.rpc.duration",
+		metric.WithDescription("Duration of calls made to provider plugins."),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60),
+	)
+}
+
+// recordOperationDuration records how long the given operation took since
+// it started.
+func recordOperationDuration(op *backend.Operation, result backend.OperationResult, start time.Time) {
+	name := strings.ToLower(strings.TrimPrefix(op.Type.String(), "OperationType"))
+	outcome := "success"
+	if result != backend.OperationSuccess {
+		outcome = "failure"
+	}
+	operationDuration.Record(context.Background(), time.Since(start).Seconds(), metric.WithAttributes(
+		attribute.String("operation", name),
+		attribute.String("result", outcome),
+	))
+}
+
+// providerMetricsInterceptor returns a gRPC client interceptor that counts
+// and times each call made to the given provider.
+func providerMetricsInterceptor(provider addrs.Provider) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		attrs := metric.WithAttributes(
+			attribute.String("provider", provider.String()),
+			attribute.String("rpc.method", method[strings.LastIndex(method, "/")+1:]),
+			attribute.String("rpc.grpc.status_code", status.Code(err).String()),
+		)
+		providerRPCCount.Add(ctx, 1, attrs)
+		providerRPCDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+		return err
+	}
 }
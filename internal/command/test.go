@@ -12,6 +12,8 @@ import (
 	"path"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hashicorp/hcl/v2"
@@ -58,15 +60,62 @@ Usage: tofu [global options] test [options]
 
 Options:
 
+  -backend-state       Start each test file from the state of the current
+                        workspace in the configured backend, instead of from
+                        an empty state. The state is only read, so run blocks
+                        must execute a plan against the module under test.
+
+  -coverage            Report which resources, output values, variables, and
+                        conditional branches of the module under test were
+                        exercised by the tests.
+
+  -coverage-report=path Write the coverage report to the given path, as JSON
+                        or HTML depending on whether it ends in .json or
+                        .html. Implies -coverage.
+
+  -coverage-threshold=n Fail if less than n percent of the module under test
+                        was covered by the tests. Implies -coverage.
+
   -filter=testfile      If specified, OpenTofu will only execute the test files
                         specified by this flag. You can use this option multiple
                         times to execute more than one test file.
 
+  -format=sarif         Produce the diagnostics, including failed assertions,
+                        as a SARIF log for code scanning tools such as those
+                        of GitHub and GitLab. The format can also be "human"
+                        (the default) or "json", which is the same as the
+                        -json option.
+
   -json                 If specified, machine readable output will be printed in
                         JSON format
 
+  -junit-xml=path       Also write the results of the tests to the given path
+                        in JUnit XML format, for CI systems to display.
+
   -no-color             If specified, output won't contain any color.
 
+  -override-set=name    Also load the environment-scoped override files of
+                        the given set, named like "*_override.name.tf".
+                        Defaults to the TF_OVERRIDE_SET environment variable.
+
+  -parallel=n           Execute up to n test files, and up to n run blocks
+                        marked as parallel within a test file, at the same
+                        time. Defaults to 1.
+
+  -quarantine=name      Report failures of the given test file, or of the run
+                        block given as "file/run", without failing the tests.
+                        Use this option multiple times to quarantine more than
+                        one known-flaky test.
+
+  -record               Execute the tests against the real providers, and record
+                        their responses for each test file into a file next to
+                        it named like "main.tfrecording.json". The values of
+                        sensitive attributes are not recorded.
+
+  -replay               Execute the tests using the responses recorded with
+                        -record instead of calling the providers, so that they
+                        don't need to be configured with any credentials.
+
   -test-directory=path  Set the OpenTofu test directory, defaults to "tests". When set, the
                         test command will search for test files in the current directory and
                         in the one specified by the flag.
@@ -94,9 +143,11 @@ func (c *TestCommand) Run(rawArgs []string) int {
 	var diags tfdiags.Diagnostics
 
 	common, rawArgs := arguments.ParseView(rawArgs)
+	rawArgs = c.Meta.processOverrideSet(rawArgs)
 	c.View.Configure(common)
 
 	args, diags := arguments.ParseTest(rawArgs)
+	diags = diags.Append(common.Parse())
 	if diags.HasErrors() {
 		c.View.Diagnostics(diags)
 		c.View.HelpPrompt("test")
@@ -154,21 +205,8 @@ func (c *TestCommand) Run(rawArgs []string) int {
 
 					fileCount++
 
-					var runs []*moduletest.Run
-					for ix, run := range file.Runs {
-						runs = append(runs, &moduletest.Run{
-							Config: run,
-							Index:  ix,
-							Name:   run.Name,
-						})
-					}
-
-					runCount += len(runs)
-					files[name] = &moduletest.File{
-						Config: file,
-						Name:   name,
-						Runs:   runs,
-					}
+					files[name] = moduletest.NewFile(name, file)
+					runCount += len(files[name].Runs)
 				}
 
 				return files
@@ -178,21 +216,8 @@ func (c *TestCommand) Run(rawArgs []string) int {
 			for name, file := range config.Module.Tests {
 				fileCount++
 
-				var runs []*moduletest.Run
-				for ix, run := range file.Runs {
-					runs = append(runs, &moduletest.Run{
-						Config: run,
-						Index:  ix,
-						Name:   run.Name,
-					})
-				}
-
-				runCount += len(runs)
-				files[name] = &moduletest.File{
-					Config: file,
-					Name:   name,
-					Runs:   runs,
-				}
+				files[name] = moduletest.NewFile(name, file)
+				runCount += len(files[name].Runs)
 			}
 			return files
 		}(),
@@ -200,6 +225,21 @@ func (c *TestCommand) Run(rawArgs []string) int {
 
 	log.Printf("[DEBUG] TestCommand: found %d files with %d run blocks", fileCount, runCount)
 
+	quarantined := make(map[string]bool)
+	for _, file := range suite.Files {
+		for _, entry := range file.Quarantine(args.Quarantine) {
+			quarantined[entry] = true
+		}
+	}
+	for _, entry := range args.Quarantine {
+		if !quarantined[entry] {
+			fileDiags = fileDiags.Append(tfdiags.Sourceless(
+				tfdiags.Warning,
+				"Unknown quarantined test",
+				fmt.Sprintf("The quarantined test, %s, does not match any test file or run block.", entry)))
+		}
+	}
+
 	diags = diags.Append(fileDiags)
 	if fileDiags.HasErrors() {
 		view.Diagnostics(nil, nil, diags)
@@ -216,6 +256,17 @@ func (c *TestCommand) Run(rawArgs []string) int {
 	// Don't use encryption during testing
 	opts.Encryption = encryption.Disabled()
 
+	var backendState *states.State
+	if args.BackendState {
+		var stateDiags tfdiags.Diagnostics
+		backendState, stateDiags = c.loadBackendState()
+		diags = diags.Append(stateDiags)
+		if stateDiags.HasErrors() {
+			view.Diagnostics(nil, nil, diags)
+			return 1
+		}
+	}
+
 	// Print out all the diagnostics we have from the setup. These will just be
 	// warnings, and we want them out of the way before we start the actual
 	// testing.
@@ -246,12 +297,15 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		CancelledCtx: cancelCtx,
 		StoppedCtx:   stopCtx,
 
-		// Just to be explicit, we'll set the following fields even though they
-		// default to these values.
-		Cancelled: false,
-		Stopped:   false,
-
-		Verbose: args.Verbose,
+		Verbose:         args.Verbose,
+		Parallel:        args.Parallel,
+		TestDirectory:   args.TestDirectory,
+		RecordProviders: args.Record,
+		ReplayProviders: args.Replay,
+		BackendState:    backendState,
+	}
+	if args.Coverage {
+		runner.Coverage = moduletest.NewCoverage(config)
 	}
 
 	view.Abstract(&suite)
@@ -272,7 +326,7 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		// Nice request to be cancelled.
 
 		view.Interrupted()
-		runner.Stopped = true
+		runner.Stopped.Store(true)
 		stop()
 
 		select {
@@ -281,7 +335,7 @@ func (c *TestCommand) Run(rawArgs []string) int {
 			// fast as possible.
 
 			view.FatalInterrupt()
-			runner.Cancelled = true
+			runner.Cancelled.Store(true)
 			cancel()
 
 			// We'll wait 5 seconds for this operation to finish now, regardless
@@ -298,13 +352,40 @@ func (c *TestCommand) Run(rawArgs []string) int {
 		// tests finished normally with no interrupts.
 	}
 
-	if runner.Cancelled {
+	if runner.Cancelled.Load() {
 		// Don't print out the conclusion if the test was cancelled.
 		return 1
 	}
 
 	view.Conclusion(&suite)
 
+	if args.JUnitXMLPath != "" {
+		if diags := writeTestJUnitReport(args.JUnitXMLPath, &suite, c.configSources()); diags.HasErrors() {
+			view.Diagnostics(nil, nil, diags)
+			return 1
+		}
+	}
+
+	if runner.Coverage != nil {
+		report := runner.Coverage.Report()
+		view.Coverage(report)
+
+		if args.CoverageReport != "" {
+			if diags := writeTestCoverageReport(args.CoverageReport, report); diags.HasErrors() {
+				view.Diagnostics(nil, nil, diags)
+				return 1
+			}
+		}
+
+		if percent := report.Percent(); percent < args.CoverageThreshold {
+			view.Diagnostics(nil, nil, tfdiags.Diagnostics{}.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Test coverage below threshold",
+				fmt.Sprintf("The tests covered %.1f%% of the module, which is below the threshold of %.1f%%.", percent, args.CoverageThreshold))))
+			return 1
+		}
+	}
+
 	if suite.Status != moduletest.Pass {
 		return 1
 	}
@@ -332,8 +413,11 @@ type TestSuiteRunner struct {
 	// be left showing `pending` as the status. We will still print out the
 	// destroy summary diagnostics that tell the user what state has been left
 	// behind and needs manual clean up.
-	Stopped   bool
-	Cancelled bool
+	//
+	// They're atomic because test files executing in parallel read them
+	// while the command sets them in response to an interrupt.
+	Stopped   atomic.Bool
+	Cancelled atomic.Bool
 
 	// StoppedCtx and CancelledCtx allow in progress OpenTofu operations to
 	// respond to external calls from the test command.
@@ -342,6 +426,30 @@ type TestSuiteRunner struct {
 
 	// Verbose tells the runner to print out plan files during each test run.
 	Verbose bool
+
+	// Parallel is the number of test files, and of run blocks marked as
+	// parallel within a file, that can execute at the same time.
+	Parallel int
+
+	// TestDirectory is the directory that test files were loaded from, so
+	// that the configuration can be loaded again for each test file when
+	// they execute in parallel.
+	TestDirectory string
+
+	// RecordProviders and ReplayProviders tell the runner to record the
+	// interactions with providers for each test file, or to replay them
+	// instead of calling the providers.
+	RecordProviders bool
+	ReplayProviders bool
+
+	// Coverage collects which parts of the module under test are exercised
+	// by the run blocks, if coverage was requested.
+	Coverage *moduletest.Coverage
+
+	// BackendState is the state loaded from the backend when the tests
+	// execute against it. Each test file starts from a copy of it instead of
+	// from an empty state, and it is never cleaned up.
+	BackendState *states.State
 }
 
 func (runner *TestSuiteRunner) Start(globals map[string]backend.UnparsedVariableValue) {
@@ -352,33 +460,94 @@ func (runner *TestSuiteRunner) Start(globals map[string]backend.UnparsedVariable
 	sort.Strings(files) // execute the files in alphabetical order
 
 	runner.Suite.Status = moduletest.Pass
+	if runner.Parallel > 1 && len(files) > 1 {
+		runner.startParallel(files)
+		return
+	}
+
 	for _, name := range files {
-		if runner.Cancelled {
+		if runner.Cancelled.Load() {
 			return
 		}
 
 		file := runner.Suite.Files[name]
+		runner.executeFile(file, runner.Config, runner.View)
+		runner.Suite.Status = runner.Suite.Status.Merge(file.Status)
+	}
+}
 
-		fileRunner := &TestFileRunner{
-			Suite: runner,
-			States: map[string]*TestFileState{
-				MainStateIdentifier: {
-					Run:   nil,
-					State: states.NewState(),
-				},
+// executeFile executes the given test file against the given configuration,
+// and then cleans up the state it created.
+func (runner *TestSuiteRunner) executeFile(file *moduletest.File, config *configs.Config, view views.Test) {
+	fileRunner := &TestFileRunner{
+		Suite:  runner,
+		Config: config,
+		Opts:   runner.Opts,
+		View:   view,
+		States: map[string]*TestFileState{
+			MainStateIdentifier: {
+				Run:   nil,
+				State: states.NewState(),
 			},
+		},
+	}
+	if runner.BackendState != nil {
+		fileRunner.States[MainStateIdentifier].State = runner.BackendState.DeepCopy()
+	}
+
+	var recording *providerRecording
+	recordingPath := providerRecordingPath(file.Name)
+	switch {
+	case runner.RecordProviders:
+		recording = new(providerRecording)
+	case runner.ReplayProviders:
+		var diags tfdiags.Diagnostics
+		recording, diags = loadProviderRecording(recordingPath)
+		if diags.HasErrors() {
+			file.Status = moduletest.Error
+			file.Diagnostics = file.Diagnostics.Append(diags)
+			view.File(file)
+			return
 		}
+	}
+	if recording != nil {
+		fileRunner.Opts = recordingContextOpts(runner.Opts, recording, runner.ReplayProviders)
+	}
 
-		fileRunner.ExecuteTestFile(file)
-		fileRunner.Cleanup(file)
-		runner.Suite.Status = runner.Suite.Status.Merge(file.Status)
+	fileRunner.ExecuteTestFile(file)
+	fileRunner.Cleanup(file)
+
+	if runner.RecordProviders && !runner.Cancelled.Load() {
+		// The recording includes the interactions from the cleanup, so that
+		// it can be replayed as well.
+		if diags := recording.save(recordingPath); diags.HasErrors() {
+			file.Status = moduletest.Error
+			view.Diagnostics(nil, file, diags)
+		}
 	}
 }
 
 type TestFileRunner struct {
 	Suite *TestSuiteRunner
 
+	// Config is the configuration under test. When test files execute in
+	// parallel each file has its own copy, since executing a run block
+	// temporarily modifies the configuration.
+	Config *configs.Config
+
+	// Opts are the options for the OpenTofu contexts that execute the run
+	// blocks, whose providers may be wrapped to record or replay their
+	// interactions.
+	Opts *tofu.ContextOpts
+
+	// View is the view that the results of the file are reported to.
+	View views.Test
+
 	States map[string]*TestFileState
+
+	// statesLock protects States and the status of the file while run
+	// blocks marked as parallel are executing.
+	statesLock sync.Mutex
 }
 
 type TestFileState struct {
@@ -389,91 +558,234 @@ type TestFileState struct {
 func (runner *TestFileRunner) ExecuteTestFile(file *moduletest.File) {
 	log.Printf("[TRACE] TestFileRunner: executing test file %s", file.Name)
 
+	start := time.Now()
 	file.Status = file.Status.Merge(moduletest.Pass)
-	for _, run := range file.Runs {
-		if runner.Suite.Cancelled {
-			// This means a hard stop has been requested, in this case we don't
-			// even stop to mark future tests as having been skipped. They'll
-			// just show up as pending in the printed summary.
-			return
+
+	if file.Setup != nil {
+		runner.executeRun(file.Setup, file)
+		if testRunFailed(file.Setup) {
+			// None of the run blocks can execute without the setup block, so
+			// they will all be skipped.
+			file.Status = moduletest.Error
 		}
+	}
 
-		if runner.Suite.Stopped {
-			// Then the test was requested to be stopped, so we just mark each
-			// following test as skipped and move on.
-			run.Status = moduletest.Skip
+	for ix := 0; ix < len(file.Runs); ix++ {
+		if runner.Suite.Parallel > 1 && file.Runs[ix].Config.Parallel {
+			// Adjacent run blocks marked as parallel execute together.
+			end := ix
+			for end < len(file.Runs) && file.Runs[end].Config.Parallel {
+				end++
+			}
+			runner.executeParallelRuns(file.Runs[ix:end], file)
+			ix = end - 1
 			continue
 		}
 
-		if file.Status == moduletest.Error {
-			// If the overall test file has errored, we don't keep trying to
-			// execute tests. Instead, we mark all remaining run blocks as
-			// skipped.
-			run.Status = moduletest.Skip
-			continue
+		runner.executeRun(file.Runs[ix], file)
+	}
+
+	if file.Teardown != nil && !runner.Suite.Cancelled.Load() {
+		if runner.Suite.Stopped.Load() {
+			file.Teardown.Status = moduletest.Skip
+		} else {
+			// The teardown block executes even if the run blocks failed, so
+			// we skip the checks in executeRun.
+			runner.executeRunState(file.Teardown, file)
 		}
+	}
 
-		key := MainStateIdentifier
-		config := runner.Suite.Config
-		if run.Config.ConfigUnderTest != nil {
-			config = run.Config.ConfigUnderTest
-			// Then we need to load an alternate state and not the main one.
+	file.Duration = time.Since(start)
+	runner.View.File(file)
+	// The setup and teardown blocks are only reported if they failed, since
+	// they don't contain any assertions.
+	if testRunFailed(file.Setup) {
+		runner.View.Run(file.Setup, file)
+	}
+	for _, run := range file.Runs {
+		runner.View.Run(run, file)
+	}
+	if testRunFailed(file.Teardown) {
+		runner.View.Run(file.Teardown, file)
+	}
+}
 
-			key = run.Config.Module.Source.String()
-			if key == MainStateIdentifier {
-				// This is bad. It means somehow the module we're loading has
-				// the same key as main state and we're about to corrupt things.
-
-				run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
-					Severity: hcl.DiagError,
-					Summary:  "Invalid module source",
-					Detail:   fmt.Sprintf("The source for the selected module evaluated to %s which should not be possible. This is a bug in OpenTofu - please report it!", key),
-					Subject:  run.Config.Module.DeclRange.Ptr(),
-				})
-
-				run.Status = moduletest.Error
-				file.Status = moduletest.Error
-				continue // Abort!
-			}
+// testRunFailed returns true if the given run exists and failed or errored.
+func testRunFailed(run *moduletest.Run) bool {
+	return run != nil && (run.Status == moduletest.Fail || run.Status == moduletest.Error)
+}
 
-			if _, exists := runner.States[key]; !exists {
-				runner.States[key] = &TestFileState{
-					Run:   nil,
-					State: states.NewState(),
-				}
-			}
+// executeRun executes a single run block against the state it uses, and
+// records the updated state and status.
+func (runner *TestFileRunner) executeRun(run *moduletest.Run, file *moduletest.File) {
+	if runner.Suite.Cancelled.Load() {
+		// This means a hard stop has been requested, in this case we don't
+		// even stop to mark future tests as having been skipped. They'll
+		// just show up as pending in the printed summary.
+		return
+	}
+
+	if runner.Suite.Stopped.Load() {
+		// Then the test was requested to be stopped, so we just mark each
+		// following test as skipped and move on.
+		run.Status = moduletest.Skip
+		return
+	}
+
+	runner.statesLock.Lock()
+	fileStatus := file.Status
+	runner.statesLock.Unlock()
+
+	if fileStatus == moduletest.Error {
+		// If the overall test file has errored, we don't keep trying to
+		// execute tests. Instead, we mark all remaining run blocks as
+		// skipped.
+		run.Status = moduletest.Skip
+		return
+	}
+
+	runner.executeRunState(run, file)
+}
+
+// executeRunState executes a single run block against the state it uses,
+// without checking whether the test file has already failed or been stopped.
+func (runner *TestFileRunner) executeRunState(run *moduletest.Run, file *moduletest.File) {
+	start := time.Now()
+	defer func() {
+		run.Duration = time.Since(start)
+	}()
+
+	key := testRunStateKey(run)
+	config := runner.Config
+	if run.Config.ConfigUnderTest != nil {
+		config = run.Config.ConfigUnderTest
+		// Then we need to load an alternate state and not the main one.
+
+		if key == MainStateIdentifier {
+			// This is bad. It means somehow the module we're loading has
+			// the same key as main state and we're about to corrupt things.
+
+			run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid module source",
+				Detail:   fmt.Sprintf("The source for the selected module evaluated to %s which should not be possible. This is a bug in OpenTofu - please report it!", key),
+				Subject:  run.Config.Module.DeclRange.Ptr(),
+			})
+
+			run.Status = moduletest.Error
+			runner.statesLock.Lock()
+			file.Status = moduletest.Error
+			runner.statesLock.Unlock()
+			return // Abort!
 		}
+	}
+
+	runner.statesLock.Lock()
+	if _, exists := runner.States[key]; !exists {
+		runner.States[key] = &TestFileState{
+			Run:   nil,
+			State: states.NewState(),
+		}
+	}
+	runner.statesLock.Unlock()
+
+	delay := run.Config.RetryDelay
+	for {
+		run.Attempts++
+
+		runner.statesLock.Lock()
+		current := runner.States[key].State
+		runner.statesLock.Unlock()
+
+		state, updatedState := runner.ExecuteTestRun(run, file, current, config)
 
-		state, updatedState := runner.ExecuteTestRun(run, file, runner.States[key].State, config)
 		if updatedState {
 			// Only update the most recent run and state if the state was
 			// actually updated by this change. We want to use the run that
 			// most recently updated the tracked state as the cleanup
-			// configuration.
+			// configuration. A failed attempt can still have updated the
+			// state, in which case the next attempt starts from it.
+			runner.statesLock.Lock()
 			runner.States[key].State = state
 			runner.States[key].Run = run
+			runner.statesLock.Unlock()
+		}
+
+		if !testRunFailed(run) || run.Attempts > run.Config.Retries || !runner.waitForRetry(run, delay) {
+			break
 		}
+		delay *= 2
 
-		file.Status = file.Status.Merge(run.Status)
+		// Only the result of the final attempt is reported.
+		run.Status = moduletest.Pending
+		run.Verbose = nil
+		run.Diagnostics = nil
 	}
 
-	runner.Suite.View.File(file)
-	for _, run := range file.Runs {
-		runner.Suite.View.Run(run, file)
+	runner.statesLock.Lock()
+	defer runner.statesLock.Unlock()
+	if run.QuarantinedFailure() {
+		// The failure of a quarantined run block is reported, but doesn't
+		// fail the test file.
+		file.Status = file.Status.Merge(moduletest.Pass)
+		return
+	}
+	file.Status = file.Status.Merge(run.Status)
+}
+
+// waitForRetry waits for the given delay before the given run block is
+// retried, and returns false if the tests were stopped in the meantime.
+func (runner *TestFileRunner) waitForRetry(run *moduletest.Run, delay time.Duration) bool {
+	log.Printf("[DEBUG] TestFileRunner: retrying run block %q in %s after %d failed attempts", run.Name, delay, run.Attempts)
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return !runner.Suite.Stopped.Load() && !runner.Suite.Cancelled.Load()
+	case <-runner.Suite.StoppedCtx.Done():
+		return false
+	}
+}
+
+// testRunStateKey returns the key of the state that the given run block
+// executes against, which is either the main state or the state of the
+// alternate module it loads.
+func testRunStateKey(run *moduletest.Run) string {
+	if run.Config.ConfigUnderTest == nil {
+		return MainStateIdentifier
+	}
+	return run.Config.Module.Source.String()
+}
+
+// statesSnapshot returns a copy of the states tracked by the runner, which is
+// safe to read while other run blocks are executing.
+func (runner *TestFileRunner) statesSnapshot() map[string]*TestFileState {
+	runner.statesLock.Lock()
+	defer runner.statesLock.Unlock()
+
+	ret := make(map[string]*TestFileState, len(runner.States))
+	for key, state := range runner.States {
+		ret[key] = &TestFileState{
+			Run:   state.Run,
+			State: state.State,
+		}
 	}
+	return ret
 }
 
 func (runner *TestFileRunner) ExecuteTestRun(run *moduletest.Run, file *moduletest.File, state *states.State, config *configs.Config) (*states.State, bool) {
 	log.Printf("[TRACE] TestFileRunner: executing run block %s/%s", file.Name, run.Name)
 
-	if runner.Suite.Cancelled {
+	if runner.Suite.Cancelled.Load() {
 		// Don't do anything, just give up and return immediately.
 		// The surrounding functions should stop this even being called, but in
 		// case of race conditions or something we can still verify this.
 		return state, false
 	}
 
-	if runner.Suite.Stopped {
+	if runner.Suite.Stopped.Load() {
 		// Basically the same as above, except we'll be a bit nicer.
 		run.Status = moduletest.Skip
 		return state, false
@@ -491,6 +803,14 @@ func (runner *TestFileRunner) ExecuteTestRun(run *moduletest.Run, file *modulete
 		return state, false
 	}
 
+	if runner.Suite.BackendState != nil {
+		run.Diagnostics = run.Diagnostics.Append(validateBackendStateRun(run))
+		if run.Diagnostics.HasErrors() {
+			run.Status = moduletest.Error
+			return state, false
+		}
+	}
+
 	resetConfig, configDiags := config.TransformForTest(run.Config, file.Config)
 	defer resetConfig()
 
@@ -551,6 +871,8 @@ func (runner *TestFileRunner) ExecuteTestRun(run *moduletest.Run, file *modulete
 			run.Diagnostics = run.Diagnostics.Append(diags)
 		}
 
+		runner.recordCoverage(run, file, plan.PlannedState)
+		checkNoChanges(run, plan)
 		planCtx.TestContext(config, plan.PlannedState, plan, variables).EvaluateAgainstPlan(run)
 		return state, false
 	}
@@ -599,6 +921,22 @@ func (runner *TestFileRunner) ExecuteTestRun(run *moduletest.Run, file *modulete
 		return updated, true
 	}
 
+	// Actions are invoked against the applied state, so that the assertions
+	// can check their effects.
+	for _, inv := range run.Config.Invocations {
+		log.Printf("[TRACE] TestFileRunner: invoking %s for %s/%s", inv.Type, file.Name, run.Name)
+		_, invokeDiags := applyCtx.Invoke(config, updated, &tofu.InvokeOpts{
+			SetVariables: variables,
+			ActionType:   inv.Type,
+			Config:       inv.Config,
+		})
+		run.Diagnostics = run.Diagnostics.Append(invokeDiags)
+		if invokeDiags.HasErrors() {
+			run.Status = moduletest.Error
+			return updated, true
+		}
+	}
+
 	if runner.Suite.Verbose {
 		schemas, diags := planCtx.Schemas(config, plan.PlannedState)
 
@@ -624,6 +962,7 @@ func (runner *TestFileRunner) ExecuteTestRun(run *moduletest.Run, file *modulete
 		run.Diagnostics = run.Diagnostics.Append(diags)
 	}
 
+	runner.recordCoverage(run, file, updated)
 	applyCtx.TestContext(config, updated, plan, variables).EvaluateAgainstState(run)
 	return updated, true
 }
@@ -633,7 +972,7 @@ func (runner *TestFileRunner) validate(config *configs.Config, run *moduletest.R
 
 	var diags tfdiags.Diagnostics
 
-	tfCtx, ctxDiags := tofu.NewContext(runner.Suite.Opts)
+	tfCtx, ctxDiags := tofu.NewContext(runner.Opts)
 	diags = diags.Append(ctxDiags)
 	if ctxDiags.HasErrors() {
 		return diags
@@ -674,7 +1013,7 @@ func (runner *TestFileRunner) destroy(config *configs.Config, state *states.Stat
 
 	var diags tfdiags.Diagnostics
 
-	evalCtx, ctxDiags := getEvalContextForTest(runner.States, config, runner.Suite.GlobalVariables)
+	evalCtx, ctxDiags := getEvalContextForTest(runner.statesSnapshot(), config, runner.Suite.GlobalVariables)
 	diags = diags.Append(ctxDiags)
 
 	variables, variableDiags := buildInputVariablesForTest(run, file, config, runner.Suite.GlobalVariables, evalCtx)
@@ -689,7 +1028,7 @@ func (runner *TestFileRunner) destroy(config *configs.Config, state *states.Stat
 		SetVariables: variables,
 	}
 
-	tfCtx, ctxDiags := tofu.NewContext(runner.Suite.Opts)
+	tfCtx, ctxDiags := tofu.NewContext(runner.Opts)
 	diags = diags.Append(ctxDiags)
 	if ctxDiags.HasErrors() {
 		return state, diags
@@ -740,7 +1079,7 @@ func (runner *TestFileRunner) plan(config *configs.Config, state *states.State,
 	references, referenceDiags := run.GetReferences()
 	diags = diags.Append(referenceDiags)
 
-	evalCtx, ctxDiags := getEvalContextForTest(runner.States, config, runner.Suite.GlobalVariables)
+	evalCtx, ctxDiags := getEvalContextForTest(runner.statesSnapshot(), config, runner.Suite.GlobalVariables)
 	diags = diags.Append(ctxDiags)
 
 	variables, variableDiags := buildInputVariablesForTest(run, file, config, runner.Suite.GlobalVariables, evalCtx)
@@ -766,7 +1105,7 @@ func (runner *TestFileRunner) plan(config *configs.Config, state *states.State,
 		ExternalReferences: references,
 	}
 
-	tfCtx, ctxDiags := tofu.NewContext(runner.Suite.Opts)
+	tfCtx, ctxDiags := tofu.NewContext(runner.Opts)
 	diags = diags.Append(ctxDiags)
 	if ctxDiags.HasErrors() {
 		return nil, nil, diags
@@ -821,7 +1160,7 @@ func (runner *TestFileRunner) apply(plan *plans.Plan, state *states.State, confi
 		created = append(created, change)
 	}
 
-	tfCtx, ctxDiags := tofu.NewContext(runner.Suite.Opts)
+	tfCtx, ctxDiags := tofu.NewContext(runner.Opts)
 	diags = diags.Append(ctxDiags)
 	if ctxDiags.HasErrors() {
 		return nil, state, diags
@@ -871,15 +1210,16 @@ func (runner *TestFileRunner) wait(ctx *tofu.Context, runningCtx context.Context
 	handleCancelled := func() {
 		log.Printf("[DEBUG] TestFileRunner: test execution cancelled during %s", identifier)
 
+		snapshot := runner.statesSnapshot()
 		states := make(map[*moduletest.Run]*states.State)
-		states[nil] = runner.States[MainStateIdentifier].State
-		for key, module := range runner.States {
+		states[nil] = snapshot[MainStateIdentifier].State
+		for key, module := range snapshot {
 			if key == MainStateIdentifier {
 				continue
 			}
 			states[module.Run] = module.State
 		}
-		runner.Suite.View.FatalInterruptSummary(run, file, states, created)
+		runner.View.FatalInterruptSummary(run, file, states, created)
 
 		cancelled = true
 		go ctx.Stop()
@@ -923,7 +1263,7 @@ func (runner *TestFileRunner) wait(ctx *tofu.Context, runningCtx context.Context
 func (runner *TestFileRunner) Cleanup(file *moduletest.File) {
 	log.Printf("[TRACE] TestStateManager: cleaning up state for %s", file.Name)
 
-	if runner.Suite.Cancelled {
+	if runner.Suite.Cancelled.Load() {
 		// Don't try and clean anything up if the execution has been cancelled.
 		log.Printf("[DEBUG] TestStateManager: skipping state cleanup for %s due to cancellation", file.Name)
 		return
@@ -931,6 +1271,12 @@ func (runner *TestFileRunner) Cleanup(file *moduletest.File) {
 
 	var states []*TestFileState
 	for key, state := range runner.States {
+		if key == MainStateIdentifier && runner.Suite.BackendState != nil {
+			// The main state was loaded from the backend, and only plans
+			// were executed against it, so there is nothing to destroy.
+			continue
+		}
+
 		if state.Run == nil {
 			if state.State.Empty() {
 				// We can see a run block being empty when the state is empty if
@@ -952,7 +1298,7 @@ func (runner *TestFileRunner) Cleanup(file *moduletest.File) {
 
 			var diags tfdiags.Diagnostics
 			diags = diags.Append(tfdiags.Sourceless(tfdiags.Error, "Inconsistent state", fmt.Sprintf("Found inconsistent state while cleaning up %s. This is a bug in OpenTofu - please report it", file.Name)))
-			runner.Suite.View.DestroySummary(diags, nil, file, state.State)
+			runner.View.DestroySummary(diags, nil, file, state.State)
 			continue
 		}
 
@@ -969,7 +1315,7 @@ func (runner *TestFileRunner) Cleanup(file *moduletest.File) {
 	for _, state := range states {
 		log.Printf("[DEBUG] TestStateManager: cleaning up state for %s/%s", file.Name, state.Run.Name)
 
-		if runner.Suite.Cancelled {
+		if runner.Suite.Cancelled.Load() {
 			// In case the cancellation came while a previous state was being
 			// destroyed.
 			log.Printf("[DEBUG] TestStateManager: skipping state cleanup for %s/%s due to cancellation", file.Name, state.Run.Name)
@@ -981,7 +1327,7 @@ func (runner *TestFileRunner) Cleanup(file *moduletest.File) {
 
 		isMainState := state.Run.Config.Module == nil
 		if isMainState {
-			runConfig = runner.Suite.Config
+			runConfig = runner.Config
 		} else {
 			runConfig = state.Run.Config.ConfigUnderTest
 		}
@@ -995,10 +1341,10 @@ func (runner *TestFileRunner) Cleanup(file *moduletest.File) {
 			updated, destroyDiags = runner.destroy(runConfig, state.State, state.Run, file)
 			diags = diags.Append(destroyDiags)
 		}
-		runner.Suite.View.DestroySummary(diags, state.Run, file, updated)
+		runner.View.DestroySummary(diags, state.Run, file, updated)
 
 		if updated.HasManagedResourceInstanceObjects() {
-			views.SaveErroredTestStateFile(updated, state.Run, file, runner.Suite.View)
+			views.SaveErroredTestStateFile(updated, state.Run, file, runner.View)
 		}
 		reset()
 	}
@@ -1164,7 +1510,7 @@ func parseAndApplyDefaultValues(unparsedVariables map[string]backend.UnparsedVar
 // the config which must be called so the config can be reused going forward.
 func (runner *TestFileRunner) prepareInputVariablesForAssertions(config *configs.Config, run *moduletest.Run, file *moduletest.File, globals map[string]backend.UnparsedVariableValue) (tofu.InputValues, func(), tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
-	ctx, ctxDiags := getEvalContextForTest(runner.States, config, globals)
+	ctx, ctxDiags := getEvalContextForTest(runner.statesSnapshot(), config, globals)
 	diags = diags.Append(ctxDiags)
 
 	variables := make(map[string]backend.UnparsedVariableValue)
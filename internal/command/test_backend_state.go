@@ -0,0 +1,127 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// loadBackendState returns the latest state snapshot of the current
+// workspace in the configured backend, for "tofu test -backend-state". The
+// state is only ever read, the test command never writes it back.
+func (c *TestCommand) loadBackendState() (*states.State, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	enc, encDiags := c.Encryption()
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, diags
+	}
+
+	b, backendDiags := c.Backend(nil, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		return nil, diags
+	}
+	c.ignoreRemoteVersionConflict(b)
+
+	workspace, err := c.Workspace()
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("error selecting workspace: %w", err))
+		return nil, diags
+	}
+
+	stateFile, err := getStateFromBackend(b, workspace)
+	if err != nil {
+		diags = diags.Append(err)
+		return nil, diags
+	}
+
+	if stateFile == nil || stateFile.State == nil {
+		return states.NewState(), diags
+	}
+	return stateFile.State, diags
+}
+
+// validateBackendStateRun checks that the given run block can execute against
+// the state loaded from the backend, which is only the case if it doesn't
+// apply the module under test. Run blocks that execute an alternate module
+// have their own state, so they can still apply it.
+func validateBackendStateRun(run *moduletest.Run) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	if run.Config.ConfigUnderTest == nil && run.Config.Command == configs.ApplyTestCommand {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Apply not allowed against backend state",
+			Detail:   fmt.Sprintf("The run block %q would apply the module under test to the state loaded from the backend with -backend-state, which is read-only. Set command = plan for this run block.", run.Name),
+			Subject:  run.Config.DeclRange.Ptr(),
+		})
+	}
+	return diags
+}
+
+// checkNoChanges fails the given run block if it set expect_no_changes and the
+// given plan changes any resources or output values.
+func checkNoChanges(run *moduletest.Run, plan *plans.Plan) {
+	if !run.Config.ExpectNoChanges {
+		return
+	}
+
+	var changed []string
+	for _, rc := range plan.Changes.Resources {
+		// Data sources read during the plan aren't changes to the
+		// infrastructure.
+		if rc.Action == plans.NoOp || rc.Action == plans.Read {
+			continue
+		}
+		changed = append(changed, fmt.Sprintf("  - %s will be %s", rc.Addr, changeActionDescription(rc.Action)))
+	}
+	for _, oc := range plan.Changes.Outputs {
+		if oc.Action == plans.NoOp || !oc.Addr.Module.IsRoot() {
+			continue
+		}
+		changed = append(changed, fmt.Sprintf("  - %s will be %s", oc.Addr, changeActionDescription(oc.Action)))
+	}
+	if len(changed) == 0 {
+		return
+	}
+	sort.Strings(changed)
+
+	run.Status = run.Status.Merge(moduletest.Fail)
+	run.Diagnostics = run.Diagnostics.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Unexpected changes",
+		Detail:   fmt.Sprintf("The run block %q expects no changes, but the plan includes the following:\n%s", run.Name, strings.Join(changed, "\n")),
+		Subject:  run.Config.DeclRange.Ptr(),
+	})
+}
+
+func changeActionDescription(action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "created"
+	case plans.Delete:
+		return "destroyed"
+	case plans.Update:
+		return "updated in-place"
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		return "replaced"
+	case plans.Forget:
+		return "removed from the state"
+	default:
+		return strings.ToLower(action.String())
+	}
+}
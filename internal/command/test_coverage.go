@@ -0,0 +1,160 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+
+	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// testCoverageFormatVersion is the version of the JSON coverage report
+// written by "tofu test -coverage-report".
+const testCoverageFormatVersion = "1.0"
+
+// recordCoverage marks the parts of the module under test that are in the
+// given state, and the variables that were set for the given run block, as
+// covered. Run blocks that execute an alternate module don't contribute to
+// the coverage.
+func (runner *TestFileRunner) recordCoverage(run *moduletest.Run, file *moduletest.File, state *states.State) {
+	coverage := runner.Suite.Coverage
+	if coverage == nil || run.Config.ConfigUnderTest != nil {
+		return
+	}
+
+	coverage.RecordState(state)
+
+	var names []string
+	for name := range run.Config.Variables {
+		names = append(names, name)
+	}
+	for name := range file.Config.Variables {
+		names = append(names, name)
+	}
+	for name := range runner.Suite.GlobalVariables {
+		names = append(names, name)
+	}
+	coverage.RecordVariables(names)
+}
+
+// writeTestCoverageReport writes the given coverage report to the given path,
+// as JSON or HTML depending on its extension.
+func writeTestCoverageReport(path string, report *moduletest.CoverageReport) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	var buf bytes.Buffer
+	switch filepath.Ext(path) {
+	case ".html":
+		if err := testCoverageHTMLTemplate.Execute(&buf, newTestCoverageHTML(report)); err != nil {
+			// Should never happen, since we control the template and data.
+			panic(err)
+		}
+	default:
+		src, err := json.MarshalIndent(struct {
+			FormatVersion string `json:"format_version"`
+			viewsjson.TestCoverage
+		}{
+			FormatVersion: testCoverageFormatVersion,
+			TestCoverage:  viewsjson.ToTestCoverage(report),
+		}, "", "  ")
+		if err != nil {
+			panic(err)
+		}
+		buf.Write(src)
+		buf.WriteByte('\n')
+	}
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write coverage report",
+			fmt.Sprintf("Could not write the coverage report to %s: %s.", path, err)))
+	}
+	return diags
+}
+
+type testCoverageHTML struct {
+	Percent string
+	Covered int
+	Total   int
+	Kinds   []testCoverageHTMLKind
+}
+
+type testCoverageHTMLKind struct {
+	Name    string
+	Covered int
+	Total   int
+	Items   []*moduletest.CoverageItem
+}
+
+func newTestCoverageHTML(report *moduletest.CoverageReport) *testCoverageHTML {
+	ret := &testCoverageHTML{
+		Percent: fmt.Sprintf("%.1f%%", report.Percent()),
+	}
+	ret.Covered, ret.Total = report.Count("")
+
+	kinds := []struct {
+		kind moduletest.CoverageKind
+		name string
+	}{
+		{moduletest.CoverageResource, "Resources"},
+		{moduletest.CoverageBranch, "Branches"},
+		{moduletest.CoverageOutput, "Outputs"},
+		{moduletest.CoverageVariable, "Variables"},
+	}
+	for _, k := range kinds {
+		kind := testCoverageHTMLKind{Name: k.name}
+		kind.Covered, kind.Total = report.Count(k.kind)
+		if kind.Total == 0 {
+			continue
+		}
+		for _, item := range report.Items {
+			if item.Kind == k.kind {
+				kind.Items = append(kind.Items, item)
+			}
+		}
+		ret.Kinds = append(ret.Kinds, kind)
+	}
+	return ret
+}
+
+var testCoverageHTMLTemplate = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>OpenTofu test coverage</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.2em; margin-top: 2em; border-bottom: 1px solid #d0d7de; padding-bottom: 0.3em; }
+table { border-collapse: collapse; }
+th, td { text-align: left; padding: 0.3em 1em 0.3em 0; vertical-align: top; }
+code { font-family: ui-monospace, Menlo, Consolas, monospace; }
+.covered { color: #1a7f37; font-weight: bold; }
+.uncovered { color: #cf222e; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>OpenTofu test coverage</h1>
+<p>{{.Percent}} covered ({{.Covered}} of {{.Total}}).</p>
+{{range .Kinds}}
+<h2>{{.Name}} ({{.Covered}} of {{.Total}})</h2>
+<table>
+<tr><th>Address</th><th>Branch</th><th>Location</th><th>Status</th></tr>
+{{range .Items}}<tr><td><code>{{.Address}}</code></td><td>{{.Branch}}</td><td>{{.DeclRange.Filename}}:{{.DeclRange.Start.Line}}</td><td>{{if .Covered}}<span class="covered">covered</span>{{else}}<span class="uncovered">not covered</span>{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
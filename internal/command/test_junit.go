@@ -0,0 +1,216 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// The JUnit XML format has no formal specification, so this follows the
+// subset that CI systems commonly understand: a testsuite for each test file,
+// and a testcase for each run block.
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Time     string            `xml:"time,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	TestCases []*junitTestCase `xml:"testcase"`
+	SystemErr string           `xml:"system-err,omitempty"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// writeTestJUnitReport writes the results of the given test suite to the
+// given path as JUnit XML.
+func writeTestJUnitReport(path string, suite *moduletest.Suite, sources map[string][]byte) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	src, err := xml.MarshalIndent(newJUnitTestSuites(suite, sources), "", "  ")
+	if err != nil {
+		// Should never happen, since we control the input here.
+		panic(err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.Write(src)
+	buf.WriteByte('\n')
+
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write JUnit XML report",
+			fmt.Sprintf("Could not write the JUnit XML report to %s: %s.", path, err)))
+	}
+	return diags
+}
+
+func newJUnitTestSuites(suite *moduletest.Suite, sources map[string][]byte) *junitTestSuites {
+	ret := &junitTestSuites{Name: "OpenTofu tests"}
+
+	var names []string
+	for name := range suite.Files {
+		names = append(names, name)
+	}
+	sort.Strings(names) // the files execute in alphabetical order
+
+	var total float64
+	for _, name := range names {
+		file := suite.Files[name]
+		ts := &junitTestSuite{
+			Name:      file.Name,
+			Time:      junitTime(file.Duration.Seconds()),
+			SystemErr: junitDiagnostics(file.Diagnostics, sources),
+		}
+		total += file.Duration.Seconds()
+
+		// The setup and teardown blocks are only reported if they failed,
+		// like in the other output formats.
+		for _, run := range file.AllRuns() {
+			if (run == file.Setup || run == file.Teardown) && !testRunFailed(run) {
+				continue
+			}
+
+			tc := newJUnitTestCase(run, file, sources)
+			ts.Tests++
+			switch {
+			case tc.Failure != nil:
+				ts.Failures++
+			case tc.Error != nil:
+				ts.Errors++
+			case tc.Skipped != nil:
+				ts.Skipped++
+			}
+			ts.TestCases = append(ts.TestCases, tc)
+		}
+
+		ret.Tests += ts.Tests
+		ret.Failures += ts.Failures
+		ret.Errors += ts.Errors
+		ret.Skipped += ts.Skipped
+		ret.Suites = append(ret.Suites, ts)
+	}
+	ret.Time = junitTime(total)
+	return ret
+}
+
+func newJUnitTestCase(run *moduletest.Run, file *moduletest.File, sources map[string][]byte) *junitTestCase {
+	tc := &junitTestCase{
+		Name:      run.Name,
+		Classname: file.Name,
+		Time:      junitTime(run.Duration.Seconds()),
+	}
+
+	var errs, warnings tfdiags.Diagnostics
+	for _, diag := range run.Diagnostics {
+		if diag.Severity() == tfdiags.Error {
+			errs = errs.Append(diag)
+		} else {
+			warnings = warnings.Append(diag)
+		}
+	}
+
+	switch {
+	case run.QuarantinedFailure():
+		// Quarantined failures don't fail the test suite, so they are
+		// reported as skipped to keep CI systems from failing the build.
+		tc.Skipped = &junitSkipped{
+			Message: "Quarantined: " + junitMessage(errs, "Test run failed"),
+		}
+	case run.Status == moduletest.Pass:
+	case run.Status == moduletest.Fail:
+		tc.Failure = &junitProblem{
+			Message: junitMessage(errs, "Test assertion failed"),
+			Type:    "assertion",
+			Body:    junitDiagnostics(errs, sources),
+		}
+	case run.Status == moduletest.Error:
+		tc.Error = &junitProblem{
+			Message: junitMessage(errs, "Test run errored"),
+			Type:    "error",
+			Body:    junitDiagnostics(errs, sources),
+		}
+	default:
+		// Runs that were never executed, because an earlier run errored or
+		// the tests were interrupted, are skipped.
+		tc.Skipped = &junitSkipped{}
+		if file.Status == moduletest.Error {
+			tc.Skipped.Message = "Skipped because an earlier run block errored"
+		}
+	}
+	tc.SystemErr = junitDiagnostics(warnings, sources)
+	return tc
+}
+
+// junitMessage summarises the given error diagnostics in a single line, for
+// the message attribute of a failure or error.
+func junitMessage(diags tfdiags.Diagnostics, fallback string) string {
+	if len(diags) == 0 {
+		return fallback
+	}
+	desc := diags[0].Description()
+	message := desc.Summary
+	if desc.Detail != "" {
+		message = fmt.Sprintf("%s: %s", desc.Summary, strings.Join(strings.Fields(desc.Detail), " "))
+	}
+	if len(diags) > 1 {
+		message = fmt.Sprintf("%s (and %d more)", message, len(diags)-1)
+	}
+	return message
+}
+
+func junitDiagnostics(diags tfdiags.Diagnostics, sources map[string][]byte) string {
+	var buf strings.Builder
+	for _, diag := range diags {
+		buf.WriteString(format.DiagnosticPlain(diag, sources, 78))
+	}
+	return buf.String()
+}
+
+func junitTime(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
@@ -0,0 +1,291 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/copy"
+	"github.com/opentofu/opentofu/internal/logging"
+	"github.com/opentofu/opentofu/internal/moduletest"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// startParallel executes the given test files concurrently, with at most
+// runner.Parallel files executing at once.
+//
+// Each file executes against its own copy of the configuration and its own
+// in-memory states. The local modules of each copy are also read from a
+// separate temporary copy of the configuration directory, so path.module and
+// path.root differ between files and a file that writes to them doesn't affect
+// the others. path.cwd is still shared by all the files. The output for each file is held back until the file has
+// finished, including its cleanup, and is then printed in the same order as
+// the files would have executed sequentially.
+func (runner *TestSuiteRunner) startParallel(files []string) {
+	// We load all the copies of the configuration up front, since the
+	// configuration loader isn't safe to use concurrently.
+	fileConfigs := make(map[string]*configs.Config, len(files))
+	var workDirs []string
+	defer func() {
+		for _, dir := range workDirs {
+			if err := os.RemoveAll(dir); err != nil {
+				log.Printf("[WARN] TestSuiteRunner: failed to remove %s: %s", dir, err)
+			}
+		}
+	}()
+	for _, name := range files {
+		file := runner.Suite.Files[name]
+
+		config, diags := runner.command.loadConfigWithTests(".", runner.TestDirectory)
+		if diags.HasErrors() {
+			file.Status = moduletest.Error
+			runner.View.Diagnostics(nil, file, diags)
+			continue
+		}
+
+		// CopyDir needs an absolute source directory to calculate the
+		// paths of the copies correctly.
+		workDir, err := os.MkdirTemp("", "tofu-test")
+		if err == nil {
+			workDirs = append(workDirs, workDir)
+			var src string
+			if src, err = filepath.Abs("."); err == nil {
+				err = copy.CopyDir(workDir, src)
+			}
+		}
+		if err != nil {
+			file.Status = moduletest.Error
+			runner.View.Diagnostics(nil, file, tfdiags.Diagnostics{}.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to copy configuration",
+				fmt.Sprintf("OpenTofu could not copy the configuration to a temporary directory to execute %s in parallel with the other test files: %s.", name, err),
+			)))
+			continue
+		}
+		runner.relocateConfig(config, name, workDir)
+
+		// Point the file and its run blocks at the new copy of the
+		// configuration, so that nothing is shared with the other files.
+		file.Config = config.Module.Tests[name]
+		for _, run := range file.Runs {
+			run.Config = file.Config.Runs[run.Index]
+		}
+		if file.Setup != nil {
+			file.Setup.Config = file.Config.Setup
+		}
+		if file.Teardown != nil {
+			file.Teardown.Config = file.Config.Teardown
+		}
+		fileConfigs[name] = config
+	}
+
+	var viewLock sync.Mutex
+	sem := make(chan struct{}, runner.Parallel)
+	done := make(map[string]chan struct{}, len(files))
+	buffers := make(map[string]*testViewBuffer, len(files))
+
+	panicHandler := logging.PanicHandlerWithTraceFn()
+	for _, name := range files {
+		config, ok := fileConfigs[name]
+		if !ok {
+			continue
+		}
+		file := runner.Suite.Files[name]
+
+		view := &testViewBuffer{view: runner.View, lock: &viewLock}
+		buffers[name] = view
+		done[name] = make(chan struct{})
+
+		go func(finished chan struct{}) {
+			defer panicHandler()
+			defer close(finished)
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if runner.Cancelled.Load() {
+				return
+			}
+
+			log.Printf("[DEBUG] TestSuiteRunner: executing test file %s in parallel", file.Name)
+			runner.executeFile(file, config, view)
+		}(done[name])
+	}
+
+	for _, name := range files {
+		finished, ok := done[name]
+		if !ok {
+			// The configuration couldn't be loaded for this file.
+			runner.Suite.Status = runner.Suite.Status.Merge(moduletest.Error)
+			continue
+		}
+		<-finished
+		buffers[name].Flush()
+
+		if runner.Cancelled.Load() {
+			// The other files won't have been cleaned up, and their
+			// remaining output isn't wanted.
+			return
+		}
+		runner.Suite.Status = runner.Suite.Status.Merge(runner.Suite.Files[name].Status)
+	}
+}
+
+// relocateConfig points the local modules of the given configuration, and of
+// the alternate configurations used by the run blocks in the named test file,
+// at their copies in workDir. Modules installed in the data directory are
+// still read from there, since they're never written to.
+func (runner *TestSuiteRunner) relocateConfig(config *configs.Config, name string, workDir string) {
+	dataDir := filepath.Clean(runner.command.DataDir())
+	relocate := func(c *configs.Config) {
+		// The same module can be reached more than once, so we skip the
+		// directories that have already been relocated.
+		dir := c.Module.SourceDir
+		if filepath.IsAbs(dir) || dir == dataDir || strings.HasPrefix(dir, dataDir+string(filepath.Separator)) {
+			return
+		}
+		c.Module.SourceDir = filepath.Join(workDir, dir)
+	}
+
+	config.DeepEach(relocate)
+	for _, run := range config.Module.Tests[name].Runs {
+		if run.ConfigUnderTest != nil {
+			run.ConfigUnderTest.DeepEach(relocate)
+		}
+	}
+}
+
+// executeParallelRuns executes a group of adjacent run blocks that are all
+// marked as parallel. Run blocks that use different states execute
+// concurrently, with at most runner.Suite.Parallel executing at once, while
+// run blocks that share a state still execute in order.
+func (runner *TestFileRunner) executeParallelRuns(runs []*moduletest.Run, file *moduletest.File) {
+	var keys []string
+	chains := make(map[string][]*moduletest.Run)
+	for _, run := range runs {
+		key := testRunStateKey(run)
+		if _, exists := chains[key]; !exists {
+			keys = append(keys, key)
+		}
+		chains[key] = append(chains[key], run)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runner.Suite.Parallel)
+	panicHandler := logging.PanicHandlerWithTraceFn()
+	for _, key := range keys {
+		chain := chains[key]
+		wg.Add(1)
+		go func() {
+			defer panicHandler()
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			for _, run := range chain {
+				log.Printf("[DEBUG] TestFileRunner: executing run block %s/%s in parallel", file.Name, run.Name)
+				runner.executeRun(run, file)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// testViewBuffer is an implementation of views.Test that holds back the
+// output of a single test file until Flush is called, so that the output of
+// test files executing in parallel isn't interleaved.
+//
+// The output of a fatal interrupt is printed straight away, since the test
+// command won't wait for the files to finish after one.
+type testViewBuffer struct {
+	view views.Test
+
+	// lock is shared by all the buffers writing to the same view.
+	lock *sync.Mutex
+
+	mu    sync.Mutex
+	calls []func()
+}
+
+var _ views.Test = (*testViewBuffer)(nil)
+
+// Flush prints everything that has been held back.
+func (v *testViewBuffer) Flush() {
+	v.mu.Lock()
+	calls := v.calls
+	v.calls = nil
+	v.mu.Unlock()
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	for _, call := range calls {
+		call()
+	}
+}
+
+func (v *testViewBuffer) buffer(call func()) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.calls = append(v.calls, call)
+}
+
+func (v *testViewBuffer) now(call func()) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	call()
+}
+
+func (v *testViewBuffer) Abstract(suite *moduletest.Suite) {
+	v.now(func() { v.view.Abstract(suite) })
+}
+
+func (v *testViewBuffer) Conclusion(suite *moduletest.Suite) {
+	v.now(func() { v.view.Conclusion(suite) })
+}
+
+func (v *testViewBuffer) Coverage(report *moduletest.CoverageReport) {
+	v.now(func() { v.view.Coverage(report) })
+}
+
+func (v *testViewBuffer) File(file *moduletest.File) {
+	v.buffer(func() { v.view.File(file) })
+}
+
+func (v *testViewBuffer) Run(run *moduletest.Run, file *moduletest.File) {
+	v.buffer(func() { v.view.Run(run, file) })
+}
+
+func (v *testViewBuffer) DestroySummary(diags tfdiags.Diagnostics, run *moduletest.Run, file *moduletest.File, state *states.State) {
+	v.buffer(func() { v.view.DestroySummary(diags, run, file, state) })
+}
+
+func (v *testViewBuffer) Diagnostics(run *moduletest.Run, file *moduletest.File, diags tfdiags.Diagnostics) {
+	v.buffer(func() { v.view.Diagnostics(run, file, diags) })
+}
+
+func (v *testViewBuffer) Interrupted() {
+	v.now(v.view.Interrupted)
+}
+
+func (v *testViewBuffer) FatalInterrupt() {
+	v.now(v.view.FatalInterrupt)
+}
+
+func (v *testViewBuffer) FatalInterruptSummary(run *moduletest.Run, file *moduletest.File, states map[*moduletest.Run]*states.State, created []*plans.ResourceInstanceChangeSrc) {
+	// Anything held back is printed first, so the summary makes sense.
+	v.Flush()
+	v.now(func() { v.view.FatalInterruptSummary(run, file, states, created) })
+}
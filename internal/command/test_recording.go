@@ -0,0 +1,653 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// providerRecordingFormatVersion is the version of the format of the
+// recording files written by "tofu test -record".
+const providerRecordingFormatVersion = "1.0"
+
+// providerRecordingPath returns the path of the recording file for the test
+// file with the given name, which sits next to the test file.
+func providerRecordingPath(name string) string {
+	for _, suffix := range []string{".tftest.hcl", ".tftest.json"} {
+		if strings.HasSuffix(name, suffix) {
+			return strings.TrimSuffix(name, suffix) + ".tfrecording.json"
+		}
+	}
+	return name + ".tfrecording.json"
+}
+
+// providerRecording holds the interactions with providers while executing a
+// single test file, so that they can be replayed later without the real
+// providers having to reach any remote APIs.
+//
+// Only the interactions that may reach remote APIs are recorded: reading,
+// planning, applying, and importing resources, and reading data sources. The
+// provider configuration is never recorded, and the values of attributes that
+// the provider schema marks as sensitive are replaced with null.
+type providerRecording struct {
+	FormatVersion string                 `json:"format_version"`
+	Interactions  []*providerInteraction `json:"interactions"`
+
+	// mu protects Interactions and used, since providers can be called
+	// concurrently.
+	mu sync.Mutex
+
+	// used records which of the interactions have already been replayed.
+	used []bool
+}
+
+// providerInteraction is a single request to a provider and its response.
+type providerInteraction struct {
+	Provider string `json:"provider"`
+	Method   string `json:"method"`
+	TypeName string `json:"type_name"`
+
+	// Request holds the values of the request, with any sensitive attributes
+	// redacted, which are compared with the values of a new request when
+	// replaying.
+	Request map[string]plans.DynamicValue `json:"request,omitempty"`
+	ID      string                        `json:"id,omitempty"`
+
+	Response providerInteractionResponse `json:"response"`
+}
+
+type providerInteractionResponse struct {
+	// State is the planned state, the new state, or the state of the data
+	// source, depending on the method.
+	State            plans.DynamicValue        `json:"state,omitempty"`
+	Private          []byte                    `json:"private,omitempty"`
+	RequiresReplace  [][]providerRecordingStep `json:"requires_replace,omitempty"`
+	LegacyTypeSystem bool                      `json:"legacy_type_system,omitempty"`
+
+	ImportedResources []providerRecordingImport `json:"imported_resources,omitempty"`
+
+	Diagnostics []providerRecordingDiagnostic `json:"diagnostics,omitempty"`
+}
+
+type providerRecordingImport struct {
+	TypeName string             `json:"type_name"`
+	State    plans.DynamicValue `json:"state"`
+	Private  []byte             `json:"private,omitempty"`
+}
+
+// providerRecordingStep is a step of an attribute path, which is one of an
+// attribute name, a map key, or a list index, like in the plugin protocol.
+type providerRecordingStep struct {
+	Attribute string  `json:"attribute,omitempty"`
+	Key       *string `json:"key,omitempty"`
+	Index     *int64  `json:"index,omitempty"`
+}
+
+type providerRecordingDiagnostic struct {
+	Severity string `json:"severity"`
+	Summary  string `json:"summary"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+// loadProviderRecording reads the recording file at the given path.
+func loadProviderRecording(path string) (*providerRecording, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	src, err := os.ReadFile(path)
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to read provider recording",
+			fmt.Sprintf("Could not read the provider recording %s: %s. Run the tests with the -record option to create it.", path, err),
+		))
+		return nil, diags
+	}
+
+	recording := new(providerRecording)
+	if err := json.Unmarshal(src, recording); err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid provider recording",
+			fmt.Sprintf("The provider recording %s is not valid: %s.", path, err),
+		))
+		return nil, diags
+	}
+	if recording.FormatVersion != providerRecordingFormatVersion {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported provider recording",
+			fmt.Sprintf("The provider recording %s has format version %q, but this version of OpenTofu only supports %q. Run the tests with the -record option to record it again.", path, recording.FormatVersion, providerRecordingFormatVersion),
+		))
+		return nil, diags
+	}
+	recording.used = make([]bool, len(recording.Interactions))
+	return recording, diags
+}
+
+// save writes the recording to the file at the given path.
+func (r *providerRecording) save(path string) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.FormatVersion = providerRecordingFormatVersion
+	if r.Interactions == nil {
+		r.Interactions = []*providerInteraction{}
+	}
+	src, err := json.MarshalIndent(r, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, append(src, '\n'), 0644)
+	}
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to write provider recording",
+			fmt.Sprintf("Could not write the provider recording %s: %s.", path, err),
+		))
+	}
+	return diags
+}
+
+func (r *providerRecording) add(interaction *providerInteraction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Interactions = append(r.Interactions, interaction)
+}
+
+// find returns the first interaction that hasn't been replayed yet and that
+// matches the given request, and marks it as replayed.
+func (r *providerRecording) find(provider addrs.Provider, method, typeName, id string, request map[string]cty.Value) (*providerInteraction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for ix, interaction := range r.Interactions {
+		if r.used[ix] || interaction.Provider != provider.String() || interaction.Method != method || interaction.TypeName != typeName || interaction.ID != id {
+			continue
+		}
+		if len(interaction.Request) != len(request) {
+			continue
+		}
+
+		matches := true
+		for name, val := range request {
+			recorded, err := interaction.Request[name].Decode(cty.DynamicPseudoType)
+			if err != nil {
+				return nil, fmt.Errorf("invalid recorded request: %w", err)
+			}
+			if !recorded.RawEquals(val) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			r.used[ix] = true
+			return interaction, nil
+		}
+	}
+	return nil, fmt.Errorf("the recording has no response from %s to a %s request for %s with the same values; run the tests with the -record option to record it again", provider, method, typeName)
+}
+
+// recordingProviderFactories wraps the given provider factories so that the
+// providers they create either record their interactions into the given
+// recording, or replay them from it.
+func recordingProviderFactories(factories map[addrs.Provider]providers.Factory, recording *providerRecording, replay bool) map[addrs.Provider]providers.Factory {
+	ret := make(map[addrs.Provider]providers.Factory, len(factories))
+	for addr, factory := range factories {
+		addr, factory := addr, factory
+		ret[addr] = func() (providers.Interface, error) {
+			provider, err := factory()
+			if err != nil {
+				return nil, err
+			}
+			return &recordingProvider{
+				Interface: provider,
+				addr:      addr,
+				recording: recording,
+				replay:    replay,
+			}, nil
+		}
+	}
+	return ret
+}
+
+// recordingProvider is a providers.Interface that records the interactions
+// with the provider it wraps, or replays them from a recording instead of
+// calling the provider. When replaying, the wrapped provider is still used for
+// its schema and for validation, which don't need it to be configured.
+type recordingProvider struct {
+	providers.Interface
+
+	addr      addrs.Provider
+	recording *providerRecording
+	replay    bool
+
+	schemaOnce sync.Once
+	schema     providers.GetProviderSchemaResponse
+}
+
+var _ providers.Interface = (*recordingProvider)(nil)
+
+// providerSchema returns the schema of the wrapped provider, which is only
+// requested once.
+func (p *recordingProvider) providerSchema() providers.GetProviderSchemaResponse {
+	p.schemaOnce.Do(func() {
+		p.schema = p.Interface.GetProviderSchema()
+	})
+	return p.schema
+}
+
+// resourceSchema returns the schema of the given resource type or data
+// source, or nil if the provider doesn't have one.
+func (p *recordingProvider) resourceSchema(mode addrs.ResourceMode, typeName string) *configschema.Block {
+	schemas := p.providerSchema().ResourceTypes
+	if mode == addrs.DataResourceMode {
+		schemas = p.providerSchema().DataSources
+	}
+	return schemas[typeName].Block
+}
+
+// request redacts the given request values, ignoring any that aren't set.
+func (p *recordingProvider) request(schema *configschema.Block, values map[string]cty.Value) map[string]cty.Value {
+	ret := make(map[string]cty.Value, len(values))
+	for name, val := range values {
+		if val != cty.NilVal {
+			ret[name] = redactSensitiveValues(val, schema)
+		}
+	}
+	return ret
+}
+
+// interact records the response to a request, or replays the recorded
+// response, returning an error if the response can't be recorded or
+// replayed.
+func (p *recordingProvider) interact(method, typeName, id string, request map[string]cty.Value, call func() (providerInteractionResponse, error)) (*providerInteractionResponse, error) {
+	if p.replay {
+		interaction, err := p.recording.find(p.addr, method, typeName, id, request)
+		if err != nil {
+			return nil, err
+		}
+		return &interaction.Response, nil
+	}
+
+	interaction := &providerInteraction{
+		Provider: p.addr.String(),
+		Method:   method,
+		TypeName: typeName,
+		ID:       id,
+		Request:  make(map[string]plans.DynamicValue, len(request)),
+	}
+	for name, val := range request {
+		dv, err := plans.NewDynamicValue(val, cty.DynamicPseudoType)
+		if err != nil {
+			return nil, err
+		}
+		interaction.Request[name] = dv
+	}
+	response, err := call()
+	if err != nil {
+		return nil, err
+	}
+	interaction.Response = response
+	p.recording.add(interaction)
+	return &interaction.Response, nil
+}
+
+func (p *recordingProvider) ConfigureProvider(req providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+	if p.replay {
+		// The recorded responses don't need a configured provider, which
+		// means that replaying doesn't need any credentials.
+		return providers.ConfigureProviderResponse{}
+	}
+	return p.Interface.ConfigureProvider(req)
+}
+
+func (p *recordingProvider) UpgradeResourceState(req providers.UpgradeResourceStateRequest) providers.UpgradeResourceStateResponse {
+	if p.replay && req.RawStateJSON != nil {
+		// The provider isn't configured when replaying, so we decode states
+		// that are already at the current schema version ourselves.
+		if schema, ok := p.providerSchema().ResourceTypes[req.TypeName]; ok && schema.Block != nil && schema.Version == req.Version {
+			var resp providers.UpgradeResourceStateResponse
+			state, err := ctyjson.Unmarshal(req.RawStateJSON, schema.Block.ImpliedType())
+			if err != nil {
+				resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+				return resp
+			}
+			resp.UpgradedState = state
+			return resp
+		}
+	}
+	return p.Interface.UpgradeResourceState(req)
+}
+
+func (p *recordingProvider) ReadResource(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	schema := p.resourceSchema(addrs.ManagedResourceMode, req.TypeName)
+
+	var resp providers.ReadResourceResponse
+	recorded, err := p.interact("ReadResource", req.TypeName, "", p.request(schema, map[string]cty.Value{
+		"prior_state": req.PriorState,
+	}), func() (providerInteractionResponse, error) {
+		resp = p.Interface.ReadResource(req)
+		state, err := recordValue(resp.NewState, schema)
+		return providerInteractionResponse{
+			State:       state,
+			Private:     resp.Private,
+			Diagnostics: recordDiagnostics(resp.Diagnostics),
+		}, err
+	})
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+		return resp
+	}
+	if p.replay {
+		resp.NewState, err = replayValue(recorded.State, req.PriorState, schema)
+		resp.Private = recorded.Private
+		resp.Diagnostics = replayDiagnostics(recorded.Diagnostics)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+		}
+	}
+	return resp
+}
+
+func (p *recordingProvider) PlanResourceChange(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	schema := p.resourceSchema(addrs.ManagedResourceMode, req.TypeName)
+
+	var resp providers.PlanResourceChangeResponse
+	recorded, err := p.interact("PlanResourceChange", req.TypeName, "", p.request(schema, map[string]cty.Value{
+		"prior_state":        req.PriorState,
+		"proposed_new_state": req.ProposedNewState,
+		"config":             req.Config,
+	}), func() (providerInteractionResponse, error) {
+		resp = p.Interface.PlanResourceChange(req)
+		state, err := recordValue(resp.PlannedState, schema)
+		return providerInteractionResponse{
+			State:            state,
+			Private:          resp.PlannedPrivate,
+			RequiresReplace:  recordPaths(resp.RequiresReplace),
+			LegacyTypeSystem: resp.LegacyTypeSystem,
+			Diagnostics:      recordDiagnostics(resp.Diagnostics),
+		}, err
+	})
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+		return resp
+	}
+	if p.replay {
+		resp.PlannedState, err = replayValue(recorded.State, req.ProposedNewState, schema)
+		resp.PlannedPrivate = recorded.Private
+		resp.RequiresReplace = replayPaths(recorded.RequiresReplace)
+		resp.LegacyTypeSystem = recorded.LegacyTypeSystem
+		resp.Diagnostics = replayDiagnostics(recorded.Diagnostics)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+		}
+	}
+	return resp
+}
+
+func (p *recordingProvider) ApplyResourceChange(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+	schema := p.resourceSchema(addrs.ManagedResourceMode, req.TypeName)
+
+	var resp providers.ApplyResourceChangeResponse
+	recorded, err := p.interact("ApplyResourceChange", req.TypeName, "", p.request(schema, map[string]cty.Value{
+		"prior_state":   req.PriorState,
+		"planned_state": req.PlannedState,
+		"config":        req.Config,
+	}), func() (providerInteractionResponse, error) {
+		resp = p.Interface.ApplyResourceChange(req)
+		state, err := recordValue(resp.NewState, schema)
+		return providerInteractionResponse{
+			State:            state,
+			Private:          resp.Private,
+			LegacyTypeSystem: resp.LegacyTypeSystem,
+			Diagnostics:      recordDiagnostics(resp.Diagnostics),
+		}, err
+	})
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+		return resp
+	}
+	if p.replay {
+		resp.NewState, err = replayValue(recorded.State, req.PlannedState, schema)
+		resp.Private = recorded.Private
+		resp.LegacyTypeSystem = recorded.LegacyTypeSystem
+		resp.Diagnostics = replayDiagnostics(recorded.Diagnostics)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+		}
+	}
+	return resp
+}
+
+func (p *recordingProvider) ImportResourceState(req providers.ImportResourceStateRequest) providers.ImportResourceStateResponse {
+	var resp providers.ImportResourceStateResponse
+	recorded, err := p.interact("ImportResourceState", req.TypeName, req.ID, nil, func() (providerInteractionResponse, error) {
+		resp = p.Interface.ImportResourceState(req)
+		recorded := providerInteractionResponse{
+			Diagnostics: recordDiagnostics(resp.Diagnostics),
+		}
+		for _, imported := range resp.ImportedResources {
+			state, err := recordValue(imported.State, p.resourceSchema(addrs.ManagedResourceMode, imported.TypeName))
+			if err != nil {
+				return recorded, err
+			}
+			recorded.ImportedResources = append(recorded.ImportedResources, providerRecordingImport{
+				TypeName: imported.TypeName,
+				State:    state,
+				Private:  imported.Private,
+			})
+		}
+		return recorded, nil
+	})
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+		return resp
+	}
+	if p.replay {
+		resp.Diagnostics = replayDiagnostics(recorded.Diagnostics)
+		for _, imported := range recorded.ImportedResources {
+			// There's no request value to restore redacted values from, so
+			// they stay null.
+			state, err := imported.State.Decode(cty.DynamicPseudoType)
+			if err != nil {
+				resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+				continue
+			}
+			resp.ImportedResources = append(resp.ImportedResources, providers.ImportedResource{
+				TypeName: imported.TypeName,
+				State:    state,
+				Private:  imported.Private,
+			})
+		}
+	}
+	return resp
+}
+
+func (p *recordingProvider) ReadDataSource(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+	schema := p.resourceSchema(addrs.DataResourceMode, req.TypeName)
+
+	var resp providers.ReadDataSourceResponse
+	recorded, err := p.interact("ReadDataSource", req.TypeName, "", p.request(schema, map[string]cty.Value{
+		"config": req.Config,
+	}), func() (providerInteractionResponse, error) {
+		resp = p.Interface.ReadDataSource(req)
+		state, err := recordValue(resp.State, schema)
+		return providerInteractionResponse{
+			State:       state,
+			Diagnostics: recordDiagnostics(resp.Diagnostics),
+		}, err
+	})
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+		return resp
+	}
+	if p.replay {
+		resp.State, err = replayValue(recorded.State, req.Config, schema)
+		resp.Diagnostics = replayDiagnostics(recorded.Diagnostics)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(providerRecordingError(p.replay, err))
+		}
+	}
+	return resp
+}
+
+func providerRecordingError(replay bool, err error) tfdiags.Diagnostic {
+	if replay {
+		return tfdiags.Sourceless(tfdiags.Error, "Failed to replay provider response", fmt.Sprintf("Could not replay the provider response: %s.", err))
+	}
+	return tfdiags.Sourceless(tfdiags.Error, "Failed to record provider response", fmt.Sprintf("Could not record the provider response: %s.", err))
+}
+
+// redactSensitiveValues replaces the values of all the attributes that the
+// given schema marks as sensitive with null.
+func redactSensitiveValues(val cty.Value, schema *configschema.Block) cty.Value {
+	if schema == nil || val.IsNull() || !val.IsKnown() || !schema.ContainsSensitive() {
+		return val
+	}
+	sensitive := schema.ValueMarks(val, nil)
+	ret, err := cty.Transform(val, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		if !v.IsKnown() {
+			// Unknown values don't reveal anything, and they must stay
+			// unknown for a replayed plan to be valid.
+			return v, nil
+		}
+		for _, pvm := range sensitive {
+			if path.Equals(pvm.Path) {
+				return cty.NullVal(v.Type()), nil
+			}
+		}
+		return v, nil
+	})
+	if err != nil {
+		// This can't happen, since the transform never returns an error.
+		panic(err)
+	}
+	return ret
+}
+
+// recordValue redacts and encodes a value from a provider response.
+func recordValue(val cty.Value, schema *configschema.Block) (plans.DynamicValue, error) {
+	return plans.NewDynamicValue(redactSensitiveValues(val, schema), cty.DynamicPseudoType)
+}
+
+// replayValue decodes a value from a recorded provider response, restoring
+// redacted values from the corresponding request value where it has them.
+func replayValue(recorded plans.DynamicValue, from cty.Value, schema *configschema.Block) (cty.Value, error) {
+	val, err := recorded.Decode(cty.DynamicPseudoType)
+	if err != nil || schema == nil || val == cty.NilVal || val.IsNull() || !val.IsKnown() || !schema.ContainsSensitive() {
+		return val, err
+	}
+	sensitive := schema.ValueMarks(val, nil)
+	return cty.Transform(val, func(path cty.Path, v cty.Value) (cty.Value, error) {
+		if !v.IsNull() {
+			return v, nil
+		}
+		for _, pvm := range sensitive {
+			if !path.Equals(pvm.Path) {
+				continue
+			}
+			if restored, err := path.Apply(from); err == nil && restored.IsWhollyKnown() && restored.Type().Equals(v.Type()) {
+				return restored, nil
+			}
+		}
+		return v, nil
+	})
+}
+
+func recordPaths(paths []cty.Path) [][]providerRecordingStep {
+	var ret [][]providerRecordingStep
+	for _, path := range paths {
+		var steps []providerRecordingStep
+		for _, step := range path {
+			switch step := step.(type) {
+			case cty.GetAttrStep:
+				steps = append(steps, providerRecordingStep{Attribute: step.Name})
+			case cty.IndexStep:
+				switch step.Key.Type() {
+				case cty.String:
+					key := step.Key.AsString()
+					steps = append(steps, providerRecordingStep{Key: &key})
+				case cty.Number:
+					index, _ := step.Key.AsBigFloat().Int64()
+					steps = append(steps, providerRecordingStep{Index: &index})
+				}
+			}
+		}
+		ret = append(ret, steps)
+	}
+	return ret
+}
+
+func replayPaths(paths [][]providerRecordingStep) []cty.Path {
+	var ret []cty.Path
+	for _, steps := range paths {
+		var path cty.Path
+		for _, step := range steps {
+			switch {
+			case step.Key != nil:
+				path = path.Index(cty.StringVal(*step.Key))
+			case step.Index != nil:
+				path = path.IndexInt(int(*step.Index))
+			default:
+				path = path.GetAttr(step.Attribute)
+			}
+		}
+		ret = append(ret, path)
+	}
+	return ret
+}
+
+func recordDiagnostics(diags tfdiags.Diagnostics) []providerRecordingDiagnostic {
+	var ret []providerRecordingDiagnostic
+	for _, diag := range diags {
+		severity := "error"
+		if diag.Severity() == tfdiags.Warning {
+			severity = "warning"
+		}
+		desc := diag.Description()
+		ret = append(ret, providerRecordingDiagnostic{
+			Severity: severity,
+			Summary:  desc.Summary,
+			Detail:   desc.Detail,
+		})
+	}
+	return ret
+}
+
+func replayDiagnostics(recorded []providerRecordingDiagnostic) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for _, diag := range recorded {
+		severity := tfdiags.Error
+		if diag.Severity == "warning" {
+			severity = tfdiags.Warning
+		}
+		diags = diags.Append(tfdiags.Sourceless(severity, diag.Summary, diag.Detail))
+	}
+	return diags
+}
+
+// recordingContextOpts returns a copy of the given options whose providers
+// record into, or replay from, the given recording.
+func recordingContextOpts(opts *tofu.ContextOpts, recording *providerRecording, replay bool) *tofu.ContextOpts {
+	ret := *opts
+	ret.Providers = recordingProviderFactories(opts.Providers, recording, replay)
+	return &ret
+}
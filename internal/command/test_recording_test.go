@@ -0,0 +1,119 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+)
+
+func TestProviderRecording_redaction(t *testing.T) {
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"id":       {Type: cty.String, Computed: true},
+			"password": {Type: cty.String, Optional: true, Sensitive: true},
+			"token":    {Type: cty.String, Computed: true, Sensitive: true},
+		},
+		BlockTypes: map[string]*configschema.NestedBlock{
+			"credentials": {
+				Nesting: configschema.NestingList,
+				Block: configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"user":   {Type: cty.String, Optional: true},
+						"secret": {Type: cty.String, Optional: true, Sensitive: true},
+					},
+				},
+			},
+		},
+	}
+
+	val := cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.StringVal("foo"),
+		"password": cty.StringVal("hunter2"),
+		"token":    cty.StringVal("generated"),
+		"credentials": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"user":   cty.StringVal("admin"),
+				"secret": cty.StringVal("s3cr3t"),
+			}),
+		}),
+	})
+
+	recorded, err := recordValue(val, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := recorded.Decode(cty.DynamicPseudoType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.StringVal("foo"),
+		"password": cty.NullVal(cty.String),
+		"token":    cty.NullVal(cty.String),
+		"credentials": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"user":   cty.StringVal("admin"),
+				"secret": cty.NullVal(cty.String),
+			}),
+		}),
+	})
+	if !got.RawEquals(want) {
+		t.Fatalf("wrong recorded value\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	// When replaying, the redacted values are restored from the request, but
+	// the computed token isn't known in the request so it stays null.
+	request := cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.UnknownVal(cty.String),
+		"password": cty.StringVal("hunter2"),
+		"token":    cty.UnknownVal(cty.String),
+		"credentials": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"user":   cty.StringVal("admin"),
+				"secret": cty.StringVal("s3cr3t"),
+			}),
+		}),
+	})
+	got, err = replayValue(recorded, request, schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = cty.ObjectVal(map[string]cty.Value{
+		"id":       cty.StringVal("foo"),
+		"password": cty.StringVal("hunter2"),
+		"token":    cty.NullVal(cty.String),
+		"credentials": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"user":   cty.StringVal("admin"),
+				"secret": cty.StringVal("s3cr3t"),
+			}),
+		}),
+	})
+	if !got.RawEquals(want) {
+		t.Fatalf("wrong replayed value\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestProviderRecording_paths(t *testing.T) {
+	paths := []cty.Path{
+		cty.GetAttrPath("name"),
+		cty.GetAttrPath("tags").Index(cty.StringVal("env")),
+		cty.GetAttrPath("rule").IndexInt(2).GetAttr("port"),
+	}
+	got := replayPaths(recordPaths(paths))
+	if len(got) != len(paths) {
+		t.Fatalf("wrong number of paths %d, want %d", len(got), len(paths))
+	}
+	for ix := range paths {
+		if !got[ix].Equals(paths[ix]) {
+			t.Errorf("wrong path %d\ngot:  %#v\nwant: %#v", ix, got[ix], paths[ix])
+		}
+	}
+}
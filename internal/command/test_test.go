@@ -6,6 +6,9 @@
 package command
 
 import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
 	"path"
 	"strings"
 	"testing"
@@ -17,6 +20,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	testing_command "github.com/opentofu/opentofu/internal/command/testing"
 	"github.com/opentofu/opentofu/internal/command/views"
+	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/terminal"
 )
@@ -77,6 +81,18 @@ func TestTest(t *testing.T) {
 			expected: "1 passed, 0 failed.",
 			code:     0,
 		},
+		"expect_failure_blocks": {
+			expected: "2 passed, 0 failed.",
+			code:     0,
+		},
+		"override_generators": {
+			expected: "2 passed, 0 failed.",
+			code:     0,
+		},
+		"helpers": {
+			expected: "2 passed, 0 failed.",
+			code:     0,
+		},
 		"expect_failures_outputs": {
 			expected: "1 passed, 0 failed.",
 			code:     0,
@@ -169,6 +185,12 @@ func TestTest(t *testing.T) {
 			expected: "1 passed, 0 failed.",
 			code:     0,
 		},
+		"multiple_files_parallel": {
+			override: "multiple_files",
+			args:     []string{"-parallel=2"},
+			expected: "2 passed, 0 failed",
+			code:     0,
+		},
 	}
 	for name, tc := range tcs {
 		t.Run(name, func(t *testing.T) {
@@ -258,6 +280,17 @@ func TestTest_Full_Output(t *testing.T) {
 			code:     0,
 			args:     []string{"-no-color"},
 		},
+		"is_sorted_parallel": {
+			override: "is_sorted",
+			expected: "1.tftest.hcl... pass\n  run \"a\"... pass\n2.tftest.hcl... pass\n  run \"b\"... pass\n3.tftest.hcl... pass\n  run \"c\"... pass",
+			code:     0,
+			args:     []string{"-no-color", "-parallel=3"},
+		},
+		"parallel_run_reference": {
+			expected: "The run block \"b\" can't refer to the outputs of \"a\"",
+			code:     1,
+			args:     []string{"-no-color"},
+		},
 	}
 	for name, tc := range tcs {
 		t.Run(name, func(t *testing.T) {
@@ -523,6 +556,683 @@ func TestTest_ModuleDependencies(t *testing.T) {
 	}
 }
 
+func TestTest_ParallelRuns(t *testing.T) {
+	for name, args := range map[string][]string{
+		"parallel":   {"-parallel=2"},
+		"sequential": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			td := t.TempDir()
+			testCopyDir(t, testFixturePath(path.Join("test", "parallel_runs")), td)
+			defer testChdir(t, td)()
+
+			provider := testing_command.NewProvider(nil)
+
+			providerSource, close := newMockProviderSource(t, map[string][]string{
+				"test": {"1.0.0"},
+			})
+			defer close()
+
+			streams, done := terminal.StreamsForTesting(t)
+			view := views.NewView(streams)
+			ui := new(cli.MockUi)
+
+			meta := Meta{
+				testingOverrides: metaOverridesForProvider(provider.Provider),
+				Ui:               ui,
+				View:             view,
+				Streams:          streams,
+				ProviderSource:   providerSource,
+			}
+
+			init := &InitCommand{
+				Meta: meta,
+			}
+
+			if code := init.Run(nil); code != 0 {
+				t.Fatalf("expected status code 0 but got %d: %s", code, ui.ErrorWriter)
+			}
+
+			command := &TestCommand{
+				Meta: meta,
+			}
+
+			code := command.Run(append([]string{"-no-color"}, args...))
+			output := done(t)
+
+			if code != 0 {
+				t.Errorf("expected status code 0 but got %d: %s", code, output.All())
+			}
+
+			expected := "main.tftest.hcl... pass\n  run \"main\"... pass\n  run \"setup\"... pass\n  run \"after\"... pass\n\nSuccess! 3 passed, 0 failed."
+			if !strings.Contains(output.Stdout(), expected) {
+				t.Errorf("output didn't contain expected string:\n\n%s\n\n----\n\nexpected: %s", output.All(), expected)
+			}
+
+			if provider.ResourceCount() > 0 {
+				t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+			}
+		})
+	}
+}
+
+func TestTest_ParallelFiles(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "parallel_files")), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+
+	providerSource, close := newMockProviderSource(t, map[string][]string{
+		"test": {"1.0.0"},
+	})
+	defer close()
+
+	streams, done := terminal.StreamsForTesting(t)
+	view := views.NewView(streams)
+	ui := new(cli.MockUi)
+
+	meta := Meta{
+		testingOverrides: metaOverridesForProvider(provider.Provider),
+		Ui:               ui,
+		View:             view,
+		Streams:          streams,
+		ProviderSource:   providerSource,
+	}
+
+	init := &InitCommand{
+		Meta: meta,
+	}
+
+	if code := init.Run(nil); code != 0 {
+		t.Fatalf("expected status code 0 but got %d: %s", code, ui.ErrorWriter)
+	}
+
+	command := &TestCommand{
+		Meta: meta,
+	}
+
+	// Each file executes in its own copy of the configuration, which
+	// should be removed once the files have finished.
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	t.Setenv("TMP", tmp)
+
+	code := command.Run([]string{"-no-color", "-parallel=2"})
+	output := done(t)
+
+	if code != 0 {
+		t.Errorf("expected status code 0 but got %d: %s", code, output.All())
+	}
+
+	expected := "one.tftest.hcl... pass\n  run \"validate_paths\"... pass\ntwo.tftest.hcl... pass\n  run \"validate_paths\"... pass\n\nSuccess! 2 passed, 0 failed."
+	if !strings.Contains(output.Stdout(), expected) {
+		t.Errorf("output didn't contain expected string:\n\n%s\n\n----\n\nexpected: %s", output.All(), expected)
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+
+	if entries, err := os.ReadDir(tmp); err != nil {
+		t.Fatal(err)
+	} else if len(entries) > 0 {
+		t.Errorf("should have removed the copies of the configuration but left %d entries in %s", len(entries), tmp)
+	}
+}
+
+func TestTest_SetupTeardown(t *testing.T) {
+	tcs := map[string]struct {
+		expected string
+		code     int
+		created  []string
+	}{
+		"setup_teardown": {
+			expected: "main.tftest.hcl... fail\n  run \"test\"... pass\n  run \"fails\"... fail\n\nFailure! 1 passed, 1 failed.",
+			code:     1,
+			created:  []string{"fixture", "fixture", "cleanup"},
+		},
+		"setup_failure": {
+			expected: "main.tftest.hcl... fail\n  run \"setup\"... fail\n  run \"test\"... skip\n\nFailure! 0 passed, 0 failed, 1 skipped.",
+			code:     1,
+			created:  []string{"cleanup"},
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			td := t.TempDir()
+			testCopyDir(t, testFixturePath(path.Join("test", name)), td)
+			defer testChdir(t, td)()
+
+			provider := testing_command.NewProvider(nil)
+
+			// We record the values of the resources that are created, so we
+			// can check that the teardown block was applied.
+			var created []string
+			provider.Provider.ApplyResourceChangeFn = func(request providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+				if !request.PlannedState.IsNull() && request.PriorState.IsNull() {
+					created = append(created, request.PlannedState.GetAttr("value").AsString())
+				}
+				return provider.ApplyResourceChange(request)
+			}
+
+			providerSource, close := newMockProviderSource(t, map[string][]string{
+				"test": {"1.0.0"},
+			})
+			defer close()
+
+			streams, done := terminal.StreamsForTesting(t)
+			view := views.NewView(streams)
+			ui := new(cli.MockUi)
+
+			meta := Meta{
+				testingOverrides: metaOverridesForProvider(provider.Provider),
+				Ui:               ui,
+				View:             view,
+				Streams:          streams,
+				ProviderSource:   providerSource,
+			}
+
+			init := &InitCommand{
+				Meta: meta,
+			}
+
+			if code := init.Run(nil); code != 0 {
+				t.Fatalf("expected status code 0 but got %d: %s", code, ui.ErrorWriter)
+			}
+
+			command := &TestCommand{
+				Meta: meta,
+			}
+
+			code := command.Run([]string{"-no-color"})
+			output := done(t)
+
+			if code != tc.code {
+				t.Errorf("expected status code %d but got %d: %s", tc.code, code, output.All())
+			}
+
+			if !strings.Contains(output.Stdout(), tc.expected) {
+				t.Errorf("output didn't contain expected string:\n\n%s\n\n----\n\nexpected: %s", output.All(), tc.expected)
+			}
+
+			if diff := cmp.Diff(tc.created, created); diff != "" {
+				t.Errorf("wrong resources created\n%s", diff)
+			}
+
+			if provider.ResourceCount() > 0 {
+				t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+			}
+		})
+	}
+}
+
+func TestTest_RecordReplay(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "record_replay")), td)
+	defer testChdir(t, td)()
+
+	run := func(provider *testing_command.TestProvider, args ...string) (int, string) {
+		view, done := testView(t)
+		c := &TestCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(provider.Provider),
+				View:             view,
+			},
+		}
+		code := c.Run(append([]string{"-no-color"}, args...))
+		return code, done(t).All()
+	}
+
+	provider := testing_command.NewProvider(nil)
+	if code, output := run(provider, "-record"); code != 0 {
+		t.Fatalf("expected status code 0 but got %d: %s", code, output)
+	}
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+	if _, err := os.Stat("main.tfrecording.json"); err != nil {
+		t.Fatalf("recording wasn't written: %s", err)
+	}
+
+	// When replaying, the provider must not be called for anything that was
+	// recorded.
+	replay := testing_command.NewProvider(nil)
+	replay.Provider.ConfigureProviderFn = func(providers.ConfigureProviderRequest) providers.ConfigureProviderResponse {
+		panic("provider was configured while replaying")
+	}
+	replay.Provider.PlanResourceChangeFn = func(providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+		panic("provider planned a change while replaying")
+	}
+	replay.Provider.ApplyResourceChangeFn = func(providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		panic("provider applied a change while replaying")
+	}
+	replay.Provider.ReadResourceFn = func(providers.ReadResourceRequest) providers.ReadResourceResponse {
+		panic("provider read a resource while replaying")
+	}
+
+	code, output := run(replay, "-replay")
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d: %s", code, output)
+	}
+	expected := "main.tftest.hcl... pass\n  run \"create\"... pass\n  run \"update\"... pass\n\nSuccess! 2 passed, 0 failed."
+	if !strings.Contains(output, expected) {
+		t.Errorf("output didn't contain expected string:\n\n%s\n\n----\n\nexpected: %s", output, expected)
+	}
+
+	if err := os.Remove("main.tfrecording.json"); err != nil {
+		t.Fatal(err)
+	}
+	code, output = run(replay, "-replay")
+	if code != 1 {
+		t.Fatalf("expected status code 1 but got %d: %s", code, output)
+	}
+	if !strings.Contains(output, "Failed to read provider recording") {
+		t.Errorf("output didn't report the missing recording:\n\n%s", output)
+	}
+}
+
+func TestTest_Coverage(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "coverage")), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", "-coverage-report=coverage.json", "-coverage-threshold=90"})
+	output := done(t).All()
+	if code != 1 {
+		t.Fatalf("expected status code 1 but got %d: %s", code, output)
+	}
+
+	expected := `Success! 2 passed, 0 failed.
+
+Coverage: 85.7% (6 of 7 covered)
+  resources: 2 of 2
+  branches: 2 of 2
+  outputs: 1 of 1
+  variables: 1 of 2
+
+Not covered:
+  - var.unused, at main.tf:6
+`
+	if !strings.Contains(output, expected) {
+		t.Errorf("output didn't contain expected string:\n\n%s\n\n----\n\nexpected: %s", output, expected)
+	}
+	if !strings.Contains(output, "Test coverage below threshold") {
+		t.Errorf("output didn't report the threshold:\n\n%s", output)
+	}
+
+	src, err := os.ReadFile("coverage.json")
+	if err != nil {
+		t.Fatalf("coverage report wasn't written: %s", err)
+	}
+	var report struct {
+		FormatVersion string `json:"format_version"`
+		Covered       int    `json:"covered"`
+		Total         int    `json:"total"`
+	}
+	if err := json.Unmarshal(src, &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.FormatVersion != "1.0" || report.Covered != 6 || report.Total != 7 {
+		t.Errorf("wrong coverage report: %s", src)
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+}
+
+func TestTest_BackendState(t *testing.T) {
+	tcs := map[string]struct {
+		filter   string
+		expected []string
+		code     int
+	}{
+		"no changes": {
+			filter: "no_changes.tftest.hcl",
+			expected: []string{
+				"no_changes.tftest.hcl... pass",
+				"Success! 1 passed, 0 failed.",
+			},
+			code: 0,
+		},
+		"changes": {
+			filter: "changes.tftest.hcl",
+			expected: []string{
+				`run "changes"... fail`,
+				"Error: Unexpected changes",
+				"test_resource.foo will be updated in-place",
+			},
+			code: 1,
+		},
+		"apply": {
+			filter: "apply.tftest.hcl",
+			expected: []string{
+				`run "apply"... fail`,
+				"Error: Apply not allowed against backend state",
+			},
+			code: 1,
+		},
+	}
+	for name, tc := range tcs {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			td := t.TempDir()
+			testCopyDir(t, testFixturePath(path.Join("test", "backend_state")), td)
+			defer testChdir(t, td)()
+
+			before, err := os.ReadFile("terraform.tfstate")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The resource in the backend state already exists.
+			provider := testing_command.NewProvider(&testing_command.ResourceStore{
+				Data: map[string]cty.Value{
+					"foo": cty.ObjectVal(map[string]cty.Value{
+						"id":              cty.StringVal("foo"),
+						"value":           cty.StringVal("bar"),
+						"interrupt_count": cty.NullVal(cty.Number),
+					}),
+				},
+			})
+			view, done := testView(t)
+
+			c := &TestCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(provider.Provider),
+					View:             view,
+				},
+			}
+
+			code := c.Run([]string{"-no-color", "-backend-state", "-filter=" + tc.filter})
+			output := done(t).All()
+			if code != tc.code {
+				t.Errorf("expected status code %d but got %d: %s", tc.code, code, output)
+			}
+			for _, expected := range tc.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("output didn't contain expected string:\n\n%s\n\n----\n\nexpected: %s", output, expected)
+				}
+			}
+
+			// The state in the backend, and the resource it tracks, must be
+			// left untouched.
+			if provider.ResourceCount() != 1 {
+				t.Errorf("should have left the existing resource but found %v", provider.ResourceString())
+			}
+			after, err := os.ReadFile("terraform.tfstate")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(string(before), string(after)); diff != "" {
+				t.Errorf("backend state was modified:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTest_Retries(t *testing.T) {
+	tcs := map[string]struct {
+		args     []string
+		expected []string
+		code     int
+
+		// junit is the message of the skipped JUnit test case for the flaky
+		// run block, if the JUnit XML report is written.
+		junit string
+	}{
+		"retried": {
+			args: []string{"-no-color"},
+			expected: []string{
+				`run "first"... pass`,
+				`run "flaky"... fail`,
+				`run "last"... pass`,
+				"Failure! 2 passed, 1 failed.",
+			},
+			code: 1,
+		},
+		"quarantined": {
+			args: []string{"-no-color", "-quarantine=main.tftest.hcl/flaky", "-junit-xml=results.xml"},
+			expected: []string{
+				`run "flaky"... fail (quarantined)`,
+				"Success! 2 passed, 0 failed, 1 quarantined.",
+			},
+			code:  0,
+			junit: "Quarantined: Test assertion failed: invalid value",
+		},
+		"quarantined file": {
+			args: []string{"-no-color", "-quarantine=main.tftest.hcl"},
+			expected: []string{
+				`run "flaky"... fail (quarantined)`,
+				"Success! 2 passed, 0 failed, 1 quarantined.",
+			},
+			code: 0,
+		},
+		"json": {
+			args: []string{"-json"},
+			expected: []string{
+				`"run":"flaky","status":"fail"`,
+				`"attempts":3}`,
+			},
+			code: 1,
+		},
+		"unknown quarantine": {
+			args: []string{"-no-color", "-quarantine=main.tftest.hcl/missing"},
+			expected: []string{
+				"Warning: Unknown quarantined test",
+				"Failure! 2 passed, 1 failed.",
+			},
+			code: 1,
+		},
+	}
+	for name, tc := range tcs {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			td := t.TempDir()
+			testCopyDir(t, testFixturePath(path.Join("test", "retries")), td)
+			defer testChdir(t, td)()
+
+			provider := testing_command.NewProvider(nil)
+			view, done := testView(t)
+
+			c := &TestCommand{
+				Meta: Meta{
+					testingOverrides: metaOverridesForProvider(provider.Provider),
+					View:             view,
+				},
+			}
+
+			code := c.Run(tc.args)
+			output := done(t).All()
+			if code != tc.code {
+				t.Errorf("expected status code %d but got %d: %s", tc.code, code, output)
+			}
+			for _, expected := range tc.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("output didn't contain expected string:\n\n%s\n\n----\n\nexpected: %s", output, expected)
+				}
+			}
+
+			if tc.junit != "" {
+				src, err := os.ReadFile("results.xml")
+				if err != nil {
+					t.Fatalf("JUnit XML report wasn't written: %s", err)
+				}
+				var report junitTestSuites
+				if err := xml.Unmarshal(src, &report); err != nil {
+					t.Fatalf("invalid JUnit XML report: %s\n\n%s", err, src)
+				}
+				if report.Failures != 0 || report.Skipped != 1 {
+					t.Errorf("wrong totals in JUnit XML report:\n\n%s", src)
+				}
+				if flaky := report.Suites[0].TestCases[1]; flaky.Skipped == nil || flaky.Skipped.Message != tc.junit {
+					t.Errorf("wrong test case for quarantined run: %#v", flaky)
+				}
+			}
+
+			// The retries apply against the same state, so there is only ever
+			// one resource to clean up.
+			if provider.ResourceCount() > 0 {
+				t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+			}
+		})
+	}
+}
+
+func TestTest_Invoke(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "invoke")), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+	p := &actionProvider{MockProvider: provider.Provider}
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+
+	code := c.Run(nil)
+	output := done(t).All()
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d: %s", code, output)
+	}
+	if !strings.Contains(output, "1 passed, 0 failed.") {
+		t.Errorf("wrong output:\n%s", output)
+	}
+
+	if p.invoked == nil {
+		t.Fatal("action was not invoked")
+	}
+	want := cty.ObjectVal(map[string]cty.Value{
+		"id":     cty.StringVal("i-abc123"),
+		"reason": cty.StringVal("maintenance"),
+	})
+	if !p.invoked.Config.RawEquals(want) {
+		t.Errorf("wrong arguments\ngot:  %#v\nwant: %#v", p.invoked.Config, want)
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+}
+
+func TestTest_Variants(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "variants")), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color"})
+	output := done(t).All()
+	if code != 0 {
+		t.Fatalf("expected status code 0 but got %d: %s", code, output)
+	}
+
+	expected := `main.tftest.hcl... pass
+  run "matrix[prefix=a,count_value=1]"... pass
+  run "matrix[prefix=a,count_value=2]"... pass
+  run "matrix[prefix=b,count_value=1]"... pass
+  run "matrix[prefix=b,count_value=2]"... pass
+
+Success! 4 passed, 0 failed.`
+	if !strings.Contains(output, expected) {
+		t.Errorf("output didn't contain expected string:\n\n%s\n\n----\n\nexpected: %s", output, expected)
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+}
+
+func TestTest_JUnitXML(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "junit")), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-no-color", "-junit-xml=results.xml"})
+	output := done(t).All()
+	if code != 1 {
+		t.Fatalf("expected status code 1 but got %d: %s", code, output)
+	}
+
+	src, err := os.ReadFile("results.xml")
+	if err != nil {
+		t.Fatalf("JUnit XML report wasn't written: %s\n\n%s", err, output)
+	}
+
+	var report junitTestSuites
+	if err := xml.Unmarshal(src, &report); err != nil {
+		t.Fatalf("invalid JUnit XML report: %s\n\n%s", err, src)
+	}
+	if report.Tests != 4 || report.Failures != 1 || report.Errors != 1 || report.Skipped != 1 {
+		t.Errorf("wrong totals in JUnit XML report:\n\n%s", src)
+	}
+	if len(report.Suites) != 2 {
+		t.Fatalf("wrong number of test suites in JUnit XML report:\n\n%s", src)
+	}
+
+	main := report.Suites[0]
+	if main.Name != "main.tftest.hcl" || len(main.TestCases) != 2 {
+		t.Fatalf("wrong test suite in JUnit XML report:\n\n%s", src)
+	}
+	if tc := main.TestCases[0]; tc.Name != "pass" || tc.Failure != nil || tc.Error != nil || tc.Skipped != nil {
+		t.Errorf("wrong test case for passing run: %#v", tc)
+	}
+	tc := main.TestCases[1]
+	if tc.Name != "fail" || tc.Failure == nil {
+		t.Fatalf("wrong test case for failing run: %#v", tc)
+	}
+	if got, want := tc.Failure.Message, "Test assertion failed: invalid value"; got != want {
+		t.Errorf("wrong failure message %q, want %q", got, want)
+	}
+	if !strings.Contains(tc.Failure.Body, "test_resource.foo.value == \"zap\"") {
+		t.Errorf("failure didn't include the condition:\n\n%s", tc.Failure.Body)
+	}
+
+	skip := report.Suites[1]
+	if len(skip.TestCases) != 2 || skip.TestCases[0].Error == nil || skip.TestCases[1].Skipped == nil {
+		t.Errorf("wrong test cases for errored file:\n\n%s", src)
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+}
+
 func TestTest_CatchesErrorsBeforeDestroy(t *testing.T) {
 	td := t.TempDir()
 	testCopyDir(t, testFixturePath(path.Join("test", "invalid_default_state")), td)
@@ -1300,3 +2010,56 @@ digits, underscores, and dashes.
 		})
 	}
 }
+
+func TestTest_SARIF(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath(path.Join("test", "simple_fail")), td)
+	defer testChdir(t, td)()
+
+	provider := testing_command.NewProvider(nil)
+	view, done := testView(t)
+
+	c := &TestCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(provider.Provider),
+			View:             view,
+		},
+	}
+
+	code := c.Run([]string{"-format=sarif"})
+	output := done(t)
+
+	if code != 1 {
+		t.Errorf("expected status code 1 but got %d", code)
+	}
+
+	var log viewsjson.SARIFLog
+	if err := json.Unmarshal([]byte(output.Stdout()), &log); err != nil {
+		t.Fatalf("output is not a SARIF log: %s\n%s", err, output.Stdout())
+	}
+	if len(log.Runs) != 1 || len(log.Runs[0].Results) != 1 {
+		t.Fatalf("expected one result, got:\n%s", output.Stdout())
+	}
+
+	result := log.Runs[0].Results[0]
+	if result.Level != "error" {
+		t.Errorf("wrong level %q", result.Level)
+	}
+	if !strings.HasPrefix(result.Message.Text, "Test assertion failed") {
+		t.Errorf("wrong message %q", result.Message.Text)
+	}
+	wantProperties := map[string]interface{}{
+		"testfile": "main.tftest.hcl",
+		"testrun":  "validate_test_resource",
+	}
+	if diff := cmp.Diff(wantProperties, result.Properties); diff != "" {
+		t.Errorf("wrong properties\n%s", diff)
+	}
+	if len(result.Locations) != 1 || result.Locations[0].PhysicalLocation.ArtifactLocation.URI != "main.tftest.hcl" {
+		t.Errorf("wrong locations %#v", result.Locations)
+	}
+
+	if provider.ResourceCount() > 0 {
+		t.Errorf("should have deleted all resources on completion but left %v", provider.ResourceString())
+	}
+}
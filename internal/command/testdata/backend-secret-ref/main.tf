@@ -0,0 +1,5 @@
+terraform {
+    backend "local" {
+        path = "vault:secret/data/tofu#state_path"
+    }
+}
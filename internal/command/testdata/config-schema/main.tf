@@ -0,0 +1,34 @@
+terraform {
+  required_providers {
+    test = {
+      source  = "hashicorp/test"
+      version = "~> 1.0"
+    }
+  }
+}
+
+variable "name" {
+  type        = string
+  description = "The name of the instance."
+
+  validation {
+    condition     = length(var.name) > 0
+    error_message = "The name must not be empty."
+  }
+}
+
+variable "tags" {
+  type = object({
+    env   = string
+    owner = optional(string)
+  })
+  default = {
+    env = "dev"
+  }
+  sensitive = true
+}
+
+output "id" {
+  value       = "example"
+  description = "The ID of the instance."
+}
@@ -0,0 +1,15 @@
+root "network" {
+  path = "network"
+}
+
+root "dns" {
+  path = "dns"
+}
+
+root "app" {
+  path       = "app"
+  depends_on = ["dns"]
+  inputs = {
+    vpc_id = output.network.vpc_id
+  }
+}
@@ -0,0 +1,20 @@
+variable "healthy" {
+  type    = bool
+  default = false
+}
+
+check "health" {
+  assert {
+    condition     = var.healthy
+    error_message = "The service is not healthy."
+  }
+}
+
+output "answer" {
+  value = var.healthy ? 0 : 42
+
+  precondition {
+    condition     = !var.healthy
+    error_message = "Unreachable."
+  }
+}
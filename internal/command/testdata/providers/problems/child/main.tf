@@ -0,0 +1,10 @@
+terraform {
+  required_providers {
+    foo = {
+      source  = "hashicorp/foo"
+      version = "< 2.0"
+    }
+  }
+}
+
+resource "foo_instance" "example" {}
@@ -0,0 +1,17 @@
+terraform {
+  required_providers {
+    foo = {
+      source  = "hashicorp/foo"
+      version = ">= 2.0"
+    }
+    unused = {
+      source = "hashicorp/unused"
+    }
+  }
+}
+
+resource "foo_instance" "example" {}
+
+module "child" {
+  source = "./child"
+}
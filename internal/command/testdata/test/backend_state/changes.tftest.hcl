@@ -0,0 +1,9 @@
+run "changes" {
+  command = plan
+
+  variables {
+    value = "baz"
+  }
+
+  expect_no_changes = true
+}
@@ -0,0 +1,9 @@
+variable "value" {
+  type    = string
+  default = "bar"
+}
+
+resource "test_resource" "foo" {
+  id    = "foo"
+  value = var.value
+}
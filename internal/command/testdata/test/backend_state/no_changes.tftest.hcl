@@ -0,0 +1,10 @@
+run "no_changes" {
+  command = plan
+
+  expect_no_changes = true
+
+  assert {
+    condition     = test_resource.foo.value == "bar"
+    error_message = "invalid value"
+  }
+}
@@ -0,0 +1,22 @@
+variable "enabled" {
+  type    = bool
+  default = false
+}
+
+variable "unused" {
+  type    = string
+  default = "unused"
+}
+
+resource "test_resource" "foo" {
+  value = "foo"
+}
+
+resource "test_resource" "optional" {
+  count = var.enabled ? 1 : 0
+  value = "optional"
+}
+
+output "id" {
+  value = test_resource.foo.id
+}
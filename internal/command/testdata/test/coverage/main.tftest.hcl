@@ -0,0 +1,19 @@
+run "disabled" {
+  command = plan
+
+  assert {
+    condition     = length(test_resource.optional) == 0
+    error_message = "optional resource should be disabled"
+  }
+}
+
+run "enabled" {
+  variables {
+    enabled = true
+  }
+
+  assert {
+    condition     = length(test_resource.optional) == 1
+    error_message = "optional resource should be enabled"
+  }
+}
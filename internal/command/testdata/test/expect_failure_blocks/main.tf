@@ -0,0 +1,12 @@
+variable "input" {
+  type = string
+
+  validation {
+    condition     = var.input == "something very specific"
+    error_message = "The input must be something very specific."
+  }
+}
+
+resource "test_resource" "resource" {
+  value = var.input
+}
@@ -0,0 +1,22 @@
+variables {
+  input = "some value"
+}
+
+run "object" {
+  command = plan
+
+  expect_failure {
+    object = var.input
+    detail = "must be something very specific"
+  }
+}
+
+run "location" {
+  command = plan
+
+  expect_failure {
+    summary  = "^Invalid value for variable$"
+    filename = "main.tf"
+    line     = 1
+  }
+}
@@ -0,0 +1,10 @@
+variables {
+  value = "bar"
+}
+
+assertions "value_is_set" {
+  assert {
+    condition     = test_resource.foo.value == var.value
+    error_message = "invalid value"
+  }
+}
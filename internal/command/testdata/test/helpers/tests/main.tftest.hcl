@@ -0,0 +1,23 @@
+imports = ["common"]
+
+before_each {
+  assertions = ["value_is_set"]
+}
+
+run "imported_variables" {
+  assert {
+    condition     = var.value == "bar"
+    error_message = "expected the imported variable"
+  }
+}
+
+run "own_variables" {
+  variables {
+    value = "baz"
+  }
+
+  assert {
+    condition     = var.value == "baz"
+    error_message = "expected the run block's variable"
+  }
+}
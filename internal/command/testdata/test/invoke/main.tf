@@ -0,0 +1,4 @@
+resource "test_resource" "foo" {
+  id    = "i-abc123"
+  value = "bar"
+}
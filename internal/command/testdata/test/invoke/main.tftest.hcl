@@ -0,0 +1,11 @@
+run "reboot" {
+  invoke "test_reboot" {
+    id     = test_resource.foo.id
+    reason = "maintenance"
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "bar"
+    error_message = "invalid value"
+  }
+}
@@ -0,0 +1,8 @@
+variable "value" {
+  type    = string
+  default = "bar"
+}
+
+resource "test_resource" "foo" {
+  value = var.value
+}
@@ -0,0 +1,13 @@
+run "pass" {
+  assert {
+    condition     = test_resource.foo.value == "bar"
+    error_message = "invalid value"
+  }
+}
+
+run "fail" {
+  assert {
+    condition     = test_resource.foo.value == "zap"
+    error_message = "invalid value"
+  }
+}
@@ -0,0 +1,13 @@
+run "error" {
+  assert {
+    condition     = test_resource.foo.value == "bar" ? null : true
+    error_message = "invalid condition"
+  }
+}
+
+run "skipped" {
+  assert {
+    condition     = test_resource.foo.value == "bar"
+    error_message = "invalid value"
+  }
+}
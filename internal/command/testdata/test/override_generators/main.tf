@@ -0,0 +1,8 @@
+resource "test_resource" "foo" {
+  count = 2
+  value = "foo"
+}
+
+data "test_data_source" "bar" {
+  id = "bar"
+}
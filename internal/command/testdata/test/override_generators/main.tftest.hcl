@@ -0,0 +1,32 @@
+override_resource {
+  target = test_resource.foo
+  generators = {
+    id = { type = "sequence", prefix = "foo-" }
+  }
+}
+
+override_data {
+  target = data.test_data_source.bar
+  generators = {
+    value = "arn"
+  }
+}
+
+run "generated" {
+  assert {
+    condition     = toset(test_resource.foo[*].id) == toset(["foo-1", "foo-2"])
+    error_message = "invalid ids"
+  }
+
+  assert {
+    condition     = startswith(data.test_data_source.bar.value, "arn:test:data:us-east-1:123456789012:source/")
+    error_message = "invalid arn"
+  }
+}
+
+run "stable" {
+  assert {
+    condition     = toset(test_resource.foo[*].id) == toset(["foo-1", "foo-2"])
+    error_message = "ids changed between runs"
+  }
+}
@@ -0,0 +1,3 @@
+output "dir" {
+  value = abspath(path.module)
+}
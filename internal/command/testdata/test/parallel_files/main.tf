@@ -0,0 +1,15 @@
+resource "test_resource" "foo" {
+  value = "bar"
+}
+
+module "child" {
+  source = "./child"
+}
+
+output "dir" {
+  value = abspath(path.module)
+}
+
+output "child_dir" {
+  value = module.child.dir
+}
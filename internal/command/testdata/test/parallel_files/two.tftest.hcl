@@ -0,0 +1,16 @@
+run "validate_paths" {
+  assert {
+    condition     = output.dir != abspath(path.cwd)
+    error_message = "the configuration was not copied"
+  }
+
+  assert {
+    condition     = strcontains(file("${output.child_dir}/main.tf"), "path.module")
+    error_message = "the copy of the configuration is incomplete"
+  }
+
+  assert {
+    condition     = output.child_dir == "${output.dir}/child"
+    error_message = "the child module was not copied with the root module"
+  }
+}
@@ -0,0 +1,11 @@
+run "a" {
+  parallel = true
+}
+
+run "b" {
+  parallel = true
+
+  variables {
+    id = run.a.id
+  }
+}
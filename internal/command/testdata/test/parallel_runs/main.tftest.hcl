@@ -0,0 +1,40 @@
+run "main" {
+  parallel = true
+
+  variables {
+    input = "main"
+  }
+
+  assert {
+    condition     = output.value == "main"
+    error_message = "bad value"
+  }
+}
+
+run "setup" {
+  parallel = true
+
+  module {
+    source = "./setup"
+  }
+
+  variables {
+    input = "setup"
+  }
+
+  assert {
+    condition     = output.value == "setup"
+    error_message = "bad value"
+  }
+}
+
+run "after" {
+  variables {
+    input = run.setup.value
+  }
+
+  assert {
+    condition     = output.value == "setup"
+    error_message = "bad value"
+  }
+}
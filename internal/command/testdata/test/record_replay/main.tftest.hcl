@@ -0,0 +1,21 @@
+run "create" {
+  variables {
+    value = "one"
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "one"
+    error_message = "invalid value"
+  }
+}
+
+run "update" {
+  variables {
+    value = "two"
+  }
+
+  assert {
+    condition     = test_resource.foo.value == "two"
+    error_message = "invalid value"
+  }
+}
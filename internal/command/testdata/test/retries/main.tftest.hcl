@@ -0,0 +1,23 @@
+run "first" {
+  assert {
+    condition     = test_resource.foo.value == "bar"
+    error_message = "invalid value"
+  }
+}
+
+run "flaky" {
+  retries     = 2
+  retry_delay = "0s"
+
+  assert {
+    condition     = test_resource.foo.value == "zap"
+    error_message = "invalid value"
+  }
+}
+
+run "last" {
+  assert {
+    condition     = test_resource.foo.value == "bar"
+    error_message = "invalid value"
+  }
+}
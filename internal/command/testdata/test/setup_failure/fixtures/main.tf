@@ -0,0 +1,16 @@
+variable "input" {
+  type = string
+}
+
+resource "test_resource" "resource" {
+  value = var.input
+}
+
+output "value" {
+  value = test_resource.resource.value
+
+  precondition {
+    condition     = var.input == "valid"
+    error_message = "invalid input"
+  }
+}
@@ -0,0 +1,25 @@
+setup {
+  module {
+    source = "./fixtures"
+  }
+
+  variables {
+    input = "invalid"
+  }
+}
+
+run "test" {
+  variables {
+    input = run.setup.value
+  }
+}
+
+teardown {
+  module {
+    source = "./cleanup"
+  }
+
+  variables {
+    input = "cleanup"
+  }
+}
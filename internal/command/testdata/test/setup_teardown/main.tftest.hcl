@@ -0,0 +1,41 @@
+setup {
+  module {
+    source = "./fixtures"
+  }
+
+  variables {
+    input = "fixture"
+  }
+}
+
+run "test" {
+  variables {
+    input = run.setup.value
+  }
+
+  assert {
+    condition     = output.value == "fixture"
+    error_message = "bad value"
+  }
+}
+
+run "fails" {
+  variables {
+    input = "other"
+  }
+
+  assert {
+    condition     = output.value == "fixture"
+    error_message = "bad value"
+  }
+}
+
+teardown {
+  module {
+    source = "./cleanup"
+  }
+
+  variables {
+    input = "cleanup"
+  }
+}
@@ -0,0 +1,12 @@
+variable "prefix" {
+  type = string
+}
+
+variable "count_value" {
+  type = number
+}
+
+resource "test_resource" "foo" {
+  count = var.count_value
+  value = "${var.prefix}-${count.index}"
+}
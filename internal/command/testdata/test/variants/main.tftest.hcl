@@ -0,0 +1,16 @@
+run "matrix" {
+  variants {
+    prefix      = ["a", "b"]
+    count_value = [1, 2]
+  }
+
+  assert {
+    condition     = length(test_resource.foo) == var.count_value
+    error_message = "wrong number of resources"
+  }
+
+  assert {
+    condition     = test_resource.foo[0].value == "${var.prefix}-0"
+    error_message = "wrong value"
+  }
+}
@@ -0,0 +1,11 @@
+run "quoted_keywords" {
+  command = "apply"
+
+  plan_options {
+    mode = "refresh-only"
+  }
+}
+
+run "unquotable" {
+  command = "not a keyword"
+}
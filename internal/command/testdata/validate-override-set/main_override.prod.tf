@@ -0,0 +1,3 @@
+resource "test_instance" "bar" {
+  ami = "baz"
+}
@@ -0,0 +1,7 @@
+terraform {
+  strict = ["deprecations"]
+}
+
+provider "test" {
+  version = "1.0.0"
+}
@@ -7,8 +7,10 @@ package command
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 
@@ -26,10 +28,12 @@ type UnlockCommand struct {
 
 func (c *UnlockCommand) Run(args []string) int {
 	args = c.Meta.process(args)
-	var force bool
+	var force, show, jsonOutput bool
 	cmdFlags := c.Meta.defaultFlagSet("force-unlock")
 	c.Meta.varFlagSet(cmdFlags)
 	cmdFlags.BoolVar(&force, "force", false, "force")
+	cmdFlags.BoolVar(&show, "show", false, "show")
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "json")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -37,13 +41,24 @@ func (c *UnlockCommand) Run(args []string) int {
 	}
 
 	args = cmdFlags.Args()
-	if len(args) != 1 {
-		c.Ui.Error("Expected a single argument: LOCK_ID")
+	if jsonOutput && !show {
+		c.Ui.Error("The -json option can only be used together with -show.")
 		return cli.RunResultHelp
 	}
-
-	lockID := args[0]
-	args = args[1:]
+	var lockID string
+	if show {
+		if len(args) != 0 {
+			c.Ui.Error("The -show option does not take a LOCK_ID argument")
+			return cli.RunResultHelp
+		}
+	} else {
+		if len(args) != 1 {
+			c.Ui.Error("Expected a single argument: LOCK_ID")
+			return cli.RunResultHelp
+		}
+		lockID = args[0]
+		args = args[1:]
+	}
 
 	// assume everything is initialized. The user can manually init if this is
 	// required.
@@ -93,6 +108,10 @@ func (c *UnlockCommand) Run(args []string) int {
 		return 1
 	}
 
+	if show {
+		return c.showLock(stateMgr, jsonOutput)
+	}
+
 	_, isLocal := stateMgr.(*statemgr.Filesystem)
 
 	if !force {
@@ -131,9 +150,68 @@ func (c *UnlockCommand) Run(args []string) int {
 	return 0
 }
 
+// showLock prints the information about the lock currently held on the
+// given state, without releasing it.
+func (c *UnlockCommand) showLock(stateMgr statemgr.Locker, jsonOutput bool) int {
+	info, err := statemgr.InspectLock(stateMgr)
+	if err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to inspect the state lock: %s", err))
+		return 1
+	}
+
+	if jsonOutput {
+		out := lockStatusJSON{Locked: info != nil}
+		if info != nil {
+			out.Lock = &lockInfoJSON{
+				ID:        info.ID,
+				Path:      info.Path,
+				Operation: info.Operation,
+				Who:       info.Who,
+				Version:   info.Version,
+				Created:   info.Created,
+				Info:      info.Info,
+			}
+		}
+		js, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal the state lock: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(js))
+		return 0
+	}
+
+	if info == nil {
+		c.Ui.Output("The state is not locked.")
+		return 0
+	}
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
+		"[reset][bold]The state is locked.[reset]\n\n%s\nTo release the lock, run \"tofu force-unlock %s\".",
+		info.String(), info.ID,
+	)))
+	return 0
+}
+
+// lockStatusJSON is the output of force-unlock -show -json.
+type lockStatusJSON struct {
+	Locked bool          `json:"locked"`
+	Lock   *lockInfoJSON `json:"lock,omitempty"`
+}
+
+type lockInfoJSON struct {
+	ID        string    `json:"id"`
+	Path      string    `json:"path,omitempty"`
+	Operation string    `json:"operation"`
+	Who       string    `json:"who"`
+	Version   string    `json:"version"`
+	Created   time.Time `json:"created"`
+	Info      string    `json:"info,omitempty"`
+}
+
 func (c *UnlockCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] force-unlock LOCK_ID
+       tofu [global options] force-unlock -show [-json]
 
   Manually unlock the state for the defined configuration.
 
@@ -142,9 +220,17 @@ Usage: tofu [global options] force-unlock LOCK_ID
   on the backend being used. Local state files cannot be unlocked by another
   process.
 
+  With -show, the current lock is printed without removing it, so that you
+  can check who holds it before deciding whether to force-unlock it.
+
 Options:
 
   -force                 Don't ask for input for unlock confirmation.
+
+  -show                  Show the current lock on the state instead of
+                         removing it.
+
+  -json                  With -show, print the lock as JSON.
 `
 	return strings.TrimSpace(helpText)
 }
@@ -6,7 +6,9 @@
 package command
 
 import (
+	"encoding/json"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/mitchellh/cli"
@@ -121,3 +123,99 @@ func TestUnlock_inmemBackend(t *testing.T) {
 	}
 
 }
+
+func TestUnlock_show(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("backend-inmem-locked"), td)
+	defer testChdir(t, td)()
+	defer inmem.Reset()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	ci := &InitCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+	if code := ci.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n%s", code, ui.ErrorWriter)
+	}
+
+	const lockID = "2b6a6738-5dd5-50d6-c0ae-f6352977666b"
+	show := func(args ...string) string {
+		t.Helper()
+		ui := new(cli.MockUi)
+		c := &UnlockCommand{
+			Meta: Meta{
+				Ui:   ui,
+				View: view,
+			},
+		}
+		if code := c.Run(args); code != 0 {
+			t.Fatalf("bad: %d\n%s\n%s", code, ui.OutputWriter.String(), ui.ErrorWriter.String())
+		}
+		return ui.OutputWriter.String()
+	}
+
+	output := show("-show")
+	if !strings.Contains(output, "The state is locked.") || !strings.Contains(output, "ID:        "+lockID) {
+		t.Fatalf("wrong output\n%s", output)
+	}
+
+	var got lockStatusJSON
+	if err := json.Unmarshal([]byte(show("-show", "-json")), &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Locked || got.Lock == nil || got.Lock.ID != lockID || got.Lock.Operation != "test" {
+		t.Fatalf("wrong JSON output: %#v", got)
+	}
+
+	// Showing the lock must not release it.
+	if output := show("-show"); !strings.Contains(output, "The state is locked.") {
+		t.Fatalf("wrong output\n%s", output)
+	}
+}
+
+func TestUnlock_showUnlocked(t *testing.T) {
+	td := t.TempDir()
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &UnlockCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+	if code := c.Run([]string{"-show", "-json"}); code != 0 {
+		t.Fatalf("bad: %d\n%s\n%s", code, ui.OutputWriter.String(), ui.ErrorWriter.String())
+	}
+	var got lockStatusJSON
+	if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Locked || got.Lock != nil {
+		t.Fatalf("wrong JSON output: %#v", got)
+	}
+
+	// The local state file must not be left behind by the inspection.
+	if _, err := os.Stat(DefaultStateFilename); !os.IsNotExist(err) {
+		t.Fatalf("state file was created while inspecting the lock: %v", err)
+	}
+
+	// -json is only valid with -show, and -show takes no lock ID.
+	for _, args := range [][]string{{"-json", "LOCK_ID"}, {"-show", "LOCK_ID"}} {
+		ui := new(cli.MockUi)
+		c := &UnlockCommand{
+			Meta: Meta{
+				Ui:   ui,
+				View: view,
+			},
+		}
+		if code := c.Run(args); code != cli.RunResultHelp {
+			t.Fatalf("bad: %d for %q\n%s", code, args, ui.ErrorWriter.String())
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/posener/complete"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/clistate"
@@ -24,11 +26,13 @@ type UntaintCommand struct {
 	Meta
 }
 
-func (c *UntaintCommand) Run(args []string) int {
+func (c *UntaintCommand) Run(args []string) (status int) {
 	args = c.Meta.process(args)
-	var allowMissing bool
+	var allowMissing, autoApprove bool
 	cmdFlags := c.Meta.ignoreRemoteVersionFlagSet("untaint")
 	cmdFlags.BoolVar(&allowMissing, "allow-missing", false, "allow missing")
+	cmdFlags.BoolVar(&autoApprove, "auto-approve", false, "skip confirmation of pattern matches")
+	cmdFlags.BoolVar(&c.Meta.input, "input", true, "input")
 	cmdFlags.StringVar(&c.Meta.backupPath, "backup", "", "path")
 	cmdFlags.BoolVar(&c.Meta.stateLock, "lock", true, "lock state")
 	cmdFlags.DurationVar(&c.Meta.stateLockTimeout, "lock-timeout", 0, "lock timeout")
@@ -50,11 +54,29 @@ func (c *UntaintCommand) Run(args []string) int {
 		return 1
 	}
 
-	addr, addrDiags := addrs.ParseAbsResourceInstanceStr(args[0])
-	diags = diags.Append(addrDiags)
-	if addrDiags.HasErrors() {
-		c.showDiagnostics(diags)
-		return 1
+	// The argument is either a single resource instance address, or a pattern
+	// which can match several instances.
+	var addr addrs.AbsResourceInstance
+	var pattern *addrs.AbsResourceInstancePattern
+	var target fmt.Stringer
+	if addrs.IsResourceInstancePattern(args[0]) {
+		p, patternDiags := addrs.ParseAbsResourceInstancePattern(args[0])
+		diags = diags.Append(patternDiags)
+		if patternDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		pattern = &p
+		target = p
+	} else {
+		var addrDiags tfdiags.Diagnostics
+		addr, addrDiags = addrs.ParseAbsResourceInstanceStr(args[0])
+		diags = diags.Append(addrDiags)
+		if addrDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		target = addr
 	}
 
 	// Load the encryption configuration
@@ -95,6 +117,9 @@ func (c *UntaintCommand) Run(args []string) int {
 		return 1
 	}
 
+	audit := c.auditBegin("untaint", stateMgr)
+	defer audit.finish(&status)
+
 	if c.stateLock {
 		stateLocker := clistate.NewLocker(c.stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
 		if diags := stateLocker.Lock(stateMgr, "untaint"); diags.HasErrors() {
@@ -117,7 +142,7 @@ func (c *UntaintCommand) Run(args []string) int {
 	state := stateMgr.State()
 	if state.Empty() {
 		if allowMissing {
-			return c.allowMissingExit(addr)
+			return c.allowMissingExit(target)
 		}
 
 		diags = diags.Append(tfdiags.Sourceless(
@@ -131,51 +156,79 @@ func (c *UntaintCommand) Run(args []string) int {
 
 	ss := state.SyncWrapper()
 
-	// Get the resource and instance we're going to taint
-	rs := ss.Resource(addr.ContainingResource())
-	is := ss.ResourceInstance(addr)
-	if is == nil {
-		if allowMissing {
-			return c.allowMissingExit(addr)
+	targets := []addrs.AbsResourceInstance{addr}
+	if pattern != nil {
+		targets = stateInstancesMatchingPattern(state, *pattern, states.ObjectTainted)
+		if len(targets) == 0 {
+			if allowMissing {
+				return c.allowMissingExit(pattern)
+			}
+
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"No matching resource instances",
+				fmt.Sprintf("There are no tainted resource instances in the state matching %s.", pattern),
+			))
+			c.showDiagnostics(diags)
+			return 1
 		}
 
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"No such resource instance",
-			fmt.Sprintf("There is no resource instance in the state with the address %s. If the resource configuration has just been added, you must run \"tofu apply\" once to create the corresponding instance(s) before they can be tainted.", addr),
-		))
-		c.showDiagnostics(diags)
-		return 1
-	}
+		confirmed, confirmDiags := c.confirmPatternTargets("untaint", *pattern, targets, autoApprove)
+		diags = diags.Append(confirmDiags)
+		if confirmDiags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		if !confirmed {
+			c.Ui.Error("Untaint cancelled.")
+			return 1
+		}
+	} else {
+		// Get the resource and instance we're going to taint
+		is := ss.ResourceInstance(addr)
+		if is == nil {
+			if allowMissing {
+				return c.allowMissingExit(addr)
+			}
 
-	obj := is.Current
-	if obj == nil {
-		if len(is.Deposed) != 0 {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
 				"No such resource instance",
-				fmt.Sprintf("Resource instance %s is currently part-way through a create_before_destroy replacement action. Run \"tofu apply\" to complete its replacement before tainting it.", addr),
+				fmt.Sprintf("There is no resource instance in the state with the address %s. If the resource configuration has just been added, you must run \"tofu apply\" once to create the corresponding instance(s) before they can be tainted.", addr),
 			))
-		} else {
-			// Don't know why we're here, but we'll produce a generic error message anyway.
+			c.showDiagnostics(diags)
+			return 1
+		}
+
+		obj := is.Current
+		if obj == nil {
+			if len(is.Deposed) != 0 {
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"No such resource instance",
+					fmt.Sprintf("Resource instance %s is currently part-way through a create_before_destroy replacement action. Run \"tofu apply\" to complete its replacement before tainting it.", addr),
+				))
+			} else {
+				// Don't know why we're here, but we'll produce a generic error message anyway.
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"No such resource instance",
+					fmt.Sprintf("Resource instance %s does not currently have a remote object associated with it, so it cannot be tainted.", addr),
+				))
+			}
+			c.showDiagnostics(diags)
+			return 1
+		}
+
+		if obj.Status != states.ObjectTainted {
 			diags = diags.Append(tfdiags.Sourceless(
 				tfdiags.Error,
-				"No such resource instance",
-				fmt.Sprintf("Resource instance %s does not currently have a remote object associated with it, so it cannot be tainted.", addr),
+				"Resource instance is not tainted",
+				fmt.Sprintf("Resource instance %s is not currently tainted, and so it cannot be untainted.", addr),
 			))
+			c.showDiagnostics(diags)
+			return 1
 		}
-		c.showDiagnostics(diags)
-		return 1
-	}
-
-	if obj.Status != states.ObjectTainted {
-		diags = diags.Append(tfdiags.Sourceless(
-			tfdiags.Error,
-			"Resource instance is not tainted",
-			fmt.Sprintf("Resource instance %s is not currently tainted, and so it cannot be untainted.", addr),
-		))
-		c.showDiagnostics(diags)
-		return 1
 	}
 
 	// Get schemas, if possible, before writing state
@@ -186,8 +239,12 @@ func (c *UntaintCommand) Run(args []string) int {
 		diags = diags.Append(schemaDiags)
 	}
 
-	obj.Status = states.ObjectReady
-	ss.SetResourceInstanceCurrent(addr, obj, rs.ProviderConfig)
+	for _, addr := range targets {
+		rs := ss.Resource(addr.ContainingResource())
+		obj := ss.ResourceInstance(addr).Current
+		obj.Status = states.ObjectReady
+		ss.SetResourceInstanceCurrent(addr, obj, rs.ProviderConfig)
+	}
 
 	if err := stateMgr.WriteState(state); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error writing state file: %s", err))
@@ -199,10 +256,28 @@ func (c *UntaintCommand) Run(args []string) int {
 	}
 
 	c.showDiagnostics(diags)
-	c.Ui.Output(fmt.Sprintf("Resource instance %s has been successfully untainted.", addr))
+	for _, addr := range targets {
+		c.Ui.Output(fmt.Sprintf("Resource instance %s has been successfully untainted.", addr))
+	}
 	return 0
 }
 
+func (c *UntaintCommand) AutocompleteArgs() complete.Predictor {
+	return completePredictSequence{
+		c.completePredictStateAddress(),
+	}
+}
+
+func (c *UntaintCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-allow-missing": complete.PredictNothing,
+		"-auto-approve":  complete.PredictNothing,
+		"-input":         completePredictBoolean,
+		"-lock":          completePredictBoolean,
+		"-lock-timeout":  complete.PredictAnything,
+	}
+}
+
 func (c *UntaintCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] untaint [options] name
@@ -219,11 +294,21 @@ Usage: tofu [global options] untaint [options] name
   This will not modify your infrastructure directly. It only avoids
   OpenTofu planning to replace a tainted instance in a future operation.
 
+  The name can also be a pattern containing "*" wildcards, such as
+  module.app.aws_instance.web[*], which untaints every matching tainted
+  resource instance after listing them for confirmation.
+
 Options:
 
   -allow-missing          If specified, the command will succeed (exit code 0)
                           even if the resource is missing.
 
+  -auto-approve           Skip the confirmation of the resource instances
+                          matched by a pattern.
+
+  -input=false            Disable the confirmation prompt. A pattern can then
+                          only be used together with -auto-approve.
+
   -lock=false             Don't hold a state lock during the operation. This is
                           dangerous if others might concurrently run commands
                           against the same workspace.
@@ -244,7 +329,7 @@ func (c *UntaintCommand) Synopsis() string {
 	return "Remove the 'tainted' state from a resource instance"
 }
 
-func (c *UntaintCommand) allowMissingExit(name addrs.AbsResourceInstance) int {
+func (c *UntaintCommand) allowMissingExit(name fmt.Stringer) int {
 	c.showDiagnostics(tfdiags.Sourceless(
 		tfdiags.Warning,
 		"No such resource instance",
@@ -535,3 +535,63 @@ module.child:
     provider = provider["registry.opentofu.org/hashicorp/test"]
 	`))
 }
+
+func TestUntaint_pattern(t *testing.T) {
+	statePath := testStateFile(t, testTaintPatternState(addrs.IntKey(0), addrs.IntKey(1), addrs.IntKey(2)))
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &UntaintCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"-auto-approve",
+		"test_instance.foo[*]",
+	}
+	if code := c.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter.String())
+	}
+
+	state := testStateRead(t, statePath)
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for key, is := range rs.Instances {
+				if is.Current.Status != states.ObjectReady {
+					t.Errorf("%s is still tainted", rs.Addr.Instance(key))
+				}
+			}
+		}
+	}
+	if got, want := ui.OutputWriter.String(), "Resource instance test_instance.foo[2] has been successfully untainted."; !strings.Contains(got, want) {
+		t.Errorf("output does not contain %q\n%s", want, got)
+	}
+}
+
+func TestUntaint_patternNoInput(t *testing.T) {
+	statePath := testStateFile(t, testTaintPatternState(addrs.IntKey(0)))
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	c := &UntaintCommand{
+		Meta: Meta{
+			Ui:   ui,
+			View: view,
+		},
+	}
+
+	args := []string{
+		"-state", statePath,
+		"test_instance.foo[*]",
+	}
+	if code := c.Run(args); code != 1 {
+		t.Fatalf("wrong exit status %d; want 1", code)
+	}
+	if got, want := ui.ErrorWriter.String(), "Use -auto-approve"; !strings.Contains(got, want) {
+		t.Fatalf("error output does not contain %q\n%s", want, got)
+	}
+}
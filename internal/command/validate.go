@@ -8,6 +8,7 @@ package command
 import (
 	"fmt"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -26,10 +27,12 @@ type ValidateCommand struct {
 func (c *ValidateCommand) Run(rawArgs []string) int {
 	// Parse and apply global view arguments
 	common, rawArgs := arguments.ParseView(rawArgs)
+	rawArgs = c.Meta.processOverrideSet(rawArgs)
 	c.View.Configure(common)
 
 	// Parse and validate flags
 	args, diags := arguments.ParseValidate(rawArgs)
+	diags = diags.Append(common.Parse())
 	if diags.HasErrors() {
 		c.View.Diagnostics(diags)
 		c.View.HelpPrompt("validate")
@@ -56,7 +59,7 @@ func (c *ValidateCommand) Run(rawArgs []string) int {
 		return view.Results(diags)
 	}
 
-	validateDiags := c.validate(dir, args.TestDirectory, args.NoTests)
+	validateDiags := c.validate(dir, args)
 	diags = diags.Append(validateDiags)
 
 	// Validating with dev overrides in effect means that the result might
@@ -68,19 +71,23 @@ func (c *ValidateCommand) Run(rawArgs []string) int {
 	return view.Results(diags)
 }
 
-func (c *ValidateCommand) validate(dir, testDir string, noTests bool) tfdiags.Diagnostics {
+func (c *ValidateCommand) validate(dir string, args *arguments.Validate) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 	var cfg *configs.Config
 
-	if noTests {
+	if args.NoTests {
 		cfg, diags = c.loadConfig(dir)
 	} else {
-		cfg, diags = c.loadConfigWithTests(dir, testDir)
+		cfg, diags = c.loadConfigWithTests(dir, args.TestDirectory)
 	}
 	if diags.HasErrors() {
 		return diags
 	}
 
+	if args.DetectSecrets {
+		diags = diags.Append(c.detectSecrets(cfg))
+	}
+
 	validate := func(cfg *configs.Config) tfdiags.Diagnostics {
 		var diags tfdiags.Diagnostics
 
@@ -101,8 +108,14 @@ func (c *ValidateCommand) validate(dir, testDir string, noTests bool) tfdiags.Di
 
 	diags = diags.Append(validate(cfg))
 
-	if noTests {
-		return diags
+	// Strict mode turns the selected categories of warnings into errors.
+	strict := args.Strict
+	if cfg.Module.Strict != nil {
+		strict = append(strict, cfg.Module.Strict.Categories...)
+	}
+
+	if args.NoTests {
+		return diags.Strict(strict)
 	}
 
 	validatedModules := make(map[string]bool)
@@ -113,7 +126,7 @@ func (c *ValidateCommand) validate(dir, testDir string, noTests bool) tfdiags.Di
 
 		diags = diags.Append(file.Validate())
 
-		for _, run := range file.Runs {
+		for _, run := range file.AllRuns() {
 
 			if run.Module != nil {
 				// Then we can also validate the referenced modules, but we are
@@ -141,6 +154,42 @@ func (c *ValidateCommand) validate(dir, testDir string, noTests bool) tfdiags.Di
 		}
 	}
 
+	return diags.Strict(strict)
+}
+
+// detectSecrets looks for strings that appear to be secrets in the files of
+// the root module and of the local modules it calls, directly or indirectly.
+// Modules installed from elsewhere are the responsibility of their authors.
+func (c *ValidateCommand) detectSecrets(cfg *configs.Config) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	loader, err := c.initConfigLoader()
+	if err != nil {
+		return diags.Append(err)
+	}
+	parser := loader.Parser()
+
+	seen := make(map[string]bool)
+	var detect func(cfg *configs.Config)
+	detect = func(cfg *configs.Config) {
+		if dir := cfg.Module.SourceDir; !seen[dir] {
+			seen[dir] = true
+			diags = diags.Append(parser.DetectSecretsInDir(dir))
+		}
+		names := make([]string, 0, len(cfg.Children))
+		for name := range cfg.Children {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			child := cfg.Children[name]
+			if _, ok := child.SourceAddr.(addrs.ModuleSourceLocal); ok {
+				detect(child)
+			}
+		}
+	}
+	detect(cfg)
+
 	return diags
 }
 
@@ -176,14 +225,35 @@ Usage: tofu [global options] validate [options]
 
 Options:
 
+  -format=sarif         Produce the diagnostics as a SARIF log, for code
+                        scanning tools such as those of GitHub and GitLab.
+                        The format can also be "human" (the default) or
+                        "json", which is the same as the -json option.
+
   -json                 Produce output in a machine-readable JSON format, 
                         suitable for use in text editor integrations and other 
                         automated systems. Always disables color.
 
   -no-color             If specified, output won't contain any color.
 
+  -override-set=name    Also load the environment-scoped override files of
+                        the given set, named like "*_override.name.tf".
+                        Defaults to the TF_OVERRIDE_SET environment variable.
+
   -no-tests             If specified, OpenTofu will not validate test files.
 
+  -detect-secrets       Also warn about strings in the configuration files and
+                        variable definitions files that look like secrets,
+                        such as access keys, private keys and other tokens.
+                        Add a "tofu:allow-secret" comment on the same line or
+                        the line before to silence the warning for a string.
+
+  -strict               Treat warnings in any of the warning categories as
+                        errors. Use -strict=category,... to select only some
+                        of the categories: deprecations,
+                        implicit_provider_inheritance, and
+                        undeclared_var_files.
+
   -test-directory=path  Set the OpenTofu test directory, defaults to "tests". When set, the
                         test command will search for test files in the current directory and
                         in the one specified by the flag.
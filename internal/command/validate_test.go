@@ -122,6 +122,118 @@ func TestValidateFailingCommandMissingVariable(t *testing.T) {
 	}
 }
 
+func TestValidateOverrideSet(t *testing.T) {
+	const summary = "Missing resource to override"
+
+	t.Run("not selected", func(t *testing.T) {
+		output, code := setupTest(t, "validate-override-set", "-override-set=dev")
+		if code != 0 {
+			t.Fatalf("unexpected non-successful exit code %d\n\n%s", code, output.Stderr())
+		}
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		output, code := setupTest(t, "validate-override-set", "-override-set=prod")
+		if code != 1 {
+			t.Fatalf("Should have failed: %d\n\n%s", code, output.Stderr())
+		}
+		if want := "Error: " + summary; !strings.Contains(output.Stderr(), want) {
+			t.Fatalf("Missing error string %q\n\n'%s'", want, output.Stderr())
+		}
+	})
+
+	t.Run("environment variable", func(t *testing.T) {
+		t.Setenv(OverrideSetEnvVar, "prod")
+		output, code := setupTest(t, "validate-override-set")
+		if code != 1 {
+			t.Fatalf("Should have failed: %d\n\n%s", code, output.Stderr())
+		}
+		if want := "Error: " + summary; !strings.Contains(output.Stderr(), want) {
+			t.Fatalf("Missing error string %q\n\n'%s'", want, output.Stderr())
+		}
+	})
+
+	t.Run("invalid name", func(t *testing.T) {
+		output, code := setupTest(t, "validate-override-set", "-override-set=prod.eu")
+		if code != 1 {
+			t.Fatalf("Should have failed: %d\n\n%s", code, output.Stderr())
+		}
+		if want := `Invalid override set name "prod.eu"`; !strings.Contains(output.Stderr(), want) {
+			t.Fatalf("Missing error string %q\n\n'%s'", want, output.Stderr())
+		}
+	})
+}
+
+func TestValidateStrict(t *testing.T) {
+	const summary = "Version constraints inside provider configuration blocks are deprecated"
+
+	t.Run("warning", func(t *testing.T) {
+		output, code := setupTest(t, "validate-strict")
+		if code != 0 {
+			t.Fatalf("unexpected non-successful exit code %d\n\n%s", code, output.Stderr())
+		}
+		if want := "Warning: " + summary; !strings.Contains(output.Stdout(), want) {
+			t.Fatalf("Missing warning string %q\n\n'%s'", want, output.Stdout())
+		}
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		output, code := setupTest(t, "validate-strict", "-strict=deprecations")
+		if code != 1 {
+			t.Fatalf("Should have failed: %d\n\n%s", code, output.Stderr())
+		}
+		if want := "Error: " + summary; !strings.Contains(output.Stderr(), want) {
+			t.Fatalf("Missing error string %q\n\n'%s'", want, output.Stderr())
+		}
+	})
+
+	t.Run("other category", func(t *testing.T) {
+		output, code := setupTest(t, "validate-strict", "-strict=undeclared_var_files")
+		if code != 0 {
+			t.Fatalf("unexpected non-successful exit code %d\n\n%s", code, output.Stderr())
+		}
+	})
+
+	t.Run("config", func(t *testing.T) {
+		output, code := setupTest(t, "validate-strict/config")
+		if code != 1 {
+			t.Fatalf("Should have failed: %d\n\n%s", code, output.Stderr())
+		}
+		if want := "Error: " + summary; !strings.Contains(output.Stderr(), want) {
+			t.Fatalf("Missing error string %q\n\n'%s'", want, output.Stderr())
+		}
+	})
+}
+
+func TestValidateDetectSecrets(t *testing.T) {
+	const summary = "Warning: Possible secret in configuration"
+
+	t.Run("disabled", func(t *testing.T) {
+		output, code := setupTest(t, "validate-secrets")
+		if code != 0 {
+			t.Fatalf("unexpected non-successful exit code %d\n\n%s", code, output.Stderr())
+		}
+		if strings.Contains(output.Stdout(), summary) {
+			t.Fatalf("Unexpected warning string %q\n\n'%s'", summary, output.Stdout())
+		}
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		output, code := setupTest(t, "validate-secrets", "-detect-secrets")
+		if code != 0 {
+			t.Fatalf("unexpected non-successful exit code %d\n\n%s", code, output.Stderr())
+		}
+		// The warning about the private key in terraform.tfvars is
+		// consolidated into the first one.
+		stdout := output.Stdout()
+		for _, want := range []string{summary, "an AWS access key ID", "(and one more similar warning elsewhere)"} {
+			if !strings.Contains(stdout, want) {
+				t.Fatalf("Missing warning string %q\n\n'%s'", want, stdout)
+			}
+		}
+	})
+}
+
 func TestSameProviderMutipleTimesShouldFail(t *testing.T) {
 	output, code := setupTest(t, "validate-invalid/multiple_providers")
 	if code != 1 {
@@ -9,12 +9,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"sort"
 	"strings"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/depsfile"
 	"github.com/opentofu/opentofu/internal/getproviders"
+	"github.com/opentofu/opentofu/internal/modsdir"
 )
 
 // VersionCommand is a Command implementation prints the version.
@@ -27,9 +30,30 @@ type VersionCommand struct {
 }
 
 type VersionOutput struct {
-	Version            string            `json:"terraform_version"`
-	Platform           string            `json:"platform"`
-	ProviderSelections map[string]string `json:"provider_selections"`
+	Version            string                         `json:"terraform_version"`
+	Platform           string                         `json:"platform"`
+	ProviderSelections map[string]string              `json:"provider_selections"`
+	ProviderLocks      map[string]VersionProviderLock `json:"provider_locks"`
+	Modules            []VersionModule                `json:"modules"`
+	BackendType        string                         `json:"backend_type"`
+	Workspace          string                         `json:"workspace,omitempty"`
+}
+
+// VersionProviderLock is the dependency lock file entry for a provider, as
+// included in VersionOutput.
+type VersionProviderLock struct {
+	Version     string   `json:"version"`
+	Constraints string   `json:"constraints,omitempty"`
+	Hashes      []string `json:"hashes"`
+}
+
+// VersionModule is a module installed in the working directory, as included
+// in VersionOutput.
+type VersionModule struct {
+	Key     string `json:"key"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+	Dir     string `json:"dir"`
 }
 
 func (c *VersionCommand) Help() string {
@@ -92,9 +116,20 @@ func (c *VersionCommand) Run(args []string) int {
 
 	if jsonOutput {
 		selectionsOutput := make(map[string]string)
+		locksOutput := make(map[string]VersionProviderLock)
 		for providerAddr, lock := range providerLocks {
 			version := lock.Version().String()
 			selectionsOutput[providerAddr.String()] = version
+
+			hashes := make([]string, len(lock.AllHashes()))
+			for i, hash := range lock.AllHashes() {
+				hashes[i] = hash.String()
+			}
+			locksOutput[providerAddr.String()] = VersionProviderLock{
+				Version:     version,
+				Constraints: getproviders.VersionConstraintsString(lock.VersionConstraints()),
+				Hashes:      hashes,
+			}
 		}
 
 		var versionOutput string
@@ -104,18 +139,35 @@ func (c *VersionCommand) Run(args []string) int {
 			versionOutput = c.Version
 		}
 
+		// The rest of the working directory information is also best-effort,
+		// so that this command still works in a directory that hasn't been
+		// initialized or that has some other problem.
+		workspace, err := c.Workspace()
+		if err != nil {
+			workspace = ""
+		}
+
 		output := VersionOutput{
 			Version:            versionOutput,
 			Platform:           c.Platform.String(),
 			ProviderSelections: selectionsOutput,
+			ProviderLocks:      locksOutput,
+			Modules:            c.installedModules(),
+			BackendType:        c.backendType(),
+			Workspace:          workspace,
 		}
 
-		jsonOutput, err := json.MarshalIndent(output, "", "  ")
-		if err != nil {
+		// Version constraints often contain ">" and "<", which we don't want
+		// to be escaped because this output isn't for embedding in HTML.
+		var jsonOutput bytes.Buffer
+		enc := json.NewEncoder(&jsonOutput)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(output); err != nil {
 			c.Ui.Error(fmt.Sprintf("\nError marshalling JSON: %s", err))
 			return 1
 		}
-		c.Ui.Output(string(jsonOutput))
+		c.Ui.Output(strings.TrimSuffix(jsonOutput.String(), "\n"))
 		return 0
 	} else {
 		c.Ui.Output(versionString.String())
@@ -132,6 +184,47 @@ func (c *VersionCommand) Run(args []string) int {
 	return 0
 }
 
+// installedModules returns the modules recorded in the manifest of the
+// modules installed by "tofu init", ordered by key.
+func (c *VersionCommand) installedModules() []VersionModule {
+	modules := []VersionModule{}
+	manifest, err := modsdir.ReadManifestSnapshotForDir(c.modulesDir())
+	if err != nil {
+		return modules
+	}
+	for key, record := range manifest {
+		if key == "" {
+			// The root module is always present and isn't installed.
+			continue
+		}
+		modules = append(modules, VersionModule{
+			Key:     key,
+			Source:  record.SourceAddr,
+			Version: record.VersionStr,
+			Dir:     record.Dir,
+		})
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i].Key < modules[j].Key
+	})
+	return modules
+}
+
+// backendType returns the type of the backend that "tofu init" most
+// recently initialized the working directory with, which is "local" if it
+// didn't initialize a backend.
+func (c *VersionCommand) backendType() string {
+	sMgr := &clistate.LocalState{Path: filepath.Join(c.DataDir(), DefaultStateFilename)}
+	if err := sMgr.RefreshState(); err != nil {
+		return "local"
+	}
+	s := sMgr.State()
+	if s == nil || s.Backend == nil || s.Backend.Type == "" {
+		return "local"
+	}
+	return s.Backend.Type
+}
+
 func (c *VersionCommand) Synopsis() string {
 	return "Show the current OpenTofu version"
 }
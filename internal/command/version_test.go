@@ -6,6 +6,8 @@
 package command
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -115,7 +117,11 @@ func TestVersion_json(t *testing.T) {
 {
   "terraform_version": "4.5.6",
   "platform": "aros_riscv64",
-  "provider_selections": {}
+  "provider_selections": {},
+  "provider_locks": {},
+  "modules": [],
+  "backend_type": "local",
+  "workspace": "default"
 }
 `)
 	if diff := cmp.Diff(expected, actual); diff != "" {
@@ -138,10 +144,22 @@ func TestVersion_json(t *testing.T) {
 	locks.SetProvider(
 		addrs.NewDefaultProvider("test1"),
 		getproviders.MustParseVersion("7.8.9-beta.2"),
-		nil,
-		nil,
+		getproviders.MustParseVersionConstraints(">= 7.0.0"),
+		[]getproviders.Hash{"h1:test1"},
 	)
 
+	// We'll also record an installed module and select a workspace.
+	if err := os.MkdirAll(filepath.Join(DefaultDataDir, "modules"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := `{"Modules":[{"Key":"","Source":"","Dir":"."},{"Key":"network","Source":"registry.opentofu.org/example/network/aws","Version":"1.2.0","Dir":".terraform/modules/network"}]}`
+	if err := os.WriteFile(filepath.Join(DefaultDataDir, "modules", "modules.json"), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(DefaultDataDir, "environment"), []byte("staging"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
 	// `tofu version -json` with prerelease and provider dependencies
 	c = &VersionCommand{
 		Meta:              meta,
@@ -164,7 +182,30 @@ func TestVersion_json(t *testing.T) {
   "provider_selections": {
     "registry.opentofu.org/hashicorp/test1": "7.8.9-beta.2",
     "registry.opentofu.org/hashicorp/test2": "1.2.3"
-  }
+  },
+  "provider_locks": {
+    "registry.opentofu.org/hashicorp/test1": {
+      "version": "7.8.9-beta.2",
+      "constraints": ">= 7.0.0",
+      "hashes": [
+        "h1:test1"
+      ]
+    },
+    "registry.opentofu.org/hashicorp/test2": {
+      "version": "1.2.3",
+      "hashes": []
+    }
+  },
+  "modules": [
+    {
+      "key": "network",
+      "source": "registry.opentofu.org/example/network/aws",
+      "version": "1.2.0",
+      "dir": ".terraform/modules/network"
+    }
+  ],
+  "backend_type": "local",
+  "workspace": "staging"
 }
 `)
 	if diff := cmp.Diff(expected, actual); diff != "" {
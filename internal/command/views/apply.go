@@ -7,10 +7,12 @@ package views
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/format"
 	"github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/i18n"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -24,6 +26,15 @@ type Apply interface {
 	Operation() Operation
 	Hooks() []tofu.Hook
 
+	// Timings reports how long the operation spent on each resource
+	// instance, as recorded by the hooks.
+	Timings()
+
+	// WatchCycleStart and WatchCycleComplete report the progress of the
+	// continuous reconcile mode enabled by "tofu apply -watch".
+	WatchCycleStart(cycle int)
+	WatchCycleComplete(cycle int, next time.Time)
+
 	Diagnostics(diags tfdiags.Diagnostics)
 	HelpPrompt()
 }
@@ -33,9 +44,10 @@ func NewApply(vt arguments.ViewType, destroy bool, view *View) Apply {
 	switch vt {
 	case arguments.ViewJSON:
 		return &ApplyJSON{
-			view:      NewJSONView(view),
-			destroy:   destroy,
-			countHook: &countHook{},
+			view:        NewJSONView(view),
+			destroy:     destroy,
+			countHook:   &countHook{},
+			timingsHook: newTimingsHook(),
 		}
 	case arguments.ViewHuman:
 		return &ApplyHuman{
@@ -43,6 +55,7 @@ func NewApply(vt arguments.ViewType, destroy bool, view *View) Apply {
 			destroy:      destroy,
 			inAutomation: view.RunningInAutomation(),
 			countHook:    &countHook{},
+			timingsHook:  newTimingsHook(),
 		}
 	default:
 		panic(fmt.Sprintf("unknown view type %v", vt))
@@ -57,32 +70,33 @@ type ApplyHuman struct {
 	destroy      bool
 	inAutomation bool
 
-	countHook *countHook
+	countHook   *countHook
+	timingsHook *timingsHook
 }
 
 var _ Apply = (*ApplyHuman)(nil)
 
 func (v *ApplyHuman) ResourceCount(stateOutPath string) {
 	if v.destroy {
-		v.view.streams.Printf(
-			v.view.colorize.Color("[reset][bold][green]\nDestroy complete! Resources: %d destroyed.\n"),
+		v.view.streams.Print(v.view.colorize.Color("[reset][bold][green]\n" + i18n.Sprintf(
+			"Destroy complete! Resources: %d destroyed.",
 			v.countHook.Removed,
-		)
+		) + "\n"))
 	} else if v.countHook.Imported > 0 {
-		v.view.streams.Printf(
-			v.view.colorize.Color("[reset][bold][green]\nApply complete! Resources: %d imported, %d added, %d changed, %d destroyed.\n"),
+		v.view.streams.Print(v.view.colorize.Color("[reset][bold][green]\n" + i18n.Sprintf(
+			"Apply complete! Resources: %d imported, %d added, %d changed, %d destroyed.",
 			v.countHook.Imported,
 			v.countHook.Added,
 			v.countHook.Changed,
 			v.countHook.Removed,
-		)
+		) + "\n"))
 	} else {
-		v.view.streams.Printf(
-			v.view.colorize.Color("[reset][bold][green]\nApply complete! Resources: %d added, %d changed, %d destroyed.\n"),
+		v.view.streams.Print(v.view.colorize.Color("[reset][bold][green]\n" + i18n.Sprintf(
+			"Apply complete! Resources: %d added, %d changed, %d destroyed.",
 			v.countHook.Added,
 			v.countHook.Changed,
 			v.countHook.Removed,
-		)
+		) + "\n"))
 	}
 	if (v.countHook.Added > 0 || v.countHook.Changed > 0) && stateOutPath != "" {
 		v.view.streams.Printf("\n%s\n\n", format.WordWrap(stateOutPathPostApply, v.view.outputColumns()))
@@ -92,7 +106,7 @@ func (v *ApplyHuman) ResourceCount(stateOutPath string) {
 
 func (v *ApplyHuman) Outputs(outputValues map[string]*states.OutputValue) {
 	if len(outputValues) > 0 {
-		v.view.streams.Print(v.view.colorize.Color("[reset][bold][green]\nOutputs:\n\n"))
+		v.view.streams.Print(v.view.colorize.Color("[reset][bold][green]\n" + i18n.T("Outputs:") + "\n\n"))
 		NewOutput(arguments.ViewHuman, v.view).Output("", outputValues)
 	}
 }
@@ -105,9 +119,29 @@ func (v *ApplyHuman) Hooks() []tofu.Hook {
 	return []tofu.Hook{
 		v.countHook,
 		NewUiHook(v.view),
+		v.timingsHook,
 	}
 }
 
+func (v *ApplyHuman) Timings() {
+	v.view.streams.Print(v.timingsHook.Render())
+}
+
+func (v *ApplyHuman) WatchCycleStart(cycle int) {
+	v.countHook.Reset()
+	v.timingsHook.Reset()
+	v.view.streams.Print(v.view.colorize.Color(fmt.Sprintf(
+		"[reset][bold]\nStarting watch cycle %d...\n", cycle,
+	)))
+}
+
+func (v *ApplyHuman) WatchCycleComplete(cycle int, next time.Time) {
+	v.view.streams.Printf(
+		"\nWatch cycle %d complete. The next cycle starts at %s.\n",
+		cycle, next.Format(time.RFC3339),
+	)
+}
+
 func (v *ApplyHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -120,6 +154,77 @@ func (v *ApplyHuman) HelpPrompt() {
 	v.view.HelpPrompt(command)
 }
 
+// NewApplyTUI returns an Apply implementation that shows the progress of
+// the apply phase in a full-screen, interactive display, and otherwise
+// renders the same output as the human-readable view.
+func NewApplyTUI(destroy bool, view *View) Apply {
+	title := "OpenTofu apply"
+	if destroy {
+		title = "OpenTofu destroy"
+	}
+	display := newTUIDisplay(title, view)
+	return &ApplyTUI{
+		ApplyHuman: &ApplyHuman{
+			view:         view,
+			destroy:      destroy,
+			inAutomation: view.RunningInAutomation(),
+			countHook:    &countHook{},
+			timingsHook:  newTimingsHook(),
+		},
+		display: display,
+		tuiHook: newTUIHook(display, NewUiHook(view)),
+	}
+}
+
+// The ApplyTUI implementation shows the progress of the apply phase in a
+// full-screen, interactive display, for large configurations where the
+// scrolling text logs are hard to follow. The planning phase is shown as
+// normal, so that the plan can be reviewed and confirmed.
+type ApplyTUI struct {
+	*ApplyHuman
+
+	display *tuiDisplay
+	tuiHook *tuiHook
+}
+
+var _ Apply = (*ApplyTUI)(nil)
+
+func (v *ApplyTUI) ResourceCount(stateOutPath string) {
+	v.display.Finish()
+	v.ApplyHuman.ResourceCount(stateOutPath)
+}
+
+func (v *ApplyTUI) Outputs(outputValues map[string]*states.OutputValue) {
+	v.display.Finish()
+	v.ApplyHuman.Outputs(outputValues)
+}
+
+func (v *ApplyTUI) Operation() Operation {
+	return &OperationTUI{
+		Operation: v.ApplyHuman.Operation(),
+		display:   v.display,
+		hook:      v.tuiHook,
+	}
+}
+
+func (v *ApplyTUI) Hooks() []tofu.Hook {
+	return []tofu.Hook{
+		v.countHook,
+		v.tuiHook,
+		v.timingsHook,
+	}
+}
+
+func (v *ApplyTUI) Timings() {
+	v.display.Finish()
+	v.ApplyHuman.Timings()
+}
+
+func (v *ApplyTUI) Diagnostics(diags tfdiags.Diagnostics) {
+	v.display.Finish()
+	v.ApplyHuman.Diagnostics(diags)
+}
+
 const stateOutPathPostApply = "The state of your infrastructure has been saved to the path below. This state is required to modify and destroy your infrastructure, so keep it safe. To inspect the complete state use the `tofu show` command."
 
 // The ApplyJSON implementation renders streaming JSON logs, suitable for
@@ -129,7 +234,8 @@ type ApplyJSON struct {
 
 	destroy bool
 
-	countHook *countHook
+	countHook   *countHook
+	timingsHook *timingsHook
 }
 
 var _ Apply = (*ApplyJSON)(nil)
@@ -165,9 +271,24 @@ func (v *ApplyJSON) Hooks() []tofu.Hook {
 	return []tofu.Hook{
 		v.countHook,
 		newJSONHook(v.view),
+		v.timingsHook,
 	}
 }
 
+func (v *ApplyJSON) Timings() {
+	v.view.Timings(v.timingsHook.JSON())
+}
+
+func (v *ApplyJSON) WatchCycleStart(cycle int) {
+	v.countHook.Reset()
+	v.timingsHook.Reset()
+	v.view.WatchCycleStart(cycle)
+}
+
+func (v *ApplyJSON) WatchCycleComplete(cycle int, next time.Time) {
+	v.view.WatchCycleComplete(cycle, next)
+}
+
 func (v *ApplyJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -0,0 +1,256 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/command/views/json"
+)
+
+const (
+	// downloadProgressHumanInterval and downloadProgressJSONInterval limit
+	// how often the progress of a download is reported, since the installers
+	// report it after every read from the network.
+	downloadProgressHumanInterval = 100 * time.Millisecond
+	downloadProgressJSONInterval  = time.Second
+
+	// downloadProgressBarWidth is the number of cells between the brackets
+	// of a progress bar.
+	downloadProgressBarWidth = 20
+)
+
+// The DownloadProgress view is used by "tofu init" to report the progress of
+// downloading provider and module packages.
+type DownloadProgress interface {
+	// Progress reports that received bytes of the given package have been
+	// downloaded so far, out of total bytes, or -1 if the size of the
+	// package isn't known.
+	Progress(pkg DownloadPackage, received, total int64)
+
+	// Done reports that the current download has finished, whether or not
+	// it succeeded. It must be called before any other output is produced,
+	// and does nothing if there is no download in progress. Downloads
+	// happen one at a time, so a Progress call for a different package also
+	// ends the previous download.
+	Done()
+}
+
+// DownloadPackage identifies a package being downloaded.
+type DownloadPackage struct {
+	Kind json.InitDownloadKind
+
+	// Name is the source address of a provider, or the address of the
+	// module call for a module.
+	Name    string
+	Version string
+}
+
+func (p DownloadPackage) String() string {
+	if p.Version == "" {
+		return p.Name
+	}
+	if p.Kind == json.InitDownloadProvider {
+		return fmt.Sprintf("%s v%s", p.Name, p.Version)
+	}
+	return fmt.Sprintf("%s %s", p.Name, p.Version)
+}
+
+// downloadProgressState tracks the progress of a single download, so that
+// it can be reported at a limited rate along with the transfer rate.
+type downloadProgressState struct {
+	pkg             DownloadPackage
+	start, reported time.Time
+	received, total int64
+	pending         bool
+}
+
+// update records new progress for the given package, starting a new state if
+// the package is not the one currently tracked, and returns whether the
+// progress should be reported now.
+func (s *downloadProgressState) update(pkg DownloadPackage, received, total int64, now time.Time, interval time.Duration) bool {
+	if s.pkg != pkg || s.start.IsZero() {
+		*s = downloadProgressState{pkg: pkg, start: now}
+	}
+	s.received, s.total = received, total
+	if now.Sub(s.reported) < interval && (total <= 0 || received < total) {
+		s.pending = true
+		return false
+	}
+	s.reported = now
+	s.pending = false
+	return true
+}
+
+// bytesPerSecond returns the average transfer rate since the download began.
+func (s *downloadProgressState) bytesPerSecond(now time.Time) int64 {
+	elapsed := now.Sub(s.start).Seconds()
+	if elapsed <= 0 {
+		return 0
+	}
+	return int64(float64(s.received) / elapsed)
+}
+
+// NewDownloadProgressHuman returns a DownloadProgress which draws a progress
+// bar for the current download, if the output is a terminal.
+func NewDownloadProgressHuman(view *View) DownloadProgress {
+	return &DownloadProgressHuman{
+		view:    view,
+		enabled: view.streams.Stdout.IsTerminal(),
+		now:     time.Now,
+	}
+}
+
+// DownloadProgressHuman is an implementation of DownloadProgress which draws
+// a progress bar on a single line of the terminal, which is cleared again
+// when the download is done. It produces no output at all if the output is
+// not a terminal.
+type DownloadProgressHuman struct {
+	view    *View
+	enabled bool
+	now     func() time.Time
+
+	mu      sync.Mutex
+	state   downloadProgressState
+	lineLen int
+}
+
+var _ DownloadProgress = (*DownloadProgressHuman)(nil)
+
+func (v *DownloadProgressHuman) Progress(pkg DownloadPackage, received, total int64) {
+	if !v.enabled {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	if !v.state.update(pkg, received, total, now, downloadProgressHumanInterval) {
+		return
+	}
+
+	line := v.line(now)
+	pad := max(v.lineLen-len(line), 0)
+	v.view.streams.Print("\r" + line + strings.Repeat(" ", pad))
+	v.lineLen = len(line)
+}
+
+func (v *DownloadProgressHuman) Done() {
+	if !v.enabled {
+		return
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.lineLen > 0 {
+		v.view.streams.Print("\r" + strings.Repeat(" ", v.lineLen) + "\r")
+	}
+	v.lineLen = 0
+	v.state = downloadProgressState{}
+}
+
+// line renders the progress of the current download, leaving out the bar
+// and then shortening the package name if the terminal is too narrow.
+func (v *DownloadProgressHuman) line(now time.Time) string {
+	s := &v.state
+	name := "  " + s.pkg.String()
+	rate := formatBytes(s.bytesPerSecond(now)) + "/s"
+
+	var bar, amount string
+	if s.total > 0 {
+		frac := min(float64(s.received)/float64(s.total), 1)
+		filled := int(frac * downloadProgressBarWidth)
+		arrow := ""
+		if filled < downloadProgressBarWidth {
+			arrow = ">"
+		}
+		bar = fmt.Sprintf(" [%s%s%s] %3d%%",
+			strings.Repeat("=", filled), arrow,
+			strings.Repeat(" ", max(downloadProgressBarWidth-filled-len(arrow), 0)),
+			int(frac*100),
+		)
+		amount = fmt.Sprintf("  %s / %s  %s", formatBytes(s.received), formatBytes(s.total), rate)
+	} else {
+		amount = fmt.Sprintf("  %s  %s", formatBytes(s.received), rate)
+	}
+
+	// Stay short of the last column, so that the terminal doesn't wrap.
+	width := v.view.outputColumns() - 1
+	if len(name)+len(bar)+len(amount) > width {
+		bar = ""
+	}
+	if extra := len(name) + len(amount) - width; extra > 0 {
+		name = name[:max(len(name)-extra-3, 2)] + "..."
+	}
+	return name + bar + amount
+}
+
+// NewDownloadProgressJSON returns a DownloadProgress which reports progress
+// as init_download_progress messages.
+func NewDownloadProgressJSON(view *JSONView) DownloadProgress {
+	return &DownloadProgressJSON{
+		view: view,
+		now:  time.Now,
+	}
+}
+
+// DownloadProgressJSON is an implementation of DownloadProgress which emits
+// machine-readable progress messages at most once a second for each
+// download, and a final message when it is done.
+type DownloadProgressJSON struct {
+	view *JSONView
+	now  func() time.Time
+
+	mu    sync.Mutex
+	state downloadProgressState
+}
+
+var _ DownloadProgress = (*DownloadProgressJSON)(nil)
+
+func (v *DownloadProgressJSON) Progress(pkg DownloadPackage, received, total int64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	now := v.now()
+	if v.state.pkg != pkg && v.state.pending {
+		v.event(now)
+	}
+	if v.state.update(pkg, received, total, now, downloadProgressJSONInterval) {
+		v.event(now)
+	}
+}
+
+func (v *DownloadProgressJSON) Done() {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.state.pending {
+		v.event(v.now())
+	}
+	v.state = downloadProgressState{}
+}
+
+func (v *DownloadProgressJSON) event(now time.Time) {
+	s := &v.state
+	v.view.InitEvent(json.NewInitDownloadProgress(s.pkg.Kind, s.pkg.Name, s.pkg.Version, s.received, s.total, s.bytesPerSecond(now)))
+}
+
+// formatBytes formats the given number of bytes using binary units.
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -0,0 +1,179 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/terminal"
+)
+
+// testClock returns a function to use as the clock of a DownloadProgress
+// view, and a function to advance it.
+func testClock() (now func() time.Time, advance func(time.Duration)) {
+	t := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	return func() time.Time { return t }, func(d time.Duration) { t = t.Add(d) }
+}
+
+func TestDownloadProgressHuman(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	now, advance := testClock()
+	v := &DownloadProgressHuman{
+		view:    NewView(streams),
+		enabled: true,
+		now:     now,
+	}
+
+	pkg := DownloadPackage{
+		Kind:    viewsjson.InitDownloadProvider,
+		Name:    "hashicorp/null",
+		Version: "3.2.1",
+	}
+	v.Progress(pkg, 0, 4<<20)
+	advance(time.Second)
+	v.Progress(pkg, 1<<20, 4<<20)
+	// This one is within the reporting interval, so it isn't drawn.
+	advance(10 * time.Millisecond)
+	v.Progress(pkg, 2<<20, 4<<20)
+	v.Done()
+
+	got := done(t).Stdout()
+	lines := strings.Split(got, "\r")
+	want := []string{
+		"",
+		"  hashicorp/null v3.2.1 [>                   ]   0%  0 B / 4.0 MiB  0 B/s",
+		// The bar is left out when the line would be wider than the
+		// terminal, which is 78 columns when testing.
+		"  hashicorp/null v3.2.1  1.0 MiB / 4.0 MiB  1.0 MiB/s",
+		"",
+		"",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("wrong output\ngot:  %q\nwant: %q", lines, want)
+	}
+	for i := range want {
+		if strings.TrimRight(lines[i], " ") != strings.TrimRight(want[i], " ") {
+			t.Errorf("wrong line %d\ngot:  %q\nwant: %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestDownloadProgressHuman_notTerminal(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewDownloadProgressHuman(NewView(streams))
+
+	pkg := DownloadPackage{Kind: viewsjson.InitDownloadModule, Name: "foo"}
+	v.Progress(pkg, 100, -1)
+	v.Done()
+
+	if got := done(t).Stdout(); got != "" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestDownloadProgressJSON(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	now, advance := testClock()
+	v := &DownloadProgressJSON{
+		view: NewJSONView(NewView(streams)),
+		now:  now,
+	}
+
+	provider := DownloadPackage{
+		Kind:    viewsjson.InitDownloadProvider,
+		Name:    "registry.opentofu.org/hashicorp/null",
+		Version: "3.2.1",
+	}
+	module := DownloadPackage{
+		Kind: viewsjson.InitDownloadModule,
+		Name: "foo",
+	}
+	v.Progress(provider, 100, 400)
+	advance(100 * time.Millisecond)
+	v.Progress(provider, 200, 400) // throttled
+	advance(100 * time.Millisecond)
+	v.Progress(provider, 300, 400) // throttled, then reported by Done
+	v.Done()
+	v.Progress(module, 1000, -1)
+	advance(2 * time.Second)
+	v.Progress(module, 5000, -1)
+	v.Done() // nothing pending
+
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "Downloading registry.opentofu.org/hashicorp/null 3.2.1: 100 of 400 bytes",
+			"@module":  "tofu.ui",
+			"type":     "init_download_progress",
+			"init": map[string]interface{}{
+				"kind":             "provider",
+				"package":          "registry.opentofu.org/hashicorp/null",
+				"version":          "3.2.1",
+				"received_bytes":   float64(100),
+				"total_bytes":      float64(400),
+				"bytes_per_second": float64(0),
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "Downloading registry.opentofu.org/hashicorp/null 3.2.1: 300 of 400 bytes",
+			"@module":  "tofu.ui",
+			"type":     "init_download_progress",
+			"init": map[string]interface{}{
+				"kind":             "provider",
+				"package":          "registry.opentofu.org/hashicorp/null",
+				"version":          "3.2.1",
+				"received_bytes":   float64(300),
+				"total_bytes":      float64(400),
+				"bytes_per_second": float64(1500),
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "Downloading foo: 1000 bytes",
+			"@module":  "tofu.ui",
+			"type":     "init_download_progress",
+			"init": map[string]interface{}{
+				"kind":             "module",
+				"package":          "foo",
+				"received_bytes":   float64(1000),
+				"bytes_per_second": float64(0),
+			},
+		},
+		{
+			"@level":   "info",
+			"@message": "Downloading foo: 5000 bytes",
+			"@module":  "tofu.ui",
+			"type":     "init_download_progress",
+			"init": map[string]interface{}{
+				"kind":             "module",
+				"package":          "foo",
+				"received_bytes":   float64(5000),
+				"bytes_per_second": float64(2500),
+			},
+		},
+	}
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:               "0 B",
+		1023:            "1023 B",
+		1024:            "1.0 KiB",
+		1536:            "1.5 KiB",
+		5 << 20:         "5.0 MiB",
+		3<<30 + 512<<20: "3.5 GiB",
+	}
+	for n, want := range tests {
+		if got := formatBytes(n); got != want {
+			t.Errorf("wrong result for %d: got %q, want %q", n, got, want)
+		}
+	}
+}
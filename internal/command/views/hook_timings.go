@@ -0,0 +1,234 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/views/json"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// timingsSummaryLimit is the number of resource instances listed in the
+// human-readable timings summary.
+const timingsSummaryLimit = 10
+
+type timingPhase string
+
+const (
+	timingRefresh timingPhase = "refresh"
+	timingPlan    timingPhase = "plan"
+	timingApply   timingPhase = "apply"
+)
+
+// timingsHook is a hook that records how long OpenTofu spends refreshing,
+// planning, and applying each resource instance, and how much of that time
+// is spent waiting for the provider.
+type timingsHook struct {
+	sync.Mutex
+	started   map[string]time.Time
+	resources map[string]*resourceTimings
+
+	// now is replaced in tests.
+	now func() time.Time
+
+	tofu.NilHook
+}
+
+var _ tofu.Hook = (*timingsHook)(nil)
+
+// resourceTimings records the time spent on a single resource instance.
+type resourceTimings struct {
+	Addr     addrs.AbsResourceInstance
+	Refresh  time.Duration
+	Plan     time.Duration
+	Apply    time.Duration
+	Provider time.Duration
+}
+
+// Total returns the time spent on the resource instance across all phases.
+func (t *resourceTimings) Total() time.Duration {
+	return t.Refresh + t.Plan + t.Apply
+}
+
+func newTimingsHook() *timingsHook {
+	return &timingsHook{
+		started:   make(map[string]time.Time),
+		resources: make(map[string]*resourceTimings),
+		now:       time.Now,
+	}
+}
+
+func (h *timingsHook) Reset() {
+	h.Lock()
+	defer h.Unlock()
+
+	h.started = make(map[string]time.Time)
+	h.resources = make(map[string]*resourceTimings)
+}
+
+func (h *timingsHook) start(addr addrs.AbsResourceInstance, phase timingPhase) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.started[string(phase)+" "+addr.String()] = h.now()
+}
+
+func (h *timingsHook) stop(addr addrs.AbsResourceInstance, phase timingPhase) {
+	h.Lock()
+	defer h.Unlock()
+
+	key := string(phase) + " " + addr.String()
+	start, ok := h.started[key]
+	if !ok {
+		return
+	}
+	delete(h.started, key)
+
+	elapsed := h.now().Sub(start)
+	t := h.resource(addr)
+	switch phase {
+	case timingRefresh:
+		t.Refresh += elapsed
+	case timingPlan:
+		t.Plan += elapsed
+	case timingApply:
+		t.Apply += elapsed
+	}
+}
+
+// resource must be called with the lock held.
+func (h *timingsHook) resource(addr addrs.AbsResourceInstance) *resourceTimings {
+	key := addr.String()
+	t, ok := h.resources[key]
+	if !ok {
+		t = &resourceTimings{Addr: addr}
+		h.resources[key] = t
+	}
+	return t
+}
+
+func (h *timingsHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (tofu.HookAction, error) {
+	h.start(addr, timingRefresh)
+	return tofu.HookActionContinue, nil
+}
+
+func (h *timingsHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value, newState cty.Value) (tofu.HookAction, error) {
+	h.stop(addr, timingRefresh)
+	return tofu.HookActionContinue, nil
+}
+
+func (h *timingsHook) PreDiff(addr addrs.AbsResourceInstance, gen states.Generation, priorState, proposedNewState cty.Value) (tofu.HookAction, error) {
+	h.start(addr, timingPlan)
+	return tofu.HookActionContinue, nil
+}
+
+func (h *timingsHook) PostDiff(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
+	h.stop(addr, timingPlan)
+	return tofu.HookActionContinue, nil
+}
+
+func (h *timingsHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
+	h.start(addr, timingApply)
+	return tofu.HookActionContinue, nil
+}
+
+func (h *timingsHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (tofu.HookAction, error) {
+	h.stop(addr, timingApply)
+	return tofu.HookActionContinue, nil
+}
+
+func (h *timingsHook) ProviderCall(addr addrs.AbsResourceInstance, method string, elapsed time.Duration) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.resource(addr).Provider += elapsed
+}
+
+// Report returns the timings recorded so far, slowest resource instance
+// first, along with the totals across all of them.
+func (h *timingsHook) Report() ([]*resourceTimings, resourceTimings) {
+	h.Lock()
+	defer h.Unlock()
+
+	var total resourceTimings
+	ret := make([]*resourceTimings, 0, len(h.resources))
+	for _, t := range h.resources {
+		copied := *t
+		ret = append(ret, &copied)
+		total.Refresh += t.Refresh
+		total.Plan += t.Plan
+		total.Apply += t.Apply
+		total.Provider += t.Provider
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ti, tj := ret[i].Total(), ret[j].Total(); ti != tj {
+			return ti > tj
+		}
+		return ret[i].Addr.Less(ret[j].Addr)
+	})
+	return ret, total
+}
+
+// Render returns a human-readable summary of the slowest resource instances.
+func (h *timingsHook) Render() string {
+	resources, total := h.Report()
+	if len(resources) == 0 {
+		return "\nTimings: no resource instances were refreshed, planned, or applied.\n"
+	}
+
+	shown := resources[:min(len(resources), timingsSummaryLimit)]
+	width := 0
+	for _, t := range shown {
+		width = max(width, len(t.Addr.String()))
+	}
+
+	var buf strings.Builder
+	if len(resources) > len(shown) {
+		fmt.Fprintf(&buf, "\nTimings (slowest %d of %d resource instances):\n", len(shown), len(resources))
+	} else {
+		fmt.Fprintf(&buf, "\nTimings (%d resource instances):\n", len(resources))
+	}
+	for _, t := range shown {
+		fmt.Fprintf(&buf, "  %-*s  %s\n", width, t.Addr, formatPhaseTimings(t))
+	}
+	fmt.Fprintf(&buf, "\nTotal: %s\n", formatPhaseTimings(&total))
+	return buf.String()
+}
+
+// JSON returns the timings recorded so far in the form used by the
+// machine-readable output.
+func (h *timingsHook) JSON() *json.Timings {
+	resources, total := h.Report()
+	ret := &json.Timings{
+		Resources: make([]json.ResourceTiming, len(resources)),
+		Total:     json.NewPhaseTimings(total.Refresh, total.Plan, total.Apply, total.Provider),
+	}
+	for i, t := range resources {
+		ret.Resources[i] = json.NewResourceTiming(t.Addr, json.NewPhaseTimings(t.Refresh, t.Plan, t.Apply, t.Provider))
+	}
+	return ret
+}
+
+func formatPhaseTimings(t *resourceTimings) string {
+	return fmt.Sprintf(
+		"%s (refresh %s, plan %s, apply %s; provider %s)",
+		formatTiming(t.Total()), formatTiming(t.Refresh), formatTiming(t.Plan), formatTiming(t.Apply), formatTiming(t.Provider),
+	)
+}
+
+func formatTiming(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
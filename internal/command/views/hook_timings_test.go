@@ -0,0 +1,137 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/terminal"
+)
+
+// testTimingsHook returns a timings hook whose clock advances by the given
+// step each time it is read, starting from the Unix epoch.
+func testTimingsHook(step time.Duration) *timingsHook {
+	h := newTimingsHook()
+	now := time.Unix(0, 0)
+	h.now = func() time.Time {
+		now = now.Add(step)
+		return now
+	}
+	return h
+}
+
+func recordTimings(h *timingsHook) (fast, slow addrs.AbsResourceInstance) {
+	fast = addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "fast",
+	}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	slow = addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: "slow",
+	}.Instance(addrs.IntKey(0)).Absolute(addrs.RootModuleInstance)
+
+	h.PreRefresh(fast, states.CurrentGen, cty.DynamicVal)
+	h.PostRefresh(fast, states.CurrentGen, cty.DynamicVal, cty.DynamicVal)
+
+	h.PreDiff(slow, states.CurrentGen, cty.DynamicVal, cty.DynamicVal)
+	h.ProviderCall(slow, "PlanResourceChange", 500*time.Millisecond)
+	h.PostDiff(slow, states.CurrentGen, plans.Create, cty.DynamicVal, cty.DynamicVal)
+	h.PreApply(slow, states.CurrentGen, plans.Create, cty.DynamicVal, cty.DynamicVal)
+	h.ProviderCall(slow, "ApplyResourceChange", 700*time.Millisecond)
+	h.PostApply(slow, states.CurrentGen, cty.DynamicVal, nil)
+
+	return fast, slow
+}
+
+func TestTimingsHook(t *testing.T) {
+	h := testTimingsHook(time.Second)
+	fast, slow := recordTimings(h)
+
+	got, total := h.Report()
+	want := []*resourceTimings{
+		{Addr: slow, Plan: time.Second, Apply: time.Second, Provider: 1200 * time.Millisecond},
+		{Addr: fast, Refresh: time.Second},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong report\n%s", diff)
+	}
+	wantTotal := resourceTimings{Refresh: time.Second, Plan: time.Second, Apply: time.Second, Provider: 1200 * time.Millisecond}
+	if diff := cmp.Diff(wantTotal, total); diff != "" {
+		t.Errorf("wrong total\n%s", diff)
+	}
+
+	wantRender := `
+Timings (2 resource instances):
+  test_instance.slow[0]  2s (refresh 0s, plan 1s, apply 1s; provider 1.2s)
+  test_instance.fast     1s (refresh 1s, plan 0s, apply 0s; provider 0s)
+
+Total: 3s (refresh 1s, plan 1s, apply 1s; provider 1.2s)
+`
+	if diff := cmp.Diff(wantRender, h.Render()); diff != "" {
+		t.Errorf("wrong summary\n%s", diff)
+	}
+
+	h.Reset()
+	if got, _ := h.Report(); len(got) != 0 {
+		t.Errorf("unexpected timings after reset: %#v", got)
+	}
+}
+
+func TestTimingsHook_json(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewPlan(arguments.ViewJSON, NewView(streams)).(*PlanJSON)
+	v.timingsHook = testTimingsHook(time.Second)
+	recordTimings(v.timingsHook)
+	v.Timings()
+
+	phases := func(refresh, plan, apply, provider float64) map[string]interface{} {
+		return map[string]interface{}{
+			"refresh_seconds":  refresh,
+			"plan_seconds":     plan,
+			"apply_seconds":    apply,
+			"provider_seconds": provider,
+		}
+	}
+	resource := func(addr, name string, key interface{}, timings map[string]interface{}) map[string]interface{} {
+		timings["resource"] = map[string]interface{}{
+			"addr":             addr,
+			"module":           "",
+			"resource":         addr,
+			"implied_provider": "test",
+			"resource_type":    "test_instance",
+			"resource_name":    name,
+			"resource_key":     key,
+		}
+		return timings
+	}
+
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "Timings: 2 resource instances, 3.00s in total",
+			"@module":  "tofu.ui",
+			"type":     "timings",
+			"timings": map[string]interface{}{
+				"resources": []interface{}{
+					resource("test_instance.slow[0]", "slow", float64(0), phases(0, 1, 1, 1.2)),
+					resource("test_instance.fast", "fast", nil, phases(1, 0, 0, 0)),
+				},
+				"total": phases(1, 1, 1, 1.2),
+			},
+		},
+	}
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
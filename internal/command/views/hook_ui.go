@@ -18,6 +18,7 @@ import (
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/i18n"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
@@ -84,16 +85,16 @@ func (h *UiHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation,
 	idKey, idValue := format.ObjectValueIDOrName(priorState)
 	switch action {
 	case plans.Delete:
-		operation = "Destroying..."
+		operation = i18n.T("Destroying...")
 		op = uiResourceDestroy
 	case plans.Create:
-		operation = "Creating..."
+		operation = i18n.T("Creating...")
 		op = uiResourceCreate
 	case plans.Update:
-		operation = "Modifying..."
+		operation = i18n.T("Modifying...")
 		op = uiResourceModify
 	case plans.Read:
-		operation = "Reading..."
+		operation = i18n.T("Reading...")
 		op = uiResourceRead
 	case plans.NoOp:
 		op = uiResourceNoOp
@@ -160,13 +161,13 @@ func (h *UiHook) stillApplying(state uiResourceState) {
 		var msg string
 		switch state.Op {
 		case uiResourceModify:
-			msg = "Still modifying..."
+			msg = i18n.T("Still modifying...")
 		case uiResourceDestroy:
-			msg = "Still destroying..."
+			msg = i18n.T("Still destroying...")
 		case uiResourceCreate:
-			msg = "Still creating..."
+			msg = i18n.T("Still creating...")
 		case uiResourceRead:
-			msg = "Still reading..."
+			msg = i18n.T("Still reading...")
 		case uiResourceUnknown:
 			return
 		}
@@ -177,11 +178,10 @@ func (h *UiHook) stillApplying(state uiResourceState) {
 		}
 
 		h.println(fmt.Sprintf(
-			h.view.colorize.Color("[reset][bold]%s: %s [%s%s elapsed][reset]"),
+			h.view.colorize.Color("[reset][bold]%s: %s [%s][reset]"),
 			state.DispAddr,
 			msg,
-			idSuffix,
-			time.Now().Round(time.Second).Sub(state.Start),
+			i18n.Sprintf("%s%s elapsed", idSuffix, time.Now().Round(time.Second).Sub(state.Start)),
 		))
 	}
 }
@@ -206,13 +206,13 @@ func (h *UiHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation
 	var msg string
 	switch state.Op {
 	case uiResourceModify:
-		msg = "Modifications complete"
+		msg = i18n.T("Modifications complete")
 	case uiResourceDestroy:
-		msg = "Destruction complete"
+		msg = i18n.T("Destruction complete")
 	case uiResourceCreate:
-		msg = "Creation complete"
+		msg = i18n.T("Creation complete")
 	case uiResourceRead:
-		msg = "Read complete"
+		msg = i18n.T("Read complete")
 	case uiResourceNoOp:
 		// We don't make any announcements about no-op changes
 		return tofu.HookActionContinue, nil
@@ -231,8 +231,8 @@ func (h *UiHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation
 	}
 
 	colorized := fmt.Sprintf(
-		h.view.colorize.Color("[reset][bold]%s: %s after %s%s"),
-		addrStr, msg, time.Now().Round(time.Second).Sub(state.Start), stateIdSuffix)
+		h.view.colorize.Color("[reset][bold]%s: %s%s"),
+		addrStr, i18n.Sprintf("%s after %s", msg, time.Now().Round(time.Second).Sub(state.Start)), stateIdSuffix)
 
 	h.println(colorized)
 
@@ -278,8 +278,8 @@ func (h *UiHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generatio
 	}
 	if !h.view.concise {
 		h.println(fmt.Sprintf(
-			h.view.colorize.Color("[reset][bold]%s: Refreshing state...%s"),
-			addrStr, stateIdSuffix))
+			h.view.colorize.Color("[reset][bold]%s: %s%s"),
+			addrStr, i18n.T("Refreshing state..."), stateIdSuffix))
 	}
 	return tofu.HookActionContinue, nil
 }
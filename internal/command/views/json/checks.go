@@ -0,0 +1,52 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/command/jsonchecks"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// CheckResults reports the results of the checks in the configuration at the
+// end of a plan or apply operation. Results has the same form as the "checks"
+// property of the JSON plan representation, while the counts summarize the
+// statuses of the checkable objects.
+type CheckResults struct {
+	Pass    int             `json:"pass"`
+	Fail    int             `json:"fail"`
+	Error   int             `json:"error"`
+	Unknown int             `json:"unknown"`
+	Results json.RawMessage `json:"results"`
+}
+
+func NewCheckResults(results *states.CheckResults) *CheckResults {
+	ret := &CheckResults{
+		Results: jsonchecks.MarshalCheckStates(results),
+	}
+	for _, config := range results.ConfigResults.Elems {
+		for _, object := range config.Value.ObjectResults.Elems {
+			switch object.Value.Status {
+			case checks.StatusPass:
+				ret.Pass++
+			case checks.StatusFail:
+				ret.Fail++
+			case checks.StatusError:
+				ret.Error++
+			default:
+				ret.Unknown++
+			}
+		}
+	}
+	return ret
+}
+
+func (c *CheckResults) String() string {
+	return fmt.Sprintf("Checks: %d passed, %d failed, %d errored, %d unknown", c.Pass, c.Fail, c.Error, c.Unknown)
+}
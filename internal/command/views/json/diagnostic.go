@@ -38,6 +38,7 @@ type Diagnostic struct {
 	Summary  string             `json:"summary"`
 	Detail   string             `json:"detail"`
 	Address  string             `json:"address,omitempty"`
+	Code     string             `json:"code,omitempty"`
 	Range    *DiagnosticRange   `json:"range,omitempty"`
 	Snippet  *DiagnosticSnippet `json:"snippet,omitempty"`
 }
@@ -151,6 +152,7 @@ func NewDiagnostic(diag tfdiags.Diagnostic, sources map[string][]byte) *Diagnost
 		Summary:  desc.Summary,
 		Detail:   desc.Detail,
 		Address:  desc.Address,
+		Code:     string(tfdiags.DiagnosticCode(diag)),
 	}
 
 	sourceRefs := diag.Source()
@@ -67,6 +67,19 @@ func TestNewDiagnostic(t *testing.T) {
 				Detail:   "Something is broken",
 			},
 		},
+		"sourceless error with code": {
+			tfdiags.WithCode(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Error acquiring the state lock",
+				"The state is locked.",
+			), tfdiags.CodeStateLock),
+			&Diagnostic{
+				Severity: "error",
+				Summary:  "Error acquiring the state lock",
+				Detail:   "The state is locked.",
+				Code:     "OTF3002",
+			},
+		},
 		"error with source code unavailable": {
 			&hcl.Diagnostic{
 				Severity: hcl.DiagError,
@@ -0,0 +1,382 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package json
+
+import (
+	"fmt"
+)
+
+// InitEvent is a message describing the progress of "tofu init".
+type InitEvent interface {
+	InitEventType() MessageType
+	String() string
+}
+
+// InitStep is one of the steps of "tofu init", which run in this order.
+type InitStep string
+
+const (
+	InitStepBackend   InitStep = "backend"
+	InitStepModules   InitStep = "modules"
+	InitStepProviders InitStep = "providers"
+)
+
+func (s InitStep) description() string {
+	switch s {
+	case InitStepBackend:
+		return "the backend"
+	case InitStepModules:
+		return "modules"
+	case InitStepProviders:
+		return "provider plugins"
+	default:
+		return string(s)
+	}
+}
+
+// initStepStart: a step of init has started
+type initStepStart struct {
+	Step InitStep `json:"step"`
+}
+
+var _ InitEvent = (*initStepStart)(nil)
+
+func (e *initStepStart) InitEventType() MessageType {
+	return MessageInitStepStart
+}
+
+func (e *initStepStart) String() string {
+	return fmt.Sprintf("Initializing %s...", e.Step.description())
+}
+
+func NewInitStepStart(step InitStep) InitEvent {
+	return &initStepStart{Step: step}
+}
+
+// initStepComplete: a step of init has completed successfully
+type initStepComplete struct {
+	Step InitStep `json:"step"`
+}
+
+var _ InitEvent = (*initStepComplete)(nil)
+
+func (e *initStepComplete) InitEventType() MessageType {
+	return MessageInitStepComplete
+}
+
+func (e *initStepComplete) String() string {
+	return fmt.Sprintf("Initialized %s", e.Step.description())
+}
+
+func NewInitStepComplete(step InitStep) InitEvent {
+	return &initStepComplete{Step: step}
+}
+
+// initStepErrored: a step of init has failed. The diagnostics that describe
+// the failure follow this message.
+type initStepErrored struct {
+	Step InitStep `json:"step"`
+}
+
+var _ InitEvent = (*initStepErrored)(nil)
+
+func (e *initStepErrored) InitEventType() MessageType {
+	return MessageInitStepErrored
+}
+
+func (e *initStepErrored) String() string {
+	return fmt.Sprintf("Failed to initialize %s", e.Step.description())
+}
+
+func NewInitStepErrored(step InitStep) InitEvent {
+	return &initStepErrored{Step: step}
+}
+
+// initBackend: the backend has been initialized
+type initBackend struct {
+	Type string `json:"type"`
+}
+
+var _ InitEvent = (*initBackend)(nil)
+
+func (e *initBackend) InitEventType() MessageType {
+	return MessageInitBackend
+}
+
+func (e *initBackend) String() string {
+	return fmt.Sprintf("Using backend %q", e.Type)
+}
+
+func NewInitBackend(backendType string) InitEvent {
+	return &initBackend{Type: backendType}
+}
+
+// initModuleDownload: a module package is being downloaded
+type initModuleDownload struct {
+	Module  string `json:"module"`
+	Source  string `json:"source"`
+	Version string `json:"version,omitempty"`
+}
+
+var _ InitEvent = (*initModuleDownload)(nil)
+
+func (e *initModuleDownload) InitEventType() MessageType {
+	return MessageInitModuleDownload
+}
+
+func (e *initModuleDownload) String() string {
+	if e.Version != "" {
+		return fmt.Sprintf("Downloading %s %s for %s...", e.Source, e.Version, e.Module)
+	}
+	return fmt.Sprintf("Downloading %s for %s...", e.Source, e.Module)
+}
+
+func NewInitModuleDownload(module, source, version string) InitEvent {
+	return &initModuleDownload{
+		Module:  module,
+		Source:  source,
+		Version: version,
+	}
+}
+
+// initModuleInstalled: a module has been installed
+type initModuleInstalled struct {
+	Module  string `json:"module"`
+	Version string `json:"version,omitempty"`
+	Dir     string `json:"dir"`
+}
+
+var _ InitEvent = (*initModuleInstalled)(nil)
+
+func (e *initModuleInstalled) InitEventType() MessageType {
+	return MessageInitModuleInstalled
+}
+
+func (e *initModuleInstalled) String() string {
+	return fmt.Sprintf("- %s in %s", e.Module, e.Dir)
+}
+
+func NewInitModuleInstalled(module, version, dir string) InitEvent {
+	return &initModuleInstalled{
+		Module:  module,
+		Version: version,
+		Dir:     dir,
+	}
+}
+
+// initProviderQuery: OpenTofu is selecting a version of a provider
+type initProviderQuery struct {
+	Provider    string `json:"provider"`
+	Constraints string `json:"constraints,omitempty"`
+	Locked      bool   `json:"locked"`
+}
+
+var _ InitEvent = (*initProviderQuery)(nil)
+
+func (e *initProviderQuery) InitEventType() MessageType {
+	return MessageInitProviderQuery
+}
+
+func (e *initProviderQuery) String() string {
+	switch {
+	case e.Locked:
+		return fmt.Sprintf("- Reusing previous version of %s from the dependency lock file", e.Provider)
+	case e.Constraints != "":
+		return fmt.Sprintf("- Finding %s versions matching %q...", e.Provider, e.Constraints)
+	default:
+		return fmt.Sprintf("- Finding latest version of %s...", e.Provider)
+	}
+}
+
+func NewInitProviderQuery(provider, constraints string, locked bool) InitEvent {
+	return &initProviderQuery{
+		Provider:    provider,
+		Constraints: constraints,
+		Locked:      locked,
+	}
+}
+
+// initProviderDownload: a provider package is being downloaded
+type initProviderDownload struct {
+	Provider string `json:"provider"`
+	Version  string `json:"version"`
+}
+
+var _ InitEvent = (*initProviderDownload)(nil)
+
+func (e *initProviderDownload) InitEventType() MessageType {
+	return MessageInitProviderDownload
+}
+
+func (e *initProviderDownload) String() string {
+	return fmt.Sprintf("- Installing %s v%s...", e.Provider, e.Version)
+}
+
+func NewInitProviderDownload(provider, version string) InitEvent {
+	return &initProviderDownload{
+		Provider: provider,
+		Version:  version,
+	}
+}
+
+// InitDownloadKind is the kind of package whose download progress is
+// reported by an init_download_progress message.
+type InitDownloadKind string
+
+const (
+	InitDownloadProvider InitDownloadKind = "provider"
+	InitDownloadModule   InitDownloadKind = "module"
+)
+
+// initDownloadProgress: part of a provider or module package has been
+// downloaded
+type initDownloadProgress struct {
+	Kind    InitDownloadKind `json:"kind"`
+	Package string           `json:"package"`
+	Version string           `json:"version,omitempty"`
+
+	// Total is omitted if the size of the package isn't known in advance.
+	Received       int64 `json:"received_bytes"`
+	Total          int64 `json:"total_bytes,omitempty"`
+	BytesPerSecond int64 `json:"bytes_per_second"`
+}
+
+var _ InitEvent = (*initDownloadProgress)(nil)
+
+func (e *initDownloadProgress) InitEventType() MessageType {
+	return MessageInitDownloadProgress
+}
+
+func (e *initDownloadProgress) String() string {
+	name := e.Package
+	if e.Version != "" {
+		name = fmt.Sprintf("%s %s", e.Package, e.Version)
+	}
+	if e.Total > 0 {
+		return fmt.Sprintf("Downloading %s: %d of %d bytes", name, e.Received, e.Total)
+	}
+	return fmt.Sprintf("Downloading %s: %d bytes", name, e.Received)
+}
+
+// NewInitDownloadProgress returns an event for the progress of a download.
+// total is -1 if the size of the package isn't known.
+func NewInitDownloadProgress(kind InitDownloadKind, pkg, version string, received, total, bytesPerSecond int64) InitEvent {
+	return &initDownloadProgress{
+		Kind:           kind,
+		Package:        pkg,
+		Version:        version,
+		Received:       received,
+		Total:          max(total, 0),
+		BytesPerSecond: bytesPerSecond,
+	}
+}
+
+// InitProviderSource describes where an installed provider came from.
+type InitProviderSource string
+
+const (
+	InitProviderSourcePrevious InitProviderSource = "previous"
+	InitProviderSourceCache    InitProviderSource = "cache"
+	InitProviderSourceBuiltIn  InitProviderSource = "builtin"
+	InitProviderSourceDownload InitProviderSource = "download"
+)
+
+// initProviderInstalled: a provider is ready to use
+type initProviderInstalled struct {
+	Provider string             `json:"provider"`
+	Version  string             `json:"version,omitempty"`
+	Source   InitProviderSource `json:"source"`
+
+	// Authentication and KeyID are only set for downloaded providers, which
+	// are verified against the checksums and signatures of the registry.
+	Authentication string `json:"authentication,omitempty"`
+	KeyID          string `json:"key_id,omitempty"`
+}
+
+var _ InitEvent = (*initProviderInstalled)(nil)
+
+func (e *initProviderInstalled) InitEventType() MessageType {
+	return MessageInitProviderInstalled
+}
+
+func (e *initProviderInstalled) String() string {
+	switch e.Source {
+	case InitProviderSourcePrevious:
+		return fmt.Sprintf("- Using previously-installed %s v%s", e.Provider, e.Version)
+	case InitProviderSourceCache:
+		return fmt.Sprintf("- Using %s v%s from the shared cache directory", e.Provider, e.Version)
+	case InitProviderSourceBuiltIn:
+		return fmt.Sprintf("- %s is built in to OpenTofu", e.Provider)
+	}
+	if e.KeyID != "" {
+		return fmt.Sprintf("- Installed %s v%s (%s, key ID %s)", e.Provider, e.Version, e.Authentication, e.KeyID)
+	}
+	return fmt.Sprintf("- Installed %s v%s (%s)", e.Provider, e.Version, e.Authentication)
+}
+
+func NewInitProviderInstalled(provider, version string, source InitProviderSource, authentication, keyID string) InitEvent {
+	return &initProviderInstalled{
+		Provider:       provider,
+		Version:        version,
+		Source:         source,
+		Authentication: authentication,
+		KeyID:          keyID,
+	}
+}
+
+// initProviderErrored: a provider could not be selected or installed. The
+// diagnostics that describe the failure follow the init_step_errored message.
+type initProviderErrored struct {
+	Provider string `json:"provider"`
+	Version  string `json:"version,omitempty"`
+	Error    string `json:"error"`
+}
+
+var _ InitEvent = (*initProviderErrored)(nil)
+
+func (e *initProviderErrored) InitEventType() MessageType {
+	return MessageInitProviderErrored
+}
+
+func (e *initProviderErrored) String() string {
+	if e.Version != "" {
+		return fmt.Sprintf("- Failed to install %s v%s", e.Provider, e.Version)
+	}
+	return fmt.Sprintf("- Failed to install %s", e.Provider)
+}
+
+func NewInitProviderErrored(provider, version string, err error) InitEvent {
+	return &initProviderErrored{
+		Provider: provider,
+		Version:  version,
+		Error:    err.Error(),
+	}
+}
+
+// initLockUpdated: the dependency lock file entry for a provider has changed
+type initLockUpdated struct {
+	Provider string   `json:"provider"`
+	Version  string   `json:"version"`
+	Hashes   []string `json:"hashes"`
+}
+
+var _ InitEvent = (*initLockUpdated)(nil)
+
+func (e *initLockUpdated) InitEventType() MessageType {
+	return MessageInitLockUpdated
+}
+
+func (e *initLockUpdated) String() string {
+	return fmt.Sprintf("Recorded %s v%s in the dependency lock file", e.Provider, e.Version)
+}
+
+func NewInitLockUpdated(provider, version string, hashes []string) InitEvent {
+	return &initLockUpdated{
+		Provider: provider,
+		Version:  version,
+		Hashes:   hashes,
+	}
+}
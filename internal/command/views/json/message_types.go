@@ -18,6 +18,26 @@ const (
 	MessagePlannedChange MessageType = "planned_change"
 	MessageChangeSummary MessageType = "change_summary"
 	MessageOutputs       MessageType = "outputs"
+	MessageTimings       MessageType = "timings"
+	MessageCheckResults  MessageType = "check_results"
+
+	// Watch mode messages
+	MessageWatchCycleStart    MessageType = "watch_cycle_start"
+	MessageWatchCycleComplete MessageType = "watch_cycle_complete"
+
+	// Init messages
+	MessageInitStepStart         MessageType = "init_step_start"
+	MessageInitStepComplete      MessageType = "init_step_complete"
+	MessageInitStepErrored       MessageType = "init_step_errored"
+	MessageInitBackend           MessageType = "init_backend"
+	MessageInitModuleDownload    MessageType = "init_module_download"
+	MessageInitModuleInstalled   MessageType = "init_module_installed"
+	MessageInitProviderQuery     MessageType = "init_provider_query"
+	MessageInitProviderDownload  MessageType = "init_provider_download"
+	MessageInitProviderInstalled MessageType = "init_provider_installed"
+	MessageInitProviderErrored   MessageType = "init_provider_errored"
+	MessageInitLockUpdated       MessageType = "init_lock_updated"
+	MessageInitDownloadProgress  MessageType = "init_download_progress"
 
 	// Hook-driven messages
 	MessageApplyStart        MessageType = "apply_start"
@@ -40,4 +60,5 @@ const (
 	MessageTestSummary   MessageType = "test_summary"
 	MessageTestCleanup   MessageType = "test_cleanup"
 	MessageTestInterrupt MessageType = "test_interrupt"
+	MessageTestCoverage  MessageType = "test_coverage"
 )
@@ -0,0 +1,217 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package json
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+const (
+	// SARIFVersion is the version of the SARIF specification that SARIFLog
+	// conforms to.
+	SARIFVersion = "2.1.0"
+
+	// SARIFSchema is the location of the JSON schema for SARIFVersion.
+	SARIFSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// SARIFLog is a Static Analysis Results Interchange Format (SARIF) log, which
+// code scanning tools such as those of GitHub and GitLab can use to annotate
+// source code with diagnostics.
+//
+// Only the subset of SARIF that OpenTofu uses is represented here.
+type SARIFLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+type SARIFRun struct {
+	Tool    SARIFTool     `json:"tool"`
+	Results []SARIFResult `json:"results"`
+}
+
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+type SARIFRule struct {
+	ID               string        `json:"id"`
+	ShortDescription SARIFMessage  `json:"shortDescription"`
+	FullDescription  *SARIFMessage `json:"fullDescription,omitempty"`
+}
+
+type SARIFResult struct {
+	RuleID     string                 `json:"ruleId"`
+	RuleIndex  int                    `json:"ruleIndex"`
+	Level      string                 `json:"level"`
+	Message    SARIFMessage           `json:"message"`
+	Locations  []SARIFLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type SARIFMessage struct {
+	Text string `json:"text"`
+}
+
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+}
+
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           *SARIFRegion          `json:"region,omitempty"`
+}
+
+type SARIFArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is a range of source code. Lines and columns are one-based,
+// and the end column is exclusive.
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+// NewSARIFLog returns a SARIF log with a single run of OpenTofu and no
+// results yet. Use AddDiagnostic to add results to it.
+func NewSARIFLog(toolVersion string) *SARIFLog {
+	return &SARIFLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs: []SARIFRun{
+			{
+				Tool: SARIFTool{
+					Driver: SARIFDriver{
+						Name:           "OpenTofu",
+						Version:        toolVersion,
+						InformationURI: "https://opentofu.org",
+						Rules:          []SARIFRule{},
+					},
+				},
+				Results: []SARIFResult{},
+			},
+		},
+	}
+}
+
+// AddDiagnostic adds a result for the given diagnostic to the log, along
+// with a rule for the kind of diagnostic if the log doesn't have one yet.
+//
+// The properties, if any, are recorded in the property bag of the result.
+func (l *SARIFLog) AddDiagnostic(diag *Diagnostic, properties map[string]interface{}) {
+	run := &l.Runs[0]
+
+	ruleID, rule := sarifRule(diag)
+	ruleIndex := -1
+	for i, existing := range run.Tool.Driver.Rules {
+		if existing.ID == ruleID {
+			ruleIndex = i
+			break
+		}
+	}
+	if ruleIndex == -1 {
+		ruleIndex = len(run.Tool.Driver.Rules)
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+	}
+
+	message := diag.Summary
+	if diag.Detail != "" {
+		message += "\n\n" + diag.Detail
+	}
+
+	result := SARIFResult{
+		RuleID:    ruleID,
+		RuleIndex: ruleIndex,
+		Level:     sarifLevel(diag.Severity),
+		Message:   SARIFMessage{Text: message},
+	}
+	if len(properties) > 0 || diag.Address != "" {
+		// We copy the properties because the caller might be using the
+		// same map for many results.
+		result.Properties = make(map[string]interface{}, len(properties)+1)
+		for k, v := range properties {
+			result.Properties[k] = v
+		}
+		if diag.Address != "" {
+			result.Properties["address"] = diag.Address
+		}
+	}
+	if diag.Range != nil {
+		result.Locations = []SARIFLocation{
+			{
+				PhysicalLocation: SARIFPhysicalLocation{
+					ArtifactLocation: SARIFArtifactLocation{
+						URI: filepath.ToSlash(diag.Range.Filename),
+					},
+					Region: &SARIFRegion{
+						StartLine:   diag.Range.Start.Line,
+						StartColumn: diag.Range.Start.Column,
+						EndLine:     diag.Range.End.Line,
+						EndColumn:   diag.Range.End.Column,
+					},
+				},
+			},
+		}
+	}
+
+	run.Results = append(run.Results, result)
+}
+
+// sarifRuleNonAlnum matches the runs of characters that are replaced when
+// making a rule ID from a diagnostic summary.
+var sarifRuleNonAlnum = regexp.MustCompile(`[^a-z0-9]+`)
+
+// sarifRule returns the ID and description of the rule for the kind of the
+// given diagnostic.
+//
+// Diagnostics that have a code use that code as the rule ID. Others don't
+// have a stable identity, so we use an ID derived from their summary, which
+// is still enough for code scanning tools to group similar results.
+func sarifRule(diag *Diagnostic) (string, SARIFRule) {
+	if diag.Code != "" {
+		rule := SARIFRule{
+			ID:               diag.Code,
+			ShortDescription: SARIFMessage{Text: diag.Summary},
+		}
+		if info, ok := tfdiags.LookupCode(diag.Code); ok {
+			rule.ShortDescription.Text = info.Summary
+			rule.FullDescription = &SARIFMessage{Text: info.Explanation}
+		}
+		return rule.ID, rule
+	}
+
+	id := "tofu/" + strings.Trim(sarifRuleNonAlnum.ReplaceAllString(strings.ToLower(diag.Summary), "-"), "-")
+	return id, SARIFRule{
+		ID:               id,
+		ShortDescription: SARIFMessage{Text: diag.Summary},
+	}
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case DiagnosticSeverityError:
+		return "error"
+	case DiagnosticSeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package json
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestSARIFLog(t *testing.T) {
+	sources := map[string][]byte{
+		"main.tf": []byte("locals {\n  a = var.nope\n}\n"),
+	}
+
+	var diags tfdiags.Diagnostics
+	diags = diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Reference to undeclared input variable",
+		Detail:   "An input variable with the name \"nope\" has not been declared.",
+		Subject: &hcl.Range{
+			Filename: "main.tf",
+			Start:    hcl.Pos{Line: 2, Column: 7, Byte: 15},
+			End:      hcl.Pos{Line: 2, Column: 15, Byte: 23},
+		},
+		Extra: tfdiags.CodeUndeclaredVariable,
+	})
+	diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Deprecated: Use of foo!",
+		"",
+	))
+	diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Warning,
+		"Deprecated: Use of foo!",
+		"Again.",
+	))
+
+	log := NewSARIFLog("1.2.3")
+	for _, diag := range diags {
+		log.AddDiagnostic(NewDiagnostic(diag, sources), map[string]interface{}{"testfile": "main.tftest.hcl"})
+	}
+
+	info, _ := tfdiags.LookupCode(string(tfdiags.CodeUndeclaredVariable))
+	want := &SARIFLog{
+		Schema:  SARIFSchema,
+		Version: SARIFVersion,
+		Runs: []SARIFRun{
+			{
+				Tool: SARIFTool{
+					Driver: SARIFDriver{
+						Name:           "OpenTofu",
+						Version:        "1.2.3",
+						InformationURI: "https://opentofu.org",
+						Rules: []SARIFRule{
+							{
+								ID:               "OTF2001",
+								ShortDescription: SARIFMessage{Text: info.Summary},
+								FullDescription:  &SARIFMessage{Text: info.Explanation},
+							},
+							{
+								ID:               "tofu/deprecated-use-of-foo",
+								ShortDescription: SARIFMessage{Text: "Deprecated: Use of foo!"},
+							},
+						},
+					},
+				},
+				Results: []SARIFResult{
+					{
+						RuleID:    "OTF2001",
+						RuleIndex: 0,
+						Level:     "error",
+						Message: SARIFMessage{
+							Text: "Reference to undeclared input variable\n\nAn input variable with the name \"nope\" has not been declared.",
+						},
+						Locations: []SARIFLocation{
+							{
+								PhysicalLocation: SARIFPhysicalLocation{
+									ArtifactLocation: SARIFArtifactLocation{URI: "main.tf"},
+									Region: &SARIFRegion{
+										StartLine:   2,
+										StartColumn: 7,
+										EndLine:     2,
+										EndColumn:   15,
+									},
+								},
+							},
+						},
+						Properties: map[string]interface{}{"testfile": "main.tftest.hcl"},
+					},
+					{
+						RuleID:     "tofu/deprecated-use-of-foo",
+						RuleIndex:  1,
+						Level:      "warning",
+						Message:    SARIFMessage{Text: "Deprecated: Use of foo!"},
+						Properties: map[string]interface{}{"testfile": "main.tftest.hcl"},
+					},
+					{
+						RuleID:     "tofu/deprecated-use-of-foo",
+						RuleIndex:  1,
+						Level:      "warning",
+						Message:    SARIFMessage{Text: "Deprecated: Use of foo!\n\nAgain."},
+						Properties: map[string]interface{}{"testfile": "main.tftest.hcl"},
+					},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(want, log); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
@@ -15,23 +15,34 @@ type TestSuiteAbstract map[string][]string
 
 type TestStatus string
 
+// TestFileStatus and TestRunStatus include how long the file or run took to
+// execute, in seconds. It is left out for runs that were skipped.
 type TestFileStatus struct {
-	Path   string     `json:"path"`
-	Status TestStatus `json:"status"`
+	Path     string     `json:"path"`
+	Status   TestStatus `json:"status"`
+	Duration float64    `json:"duration,omitempty"`
 }
 
 type TestRunStatus struct {
-	Path   string     `json:"path"`
-	Run    string     `json:"run"`
-	Status TestStatus `json:"status"`
+	Path        string     `json:"path"`
+	Run         string     `json:"run"`
+	Status      TestStatus `json:"status"`
+	Duration    float64    `json:"duration,omitempty"`
+	Attempts    int        `json:"attempts,omitempty"`
+	Quarantined bool       `json:"quarantined,omitempty"`
 }
 
+// TestSuiteSummary counts the run blocks by status. Run blocks that passed
+// after being retried are also counted in PassedOnRetry, while quarantined
+// run blocks that failed or errored are only counted in Quarantined.
 type TestSuiteSummary struct {
-	Status  TestStatus `json:"status"`
-	Passed  int        `json:"passed"`
-	Failed  int        `json:"failed"`
-	Errored int        `json:"errored"`
-	Skipped int        `json:"skipped"`
+	Status        TestStatus `json:"status"`
+	Passed        int        `json:"passed"`
+	Failed        int        `json:"failed"`
+	Errored       int        `json:"errored"`
+	Skipped       int        `json:"skipped"`
+	PassedOnRetry int        `json:"passed_on_retry,omitempty"`
+	Quarantined   int        `json:"quarantined,omitempty"`
 }
 
 type TestFileCleanup struct {
@@ -49,6 +60,43 @@ type TestFatalInterrupt struct {
 	Planned []string                        `json:"planned,omitempty"`
 }
 
+type TestCoverage struct {
+	Covered int                `json:"covered"`
+	Total   int                `json:"total"`
+	Percent float64            `json:"percent"`
+	Items   []TestCoverageItem `json:"items"`
+}
+
+type TestCoverageItem struct {
+	Kind     string `json:"kind"`
+	Address  string `json:"address"`
+	Branch   string `json:"branch,omitempty"`
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+	Covered  bool   `json:"covered"`
+}
+
+func ToTestCoverage(report *moduletest.CoverageReport) TestCoverage {
+	covered, total := report.Count("")
+	coverage := TestCoverage{
+		Covered: covered,
+		Total:   total,
+		Percent: report.Percent(),
+		Items:   make([]TestCoverageItem, 0, len(report.Items)),
+	}
+	for _, item := range report.Items {
+		coverage.Items = append(coverage.Items, TestCoverageItem{
+			Kind:     string(item.Kind),
+			Address:  item.Address,
+			Branch:   item.Branch,
+			Filename: item.DeclRange.Filename,
+			Line:     item.DeclRange.Start.Line,
+			Covered:  item.Covered,
+		})
+	}
+	return coverage
+}
+
 func ToTestStatus(status moduletest.Status) TestStatus {
 	return TestStatus(strings.ToLower(status.String()))
 }
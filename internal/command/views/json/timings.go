@@ -0,0 +1,57 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package json
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// Timings reports how long OpenTofu spent on each resource instance during
+// an operation, as requested with the -timings option.
+type Timings struct {
+	Resources []ResourceTiming `json:"resources"`
+	Total     PhaseTimings     `json:"total"`
+}
+
+// ResourceTiming reports the time spent on a single resource instance.
+type ResourceTiming struct {
+	Resource ResourceAddr `json:"resource"`
+	PhaseTimings
+}
+
+// PhaseTimings breaks down time spent by the phase of the operation, in
+// seconds. Provider is the part of the other phases spent waiting for
+// responses from the provider, rather than an additional phase.
+type PhaseTimings struct {
+	Refresh  float64 `json:"refresh_seconds"`
+	Plan     float64 `json:"plan_seconds"`
+	Apply    float64 `json:"apply_seconds"`
+	Provider float64 `json:"provider_seconds"`
+}
+
+func NewPhaseTimings(refresh, plan, apply, provider time.Duration) PhaseTimings {
+	return PhaseTimings{
+		Refresh:  refresh.Seconds(),
+		Plan:     plan.Seconds(),
+		Apply:    apply.Seconds(),
+		Provider: provider.Seconds(),
+	}
+}
+
+func NewResourceTiming(addr addrs.AbsResourceInstance, timings PhaseTimings) ResourceTiming {
+	return ResourceTiming{
+		Resource:     newResourceAddr(addr),
+		PhaseTimings: timings,
+	}
+}
+
+func (t *Timings) String() string {
+	total := t.Total.Refresh + t.Total.Plan + t.Total.Apply
+	return fmt.Sprintf("Timings: %d resource instances, %.2fs in total", len(t.Resources), total)
+}
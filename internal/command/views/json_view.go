@@ -8,6 +8,7 @@ package views
 import (
 	encJson "encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/go-hclog"
 
@@ -19,7 +20,7 @@ import (
 // This version describes the schema of JSON UI messages. This version must be
 // updated after making any changes to this view, the jsonHook, or any of the
 // command/views/json package.
-const JSON_UI_VERSION = "1.2"
+const JSON_UI_VERSION = "1.9"
 
 func NewJSONView(view *View) *JSONView {
 	log := hclog.New(&hclog.LoggerOptions{
@@ -114,6 +115,39 @@ func (v *JSONView) ChangeSummary(cs *json.ChangeSummary) {
 	)
 }
 
+func (v *JSONView) Timings(timings *json.Timings) {
+	v.log.Info(
+		timings.String(),
+		"type", json.MessageTimings,
+		"timings", timings,
+	)
+}
+
+func (v *JSONView) CheckResults(results *json.CheckResults) {
+	v.log.Info(
+		results.String(),
+		"type", json.MessageCheckResults,
+		"checks", results,
+	)
+}
+
+func (v *JSONView) WatchCycleStart(cycle int) {
+	v.log.Info(
+		fmt.Sprintf("Watch cycle %d started", cycle),
+		"type", json.MessageWatchCycleStart,
+		"cycle", cycle,
+	)
+}
+
+func (v *JSONView) WatchCycleComplete(cycle int, next time.Time) {
+	v.log.Info(
+		fmt.Sprintf("Watch cycle %d complete", cycle),
+		"type", json.MessageWatchCycleComplete,
+		"cycle", cycle,
+		"next_cycle", next.Format(time.RFC3339),
+	)
+}
+
 func (v *JSONView) Hook(h json.Hook) {
 	v.log.Info(
 		h.String(),
@@ -122,6 +156,14 @@ func (v *JSONView) Hook(h json.Hook) {
 	)
 }
 
+func (v *JSONView) InitEvent(e json.InitEvent) {
+	v.log.Info(
+		e.String(),
+		"type", e.InitEventType(),
+		"init", e,
+	)
+}
+
 func (v *JSONView) Outputs(outputs json.Outputs) {
 	v.log.Info(
 		outputs.String(),
@@ -15,6 +15,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/command/jsonschema"
 	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/terminal"
@@ -289,6 +290,34 @@ func TestJSONView_ChangeSummary(t *testing.T) {
 	testJSONViewOutputEquals(t, done(t).Stdout(), want)
 }
 
+func TestJSONView_WatchCycle(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	jv := NewJSONView(NewView(streams))
+
+	next := time.Date(2021, 5, 25, 13, 37, 41, 0, time.UTC)
+	jv.WatchCycleStart(2)
+	jv.WatchCycleComplete(2, next)
+
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "Watch cycle 2 started",
+			"@module":  "tofu.ui",
+			"type":     "watch_cycle_start",
+			"cycle":    float64(2),
+		},
+		{
+			"@level":     "info",
+			"@message":   "Watch cycle 2 complete",
+			"@module":    "tofu.ui",
+			"type":       "watch_cycle_complete",
+			"cycle":      float64(2),
+			"next_cycle": "2021-05-25T13:37:41Z",
+		},
+	}
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
+
 func TestJSONView_ChangeSummaryWithImport(t *testing.T) {
 	streams, done := terminal.StreamsForTesting(t)
 	jv := NewJSONView(NewView(streams))
@@ -459,3 +488,39 @@ func testJSONViewOutputEquals(t *testing.T, output string, want []map[string]int
 	}
 	testJSONViewOutputEqualsFull(t, output, want, options...)
 }
+
+// The messages of the JSON view must conform to the published schema of the
+// UI format.
+func TestJSONView_schema(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	jv := NewJSONView(NewView(streams))
+
+	foo, diags := addrs.ParseModuleInstanceStr("module.foo")
+	if len(diags) > 0 {
+		t.Fatal(diags.Err())
+	}
+	managed := addrs.Resource{Mode: addrs.ManagedResourceMode, Type: "test_instance", Name: "bar"}
+	addr := managed.Instance(addrs.IntKey(0)).Absolute(foo)
+
+	jv.Log("hello, world")
+	diags = diags.Append(tfdiags.Sourceless(tfdiags.Warning, "Something odd", "It's probably fine."))
+	jv.Diagnostics(diags)
+	jv.PlannedChange(viewsjson.NewResourceInstanceChange(&plans.ResourceInstanceChangeSrc{
+		Addr:        addr,
+		PrevRunAddr: addr,
+		ChangeSrc:   plans.ChangeSrc{Action: plans.DeleteThenCreate},
+	}))
+	jv.ChangeSummary(&viewsjson.ChangeSummary{Add: 1, Remove: 1, Operation: viewsjson.OperationPlanned})
+	jv.Hook(viewsjson.NewApplyComplete(addr, plans.Create, "id", "boop-beep", 34*time.Second))
+	jv.Outputs(viewsjson.Outputs{
+		"password": {
+			Sensitive: true,
+			Value:     json.RawMessage(`"horse-battery"`),
+			Type:      json.RawMessage(`"string"`),
+		},
+	})
+
+	if err := jsonschema.Validate(jsonschema.UI, jsonschema.LatestVersion, []byte(done(t).Stdout())); err != nil {
+		t.Fatalf("messages don't conform to the schema:\n%s", err)
+	}
+}
@@ -18,7 +18,9 @@ import (
 	"github.com/opentofu/opentofu/internal/command/jsonprovider"
 	"github.com/opentofu/opentofu/internal/command/views/json"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/i18n"
 	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
@@ -35,6 +37,7 @@ type Operation interface {
 	PlannedChange(change *plans.ResourceInstanceChangeSrc)
 	Plan(plan *plans.Plan, schemas *tofu.Schemas)
 	PlanNextStep(planPath string, genConfigPath string)
+	CheckResults(results *states.CheckResults)
 
 	Diagnostics(diags tfdiags.Diagnostics)
 }
@@ -64,23 +67,23 @@ type OperationHuman struct {
 var _ Operation = (*OperationHuman)(nil)
 
 func (v *OperationHuman) Interrupted() {
-	v.view.streams.Println(format.WordWrap(interrupted, v.view.outputColumns()))
+	v.view.streams.Println(format.WordWrap(i18n.T(interrupted), v.view.outputColumns()))
 }
 
 func (v *OperationHuman) FatalInterrupt() {
-	v.view.streams.Eprintln(format.WordWrap(fatalInterrupt, v.view.errorColumns()))
+	v.view.streams.Eprintln(format.WordWrap(i18n.T(fatalInterrupt), v.view.errorColumns()))
 }
 
 func (v *OperationHuman) Stopping() {
-	v.view.streams.Println("Stopping operation...")
+	v.view.streams.Println(i18n.T("Stopping operation..."))
 }
 
 func (v *OperationHuman) Cancelled(planMode plans.Mode) {
 	switch planMode {
 	case plans.DestroyMode:
-		v.view.streams.Println("Destroy cancelled.")
+		v.view.streams.Println(i18n.T("Destroy cancelled."))
 	default:
-		v.view.streams.Println("Apply cancelled.")
+		v.view.streams.Println(i18n.T("Apply cancelled."))
 	}
 }
 
@@ -105,6 +108,10 @@ func (v *OperationHuman) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 		Colorize:            v.view.colorize,
 		Streams:             v.view.streams,
 		RunningInAutomation: v.inAutomation,
+		FullStringDiff:      v.view.fullStringDiff,
+		SuppressAttributes:  v.view.suppressAttributes,
+		Summary:             v.view.summary,
+		SummaryExpand:       v.view.summaryExpand,
 	}
 
 	jplan := jsonformat.Plan{
@@ -136,6 +143,11 @@ func (v *OperationHuman) PlannedChange(change *plans.ResourceInstanceChangeSrc)
 	// change details for all resource instances.
 }
 
+// CheckResults does nothing for the human-readable view, because any failed
+// checks are already reported as diagnostics.
+func (v *OperationHuman) CheckResults(results *states.CheckResults) {
+}
+
 // PlanNextStep gives the user some next-steps, unless we're running in an
 // automation tool which is presumed to provide its own UI for further actions.
 func (v *OperationHuman) PlanNextStep(planPath string, genConfigPath string) {
@@ -255,6 +267,8 @@ func (v *OperationJSON) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
 
 	v.view.ChangeSummary(cs)
 
+	v.CheckResults(plan.Checks)
+
 	var rootModuleOutputs []*plans.OutputChangeSrc
 	for _, output := range plan.Changes.Outputs {
 		if !output.Addr.Module.IsRoot() {
@@ -280,6 +294,15 @@ func (v *OperationJSON) PlannedChange(change *plans.ResourceInstanceChangeSrc) {
 func (v *OperationJSON) PlanNextStep(planPath string, genConfigPath string) {
 }
 
+// CheckResults logs the results of all of the checks in the configuration,
+// if there are any.
+func (v *OperationJSON) CheckResults(results *states.CheckResults) {
+	if results == nil || results.ConfigResults.Len() == 0 {
+		return
+	}
+	v.view.CheckResults(json.NewCheckResults(results))
+}
+
 func (v *OperationJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -11,7 +11,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/hcl/v2"
+
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/lang/globalref"
@@ -1330,3 +1333,92 @@ func TestOperationJSON_plannedChange(t *testing.T) {
 
 	testJSONViewOutputEquals(t, done(t).Stdout(), want)
 }
+
+func TestOperationJSON_checkResults(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	v := &OperationJSON{view: NewJSONView(NewView(streams))}
+
+	// No message is logged when the configuration has no checks.
+	v.CheckResults(&states.CheckResults{})
+
+	checkAddr := addrs.Check{Name: "health"}
+	checkInstAddr := checkAddr.Absolute(addrs.RootModuleInstance.Child("child", addrs.NoKey))
+	v.CheckResults(&states.CheckResults{
+		ConfigResults: addrs.MakeMap(
+			addrs.MakeMapElem[addrs.ConfigCheckable](checkAddr.InModule(addrs.RootModule.Child("child")), &states.CheckResultAggregate{
+				Status: checks.StatusFail,
+				ObjectResults: addrs.MakeMap(
+					addrs.MakeMapElem[addrs.Checkable](checkInstAddr, &states.CheckResultObject{
+						Status:          checks.StatusFail,
+						FailureMessages: []string{"Unhealthy."},
+						Rules: []checks.RuleResult{
+							{
+								Rule:           addrs.NewCheckRule(checkInstAddr, addrs.CheckAssertion, 0),
+								Status:         checks.StatusFail,
+								FailureMessage: "Unhealthy.",
+								DeclRange: hcl.Range{
+									Filename: "child/main.tf",
+									Start:    hcl.Pos{Line: 2, Column: 3, Byte: 20},
+									End:      hcl.Pos{Line: 2, Column: 9, Byte: 26},
+								},
+							},
+						},
+					}),
+				),
+			}),
+		),
+	})
+
+	rng := map[string]interface{}{
+		"filename": "child/main.tf",
+		"start":    map[string]interface{}{"line": float64(2), "column": float64(3), "byte": float64(20)},
+		"end":      map[string]interface{}{"line": float64(2), "column": float64(9), "byte": float64(26)},
+	}
+	want := []map[string]interface{}{
+		{
+			"@level":   "info",
+			"@message": "Checks: 0 passed, 1 failed, 0 errored, 0 unknown",
+			"@module":  "tofu.ui",
+			"type":     "check_results",
+			"checks": map[string]interface{}{
+				"pass":    float64(0),
+				"fail":    float64(1),
+				"error":   float64(0),
+				"unknown": float64(0),
+				"results": []interface{}{
+					map[string]interface{}{
+						"address": map[string]interface{}{
+							"kind":       "check",
+							"module":     "module.child",
+							"name":       "health",
+							"to_display": "module.child.check.health",
+						},
+						"status": "fail",
+						"instances": []interface{}{
+							map[string]interface{}{
+								"address": map[string]interface{}{
+									"module":     "module.child",
+									"to_display": "module.child.check.health",
+								},
+								"status": "fail",
+								"problems": []interface{}{
+									map[string]interface{}{"message": "Unhealthy.", "range": rng},
+								},
+								"conditions": []interface{}{
+									map[string]interface{}{
+										"address": "module.child.check.health.assert[0]",
+										"status":  "fail",
+										"message": "Unhealthy.",
+										"range":   rng,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	testJSONViewOutputEquals(t, done(t).Stdout(), want)
+}
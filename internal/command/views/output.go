@@ -17,6 +17,7 @@ import (
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/format"
 	"github.com/opentofu/opentofu/internal/repl"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -259,6 +260,202 @@ func (v *OutputJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
 
+// The OutputEnv implementation renders outputs as environment variable
+// assignments, in one of several syntaxes. The variable names are the output
+// names in upper case, with any characters that aren't valid in variable
+// names replaced by underscores.
+//
+// Sensitive output values are omitted unless showSensitive is set, because
+// the result is usually written somewhere, such as a file, that doesn't
+// otherwise contain secrets.
+type OutputEnv struct {
+	view          *View
+	format        arguments.EnvFormat
+	showSensitive bool
+}
+
+var _ Output = (*OutputEnv)(nil)
+
+// NewOutputEnv returns an Output implementation that renders outputs as
+// environment variable assignments in the given format.
+func NewOutputEnv(format arguments.EnvFormat, showSensitive bool, view *View) Output {
+	return &OutputEnv{
+		view:          view,
+		format:        format,
+		showSensitive: showSensitive,
+	}
+}
+
+func (v *OutputEnv) Output(name string, outputs map[string]*states.OutputValue) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	if len(outputs) == 0 {
+		diags = diags.Append(noOutputsWarning())
+		return diags
+	}
+
+	names := make([]string, 0, len(outputs))
+	if name != "" {
+		if _, ok := outputs[name]; !ok {
+			diags = diags.Append(missingOutputError(name))
+			return diags
+		}
+		names = append(names, name)
+	} else {
+		for name := range outputs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var buf strings.Builder
+	var omitted []string
+	varNames := make(map[string]string, len(names))
+	for _, name := range names {
+		output := outputs[name]
+		if output.Sensitive && !v.showSensitive {
+			omitted = append(omitted, name)
+			continue
+		}
+
+		varName := envVarName(name)
+		if other, exists := varNames[varName]; exists {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Conflicting environment variable names",
+				fmt.Sprintf("The output values %q and %q would both be written as the environment variable %s.", other, name, varName),
+			))
+			continue
+		}
+		varNames[varName] = name
+
+		value, err := envVarValue(output.Value)
+		if err != nil {
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Unsupported value for environment variable output",
+				fmt.Sprintf("Cannot write output value %q as an environment variable: %s.", name, err),
+			))
+			continue
+		}
+
+		switch v.format {
+		case arguments.EnvFormatShell:
+			fmt.Fprintf(&buf, "export %s=%s\n", varName, shellQuote(value))
+		case arguments.EnvFormatGitHubEnv:
+			if !strings.ContainsAny(value, "\r\n") {
+				fmt.Fprintf(&buf, "%s=%s\n", varName, value)
+				continue
+			}
+			// Multi-line values use a delimiter, which must not appear in
+			// the value itself.
+			delim := "TOFU_OUTPUT_EOF"
+			for i := 1; strings.Contains(value, delim); i++ {
+				delim = fmt.Sprintf("TOFU_OUTPUT_EOF_%d", i)
+			}
+			fmt.Fprintf(&buf, "%s<<%s\n%s\n%s\n", varName, delim, value, delim)
+		default:
+			fmt.Fprintf(&buf, "%s=%s\n", varName, dotenvQuote(value))
+		}
+	}
+
+	if diags.HasErrors() {
+		return diags
+	}
+
+	if len(omitted) > 0 {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Sensitive output values omitted",
+			fmt.Sprintf("The following output values are sensitive, so they were not included: %s. Use the -show-sensitive option to include them.", strings.Join(omitted, ", ")),
+		))
+	}
+
+	v.view.streams.Print(buf.String())
+	return diags
+}
+
+// Diagnostics renders all diagnostics to stderr, including warnings, so that
+// they can't be mistaken for variable assignments by a program that reads
+// stdout.
+func (v *OutputEnv) Diagnostics(diags tfdiags.Diagnostics) {
+	diags.Sort()
+	for _, diag := range diags {
+		if v.view.colorize.Disable {
+			v.view.streams.Eprint(format.DiagnosticPlain(diag, v.view.configSources(), v.view.streams.Stderr.Columns()))
+		} else {
+			v.view.streams.Eprint(format.Diagnostic(diag, v.view.configSources(), v.view.colorize, v.view.streams.Stderr.Columns()))
+		}
+	}
+}
+
+// envVarName returns the name of the environment variable for the output
+// value with the given name.
+func envVarName(name string) string {
+	var buf strings.Builder
+	for i, r := range strings.ToUpper(name) {
+		switch {
+		case r >= 'A' && r <= 'Z', r == '_':
+			buf.WriteRune(r)
+		case r >= '0' && r <= '9':
+			if i == 0 {
+				buf.WriteRune('_')
+			}
+			buf.WriteRune(r)
+		default:
+			buf.WriteRune('_')
+		}
+	}
+	return buf.String()
+}
+
+// envVarValue returns the string to use as the value of an environment
+// variable. Strings, numbers, and booleans are written like -raw writes them,
+// and values of other types are written as JSON.
+func envVarValue(val cty.Value) (string, error) {
+	if !val.IsWhollyKnown() {
+		return "", fmt.Errorf("the value won't be known until after a successful tofu apply")
+	}
+	if val.IsNull() {
+		return "", nil
+	}
+
+	ty := val.Type()
+	if ty.IsPrimitiveType() {
+		strV, err := convert.Convert(val, cty.String)
+		if err != nil {
+			return "", err
+		}
+		return strV.AsString(), nil
+	}
+
+	unmarked, _ := val.UnmarkDeep()
+	j, err := ctyjson.Marshal(unmarked, ty)
+	if err != nil {
+		return "", err
+	}
+	return string(j), nil
+}
+
+// shellQuote quotes a string for POSIX shells, using single quotes so that
+// nothing in the string is interpreted by the shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// dotenvQuote quotes a string for a .env file, using double quotes and
+// backslash escapes for the characters that are special inside them.
+func dotenvQuote(s string) string {
+	r := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"\n", `\n`,
+		"\r", `\r`,
+	)
+	return `"` + r.Replace(s) + `"`
+}
+
 // For text and raw output modes, an empty map of outputs is considered a
 // separate and higher priority failure mode than an output not being present
 // in a non-empty map. This warning diagnostic explains how this might have
@@ -366,3 +366,108 @@ func TestOutput_missing(t *testing.T) {
 		})
 	}
 }
+
+func TestOutputEnv(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"api-key": {
+			Value:     cty.StringVal("secret"),
+			Sensitive: true,
+		},
+		"region": {
+			Value: cty.StringVal("us-east-1"),
+		},
+		"count": {
+			Value: cty.NumberIntVal(3),
+		},
+		"motd": {
+			Value: cty.StringVal("it's \"$HOME\"\nand more"),
+		},
+		"tags": {
+			Value: cty.MapVal(map[string]cty.Value{
+				"env": cty.StringVal("prod"),
+			}),
+		},
+	}
+
+	testCases := map[string]struct {
+		format        arguments.EnvFormat
+		showSensitive bool
+		want          string
+	}{
+		"dotenv": {
+			arguments.EnvFormatDotenv,
+			false,
+			`COUNT="3"
+MOTD="it's \"\$HOME\"\nand more"
+REGION="us-east-1"
+TAGS="{\"env\":\"prod\"}"
+`,
+		},
+		"shell": {
+			arguments.EnvFormatShell,
+			true,
+			`export API_KEY='secret'
+export COUNT='3'
+export MOTD='it'\''s "$HOME"
+and more'
+export REGION='us-east-1'
+export TAGS='{"env":"prod"}'
+`,
+		},
+		"github-env": {
+			arguments.EnvFormatGitHubEnv,
+			false,
+			`COUNT=3
+MOTD<<TOFU_OUTPUT_EOF
+it's "$HOME"
+and more
+TOFU_OUTPUT_EOF
+REGION=us-east-1
+TAGS={"env":"prod"}
+`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			streams, done := terminal.StreamsForTesting(t)
+			v := NewOutputEnv(tc.format, tc.showSensitive, NewView(streams))
+			diags := v.Output("", outputs)
+
+			if diags.HasErrors() {
+				t.Fatalf("unexpected diagnostics: %s", diags.Err())
+			}
+			if tc.showSensitive && len(diags) != 0 {
+				t.Errorf("unexpected warnings: %#v", diags)
+			}
+			if !tc.showSensitive && (len(diags) != 1 || !strings.Contains(diags[0].Description().Detail, "api-key")) {
+				t.Errorf("expected a warning about the omitted sensitive value, got: %#v", diags)
+			}
+
+			if got := done(t).Stdout(); got != tc.want {
+				t.Errorf("wrong result\ngot:  %q\nwant: %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestOutputEnv_conflictingNames(t *testing.T) {
+	outputs := map[string]*states.OutputValue{
+		"foo-bar": {Value: cty.StringVal("a")},
+		"foo_bar": {Value: cty.StringVal("b")},
+	}
+
+	streams, done := terminal.StreamsForTesting(t)
+	v := NewOutputEnv(arguments.EnvFormatDotenv, false, NewView(streams))
+	diags := v.Output("", outputs)
+
+	if !diags.HasErrors() {
+		t.Fatal("expected an error")
+	}
+	if got, want := diags.Err().Error(), "Conflicting environment variable names"; !strings.Contains(got, want) {
+		t.Errorf("wrong error %q; want %q", got, want)
+	}
+	if got := done(t).Stdout(); got != "" {
+		t.Errorf("unexpected output: %q", got)
+	}
+}
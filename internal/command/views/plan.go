@@ -18,6 +18,10 @@ type Plan interface {
 	Operation() Operation
 	Hooks() []tofu.Hook
 
+	// Timings reports how long the operation spent on each resource
+	// instance, as recorded by the hooks.
+	Timings()
+
 	Diagnostics(diags tfdiags.Diagnostics)
 	HelpPrompt()
 }
@@ -27,12 +31,14 @@ func NewPlan(vt arguments.ViewType, view *View) Plan {
 	switch vt {
 	case arguments.ViewJSON:
 		return &PlanJSON{
-			view: NewJSONView(view),
+			view:        NewJSONView(view),
+			timingsHook: newTimingsHook(),
 		}
 	case arguments.ViewHuman:
 		return &PlanHuman{
 			view:         view,
 			inAutomation: view.RunningInAutomation(),
+			timingsHook:  newTimingsHook(),
 		}
 	default:
 		panic(fmt.Sprintf("unknown view type %v", vt))
@@ -45,6 +51,8 @@ type PlanHuman struct {
 	view *View
 
 	inAutomation bool
+
+	timingsHook *timingsHook
 }
 
 var _ Plan = (*PlanHuman)(nil)
@@ -56,9 +64,14 @@ func (v *PlanHuman) Operation() Operation {
 func (v *PlanHuman) Hooks() []tofu.Hook {
 	return []tofu.Hook{
 		NewUiHook(v.view),
+		v.timingsHook,
 	}
 }
 
+func (v *PlanHuman) Timings() {
+	v.view.streams.Print(v.timingsHook.Render())
+}
+
 func (v *PlanHuman) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
@@ -67,10 +80,65 @@ func (v *PlanHuman) HelpPrompt() {
 	v.view.HelpPrompt("plan")
 }
 
+// NewPlanTUI returns a Plan implementation that shows the progress of the
+// operation in a full-screen, interactive display, and otherwise renders the
+// same output as the human-readable view.
+func NewPlanTUI(view *View) Plan {
+	display := newTUIDisplay("OpenTofu plan", view)
+	return &PlanTUI{
+		PlanHuman: &PlanHuman{
+			view:         view,
+			inAutomation: view.RunningInAutomation(),
+			timingsHook:  newTimingsHook(),
+		},
+		display: display,
+		tuiHook: newTUIHook(display, nil),
+	}
+}
+
+// The PlanTUI implementation shows the progress of the operation in a
+// full-screen, interactive display, for large configurations where the
+// scrolling text logs are hard to follow.
+type PlanTUI struct {
+	*PlanHuman
+
+	display *tuiDisplay
+	tuiHook *tuiHook
+}
+
+var _ Plan = (*PlanTUI)(nil)
+
+func (v *PlanTUI) Operation() Operation {
+	return &OperationTUI{
+		Operation: v.PlanHuman.Operation(),
+		display:   v.display,
+		hook:      v.tuiHook,
+	}
+}
+
+func (v *PlanTUI) Hooks() []tofu.Hook {
+	return []tofu.Hook{
+		v.tuiHook,
+		v.timingsHook,
+	}
+}
+
+func (v *PlanTUI) Timings() {
+	v.display.Finish()
+	v.PlanHuman.Timings()
+}
+
+func (v *PlanTUI) Diagnostics(diags tfdiags.Diagnostics) {
+	v.display.Finish()
+	v.PlanHuman.Diagnostics(diags)
+}
+
 // The PlanJSON implementation renders streaming JSON logs, suitable for
 // integrating with other software.
 type PlanJSON struct {
 	view *JSONView
+
+	timingsHook *timingsHook
 }
 
 var _ Plan = (*PlanJSON)(nil)
@@ -82,9 +150,14 @@ func (v *PlanJSON) Operation() Operation {
 func (v *PlanJSON) Hooks() []tofu.Hook {
 	return []tofu.Hook{
 		newJSONHook(v.view),
+		v.timingsHook,
 	}
 }
 
+func (v *PlanJSON) Timings() {
+	v.view.Timings(v.timingsHook.JSON())
+}
+
 func (v *PlanJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
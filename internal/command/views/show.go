@@ -15,6 +15,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/jsonformat"
 	"github.com/opentofu/opentofu/internal/command/jsonplan"
 	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/command/jsonschema"
 	"github.com/opentofu/opentofu/internal/command/jsonstate"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/plans"
@@ -27,6 +28,10 @@ type Show interface {
 	// Display renders the plan, if it is available. If plan is nil, it renders the statefile.
 	Display(config *configs.Config, plan *plans.Plan, planJSON *cloudplan.RemotePlanJSON, stateFile *statefile.File, schemas *tofu.Schemas) int
 
+	// DisplayModules renders the modules installed in the given directory,
+	// for "tofu show -modules".
+	DisplayModules(modulesDir string, modules []*ShowModule) int
+
 	// Diagnostics renders early diagnostics, resulting from argument parsing.
 	Diagnostics(diags tfdiags.Diagnostics)
 }
@@ -37,6 +42,8 @@ func NewShow(vt arguments.ViewType, view *View) Show {
 		return &ShowJSON{view: view}
 	case arguments.ViewHuman:
 		return &ShowHuman{view: view}
+	case arguments.ViewHTML:
+		return &ShowHTML{view: view}
 	default:
 		panic(fmt.Sprintf("unknown view type %v", vt))
 	}
@@ -53,6 +60,10 @@ func (v *ShowHuman) Display(config *configs.Config, plan *plans.Plan, planJSON *
 		Colorize:            v.view.colorize,
 		Streams:             v.view.streams,
 		RunningInAutomation: v.view.runningInAutomation,
+		FullStringDiff:      v.view.fullStringDiff,
+		SuppressAttributes:  v.view.suppressAttributes,
+		Summary:             v.view.summary,
+		SummaryExpand:       v.view.summaryExpand,
 	}
 
 	// Prefer to display a pre-built JSON plan, if we got one; then, fall back
@@ -129,6 +140,11 @@ func (v *ShowHuman) Diagnostics(diags tfdiags.Diagnostics) {
 
 type ShowJSON struct {
 	view *View
+
+	// ValidateSchema makes Display check the JSON output against the
+	// published schema for its format, and fail instead of displaying
+	// output that doesn't conform to it.
+	ValidateSchema bool
 }
 
 var _ Show = (*ShowJSON)(nil)
@@ -141,15 +157,31 @@ func (v *ShowJSON) Display(config *configs.Config, plan *plans.Plan, planJSON *c
 			v.view.streams.Eprintf("Didn't get external JSON plan format")
 			return 1
 		}
+		if !v.validate(jsonschema.Plan, planJSON.JSONBytes) {
+			return 1
+		}
 		v.view.streams.Println(string(planJSON.JSONBytes))
 	} else if plan != nil {
-		planJSON, err := jsonplan.Marshal(config, plan, stateFile, schemas)
-
+		// The plan is written as it's encoded, so that very large plans
+		// don't need to be held in memory in their entirety, unless it
+		// must be validated first.
+		if v.ValidateSchema {
+			var buf bytes.Buffer
+			if err := jsonplan.MarshalTo(&buf, config, plan, stateFile, schemas); err != nil {
+				v.view.streams.Eprintf("Failed to marshal plan to json: %s", err)
+				return 1
+			}
+			if !v.validate(jsonschema.Plan, buf.Bytes()) {
+				return 1
+			}
+			v.view.streams.Print(buf.String())
+			return 0
+		}
+		err := jsonplan.MarshalTo(v.view.streams.Stdout.File, config, plan, stateFile, schemas)
 		if err != nil {
 			v.view.streams.Eprintf("Failed to marshal plan to json: %s", err)
 			return 1
 		}
-		v.view.streams.Println(string(planJSON))
 	} else {
 		// It is possible that there is neither state nor a plan.
 		// That's ok, we'll just return an empty object.
@@ -158,11 +190,27 @@ func (v *ShowJSON) Display(config *configs.Config, plan *plans.Plan, planJSON *c
 			v.view.streams.Eprintf("Failed to marshal state to json: %s", err)
 			return 1
 		}
+		if !v.validate(jsonschema.State, jsonState) {
+			return 1
+		}
 		v.view.streams.Println(string(jsonState))
 	}
 	return 0
 }
 
+// validate checks the given output against the schema for its format if
+// ValidateSchema is set, and reports whether it may be displayed.
+func (v *ShowJSON) validate(format jsonschema.Format, doc []byte) bool {
+	if !v.ValidateSchema {
+		return true
+	}
+	if err := jsonschema.Validate(format, v.view.JSONVersion(), doc); err != nil {
+		v.view.streams.Eprintf("The JSON %s doesn't conform to version %d of its schema:\n%s\n", format, v.view.JSONVersion(), err)
+		return false
+	}
+	return true
+}
+
 // Diagnostics should only be called if show cannot be executed.
 // In this case, we choose to render human-readable diagnostic output,
 // primarily for backwards compatibility.
@@ -0,0 +1,289 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"html/template"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/colorstring"
+
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/cloud/cloudplan"
+	"github.com/opentofu/opentofu/internal/command/jsonformat"
+	"github.com/opentofu/opentofu/internal/command/jsonplan"
+	"github.com/opentofu/opentofu/internal/command/jsonprovider"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+	tfversion "github.com/opentofu/opentofu/version"
+)
+
+// ShowHTML renders a saved plan as a self-contained HTML document, which
+// can be attached to change tickets or published as a build artifact.
+//
+// The resource changes are described in the same way as in the human
+// output, so sensitive values are redacted in the same way.
+type ShowHTML struct {
+	view *View
+}
+
+var _ Show = (*ShowHTML)(nil)
+
+func (v *ShowHTML) Display(config *configs.Config, plan *plans.Plan, planJSON *cloudplan.RemotePlanJSON, stateFile *statefile.File, schemas *tofu.Schemas) int {
+	if planJSON != nil || plan == nil {
+		v.view.streams.Eprintln("The HTML format is only supported for plan files created by \"tofu plan -out\".")
+		return 1
+	}
+
+	outputs, changed, drift, attrs, err := jsonplan.MarshalForRenderer(plan, schemas)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal plan to json: %s", err)
+		return 1
+	}
+
+	jplan := jsonformat.Plan{
+		PlanFormatVersion:     jsonplan.FormatVersion,
+		ProviderFormatVersion: jsonprovider.FormatVersion,
+		OutputChanges:         outputs,
+		ResourceChanges:       changed,
+		ResourceDrift:         drift,
+		ProviderSchemas:       jsonprovider.MarshalForRenderer(schemas),
+		RelevantAttributes:    attrs,
+	}
+
+	// The report is plain text, so we render it without any color codes.
+	renderer := jsonformat.Renderer{
+		Colorize: &colorstring.Colorize{
+			Colors:  colorstring.DefaultColors,
+			Disable: true,
+		},
+		Streams:            v.view.streams,
+		FullStringDiff:     v.view.fullStringDiff,
+		SuppressAttributes: v.view.suppressAttributes,
+	}
+	report := renderer.RenderPlanReport(jplan, plan.UIMode)
+
+	var buf strings.Builder
+	if err := showHTMLTemplate.Execute(&buf, newShowHTMLData(plan, report)); err != nil {
+		v.view.streams.Eprintf("Failed to render HTML report: %s", err)
+		return 1
+	}
+	v.view.streams.Print(buf.String())
+	return 0
+}
+
+// Diagnostics should only be called if show cannot be executed. In this
+// case, we render human-readable diagnostic output, as for the JSON view.
+func (v *ShowHTML) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
+
+type showHTMLData struct {
+	Version   string
+	Timestamp string
+	Mode      string
+	Errored   bool
+
+	Import, Add, Change, Destroy, Forget int
+
+	Changes []showHTMLChange
+	Outputs string
+	Checks  []showHTMLCheck
+}
+
+type showHTMLChange struct {
+	Address string
+	Action  string
+	Class   string
+	Diff    string
+}
+
+type showHTMLCheck struct {
+	Address  string
+	Status   string
+	Class    string
+	Messages []string
+}
+
+func newShowHTMLData(plan *plans.Plan, report jsonformat.PlanReport) showHTMLData {
+	data := showHTMLData{
+		Version:   tfversion.String(),
+		Timestamp: plan.Timestamp.UTC().Format(time.RFC3339),
+		Errored:   plan.Errored,
+		Outputs:   report.Outputs,
+	}
+
+	switch plan.UIMode {
+	case plans.DestroyMode:
+		data.Mode = "destroy"
+	case plans.RefreshOnlyMode:
+		data.Mode = "refresh-only"
+	default:
+		data.Mode = "normal"
+	}
+
+	for _, change := range report.Changes {
+		if change.Importing {
+			data.Import++
+		}
+		switch change.Action {
+		case plans.Create:
+			data.Add++
+		case plans.Update:
+			data.Change++
+		case plans.Delete:
+			data.Destroy++
+		case plans.DeleteThenCreate, plans.CreateThenDelete:
+			data.Add++
+			data.Destroy++
+		case plans.Forget:
+			data.Forget++
+		}
+
+		action, class := showHTMLAction(change)
+		data.Changes = append(data.Changes, showHTMLChange{
+			Address: change.Address,
+			Action:  action,
+			Class:   class,
+			Diff:    change.Diff,
+		})
+	}
+
+	if plan.Checks != nil {
+		for _, configElem := range plan.Checks.ConfigResults.Elements() {
+			aggregate := configElem.Value
+			if aggregate.ObjectResults.Len() == 0 {
+				status, class := showHTMLCheckStatus(aggregate.Status)
+				data.Checks = append(data.Checks, showHTMLCheck{
+					Address: configElem.Key.String(),
+					Status:  status,
+					Class:   class,
+				})
+				continue
+			}
+			for _, objElem := range aggregate.ObjectResults.Elements() {
+				status, class := showHTMLCheckStatus(objElem.Value.Status)
+				data.Checks = append(data.Checks, showHTMLCheck{
+					Address:  objElem.Key.String(),
+					Status:   status,
+					Class:    class,
+					Messages: objElem.Value.FailureMessages,
+				})
+			}
+		}
+		sort.Slice(data.Checks, func(i, j int) bool {
+			return data.Checks[i].Address < data.Checks[j].Address
+		})
+	}
+
+	return data
+}
+
+// showHTMLAction returns the description of the action of a resource change,
+// and the CSS class to style it with.
+func showHTMLAction(change jsonformat.ResourceChangeReport) (string, string) {
+	switch change.Action {
+	case plans.Create:
+		return "create", "create"
+	case plans.Update:
+		return "update", "update"
+	case plans.Delete:
+		return "destroy", "delete"
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		return "replace", "replace"
+	case plans.Read:
+		return "read", "read"
+	case plans.Forget:
+		return "forget", "delete"
+	}
+	switch {
+	case change.Importing:
+		return "import", "create"
+	case change.Moved:
+		return "move", "update"
+	default:
+		return "no-op", "noop"
+	}
+}
+
+// showHTMLCheckStatus returns the description of a check status and the CSS
+// class to style it with.
+func showHTMLCheckStatus(status checks.Status) (string, string) {
+	switch status {
+	case checks.StatusPass:
+		return "pass", "create"
+	case checks.StatusFail:
+		return "fail", "delete"
+	case checks.StatusError:
+		return "error", "delete"
+	default:
+		return "unknown", "noop"
+	}
+}
+
+var showHTMLTemplate = template.Must(template.New("plan").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>OpenTofu plan</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #1f2328; }
+h1 { font-size: 1.6em; }
+h2 { font-size: 1.2em; margin-top: 2em; border-bottom: 1px solid #d0d7de; padding-bottom: 0.3em; }
+table { border-collapse: collapse; }
+th, td { text-align: left; padding: 0.3em 1em 0.3em 0; vertical-align: top; }
+details { border: 1px solid #d0d7de; border-radius: 6px; margin: 0.5em 0; }
+summary { cursor: pointer; padding: 0.5em; font-family: ui-monospace, Menlo, Consolas, monospace; }
+pre { margin: 0; padding: 0.5em 1em; background: #f6f8fa; overflow-x: auto; font-family: ui-monospace, Menlo, Consolas, monospace; font-size: 0.9em; }
+.action { display: inline-block; min-width: 5em; font-weight: bold; }
+.create { color: #1a7f37; }
+.update { color: #9a6700; }
+.delete { color: #cf222e; }
+.replace { color: #8250df; }
+.read { color: #0969da; }
+.noop { color: #57606a; }
+.error { color: #cf222e; font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>OpenTofu plan</h1>
+<table>
+<tr><th>Created</th><td>{{.Timestamp}}</td></tr>
+<tr><th>Mode</th><td>{{.Mode}}</td></tr>
+<tr><th>OpenTofu</th><td>{{.Version}}</td></tr>
+</table>
+{{if .Errored}}<p class="error">Planning failed. OpenTofu encountered an error while generating this plan, so it is incomplete.</p>{{end}}
+
+<h2>Summary</h2>
+{{if or .Changes .Outputs}}<p>{{if .Import}}<span class="create">{{.Import}} to import</span>, {{end}}<span class="create">{{.Add}} to add</span>, <span class="update">{{.Change}} to change</span>, <span class="delete">{{.Destroy}} to destroy</span>{{if .Forget}}, <span class="delete">{{.Forget}} to forget</span>{{end}}.</p>
+{{else}}<p>No changes. The infrastructure matches the configuration.</p>
+{{end}}
+{{- if .Changes}}
+<h2>Resource changes</h2>
+{{range .Changes}}<details>
+<summary><span class="action {{.Class}}">{{.Action}}</span> {{.Address}}</summary>
+<pre>{{.Diff}}</pre>
+</details>
+{{end}}{{end}}
+{{- if .Outputs}}
+<h2>Output changes</h2>
+<pre>{{.Outputs}}</pre>
+{{end}}
+{{- if .Checks}}
+<h2>Checks</h2>
+<table>
+<tr><th>Object</th><th>Status</th><th>Problems</th></tr>
+{{range .Checks}}<tr><td>{{.Address}}</td><td class="{{.Class}}">{{.Status}}</td><td>{{range .Messages}}{{.}}<br>{{end}}</td></tr>
+{{end}}</table>
+{{end}}
+</body>
+</html>
+`))
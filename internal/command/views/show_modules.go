@@ -0,0 +1,160 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// showModulesFormatVersion is the version of the JSON output of
+// "tofu show -modules -json".
+const showModulesFormatVersion = "1.0"
+
+// ModuleStatus describes whether an installed module matches the module call
+// in the configuration.
+type ModuleStatus string
+
+const (
+	// ModuleInstalled means that the module is installed and matches its
+	// module call.
+	ModuleInstalled ModuleStatus = "installed"
+
+	// ModuleNotInstalled, ModuleSourceChanged, and ModuleVersionChanged mean
+	// that the module must be installed or reinstalled by running
+	// "tofu init".
+	ModuleNotInstalled   ModuleStatus = "not_installed"
+	ModuleSourceChanged  ModuleStatus = "source_changed"
+	ModuleVersionChanged ModuleStatus = "version_changed"
+
+	// ModuleUnused means that the module is installed but no longer called
+	// from the configuration.
+	ModuleUnused ModuleStatus = "unused"
+)
+
+// NeedsInstall returns true if the module must be installed or reinstalled
+// for the configuration to be used.
+func (s ModuleStatus) NeedsInstall() bool {
+	switch s {
+	case ModuleNotInstalled, ModuleSourceChanged, ModuleVersionChanged:
+		return true
+	default:
+		return false
+	}
+}
+
+// ShowModule describes one module in the output of "tofu show -modules".
+type ShowModule struct {
+	// Path is the path of the module in the module tree, which is never
+	// empty since the root module isn't included.
+	Path []string
+
+	// Source, Version, and Dir describe the installed module, as recorded in
+	// the module manifest. If the module isn't installed, Source is the
+	// source address from the configuration and the others are empty.
+	Source  string
+	Version string
+	Dir     string
+
+	Status ModuleStatus
+
+	// Detail explains the status, if it's anything other than
+	// ModuleInstalled.
+	Detail string
+}
+
+// Address returns the address of the module, like "module.a.module.b".
+func (m *ShowModule) Address() string {
+	return "module." + strings.Join(m.Path, ".module.")
+}
+
+// DisplayModules renders the installed modules, which must be sorted so that
+// each module comes after its parent.
+func (v *ShowHuman) DisplayModules(modulesDir string, modules []*ShowModule) int {
+	if len(modules) == 0 {
+		v.view.streams.Println("No modules are installed or called from the configuration.")
+		return 0
+	}
+
+	v.view.streams.Print(v.view.colorize.Color(fmt.Sprintf("[reset][bold]Modules installed in %s:[reset]\n\n", modulesDir)))
+
+	needInstall := false
+	for _, m := range modules {
+		indent := strings.Repeat("  ", len(m.Path)-1)
+		name := m.Path[len(m.Path)-1]
+		source := m.Source
+		if m.Version != "" {
+			source += " " + m.Version
+		}
+		v.view.streams.Print(v.view.colorize.Color(fmt.Sprintf("%s- [bold]%s[reset]: %s\n", indent, name, source)))
+		if m.Dir != "" {
+			v.view.streams.Printf("%s    %s\n", indent, m.Dir)
+		}
+
+		switch {
+		case m.Status.NeedsInstall():
+			needInstall = true
+			label := "Needs reinstall"
+			if m.Status == ModuleNotInstalled {
+				label = "Not installed"
+			}
+			v.view.streams.Print(v.view.colorize.Color(fmt.Sprintf("%s    [yellow]%s:[reset] %s\n", indent, label, m.Detail)))
+		case m.Status == ModuleUnused:
+			v.view.streams.Printf("%s    Unused: %s\n", indent, m.Detail)
+		}
+	}
+
+	if needInstall {
+		v.view.streams.Println("\nRun \"tofu init\" to install the modules that need it.")
+	}
+	return 0
+}
+
+type showModulesJSON struct {
+	FormatVersion string           `json:"format_version"`
+	Modules       []showModuleJSON `json:"modules"`
+}
+
+type showModuleJSON struct {
+	Address string       `json:"address"`
+	Source  string       `json:"source"`
+	Version string       `json:"version,omitempty"`
+	Dir     string       `json:"dir,omitempty"`
+	Status  ModuleStatus `json:"status"`
+	Detail  string       `json:"detail,omitempty"`
+}
+
+func (v *ShowJSON) DisplayModules(modulesDir string, modules []*ShowModule) int {
+	out := showModulesJSON{
+		FormatVersion: showModulesFormatVersion,
+		Modules:       make([]showModuleJSON, 0, len(modules)),
+	}
+	for _, m := range modules {
+		out.Modules = append(out.Modules, showModuleJSON{
+			Address: m.Address(),
+			Source:  m.Source,
+			Version: m.Version,
+			Dir:     m.Dir,
+			Status:  m.Status,
+			Detail:  m.Detail,
+		})
+	}
+
+	js, err := json.Marshal(out)
+	if err != nil {
+		v.view.streams.Eprintf("Failed to marshal modules to json: %s", err)
+		return 1
+	}
+	v.view.streams.Println(string(js))
+	return 0
+}
+
+func (v *ShowHTML) DisplayModules(modulesDir string, modules []*ShowModule) int {
+	// The arguments parser rejects -modules with -format=html.
+	v.view.streams.Eprintln("The HTML format can only display a saved plan.")
+	return 1
+}
@@ -216,6 +216,52 @@ func TestShowJSON(t *testing.T) {
 	}
 }
 
+func TestShowHTML(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := NewView(streams)
+	view.Configure(&arguments.View{NoColor: true})
+	v := NewShow(arguments.ViewHTML, view)
+
+	code := v.Display(nil, testPlan(t), nil, nil, testSchemas())
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("expected 0 return code, got %d\n%s", code, output.Stderr())
+	}
+
+	got := output.Stdout()
+	for _, want := range []string{
+		"<!DOCTYPE html>",
+		`<span class="action create">create</span> test_resource.foo`,
+		"# test_resource.foo will be created",
+		`<span class="create">1 to add</span>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output is missing %q\n%s", want, got)
+		}
+	}
+}
+
+func TestShowHTML_notPlan(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := NewView(streams)
+	view.Configure(&arguments.View{NoColor: true})
+	v := NewShow(arguments.ViewHTML, view)
+
+	stateFile := &statefile.File{
+		Lineage: "fake-for-testing",
+		State:   testState(),
+	}
+	code := v.Display(nil, nil, nil, stateFile, testSchemas())
+	if code != 1 {
+		t.Fatalf("expected 1 return code, got %d", code)
+	}
+
+	output := done(t)
+	if got, want := output.Stderr(), "only supported for plan files"; !strings.Contains(got, want) {
+		t.Fatalf("unexpected error output\ngot: %s\nwant: %s", got, want)
+	}
+}
+
 // testState returns a test State structure.
 func testState() *states.State {
 	return states.BuildState(func(s *states.SyncState) {
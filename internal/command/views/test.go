@@ -7,6 +7,7 @@ package views
 
 import (
 	"bytes"
+	stdjson "encoding/json"
 	"fmt"
 
 	"github.com/mitchellh/colorstring"
@@ -27,6 +28,7 @@ import (
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/opentofu/opentofu/internal/tofu"
+	tfversion "github.com/opentofu/opentofu/version"
 )
 
 // Test renders outputs for test executions.
@@ -42,6 +44,11 @@ type Test interface {
 	// completed status.
 	Conclusion(suite *moduletest.Suite)
 
+	// Coverage prints out which parts of the module under test were
+	// exercised by the tests. This is only called when coverage was
+	// requested, after Conclusion.
+	Coverage(report *moduletest.CoverageReport)
+
 	// File prints out the summary for an entire test file.
 	File(file *moduletest.File)
 
@@ -84,6 +91,11 @@ func NewTest(vt arguments.ViewType, view *View) Test {
 		return &TestHuman{
 			view: view,
 		}
+	case arguments.ViewSARIF:
+		return &TestSARIF{
+			human: &TestHuman{view: view},
+			log:   json.NewSARIFLog(tfversion.String()),
+		}
 	default:
 		panic(fmt.Sprintf("unknown view type %v", vt))
 	}
@@ -103,8 +115,16 @@ func (t *TestHuman) Conclusion(suite *moduletest.Suite) {
 	t.view.streams.Println()
 
 	counts := make(map[moduletest.Status]int)
+	var retried, quarantined int
 	for _, file := range suite.Files {
 		for _, run := range file.Runs {
+			if run.QuarantinedFailure() {
+				quarantined++
+				continue
+			}
+			if run.PassedOnRetry() {
+				retried++
+			}
 			count := counts[run.Status]
 			counts[run.Status] = count + 1
 		}
@@ -127,7 +147,14 @@ func (t *TestHuman) Conclusion(suite *moduletest.Suite) {
 		t.view.streams.Print(t.view.colorize.Color("[red]Failure![reset]"))
 	}
 
-	t.view.streams.Printf(" %d passed, %d failed", counts[moduletest.Pass], counts[moduletest.Fail]+counts[moduletest.Error])
+	t.view.streams.Printf(" %d passed", counts[moduletest.Pass])
+	if retried > 0 {
+		t.view.streams.Printf(" (%d on retry)", retried)
+	}
+	t.view.streams.Printf(", %d failed", counts[moduletest.Fail]+counts[moduletest.Error])
+	if quarantined > 0 {
+		t.view.streams.Printf(", %d quarantined", quarantined)
+	}
 	if counts[moduletest.Skip] > 0 {
 		t.view.streams.Printf(", %d skipped.\n", counts[moduletest.Skip])
 	} else {
@@ -135,13 +162,47 @@ func (t *TestHuman) Conclusion(suite *moduletest.Suite) {
 	}
 }
 
+func (t *TestHuman) Coverage(report *moduletest.CoverageReport) {
+	covered, total := report.Count("")
+	t.view.streams.Printf("\nCoverage: %.1f%% (%d of %d covered)\n", report.Percent(), covered, total)
+
+	kinds := []struct {
+		kind moduletest.CoverageKind
+		name string
+	}{
+		{moduletest.CoverageResource, "resources"},
+		{moduletest.CoverageBranch, "branches"},
+		{moduletest.CoverageOutput, "outputs"},
+		{moduletest.CoverageVariable, "variables"},
+	}
+	for _, k := range kinds {
+		if covered, total := report.Count(k.kind); total > 0 {
+			t.view.streams.Printf("  %s: %d of %d\n", k.name, covered, total)
+		}
+	}
+
+	var uncovered []*moduletest.CoverageItem
+	for _, item := range report.Items {
+		if !item.Covered {
+			uncovered = append(uncovered, item)
+		}
+	}
+	if len(uncovered) == 0 {
+		return
+	}
+	t.view.streams.Println("\nNot covered:")
+	for _, item := range uncovered {
+		t.view.streams.Printf("  - %s, at %s:%d\n", item.String(), item.DeclRange.Filename, item.DeclRange.Start.Line)
+	}
+}
+
 func (t *TestHuman) File(file *moduletest.File) {
 	t.view.streams.Printf("%s... %s\n", file.Name, colorizeTestStatus(file.Status, t.view.colorize))
 	t.Diagnostics(nil, file, file.Diagnostics)
 }
 
 func (t *TestHuman) Run(run *moduletest.Run, file *moduletest.File) {
-	t.view.streams.Printf("  run %q... %s\n", run.Name, colorizeTestStatus(run.Status, t.view.colorize))
+	t.view.streams.Printf("  run %q... %s%s\n", run.Name, colorizeTestStatus(run.Status, t.view.colorize), testRunStatusDetail(run))
 
 	if run.Verbose != nil {
 		// We're going to be more verbose about what we print, here's the plan
@@ -156,6 +217,10 @@ func (t *TestHuman) Run(run *moduletest.Run, file *moduletest.File) {
 			Streams:             t.view.streams,
 			Colorize:            t.view.colorize,
 			RunningInAutomation: t.view.runningInAutomation,
+			FullStringDiff:      t.view.fullStringDiff,
+			SuppressAttributes:  t.view.suppressAttributes,
+			Summary:             t.view.summary,
+			SummaryExpand:       t.view.summaryExpand,
 		}
 
 		if run.Config.Command == configs.ApplyTestCommand {
@@ -265,7 +330,7 @@ func (t *TestHuman) FatalInterruptSummary(run *moduletest.Run, file *moduletest.
 	}
 
 	// Then print out the other states in order.
-	for _, run := range file.Runs {
+	for _, run := range file.AllRuns() {
 		state, exists := existingStates[run]
 		if !exists || state.Empty() {
 			continue
@@ -347,6 +412,13 @@ func (t *TestJSON) Conclusion(suite *moduletest.Suite) {
 	}
 	for _, file := range suite.Files {
 		for _, run := range file.Runs {
+			if run.QuarantinedFailure() {
+				summary.Quarantined++
+				continue
+			}
+			if run.PassedOnRetry() {
+				summary.PassedOnRetry++
+			}
 			switch run.Status {
 			case moduletest.Skip:
 				summary.Skipped++
@@ -376,7 +448,14 @@ func (t *TestJSON) Conclusion(suite *moduletest.Suite) {
 			message.WriteString("Failure!")
 		}
 
-		message.WriteString(fmt.Sprintf(" %d passed, %d failed", summary.Passed, summary.Failed+summary.Errored))
+		message.WriteString(fmt.Sprintf(" %d passed", summary.Passed))
+		if summary.PassedOnRetry > 0 {
+			message.WriteString(fmt.Sprintf(" (%d on retry)", summary.PassedOnRetry))
+		}
+		message.WriteString(fmt.Sprintf(", %d failed", summary.Failed+summary.Errored))
+		if summary.Quarantined > 0 {
+			message.WriteString(fmt.Sprintf(", %d quarantined", summary.Quarantined))
+		}
 		if summary.Skipped > 0 {
 			message.WriteString(fmt.Sprintf(", %d skipped.", summary.Skipped))
 		} else {
@@ -390,20 +469,44 @@ func (t *TestJSON) Conclusion(suite *moduletest.Suite) {
 		json.MessageTestSummary, summary)
 }
 
+func (t *TestJSON) Coverage(report *moduletest.CoverageReport) {
+	coverage := json.ToTestCoverage(report)
+	t.view.log.Info(
+		fmt.Sprintf("Coverage: %.1f%% (%d of %d covered)", coverage.Percent, coverage.Covered, coverage.Total),
+		"type", json.MessageTestCoverage,
+		json.MessageTestCoverage, coverage)
+}
+
 func (t *TestJSON) File(file *moduletest.File) {
 	t.view.log.Info(
 		fmt.Sprintf("%s... %s", file.Name, testStatus(file.Status)),
 		"type", json.MessageTestFile,
-		json.MessageTestFile, json.TestFileStatus{file.Name, json.ToTestStatus(file.Status)},
+		json.MessageTestFile, json.TestFileStatus{
+			Path:     file.Name,
+			Status:   json.ToTestStatus(file.Status),
+			Duration: file.Duration.Seconds(),
+		},
 		"@testfile", file.Name)
 	t.Diagnostics(nil, file, file.Diagnostics)
 }
 
 func (t *TestJSON) Run(run *moduletest.Run, file *moduletest.File) {
+	status := json.TestRunStatus{
+		Path:        file.Name,
+		Run:         run.Name,
+		Status:      json.ToTestStatus(run.Status),
+		Duration:    run.Duration.Seconds(),
+		Quarantined: run.Quarantined,
+	}
+	if run.Attempts > 1 {
+		// Only retried run blocks report their attempts.
+		status.Attempts = run.Attempts
+	}
+
 	t.view.log.Info(
-		fmt.Sprintf("  %q... %s", run.Name, testStatus(run.Status)),
+		fmt.Sprintf("  %q... %s%s", run.Name, testStatus(run.Status), testRunStatusDetail(run)),
 		"type", json.MessageTestRun,
-		json.MessageTestRun, json.TestRunStatus{file.Name, run.Name, json.ToTestStatus(run.Status)},
+		json.MessageTestRun, status,
 		"@testfile", file.Name,
 		"@testrun", run.Name)
 
@@ -541,6 +644,96 @@ func (t *TestJSON) FatalInterruptSummary(run *moduletest.Run, file *moduletest.F
 		"@testfile", file.Name)
 }
 
+// TestSARIF renders the diagnostics from a test execution as a SARIF log,
+// for code scanning tools. The log is only written once all of the tests
+// have completed, so that it is a single valid document. Messages that
+// aren't diagnostics are written to stderr in human-readable form.
+type TestSARIF struct {
+	human *TestHuman
+	log   *json.SARIFLog
+}
+
+var _ Test = (*TestSARIF)(nil)
+
+func (t *TestSARIF) Abstract(_ *moduletest.Suite) {
+	// Do nothing, the SARIF log has no equivalent of an abstract.
+}
+
+func (t *TestSARIF) Conclusion(_ *moduletest.Suite) {
+	t.printLog()
+}
+
+func (t *TestSARIF) Coverage(_ *moduletest.CoverageReport) {
+	// Do nothing, the SARIF log only contains diagnostics. The coverage can
+	// be written to a file with -coverage-report instead.
+}
+
+func (t *TestSARIF) File(file *moduletest.File) {
+	t.Diagnostics(nil, file, file.Diagnostics)
+}
+
+func (t *TestSARIF) Run(run *moduletest.Run, file *moduletest.File) {
+	t.Diagnostics(run, file, run.Diagnostics)
+}
+
+func (t *TestSARIF) DestroySummary(diags tfdiags.Diagnostics, run *moduletest.Run, file *moduletest.File, state *states.State) {
+	t.Diagnostics(run, file, diags)
+
+	if state.HasManagedResourceInstanceObjects() {
+		// The human view writes the list of left-over resources to stderr,
+		// so we can use it as long as we don't pass it any diagnostics.
+		t.human.DestroySummary(nil, run, file, state)
+	}
+}
+
+func (t *TestSARIF) Diagnostics(run *moduletest.Run, file *moduletest.File, diags tfdiags.Diagnostics) {
+	var properties map[string]interface{}
+	if file != nil {
+		properties = map[string]interface{}{
+			"testfile": file.Name,
+		}
+		if run != nil {
+			properties["testrun"] = run.Name
+			if run.Quarantined {
+				properties["quarantined"] = true
+			}
+		}
+	}
+
+	configSources := t.human.view.configSources()
+	for _, diag := range diags {
+		t.log.AddDiagnostic(json.NewDiagnostic(diag, configSources), properties)
+	}
+
+	if run == nil && file == nil && diags.HasErrors() {
+		// Errors that aren't related to a test file prevent the tests from
+		// running at all, so there won't be a conclusion and we must print
+		// the log now.
+		t.printLog()
+	}
+}
+
+func (t *TestSARIF) Interrupted() {
+	t.human.Interrupted()
+}
+
+func (t *TestSARIF) FatalInterrupt() {
+	t.human.FatalInterrupt()
+}
+
+func (t *TestSARIF) FatalInterruptSummary(run *moduletest.Run, file *moduletest.File, existingStates map[*moduletest.Run]*states.State, created []*plans.ResourceInstanceChangeSrc) {
+	t.human.FatalInterruptSummary(run, file, existingStates, created)
+}
+
+func (t *TestSARIF) printLog() {
+	j, err := stdjson.MarshalIndent(t.log, "", "  ")
+	if err != nil {
+		// Should never happen because we fully-control the input here
+		panic(err)
+	}
+	t.human.view.streams.Println(string(j))
+}
+
 func colorizeTestStatus(status moduletest.Status, color *colorstring.Colorize) string {
 	switch status {
 	case moduletest.Error, moduletest.Fail:
@@ -556,6 +749,19 @@ func colorizeTestStatus(status moduletest.Status, color *colorstring.Colorize) s
 	}
 }
 
+// testRunStatusDetail returns a suffix for the status of the given run block
+// if it is quarantined and failed, or if it passed after being retried.
+func testRunStatusDetail(run *moduletest.Run) string {
+	switch {
+	case run.QuarantinedFailure():
+		return " (quarantined)"
+	case run.PassedOnRetry():
+		return fmt.Sprintf(" (after %d attempts)", run.Attempts)
+	default:
+		return ""
+	}
+}
+
 func testStatus(status moduletest.Status) string {
 	switch status {
 	case moduletest.Error, moduletest.Fail:
@@ -585,6 +791,9 @@ func SaveErroredTestStateFile(state *states.State, run *moduletest.Run, file *mo
 	case *TestHuman:
 		op = NewOperation(arguments.ViewHuman, false, v.view)
 		v.view.streams.Eprint(format.WordWrap("\nWriting state to file: errored_test.tfstate\n", v.view.errorColumns()))
+	case *TestSARIF:
+		op = NewOperation(arguments.ViewHuman, false, v.human.view)
+		v.human.view.streams.Eprint(format.WordWrap("\nWriting state to file: errored_test.tfstate\n", v.human.view.errorColumns()))
 	case *TestJSON:
 		op = &OperationJSON{
 			view: v.view,
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/zclconf/go-cty/cty"
@@ -395,6 +396,37 @@ func TestTestHuman_Conclusion(t *testing.T) {
 			},
 			Expected: "\nFailure! 2 passed, 2 failed, 2 skipped.\n",
 		},
+
+		"retried and quarantined tests": {
+			Suite: &moduletest.Suite{
+				Status: moduletest.Pass,
+				Files: map[string]*moduletest.File{
+					"descriptive_test_name.tftest.hcl": {
+						Name:   "descriptive_test_name.tftest.hcl",
+						Status: moduletest.Pass,
+						Runs: []*moduletest.Run{
+							{
+								Name:     "test_one",
+								Status:   moduletest.Pass,
+								Attempts: 1,
+							},
+							{
+								Name:     "test_two",
+								Status:   moduletest.Pass,
+								Attempts: 2,
+							},
+							{
+								Name:        "test_three",
+								Status:      moduletest.Fail,
+								Attempts:    1,
+								Quarantined: true,
+							},
+						},
+					},
+				},
+			},
+			Expected: "\nSuccess! 2 passed (1 on retry), 0 failed, 1 quarantined.\n",
+		},
 	}
 	for name, tc := range tcs {
 		t.Run(name, func(t *testing.T) {
@@ -526,6 +558,16 @@ other details
 			StdOut: "  run \"run_block\"... fail\n",
 		},
 
+		"pass_on_retry": {
+			Run:    &moduletest.Run{Name: "run_block", Status: moduletest.Pass, Attempts: 3},
+			StdOut: "  run \"run_block\"... pass (after 3 attempts)\n",
+		},
+
+		"fail_quarantined": {
+			Run:    &moduletest.Run{Name: "run_block", Status: moduletest.Fail, Attempts: 1, Quarantined: true},
+			StdOut: "  run \"run_block\"... fail (quarantined)\n",
+		},
+
 		"error_with_diags": {
 			Run: &moduletest.Run{
 				Name:        "run_block",
@@ -2483,6 +2525,26 @@ func TestTestJSON_Run(t *testing.T) {
 			},
 		},
 
+		"pass_with_duration": {
+			run: &moduletest.Run{Name: "run_block", Status: moduletest.Pass, Duration: 1500 * time.Millisecond},
+			want: []map[string]interface{}{
+				{
+					"@level":    "info",
+					"@message":  "  \"run_block\"... pass",
+					"@module":   "tofu.ui",
+					"@testfile": "main.tftest.hcl",
+					"@testrun":  "run_block",
+					"test_run": map[string]interface{}{
+						"path":     "main.tftest.hcl",
+						"run":      "run_block",
+						"status":   "pass",
+						"duration": 1.5,
+					},
+					"type": "test_run",
+				},
+			},
+		},
+
 		"pass_with_diags": {
 			run: &moduletest.Run{
 				Name:        "run_block",
@@ -0,0 +1,560 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// tuiStatus is the status of a resource instance in the interactive display.
+type tuiStatus rune
+
+const (
+	tuiRunning  tuiStatus = 'R'
+	tuiComplete tuiStatus = 'C'
+	tuiFailed   tuiStatus = 'F'
+)
+
+// tuiNode is a resource instance shown in the interactive display, along with
+// what OpenTofu has done to it so far.
+type tuiNode struct {
+	Addr    addrs.AbsResourceInstance
+	Deposed states.DeposedKey
+	Label   string
+	Status  tuiStatus
+
+	Started time.Time
+	Elapsed time.Duration
+
+	// Diff describes the planned change to the resource instance, one
+	// attribute per line.
+	Diff []string
+
+	// Logs are the progress messages for the resource instance, including
+	// the output of its provisioners.
+	Logs []string
+}
+
+// tuiHook is a hook that records the progress of each resource instance for
+// the interactive display, and starts the display when the first resource
+// instance is reached.
+type tuiHook struct {
+	sync.Mutex
+	nodes map[string]*tuiNode
+
+	display *tuiDisplay
+
+	// Until the hook is active it passes all events to inner instead. The
+	// apply command uses this to show its planning phase as normal, so that
+	// the plan can be reviewed and confirmed.
+	active bool
+	inner  tofu.Hook
+
+	// now is replaced in tests.
+	now func() time.Time
+
+	tofu.NilHook
+}
+
+var _ tofu.Hook = (*tuiHook)(nil)
+
+// newTUIHook returns a hook for the given display. If inner is not nil then
+// the hook passes events to it until Activate is called.
+func newTUIHook(display *tuiDisplay, inner tofu.Hook) *tuiHook {
+	h := &tuiHook{
+		nodes:   make(map[string]*tuiNode),
+		display: display,
+		active:  inner == nil,
+		inner:   inner,
+		now:     time.Now,
+	}
+	display.nodes = h.Nodes
+	return h
+}
+
+// Activate makes the hook record events for the display, instead of passing
+// them to the inner hook.
+func (h *tuiHook) Activate() {
+	h.Lock()
+	defer h.Unlock()
+	h.active = true
+}
+
+// passthrough returns the hook to pass events to instead of recording them,
+// or nil if the hook is active.
+func (h *tuiHook) passthrough() tofu.Hook {
+	h.Lock()
+	defer h.Unlock()
+	if h.active {
+		return nil
+	}
+	return h.inner
+}
+
+// Nodes returns a copy of the resource instances seen so far, ordered by
+// address.
+func (h *tuiHook) Nodes() []tuiNode {
+	h.Lock()
+	defer h.Unlock()
+
+	ret := make([]tuiNode, 0, len(h.nodes))
+	for _, n := range h.nodes {
+		node := *n
+		if node.Status == tuiRunning {
+			node.Elapsed = h.now().Sub(node.Started)
+		}
+		node.Logs = append([]string(nil), n.Logs...)
+		ret = append(ret, node)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if !ret[i].Addr.Equal(ret[j].Addr) {
+			return ret[i].Addr.Less(ret[j].Addr)
+		}
+		return ret[i].Deposed < ret[j].Deposed
+	})
+	return ret
+}
+
+// update calls the given function with the node for the given resource
+// instance, creating it first if necessary.
+func (h *tuiHook) update(addr addrs.AbsResourceInstance, gen states.Generation, f func(n *tuiNode)) {
+	h.Lock()
+	var deposed states.DeposedKey
+	if dk, ok := gen.(states.DeposedKey); ok {
+		deposed = dk
+	}
+	key := addr.String() + " " + string(deposed)
+	n, ok := h.nodes[key]
+	if !ok {
+		n = &tuiNode{Addr: addr, Deposed: deposed}
+		h.nodes[key] = n
+	}
+	f(n)
+	h.Unlock()
+
+	h.display.Start()
+}
+
+func (h *tuiHook) start(n *tuiNode, label, msg string) {
+	n.Label = label
+	n.Status = tuiRunning
+	n.Started = h.now()
+	n.log(n.Started, msg)
+}
+
+func (h *tuiHook) stop(n *tuiNode, err error, msg string) {
+	now := h.now()
+	n.Elapsed = now.Sub(n.Started)
+	if err != nil {
+		n.Status = tuiFailed
+		n.log(now, fmt.Sprintf("Error: %s", err))
+		return
+	}
+	n.Status = tuiComplete
+	n.log(now, fmt.Sprintf("%s after %s", msg, n.Elapsed.Round(time.Second)))
+}
+
+func (n *tuiNode) log(now time.Time, msg string) {
+	n.Logs = append(n.Logs, fmt.Sprintf("%s %s", now.Format("15:04:05"), msg))
+}
+
+func (h *tuiHook) PreApply(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PreApply(addr, gen, action, priorState, plannedNewState)
+	}
+	var msg string
+	switch action {
+	case plans.Delete:
+		msg = "Destroying..."
+	case plans.Create:
+		msg = "Creating..."
+	case plans.Update:
+		msg = "Modifying..."
+	case plans.Read:
+		msg = "Reading..."
+	default:
+		return tofu.HookActionContinue, nil
+	}
+	h.update(addr, gen, func(n *tuiNode) {
+		h.start(n, tuiActionLabel(action), msg)
+		n.Diff = tuiDiff(priorState, plannedNewState)
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) PostApply(addr addrs.AbsResourceInstance, gen states.Generation, newState cty.Value, err error) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PostApply(addr, gen, newState, err)
+	}
+	h.update(addr, gen, func(n *tuiNode) {
+		if n.Status != tuiRunning {
+			return
+		}
+		msg := "Complete"
+		switch n.Label {
+		case "create":
+			msg = "Creation complete"
+		case "update":
+			msg = "Modifications complete"
+		case "destroy":
+			msg = "Destruction complete"
+		case "read":
+			msg = "Read complete"
+		}
+		h.stop(n, err, msg)
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) PreProvisionInstanceStep(addr addrs.AbsResourceInstance, typeName string) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PreProvisionInstanceStep(addr, typeName)
+	}
+	h.update(addr, states.CurrentGen, func(n *tuiNode) {
+		n.log(h.now(), fmt.Sprintf("Provisioning with '%s'...", typeName))
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) PostProvisionInstanceStep(addr addrs.AbsResourceInstance, typeName string, err error) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PostProvisionInstanceStep(addr, typeName, err)
+	}
+	if err != nil {
+		h.update(addr, states.CurrentGen, func(n *tuiNode) {
+			n.log(h.now(), fmt.Sprintf("Provisioner '%s' failed: %s", typeName, err))
+		})
+	}
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName string, line string) {
+	if inner := h.passthrough(); inner != nil {
+		inner.ProvisionOutput(addr, typeName, line)
+		return
+	}
+	h.update(addr, states.CurrentGen, func(n *tuiNode) {
+		for _, l := range strings.Split(strings.TrimRight(line, "\n"), "\n") {
+			n.log(h.now(), fmt.Sprintf("(%s): %s", typeName, l))
+		}
+	})
+}
+
+func (h *tuiHook) PreApplyImport(addr addrs.AbsResourceInstance, importing plans.ImportingSrc) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PreApplyImport(addr, importing)
+	}
+	h.update(addr, states.CurrentGen, func(n *tuiNode) {
+		n.log(h.now(), fmt.Sprintf("Importing... [id=%s]", importing.ID))
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) PostApplyImport(addr addrs.AbsResourceInstance, importing plans.ImportingSrc) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PostApplyImport(addr, importing)
+	}
+	h.update(addr, states.CurrentGen, func(n *tuiNode) {
+		n.log(h.now(), fmt.Sprintf("Import complete [id=%s]", importing.ID))
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PreRefresh(addr, gen, priorState)
+	}
+	h.update(addr, gen, func(n *tuiNode) {
+		h.start(n, "refresh", "Refreshing state...")
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value, newState cty.Value) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PostRefresh(addr, gen, priorState, newState)
+	}
+	h.update(addr, gen, func(n *tuiNode) {
+		h.stop(n, nil, "Refresh complete")
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) PreDiff(addr addrs.AbsResourceInstance, gen states.Generation, priorState, proposedNewState cty.Value) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PreDiff(addr, gen, priorState, proposedNewState)
+	}
+	h.update(addr, gen, func(n *tuiNode) {
+		h.start(n, "plan", "Planning...")
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) PostDiff(addr addrs.AbsResourceInstance, gen states.Generation, action plans.Action, priorState, plannedNewState cty.Value) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PostDiff(addr, gen, action, priorState, plannedNewState)
+	}
+	h.update(addr, gen, func(n *tuiNode) {
+		h.stop(n, nil, "Planning complete")
+		n.Label = tuiActionLabel(action)
+		n.Diff = tuiDiff(priorState, plannedNewState)
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) PrePlanImport(addr addrs.AbsResourceInstance, importID string) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PrePlanImport(addr, importID)
+	}
+	h.update(addr, states.CurrentGen, func(n *tuiNode) {
+		n.log(h.now(), fmt.Sprintf("Preparing import... [id=%s]", importID))
+	})
+	return tofu.HookActionContinue, nil
+}
+
+func (h *tuiHook) PostPlanImport(addr addrs.AbsResourceInstance, imported []providers.ImportedResource) (tofu.HookAction, error) {
+	if inner := h.passthrough(); inner != nil {
+		return inner.PostPlanImport(addr, imported)
+	}
+	h.update(addr, states.CurrentGen, func(n *tuiNode) {
+		n.log(h.now(), "Import prepared")
+	})
+	return tofu.HookActionContinue, nil
+}
+
+// tuiActionLabel returns the short description of the given action that is
+// shown next to each resource instance.
+func tuiActionLabel(action plans.Action) string {
+	switch action {
+	case plans.Create:
+		return "create"
+	case plans.Update:
+		return "update"
+	case plans.Delete:
+		return "destroy"
+	case plans.DeleteThenCreate, plans.CreateThenDelete:
+		return "replace"
+	case plans.Read:
+		return "read"
+	case plans.Forget:
+		return "forget"
+	default:
+		return "no changes"
+	}
+}
+
+// tuiDiff describes the differences between two objects, one attribute per
+// line. Unlike the rendered plan it doesn't need the provider's schema, so
+// nested attributes are flattened into paths.
+func tuiDiff(before, after cty.Value) []string {
+	old := tuiFlatten(before)
+	new := tuiFlatten(after)
+
+	keys := make([]string, 0, len(old)+len(new))
+	for k := range old {
+		keys = append(keys, k)
+	}
+	for k := range new {
+		if _, exists := old[k]; !exists {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var ret []string
+	for _, k := range keys {
+		ov, inOld := old[k]
+		nv, inNew := new[k]
+		switch {
+		case !inOld:
+			ret = append(ret, fmt.Sprintf("+ %s = %s", k, nv))
+		case !inNew:
+			ret = append(ret, fmt.Sprintf("- %s = %s", k, ov))
+		case ov != nv:
+			ret = append(ret, fmt.Sprintf("~ %s = %s -> %s", k, ov, nv))
+		}
+	}
+	return ret
+}
+
+// tuiFlatten returns the leaf values of the given object, keyed by their
+// paths.
+func tuiFlatten(v cty.Value) map[string]string {
+	ret := make(map[string]string)
+	if v == cty.NilVal || v.IsNull() {
+		return ret
+	}
+
+	v, pvm := v.UnmarkDeepWithPaths()
+	sensitive := func(path cty.Path) bool {
+		for _, pv := range pvm {
+			if _, ok := pv.Marks[marks.Sensitive]; ok && path.HasPrefix(pv.Path) {
+				return true
+			}
+		}
+		return false
+	}
+
+	_ = cty.Walk(v, func(path cty.Path, v cty.Value) (bool, error) {
+		if len(path) == 0 {
+			return v.IsKnown(), nil
+		}
+		key := tuiPathString(path)
+		ty := v.Type()
+		switch {
+		case sensitive(path):
+			ret[key] = "(sensitive value)"
+			return false, nil
+		case !v.IsKnown():
+			ret[key] = "(known after apply)"
+			return false, nil
+		case v.IsNull():
+			return false, nil
+		case ty.IsPrimitiveType():
+			ret[key] = tuiValueString(v)
+			return false, nil
+		case ty.IsSetType():
+			// Set elements have no stable path, so we show the set as a
+			// whole.
+			if !v.IsWhollyKnown() {
+				ret[key] = "(known after apply)"
+				return false, nil
+			}
+			ret[key] = tuiValueString(v)
+			return false, nil
+		case v.LengthInt() == 0:
+			if ty.IsObjectType() {
+				return false, nil
+			}
+			ret[key] = tuiValueString(v)
+			return false, nil
+		}
+		return true, nil
+	})
+	return ret
+}
+
+func tuiPathString(path cty.Path) string {
+	var b strings.Builder
+	for _, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			if b.Len() > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(step.Name)
+		case cty.IndexStep:
+			switch step.Key.Type() {
+			case cty.String:
+				fmt.Fprintf(&b, "[%q]", step.Key.AsString())
+			case cty.Number:
+				fmt.Fprintf(&b, "[%s]", step.Key.AsBigFloat().Text('f', -1))
+			}
+		}
+	}
+	return b.String()
+}
+
+func tuiValueString(v cty.Value) string {
+	switch v.Type() {
+	case cty.String:
+		return fmt.Sprintf("%q", v.AsString())
+	case cty.Number:
+		return v.AsBigFloat().Text('f', -1)
+	case cty.Bool:
+		return fmt.Sprintf("%t", v.True())
+	}
+	src, err := ctyjson.Marshal(v, v.Type())
+	if err != nil {
+		return "(complex value)"
+	}
+	return string(src)
+}
+
+// OperationTUI wraps the human-readable operation view, closing the
+// interactive display before anything else is rendered, so that the normal
+// output follows once the user leaves the display.
+type OperationTUI struct {
+	Operation
+
+	display *tuiDisplay
+	hook    *tuiHook
+}
+
+var _ Operation = (*OperationTUI)(nil)
+
+func (v *OperationTUI) Interrupted() {
+	v.display.Close()
+	v.Operation.Interrupted()
+}
+
+func (v *OperationTUI) FatalInterrupt() {
+	v.display.Close()
+	v.Operation.FatalInterrupt()
+}
+
+func (v *OperationTUI) Stopping() {
+	v.display.Close()
+	v.Operation.Stopping()
+}
+
+func (v *OperationTUI) Cancelled(planMode plans.Mode) {
+	v.display.Finish()
+	v.Operation.Cancelled(planMode)
+}
+
+func (v *OperationTUI) EmergencyDumpState(stateFile *statefile.File, enc encryption.StateEncryption) error {
+	v.display.Close()
+	return v.Operation.EmergencyDumpState(stateFile, enc)
+}
+
+// Plan is called before the apply phase of the apply command, unless it's
+// applying a saved plan, so it's where the hook takes over from the normal
+// output.
+func (v *OperationTUI) Plan(plan *plans.Plan, schemas *tofu.Schemas) {
+	v.display.Finish()
+	v.Operation.Plan(plan, schemas)
+	v.hook.Activate()
+}
+
+// PlannedChange is called for each change in a saved plan before applying
+// it.
+func (v *OperationTUI) PlannedChange(change *plans.ResourceInstanceChangeSrc) {
+	v.Operation.PlannedChange(change)
+	v.hook.Activate()
+}
+
+func (v *OperationTUI) PlanNextStep(planPath string, genConfigPath string) {
+	v.display.Finish()
+	v.Operation.PlanNextStep(planPath, genConfigPath)
+}
+
+func (v *OperationTUI) CheckResults(results *states.CheckResults) {
+	v.display.Finish()
+	v.Operation.CheckResults(results)
+}
+
+func (v *OperationTUI) Diagnostics(diags tfdiags.Diagnostics) {
+	v.display.Finish()
+	v.Operation.Diagnostics(diags)
+}
@@ -0,0 +1,516 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mitchellh/colorstring"
+	"golang.org/x/term"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// tuiRefreshInterval is how often the interactive display is redrawn while
+// an operation is running, to update the elapsed times.
+const tuiRefreshInterval = 250 * time.Millisecond
+
+// Control sequences for the terminals that support the interactive display.
+const (
+	tuiEnterScreen = "\x1b[?1049h\x1b[?25l"
+	tuiLeaveScreen = "\x1b[?25h\x1b[?1049l"
+	tuiHome        = "\x1b[H"
+	tuiClearLine   = "\x1b[K"
+	tuiClearRest   = "\x1b[J"
+)
+
+// tuiPane selects what the lower part of the interactive display shows for
+// the selected resource instance.
+type tuiPane rune
+
+const (
+	tuiPaneDiff tuiPane = 'D'
+	tuiPaneLogs tuiPane = 'L'
+)
+
+// tuiDisplay is a full-screen, interactive display of the progress of an
+// operation: a tree of the resource instances with their statuses and
+// timings, and the planned changes and progress messages of the selected
+// resource instance.
+//
+// The display takes over the terminal from when the first resource instance
+// is reached until the operation completes, and then waits for the user to
+// leave it so that they can review the results before the normal output is
+// shown.
+type tuiDisplay struct {
+	mu sync.Mutex
+
+	title    string
+	colorize *colorstring.Colorize
+
+	// nodes returns the resource instances to show, and is set by the hook
+	// that records them.
+	nodes func() []tuiNode
+
+	// The terminal, which is replaced in tests.
+	in        io.Reader
+	out       io.Writer
+	size      func() (width, height int)
+	makeRaw   func() (restore func(), err error)
+	interrupt func()
+
+	running  bool
+	finished bool
+	started  time.Time
+	stop     chan struct{}
+	quit     chan struct{}
+	quitOnce sync.Once
+	restore  func()
+
+	selected  int
+	pane      tuiPane
+	scroll    int
+	filter    string
+	filtering bool
+}
+
+func newTUIDisplay(title string, view *View) *tuiDisplay {
+	d := &tuiDisplay{
+		title:    title,
+		colorize: view.colorize,
+		in:       view.streams.Stdin.File,
+		out:      view.streams.Stdout.File,
+		pane:     tuiPaneDiff,
+	}
+	fd := int(view.streams.Stdout.File.Fd())
+	d.size = func() (int, int) {
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			return view.outputColumns(), 24
+		}
+		return width, height
+	}
+	d.makeRaw = func() (func(), error) {
+		fd := int(view.streams.Stdin.File.Fd())
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return nil, err
+		}
+		return func() { _ = term.Restore(fd, state) }, nil
+	}
+	d.interrupt = func() {
+		// The terminal doesn't generate an interrupt for ctrl-c in raw mode,
+		// so we send it ourselves. If that isn't possible on this platform
+		// then we leave the display, so that ctrl-c works as normal again.
+		p, err := os.FindProcess(os.Getpid())
+		if err == nil {
+			err = p.Signal(os.Interrupt)
+		}
+		if err != nil {
+			d.Close()
+		}
+	}
+	return d
+}
+
+// Start takes over the terminal, unless the display is already running or
+// has already been used.
+func (d *tuiDisplay) Start() {
+	d.mu.Lock()
+	if d.running || d.stop != nil {
+		d.mu.Unlock()
+		return
+	}
+	restore, err := d.makeRaw()
+	if err != nil {
+		// We can't take over the terminal, so the operation continues
+		// without any progress display at all rather than failing.
+		d.stop = make(chan struct{})
+		d.mu.Unlock()
+		return
+	}
+	d.running = true
+	d.started = time.Now()
+	d.restore = restore
+	d.stop = make(chan struct{})
+	d.quit = make(chan struct{})
+	fmt.Fprint(d.out, tuiEnterScreen)
+	d.mu.Unlock()
+
+	go d.readInput()
+	go func() {
+		ticker := time.NewTicker(tuiRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-d.stop:
+				return
+			case <-ticker.C:
+				d.draw()
+			}
+		}
+	}()
+	d.draw()
+}
+
+// Finish marks the operation as complete and waits for the user to leave the
+// display, if it is running.
+func (d *tuiDisplay) Finish() {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return
+	}
+	d.finished = true
+	quit := d.quit
+	d.mu.Unlock()
+
+	d.draw()
+	<-quit
+	d.Close()
+}
+
+// Close gives the terminal back without waiting for the user, if the display
+// is running.
+func (d *tuiDisplay) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.running {
+		return
+	}
+	d.running = false
+	close(d.stop)
+	fmt.Fprint(d.out, tuiLeaveScreen)
+	d.restore()
+}
+
+func (d *tuiDisplay) readInput() {
+	buf := make([]byte, 64)
+	for {
+		n, err := d.in.Read(buf)
+		if n > 0 {
+			d.handleInput(string(buf[:n]))
+		}
+		if err != nil {
+			// Without any input the user has no way to leave the display, so
+			// we stop waiting for them.
+			d.quitOnce.Do(func() { close(d.quit) })
+			return
+		}
+	}
+}
+
+func (d *tuiDisplay) handleInput(input string) {
+	d.mu.Lock()
+	if !d.running {
+		d.mu.Unlock()
+		return
+	}
+
+	var interrupt, quit bool
+	for len(input) > 0 {
+		key := input[:1]
+		for _, seq := range []string{"\x1b[A", "\x1b[B", "\x1b[5~", "\x1b[6~", "\x1b[H", "\x1b[F"} {
+			if strings.HasPrefix(input, seq) {
+				key = seq
+				break
+			}
+		}
+		input = input[len(key):]
+
+		if key == "\x03" {
+			interrupt = true
+			continue
+		}
+		if d.filtering {
+			switch key {
+			case "\r", "\n":
+				d.filtering = false
+			case "\x1b":
+				d.filtering = false
+				d.filter = ""
+			case "\x7f", "\b":
+				if len(d.filter) > 0 {
+					d.filter = d.filter[:len(d.filter)-1]
+				}
+			default:
+				if key[0] >= ' ' && key[0] < 0x7f {
+					d.filter += key
+				}
+			}
+			d.selected, d.scroll = 0, 0
+			continue
+		}
+		switch key {
+		case "\x1b[A", "k":
+			d.selected--
+			d.scroll = 0
+		case "\x1b[B", "j":
+			d.selected++
+			d.scroll = 0
+		case "\x1b[H", "g":
+			d.selected, d.scroll = 0, 0
+		case "\x1b[F", "G":
+			d.selected, d.scroll = len(d.nodes()), 0
+		case "\x1b[5~", "K":
+			d.scroll -= 10
+		case "\x1b[6~", "J", " ":
+			d.scroll += 10
+		case "\t":
+			if d.pane == tuiPaneDiff {
+				d.pane = tuiPaneLogs
+			} else {
+				d.pane = tuiPaneDiff
+			}
+			d.scroll = 0
+		case "/":
+			d.filtering = true
+		case "\x1b":
+			d.filter = ""
+		case "q":
+			quit = d.finished
+		}
+	}
+	d.mu.Unlock()
+
+	if interrupt {
+		d.interrupt()
+	}
+	if quit {
+		d.quitOnce.Do(func() { close(d.quit) })
+		return
+	}
+	d.draw()
+}
+
+func (d *tuiDisplay) draw() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if !d.running {
+		return
+	}
+	width, height := d.size()
+	lines := d.render(d.nodes(), width, height, time.Since(d.started))
+	fmt.Fprint(d.out, tuiHome+strings.Join(lines, tuiClearLine+"\r\n")+tuiClearLine+tuiClearRest)
+}
+
+// render returns the lines of the display for the given resource instances
+// and terminal size. It must be called with the lock held.
+func (d *tuiDisplay) render(nodes []tuiNode, width, height int, elapsed time.Duration) []string {
+	if width < 20 {
+		width = 20
+	}
+	if height < 8 {
+		height = 8
+	}
+
+	var running, complete, failed int
+	for _, n := range nodes {
+		switch n.Status {
+		case tuiRunning:
+			running++
+		case tuiComplete:
+			complete++
+		case tuiFailed:
+			failed++
+		}
+	}
+
+	var matched []tuiNode
+	filter := strings.ToLower(d.filter)
+	for _, n := range nodes {
+		if filter == "" || strings.Contains(strings.ToLower(n.Addr.String()+" "+n.Label), filter) {
+			matched = append(matched, n)
+		}
+	}
+	if d.selected >= len(matched) {
+		d.selected = len(matched) - 1
+	}
+	if d.selected < 0 {
+		d.selected = 0
+	}
+
+	lines := make([]string, 0, height)
+	lines = append(lines, d.colorize.Color("[bold]")+tuiTruncate(fmt.Sprintf(
+		"%s: %d resources, %d complete, %d running, %d failed (%s elapsed)",
+		d.title, len(nodes), complete, running, failed, elapsed.Round(time.Second),
+	), width)+d.colorize.Color("[reset]"))
+
+	// The tree takes the larger part of the screen, and the selected
+	// resource instance's details the rest.
+	treeHeight := (height - 3) * 3 / 5
+	detailHeight := height - 3 - treeHeight
+
+	tree, selectedRow := tuiTree(matched, d.selected)
+	first := 0
+	if selectedRow >= treeHeight {
+		first = selectedRow - treeHeight + 1
+	}
+	for i := first; i < first+treeHeight; i++ {
+		if i >= len(tree) {
+			lines = append(lines, "")
+			continue
+		}
+		lines = append(lines, d.renderTreeRow(tree[i], i == selectedRow, width))
+	}
+
+	var selected *tuiNode
+	if len(matched) > 0 {
+		selected = &matched[d.selected]
+	}
+	var detail []string
+	heading := "Changes"
+	if d.pane == tuiPaneLogs {
+		heading = "Logs"
+	}
+	if selected != nil {
+		heading = fmt.Sprintf("%s: %s", heading, selected.Addr)
+		if d.pane == tuiPaneLogs {
+			detail = selected.Logs
+		} else {
+			detail = selected.Diff
+			if len(detail) == 0 {
+				detail = []string{"(no changes)"}
+			}
+		}
+	}
+	heading = "── " + heading + " "
+	if n := width - len([]rune(heading)); n > 0 {
+		heading += strings.Repeat("─", n)
+	}
+	lines = append(lines, d.colorize.Color("[bold]")+tuiTruncate(heading, width)+d.colorize.Color("[reset]"))
+
+	if d.scroll > len(detail)-detailHeight {
+		d.scroll = len(detail) - detailHeight
+	}
+	if d.scroll < 0 {
+		d.scroll = 0
+	}
+	for i := d.scroll; i < d.scroll+detailHeight; i++ {
+		if i >= len(detail) {
+			lines = append(lines, "")
+			continue
+		}
+		line := tuiTruncate(detail[i], width)
+		color := ""
+		if d.pane == tuiPaneDiff && line != "" {
+			switch line[0] {
+			case '+':
+				color = "[green]"
+			case '-':
+				color = "[red]"
+			case '~':
+				color = "[yellow]"
+			}
+		}
+		if color != "" {
+			line = d.colorize.Color(color) + line + d.colorize.Color("[reset]")
+		}
+		lines = append(lines, line)
+	}
+
+	var footer string
+	switch {
+	case d.filtering:
+		footer = "Filter: " + d.filter + "_"
+	case d.finished:
+		footer = "Complete. q: quit  ↑/↓: select  tab: changes/logs  PgUp/PgDn: scroll  /: filter"
+	default:
+		footer = "Running... ↑/↓: select  tab: changes/logs  PgUp/PgDn: scroll  /: filter  ctrl-c: interrupt"
+	}
+	if !d.filtering && d.filter != "" {
+		footer = fmt.Sprintf("[filter: %s, esc to clear] %s", d.filter, footer)
+	}
+	lines = append(lines, tuiTruncate(footer, width))
+	return lines
+}
+
+// tuiRow is a row of the resource tree: either a module instance, or a
+// resource instance within one.
+type tuiRow struct {
+	Depth  int
+	Module addrs.ModuleInstance
+	Node   *tuiNode
+}
+
+// tuiTree arranges the given resource instances, which must be ordered by
+// address, into a tree of module instances, and returns the rows of the tree
+// along with the index of the row of the selected resource instance.
+func tuiTree(nodes []tuiNode, selected int) ([]tuiRow, int) {
+	var rows []tuiRow
+	selectedRow := 0
+	var prev addrs.ModuleInstance
+	for i := range nodes {
+		n := &nodes[i]
+		module := n.Addr.Module
+
+		// Add a row for each module instance that the previous resource
+		// instance wasn't also in.
+		common := 0
+		for common < len(prev) && common < len(module) && prev[common] == module[common] {
+			common++
+		}
+		for depth := common; depth < len(module); depth++ {
+			rows = append(rows, tuiRow{Depth: depth, Module: module[:depth+1]})
+		}
+		prev = module
+
+		if i == selected {
+			selectedRow = len(rows)
+		}
+		rows = append(rows, tuiRow{Depth: len(module), Node: n})
+	}
+	return rows, selectedRow
+}
+
+func (d *tuiDisplay) renderTreeRow(row tuiRow, selected bool, width int) string {
+	prefix := "  "
+	if selected {
+		prefix = "> "
+	}
+	prefix += strings.Repeat("  ", row.Depth)
+
+	if row.Node == nil {
+		return tuiTruncate(prefix+"module."+row.Module[len(row.Module)-1].String(), width)
+	}
+
+	n := row.Node
+	var symbol, color string
+	switch n.Status {
+	case tuiRunning:
+		symbol, color = "•", "[yellow]"
+	case tuiComplete:
+		symbol, color = "✓", "[green]"
+	case tuiFailed:
+		symbol, color = "✗", "[red]"
+	}
+	addr := n.Addr.Resource.String()
+	if n.Deposed != states.NotDeposed {
+		addr = fmt.Sprintf("%s (deposed object %s)", addr, n.Deposed)
+	}
+	text := fmt.Sprintf("%s  %s  %s", addr, n.Label, n.Elapsed.Round(time.Second))
+	text = tuiTruncate(text, width-len([]rune(prefix))-2)
+	return prefix + d.colorize.Color(color) + symbol + d.colorize.Color("[reset]") + " " + text
+}
+
+// tuiTruncate shortens the given line to fit within the given number of
+// columns, assuming that each character takes one column.
+func tuiTruncate(line string, width int) string {
+	runes := []rune(line)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) <= width {
+		return line
+	}
+	return string(runes[:width-1]) + "…"
+}
@@ -0,0 +1,248 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package views
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/mitchellh/colorstring"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// testTUIDisplay returns a display that reads its input from the returned
+// pipe and writes its output to the returned buffer, along with a hook that
+// records events for it.
+func testTUIDisplay(t *testing.T, inner tofu.Hook) (*tuiDisplay, *tuiHook, *io.PipeWriter, *bytes.Buffer) {
+	t.Helper()
+
+	in, input := io.Pipe()
+	t.Cleanup(func() { input.Close() })
+	out := new(bytes.Buffer)
+	d := &tuiDisplay{
+		title:     "OpenTofu apply",
+		colorize:  &colorstring.Colorize{Colors: colorstring.DefaultColors, Disable: true},
+		in:        in,
+		out:       out,
+		size:      func() (int, int) { return 80, 20 },
+		makeRaw:   func() (func(), error) { return func() {}, nil },
+		interrupt: func() {},
+		pane:      tuiPaneDiff,
+	}
+	h := newTUIHook(d, inner)
+	h.now = func() time.Time { return time.Unix(0, 0) }
+	return d, h, input, out
+}
+
+// testTUIClock sets the hook's clock to the given number of seconds after
+// the Unix epoch.
+func testTUIClock(h *tuiHook, seconds int64) {
+	h.Lock()
+	defer h.Unlock()
+	h.now = func() time.Time { return time.Unix(seconds, 0) }
+}
+
+func testTUIAddr(module addrs.ModuleInstance, name string) addrs.AbsResourceInstance {
+	return addrs.Resource{
+		Mode: addrs.ManagedResourceMode,
+		Type: "test_instance",
+		Name: name,
+	}.Instance(addrs.NoKey).Absolute(module)
+}
+
+func TestTUIHook(t *testing.T) {
+	d, h, input, out := testTUIDisplay(t, nil)
+
+	web := testTUIAddr(addrs.RootModuleInstance, "web")
+	db := testTUIAddr(addrs.RootModuleInstance.Child("db", addrs.NoKey), "main")
+
+	testTUIClock(h, 1)
+	h.PreApply(web, states.CurrentGen, plans.Create, cty.NullVal(cty.DynamicPseudoType), cty.ObjectVal(map[string]cty.Value{
+		"ami": cty.StringVal("ami-123"),
+		"id":  cty.UnknownVal(cty.String),
+	}))
+	testTUIClock(h, 2)
+	h.ProvisionOutput(web, "local-exec", "hello\n")
+	testTUIClock(h, 3)
+	h.PostApply(web, states.CurrentGen, cty.DynamicVal, nil)
+	h.PreApply(db, states.CurrentGen, plans.Delete, cty.ObjectVal(map[string]cty.Value{
+		"id": cty.StringVal("db-1"),
+	}), cty.NullVal(cty.DynamicPseudoType))
+	h.PostApply(db, states.CurrentGen, cty.DynamicVal, errors.New("still in use"))
+
+	nodes := h.Nodes()
+	if len(nodes) != 2 {
+		t.Fatalf("wrong number of nodes %d", len(nodes))
+	}
+	// Nodes are ordered by address, so the root module's are first.
+	got, want := nodes[0], tuiNode{
+		Addr:    web,
+		Label:   "create",
+		Status:  tuiComplete,
+		Started: time.Unix(1, 0),
+		Elapsed: 2 * time.Second,
+		Diff: []string{
+			`+ ami = "ami-123"`,
+			`+ id = (known after apply)`,
+		},
+		Logs: []string{
+			time.Unix(1, 0).Format("15:04:05") + " Creating...",
+			time.Unix(2, 0).Format("15:04:05") + " (local-exec): hello",
+			time.Unix(3, 0).Format("15:04:05") + " Creation complete after 2s",
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong node\n%s", diff)
+	}
+	if got := nodes[1]; got.Status != tuiFailed || got.Label != "destroy" || !strings.HasSuffix(got.Logs[len(got.Logs)-1], "Error: still in use") {
+		t.Errorf("wrong failed node %#v", got)
+	}
+
+	// The first event took over the terminal, which is given back once the
+	// user leaves the display.
+	done := make(chan struct{})
+	go func() {
+		d.Finish()
+		close(done)
+	}()
+	if _, err := io.WriteString(input, "q"); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+
+	output := out.String()
+	if !strings.HasPrefix(output, tuiEnterScreen) || !strings.HasSuffix(output, tuiLeaveScreen) {
+		t.Fatalf("display didn't take over and give back the terminal:\n%q", output)
+	}
+	if !strings.Contains(output, "2 resources, 1 complete, 0 running, 1 failed") {
+		t.Fatalf("display is missing the summary:\n%s", output)
+	}
+}
+
+func TestTUIHook_inactive(t *testing.T) {
+	inner := &tofu.MockHook{}
+	d, h, _, out := testTUIDisplay(t, inner)
+
+	addr := testTUIAddr(addrs.RootModuleInstance, "web")
+	h.PreRefresh(addr, states.CurrentGen, cty.DynamicVal)
+	h.PreApply(addr, states.CurrentGen, plans.Read, cty.DynamicVal, cty.DynamicVal)
+	if !inner.PreRefreshCalled || !inner.PreApplyCalled {
+		t.Fatalf("events weren't passed to the inner hook")
+	}
+	if len(h.Nodes()) != 0 || out.Len() != 0 {
+		t.Fatalf("inactive hook recorded events")
+	}
+
+	h.Activate()
+	inner.PreApplyCalled = false
+	h.PreApply(addr, states.CurrentGen, plans.Create, cty.DynamicVal, cty.DynamicVal)
+	if inner.PreApplyCalled {
+		t.Fatalf("event was passed to the inner hook after activating")
+	}
+	if len(h.Nodes()) != 1 {
+		t.Fatalf("active hook didn't record the event")
+	}
+	d.Close()
+}
+
+func TestTUIDiff(t *testing.T) {
+	before := cty.ObjectVal(map[string]cty.Value{
+		"name":     cty.StringVal("old"),
+		"size":     cty.NumberIntVal(1),
+		"password": cty.StringVal("secret").Mark(marks.Sensitive),
+		"tags": cty.MapVal(map[string]cty.Value{
+			"env": cty.StringVal("dev"),
+		}),
+		"zones":   cty.SetVal([]cty.Value{cty.StringVal("a")}),
+		"removed": cty.True,
+	})
+	after := cty.ObjectVal(map[string]cty.Value{
+		"name":     cty.StringVal("new"),
+		"size":     cty.NumberIntVal(1),
+		"password": cty.StringVal("other").Mark(marks.Sensitive),
+		"tags": cty.MapVal(map[string]cty.Value{
+			"env":  cty.StringVal("dev"),
+			"team": cty.StringVal("ops"),
+		}),
+		"zones":   cty.SetVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+		"removed": cty.NullVal(cty.Bool),
+	})
+
+	got := tuiDiff(before, after)
+	want := []string{
+		`~ name = "old" -> "new"`,
+		`- removed = true`,
+		`+ tags["team"] = "ops"`,
+		`~ zones = ["a"] -> ["a","b"]`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong diff\n%s", diff)
+	}
+}
+
+func TestTUIDisplay_render(t *testing.T) {
+	d, _, _, _ := testTUIDisplay(t, nil)
+
+	module := addrs.RootModuleInstance.Child("network", addrs.NoKey)
+	nodes := []tuiNode{
+		{Addr: testTUIAddr(addrs.RootModuleInstance, "web"), Label: "create", Status: tuiRunning, Elapsed: 3 * time.Second, Diff: []string{`+ ami = "ami-123"`}},
+		{Addr: testTUIAddr(module, "subnet"), Label: "update", Status: tuiComplete, Elapsed: time.Second, Logs: []string{"Modifying..."}},
+		{Addr: testTUIAddr(module, "vpc"), Label: "destroy", Status: tuiFailed},
+	}
+
+	lines := d.render(nodes, 60, 12, 5*time.Second)
+	want := []string{
+		"OpenTofu apply: 3 resources, 1 complete, 1 running, 1 faile…",
+		"> • test_instance.web  create  3s",
+		"  module.network",
+		"    ✓ test_instance.subnet  update  1s",
+		"    ✗ test_instance.vpc  destroy  0s",
+		"",
+		"── Changes: test_instance.web ──────────────────────────────",
+		`+ ami = "ami-123"`,
+		"",
+		"",
+		"",
+		"Running... ↑/↓: select  tab: changes/logs  PgUp/PgDn: scrol…",
+	}
+	if diff := cmp.Diff(want, lines); diff != "" {
+		t.Errorf("wrong lines\n%s", diff)
+	}
+
+	// Filtering selects among the matching resource instances only.
+	d.running = true
+	d.handleInput("/subnet\r\t")
+	d.running = false
+	lines = d.render(nodes, 60, 12, 5*time.Second)
+	want = []string{
+		"OpenTofu apply: 3 resources, 1 complete, 1 running, 1 faile…",
+		"  module.network",
+		">   ✓ test_instance.subnet  update  1s",
+		"",
+		"",
+		"",
+		"── Logs: module.network.test_instance.subnet ───────────────",
+		"Modifying...",
+		"",
+		"",
+		"",
+		"[filter: subnet, esc to clear] Running... ↑/↓: select  tab:…",
+	}
+	if diff := cmp.Diff(want, lines); diff != "" {
+		t.Errorf("wrong lines after filtering\n%s", diff)
+	}
+}
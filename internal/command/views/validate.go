@@ -13,6 +13,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/format"
 	viewsjson "github.com/opentofu/opentofu/internal/command/views/json"
 	"github.com/opentofu/opentofu/internal/tfdiags"
+	tfversion "github.com/opentofu/opentofu/version"
 )
 
 // The Validate is used for the validate command.
@@ -32,6 +33,8 @@ func NewValidate(vt arguments.ViewType, view *View) Validate {
 		return &ValidateJSON{view: view}
 	case arguments.ViewHuman:
 		return &ValidateHuman{view: view}
+	case arguments.ViewSARIF:
+		return &ValidateSARIF{view: view}
 	default:
 		panic(fmt.Sprintf("unknown view type %v", vt))
 	}
@@ -141,3 +144,38 @@ func (v *ValidateJSON) Results(diags tfdiags.Diagnostics) int {
 func (v *ValidateJSON) Diagnostics(diags tfdiags.Diagnostics) {
 	v.view.Diagnostics(diags)
 }
+
+// The ValidateSARIF implementation renders validation results as a SARIF log,
+// for code scanning tools.
+type ValidateSARIF struct {
+	view *View
+}
+
+var _ Validate = (*ValidateSARIF)(nil)
+
+func (v *ValidateSARIF) Results(diags tfdiags.Diagnostics) int {
+	log := viewsjson.NewSARIFLog(tfversion.String())
+	configSources := v.view.configSources()
+	for _, diag := range diags {
+		log.AddDiagnostic(viewsjson.NewDiagnostic(diag, configSources), nil)
+	}
+
+	j, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		// Should never happen because we fully-control the input here
+		panic(err)
+	}
+	v.view.streams.Println(string(j))
+
+	if diags.HasErrors() {
+		return 1
+	}
+	return 0
+}
+
+// Diagnostics should only be called if the validation walk cannot be executed.
+// In this case, we choose to render human-readable diagnostic output, as for
+// the JSON view.
+func (v *ValidateSARIF) Diagnostics(diags tfdiags.Diagnostics) {
+	v.view.Diagnostics(diags)
+}
@@ -136,3 +136,41 @@ func TestValidateJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSARIF(t *testing.T) {
+	streams, done := terminal.StreamsForTesting(t)
+	view := NewView(streams)
+	view.Configure(&arguments.View{NoColor: true})
+	v := NewValidate(arguments.ViewSARIF, view)
+
+	var diags tfdiags.Diagnostics
+	diags = diags.Append(tfdiags.Sourceless(
+		tfdiags.Error,
+		"Configuration is missing random_pet",
+		"Every configuration should have a random_pet.",
+	))
+
+	if ret := v.Results(diags); ret != 1 {
+		t.Errorf("expected 1 return code, got %d", ret)
+	}
+
+	got := done(t).Stdout()
+
+	// The structure of the results is tested with the SARIF log itself, so
+	// here we only check that the output is a SARIF log with one result.
+	var result struct {
+		Version string `json:"version"`
+		Runs    []struct {
+			Results []interface{} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal([]byte(got), &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Version != "2.1.0" {
+		t.Errorf("wrong SARIF version %q", result.Version)
+	}
+	if len(result.Runs) != 1 || len(result.Runs[0].Results) != 1 {
+		t.Errorf("expected one run with one result, got:\n%s", got)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/mitchellh/colorstring"
 	"github.com/opentofu/opentofu/internal/command/arguments"
 	"github.com/opentofu/opentofu/internal/command/format"
+	"github.com/opentofu/opentofu/internal/command/jsonschema"
+	"github.com/opentofu/opentofu/internal/i18n"
 	"github.com/opentofu/opentofu/internal/terminal"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -33,6 +35,23 @@ type View struct {
 	// only the important details.
 	concise bool
 
+	// fullStringDiff disables the inline diffs of changes to long strings in
+	// rendered plans.
+	fullStringDiff bool
+
+	// suppressAttributes are the patterns of resource attributes whose
+	// values are hidden in rendered plans.
+	suppressAttributes []string
+
+	// summary renders planned changes grouped by module and resource type,
+	// only rendering in full the changes matching summaryExpand.
+	summary       bool
+	summaryExpand []string
+
+	// jsonVersion is the major version of the JSON output formats selected
+	// with the -json-version option.
+	jsonVersion int
+
 	// This unfortunate wart is required to enable rendering of diagnostics which
 	// have associated source code in the configuration. This function pointer
 	// will be dereferenced as late as possible when rendering diagnostics in
@@ -51,6 +70,7 @@ func NewView(streams *terminal.Streams) *View {
 			Reset:   true,
 		},
 		configSources: func() map[string][]byte { return nil },
+		jsonVersion:   jsonschema.LatestVersion,
 	}
 }
 
@@ -70,11 +90,32 @@ func (v *View) RunningInAutomation() bool {
 	return v.runningInAutomation
 }
 
+// InteractiveTerminal returns true if both the input and the output are
+// terminals, as required for full-screen, interactive displays.
+func (v *View) InteractiveTerminal() bool {
+	return v.streams.Stdin.IsTerminal() && v.streams.Stdout.IsTerminal()
+}
+
 // Configure applies the global view configuration flags.
 func (v *View) Configure(view *arguments.View) {
 	v.colorize.Disable = view.NoColor
 	v.compactWarnings = view.CompactWarnings
 	v.concise = view.Concise
+	v.fullStringDiff = view.FullStringDiff
+	v.suppressAttributes = view.SuppressAttributes
+	v.summary = view.Summary
+	v.summaryExpand = view.SummaryExpand
+	// An unsupported version is reported by view.Parse, so we just keep the
+	// latest one in that case.
+	if version, err := jsonschema.ParseVersion(view.JSONVersion); err == nil {
+		v.jsonVersion = version
+	}
+}
+
+// JSONVersion returns the major version of the JSON output formats to
+// produce.
+func (v *View) JSONVersion() int {
+	return v.jsonVersion
 }
 
 // SetConfigSources overrides the default no-op callback with a new function
@@ -136,7 +177,7 @@ func (v *View) Diagnostics(diags tfdiags.Diagnostics) {
 // of their CLI arguments successfully. It refers users to the full help output
 // rather than rendering it directly, which can be overwhelming and confusing.
 func (v *View) HelpPrompt(command string) {
-	v.streams.Eprintf(helpPrompt, command)
+	v.streams.Eprint(i18n.Sprintf(helpPrompt, command))
 }
 
 const helpPrompt = `
@@ -33,7 +33,8 @@ func (c *WorkspaceCommand) Help() string {
 	helpText := `
 Usage: tofu [global options] workspace
 
-  new, list, show, select and delete OpenTofu workspaces.
+  new, list, show, select, update, compare and delete OpenTofu workspaces,
+  and manage the variable values stored with them.
 
 `
 	return strings.TrimSpace(helpText)
@@ -87,6 +88,8 @@ for this configuration.
 
 	envDeleted = `[reset][green]Deleted workspace %q!`
 
+	envUpdated = `[reset][green]Updated workspace %q.`
+
 	envWarnNotEmpty = `[reset][yellow]WARNING: %q was non-empty.
 The resources managed by the deleted workspace may still exist,
 but are no longer manageable by OpenTofu since the state has
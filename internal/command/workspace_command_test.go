@@ -6,17 +6,22 @@
 package command
 
 import (
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 
 	"github.com/mitchellh/cli"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend"
 	"github.com/opentofu/opentofu/internal/backend/local"
 	"github.com/opentofu/opentofu/internal/backend/remote-state/inmem"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statemgr"
 
@@ -290,6 +295,315 @@ func TestWorkspace_createWithState(t *testing.T) {
 	}
 }
 
+func TestWorkspace_createWithMeta(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("inmem-backend"), td)
+	defer testChdir(t, td)()
+	defer inmem.Reset()
+
+	// init the backend
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	initCmd := &InitCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := initCmd.Run([]string{}); code != 0 {
+		t.Fatalf("bad: \n%s", ui.ErrorWriter.String())
+	}
+
+	workspace := "test_workspace"
+
+	args := []string{"-description", "Production environment", "-tag", "env=prod", "-tag", "team=platform", workspace}
+	ui = new(cli.MockUi)
+	newCmd := &WorkspaceNewCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := newCmd.Run(args); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	b := backend.TestBackendConfig(t, inmem.New(encryption.StateEncryptionDisabled()), nil)
+	sMgr, err := b.StateMgr(workspace)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sMgr.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := sMgr.State().WorkspaceMeta
+	want := &states.WorkspaceMeta{
+		Description: "Production environment",
+		Tags: map[string]string{
+			"env":  "prod",
+			"team": "platform",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong workspace metadata\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestWorkspace_update(t *testing.T) {
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	newCmd := &WorkspaceNewCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := newCmd.Run([]string{"-description", "Staging", "-tag", "env=staging", "-tag", "team=platform", "test"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	ui = new(cli.MockUi)
+	updateCmd := &WorkspaceUpdateCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := updateCmd.Run([]string{"-description", "Production", "-tag", "env=prod", "-untag", "team", "test"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	b, err := local.New(encryption.StateEncryptionDisabled()).StateMgr("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+
+	got := b.State().WorkspaceMeta
+	want := &states.WorkspaceMeta{
+		Description: "Production",
+		Tags: map[string]string{
+			"env": "prod",
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong workspace metadata\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestWorkspace_updateInvalid(t *testing.T) {
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	updateCmd := &WorkspaceUpdateCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+
+	// No changes requested
+	if code := updateCmd.Run([]string{"default"}); code != cli.RunResultHelp {
+		t.Fatalf("expected help, got %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	// Workspace does not exist
+	ui = new(cli.MockUi)
+	updateCmd = &WorkspaceUpdateCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := updateCmd.Run([]string{"-description", "foo", "missing"}); code != 1 {
+		t.Fatalf("expected failure, got %d", code)
+	}
+	if got, want := ui.ErrorWriter.String(), `Workspace "missing" doesn't exist.`; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestWorkspace_diff(t *testing.T) {
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	provider := addrs.AbsProviderConfig{
+		Provider: addrs.NewDefaultProvider("test"),
+		Module:   addrs.RootModule,
+	}
+	instance := func(name string) addrs.AbsResourceInstance {
+		return addrs.Resource{
+			Mode: addrs.ManagedResourceMode,
+			Type: "test_instance",
+			Name: name,
+		}.Instance(addrs.NoKey).Absolute(addrs.RootModuleInstance)
+	}
+	object := func(attrs string) *states.ResourceInstanceObjectSrc {
+		return &states.ResourceInstanceObjectSrc{
+			AttrsJSON: []byte(attrs),
+			Status:    states.ObjectReady,
+			AttrSensitivePaths: []cty.PathValueMarks{
+				{Path: cty.GetAttrPath("password"), Marks: cty.NewValueMarks(marks.Sensitive)},
+			},
+		}
+	}
+
+	staging := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(instance("web"), object(`{"id":"web","size":"small","tags":{"env":"staging"},"password":"a"}`), provider)
+		s.SetResourceInstanceCurrent(instance("same"), object(`{"id":"same"}`), provider)
+		s.SetResourceInstanceCurrent(instance("staging_only"), object(`{"id":"s"}`), provider)
+	})
+	prod := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(instance("web"), object(`{"id":"web","size":"large","tags":{"env":"prod"},"password":"b"}`), provider)
+		s.SetResourceInstanceCurrent(instance("same"), object(`{"id":"same"}`), provider)
+		s.SetResourceInstanceCurrent(instance("prod_only"), object(`{"id":"p"}`), provider)
+	})
+	testStateFileWorkspaceDefault(t, "staging", staging)
+	testStateFileWorkspaceDefault(t, "prod", prod)
+	prodPath := testStateFile(t, prod)
+
+	for name, args := range map[string][]string{
+		"workspaces": {"-detailed-exitcode", "staging", "prod"},
+		"state file": {"-detailed-exitcode", "-other-state", prodPath, "staging"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			ui := new(cli.MockUi)
+			view, _ := testView(t)
+			diffCmd := &WorkspaceDiffCommand{
+				Meta: Meta{Ui: ui, View: view},
+			}
+			if code := diffCmd.Run(args); code != 2 {
+				t.Fatalf("expected differences, got %d\n\n%s", code, ui.ErrorWriter)
+			}
+
+			got := ui.OutputWriter.String()
+			for _, want := range []string{
+				"differ in 3 resource instances",
+				"+ test_instance.prod_only",
+				"- test_instance.staging_only",
+				"~ test_instance.web",
+				`"small"`,
+				`"large"`,
+				"tags.env",
+			} {
+				if !strings.Contains(got, want) {
+					t.Errorf("output is missing %q\n%s", want, got)
+				}
+			}
+			for _, unwanted := range []string{"test_instance.same", "password", `"a"`, `"b"`} {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("output unexpectedly contains %q\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	diffCmd := &WorkspaceDiffCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := diffCmd.Run([]string{"-detailed-exitcode", "staging", "staging"}); code != 0 {
+		t.Fatalf("expected no differences, got %d\n\n%s", code, ui.OutputWriter)
+	}
+	if got, want := ui.OutputWriter.String(), "have the same resource instances and attributes"; !strings.Contains(got, want) {
+		t.Fatalf("wrong output\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestWorkspace_diffInvalid(t *testing.T) {
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	diffCmd := &WorkspaceDiffCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+
+	// Only one workspace
+	if code := diffCmd.Run([]string{"default"}); code != cli.RunResultHelp {
+		t.Fatalf("expected help, got %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	// Workspace does not exist
+	ui = new(cli.MockUi)
+	diffCmd = &WorkspaceDiffCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := diffCmd.Run([]string{"default", "missing"}); code != 1 {
+		t.Fatalf("expected failure, got %d", code)
+	}
+	if got, want := ui.ErrorWriter.String(), `Workspace "missing" doesn't exist.`; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestWorkspace_listWithMeta(t *testing.T) {
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	for _, args := range [][]string{
+		{"-description", "Production", "-tag", "env=prod", "-tag", "team=platform", "prod"},
+		{"-tag", "env=staging", "-tag", "team=platform", "staging"},
+		{"-tag", "env=dev", "dev"},
+	} {
+		ui := new(cli.MockUi)
+		view, _ := testView(t)
+		newCmd := &WorkspaceNewCommand{
+			Meta: Meta{Ui: ui, View: view},
+		}
+		if code := newCmd.Run(args); code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+		}
+	}
+
+	t.Run("filter", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		view, _ := testView(t)
+		listCmd := &WorkspaceListCommand{
+			Meta: Meta{Ui: ui, View: view},
+		}
+		if code := listCmd.Run([]string{"-tag", "team"}); code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+		}
+
+		got := strings.TrimSpace(ui.OutputWriter.String())
+		want := "prod\n  staging"
+		if got != want {
+			t.Fatalf("\nexpected: %q\nactual:  %q", want, got)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		ui := new(cli.MockUi)
+		view, _ := testView(t)
+		listCmd := &WorkspaceListCommand{
+			Meta: Meta{Ui: ui, View: view},
+		}
+		if code := listCmd.Run([]string{"-json", "-tag", "team=platform", "-tag", "env=prod"}); code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+		}
+
+		var got workspaceListJSON
+		if err := json.Unmarshal(ui.OutputWriter.Bytes(), &got); err != nil {
+			t.Fatalf("invalid JSON output: %s\n%s", err, ui.OutputWriter)
+		}
+		want := workspaceListJSON{
+			FormatVersion: "1.0",
+			Workspaces: []workspaceJSON{
+				{
+					Name:        "prod",
+					Current:     false,
+					Description: "Production",
+					Tags: map[string]string{
+						"env":  "prod",
+						"team": "platform",
+					},
+				},
+			},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("wrong output\ngot:  %#v\nwant: %#v", got, want)
+		}
+	})
+}
+
 func TestWorkspace_delete(t *testing.T) {
 	td := t.TempDir()
 	os.MkdirAll(td, 0755)
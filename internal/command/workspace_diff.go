@@ -0,0 +1,507 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// WorkspaceDiffCommand is a Command implementation that compares the
+// resource instances recorded in the states of two workspaces.
+type WorkspaceDiffCommand struct {
+	Meta
+}
+
+func (c *WorkspaceDiffCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+
+	var otherStatePath string
+	var detailedExitCode bool
+	cmdFlags := c.Meta.defaultFlagSet("workspace diff")
+	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.StringVar(&otherStatePath, "other-state", "", "path")
+	cmdFlags.BoolVar(&detailedExitCode, "detailed-exitcode", false, "detailed-exitcode")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	// With -other-state the second workspace is replaced by a state file,
+	// so there's one less positional argument before the optional DIR.
+	args = cmdFlags.Args()
+	names := 2
+	if otherStatePath != "" {
+		names = 1
+	}
+	if len(args) < names || len(args) > names+1 {
+		if otherStatePath != "" {
+			c.Ui.Error("Expected a single argument: NAME.\n")
+		} else {
+			c.Ui.Error("Expected two arguments: NAME and OTHER.\n")
+		}
+		return cli.RunResultHelp
+	}
+
+	configPath, err := modulePath(args[names:])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	backendConfig, backendDiags := c.loadBackendConfig(configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// This is a read-only command
+	c.ignoreRemoteVersionConflict(b)
+
+	workspaces, err := b.Workspaces()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	left := fmt.Sprintf("workspace %q", args[0])
+	leftState, err := c.workspaceDiffState(b, workspaces, args[0])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	var right string
+	var rightState *states.State
+	if otherStatePath != "" {
+		right = fmt.Sprintf("state file %q", otherStatePath)
+		rightState, err = readWorkspaceDiffStateFile(otherStatePath)
+	} else {
+		right = fmt.Sprintf("workspace %q", args[1])
+		rightState, err = c.workspaceDiffState(b, workspaces, args[1])
+	}
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	d := diffWorkspaceStates(leftState, rightState)
+	c.Ui.Output(c.Colorize().Color(d.render(left, right)))
+
+	if detailedExitCode && !d.empty() {
+		return 2
+	}
+	return 0
+}
+
+// workspaceDiffState returns the latest state snapshot of the given
+// workspace, which must be one of the given existing workspaces.
+func (c *WorkspaceDiffCommand) workspaceDiffState(b backend.Backend, workspaces []string, workspace string) (*states.State, error) {
+	exists := false
+	for _, ws := range workspaces {
+		if workspace == ws {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		return nil, fmt.Errorf(strings.TrimSpace(envDoesNotExist), workspace)
+	}
+
+	stateMgr, err := b.StateMgr(workspace)
+	if err != nil {
+		return nil, err
+	}
+	if err := stateMgr.RefreshState(); err != nil {
+		return nil, fmt.Errorf("Failed to load the state of workspace %q: %w", workspace, err)
+	}
+	state := stateMgr.State()
+	if state == nil {
+		state = states.NewState()
+	}
+	return state, nil
+}
+
+// readWorkspaceDiffStateFile reads the state snapshot in the given file, such
+// as one saved from another backend by "tofu state pull".
+func readWorkspaceDiffStateFile(path string) (*states.State, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sf, err := statefile.Read(f, encryption.StateEncryptionDisabled()) // Assume the given statefile is not encrypted
+	if err != nil {
+		return nil, fmt.Errorf("Error reading state file %q: %w", path, err)
+	}
+	return sf.State, nil
+}
+
+// workspaceStateDiff describes the differences between the resource
+// instances in two states, which are called left and right.
+type workspaceStateDiff struct {
+	// OnlyLeft and OnlyRight are the addresses of the resource instances
+	// that have a current object in only one of the states.
+	OnlyLeft  []string
+	OnlyRight []string
+
+	// Changed are the resource instances that are in both states, but
+	// whose current objects differ.
+	Changed []workspaceInstanceDiff
+}
+
+// workspaceInstanceDiff describes the differences between the current
+// objects of a resource instance that is in both states.
+type workspaceInstanceDiff struct {
+	Addr       string
+	Attributes []workspaceAttributeDiff
+}
+
+// workspaceAttributeDiff describes an attribute whose value differs between
+// the two objects of a resource instance. The values are rendered for
+// display, and are empty if the attribute is absent from that object.
+type workspaceAttributeDiff struct {
+	Path        string
+	Left, Right string
+}
+
+func (d *workspaceStateDiff) empty() bool {
+	return len(d.OnlyLeft) == 0 && len(d.OnlyRight) == 0 && len(d.Changed) == 0
+}
+
+// render returns a description of the differences for display, using the
+// given names for the two states.
+func (d *workspaceStateDiff) render(left, right string) string {
+	var buf strings.Builder
+	if d.empty() {
+		fmt.Fprintf(&buf, "[reset][green]The states of %s and %s have the same resource instances and attributes.", left, right)
+		return buf.String()
+	}
+
+	count := len(d.OnlyLeft) + len(d.OnlyRight) + len(d.Changed)
+	noun := "instances"
+	if count == 1 {
+		noun = "instance"
+	}
+	fmt.Fprintf(&buf, "[reset]The states of %s and %s differ in %d resource %s.\n", left, right, count, noun)
+
+	if len(d.OnlyLeft) > 0 {
+		fmt.Fprintf(&buf, "\n[bold]Only in %s:[reset]\n", left)
+		for _, addr := range d.OnlyLeft {
+			fmt.Fprintf(&buf, "  [red]-[reset] %s\n", addr)
+		}
+	}
+	if len(d.OnlyRight) > 0 {
+		fmt.Fprintf(&buf, "\n[bold]Only in %s:[reset]\n", right)
+		for _, addr := range d.OnlyRight {
+			fmt.Fprintf(&buf, "  [green]+[reset] %s\n", addr)
+		}
+	}
+	if len(d.Changed) > 0 {
+		// Align the values of both states under each attribute.
+		labelWidth := len(left)
+		if len(right) > labelWidth {
+			labelWidth = len(right)
+		}
+		absent := func(v string) string {
+			if v == "" {
+				return "(absent)"
+			}
+			return v
+		}
+
+		buf.WriteString("\n[bold]Different in both:[reset]\n")
+		for _, inst := range d.Changed {
+			fmt.Fprintf(&buf, "  [yellow]~[reset] %s\n", inst.Addr)
+			for _, attr := range inst.Attributes {
+				fmt.Fprintf(&buf, "      %s\n", attr.Path)
+				fmt.Fprintf(&buf, "        %-*s  %s\n", labelWidth+1, left+":", absent(attr.Left))
+				fmt.Fprintf(&buf, "        %-*s  %s\n", labelWidth+1, right+":", absent(attr.Right))
+			}
+		}
+	}
+
+	return strings.TrimRight(buf.String(), "\n")
+}
+
+// diffWorkspaceStates compares the current objects of the resource instances
+// in the two given states. Values are compared as they're recorded in the
+// state, so no provider schemas are needed, and sensitive values are not
+// shown.
+func diffWorkspaceStates(left, right *states.State) *workspaceStateDiff {
+	leftObjs := workspaceDiffObjects(left)
+	rightObjs := workspaceDiffObjects(right)
+
+	ret := &workspaceStateDiff{}
+	for addr, l := range leftObjs {
+		r, ok := rightObjs[addr]
+		if !ok {
+			ret.OnlyLeft = append(ret.OnlyLeft, addr)
+			continue
+		}
+		if attrs := diffWorkspaceObjects(l, r); len(attrs) > 0 {
+			ret.Changed = append(ret.Changed, workspaceInstanceDiff{
+				Addr:       addr,
+				Attributes: attrs,
+			})
+		}
+	}
+	for addr := range rightObjs {
+		if _, ok := leftObjs[addr]; !ok {
+			ret.OnlyRight = append(ret.OnlyRight, addr)
+		}
+	}
+
+	sort.Strings(ret.OnlyLeft)
+	sort.Strings(ret.OnlyRight)
+	sort.Slice(ret.Changed, func(i, j int) bool {
+		return ret.Changed[i].Addr < ret.Changed[j].Addr
+	})
+	return ret
+}
+
+// workspaceDiffObject is the comparable form of the current object of a
+// resource instance.
+type workspaceDiffObject struct {
+	Provider string
+	Status   string
+
+	// Attrs maps the path of each leaf value in the object to its value
+	// rendered as JSON, or to a placeholder if the value is sensitive.
+	Attrs map[string]string
+}
+
+// workspaceDiffObjects returns the comparable form of the current object of
+// each resource instance in the given state, by instance address.
+func workspaceDiffObjects(state *states.State) map[string]workspaceDiffObject {
+	ret := make(map[string]workspaceDiffObject)
+	if state == nil {
+		return ret
+	}
+	for _, ms := range state.Modules {
+		for _, rs := range ms.Resources {
+			for key, is := range rs.Instances {
+				obj := is.Current
+				if obj == nil {
+					// Only deposed objects remain, which will be destroyed
+					// by the next apply.
+					continue
+				}
+
+				var sensitive []string
+				for _, pvm := range obj.AttrSensitivePaths {
+					sensitive = append(sensitive, workspaceDiffPathString(pvm.Path))
+				}
+
+				attrs := make(map[string]string)
+				var raw interface{}
+				if err := json.Unmarshal(obj.AttrsJSON, &raw); err == nil {
+					flattenWorkspaceDiffValue("", raw, sensitive, attrs)
+				}
+
+				ret[rs.Addr.Instance(key).String()] = workspaceDiffObject{
+					Provider: rs.ProviderConfig.String(),
+					Status:   workspaceDiffStatus(obj.Status),
+					Attrs:    attrs,
+				}
+			}
+		}
+	}
+	return ret
+}
+
+// diffWorkspaceObjects returns the differences between the two objects of a
+// resource instance, ordered by attribute path. The provider configuration
+// and status of the objects are compared as if they were attributes.
+func diffWorkspaceObjects(left, right workspaceDiffObject) []workspaceAttributeDiff {
+	var ret []workspaceAttributeDiff
+	if left.Provider != right.Provider {
+		ret = append(ret, workspaceAttributeDiff{Path: "(provider)", Left: left.Provider, Right: right.Provider})
+	}
+	if left.Status != right.Status {
+		ret = append(ret, workspaceAttributeDiff{Path: "(status)", Left: left.Status, Right: right.Status})
+	}
+
+	var attrs []workspaceAttributeDiff
+	for path, l := range left.Attrs {
+		if r := right.Attrs[path]; l != r {
+			attrs = append(attrs, workspaceAttributeDiff{Path: path, Left: l, Right: r})
+		}
+	}
+	for path, r := range right.Attrs {
+		if _, ok := left.Attrs[path]; !ok {
+			attrs = append(attrs, workspaceAttributeDiff{Path: path, Right: r})
+		}
+	}
+	sort.Slice(attrs, func(i, j int) bool {
+		return attrs[i].Path < attrs[j].Path
+	})
+	return append(ret, attrs...)
+}
+
+func workspaceDiffStatus(status states.ObjectStatus) string {
+	if status == states.ObjectTainted {
+		return "tainted"
+	}
+	return "ready"
+}
+
+// flattenWorkspaceDiffValue records each leaf of the given value, decoded
+// from JSON, under its path in attrs. Empty collections are leaves, so that
+// they're distinguishable from absent ones.
+func flattenWorkspaceDiffValue(path string, v interface{}, sensitive []string, attrs map[string]string) {
+	for _, s := range sensitive {
+		if path == s || strings.HasPrefix(path, s+".") || strings.HasPrefix(path, s+"[") {
+			attrs[path] = "(sensitive value)"
+			return
+		}
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if len(v) > 0 {
+			for k, elem := range v {
+				elemPath := k
+				if path != "" {
+					elemPath = path + "." + k
+				}
+				flattenWorkspaceDiffValue(elemPath, elem, sensitive, attrs)
+			}
+			return
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			for i, elem := range v {
+				flattenWorkspaceDiffValue(path+"["+strconv.Itoa(i)+"]", elem, sensitive, attrs)
+			}
+			return
+		}
+	}
+
+	if v == nil {
+		// Null attributes are equivalent to absent ones for comparison.
+		return
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	attrs[path] = string(raw)
+}
+
+// workspaceDiffPathString renders the given path in the same way as
+// flattenWorkspaceDiffValue renders the paths of values.
+func workspaceDiffPathString(path cty.Path) string {
+	var buf strings.Builder
+	for _, step := range path {
+		switch step := step.(type) {
+		case cty.GetAttrStep:
+			if buf.Len() > 0 {
+				buf.WriteByte('.')
+			}
+			buf.WriteString(step.Name)
+		case cty.IndexStep:
+			switch step.Key.Type() {
+			case cty.String:
+				if buf.Len() > 0 {
+					buf.WriteByte('.')
+				}
+				buf.WriteString(step.Key.AsString())
+			case cty.Number:
+				fmt.Fprintf(&buf, "[%s]", step.Key.AsBigFloat().Text('f', -1))
+			}
+		}
+	}
+	return buf.String()
+}
+
+func (c *WorkspaceDiffCommand) AutocompleteArgs() complete.Predictor {
+	return completePredictSequence{
+		c.completePredictWorkspaceName(),
+		c.completePredictWorkspaceName(),
+		complete.PredictDirs(""),
+	}
+}
+
+func (c *WorkspaceDiffCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-other-state":       complete.PredictFiles("*.tfstate"),
+		"-detailed-exitcode": complete.PredictNothing,
+	}
+}
+
+func (c *WorkspaceDiffCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] workspace diff [options] NAME OTHER [DIR]
+
+  Compare the resource instances recorded in the states of two workspaces,
+  listing the instances that are in only one of them and the attributes
+  that differ for instances that are in both. This helps to check that
+  workspaces for different environments are alike.
+
+  Values are compared as they're recorded in the states, without refreshing
+  them, and sensitive values are never shown.
+
+Options:
+
+    -other-state=path   Compare the workspace NAME with the state snapshot
+                        in the given file instead of another workspace, and
+                        omit OTHER. The snapshot can come from another
+                        backend, using "tofu state pull".
+
+    -detailed-exitcode  Return a detailed exit code when the command exits.
+                        When provided, this argument changes the exit codes
+                        and their meanings to provide more granular
+                        information about what the resulting comparison
+                        contains:
+                        0 - Succeeded, the states are alike
+                        1 - Errored
+                        2 - Succeeded, there are differences
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WorkspaceDiffCommand) Synopsis() string {
+	return "Compare the states of two workspaces"
+}
@@ -7,11 +7,14 @@ package command
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/posener/complete"
 
+	"github.com/opentofu/opentofu/internal/backend"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -24,8 +27,12 @@ func (c *WorkspaceListCommand) Run(args []string) int {
 	args = c.Meta.process(args)
 	envCommandShowWarning(c.Ui, c.LegacyName)
 
+	var jsonOutput bool
+	var tagFilters FlagStringSlice
 	cmdFlags := c.Meta.defaultFlagSet("workspace list")
 	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.BoolVar(&jsonOutput, "json", false, "produce JSON output")
+	cmdFlags.Var(&tagFilters, "tag", "only list workspaces with this tag")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -68,7 +75,7 @@ func (c *WorkspaceListCommand) Run(args []string) int {
 	// This command will not write state
 	c.ignoreRemoteVersionConflict(b)
 
-	states, err := b.Workspaces()
+	workspaces, err := b.Workspaces()
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
@@ -76,8 +83,58 @@ func (c *WorkspaceListCommand) Run(args []string) int {
 
 	env, isOverridden := c.WorkspaceOverridden()
 
+	// Reading the metadata requires reading the latest state snapshot of
+	// each workspace, so we only do it when it's needed.
+	var metas map[string]*states.WorkspaceMeta
+	if jsonOutput || len(tagFilters) > 0 {
+		metas = make(map[string]*states.WorkspaceMeta, len(workspaces))
+		var filtered []string
+		for _, s := range workspaces {
+			meta, err := workspaceMeta(b, s)
+			if err != nil {
+				c.Ui.Error(fmt.Sprintf("Failed to read the metadata of workspace %q: %s", s, err))
+				return 1
+			}
+			if !workspaceHasTags(meta, tagFilters) {
+				continue
+			}
+			metas[s] = meta
+			filtered = append(filtered, s)
+		}
+		workspaces = filtered
+	}
+
+	if jsonOutput {
+		output := workspaceListJSON{
+			FormatVersion: workspaceListFormatVersion,
+			Workspaces:    make([]workspaceJSON, 0, len(workspaces)),
+		}
+		for _, s := range workspaces {
+			ws := workspaceJSON{
+				Name:    s,
+				Current: s == env,
+				Tags:    map[string]string{},
+			}
+			if meta := metas[s]; meta != nil {
+				ws.Description = meta.Description
+				for k, v := range meta.Tags {
+					ws.Tags[k] = v
+				}
+			}
+			output.Workspaces = append(output.Workspaces, ws)
+		}
+
+		jsonOut, err := json.MarshalIndent(output, "", "  ")
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to marshal workspaces to JSON: %s", err))
+			return 1
+		}
+		c.Ui.Output(string(jsonOut))
+		return 0
+	}
+
 	var out bytes.Buffer
-	for _, s := range states {
+	for _, s := range workspaces {
 		if s == env {
 			out.WriteString("* ")
 		} else {
@@ -100,15 +157,29 @@ func (c *WorkspaceListCommand) AutocompleteArgs() complete.Predictor {
 }
 
 func (c *WorkspaceListCommand) AutocompleteFlags() complete.Flags {
-	return nil
+	return complete.Flags{
+		"-json": complete.PredictNothing,
+		"-tag":  complete.PredictAnything,
+	}
 }
 
 func (c *WorkspaceListCommand) Help() string {
 	helpText := `
-Usage: tofu [global options] workspace list
+Usage: tofu [global options] workspace list [OPTIONS] [DIR]
 
   List OpenTofu workspaces.
 
+Options:
+
+    -tag=key=value      Only list the workspaces that have the given tag. Use
+                        -tag=key to match any value of the tag. This option
+                        can be repeated, in which case workspaces must have
+                        all of the given tags.
+
+    -json               Produce output in a machine-readable JSON format,
+                        including the description and tags of each
+                        workspace.
+
 `
 	return strings.TrimSpace(helpText)
 }
@@ -116,3 +187,54 @@ Usage: tofu [global options] workspace list
 func (c *WorkspaceListCommand) Synopsis() string {
 	return "List Workspaces"
 }
+
+// workspaceListFormatVersion is the version of the JSON output of
+// "tofu workspace list -json". The minor version is incremented for
+// backward-compatible changes, and the major version for breaking changes.
+const workspaceListFormatVersion = "1.0"
+
+type workspaceListJSON struct {
+	FormatVersion string          `json:"format_version"`
+	Workspaces    []workspaceJSON `json:"workspaces"`
+}
+
+type workspaceJSON struct {
+	Name        string            `json:"name"`
+	Current     bool              `json:"current"`
+	Description string            `json:"description"`
+	Tags        map[string]string `json:"tags"`
+}
+
+// workspaceMeta returns the metadata recorded in the latest state snapshot
+// of the given workspace, which is nil if there is none.
+func workspaceMeta(b backend.Backend, name string) (*states.WorkspaceMeta, error) {
+	stateMgr, err := b.StateMgr(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := stateMgr.RefreshState(); err != nil {
+		return nil, err
+	}
+	state := stateMgr.State()
+	if state == nil {
+		return nil, nil
+	}
+	return state.WorkspaceMeta, nil
+}
+
+// workspaceHasTags returns true if the given metadata has all of the tags
+// described by the given filters, each of which is either "key=value" to
+// match a tag with that exact value, or just "key" to match any value.
+func workspaceHasTags(meta *states.WorkspaceMeta, filters []string) bool {
+	for _, filter := range filters {
+		key, want, hasValue := strings.Cut(filter, "=")
+		if meta == nil {
+			return false
+		}
+		got, ok := meta.Tags[key]
+		if !ok || (hasValue && got != want) {
+			return false
+		}
+	}
+	return true
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/opentofu/opentofu/internal/command/clistate"
 	"github.com/opentofu/opentofu/internal/command/views"
 	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -34,11 +35,15 @@ func (c *WorkspaceNewCommand) Run(args []string) int {
 	var stateLock bool
 	var stateLockTimeout time.Duration
 	var statePath string
+	var description string
+	var tags FlagStringKV
 	cmdFlags := c.Meta.defaultFlagSet("workspace new")
 	c.Meta.varFlagSet(cmdFlags)
 	cmdFlags.BoolVar(&stateLock, "lock", true, "lock state")
 	cmdFlags.DurationVar(&stateLockTimeout, "lock-timeout", 0, "lock timeout")
 	cmdFlags.StringVar(&statePath, "state", "", "tofu state file")
+	cmdFlags.StringVar(&description, "description", "", "workspace description")
+	cmdFlags.Var(&tags, "tag", "workspace tag")
 	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
 	if err := cmdFlags.Parse(args); err != nil {
 		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
@@ -128,8 +133,12 @@ func (c *WorkspaceNewCommand) Run(args []string) int {
 	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(
 		strings.TrimSpace(envCreated), workspace)))
 
-	if statePath == "" {
-		// if we're not loading a state, then we're done
+	meta := &states.WorkspaceMeta{
+		Description: description,
+		Tags:        tags,
+	}
+	if statePath == "" && meta.Empty() {
+		// if we're not loading a state or recording metadata, then we're done
 		return 0
 	}
 
@@ -153,21 +162,28 @@ func (c *WorkspaceNewCommand) Run(args []string) int {
 		}()
 	}
 
-	// read the existing state file
-	f, err := os.Open(statePath)
-	if err != nil {
-		c.Ui.Error(err.Error())
-		return 1
-	}
+	state := states.NewState()
+	if statePath != "" {
+		// read the existing state file
+		f, err := os.Open(statePath)
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
 
-	stateFile, err := statefile.Read(f, encryption.StateEncryptionDisabled()) // Assume given statefile is not encrypted
-	if err != nil {
-		c.Ui.Error(err.Error())
-		return 1
+		stateFile, err := statefile.Read(f, encryption.StateEncryptionDisabled()) // Assume given statefile is not encrypted
+		if err != nil {
+			c.Ui.Error(err.Error())
+			return 1
+		}
+		state = stateFile.State
+	}
+	if !meta.Empty() {
+		state.WorkspaceMeta = meta
 	}
 
-	// save the existing state in the new Backend.
-	err = stateMgr.WriteState(stateFile.State)
+	// save the state in the new Backend.
+	err = stateMgr.WriteState(state)
 	if err != nil {
 		c.Ui.Error(err.Error())
 		return 1
@@ -190,7 +206,9 @@ func (c *WorkspaceNewCommand) AutocompleteArgs() complete.Predictor {
 
 func (c *WorkspaceNewCommand) AutocompleteFlags() complete.Flags {
 	return complete.Flags{
-		"-state": complete.PredictFiles("*.tfstate"),
+		"-state":       complete.PredictFiles("*.tfstate"),
+		"-description": complete.PredictAnything,
+		"-tag":         complete.PredictAnything,
 	}
 }
 
@@ -210,6 +228,13 @@ Options:
 
     -state=path         Copy an existing state file into the new workspace.
 
+    -description=text   A description of the new workspace, which the
+                        configuration can read as tofu.workspace_meta.
+
+    -tag=key=value      A tag to record for the new workspace, which the
+                        configuration can read as tofu.workspace_meta. This
+                        option can be repeated to set multiple tags.
+
 `
 	return strings.TrimSpace(helpText)
 }
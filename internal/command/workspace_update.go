@@ -0,0 +1,228 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+	"github.com/posener/complete"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// WorkspaceUpdateCommand is a Command implementation that changes the
+// description and tags of an existing workspace.
+type WorkspaceUpdateCommand struct {
+	Meta
+}
+
+func (c *WorkspaceUpdateCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+
+	var stateLock bool
+	var stateLockTimeout time.Duration
+	var description string
+	var tags FlagStringKV
+	var untags FlagStringSlice
+	cmdFlags := c.Meta.defaultFlagSet("workspace update")
+	c.Meta.varFlagSet(cmdFlags)
+	cmdFlags.BoolVar(&stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	cmdFlags.StringVar(&description, "description", "", "workspace description")
+	cmdFlags.Var(&tags, "tag", "workspace tag")
+	cmdFlags.Var(&untags, "untag", "workspace tag to remove")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) != 1 {
+		c.Ui.Error("Expected a single argument: NAME.\n")
+		return cli.RunResultHelp
+	}
+
+	setDescription := arguments.FlagIsSet(cmdFlags, "description")
+	if !setDescription && len(tags) == 0 && len(untags) == 0 {
+		c.Ui.Error("At least one of -description, -tag or -untag is required.\n")
+		return cli.RunResultHelp
+	}
+
+	configPath, err := modulePath(args[1:])
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	var diags tfdiags.Diagnostics
+
+	backendConfig, backendDiags := c.loadBackendConfig(configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the encryption configuration
+	enc, encDiags := c.EncryptionFromPath(configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// Load the backend
+	b, backendDiags := c.Backend(&BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// This command only changes the workspace metadata, so the rest of the
+	// state is written back exactly as the remote version left it.
+	c.ignoreRemoteVersionConflict(b)
+
+	workspaces, err := b.Workspaces()
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	workspace := args[0]
+	exists := false
+	for _, ws := range workspaces {
+		if workspace == ws {
+			exists = true
+			break
+		}
+	}
+
+	if !exists {
+		c.Ui.Error(fmt.Sprintf(strings.TrimSpace(envDoesNotExist), workspace))
+		return 1
+	}
+
+	stateMgr, err := b.StateMgr(workspace)
+	if err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	if stateLock {
+		stateLocker := clistate.NewLocker(stateLockTimeout, views.NewStateLocker(arguments.ViewHuman, c.View))
+		if diags := stateLocker.Lock(stateMgr, "workspace-update"); diags.HasErrors() {
+			c.showDiagnostics(diags)
+			return 1
+		}
+		defer func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				c.showDiagnostics(diags)
+			}
+		}()
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	state := stateMgr.State()
+	if state == nil {
+		state = states.NewState()
+	}
+
+	meta := state.WorkspaceMeta.DeepCopy()
+	if meta == nil {
+		meta = &states.WorkspaceMeta{}
+	}
+	if setDescription {
+		meta.Description = description
+	}
+	for _, key := range untags {
+		delete(meta.Tags, key)
+	}
+	for key, value := range tags {
+		if meta.Tags == nil {
+			meta.Tags = make(map[string]string)
+		}
+		meta.Tags[key] = value
+	}
+	if meta.Empty() {
+		meta = nil
+	}
+	state.WorkspaceMeta = meta
+
+	if err := stateMgr.WriteState(state); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+	if err := stateMgr.PersistState(nil); err != nil {
+		c.Ui.Error(err.Error())
+		return 1
+	}
+
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf(envUpdated, workspace)))
+
+	return 0
+}
+
+func (c *WorkspaceUpdateCommand) AutocompleteArgs() complete.Predictor {
+	return completePredictSequence{
+		c.completePredictWorkspaceName(),
+		complete.PredictDirs(""),
+	}
+}
+
+func (c *WorkspaceUpdateCommand) AutocompleteFlags() complete.Flags {
+	return complete.Flags{
+		"-description": complete.PredictAnything,
+		"-tag":         complete.PredictAnything,
+		"-untag":       complete.PredictAnything,
+	}
+}
+
+func (c *WorkspaceUpdateCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] workspace update [OPTIONS] NAME [DIR]
+
+  Change the description and tags of an existing OpenTofu workspace.
+
+Options:
+
+    -description=text   Set the description of the workspace. Use an empty
+                        value to remove the description.
+
+    -tag=key=value      Set a tag of the workspace, replacing any existing
+                        value for the same key. This option can be repeated
+                        to set multiple tags.
+
+    -untag=key          Remove the tag with the given key. This option can
+                        be repeated to remove multiple tags.
+
+    -lock=false         Don't hold a state lock during the operation. This is
+                        dangerous if others might concurrently run commands
+                        against the same workspace.
+
+    -lock-timeout=0s    Duration to retry a state lock.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WorkspaceUpdateCommand) Synopsis() string {
+	return "Update the description and tags of a workspace"
+}
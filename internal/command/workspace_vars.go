@@ -0,0 +1,137 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/command/arguments"
+	"github.com/opentofu/opentofu/internal/command/clistate"
+	"github.com/opentofu/opentofu/internal/command/views"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statemgr"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// WorkspaceVarsCommand is a Command implementation that just shows help for
+// the subcommands nested below it.
+type WorkspaceVarsCommand struct {
+	Meta
+}
+
+func (c *WorkspaceVarsCommand) Run(args []string) int {
+	return cli.RunResultHelp
+}
+
+func (c *WorkspaceVarsCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] workspace vars <subcommand> [options] [args]
+
+  Manage the values of input variables stored with the current workspace.
+
+  Stored values are saved in the workspace's state, in the backend, and are
+  encrypted with the state when state encryption is configured. Every
+  operation in the workspace uses them, unless a value for the same variable
+  is given in any other way, such as with -var, a .tfvars file or a TF_VAR_
+  environment variable.
+
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WorkspaceVarsCommand) Synopsis() string {
+	return "Manage input variable values stored with the workspace"
+}
+
+// workspaceVarsState returns the state manager and the latest state of the
+// current workspace, for the "workspace vars" commands.
+//
+// If lock is true then the state is locked first, and the caller must call
+// the returned unlock function once it has written the state.
+func (m *Meta) workspaceVarsState(lock bool, lockTimeout time.Duration, operation string) (statemgr.Full, *states.State, func(), tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	unlock := func() {}
+
+	configPath := m.normalizePath(".")
+
+	backendConfig, backendDiags := m.loadBackendConfig(configPath)
+	diags = diags.Append(backendDiags)
+	if diags.HasErrors() {
+		return nil, nil, unlock, diags
+	}
+
+	// Load the encryption configuration, so that the stored values are
+	// encrypted with the rest of the state.
+	enc, encDiags := m.EncryptionFromPath(configPath)
+	diags = diags.Append(encDiags)
+	if encDiags.HasErrors() {
+		return nil, nil, unlock, diags
+	}
+
+	b, backendDiags := m.Backend(&BackendOpts{
+		Config: backendConfig,
+	}, enc.State())
+	diags = diags.Append(backendDiags)
+	if backendDiags.HasErrors() {
+		return nil, nil, unlock, diags
+	}
+
+	// These commands only change the stored variables, so the rest of the
+	// state is written back exactly as the remote version left it.
+	m.ignoreRemoteVersionConflict(b)
+
+	workspace, err := m.Workspace()
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Error selecting workspace: %w", err))
+		return nil, nil, unlock, diags
+	}
+	stateMgr, err := b.StateMgr(workspace)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("Failed to load state: %w", err))
+		return nil, nil, unlock, diags
+	}
+
+	if lock {
+		stateLocker := clistate.NewLocker(lockTimeout, views.NewStateLocker(arguments.ViewHuman, m.View))
+		if lockDiags := stateLocker.Lock(stateMgr, operation); lockDiags.HasErrors() {
+			diags = diags.Append(lockDiags)
+			return nil, nil, unlock, diags
+		}
+		unlock = func() {
+			if diags := stateLocker.Unlock(); diags.HasErrors() {
+				m.showDiagnostics(diags)
+			}
+		}
+	}
+
+	if err := stateMgr.RefreshState(); err != nil {
+		unlock()
+		diags = diags.Append(fmt.Errorf("Failed to load state: %w", err))
+		return nil, nil, func() {}, diags
+	}
+
+	state := stateMgr.State()
+	if state == nil {
+		state = states.NewState()
+	}
+	return stateMgr, state, unlock, diags
+}
+
+// writeWorkspaceVarsState writes and persists the given state, whose stored
+// variables a "workspace vars" command has changed.
+func writeWorkspaceVarsState(stateMgr statemgr.Full, state *states.State) error {
+	if len(state.WorkspaceVariables) == 0 {
+		state.WorkspaceVariables = nil
+	}
+	if err := stateMgr.WriteState(state); err != nil {
+		return err
+	}
+	return stateMgr.PersistState(nil)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WorkspaceVarsListCommand is a Command implementation that lists the input
+// variable values stored with the current workspace.
+type WorkspaceVarsListCommand struct {
+	Meta
+}
+
+func (c *WorkspaceVarsListCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+
+	cmdFlags := c.Meta.defaultFlagSet("workspace vars list")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+	if len(cmdFlags.Args()) != 0 {
+		c.Ui.Error("The workspace vars list command expects no arguments.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	// This command only reads the state, so it doesn't lock it.
+	_, state, unlock, diags := c.workspaceVarsState(false, 0, "workspace-vars-list")
+	defer unlock()
+	c.showDiagnostics(diags)
+	if diags.HasErrors() {
+		return 1
+	}
+
+	if len(state.WorkspaceVariables) == 0 {
+		c.Ui.Output("No variable values are stored with the workspace.")
+		return 0
+	}
+
+	names := make([]string, 0, len(state.WorkspaceVariables))
+	for name := range state.WorkspaceVariables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		v := state.WorkspaceVariables[name]
+		if v.Sensitive {
+			c.Ui.Output(fmt.Sprintf("%s = (sensitive value)", name))
+			continue
+		}
+		c.Ui.Output(fmt.Sprintf("%s = %s", name, v.Value))
+	}
+	return 0
+}
+
+func (c *WorkspaceVarsListCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] workspace vars list
+
+  List the input variable values stored with the current workspace. The
+  values of sensitive variables are not shown.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WorkspaceVarsListCommand) Synopsis() string {
+	return "List input variable values stored with the workspace"
+}
@@ -0,0 +1,91 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WorkspaceVarsRmCommand is a Command implementation that removes input
+// variable values stored with the current workspace.
+type WorkspaceVarsRmCommand struct {
+	Meta
+}
+
+func (c *WorkspaceVarsRmCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+
+	var stateLock bool
+	var stateLockTimeout time.Duration
+	cmdFlags := c.Meta.defaultFlagSet("workspace vars rm")
+	cmdFlags.BoolVar(&stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) == 0 {
+		c.Ui.Error("Expected at least one argument: NAME.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	stateMgr, state, unlock, diags := c.workspaceVarsState(stateLock, stateLockTimeout, "workspace-vars-rm")
+	defer unlock()
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	// We check all of the names before removing any, so that a typo doesn't
+	// leave the stored variables half-changed.
+	for _, name := range args {
+		if _, exists := state.WorkspaceVariables[name]; !exists {
+			c.showDiagnostics(diags)
+			c.Ui.Error(fmt.Sprintf("No value is stored with the workspace for the variable %q.", name))
+			return 1
+		}
+	}
+	for _, name := range args {
+		delete(state.WorkspaceVariables, name)
+	}
+
+	if err := writeWorkspaceVarsState(stateMgr, state); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to save the variables: %s", err))
+		return 1
+	}
+
+	c.showDiagnostics(diags)
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[reset][green]Removed %d variable value(s) from the workspace.", len(args))))
+	return 0
+}
+
+func (c *WorkspaceVarsRmCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] workspace vars rm [options] NAME...
+
+  Remove the values of the given input variables stored with the current
+  workspace.
+
+Options:
+
+  -lock=false         Don't hold a state lock during the operation. This is
+                      dangerous if others might concurrently run commands
+                      against the same workspace.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WorkspaceVarsRmCommand) Synopsis() string {
+	return "Remove input variable values stored with the workspace"
+}
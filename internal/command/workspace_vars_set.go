@@ -0,0 +1,135 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// WorkspaceVarsSetCommand is a Command implementation that stores values for
+// input variables with the current workspace.
+type WorkspaceVarsSetCommand struct {
+	Meta
+}
+
+func (c *WorkspaceVarsSetCommand) Run(args []string) int {
+	args = c.Meta.process(args)
+
+	var sensitive bool
+	var stateLock bool
+	var stateLockTimeout time.Duration
+	cmdFlags := c.Meta.defaultFlagSet("workspace vars set")
+	cmdFlags.BoolVar(&sensitive, "sensitive", false, "sensitive")
+	cmdFlags.BoolVar(&stateLock, "lock", true, "lock state")
+	cmdFlags.DurationVar(&stateLockTimeout, "lock-timeout", 0, "lock timeout")
+	cmdFlags.Usage = func() { c.Ui.Error(c.Help()) }
+	if err := cmdFlags.Parse(args); err != nil {
+		c.Ui.Error(fmt.Sprintf("Error parsing command-line flags: %s\n", err.Error()))
+		return 1
+	}
+
+	args = cmdFlags.Args()
+	if len(args) == 0 {
+		c.Ui.Error("Expected at least one argument: NAME=VALUE.\n")
+		cmdFlags.Usage()
+		return 1
+	}
+
+	values := make(map[string]string, len(args))
+	var prompt []string
+	for _, arg := range args {
+		name, value, hasValue := strings.Cut(arg, "=")
+		if !hclsyntax.ValidIdentifier(name) {
+			c.Ui.Error(fmt.Sprintf("Invalid variable name %q. Each argument must be a variable name and value separated by an equals sign, like region=us-east-1.", name))
+			return 1
+		}
+		if !hasValue {
+			prompt = append(prompt, name)
+			continue
+		}
+		values[name] = value
+	}
+
+	// Values that aren't given on the command line are read interactively,
+	// so that sensitive values don't end up in the shell history.
+	for _, name := range prompt {
+		value, err := c.UIInput().Input(context.Background(), &tofu.InputOpts{
+			Id:     fmt.Sprintf("var.%s", name),
+			Query:  fmt.Sprintf("var.%s", name),
+			Secret: sensitive,
+		})
+		if err != nil {
+			c.Ui.Error(fmt.Sprintf("Failed to read the value of %s: %s", name, err))
+			return 1
+		}
+		values[name] = value
+	}
+
+	stateMgr, state, unlock, diags := c.workspaceVarsState(stateLock, stateLockTimeout, "workspace-vars-set")
+	defer unlock()
+	if diags.HasErrors() {
+		c.showDiagnostics(diags)
+		return 1
+	}
+
+	if state.WorkspaceVariables == nil {
+		state.WorkspaceVariables = make(map[string]*states.WorkspaceVariable, len(values))
+	}
+	for name, value := range values {
+		state.WorkspaceVariables[name] = &states.WorkspaceVariable{
+			Value:     value,
+			Sensitive: sensitive,
+		}
+	}
+
+	if err := writeWorkspaceVarsState(stateMgr, state); err != nil {
+		c.Ui.Error(fmt.Sprintf("Failed to save the variables: %s", err))
+		return 1
+	}
+
+	c.showDiagnostics(diags)
+	c.Ui.Output(c.Colorize().Color(fmt.Sprintf("[reset][green]Stored %d variable value(s) with the workspace.", len(values))))
+	return 0
+}
+
+func (c *WorkspaceVarsSetCommand) Help() string {
+	helpText := `
+Usage: tofu [global options] workspace vars set [options] NAME=VALUE...
+
+  Store values for input variables with the current workspace, replacing
+  any values already stored for the same variables.
+
+  Each value is interpreted in the same way as a value given with -var: as a
+  literal string for variables of type string, or as an expression
+  otherwise, such as '["a", "b"]'. Give only NAME, without a value, to be
+  prompted for the value instead.
+
+Options:
+
+  -sensitive          Mark the values as sensitive, so that they are never
+                      shown by "tofu workspace vars list" and are read
+                      without echoing them when prompted.
+
+  -lock=false         Don't hold a state lock during the operation. This is
+                      dangerous if others might concurrently run commands
+                      against the same workspace.
+
+  -lock-timeout=0s    Duration to retry a state lock.
+`
+	return strings.TrimSpace(helpText)
+}
+
+func (c *WorkspaceVarsSetCommand) Synopsis() string {
+	return "Store input variable values with the workspace"
+}
@@ -0,0 +1,209 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package command
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mitchellh/cli"
+
+	"github.com/opentofu/opentofu/internal/backend/local"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestWorkspaceVars(t *testing.T) {
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	view, _ := testView(t)
+
+	ui := new(cli.MockUi)
+	setCmd := &WorkspaceVarsSetCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := setCmd.Run([]string{"region=eu-west-1", "zones=[\"a\", \"b\"]"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	ui = new(cli.MockUi)
+	setCmd = &WorkspaceVarsSetCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := setCmd.Run([]string{"-sensitive", "db_password=hunter2"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	ui = new(cli.MockUi)
+	rmCmd := &WorkspaceVarsRmCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := rmCmd.Run([]string{"zones"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	b, err := local.New(encryption.StateEncryptionDisabled()).StateMgr("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	got := b.State().WorkspaceVariables
+	want := map[string]*states.WorkspaceVariable{
+		"region":      {Value: "eu-west-1"},
+		"db_password": {Value: "hunter2", Sensitive: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong workspace variables\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	ui = new(cli.MockUi)
+	listCmd := &WorkspaceVarsListCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := listCmd.Run(nil); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+	if got, want := ui.OutputWriter.String(), "db_password = (sensitive value)\nregion = eu-west-1\n"; got != want {
+		t.Fatalf("wrong output\ngot:  %q\nwant: %q", got, want)
+	}
+}
+
+func TestWorkspaceVars_rmMissing(t *testing.T) {
+	td := t.TempDir()
+	os.MkdirAll(td, 0755)
+	defer testChdir(t, td)()
+
+	view, _ := testView(t)
+
+	ui := new(cli.MockUi)
+	setCmd := &WorkspaceVarsSetCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := setCmd.Run([]string{"region=eu-west-1"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	ui = new(cli.MockUi)
+	rmCmd := &WorkspaceVarsRmCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := rmCmd.Run([]string{"region", "missing"}); code != 1 {
+		t.Fatalf("expected failure, got %d", code)
+	}
+	if got, want := ui.ErrorWriter.String(), `No value is stored with the workspace for the variable "missing".`; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+
+	// Nothing is removed if any of the names is missing.
+	b, err := local.New(encryption.StateEncryptionDisabled()).StateMgr("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := b.State().WorkspaceVariables["region"]; !exists {
+		t.Fatal("region was removed")
+	}
+}
+
+func TestPlan_workspaceVars(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-vars"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	setCmd := &WorkspaceVarsSetCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := setCmd.Run([]string{"foo=stored"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{nil, "stored"},
+		{[]string{"-var", "foo=given"}, "given"},
+	} {
+		p := planVarsFixtureProvider()
+		actual := ""
+		p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
+			actual = req.ProposedNewState.GetAttr("value").AsString()
+			resp.PlannedState = req.ProposedNewState
+			return
+		}
+		view, done := testView(t)
+		c := &PlanCommand{
+			Meta: Meta{
+				testingOverrides: metaOverridesForProvider(p),
+				View:             view,
+			},
+		}
+		code := c.Run(tc.args)
+		output := done(t)
+		if code != 0 {
+			t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+		}
+		if actual != tc.want {
+			t.Errorf("wrong value %q with %v; want %q", actual, tc.args, tc.want)
+		}
+	}
+}
+
+func TestApply_workspaceVarsPreserved(t *testing.T) {
+	td := t.TempDir()
+	testCopyDir(t, testFixturePath("plan-vars"), td)
+	defer testChdir(t, td)()
+
+	ui := new(cli.MockUi)
+	view, _ := testView(t)
+	setCmd := &WorkspaceVarsSetCommand{
+		Meta: Meta{Ui: ui, View: view},
+	}
+	if code := setCmd.Run([]string{"foo=stored"}); code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, ui.ErrorWriter)
+	}
+
+	p := planVarsFixtureProvider()
+	view, done := testView(t)
+	c := &ApplyCommand{
+		Meta: Meta{
+			testingOverrides: metaOverridesForProvider(p),
+			View:             view,
+		},
+	}
+	code := c.Run([]string{"-auto-approve"})
+	output := done(t)
+	if code != 0 {
+		t.Fatalf("bad: %d\n\n%s", code, output.Stderr())
+	}
+
+	// The stored variables are kept when the apply writes the new state.
+	b, err := local.New(encryption.StateEncryptionDisabled()).StateMgr("default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	state := b.State()
+	if state.Empty() {
+		t.Fatal("apply didn't create any resources")
+	}
+	if got, want := state.WorkspaceVariables["foo"], (&states.WorkspaceVariable{Value: "stored"}); !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong stored variable\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
 	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 // -------------------------------------------------------------------------
@@ -80,6 +82,7 @@ func shimTraversalInString(expr hcl.Expression, wantKeyword bool) (hcl.Expressio
 			Summary:  "Quoted keywords are deprecated",
 			Detail:   "In this context, keywords are expected literally rather than in quotes. OpenTofu 0.11 and earlier required quotes, but quoted keywords are now deprecated and will be removed in a future version of OpenTofu. Remove the quotes surrounding this keyword to silence this warning.",
 			Subject:  &srcRange,
+			Extra:    tfdiags.WarningDeprecation,
 		})
 	} else {
 		diags = append(diags, &hcl.Diagnostic{
@@ -87,6 +90,7 @@ func shimTraversalInString(expr hcl.Expression, wantKeyword bool) (hcl.Expressio
 			Summary:  "Quoted references are deprecated",
 			Detail:   "In this context, references are expected literally rather than in quotes. OpenTofu 0.11 and earlier required quotes, but quoted references are now deprecated and will be removed in a future version of OpenTofu. Remove the quotes surrounding this reference to silence this warning.",
 			Subject:  &srcRange,
+			Extra:    tfdiags.WarningDeprecation,
 		})
 	}
 
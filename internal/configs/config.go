@@ -349,7 +349,7 @@ func (c *Config) ProviderRequirementsByModule() (*ModuleRequirements, hcl.Diagno
 			diags = append(diags, c.addProviderRequirementsFromProviderBlock(testReqs.Requirements, provider)...)
 		}
 
-		for _, run := range test.Runs {
+		for _, run := range test.AllRuns() {
 			if run.ConfigUnderTest == nil {
 				continue
 			}
@@ -539,7 +539,7 @@ func (c *Config) addProviderRequirements(reqs getproviders.Requirements, recurse
 
 			if recurse {
 				// Then we'll also look for requirements in testing modules.
-				for _, run := range file.Runs {
+				for _, run := range file.AllRuns() {
 					if run.ConfigUnderTest != nil {
 						moreDiags := run.ConfigUnderTest.addProviderRequirements(reqs, true, false)
 						diags = append(diags, moreDiags...)
@@ -676,7 +676,7 @@ func (c *Config) resolveProviderTypesForTests(providers map[string]addrs.Provide
 		// configs that can affect the types of providers when the names don't
 		// match, so we'll do that here.
 
-		for _, run := range test.Runs {
+		for _, run := range test.AllRuns() {
 
 			// If this run block is executing against our main configuration, we
 			// want to use the external providers passed in. If we are executing
@@ -1036,6 +1036,7 @@ func (c *Config) transformOverriddenResourcesForTest(run *TestRun, file *TestFil
 
 		res.IsOverridden = true
 		res.OverrideValues = overrideRes.Values
+		res.OverrideGenerators = overrideRes.Generators
 	}
 
 	return func() {
@@ -1053,6 +1054,7 @@ func (c *Config) transformOverriddenResourcesForTest(run *TestRun, file *TestFil
 
 			res.IsOverridden = false
 			res.OverrideValues = nil
+			res.OverrideGenerators = nil
 		}
 	}, diags
 }
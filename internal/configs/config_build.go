@@ -52,7 +52,7 @@ func buildTestModules(root *Config, walker ModuleWalker) hcl.Diagnostics {
 	var diags hcl.Diagnostics
 
 	for name, file := range root.Module.Tests {
-		for _, run := range file.Runs {
+		for _, run := range file.AllRuns() {
 			if run.Module == nil {
 				continue
 			}
@@ -134,6 +134,13 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 		copy(path, parent.Path)
 		path[len(path)-1] = call.Name
 
+		staticCall := NewStaticModuleCall(path, call.Variables, parent.Root.Module.SourceDir, call.Workspace)
+		if _, isLocal := call.SourceAddr.(addrs.ModuleSourceLocal); isLocal {
+			// Modules in the same package as their caller share its
+			// named types.
+			staticCall = staticCall.WithTypes(parent.Module.namedTypes)
+		}
+
 		req := ModuleRequest{
 			Name:              call.Name,
 			Path:              path,
@@ -142,7 +149,7 @@ func buildChildModules(parent *Config, walker ModuleWalker) (map[string]*Config,
 			VersionConstraint: call.Version,
 			Parent:            parent,
 			CallRange:         call.DeclRange,
-			Call:              NewStaticModuleCall(path, call.Variables, parent.Root.Module.SourceDir, call.Workspace),
+			Call:              staticCall,
 		}
 		child, modDiags := loadModule(parent.Root, &req, walker)
 		diags = append(diags, modDiags...)
@@ -28,6 +28,10 @@ type Loader struct {
 	// modules is used to install and locate descendent modules that are
 	// referenced (directly or indirectly) from the root module.
 	modules moduleMgr
+
+	// preloaded holds the files of installed modules that were loaded by
+	// preloadModules and not yet used, by module directory.
+	preloaded map[string]*preloadedModule
 }
 
 // Config is used with NewLoader to specify configuration arguments for the
@@ -166,3 +170,10 @@ func (l *Loader) ImportSourcesFromSnapshot(snap *Snapshot) {
 func (l *Loader) AllowLanguageExperiments(allowed bool) {
 	l.parser.AllowLanguageExperiments(allowed)
 }
+
+// SetOverrideSet selects the set of environment-scoped override files that
+// subsequent LoadConfig (and similar) calls will load. See
+// configs.Parser.SetOverrideSet for more information.
+func (l *Loader) SetOverrideSet(name string) {
+	l.parser.SetOverrideSet(name)
+}
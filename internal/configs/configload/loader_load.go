@@ -45,6 +45,9 @@ func (l *Loader) loadConfig(rootMod *configs.Module, diags hcl.Diagnostics) (*co
 		return cfg, diags
 	}
 
+	l.preloadModules()
+	defer func() { l.preloaded = nil }()
+
 	cfg, cDiags := configs.BuildConfig(rootMod, configs.ModuleWalkerFunc(l.moduleWalkerLoad))
 	diags = append(diags, cDiags...)
 
@@ -107,7 +110,7 @@ func (l *Loader) moduleWalkerLoad(req *configs.ModuleRequest) (*configs.Module,
 		})
 	}
 
-	mod, mDiags := l.parser.LoadConfigDir(record.Dir, req.Call)
+	mod, mDiags := l.loadModuleDir(record.Dir, req.Call)
 	diags = append(diags, mDiags...)
 	if mod == nil {
 		// nil specifically indicates that the directory does not exist or
@@ -210,3 +210,48 @@ func TestLoaderLoadConfig_childProviderGrandchildCount(t *testing.T) {
 	})
 
 }
+
+func TestLoaderLoadConfig_preload(t *testing.T) {
+	// The files of the installed modules are loaded concurrently before the
+	// walk, but the diagnostics must still be in the order of the walk, and
+	// a directory used by more than one module call must be loaded for each.
+	fixtureDir := filepath.Clean("testdata/preload-modules")
+	want := []string{
+		"testdata/preload-modules/a/main.tf:2,3-8",
+		"testdata/preload-modules/b/main.tf:2,3-8",
+		"testdata/preload-modules/b/main.tf:2,3-8",
+		"testdata/preload-modules/c/main.tf:2,3-8",
+		"testdata/preload-modules/c/d/main.tf:2,3-8",
+	}
+
+	for i := 0; i < 10; i++ {
+		loader, err := NewLoader(&Config{
+			ModulesDir: filepath.Join(fixtureDir, ".terraform/modules"),
+		})
+		if err != nil {
+			t.Fatalf("unexpected error from NewLoader: %s", err)
+		}
+
+		cfg, diags := loader.LoadConfig(fixtureDir, configs.RootModuleCallForTesting())
+		var got []string
+		for _, diag := range diags {
+			if diag.Summary != "Unsupported argument" {
+				t.Fatalf("unexpected diagnostic: %s", diag.Error())
+			}
+			got = append(got, filepath.ToSlash(diag.Subject.String()))
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("wrong diagnostics\ngot: %swant: %s", spew.Sdump(got), spew.Sdump(want))
+		}
+
+		if cfg.Children["b"].Module == cfg.Children["b2"].Module {
+			t.Fatal("module calls b and b2 share the same module")
+		}
+		if _, ok := cfg.Children["c"].Children["d"].Module.Variables["v"]; !ok {
+			t.Fatal("module c.d has no variable v")
+		}
+		if loader.preloaded != nil {
+			t.Fatal("preloaded modules were not discarded after loading")
+		}
+	}
+}
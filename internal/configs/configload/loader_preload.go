@@ -0,0 +1,122 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configload
+
+import (
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/configs"
+)
+
+// preloadedModule is the result of parsing and decoding the files of an
+// installed module's directory ahead of the walk of the module tree.
+type preloadedModule struct {
+	primary, override []*configs.File
+	diags             hcl.Diagnostics
+}
+
+// preloadModules parses and decodes the files of each of the installed
+// modules concurrently, using up to one goroutine per CPU, so that the
+// sequential walk in configs.BuildConfig only needs to build each module
+// from files that are already loaded.
+//
+// Building a module needs the static values of the variables from its
+// module call, so that part can't be done before the walk. The diagnostics
+// from loading each directory are kept until the walk reaches it, which
+// keeps them in the same order as when loading sequentially.
+func (l *Loader) preloadModules() {
+	seen := make(map[string]bool)
+	var dirs []string
+	for key, record := range l.modules.manifest {
+		if key == "" || seen[record.Dir] {
+			// The root module is loaded separately before the walk.
+			continue
+		}
+		seen[record.Dir] = true
+		dirs = append(dirs, record.Dir)
+	}
+	if len(dirs) < 2 {
+		// Nothing to gain from doing this in the background.
+		return
+	}
+	sort.Strings(dirs)
+
+	results := make([]*preloadedModule, len(dirs))
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for n := min(runtime.NumCPU(), len(dirs)); n > 0; n-- {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				results[i] = l.preloadModule(dirs[i])
+			}
+		}()
+	}
+	for i := range dirs {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	l.preloaded = make(map[string]*preloadedModule, len(dirs))
+	for i, dir := range dirs {
+		if results[i] != nil {
+			l.preloaded[dir] = results[i]
+		}
+	}
+}
+
+// preloadModule loads the files of the module in the given directory in the
+// same way as configs.Parser.LoadConfigDir, or returns nil if the directory
+// can't be read so that the walk will report that in the usual way.
+func (l *Loader) preloadModule(dir string) *preloadedModule {
+	primaryPaths, overridePaths, diags := l.parser.ConfigDirFiles(dir)
+	if diags.HasErrors() {
+		return nil
+	}
+
+	ret := &preloadedModule{}
+	for _, path := range primaryPaths {
+		f, fDiags := l.parser.LoadConfigFile(path)
+		diags = append(diags, fDiags...)
+		if f != nil {
+			ret.primary = append(ret.primary, f)
+		}
+	}
+	for _, path := range overridePaths {
+		f, fDiags := l.parser.LoadConfigFileOverride(path)
+		diags = append(diags, fDiags...)
+		if f != nil {
+			ret.override = append(ret.override, f)
+		}
+	}
+	ret.diags = diags
+	return ret
+}
+
+// loadModuleDir returns the module in the given directory, using the files
+// loaded by preloadModules if they're available.
+func (l *Loader) loadModuleDir(dir string, call configs.StaticModuleCall) (*configs.Module, hcl.Diagnostics) {
+	pre, ok := l.preloaded[dir]
+	if !ok {
+		return l.parser.LoadConfigDir(dir, call)
+	}
+
+	// The files can only be used once, because building a module modifies
+	// them when merging in the override files. If another module call uses
+	// the same directory then it'll be loaded again in the usual way.
+	delete(l.preloaded, dir)
+
+	diags := pre.diags
+	mod, modDiags := configs.NewModule(pre.primary, pre.override, call, dir)
+	diags = append(diags, modDiags...)
+	return mod, diags
+}
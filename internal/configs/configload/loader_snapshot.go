@@ -33,6 +33,8 @@ func (l *Loader) LoadConfigWithSnapshot(rootDir string, call configs.StaticModul
 		Modules: map[string]*SnapshotModule{},
 	}
 	walker := l.makeModuleWalkerSnapshot(snap)
+	l.preloadModules()
+	defer func() { l.preloaded = nil }()
 	cfg, cDiags := configs.BuildConfig(rootMod, walker)
 	diags = append(diags, cDiags...)
 
@@ -56,6 +58,17 @@ func NewLoaderFromSnapshot(snap *Snapshot) *Loader {
 	fs := snapshotFS{snap}
 	parser := configs.NewParser(fs)
 
+	// A snapshot contains only the environment-scoped override files of the
+	// override set that was selected when it was created, so we select that
+	// same set again to load them.
+	for _, mod := range snap.Modules {
+		for filename := range mod.Files {
+			if set, ok := configs.OverrideSetName(filename); ok {
+				parser.SetOverrideSet(set)
+			}
+		}
+	}
+
 	ret := &Loader{
 		parser: parser,
 		modules: moduleMgr{
@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -146,3 +147,34 @@ func TestSnapshotRoundtrip(t *testing.T) {
 		t.Errorf("wrong number of module calls in child_a %d; want %d", got, want)
 	}
 }
+
+func TestSnapshotRoundtrip_overrideSet(t *testing.T) {
+	fixtureDir := filepath.Clean("testdata/override-set")
+	loader, err := NewLoader(&Config{
+		ModulesDir: filepath.Join(fixtureDir, ".terraform/modules"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error from NewLoader: %s", err)
+	}
+	loader.SetOverrideSet("prod")
+
+	_, snap, diags := loader.LoadConfigWithSnapshot(fixtureDir, configs.RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+
+	var gotFiles []string
+	for filename := range snap.Modules[""].Files {
+		gotFiles = append(gotFiles, filename)
+	}
+	sort.Strings(gotFiles)
+	wantFiles := []string{"main.tf", "main_override.prod.tf"}
+	if !reflect.DeepEqual(gotFiles, wantFiles) {
+		t.Errorf("wrong files in snapshot\ngot:  %#v\nwant: %#v", gotFiles, wantFiles)
+	}
+
+	config, diags := NewLoaderFromSnapshot(snap).LoadConfig(fixtureDir, configs.RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+
+	if got, want := config.Module.Variables["size"].Default.AsString(), "large"; got != want {
+		t.Errorf("wrong default %q; want %q", got, want)
+	}
+}
@@ -0,0 +1,3 @@
+variable "v" {
+  bogus = "c/d"
+}
@@ -0,0 +1,7 @@
+variable "v" {
+  bogus = "c"
+}
+
+module "d" {
+  source = "./d"
+}
@@ -0,0 +1,15 @@
+module "a" {
+  source = "./a"
+}
+
+module "b" {
+  source = "./b"
+}
+
+module "b2" {
+  source = "./b"
+}
+
+module "c" {
+  source = "./c"
+}
@@ -97,6 +97,7 @@ func (b *Block) StaticValidateTraversal(traversal hcl.Traversal) tfdiags.Diagnos
 				Summary:  `Deprecated attribute`,
 				Detail:   fmt.Sprintf(`The attribute %q is deprecated. Refer to the provider documentation for details.`, name),
 				Subject:  next.SourceRange().Ptr(),
+				Extra:    tfdiags.WarningDeprecation,
 			})
 		}
 
@@ -127,7 +128,7 @@ func (b *Block) StaticValidateTraversal(traversal hcl.Traversal) tfdiags.Diagnos
 	sort.Strings(suggestions)
 	suggestion := didyoumean.NameSuggestion(name, suggestions)
 	if suggestion != "" {
-		suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+		suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
 	}
 	diags = diags.Append(&hcl.Diagnostic{
 		Severity: hcl.DiagError,
@@ -140,7 +140,9 @@ func TestStaticValidateTraversal(t *testing.T) {
 		},
 		{
 			`obj.atr`,
-			`Unsupported attribute: This object has no argument, nested block, or exported attribute named "atr". Did you mean "str"?`,
+			`Unsupported attribute: This object has no argument, nested block, or exported attribute named "atr".
+
+Did you mean "str"?`,
 		},
 		{
 			`obj.single_block`,
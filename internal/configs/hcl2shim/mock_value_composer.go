@@ -19,8 +19,20 @@ func ComposeMockValueBySchema(schema *configschema.Block, config cty.Value, defa
 	return mockValueComposer{}.composeMockValueBySchema(schema, config, defaults)
 }
 
+// ComposeMockValueBySchemaWithGenerator is like ComposeMockValueBySchema, but
+// uses the given function to generate the computed attributes of the top-level
+// block that have no value in the defaults before falling back to its own
+// defaults.
+func ComposeMockValueBySchemaWithGenerator(schema *configschema.Block, config cty.Value, defaults map[string]cty.Value, generate MockValueGenerateFunc) (
+	cty.Value, tfdiags.Diagnostics) {
+	return mockValueComposer{generate: generate}.composeMockValueBySchema(schema, config, defaults)
+}
+
 type mockValueComposer struct {
 	getMockStringOverride func() string
+
+	// generate is used for the attributes of the top-level block only.
+	generate MockValueGenerateFunc
 }
 
 func (mvc mockValueComposer) getMockString() string {
@@ -124,6 +136,20 @@ func (mvc mockValueComposer) composeMockValueForAttributes(schema *configschema.
 			}
 		}
 
+		if mvc.generate != nil {
+			gv, err := mvc.generate(k, attr.Type)
+			if err != nil {
+				diags = diags.Append(tfdiags.WholeContainingBody(
+					tfdiags.Warning,
+					fmt.Sprintf("Ignored mock/override generator for `%v`", k),
+					fmt.Sprintf("The generator cannot be used for this field: %v.", err),
+				))
+			} else if gv != cty.NilVal {
+				mockAttrs[k] = gv
+				continue
+			}
+		}
+
 		// If there's no value in defaults, we generate our own.
 		v, ok := mvc.getMockValueByType(impliedTypes[k])
 		if !ok {
@@ -216,6 +242,9 @@ func (mvc mockValueComposer) composeMockValueForBlocks(schema *configschema.Bloc
 func (mvc mockValueComposer) getMockValueForBlock(targetType cty.Type, configVal cty.Value, block *configschema.Block, defaults map[string]cty.Value) (cty.Value, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
+	// Generators are only defined for top-level attributes.
+	mvc.generate = nil
+
 	switch {
 	case targetType.IsObjectType():
 		mockBlockVal, moreDiags := mvc.composeMockValueBySchema(block, configVal, defaults)
@@ -0,0 +1,184 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcl2shim
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+// MockValueGeneratorKind is the kind of values that a MockValueGenerator
+// produces.
+type MockValueGeneratorKind string
+
+const (
+	// MockSequence produces sequential numbers, starting from 1 for the first
+	// resource instance that is generated and incrementing for each further
+	// instance.
+	MockSequence MockValueGeneratorKind = "sequence"
+
+	// MockString produces random alphanumeric strings, seeded by the address
+	// of the resource instance and the name of the attribute so that the same
+	// instance always gets the same value.
+	MockString MockValueGeneratorKind = "string"
+
+	// MockTimestamp produces RFC 3339 timestamps, one hour apart for each
+	// resource instance in the order of the sequence.
+	MockTimestamp MockValueGeneratorKind = "timestamp"
+
+	// MockARN produces strings shaped like Amazon Resource Names, with the
+	// service and resource type taken from the resource type.
+	MockARN MockValueGeneratorKind = "arn"
+)
+
+// MockValueGeneratorKinds are all the valid kinds of generators.
+var MockValueGeneratorKinds = []MockValueGeneratorKind{MockSequence, MockString, MockTimestamp, MockARN}
+
+const (
+	mockDefaultStringLength = 16
+	mockDefaultRegion       = "us-east-1"
+	mockDefaultAccount      = "123456789012"
+)
+
+// mockTimestampBase is the first timestamp produced by MockTimestamp.
+var mockTimestampBase = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// MockValueGenerator describes how to generate the value of a single
+// computed attribute.
+type MockValueGenerator struct {
+	Kind MockValueGeneratorKind
+
+	// Prefix is prepended to the values of MockSequence and MockString.
+	Prefix string
+
+	// Length is the number of random characters of MockString, or the default
+	// length if zero.
+	Length int
+
+	// Region and Account are used in the values of MockARN, or the defaults
+	// if empty.
+	Region  string
+	Account string
+}
+
+// MockValueGenerateFunc returns the generated value of the given attribute
+// with the given type. It returns cty.NilVal if the attribute has no
+// generator, and an error if its generator can't produce the type.
+type MockValueGenerateFunc func(name string, ty cty.Type) (cty.Value, error)
+
+// MockValueGenerators generates the values of computed attributes of an
+// overridden resource or data source, by attribute name.
+//
+// MockValueGenerators is safe to use concurrently.
+type MockValueGenerators struct {
+	Attributes map[string]*MockValueGenerator
+
+	mu        sync.Mutex
+	sequences map[string]int
+}
+
+// ForInstance returns the function that generates the values of the given
+// resource instance, which has the given resource type. Each instance is
+// given the next number in the sequence the first time it is seen, and keeps
+// it afterwards, so the values of an instance don't change between plan,
+// apply, and refresh.
+func (g *MockValueGenerators) ForInstance(addr, resourceType string) MockValueGenerateFunc {
+	g.mu.Lock()
+	if g.sequences == nil {
+		g.sequences = make(map[string]int)
+	}
+	n, ok := g.sequences[addr]
+	if !ok {
+		n = len(g.sequences) + 1
+		g.sequences[addr] = n
+	}
+	g.mu.Unlock()
+
+	return func(name string, ty cty.Type) (cty.Value, error) {
+		gen, ok := g.Attributes[name]
+		if !ok {
+			return cty.NilVal, nil
+		}
+
+		if gen.Kind == MockSequence && ty.Equals(cty.Number) && gen.Prefix == "" {
+			return cty.NumberIntVal(int64(n)), nil
+		}
+		if !ty.Equals(cty.String) {
+			return cty.NilVal, fmt.Errorf("the %s generator can't produce values of type %s", gen.Kind, ty.FriendlyName())
+		}
+
+		seed := fmt.Sprintf("%s.%s", addr, name)
+		switch gen.Kind {
+		case MockSequence:
+			return cty.StringVal(gen.Prefix + strconv.Itoa(n)), nil
+		case MockString:
+			length := gen.Length
+			if length == 0 {
+				length = mockDefaultStringLength
+			}
+			return cty.StringVal(gen.Prefix + seededAlphaNumString(seed, length, mockAlphaNumChars)), nil
+		case MockTimestamp:
+			return cty.StringVal(mockTimestampBase.Add(time.Duration(n-1) * time.Hour).Format(time.RFC3339)), nil
+		case MockARN:
+			return cty.StringVal(mockARN(gen, resourceType, seed)), nil
+		default:
+			return cty.NilVal, fmt.Errorf("unknown generator %q", gen.Kind)
+		}
+	}
+}
+
+// mockARN returns an ARN-shaped string for the given resource type, using
+// the name of the provider as the partition and the first word of the rest
+// of the type as the service. For example, aws_s3_bucket produces
+// "arn:aws:s3:us-east-1:123456789012:bucket/...".
+func mockARN(gen *MockValueGenerator, resourceType, seed string) string {
+	partition, rest, found := strings.Cut(resourceType, "_")
+	if !found {
+		partition, rest = "mock", resourceType
+	}
+
+	service, resource, found := strings.Cut(rest, "_")
+	if !found {
+		resource = service
+	}
+	resource = strings.ReplaceAll(resource, "_", "-")
+
+	region, account := gen.Region, gen.Account
+	if region == "" {
+		region = mockDefaultRegion
+	}
+	if account == "" {
+		account = mockDefaultAccount
+	}
+
+	id := seededAlphaNumString(seed, 17, mockLowerAlphaNumChars)
+	return fmt.Sprintf("arn:%s:%s:%s:%s:%s/%s", partition, service, region, account, resource, id)
+}
+
+const (
+	mockAlphaNumChars      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+	mockLowerAlphaNumChars = "abcdefghijklmnopqrstuvwxyz1234567890"
+)
+
+func seededAlphaNumString(seed string, length int, chars string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(seed))
+	r := rand.New(rand.NewSource(int64(h.Sum64()))) //nolint:gosec // It doesn't need to be secure.
+
+	b := strings.Builder{}
+	b.Grow(length)
+	for i := 0; i < length; i++ {
+		b.WriteByte(chars[r.Intn(len(chars))])
+	}
+	return b.String()
+}
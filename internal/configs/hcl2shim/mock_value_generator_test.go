@@ -0,0 +1,106 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package hcl2shim
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+)
+
+func TestMockValueGenerators(t *testing.T) {
+	t.Parallel()
+
+	generators := &MockValueGenerators{
+		Attributes: map[string]*MockValueGenerator{
+			"id":      {Kind: MockSequence, Prefix: "i-"},
+			"count":   {Kind: MockSequence},
+			"name":    {Kind: MockString, Length: 8},
+			"created": {Kind: MockTimestamp},
+			"arn":     {Kind: MockARN, Region: "eu-west-1"},
+			"enabled": {Kind: MockString},
+		},
+	}
+
+	schema := &configschema.Block{
+		Attributes: map[string]*configschema.Attribute{
+			"id":      {Type: cty.String, Computed: true},
+			"count":   {Type: cty.Number, Computed: true},
+			"name":    {Type: cty.String, Computed: true},
+			"created": {Type: cty.String, Computed: true},
+			"arn":     {Type: cty.String, Computed: true},
+			"enabled": {Type: cty.Bool, Computed: true},
+			"other":   {Type: cty.String, Optional: true},
+		},
+	}
+	config := cty.ObjectVal(map[string]cty.Value{
+		"id":      cty.NullVal(cty.String),
+		"count":   cty.NullVal(cty.Number),
+		"name":    cty.NullVal(cty.String),
+		"created": cty.NullVal(cty.String),
+		"arn":     cty.NullVal(cty.String),
+		"enabled": cty.NullVal(cty.Bool),
+		"other":   cty.StringVal("other"),
+	})
+
+	compose := func(addr string) map[string]cty.Value {
+		t.Helper()
+		val, diags := ComposeMockValueBySchemaWithGenerator(schema, config, nil, generators.ForInstance(addr, "aws_s3_bucket"))
+		if diags.HasErrors() {
+			t.Fatalf("unexpected errors: %s", diags.Err())
+		}
+		// The bool attribute can't be generated as a string, so it falls
+		// back to the default with a warning.
+		if len(diags) != 1 {
+			t.Fatalf("expected one warning, got %d", len(diags))
+		}
+		return val.AsValueMap()
+	}
+
+	first := compose("aws_s3_bucket.a")
+	second := compose("aws_s3_bucket.b")
+	again := compose("aws_s3_bucket.a")
+
+	if got, want := first["id"], cty.StringVal("i-1"); !got.RawEquals(want) {
+		t.Errorf("wrong id %#v, want %#v", got, want)
+	}
+	if got, want := second["id"], cty.StringVal("i-2"); !got.RawEquals(want) {
+		t.Errorf("wrong id %#v, want %#v", got, want)
+	}
+	if got, want := second["count"], cty.NumberIntVal(2); !got.RawEquals(want) {
+		t.Errorf("wrong count %#v, want %#v", got, want)
+	}
+	if got, want := second["created"], cty.StringVal("2024-01-01T01:00:00Z"); !got.RawEquals(want) {
+		t.Errorf("wrong created %#v, want %#v", got, want)
+	}
+	if got, want := first["enabled"], cty.False; !got.RawEquals(want) {
+		t.Errorf("wrong enabled %#v, want %#v", got, want)
+	}
+	if got, want := first["other"], cty.StringVal("other"); !got.RawEquals(want) {
+		t.Errorf("wrong other %#v, want %#v", got, want)
+	}
+
+	if name := first["name"].AsString(); !regexp.MustCompile(`^[a-zA-Z0-9]{8}$`).MatchString(name) {
+		t.Errorf("wrong name %q", name)
+	}
+	if arn := first["arn"].AsString(); !regexp.MustCompile(`^arn:aws:s3:eu-west-1:123456789012:bucket/[a-z0-9]{17}$`).MatchString(arn) {
+		t.Errorf("wrong arn %q", arn)
+	}
+
+	// The same instance always gets the same values, while different
+	// instances get different ones.
+	for name, val := range first {
+		if !again[name].RawEquals(val) {
+			t.Errorf("%s changed from %#v to %#v", name, val, again[name])
+		}
+	}
+	if first["name"].RawEquals(second["name"]) {
+		t.Errorf("expected different names for different instances, got %#v", first["name"])
+	}
+}
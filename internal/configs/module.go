@@ -13,6 +13,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/encryption/config"
 	"github.com/opentofu/opentofu/internal/experiments"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 
 	tfversion "github.com/opentofu/opentofu/version"
 )
@@ -43,10 +44,12 @@ type Module struct {
 	ProviderLocalNames   map[addrs.Provider]string
 	ProviderMetas        map[addrs.Provider]*ProviderMeta
 	Encryption           *config.EncryptionConfig
+	Strict               *Strict
 
-	Variables map[string]*Variable
-	Locals    map[string]*Local
-	Outputs   map[string]*Output
+	Variables       map[string]*Variable
+	Locals          map[string]*Local
+	Outputs         map[string]*Output
+	TypeDefinitions map[string]*TypeDefinition
 
 	ModuleCalls map[string]*ModuleCall
 
@@ -67,6 +70,10 @@ type Module struct {
 
 	// StaticEvaluator is used to evaluate static expressions in the scope of the Module.
 	StaticEvaluator *StaticEvaluator
+
+	// namedTypes are all of the named types visible in the module, including
+	// any inherited from the module that called it.
+	namedTypes map[string]*TypeDefinition
 }
 
 // File describes the contents of a single configuration file.
@@ -91,10 +98,12 @@ type File struct {
 	ProviderMetas     []*ProviderMeta
 	RequiredProviders []*RequiredProviders
 	Encryptions       []*config.EncryptionConfig
+	StrictSettings    []*Strict
 
-	Variables []*Variable
-	Locals    []*Local
-	Outputs   []*Output
+	Variables       []*Variable
+	Locals          []*Local
+	Outputs         []*Output
+	TypeDefinitions []*TypeDefinition
 
 	ModuleCalls []*ModuleCall
 
@@ -134,6 +143,7 @@ func NewModule(primaryFiles, overrideFiles []*File, call StaticModuleCall, sourc
 		Variables:          map[string]*Variable{},
 		Locals:             map[string]*Local{},
 		Outputs:            map[string]*Output{},
+		TypeDefinitions:    map[string]*TypeDefinition{},
 		ModuleCalls:        map[string]*ModuleCall{},
 		ManagedResources:   map[string]*Resource{},
 		DataResources:      map[string]*Resource{},
@@ -188,6 +198,10 @@ func NewModule(primaryFiles, overrideFiles []*File, call StaticModuleCall, sourc
 		diags = append(diags, fileDiags...)
 	}
 
+	// Variable types that refer to named types can be decoded only now that
+	// we know all of the type definitions.
+	diags = append(diags, mod.resolveVariableTypes(call)...)
+
 	// Static evaluation to build a StaticContext now that module has all relevant Locals / Variables
 	mod.StaticEvaluator = NewStaticEvaluator(mod, call)
 
@@ -206,6 +220,14 @@ func NewModule(primaryFiles, overrideFiles []*File, call StaticModuleCall, sourc
 		diags = append(diags, mDiags...)
 	}
 
+	// Expand any provider configurations with for_each
+	for _, pc := range mod.ProviderConfigs {
+		diags = append(diags, pc.decodeStaticFields(mod.StaticEvaluator)...)
+	}
+	diags = append(diags, checkProviderInstanceRefs(mod)...)
+
+	diags = append(diags, checkVariableValidationRefs(mod)...)
+
 	diags = append(diags, checkModuleExperiments(mod)...)
 
 	// Generate the FQN -> LocalProviderName map
@@ -322,6 +344,19 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 		m.Encryption = e
 	}
 
+	for _, st := range file.StrictSettings {
+		if m.Strict != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate strict setting",
+				Detail:   fmt.Sprintf("A module may have only one strict setting. Strict mode was previously configured at %s.", m.Strict.DeclRange),
+				Subject:  &st.DeclRange,
+			})
+			continue
+		}
+		m.Strict = st
+	}
+
 	for _, v := range file.Variables {
 		if existing, exists := m.Variables[v.Name]; exists {
 			diags = append(diags, &hcl.Diagnostic{
@@ -329,11 +364,24 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 				Summary:  "Duplicate variable declaration",
 				Detail:   fmt.Sprintf("A variable named %q was already declared at %s. Variable names must be unique within a module.", existing.Name, existing.DeclRange),
 				Subject:  &v.DeclRange,
+				Extra:    tfdiags.CodeDuplicateVariable,
 			})
 		}
 		m.Variables[v.Name] = v
 	}
 
+	for _, td := range file.TypeDefinitions {
+		if existing, exists := m.TypeDefinitions[td.Name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Duplicate type definition",
+				Detail:   fmt.Sprintf("A type named %q was already defined at %s. Type names must be unique within a module.", existing.Name, existing.DeclRange),
+				Subject:  &td.DeclRange,
+			})
+		}
+		m.TypeDefinitions[td.Name] = td
+	}
+
 	for _, l := range file.Locals {
 		if existing, exists := m.Locals[l.Name]; exists {
 			diags = append(diags, &hcl.Diagnostic{
@@ -341,6 +389,7 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 				Summary:  "Duplicate local value definition",
 				Detail:   fmt.Sprintf("A local value named %q was already defined at %s. Local value names must be unique within a module.", existing.Name, existing.DeclRange),
 				Subject:  &l.DeclRange,
+				Extra:    tfdiags.CodeDuplicateLocal,
 			})
 		}
 		m.Locals[l.Name] = l
@@ -353,6 +402,7 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 				Summary:  "Duplicate output definition",
 				Detail:   fmt.Sprintf("An output named %q was already defined at %s. Output names must be unique within a module.", existing.Name, existing.DeclRange),
 				Subject:  &o.DeclRange,
+				Extra:    tfdiags.CodeDuplicateOutput,
 			})
 		}
 		m.Outputs[o.Name] = o
@@ -378,6 +428,7 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 				Summary:  fmt.Sprintf("Duplicate resource %q configuration", existing.Type),
 				Detail:   fmt.Sprintf("A %s resource named %q was already declared at %s. Resource names must be unique per type in each module.", existing.Type, existing.Name, existing.DeclRange),
 				Subject:  &r.DeclRange,
+				Extra:    tfdiags.CodeDuplicateResource,
 			})
 			continue
 		}
@@ -410,6 +461,7 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 				Summary:  fmt.Sprintf("Duplicate data %q configuration", existing.Type),
 				Detail:   fmt.Sprintf("A %s data resource named %q was already declared at %s. Resource names must be unique per type in each module.", existing.Type, existing.Name, existing.DeclRange),
 				Subject:  &r.DeclRange,
+				Extra:    tfdiags.CodeDuplicateResource,
 			})
 			continue
 		}
@@ -425,6 +477,7 @@ func (m *Module) appendFile(file *File) hcl.Diagnostics {
 					Summary:  fmt.Sprintf("Duplicate data %q configuration", existing.Type),
 					Detail:   fmt.Sprintf("A %s data resource named %q was already declared at %s. Resource names must be unique per type in each module, including within check blocks.", existing.Type, existing.Name, existing.DeclRange),
 					Subject:  &c.DataResource.DeclRange,
+					Extra:    tfdiags.CodeDuplicateResource,
 				})
 				continue
 			}
@@ -594,6 +647,11 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 		}
 	}
 
+	if len(file.StrictSettings) != 0 {
+		// Each override file clobbers any existing strict setting.
+		m.Strict = file.StrictSettings[len(file.StrictSettings)-1]
+	}
+
 	for _, v := range file.Variables {
 		existing, exists := m.Variables[v.Name]
 		if !exists {
@@ -602,6 +660,7 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 				Summary:  "Missing base variable declaration to override",
 				Detail:   fmt.Sprintf("There is no variable named %q. An override file can only override a variable that was already declared in a primary configuration file.", v.Name),
 				Subject:  &v.DeclRange,
+				Extra:    tfdiags.CodeMissingOverrideTarget,
 			})
 			continue
 		}
@@ -617,6 +676,7 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 				Summary:  "Missing base local value definition to override",
 				Detail:   fmt.Sprintf("There is no local value named %q. An override file can only override a local value that was already defined in a primary configuration file.", l.Name),
 				Subject:  &l.DeclRange,
+				Extra:    tfdiags.CodeMissingOverrideTarget,
 			})
 			continue
 		}
@@ -624,6 +684,20 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 		diags = append(diags, mergeDiags...)
 	}
 
+	for _, td := range file.TypeDefinitions {
+		if _, exists := m.TypeDefinitions[td.Name]; !exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing base type definition to override",
+				Detail:   fmt.Sprintf("There is no type named %q. An override file can only override a type that was already defined in a primary configuration file.", td.Name),
+				Subject:  &td.DeclRange,
+				Extra:    tfdiags.CodeMissingOverrideTarget,
+			})
+			continue
+		}
+		m.TypeDefinitions[td.Name] = td
+	}
+
 	for _, o := range file.Outputs {
 		existing, exists := m.Outputs[o.Name]
 		if !exists {
@@ -632,6 +706,7 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 				Summary:  "Missing base output definition to override",
 				Detail:   fmt.Sprintf("There is no output named %q. An override file can only override an output that was already defined in a primary configuration file.", o.Name),
 				Subject:  &o.DeclRange,
+				Extra:    tfdiags.CodeMissingOverrideTarget,
 			})
 			continue
 		}
@@ -647,6 +722,7 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 				Summary:  "Missing module call to override",
 				Detail:   fmt.Sprintf("There is no module call named %q. An override file can only override a module call that was defined in a primary configuration file.", mc.Name),
 				Subject:  &mc.DeclRange,
+				Extra:    tfdiags.CodeMissingOverrideTarget,
 			})
 			continue
 		}
@@ -663,6 +739,7 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 				Summary:  "Missing resource to override",
 				Detail:   fmt.Sprintf("There is no %s resource named %q. An override file can only override a resource block defined in a primary configuration file.", r.Type, r.Name),
 				Subject:  &r.DeclRange,
+				Extra:    tfdiags.CodeMissingOverrideTarget,
 			})
 			continue
 		}
@@ -679,6 +756,7 @@ func (m *Module) mergeFile(file *File) hcl.Diagnostics {
 				Summary:  "Missing data resource to override",
 				Detail:   fmt.Sprintf("There is no %s data resource named %q. An override file can only override a data block defined in a primary configuration file.", r.Type, r.Name),
 				Subject:  &r.DeclRange,
+				Extra:    tfdiags.CodeMissingOverrideTarget,
 			})
 			continue
 		}
@@ -798,6 +876,7 @@ func (m *Module) CheckCoreVersionRequirements(path addrs.Module, sourceAddr addr
 						tfversion.String(),
 					),
 					Subject: constraint.DeclRange.Ptr(),
+					Extra:   tfdiags.CodeUnsupportedCoreVersion,
 				})
 			default:
 				diags = diags.Append(&hcl.Diagnostic{
@@ -808,6 +887,7 @@ func (m *Module) CheckCoreVersionRequirements(path addrs.Module, sourceAddr addr
 						path, sourceAddr, tfversion.String(),
 					),
 					Subject: constraint.DeclRange.Ptr(),
+					Extra:   tfdiags.CodeUnsupportedCoreVersion,
 				})
 			}
 		}
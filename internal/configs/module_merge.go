@@ -34,6 +34,9 @@ func (p *Provider) merge(op *Provider) hcl.Diagnostics {
 	if op.Version.Required != nil {
 		p.Version = op.Version
 	}
+	if op.ForEach != nil {
+		p.ForEach = op.ForEach
+	}
 
 	p.Config = MergeBodies(p.Config, op.Config)
 
@@ -51,12 +54,22 @@ func (v *Variable) merge(ov *Variable) hcl.Diagnostics {
 		v.Sensitive = ov.Sensitive
 		v.SensitiveSet = ov.SensitiveSet
 	}
+	if ov.DeprecatedSet {
+		v.Deprecated = ov.Deprecated
+		v.DeprecatedSet = ov.DeprecatedSet
+	}
 	if ov.Default != cty.NilVal {
 		v.Default = ov.Default
 	}
 	if ov.Type != cty.NilType {
 		v.Type = ov.Type
 		v.ConstraintType = ov.ConstraintType
+		v.typeExpr = nil
+	}
+	if ov.typeExpr != nil {
+		// The overridden type refers to named types, so it'll be resolved
+		// along with the rest of the module's variable types.
+		v.typeExpr = ov.typeExpr
 	}
 	if ov.ParsingMode != 0 {
 		v.ParsingMode = ov.ParsingMode
@@ -76,7 +89,10 @@ func (v *Variable) merge(ov *Variable) hcl.Diagnostics {
 	// literal value in config could've been converted to the overridden type
 	// constraint but the converted value cannot. In practice, this situation
 	// should be rare since most of our conversions are interchangable.
-	if v.Default != cty.NilVal {
+	//
+	// A type that refers to named types isn't known yet, so the default is
+	// instead converted once the type is resolved.
+	if v.Default != cty.NilVal && v.typeExpr == nil {
 		val, err := convert.Convert(v.Default, v.ConstraintType)
 		if err != nil {
 			// What exactly we'll say in the error message here depends on whether
@@ -150,6 +166,14 @@ func (o *Output) merge(oo *Output) hcl.Diagnostics {
 		o.Sensitive = oo.Sensitive
 		o.SensitiveSet = oo.SensitiveSet
 	}
+	if oo.DeprecatedSet {
+		o.Deprecated = oo.Deprecated
+		o.DeprecatedSet = oo.DeprecatedSet
+	}
+	if oo.SharedWithSet {
+		o.SharedWith = oo.SharedWith
+		o.SharedWithSet = oo.SharedWithSet
+	}
 
 	// We don't allow depends_on to be overridden because that is likely to
 	// cause confusing misbehavior.
@@ -232,6 +256,11 @@ func (r *Resource) merge(or *Resource, rps map[string]*RequiredProvider) hcl.Dia
 
 	// Provider FQN is set by OpenTofu during Merge
 
+	if or.OperationTimeoutSet {
+		r.OperationTimeout = or.OperationTimeout
+		r.OperationTimeoutSet = or.OperationTimeoutSet
+	}
+
 	if r.Mode == addrs.ManagedResourceMode {
 		// or.Managed is always non-nil for managed resource mode
 
@@ -252,6 +281,22 @@ func (r *Resource) merge(or *Resource, rps map[string]*RequiredProvider) hcl.Dia
 			r.Managed.PreventDestroy = or.Managed.PreventDestroy
 			r.Managed.PreventDestroySet = or.Managed.PreventDestroySet
 		}
+		if or.Managed.PrioritySet {
+			r.Managed.Priority = or.Managed.Priority
+			r.Managed.PrioritySet = or.Managed.PrioritySet
+		}
+		if or.Managed.TwoPhaseReplaceSet {
+			r.Managed.TwoPhaseReplace = or.Managed.TwoPhaseReplace
+			r.Managed.TwoPhaseReplaceSet = or.Managed.TwoPhaseReplaceSet
+		}
+		if or.Managed.TwoPhaseReplaceTimeoutSet {
+			r.Managed.TwoPhaseReplaceTimeout = or.Managed.TwoPhaseReplaceTimeout
+			r.Managed.TwoPhaseReplaceTimeoutSet = or.Managed.TwoPhaseReplaceTimeoutSet
+		}
+		if or.Managed.CanarySet {
+			r.Managed.Canary = or.Managed.Canary
+			r.Managed.CanarySet = or.Managed.CanarySet
+		}
 		if len(or.Managed.Provisioners) != 0 {
 			r.Managed.Provisioners = or.Managed.Provisioners
 		}
@@ -0,0 +1,339 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/opentofu/opentofu/internal/didyoumean"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// TypeDefinition represents a "type" block in a module or file, which gives
+// a name to a type constraint so that it can be referred to as type.<name>
+// from the type constraints of input variables and of other type
+// definitions.
+type TypeDefinition struct {
+	Name        string
+	Description string
+
+	// Expr is the type constraint expression exactly as written in the
+	// configuration, which may itself refer to other named types.
+	Expr hcl.Expression
+
+	DeclRange hcl.Range
+}
+
+func decodeTypeDefinitionBlock(block *hcl.Block) (*TypeDefinition, hcl.Diagnostics) {
+	td := &TypeDefinition{
+		Name:      block.Labels[0],
+		DeclRange: block.DefRange,
+	}
+
+	content, diags := block.Body.Content(typeDefinitionBlockSchema)
+
+	if !hclsyntax.ValidIdentifier(td.Name) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid type name",
+			Detail:   badIdentifierDetail,
+			Subject:  &block.LabelRanges[0],
+		})
+	}
+
+	if attr, exists := content.Attributes["description"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &td.Description)
+		diags = append(diags, valDiags...)
+	}
+
+	if attr, exists := content.Attributes["type"]; exists {
+		td.Expr = attr.Expr
+	}
+
+	return td, diags
+}
+
+// typeExprUsesNamedTypes returns true if the given type constraint
+// expression refers to at least one named type, in which case it can be
+// decoded only once all of the type definitions in the module are known.
+func typeExprUsesNamedTypes(expr hcl.Expression) bool {
+	if _, ok := namedTypeRef(expr); ok {
+		return true
+	}
+	if _, ok := expr.(hclsyntax.Expression); !ok {
+		// Other syntaxes can refer to a named type only as the whole
+		// expression, which we already checked above.
+		return false
+	}
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() == "type" {
+			return true
+		}
+	}
+	return false
+}
+
+// namedTypeRef returns the name of the named type that the given expression
+// refers to, if the expression is a reference to a named type.
+func namedTypeRef(expr hcl.Expression) (string, bool) {
+	traversal, diags := hcl.AbsTraversalForExpr(expr)
+	if diags.HasErrors() || len(traversal) != 2 || traversal.RootName() != "type" {
+		return "", false
+	}
+	attr, ok := traversal[1].(hcl.TraverseAttr)
+	if !ok {
+		return "", false
+	}
+	return attr.Name, true
+}
+
+// namedTypeResolver replaces the references to named types in type
+// constraint expressions with the type constraint expressions they refer to.
+type namedTypeResolver struct {
+	types map[string]*TypeDefinition
+
+	// resolving tracks the named types that are currently being resolved, so
+	// that we can detect definitions that refer to themselves.
+	resolving map[string]bool
+}
+
+func newNamedTypeResolver(types map[string]*TypeDefinition) *namedTypeResolver {
+	return &namedTypeResolver{
+		types:     types,
+		resolving: map[string]bool{},
+	}
+}
+
+// Resolve returns a copy of the given expression with all of its references
+// to named types replaced, which typeexpr can then decode as normal.
+func (r *namedTypeResolver) Resolve(expr hcl.Expression) (hcl.Expression, hcl.Diagnostics) {
+	if name, ok := namedTypeRef(expr); ok {
+		return r.resolveName(name, expr.Range())
+	}
+
+	var diags hcl.Diagnostics
+	switch expr := expr.(type) {
+	case *hclsyntax.ScopeTraversalExpr:
+		if expr.Traversal.RootName() == "type" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid named type reference",
+				Detail:   "A reference to a named type must be of the form type.<name>.",
+				Subject:  expr.Range().Ptr(),
+			})
+		}
+		return expr, diags
+
+	case *hclsyntax.FunctionCallExpr:
+		ret := *expr
+		ret.Args = make([]hclsyntax.Expression, len(expr.Args))
+		for i, arg := range expr.Args {
+			resolved, moreDiags := r.Resolve(arg)
+			diags = append(diags, moreDiags...)
+			ret.Args[i] = syntaxExpr(resolved, arg)
+		}
+		return &ret, diags
+
+	case *hclsyntax.TupleConsExpr:
+		ret := *expr
+		ret.Exprs = make([]hclsyntax.Expression, len(expr.Exprs))
+		for i, elem := range expr.Exprs {
+			resolved, moreDiags := r.Resolve(elem)
+			diags = append(diags, moreDiags...)
+			ret.Exprs[i] = syntaxExpr(resolved, elem)
+		}
+		return &ret, diags
+
+	case *hclsyntax.ObjectConsExpr:
+		ret := *expr
+		ret.Items = make([]hclsyntax.ObjectConsItem, len(expr.Items))
+		for i, item := range expr.Items {
+			resolved, moreDiags := r.Resolve(item.ValueExpr)
+			diags = append(diags, moreDiags...)
+			ret.Items[i] = hclsyntax.ObjectConsItem{
+				KeyExpr:   item.KeyExpr,
+				ValueExpr: syntaxExpr(resolved, item.ValueExpr),
+			}
+		}
+		return &ret, diags
+
+	default:
+		return expr, diags
+	}
+}
+
+func (r *namedTypeResolver) resolveName(name string, rng hcl.Range) (hcl.Expression, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	td, exists := r.types[name]
+	if !exists {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Reference to undeclared type",
+			Detail:   fmt.Sprintf("There is no type named %q declared in this module.%s", name, r.suggestion(name)),
+			Subject:  rng.Ptr(),
+		})
+		return anyTypeExpr(rng), diags
+	}
+	if td.Expr == nil {
+		// decodeTypeDefinitionBlock already reported the missing argument.
+		return anyTypeExpr(rng), diags
+	}
+	if r.resolving[name] {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Self-referencing type definition",
+			Detail:   fmt.Sprintf("The type %q is defined in terms of itself, which is not allowed.", name),
+			Subject:  td.Expr.Range().Ptr(),
+		})
+		return anyTypeExpr(rng), diags
+	}
+
+	r.resolving[name] = true
+	defer delete(r.resolving, name)
+	return r.Resolve(td.Expr)
+}
+
+func (r *namedTypeResolver) suggestion(given string) string {
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	if suggestion := didyoumean.NameSuggestion(given, names); suggestion != "" {
+		return fmt.Sprintf("\n\nDid you mean type.%s?", suggestion)
+	}
+	return ""
+}
+
+// anyTypeExpr stands in for named types that could not be resolved, so
+// that decoding can continue without reporting further errors about them.
+func anyTypeExpr(rng hcl.Range) hclsyntax.Expression {
+	return &hclsyntax.ScopeTraversalExpr{
+		Traversal: hcl.Traversal{hcl.TraverseRoot{Name: "any", SrcRange: rng}},
+		SrcRange:  rng,
+	}
+}
+
+// syntaxExpr returns the given resolved expression as a native syntax
+// expression, so it can take the place of orig within a native syntax
+// expression tree.
+func syntaxExpr(resolved hcl.Expression, orig hclsyntax.Expression) hclsyntax.Expression {
+	if expr, ok := resolved.(hclsyntax.Expression); ok {
+		return expr
+	}
+	// Type definitions written in JSON syntax are not native syntax
+	// expressions, so we must wrap them to use them within one.
+	return &foreignTypeExpr{
+		LiteralValueExpr: hclsyntax.LiteralValueExpr{Val: cty.DynamicVal, SrcRange: orig.Range()},
+		expr:             resolved,
+	}
+}
+
+// foreignTypeExpr embeds an expression from another syntax within a native
+// syntax expression. It is only ever given to typeexpr, which uses the
+// static analysis functions that we delegate to the wrapped expression.
+type foreignTypeExpr struct {
+	hclsyntax.LiteralValueExpr
+	expr hcl.Expression
+}
+
+func (e *foreignTypeExpr) UnwrapExpression() hcl.Expression {
+	return e.expr
+}
+
+// resolveVariableTypes decodes the type constraints of the module's input
+// variables that refer to named types. The named types visible in a module
+// are those it declares itself, along with any inherited from the module
+// that called it.
+func (m *Module) resolveVariableTypes(call StaticModuleCall) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	types := make(map[string]*TypeDefinition, len(call.types)+len(m.TypeDefinitions))
+	for name, td := range call.types {
+		types[name] = td
+	}
+	for name, td := range m.TypeDefinitions {
+		types[name] = td
+	}
+	m.namedTypes = types
+
+	// We'll check the module's own type definitions even if no variable
+	// uses them, so that mistakes are reported early.
+	names := make([]string, 0, len(m.TypeDefinitions))
+	for name := range m.TypeDefinitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		td := m.TypeDefinitions[name]
+		if td.Expr == nil {
+			continue
+		}
+		expr, moreDiags := newNamedTypeResolver(types).resolveName(name, td.Expr.Range())
+		diags = append(diags, moreDiags...)
+		if moreDiags.HasErrors() {
+			continue
+		}
+		_, _, _, moreDiags = decodeVariableType(expr)
+		diags = append(diags, moreDiags...)
+	}
+	if diags.HasErrors() {
+		// Variables using the invalid definitions would only repeat the
+		// same errors.
+		for _, v := range m.Variables {
+			if v.typeExpr != nil {
+				v.resolveType(anyTypeExpr(v.typeExpr.Range()))
+			}
+		}
+		return diags
+	}
+
+	for _, v := range m.Variables {
+		if v.typeExpr == nil {
+			continue
+		}
+		expr, moreDiags := newNamedTypeResolver(types).Resolve(v.typeExpr)
+		diags = append(diags, moreDiags...)
+		diags = append(diags, v.resolveType(expr)...)
+	}
+
+	return diags
+}
+
+// resolveType finishes decoding a variable whose type constraint refers to
+// named types, given its type constraint with those references resolved.
+func (v *Variable) resolveType(expr hcl.Expression) hcl.Diagnostics {
+	ty, tyDefaults, parseMode, diags := decodeVariableType(expr)
+	v.ConstraintType = ty
+	v.TypeDefaults = tyDefaults
+	v.Type = ty.WithoutOptionalAttributesDeep()
+	v.ParsingMode = parseMode
+	v.typeExpr = nil
+
+	if v.Default != cty.NilVal {
+		val, defDiags := v.convertDefault(v.Default, v.DeclRange)
+		diags = append(diags, defDiags...)
+		v.Default = val
+	}
+
+	return diags
+}
+
+var typeDefinitionBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name:     "type",
+			Required: true,
+		},
+		{
+			Name: "description",
+		},
+	},
+}
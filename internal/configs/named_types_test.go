@@ -0,0 +1,213 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"path"
+	"testing"
+
+	version "github.com/hashicorp/go-version"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestNamedTypes(t *testing.T) {
+	parser := testParser(map[string]string{
+		"mod/main.tf": namedTypesTestConfig,
+	})
+	mod, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+
+	subnet := cty.ObjectWithOptionalAttrs(map[string]cty.Type{
+		"name":   cty.String,
+		"public": cty.Bool,
+	}, []string{"public"})
+
+	v := mod.Variables["subnets"]
+	if want := cty.List(subnet); !v.ConstraintType.Equals(want) {
+		t.Errorf("wrong constraint type\ngot:  %#v\nwant: %#v", v.ConstraintType, want)
+	}
+	if got, want := v.ParsingMode, VariableParseHCL; got != want {
+		t.Errorf("wrong parsing mode %#v; want %#v", got, want)
+	}
+	wantDefault := cty.ListVal([]cty.Value{
+		cty.ObjectVal(map[string]cty.Value{
+			"name":   cty.StringVal("a"),
+			"public": cty.False,
+		}),
+	})
+	if !v.Default.RawEquals(wantDefault) {
+		t.Errorf("wrong default\ngot:  %#v\nwant: %#v", v.Default, wantDefault)
+	}
+
+	v = mod.Variables["name"]
+	if !v.ConstraintType.Equals(cty.String) {
+		t.Errorf("wrong constraint type %#v; want cty.String", v.ConstraintType)
+	}
+	if got, want := v.ParsingMode, VariableParseLiteral; got != want {
+		t.Errorf("wrong parsing mode %#v; want %#v", got, want)
+	}
+}
+
+const namedTypesTestConfig = `
+type "name" {
+  type = string
+}
+
+type "subnet" {
+  type = object({
+    name   = type.name
+    public = optional(bool, false)
+  })
+}
+
+variable "subnets" {
+  type = list(type.subnet)
+  default = [
+    {
+      name = "a"
+    },
+  ]
+}
+
+variable "name" {
+  type = type.name
+}
+`
+
+func TestNamedTypes_errors(t *testing.T) {
+	tests := map[string]struct {
+		config string
+		want   []string
+	}{
+		"undeclared": {
+			`
+type "subnet" {
+  type = string
+}
+
+variable "subnets" {
+  type = list(type.subnets)
+}
+`,
+			[]string{
+				`mod/main.tf:7,15-27: Reference to undeclared type; There is no type named "subnets" declared in this module.
+
+Did you mean type.subnet?`,
+			},
+		},
+		"self-referencing": {
+			`
+type "a" {
+  type = list(type.b)
+}
+
+type "b" {
+  type = map(type.a)
+}
+`,
+			[]string{
+				`mod/main.tf:3,10-22: Self-referencing type definition; The type "a" is defined in terms of itself, which is not allowed.`,
+				`mod/main.tf:7,10-21: Self-referencing type definition; The type "b" is defined in terms of itself, which is not allowed.`,
+			},
+		},
+		"invalid default": {
+			`
+type "port" {
+  type = number
+}
+
+variable "port" {
+  type    = type.port
+  default = "http"
+}
+`,
+			[]string{
+				`mod/main.tf:6,1-16: Invalid default value for variable; This default value is not compatible with the variable's type constraint: a number is required.`,
+			},
+		},
+		"duplicate": {
+			`
+type "port" {
+  type = number
+}
+
+type "port" {
+  type = string
+}
+`,
+			[]string{
+				`mod/main.tf:6,1-12: Duplicate type definition; A type named "port" was already defined at mod/main.tf:2,1-12. Type names must be unique within a module.`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{
+				"mod/main.tf": test.config,
+			})
+			_, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
+			assertExactDiagnostics(t, diags, test.want)
+		})
+	}
+}
+
+func TestNamedTypes_localModules(t *testing.T) {
+	parser := testParser(map[string]string{
+		"root/main.tf": `
+type "subnet" {
+  type = object({
+    name = string
+  })
+}
+
+module "local" {
+  source = "./child"
+}
+
+module "remote" {
+  source = "example.com/foo/bar/baz"
+}
+`,
+		"root/child/main.tf": `
+variable "subnets" {
+  type = list(type.subnet)
+}
+`,
+		"remote/main.tf": `
+variable "subnets" {
+  type = list(type.subnet)
+}
+`,
+	})
+
+	mod, diags := parser.LoadConfigDir("root", RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+
+	cfg, diags := BuildConfig(mod, ModuleWalkerFunc(
+		func(req *ModuleRequest) (*Module, *version.Version, hcl.Diagnostics) {
+			dir := "remote"
+			if req.Name == "local" {
+				dir = path.Join("root", req.SourceAddr.String())
+			}
+			mod, diags := parser.LoadConfigDir(dir, req.Call)
+			return mod, nil, diags
+		},
+	))
+	// Only the module from the same package inherits the named types.
+	assertExactDiagnostics(t, diags, []string{
+		`remote/main.tf:3,15-26: Reference to undeclared type; There is no type named "subnet" declared in this module.`,
+	})
+
+	v := cfg.Children["local"].Module.Variables["subnets"]
+	want := cty.List(cty.Object(map[string]cty.Type{
+		"name": cty.String,
+	}))
+	if !v.ConstraintType.Equals(want) {
+		t.Errorf("wrong constraint type\ngot:  %#v\nwant: %#v", v.ConstraintType, want)
+	}
+}
@@ -7,6 +7,8 @@ package configs
 
 import (
 	"fmt"
+	"path"
+	"sort"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/ext/typeexpr"
@@ -47,7 +49,18 @@ type Variable struct {
 	Nullable    bool
 	NullableSet bool
 
+	// Deprecated, if set, is a message explaining that the variable is
+	// deprecated and what to use instead. OpenTofu reports it as a warning
+	// to any module call that sets the variable.
+	Deprecated    string
+	DeprecatedSet bool
+
 	DeclRange hcl.Range
+
+	// typeExpr is the type constraint expression of a variable whose type
+	// refers to named types, until the module's type definitions are known
+	// and resolveVariableTypes sets the type fields above from it.
+	typeExpr hcl.Expression
 }
 
 func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagnostics) {
@@ -108,12 +121,22 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 	}
 
 	if attr, exists := content.Attributes["type"]; exists {
-		ty, tyDefaults, parseMode, tyDiags := decodeVariableType(attr.Expr)
-		diags = append(diags, tyDiags...)
-		v.ConstraintType = ty
-		v.TypeDefaults = tyDefaults
-		v.Type = ty.WithoutOptionalAttributesDeep()
-		v.ParsingMode = parseMode
+		if typeExprUsesNamedTypes(attr.Expr) {
+			// We can only decode this type constraint once we know all of
+			// the module's type definitions, so NewModule will finish the
+			// job. Until then the type is unknown, as in an override file.
+			v.typeExpr = attr.Expr
+			v.Type = cty.NilType
+			v.ConstraintType = cty.NilType
+			v.ParsingMode = 0
+		} else {
+			ty, tyDefaults, parseMode, tyDiags := decodeVariableType(attr.Expr)
+			diags = append(diags, tyDiags...)
+			v.ConstraintType = ty
+			v.TypeDefaults = tyDefaults
+			v.Type = ty.WithoutOptionalAttributesDeep()
+			v.ParsingMode = parseMode
+		}
 	}
 
 	if attr, exists := content.Attributes["sensitive"]; exists {
@@ -132,6 +155,12 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 		v.Nullable = true
 	}
 
+	if attr, exists := content.Attributes["deprecated"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &v.Deprecated)
+		diags = append(diags, valDiags...)
+		v.DeprecatedSet = true
+	}
+
 	if attr, exists := content.Attributes["default"]; exists {
 		val, valDiags := attr.Expr.Value(nil)
 		diags = append(diags, valDiags...)
@@ -141,27 +170,13 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 		// Note that this depends on us having already processed any "type"
 		// attribute above.
 		// However, we can't do this if we're in an override file where
-		// the type might not be set; we'll catch that during merge.
+		// the type might not be set; we'll catch that during merge. The
+		// same goes for a type that refers to named types, which we'll
+		// catch once the type is resolved.
 		if v.ConstraintType != cty.NilType {
-			var err error
-			// If the type constraint has defaults, we must apply those
-			// defaults to the variable default value before type conversion,
-			// unless the default value is null. Null is excluded from the
-			// type default application process as a special case, to allow
-			// nullable variables to have a null default value.
-			if v.TypeDefaults != nil && !val.IsNull() {
-				val = v.TypeDefaults.Apply(val)
-			}
-			val, err = convert.Convert(val, v.ConstraintType)
-			if err != nil {
-				diags = append(diags, &hcl.Diagnostic{
-					Severity: hcl.DiagError,
-					Summary:  "Invalid default value for variable",
-					Detail:   fmt.Sprintf("This default value is not compatible with the variable's type constraint: %s.", err),
-					Subject:  attr.Expr.Range().Ptr(),
-				})
-				val = cty.DynamicVal
-			}
+			var convDiags hcl.Diagnostics
+			val, convDiags = v.convertDefault(val, attr.Expr.Range())
+			diags = append(diags, convDiags...)
 		}
 
 		if !v.Nullable && val.IsNull() {
@@ -194,6 +209,32 @@ func decodeVariableBlock(block *hcl.Block, override bool) (*Variable, hcl.Diagno
 	return v, diags
 }
 
+// convertDefault converts the given default value to the variable's type
+// constraint, applying any defaults for optional attributes first.
+func (v *Variable) convertDefault(val cty.Value, rng hcl.Range) (cty.Value, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	// If the type constraint has defaults, we must apply those
+	// defaults to the variable default value before type conversion,
+	// unless the default value is null. Null is excluded from the
+	// type default application process as a special case, to allow
+	// nullable variables to have a null default value.
+	if v.TypeDefaults != nil && !val.IsNull() {
+		val = v.TypeDefaults.Apply(val)
+	}
+	val, err := convert.Convert(val, v.ConstraintType)
+	if err != nil {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid default value for variable",
+			Detail:   fmt.Sprintf("This default value is not compatible with the variable's type constraint: %s.", err),
+			Subject:  rng.Ptr(),
+		})
+		val = cty.DynamicVal
+	}
+	return val, diags
+}
+
 func decodeVariableType(expr hcl.Expression) (cty.Type, *typeexpr.Defaults, VariableParsingMode, hcl.Diagnostics) {
 	if exprIsNativeQuotedString(expr) {
 		// If a user provides the pre-0.12 form of variable type argument where
@@ -327,30 +368,29 @@ func (m VariableParsingMode) Parse(name, value string) (cty.Value, hcl.Diagnosti
 }
 
 // decodeVariableValidationBlock is a wrapper around decodeCheckRuleBlock
-// that imposes the additional rule that the condition expression can refer
-// only to an input variable of the given name.
+// that imposes the additional rule that the condition expression must refer
+// to the input variable of the given name, and can otherwise refer only to
+// other input variables and to static data about the module.
 func decodeVariableValidationBlock(varName string, block *hcl.Block, override bool) (*CheckRule, hcl.Diagnostics) {
 	vv, diags := decodeCheckRuleBlock(block, override)
 	if vv.Condition != nil {
-		// The validation condition can only refer to the variable itself,
-		// to ensure that the variable declaration can't create additional
-		// edges in the dependency graph.
+		// The validation condition can only refer to input variables and
+		// static data, to ensure that the variable declaration can't create
+		// dependencies on any other objects in the module.
 		goodRefs := 0
 		for _, traversal := range vv.Condition.Variables() {
-			ref, moreDiags := addrs.ParseRef(traversal)
-			if !moreDiags.HasErrors() {
-				if addr, ok := ref.Subject.(addrs.InputVariable); ok {
-					if addr.Name == varName {
-						goodRefs++
-						continue // Reference is valid
-					}
-				}
+			self, ok := variableValidationRef(varName, traversal)
+			if self {
+				goodRefs++
+			}
+			if ok {
+				continue // Reference is valid
 			}
 			// If we fall out here then the reference is invalid.
 			diags = diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Invalid reference in variable validation",
-				Detail:   fmt.Sprintf("The condition for variable %q can only refer to the variable itself, using var.%s.", varName, varName),
+				Detail:   fmt.Sprintf("The condition for variable %q can only refer to input variables, such as var.%s, and to path.module, path.root, path.cwd, or terraform.workspace.", varName, varName),
 				Subject:  traversal.SourceRange().Ptr(),
 			})
 		}
@@ -368,22 +408,15 @@ func decodeVariableValidationBlock(varName string, block *hcl.Block, override bo
 		// The same applies to the validation error message, except that
 		// references are not required. A string literal is a valid error
 		// message.
-		goodRefs := 0
 		for _, traversal := range vv.ErrorMessage.Variables() {
-			ref, moreDiags := addrs.ParseRef(traversal)
-			if !moreDiags.HasErrors() {
-				if addr, ok := ref.Subject.(addrs.InputVariable); ok {
-					if addr.Name == varName {
-						goodRefs++
-						continue // Reference is valid
-					}
-				}
+			if _, ok := variableValidationRef(varName, traversal); ok {
+				continue // Reference is valid
 			}
 			// If we fall out here then the reference is invalid.
 			diags = diags.Append(&hcl.Diagnostic{
 				Severity: hcl.DiagError,
 				Summary:  "Invalid reference in variable validation",
-				Detail:   fmt.Sprintf("The error message for variable %q can only refer to the variable itself, using var.%s.", varName, varName),
+				Detail:   fmt.Sprintf("The error message for variable %q can only refer to input variables, such as var.%s, and to path.module, path.root, path.cwd, or terraform.workspace.", varName, varName),
 				Subject:  traversal.SourceRange().Ptr(),
 			})
 		}
@@ -392,6 +425,67 @@ func decodeVariableValidationBlock(varName string, block *hcl.Block, override bo
 	return vv, diags
 }
 
+// variableValidationRef checks a reference from a validation rule of the
+// variable with the given name. It returns whether the reference is to that
+// variable itself, and whether it's valid in a validation rule at all.
+func variableValidationRef(varName string, traversal hcl.Traversal) (self, ok bool) {
+	ref, diags := addrs.ParseRef(traversal)
+	if diags.HasErrors() {
+		return false, false
+	}
+	switch addr := ref.Subject.(type) {
+	case addrs.InputVariable:
+		return addr.Name == varName, true
+	case addrs.PathAttr:
+		return false, true
+	case addrs.TerraformAttr:
+		return false, addr.Name == "workspace" || addr.Name == "workspace_meta"
+	default:
+		return false, false
+	}
+}
+
+// checkVariableValidationRefs checks that the validation rules of the
+// variables in the given module refer only to variables that the module
+// declares.
+func checkVariableValidationRefs(mod *Module) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	names := make([]string, 0, len(mod.Variables))
+	for name := range mod.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, vv := range mod.Variables[name].Validations {
+			for _, expr := range []hcl.Expression{vv.Condition, vv.ErrorMessage} {
+				if expr == nil {
+					continue
+				}
+				for _, traversal := range expr.Variables() {
+					ref, refDiags := addrs.ParseRef(traversal)
+					if refDiags.HasErrors() {
+						continue
+					}
+					addr, ok := ref.Subject.(addrs.InputVariable)
+					if !ok || mod.Variables[addr.Name] != nil {
+						continue
+					}
+					diags = diags.Append(&hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Reference to undeclared input variable",
+						Detail:   fmt.Sprintf("The validation rules for variable %q refer to var.%s, which is not declared in this module.", name, addr.Name),
+						Subject:  traversal.SourceRange().Ptr(),
+					})
+				}
+			}
+		}
+	}
+
+	return diags
+}
+
 // Output represents an "output" block in a module or file.
 type Output struct {
 	Name        string
@@ -402,8 +496,21 @@ type Output struct {
 
 	Preconditions []*CheckRule
 
+	// Deprecated, if set, is a message explaining that the output value is
+	// deprecated and what to use instead. OpenTofu reports it as a warning
+	// wherever the calling module refers to the output value.
+	Deprecated string
+
+	// SharedWith lists the consumers that can read the output value with the
+	// terraform_shared_outputs data source, as names or path.Match patterns
+	// of names, such as "app-*". It is only used for the root module, whose
+	// output values are persisted in the state.
+	SharedWith []string
+
 	DescriptionSet bool
 	SensitiveSet   bool
+	DeprecatedSet  bool
+	SharedWithSet  bool
 
 	DeclRange hcl.Range
 
@@ -457,6 +564,28 @@ func decodeOutputBlock(block *hcl.Block, override bool) (*Output, hcl.Diagnostic
 		o.SensitiveSet = true
 	}
 
+	if attr, exists := content.Attributes["deprecated"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.Deprecated)
+		diags = append(diags, valDiags...)
+		o.DeprecatedSet = true
+	}
+
+	if attr, exists := content.Attributes["shared_with"]; exists {
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &o.SharedWith)
+		diags = append(diags, valDiags...)
+		o.SharedWithSet = true
+		for _, consumer := range o.SharedWith {
+			if _, err := path.Match(consumer, ""); err != nil || consumer == "" {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid shared_with consumer",
+					Detail:   fmt.Sprintf("The consumer %q is not valid. Each consumer must be a name, or a pattern of names such as \"app-*\".", consumer),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			}
+		}
+	}
+
 	if attr, exists := content.Attributes["depends_on"]; exists {
 		deps, depsDiags := decodeDependsOn(attr)
 		diags = append(diags, depsDiags...)
@@ -551,6 +680,9 @@ var variableBlockSchema = &hcl.BodySchema{
 		{
 			Name: "nullable",
 		},
+		{
+			Name: "deprecated",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -574,6 +706,12 @@ var outputBlockSchema = &hcl.BodySchema{
 		{
 			Name: "sensitive",
 		},
+		{
+			Name: "deprecated",
+		},
+		{
+			Name: "shared_with",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "precondition"},
@@ -8,9 +8,12 @@ package configs
 import (
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	hcljson "github.com/hashicorp/hcl/v2/json"
 	"github.com/spf13/afero"
 )
 
@@ -19,8 +22,15 @@ import (
 //
 // It retains a cache of all files that are loaded so that they can be used
 // to create source code snippets in diagnostics, etc.
+//
+// Files can be loaded from several goroutines at once, but the settings
+// methods such as AllowLanguageExperiments must not be called concurrently
+// with loading.
 type Parser struct {
 	fs afero.Afero
+
+	// mu guards p, whose cache of files isn't safe for concurrent use.
+	mu sync.Mutex
 	p  *hclparse.Parser
 
 	// allowExperiments controls whether we will allow modules to opt in to
@@ -29,6 +39,12 @@ type Parser struct {
 	// for itself whether to enable it so that tests can cover both the
 	// allowed and not-allowed situations.
 	allowExperiments bool
+
+	// overrideSet is the name of the set of environment-scoped override
+	// files, named like "example_override.<name>.tf", that LoadConfigDir
+	// (and similar) will load after the other override files. Such files
+	// are ignored when they belong to any other set.
+	overrideSet string
 }
 
 // NewParser creates and returns a new Parser that reads files from the given
@@ -75,9 +91,9 @@ func (p *Parser) LoadHCLFile(path string) (hcl.Body, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 	switch {
 	case strings.HasSuffix(path, ".json"):
-		file, diags = p.p.ParseJSON(src, path)
+		file, diags = p.parse(src, path, hcljson.Parse)
 	default:
-		file, diags = p.p.ParseHCL(src, path)
+		file, diags = p.parse(src, path, parseHCL)
 	}
 
 	// If the returned file or body is nil, then we'll return a non-nil empty
@@ -89,10 +105,42 @@ func (p *Parser) LoadHCLFile(path string) (hcl.Body, hcl.Diagnostics) {
 	return file.Body, diags
 }
 
+// parse returns the cached file for the given path if there is one, or
+// otherwise parses the given source with the given function and adds the
+// result to the cache, like the methods of hclparse.Parser.
+//
+// The lock is not held while parsing, so that files can be parsed in
+// parallel. If two goroutines parse the same file at once then the first
+// result to be cached is kept.
+func (p *Parser) parse(src []byte, path string, parseFn func([]byte, string) (*hcl.File, hcl.Diagnostics)) (*hcl.File, hcl.Diagnostics) {
+	p.mu.Lock()
+	existing := p.p.Files()[path]
+	p.mu.Unlock()
+	if existing != nil {
+		return existing, nil
+	}
+
+	file, diags := parseFn(src, path)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if existing := p.p.Files()[path]; existing != nil {
+		return existing, nil
+	}
+	p.p.AddFile(path, file)
+	return file, diags
+}
+
+func parseHCL(src []byte, path string) (*hcl.File, hcl.Diagnostics) {
+	return hclsyntax.ParseConfig(src, path, hcl.InitialPos)
+}
+
 // Sources returns a map of the cached source buffers for all files that
 // have been loaded through this parser, with source filenames (as requested
 // when each file was opened) as the keys.
 func (p *Parser) Sources() map[string][]byte {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	return p.p.Sources()
 }
 
@@ -105,6 +153,8 @@ func (p *Parser) Sources() map[string][]byte {
 func (p *Parser) ForceFileSource(filename string, src []byte) {
 	// We'll make a synthetic hcl.File here just so we can reuse the
 	// existing cache.
+	p.mu.Lock()
+	defer p.mu.Unlock()
 	p.p.AddFile(filename, &hcl.File{
 		Body:  hcl.EmptyBody(),
 		Bytes: src,
@@ -123,3 +173,13 @@ func (p *Parser) ForceFileSource(filename string, src []byte) {
 func (p *Parser) AllowLanguageExperiments(allowed bool) {
 	p.allowExperiments = allowed
 }
+
+// SetOverrideSet selects the set of environment-scoped override files that
+// subsequent LoadConfigDir (and similar) calls will load, such as "prod" to
+// load files named like "example_override.prod.tf".
+//
+// If this method is never called for a particular parser, or is called with
+// an empty string, all environment-scoped override files are ignored.
+func (p *Parser) SetOverrideSet(name string) {
+	p.overrideSet = name
+}
@@ -40,6 +40,9 @@ func (p *Parser) LoadConfigFileOverride(path string) (*File, hcl.Diagnostics) {
 //
 // It references the same LoadHCLFile as LoadConfigFile, so inherits the same
 // syntax selection behaviours.
+//
+// The helpers that the test file imports are not resolved, since they are
+// relative to the test directory. LoadConfigDirWithTests resolves them.
 func (p *Parser) LoadTestFile(path string) (*TestFile, hcl.Diagnostics) {
 	body, diags := p.LoadHCLFile(path)
 	if body == nil {
@@ -83,6 +86,14 @@ func (p *Parser) loadConfigFile(path string, override bool) (*File, hcl.Diagnost
 			// attributes here because sniffCoreVersionRequirements and
 			// sniffActiveExperiments already dealt with those above.
 
+			if attr, exists := content.Attributes["strict"]; exists {
+				strict, strictDiags := decodeStrictAttr(attr)
+				diags = append(diags, strictDiags...)
+				if strict != nil {
+					file.StrictSettings = append(file.StrictSettings, strict)
+				}
+			}
+
 			for _, innerBlock := range content.Blocks {
 				switch innerBlock.Type {
 
@@ -150,6 +161,13 @@ func (p *Parser) loadConfigFile(path string, override bool) (*File, hcl.Diagnost
 				file.Variables = append(file.Variables, cfg)
 			}
 
+		case "type":
+			cfg, cfgDiags := decodeTypeDefinitionBlock(block)
+			diags = append(diags, cfgDiags...)
+			if cfg != nil {
+				file.TypeDefinitions = append(file.TypeDefinitions, cfg)
+			}
+
 		case "locals":
 			defs, defsDiags := decodeLocalsBlock(block)
 			diags = append(diags, defsDiags...)
@@ -279,6 +297,10 @@ var configFileSchema = &hcl.BodySchema{
 			Type:       "variable",
 			LabelNames: []string{"name"},
 		},
+		{
+			Type:       "type",
+			LabelNames: []string{"name"},
+		},
 		{
 			Type: "locals",
 		},
@@ -321,6 +343,7 @@ var terraformBlockSchema = &hcl.BodySchema{
 		{Name: "required_version"},
 		{Name: "experiments"},
 		{Name: "language"},
+		{Name: "strict"},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -80,7 +80,7 @@ func (p *Parser) LoadConfigDirWithTests(path string, testDirectory string, call
 	diags = append(diags, fDiags...)
 	override, fDiags := p.loadFiles(overridePaths, true)
 	diags = append(diags, fDiags...)
-	tests, fDiags := p.loadTestFiles(path, testPaths)
+	tests, fDiags := p.loadTestFiles(path, testDirectory, testPaths)
 	diags = append(diags, fDiags...)
 
 	mod, modDiags := NewModuleWithTests(primary, override, tests, call, path)
@@ -94,14 +94,14 @@ func (p *Parser) LoadConfigDirWithTests(path string, testDirectory string, call
 //
 // If the given directory does not exist or cannot be read, error diagnostics
 // are returned. If errors are returned, the resulting lists may be incomplete.
-func (p Parser) ConfigDirFiles(dir string) (primary, override []string, diags hcl.Diagnostics) {
+func (p *Parser) ConfigDirFiles(dir string) (primary, override []string, diags hcl.Diagnostics) {
 	primary, override, _, diags = p.dirFiles(dir, "")
 	return primary, override, diags
 }
 
 // ConfigDirFilesWithTests matches ConfigDirFiles except it also returns the
 // paths to any test files within the module.
-func (p Parser) ConfigDirFilesWithTests(dir string, testDirectory string) (primary, override, tests []string, diags hcl.Diagnostics) {
+func (p *Parser) ConfigDirFilesWithTests(dir string, testDirectory string) (primary, override, tests []string, diags hcl.Diagnostics) {
 	return p.dirFiles(dir, testDirectory)
 }
 
@@ -206,6 +206,7 @@ func (p *Parser) dirFiles(dir string, testsDir string) (primary, override, tests
 		return
 	}
 
+	var setOverride []string
 	for _, info := range infos {
 		if info.IsDir() {
 			// We only care about tofu configuration files.
@@ -226,19 +227,79 @@ func (p *Parser) dirFiles(dir string, testsDir string) (primary, override, tests
 		}
 
 		baseName := name[:len(name)-len(ext)] // strip extension
-		isOverride := baseName == "override" || strings.HasSuffix(baseName, "_override")
-
 		fullPath := filepath.Join(dir, name)
-		if isOverride {
+
+		if set, ok := overrideSetName(baseName); ok {
+			if set != p.overrideSet {
+				log.Printf("[TRACE] dirFiles: ignoring %s, which belongs to override set %q", fullPath, set)
+				continue
+			}
+			setOverride = append(setOverride, fullPath)
+			continue
+		}
+
+		if isOverrideBaseName(baseName) {
 			override = append(override, fullPath)
 		} else {
 			primary = append(primary, fullPath)
 		}
 	}
 
+	// The files from the selected override set are merged after all of the
+	// other override files, so that they take precedence.
+	override = append(override, setOverride...)
+
 	return filterTfPathsWithTofuAlternatives(primary), filterTfPathsWithTofuAlternatives(override), filterTfPathsWithTofuAlternatives(tests), diags
 }
 
+func isOverrideBaseName(baseName string) bool {
+	return baseName == "override" || strings.HasSuffix(baseName, "_override")
+}
+
+// OverrideSetName returns the name of the override set that the
+// environment-scoped override file with the given filename belongs to, or
+// false if the file isn't an environment-scoped override file.
+func OverrideSetName(filename string) (string, bool) {
+	ext := fileExt(filename)
+	if ext == "" || isTestFileExt(ext) {
+		return "", false
+	}
+	return overrideSetName(filepath.Base(filename[:len(filename)-len(ext)]))
+}
+
+// overrideSetName returns the name of the override set that an
+// environment-scoped override file with the given base name belongs to, such
+// as "prod" for "example_override.prod", or false if the file isn't an
+// environment-scoped override file.
+func overrideSetName(baseName string) (string, bool) {
+	i := strings.LastIndex(baseName, ".")
+	if i < 0 {
+		return "", false
+	}
+	stem, set := baseName[:i], baseName[i+1:]
+	if !isOverrideBaseName(stem) || !ValidOverrideSetName(set) {
+		return "", false
+	}
+	return set, true
+}
+
+// ValidOverrideSetName returns true if the given name can be used as the name
+// of a set of environment-scoped override files. Valid names consist of one or
+// more letters, digits, underscores, and dashes.
+func ValidOverrideSetName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 // filterTfPathsWithTofuAlternatives filters out .tf files if they have an
 // alternative .tofu file with the same name.
 // For example, if there are both 'resources.tf.json' and
@@ -276,13 +337,19 @@ func filterTfPathsWithTofuAlternatives(paths []string) []string {
 	return relevantPaths
 }
 
-func (p *Parser) loadTestFiles(basePath string, paths []string) (map[string]*TestFile, hcl.Diagnostics) {
+func (p *Parser) loadTestFiles(basePath string, testDirectory string, paths []string) (map[string]*TestFile, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
+	helpers := make(map[string]*TestHelper)
 	tfs := make(map[string]*TestFile)
 	for _, path := range paths {
 		tf, fDiags := p.LoadTestFile(path)
 		diags = append(diags, fDiags...)
+		if tf != nil && len(tf.Imports) > 0 {
+			imported, importDiags := p.loadTestHelpers(testHelperDirectory(basePath, testDirectory), tf.Imports, helpers)
+			diags = append(diags, importDiags...)
+			diags = append(diags, tf.resolveHelpers(imported)...)
+		}
 		if tf != nil {
 			// We index test files relative to the module they are testing, so
 			// the key is the relative path between basePath and path.
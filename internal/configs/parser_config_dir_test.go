@@ -11,8 +11,10 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/spf13/afero"
 	"github.com/zclconf/go-cty/cty"
 )
 
@@ -320,6 +322,51 @@ func TestParserLoadConfigDirWithTests_TofuFiles(t *testing.T) {
 	}
 }
 
+func TestParserLoadConfigDir_overrideSet(t *testing.T) {
+	files := map[string]string{
+		"mod/main.tf": `
+variable "size" {
+  default = "small"
+}
+`,
+		"mod/main_override.tf": `
+variable "size" {
+  default = "medium"
+}
+`,
+		"mod/main_override.prod.tf": `
+variable "size" {
+  default = "large"
+}
+`,
+		"mod/override.staging.tf": `
+variable "size" {
+  default = "tiny"
+}
+`,
+	}
+
+	tests := map[string]string{
+		"":        "medium",
+		"prod":    "large",
+		"staging": "tiny",
+		"dev":     "medium",
+	}
+	for set, want := range tests {
+		t.Run(set, func(t *testing.T) {
+			parser := testParser(files)
+			parser.SetOverrideSet(set)
+			mod, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
+			assertNoDiagnostics(t, diags)
+
+			got := mod.Variables["size"].Default
+			if !got.RawEquals(cty.StringVal(want)) {
+				t.Errorf("wrong default\ngot:  %#v\nwant: %#v", got, cty.StringVal(want))
+			}
+		})
+	}
+}
+
 func TestIsEmptyDir(t *testing.T) {
 	val, err := IsEmptyDir(filepath.Join("testdata", "valid-files"))
 	if err != nil {
@@ -349,3 +396,93 @@ func TestIsEmptyDir_noConfigs(t *testing.T) {
 		t.Fatal("should be empty")
 	}
 }
+
+func TestParserLoadConfigDirWithTests_helpers(t *testing.T) {
+	parser := NewParser(nil)
+	mod, diags := parser.LoadConfigDirWithTests("testdata/valid-modules/with-tests-helpers", DefaultTestDirectory, RootModuleCallForTesting())
+	if len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %s", diags)
+	}
+
+	// The helper in the lib directory isn't a test file itself.
+	if len(mod.Tests) != 1 {
+		t.Fatalf("incorrect number of test files found: %d", len(mod.Tests))
+	}
+	file := mod.Tests[filepath.Join("tests", "main.tftest.hcl")]
+	if file == nil {
+		t.Fatalf("test file not found")
+	}
+
+	if _, ok := file.Variables["input"]; !ok {
+		t.Errorf("expected the variables of the helper to be imported")
+	}
+	if _, ok := file.Providers["foo.helper"]; !ok {
+		t.Errorf("expected the providers of the helper to be imported")
+	}
+	if len(file.OverrideResources) != 1 {
+		t.Errorf("expected the overrides of the helper to be imported, got %d", len(file.OverrideResources))
+	}
+
+	if len(file.Runs) != 2 {
+		t.Fatalf("incorrect number of run blocks: %d", len(file.Runs))
+	}
+
+	value := func(expr hcl.Expression) string {
+		t.Helper()
+		v, diags := expr.Value(nil)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return v.AsString()
+	}
+
+	// The first run block gets the assertion of the before_each block, and
+	// the two included assertions.
+	defaults := file.Runs[0]
+	if got := len(defaults.CheckRules); got != 3 {
+		t.Errorf("wrong number of assertions for %s: %d", defaults.Name, got)
+	}
+	if got := value(defaults.Variables["input"]); got != "before_each" {
+		t.Errorf("wrong input for %s: %s", defaults.Name, got)
+	}
+
+	// The second run block keeps its own variable.
+	own := file.Runs[1]
+	if got := len(own.CheckRules); got != 2 {
+		t.Errorf("wrong number of assertions for %s: %d", own.Name, got)
+	}
+	if got := value(own.Variables["input"]); got != "own" {
+		t.Errorf("wrong input for %s: %s", own.Name, got)
+	}
+}
+
+func TestParserLoadConfigDirWithTests_missingHelper(t *testing.T) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	if err := fs.WriteFile("main.tf", []byte(`resource "foo_resource" "a" {}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.WriteFile("main.tftest.hcl", []byte(`
+imports = ["missing", "../outside"]
+
+run "test" {
+  assertions = ["unknown"]
+}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, diags := NewParser(fs).LoadConfigDirWithTests(".", DefaultTestDirectory, RootModuleCallForTesting())
+
+	var got []string
+	for _, diag := range diags {
+		got = append(got, diag.Summary)
+	}
+	want := []string{
+		"Test helper not found",
+		"Invalid test helper import",
+		"Unknown assertions",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong diagnostics\n%s", diff)
+	}
+}
@@ -104,6 +104,16 @@ func TestParserLoadConfigFileFailureMessages(t *testing.T) {
 			hcl.DiagError,
 			"Invalid data resource lifecycle argument",
 		},
+		{
+			"invalid-files/resource-lifecycle-badtimeout.tf",
+			hcl.DiagError,
+			"Invalid operation_timeout argument",
+		},
+		{
+			"invalid-files/resource-lifecycle-bad-two-phase-timeout.tf",
+			hcl.DiagError,
+			"Invalid two_phase_replace_timeout argument",
+		},
 		{
 			"invalid-files/variable-type-unknown.tf",
 			hcl.DiagError,
@@ -114,6 +124,11 @@ func TestParserLoadConfigFileFailureMessages(t *testing.T) {
 			hcl.DiagError,
 			"Unsupported argument",
 		},
+		{
+			"invalid-files/output-shared-with.tf",
+			hcl.DiagError,
+			"Invalid shared_with consumer",
+		},
 		{
 			"invalid-files/unexpected-block.tf",
 			hcl.DiagError,
@@ -165,6 +180,51 @@ func TestParserLoadConfigFileFailureMessages(t *testing.T) {
 	}
 }
 
+// TestParserLoadConfigFileUnsupportedBlockType verifies that a block of the
+// wrong type suggests a similar block type that is expected in its place.
+func TestParserLoadConfigFileUnsupportedBlockType(t *testing.T) {
+	tests := map[string]struct {
+		Src  string
+		Want string
+	}{
+		"top-level": {
+			`varyable "whoops" {}`,
+			`Did you mean "variable"?`,
+		},
+		"nested in variable": {
+			`variable "foo" {
+  validaton {}
+}`,
+			`Did you mean "validation"?`,
+		},
+		"nested in terraform": {
+			`terraform {
+  required_provders {}
+}`,
+			`Did you mean "required_providers"?`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{
+				"main.tf": test.Src,
+			})
+
+			_, diags := parser.LoadConfigFile("main.tf")
+			if len(diags) != 1 {
+				t.Fatalf("wrong number of diagnostics %d; want 1\n%s", len(diags), diags.Error())
+			}
+			if got, want := diags[0].Summary, "Unsupported block type"; got != want {
+				t.Errorf("wrong diagnostic summary\ngot:  %s\nwant: %s", got, want)
+			}
+			if !strings.Contains(diags[0].Detail, test.Want) {
+				t.Errorf("diagnostic detail does not suggest a block type\ngot:  %s\nwant: %s", diags[0].Detail, test.Want)
+			}
+		})
+	}
+}
+
 // TestParseLoadConfigFileWarning is a test that verifies files from
 // testdata/warning-files produce particular warnings.
 //
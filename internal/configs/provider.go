@@ -7,12 +7,16 @@ package configs
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/instances"
+	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
@@ -29,6 +33,17 @@ type Provider struct {
 
 	Config hcl.Body
 
+	// ForEach is the for_each expression of a provider configuration with
+	// multiple instances, or nil for a provider configuration with only a
+	// single instance. It is evaluated statically when the module is loaded,
+	// producing Instances.
+	ForEach hcl.Expression
+
+	// Instances has an element for each instance of a provider configuration
+	// with for_each, giving the values of each.key and each.value for that
+	// instance. It is nil for a provider configuration without for_each.
+	Instances map[addrs.InstanceKey]instances.RepetitionData
+
 	DeclRange hcl.Range
 
 	// TODO: this may not be set in some cases, so it is not yet suitable for
@@ -84,14 +99,27 @@ func decodeProviderBlock(block *hcl.Block) (*Provider, hcl.Diagnostics) {
 			Summary:  "Version constraints inside provider configuration blocks are deprecated",
 			Detail:   "OpenTofu 0.13 and earlier allowed provider version constraints inside the provider configuration block, but that is now deprecated and will be removed in a future version of OpenTofu. To silence this warning, move the provider version constraint into the required_providers block.",
 			Subject:  attr.Expr.Range().Ptr(),
+			Extra:    tfdiags.WarningDeprecation,
 		})
 		var versionDiags hcl.Diagnostics
 		provider.Version, versionDiags = decodeVersionConstraint(attr)
 		diags = append(diags, versionDiags...)
 	}
 
+	if attr, exists := content.Attributes["for_each"]; exists {
+		provider.ForEach = attr.Expr
+		if provider.Alias == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing alias for provider configuration with for_each",
+				Detail:   "A provider configuration with for_each must have an alias, so that resources can refer to its instances using the alias and an instance key.",
+				Subject:  &attr.NameRange,
+			})
+		}
+	}
+
 	// Reserved attribute names
-	for _, name := range []string{"count", "depends_on", "for_each", "source"} {
+	for _, name := range []string{"count", "depends_on", "source"} {
 		if attr, exists := content.Attributes[name]; exists {
 			diags = append(diags, &hcl.Diagnostic{
 				Severity: hcl.DiagError,
@@ -149,6 +177,87 @@ func (p *Provider) Addr() addrs.LocalProviderConfig {
 	}
 }
 
+// InstanceKeys returns the instance keys of the receiving provider
+// configuration in lexical order, or a single addrs.NoKey if it has no
+// for_each argument.
+func (p *Provider) InstanceKeys() []addrs.InstanceKey {
+	if p.ForEach == nil {
+		return []addrs.InstanceKey{addrs.NoKey}
+	}
+	keys := make([]addrs.InstanceKey, 0, len(p.Instances))
+	for key := range p.Instances {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return addrs.InstanceKeyLess(keys[i], keys[j])
+	})
+	return keys
+}
+
+// decodeStaticFields evaluates the for_each argument of the provider
+// configuration, if any, which must be known when the module is loaded.
+func (p *Provider) decodeStaticFields(eval *StaticEvaluator) hcl.Diagnostics {
+	if p.ForEach == nil {
+		return nil
+	}
+
+	val, diags := eval.Evaluate(p.ForEach, StaticIdentifier{
+		Module:    eval.call.addr,
+		Subject:   fmt.Sprintf("provider.%s.for_each", p.moduleUniqueKey()),
+		DeclRange: p.ForEach.Range(),
+	})
+	if diags.HasErrors() {
+		return diags
+	}
+
+	invalid := func(detail string) hcl.Diagnostics {
+		return append(diags, &hcl.Diagnostic{
+			Severity:   hcl.DiagError,
+			Summary:    "Invalid for_each argument",
+			Detail:     detail,
+			Subject:    p.ForEach.Range().Ptr(),
+			Expression: p.ForEach,
+		})
+	}
+	switch {
+	case val.IsNull():
+		return invalid("The given \"for_each\" argument value is null. A map, or set of strings, is allowed.")
+	case !val.IsWhollyKnown():
+		return invalid("The \"for_each\" argument of a provider configuration must be known when OpenTofu loads the configuration, so it can refer only to input variables, local values, and literal values.")
+	case marks.Contains(val, marks.Ephemeral):
+		return invalid("Ephemeral values, such as the results of key generation functions, cannot be used as for_each arguments, because OpenTofu saves the provider instance keys in the plan and state.")
+	case marks.Contains(val, marks.Sensitive):
+		return invalid("Sensitive values, or values derived from sensitive values, cannot be used as for_each arguments, because they would be disclosed in the provider instance keys.")
+	}
+
+	ty := val.Type()
+	p.Instances = make(map[addrs.InstanceKey]instances.RepetitionData)
+	switch {
+	case ty.IsMapType() || ty.IsObjectType():
+		for it := val.ElementIterator(); it.Next(); {
+			k, v := it.Element()
+			p.Instances[addrs.StringKey(k.AsString())] = instances.RepetitionData{
+				EachKey:   k,
+				EachValue: v,
+			}
+		}
+	case ty.IsSetType() && ty.ElementType() == cty.String:
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			if v.IsNull() {
+				return invalid("The given \"for_each\" argument value contains a null string.")
+			}
+			p.Instances[addrs.StringKey(v.AsString())] = instances.RepetitionData{
+				EachKey:   v,
+				EachValue: v,
+			}
+		}
+	default:
+		return invalid(fmt.Sprintf("The \"for_each\" argument must be a map, or set of strings, and you have provided a value of type %s.", ty.FriendlyName()))
+	}
+	return diags
+}
+
 func (p *Provider) moduleUniqueKey() string {
 	if p.Alias != "" {
 		return fmt.Sprintf("%s.%s", p.Name, p.Alias)
@@ -156,6 +265,83 @@ func (p *Provider) moduleUniqueKey() string {
 	return p.Name
 }
 
+// checkProviderInstanceRefs checks that the references to provider
+// configurations with for_each in the given module select one of their
+// instances, and that only those references have instance keys.
+func checkProviderInstanceRefs(mod *Module) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	forEach := func(ref *ProviderConfigRef) *Provider {
+		pc := mod.ProviderConfigs[providerName(ref.Name, ref.Alias)]
+		if pc == nil || pc.ForEach == nil {
+			return nil
+		}
+		return pc
+	}
+
+	var resources []*Resource
+	for _, r := range mod.ManagedResources {
+		resources = append(resources, r)
+	}
+	for _, r := range mod.DataResources {
+		resources = append(resources, r)
+	}
+	for _, c := range mod.Checks {
+		if c.DataResource != nil {
+			resources = append(resources, c.DataResource)
+		}
+	}
+	for _, r := range resources {
+		ref := r.ProviderConfigRef
+		if ref == nil {
+			continue
+		}
+		pc := forEach(ref)
+		switch {
+		case pc != nil && ref.KeyExpression == nil:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing provider instance key",
+				Detail:   fmt.Sprintf("The provider configuration %s has for_each, so the provider argument must select one of its instances, such as %s[each.key].", ref, ref),
+				Subject:  ref.AliasRange,
+			})
+		case pc == nil && ref.KeyExpression != nil:
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider instance key",
+				Detail:   fmt.Sprintf("The provider configuration %s does not have for_each in this module, so its instances cannot be selected by key.", ref),
+				Subject:  ref.KeyExpression.Range().Ptr(),
+			})
+		}
+	}
+
+	for _, mc := range mod.ModuleCalls {
+		for _, passed := range mc.Providers {
+			if passed.InChild.KeyExpression != nil || passed.InParent.KeyExpression != nil || forEach(passed.InParent) != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid provider configuration for module",
+					Detail:   "Provider configurations with for_each, and their instances, cannot be passed to child modules.",
+					Subject:  passed.InParent.NameRange.Ptr(),
+				})
+			}
+		}
+	}
+
+	for _, imp := range mod.Import {
+		if ref := imp.ProviderConfigRef; ref != nil && (ref.KeyExpression != nil || forEach(ref) != nil) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid import provider argument",
+				Detail:   "Provider configurations with for_each, and their instances, cannot be used in import blocks.",
+				Subject:  imp.ProviderDeclRange.Ptr(),
+			})
+		}
+	}
+
+	return diags
+}
+
 // ParseProviderConfigCompact parses the given absolute traversal as a relative
 // provider address in compact form. The following are examples of traversals
 // that can be successfully parsed as compact relative provider configuration
@@ -243,10 +429,13 @@ var providerBlockSchema = &hcl.BodySchema{
 			Name: "version",
 		},
 
+		{
+			Name: "for_each",
+		},
+
 		// Attribute names reserved for future expansion.
 		{Name: "count"},
 		{Name: "depends_on"},
-		{Name: "for_each"},
 		{Name: "source"},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
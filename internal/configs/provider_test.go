@@ -6,12 +6,17 @@
 package configs
 
 import (
+	"fmt"
 	"os"
+	"sort"
 	"testing"
 
 	"github.com/go-test/deep"
+	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 )
 
@@ -30,10 +35,9 @@ func TestProviderReservedNames(t *testing.T) {
 		`config.tf:4,13-20: Version constraints inside provider configuration blocks are deprecated; OpenTofu 0.13 and earlier allowed provider version constraints inside the provider configuration block, but that is now deprecated and will be removed in a future version of OpenTofu. To silence this warning, move the provider version constraint into the required_providers block.`,
 		`config.tf:10,3-8: Reserved argument name in provider block; The provider argument name "count" is reserved for use by OpenTofu in a future version.`,
 		`config.tf:11,3-13: Reserved argument name in provider block; The provider argument name "depends_on" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:12,3-11: Reserved argument name in provider block; The provider argument name "for_each" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:14,3-12: Reserved block type name in provider block; The block type name "lifecycle" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:15,3-9: Reserved block type name in provider block; The block type name "locals" is reserved for use by OpenTofu in a future version.`,
-		`config.tf:13,3-9: Reserved argument name in provider block; The provider argument name "source" is reserved for use by OpenTofu in a future version.`,
+		`config.tf:13,3-12: Reserved block type name in provider block; The block type name "lifecycle" is reserved for use by OpenTofu in a future version.`,
+		`config.tf:14,3-9: Reserved block type name in provider block; The block type name "locals" is reserved for use by OpenTofu in a future version.`,
+		`config.tf:12,3-9: Reserved argument name in provider block; The provider argument name "source" is reserved for use by OpenTofu in a future version.`,
 	})
 }
 
@@ -153,3 +157,57 @@ func TestParseProviderConfigCompactStr(t *testing.T) {
 		})
 	}
 }
+
+func TestProviderForEach(t *testing.T) {
+	mod, diags := testModuleFromDir("testdata/valid-modules/provider-for-each")
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	pc := mod.ProviderConfigs["aws.regions"]
+	got := pc.InstanceKeys()
+	want := []addrs.InstanceKey{addrs.StringKey("east"), addrs.StringKey("west")}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong instance keys\n%s", diff)
+	}
+	if got, want := pc.Instances[addrs.StringKey("west")].EachValue, cty.StringVal("us-west-2"); !got.RawEquals(want) {
+		t.Errorf("wrong each.value for west\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	if ref := mod.ManagedResources["aws_instance.web"].ProviderConfigRef; ref.Alias != "regions" || ref.KeyExpression == nil {
+		t.Errorf("wrong provider reference for aws_instance.web: %#v", ref)
+	}
+	ref := mod.DataResources["data.aws_ami.east"].ProviderConfigRef
+	key, _ := ref.KeyExpression.Value(nil)
+	if !key.RawEquals(cty.StringVal("east")) {
+		t.Errorf("wrong instance key for data.aws_ami.east: %#v", key)
+	}
+}
+
+func TestProviderForEach_invalid(t *testing.T) {
+	src, err := os.ReadFile("testdata/invalid-files/provider-for-each.tf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := testParser(map[string]string{
+		"mod/config.tf": string(src),
+	})
+	_, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
+
+	var got []string
+	for _, diag := range diags {
+		got = append(got, fmt.Sprintf("%d: %s", diag.Subject.Start.Line, diag.Summary))
+	}
+	sort.Strings(got)
+	want := []string{
+		"12: Invalid for_each argument",
+		"16: Missing provider instance key",
+		"20: Invalid provider instance key",
+		"26: Invalid provider configuration for module",
+		"2: Missing alias for provider configuration with for_each",
+		"32: Invalid for_each argument",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong diagnostics\n%s", diff)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/hashicorp/hcl/v2"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
 )
 
 // validateProviderConfigsForTests performs the same role as
@@ -37,7 +38,7 @@ import (
 func validateProviderConfigsForTests(cfg *Config) (diags hcl.Diagnostics) {
 
 	for name, test := range cfg.Module.Tests {
-		for _, run := range test.Runs {
+		for _, run := range test.AllRuns() {
 
 			if run.ConfigUnderTest == nil {
 				// Then we're calling out to the main configuration under test.
@@ -502,6 +503,7 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 						parentModuleText, name, defAddr.ForDisplay(),
 					),
 					Subject: &passed.InParent.NameRange,
+					Extra:   tfdiags.WarningImplicitProviderInheritance,
 				})
 				continue
 			}
@@ -520,6 +522,7 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 						name, parentCall.Name,
 					),
 					Subject: parentCall.DeclRange.Ptr(),
+					Extra:   tfdiags.WarningImplicitProviderInheritance,
 				})
 			}
 		}
@@ -627,6 +630,7 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 						name, providerAddr.Provider.ForDisplay(),
 					),
 					Subject: &passed.InChild.NameRange,
+					Extra:   tfdiags.WarningImplicitProviderInheritance,
 				})
 			} else {
 				diags = append(diags, &hcl.Diagnostic{
@@ -784,6 +788,7 @@ func validateProviderConfigs(parentCall *ModuleCall, cfg *Config, noProviderConf
 			Summary:  "Redundant empty provider block",
 			Detail:   buf.String(),
 			Subject:  suggestion.SourceRanges[0].Ptr(),
+			Extra:    tfdiags.WarningDeprecation,
 		})
 	}
 
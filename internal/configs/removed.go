@@ -7,6 +7,7 @@ package configs
 
 import (
 	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/opentofu/opentofu/internal/addrs"
 )
 
@@ -14,6 +15,15 @@ import (
 type Removed struct {
 	From *addrs.RemoveEndpoint
 
+	// Destroy is true if the objects that From refers to should be destroyed,
+	// rather than only removed from the state. It is set by the "destroy"
+	// argument in a nested "lifecycle" block.
+	Destroy bool
+
+	// Provisioners are the destroy-time provisioners to run before
+	// destroying each instance of the resource that From refers to.
+	Provisioners []*Provisioner
+
 	DeclRange hcl.Range
 }
 
@@ -36,6 +46,57 @@ func decodeRemovedBlock(block *hcl.Block) (*Removed, hcl.Diagnostics) {
 		}
 	}
 
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "lifecycle":
+			lcContent, lcDiags := block.Body.Content(removedLifecycleBlockSchema)
+			diags = append(diags, lcDiags...)
+
+			if attr, exists := lcContent.Attributes["destroy"]; exists {
+				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &removed.Destroy)
+				diags = append(diags, valDiags...)
+			}
+
+		case "provisioner":
+			pv, pvDiags := decodeProvisionerBlock(block)
+			diags = append(diags, pvDiags...)
+			if pv == nil {
+				continue
+			}
+			if pv.When != ProvisionerWhenDestroy {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid provisioner in removed block",
+					Detail:   "A removed block can only run provisioners when destroying the objects it refers to, so each of its provisioners must set when = destroy.",
+					Subject:  &block.DefRange,
+				})
+				continue
+			}
+			removed.Provisioners = append(removed.Provisioners, pv)
+		}
+	}
+
+	if len(removed.Provisioners) > 0 {
+		if removed.From != nil {
+			if _, ok := removed.From.RelSubject.(addrs.Module); ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid provisioner in removed block",
+					Detail:   "Provisioners are allowed only in removed blocks that refer to a resource, not to a module.",
+					Subject:  &removed.Provisioners[0].DeclRange,
+				})
+			}
+		}
+		if !removed.Destroy {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provisioner in removed block",
+				Detail:   "The provisioners in a removed block run only when the objects it refers to are destroyed. To destroy the objects instead of only removing them from the state, add a lifecycle block with destroy = true.",
+				Subject:  &removed.Provisioners[0].DeclRange,
+			})
+		}
+	}
+
 	return removed, diags
 }
 
@@ -46,4 +107,16 @@ var removedBlockSchema = &hcl.BodySchema{
 			Required: true,
 		},
 	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{Type: "lifecycle"},
+		{Type: "provisioner", LabelNames: []string{"type"}},
+	},
+}
+
+var removedLifecycleBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{
+			Name: "destroy",
+		},
+	},
 }
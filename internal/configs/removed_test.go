@@ -196,3 +196,115 @@ func mustRemoveEndpointFromExpr(expr hcl.Expression) *addrs.RemoveEndpoint {
 
 	return ep
 }
+
+func TestRemovedBlock_destroy(t *testing.T) {
+	parser := testParser(map[string]string{
+		"mod/main.tf": `
+removed {
+  from = test.foo
+
+  lifecycle {
+    destroy = true
+  }
+
+  provisioner "local-exec" {
+    when    = destroy
+    command = "echo ${self.id}"
+  }
+}
+
+removed {
+  from = module.bar
+}
+`,
+	})
+	mod, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
+	assertNoDiagnostics(t, diags)
+
+	if got, want := len(mod.Removed), 2; got != want {
+		t.Fatalf("wrong number of removed blocks %d; want %d", got, want)
+	}
+	foo, bar := mod.Removed[0], mod.Removed[1]
+	if !foo.Destroy {
+		t.Errorf("removed block for test.foo should destroy")
+	}
+	if got, want := len(foo.Provisioners), 1; got != want {
+		t.Fatalf("wrong number of provisioners %d; want %d", got, want)
+	}
+	if got, want := foo.Provisioners[0].Type, "local-exec"; got != want {
+		t.Errorf("wrong provisioner type %q; want %q", got, want)
+	}
+	if bar.Destroy {
+		t.Errorf("removed block for module.bar should not destroy")
+	}
+}
+
+func TestRemovedBlock_destroyErrors(t *testing.T) {
+	tests := map[string]struct {
+		config string
+		want   []string
+	}{
+		"create-time provisioner": {
+			`
+removed {
+  from = test.foo
+
+  lifecycle {
+    destroy = true
+  }
+
+  provisioner "local-exec" {
+    command = "echo"
+  }
+}
+`,
+			[]string{
+				`mod/main.tf:9,3-27: Invalid provisioner in removed block; A removed block can only run provisioners when destroying the objects it refers to, so each of its provisioners must set when = destroy.`,
+			},
+		},
+		"provisioner without destroy": {
+			`
+removed {
+  from = test.foo
+
+  provisioner "local-exec" {
+    when    = destroy
+    command = "echo"
+  }
+}
+`,
+			[]string{
+				`mod/main.tf:5,3-27: Invalid provisioner in removed block; The provisioners in a removed block run only when the objects it refers to are destroyed. To destroy the objects instead of only removing them from the state, add a lifecycle block with destroy = true.`,
+			},
+		},
+		"provisioner for module": {
+			`
+removed {
+  from = module.foo
+
+  lifecycle {
+    destroy = true
+  }
+
+  provisioner "local-exec" {
+    when    = destroy
+    command = "echo"
+  }
+}
+`,
+			[]string{
+				`mod/main.tf:9,3-27: Invalid provisioner in removed block; Provisioners are allowed only in removed blocks that refer to a resource, not to a module.`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{
+				"mod/main.tf": test.config,
+			})
+			_, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
+			assertExactDiagnostics(t, diags, test.want)
+		})
+	}
+}
@@ -7,6 +7,7 @@ package configs
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
@@ -39,6 +40,13 @@ type Resource struct {
 
 	TriggersReplacement []hcl.Expression
 
+	// OperationTimeout limits how long each call to the provider to read,
+	// create, update or delete an instance of this resource may take, and
+	// is meaningful only if OperationTimeoutSet is true. When it's not set,
+	// the default from the CLI configuration applies, if any.
+	OperationTimeout    time.Duration
+	OperationTimeoutSet bool
+
 	// Managed is populated only for Mode = addrs.ManagedResourceMode,
 	// containing the additional fields that apply to managed resources.
 	// For all other resource modes, this field is nil.
@@ -58,6 +66,10 @@ type Resource struct {
 	// should be used to compose mock provider response. It is possible to have
 	// zero-length OverrideValues even if IsOverridden is set to true.
 	OverrideValues map[string]cty.Value
+	// OverrideGenerators are only valid if IsOverridden is set to true. They
+	// generate the computed values that are not in OverrideValues, and may
+	// be nil.
+	OverrideGenerators *hcl2shim.MockValueGenerators
 
 	DeclRange hcl.Range
 	TypeRange hcl.Range
@@ -75,6 +87,32 @@ type ManagedResource struct {
 
 	CreateBeforeDestroySet bool
 	PreventDestroySet      bool
+
+	// Priority is a scheduling hint: when more resource instances are ready
+	// to be processed than the walk parallelism allows, those with a higher
+	// priority start first. It is used to start long-running operations as
+	// early as possible, and is meaningful only if PrioritySet is true.
+	Priority    int
+	PrioritySet bool
+
+	// TwoPhaseReplace, for create_before_destroy replacements, pauses after
+	// the new object is created and healthy and asks for confirmation before
+	// destroying the previous object.
+	TwoPhaseReplace    bool
+	TwoPhaseReplaceSet bool
+
+	// TwoPhaseReplaceTimeout limits how long a two-phase replacement waits
+	// for the new object to become ready, and is meaningful only if
+	// TwoPhaseReplaceTimeoutSet is true.
+	TwoPhaseReplaceTimeout    time.Duration
+	TwoPhaseReplaceTimeoutSet bool
+
+	// Canary, for resources using count or for_each, lists the instance keys
+	// whose changes are applied first. The changes to the other instances
+	// are applied only once the canary instances are applied and the check
+	// blocks that refer to the resource pass.
+	Canary    []addrs.InstanceKey
+	CanarySet bool
 }
 
 func (r *Resource) moduleUniqueKey() string {
@@ -210,6 +248,43 @@ func decodeResourceBlock(block *hcl.Block, override bool) (*Resource, hcl.Diagno
 				r.Managed.PreventDestroySet = true
 			}
 
+			if attr, exists := lcContent.Attributes["priority"]; exists {
+				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.Managed.Priority)
+				diags = append(diags, valDiags...)
+				r.Managed.PrioritySet = true
+			}
+
+			if attr, exists := lcContent.Attributes["two_phase_replace"]; exists {
+				valDiags := gohcl.DecodeExpression(attr.Expr, nil, &r.Managed.TwoPhaseReplace)
+				diags = append(diags, valDiags...)
+				r.Managed.TwoPhaseReplaceSet = true
+			}
+
+			if attr, exists := lcContent.Attributes["two_phase_replace_timeout"]; exists {
+				timeout, timeoutDiags := decodeLifecycleDuration(attr)
+				diags = append(diags, timeoutDiags...)
+				r.Managed.TwoPhaseReplaceTimeout = timeout
+				r.Managed.TwoPhaseReplaceTimeoutSet = !timeoutDiags.HasErrors()
+			}
+
+			if attr, exists := lcContent.Attributes["operation_timeout"]; exists {
+				timeout, timeoutDiags := decodeLifecycleDuration(attr)
+				diags = append(diags, timeoutDiags...)
+				r.OperationTimeout = timeout
+				r.OperationTimeoutSet = !timeoutDiags.HasErrors()
+			}
+
+			if attr, exists := lcContent.Attributes["canary"]; exists {
+				keys, keyDiags := decodeCanaryKeys(attr.Expr)
+				diags = append(diags, keyDiags...)
+				r.Managed.Canary = keys
+				r.Managed.CanarySet = true
+
+				if !override && !keyDiags.HasErrors() {
+					diags = append(diags, validateCanaryKeys(r, keys, attr)...)
+				}
+			}
+
 			if attr, exists := lcContent.Attributes["replace_triggered_by"]; exists {
 				exprs, hclDiags := decodeReplaceTriggeredBy(attr.Expr)
 				diags = diags.Extend(hclDiags)
@@ -498,10 +573,20 @@ func decodeDataBlock(block *hcl.Block, override, nested bool) (*Resource, hcl.Di
 			lcContent, lcDiags := block.Body.Content(resourceLifecycleBlockSchema)
 			diags = append(diags, lcDiags...)
 
-			// All of the attributes defined for resource lifecycle are for
-			// managed resources only, so we can emit a common error message
-			// for any given attributes that HCL accepted.
+			if attr, exists := lcContent.Attributes["operation_timeout"]; exists {
+				timeout, timeoutDiags := decodeLifecycleDuration(attr)
+				diags = append(diags, timeoutDiags...)
+				r.OperationTimeout = timeout
+				r.OperationTimeoutSet = !timeoutDiags.HasErrors()
+			}
+
+			// All of the other attributes defined for resource lifecycle are
+			// for managed resources only, so we can emit a common error
+			// message for any given attributes that HCL accepted.
 			for name, attr := range lcContent.Attributes {
+				if name == "operation_timeout" {
+					continue
+				}
 				diags = append(diags, &hcl.Diagnostic{
 					Severity: hcl.DiagError,
 					Summary:  "Invalid data resource lifecycle argument",
@@ -547,6 +632,91 @@ func decodeDataBlock(block *hcl.Block, override, nested bool) (*Resource, hcl.Di
 	return r, diags
 }
 
+// decodeLifecycleDuration decodes the duration given in a lifecycle argument
+// such as operation_timeout.
+func decodeLifecycleDuration(attr *hcl.Attribute) (time.Duration, hcl.Diagnostics) {
+	var raw string
+	diags := gohcl.DecodeExpression(attr.Expr, nil, &raw)
+	if diags.HasErrors() {
+		return 0, diags
+	}
+
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		return 0, append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Invalid %s argument", attr.Name),
+			Detail:   fmt.Sprintf("The %s argument must be a positive duration, such as \"90s\" or \"30m\".", attr.Name),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+	return timeout, diags
+}
+
+// decodeCanaryKeys decodes the static list of instance keys given in the
+// canary lifecycle argument. Strings become string keys and whole numbers
+// become integer keys.
+func decodeCanaryKeys(expr hcl.Expression) ([]addrs.InstanceKey, hcl.Diagnostics) {
+	val, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	invalid := func(detail string) hcl.Diagnostics {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid canary argument",
+			Detail:   detail,
+			Subject:  expr.Range().Ptr(),
+		})
+	}
+
+	ty := val.Type()
+	if val.IsNull() || !(ty.IsListType() || ty.IsTupleType() || ty.IsSetType()) {
+		return nil, invalid("The canary argument must be a list of instance keys.")
+	}
+
+	var keys []addrs.InstanceKey
+	for it := val.ElementIterator(); it.Next(); {
+		_, v := it.Element()
+		if v.IsNull() || !v.IsKnown() {
+			return nil, invalid("The canary instance keys must be strings or whole numbers.")
+		}
+		key, err := addrs.ParseInstanceKey(v)
+		if err != nil {
+			return nil, invalid(fmt.Sprintf("Invalid canary instance key: %s.", err))
+		}
+		keys = append(keys, key)
+	}
+	return keys, diags
+}
+
+// validateCanaryKeys checks that the canary instance keys are of the kind
+// produced by the repetition argument of the given resource.
+func validateCanaryKeys(r *Resource, keys []addrs.InstanceKey, attr *hcl.Attribute) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+	if r.Count == nil && r.ForEach == nil {
+		return append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid canary argument",
+			Detail:   `The canary argument can be used only for resources that use "count" or "for_each".`,
+			Subject:  attr.NameRange.Ptr(),
+		})
+	}
+	for _, key := range keys {
+		_, isInt := key.(addrs.IntKey)
+		if isInt != (r.Count != nil) {
+			return append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid canary argument",
+				Detail:   fmt.Sprintf(`The canary instance key %s does not match the keys of this resource. Resources using "count" have whole number keys, and resources using "for_each" have string keys.`, key),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+	return diags
+}
+
 // decodeReplaceTriggeredBy decodes and does basic validation of the
 // replace_triggered_by expressions, ensuring they only contains references to
 // a single resource, and the only extra variables are count.index or each.key.
@@ -653,6 +823,11 @@ type ProviderConfigRef struct {
 	Alias      string
 	AliasRange *hcl.Range // nil if alias not set
 
+	// KeyExpression is the expression giving the instance key of a reference
+	// to an instance of a provider configuration with for_each, such as
+	// each.key in aws.regions[each.key], or nil if there is no instance key.
+	KeyExpression hcl.Expression
+
 	// TODO: this may not be set in some cases, so it is not yet suitable for
 	// use outside of this package. We currently only use it for internal
 	// validation, but once we verify that this can be set in all cases, we can
@@ -668,6 +843,13 @@ func decodeProviderConfigRef(expr hcl.Expression, argName string) (*ProviderConf
 	expr, shimDiags = shimTraversalInString(expr, false)
 	diags = append(diags, shimDiags...)
 
+	// A reference to an instance of a provider configuration with for_each
+	// ends with an index, whose key can be an arbitrary expression.
+	var keyExpr hcl.Expression
+	if indexExpr, ok := expr.(*hclsyntax.IndexExpr); ok {
+		expr, keyExpr = indexExpr.Collection, indexExpr.Key
+	}
+
 	traversal, travDiags := hcl.AbsTraversalForExpr(expr)
 
 	// AbsTraversalForExpr produces only generic errors, so we'll discard
@@ -677,6 +859,14 @@ func decodeProviderConfigRef(expr hcl.Expression, argName string) (*ProviderConf
 		diags = append(diags, travDiags...)
 	}
 
+	// A literal instance key is part of the traversal itself.
+	if len(traversal) == 3 && keyExpr == nil {
+		if indexStep, ok := traversal[2].(hcl.TraverseIndex); ok {
+			keyExpr = hcl.StaticExpr(indexStep.Key, indexStep.SrcRange)
+			traversal = traversal[:2]
+		}
+	}
+
 	if len(traversal) < 1 || len(traversal) > 2 {
 		// A provider reference was given as a string literal in the legacy
 		// configuration language and there are lots of examples out there
@@ -731,6 +921,19 @@ func decodeProviderConfigRef(expr hcl.Expression, argName string) (*ProviderConf
 		ret.AliasRange = aliasStep.SourceRange().Ptr()
 	}
 
+	if keyExpr != nil {
+		if ret.Alias == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider configuration reference",
+				Detail:   "An instance key can only follow the alias of a provider configuration with for_each.",
+				Subject:  keyExpr.Range().Ptr(),
+			})
+			return ret, diags
+		}
+		ret.KeyExpression = keyExpr
+	}
+
 	return ret, diags
 }
 
@@ -814,6 +1017,21 @@ var resourceLifecycleBlockSchema = &hcl.BodySchema{
 		{
 			Name: "replace_triggered_by",
 		},
+		{
+			Name: "priority",
+		},
+		{
+			Name: "two_phase_replace",
+		},
+		{
+			Name: "two_phase_replace_timeout",
+		},
+		{
+			Name: "canary",
+		},
+		{
+			Name: "operation_timeout",
+		},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{Type: "precondition"},
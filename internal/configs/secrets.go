@@ -0,0 +1,172 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// SecretAllowPragma is the text of a comment that silences the warnings from
+// DetectSecretsInDir for a string on the same line as the comment or on the
+// line following it.
+const SecretAllowPragma = "tofu:allow-secret"
+
+var (
+	awsAccessKeyIDPattern = regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA)[0-9A-Z]{16}\b`)
+	privateKeyPattern     = regexp.MustCompile(`-----BEGIN [A-Z0-9 ]*PRIVATE KEY(?: BLOCK)?-----`)
+	secretTokenPattern    = regexp.MustCompile(`[A-Za-z0-9+/_=-]{32,}`)
+)
+
+// secretTokenMinEntropy is the Shannon entropy, in bits per character, from
+// which we consider a long token that mixes upper case letters, lower case
+// letters and digits to be random enough to be a secret.
+const secretTokenMinEntropy = 4.0
+
+// DetectSecretsInDir looks for string literals that appear to be secrets,
+// such as credentials or private keys, in the native syntax configuration
+// files and variable definitions files in the given directory.
+//
+// Each string found is reported as a warning, unless it is on the same line
+// as a comment containing SecretAllowPragma or on the line after one. Files
+// that cannot be read or parsed are skipped, because loading the
+// configuration reports those problems already.
+func (p *Parser) DetectSecretsInDir(dir string) hcl.Diagnostics {
+	primary, override, _, _ := p.dirFiles(dir, "")
+
+	paths := append(primary, override...)
+	if infos, err := p.fs.ReadDir(dir); err == nil {
+		for _, info := range infos {
+			name := info.Name()
+			if !info.IsDir() && !IsIgnoredFile(name) && strings.HasSuffix(name, ".tfvars") {
+				paths = append(paths, filepath.Join(dir, name))
+			}
+		}
+	}
+	sort.Strings(paths)
+
+	var diags hcl.Diagnostics
+	for _, path := range paths {
+		if strings.HasSuffix(path, ".json") {
+			// JSON files have no comments for the pragma, and their strings
+			// are not literals that we can find without decoding them.
+			continue
+		}
+		diags = append(diags, p.detectSecretsInFile(path)...)
+	}
+	return diags
+}
+
+func (p *Parser) detectSecretsInFile(path string) hcl.Diagnostics {
+	src, err := p.fs.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	file, parseDiags := p.parse(src, path, parseHCL)
+	if parseDiags.HasErrors() {
+		return nil
+	}
+	body, ok := file.Body.(*hclsyntax.Body)
+	if !ok {
+		return nil
+	}
+
+	allowed := secretAllowedLines(src, path)
+
+	var diags hcl.Diagnostics
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		attr, ok := node.(*hclsyntax.Attribute)
+		if !ok {
+			return nil
+		}
+		attrLine := attr.SrcRange.Start.Line
+		hclsyntax.VisitAll(attr.Expr, func(node hclsyntax.Node) hcl.Diagnostics {
+			lit, ok := node.(*hclsyntax.LiteralValueExpr)
+			if !ok || lit.Val.Type() != cty.String || lit.Val.IsNull() || !lit.Val.IsKnown() {
+				return nil
+			}
+			line := lit.SrcRange.Start.Line
+			if allowed[line] || allowed[line-1] || allowed[attrLine] || allowed[attrLine-1] {
+				return nil
+			}
+			kind := secretKind(lit.Val.AsString())
+			if kind == "" {
+				return nil
+			}
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Possible secret in configuration",
+				Detail: fmt.Sprintf(
+					"This string looks like %s. Secrets written in configuration files can be read by anyone with access to the files, including through version control.\n\nInstead, provide the value through an input variable marked as sensitive. If this string is not a secret, add a comment containing %q on the same line or on the line before to silence this warning.",
+					kind, SecretAllowPragma,
+				),
+				Subject: lit.SrcRange.Ptr(),
+			})
+			return nil
+		})
+		return nil
+	})
+	return diags
+}
+
+// secretAllowedLines returns the lines of the given source that have a
+// comment containing SecretAllowPragma.
+func secretAllowedLines(src []byte, filename string) map[int]bool {
+	ret := make(map[int]bool)
+	tokens, _ := hclsyntax.LexConfig(src, filename, hcl.InitialPos)
+	for _, token := range tokens {
+		if token.Type == hclsyntax.TokenComment && strings.Contains(string(token.Bytes), SecretAllowPragma) {
+			ret[token.Range.Start.Line] = true
+		}
+	}
+	return ret
+}
+
+// secretKind returns a description of the kind of secret that the given
+// string appears to contain, or an empty string if it doesn't appear to
+// contain one.
+func secretKind(s string) string {
+	switch {
+	case privateKeyPattern.MatchString(s):
+		return "a private key"
+	case awsAccessKeyIDPattern.MatchString(s):
+		return "an AWS access key ID"
+	}
+	for _, token := range secretTokenPattern.FindAllString(s, -1) {
+		mixed := strings.ContainsAny(token, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") &&
+			strings.ContainsAny(token, "abcdefghijklmnopqrstuvwxyz") &&
+			strings.ContainsAny(token, "0123456789")
+		if mixed && shannonEntropy(token) >= secretTokenMinEntropy {
+			return "a randomly-generated token, such as an API key or a password"
+		}
+	}
+	return ""
+}
+
+// shannonEntropy returns the Shannon entropy of the given string, in bits per
+// character.
+func shannonEntropy(s string) float64 {
+	counts := make(map[rune]int)
+	total := 0
+	for _, r := range s {
+		counts[r]++
+		total++
+	}
+	var ret float64
+	for _, count := range counts {
+		p := float64(count) / float64(total)
+		ret -= p * math.Log2(p)
+	}
+	return ret
+}
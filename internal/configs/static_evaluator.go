@@ -37,6 +37,9 @@ type StaticModuleCall struct {
 	vars      StaticModuleVariables
 	rootPath  string
 	workspace string
+
+	// types are the named types the module inherits from its caller.
+	types map[string]*TypeDefinition
 }
 
 func NewStaticModuleCall(addr addrs.Module, vars StaticModuleVariables, rootPath string, workspace string) StaticModuleCall {
@@ -48,6 +51,13 @@ func NewStaticModuleCall(addr addrs.Module, vars StaticModuleVariables, rootPath
 	}
 }
 
+// WithTypes returns a copy of the call that makes the given named types
+// visible to the called module, alongside any that it defines itself.
+func (s StaticModuleCall) WithTypes(types map[string]*TypeDefinition) StaticModuleCall {
+	s.types = types
+	return s
+}
+
 // only used in testing
 func RootModuleCallForTesting() StaticModuleCall {
 	return NewStaticModuleCall(addrs.RootModule, func(_ *Variable) (cty.Value, hcl.Diagnostics) {
@@ -192,7 +192,7 @@ func (s staticScopeData) GetPathAttr(addr addrs.PathAttr, rng tfdiags.SourceRang
 	default:
 		suggestion := didyoumean.NameSuggestion(addr.Name, []string{"cwd", "module", "root"})
 		if suggestion != "" {
-			suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
 		}
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -212,6 +212,17 @@ func (s staticScopeData) GetTerraformAttr(addr addrs.TerraformAttr, rng tfdiags.
 		workspaceName := s.eval.call.workspace
 		return cty.StringVal(workspaceName), diags
 
+	case "workspace_meta":
+		// The workspace metadata is kept in the state, which isn't
+		// available when evaluating static expressions.
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Workspace metadata not supported in static context",
+			Detail:   fmt.Sprintf("Unable to use %s in static context, because the workspace metadata is only available once the state has been read.", addr),
+			Subject:  rng.ToHCL().Ptr(),
+		})
+		return cty.DynamicVal, diags
+
 	case "env":
 		// Prior to Terraform 0.12 there was an attribute "env", which was
 		// an alias name for "workspace". This was deprecated and is now
@@ -225,10 +236,14 @@ func (s staticScopeData) GetTerraformAttr(addr addrs.TerraformAttr, rng tfdiags.
 		return cty.DynamicVal, diags
 
 	default:
+		suggestion := didyoumean.NameSuggestion(addr.Name, []string{"workspace", "workspace_meta"})
+		if suggestion != "" {
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
+		}
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Invalid "terraform" attribute`,
-			Detail:   fmt.Sprintf(`The "terraform" object does not have an attribute named %q. The only supported attribute is terraform.workspace, the name of the currently-selected workspace.`, addr.Name),
+			Detail:   fmt.Sprintf(`The "terraform" object does not have an attribute named %q. The only supported attributes are terraform.workspace, the name of the currently-selected workspace, and terraform.workspace_meta, its description and tags.%s`, addr.Name, suggestion),
 			Subject:  rng.ToHCL().Ptr(),
 		})
 		return cty.DynamicVal, diags
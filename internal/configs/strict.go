@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/didyoumean"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Strict represents the "strict" argument in a "terraform" block, which
+// selects the categories of warnings that OpenTofu should treat as errors.
+//
+// The argument is either a bool, selecting all or none of the categories, or
+// a list of category names. Only the setting in the root module has any
+// effect.
+type Strict struct {
+	Categories []tfdiags.WarningCategory
+
+	DeclRange hcl.Range
+}
+
+func decodeStrictAttr(attr *hcl.Attribute) (*Strict, hcl.Diagnostics) {
+	ret := &Strict{
+		DeclRange: attr.Range,
+	}
+
+	val, diags := attr.Expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	ty := val.Type()
+	switch {
+	case val.IsNull():
+		// A null value is the same as omitting the argument.
+		return ret, diags
+
+	case ty == cty.Bool:
+		if !val.IsKnown() {
+			break
+		}
+		if val.True() {
+			ret.Categories = append(ret.Categories, tfdiags.WarningCategories...)
+		}
+		return ret, diags
+
+	case ty.IsTupleType() || ty.IsListType() || ty.IsSetType():
+		if !val.IsWhollyKnown() {
+			break
+		}
+		for it := val.ElementIterator(); it.Next(); {
+			_, elem := it.Element()
+			if elem.IsNull() || elem.Type() != cty.String {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid warning category",
+					Detail:   fmt.Sprintf("Each warning category must be a string. The supported categories are %s.", strictCategoryNames()),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+			name := elem.AsString()
+			if !tfdiags.ValidWarningCategory(name) {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid warning category",
+					Detail:   fmt.Sprintf("There is no warning category named %q. The supported categories are %s.%s", name, strictCategoryNames(), strictCategorySuggestion(name)),
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+				continue
+			}
+			ret.Categories = append(ret.Categories, tfdiags.WarningCategory(name))
+		}
+		return ret, diags
+	}
+
+	diags = append(diags, &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Invalid strict setting",
+		Detail:   "The \"strict\" argument must be either a bool or a list of warning category names.",
+		Subject:  attr.Expr.Range().Ptr(),
+	})
+	return nil, diags
+}
+
+func strictCategoryNames() string {
+	names := make([]string, len(tfdiags.WarningCategories))
+	for i, category := range tfdiags.WarningCategories {
+		names[i] = fmt.Sprintf("%q", category)
+	}
+	return strings.Join(names, ", ")
+}
+
+func strictCategorySuggestion(given string) string {
+	names := make([]string, len(tfdiags.WarningCategories))
+	for i, category := range tfdiags.WarningCategories {
+		names[i] = string(category)
+	}
+	if suggestion := didyoumean.NameSuggestion(given, names); suggestion != "" {
+		return fmt.Sprintf("\n\nDid you mean %q?", suggestion)
+	}
+	return ""
+}
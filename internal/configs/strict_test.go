@@ -0,0 +1,120 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestStrict(t *testing.T) {
+	tests := map[string]struct {
+		config string
+		want   []tfdiags.WarningCategory
+	}{
+		"true": {
+			`
+terraform {
+  strict = true
+}
+`,
+			tfdiags.WarningCategories,
+		},
+		"false": {
+			`
+terraform {
+  strict = false
+}
+`,
+			nil,
+		},
+		"list": {
+			`
+terraform {
+  strict = ["deprecations", "undeclared_var_files"]
+}
+`,
+			[]tfdiags.WarningCategory{
+				tfdiags.WarningDeprecation,
+				tfdiags.WarningUndeclaredVarFile,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{
+				"mod/main.tf": test.config,
+			})
+			mod, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
+			assertNoDiagnostics(t, diags)
+
+			if mod.Strict == nil {
+				t.Fatal("strict setting was not recorded")
+			}
+			if diff := cmp.Diff(test.want, mod.Strict.Categories); diff != "" {
+				t.Errorf("wrong categories\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestStrict_errors(t *testing.T) {
+	tests := map[string]struct {
+		config string
+		want   []string
+	}{
+		"unknown category": {
+			`
+terraform {
+  strict = ["deprecation"]
+}
+`,
+			[]string{
+				`mod/main.tf:3,12-27: Invalid warning category; There is no warning category named "deprecation". The supported categories are "deprecations", "implicit_provider_inheritance", "undeclared_var_files".
+
+Did you mean "deprecations"?`,
+			},
+		},
+		"wrong type": {
+			`
+terraform {
+  strict = "deprecations"
+}
+`,
+			[]string{
+				`mod/main.tf:3,12-26: Invalid strict setting; The "strict" argument must be either a bool or a list of warning category names.`,
+			},
+		},
+		"duplicate": {
+			`
+terraform {
+  strict = true
+}
+
+terraform {
+  strict = false
+}
+`,
+			[]string{
+				`mod/main.tf:7,3-17: Duplicate strict setting; A module may have only one strict setting. Strict mode was previously configured at mod/main.tf:3,3-16.`,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parser := testParser(map[string]string{
+				"mod/main.tf": test.config,
+			})
+			_, diags := parser.LoadConfigDir("mod", RootModuleCallForTesting())
+			assertExactDiagnostics(t, diags, test.want)
+		})
+	}
+}
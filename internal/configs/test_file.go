@@ -7,13 +7,21 @@ package configs
 
 import (
 	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/gohcl"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/hcl2shim"
 	"github.com/opentofu/opentofu/internal/getmodules"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -43,6 +51,10 @@ const (
 	RefreshOnlyTestMode TestMode = 'R'
 )
 
+// DefaultTestRetryDelay is how long to wait before retrying a run block that
+// sets retries but not retry_delay.
+const DefaultTestRetryDelay = time.Second
+
 // TestFile represents a single test file within a `tofu test` execution.
 //
 // A test file is made up of a sequential list of run blocks, each designating
@@ -63,6 +75,12 @@ type TestFile struct {
 	// order.
 	Runs []*TestRun
 
+	// Setup and Teardown are optional run blocks, decoded from the setup and
+	// teardown blocks, that apply helper modules before the first run block
+	// and after the last, regardless of whether the run blocks failed.
+	Setup    *TestRun
+	Teardown *TestRun
+
 	// OverrideResources is a list of resources to be overridden with static values.
 	// Underlying providers shouldn't be called for overridden resources.
 	OverrideResources []*OverrideResource
@@ -71,9 +89,35 @@ type TestFile struct {
 	// Underlying modules shouldn't be called.
 	OverrideModules []*OverrideModule
 
+	// Imports are the helpers that the test file imports from the lib
+	// directory within the test directory, in the order they are listed.
+	Imports []TestImport
+
+	// Assertions are the named groups of assertions declared in the test
+	// file, which its run blocks can include.
+	Assertions map[string]*TestAssertions
+
+	// BeforeEach declares definitions that apply to every run block in the
+	// test file, or is nil if the file has no before_each block.
+	BeforeEach *TestBeforeEach
+
 	VariablesDeclRange hcl.Range
 }
 
+// AllRuns returns the setup run block, followed by the run blocks, followed by
+// the teardown run block, leaving out any that aren't defined.
+func (file *TestFile) AllRuns() []*TestRun {
+	var ret []*TestRun
+	if file.Setup != nil {
+		ret = append(ret, file.Setup)
+	}
+	ret = append(ret, file.Runs...)
+	if file.Teardown != nil {
+		ret = append(ret, file.Teardown)
+	}
+	return ret
+}
+
 // Validate does a very simple and cursory check across the file blocks to look
 // for simple issues we can highlight early on. It doesn't validate nested run blocks.
 func (file *TestFile) Validate() tfdiags.Diagnostics {
@@ -147,6 +191,32 @@ type TestRun struct {
 	// run.
 	ExpectFailures []hcl.Traversal
 
+	// Variants are the variables of the variants block, if the run block has
+	// one, in the order they are declared. The run block is expanded into a
+	// run block for each combination of their values when the test file is
+	// loaded, so this is only set on the original run block.
+	Variants []*TestRunVariant
+
+	// VariantName describes the combination of variant values that this
+	// run block was expanded for, such as "region=us-east-1", or is empty if
+	// it wasn't expanded from a run block with a variants block.
+	VariantName string
+
+	// Assertions are the named groups of assertions that the run block
+	// includes, in addition to its own assert blocks. They are resolved into
+	// CheckRules when the test file is loaded.
+	Assertions []TestAssertionsRef
+
+	// ExpectedFailures are the expect_failure blocks of the run block, each of
+	// which describes an error diagnostic that this test run is expected to
+	// produce more precisely than ExpectFailures can.
+	ExpectedFailures []*TestExpectedFailure
+
+	// Invocations are the invoke blocks of the run block, each of which
+	// invokes a provider-defined action after the configuration is applied
+	// and before the assertions are checked, in the order they are declared.
+	Invocations []*TestRunInvocation
+
 	// OverrideResources is a list of resources to be overridden with static values.
 	// Underlying providers shouldn't be called for overridden resources.
 	OverrideResources []*OverrideResource
@@ -155,6 +225,24 @@ type TestRun struct {
 	// Underlying modules shouldn't be called.
 	OverrideModules []*OverrideModule
 
+	// Parallel declares that this run block is safe to execute concurrently
+	// with the adjacent run blocks that also set it, as long as they use
+	// different state. Run blocks within such a group can't refer to each
+	// other's outputs.
+	Parallel bool
+
+	// ExpectNoChanges declares that the plan of this run block must not
+	// change any resources or output values. It can only be set on run blocks
+	// that execute a plan.
+	ExpectNoChanges bool
+
+	// Retries is the number of times the run block is executed again after
+	// it fails or errors, before its failure is reported. RetryDelay is how
+	// long to wait before the first retry, which doubles for each retry
+	// after that.
+	Retries    int
+	RetryDelay time.Duration
+
 	NameDeclRange      hcl.Range
 	VariablesDeclRange hcl.Range
 	DeclRange          hcl.Range
@@ -190,6 +278,29 @@ func (run *TestRun) Validate() tfdiags.Diagnostics {
 
 	}
 
+	for _, failure := range run.ExpectedFailures {
+		if failure.Object == nil {
+			continue
+		}
+
+		reference, refDiags := addrs.ParseRefFromTestingScope(failure.Object)
+		diags = diags.Append(refDiags)
+		if refDiags.HasErrors() {
+			continue
+		}
+
+		switch reference.Subject.(type) {
+		case addrs.OutputValue, addrs.InputVariable, addrs.Check, addrs.ResourceInstance, addrs.Resource:
+		default:
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid `expect_failure` object",
+				Detail:   fmt.Sprintf("You cannot expect failures from %s. You can only expect failures from checkable objects such as input variables, output values, check blocks, managed resources and data sources.", reference.Subject.String()),
+				Subject:  reference.SourceRange.ToHCL().Ptr(),
+			})
+		}
+	}
+
 	// It's not allowed to have multiple `override_resource`, `override_data` or `override_module` blocks
 	// inside a single run block with the same target address so we want to ensure there's no such cases.
 	diags = diags.Append(checkForDuplicatedOverrideResources(run.OverrideResources))
@@ -198,6 +309,102 @@ func (run *TestRun) Validate() tfdiags.Diagnostics {
 	return diags
 }
 
+// TestRunInvocation is an invoke block within a run block, which invokes a
+// provider-defined action with the arguments in its body.
+type TestRunInvocation struct {
+	// Type is the action type, which implies the provider that offers it.
+	Type string
+
+	// Config sets the arguments of the action. Its expressions can refer to
+	// the resources, variables and local values of the module under test.
+	Config hcl.Body
+
+	DeclRange hcl.Range
+}
+
+// TestRunVariant is a single variable within the variants block of a run
+// block, with each of the values that the run block should be executed with.
+type TestRunVariant struct {
+	Name   string
+	Values []cty.Value
+
+	DeclRange hcl.Range
+}
+
+// ExpandVariants returns the run blocks that the given run block expands
+// to, which is a copy of it for each combination of the values of its
+// variants, or just the run block itself if it has no variants.
+//
+// The copies are named after the original run block and the combination of
+// values, such as "test[region=us-east-1,size=small]", and the variables of
+// each copy are set to the values of its combination.
+func (run *TestRun) ExpandVariants() []*TestRun {
+	if len(run.Variants) == 0 {
+		return []*TestRun{run}
+	}
+
+	// The first variant changes the least often, like nested for loops.
+	var ret []*TestRun
+	var expand func(ix int, names []string, values map[string]cty.Value)
+	expand = func(ix int, names []string, values map[string]cty.Value) {
+		if ix == len(run.Variants) {
+			expanded := *run
+			expanded.Variants = nil
+			expanded.VariantName = strings.Join(names, ",")
+			expanded.Name = fmt.Sprintf("%s[%s]", run.Name, expanded.VariantName)
+			expanded.Variables = make(map[string]hcl.Expression, len(run.Variables)+len(values))
+			for name, expr := range run.Variables {
+				expanded.Variables[name] = expr
+			}
+			for _, variant := range run.Variants {
+				expanded.Variables[variant.Name] = hcl.StaticExpr(values[variant.Name], variant.DeclRange)
+			}
+			ret = append(ret, &expanded)
+			return
+		}
+
+		variant := run.Variants[ix]
+		for _, value := range variant.Values {
+			values[variant.Name] = value
+			expand(ix+1, append(names[:ix:ix], fmt.Sprintf("%s=%s", variant.Name, testVariantValueString(value))), values)
+		}
+	}
+	expand(0, nil, make(map[string]cty.Value))
+	return ret
+}
+
+// testVariantValueString returns a short representation of a variant value
+// for the name of an expanded run block.
+func testVariantValueString(value cty.Value) string {
+	if value.Type() == cty.String && value.IsKnown() && !value.IsNull() {
+		return value.AsString()
+	}
+	return strings.TrimSpace(string(hclwrite.TokensForValue(value).Bytes()))
+}
+
+// TestExpectedFailure is an expect_failure block within a run block, which
+// matches the error diagnostics that satisfy all of its arguments.
+type TestExpectedFailure struct {
+	// Object is the checkable object whose custom conditions must have
+	// reported the error, or nil if the error can come from anywhere.
+	Object hcl.Traversal
+
+	// Summary and Detail are regular expressions that the summary and detail
+	// of the error must match, or nil if they can be anything.
+	Summary *regexp.Regexp
+	Detail  *regexp.Regexp
+
+	// Filename and Line are the source location that the error must refer
+	// to. Filename matches if it is the whole path of the file or a suffix of
+	// it after a path separator, and Line matches if it is within the range
+	// that the error refers to. They are empty and zero if the location can
+	// be anything.
+	Filename string
+	Line     int
+
+	DeclRange hcl.Range
+}
+
 // TestRunModuleCall specifies which module should be executed by a given run
 // block.
 type TestRunModuleCall struct {
@@ -245,6 +452,10 @@ type OverrideResource struct {
 	// Values represents fields to use as defaults
 	// if they are not present in configuration.
 	Values map[string]cty.Value
+
+	// Generators generate the computed fields that are in neither the
+	// configuration nor Values, or is nil if there are no generators.
+	Generators *hcl2shim.MockValueGenerators
 }
 
 func (r OverrideResource) getBlockName() string {
@@ -280,7 +491,14 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 	diags = append(diags, contentDiags...)
 
 	tf := TestFile{
-		Providers: make(map[string]*Provider),
+		Providers:  make(map[string]*Provider),
+		Assertions: make(map[string]*TestAssertions),
+	}
+
+	if attr, exists := content.Attributes["imports"]; exists {
+		imports, importsDiags := decodeTestImports(attr)
+		diags = append(diags, importsDiags...)
+		tf.Imports = imports
 	}
 
 	for _, block := range content.Blocks {
@@ -289,7 +507,7 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 			run, runDiags := decodeTestRunBlock(block)
 			diags = append(diags, runDiags...)
 			if !runDiags.HasErrors() {
-				tf.Runs = append(tf.Runs, run)
+				tf.Runs = append(tf.Runs, run.ExpandVariants()...)
 			}
 
 		case "variables":
@@ -312,6 +530,27 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 				tf.Variables[v.Name] = v.Expr
 			}
 
+		case "setup", "teardown":
+			existing := &tf.Setup
+			if block.Type == "teardown" {
+				existing = &tf.Teardown
+			}
+			if *existing != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  fmt.Sprintf("Multiple %q blocks", block.Type),
+					Detail:   fmt.Sprintf("This test file already has a %s block defined at %s.", block.Type, (*existing).DeclRange),
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+
+			run, runDiags := decodeTestSetupBlock(block)
+			diags = append(diags, runDiags...)
+			if !runDiags.HasErrors() {
+				*existing = run
+			}
+
 		case "provider":
 			provider, providerDiags := decodeProviderBlock(block)
 			diags = append(diags, providerDiags...)
@@ -340,6 +579,52 @@ func loadTestFile(body hcl.Body) (*TestFile, hcl.Diagnostics) {
 				tf.OverrideModules = append(tf.OverrideModules, overrideMod)
 			}
 
+		case "assertions":
+			assertions, assertionsDiags := decodeTestAssertionsBlock(block)
+			diags = append(diags, assertionsDiags...)
+			diags = append(diags, addTestAssertions(tf.Assertions, assertions)...)
+
+		case "before_each":
+			if tf.BeforeEach != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Multiple \"before_each\" blocks",
+					Detail:   fmt.Sprintf("This test file already has a before_each block defined at %s.", tf.BeforeEach.DeclRange),
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+
+			beforeEach, beforeEachDiags := decodeTestBeforeEachBlock(block)
+			diags = append(diags, beforeEachDiags...)
+			tf.BeforeEach = beforeEach
+		}
+	}
+
+	// The imported helpers can only be found relative to the test directory,
+	// so if there are any the helpers are resolved once they are loaded.
+	if len(tf.Imports) == 0 {
+		diags = append(diags, tf.resolveHelpers(nil)...)
+	}
+
+	diags = append(diags, checkParallelRunReferences(tf.Runs)...)
+
+	// The setup and teardown blocks are named like run blocks, so that their
+	// outputs can be referred to in the same way, which means that no run
+	// block can have the same name.
+	for _, hook := range []*TestRun{tf.Setup, tf.Teardown} {
+		if hook == nil {
+			continue
+		}
+		for _, run := range tf.Runs {
+			if run.Name == hook.Name {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Reserved run block name",
+					Detail:   fmt.Sprintf("The name %q refers to the %s block in this test file, so it can't also be used for a run block.", hook.Name, hook.Name),
+					Subject:  run.NameDeclRange.Ptr(),
+				})
+			}
 		}
 	}
 
@@ -426,6 +711,44 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 				r.Module = module
 			}
 
+		case "variants":
+			if r.Variants != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Multiple \"variants\" blocks",
+					Detail:   "This run block already has a variants block defined.",
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+
+			variants, variantsDiags := decodeTestRunVariantsBlock(block)
+			diags = append(diags, variantsDiags...)
+			r.Variants = variants
+
+		case "invoke":
+			if !hclsyntax.ValidIdentifier(block.Labels[0]) {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid action type",
+					Detail:   badIdentifierDetail,
+					Subject:  &block.LabelRanges[0],
+				})
+				continue
+			}
+			r.Invocations = append(r.Invocations, &TestRunInvocation{
+				Type:      block.Labels[0],
+				Config:    block.Body,
+				DeclRange: block.DefRange,
+			})
+
+		case "expect_failure":
+			failure, failureDiags := decodeTestExpectedFailureBlock(block)
+			diags = append(diags, failureDiags...)
+			if !failureDiags.HasErrors() {
+				r.ExpectedFailures = append(r.ExpectedFailures, failure)
+			}
+
 		case blockNameOverrideResource:
 			overrideRes, overrideResDiags := decodeOverrideResourceBlock(block, addrs.ManagedResourceMode)
 			diags = append(diags, overrideResDiags...)
@@ -456,6 +779,17 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 		r.Variables = make(map[string]hcl.Expression)
 	}
 
+	for _, variant := range r.Variants {
+		if _, exists := r.Variables[variant.Name]; exists {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Conflicting variant variable",
+				Detail:   fmt.Sprintf("The variable %q is set by both the variants block and the variables block of this run block. Remove it from one of them.", variant.Name),
+				Subject:  variant.DeclRange.Ptr(),
+			})
+		}
+	}
+
 	if r.Options == nil {
 		// Create an options with default values if the user didn't specify
 		// anything.
@@ -495,9 +829,332 @@ func decodeTestRunBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
 		r.ExpectFailures = failures
 	}
 
+	if attr, exists := content.Attributes["parallel"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &r.Parallel)...)
+	}
+
+	if attr, exists := content.Attributes["assertions"]; exists {
+		refs, refsDiags := decodeTestAssertionsRefs(attr)
+		diags = append(diags, refsDiags...)
+		r.Assertions = refs
+	}
+
+	if attr, exists := content.Attributes["expect_no_changes"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &r.ExpectNoChanges)...)
+		if r.ExpectNoChanges && r.Command != PlanTestCommand {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"expect_no_changes\" argument",
+				Detail:   "The \"expect_no_changes\" argument can only be set on run blocks that execute a plan, with command = plan.",
+				Subject:  attr.Range.Ptr(),
+			})
+		}
+	}
+
+	if len(r.Invocations) > 0 && r.Command != ApplyTestCommand {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid \"invoke\" block",
+			Detail:   "Actions can only be invoked by run blocks that apply the configuration, with command = apply.",
+			Subject:  r.Invocations[0].DeclRange.Ptr(),
+		})
+	}
+
+	r.RetryDelay = DefaultTestRetryDelay
+	if attr, exists := content.Attributes["retries"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &r.Retries)...)
+		if r.Retries < 0 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid \"retries\" argument",
+				Detail:   "The \"retries\" argument must be zero or a positive whole number.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			r.Retries = 0
+		}
+	}
+
+	if attr, exists := content.Attributes["retry_delay"]; exists {
+		var delay string
+		valDiags := gohcl.DecodeExpression(attr.Expr, nil, &delay)
+		diags = append(diags, valDiags...)
+		if !valDiags.HasErrors() {
+			d, err := time.ParseDuration(delay)
+			if err != nil || d < 0 {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Invalid \"retry_delay\" argument",
+					Detail:   "The \"retry_delay\" argument must be a duration, such as \"500ms\" or \"10s\".",
+					Subject:  attr.Expr.Range().Ptr(),
+				})
+			} else {
+				r.RetryDelay = d
+			}
+		}
+	}
+
 	return &r, diags
 }
 
+// decodeTestSetupBlock decodes a setup or teardown block into a run block
+// named after the block type, which applies the module given in its module
+// block.
+func decodeTestSetupBlock(block *hcl.Block) (*TestRun, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := block.Body.Content(testSetupBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	r := TestRun{
+		Name:          block.Type,
+		Command:       ApplyTestCommand,
+		Variables:     make(map[string]hcl.Expression),
+		NameDeclRange: block.TypeRange,
+		DeclRange:     block.DefRange,
+		Options: &TestRunOptions{
+			Mode:    NormalTestMode,
+			Refresh: true,
+		},
+	}
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "variables":
+			if r.VariablesDeclRange != (hcl.Range{}) {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Multiple \"variables\" blocks",
+					Detail:   fmt.Sprintf("This %s block already has a variables block defined at %s.", r.Name, r.VariablesDeclRange),
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+
+			r.VariablesDeclRange = block.DefRange
+
+			vars, varsDiags := block.Body.JustAttributes()
+			diags = append(diags, varsDiags...)
+			for _, v := range vars {
+				r.Variables[v.Name] = v.Expr
+			}
+		case "module":
+			if r.Module != nil {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Multiple \"module\" blocks",
+					Detail:   fmt.Sprintf("This %s block already has a module block defined at %s.", r.Name, r.Module.DeclRange),
+					Subject:  block.DefRange.Ptr(),
+				})
+				continue
+			}
+
+			module, moduleDiags := decodeTestRunModuleBlock(block)
+			diags = append(diags, moduleDiags...)
+			if !moduleDiags.HasErrors() {
+				r.Module = module
+			}
+		}
+	}
+
+	if r.Module == nil && !diags.HasErrors() {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Missing module block in %s block", r.Name),
+			Detail:   fmt.Sprintf("A %s block must contain a module block with the source of the module to apply.", r.Name),
+			Subject:  block.DefRange.Ptr(),
+		})
+	}
+
+	if attr, exists := content.Attributes["providers"]; exists {
+		providers, providerDiags := decodePassedProviderConfigs(attr)
+		diags = append(diags, providerDiags...)
+		r.Providers = append(r.Providers, providers...)
+	}
+
+	return &r, diags
+}
+
+// checkParallelRunReferences returns errors for any run blocks that refer to
+// the outputs of another run block in the same group of adjacent run blocks
+// marked as parallel, since those outputs may not be available yet.
+func checkParallelRunReferences(runs []*TestRun) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	for start := 0; start < len(runs); start++ {
+		if !runs[start].Parallel {
+			continue
+		}
+		end := start
+		group := make(map[string]bool)
+		for end < len(runs) && runs[end].Parallel {
+			group[runs[end].Name] = true
+			end++
+		}
+
+		for _, run := range runs[start:end] {
+			var exprs []hcl.Expression
+			for _, expr := range run.Variables {
+				exprs = append(exprs, expr)
+			}
+			for _, rule := range run.CheckRules {
+				exprs = append(exprs, rule.Condition, rule.ErrorMessage)
+			}
+
+			for _, expr := range exprs {
+				if expr == nil {
+					continue
+				}
+				for _, traversal := range expr.Variables() {
+					if traversal.RootName() != "run" || len(traversal) < 2 {
+						continue
+					}
+					attr, ok := traversal[1].(hcl.TraverseAttr)
+					if !ok || attr.Name == run.Name || !group[attr.Name] {
+						continue
+					}
+					diags = append(diags, &hcl.Diagnostic{
+						Severity: hcl.DiagError,
+						Summary:  "Invalid reference to parallel run block",
+						Detail:   fmt.Sprintf("The run block %q can't refer to the outputs of %q, because both are marked as parallel and may execute at the same time. Set parallel to false in one of them, or separate them with a run block that isn't parallel.", run.Name, attr.Name),
+						Subject:  traversal.SourceRange().Ptr(),
+					})
+				}
+			}
+		}
+
+		start = end
+	}
+
+	return diags
+}
+
+func decodeTestRunVariantsBlock(block *hcl.Block) ([]*TestRunVariant, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	attrs, attrsDiags := block.Body.JustAttributes()
+	diags = append(diags, attrsDiags...)
+
+	variants := make([]*TestRunVariant, 0, len(attrs))
+	for _, attr := range attrs {
+		variant := &TestRunVariant{
+			Name:      attr.Name,
+			DeclRange: attr.Range,
+		}
+
+		// The values can't refer to anything, since the run blocks are
+		// expanded when the test file is loaded.
+		val, valDiags := attr.Expr.Value(nil)
+		diags = append(diags, valDiags...)
+		if valDiags.HasErrors() {
+			continue
+		}
+
+		if !val.Type().IsTupleType() && !val.Type().IsListType() && !val.Type().IsSetType() || val.IsNull() || !val.IsWhollyKnown() || val.LengthInt() == 0 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid variant values",
+				Detail:   fmt.Sprintf("The variant %q must be a non-empty list of the values to execute the run block with.", attr.Name),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			continue
+		}
+
+		for it := val.ElementIterator(); it.Next(); {
+			_, v := it.Element()
+			variant.Values = append(variant.Values, v)
+		}
+		variants = append(variants, variant)
+	}
+
+	// Attributes are returned as a map, so we put them back in the order
+	// they are declared.
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].DeclRange.Start.Byte < variants[j].DeclRange.Start.Byte
+	})
+
+	if len(attrs) == 0 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Empty variants block",
+			Detail:   "A variants block must set at least one variable to a list of values.",
+			Subject:  block.DefRange.Ptr(),
+		})
+	}
+
+	return variants, diags
+}
+
+func decodeTestExpectedFailureBlock(block *hcl.Block) (*TestExpectedFailure, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := block.Body.Content(testExpectedFailureBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	failure := TestExpectedFailure{
+		DeclRange: block.DefRange,
+	}
+
+	if attr, exists := content.Attributes["object"]; exists {
+		traversal, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
+		diags = append(diags, traversalDiags...)
+		failure.Object = traversal
+	}
+
+	decodeRegexp := func(name string) *regexp.Regexp {
+		attr, exists := content.Attributes[name]
+		if !exists {
+			return nil
+		}
+
+		var raw string
+		rawDiags := gohcl.DecodeExpression(attr.Expr, nil, &raw)
+		diags = append(diags, rawDiags...)
+		if rawDiags.HasErrors() {
+			return nil
+		}
+
+		re, err := regexp.Compile(raw)
+		if err != nil {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid regular expression",
+				Detail:   fmt.Sprintf("The %s argument must be a valid regular expression: %s.", name, err),
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+			return nil
+		}
+		return re
+	}
+	failure.Summary = decodeRegexp("summary")
+	failure.Detail = decodeRegexp("detail")
+
+	if attr, exists := content.Attributes["filename"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &failure.Filename)...)
+	}
+	if attr, exists := content.Attributes["line"]; exists {
+		diags = append(diags, gohcl.DecodeExpression(attr.Expr, nil, &failure.Line)...)
+		if failure.Line < 1 {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid line number",
+				Detail:   "The line argument must be a positive line number.",
+				Subject:  attr.Expr.Range().Ptr(),
+			})
+		}
+	}
+
+	if len(content.Attributes) == 0 {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Empty expect_failure block",
+			Detail:   "An expect_failure block must set at least one of the object, summary, detail, filename, and line arguments, to describe the error that is expected.",
+			Subject:  block.DefRange.Ptr(),
+		})
+	}
+
+	return &failure, diags
+}
+
 func decodeTestRunModuleBlock(block *hcl.Block) (*TestRunModuleCall, hcl.Diagnostics) {
 	var diags hcl.Diagnostics
 
@@ -696,9 +1353,110 @@ func decodeOverrideResourceBlock(block *hcl.Block, mode addrs.ResourceMode) (*Ov
 		res.Values, diags = v, append(diags, moreDiags...)
 	}
 
+	if attr, exists := content.Attributes["generators"]; exists {
+		generators, moreDiags := decodeOverrideGenerators(attr)
+		diags = append(diags, moreDiags...)
+
+		for name := range generators {
+			if _, ok := res.Values[name]; ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Conflicting override field",
+					Detail:   fmt.Sprintf("The field `%v` is set in both `values` and `generators`. Remove it from one of them.", name),
+					Subject:  attr.Range.Ptr(),
+				})
+			}
+		}
+
+		if len(generators) > 0 {
+			res.Generators = &hcl2shim.MockValueGenerators{Attributes: generators}
+		}
+	}
+
 	return res, diags
 }
 
+// decodeOverrideGenerators decodes the generators attribute of an
+// override_resource or override_data block. Each field is either the name of
+// a generator, or an object with the name of the generator in its type
+// attribute and any of its options.
+func decodeOverrideGenerators(attr *hcl.Attribute) (map[string]*hcl2shim.MockValueGenerator, hcl.Diagnostics) {
+	fields, diags := parseObjectAttrWithNoVariables(attr)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	invalid := func(name, detail string) {
+		diags = append(diags, &hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid generator",
+			Detail:   fmt.Sprintf("Invalid generator for `%v`: %s", name, detail),
+			Subject:  attr.Expr.Range().Ptr(),
+		})
+	}
+
+	generators := make(map[string]*hcl2shim.MockValueGenerator)
+	for name, val := range fields {
+		gen := &hcl2shim.MockValueGenerator{}
+
+		var options map[string]cty.Value
+		switch {
+		case val.IsNull() || !val.IsKnown():
+			invalid(name, "the generator must not be null.")
+			continue
+		case val.Type() == cty.String:
+			options = map[string]cty.Value{"type": val}
+		case val.Type().IsObjectType():
+			options = val.AsValueMap()
+		default:
+			invalid(name, "the generator must be the name of a generator, or an object with a `type` attribute.")
+			continue
+		}
+
+		valid := true
+		for key, opt := range options {
+			var err error
+			switch key {
+			case "type":
+				var kind string
+				err = gocty.FromCtyValue(opt, &kind)
+				gen.Kind = hcl2shim.MockValueGeneratorKind(kind)
+			case "prefix":
+				err = gocty.FromCtyValue(opt, &gen.Prefix)
+			case "length":
+				err = gocty.FromCtyValue(opt, &gen.Length)
+				if err == nil && gen.Length < 1 {
+					err = fmt.Errorf("must be at least 1")
+				}
+			case "region":
+				err = gocty.FromCtyValue(opt, &gen.Region)
+			case "account":
+				err = gocty.FromCtyValue(opt, &gen.Account)
+			default:
+				invalid(name, fmt.Sprintf("unsupported option `%v`. The options are type, prefix, length, region, and account.", key))
+				valid = false
+				continue
+			}
+			if err != nil {
+				invalid(name, fmt.Sprintf("the `%v` option is invalid: %v.", key, tfdiags.FormatError(err)))
+				valid = false
+			}
+		}
+		if !valid {
+			continue
+		}
+
+		if !slices.Contains(hcl2shim.MockValueGeneratorKinds, gen.Kind) {
+			invalid(name, fmt.Sprintf("the generator type must be one of %v.", hcl2shim.MockValueGeneratorKinds))
+			continue
+		}
+
+		generators[name] = gen
+	}
+
+	return generators, diags
+}
+
 func decodeOverrideModuleBlock(block *hcl.Block) (*OverrideModule, hcl.Diagnostics) {
 	parseTarget := func(attr *hcl.Attribute) (hcl.Traversal, addrs.Module, hcl.Diagnostics) {
 		traversal, traversalDiags := hcl.AbsTraversalForExpr(attr.Expr)
@@ -800,6 +1558,9 @@ func checkForDuplicatedOverrideModules(modules []*OverrideModule) hcl.Diagnostic
 }
 
 var testFileSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "imports"},
+	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
 			Type:       "run",
@@ -812,6 +1573,12 @@ var testFileSchema = &hcl.BodySchema{
 		{
 			Type: "variables",
 		},
+		{
+			Type: "setup",
+		},
+		{
+			Type: "teardown",
+		},
 		{
 			Type: blockNameOverrideResource,
 		},
@@ -821,6 +1588,27 @@ var testFileSchema = &hcl.BodySchema{
 		{
 			Type: blockNameOverrideModule,
 		},
+		{
+			Type:       "assertions",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type: "before_each",
+		},
+	},
+}
+
+var testSetupBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "providers"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "variables",
+		},
+		{
+			Type: "module",
+		},
 	},
 }
 
@@ -829,6 +1617,11 @@ var testRunBlockSchema = &hcl.BodySchema{
 		{Name: "command"},
 		{Name: "providers"},
 		{Name: "expect_failures"},
+		{Name: "parallel"},
+		{Name: "expect_no_changes"},
+		{Name: "assertions"},
+		{Name: "retries"},
+		{Name: "retry_delay"},
 	},
 	Blocks: []hcl.BlockHeaderSchema{
 		{
@@ -837,6 +1630,16 @@ var testRunBlockSchema = &hcl.BodySchema{
 		{
 			Type: "assert",
 		},
+		{
+			Type: "expect_failure",
+		},
+		{
+			Type:       "invoke",
+			LabelNames: []string{"type"},
+		},
+		{
+			Type: "variants",
+		},
 		{
 			Type: "variables",
 		},
@@ -864,6 +1667,16 @@ var testRunOptionsBlockSchema = &hcl.BodySchema{
 	},
 }
 
+var testExpectedFailureBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "object"},
+		{Name: "summary"},
+		{Name: "detail"},
+		{Name: "filename"},
+		{Name: "line"},
+	},
+}
+
 var testRunModuleBlockSchema = &hcl.BodySchema{
 	Attributes: []hcl.AttributeSchema{
 		{Name: "source"},
@@ -882,6 +1695,10 @@ var overrideResourceBlockSchema = &hcl.BodySchema{
 			Name:     "values",
 			Required: false,
 		},
+		{
+			Name:     "generators",
+			Required: false,
+		},
 	},
 }
 
@@ -6,11 +6,17 @@
 package configs
 
 import (
+	"fmt"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/opentofu/opentofu/internal/configs/hcl2shim"
 )
 
 func TestTestRun_Validate(t *testing.T) {
@@ -97,3 +103,691 @@ func parseTraversal(t *testing.T, addr string) hcl.Traversal {
 	}
 	return traversal
 }
+
+func TestLoadTestFile_parallel(t *testing.T) {
+	tcs := map[string]struct {
+		src      string
+		parallel []bool
+		diags    []string
+	}{
+		"default": {
+			src: `
+run "a" {}
+`,
+			parallel: []bool{false},
+		},
+		"independent": {
+			src: `
+run "a" {
+  parallel = true
+}
+
+run "b" {
+  parallel = true
+
+  module {
+    source = "./setup"
+  }
+}
+`,
+			parallel: []bool{true, true},
+		},
+		"reference within group": {
+			src: `
+run "a" {
+  parallel = true
+}
+
+run "b" {
+  parallel = true
+
+  variables {
+    input = run.a.output
+  }
+}
+`,
+			parallel: []bool{true, true},
+			diags: []string{
+				`The run block "b" can't refer to the outputs of "a", because both are marked as parallel and may execute at the same time. Set parallel to false in one of them, or separate them with a run block that isn't parallel.`,
+			},
+		},
+		"reference in assertion": {
+			src: `
+run "a" {
+  parallel = true
+}
+
+run "b" {
+  parallel = true
+
+  assert {
+    condition     = run.a.output == "foo"
+    error_message = "wrong output"
+  }
+}
+`,
+			parallel: []bool{true, true},
+			diags: []string{
+				`The run block "b" can't refer to the outputs of "a", because both are marked as parallel and may execute at the same time. Set parallel to false in one of them, or separate them with a run block that isn't parallel.`,
+			},
+		},
+		"reference across groups": {
+			src: `
+run "a" {
+  parallel = true
+}
+
+run "b" {}
+
+run "c" {
+  parallel = true
+
+  variables {
+    input = run.a.output
+  }
+}
+`,
+			parallel: []bool{true, false, true},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			file, diags := loadTestFile(f.Body)
+
+			var gotDiags []string
+			for _, diag := range diags {
+				gotDiags = append(gotDiags, diag.Detail)
+			}
+			if diff := cmp.Diff(tc.diags, gotDiags); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+
+			var gotParallel []bool
+			for _, run := range file.Runs {
+				gotParallel = append(gotParallel, run.Parallel)
+			}
+			if diff := cmp.Diff(tc.parallel, gotParallel); diff != "" {
+				t.Errorf("wrong parallel settings\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_retries(t *testing.T) {
+	tcs := map[string]struct {
+		src     string
+		retries int
+		delay   time.Duration
+		diags   []string
+	}{
+		"default": {
+			src: `
+run "a" {}
+`,
+			delay: DefaultTestRetryDelay,
+		},
+		"retries": {
+			src: `
+run "a" {
+  retries = 3
+}
+`,
+			retries: 3,
+			delay:   DefaultTestRetryDelay,
+		},
+		"retry delay": {
+			src: `
+run "a" {
+  retries     = 2
+  retry_delay = "250ms"
+}
+`,
+			retries: 2,
+			delay:   250 * time.Millisecond,
+		},
+		"invalid": {
+			src: `
+run "a" {
+  retries     = -1
+  retry_delay = "soon"
+}
+`,
+			diags: []string{
+				`The "retries" argument must be zero or a positive whole number.`,
+				`The "retry_delay" argument must be a duration, such as "500ms" or "10s".`,
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			file, diags := loadTestFile(f.Body)
+
+			var gotDiags []string
+			for _, diag := range diags {
+				gotDiags = append(gotDiags, diag.Detail)
+			}
+			if diff := cmp.Diff(tc.diags, gotDiags); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+
+			if len(tc.diags) > 0 {
+				// The run block isn't loaded if it has errors.
+				return
+			}
+
+			run := file.Runs[0]
+			if run.Retries != tc.retries {
+				t.Errorf("wrong retries %d, want %d", run.Retries, tc.retries)
+			}
+			if run.RetryDelay != tc.delay {
+				t.Errorf("wrong retry delay %s, want %s", run.RetryDelay, tc.delay)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_invoke(t *testing.T) {
+	tcs := map[string]struct {
+		src   string
+		types []string
+		diags []string
+	}{
+		"none": {
+			src: `
+run "a" {}
+`,
+		},
+		"invocations": {
+			src: `
+run "a" {
+  invoke "test_reboot" {
+    id = test_instance.foo.id
+  }
+
+  invoke "test_rotate_key" {}
+}
+`,
+			types: []string{"test_reboot", "test_rotate_key"},
+		},
+		"plan": {
+			src: `
+run "a" {
+  command = plan
+
+  invoke "test_reboot" {}
+}
+`,
+			diags: []string{
+				"Actions can only be invoked by run blocks that apply the configuration, with command = apply.",
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			file, diags := loadTestFile(f.Body)
+
+			var gotDiags []string
+			for _, diag := range diags {
+				gotDiags = append(gotDiags, diag.Detail)
+			}
+			if diff := cmp.Diff(tc.diags, gotDiags); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+
+			if len(tc.diags) > 0 {
+				// The run block isn't loaded if it has errors.
+				return
+			}
+
+			var gotTypes []string
+			for _, inv := range file.Runs[0].Invocations {
+				gotTypes = append(gotTypes, inv.Type)
+			}
+			if diff := cmp.Diff(tc.types, gotTypes); diff != "" {
+				t.Errorf("wrong invocations\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_setupTeardown(t *testing.T) {
+	tcs := map[string]struct {
+		src      string
+		setup    string
+		teardown string
+		runs     []string
+		diags    []string
+	}{
+		"setup and teardown": {
+			src: `
+setup {
+  module {
+    source = "./fixtures"
+  }
+
+  variables {
+    name = "foo"
+  }
+}
+
+run "test" {
+  variables {
+    id = run.setup.id
+  }
+}
+
+teardown {
+  module {
+    source = "./cleanup"
+  }
+}
+`,
+			setup:    "./fixtures",
+			teardown: "./cleanup",
+			runs:     []string{"setup", "test", "teardown"},
+		},
+		"missing module": {
+			src: `
+setup {}
+`,
+			diags: []string{
+				"A setup block must contain a module block with the source of the module to apply.",
+			},
+		},
+		"duplicate": {
+			src: `
+teardown {
+  module {
+    source = "./cleanup"
+  }
+}
+
+teardown {
+  module {
+    source = "./cleanup"
+  }
+}
+`,
+			teardown: "./cleanup",
+			runs:     []string{"teardown"},
+			diags: []string{
+				"This test file already has a teardown block defined at main.tftest.hcl:2,1-9.",
+			},
+		},
+		"reserved name": {
+			src: `
+setup {
+  module {
+    source = "./fixtures"
+  }
+}
+
+run "setup" {}
+`,
+			setup: "./fixtures",
+			runs:  []string{"setup", "setup"},
+			diags: []string{
+				`The name "setup" refers to the setup block in this test file, so it can't also be used for a run block.`,
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			file, diags := loadTestFile(f.Body)
+
+			var gotDiags []string
+			for _, diag := range diags {
+				gotDiags = append(gotDiags, diag.Detail)
+			}
+			if diff := cmp.Diff(tc.diags, gotDiags); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+
+			source := func(run *TestRun) string {
+				if run == nil {
+					return ""
+				}
+				if run.Command != ApplyTestCommand {
+					t.Errorf("wrong command for %s: %v", run.Name, run.Command)
+				}
+				return run.Module.Source.String()
+			}
+			if got := source(file.Setup); got != tc.setup {
+				t.Errorf("wrong setup module %q, want %q", got, tc.setup)
+			}
+			if got := source(file.Teardown); got != tc.teardown {
+				t.Errorf("wrong teardown module %q, want %q", got, tc.teardown)
+			}
+
+			var gotRuns []string
+			for _, run := range file.AllRuns() {
+				gotRuns = append(gotRuns, run.Name)
+			}
+			if diff := cmp.Diff(tc.runs, gotRuns); diff != "" {
+				t.Errorf("wrong runs\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_expectFailure(t *testing.T) {
+	tcs := map[string]struct {
+		src   string
+		want  []string
+		diags []string
+	}{
+		"all arguments": {
+			src: `
+run "test" {
+  expect_failure {
+    object   = var.input
+    summary  = "^Invalid value for variable$"
+    detail   = "must not be empty"
+    filename = "main.tf"
+    line     = 3
+  }
+
+  expect_failure {
+    detail = "bad"
+  }
+}
+`,
+			want: []string{
+				`var.input ^Invalid value for variable$ must not be empty main.tf:3`,
+				`  bad :0`,
+			},
+		},
+		"invalid regexp": {
+			src: `
+run "test" {
+  expect_failure {
+    summary = "("
+  }
+}
+`,
+			diags: []string{
+				"The summary argument must be a valid regular expression: error parsing regexp: missing closing ): `(`.",
+			},
+		},
+		"empty": {
+			src: `
+run "test" {
+  expect_failure {}
+}
+`,
+			diags: []string{
+				"An expect_failure block must set at least one of the object, summary, detail, filename, and line arguments, to describe the error that is expected.",
+			},
+		},
+		"invalid line": {
+			src: `
+run "test" {
+  expect_failure {
+    line = 0
+  }
+}
+`,
+			diags: []string{
+				"The line argument must be a positive line number.",
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			file, diags := loadTestFile(f.Body)
+
+			var gotDiags []string
+			for _, diag := range diags {
+				gotDiags = append(gotDiags, diag.Detail)
+			}
+			if diff := cmp.Diff(tc.diags, gotDiags); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+
+			var got []string
+			if len(file.Runs) == 0 {
+				// The run block isn't kept if it has errors.
+				return
+			}
+			for _, failure := range file.Runs[0].ExpectedFailures {
+				var object, summary, detail string
+				if failure.Object != nil {
+					object = string(hclwrite.TokensForTraversal(failure.Object).Bytes())
+				}
+				if failure.Summary != nil {
+					summary = failure.Summary.String()
+				}
+				if failure.Detail != nil {
+					detail = failure.Detail.String()
+				}
+				got = append(got, fmt.Sprintf("%s %s %s %s:%d", object, summary, detail, failure.Filename, failure.Line))
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("wrong expected failures\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_variants(t *testing.T) {
+	tcs := map[string]struct {
+		src   string
+		want  []string
+		diags []string
+	}{
+		"matrix": {
+			src: `
+run "first" {}
+
+run "matrix" {
+  variants {
+    region = ["us-east-1", "eu-west-1"]
+    size   = [1, 2]
+  }
+
+  variables {
+    name = "foo"
+  }
+}
+
+run "last" {}
+`,
+			want: []string{
+				"first",
+				`matrix[region=us-east-1,size=1] name="foo" region="us-east-1" size=1`,
+				`matrix[region=us-east-1,size=2] name="foo" region="us-east-1" size=2`,
+				`matrix[region=eu-west-1,size=1] name="foo" region="eu-west-1" size=1`,
+				`matrix[region=eu-west-1,size=2] name="foo" region="eu-west-1" size=2`,
+				"last",
+			},
+		},
+		"conflict": {
+			src: `
+run "matrix" {
+  variants {
+    region = ["us-east-1"]
+  }
+
+  variables {
+    region = "eu-west-1"
+  }
+}
+`,
+			diags: []string{
+				`The variable "region" is set by both the variants block and the variables block of this run block. Remove it from one of them.`,
+			},
+		},
+		"invalid values": {
+			src: `
+run "matrix" {
+  variants {
+    region = "us-east-1"
+    size   = []
+  }
+}
+`,
+			diags: []string{
+				`The variant "region" must be a non-empty list of the values to execute the run block with.`,
+				`The variant "size" must be a non-empty list of the values to execute the run block with.`,
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			file, diags := loadTestFile(f.Body)
+
+			var gotDiags []string
+			for _, diag := range diags {
+				gotDiags = append(gotDiags, diag.Detail)
+			}
+			sort.Strings(gotDiags)
+			if diff := cmp.Diff(tc.diags, gotDiags); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+
+			var got []string
+			for _, run := range file.Runs {
+				var names []string
+				for name := range run.Variables {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				desc := run.Name
+				for _, name := range names {
+					val, diags := run.Variables[name].Value(nil)
+					if diags.HasErrors() {
+						t.Fatal(diags.Error())
+					}
+					desc += fmt.Sprintf(" %s=%s", name, hclwrite.TokensForValue(val).Bytes())
+				}
+				got = append(got, desc)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("wrong runs\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLoadTestFile_overrideGenerators(t *testing.T) {
+	tcs := map[string]struct {
+		src   string
+		want  map[string]*hcl2shim.MockValueGenerator
+		diags []string
+	}{
+		"valid": {
+			src: `
+override_resource {
+  target = aws_s3_bucket.logs
+  values = {
+    region = "eu-west-1"
+  }
+  generators = {
+    id     = "sequence"
+    name   = { type = "string", prefix = "logs-", length = 8 }
+    arn    = { type = "arn", region = "eu-west-1", account = "000000000000" }
+    create = "timestamp"
+  }
+}
+`,
+			want: map[string]*hcl2shim.MockValueGenerator{
+				"id":     {Kind: hcl2shim.MockSequence},
+				"name":   {Kind: hcl2shim.MockString, Prefix: "logs-", Length: 8},
+				"arn":    {Kind: hcl2shim.MockARN, Region: "eu-west-1", Account: "000000000000"},
+				"create": {Kind: hcl2shim.MockTimestamp},
+			},
+		},
+		"invalid": {
+			src: `
+override_resource {
+  target = aws_s3_bucket.logs
+  values = {
+    id = "logs"
+  }
+  generators = {
+    id      = "sequence"
+    name    = "uuid"
+    arn     = { type = "arn", partition = "aws" }
+    length  = { type = "string", length = 0 }
+    enabled = true
+  }
+}
+`,
+			diags: []string{
+				"Invalid generator for `arn`: unsupported option `partition`. The options are type, prefix, length, region, and account.",
+				"Invalid generator for `enabled`: the generator must be the name of a generator, or an object with a `type` attribute.",
+				"Invalid generator for `length`: the `length` option is invalid: must be at least 1.",
+				"Invalid generator for `name`: the generator type must be one of [sequence string timestamp arn].",
+				"The field `id` is set in both `values` and `generators`. Remove it from one of them.",
+			},
+		},
+	}
+
+	for name, tc := range tcs {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			f, diags := hclsyntax.ParseConfig([]byte(tc.src), "main.tftest.hcl", hcl.InitialPos)
+			if diags.HasErrors() {
+				t.Fatal(diags.Error())
+			}
+
+			file, diags := loadTestFile(f.Body)
+
+			var gotDiags []string
+			for _, diag := range diags {
+				gotDiags = append(gotDiags, diag.Detail)
+			}
+			sort.Strings(gotDiags)
+			if diff := cmp.Diff(tc.diags, gotDiags); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+
+			if tc.want == nil {
+				return
+			}
+			if len(file.OverrideResources) != 1 || file.OverrideResources[0].Generators == nil {
+				t.Fatalf("expected an override_resource block with generators")
+			}
+			if diff := cmp.Diff(tc.want, file.OverrideResources[0].Generators.Attributes); diff != "" {
+				t.Errorf("wrong generators\n%s", diff)
+			}
+		})
+	}
+}
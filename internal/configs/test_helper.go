@@ -0,0 +1,488 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package configs
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+// TestHelperDirectory is the directory, within the test directory, that test
+// files import their helpers from.
+const TestHelperDirectory = "lib"
+
+// TestHelper is a file of shared definitions that test files can import, from
+// the lib directory within the test directory. The definitions of a helper
+// apply to the test files that import it as if they were declared in them,
+// except that the test files' own definitions take precedence.
+type TestHelper struct {
+	// Variables are added to the global variables of the test file.
+	Variables map[string]hcl.Expression
+
+	// Providers are added to the providers of the test file.
+	Providers map[string]*Provider
+
+	// OverrideResources and OverrideModules are added to the ones declared
+	// globally in the test file.
+	OverrideResources []*OverrideResource
+	OverrideModules   []*OverrideModule
+
+	// Assertions are the named groups of assertions that the run blocks of
+	// the test file can include.
+	Assertions map[string]*TestAssertions
+}
+
+// TestImport is a single entry of the imports attribute of a test file,
+// naming a helper file.
+type TestImport struct {
+	Name string
+
+	DeclRange hcl.Range
+}
+
+// TestAssertions is a named group of assertions, declared with an assertions
+// block in a test file or a helper, which run blocks can include with their
+// assertions attribute instead of repeating the assert blocks.
+type TestAssertions struct {
+	Name       string
+	CheckRules []*CheckRule
+
+	DeclRange hcl.Range
+}
+
+// TestAssertionsRef is a reference to a TestAssertions from the assertions
+// attribute of a run block or of the before_each block.
+type TestAssertionsRef struct {
+	Name string
+
+	DeclRange hcl.Range
+}
+
+// TestBeforeEach is the before_each block of a test file, which declares
+// definitions that apply to every run block in the file. The run blocks' own
+// definitions take precedence.
+type TestBeforeEach struct {
+	Variables         map[string]hcl.Expression
+	CheckRules        []*CheckRule
+	Assertions        []TestAssertionsRef
+	OverrideResources []*OverrideResource
+	OverrideModules   []*OverrideModule
+
+	DeclRange hcl.Range
+}
+
+// loadTestHelpers loads the helpers that the given test file imports from the
+// given directory. The helpers are cached by path in the given map, so that
+// each helper is only loaded, and its diagnostics reported, once.
+func (p *Parser) loadTestHelpers(dir string, imports []TestImport, cache map[string]*TestHelper) ([]*TestHelper, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	var helpers []*TestHelper
+	for _, imp := range imports {
+		if !filepath.IsLocal(imp.Name) {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid test helper import",
+				Detail:   fmt.Sprintf("The test helper %q must be a path within the %s directory of the test directory.", imp.Name, TestHelperDirectory),
+				Subject:  imp.DeclRange.Ptr(),
+			})
+			continue
+		}
+
+		var path string
+		for _, ext := range []string{tfTestExt, tfTestJSONExt} {
+			candidate := filepath.Join(dir, imp.Name+ext)
+			if _, err := p.fs.Stat(candidate); err == nil {
+				path = candidate
+				break
+			}
+		}
+		if path == "" {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Test helper not found",
+				Detail:   fmt.Sprintf("There is no test helper %q. Test helpers are loaded from files named like %s within the %s directory.", imp.Name, filepath.Join(dir, imp.Name+tfTestExt), TestHelperDirectory),
+				Subject:  imp.DeclRange.Ptr(),
+			})
+			continue
+		}
+
+		helper, ok := cache[path]
+		if !ok {
+			body, loadDiags := p.LoadHCLFile(path)
+			diags = append(diags, loadDiags...)
+			if body != nil {
+				var helperDiags hcl.Diagnostics
+				helper, helperDiags = loadTestHelper(body)
+				diags = append(diags, helperDiags...)
+			}
+			cache[path] = helper
+		}
+		if helper != nil {
+			helpers = append(helpers, helper)
+		}
+	}
+
+	return helpers, diags
+}
+
+func loadTestHelper(body hcl.Body) (*TestHelper, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := body.Content(testHelperSchema)
+	diags = append(diags, contentDiags...)
+
+	helper := &TestHelper{
+		Variables:  make(map[string]hcl.Expression),
+		Providers:  make(map[string]*Provider),
+		Assertions: make(map[string]*TestAssertions),
+	}
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "variables":
+			vars, varsDiags := block.Body.JustAttributes()
+			diags = append(diags, varsDiags...)
+			for _, v := range vars {
+				helper.Variables[v.Name] = v.Expr
+			}
+
+		case "provider":
+			provider, providerDiags := decodeProviderBlock(block)
+			diags = append(diags, providerDiags...)
+			if provider != nil {
+				helper.Providers[provider.moduleUniqueKey()] = provider
+			}
+
+		case "assertions":
+			assertions, assertionsDiags := decodeTestAssertionsBlock(block)
+			diags = append(diags, assertionsDiags...)
+			diags = append(diags, addTestAssertions(helper.Assertions, assertions)...)
+
+		case blockNameOverrideResource, blockNameOverrideData:
+			mode := addrs.ManagedResourceMode
+			if block.Type == blockNameOverrideData {
+				mode = addrs.DataResourceMode
+			}
+			overrideRes, overrideResDiags := decodeOverrideResourceBlock(block, mode)
+			diags = append(diags, overrideResDiags...)
+			if !overrideResDiags.HasErrors() {
+				helper.OverrideResources = append(helper.OverrideResources, overrideRes)
+			}
+
+		case blockNameOverrideModule:
+			overrideMod, overrideModDiags := decodeOverrideModuleBlock(block)
+			diags = append(diags, overrideModDiags...)
+			if !overrideModDiags.HasErrors() {
+				helper.OverrideModules = append(helper.OverrideModules, overrideMod)
+			}
+		}
+	}
+
+	return helper, diags
+}
+
+func decodeTestAssertionsBlock(block *hcl.Block) (*TestAssertions, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := block.Body.Content(testAssertionsBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	assertions := &TestAssertions{
+		Name:      block.Labels[0],
+		DeclRange: block.DefRange,
+	}
+	for _, block := range content.Blocks {
+		cr, crDiags := decodeCheckRuleBlock(block, false)
+		diags = append(diags, crDiags...)
+		if !crDiags.HasErrors() {
+			assertions.CheckRules = append(assertions.CheckRules, cr)
+		}
+	}
+	return assertions, diags
+}
+
+func addTestAssertions(all map[string]*TestAssertions, assertions *TestAssertions) hcl.Diagnostics {
+	if existing, ok := all[assertions.Name]; ok {
+		return hcl.Diagnostics{{
+			Severity: hcl.DiagError,
+			Summary:  "Duplicate assertions block",
+			Detail:   fmt.Sprintf("An assertions block named %q was already declared at %s.", assertions.Name, existing.DeclRange),
+			Subject:  assertions.DeclRange.Ptr(),
+		}}
+	}
+	all[assertions.Name] = assertions
+	return nil
+}
+
+func decodeTestBeforeEachBlock(block *hcl.Block) (*TestBeforeEach, hcl.Diagnostics) {
+	var diags hcl.Diagnostics
+
+	content, contentDiags := block.Body.Content(testBeforeEachBlockSchema)
+	diags = append(diags, contentDiags...)
+
+	beforeEach := &TestBeforeEach{
+		Variables: make(map[string]hcl.Expression),
+		DeclRange: block.DefRange,
+	}
+
+	if attr, exists := content.Attributes["assertions"]; exists {
+		refs, refsDiags := decodeTestAssertionsRefs(attr)
+		diags = append(diags, refsDiags...)
+		beforeEach.Assertions = refs
+	}
+
+	for _, block := range content.Blocks {
+		switch block.Type {
+		case "variables":
+			vars, varsDiags := block.Body.JustAttributes()
+			diags = append(diags, varsDiags...)
+			for _, v := range vars {
+				beforeEach.Variables[v.Name] = v.Expr
+			}
+
+		case "assert":
+			cr, crDiags := decodeCheckRuleBlock(block, false)
+			diags = append(diags, crDiags...)
+			if !crDiags.HasErrors() {
+				beforeEach.CheckRules = append(beforeEach.CheckRules, cr)
+			}
+
+		case blockNameOverrideResource, blockNameOverrideData:
+			mode := addrs.ManagedResourceMode
+			if block.Type == blockNameOverrideData {
+				mode = addrs.DataResourceMode
+			}
+			overrideRes, overrideResDiags := decodeOverrideResourceBlock(block, mode)
+			diags = append(diags, overrideResDiags...)
+			if !overrideResDiags.HasErrors() {
+				beforeEach.OverrideResources = append(beforeEach.OverrideResources, overrideRes)
+			}
+
+		case blockNameOverrideModule:
+			overrideMod, overrideModDiags := decodeOverrideModuleBlock(block)
+			diags = append(diags, overrideModDiags...)
+			if !overrideModDiags.HasErrors() {
+				beforeEach.OverrideModules = append(beforeEach.OverrideModules, overrideMod)
+			}
+		}
+	}
+
+	return beforeEach, diags
+}
+
+// decodeTestImports decodes the imports attribute of a test file, which is a
+// list of the names of helper files.
+func decodeTestImports(attr *hcl.Attribute) ([]TestImport, hcl.Diagnostics) {
+	var names []string
+	diags := gohcl.DecodeExpression(attr.Expr, nil, &names)
+
+	var imports []TestImport
+	for _, name := range names {
+		imports = append(imports, TestImport{Name: name, DeclRange: attr.Expr.Range()})
+	}
+	return imports, diags
+}
+
+// decodeTestAssertionsRefs decodes the assertions attribute of a run block or
+// of the before_each block, which is a list of the names of assertions
+// blocks.
+func decodeTestAssertionsRefs(attr *hcl.Attribute) ([]TestAssertionsRef, hcl.Diagnostics) {
+	var names []string
+	diags := gohcl.DecodeExpression(attr.Expr, nil, &names)
+
+	var refs []TestAssertionsRef
+	for _, name := range names {
+		refs = append(refs, TestAssertionsRef{Name: name, DeclRange: attr.Expr.Range()})
+	}
+	return refs, diags
+}
+
+// resolveHelpers adds the definitions of the given imported helpers to the
+// test file, and then applies the before_each block and the assertions that
+// are included by name to each of its run blocks.
+func (file *TestFile) resolveHelpers(helpers []*TestHelper) hcl.Diagnostics {
+	var diags hcl.Diagnostics
+
+	assertions := make(map[string]*TestAssertions)
+	for name, a := range file.Assertions {
+		assertions[name] = a
+	}
+
+	for _, helper := range helpers {
+		for name, expr := range helper.Variables {
+			if file.Variables == nil {
+				file.Variables = make(map[string]hcl.Expression)
+			}
+			if _, exists := file.Variables[name]; !exists {
+				file.Variables[name] = expr
+			}
+		}
+		for key, provider := range helper.Providers {
+			if _, exists := file.Providers[key]; !exists {
+				file.Providers[key] = provider
+			}
+		}
+		file.OverrideResources = appendOverrideResources(file.OverrideResources, helper.OverrideResources)
+		file.OverrideModules = appendOverrideModules(file.OverrideModules, helper.OverrideModules)
+
+		for name, a := range helper.Assertions {
+			if _, exists := file.Assertions[name]; exists {
+				// The test file's own assertions take precedence.
+				continue
+			}
+			diags = append(diags, addTestAssertions(assertions, a)...)
+		}
+	}
+
+	resolve := func(refs []TestAssertionsRef) []*CheckRule {
+		var rules []*CheckRule
+		for _, ref := range refs {
+			a, ok := assertions[ref.Name]
+			if !ok {
+				diags = append(diags, &hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Unknown assertions",
+					Detail:   fmt.Sprintf("There is no assertions block named %q in this test file or the helpers it imports.", ref.Name),
+					Subject:  ref.DeclRange.Ptr(),
+				})
+				continue
+			}
+			rules = append(rules, a.CheckRules...)
+		}
+		return rules
+	}
+
+	var beforeEachRules []*CheckRule
+	if file.BeforeEach != nil {
+		beforeEachRules = append(beforeEachRules, file.BeforeEach.CheckRules...)
+		beforeEachRules = append(beforeEachRules, resolve(file.BeforeEach.Assertions)...)
+	}
+
+	for _, run := range file.Runs {
+		// Run blocks expanded from the same variants block share their
+		// slices, so we always build new ones here.
+		var rules []*CheckRule
+		rules = append(rules, beforeEachRules...)
+		rules = append(rules, run.CheckRules...)
+		rules = append(rules, resolve(run.Assertions)...)
+		run.CheckRules = rules
+
+		if file.BeforeEach == nil {
+			continue
+		}
+
+		variables := make(map[string]hcl.Expression, len(run.Variables)+len(file.BeforeEach.Variables))
+		for name, expr := range file.BeforeEach.Variables {
+			variables[name] = expr
+		}
+		for name, expr := range run.Variables {
+			variables[name] = expr
+		}
+		run.Variables = variables
+
+		run.OverrideResources = appendOverrideResources(run.OverrideResources[:len(run.OverrideResources):len(run.OverrideResources)], file.BeforeEach.OverrideResources)
+		run.OverrideModules = appendOverrideModules(run.OverrideModules[:len(run.OverrideModules):len(run.OverrideModules)], file.BeforeEach.OverrideModules)
+	}
+
+	return diags
+}
+
+// appendOverrideResources appends the given extra overrides to the given
+// overrides, leaving out any for a target that is already overridden.
+func appendOverrideResources(overrides, extra []*OverrideResource) []*OverrideResource {
+	targets := make(map[string]bool)
+	for _, o := range overrides {
+		targets[o.TargetParsed.String()] = true
+	}
+	for _, o := range extra {
+		if !targets[o.TargetParsed.String()] {
+			overrides = append(overrides, o)
+		}
+	}
+	return overrides
+}
+
+// appendOverrideModules is like appendOverrideResources, for modules.
+func appendOverrideModules(overrides, extra []*OverrideModule) []*OverrideModule {
+	targets := make(map[string]bool)
+	for _, o := range overrides {
+		targets[o.TargetParsed.String()] = true
+	}
+	for _, o := range extra {
+		if !targets[o.TargetParsed.String()] {
+			overrides = append(overrides, o)
+		}
+	}
+	return overrides
+}
+
+// testHelperDirectory returns the directory that the test files of the module
+// in the given directory import their helpers from.
+func testHelperDirectory(moduleDir, testDirectory string) string {
+	return filepath.Join(moduleDir, testDirectory, TestHelperDirectory)
+}
+
+var testHelperSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type:       "provider",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type: "variables",
+		},
+		{
+			Type:       "assertions",
+			LabelNames: []string{"name"},
+		},
+		{
+			Type: blockNameOverrideResource,
+		},
+		{
+			Type: blockNameOverrideData,
+		},
+		{
+			Type: blockNameOverrideModule,
+		},
+	},
+}
+
+var testAssertionsBlockSchema = &hcl.BodySchema{
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "assert",
+		},
+	},
+}
+
+var testBeforeEachBlockSchema = &hcl.BodySchema{
+	Attributes: []hcl.AttributeSchema{
+		{Name: "assertions"},
+	},
+	Blocks: []hcl.BlockHeaderSchema{
+		{
+			Type: "variables",
+		},
+		{
+			Type: "assert",
+		},
+		{
+			Type: blockNameOverrideResource,
+		},
+		{
+			Type: blockNameOverrideData,
+		},
+		{
+			Type: blockNameOverrideModule,
+		},
+	},
+}
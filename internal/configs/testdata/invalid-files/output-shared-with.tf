@@ -0,0 +1,4 @@
+output "shared_pizza" {
+  value       = "🍕"
+  shared_with = ["app-["]
+}
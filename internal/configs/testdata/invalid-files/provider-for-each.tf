@@ -0,0 +1,33 @@
+provider "aws" {
+  for_each = toset(["a", "b"]) # ERROR: Missing alias for provider configuration with for_each
+}
+
+provider "aws" {
+  alias    = "regions"
+  for_each = toset(["a", "b"])
+}
+
+provider "aws" {
+  alias    = "null"
+  for_each = null # ERROR: Invalid for_each argument
+}
+
+resource "aws_instance" "missing_key" {
+  provider = aws.regions # ERROR: Missing provider instance key
+}
+
+resource "aws_instance" "unexpected_key" {
+  provider = aws.other["a"] # ERROR: Invalid provider instance key
+}
+
+module "child" {
+  source = "./child"
+  providers = {
+    aws = aws.regions["a"] # ERROR: Invalid provider configuration for module
+  }
+}
+
+provider "aws" {
+  alias    = "ephemeral"
+  for_each = toset([nonsensitive(ed25519_keypair().public_key_openssh)]) # ERROR: Invalid for_each argument
+}
@@ -0,0 +1,7 @@
+resource "example" "example" {
+  lifecycle {
+    create_before_destroy     = true
+    two_phase_replace         = true
+    two_phase_replace_timeout = "0s"
+  }
+}
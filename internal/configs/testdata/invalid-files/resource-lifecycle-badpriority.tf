@@ -0,0 +1,5 @@
+resource "example" "example" {
+  lifecycle {
+    priority = "as soon as possible"
+  }
+}
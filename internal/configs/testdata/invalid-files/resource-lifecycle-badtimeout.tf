@@ -0,0 +1,5 @@
+resource "example" "example" {
+  lifecycle {
+    operation_timeout = "a while"
+  }
+}
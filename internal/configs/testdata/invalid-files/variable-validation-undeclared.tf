@@ -0,0 +1,13 @@
+variable "validation" {
+  validation {
+    condition     = var.validation != var.undeclared # ERROR: Reference to undeclared input variable
+    error_message = "Must not equal var.undeclared."
+  }
+}
+
+variable "validation_data" {
+  validation {
+    condition     = var.validation_data != terraform.env # ERROR: Invalid reference in variable validation
+    error_message = "Must not equal the workspace name."
+  }
+}
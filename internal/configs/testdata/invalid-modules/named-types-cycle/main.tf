@@ -0,0 +1,5 @@
+type "tree" {
+  type = object({
+    children = list(type.tree)
+  })
+}
@@ -0,0 +1,9 @@
+type "subnet" {
+  type = object({
+    cidr_block = string
+  })
+}
+
+variable "subnets" {
+  type = list(type.subnets)
+}
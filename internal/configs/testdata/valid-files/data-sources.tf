@@ -12,4 +12,8 @@ data "http" "example2" {
   depends_on = [
     data.http.example1,
   ]
+
+  lifecycle {
+    operation_timeout = "90s"
+  }
 }
@@ -0,0 +1,33 @@
+type "network" {
+  description = "A network and its subnets."
+  type = object({
+    cidr_block = string
+    subnets    = optional(list(type.subnet), [])
+  })
+}
+
+type "subnet" {
+  type = object({
+    name       = string
+    cidr_block = string
+    public     = optional(bool, false)
+  })
+}
+
+variable "network" {
+  type = type.network
+  default = {
+    cidr_block = "10.0.0.0/16"
+    subnets = [
+      {
+        name       = "a"
+        cidr_block = "10.0.1.0/24"
+      },
+    ]
+  }
+}
+
+variable "networks" {
+  type    = map(type.network)
+  default = {}
+}
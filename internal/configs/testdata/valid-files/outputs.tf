@@ -23,3 +23,13 @@ output "π" {
     pizza.cheese,
   ]
 }
+
+output "old_pizza" {
+  value      = "🍕"
+  deprecated = "Use cheeze_pizza instead."
+}
+
+output "shared_pizza" {
+  value       = "🍕"
+  shared_with = ["app-*", "billing"]
+}
@@ -2,6 +2,10 @@ resource "aws_security_group" "firewall" {
   lifecycle {
     create_before_destroy = true
     prevent_destroy = true
+    priority = 10
+    two_phase_replace = true
+    two_phase_replace_timeout = "20m"
+    operation_timeout = "30m"
     ignore_changes = [
       description,
     ]
@@ -40,6 +44,10 @@ resource "aws_instance" "web" {
   depends_on = [
     aws_security_group.firewall,
   ]
+
+  lifecycle {
+    canary = [0]
+  }
 }
 
 resource "aws_instance" "depends" {
@@ -20,3 +20,11 @@ variable "validation_error_expression" {
     error_message = "Too long (${length(var.validation_error_expression)} is greater than 10)."
   }
 }
+
+variable "validation_other_variable" {
+  type = number
+  validation {
+    condition     = var.validation != 5 || var.validation_other_variable >= 3
+    error_message = "Must be at least 3 in ${path.module} when var.validation is ${var.validation}."
+  }
+}
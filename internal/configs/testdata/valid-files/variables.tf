@@ -42,3 +42,8 @@ variable "nullable_default_null" {
   nullable = true
   default = null
 }
+
+variable "old_pizza" {
+  default    = "cheese"
+  deprecated = "Use cheeze_pizza instead."
+}
@@ -0,0 +1,22 @@
+locals {
+  regions = {
+    east = "us-east-1"
+    west = "us-west-2"
+  }
+}
+
+provider "aws" {
+  alias    = "regions"
+  for_each = local.regions
+
+  region = each.value
+}
+
+resource "aws_instance" "web" {
+  for_each = local.regions
+  provider = aws.regions[each.key]
+}
+
+data "aws_ami" "east" {
+  provider = aws.regions["east"]
+}
@@ -0,0 +1,23 @@
+variables {
+  input = "common"
+}
+
+provider "foo" {
+  alias = "helper"
+}
+
+override_resource {
+  target = bar_resource.c
+}
+
+assertions "has_value" {
+  assert {
+    condition     = foo_resource.a.value != ""
+    error_message = "empty value"
+  }
+
+  assert {
+    condition     = foo_resource.a.value == var.input
+    error_message = "wrong value"
+  }
+}
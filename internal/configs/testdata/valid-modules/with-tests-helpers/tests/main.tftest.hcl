@@ -0,0 +1,27 @@
+imports = ["common"]
+
+before_each {
+  variables {
+    input = "before_each"
+  }
+
+  assert {
+    condition     = bar_resource.c != null
+    error_message = "missing resource"
+  }
+}
+
+run "defaults" {
+  assertions = ["has_value"]
+}
+
+run "own_variables" {
+  variables {
+    input = "own"
+  }
+
+  assert {
+    condition     = foo_resource.a.value == "own"
+    error_message = "wrong value"
+  }
+}
@@ -161,6 +161,70 @@ func (g *AcyclicGraph) Cycles() [][]Vertex {
 	return cycles
 }
 
+// CyclePath returns the vertices of one cycle through the given strongly
+// connected component, as returned by Cycles, in the order of the edges
+// between them. The path starts at the vertex whose name sorts first and
+// each vertex has an edge to the next one, with the last vertex having an
+// edge back to the first. The shortest such cycle is chosen, so that it's
+// easier for a person to follow.
+func (g *AcyclicGraph) CyclePath(component []Vertex) []Vertex {
+	if len(component) == 0 {
+		return nil
+	}
+
+	members := make(Set, len(component))
+	for _, v := range component {
+		members.Add(v)
+	}
+	sorted := func(s Set) []Vertex {
+		vs := AsVertexList(s)
+		sort.Slice(vs, func(i, j int) bool {
+			return VertexName(vs[i]) < VertexName(vs[j])
+		})
+		return vs
+	}
+
+	start := sorted(members)[0]
+
+	// Breadth-first search from the start vertex, staying within the
+	// component, until we find an edge leading back to the start.
+	prev := map[interface{}]Vertex{}
+	queue := []Vertex{start}
+	seen := Set{}
+	seen.Add(start)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range sorted(g.downEdgesNoCopy(current)) {
+			if !members.Include(next) {
+				continue
+			}
+			if hashcode(next) == hashcode(start) {
+				var path []Vertex
+				for v := current; hashcode(v) != hashcode(start); v = prev[hashcode(v)] {
+					path = append(path, v)
+				}
+				path = append(path, start)
+				for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+					path[i], path[j] = path[j], path[i]
+				}
+				return path
+			}
+			if seen.Include(next) {
+				continue
+			}
+			seen.Add(next)
+			prev[hashcode(next)] = current
+			queue = append(queue, next)
+		}
+	}
+
+	// A strongly connected component always contains a cycle through
+	// each of its vertices, so we can only get here if the given vertices
+	// were not a component of this graph.
+	return nil
+}
+
 // Walk walks the graph, calling your callback as each node is visited.
 // This will walk nodes in parallel if it can. The resulting diagnostics
 // contains problems from all graphs visited, in no particular order.
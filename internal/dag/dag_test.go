@@ -230,6 +230,36 @@ func TestAcyclicGraphValidate_cycleSelf(t *testing.T) {
 	}
 }
 
+func TestAcyclicGraphCyclePath(t *testing.T) {
+	var g AcyclicGraph
+	for i := 1; i <= 5; i++ {
+		g.Add(i)
+	}
+	// 1 -> 2 -> 3 -> 4 -> 1 and the shortcut 2 -> 4, with 5 outside the
+	// cycle but reachable from it.
+	g.Connect(BasicEdge(1, 2))
+	g.Connect(BasicEdge(2, 3))
+	g.Connect(BasicEdge(3, 4))
+	g.Connect(BasicEdge(4, 1))
+	g.Connect(BasicEdge(2, 4))
+	g.Connect(BasicEdge(4, 5))
+
+	cycles := g.Cycles()
+	if len(cycles) != 1 {
+		t.Fatalf("wrong number of cycles: %#v", cycles)
+	}
+
+	got := g.CyclePath(cycles[0])
+	want := []Vertex{1, 2, 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong path\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	if got := g.CyclePath([]Vertex{3, 5}); got != nil {
+		t.Fatalf("unexpected path for vertices that aren't a cycle: %#v", got)
+	}
+}
+
 func TestAcyclicGraphAncestors(t *testing.T) {
 	var g AcyclicGraph
 	g.Add(1)
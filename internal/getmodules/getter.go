@@ -8,6 +8,7 @@ package getmodules
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"os"
 
@@ -126,7 +127,7 @@ type reusingGetter map[string]string
 // end-user-actionable error messages. At this time we do not have any
 // reasonable way to improve these error messages at this layer because
 // the underlying errors are not separately recognizable.
-func (g reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAddr string) error {
+func (g reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAddr string, progress ProgressFunc) error {
 	var err error
 
 	if prevDir, exists := g[packageAddr]; exists {
@@ -153,6 +154,9 @@ func (g reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAdd
 			Getters:       goGetterGetters,
 			Ctx:           ctx,
 		}
+		if progress != nil {
+			client.ProgressListener = progressTracker(progress)
+		}
 		err = client.Get()
 		if err != nil {
 			return err
@@ -167,3 +171,33 @@ func (g reusingGetter) getWithGoGetter(ctx context.Context, instPath, packageAdd
 	// have got the full module package structure written into instPath.
 	return nil
 }
+
+// progressTracker adapts a ProgressFunc to go-getter's progress tracking
+// interface.
+type progressTracker ProgressFunc
+
+func (t progressTracker) TrackProgress(src string, currentSize, totalSize int64, stream io.ReadCloser) io.ReadCloser {
+	return &progressReadCloser{
+		ReadCloser: stream,
+		received:   currentSize,
+		total:      totalSize,
+		report:     ProgressFunc(t),
+	}
+}
+
+// progressReadCloser reports the number of bytes read so far after each read
+// from the wrapped stream.
+type progressReadCloser struct {
+	io.ReadCloser
+	received, total int64
+	report          ProgressFunc
+}
+
+func (r *progressReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.received += int64(n)
+		r.report(r.received, r.total)
+	}
+	return n, err
+}
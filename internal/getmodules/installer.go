@@ -32,6 +32,15 @@ func NewPackageFetcher() *PackageFetcher {
 	}
 }
 
+// ProgressFunc is called repeatedly while a package is downloaded, with the
+// number of bytes received so far and the total size of the package, or -1 if
+// the size isn't known in advance.
+//
+// Only some kinds of package source can report progress, so a ProgressFunc
+// may not be called at all even for a package that is downloaded over the
+// network.
+type ProgressFunc func(received, total int64)
+
 // FetchPackage downloads or otherwise retrieves the filesystem inside the
 // package at the given address into the given local installation directory.
 //
@@ -44,6 +53,9 @@ func NewPackageFetcher() *PackageFetcher {
 // a module source address which includes a subdirectory portion then the
 // caller must resolve that itself, possibly with the help of the
 // getmodules.SplitPackageSubdir and getmodules.ExpandSubdirGlobs functions.
-func (f *PackageFetcher) FetchPackage(ctx context.Context, instDir string, packageAddr string) error {
-	return f.getter.getWithGoGetter(ctx, instDir, packageAddr)
+//
+// If progress is not nil, it is called to report the progress of the
+// download where the package source supports it.
+func (f *PackageFetcher) FetchPackage(ctx context.Context, instDir string, packageAddr string, progress ProgressFunc) error {
+	return f.getter.getWithGoGetter(ctx, instDir, packageAddr, progress)
 }
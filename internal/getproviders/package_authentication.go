@@ -140,6 +140,20 @@ type PackageAuthenticationHashes interface {
 	AcceptableHashes() []Hash
 }
 
+// PackageAuthenticationSignedChecksums is an optional interface implemented
+// by PackageAuthentication implementations that verify a checksums document
+// signed by the distributor of a package, which allows callers to keep a copy
+// of the signed material, such as to publish it along with a mirror of the
+// packages so that it can be verified again later.
+type PackageAuthenticationSignedChecksums interface {
+	PackageAuthentication
+
+	// SignedChecksums returns the checksums document, its detached
+	// signature, and the public keys that the signature may be verified
+	// with. The results are nil if the authentication has no such document.
+	SignedChecksums() (document, signature []byte, keys []SigningKey)
+}
+
 type packageAuthenticationAll []PackageAuthentication
 
 // PackageAuthenticationAll combines several authentications together into a
@@ -191,6 +205,23 @@ func (checks packageAuthenticationAll) AcceptableHashes() []Hash {
 	return nil
 }
 
+// SignedChecksums returns the signed checksums from the last of the checks
+// that implements PackageAuthenticationSignedChecksums and has them, or nil
+// results if none do.
+func (checks packageAuthenticationAll) SignedChecksums() (document, signature []byte, keys []SigningKey) {
+	for i := len(checks) - 1; i >= 0; i-- {
+		check, ok := checks[i].(PackageAuthenticationSignedChecksums)
+		if !ok {
+			continue
+		}
+		document, signature, keys = check.SignedChecksums()
+		if document != nil {
+			return document, signature, keys
+		}
+	}
+	return nil, nil, nil
+}
+
 type packageHashAuthentication struct {
 	RequiredHashes []Hash
 	AllHashes      []Hash
@@ -483,6 +514,10 @@ func (s signatureAuthentication) AcceptableHashes() []Hash {
 	return ret
 }
 
+func (s signatureAuthentication) SignedChecksums() (document, signature []byte, keys []SigningKey) {
+	return s.Document, s.Signature, s.Keys
+}
+
 // findSigningKey attempts to verify the signature using each of the keys
 // returned by the registry. If a valid signature is found, it returns the
 // signing key.
@@ -6,6 +6,7 @@
 package getproviders
 
 import (
+	"bytes"
 	"crypto/sha256"
 	"encoding/base64"
 	"errors"
@@ -95,6 +96,37 @@ func TestPackageAuthenticationAll_failure(t *testing.T) {
 	}
 }
 
+// The signed checksums of a combined authentication come from the signature
+// authentication among its checks.
+func TestPackageAuthenticationAll_signedChecksums(t *testing.T) {
+	document := []byte("document")
+	signature := []byte("signature")
+	keys := []SigningKey{{ASCIIArmor: "key"}}
+	auth := PackageAuthenticationAll(
+		&mockAuthentication{result: verifiedChecksum},
+		NewSignatureAuthentication(PackageMeta{}, document, signature, keys, nil),
+	)
+
+	signed, ok := auth.(PackageAuthenticationSignedChecksums)
+	if !ok {
+		t.Fatalf("%T does not implement PackageAuthenticationSignedChecksums", auth)
+	}
+	gotDocument, gotSignature, gotKeys := signed.SignedChecksums()
+	if !bytes.Equal(gotDocument, document) || !bytes.Equal(gotSignature, signature) {
+		t.Errorf("wrong signed checksums %q, %q", gotDocument, gotSignature)
+	}
+	if diff := cmp.Diff(keys, gotKeys); diff != "" {
+		t.Errorf("wrong keys\n%s", diff)
+	}
+
+	gotDocument, _, _ = PackageAuthenticationAll(
+		&mockAuthentication{result: verifiedChecksum},
+	).(PackageAuthenticationSignedChecksums).SignedChecksums()
+	if gotDocument != nil {
+		t.Errorf("unexpected document %q", gotDocument)
+	}
+}
+
 // Package hash authentication requires a zip file or directory fixture and a
 // known-good set of hashes, of which the authenticator will pick one. The
 // result should be "verified checksum".
@@ -109,6 +109,21 @@ func (s *RegistrySource) PackageMeta(ctx context.Context, provider addrs.Provide
 	return client.PackageMeta(ctx, provider, version, target)
 }
 
+// AvailablePlatforms returns the target platforms that the origin registry
+// of the given provider has packages of the given version for.
+//
+// Callers of AvailablePlatforms should first call AvailableVersions and pass
+// one of the resulting versions to this function. The errors are as for
+// AvailableVersions.
+func (s *RegistrySource) AvailablePlatforms(ctx context.Context, provider addrs.Provider, version Version) ([]Platform, error) {
+	client, err := s.registryClient(provider.Hostname)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ProviderPlatforms(ctx, provider, version)
+}
+
 func (s *RegistrySource) registryClient(hostname svchost.Hostname) (*registryClient, error) {
 	host, err := s.services.Discover(hostname)
 	if err != nil {
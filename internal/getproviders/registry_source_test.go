@@ -114,6 +114,33 @@ func TestSourceAvailableVersions_warnings(t *testing.T) {
 
 }
 
+func TestSourceAvailablePlatforms(t *testing.T) {
+	source, _, close := testRegistrySource(t)
+	defer close()
+
+	provider := addrs.MustParseProviderSourceString("example.com/awesomesauce/happycloud")
+
+	got, err := source.AvailablePlatforms(context.Background(), provider, MustParseVersion("1.2.0"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := []Platform{
+		{OS: "linux", Arch: "amd64"},
+		{OS: "darwin", Arch: "arm64"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	_, err = source.AvailablePlatforms(context.Background(), provider, MustParseVersion("3.0.0"))
+	if err == nil {
+		t.Fatal("expected error for unknown version")
+	}
+	if got, want := err.Error(), "the registry does not list version 3.0.0"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error %q; want %q", got, want)
+	}
+}
+
 func TestSourcePackageMeta(t *testing.T) {
 	source, baseURL, close := testRegistrySource(t)
 	defer close()
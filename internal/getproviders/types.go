@@ -113,6 +113,48 @@ func MeetingConstraints(vc VersionConstraints) VersionSet {
 	return versions.MeetingConstraints(vc)
 }
 
+// VersionConstraintsSatisfiable returns true if at least one version could
+// meet all of the given constraints, regardless of which versions are actually
+// available. It returns false for constraints that contradict one another,
+// such as ">= 2.0.0" and "< 2.0.0".
+func VersionConstraintsSatisfiable(vc VersionConstraints) bool {
+	// The lowest version in the set of versions meeting the constraints, if
+	// there is one, is either the lowest possible version, the lower bound
+	// of one of the constraints, or the version just above it if that bound
+	// is exclusive. We therefore only need to test those candidates, along
+	// with the next minor and major versions to account for
+	// partially-specified bounds. (0.0.0 is reserved to represent an
+	// unspecified version, so the lowest possible version is 0.0.1.)
+	if len(vc) == 0 {
+		return true
+	}
+	set := MeetingConstraints(vc)
+	if set.Has(Version{Patch: 1}) {
+		return true
+	}
+	for _, sel := range vc {
+		boundary := sel.Boundary.ConstrainToZero()
+		v := Version{
+			Major:      boundary.Major.Num,
+			Minor:      boundary.Minor.Num,
+			Patch:      boundary.Patch.Num,
+			Prerelease: versions.VersionExtra(boundary.Prerelease),
+		}
+		candidates := []Version{
+			v,
+			{Major: v.Major, Minor: v.Minor, Patch: v.Patch + 1},
+			{Major: v.Major, Minor: v.Minor + 1},
+			{Major: v.Major + 1},
+		}
+		for _, candidate := range candidates {
+			if set.Has(candidate) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Platform represents a target platform that a provider is or might be
 // available for.
 type Platform struct {
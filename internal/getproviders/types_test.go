@@ -100,6 +100,71 @@ func TestVersionConstraintsString(t *testing.T) {
 	}
 }
 
+func TestVersionConstraintsSatisfiable(t *testing.T) {
+	testCases := map[string]struct {
+		constraints string
+		want        bool
+	}{
+		"unconstrained": {
+			"",
+			true,
+		},
+		"lower bound": {
+			">= 1.2.0",
+			true,
+		},
+		"upper bound": {
+			"< 1.0.0",
+			true,
+		},
+		"overlapping range": {
+			">= 1.0.0, < 2.0.0, > 1.5",
+			true,
+		},
+		"exclusive bounds with room": {
+			"> 1.2.3, < 1.2.5",
+			true,
+		},
+		"exclusive bounds without room": {
+			"> 1.2.3, < 1.2.4",
+			false,
+		},
+		"pessimistic and exact": {
+			"~> 1.2, 1.4.1",
+			true,
+		},
+		"disjoint range": {
+			">= 2.0.0, < 2.0.0",
+			false,
+		},
+		"pessimistic conflict": {
+			"~> 1.2.0, ~> 1.3.0",
+			false,
+		},
+		"different exact versions": {
+			"1.0.0, 1.0.1",
+			false,
+		},
+		"excluded exact version": {
+			"1.0.0, != 1.0.0",
+			false,
+		},
+		"prerelease": {
+			"1.0.0-beta, >= 0.9",
+			true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			vc := MustParseVersionConstraints(tc.constraints)
+			if got := VersionConstraintsSatisfiable(vc); got != tc.want {
+				t.Errorf("wrong result for %q: got %t, want %t", tc.constraints, got, tc.want)
+			}
+		})
+	}
+}
+
 func TestParsePlatform(t *testing.T) {
 	tests := []struct {
 		Input string
@@ -7,6 +7,7 @@ package grpcwrap
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/opentofu/opentofu/internal/plugin/convert"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -14,6 +15,8 @@ import (
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 	"github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // New wraps a providers.Interface to implement a grpc ProviderServer.
@@ -69,7 +72,14 @@ func (p *provider) GetSchema(_ context.Context, req *tfplugin5.GetProviderSchema
 	}
 
 	resp.ServerCapabilities = &tfplugin5.ServerCapabilities{
-		PlanDestroy: p.schema.ServerCapabilities.PlanDestroy,
+		PlanDestroy:            p.schema.ServerCapabilities.PlanDestroy,
+		ReadResources:          implements[providers.BulkResourceReader](p.provider),
+		ResourcePriorities:     implements[providers.ResourcePriorityHinter](p.provider),
+		FindResource:           implements[providers.ResourceFinder](p.provider),
+		CheckResourceReadiness: implements[providers.ResourceReadinessChecker](p.provider),
+		ListResources:          implements[providers.ResourceLister](p.provider),
+		Actions:                implements[providers.ActionInvoker](p.provider),
+		CheckHealth:            implements[providers.HealthChecker](p.provider),
 	}
 
 	// include any diagnostics from the original GetSchema call
@@ -161,6 +171,21 @@ func (p *provider) UpgradeResourceState(_ context.Context, req *tfplugin5.Upgrad
 	return resp, nil
 }
 
+func (p *provider) GetResourcePriorities(context.Context, *tfplugin5.GetResourcePriorities_Request) (*tfplugin5.GetResourcePriorities_Response, error) {
+	hinter, ok := p.provider.(providers.ResourcePriorityHinter)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not suggest resource priorities")
+	}
+
+	resp := &tfplugin5.GetResourcePriorities_Response{
+		Priorities: make(map[string]int64),
+	}
+	for typeName, priority := range hinter.ResourcePriorities() {
+		resp.Priorities[typeName] = int64(priority)
+	}
+	return resp, nil
+}
+
 func (p *provider) Configure(_ context.Context, req *tfplugin5.Configure_Request) (*tfplugin5.Configure_Response, error) {
 	resp := &tfplugin5.Configure_Response{}
 	ty := p.schema.Provider.Block.ImpliedType()
@@ -219,6 +244,62 @@ func (p *provider) ReadResource(_ context.Context, req *tfplugin5.ReadResource_R
 	return resp, nil
 }
 
+func (p *provider) ReadResources(_ context.Context, req *tfplugin5.ReadResources_Request) (*tfplugin5.ReadResources_Response, error) {
+	bulk, ok := p.provider.(providers.BulkResourceReader)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not support bulk reads")
+	}
+
+	resp := &tfplugin5.ReadResources_Response{}
+	metaTy := p.schema.ProviderMeta.Block.ImpliedType()
+
+	bulkReq := providers.ReadResourcesRequest{
+		Requests: make([]providers.ReadResourceRequest, len(req.Requests)),
+	}
+	for i, r := range req.Requests {
+		ty := p.schema.ResourceTypes[r.TypeName].Block.ImpliedType()
+		stateVal, err := decodeDynamicValue(r.CurrentState, ty)
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+			return resp, nil
+		}
+		metaVal, err := decodeDynamicValue(r.ProviderMeta, metaTy)
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+			return resp, nil
+		}
+		bulkReq.Requests[i] = providers.ReadResourceRequest{
+			TypeName:     r.TypeName,
+			PriorState:   stateVal,
+			Private:      r.Private,
+			ProviderMeta: metaVal,
+		}
+	}
+
+	bulkResp := bulk.ReadResources(bulkReq)
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, bulkResp.Diagnostics)
+	if bulkResp.Diagnostics.HasErrors() {
+		return resp, nil
+	}
+
+	for i, readResp := range bulkResp.Responses {
+		protoResp := &tfplugin5.ReadResource_Response{}
+		protoResp.Diagnostics = convert.AppendProtoDiag(protoResp.Diagnostics, readResp.Diagnostics)
+		if !readResp.Diagnostics.HasErrors() && i < len(req.Requests) {
+			protoResp.Private = readResp.Private
+			ty := p.schema.ResourceTypes[req.Requests[i].TypeName].Block.ImpliedType()
+			dv, err := encodeDynamicValue(readResp.NewState, ty)
+			if err != nil {
+				protoResp.Diagnostics = convert.AppendProtoDiag(protoResp.Diagnostics, err)
+			}
+			protoResp.NewState = dv
+		}
+		resp.Responses = append(resp.Responses, protoResp)
+	}
+
+	return resp, nil
+}
+
 func (p *provider) PlanResourceChange(_ context.Context, req *tfplugin5.PlanResourceChange_Request) (*tfplugin5.PlanResourceChange_Response, error) {
 	resp := &tfplugin5.PlanResourceChange_Response{}
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
@@ -356,6 +437,156 @@ func (p *provider) ImportResourceState(_ context.Context, req *tfplugin5.ImportR
 	return resp, nil
 }
 
+func (p *provider) FindResource(_ context.Context, req *tfplugin5.FindResource_Request) (*tfplugin5.FindResource_Response, error) {
+	finder, ok := p.provider.(providers.ResourceFinder)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not support finding existing objects")
+	}
+
+	resp := &tfplugin5.FindResource_Response{}
+	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
+
+	configVal, err := decodeDynamicValue(req.Config, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	findResp := finder.FindResource(providers.FindResourceRequest{
+		TypeName: req.TypeName,
+		Config:   configVal,
+	})
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, findResp.Diagnostics)
+	resp.Id = findResp.ID
+	return resp, nil
+}
+
+func (p *provider) CheckResourceReadiness(_ context.Context, req *tfplugin5.CheckResourceReadiness_Request) (*tfplugin5.CheckResourceReadiness_Response, error) {
+	checker, ok := p.provider.(providers.ResourceReadinessChecker)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not support readiness checks")
+	}
+
+	resp := &tfplugin5.CheckResourceReadiness_Response{}
+	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
+
+	stateVal, err := decodeDynamicValue(req.State, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	checkResp := checker.CheckResourceReadiness(providers.CheckResourceReadinessRequest{
+		TypeName: req.TypeName,
+		State:    stateVal,
+	})
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, checkResp.Diagnostics)
+	resp.Ready = checkResp.Ready
+	return resp, nil
+}
+
+func (p *provider) ListResources(_ context.Context, req *tfplugin5.ListResources_Request) (*tfplugin5.ListResources_Response, error) {
+	lister, ok := p.provider.(providers.ResourceLister)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not support listing resources")
+	}
+
+	resp := &tfplugin5.ListResources_Response{}
+	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
+
+	listResp := lister.ListResources(providers.ListResourcesRequest{
+		TypeName: req.TypeName,
+		Filters:  req.Filters,
+	})
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, listResp.Diagnostics)
+	for _, listed := range listResp.Resources {
+		res := &tfplugin5.ListResources_Resource{
+			Id:   listed.ID,
+			Name: listed.Name,
+		}
+		if listed.State != cty.NilVal {
+			state, err := encodeDynamicValue(listed.State, ty)
+			if err != nil {
+				resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+				return resp, nil
+			}
+			res.State = state
+		}
+		resp.Resources = append(resp.Resources, res)
+	}
+	return resp, nil
+}
+
+func (p *provider) GetActionSchemas(_ context.Context, req *tfplugin5.GetActionSchemas_Request) (*tfplugin5.GetActionSchemas_Response, error) {
+	invoker, ok := p.provider.(providers.ActionInvoker)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not offer actions")
+	}
+
+	resp := &tfplugin5.GetActionSchemas_Response{
+		Actions: make(map[string]*tfplugin5.Schema),
+	}
+	schemasResp := invoker.GetActionSchemas()
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, schemasResp.Diagnostics)
+	for name, schema := range schemasResp.Actions {
+		resp.Actions[name] = &tfplugin5.Schema{
+			Version: schema.Version,
+			Block:   convert.ConfigSchemaToProto(schema.Block),
+		}
+	}
+	return resp, nil
+}
+
+func (p *provider) InvokeAction(_ context.Context, req *tfplugin5.InvokeAction_Request) (*tfplugin5.InvokeAction_Response, error) {
+	invoker, ok := p.provider.(providers.ActionInvoker)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not offer actions")
+	}
+
+	resp := &tfplugin5.InvokeAction_Response{}
+	schemasResp := invoker.GetActionSchemas()
+	if schemasResp.Diagnostics.HasErrors() {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, schemasResp.Diagnostics)
+		return resp, nil
+	}
+	schema, ok := schemasResp.Actions[req.TypeName]
+	if !ok {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, fmt.Errorf("unknown action type %q", req.TypeName))
+		return resp, nil
+	}
+
+	configVal, err := decodeDynamicValue(req.Config, schema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	invokeResp := invoker.InvokeAction(providers.InvokeActionRequest{
+		TypeName: req.TypeName,
+		Config:   configVal,
+	})
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, invokeResp.Diagnostics)
+	if invokeResp.Result != cty.NilVal {
+		resp.Result, err = encodeDynamicValue(invokeResp.Result, cty.DynamicPseudoType)
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		}
+	}
+	return resp, nil
+}
+
+func (p *provider) CheckHealth(_ context.Context, req *tfplugin5.CheckHealth_Request) (*tfplugin5.CheckHealth_Response, error) {
+	checker, ok := p.provider.(providers.HealthChecker)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not support health checks")
+	}
+
+	resp := &tfplugin5.CheckHealth_Response{}
+	checkResp := checker.CheckHealth(providers.CheckHealthRequest{})
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, checkResp.Diagnostics)
+	return resp, nil
+}
+
 func (p *provider) MoveResourceState(context.Context, *tfplugin5.MoveResourceState_Request) (*tfplugin5.MoveResourceState_Response, error) {
 	panic("Not Implemented")
 }
@@ -438,3 +669,10 @@ func encodeDynamicValue(v cty.Value, ty cty.Type) (*tfplugin5.DynamicValue, erro
 		Msgpack: mp,
 	}, err
 }
+
+// implements returns true if the given provider implements the optional
+// provider interface T, and so supports the corresponding capability.
+func implements[T any](p providers.Interface) bool {
+	_, ok := p.(T)
+	return ok
+}
@@ -7,6 +7,7 @@ package grpcwrap
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/opentofu/opentofu/internal/plugin6/convert"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -14,6 +15,8 @@ import (
 	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 	"github.com/zclconf/go-cty/cty/msgpack"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // New wraps a providers.Interface to implement a grpc ProviderServer using
@@ -69,7 +72,14 @@ func (p *provider6) GetProviderSchema(_ context.Context, req *tfplugin6.GetProvi
 	}
 
 	resp.ServerCapabilities = &tfplugin6.ServerCapabilities{
-		PlanDestroy: p.schema.ServerCapabilities.PlanDestroy,
+		PlanDestroy:            p.schema.ServerCapabilities.PlanDestroy,
+		ReadResources:          implements[providers.BulkResourceReader](p.provider),
+		ResourcePriorities:     implements[providers.ResourcePriorityHinter](p.provider),
+		FindResource:           implements[providers.ResourceFinder](p.provider),
+		CheckResourceReadiness: implements[providers.ResourceReadinessChecker](p.provider),
+		ListResources:          implements[providers.ResourceLister](p.provider),
+		Actions:                implements[providers.ActionInvoker](p.provider),
+		CheckHealth:            implements[providers.HealthChecker](p.provider),
 	}
 
 	// include any diagnostics from the original GetSchema call
@@ -161,6 +171,21 @@ func (p *provider6) UpgradeResourceState(_ context.Context, req *tfplugin6.Upgra
 	return resp, nil
 }
 
+func (p *provider6) GetResourcePriorities(context.Context, *tfplugin6.GetResourcePriorities_Request) (*tfplugin6.GetResourcePriorities_Response, error) {
+	hinter, ok := p.provider.(providers.ResourcePriorityHinter)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not suggest resource priorities")
+	}
+
+	resp := &tfplugin6.GetResourcePriorities_Response{
+		Priorities: make(map[string]int64),
+	}
+	for typeName, priority := range hinter.ResourcePriorities() {
+		resp.Priorities[typeName] = int64(priority)
+	}
+	return resp, nil
+}
+
 func (p *provider6) ConfigureProvider(_ context.Context, req *tfplugin6.ConfigureProvider_Request) (*tfplugin6.ConfigureProvider_Response, error) {
 	resp := &tfplugin6.ConfigureProvider_Response{}
 	ty := p.schema.Provider.Block.ImpliedType()
@@ -219,6 +244,62 @@ func (p *provider6) ReadResource(_ context.Context, req *tfplugin6.ReadResource_
 	return resp, nil
 }
 
+func (p *provider6) ReadResources(_ context.Context, req *tfplugin6.ReadResources_Request) (*tfplugin6.ReadResources_Response, error) {
+	bulk, ok := p.provider.(providers.BulkResourceReader)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not support bulk reads")
+	}
+
+	resp := &tfplugin6.ReadResources_Response{}
+	metaTy := p.schema.ProviderMeta.Block.ImpliedType()
+
+	bulkReq := providers.ReadResourcesRequest{
+		Requests: make([]providers.ReadResourceRequest, len(req.Requests)),
+	}
+	for i, r := range req.Requests {
+		ty := p.schema.ResourceTypes[r.TypeName].Block.ImpliedType()
+		stateVal, err := decodeDynamicValue6(r.CurrentState, ty)
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+			return resp, nil
+		}
+		metaVal, err := decodeDynamicValue6(r.ProviderMeta, metaTy)
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+			return resp, nil
+		}
+		bulkReq.Requests[i] = providers.ReadResourceRequest{
+			TypeName:     r.TypeName,
+			PriorState:   stateVal,
+			Private:      r.Private,
+			ProviderMeta: metaVal,
+		}
+	}
+
+	bulkResp := bulk.ReadResources(bulkReq)
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, bulkResp.Diagnostics)
+	if bulkResp.Diagnostics.HasErrors() {
+		return resp, nil
+	}
+
+	for i, readResp := range bulkResp.Responses {
+		protoResp := &tfplugin6.ReadResource_Response{}
+		protoResp.Diagnostics = convert.AppendProtoDiag(protoResp.Diagnostics, readResp.Diagnostics)
+		if !readResp.Diagnostics.HasErrors() && i < len(req.Requests) {
+			protoResp.Private = readResp.Private
+			ty := p.schema.ResourceTypes[req.Requests[i].TypeName].Block.ImpliedType()
+			dv, err := encodeDynamicValue6(readResp.NewState, ty)
+			if err != nil {
+				protoResp.Diagnostics = convert.AppendProtoDiag(protoResp.Diagnostics, err)
+			}
+			protoResp.NewState = dv
+		}
+		resp.Responses = append(resp.Responses, protoResp)
+	}
+
+	return resp, nil
+}
+
 func (p *provider6) PlanResourceChange(_ context.Context, req *tfplugin6.PlanResourceChange_Request) (*tfplugin6.PlanResourceChange_Response, error) {
 	resp := &tfplugin6.PlanResourceChange_Response{}
 	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
@@ -356,6 +437,156 @@ func (p *provider6) ImportResourceState(_ context.Context, req *tfplugin6.Import
 	return resp, nil
 }
 
+func (p *provider6) FindResource(_ context.Context, req *tfplugin6.FindResource_Request) (*tfplugin6.FindResource_Response, error) {
+	finder, ok := p.provider.(providers.ResourceFinder)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not support finding existing objects")
+	}
+
+	resp := &tfplugin6.FindResource_Response{}
+	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
+
+	configVal, err := decodeDynamicValue6(req.Config, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	findResp := finder.FindResource(providers.FindResourceRequest{
+		TypeName: req.TypeName,
+		Config:   configVal,
+	})
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, findResp.Diagnostics)
+	resp.Id = findResp.ID
+	return resp, nil
+}
+
+func (p *provider6) CheckResourceReadiness(_ context.Context, req *tfplugin6.CheckResourceReadiness_Request) (*tfplugin6.CheckResourceReadiness_Response, error) {
+	checker, ok := p.provider.(providers.ResourceReadinessChecker)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not support readiness checks")
+	}
+
+	resp := &tfplugin6.CheckResourceReadiness_Response{}
+	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
+
+	stateVal, err := decodeDynamicValue6(req.State, ty)
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	checkResp := checker.CheckResourceReadiness(providers.CheckResourceReadinessRequest{
+		TypeName: req.TypeName,
+		State:    stateVal,
+	})
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, checkResp.Diagnostics)
+	resp.Ready = checkResp.Ready
+	return resp, nil
+}
+
+func (p *provider6) ListResources(_ context.Context, req *tfplugin6.ListResources_Request) (*tfplugin6.ListResources_Response, error) {
+	lister, ok := p.provider.(providers.ResourceLister)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not support listing resources")
+	}
+
+	resp := &tfplugin6.ListResources_Response{}
+	ty := p.schema.ResourceTypes[req.TypeName].Block.ImpliedType()
+
+	listResp := lister.ListResources(providers.ListResourcesRequest{
+		TypeName: req.TypeName,
+		Filters:  req.Filters,
+	})
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, listResp.Diagnostics)
+	for _, listed := range listResp.Resources {
+		res := &tfplugin6.ListResources_Resource{
+			Id:   listed.ID,
+			Name: listed.Name,
+		}
+		if listed.State != cty.NilVal {
+			state, err := encodeDynamicValue6(listed.State, ty)
+			if err != nil {
+				resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+				return resp, nil
+			}
+			res.State = state
+		}
+		resp.Resources = append(resp.Resources, res)
+	}
+	return resp, nil
+}
+
+func (p *provider6) GetActionSchemas(_ context.Context, req *tfplugin6.GetActionSchemas_Request) (*tfplugin6.GetActionSchemas_Response, error) {
+	invoker, ok := p.provider.(providers.ActionInvoker)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not offer actions")
+	}
+
+	resp := &tfplugin6.GetActionSchemas_Response{
+		Actions: make(map[string]*tfplugin6.Schema),
+	}
+	schemasResp := invoker.GetActionSchemas()
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, schemasResp.Diagnostics)
+	for name, schema := range schemasResp.Actions {
+		resp.Actions[name] = &tfplugin6.Schema{
+			Version: schema.Version,
+			Block:   convert.ConfigSchemaToProto(schema.Block),
+		}
+	}
+	return resp, nil
+}
+
+func (p *provider6) InvokeAction(_ context.Context, req *tfplugin6.InvokeAction_Request) (*tfplugin6.InvokeAction_Response, error) {
+	invoker, ok := p.provider.(providers.ActionInvoker)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not offer actions")
+	}
+
+	resp := &tfplugin6.InvokeAction_Response{}
+	schemasResp := invoker.GetActionSchemas()
+	if schemasResp.Diagnostics.HasErrors() {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, schemasResp.Diagnostics)
+		return resp, nil
+	}
+	schema, ok := schemasResp.Actions[req.TypeName]
+	if !ok {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, fmt.Errorf("unknown action type %q", req.TypeName))
+		return resp, nil
+	}
+
+	configVal, err := decodeDynamicValue6(req.Config, schema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		return resp, nil
+	}
+
+	invokeResp := invoker.InvokeAction(providers.InvokeActionRequest{
+		TypeName: req.TypeName,
+		Config:   configVal,
+	})
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, invokeResp.Diagnostics)
+	if invokeResp.Result != cty.NilVal {
+		resp.Result, err = encodeDynamicValue6(invokeResp.Result, cty.DynamicPseudoType)
+		if err != nil {
+			resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, err)
+		}
+	}
+	return resp, nil
+}
+
+func (p *provider6) CheckHealth(_ context.Context, req *tfplugin6.CheckHealth_Request) (*tfplugin6.CheckHealth_Response, error) {
+	checker, ok := p.provider.(providers.HealthChecker)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "provider does not support health checks")
+	}
+
+	resp := &tfplugin6.CheckHealth_Response{}
+	checkResp := checker.CheckHealth(providers.CheckHealthRequest{})
+	resp.Diagnostics = convert.AppendProtoDiag(resp.Diagnostics, checkResp.Diagnostics)
+	return resp, nil
+}
+
 func (p *provider6) MoveResourceState(context.Context, *tfplugin6.MoveResourceState_Request) (*tfplugin6.MoveResourceState_Response, error) {
 	panic("Not Implemented")
 }
@@ -0,0 +1,64 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package i18n
+
+var catalogDE = &Catalog{
+	Language: "de",
+	Name:     "Deutsch",
+	Messages: map[string]string{
+		// Diagnostics
+		"Error: ":   "Fehler: ",
+		"Warning: ": "Warnung: ",
+		"Warnings:": "Warnungen:",
+
+		"Duplicate variable declaration":         "Doppelte Variablendeklaration",
+		"Duplicate local value definition":       "Doppelte Definition eines lokalen Werts",
+		"Duplicate output definition":            "Doppelte Ausgabedefinition",
+		"Duplicate resource configuration":       "Doppelte Ressourcenkonfiguration",
+		"Missing block to override":              "Zu überschreibender Block fehlt",
+		"Unsupported OpenTofu Core version":      "Nicht unterstützte Version von OpenTofu Core",
+		"Reference to undeclared input variable": "Verweis auf eine nicht deklarierte Eingabevariable",
+		"Reference to undeclared local value":    "Verweis auf einen nicht deklarierten lokalen Wert",
+		"Reference to undeclared module":         "Verweis auf ein nicht deklariertes Modul",
+		"Reference to undeclared resource":       "Verweis auf eine nicht deklarierte Ressource",
+		"Reference to undeclared output value":   "Verweis auf einen nicht deklarierten Ausgabewert",
+		"Invalid count argument":                 "Ungültiges count-Argument",
+		"Invalid for_each argument":              "Ungültiges for_each-Argument",
+		"Backend initialization required":        "Backend-Initialisierung erforderlich",
+		"Error acquiring the state lock":         "Fehler beim Sperren des Zustands",
+
+		// Command views
+		"\nFor more help on using this command, run:\n  tofu %s -help\n": "\nWeitere Hilfe zu diesem Befehl erhalten Sie mit:\n  tofu %s -help\n",
+
+		"Destroy complete! Resources: %d destroyed.":                                  "Zerstörung abgeschlossen! Ressourcen: %d zerstört.",
+		"Apply complete! Resources: %d imported, %d added, %d changed, %d destroyed.": "Anwendung abgeschlossen! Ressourcen: %d importiert, %d hinzugefügt, %d geändert, %d zerstört.",
+		"Apply complete! Resources: %d added, %d changed, %d destroyed.":              "Anwendung abgeschlossen! Ressourcen: %d hinzugefügt, %d geändert, %d zerstört.",
+		"Outputs:": "Ausgaben:",
+
+		"\nInterrupt received.\nPlease wait for OpenTofu to exit or data loss may occur.\nGracefully shutting down...\n": "\nUnterbrechung empfangen.\nBitte warten Sie, bis OpenTofu beendet ist, sonst können Daten verloren gehen.\nOpenTofu wird ordnungsgemäß beendet...\n",
+		"\nTwo interrupts received. Exiting immediately. Note that data loss may have occurred.\n":                       "\nZwei Unterbrechungen empfangen. OpenTofu wird sofort beendet. Beachten Sie, dass dabei Daten verloren gegangen sein können.\n",
+		"Stopping operation...": "Vorgang wird angehalten...",
+		"Destroy cancelled.":    "Zerstörung abgebrochen.",
+		"Apply cancelled.":      "Anwendung abgebrochen.",
+
+		// Progress of operations on resources
+		"Destroying...":          "Wird zerstört...",
+		"Creating...":            "Wird erstellt...",
+		"Modifying...":           "Wird geändert...",
+		"Reading...":             "Wird gelesen...",
+		"Still destroying...":    "Wird noch zerstört...",
+		"Still creating...":      "Wird noch erstellt...",
+		"Still modifying...":     "Wird noch geändert...",
+		"Still reading...":       "Wird noch gelesen...",
+		"%s%s elapsed":           "%s%s vergangen",
+		"Destruction complete":   "Zerstörung abgeschlossen",
+		"Creation complete":      "Erstellung abgeschlossen",
+		"Modifications complete": "Änderungen abgeschlossen",
+		"Read complete":          "Lesen abgeschlossen",
+		"%s after %s":            "%s nach %s",
+		"Refreshing state...":    "Zustand wird aktualisiert...",
+	},
+}
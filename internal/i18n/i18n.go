@@ -0,0 +1,161 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package i18n translates the text that OpenTofu shows to people, such as
+// diagnostics, help text, and progress messages, into the language that the
+// user selects with the TF_LANG environment variable.
+//
+// Messages are identified by their English text, so code that shows a
+// message passes the English text through T, or through Sprintf for format
+// strings, and gets back either a translation from the catalog for the
+// selected language or the English text unchanged. A message that a catalog
+// doesn't translate is therefore always shown in English, and catalogs can
+// grow one message at a time.
+//
+// Only text intended for people should be translated. Machine-readable
+// output, such as the JSON views, must remain in English so that other
+// software can rely on it.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EnvLanguage is the environment variable that selects the language of the
+// text that OpenTofu shows.
+const EnvLanguage = "TF_LANG"
+
+// English is the language of the messages themselves, which needs no
+// catalog.
+const English = "en"
+
+// Catalog is the translation of messages into a particular language.
+type Catalog struct {
+	// Language is the language tag of the catalog, such as "de" or
+	// "pt_BR".
+	Language string
+
+	// Name is the name of the language in that language.
+	Name string
+
+	// Messages maps the English text of each message to its translation.
+	// For format strings, the translation must use the same verbs in the
+	// same order, or explicit argument indexes such as %[2]s.
+	Messages map[string]string
+}
+
+// catalogs are all of the available translations, by language tag.
+var catalogs = map[string]*Catalog{
+	catalogDE.Language: catalogDE,
+}
+
+var (
+	mu      sync.RWMutex
+	current *Catalog
+)
+
+// SetLanguage selects the language of the translated messages. The language
+// can be a language tag such as "de" or "pt-BR", or a POSIX locale name such
+// as "de_DE.UTF-8", in which case the most specific available catalog is
+// used.
+//
+// An empty language, "C", "POSIX", or any form of English selects the
+// English messages. If there is no catalog for the language then SetLanguage
+// selects English and returns an error.
+func SetLanguage(lang string) error {
+	catalog, err := lookup(lang)
+
+	mu.Lock()
+	defer mu.Unlock()
+	current = catalog
+	return err
+}
+
+// Language returns the language tag of the selected language.
+func Language() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current == nil {
+		return English
+	}
+	return current.Language
+}
+
+// Languages returns the language tags of all of the available languages,
+// including English, in lexical order.
+func Languages() []string {
+	ret := []string{English}
+	for lang := range catalogs {
+		ret = append(ret, lang)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// T returns the translation of the given message into the selected
+// language, or the message itself if there is no translation.
+func T(msg string) string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if current == nil {
+		return msg
+	}
+	if translated, ok := current.Messages[msg]; ok {
+		return translated
+	}
+	return msg
+}
+
+// Sprintf formats according to the translation of the given format string
+// into the selected language.
+func Sprintf(format string, a ...interface{}) string {
+	return fmt.Sprintf(T(format), a...)
+}
+
+func lookup(lang string) (*Catalog, error) {
+	tag := normalize(lang)
+	switch tag {
+	case "", "c", "posix", English:
+		return nil, nil
+	}
+	if strings.HasPrefix(tag, English+"_") {
+		return nil, nil
+	}
+
+	// A catalog for a specific region is preferred, but a catalog for the
+	// language in general is good enough.
+	if catalog, ok := catalogs[tag]; ok {
+		return catalog, nil
+	}
+	if base, _, ok := strings.Cut(tag, "_"); ok {
+		if catalog, ok := catalogs[base]; ok {
+			return catalog, nil
+		}
+	}
+	return nil, fmt.Errorf("no translations are available for the language %q; the available languages are %s", lang, strings.Join(Languages(), ", "))
+}
+
+// normalize converts a language tag or POSIX locale name into the form used
+// for the keys of catalogs: a lowercase language, optionally followed by an
+// underscore and an uppercase region.
+func normalize(lang string) string {
+	lang = strings.TrimSpace(lang)
+	// POSIX locale names can have a character set and a modifier, such as
+	// "de_DE.UTF-8@euro", which don't affect the messages.
+	if i := strings.IndexAny(lang, ".@"); i >= 0 {
+		lang = lang[:i]
+	}
+	lang = strings.ReplaceAll(lang, "-", "_")
+
+	base, region, hasRegion := strings.Cut(lang, "_")
+	base = strings.ToLower(base)
+	if base == "c" || base == "posix" || !hasRegion {
+		return base
+	}
+	return base + "_" + strings.ToUpper(region)
+}
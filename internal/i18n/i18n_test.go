@@ -0,0 +1,100 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+func testLanguage(t *testing.T, lang string) {
+	t.Helper()
+	if err := SetLanguage(lang); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { SetLanguage(English) })
+}
+
+func TestSetLanguage(t *testing.T) {
+	tests := map[string]struct {
+		Want    string
+		WantErr bool
+	}{
+		"":                 {English, false},
+		"C":                {English, false},
+		"POSIX":            {English, false},
+		"en":               {English, false},
+		"en_US.UTF-8":      {English, false},
+		"en-GB":            {English, false},
+		"de":               {"de", false},
+		"DE":               {"de", false},
+		"de-AT":            {"de", false},
+		"de_DE.UTF-8@euro": {"de", false},
+		"xx":               {English, true},
+		"xx_YY":            {English, true},
+	}
+
+	for lang, test := range tests {
+		t.Run(lang, func(t *testing.T) {
+			t.Cleanup(func() { SetLanguage(English) })
+			err := SetLanguage(lang)
+			if gotErr := err != nil; gotErr != test.WantErr {
+				t.Fatalf("wrong error: %v", err)
+			}
+			if got := Language(); got != test.Want {
+				t.Fatalf("wrong language %q; want %q", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	if got, want := T("Error: "), "Error: "; got != want {
+		t.Fatalf("wrong English message %q; want %q", got, want)
+	}
+
+	testLanguage(t, "de")
+	if got, want := T("Error: "), "Fehler: "; got != want {
+		t.Fatalf("wrong translation %q; want %q", got, want)
+	}
+	// Messages without a translation are shown in English.
+	if got, want := T("Not translated"), "Not translated"; got != want {
+		t.Fatalf("wrong fallback %q; want %q", got, want)
+	}
+	if got, want := Sprintf("Destroy complete! Resources: %d destroyed.", 2), "Zerstörung abgeschlossen! Ressourcen: 2 zerstört."; got != want {
+		t.Fatalf("wrong formatted translation %q; want %q", got, want)
+	}
+}
+
+var verbs = regexp.MustCompile(`%(\[\d+\])?[-+# 0]*\d*(\.\d+)?[a-zA-Z%]`)
+
+func TestCatalogs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		t.Run(lang, func(t *testing.T) {
+			if catalog.Language != lang || catalog.Name == "" {
+				t.Fatalf("catalog has wrong language %q or name %q", catalog.Language, catalog.Name)
+			}
+			for msg, translated := range catalog.Messages {
+				if msg == "" || translated == "" {
+					t.Errorf("empty message %q translated as %q", msg, translated)
+					continue
+				}
+				// Translations of format strings that don't use explicit
+				// argument indexes must use the same verbs in the same order.
+				want, got := verbs.FindAllString(msg, -1), verbs.FindAllString(translated, -1)
+				if len(want) != len(got) {
+					t.Errorf("translation of %q has %d verbs; want %d", msg, len(got), len(want))
+					continue
+				}
+				for i := range want {
+					if got[i] != want[i] && got[i][1] != '[' {
+						t.Errorf("translation of %q has verb %s; want %s", msg, got[i], want[i])
+					}
+				}
+			}
+		})
+	}
+}
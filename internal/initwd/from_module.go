@@ -421,3 +421,12 @@ func (h installHooksInitDir) Download(moduleAddr, packageAddr string, version *v
 	trimAddr := moduleAddr[len(initFromModuleRootKeyPrefix):]
 	h.Wrapped.Download(trimAddr, packageAddr, version)
 }
+
+func (h installHooksInitDir) DownloadProgress(moduleAddr string, received, total int64) {
+	if !strings.HasPrefix(moduleAddr, initFromModuleRootKeyPrefix) {
+		return
+	}
+
+	trimAddr := moduleAddr[len(initFromModuleRootKeyPrefix):]
+	h.Wrapped.DownloadProgress(trimAddr, received, total)
+}
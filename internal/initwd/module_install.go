@@ -468,10 +468,10 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 		i.registryPackageVersions[packageAddr] = resp
 	}
 
-	// The response might contain information about dependencies to allow us
-	// to potentially optimize future requests, but we don't currently do that
-	// and so for now we'll just take the first item which is guaranteed to
-	// be the address we requested.
+	// The response might contain information about other modules, but the
+	// first item is guaranteed to be the address we requested. We use the
+	// dependency information for the selected version below, to pre-resolve
+	// the nested registry modules.
 	if len(resp.Modules) < 1 {
 		// Should never happen, but since this is a remote service that may
 		// be implemented by third-parties we will handle it gracefully.
@@ -625,9 +625,15 @@ func (i *ModuleInstaller) installRegistryModule(ctx context.Context, req *config
 
 	dlAddr := i.registryPackageSources[moduleAddr]
 
+	// While we download this package, we look up the nested registry modules
+	// that the registry says it depends on, so that they are ready to be
+	// installed as soon as their calls are loaded.
+	waitPrefetch := i.prefetchRegistryDependencies(ctx, registryDependencies(modMeta, latestMatch, addr.Subdir))
+	defer waitPrefetch()
+
 	log.Printf("[TRACE] ModuleInstaller: %s %s %s is available at %q", key, packageAddr, latestMatch, dlAddr.Package)
 
-	err := fetcher.FetchPackage(ctx, instPath, dlAddr.Package.String())
+	err := fetcher.FetchPackage(ctx, instPath, dlAddr.Package.String(), downloadProgress(hooks, key))
 	if errors.Is(err, context.Canceled) {
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -715,7 +721,7 @@ func (i *ModuleInstaller) installGoGetterModule(ctx context.Context, req *config
 		return nil, diags
 	}
 
-	err := fetcher.FetchPackage(ctx, instPath, packageAddr.String())
+	err := fetcher.FetchPackage(ctx, instPath, packageAddr.String(), downloadProgress(hooks, key))
 	if err != nil {
 		// go-getter generates a poor error for an invalid relative path, so
 		// we'll detect that case and generate a better one.
@@ -942,3 +948,11 @@ func splitAddrSubdir(addr addrs.ModuleSource) (string, string) {
 		return addr.String(), ""
 	}
 }
+
+// downloadProgress returns a function that reports the download progress of
+// the package for the module with the given key to the given hooks.
+func downloadProgress(hooks ModuleInstallHooks, key string) getmodules.ProgressFunc {
+	return func(received, total int64) {
+		hooks.DownloadProgress(key, received, total)
+	}
+}
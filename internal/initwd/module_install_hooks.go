@@ -21,6 +21,13 @@ type ModuleInstallHooks interface {
 	// on progress through a possibly-long sequence of downloads.
 	Download(moduleAddr, packageAddr string, version *version.Version)
 
+	// DownloadProgress is called repeatedly during a download that was
+	// announced by Download, with the number of bytes received so far and
+	// the total size of the package, or -1 if the size isn't known. Not all
+	// kinds of remote source can report progress, so it may not be called
+	// at all.
+	DownloadProgress(moduleAddr string, received, total int64)
+
 	// Install is called for each module that is installed, even if it did
 	// not need to be downloaded from a remote source.
 	Install(moduleAddr string, version *version.Version, localPath string)
@@ -35,6 +42,9 @@ type ModuleInstallHooksImpl struct {
 func (h ModuleInstallHooksImpl) Download(moduleAddr, packageAddr string, version *version.Version) {
 }
 
+func (h ModuleInstallHooksImpl) DownloadProgress(moduleAddr string, received, total int64) {
+}
+
 func (h ModuleInstallHooksImpl) Install(moduleAddr string, version *version.Version, localPath string) {
 }
 
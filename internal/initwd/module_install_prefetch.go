@@ -0,0 +1,201 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package initwd
+
+import (
+	"context"
+	"log"
+	"path"
+	"sync"
+
+	version "github.com/hashicorp/go-version"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/registry/regsrc"
+	"github.com/opentofu/opentofu/internal/registry/response"
+)
+
+// registryPrefetchConcurrency is the maximum number of concurrent requests
+// that the module installer makes to module registries while pre-resolving
+// the dependencies of a registry module.
+const registryPrefetchConcurrency = 8
+
+// registryDependencies returns the module dependencies that the registry
+// reported for the given version of a module package, for the module in the
+// given subdirectory of the package or for its root module if subdir is
+// empty.
+func registryDependencies(modMeta *response.ModuleProviderVersions, v *version.Version, subdir string) []*response.ModuleDep {
+	for _, mv := range modMeta.Versions {
+		if mv == nil {
+			continue
+		}
+		mvVersion, err := version.NewVersion(mv.Version)
+		if err != nil || !mvVersion.Equal(v) {
+			continue
+		}
+		if subdir == "" {
+			return mv.Root.Dependencies
+		}
+		for _, sub := range mv.Submodules {
+			if sub != nil && path.Clean(sub.Path) == path.Clean(subdir) {
+				return sub.Dependencies
+			}
+		}
+	}
+	return nil
+}
+
+// prefetchRegistryDependencies concurrently looks up the available versions
+// of each of the given registry module dependencies, and the download
+// location of the newest version of each that matches its version
+// constraint, so that installing the dependencies once their calls are
+// loaded doesn't need a sequential round-trip to the registry for each of
+// them.
+//
+// The lookups run in the background until the returned function is called,
+// which waits for them and records their results in the installer's caches
+// of registry responses. It must be called from the goroutine that installs
+// the modules, which owns those caches.
+//
+// The registry's dependency metadata is only a hint: failed lookups are just
+// logged, and the dependencies are installed as normal, according to the
+// module calls in their configuration, when it is loaded.
+func (i *ModuleInstaller) prefetchRegistryDependencies(ctx context.Context, deps []*response.ModuleDep) (wait func()) {
+	type prefetchResult struct {
+		pkg      addrs.ModuleRegistryPackage
+		versions *response.ModuleVersions
+		version  string
+		source   addrs.ModuleSourceRemote
+	}
+
+	var pkgs []addrs.ModuleRegistryPackage
+	constraints := make(map[addrs.ModuleRegistryPackage]version.Constraints)
+	for _, dep := range deps {
+		if dep == nil {
+			continue
+		}
+		sourceAddr, err := addrs.ParseModuleSource(dep.Source)
+		if err != nil {
+			log.Printf("[TRACE] ModuleInstaller: ignoring registry dependency %q with invalid source address: %s", dep.Name, err)
+			continue
+		}
+		regAddr, ok := sourceAddr.(addrs.ModuleSourceRegistry)
+		if !ok {
+			// Dependencies on local paths and remote packages don't need any
+			// further requests to a registry.
+			continue
+		}
+		pkg := regAddr.Package
+		if _, exists := i.registryPackageVersions[pkg]; exists {
+			continue
+		}
+		if _, exists := constraints[pkg]; exists {
+			continue
+		}
+		var vc version.Constraints
+		if dep.Version != "" {
+			vc, err = version.NewConstraint(dep.Version)
+			if err != nil {
+				log.Printf("[TRACE] ModuleInstaller: ignoring invalid version constraint %q of registry dependency %q: %s", dep.Version, dep.Name, err)
+			}
+		}
+		constraints[pkg] = vc
+		pkgs = append(pkgs, pkg)
+	}
+	if len(pkgs) == 0 {
+		return func() {}
+	}
+
+	log.Printf("[DEBUG] ModuleInstaller: pre-resolving %d registry module dependencies", len(pkgs))
+	results := make([]prefetchResult, len(pkgs))
+	sem := make(chan struct{}, registryPrefetchConcurrency)
+	var wg sync.WaitGroup
+	for ix, pkg := range pkgs {
+		wg.Add(1)
+		go func(ix int, pkg addrs.ModuleRegistryPackage, vc version.Constraints) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			result := &results[ix]
+			result.pkg = pkg
+
+			regsrcAddr := regsrc.ModuleFromRegistryPackageAddr(pkg)
+			resp, err := i.reg.ModuleVersions(ctx, regsrcAddr)
+			if err != nil {
+				log.Printf("[TRACE] ModuleInstaller: failed to pre-resolve versions of %s: %s", pkg, err)
+				return
+			}
+			result.versions = resp
+			if len(resp.Modules) < 1 {
+				return
+			}
+
+			latest := newestMatchingVersion(resp.Modules[0], vc)
+			if latest == nil {
+				return
+			}
+			realAddrRaw, err := i.reg.ModuleLocation(ctx, regsrcAddr, latest.String())
+			if err != nil {
+				log.Printf("[TRACE] ModuleInstaller: failed to pre-resolve the location of %s %s: %s", pkg, latest, err)
+				return
+			}
+			realAddr, err := addrs.ParseModuleSource(realAddrRaw)
+			if err != nil {
+				return
+			}
+			if realAddr, ok := realAddr.(addrs.ModuleSourceRemote); ok {
+				result.version = latest.String()
+				result.source = realAddr
+			}
+		}(ix, pkg, constraints[pkg])
+	}
+
+	return func() {
+		wg.Wait()
+		for _, result := range results {
+			if result.versions == nil {
+				continue
+			}
+			if _, exists := i.registryPackageVersions[result.pkg]; !exists {
+				i.registryPackageVersions[result.pkg] = result.versions
+			}
+			if result.version != "" {
+				key := moduleVersion{module: result.pkg, version: result.version}
+				if _, exists := i.registryPackageSources[key]; !exists {
+					i.registryPackageSources[key] = result.source
+				}
+			}
+		}
+	}
+}
+
+// newestMatchingVersion returns the newest version of a module that is not
+// a pre-release and matches the given constraints, or nil if there is none.
+//
+// This is a simplified version of the selection that installRegistryModule
+// makes, which is enough to predict which location it will look up in most
+// cases. If the prediction is wrong, the installer just looks up the right
+// location itself.
+func newestMatchingVersion(modMeta *response.ModuleProviderVersions, vc version.Constraints) *version.Version {
+	var latest *version.Version
+	for _, mv := range modMeta.Versions {
+		if mv == nil {
+			continue
+		}
+		v, err := version.NewVersion(mv.Version)
+		if err != nil || v.Prerelease() != "" {
+			continue
+		}
+		if vc != nil && !vc.Check(v) {
+			continue
+		}
+		if latest == nil || v.GreaterThan(latest) {
+			latest = v
+		}
+	}
+	return latest
+}
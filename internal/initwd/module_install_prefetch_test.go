@@ -0,0 +1,113 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package initwd
+
+import (
+	"context"
+	"testing"
+
+	version "github.com/hashicorp/go-version"
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configload"
+	"github.com/opentofu/opentofu/internal/registry"
+	"github.com/opentofu/opentofu/internal/registry/response"
+	"github.com/opentofu/opentofu/internal/registry/test"
+)
+
+func TestModuleInstaller_prefetchRegistryDependencies(t *testing.T) {
+	server := test.Registry()
+	defer server.Close()
+
+	loader, close := configload.NewLoaderForTests(t)
+	defer close()
+	inst := NewModuleInstaller(t.TempDir(), loader, registry.NewClient(test.Disco(server), nil))
+
+	wait := inst.prefetchRegistryDependencies(context.Background(), []*response.ModuleDep{
+		{Name: "bar", Source: "registry/foo/bar", Version: "~> 0.2"},
+		{Name: "versions", Source: "test-versions/name/provider", Version: "~> 2.1.0"},
+		{Name: "local", Source: "./modules/local"},
+		{Name: "missing", Source: "missing/name/provider"},
+	})
+	wait()
+
+	pkg := func(namespace, name, target string) addrs.ModuleRegistryPackage {
+		return addrs.ModuleRegistryPackage{
+			Host:         svchost.Hostname("registry.opentofu.org"),
+			Namespace:    namespace,
+			Name:         name,
+			TargetSystem: target,
+		}
+	}
+	barPkg := pkg("registry", "foo", "bar")
+	versionsPkg := pkg("test-versions", "name", "provider")
+
+	for _, p := range []addrs.ModuleRegistryPackage{barPkg, versionsPkg} {
+		if _, ok := inst.registryPackageVersions[p]; !ok {
+			t.Errorf("versions of %s were not pre-resolved", p)
+		}
+	}
+	if _, ok := inst.registryPackageVersions[pkg("missing", "name", "provider")]; ok {
+		t.Error("versions of a missing module were cached")
+	}
+
+	got, ok := inst.registryPackageSources[moduleVersion{module: barPkg, version: "0.2.3"}]
+	if !ok {
+		t.Fatalf("location of %s 0.2.3 was not pre-resolved\ngot: %#v", barPkg, inst.registryPackageSources)
+	}
+	if want := "file:///download/registry/foo/bar/0.2.3//*?archive=tar.gz"; got.String() != want {
+		t.Errorf("wrong location %s; want %s", got, want)
+	}
+	if _, ok := inst.registryPackageSources[moduleVersion{module: versionsPkg, version: "2.1.1"}]; !ok {
+		t.Errorf("location of %s 2.1.1 was not pre-resolved", versionsPkg)
+	}
+}
+
+func TestRegistryDependencies(t *testing.T) {
+	deps := func(names ...string) []*response.ModuleDep {
+		var ret []*response.ModuleDep
+		for _, name := range names {
+			ret = append(ret, &response.ModuleDep{Name: name})
+		}
+		return ret
+	}
+	modMeta := &response.ModuleProviderVersions{
+		Versions: []*response.ModuleVersion{
+			{
+				Version: "1.0.0",
+				Root:    response.VersionSubmodule{Dependencies: deps("old")},
+			},
+			{
+				Version: "2.0.0",
+				Root:    response.VersionSubmodule{Dependencies: deps("root")},
+				Submodules: []*response.VersionSubmodule{
+					{Path: "modules/child", Dependencies: deps("child")},
+				},
+			},
+		},
+	}
+
+	v := version.Must(version.NewVersion("2.0.0"))
+	tests := map[string]string{
+		"":                "root",
+		"modules/child":   "child",
+		"modules/child/":  "child",
+		"modules/missing": "",
+	}
+	for subdir, want := range tests {
+		got := registryDependencies(modMeta, v, subdir)
+		if want == "" {
+			if len(got) != 0 {
+				t.Errorf("wrong dependencies for %q: %#v", subdir, got)
+			}
+			continue
+		}
+		if len(got) != 1 || got[0].Name != want {
+			t.Errorf("wrong dependencies for %q: %#v; want %s", subdir, got, want)
+		}
+	}
+}
@@ -899,6 +899,11 @@ func (h *testInstallHooks) Download(moduleAddr, packageAddr string, version *ver
 	})
 }
 
+// DownloadProgress is not recorded, since the number of calls depends on how
+// the package is read from the network.
+func (h *testInstallHooks) DownloadProgress(moduleAddr string, received, total int64) {
+}
+
 func (h *testInstallHooks) Install(moduleAddr string, version *version.Version, localPath string) {
 	h.Calls = append(h.Calls, testInstallHookCall{
 		Name:       "Install",
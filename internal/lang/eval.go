@@ -145,6 +145,7 @@ func (s *Scope) EvalSelfBlock(body hcl.Body, self cty.Value, schema *configschem
 
 	vals["path"] = cty.ObjectVal(pathAttrs)
 	vals["terraform"] = cty.ObjectVal(terraformAttrs)
+	vals["tofu"] = vals["terraform"]
 
 	ctx := &hcl.EvalContext{
 		Variables: vals,
@@ -178,6 +179,32 @@ func (s *Scope) EvalExpr(expr hcl.Expression, wantType cty.Type) (cty.Value, tfd
 		return cty.UnknownVal(wantType), diags
 	}
 
+	var cacheKey exprCacheKey
+	var cacheVars cty.Value
+	cached := s.ExprCache != nil && !s.ConsoleMode && exprCacheable(expr, refs)
+	if cached {
+		cacheKey = exprCacheKey{
+			rng:      expr.Range(),
+			baseDir:  s.BaseDir,
+			pureOnly: s.PureOnly,
+		}
+		cacheVars = cty.ObjectVal(ctx.Variables)
+		if val, cachedDiags, ok := s.ExprCache.get(cacheKey, wantType, cacheVars); ok {
+			return val, diags.Append(cachedDiags)
+		}
+	}
+
+	val, evalDiags := s.evalExpr(ctx, expr, wantType)
+	if cached {
+		s.ExprCache.put(cacheKey, wantType, cacheVars, val, evalDiags)
+	}
+	return val, diags.Append(evalDiags)
+}
+
+// evalExpr evaluates the given expression in the given context and converts
+// the result to the given type.
+func (s *Scope) evalExpr(ctx *hcl.EvalContext, expr hcl.Expression, wantType cty.Type) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
 	val, evalDiags := expr.Value(ctx)
 	diags = diags.Append(s.enhanceFunctionDiags(evalDiags))
 
@@ -515,6 +542,7 @@ func (s *Scope) evalContext(parent *hcl.EvalContext, refs []*addrs.Reference, se
 	vals["local"] = cty.ObjectVal(localValues)
 	vals["path"] = cty.ObjectVal(pathAttrs)
 	vals["terraform"] = cty.ObjectVal(terraformAttrs)
+	vals["tofu"] = vals["terraform"]
 	vals["count"] = cty.ObjectVal(countAttrs)
 	vals["each"] = cty.ObjectVal(forEachAttrs)
 
@@ -0,0 +1,122 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lang
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// exprCacheEntriesPerExpr is the maximum number of results retained for any
+// single expression. Once this is exceeded the oldest result is discarded.
+const exprCacheEntriesPerExpr = 8
+
+// ExprCache memoizes the results of evaluating expressions, so that an
+// expression evaluated repeatedly with the same values for everything it
+// refers to is only actually evaluated once.
+//
+// Results are keyed by the source range of the expression and are reused
+// only if all of the values the expression refers to are identical to those
+// used to produce the cached result. Expressions that call any function
+// other than the known pure functions in cacheableFunctions, such as
+// functions that read files or provider-defined functions, or that refer to
+// instance-specific values like count.index, each.key, or self, are never
+// cached.
+//
+// An ExprCache is safe for concurrent use. The zero value is not valid; use
+// NewExprCache.
+type ExprCache struct {
+	mu      sync.Mutex
+	entries map[exprCacheKey][]*exprCacheEntry
+}
+
+func NewExprCache() *ExprCache {
+	return &ExprCache{
+		entries: make(map[exprCacheKey][]*exprCacheEntry),
+	}
+}
+
+type exprCacheKey struct {
+	rng      hcl.Range
+	baseDir  string
+	pureOnly bool
+}
+
+type exprCacheEntry struct {
+	wantType cty.Type
+	vars     cty.Value
+	val      cty.Value
+	diags    tfdiags.Diagnostics
+}
+
+func (c *ExprCache) get(key exprCacheKey, wantType cty.Type, vars cty.Value) (cty.Value, tfdiags.Diagnostics, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range c.entries[key] {
+		if entry.wantType.Equals(wantType) && entry.vars.RawEquals(vars) {
+			return entry.val, entry.diags, true
+		}
+	}
+	return cty.NilVal, nil, false
+}
+
+func (c *ExprCache) put(key exprCacheKey, wantType cty.Type, vars, val cty.Value, diags tfdiags.Diagnostics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries := c.entries[key]
+	if len(entries) >= exprCacheEntriesPerExpr {
+		entries = entries[1:]
+	}
+	c.entries[key] = append(entries, &exprCacheEntry{
+		wantType: wantType,
+		vars:     vars,
+		val:      val,
+		diags:    diags,
+	})
+}
+
+// exprCacheable returns true if the result of evaluating the given expression
+// depends only on the values of the given references, and those references
+// are not specific to a single instance of a resource or module.
+func exprCacheable(expr hcl.Expression, refs []*addrs.Reference) bool {
+	for _, ref := range refs {
+		switch ref.Subject.(type) {
+		case addrs.CountAttr, addrs.ForEachAttr:
+			return false
+		}
+		if ref.Subject == addrs.Self {
+			return false
+		}
+	}
+
+	node, ok := expr.(hclsyntax.Node)
+	if !ok {
+		return false
+	}
+	cacheable := true
+	hclsyntax.VisitAll(node, func(n hclsyntax.Node) hcl.Diagnostics {
+		call, ok := n.(*hclsyntax.FunctionCallExpr)
+		if !ok {
+			return nil
+		}
+		// Only calls to functions known to be pure can be cached. This rules
+		// out provider-defined functions, which might not be pure, and any
+		// function added without considering whether it can be cached.
+		name := strings.TrimPrefix(call.Name, CoreNamespace)
+		if !cacheableFunctions[name] {
+			cacheable = false
+		}
+		return nil
+	})
+	return cacheable
+}
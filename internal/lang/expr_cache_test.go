@@ -0,0 +1,302 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package lang
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/lang/funcs"
+)
+
+func TestScopeEvalExpr_cache(t *testing.T) {
+	tests := map[string]struct {
+		expr       string
+		wantCached bool
+	}{
+		"local value": {
+			`[for s in split(",", local.foo) : upper(s)]`,
+			true,
+		},
+		"no references": {
+			`1 + 2`,
+			true,
+		},
+		"count.index": {
+			`count.index + 1`,
+			false,
+		},
+		"each.key": {
+			`"${each.key}-${local.foo}"`,
+			false,
+		},
+		"impure function": {
+			`uuid()`,
+			false,
+		},
+		"file function": {
+			`fileexists("foo")`,
+			false,
+		},
+		"provider function": {
+			`provider::test::echo(local.foo)`,
+			false,
+		},
+		"pure function in core namespace": {
+			`core::upper(local.foo)`,
+			true,
+		},
+		"unknown function": {
+			`notafunction(local.foo)`,
+			false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			expr, parseDiags := hclsyntax.ParseExpression([]byte(test.expr), "test.tf", hcl.Pos{Line: 1, Column: 1})
+			if parseDiags.HasErrors() {
+				t.Fatal(parseDiags.Error())
+			}
+
+			cache := NewExprCache()
+			if got := exprCacheable(expr, mustReferences(t, expr)); got != test.wantCached {
+				t.Fatalf("wrong cacheability %t; want %t", got, test.wantCached)
+			}
+			if !test.wantCached {
+				return
+			}
+
+			scope := &Scope{
+				Data: &dataForTests{
+					LocalValues: map[string]cty.Value{
+						"foo": cty.StringVal("a,b"),
+					},
+				},
+				ParseRef:  addrs.ParseRef,
+				ExprCache: cache,
+			}
+			first, diags := scope.EvalExpr(expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				t.Fatal(diags.Err())
+			}
+			if got := len(cache.entries); got != 1 {
+				t.Fatalf("cache has %d entries after first evaluation; want 1", got)
+			}
+			second, diags := scope.EvalExpr(expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				t.Fatal(diags.Err())
+			}
+			if !second.RawEquals(first) {
+				t.Fatalf("cached result %#v differs from original %#v", second, first)
+			}
+			if got := len(cache.entries[exprCacheKey{rng: expr.Range()}]); got != 1 {
+				t.Fatalf("cache has %d results after second evaluation; want 1", got)
+			}
+		})
+	}
+}
+
+func TestScopeEvalExpr_cacheChangedInputs(t *testing.T) {
+	expr, parseDiags := hclsyntax.ParseExpression([]byte(`upper(local.foo)`), "test.tf", hcl.Pos{Line: 1, Column: 1})
+	if parseDiags.HasErrors() {
+		t.Fatal(parseDiags.Error())
+	}
+
+	cache := NewExprCache()
+	data := &dataForTests{
+		LocalValues: map[string]cty.Value{
+			"foo": cty.StringVal("a"),
+		},
+	}
+	scope := &Scope{
+		Data:      data,
+		ParseRef:  addrs.ParseRef,
+		ExprCache: cache,
+	}
+
+	got, diags := scope.EvalExpr(expr, cty.String)
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if want := cty.StringVal("A"); !got.RawEquals(want) {
+		t.Fatalf("wrong result %#v; want %#v", got, want)
+	}
+
+	data.LocalValues["foo"] = cty.StringVal("b")
+	got, diags = scope.EvalExpr(expr, cty.String)
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if want := cty.StringVal("B"); !got.RawEquals(want) {
+		t.Fatalf("wrong result after changing inputs %#v; want %#v", got, want)
+	}
+}
+
+func TestScopeEvalExpr_cacheNondeterministic(t *testing.T) {
+	// Each of these expressions is evaluated twice, as it would be for two
+	// instances of a resource, and must produce a different result each time.
+	tests := map[string]string{
+		"uuid":                 `uuid()`,
+		"ed25519_keypair":      `ed25519_keypair()`,
+		"rsa_keypair":          `rsa_keypair(2048)`,
+		"tls_self_signed_cert": `tls_self_signed_cert(local.key, "example.com", 1)`,
+	}
+
+	key, err := funcs.Ed25519KeypairFunc.Call(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, src := range tests {
+		t.Run(name, func(t *testing.T) {
+			expr, parseDiags := hclsyntax.ParseExpression([]byte(src), "test.tf", hcl.Pos{Line: 1, Column: 1})
+			if parseDiags.HasErrors() {
+				t.Fatal(parseDiags.Error())
+			}
+
+			cache := NewExprCache()
+			scope := &Scope{
+				Data: &dataForTests{
+					LocalValues: map[string]cty.Value{
+						"key": key.GetAttr("private_key_pem"),
+					},
+				},
+				ParseRef:  addrs.ParseRef,
+				ExprCache: cache,
+			}
+
+			first, diags := scope.EvalExpr(expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				t.Fatal(diags.Err())
+			}
+			second, diags := scope.EvalExpr(expr, cty.DynamicPseudoType)
+			if diags.HasErrors() {
+				t.Fatal(diags.Err())
+			}
+			if first.RawEquals(second) {
+				t.Fatalf("%s returned %#v twice; the second call was served from the cache", src, first)
+			}
+			if got := len(cache.entries); got != 0 {
+				t.Fatalf("cache has %d entries; want 0", got)
+			}
+		})
+	}
+}
+
+func TestCacheableFunctions(t *testing.T) {
+	scope := &Scope{
+		ConsoleMode: true,
+	}
+	funcs := scope.Functions()
+	// plantimestamp isn't available in console mode.
+	funcs["plantimestamp"] = (&Scope{}).Functions()["plantimestamp"]
+
+	for name := range cacheableFunctions {
+		if _, ok := funcs[name]; !ok {
+			t.Errorf("cacheable function %q is not a function", name)
+		}
+	}
+	for _, name := range impureFunctions {
+		if cacheableFunctions[name] {
+			t.Errorf("impure function %q is cacheable", name)
+		}
+	}
+
+	// These functions aren't impure, because provider configurations need
+	// their results during plan, but they return a different result each
+	// time they're called, so they must never be cached.
+	for _, name := range []string{
+		"ed25519_keypair",
+		"rsa_keypair",
+		"tls_self_signed_cert",
+	} {
+		if cacheableFunctions[name] {
+			t.Errorf("nondeterministic function %q is cacheable", name)
+		}
+	}
+
+	// These functions read files, which can change during an operation,
+	// such as when a provisioner or local-exec writes to them.
+	for _, name := range []string{
+		"dirhash",
+		"file",
+		"filebase64",
+		"filebase64sha256",
+		"filebase64sha512",
+		"fileexists",
+		"filemd5",
+		"fileset",
+		"filesets",
+		"filesha1",
+		"filesha256",
+		"filesha512",
+		"filestat",
+		"templatefile",
+	} {
+		if cacheableFunctions[name] {
+			t.Errorf("filesystem function %q is cacheable", name)
+		}
+	}
+}
+
+func TestScopeEvalExpr_cacheDirhash(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	expr, parseDiags := hclsyntax.ParseExpression([]byte(`dirhash(".")`), "test.tf", hcl.Pos{Line: 1, Column: 1})
+	if parseDiags.HasErrors() {
+		t.Fatal(parseDiags.Error())
+	}
+	if exprCacheable(expr, mustReferences(t, expr)) {
+		t.Fatal("dirhash is cacheable")
+	}
+
+	cache := NewExprCache()
+	scope := &Scope{
+		Data:      &dataForTests{},
+		ParseRef:  addrs.ParseRef,
+		BaseDir:   dir,
+		ExprCache: cache,
+	}
+
+	first, diags := scope.EvalExpr(expr, cty.String)
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+
+	// A file written during the operation must change the result.
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	second, diags := scope.EvalExpr(expr, cty.String)
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	if first.RawEquals(second) {
+		t.Fatalf("dirhash returned %#v before and after adding a file; the second call was served from the cache", first)
+	}
+	if got := len(cache.entries); got != 0 {
+		t.Fatalf("cache has %d entries; want 0", got)
+	}
+}
+
+func mustReferences(t *testing.T, expr hcl.Expression) []*addrs.Reference {
+	t.Helper()
+	refs, diags := ReferencesInExpr(addrs.ParseRef, expr)
+	if diags.HasErrors() {
+		t.Fatal(diags.Err())
+	}
+	return refs
+}
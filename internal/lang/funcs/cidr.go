@@ -270,6 +270,43 @@ var CidrContainsFunc = function.New(&function.Spec{
 	},
 })
 
+// CidrOverlapsFunc constructs a function that checks whether two IP network
+// address prefixes have any addresses in common.
+var CidrOverlapsFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "prefix1",
+			Type: cty.String,
+		},
+		{
+			Name: "prefix2",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Bool),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
+		_, a, err := ipaddr.ParseCIDR(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "invalid CIDR expression: %s", err)
+		}
+		_, b, err := ipaddr.ParseCIDR(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(1, "invalid CIDR expression: %s", err)
+		}
+
+		// As with cidrcontains, comparing prefixes of different address
+		// families is an error rather than a false result.
+		if (a.IP.To4() == nil) != (b.IP.To4() == nil) {
+			return cty.UnknownVal(cty.Bool), fmt.Errorf("address family mismatch: %s vs. %s", args[0].AsString(), args[1].AsString())
+		}
+
+		// Two prefixes overlap exactly when one of them contains the
+		// first address of the other.
+		return cty.BoolVal(a.Contains(b.IP) || b.Contains(a.IP)), nil
+	},
+})
+
 // CidrHost calculates a full host IP address within a given IP network address prefix.
 func CidrHost(prefix, hostnum cty.Value) (cty.Value, error) {
 	return CidrHostFunc.Call([]cty.Value{prefix, hostnum})
@@ -298,3 +335,9 @@ func CidrSubnets(prefix cty.Value, newbits ...cty.Value) (cty.Value, error) {
 func CidrContains(prefix, address cty.Value) (cty.Value, error) {
 	return CidrContainsFunc.Call([]cty.Value{prefix, address})
 }
+
+// CidrOverlaps checks whether two IP network address prefixes have any
+// addresses in common.
+func CidrOverlaps(prefix1, prefix2 cty.Value) (cty.Value, error) {
+	return CidrOverlapsFunc.Call([]cty.Value{prefix1, prefix2})
+}
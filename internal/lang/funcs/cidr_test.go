@@ -520,3 +520,91 @@ func TestCidrContains(t *testing.T) {
 		})
 	}
 }
+
+func TestCidrOverlaps(t *testing.T) {
+	tests := []struct {
+		Prefix1 cty.Value
+		Prefix2 cty.Value
+		Want    cty.Value
+		Err     string
+	}{
+		{
+			// IPv4, one inside the other.
+			cty.StringVal("10.0.0.0/16"),
+			cty.StringVal("10.0.4.0/24"),
+			cty.True,
+			"",
+		},
+		{
+			// IPv4, the other way around.
+			cty.StringVal("10.0.4.0/24"),
+			cty.StringVal("10.0.0.0/16"),
+			cty.True,
+			"",
+		},
+		{
+			// IPv4, adjacent.
+			cty.StringVal("10.0.0.0/24"),
+			cty.StringVal("10.0.1.0/24"),
+			cty.False,
+			"",
+		},
+		{
+			// IPv4, equal.
+			cty.StringVal("192.168.0.0/20"),
+			cty.StringVal("192.168.0.0/20"),
+			cty.True,
+			"",
+		},
+		{
+			// IPv6, overlapping.
+			cty.StringVal("fe80::/48"),
+			cty.StringVal("fe80::/64"),
+			cty.True,
+			"",
+		},
+		{
+			// IPv6, disjoint.
+			cty.StringVal("fe80::/48"),
+			cty.StringVal("fe81::/48"),
+			cty.False,
+			"",
+		},
+		{
+			// Address family mismatch.
+			cty.StringVal("10.0.0.0/8"),
+			cty.StringVal("fe80::/48"),
+			cty.UnknownVal(cty.Bool),
+			"address family mismatch: 10.0.0.0/8 vs. fe80::/48",
+		},
+		{
+			// Not a prefix.
+			cty.StringVal("10.0.0.0/8"),
+			cty.StringVal("10.0.0.1"),
+			cty.UnknownVal(cty.Bool),
+			"invalid CIDR expression: invalid CIDR address: 10.0.0.1",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("cidroverlaps(%#v, %#v)", test.Prefix1, test.Prefix2), func(t *testing.T) {
+			got, err := CidrOverlaps(test.Prefix1, test.Prefix2)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if err.Error() != test.Err {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -131,6 +131,13 @@ var DescriptionList = map[string]descriptionEntry{
 			"",
 		},
 	},
+	"cidroverlaps": {
+		Description: "`cidroverlaps` determines whether two IP network address prefixes given in CIDR notation have any addresses in common.",
+		ParamDescription: []string{
+			"`prefix1` must be given in CIDR notation, as defined in [RFC 4632 section 3.1](https://tools.ietf.org/html/rfc4632#section-3.1).",
+			"`prefix2` must be given in CIDR notation, and must belong to the same address family as `prefix1`.",
+		},
+	},
 	"coalesce": {
 		Description:      "`coalesce` takes any number of arguments and returns the first one that isn't null or an empty string.",
 		ParamDescription: []string{""},
@@ -157,6 +164,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`csvdecode` decodes a string containing CSV-formatted data and produces a list of maps representing that data.",
 		ParamDescription: []string{""},
 	},
+	"dirhash": {
+		Description:      "`dirhash` returns a hash of the names and contents of all of the regular files in a directory tree, except for those matching any of the given exclude patterns. The result only changes when a file is added, removed, renamed or modified.",
+		ParamDescription: []string{"", ""},
+	},
 	"dirname": {
 		Description:      "`dirname` takes a string containing a filesystem path and removes the last portion from it.",
 		ParamDescription: []string{""},
@@ -165,6 +176,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`distinct` takes a list and returns a new list with any duplicate elements removed.",
 		ParamDescription: []string{""},
 	},
+	"ed25519_keypair": {
+		Description:      "`ed25519_keypair` generates a new Ed25519 key pair. The result is ephemeral, so it can only be used where OpenTofu doesn't save it in the plan or state.",
+		ParamDescription: []string{},
+	},
 	"element": {
 		Description:      "`element` retrieves a single element from a list.",
 		ParamDescription: []string{"", ""},
@@ -201,6 +216,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`fileset` enumerates a set of regular file names given a path and pattern. The path is automatically removed from the resulting set of file names and any result still containing path separators always returns forward slash (`/`) as the path separator for cross-system compatibility.",
 		ParamDescription: []string{"", ""},
 	},
+	"filesets": {
+		Description:      "`filesets` enumerates the file names in a directory tree that match an object of options, including lists of `include` and `exclude` patterns. Like `fileset`, the path is removed from the resulting set of file names and forward slash (`/`) is always used as the path separator.",
+		ParamDescription: []string{"", ""},
+	},
 	"filesha1": {
 		Description:      "`filesha1` is a variant of `sha1` that hashes the contents of a given file rather than a literal string.",
 		ParamDescription: []string{""},
@@ -213,6 +232,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`filesha512` is a variant of `sha512` that hashes the contents of a given file rather than a literal string.",
 		ParamDescription: []string{""},
 	},
+	"filestat": {
+		Description:      "`filestat` returns an object describing the file at the given path, with its `size`, `mode`, `type` and `modified` time.",
+		ParamDescription: []string{""},
+	},
 	"flatten": {
 		Description:      "`flatten` takes a list and replaces any elements that are lists with a flattened sequence of the list contents.",
 		ParamDescription: []string{""},
@@ -263,6 +286,13 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`jsonencode` encodes a given value to a string using JSON syntax.",
 		ParamDescription: []string{""},
 	},
+	"jsonpatch": {
+		Description: "`jsonpatch` applies a JSON Patch, as defined in [RFC 6902](https://tools.ietf.org/html/rfc6902), to a JSON document and returns the patched document as a JSON string.",
+		ParamDescription: []string{
+			"The JSON document to patch, as a string.",
+			"The JSON Patch to apply, as a string containing an array of operations.",
+		},
+	},
 	"keys": {
 		Description: "`keys` takes a map and returns a list containing the keys from that map.",
 		ParamDescription: []string{
@@ -353,10 +383,23 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`reverse` takes a sequence and produces a new sequence of the same length with all of the same elements as the given sequence but in reverse order.",
 		ParamDescription: []string{""},
 	},
+	"rsa_keypair": {
+		Description: "`rsa_keypair` generates a new RSA key pair. The result is ephemeral, so it can only be used where OpenTofu doesn't save it in the plan or state.",
+		ParamDescription: []string{
+			"The size of the key's modulus in bits, between 2048 and 8192.",
+		},
+	},
 	"rsadecrypt": {
 		Description:      "`rsadecrypt` decrypts an RSA-encrypted ciphertext, returning the corresponding cleartext.",
 		ParamDescription: []string{"", ""},
 	},
+	"semverconstraint": {
+		Description: "`semverconstraint` determines whether a semantic version meets a version constraint, written in the same syntax as the version constraints for providers and modules.",
+		ParamDescription: []string{
+			"The version to check, such as `1.2.3`.",
+			"The version constraint, such as `>= 1.2.0, < 2.0.0`.",
+		},
+	},
 	"sensitive": {
 		Description:      "`sensitive` takes any value and returns a copy of it marked so that OpenTofu will treat it as sensitive, with the same meaning and behavior as for [sensitive input variables](/language/values/variables#suppressing-values-in-cli-output).",
 		ParamDescription: []string{""},
@@ -429,11 +472,11 @@ var DescriptionList = map[string]descriptionEntry{
 	},
 	"templatefile": {
 		Description:      "`templatefile` reads the file at the given path and renders its content as a template using a supplied set of template variables.",
-		ParamDescription: []string{"", ""},
+		ParamDescription: []string{"", "", ""},
 	},
 	"templatestring": {
 		Description:      "`templatestring` processes the provided string as a template using a supplied set of template variables.",
-		ParamDescription: []string{"", ""},
+		ParamDescription: []string{"", "", ""},
 	},
 	"textdecodebase64": {
 		Description:      "`textdecodebase64` function decodes a string that was previously Base64-encoded, and then interprets the result as characters in a specified character encoding.",
@@ -459,6 +502,15 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`plantimestamp` returns a UTC timestamp string in [RFC 3339](https://tools.ietf.org/html/rfc3339) format, fixed to a constant time representing the time of the plan.",
 		ParamDescription: []string{},
 	},
+	"tls_self_signed_cert": {
+		Description: "`tls_self_signed_cert` creates a self-signed TLS certificate for a private key. The result is ephemeral, so it can only be used where OpenTofu doesn't save it in the plan or state.",
+		ParamDescription: []string{
+			"The private key in PEM format, such as the `private_key_pem` attribute of the result of `ed25519_keypair` or `rsa_keypair`.",
+			"The common name of the certificate's subject.",
+			"The number of hours from now that the certificate is valid for.",
+			"Additional DNS names and IP addresses that the certificate is valid for.",
+		},
+	},
 	"title": {
 		Description:      "`title` converts the first letter of each word in the given string to uppercase.",
 		ParamDescription: []string{""},
@@ -487,6 +539,14 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`tostring` converts its argument to a string value.",
 		ParamDescription: []string{""},
 	},
+	"tomldecode": {
+		Description:      "`tomldecode` parses a string as a TOML document, and produces an object representing its top-level table.",
+		ParamDescription: []string{""},
+	},
+	"tomlencode": {
+		Description:      "`tomlencode` encodes a given object or map as a [TOML](https://toml.io/) document.",
+		ParamDescription: []string{""},
+	},
 	"transpose": {
 		Description:      "`transpose` takes a map of lists of strings and swaps the keys and values to produce a new map of lists of strings.",
 		ParamDescription: []string{""},
@@ -530,6 +590,10 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`urldecode` applies URL decoding to a given encoded string.",
 		ParamDescription: []string{""},
 	},
+	"urlparse": {
+		Description:      "`urlparse` parses a URL into an object with its scheme, user information, host, port, path, query string and fragment.",
+		ParamDescription: []string{""},
+	},
 	"uuid": {
 		Description:      "`uuid` generates a unique identifier string.",
 		ParamDescription: []string{},
@@ -546,10 +610,18 @@ var DescriptionList = map[string]descriptionEntry{
 		Description:      "`yamldecode` parses a string as a subset of YAML, and produces a representation of its value.",
 		ParamDescription: []string{""},
 	},
+	"yamldecodeall": {
+		Description:      "`yamldecodeall` parses a string as a stream of YAML documents separated by `---`, and produces a tuple with a representation of each document.",
+		ParamDescription: []string{""},
+	},
 	"yamlencode": {
 		Description:      "`yamlencode` encodes a given value to a string using [YAML 1.2](https://yaml.org/spec/1.2/spec.html) block syntax.",
 		ParamDescription: []string{""},
 	},
+	"yamlencodeall": {
+		Description:      "`yamlencodeall` encodes each element of a given list or tuple as a separate YAML document, and joins the documents with `---` separators.",
+		ParamDescription: []string{""},
+	},
 	"zipmap": {
 		Description:      "`zipmap` constructs a map from a list of keys and a corresponding list of values.",
 		ParamDescription: []string{"", ""},
@@ -247,6 +247,67 @@ var URLDecodeFunc = function.New(&function.Spec{
 	},
 })
 
+// urlParseType is the type of the object that urlparse returns.
+var urlParseType = cty.Object(map[string]cty.Type{
+	"scheme":    cty.String,
+	"username":  cty.String,
+	"password":  cty.String,
+	"host":      cty.String,
+	"port":      cty.String,
+	"path":      cty.String,
+	"raw_query": cty.String,
+	"query":     cty.Map(cty.List(cty.String)),
+	"fragment":  cty.String,
+})
+
+// URLParseFunc constructs a function that parses a URL into its components.
+var URLParseFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "url",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(urlParseType),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		u, err := url.Parse(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(retType), fmt.Errorf("failed to parse URL: %w", err)
+		}
+		query, err := url.ParseQuery(u.RawQuery)
+		if err != nil {
+			return cty.UnknownVal(retType), fmt.Errorf("failed to parse URL query string: %w", err)
+		}
+
+		params := make(map[string]cty.Value, len(query))
+		for name, values := range query {
+			vals := make([]cty.Value, len(values))
+			for i, v := range values {
+				vals[i] = cty.StringVal(v)
+			}
+			params[name] = cty.ListVal(vals)
+		}
+		queryVal := cty.MapValEmpty(cty.List(cty.String))
+		if len(params) != 0 {
+			queryVal = cty.MapVal(params)
+		}
+
+		password, _ := u.User.Password()
+		return cty.ObjectVal(map[string]cty.Value{
+			"scheme":    cty.StringVal(u.Scheme),
+			"username":  cty.StringVal(u.User.Username()),
+			"password":  cty.StringVal(password),
+			"host":      cty.StringVal(u.Hostname()),
+			"port":      cty.StringVal(u.Port()),
+			"path":      cty.StringVal(u.Path),
+			"raw_query": cty.StringVal(u.RawQuery),
+			"query":     queryVal,
+			"fragment":  cty.StringVal(u.Fragment),
+		}), nil
+	},
+})
+
 // Base64Decode decodes a string containing a base64 sequence.
 //
 // OpenTofu uses the "standard" Base64 alphabet as defined in RFC 4648 section 4.
@@ -311,6 +372,12 @@ func URLDecode(str cty.Value) (cty.Value, error) {
 	return URLDecodeFunc.Call([]cty.Value{str})
 }
 
+// URLParse parses a URL into an object with its scheme, user information,
+// host, port, path, query string and fragment.
+func URLParse(str cty.Value) (cty.Value, error) {
+	return URLParseFunc.Call([]cty.Value{str})
+}
+
 // TextEncodeBase64 applies Base64 encoding to a string that was encoded before with a target encoding.
 //
 // OpenTofu uses the "standard" Base64 alphabet as defined in RFC 4648 section 4.
@@ -0,0 +1,234 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+// keypairType is the type of the result of the key pair generation
+// functions.
+var keypairType = cty.Object(map[string]cty.Type{
+	"private_key_pem":     cty.String,
+	"private_key_openssh": cty.String,
+	"public_key_pem":      cty.String,
+	"public_key_openssh":  cty.String,
+})
+
+const (
+	rsaKeypairMinBits = 2048
+	rsaKeypairMaxBits = 8192
+)
+
+// Ed25519KeypairFunc constructs a function that generates a new Ed25519 key
+// pair.
+//
+// Each call generates a different key, and the result is ephemeral, so it
+// can only be used where OpenTofu doesn't save it in the plan or state.
+var Ed25519KeypairFunc = function.New(&function.Spec{
+	Params:       []function.Parameter{},
+	Type:         function.StaticReturnType(keypairType),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		_, priv, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return cty.UnknownVal(retType), fmt.Errorf("failed to generate key: %w", err)
+		}
+		return keypairVal(priv)
+	},
+})
+
+// RsaKeypairFunc constructs a function that generates a new RSA key pair
+// with a modulus of the given number of bits.
+//
+// Each call generates a different key, and the result is ephemeral, so it
+// can only be used where OpenTofu doesn't save it in the plan or state.
+var RsaKeypairFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:         "bits",
+			Type:         cty.Number,
+			AllowUnknown: true,
+		},
+	},
+	Type:         function.StaticReturnType(keypairType),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		if !args[0].IsKnown() {
+			return ephemeralVal(cty.UnknownVal(retType)), nil
+		}
+		bf := args[0].AsBigFloat()
+		bits, acc := bf.Int64()
+		if acc != big.Exact || bits < rsaKeypairMinBits || bits > rsaKeypairMaxBits {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "must be a whole number between %d and %d", rsaKeypairMinBits, rsaKeypairMaxBits)
+		}
+
+		priv, err := rsa.GenerateKey(rand.Reader, int(bits))
+		if err != nil {
+			return cty.UnknownVal(retType), fmt.Errorf("failed to generate key: %w", err)
+		}
+		return keypairVal(priv)
+	},
+})
+
+// TLSSelfSignedCertFunc constructs a function that creates a self-signed
+// TLS certificate for a private key given in PEM format.
+//
+// The certificate is valid from the time of the call for the given number of
+// hours, for the given common name and for any additional DNS names or IP
+// addresses. Each call creates a different certificate, and the result is
+// ephemeral, so it can only be used where OpenTofu doesn't save it in the
+// plan or state.
+var TLSSelfSignedCertFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name:         "private_key_pem",
+			Type:         cty.String,
+			AllowUnknown: true,
+		},
+		{
+			Name:         "common_name",
+			Type:         cty.String,
+			AllowUnknown: true,
+		},
+		{
+			Name:         "validity_hours",
+			Type:         cty.Number,
+			AllowUnknown: true,
+		},
+	},
+	VarParam: &function.Parameter{
+		Name:         "names",
+		Type:         cty.String,
+		AllowUnknown: true,
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		for _, arg := range args {
+			if !arg.IsKnown() {
+				return ephemeralVal(cty.UnknownVal(retType)), nil
+			}
+		}
+
+		rawKey, err := ssh.ParseRawPrivateKey([]byte(args[0].AsString()))
+		if err != nil {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "invalid private key: %s", err)
+		}
+		key, ok := rawKey.(crypto.Signer)
+		if !ok {
+			return cty.UnknownVal(retType), function.NewArgErrorf(0, "unsupported private key type %T", rawKey)
+		}
+
+		hours, acc := args[2].AsBigFloat().Int64()
+		if acc != big.Exact || hours < 1 {
+			return cty.UnknownVal(retType), function.NewArgErrorf(2, "must be a whole number of hours greater than zero")
+		}
+
+		serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+		if err != nil {
+			return cty.UnknownVal(retType), fmt.Errorf("failed to generate serial number: %w", err)
+		}
+		now := time.Now()
+		template := &x509.Certificate{
+			SerialNumber:          serial,
+			Subject:               pkix.Name{CommonName: args[1].AsString()},
+			NotBefore:             now,
+			NotAfter:              now.Add(time.Duration(hours) * time.Hour),
+			KeyUsage:              x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+			BasicConstraintsValid: true,
+		}
+		if _, ok := key.(*rsa.PrivateKey); ok {
+			template.KeyUsage |= x509.KeyUsageKeyEncipherment
+		}
+		for _, arg := range args[3:] {
+			name := arg.AsString()
+			if ip := net.ParseIP(name); ip != nil {
+				template.IPAddresses = append(template.IPAddresses, ip)
+			} else {
+				template.DNSNames = append(template.DNSNames, name)
+			}
+		}
+
+		der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+		if err != nil {
+			return cty.UnknownVal(retType), fmt.Errorf("failed to create certificate: %w", err)
+		}
+		return ephemeralVal(cty.StringVal(string(pem.EncodeToMemory(&pem.Block{
+			Type:  "CERTIFICATE",
+			Bytes: der,
+		})))), nil
+	},
+})
+
+// keypairVal returns the result of a key pair generation function for the
+// given private key.
+func keypairVal(priv crypto.Signer) (cty.Value, error) {
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return cty.UnknownVal(keypairType), fmt.Errorf("failed to encode private key: %w", err)
+	}
+	privSSH, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		return cty.UnknownVal(keypairType), fmt.Errorf("failed to encode private key: %w", err)
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(priv.Public())
+	if err != nil {
+		return cty.UnknownVal(keypairType), fmt.Errorf("failed to encode public key: %w", err)
+	}
+	pubSSH, err := ssh.NewPublicKey(priv.Public())
+	if err != nil {
+		return cty.UnknownVal(keypairType), fmt.Errorf("failed to encode public key: %w", err)
+	}
+
+	return ephemeralVal(cty.ObjectVal(map[string]cty.Value{
+		"private_key_pem":     cty.StringVal(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}))),
+		"private_key_openssh": cty.StringVal(string(pem.EncodeToMemory(privSSH))),
+		"public_key_pem":      cty.StringVal(string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))),
+		"public_key_openssh":  cty.StringVal(string(ssh.MarshalAuthorizedKey(pubSSH))),
+	})), nil
+}
+
+// ephemeralVal marks the result of a key generation function as both
+// sensitive and ephemeral, so that it's hidden in the UI and can't be saved
+// in the plan or state.
+func ephemeralVal(v cty.Value) cty.Value {
+	return v.Mark(marks.Sensitive).Mark(marks.Ephemeral)
+}
+
+// Ed25519Keypair generates a new Ed25519 key pair.
+func Ed25519Keypair() (cty.Value, error) {
+	return Ed25519KeypairFunc.Call([]cty.Value{})
+}
+
+// RsaKeypair generates a new RSA key pair with the given number of bits.
+func RsaKeypair(bits cty.Value) (cty.Value, error) {
+	return RsaKeypairFunc.Call([]cty.Value{bits})
+}
+
+// TLSSelfSignedCert creates a self-signed TLS certificate for the given
+// private key.
+func TLSSelfSignedCert(privateKeyPEM, commonName, validityHours cty.Value, names ...cty.Value) (cty.Value, error) {
+	args := []cty.Value{privateKeyPEM, commonName, validityHours}
+	return TLSSelfSignedCertFunc.Call(append(args, names...))
+}
@@ -0,0 +1,164 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+// testKeypair checks that the given result of a key pair generation function
+// is marked as ephemeral and contains consistent encodings of a key pair, and
+// returns the private key.
+func testKeypair(t *testing.T, got cty.Value) interface{} {
+	t.Helper()
+
+	if !got.HasMark(marks.Ephemeral) || !got.HasMark(marks.Sensitive) {
+		t.Fatalf("result is not marked as sensitive and ephemeral: %#v", got)
+	}
+	got, _ = got.Unmark()
+
+	block, _ := pem.Decode([]byte(got.GetAttr("private_key_pem").AsString()))
+	if block == nil || block.Type != "PRIVATE KEY" {
+		t.Fatalf("private_key_pem is not a PEM-encoded private key")
+	}
+	priv, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("invalid private_key_pem: %s", err)
+	}
+	if _, err := ssh.ParseRawPrivateKey([]byte(got.GetAttr("private_key_openssh").AsString())); err != nil {
+		t.Fatalf("invalid private_key_openssh: %s", err)
+	}
+
+	block, _ = pem.Decode([]byte(got.GetAttr("public_key_pem").AsString()))
+	if block == nil || block.Type != "PUBLIC KEY" {
+		t.Fatalf("public_key_pem is not a PEM-encoded public key")
+	}
+	pub, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		t.Fatalf("invalid public_key_pem: %s", err)
+	}
+	sshPub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(got.GetAttr("public_key_openssh").AsString()))
+	if err != nil {
+		t.Fatalf("invalid public_key_openssh: %s", err)
+	}
+
+	wantPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(sshPub.Marshal()) != string(wantPub.Marshal()) {
+		t.Fatalf("public_key_openssh doesn't match public_key_pem")
+	}
+	return priv
+}
+
+func TestEd25519Keypair(t *testing.T) {
+	got, err := Ed25519Keypair()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, ok := testKeypair(t, got).(ed25519.PrivateKey); !ok {
+		t.Fatalf("wrong type of private key")
+	}
+
+	// Each call generates a new key.
+	other, err := Ed25519Keypair()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.RawEquals(other) {
+		t.Fatalf("generated the same key twice")
+	}
+}
+
+func TestRsaKeypair(t *testing.T) {
+	got, err := RsaKeypair(cty.NumberIntVal(2048))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	priv, ok := testKeypair(t, got).(*rsa.PrivateKey)
+	if !ok {
+		t.Fatalf("wrong type of private key")
+	}
+	if got := priv.N.BitLen(); got != 2048 {
+		t.Fatalf("wrong key size %d", got)
+	}
+
+	got, err = RsaKeypair(cty.UnknownVal(cty.Number))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got.IsKnown() || !got.HasMark(marks.Ephemeral) {
+		t.Fatalf("result for unknown size is not an ephemeral unknown value: %#v", got)
+	}
+
+	for _, bits := range []cty.Value{cty.NumberIntVal(1024), cty.NumberIntVal(16384), cty.NumberFloatVal(2048.5)} {
+		if _, err := RsaKeypair(bits); err == nil || !strings.Contains(err.Error(), "whole number between 2048 and 8192") {
+			t.Errorf("wrong error for %#v: %v", bits, err)
+		}
+	}
+}
+
+func TestTLSSelfSignedCert(t *testing.T) {
+	keypair, err := Ed25519Keypair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	keypair, _ = keypair.Unmark()
+	key := keypair.GetAttr("private_key_pem")
+
+	before := time.Now().Truncate(time.Second)
+	got, err := TLSSelfSignedCert(key, cty.StringVal("example.com"), cty.NumberIntVal(24), cty.StringVal("www.example.com"), cty.StringVal("10.0.0.1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !got.HasMark(marks.Ephemeral) {
+		t.Fatalf("result is not marked as ephemeral: %#v", got)
+	}
+	got, _ = got.Unmark()
+
+	block, _ := pem.Decode([]byte(got.AsString()))
+	if block == nil || block.Type != "CERTIFICATE" {
+		t.Fatalf("result is not a PEM-encoded certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("invalid certificate: %s", err)
+	}
+	if err := cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err != nil {
+		t.Fatalf("certificate is not self-signed: %s", err)
+	}
+	if cert.Subject.CommonName != "example.com" {
+		t.Errorf("wrong common name %q", cert.Subject.CommonName)
+	}
+	if len(cert.DNSNames) != 1 || cert.DNSNames[0] != "www.example.com" {
+		t.Errorf("wrong DNS names %q", cert.DNSNames)
+	}
+	if len(cert.IPAddresses) != 1 || cert.IPAddresses[0].String() != "10.0.0.1" {
+		t.Errorf("wrong IP addresses %q", cert.IPAddresses)
+	}
+	if cert.NotBefore.Before(before) || cert.NotAfter.Sub(cert.NotBefore) != 24*time.Hour {
+		t.Errorf("wrong validity period %s to %s", cert.NotBefore, cert.NotAfter)
+	}
+
+	if _, err := TLSSelfSignedCert(cty.StringVal("not a key"), cty.StringVal("example.com"), cty.NumberIntVal(1)); err == nil || !strings.Contains(err.Error(), "invalid private key") {
+		t.Errorf("wrong error for an invalid key: %v", err)
+	}
+	if _, err := TLSSelfSignedCert(key, cty.StringVal("example.com"), cty.NumberIntVal(0)); err == nil || !strings.Contains(err.Error(), "greater than zero") {
+		t.Errorf("wrong error for an invalid validity period: %v", err)
+	}
+}
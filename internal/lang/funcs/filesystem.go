@@ -117,6 +117,10 @@ func (err ErrorTemplateRecursionLimit) Error() string {
 //
 // As a special exception, a referenced template file may call the templatefile
 // function, with a recursion depth limit providing an error when reached
+//
+// An optional third argument can restrict the functions available to the
+// template and provide fragments that the template can render by calling the
+// fragment function.
 func MakeTemplateFileFunc(baseDir string, funcsCb func() map[string]function.Function) function.Function {
 	return makeTemplateFileFuncImpl(baseDir, funcsCb, 0)
 }
@@ -174,11 +178,20 @@ func makeTemplateFileFuncImpl(baseDir string, funcsCb func() map[string]function
 	}
 
 	return function.New(&function.Spec{
-		Params: params,
+		Params:   params,
+		VarParam: templateOptionsParam,
 		Type: func(args []cty.Value) (cty.Type, error) {
 			if !(args[0].IsKnown() && args[1].IsKnown()) {
 				return cty.DynamicPseudoType, nil
 			}
+			opts, err := decodeTemplateOptions(args[2:])
+			if opts == nil {
+				return cty.DynamicPseudoType, err
+			}
+			funcs, err := opts.funcs(funcsCbDepth(), 0)
+			if err != nil {
+				return cty.DynamicPseudoType, err
+			}
 
 			// We'll render our template now to see what result type it produces.
 			// A template consisting only of a single interpolation an potentially
@@ -192,17 +205,26 @@ func makeTemplateFileFuncImpl(baseDir string, funcsCb func() map[string]function
 
 			// This is safe even if args[1] contains unknowns because the HCL
 			// template renderer itself knows how to short-circuit those.
-			val, err := renderTemplate(expr, args[1], funcsCbDepth())
+			val, err := renderTemplate(expr, args[1], funcs)
 			return val.Type(), err
 		},
 		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			opts, err := decodeTemplateOptions(args[2:])
+			if opts == nil {
+				return cty.UnknownVal(retType), err
+			}
+			funcs, err := opts.funcs(funcsCbDepth(), 0)
+			if err != nil {
+				return cty.DynamicVal, err
+			}
+
 			pathArg, pathMarks := args[0].Unmark()
 			expr, err := loadTmpl(pathArg.AsString(), pathMarks)
 			if err != nil {
 				return cty.DynamicVal, err
 			}
 
-			result, err := renderTemplate(expr, args[1], funcsCbDepth())
+			result, err := renderTemplate(expr, args[1], funcs)
 			return result.WithMarks(pathMarks), err
 		},
 	})
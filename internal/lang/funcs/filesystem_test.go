@@ -242,7 +242,7 @@ func Test_templateMaxRecursionDepth(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(fmt.Sprintf("templateMaxRecursion(%s)", test.Input), func(t *testing.T) {
-			os.Setenv("TF_TEMPLATE_RECURSION_DEPTH", test.Input)
+			t.Setenv("TF_TEMPLATE_RECURSION_DEPTH", test.Input)
 			got, err := templateMaxRecursionDepth()
 			if test.Err != "" {
 				if err == nil {
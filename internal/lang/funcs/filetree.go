@@ -0,0 +1,440 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// fileStatType is the type of the result of the filestat function.
+var fileStatType = cty.Object(map[string]cty.Type{
+	"size":     cty.Number,
+	"mode":     cty.String,
+	"type":     cty.String,
+	"modified": cty.String,
+})
+
+// fileTreeOptions controls which entries walkFileTree returns.
+type fileTreeOptions struct {
+	Include         []string
+	Exclude         []string
+	IncludeDirs     bool
+	IncludeHidden   bool
+	FollowSymlinks  bool
+	CaseInsensitive bool
+}
+
+// MakeDirHashFunc constructs a function that returns a hash of the names and
+// contents of all of the regular files in a directory tree, except for those
+// matching any of the given exclude patterns.
+//
+// The result uses the same "h1:" format as the hashes of Go modules, so it
+// only changes when a file is added, removed, renamed or modified.
+func MakeDirHashFunc(baseDir string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name:        "path",
+				Type:        cty.String,
+				AllowMarked: true,
+			},
+		},
+		VarParam: &function.Parameter{
+			Name:        "excludes",
+			Type:        cty.String,
+			AllowMarked: true,
+		},
+		Type:         function.StaticReturnType(cty.String),
+		RefineResult: refineNotNull,
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			root, marks, err := fileTreeRoot(baseDir, args[0])
+			if err != nil {
+				return cty.UnknownVal(cty.String), err
+			}
+
+			opts := fileTreeOptions{
+				Include:       []string{"**"},
+				IncludeHidden: true,
+			}
+			for _, arg := range args[1:] {
+				excludeArg, excludeMarks := arg.Unmark()
+				marks = append(marks, excludeMarks)
+				opts.Exclude = append(opts.Exclude, excludeArg.AsString())
+			}
+			if err := validateFileTreePatterns(opts.Exclude); err != nil {
+				return cty.UnknownVal(cty.String), err
+			}
+
+			files, err := walkFileTree(root, opts)
+			if err != nil {
+				return cty.UnknownVal(cty.String), fmt.Errorf("failed to read directory %s: %w", redactIfSensitive(root, marks...), err)
+			}
+
+			hash, err := dirhash.Hash1(files, func(name string) (io.ReadCloser, error) {
+				return os.Open(filepath.Join(root, filepath.FromSlash(name)))
+			})
+			if err != nil {
+				return cty.UnknownVal(cty.String), fmt.Errorf("failed to hash directory %s: %w", redactIfSensitive(root, marks...), err)
+			}
+			return cty.StringVal(hash).WithMarks(marks...), nil
+		},
+	})
+}
+
+// MakeFileSetsFunc constructs a function that enumerates the entries of a
+// directory tree that match the include and exclude patterns and other
+// settings of the given options object.
+//
+// It's a more flexible variant of fileset for selecting the files to package
+// into an archive.
+func MakeFileSetsFunc(baseDir string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name:        "path",
+				Type:        cty.String,
+				AllowMarked: true,
+			},
+			{
+				Name:        "options",
+				Type:        cty.DynamicPseudoType,
+				AllowMarked: true,
+			},
+		},
+		Type:         function.StaticReturnType(cty.Set(cty.String)),
+		RefineResult: refineNotNull,
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			optionsArg, optionsMarks := args[1].UnmarkDeep()
+			if !optionsArg.IsWhollyKnown() {
+				return cty.UnknownVal(retType), nil
+			}
+			opts, err := fileSetsOptions(optionsArg)
+			if err != nil {
+				return cty.UnknownVal(retType), function.NewArgError(1, err)
+			}
+
+			root, marks, err := fileTreeRoot(baseDir, args[0])
+			if err != nil {
+				return cty.UnknownVal(retType), err
+			}
+			marks = append(marks, optionsMarks)
+
+			matches, err := walkFileTree(root, opts)
+			if err != nil {
+				return cty.UnknownVal(retType), fmt.Errorf("failed to read directory %s: %w", redactIfSensitive(root, marks...), err)
+			}
+			if len(matches) == 0 {
+				return cty.SetValEmpty(cty.String).WithMarks(marks...), nil
+			}
+
+			matchVals := make([]cty.Value, len(matches))
+			for i, match := range matches {
+				matchVals[i] = cty.StringVal(match)
+			}
+			return cty.SetVal(matchVals).WithMarks(marks...), nil
+		},
+	})
+}
+
+// MakeFileStatFunc constructs a function that returns the size, permissions,
+// type and modification time of a file.
+//
+// Symbolic links are not followed, so the result describes the link itself.
+func MakeFileStatFunc(baseDir string) function.Function {
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name:        "path",
+				Type:        cty.String,
+				AllowMarked: true,
+			},
+		},
+		Type:         function.StaticReturnType(fileStatType),
+		RefineResult: refineNotNull,
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			pathArg, pathMarks := args[0].Unmark()
+			path, err := resolveFileTreePath(baseDir, pathArg.AsString())
+			if err != nil {
+				return cty.UnknownVal(retType), err
+			}
+
+			fi, err := os.Lstat(path)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return cty.UnknownVal(retType), fmt.Errorf("no file exists at %s", redactIfSensitive(path, pathMarks))
+				}
+				return cty.UnknownVal(retType), fmt.Errorf("failed to stat %s: %w", redactIfSensitive(path, pathMarks), err)
+			}
+
+			var typ string
+			switch mode := fi.Mode(); {
+			case mode.IsRegular():
+				typ = "file"
+			case mode.IsDir():
+				typ = "dir"
+			case mode&fs.ModeSymlink != 0:
+				typ = "symlink"
+			default:
+				typ = "other"
+			}
+
+			return cty.ObjectVal(map[string]cty.Value{
+				"size":     cty.NumberIntVal(fi.Size()),
+				"mode":     cty.StringVal(fmt.Sprintf("%04o", fi.Mode().Perm())),
+				"type":     cty.StringVal(typ),
+				"modified": cty.StringVal(fi.ModTime().UTC().Format(time.RFC3339)),
+			}).WithMarks(pathMarks), nil
+		},
+	})
+}
+
+// fileTreeRoot resolves the path argument of a directory tree function and
+// checks that it refers to a directory.
+func fileTreeRoot(baseDir string, pathArg cty.Value) (string, []cty.ValueMarks, error) {
+	pathArg, pathMarks := pathArg.Unmark()
+	marks := []cty.ValueMarks{pathMarks}
+
+	root, err := resolveFileTreePath(baseDir, pathArg.AsString())
+	if err != nil {
+		return "", marks, err
+	}
+	fi, err := os.Stat(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", marks, fmt.Errorf("no directory exists at %s", redactIfSensitive(root, marks...))
+		}
+		return "", marks, fmt.Errorf("failed to stat %s: %w", redactIfSensitive(root, marks...), err)
+	}
+	if !fi.IsDir() {
+		return "", marks, fmt.Errorf("%s is not a directory", redactIfSensitive(root, marks...))
+	}
+	return root, marks, nil
+}
+
+// resolveFileTreePath expands a leading ~ in the given path and makes it
+// relative to baseDir if it isn't already absolute.
+func resolveFileTreePath(baseDir, path string) (string, error) {
+	path, err := homedir.Expand(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand ~: %w", err)
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(baseDir, path)
+	}
+	return filepath.Clean(path), nil
+}
+
+// fileSetsOptions decodes the options argument of the filesets function.
+func fileSetsOptions(v cty.Value) (fileTreeOptions, error) {
+	opts := fileTreeOptions{
+		Include:       []string{"**"},
+		IncludeHidden: true,
+	}
+	if v.IsNull() {
+		return opts, nil
+	}
+	if !v.Type().IsObjectType() && !v.Type().IsMapType() {
+		return opts, fmt.Errorf("must be an object")
+	}
+
+	for it := v.ElementIterator(); it.Next(); {
+		k, attr := it.Element()
+		name := k.AsString()
+		if attr.IsNull() {
+			continue
+		}
+
+		var err error
+		switch name {
+		case "include":
+			opts.Include, err = fileSetsPatterns(attr)
+		case "exclude":
+			opts.Exclude, err = fileSetsPatterns(attr)
+		case "include_dirs":
+			opts.IncludeDirs, err = fileSetsBool(attr)
+		case "include_hidden":
+			opts.IncludeHidden, err = fileSetsBool(attr)
+		case "follow_symlinks":
+			opts.FollowSymlinks, err = fileSetsBool(attr)
+		case "case_insensitive":
+			opts.CaseInsensitive, err = fileSetsBool(attr)
+		default:
+			return opts, fmt.Errorf("unsupported option %q", name)
+		}
+		if err != nil {
+			return opts, fmt.Errorf("invalid value for %q: %w", name, err)
+		}
+	}
+
+	if opts.CaseInsensitive {
+		for i, pattern := range opts.Include {
+			opts.Include[i] = strings.ToLower(pattern)
+		}
+		for i, pattern := range opts.Exclude {
+			opts.Exclude[i] = strings.ToLower(pattern)
+		}
+	}
+	return opts, nil
+}
+
+func fileSetsPatterns(v cty.Value) ([]string, error) {
+	v, err := convert.Convert(v, cty.List(cty.String))
+	if err != nil {
+		return nil, fmt.Errorf("must be a list of strings")
+	}
+	var patterns []string
+	for it := v.ElementIterator(); it.Next(); {
+		_, pattern := it.Element()
+		if pattern.IsNull() {
+			return nil, fmt.Errorf("patterns must not be null")
+		}
+		patterns = append(patterns, pattern.AsString())
+	}
+	return patterns, validateFileTreePatterns(patterns)
+}
+
+func fileSetsBool(v cty.Value) (bool, error) {
+	v, err := convert.Convert(v, cty.Bool)
+	if err != nil {
+		return false, fmt.Errorf("must be a bool")
+	}
+	return v.True(), nil
+}
+
+func validateFileTreePatterns(patterns []string) error {
+	for _, pattern := range patterns {
+		if !doublestar.ValidatePattern(pattern) {
+			return fmt.Errorf("invalid pattern %q", pattern)
+		}
+	}
+	return nil
+}
+
+// walkFileTree returns the sorted, slash-separated paths relative to root of
+// the regular files, and optionally directories, beneath root that match at
+// least one of the include patterns and none of the exclude patterns.
+//
+// A directory matching an exclude pattern is skipped along with everything
+// inside it.
+func walkFileTree(root string, opts fileTreeOptions) ([]string, error) {
+	var matches []string
+	visited := map[string]bool{}
+
+	match := func(patterns []string, name string) bool {
+		if opts.CaseInsensitive {
+			name = strings.ToLower(name)
+		}
+		for _, pattern := range patterns {
+			// The patterns were validated already, so matching can't fail.
+			if ok, _ := doublestar.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var walk func(dir, rel string) error
+	walk = func(dir, rel string) error {
+		if opts.FollowSymlinks {
+			// Guard against symlinks that lead back to a directory that
+			// we're already walking.
+			realDir, err := filepath.EvalSymlinks(dir)
+			if err != nil {
+				return err
+			}
+			if visited[realDir] {
+				return nil
+			}
+			visited[realDir] = true
+			defer delete(visited, realDir)
+		}
+
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if !opts.IncludeHidden && strings.HasPrefix(name, ".") {
+				continue
+			}
+			entryRel := path.Join(rel, name)
+			if match(opts.Exclude, entryRel) {
+				continue
+			}
+
+			entryPath := filepath.Join(dir, name)
+			mode := entry.Type()
+			if mode&fs.ModeSymlink != 0 {
+				fi, err := os.Stat(entryPath)
+				if err != nil {
+					// Dangling symlinks don't refer to anything to include.
+					continue
+				}
+				mode = fi.Mode().Type()
+				if mode.IsDir() && !opts.FollowSymlinks {
+					continue
+				}
+			}
+
+			switch {
+			case mode.IsDir():
+				if opts.IncludeDirs && match(opts.Include, entryRel) {
+					matches = append(matches, entryRel)
+				}
+				if err := walk(entryPath, entryRel); err != nil {
+					return err
+				}
+			case mode.IsRegular():
+				if match(opts.Include, entryRel) {
+					matches = append(matches, entryRel)
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := walk(root, ""); err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// DirHash returns a hash of the contents of the directory tree at the given
+// path, except for the files matching any of the given exclude patterns.
+func DirHash(baseDir string, path cty.Value, excludes ...cty.Value) (cty.Value, error) {
+	fn := MakeDirHashFunc(baseDir)
+	return fn.Call(append([]cty.Value{path}, excludes...))
+}
+
+// FileSets enumerates the entries of the directory tree at the given path
+// that match the given options.
+func FileSets(baseDir string, path, options cty.Value) (cty.Value, error) {
+	fn := MakeFileSetsFunc(baseDir)
+	return fn.Call([]cty.Value{path, options})
+}
+
+// FileStat returns information about the file at the given path.
+func FileStat(baseDir string, path cty.Value) (cty.Value, error) {
+	fn := MakeFileStatFunc(baseDir)
+	return fn.Call([]cty.Value{path})
+}
@@ -0,0 +1,362 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+// testFileTree creates a directory tree for the file tree function tests
+// and returns its path.
+func testFileTree(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.py":                    "print('hello')\n",
+		"README.md":                  "# app\n",
+		".env":                       "SECRET=1\n",
+		"lib/util.py":                "def util(): pass\n",
+		"lib/__pycache__/util.pyc":   "compiled",
+		"node_modules/dep/index.js":  "module.exports = {}\n",
+		".git/HEAD":                  "ref: refs/heads/main\n",
+		"Docs/Guide.MD":              "guide\n",
+		"lib/nested/deep/module.py":  "x = 1\n",
+		"lib/nested/deep/.gitignore": "*.pyc\n",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestDirHash(t *testing.T) {
+	dir := testFileTree(t)
+
+	hash := func(excludes ...string) cty.Value {
+		t.Helper()
+		var args []cty.Value
+		for _, exclude := range excludes {
+			args = append(args, cty.StringVal(exclude))
+		}
+		got, err := DirHash(dir, cty.StringVal("."), args...)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		return got
+	}
+
+	all := hash()
+	if !strings.HasPrefix(all.AsString(), "h1:") {
+		t.Fatalf("wrong hash format %q", all.AsString())
+	}
+	if got := hash(); !got.RawEquals(all) {
+		t.Fatalf("hash is not stable\nfirst:  %#v\nsecond: %#v", all, got)
+	}
+
+	excluded := hash("node_modules", "**/__pycache__", ".git")
+	if excluded.RawEquals(all) {
+		t.Fatalf("excludes didn't change the hash")
+	}
+
+	// Changing an excluded file doesn't change the hash, but changing an
+	// included one does.
+	if err := os.WriteFile(filepath.Join(dir, "node_modules", "dep", "index.js"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := hash("node_modules", "**/__pycache__", ".git"); !got.RawEquals(excluded) {
+		t.Errorf("changing an excluded file changed the hash")
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.py"), []byte("changed"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := hash("node_modules", "**/__pycache__", ".git"); got.RawEquals(excluded) {
+		t.Errorf("changing an included file didn't change the hash")
+	}
+
+	// Renaming a file changes the hash even though the contents are the same.
+	before := hash()
+	if err := os.Rename(filepath.Join(dir, "README.md"), filepath.Join(dir, "README.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if got := hash(); got.RawEquals(before) {
+		t.Errorf("renaming a file didn't change the hash")
+	}
+
+	got, err := DirHash(dir, cty.StringVal(".").Mark(marks.Sensitive))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !got.HasMark(marks.Sensitive) {
+		t.Errorf("result is not marked as sensitive")
+	}
+
+	if _, err := DirHash(dir, cty.StringVal("main.py")); err == nil || !strings.Contains(err.Error(), "is not a directory") {
+		t.Errorf("wrong error for a file: %v", err)
+	}
+	if _, err := DirHash(dir, cty.StringVal("missing")); err == nil || !strings.Contains(err.Error(), "no directory exists") {
+		t.Errorf("wrong error for a missing directory: %v", err)
+	}
+	if _, err := DirHash(dir, cty.StringVal("."), cty.StringVal("[")); err == nil || !strings.Contains(err.Error(), "invalid pattern") {
+		t.Errorf("wrong error for an invalid pattern: %v", err)
+	}
+}
+
+func TestFileSets(t *testing.T) {
+	dir := testFileTree(t)
+
+	tests := []struct {
+		Options cty.Value
+		Want    []string
+		Err     string
+	}{
+		{
+			cty.EmptyObjectVal,
+			[]string{
+				".env",
+				".git/HEAD",
+				"Docs/Guide.MD",
+				"README.md",
+				"lib/__pycache__/util.pyc",
+				"lib/nested/deep/.gitignore",
+				"lib/nested/deep/module.py",
+				"lib/util.py",
+				"main.py",
+				"node_modules/dep/index.js",
+			},
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"include": cty.TupleVal([]cty.Value{cty.StringVal("**/*.py")}),
+			}),
+			[]string{
+				"lib/nested/deep/module.py",
+				"lib/util.py",
+				"main.py",
+			},
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"exclude": cty.TupleVal([]cty.Value{
+					cty.StringVal("node_modules"),
+					cty.StringVal("**/__pycache__"),
+					cty.StringVal("lib/nested"),
+				}),
+				"include_hidden": cty.False,
+			}),
+			[]string{
+				"Docs/Guide.MD",
+				"README.md",
+				"lib/util.py",
+				"main.py",
+			},
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"include":      cty.TupleVal([]cty.Value{cty.StringVal("lib/**")}),
+				"exclude":      cty.TupleVal([]cty.Value{cty.StringVal("**/__pycache__")}),
+				"include_dirs": cty.True,
+			}),
+			[]string{
+				"lib",
+				"lib/nested",
+				"lib/nested/deep",
+				"lib/nested/deep/.gitignore",
+				"lib/nested/deep/module.py",
+				"lib/util.py",
+			},
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"include":          cty.TupleVal([]cty.Value{cty.StringVal("**/*.md")}),
+				"case_insensitive": cty.True,
+			}),
+			[]string{
+				"Docs/Guide.MD",
+				"README.md",
+			},
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"include": cty.TupleVal([]cty.Value{cty.StringVal("*.rb")}),
+			}),
+			nil,
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"includes": cty.TupleVal([]cty.Value{cty.StringVal("*.py")}),
+			}),
+			nil,
+			`unsupported option "includes"`,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"include_dirs": cty.StringVal("maybe"),
+			}),
+			nil,
+			`invalid value for "include_dirs": must be a bool`,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"exclude": cty.TupleVal([]cty.Value{cty.StringVal("[")}),
+			}),
+			nil,
+			`invalid pattern "["`,
+		},
+		{
+			cty.StringVal("**/*.py"),
+			nil,
+			`must be an object`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("filesets(%#v)", test.Options), func(t *testing.T) {
+			got, err := FileSets(dir, cty.StringVal("."), test.Options)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if !strings.Contains(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			want := cty.SetValEmpty(cty.String)
+			if len(test.Want) > 0 {
+				var wantVals []cty.Value
+				for _, name := range test.Want {
+					wantVals = append(wantVals, cty.StringVal(name))
+				}
+				want = cty.SetVal(wantVals)
+			}
+			if !got.RawEquals(want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestFileSets_symlinks(t *testing.T) {
+	dir := testFileTree(t)
+	if err := os.Symlink(filepath.Join(dir, "lib"), filepath.Join(dir, "Docs", "lib")); err != nil {
+		t.Skipf("can't create symlinks: %s", err)
+	}
+	// A symlink back to an ancestor is skipped rather than causing an
+	// infinite walk.
+	if err := os.Symlink(dir, filepath.Join(dir, "lib", "nested", "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	options := func(follow bool) cty.Value {
+		return cty.ObjectVal(map[string]cty.Value{
+			"include":         cty.TupleVal([]cty.Value{cty.StringVal("Docs/**")}),
+			"exclude":         cty.TupleVal([]cty.Value{cty.StringVal("**/__pycache__")}),
+			"include_hidden":  cty.False,
+			"follow_symlinks": cty.BoolVal(follow),
+		})
+	}
+
+	got, err := FileSets(dir, cty.StringVal("."), options(false))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := cty.SetVal([]cty.Value{cty.StringVal("Docs/Guide.MD")})
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result without following symlinks\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	got, err = FileSets(dir, cty.StringVal("."), options(true))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want = cty.SetVal([]cty.Value{
+		cty.StringVal("Docs/Guide.MD"),
+		cty.StringVal("Docs/lib/nested/deep/module.py"),
+		cty.StringVal("Docs/lib/util.py"),
+	})
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result when following symlinks\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
+
+func TestFileStat(t *testing.T) {
+	dir := testFileTree(t)
+	if err := os.Chmod(filepath.Join(dir, "main.py"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := FileStat(dir, cty.StringVal("main.py"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	fi, err := os.Stat(filepath.Join(dir, "main.py"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := cty.ObjectVal(map[string]cty.Value{
+		"size":     cty.NumberIntVal(15),
+		"mode":     cty.StringVal(fmt.Sprintf("%04o", fi.Mode().Perm())),
+		"type":     cty.StringVal("file"),
+		"modified": cty.StringVal(fi.ModTime().UTC().Format("2006-01-02T15:04:05Z")),
+	})
+	if !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	got, err = FileStat(dir, cty.StringVal("lib").Mark(marks.Sensitive))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !got.HasMark(marks.Sensitive) {
+		t.Errorf("result is not marked as sensitive")
+	}
+	got, _ = got.Unmark()
+	if got := got.GetAttr("type"); !got.RawEquals(cty.StringVal("dir")) {
+		t.Errorf("wrong type for a directory %#v", got)
+	}
+
+	if err := os.Symlink("main.py", filepath.Join(dir, "link.py")); err == nil {
+		got, err := FileStat(dir, cty.StringVal("link.py"))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got := got.GetAttr("type"); !got.RawEquals(cty.StringVal("symlink")) {
+			t.Errorf("wrong type for a symlink %#v", got)
+		}
+	}
+
+	if _, err := FileStat(dir, cty.StringVal("missing.py")); err == nil || !strings.Contains(err.Error(), "no file exists") {
+		t.Errorf("wrong error for a missing file: %v", err)
+	}
+	if _, err := FileStat(dir, cty.StringVal("missing.py").Mark(marks.Sensitive)); err == nil || strings.Contains(err.Error(), "missing.py") {
+		t.Errorf("error for a sensitive path doesn't redact it: %v", err)
+	}
+}
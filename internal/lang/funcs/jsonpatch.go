@@ -0,0 +1,347 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// JSONPatchFunc constructs a function that applies a JSON Patch, as defined
+// in RFC 6902, to a JSON document. Both the document and the patch are given
+// as JSON strings, and the result is the patched document as a JSON string.
+var JSONPatchFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "document",
+			Type: cty.String,
+		},
+		{
+			Name: "patch",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		doc, err := jsonPatchDecode(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(0, "invalid JSON document: %s", err)
+		}
+
+		var ops []jsonPatchOperation
+		dec := json.NewDecoder(strings.NewReader(args[1].AsString()))
+		dec.UseNumber()
+		if err := dec.Decode(&ops); err != nil {
+			return cty.UnknownVal(cty.String), function.NewArgErrorf(1, "invalid JSON patch: %s", err)
+		}
+
+		for i, op := range ops {
+			doc, err = op.apply(doc)
+			if err != nil {
+				return cty.UnknownVal(cty.String), function.NewArgErrorf(1, "patch operation %d (%q) failed: %s", i, op.Op, err)
+			}
+		}
+
+		result, err := json.Marshal(doc)
+		if err != nil {
+			return cty.UnknownVal(cty.String), err
+		}
+		return cty.StringVal(string(result)), nil
+	},
+})
+
+// JSONPatch applies a JSON Patch, as defined in RFC 6902, to a JSON document.
+func JSONPatch(document, patch cty.Value) (cty.Value, error) {
+	return JSONPatchFunc.Call([]cty.Value{document, patch})
+}
+
+// jsonPatchOperation is a single operation of a JSON Patch document.
+type jsonPatchOperation struct {
+	Op    string           `json:"op"`
+	Path  *string          `json:"path"`
+	From  *string          `json:"from"`
+	Value *json.RawMessage `json:"value"`
+}
+
+func (op jsonPatchOperation) apply(doc interface{}) (interface{}, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("missing \"path\"")
+	}
+	path, err := parseJSONPointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("missing \"value\"")
+		}
+		value, err := jsonPatchDecode(string(*op.Value))
+		if err != nil {
+			return nil, err
+		}
+		switch op.Op {
+		case "add":
+			return jsonPatchAdd(doc, path, value)
+		case "replace":
+			return jsonPointerUpdate(doc, path, value, func(parent interface{}, key string) (interface{}, error) {
+				if _, err := jsonPointerChild(parent, key); err != nil {
+					return nil, err
+				}
+				return jsonPointerSet(parent, key, value)
+			})
+		default:
+			current, err := jsonPointerGet(doc, path)
+			if err != nil {
+				return nil, err
+			}
+			if !jsonPatchEqual(current, value) {
+				return nil, fmt.Errorf("the value does not match")
+			}
+			return doc, nil
+		}
+
+	case "remove":
+		return jsonPatchRemove(doc, path)
+
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("missing \"from\"")
+		}
+		from, err := parseJSONPointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		value, err := jsonPointerGet(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			// The copy must not share any maps or slices with the original.
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			if value, err = jsonPatchDecode(string(raw)); err != nil {
+				return nil, err
+			}
+			return jsonPatchAdd(doc, path, value)
+		}
+		if *op.Path != *op.From && strings.HasPrefix(*op.Path, *op.From+"/") {
+			return nil, fmt.Errorf("cannot move a value into one of its own children")
+		}
+		if doc, err = jsonPatchRemove(doc, from); err != nil {
+			return nil, err
+		}
+		return jsonPatchAdd(doc, path, value)
+
+	default:
+		return nil, fmt.Errorf("unsupported operation; must be one of add, remove, replace, move, copy, or test")
+	}
+}
+
+func jsonPatchDecode(src string) (interface{}, error) {
+	var ret interface{}
+	dec := json.NewDecoder(strings.NewReader(src))
+	dec.UseNumber()
+	if err := dec.Decode(&ret); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("extra characters after the JSON value")
+	}
+	return ret, nil
+}
+
+func jsonPatchAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	return jsonPointerUpdate(doc, path, value, func(parent interface{}, key string) (interface{}, error) {
+		list, ok := parent.([]interface{})
+		if !ok {
+			return jsonPointerSet(parent, key, value)
+		}
+		idx := len(list)
+		if key != "-" {
+			var err error
+			if idx, err = jsonPointerIndex(key, len(list)+1); err != nil {
+				return nil, err
+			}
+		}
+		ret := make([]interface{}, 0, len(list)+1)
+		ret = append(ret, list[:idx]...)
+		ret = append(ret, value)
+		return append(ret, list[idx:]...), nil
+	})
+}
+
+func jsonPatchRemove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("cannot remove the whole document")
+	}
+	return jsonPointerUpdate(doc, path, nil, func(parent interface{}, key string) (interface{}, error) {
+		if _, err := jsonPointerChild(parent, key); err != nil {
+			return nil, err
+		}
+		switch parent := parent.(type) {
+		case map[string]interface{}:
+			delete(parent, key)
+			return parent, nil
+		default:
+			list := parent.([]interface{})
+			idx, _ := jsonPointerIndex(key, len(list))
+			ret := make([]interface{}, 0, len(list)-1)
+			ret = append(ret, list[:idx]...)
+			return append(ret, list[idx+1:]...), nil
+		}
+	})
+}
+
+// parseJSONPointer parses a JSON Pointer, as defined in RFC 6901, into its
+// reference tokens.
+func parseJSONPointer(ptr string) ([]string, error) {
+	if ptr == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(ptr, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q: must be empty or start with \"/\"", ptr)
+	}
+	tokens := strings.Split(ptr[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+// jsonPointerUpdate calls fn with the value containing the location at the
+// given path and the last reference token of the path, and returns the
+// document with that containing value replaced by the result. If the path
+// refers to the whole document, the result is root instead.
+func jsonPointerUpdate(doc interface{}, path []string, root interface{}, fn func(parent interface{}, key string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 0 {
+		return root, nil
+	}
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	child, err := jsonPointerChild(doc, path[0])
+	if err != nil {
+		return nil, err
+	}
+	child, err = jsonPointerUpdate(child, path[1:], root, fn)
+	if err != nil {
+		return nil, err
+	}
+	return jsonPointerSet(doc, path[0], child)
+}
+
+func jsonPointerGet(doc interface{}, path []string) (interface{}, error) {
+	for _, key := range path {
+		var err error
+		if doc, err = jsonPointerChild(doc, key); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+func jsonPointerChild(parent interface{}, key string) (interface{}, error) {
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		child, ok := parent[key]
+		if !ok {
+			return nil, fmt.Errorf("no member named %q", key)
+		}
+		return child, nil
+	case []interface{}:
+		idx, err := jsonPointerIndex(key, len(parent))
+		if err != nil {
+			return nil, err
+		}
+		return parent[idx], nil
+	default:
+		return nil, fmt.Errorf("cannot find %q in a value that is not an object or an array", key)
+	}
+}
+
+func jsonPointerSet(parent interface{}, key string, value interface{}) (interface{}, error) {
+	switch parent := parent.(type) {
+	case map[string]interface{}:
+		parent[key] = value
+		return parent, nil
+	case []interface{}:
+		idx, err := jsonPointerIndex(key, len(parent))
+		if err != nil {
+			return nil, err
+		}
+		parent[idx] = value
+		return parent, nil
+	default:
+		return nil, fmt.Errorf("cannot set %q in a value that is not an object or an array", key)
+	}
+}
+
+// jsonPointerIndex parses an array index reference token, which must be less
+// than limit.
+func jsonPointerIndex(key string, limit int) (int, error) {
+	if key == "" || (len(key) > 1 && key[0] == '0') || strings.TrimLeft(key, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", key)
+	}
+	idx, err := strconv.Atoi(key)
+	if err != nil || idx >= limit {
+		return 0, fmt.Errorf("array index %s is out of range", key)
+	}
+	return idx, nil
+}
+
+// jsonPatchEqual compares two decoded JSON values, treating numbers as equal
+// if they have the same value regardless of how they are written.
+func jsonPatchEqual(a, b interface{}) bool {
+	switch a := a.(type) {
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for k, av := range a {
+			bv, ok := b[k]
+			if !ok || !jsonPatchEqual(av, bv) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !jsonPatchEqual(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		af, _, aErr := big.ParseFloat(string(a), 10, 512, big.ToNearestEven)
+		bf, _, bErr := big.ParseFloat(string(b), 10, 512, big.ToNearestEven)
+		if aErr != nil || bErr != nil {
+			return a == b
+		}
+		return af.Cmp(bf) == 0
+	default:
+		return a == b
+	}
+}
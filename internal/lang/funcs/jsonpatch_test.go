@@ -0,0 +1,181 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		Document string
+		Patch    string
+		Want     string
+		Err      string
+	}{
+		// The examples from RFC 6902 Appendix A.
+		{
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz","value":"qux"}]`,
+			`{"baz":"qux","foo":"bar"}`,
+			``,
+		},
+		{
+			`{"foo":["bar","baz"]}`,
+			`[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			`{"foo":["bar","qux","baz"]}`,
+			``,
+		},
+		{
+			`{"baz":"qux","foo":"bar"}`,
+			`[{"op":"remove","path":"/baz"}]`,
+			`{"foo":"bar"}`,
+			``,
+		},
+		{
+			`{"foo":["bar","qux","baz"]}`,
+			`[{"op":"remove","path":"/foo/1"}]`,
+			`{"foo":["bar","baz"]}`,
+			``,
+		},
+		{
+			`{"baz":"qux","foo":"bar"}`,
+			`[{"op":"replace","path":"/baz","value":"boo"}]`,
+			`{"baz":"boo","foo":"bar"}`,
+			``,
+		},
+		{
+			`{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			`[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			`{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+			``,
+		},
+		{
+			`{"foo":["all","grass","cows","eat"]}`,
+			`[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			`{"foo":["all","cows","eat","grass"]}`,
+			``,
+		},
+		{
+			`{"baz":"qux","foo":["a",2,"c"]}`,
+			`[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`,
+			`{"baz":"qux","foo":["a",2,"c"]}`,
+			``,
+		},
+		{
+			`{"baz":"qux"}`,
+			`[{"op":"test","path":"/baz","value":"bar"}]`,
+			``,
+			`patch operation 0 ("test") failed: the value does not match`,
+		},
+		{
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/child","value":{"grandchild":{}}}]`,
+			`{"child":{"grandchild":{}},"foo":"bar"}`,
+			``,
+		},
+		{
+			`{"foo":"bar"}`,
+			`[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			``,
+			`patch operation 0 ("add") failed: no member named "baz"`,
+		},
+		{
+			`{"/":9,"~1":10}`,
+			`[{"op":"test","path":"/~01","value":10}]`,
+			`{"/":9,"~1":10}`,
+			``,
+		},
+		{
+			`{"foo":["bar"]}`,
+			`[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			`{"foo":["bar",["abc","def"]]}`,
+			``,
+		},
+
+		// Other cases.
+		{
+			`{"a":{"b":[1,2]}}`,
+			`[{"op":"copy","from":"/a/b","path":"/c"},{"op":"add","path":"/c/0","value":0}]`,
+			`{"a":{"b":[1,2]},"c":[0,1,2]}`,
+			``,
+		},
+		{
+			`{"a":1}`,
+			`[{"op":"replace","path":"","value":[true,null]}]`,
+			`[true,null]`,
+			``,
+		},
+		{
+			`{"a":1}`,
+			`[{"op":"replace","path":"/b","value":2}]`,
+			``,
+			`patch operation 0 ("replace") failed: no member named "b"`,
+		},
+		{
+			`{"a":{"b":1}}`,
+			`[{"op":"move","from":"/a","path":"/a/c"}]`,
+			``,
+			`patch operation 0 ("move") failed: cannot move a value into one of its own children`,
+		},
+		{
+			`[1,2]`,
+			`[{"op":"remove","path":"/2"}]`,
+			``,
+			`patch operation 0 ("remove") failed: array index 2 is out of range`,
+		},
+		{
+			`[1,2]`,
+			`[{"op":"remove","path":"/01"}]`,
+			``,
+			`patch operation 0 ("remove") failed: invalid array index "01"`,
+		},
+		{
+			`{}`,
+			`[{"op":"frobnicate","path":"/a"}]`,
+			``,
+			`patch operation 0 ("frobnicate") failed: unsupported operation`,
+		},
+		{
+			`{}`,
+			`{"op":"add","path":"/a","value":1}`,
+			``,
+			`invalid JSON patch`,
+		},
+		{
+			`{`,
+			`[]`,
+			``,
+			`invalid JSON document`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("jsonpatch(%q, %q)", test.Document, test.Patch), func(t *testing.T) {
+			got, err := JSONPatch(cty.StringVal(test.Document), cty.StringVal(test.Patch))
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if !strings.Contains(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if want := cty.StringVal(test.Want); !got.RawEquals(want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+			}
+		})
+	}
+}
@@ -60,7 +60,7 @@ func renderTemplate(expr hcl.Expression, varsVal cty.Value, funcs map[string]fun
 		for _, diag := range diags {
 			// Roll up recursive errors
 			if extra, ok := diag.Extra.(hclsyntax.FunctionCallDiagExtra); ok {
-				if extra.CalledFunctionName() == "templatefile" {
+				if name := extra.CalledFunctionName(); name == "templatefile" || name == templateFragmentFuncName {
 					err := extra.FunctionCallError()
 					if err, ok := err.(ErrorTemplateRecursionLimit); ok {
 						return cty.DynamicVal, ErrorTemplateRecursionLimit{sources: append(err.sources, diag.Subject.String())}
@@ -0,0 +1,48 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"github.com/apparentlymart/go-versions/versions"
+	"github.com/apparentlymart/go-versions/versions/constraints"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// SemverConstraintFunc constructs a function that checks whether a semantic
+// version meets a version constraint, written in the same syntax as the
+// version constraints for providers and modules.
+var SemverConstraintFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "version",
+			Type: cty.String,
+		},
+		{
+			Name: "constraint",
+			Type: cty.String,
+		},
+	},
+	Type:         function.StaticReturnType(cty.Bool),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		version, err := versions.ParseVersion(args[0].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(0, "invalid version: %s", err)
+		}
+		spec, err := constraints.ParseRubyStyleMulti(args[1].AsString())
+		if err != nil {
+			return cty.UnknownVal(cty.Bool), function.NewArgErrorf(1, "invalid version constraint: %s", err)
+		}
+		return cty.BoolVal(versions.MeetingConstraints(spec).Has(version)), nil
+	},
+})
+
+// SemverConstraint checks whether a semantic version meets a version
+// constraint.
+func SemverConstraint(version, constraint cty.Value) (cty.Value, error) {
+	return SemverConstraintFunc.Call([]cty.Value{version, constraint})
+}
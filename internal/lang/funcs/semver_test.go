@@ -0,0 +1,97 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestSemverConstraint(t *testing.T) {
+	tests := []struct {
+		Version    cty.Value
+		Constraint cty.Value
+		Want       cty.Value
+		Err        bool
+	}{
+		{
+			cty.StringVal("1.2.3"),
+			cty.StringVal(">= 1.0.0"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("1.2.3"),
+			cty.StringVal(">= 1.0.0, < 1.2.0"),
+			cty.False,
+			false,
+		},
+		{
+			cty.StringVal("1.2.9"),
+			cty.StringVal("~> 1.2.0"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("1.3.0"),
+			cty.StringVal("~> 1.2.0"),
+			cty.False,
+			false,
+		},
+		{
+			cty.StringVal("2.0.0"),
+			cty.StringVal("!= 2.0.0"),
+			cty.False,
+			false,
+		},
+		{
+			// Prereleases only meet constraints that select them exactly.
+			cty.StringVal("1.3.0-beta1"),
+			cty.StringVal(">= 1.0.0"),
+			cty.False,
+			false,
+		},
+		{
+			cty.StringVal("1.3.0-beta1"),
+			cty.StringVal("1.3.0-beta1"),
+			cty.True,
+			false,
+		},
+		{
+			cty.StringVal("not-a-version"),
+			cty.StringVal(">= 1.0.0"),
+			cty.UnknownVal(cty.Bool),
+			true,
+		},
+		{
+			cty.StringVal("1.0.0"),
+			cty.StringVal("=> 1.0.0"),
+			cty.UnknownVal(cty.Bool),
+			true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("semverconstraint(%#v, %#v)", test.Version, test.Constraint), func(t *testing.T) {
+			got, err := SemverConstraint(test.Version, test.Constraint)
+
+			if test.Err {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -30,8 +30,14 @@ var SensitiveFunc = function.New(&function.Spec{
 		return args[0].Type(), nil
 	},
 	Impl: func(args []cty.Value, retType cty.Type) (ret cty.Value, err error) {
-		val, _ := args[0].Unmark()
-		return val.Mark(marks.Sensitive), nil
+		val, valMarks := args[0].Unmark()
+		ret = val.Mark(marks.Sensitive)
+		if _, ok := valMarks[marks.Ephemeral]; ok {
+			// Marking a value as sensitive must not make it possible to
+			// save an ephemeral value.
+			ret = ret.Mark(marks.Ephemeral)
+		}
+		return ret, nil
 	},
 })
 
@@ -236,3 +236,15 @@ func TestIsSensitive(t *testing.T) {
 		})
 	}
 }
+
+func TestSensitive_ephemeral(t *testing.T) {
+	// Marking an ephemeral value as sensitive must keep it ephemeral, so that
+	// it still can't be saved.
+	got, err := Sensitive(cty.StringVal("key").Mark(marks.Ephemeral))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !got.HasMark(marks.Sensitive) || !got.HasMark(marks.Ephemeral) {
+		t.Fatalf("wrong marks on result %#v", got)
+	}
+}
@@ -176,6 +176,9 @@ const (
 // MakeTemplateStringFunc constructs a function that takes a string and
 // an arbitrary object of named values and attempts to render that string
 // as a template using HCL template syntax.
+//
+// As with templatefile, an optional third argument can restrict the functions
+// available to the template and provide fragments for it to render.
 func MakeTemplateStringFunc(content string, funcsCb func() map[string]function.Function) function.Function {
 
 	params := []function.Parameter{
@@ -201,11 +204,20 @@ func MakeTemplateStringFunc(content string, funcsCb func() map[string]function.F
 	}
 
 	return function.New(&function.Spec{
-		Params: params,
+		Params:   params,
+		VarParam: templateOptionsParam,
 		Type: func(args []cty.Value) (cty.Type, error) {
 			if !(args[0].IsKnown() && args[1].IsKnown()) {
 				return cty.DynamicPseudoType, nil
 			}
+			opts, err := decodeTemplateOptions(args[2:])
+			if opts == nil {
+				return cty.DynamicPseudoType, err
+			}
+			funcs, err := opts.funcs(funcsCb(), 0)
+			if err != nil {
+				return cty.DynamicPseudoType, err
+			}
 
 			// We'll render our template now to see what result type it produces.
 			// A template consisting only of a single interpolation can potentially
@@ -218,16 +230,25 @@ func MakeTemplateStringFunc(content string, funcsCb func() map[string]function.F
 
 			// This is safe even if args[1] contains unknowns because the HCL
 			// template renderer itself knows how to short-circuit those.
-			val, err := renderTemplate(expr, args[1], funcsCb())
+			val, err := renderTemplate(expr, args[1], funcs)
 			return val.Type(), err
 		},
 		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			opts, err := decodeTemplateOptions(args[2:])
+			if opts == nil {
+				return cty.UnknownVal(retType), err
+			}
+			funcs, err := opts.funcs(funcsCb(), 0)
+			if err != nil {
+				return cty.DynamicVal, err
+			}
+
 			dataArg, dataMarks := args[0].Unmark()
 			expr, err := loadTmpl(dataArg.AsString(), dataMarks)
 			if err != nil {
 				return cty.DynamicVal, err
 			}
-			result, err := renderTemplate(expr, args[1], funcsCb())
+			result, err := renderTemplate(expr, args[1], funcs)
 			return result.WithMarks(dataMarks), err
 		},
 	})
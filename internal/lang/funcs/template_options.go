@@ -0,0 +1,213 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+)
+
+// templateFragmentFuncName is the name of the function that templates can
+// call to render one of the fragments given in their options.
+const templateFragmentFuncName = "fragment"
+
+// templateOptionsArgIdx is the index of the optional options argument of
+// both templatefile and templatestring.
+const templateOptionsArgIdx = 2
+
+// templateOptions represents the optional final argument of templatefile
+// and templatestring.
+type templateOptions struct {
+	// functions, if not nil, is the set of the only functions that the
+	// template is allowed to call.
+	functions map[string]bool
+
+	// fragments are the templates that the main template and the fragments
+	// themselves can render by calling the fragment function.
+	fragments map[string]hcl.Expression
+}
+
+// templateOptionsParam is the variadic parameter that templatefile and
+// templatestring use to accept their optional options argument.
+var templateOptionsParam = &function.Parameter{
+	Name:      "options",
+	Type:      cty.DynamicPseudoType,
+	AllowNull: true,
+}
+
+// decodeTemplateOptions decodes the optional options argument from the given
+// variadic arguments, returning empty options if the argument is not set.
+//
+// The result is also nil if the options are not yet known, in which case
+// the caller must return an unknown result.
+func decodeTemplateOptions(args []cty.Value) (*templateOptions, error) {
+	switch len(args) {
+	case 0:
+		return &templateOptions{}, nil
+	case 1:
+		// continues below
+	default:
+		return nil, function.NewArgErrorf(templateOptionsArgIdx+1, "too many arguments; only one options object is allowed")
+	}
+
+	val := args[0]
+	if !val.IsWhollyKnown() {
+		return nil, nil
+	}
+	if val.IsNull() {
+		return &templateOptions{}, nil
+	}
+	if ty := val.Type(); !(ty.IsObjectType() || ty.IsMapType()) {
+		return nil, function.NewArgErrorf(templateOptionsArgIdx, "invalid options value: must be an object")
+	}
+
+	opts := &templateOptions{}
+	for it := val.ElementIterator(); it.Next(); {
+		k, v := it.Element()
+		switch name := k.AsString(); name {
+		case "functions":
+			if v.IsNull() {
+				continue
+			}
+			if ty := v.Type(); !(ty.IsListType() || ty.IsSetType() || ty.IsTupleType()) {
+				return nil, function.NewArgErrorf(templateOptionsArgIdx, "invalid options value: functions must be a list of function names")
+			}
+			opts.functions = make(map[string]bool)
+			for it := v.ElementIterator(); it.Next(); {
+				_, fn := it.Element()
+				if fn.IsNull() || fn.Type() != cty.String {
+					return nil, function.NewArgErrorf(templateOptionsArgIdx, "invalid options value: functions must be a list of function names")
+				}
+				opts.functions[fn.AsString()] = true
+			}
+		case "fragments":
+			if v.IsNull() {
+				continue
+			}
+			if ty := v.Type(); !(ty.IsObjectType() || ty.IsMapType()) {
+				return nil, function.NewArgErrorf(templateOptionsArgIdx, "invalid options value: fragments must be a map of template strings")
+			}
+			opts.fragments = make(map[string]hcl.Expression)
+			for it := v.ElementIterator(); it.Next(); {
+				k, src := it.Element()
+				fragName := k.AsString()
+				if src.IsNull() || src.Type() != cty.String {
+					return nil, function.NewArgErrorf(templateOptionsArgIdx, "invalid options value: fragment %q must be a template string", fragName)
+				}
+				expr, diags := hclsyntax.ParseTemplate([]byte(src.AsString()), templateFragmentFilename(fragName), hcl.Pos{Line: 1, Column: 1})
+				if diags.HasErrors() {
+					return nil, function.NewArgError(templateOptionsArgIdx, diags)
+				}
+				opts.fragments[fragName] = expr
+			}
+		default:
+			return nil, function.NewArgErrorf(templateOptionsArgIdx, "invalid options value: unsupported option %q; the supported options are \"functions\" and \"fragments\"", name)
+		}
+	}
+	return opts, nil
+}
+
+func templateFragmentFilename(name string) string {
+	return fmt.Sprintf("<fragment %q>", name)
+}
+
+// funcs returns the functions that a template rendered with these options
+// can call, given all of the functions that would otherwise be available.
+func (o *templateOptions) funcs(available map[string]function.Function, depth int) (map[string]function.Function, error) {
+	if o.functions == nil && o.fragments == nil {
+		return available, nil
+	}
+
+	ret := available
+	if o.functions != nil {
+		names := make([]string, 0, len(o.functions))
+		for name := range o.functions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		ret = make(map[string]function.Function, len(names))
+		for _, name := range names {
+			if name == templateFragmentFuncName {
+				// The fragment function is added below whenever there are
+				// fragments, so allowing it explicitly is redundant but
+				// harmless.
+				continue
+			}
+			fn, ok := available[name]
+			if !ok {
+				return nil, function.NewArgErrorf(templateOptionsArgIdx, "invalid options value: there is no function named %q", name)
+			}
+			ret[name] = fn
+		}
+	}
+
+	if o.fragments != nil {
+		withFragments := make(map[string]function.Function, len(ret)+1)
+		for name, fn := range ret {
+			withFragments[name] = fn
+		}
+		withFragments[templateFragmentFuncName] = o.makeFragmentFunc(available, depth)
+		ret = withFragments
+	}
+	return ret, nil
+}
+
+// makeFragmentFunc constructs the function that templates call to render
+// one of the fragments. Fragments are rendered with the same restrictions
+// as the template that calls them, and can render other fragments up to the
+// same recursion depth limit as templatefile.
+func (o *templateOptions) makeFragmentFunc(available map[string]function.Function, depth int) function.Function {
+	render := func(args []cty.Value) (cty.Value, error) {
+		maxDepth, err := templateMaxRecursionDepth()
+		if err != nil {
+			return cty.DynamicVal, err
+		}
+		if depth >= maxDepth {
+			// Sources will unwind up the stack
+			return cty.DynamicVal, ErrorTemplateRecursionLimit{}
+		}
+
+		name := args[0].AsString()
+		expr, ok := o.fragments[name]
+		if !ok {
+			return cty.DynamicVal, function.NewArgErrorf(0, "there is no template fragment named %q", name)
+		}
+		funcs, err := o.funcs(available, depth+1)
+		if err != nil {
+			return cty.DynamicVal, err
+		}
+		return renderTemplate(expr, args[1], funcs)
+	}
+
+	return function.New(&function.Spec{
+		Params: []function.Parameter{
+			{
+				Name: "name",
+				Type: cty.String,
+			},
+			{
+				Name: "vars",
+				Type: cty.DynamicPseudoType,
+			},
+		},
+		Type: func(args []cty.Value) (cty.Type, error) {
+			if !(args[0].IsKnown() && args[1].IsKnown()) {
+				return cty.DynamicPseudoType, nil
+			}
+			val, err := render(args)
+			return val.Type(), err
+		},
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			return render(args)
+		},
+	})
+}
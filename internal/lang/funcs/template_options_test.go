@@ -0,0 +1,224 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+)
+
+func TestTemplateOptions(t *testing.T) {
+	list := cty.ObjectVal(map[string]cty.Value{
+		"list": cty.ListVal([]cty.Value{
+			cty.StringVal("a"),
+			cty.StringVal("b"),
+		}),
+	})
+
+	tests := map[string]struct {
+		Template string
+		Vars     cty.Value
+		Options  cty.Value
+		Want     cty.Value
+		Err      string
+	}{
+		"no options": {
+			`${upper(join(",", list))}`,
+			list,
+			cty.NullVal(cty.EmptyObject),
+			cty.StringVal("A,B"),
+			``,
+		},
+		"allowed functions": {
+			`${join(",", list)}`,
+			list,
+			cty.ObjectVal(map[string]cty.Value{
+				"functions": cty.TupleVal([]cty.Value{cty.StringVal("join")}),
+			}),
+			cty.StringVal("a,b"),
+			``,
+		},
+		"function not allowed": {
+			`${upper(join(",", list))}`,
+			list,
+			cty.ObjectVal(map[string]cty.Value{
+				"functions": cty.TupleVal([]cty.Value{cty.StringVal("join")}),
+			}),
+			cty.NilVal,
+			`NoFileNeeded:1,3-8: Call to unknown function; There is no function named "upper".`,
+		},
+		"no functions allowed": {
+			`${upper("a")}`,
+			cty.EmptyObjectVal,
+			cty.ObjectVal(map[string]cty.Value{
+				"functions": cty.EmptyTupleVal,
+			}),
+			cty.NilVal,
+			`NoFileNeeded:1,3-8: Call to unknown function; There is no function named "upper".`,
+		},
+		"unknown function in allowlist": {
+			`hello`,
+			cty.EmptyObjectVal,
+			cty.ObjectVal(map[string]cty.Value{
+				"functions": cty.TupleVal([]cty.Value{cty.StringVal("jion")}),
+			}),
+			cty.NilVal,
+			`invalid options value: there is no function named "jion"`,
+		},
+		"fragments": {
+			`%{ for x in list }${fragment("item", { name = x })}%{ endfor }`,
+			list,
+			cty.ObjectVal(map[string]cty.Value{
+				"fragments": cty.ObjectVal(map[string]cty.Value{
+					"item": cty.StringVal("- ${fragment(\"name\", { name = name })}\n"),
+					"name": cty.StringVal(`${upper(name)}`),
+				}),
+			}),
+			cty.StringVal("- A\n- B\n"),
+			``,
+		},
+		"fragments with allowed functions": {
+			`${fragment("item", { name = "a" })}`,
+			cty.EmptyObjectVal,
+			cty.ObjectVal(map[string]cty.Value{
+				"functions": cty.TupleVal([]cty.Value{cty.StringVal("join")}),
+				"fragments": cty.ObjectVal(map[string]cty.Value{
+					"item": cty.StringVal(`${upper(name)}`),
+				}),
+			}),
+			cty.NilVal,
+			`NoFileNeeded:1,3-12: Error in function call; Call to function "fragment" failed: <fragment "item">:1,3-8: Call to unknown function; There is no function named "upper"..`,
+		},
+		"unknown fragment": {
+			`${fragment("header", {})}`,
+			cty.EmptyObjectVal,
+			cty.ObjectVal(map[string]cty.Value{
+				"fragments": cty.ObjectVal(map[string]cty.Value{
+					"footer": cty.StringVal(`bye`),
+				}),
+			}),
+			cty.NilVal,
+			`NoFileNeeded:1,13-19: Invalid function argument; Invalid value for "name" parameter: there is no template fragment named "header".`,
+		},
+		"recursive fragment": {
+			`${fragment("loop", {})}`,
+			cty.EmptyObjectVal,
+			cty.ObjectVal(map[string]cty.Value{
+				"fragments": cty.ObjectVal(map[string]cty.Value{
+					"loop": cty.StringVal(`${fragment("loop", {})}`),
+				}),
+			}),
+			cty.NilVal,
+			`maximum recursion depth 1024 reached in <fragment "loop">:1,3-12, <fragment "loop">:1,3-12 ... `,
+		},
+		"unsupported option": {
+			`hello`,
+			cty.EmptyObjectVal,
+			cty.ObjectVal(map[string]cty.Value{
+				"function": cty.EmptyTupleVal,
+			}),
+			cty.NilVal,
+			`invalid options value: unsupported option "function"; the supported options are "functions" and "fragments"`,
+		},
+		"invalid fragment": {
+			`hello`,
+			cty.EmptyObjectVal,
+			cty.ObjectVal(map[string]cty.Value{
+				"fragments": cty.ObjectVal(map[string]cty.Value{
+					"item": cty.True,
+				}),
+			}),
+			cty.NilVal,
+			`invalid options value: fragment "item" must be a template string`,
+		},
+		"unknown options": {
+			`hello`,
+			cty.EmptyObjectVal,
+			cty.ObjectVal(map[string]cty.Value{
+				"functions": cty.UnknownVal(cty.List(cty.String)),
+			}),
+			cty.DynamicVal,
+			``,
+		},
+	}
+
+	templateStringFn := MakeTemplateStringFunc(".", func() map[string]function.Function {
+		return map[string]function.Function{
+			"join":  stdlib.JoinFunc,
+			"upper": stdlib.UpperFunc,
+		}
+	})
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := templateStringFn.Call([]cty.Value{cty.StringVal(test.Template), test.Vars, test.Options})
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if got, want := err.Error(), test.Err; got != want {
+					t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestTemplateFileOptions(t *testing.T) {
+	templateFileFn := MakeTemplateFileFunc(".", func() map[string]function.Function {
+		return map[string]function.Function{
+			"join":  stdlib.JoinFunc,
+			"upper": stdlib.UpperFunc,
+		}
+	})
+
+	vars := cty.ObjectVal(map[string]cty.Value{
+		"list": cty.ListVal([]cty.Value{
+			cty.StringVal("a"),
+			cty.StringVal("b"),
+		}),
+	})
+	opts := cty.ObjectVal(map[string]cty.Value{
+		"functions": cty.TupleVal([]cty.Value{cty.StringVal("upper")}),
+	})
+
+	_, err := templateFileFn.Call([]cty.Value{cty.StringVal("testdata/func.tmpl"), vars, opts})
+	if err == nil {
+		t.Fatal("succeeded; want error")
+	}
+	want := `testdata/func.tmpl:1,17-21: Call to unknown function; There is no function named "join".`
+	if got := err.Error(); got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+
+	opts = cty.ObjectVal(map[string]cty.Value{
+		"functions": cty.TupleVal([]cty.Value{cty.StringVal("join")}),
+	})
+	got, err := templateFileFn.Call([]cty.Value{cty.StringVal("testdata/func.tmpl"), vars, opts})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if want := cty.StringVal("The items are a, b"); !got.RawEquals(want) {
+		t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, want)
+	}
+
+	_, err = templateFileFn.Call([]cty.Value{cty.StringVal("testdata/func.tmpl"), vars, opts, opts})
+	if got, want := fmt.Sprint(err), "too many arguments; only one options object is allowed"; got != want {
+		t.Errorf("wrong error\ngot:  %s\nwant: %s", got, want)
+	}
+}
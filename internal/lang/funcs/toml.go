@@ -0,0 +1,239 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// TOMLDecodeFunc constructs a function that decodes a TOML document into an
+// object.
+//
+// Tables become objects and arrays become tuples. Dates and times, which
+// have no equivalent in the OpenTofu language, become strings in the same
+// form as they were written.
+var TOMLDecodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "src",
+			Type: cty.String,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		if !args[0].IsKnown() {
+			return cty.DynamicPseudoType, nil
+		}
+		v, err := tomlDecode(args[0].AsString())
+		if err != nil {
+			return cty.NilType, function.NewArgError(0, err)
+		}
+		return v.Type(), nil
+	},
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		v, err := tomlDecode(args[0].AsString())
+		if err != nil {
+			return cty.NilVal, function.NewArgError(0, err)
+		}
+		return v, nil
+	},
+})
+
+// TOMLEncodeFunc constructs a function that encodes an object or map as a
+// TOML document.
+//
+// TOML has no null value, so attributes that are null are left out of the
+// document, and null elements of lists are an error.
+var TOMLEncodeFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "value",
+			Type: cty.DynamicPseudoType,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		val := args[0]
+		ty := val.Type()
+		if !ty.IsObjectType() && !ty.IsMapType() {
+			return cty.NilVal, function.NewArgErrorf(0, "a TOML document must be an object or map, not %s", ty.FriendlyName())
+		}
+		if !val.IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+
+		raw, err := tomlValue(val, cty.Path{})
+		if err != nil {
+			return cty.NilVal, function.NewArgError(0, err)
+		}
+		var buf strings.Builder
+		enc := toml.NewEncoder(&buf)
+		enc.Indent = ""
+		if err := enc.Encode(raw); err != nil {
+			return cty.NilVal, function.NewArgError(0, err)
+		}
+		return cty.StringVal(buf.String()), nil
+	},
+})
+
+// TOMLDecode decodes a TOML document into an object.
+func TOMLDecode(src cty.Value) (cty.Value, error) {
+	return TOMLDecodeFunc.Call([]cty.Value{src})
+}
+
+// TOMLEncode encodes an object or map as a TOML document.
+func TOMLEncode(val cty.Value) (cty.Value, error) {
+	return TOMLEncodeFunc.Call([]cty.Value{val})
+}
+
+func tomlDecode(src string) (cty.Value, error) {
+	var raw map[string]interface{}
+	if _, err := toml.Decode(src, &raw); err != nil {
+		return cty.NilVal, fmt.Errorf("invalid TOML: %w", err)
+	}
+	return tomlCtyValue(raw, cty.Path{})
+}
+
+// tomlCtyValue converts a value decoded from TOML into the equivalent cty
+// value.
+func tomlCtyValue(raw interface{}, path cty.Path) (cty.Value, error) {
+	switch raw := raw.(type) {
+	case string:
+		return cty.StringVal(raw), nil
+	case bool:
+		return cty.BoolVal(raw), nil
+	case int64:
+		return cty.NumberIntVal(raw), nil
+	case float64:
+		if math.IsNaN(raw) {
+			return cty.NilVal, tomlPathErrorf(path, "NaN cannot be represented as a number")
+		}
+		return cty.NumberFloatVal(raw), nil
+	case time.Time:
+		// The decoder marks dates and times without an offset by giving
+		// them one of these locations.
+		switch raw.Location().String() {
+		case "datetime-local":
+			return cty.StringVal(raw.Format("2006-01-02T15:04:05.999999999")), nil
+		case "date-local":
+			return cty.StringVal(raw.Format("2006-01-02")), nil
+		case "time-local":
+			return cty.StringVal(raw.Format("15:04:05.999999999")), nil
+		}
+		return cty.StringVal(raw.Format(time.RFC3339Nano)), nil
+	case []interface{}:
+		vals := make([]cty.Value, len(raw))
+		for i, elem := range raw {
+			v, err := tomlCtyValue(elem, path.IndexInt(i))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals[i] = v
+		}
+		return cty.TupleVal(vals), nil
+	case []map[string]interface{}:
+		vals := make([]cty.Value, len(raw))
+		for i, elem := range raw {
+			v, err := tomlCtyValue(elem, path.IndexInt(i))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			vals[i] = v
+		}
+		return cty.TupleVal(vals), nil
+	case map[string]interface{}:
+		attrs := make(map[string]cty.Value, len(raw))
+		for k, elem := range raw {
+			v, err := tomlCtyValue(elem, path.GetAttr(k))
+			if err != nil {
+				return cty.NilVal, err
+			}
+			attrs[k] = v
+		}
+		return cty.ObjectVal(attrs), nil
+	default:
+		return cty.NilVal, tomlPathErrorf(path, "unsupported TOML value of type %T", raw)
+	}
+}
+
+// tomlValue converts a known cty value into a value that the TOML encoder
+// accepts.
+func tomlValue(val cty.Value, path cty.Path) (interface{}, error) {
+	ty := val.Type()
+	switch {
+	case val.IsNull():
+		return nil, tomlPathErrorf(path, "TOML cannot represent null values")
+	case ty == cty.String:
+		return val.AsString(), nil
+	case ty == cty.Bool:
+		return val.True(), nil
+	case ty == cty.Number:
+		bf := val.AsBigFloat()
+		if bf.IsInt() {
+			if i, acc := bf.Int64(); acc == big.Exact {
+				return i, nil
+			}
+		}
+		f, _ := bf.Float64()
+		return f, nil
+	case ty.IsListType() || ty.IsTupleType() || ty.IsSetType():
+		ret := make([]interface{}, 0, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, elem := it.Element()
+			elemPath := path
+			if !ty.IsSetType() {
+				elemPath = path.Index(k)
+			}
+			v, err := tomlValue(elem, elemPath)
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, v)
+		}
+		return ret, nil
+	case ty.IsMapType() || ty.IsObjectType():
+		ret := make(map[string]interface{}, val.LengthInt())
+		for it := val.ElementIterator(); it.Next(); {
+			k, elem := it.Element()
+			if elem.IsNull() {
+				continue
+			}
+			elemPath := path.Index(k)
+			if ty.IsObjectType() {
+				elemPath = path.GetAttr(k.AsString())
+			}
+			v, err := tomlValue(elem, elemPath)
+			if err != nil {
+				return nil, err
+			}
+			ret[k.AsString()] = v
+		}
+		return ret, nil
+	default:
+		return nil, tomlPathErrorf(path, "TOML cannot represent values of type %s", ty.FriendlyName())
+	}
+}
+
+// tomlPathErrorf returns an error that describes a problem with the value at
+// the given path.
+func tomlPathErrorf(path cty.Path, format string, args ...interface{}) error {
+	msg := fmt.Sprintf(format, args...)
+	if len(path) == 0 {
+		return path.NewErrorf("%s", msg)
+	}
+	return path.NewErrorf("%s: %s", strings.TrimPrefix(tfdiags.FormatCtyPath(path), "."), msg)
+}
@@ -0,0 +1,198 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestTOMLDecode(t *testing.T) {
+	tests := []struct {
+		Src  cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{
+			cty.StringVal(""),
+			cty.EmptyObjectVal,
+			``,
+		},
+		{
+			cty.StringVal(`
+title = "app"
+port = 8080
+ratio = 0.5
+debug = true
+hosts = ["a", "b"]
+mixed = [1, "two"]
+
+[database]
+created = 1979-05-27T07:32:00Z
+day = 1979-05-27
+at = 07:32:00
+local = 1979-05-27T07:32:00
+
+[[servers]]
+name = "alpha"
+
+[[servers]]
+name = "beta"
+`),
+			cty.ObjectVal(map[string]cty.Value{
+				"title": cty.StringVal("app"),
+				"port":  cty.NumberIntVal(8080),
+				"ratio": cty.NumberFloatVal(0.5),
+				"debug": cty.True,
+				"hosts": cty.TupleVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+				"mixed": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.StringVal("two")}),
+				"database": cty.ObjectVal(map[string]cty.Value{
+					"created": cty.StringVal("1979-05-27T07:32:00Z"),
+					"day":     cty.StringVal("1979-05-27"),
+					"at":      cty.StringVal("07:32:00"),
+					"local":   cty.StringVal("1979-05-27T07:32:00"),
+				}),
+				"servers": cty.TupleVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("alpha")}),
+					cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("beta")}),
+				}),
+			}),
+			``,
+		},
+		{
+			cty.StringVal(`a = 1`).Mark(marks.Sensitive),
+			cty.ObjectVal(map[string]cty.Value{"a": cty.NumberIntVal(1)}).Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.DynamicVal,
+			``,
+		},
+		{
+			cty.StringVal("[a]\nb = nan\n"),
+			cty.NilVal,
+			`a.b: NaN cannot be represented`,
+		},
+		{
+			cty.StringVal("a = \n"),
+			cty.NilVal,
+			`invalid TOML`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("tomldecode(%#v)", test.Src), func(t *testing.T) {
+			got, err := TOMLDecode(test.Src)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if !strings.Contains(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestTOMLEncode(t *testing.T) {
+	tests := []struct {
+		Val  cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{
+			cty.EmptyObjectVal,
+			cty.StringVal(""),
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"title":   cty.StringVal("app"),
+				"port":    cty.NumberIntVal(8080),
+				"ratio":   cty.NumberFloatVal(0.5),
+				"unset":   cty.NullVal(cty.String),
+				"hosts":   cty.ListVal([]cty.Value{cty.StringVal("a"), cty.StringVal("b")}),
+				"servers": cty.TupleVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{"name": cty.StringVal("alpha")})}),
+				"database": cty.MapVal(map[string]cty.Value{
+					"user": cty.StringVal("admin"),
+				}),
+			}),
+			cty.StringVal(`hosts = ["a", "b"]
+port = 8080
+ratio = 0.5
+title = "app"
+
+[database]
+user = "admin"
+
+[[servers]]
+name = "alpha"
+`),
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{"a": cty.NumberIntVal(1)}).Mark(marks.Sensitive),
+			cty.StringVal("a = 1\n").Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{"a": cty.UnknownVal(cty.String)}),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{
+				"a": cty.ObjectVal(map[string]cty.Value{
+					"b": cty.ListVal([]cty.Value{cty.NullVal(cty.String)}),
+				}),
+			}),
+			cty.NilVal,
+			`a.b[0]: TOML cannot represent null values`,
+		},
+		{
+			cty.ListVal([]cty.Value{cty.StringVal("a")}),
+			cty.NilVal,
+			`must be an object or map`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("tomlencode(%#v)", test.Val), func(t *testing.T) {
+			got, err := TOMLEncode(test.Val)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if !strings.Contains(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	ctyyaml "github.com/zclconf/go-cty-yaml"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLDecodeAllFunc constructs a function that decodes a stream of YAML
+// documents, such as a file of Kubernetes manifests separated by "---", into
+// a tuple with one element for each document.
+//
+// Each document is decoded in the same way as by yamldecode. Documents with
+// no content, such as those produced by a leading or trailing "---", are
+// skipped.
+var YAMLDecodeAllFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "src",
+			Type: cty.String,
+		},
+	},
+	Type: func(args []cty.Value) (cty.Type, error) {
+		if !args[0].IsKnown() {
+			return cty.DynamicPseudoType, nil
+		}
+		docs, err := yamlSplitDocuments(args[0].AsString())
+		if err != nil {
+			return cty.NilType, function.NewArgError(0, err)
+		}
+		types := make([]cty.Type, len(docs))
+		for i, doc := range docs {
+			ty, err := ctyyaml.Standard.ImpliedType(doc)
+			if err != nil {
+				return cty.NilType, function.NewArgErrorf(0, "document %d: %s", i, err)
+			}
+			types[i] = ty
+		}
+		return cty.Tuple(types), nil
+	},
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		docs, err := yamlSplitDocuments(args[0].AsString())
+		if err != nil {
+			return cty.NilVal, function.NewArgError(0, err)
+		}
+		if len(docs) == 0 {
+			return cty.EmptyTupleVal, nil
+		}
+		types := retType.TupleElementTypes()
+		vals := make([]cty.Value, len(docs))
+		for i, doc := range docs {
+			v, err := ctyyaml.Standard.Unmarshal(doc, types[i])
+			if err != nil {
+				return cty.NilVal, function.NewArgErrorf(0, "document %d: %s", i, err)
+			}
+			vals[i] = v
+		}
+		return cty.TupleVal(vals), nil
+	},
+})
+
+// YAMLEncodeAllFunc constructs a function that encodes each element of a
+// list or tuple as a separate YAML document, in the same way as by
+// yamlencode, and joins the documents with "---" separators.
+var YAMLEncodeAllFunc = function.New(&function.Spec{
+	Params: []function.Parameter{
+		{
+			Name: "values",
+			Type: cty.DynamicPseudoType,
+		},
+	},
+	Type:         function.StaticReturnType(cty.String),
+	RefineResult: refineNotNull,
+	Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+		values := args[0]
+		ty := values.Type()
+		if !ty.IsListType() && !ty.IsTupleType() {
+			return cty.NilVal, function.NewArgErrorf(0, "must be a list or tuple of the values to encode, not %s", ty.FriendlyName())
+		}
+		if !values.IsWhollyKnown() {
+			return cty.UnknownVal(retType), nil
+		}
+
+		var buf strings.Builder
+		for it := values.ElementIterator(); it.Next(); {
+			i, v := it.Element()
+			raw, err := ctyyaml.Standard.Marshal(v)
+			if err != nil {
+				return cty.NilVal, function.NewArgErrorf(0, "element %s: %s", i.AsBigFloat().String(), err)
+			}
+			if buf.Len() > 0 {
+				buf.WriteString("---\n")
+			}
+			buf.Write(raw)
+		}
+		return cty.StringVal(buf.String()), nil
+	},
+})
+
+// YAMLDecodeAll decodes a stream of YAML documents into a tuple with one
+// element for each document.
+func YAMLDecodeAll(src cty.Value) (cty.Value, error) {
+	return YAMLDecodeAllFunc.Call([]cty.Value{src})
+}
+
+// YAMLEncodeAll encodes each element of a list or tuple as a separate YAML
+// document.
+func YAMLEncodeAll(values cty.Value) (cty.Value, error) {
+	return YAMLEncodeAllFunc.Call([]cty.Value{values})
+}
+
+// yamlSplitDocuments splits a stream of YAML documents into the source of
+// each document that has content.
+func yamlSplitDocuments(src string) ([][]byte, error) {
+	var docs [][]byte
+	dec := yaml.NewDecoder(strings.NewReader(src))
+	for {
+		var node yaml.Node
+		err := dec.Decode(&node)
+		if errors.Is(err, io.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		}
+		if yamlEmptyDocument(&node) {
+			continue
+		}
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		if err := enc.Encode(&node); err != nil {
+			return nil, fmt.Errorf("document %d: %w", len(docs), err)
+		}
+		if err := enc.Close(); err != nil {
+			return nil, fmt.Errorf("document %d: %w", len(docs), err)
+		}
+		docs = append(docs, buf.Bytes())
+	}
+}
+
+// yamlEmptyDocument returns true if the given document node has no content,
+// which the decoder represents as a null scalar with no text.
+func yamlEmptyDocument(node *yaml.Node) bool {
+	if len(node.Content) == 0 {
+		return true
+	}
+	content := node.Content[0]
+	return content.Kind == yaml.ScalarNode && content.Tag == "!!null" && content.Value == ""
+}
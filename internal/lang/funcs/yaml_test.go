@@ -0,0 +1,180 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package funcs
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+)
+
+func TestYAMLDecodeAll(t *testing.T) {
+	tests := []struct {
+		Src  cty.Value
+		Want cty.Value
+		Err  string
+	}{
+		{
+			cty.StringVal(""),
+			cty.EmptyTupleVal,
+			``,
+		},
+		{
+			cty.StringVal("a: 1\n"),
+			cty.TupleVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"a": cty.NumberIntVal(1)}),
+			}),
+			``,
+		},
+		{
+			// Empty documents, like those from leading and trailing
+			// separators, are skipped but explicit nulls are not.
+			cty.StringVal("---\nkind: Namespace\nmetadata:\n  name: web\n---\n---\n~\n---\n"),
+			cty.TupleVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"kind": cty.StringVal("Namespace"),
+					"metadata": cty.ObjectVal(map[string]cty.Value{
+						"name": cty.StringVal("web"),
+					}),
+				}),
+				cty.NullVal(cty.DynamicPseudoType),
+			}),
+			``,
+		},
+		{
+			// Anchors belong to the document that defines them.
+			cty.StringVal("a: &x [1, 2]\nb: *x\n---\n- one\n- true\n"),
+			cty.TupleVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{
+					"a": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(2)}),
+					"b": cty.TupleVal([]cty.Value{cty.NumberIntVal(1), cty.NumberIntVal(2)}),
+				}),
+				cty.TupleVal([]cty.Value{cty.StringVal("one"), cty.True}),
+			}),
+			``,
+		},
+		{
+			cty.StringVal("a: 1\n").Mark(marks.Sensitive),
+			cty.TupleVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"a": cty.NumberIntVal(1)}),
+			}).Mark(marks.Sensitive),
+			``,
+		},
+		{
+			cty.UnknownVal(cty.String),
+			cty.DynamicVal,
+			``,
+		},
+		{
+			cty.StringVal("a: 1\n---\nb: *missing\n"),
+			cty.NilVal,
+			`unknown anchor 'missing'`,
+		},
+		{
+			cty.StringVal("a: [1\n"),
+			cty.NilVal,
+			`invalid YAML`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("yamldecodeall(%#v)", test.Src), func(t *testing.T) {
+			got, err := YAMLDecodeAll(test.Src)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if !strings.Contains(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+}
+
+func TestYAMLEncodeAll(t *testing.T) {
+	tests := []struct {
+		Values cty.Value
+		Want   cty.Value
+		Err    string
+	}{
+		{
+			cty.EmptyTupleVal,
+			cty.StringVal(""),
+			``,
+		},
+		{
+			cty.TupleVal([]cty.Value{
+				cty.ObjectVal(map[string]cty.Value{"kind": cty.StringVal("Namespace")}),
+				cty.ListVal([]cty.Value{cty.StringVal("a")}),
+				cty.True,
+			}),
+			cty.StringVal("\"kind\": \"Namespace\"\n---\n- \"a\"\n---\ntrue\n...\n"),
+			``,
+		},
+		{
+			cty.ListVal([]cty.Value{cty.UnknownVal(cty.String)}),
+			cty.UnknownVal(cty.String).RefineNotNull(),
+			``,
+		},
+		{
+			cty.ObjectVal(map[string]cty.Value{"kind": cty.StringVal("Namespace")}),
+			cty.NilVal,
+			`must be a list or tuple`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("yamlencodeall(%#v)", test.Values), func(t *testing.T) {
+			got, err := YAMLEncodeAll(test.Values)
+
+			if test.Err != "" {
+				if err == nil {
+					t.Fatal("succeeded; want error")
+				}
+				if !strings.Contains(err.Error(), test.Err) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: message containing %q", err, test.Err)
+				}
+				return
+			} else if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			if !got.RawEquals(test.Want) {
+				t.Errorf("wrong result\ngot:  %#v\nwant: %#v", got, test.Want)
+			}
+		})
+	}
+
+	// The documents decode back into the values they were encoded from.
+	want := cty.TupleVal([]cty.Value{
+		cty.ObjectVal(map[string]cty.Value{"a": cty.StringVal("b")}),
+		cty.NumberIntVal(2),
+	})
+	encoded, err := YAMLEncodeAll(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := YAMLDecodeAll(encoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.RawEquals(want) {
+		t.Errorf("wrong round trip result\ngot:  %#v\nwant: %#v", got, want)
+	}
+}
@@ -25,6 +25,139 @@ var impureFunctions = []string{
 	"uuid",
 }
 
+// cacheableFunctions are the functions whose results depend only on their
+// arguments, so that expressions calling them can be cached by ExprCache.
+// Expressions calling any other function are always evaluated again. New
+// functions must be added here explicitly if they're pure; functions that
+// read files, which can change during an operation, or that return a
+// different result each time they're called, must not be.
+var cacheableFunctions = map[string]bool{
+	"abs":              true,
+	"abspath":          true,
+	"alltrue":          true,
+	"anytrue":          true,
+	"base64decode":     true,
+	"base64encode":     true,
+	"base64gunzip":     true,
+	"base64gzip":       true,
+	"base64sha256":     true,
+	"base64sha512":     true,
+	"basename":         true,
+	"can":              true,
+	"ceil":             true,
+	"chomp":            true,
+	"chunklist":        true,
+	"cidrcontains":     true,
+	"cidrhost":         true,
+	"cidrnetmask":      true,
+	"cidroverlaps":     true,
+	"cidrsubnet":       true,
+	"cidrsubnets":      true,
+	"coalesce":         true,
+	"coalescelist":     true,
+	"compact":          true,
+	"concat":           true,
+	"contains":         true,
+	"csvdecode":        true,
+	"dirname":          true,
+	"distinct":         true,
+	"element":          true,
+	"endswith":         true,
+	"flatten":          true,
+	"floor":            true,
+	"format":           true,
+	"formatdate":       true,
+	"formatlist":       true,
+	"indent":           true,
+	"index":            true,
+	"issensitive":      true,
+	"join":             true,
+	"jsondecode":       true,
+	"jsonencode":       true,
+	"jsonpatch":        true,
+	"keys":             true,
+	"length":           true,
+	"list":             true,
+	"log":              true,
+	"lookup":           true,
+	"lower":            true,
+	"map":              true,
+	"matchkeys":        true,
+	"max":              true,
+	"md5":              true,
+	"merge":            true,
+	"min":              true,
+	"nonsensitive":     true,
+	"one":              true,
+	"parseint":         true,
+	"pathexpand":       true,
+	"plantimestamp":    true,
+	"pow":              true,
+	"range":            true,
+	"regex":            true,
+	"regexall":         true,
+	"replace":          true,
+	"reverse":          true,
+	"rsadecrypt":       true,
+	"semverconstraint": true,
+	"sensitive":        true,
+	"setintersection":  true,
+	"setproduct":       true,
+	"setsubtract":      true,
+	"setunion":         true,
+	"sha1":             true,
+	"sha256":           true,
+	"sha512":           true,
+	"signum":           true,
+	"slice":            true,
+	"sort":             true,
+	"split":            true,
+	"startswith":       true,
+	"strcontains":      true,
+	"strrev":           true,
+	"substr":           true,
+	"sum":              true,
+	"textdecodebase64": true,
+	"textencodebase64": true,
+	"timeadd":          true,
+	"timecmp":          true,
+	"title":            true,
+	"tobool":           true,
+	"tolist":           true,
+	"tomap":            true,
+	"tomldecode":       true,
+	"tomlencode":       true,
+	"tonumber":         true,
+	"toset":            true,
+	"tostring":         true,
+	"transpose":        true,
+	"trim":             true,
+	"trimprefix":       true,
+	"trimspace":        true,
+	"trimsuffix":       true,
+	"try":              true,
+	"type":             true,
+	"upper":            true,
+	"urldecode":        true,
+	"urlencode":        true,
+	"urlparse":         true,
+	"uuidv5":           true,
+	"values":           true,
+	"yamldecode":       true,
+	"yamldecodeall":    true,
+	"yamlencode":       true,
+	"yamlencodeall":    true,
+	"zipmap":           true,
+
+	// These functions read whole directories, whose contents are
+	// especially likely to change during an operation, such as when a
+	// provisioner writes build artifacts. They're listed to make it clear
+	// that leaving them out is deliberate.
+	"dirhash":  false,
+	"filesets": false,
+	"filestat": false,
+}
+
 // This should probably be replaced with addrs.Function everywhere
 const CoreNamespace = addrs.FunctionNamespaceCore + "::"
 
@@ -61,12 +194,14 @@ func (s *Scope) Functions() map[string]function.Function {
 			"cidrnetmask":      funcs.CidrNetmaskFunc,
 			"cidrsubnet":       funcs.CidrSubnetFunc,
 			"cidrsubnets":      funcs.CidrSubnetsFunc,
+			"cidroverlaps":     funcs.CidrOverlapsFunc,
 			"coalesce":         funcs.CoalesceFunc,
 			"coalescelist":     stdlib.CoalesceListFunc,
 			"compact":          stdlib.CompactFunc,
 			"concat":           stdlib.ConcatFunc,
 			"contains":         stdlib.ContainsFunc,
 			"csvdecode":        stdlib.CSVDecodeFunc,
+			"dirhash":          funcs.MakeDirHashFunc(s.BaseDir),
 			"dirname":          funcs.DirnameFunc,
 			"distinct":         stdlib.DistinctFunc,
 			"element":          stdlib.ElementFunc,
@@ -75,6 +210,8 @@ func (s *Scope) Functions() map[string]function.Function {
 			"file":             funcs.MakeFileFunc(s.BaseDir, false),
 			"fileexists":       funcs.MakeFileExistsFunc(s.BaseDir),
 			"fileset":          funcs.MakeFileSetFunc(s.BaseDir),
+			"filesets":         funcs.MakeFileSetsFunc(s.BaseDir),
+			"filestat":         funcs.MakeFileStatFunc(s.BaseDir),
 			"filebase64":       funcs.MakeFileFunc(s.BaseDir, true),
 			"filebase64sha256": funcs.MakeFileBase64Sha256Func(s.BaseDir),
 			"filebase64sha512": funcs.MakeFileBase64Sha512Func(s.BaseDir),
@@ -92,6 +229,7 @@ func (s *Scope) Functions() map[string]function.Function {
 			"join":             stdlib.JoinFunc,
 			"jsondecode":       stdlib.JSONDecodeFunc,
 			"jsonencode":       stdlib.JSONEncodeFunc,
+			"jsonpatch":        funcs.JSONPatchFunc,
 			"keys":             stdlib.KeysFunc,
 			"length":           funcs.LengthFunc,
 			"list":             funcs.ListFunc,
@@ -117,6 +255,7 @@ func (s *Scope) Functions() map[string]function.Function {
 			"sensitive":        funcs.SensitiveFunc,
 			"nonsensitive":     funcs.NonsensitiveFunc,
 			"issensitive":      funcs.IsSensitiveFunc,
+			"semverconstraint": funcs.SemverConstraintFunc,
 			"setintersection":  stdlib.SetIntersectionFunc,
 			"setproduct":       stdlib.SetProductFunc,
 			"setsubtract":      stdlib.SetSubtractFunc,
@@ -145,6 +284,8 @@ func (s *Scope) Functions() map[string]function.Function {
 			"toset":            funcs.MakeToFunc(cty.Set(cty.DynamicPseudoType)),
 			"tolist":           funcs.MakeToFunc(cty.List(cty.DynamicPseudoType)),
 			"tomap":            funcs.MakeToFunc(cty.Map(cty.DynamicPseudoType)),
+			"tomldecode":       funcs.TOMLDecodeFunc,
+			"tomlencode":       funcs.TOMLEncodeFunc,
 			"transpose":        funcs.TransposeFunc,
 			"trim":             stdlib.TrimFunc,
 			"trimprefix":       stdlib.TrimPrefixFunc,
@@ -154,12 +295,21 @@ func (s *Scope) Functions() map[string]function.Function {
 			"upper":            stdlib.UpperFunc,
 			"urlencode":        funcs.URLEncodeFunc,
 			"urldecode":        funcs.URLDecodeFunc,
+			"urlparse":         funcs.URLParseFunc,
 			"uuid":             funcs.UUIDFunc,
 			"uuidv5":           funcs.UUIDV5Func,
 			"values":           stdlib.ValuesFunc,
 			"yamldecode":       ctyyaml.YAMLDecodeFunc,
 			"yamlencode":       ctyyaml.YAMLEncodeFunc,
+			"yamldecodeall":    funcs.YAMLDecodeAllFunc,
+			"yamlencodeall":    funcs.YAMLEncodeAllFunc,
 			"zipmap":           stdlib.ZipmapFunc,
+
+			// The key generation functions return ephemeral values, which
+			// can't be saved in the plan or state.
+			"ed25519_keypair":      funcs.Ed25519KeypairFunc,
+			"rsa_keypair":          funcs.RsaKeypairFunc,
+			"tls_self_signed_cert": funcs.TLSSelfSignedCertFunc,
 		}
 
 		s.funcs["templatefile"] = funcs.MakeTemplateFileFunc(s.BaseDir, func() map[string]function.Function {
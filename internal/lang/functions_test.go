@@ -232,6 +232,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"cidroverlaps": {
+			{
+				`cidroverlaps("10.0.0.0/16", "10.0.4.0/24")`,
+				cty.True,
+			},
+		},
+
 		"coalesce": {
 			{
 				`coalesce("first", "second", "third")`,
@@ -313,6 +320,17 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"dirhash": {
+			{
+				`dirhash(".")`,
+				cty.StringVal("h1:rVhLgLuVTD7CrW1YmrxLn2saX/rx0x3vdZUyF+uMwrk="),
+			},
+			{
+				`dirhash(".", "**/*.tmpl")`,
+				cty.StringVal("h1:1DdIVkyk4LwThfkibD9KU3umbaATUB0LqdvbrclqYlQ="),
+			},
+		},
+
 		"dirname": {
 			{
 				`dirname("testdata/hello.txt")`,
@@ -329,6 +347,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"ed25519_keypair": {
+			{
+				`startswith(nonsensitive(ed25519_keypair().public_key_openssh), "ssh-ed25519 ")`,
+				cty.True.Mark(marks.Ephemeral),
+			},
+		},
+
 		"element": {
 			{
 				`element(["hello"], 0)`,
@@ -450,6 +475,34 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"filesets": {
+			{
+				`filesets(".", { include = ["**/*.txt"] })`,
+				cty.SetVal([]cty.Value{
+					cty.StringVal("hello.txt"),
+					cty.StringVal("subdirectory/hello.txt"),
+				}),
+			},
+			{
+				`filesets(".", { exclude = ["subdirectory"], include_dirs = true })`,
+				cty.SetVal([]cty.Value{
+					cty.StringVal("hello.tmpl"),
+					cty.StringVal("hello.txt"),
+				}),
+			},
+		},
+
+		"filestat": {
+			{
+				`filestat("hello.txt").size`,
+				cty.NumberIntVal(6),
+			},
+			{
+				`filestat("subdirectory").type`,
+				cty.StringVal("dir"),
+			},
+		},
+
 		"filesha1": {
 			{
 				`filesha1("hello.txt")`,
@@ -570,6 +623,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"jsonpatch": {
+			{
+				`jsonpatch("{\"a\":1}", "[{\"op\":\"add\",\"path\":\"/b\",\"value\":2}]")`,
+				cty.StringVal(`{"a":1,"b":2}`),
+			},
+		},
+
 		"keys": {
 			{
 				`keys({"hello"=1, "goodbye"=42})`,
@@ -775,6 +835,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"rsa_keypair": {
+			{
+				`startswith(nonsensitive(rsa_keypair(2048).public_key_openssh), "ssh-rsa ")`,
+				cty.True.Mark(marks.Ephemeral),
+			},
+		},
+
 		"rsadecrypt": {
 			{
 				fmt.Sprintf("rsadecrypt(%#v, %#v)", CipherBase64, PrivateKey),
@@ -782,6 +849,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"semverconstraint": {
+			{
+				`semverconstraint("1.2.3", "~> 1.2.0")`,
+				cty.True,
+			},
+		},
+
 		"sensitive": {
 			{
 				`sensitive(1)`,
@@ -1012,6 +1086,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"tls_self_signed_cert": {
+			{
+				`startswith(nonsensitive(tls_self_signed_cert(ed25519_keypair().private_key_pem, "example.com", 1)), "-----BEGIN CERTIFICATE-----")`,
+				cty.True.Mark(marks.Ephemeral),
+			},
+		},
+
 		"title": {
 			{
 				`title("hello")`,
@@ -1045,6 +1126,25 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"tomldecode": {
+			{
+				`tomldecode("name = \"web\"\n[tags]\nenv = \"dev\"\n")`,
+				cty.ObjectVal(map[string]cty.Value{
+					"name": cty.StringVal("web"),
+					"tags": cty.ObjectVal(map[string]cty.Value{
+						"env": cty.StringVal("dev"),
+					}),
+				}),
+			},
+		},
+
+		"tomlencode": {
+			{
+				`tomlencode({name = "web", tags = {env = "dev"}})`,
+				cty.StringVal("name = \"web\"\n\n[tags]\nenv = \"dev\"\n"),
+			},
+		},
+
 		"tonumber": {
 			{
 				`tonumber("42")`,
@@ -1151,6 +1251,13 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"urlparse": {
+			{
+				`urlparse("https://example.com:8443/a?b=c").port`,
+				cty.StringVal("8443"),
+			},
+		},
+
 		"uuidv5": {
 			{
 				`uuidv5("dns", "tada")`,
@@ -1217,6 +1324,27 @@ func TestFunctions(t *testing.T) {
 			},
 		},
 
+		"yamldecodeall": {
+			{
+				`yamldecodeall("a: 1\n---\nb: 2\n")`,
+				cty.TupleVal([]cty.Value{
+					cty.ObjectVal(map[string]cty.Value{
+						"a": cty.NumberIntVal(1),
+					}),
+					cty.ObjectVal(map[string]cty.Value{
+						"b": cty.NumberIntVal(2),
+					}),
+				}),
+			},
+		},
+
+		"yamlencodeall": {
+			{
+				`yamlencodeall([{a = 1}, {b = 2}])`,
+				cty.StringVal("\"a\": 1\n---\n\"b\": 2\n"),
+			},
+		},
+
 		"zipmap": {
 			{
 				`zipmap(["hello", "bar"], ["world", "baz"])`,
@@ -45,3 +45,9 @@ const Sensitive = valueMark("Sensitive")
 // another value's type. This is part of the implementation of the console-only
 // `type` function.
 const TypeType = valueMark("TypeType")
+
+// Ephemeral indicates that this value must not be saved in a plan or state,
+// such as a private key generated by one of the key-generation functions.
+// Ephemeral values can only be used where OpenTofu doesn't persist the
+// result, such as in provider and provisioner configuration.
+const Ephemeral = valueMark("Ephemeral")
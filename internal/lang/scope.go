@@ -73,6 +73,10 @@ type Scope struct {
 	PlanTimestamp time.Time
 
 	ProviderFunctions ProviderFunction
+
+	// ExprCache, if set, is used by EvalExpr to reuse the results of
+	// earlier evaluations of the same expression with the same inputs.
+	ExprCache *ExprCache
 }
 
 type ProviderFunction func(addrs.ProviderFunction, tfdiags.SourceRange) (*function.Function, tfdiags.Diagnostics)
@@ -19,8 +19,9 @@ import (
 // These are the environmental variables that determine if we log, and if
 // we log whether or not the log should go to a file.
 const (
-	envLog     = "TF_LOG"
-	envLogFile = "TF_LOG_PATH"
+	envLog       = "TF_LOG"
+	envLogFile   = "TF_LOG_PATH"
+	envLogFormat = "TF_LOG_FORMAT"
 
 	// Allow logging of specific subsystems.
 	// We only separate core and providers for now, but this could be extended
@@ -34,6 +35,9 @@ var (
 	// ValidLevels are the log level names that OpenTofu recognizes.
 	ValidLevels = []string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "OFF"}
 
+	// ValidFormats are the log format names that OpenTofu recognizes.
+	ValidFormats = []string{"TEXT", "JSON"}
+
 	// logger is the global hclog logger
 	logger hclog.Logger
 
@@ -49,7 +53,11 @@ var (
 
 func init() {
 	logger = newHCLogger("")
-	logWriter = logger.StandardWriter(&hclog.StandardLoggerOptions{InferLevels: true})
+	if _, json := globalLogLevel(); json {
+		logWriter = &structuredLogWriter{log: logger}
+	} else {
+		logWriter = logger.StandardWriter(&hclog.StandardLoggerOptions{InferLevels: true})
+	}
 
 	// set up the default std library logger to use our output
 	log.SetFlags(0)
@@ -179,9 +187,28 @@ func globalLogLevel() (hclog.Level, bool) {
 	if envLevel == "JSON" {
 		json = true
 	}
+	if logFormat() == "JSON" {
+		json = true
+	}
 	return parseLogLevel(envLevel), json
 }
 
+// logFormat returns the log format set by the environment, which is "TEXT"
+// unless it is set to "JSON".
+func logFormat() string {
+	format := strings.ToUpper(os.Getenv(envLogFormat))
+	switch format {
+	case "":
+		return "TEXT"
+	case "TEXT", "JSON":
+		return format
+	default:
+		fmt.Fprintf(os.Stderr, "[WARN] Invalid log format: %q. Defaulting to format: TEXT. Valid formats are: %+v",
+			format, ValidFormats)
+		return "TEXT"
+	}
+}
+
 func parseLogLevel(envLevel string) hclog.Level {
 	if envLevel == "" {
 		return hclog.Off
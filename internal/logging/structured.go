@@ -0,0 +1,63 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-hclog"
+)
+
+// structuredLogWriter is an io.Writer for the standard library logger that
+// logs each write as a single record of the given logger, which should use
+// the JSON format.
+//
+// Most of OpenTofu logs with log.Printf and a message like
+// "[TRACE] backend/local: starting Plan operation", so in addition to the
+// level the writer extracts the subsystem from the prefix of the message,
+// and the address of the resource or module that the message is about, if
+// the message contains one.
+type structuredLogWriter struct {
+	log hclog.Logger
+}
+
+var (
+	// logLevelPattern matches the level prefix of a log message.
+	logLevelPattern = regexp.MustCompile(`^\[(TRACE|DEBUG|INFO|WARN|ERROR)\] ?`)
+
+	// logSubsystemPattern matches the subsystem prefix of a log message,
+	// such as "backend/local: " or "TestFileRunner: ".
+	logSubsystemPattern = regexp.MustCompile(`^([A-Za-z][\w./-]*): `)
+
+	// logAddressPattern matches a resource or module address at the start of
+	// a quoted string, which is how most log messages refer to them, such as
+	// in "vertex \"module.a.test_instance.b (expand)\"".
+	logAddressPattern = regexp.MustCompile(`"((?:module\.[\w-]+(?:\[[^\]]*\])?\.)*(?:(?:data\.)?[A-Za-z][\w-]*_[\w-]*\.[\w-]+|module\.[\w-]+)(?:\[[^\]]*\])?)[" ]`)
+)
+
+func (w *structuredLogWriter) Write(p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+
+	level := hclog.Info
+	if match := logLevelPattern.FindStringSubmatch(msg); match != nil {
+		level = hclog.LevelFromString(match[1])
+		msg = msg[len(match[0]):]
+	}
+
+	var args []interface{}
+	if match := logSubsystemPattern.FindStringSubmatch(msg); match != nil {
+		args = append(args, "subsystem", match[1])
+		msg = msg[len(match[0]):]
+	}
+	if match := logAddressPattern.FindStringSubmatch(msg); match != nil {
+		// Instance keys are quoted within the already quoted address.
+		args = append(args, "address", strings.ReplaceAll(match[1], `\"`, `"`))
+	}
+
+	w.log.Log(level, msg, args...)
+	return len(p), nil
+}
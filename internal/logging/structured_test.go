@@ -0,0 +1,134 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-hclog"
+)
+
+func TestStructuredLogWriter(t *testing.T) {
+	tcs := map[string]struct {
+		line string
+		want map[string]interface{}
+	}{
+		"no level": {
+			line: "hello world\n",
+			want: map[string]interface{}{
+				"@level":   "info",
+				"@message": "hello world",
+			},
+		},
+		"subsystem": {
+			line: "[DEBUG] backend/local: starting Plan operation\n",
+			want: map[string]interface{}{
+				"@level":    "debug",
+				"@message":  "starting Plan operation",
+				"subsystem": "backend/local",
+			},
+		},
+		"resource address": {
+			line: "[TRACE] vertex \"test_instance.foo\": starting visit (*tofu.NodeValidatableResource)\n",
+			want: map[string]interface{}{
+				"@level":   "trace",
+				"@message": "vertex \"test_instance.foo\": starting visit (*tofu.NodeValidatableResource)",
+				"address":  "test_instance.foo",
+			},
+		},
+		"nested resource instance address": {
+			line: "[TRACE] NodeApplyableResourceInstance: applying \"module.child[\\\"a\\\"].data.test_data.bar[0]\"\n",
+			want: map[string]interface{}{
+				"@level":    "trace",
+				"@message":  "applying \"module.child[\\\"a\\\"].data.test_data.bar[0]\"",
+				"subsystem": "NodeApplyableResourceInstance",
+				"address":   "module.child[\"a\"].data.test_data.bar[0]",
+			},
+		},
+		"module address": {
+			line: "[TRACE] vertex \"module.child (expand)\": visit complete\n",
+			want: map[string]interface{}{
+				"@level":   "trace",
+				"@message": "vertex \"module.child (expand)\": visit complete",
+				"address":  "module.child",
+			},
+		},
+		"not an address": {
+			line: "[WARN] Failed to read \"terraform.tfstate\": not found\n",
+			want: map[string]interface{}{
+				"@level":   "warn",
+				"@message": "Failed to read \"terraform.tfstate\": not found",
+			},
+		},
+		"multiple lines": {
+			line: "[ERROR] plan failed:\n  first\n  second\n",
+			want: map[string]interface{}{
+				"@level":   "error",
+				"@message": "plan failed:\n  first\n  second",
+			},
+		},
+	}
+	for name, tc := range tcs {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := &structuredLogWriter{
+				log: hclog.New(&hclog.LoggerOptions{
+					Level:      hclog.Trace,
+					Output:     &buf,
+					JSONFormat: true,
+				}),
+			}
+
+			if _, err := w.Write([]byte(tc.line)); err != nil {
+				t.Fatal(err)
+			}
+
+			var got map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("wrote more than one JSON record: %s\n\n%s", err, buf.String())
+			}
+			if _, ok := got["@timestamp"]; !ok {
+				t.Errorf("record has no timestamp: %s", buf.String())
+			}
+			delete(got, "@timestamp")
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("wrong record\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGlobalLogLevel_format(t *testing.T) {
+	tcs := map[string]struct {
+		level, format string
+		wantLevel     hclog.Level
+		wantJSON      bool
+	}{
+		"text":           {level: "DEBUG", format: "", wantLevel: hclog.Debug},
+		"json format":    {level: "debug", format: "json", wantLevel: hclog.Debug, wantJSON: true},
+		"json level":     {level: "JSON", format: "", wantLevel: hclog.Trace, wantJSON: true},
+		"explicit text":  {level: "WARN", format: "text", wantLevel: hclog.Warn},
+		"invalid format": {level: "INFO", format: "yaml", wantLevel: hclog.Info},
+		"logging is off": {level: "", format: "json", wantLevel: hclog.Off, wantJSON: true},
+	}
+	for name, tc := range tcs {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(envLog, tc.level)
+			t.Setenv(envLogCore, "")
+			t.Setenv(envLogFormat, tc.format)
+
+			level, json := globalLogLevel()
+			if level != tc.wantLevel || json != tc.wantJSON {
+				t.Errorf("got level %s and json %t, want level %s and json %t", level, json, tc.wantLevel, tc.wantJSON)
+			}
+		})
+	}
+}
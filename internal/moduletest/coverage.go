@@ -0,0 +1,354 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package moduletest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// CoverageKind is the kind of object that a CoverageItem describes.
+type CoverageKind string
+
+const (
+	CoverageResource CoverageKind = "resource"
+	CoverageOutput   CoverageKind = "output"
+	CoverageVariable CoverageKind = "variable"
+
+	// CoverageBranch items describe one of the arms of a conditional part of
+	// a resource: having no instances or some instances for count and
+	// for_each, and producing no blocks or some blocks for dynamic blocks.
+	CoverageBranch CoverageKind = "branch"
+)
+
+// CoverageItem is a single part of the module under test, and whether any run
+// block exercised it.
+type CoverageItem struct {
+	Kind CoverageKind
+
+	// Address is the address of the resource, output value, or variable, or
+	// of the resource that contains the branch.
+	Address string
+
+	// Branch describes the arm of a CoverageBranch item, such as
+	// "count = 0", and is empty for the other kinds.
+	Branch string
+
+	DeclRange hcl.Range
+	Covered   bool
+}
+
+// String returns the address of the item, followed by the branch if it has
+// one.
+func (item *CoverageItem) String() string {
+	if item.Branch == "" {
+		return item.Address
+	}
+	return fmt.Sprintf("%s (%s)", item.Address, item.Branch)
+}
+
+// Coverage tracks which parts of the module under test have been exercised
+// by the run blocks of a test suite.
+//
+// Resources and their branches are tracked throughout the module tree, while
+// output values and variables are only tracked for the root module, since
+// those are the ones that run blocks can set and assert against.
+//
+// Coverage is safe to use concurrently.
+type Coverage struct {
+	mu    sync.Mutex
+	items []*CoverageItem
+
+	// modules holds the resources to check in each module, by module path.
+	modules map[string][]*coverageResource
+
+	outputs   map[string]*CoverageItem
+	variables map[string]*CoverageItem
+}
+
+type coverageResource struct {
+	addr     addrs.Resource
+	resource *CoverageItem
+
+	// empty and nonEmpty are the arms for count or for_each, if the resource
+	// has either of them.
+	empty, nonEmpty *CoverageItem
+
+	dynamic []*coverageDynamicBlock
+}
+
+type coverageDynamicBlock struct {
+	// path is the sequence of nested block types leading to the dynamic
+	// block, ending with the type of block it generates.
+	path []string
+
+	empty, nonEmpty *CoverageItem
+}
+
+// NewCoverage returns a Coverage for the given configuration, with nothing
+// covered yet.
+func NewCoverage(config *configs.Config) *Coverage {
+	c := &Coverage{
+		modules:   make(map[string][]*coverageResource),
+		outputs:   make(map[string]*CoverageItem),
+		variables: make(map[string]*CoverageItem),
+	}
+
+	config.DeepEach(func(cfg *configs.Config) {
+		var resources []*configs.Resource
+		for _, r := range cfg.Module.ManagedResources {
+			resources = append(resources, r)
+		}
+		for _, r := range cfg.Module.DataResources {
+			resources = append(resources, r)
+		}
+
+		for _, r := range resources {
+			address := r.Addr().InModule(cfg.Path).String()
+			cr := &coverageResource{
+				addr:     r.Addr(),
+				resource: c.add(CoverageResource, address, "", r.DeclRange),
+			}
+
+			switch {
+			case r.Count != nil:
+				cr.empty = c.add(CoverageBranch, address, "count = 0", r.Count.Range())
+				cr.nonEmpty = c.add(CoverageBranch, address, "count > 0", r.Count.Range())
+			case r.ForEach != nil:
+				cr.empty = c.add(CoverageBranch, address, "for_each is empty", r.ForEach.Range())
+				cr.nonEmpty = c.add(CoverageBranch, address, "for_each is not empty", r.ForEach.Range())
+			}
+
+			if body, ok := r.Config.(*hclsyntax.Body); ok {
+				c.addDynamicBlocks(cr, address, body, nil)
+			}
+
+			key := cfg.Path.String()
+			c.modules[key] = append(c.modules[key], cr)
+		}
+	})
+
+	for name, output := range config.Module.Outputs {
+		c.outputs[name] = c.add(CoverageOutput, "output."+name, "", output.DeclRange)
+	}
+	for name, variable := range config.Module.Variables {
+		c.variables[name] = c.add(CoverageVariable, "var."+name, "", variable.DeclRange)
+	}
+
+	sort.SliceStable(c.items, func(i, j int) bool {
+		a, b := c.items[i], c.items[j]
+		if a.DeclRange.Filename != b.DeclRange.Filename {
+			return a.DeclRange.Filename < b.DeclRange.Filename
+		}
+		return a.DeclRange.Start.Byte < b.DeclRange.Start.Byte
+	})
+	return c
+}
+
+func (c *Coverage) add(kind CoverageKind, address, branch string, rng hcl.Range) *CoverageItem {
+	item := &CoverageItem{
+		Kind:      kind,
+		Address:   address,
+		Branch:    branch,
+		DeclRange: rng,
+	}
+	c.items = append(c.items, item)
+	return item
+}
+
+// addDynamicBlocks adds the branches for the dynamic blocks in the given
+// body, including those nested within other blocks.
+func (c *Coverage) addDynamicBlocks(cr *coverageResource, address string, body *hclsyntax.Body, path []string) {
+	for _, block := range body.Blocks {
+		if block.Type != "dynamic" || len(block.Labels) != 1 {
+			// Dynamic blocks can also be nested within static blocks.
+			c.addDynamicBlocks(cr, address, block.Body, append(path[:len(path):len(path)], block.Type))
+			continue
+		}
+
+		name := block.Labels[0]
+		blockPath := append(path[:len(path):len(path)], name)
+		cr.dynamic = append(cr.dynamic, &coverageDynamicBlock{
+			path:     blockPath,
+			empty:    c.add(CoverageBranch, address, fmt.Sprintf("dynamic %q is empty", name), block.DefRange()),
+			nonEmpty: c.add(CoverageBranch, address, fmt.Sprintf("dynamic %q is not empty", name), block.DefRange()),
+		})
+
+		// The content of a dynamic block can contain more dynamic blocks.
+		for _, content := range block.Body.Blocks {
+			if content.Type == "content" {
+				c.addDynamicBlocks(cr, address, content.Body, blockPath)
+			}
+		}
+	}
+}
+
+// RecordState marks everything in the given state, which is either the
+// planned state of a run block that executed a plan or the state after a run
+// block that executed an apply, as covered.
+func (c *Coverage) RecordState(state *states.State) {
+	if state == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, ms := range state.Modules {
+		for _, cr := range c.modules[ms.Addr.Module().String()] {
+			rs := ms.Resource(cr.addr)
+
+			var instances int
+			if rs != nil {
+				instances = len(rs.Instances)
+			}
+			if instances > 0 {
+				cr.resource.Covered = true
+			}
+			if cr.empty != nil {
+				// A resource with no instances isn't in the state at all,
+				// so its absence from the module instance means that the
+				// count or for_each was empty.
+				if instances == 0 {
+					cr.empty.Covered = true
+				} else {
+					cr.nonEmpty.Covered = true
+				}
+			}
+
+			if rs == nil {
+				continue
+			}
+			for _, is := range rs.Instances {
+				if is.Current == nil || is.Current.AttrsJSON == nil {
+					continue
+				}
+				var attrs interface{}
+				if err := json.Unmarshal(is.Current.AttrsJSON, &attrs); err != nil {
+					continue
+				}
+				for _, block := range cr.dynamic {
+					recordDynamicBlock(block, attrs, block.path)
+				}
+			}
+		}
+	}
+
+	if root := state.RootModule(); root != nil {
+		for name := range root.OutputValues {
+			if item, ok := c.outputs[name]; ok {
+				item.Covered = true
+			}
+		}
+	}
+}
+
+// recordDynamicBlock marks the arms of the given dynamic block as covered,
+// based on the blocks found at the remaining path within the given value,
+// which was decoded from the JSON representation of a resource instance.
+func recordDynamicBlock(block *coverageDynamicBlock, val interface{}, path []string) {
+	obj, ok := val.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	var children []interface{}
+	switch v := obj[path[0]].(type) {
+	case nil:
+		// A single nested block that isn't present.
+	case []interface{}:
+		children = v
+	case map[string]interface{}:
+		if len(path) == 1 {
+			// A block with single nesting, or a map of blocks. We can't
+			// tell which, but either way it isn't empty.
+			children = append(children, v)
+		} else {
+			for _, child := range v {
+				children = append(children, child)
+			}
+		}
+	default:
+		return
+	}
+
+	if len(path) > 1 {
+		for _, child := range children {
+			recordDynamicBlock(block, child, path[1:])
+		}
+		return
+	}
+	if len(children) == 0 {
+		block.empty.Covered = true
+	} else {
+		block.nonEmpty.Covered = true
+	}
+}
+
+// RecordVariables marks the given root module variables as covered.
+func (c *Coverage) RecordVariables(names []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, name := range names {
+		if item, ok := c.variables[name]; ok {
+			item.Covered = true
+		}
+	}
+}
+
+// Report returns a snapshot of the coverage.
+func (c *Coverage) Report() *CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	report := &CoverageReport{}
+	for _, item := range c.items {
+		copied := *item
+		report.Items = append(report.Items, &copied)
+	}
+	return report
+}
+
+// CoverageReport lists the parts of the module under test and whether they
+// were covered, in the order they are declared.
+type CoverageReport struct {
+	Items []*CoverageItem
+}
+
+// Count returns the number of items of the given kind, and how many of them
+// are covered. If kind is empty, all the items are counted.
+func (r *CoverageReport) Count(kind CoverageKind) (covered, total int) {
+	for _, item := range r.Items {
+		if kind != "" && item.Kind != kind {
+			continue
+		}
+		total++
+		if item.Covered {
+			covered++
+		}
+	}
+	return covered, total
+}
+
+// Percent returns the percentage of the items that are covered, which is 100
+// if there are no items at all.
+func (r *CoverageReport) Percent() float64 {
+	covered, total := r.Count("")
+	if total == 0 {
+		return 100
+	}
+	return float64(covered) * 100 / float64(total)
+}
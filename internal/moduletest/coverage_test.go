@@ -0,0 +1,157 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package moduletest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/spf13/afero"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestCoverage(t *testing.T) {
+	fs := afero.Afero{Fs: afero.NewMemMapFs()}
+	if err := fs.WriteFile("main.tf", []byte(`
+variable "names" {
+  type    = list(string)
+  default = []
+}
+
+variable "unused" {
+  type    = string
+  default = "unused"
+}
+
+resource "test_resource" "single" {
+  dynamic "rule" {
+    for_each = var.names
+    content {
+      name = rule.value
+    }
+  }
+}
+
+resource "test_resource" "counted" {
+  count = length(var.names)
+}
+
+data "test_data_source" "unused" {
+  id = "unused"
+}
+
+output "single" {
+  value = test_resource.single.id
+}
+
+output "unused" {
+  value = "unused"
+}
+`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mod, diags := configs.NewParser(fs).LoadConfigDir(".", configs.RootModuleCallForTesting())
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	config, diags := configs.BuildConfig(mod, configs.DisabledModuleWalker)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+
+	coverage := NewCoverage(config)
+
+	// The first run has no names, so only the single resource is created and
+	// its dynamic block is empty.
+	coverage.RecordState(states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("test_resource.single"),
+			&states.ResourceInstanceObjectSrc{
+				Status:    states.ObjectReady,
+				AttrsJSON: []byte(`{"id":"single","rule":[]}`),
+			},
+			mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+		)
+		s.SetOutputValue(addrs.OutputValue{Name: "single"}.Absolute(addrs.RootModuleInstance), cty.StringVal("single"), false)
+	}))
+
+	// The second run sets the names, which creates the counted resource and
+	// a rule.
+	coverage.RecordState(states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("test_resource.single"),
+			&states.ResourceInstanceObjectSrc{
+				Status:    states.ObjectReady,
+				AttrsJSON: []byte(`{"id":"single","rule":[{"name":"a"}]}`),
+			},
+			mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+		)
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("test_resource.counted[0]"),
+			&states.ResourceInstanceObjectSrc{
+				Status:    states.ObjectReady,
+				AttrsJSON: []byte(`{"id":"counted"}`),
+			},
+			mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+		)
+	}))
+	coverage.RecordVariables([]string{"names"})
+
+	report := coverage.Report()
+	got := make(map[string]bool)
+	for _, item := range report.Items {
+		got[item.String()] = item.Covered
+	}
+	want := map[string]bool{
+		"var.names":            true,
+		"var.unused":           false,
+		"test_resource.single": true,
+		"test_resource.single (dynamic \"rule\" is empty)":     true,
+		"test_resource.single (dynamic \"rule\" is not empty)": true,
+		"test_resource.counted":                                true,
+		"test_resource.counted (count = 0)":                    true,
+		"test_resource.counted (count > 0)":                    true,
+		"data.test_data_source.unused":                         false,
+		"output.single":                                        true,
+		"output.unused":                                        false,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong coverage\n%s", diff)
+	}
+
+	if covered, total := report.Count(CoverageBranch); covered != 4 || total != 4 {
+		t.Errorf("wrong branch coverage %d of %d, want 4 of 4", covered, total)
+	}
+	if got, want := report.Percent(), float64(8)*100/11; got != want {
+		t.Errorf("wrong percentage %f, want %f", got, want)
+	}
+
+	// The items are in the order they are declared.
+	if got, want := report.Items[0].String(), "var.names"; got != want {
+		t.Errorf("wrong first item %q, want %q", got, want)
+	}
+}
+
+func mustResourceInstanceAddr(s string) addrs.AbsResourceInstance {
+	addr, diags := addrs.ParseAbsResourceInstanceStr(s)
+	if diags.HasErrors() {
+		panic(diags.Err())
+	}
+	return addr
+}
+
+func mustProviderConfig(s string) addrs.AbsProviderConfig {
+	p, diags := addrs.ParseAbsProviderConfigStr(s)
+	if diags.HasErrors() {
+		panic(diags.Err())
+	}
+	return p
+}
@@ -6,6 +6,9 @@
 package moduletest
 
 import (
+	"strings"
+	"time"
+
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -18,5 +21,90 @@ type File struct {
 
 	Runs []*Run
 
+	// Setup and Teardown are the runs for the setup and teardown blocks of
+	// the file, if it has them. They aren't reported with the other runs
+	// unless they fail.
+	Setup    *Run
+	Teardown *Run
+
+	// Duration is how long it took to execute the runs of the file, not
+	// including the cleanup of the states they created.
+	Duration time.Duration
+
 	Diagnostics tfdiags.Diagnostics
 }
+
+// AllRuns returns the setup run, followed by the runs for the run blocks,
+// followed by the teardown run, leaving out any that the file doesn't have.
+func (file *File) AllRuns() []*Run {
+	var ret []*Run
+	if file.Setup != nil {
+		ret = append(ret, file.Setup)
+	}
+	ret = append(ret, file.Runs...)
+	if file.Teardown != nil {
+		ret = append(ret, file.Teardown)
+	}
+	return ret
+}
+
+// NewFile returns a File for the given test file configuration, with a Run for
+// each of its run blocks and for its setup and teardown blocks.
+func NewFile(name string, config *configs.TestFile) *File {
+	file := &File{
+		Config: config,
+		Name:   name,
+	}
+	for ix, run := range config.Runs {
+		file.Runs = append(file.Runs, &Run{
+			Config: run,
+			Index:  ix,
+			Name:   run.Name,
+		})
+	}
+
+	// The setup run comes before all the run blocks and the teardown run
+	// after them, which is also the order in which their states are
+	// destroyed in reverse.
+	if config.Setup != nil {
+		file.Setup = &Run{
+			Config: config.Setup,
+			Index:  -1,
+			Name:   config.Setup.Name,
+		}
+	}
+	if config.Teardown != nil {
+		file.Teardown = &Run{
+			Config: config.Teardown,
+			Index:  len(config.Runs),
+			Name:   config.Teardown.Name,
+		}
+	}
+	return file
+}
+
+// Quarantine marks the run blocks of the file that match any of the given
+// entries as quarantined, and returns the entries that matched.
+//
+// An entry that is the name of the file matches all of its run blocks, while
+// an entry in the form "<file>/<run>" matches a single run block. The run
+// blocks expanded from a variants block also match the name of the original
+// run block.
+func (file *File) Quarantine(entries []string) []string {
+	var matched []string
+	for _, entry := range entries {
+		found := false
+		for _, run := range file.Runs {
+			name, _, _ := strings.Cut(run.Name, "[")
+			switch entry {
+			case file.Name, file.Name + "/" + run.Name, file.Name + "/" + name:
+				run.Quarantined = true
+				found = true
+			}
+		}
+		if found {
+			matched = append(matched, entry)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,69 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package moduletest
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFile_Quarantine(t *testing.T) {
+	tcs := map[string]struct {
+		entries     []string
+		matched     []string
+		quarantined []bool
+	}{
+		"none": {
+			quarantined: []bool{false, false, false},
+		},
+		"file": {
+			entries:     []string{"main.tftest.hcl"},
+			matched:     []string{"main.tftest.hcl"},
+			quarantined: []bool{true, true, true},
+		},
+		"run": {
+			entries:     []string{"main.tftest.hcl/first", "other.tftest.hcl/first"},
+			matched:     []string{"main.tftest.hcl/first"},
+			quarantined: []bool{true, false, false},
+		},
+		"variants": {
+			entries:     []string{"main.tftest.hcl/second"},
+			matched:     []string{"main.tftest.hcl/second"},
+			quarantined: []bool{false, true, true},
+		},
+		"single variant": {
+			entries:     []string{"main.tftest.hcl/second[size=large]"},
+			matched:     []string{"main.tftest.hcl/second[size=large]"},
+			quarantined: []bool{false, false, true},
+		},
+	}
+	for name, tc := range tcs {
+		tc := tc
+		t.Run(name, func(t *testing.T) {
+			file := &File{
+				Name: "main.tftest.hcl",
+				Runs: []*Run{
+					{Name: "first"},
+					{Name: "second[size=small]"},
+					{Name: "second[size=large]"},
+				},
+			}
+
+			if diff := cmp.Diff(tc.matched, file.Quarantine(tc.entries)); diff != "" {
+				t.Errorf("wrong matched entries\n%s", diff)
+			}
+
+			var quarantined []bool
+			for _, run := range file.Runs {
+				quarantined = append(quarantined, run.Quarantined)
+			}
+			if diff := cmp.Diff(tc.quarantined, quarantined); diff != "" {
+				t.Errorf("wrong quarantined run blocks\n%s", diff)
+			}
+		})
+	}
+}
@@ -7,6 +7,9 @@ package moduletest
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 
@@ -28,9 +31,33 @@ type Run struct {
 	Index  int
 	Status Status
 
+	// Duration is how long it took to execute the run, which is zero if it
+	// was skipped.
+	Duration time.Duration
+
+	// Attempts is the number of times the run block was executed, which is
+	// more than one if it failed and was retried.
+	Attempts int
+
+	// Quarantined is true if the run block is known to be flaky, in which
+	// case it failing doesn't fail the test file.
+	Quarantined bool
+
 	Diagnostics tfdiags.Diagnostics
 }
 
+// PassedOnRetry returns true if the run block passed, but only after it was
+// retried.
+func (run *Run) PassedOnRetry() bool {
+	return run.Status == Pass && run.Attempts > 1
+}
+
+// QuarantinedFailure returns true if the run block is quarantined and failed
+// or errored, which is reported but doesn't fail the test file.
+func (run *Run) QuarantinedFailure() bool {
+	return run.Quarantined && (run.Status == Fail || run.Status == Error)
+}
+
 // Verbose is a utility struct that holds all the information required for a run
 // to render the results verbosely.
 //
@@ -146,8 +173,26 @@ func (run *Run) ValidateExpectedFailures(originals tfdiags.Diagnostics) tfdiags.
 		sourceRanges.Put(reference.Subject, reference.SourceRange)
 	}
 
+	// The expect_failure blocks are checked first, since they describe
+	// specific errors while the expect_failures list matches everything from
+	// an object.
+	blocks := make([]*expectedFailureBlock, 0, len(run.Config.ExpectedFailures))
+	for _, config := range run.Config.ExpectedFailures {
+		block := &expectedFailureBlock{config: config}
+		if config.Object != nil {
+			// As above, the reference was checked by the validate stage.
+			reference, _ := addrs.ParseRefFromTestingScope(config.Object)
+			block.object = reference.Subject
+		}
+		blocks = append(blocks, block)
+	}
+
 	var diags tfdiags.Diagnostics
 	for _, diag := range originals {
+		if matchExpectedFailureBlocks(blocks, diag) {
+			// Then this failure is expected, so we swallow it.
+			continue
+		}
 
 		if rule, ok := addrs.DiagnosticOriginatesFromCheckRule(diag); ok {
 			switch rule.Container.CheckableKind() {
@@ -338,5 +383,139 @@ func (run *Run) ValidateExpectedFailures(originals tfdiags.Diagnostics) tfdiags.
 		}
 	}
 
+	for _, block := range blocks {
+		if !block.matched {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Missing expected failure",
+				Detail:   fmt.Sprintf("No error was reported %s.", block.describe()),
+				Subject:  block.config.DeclRange.Ptr(),
+			})
+		}
+	}
+
 	return diags
 }
+
+// expectedFailureBlock tracks whether an expect_failure block has matched any
+// of the diagnostics from a plan or apply operation.
+type expectedFailureBlock struct {
+	config  *configs.TestExpectedFailure
+	object  addrs.Referenceable
+	matched bool
+}
+
+// matchExpectedFailureBlocks returns true if the given diagnostic is an error
+// that matches any of the given blocks, marking all the blocks it matches.
+func matchExpectedFailureBlocks(blocks []*expectedFailureBlock, diag tfdiags.Diagnostic) bool {
+	if len(blocks) == 0 || !isTestError(diag) {
+		return false
+	}
+
+	var matched bool
+	for _, block := range blocks {
+		if block.matches(diag) {
+			block.matched = true
+			matched = true
+		}
+	}
+	return matched
+}
+
+// isTestError returns true if the given diagnostic fails a test, including
+// the diagnostics from check blocks that are reported as warnings but that
+// ValidateExpectedFailures upgrades to errors.
+func isTestError(diag tfdiags.Diagnostic) bool {
+	if rule, ok := addrs.DiagnosticOriginatesFromCheckRule(diag); ok && rule.Container.CheckableKind() == addrs.CheckableCheck {
+		if rule.Type == addrs.CheckAssertion {
+			return true
+		}
+		return tfdiags.UndoOverride(diag).Severity() == tfdiags.Error
+	}
+	return diag.Severity() == tfdiags.Error
+}
+
+func (block *expectedFailureBlock) matches(diag tfdiags.Diagnostic) bool {
+	config := block.config
+
+	if block.object != nil && !diagnosticFromObject(diag, block.object) {
+		return false
+	}
+
+	desc := diag.Description()
+	if config.Summary != nil && !config.Summary.MatchString(desc.Summary) {
+		return false
+	}
+	if config.Detail != nil && !config.Detail.MatchString(desc.Detail) {
+		return false
+	}
+
+	if config.Filename != "" || config.Line > 0 {
+		subject := diag.Source().Subject
+		if subject == nil {
+			return false
+		}
+		if config.Filename != "" {
+			got, want := filepath.ToSlash(filepath.Clean(subject.Filename)), filepath.ToSlash(filepath.Clean(config.Filename))
+			if got != want && !strings.HasSuffix(got, "/"+want) {
+				return false
+			}
+		}
+		if config.Line > 0 && (config.Line < subject.Start.Line || config.Line > subject.End.Line) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// describe returns a description of the errors that the block matches, to
+// complete a sentence such as "No error was reported ...".
+func (block *expectedFailureBlock) describe() string {
+	config := block.config
+
+	var parts []string
+	if block.object != nil {
+		parts = append(parts, fmt.Sprintf("by %s", block.object.String()))
+	}
+	if config.Summary != nil {
+		parts = append(parts, fmt.Sprintf("with a summary matching %q", config.Summary.String()))
+	}
+	if config.Detail != nil {
+		parts = append(parts, fmt.Sprintf("with a detail matching %q", config.Detail.String()))
+	}
+	switch {
+	case config.Filename != "" && config.Line > 0:
+		parts = append(parts, fmt.Sprintf("at %s line %d", config.Filename, config.Line))
+	case config.Filename != "":
+		parts = append(parts, fmt.Sprintf("in %s", config.Filename))
+	case config.Line > 0:
+		parts = append(parts, fmt.Sprintf("at line %d", config.Line))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// diagnosticFromObject returns true if the given diagnostic was reported by
+// the custom conditions of the given checkable object in the root module.
+func diagnosticFromObject(diag tfdiags.Diagnostic, object addrs.Referenceable) bool {
+	rule, ok := addrs.DiagnosticOriginatesFromCheckRule(diag)
+	if !ok {
+		return false
+	}
+
+	want := object.String()
+	switch addr := rule.Container.(type) {
+	case addrs.AbsOutputValue:
+		return addr.Module.IsRoot() && addr.OutputValue.String() == want
+	case addrs.AbsInputVariableInstance:
+		return addr.Module.IsRoot() && addr.Variable.String() == want
+	case addrs.AbsResourceInstance:
+		// Failures can be expected from a single instance or from all the
+		// instances of a resource.
+		return addr.Module.IsRoot() && (addr.Resource.String() == want || addr.Resource.Resource.String() == want)
+	case addrs.AbsCheck:
+		return addr.Module.IsRoot() && addr.Check.String() == want
+	default:
+		return false
+	}
+}
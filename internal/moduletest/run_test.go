@@ -6,6 +6,8 @@
 package moduletest
 
 import (
+	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -796,6 +798,156 @@ func TestRun_ValidateExpectedFailures(t *testing.T) {
 	}
 }
 
+func TestRun_ValidateExpectedFailureBlocks(t *testing.T) {
+	input := createDiagnostics(func(diags tfdiags.Diagnostics) tfdiags.Diagnostics {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid value for variable",
+			Detail:   "The input must not be empty.",
+			Subject: &hcl.Range{
+				Filename: "main.tf",
+				Start:    hcl.Pos{Line: 4, Column: 3},
+				End:      hcl.Pos{Line: 4, Column: 20},
+			},
+			Extra: &addrs.CheckRuleDiagnosticExtra{
+				CheckRule: addrs.NewCheckRule(addrs.AbsInputVariableInstance{
+					Module:   addrs.RootModuleInstance,
+					Variable: addrs.InputVariable{Name: "input"},
+				}, addrs.InputValidation, 0),
+			},
+		})
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Error in function call",
+			Detail:   "Call to function \"file\" failed: no file exists at \"missing.txt\".",
+			Subject: &hcl.Range{
+				Filename: "modules/child/main.tf",
+				Start:    hcl.Pos{Line: 10, Column: 11},
+				End:      hcl.Pos{Line: 10, Column: 30},
+			},
+		})
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Check block assertion failed",
+			Detail:   "The value is wrong.",
+			Extra: &addrs.CheckRuleDiagnosticExtra{
+				CheckRule: addrs.NewCheckRule(addrs.AbsCheck{
+					Module: addrs.RootModuleInstance,
+					Check:  addrs.Check{Name: "health"},
+				}, addrs.CheckAssertion, 0),
+			},
+		})
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Deprecated argument",
+			Detail:   "Warnings can't be expected.",
+		})
+		return diags
+	})
+
+	mustTraversal := func(src string) hcl.Traversal {
+		traversal, diags := hclsyntax.ParseTraversalAbs([]byte(src), "main.tftest.hcl", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatal(diags.Error())
+		}
+		return traversal
+	}
+
+	tcs := map[string]struct {
+		failures []*configs.TestExpectedFailure
+		want     []string
+	}{
+		"none": {
+			want: []string{
+				"Invalid value for variable",
+				"Error in function call",
+				"Check block assertion failed",
+				"Deprecated argument",
+			},
+		},
+		"object and detail": {
+			failures: []*configs.TestExpectedFailure{
+				{
+					Object: mustTraversal("var.input"),
+					Detail: regexp.MustCompile("must not be empty"),
+				},
+			},
+			want: []string{
+				"Error in function call",
+				"Check block assertion failed",
+				"Deprecated argument",
+			},
+		},
+		"location in child module": {
+			failures: []*configs.TestExpectedFailure{
+				{
+					Summary:  regexp.MustCompile("^Error in function call$"),
+					Filename: "child/main.tf",
+					Line:     10,
+				},
+			},
+			want: []string{
+				"Invalid value for variable",
+				"Check block assertion failed",
+				"Deprecated argument",
+			},
+		},
+		"check block": {
+			failures: []*configs.TestExpectedFailure{
+				{
+					Object: mustTraversal("check.health"),
+				},
+			},
+			want: []string{
+				"Invalid value for variable",
+				"Error in function call",
+				"Deprecated argument",
+			},
+		},
+		"mismatch": {
+			failures: []*configs.TestExpectedFailure{
+				{
+					Object: mustTraversal("var.input"),
+					Line:   5,
+				},
+				{
+					Summary: regexp.MustCompile("Deprecated"),
+				},
+			},
+			want: []string{
+				"Invalid value for variable",
+				"Error in function call",
+				"Check block assertion failed",
+				"Deprecated argument",
+				"Missing expected failure: No error was reported by var.input, at line 5.",
+				"Missing expected failure: No error was reported with a summary matching \"Deprecated\".",
+			},
+		},
+	}
+	for name, tc := range tcs {
+		t.Run(name, func(t *testing.T) {
+			run := Run{
+				Config: &configs.TestRun{
+					ExpectedFailures: tc.failures,
+				},
+			}
+
+			var got []string
+			for _, diag := range run.ValidateExpectedFailures(input) {
+				desc := diag.Description()
+				if desc.Summary == "Missing expected failure" {
+					got = append(got, fmt.Sprintf("%s: %s", desc.Summary, desc.Detail))
+					continue
+				}
+				got = append(got, desc.Summary)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("wrong diagnostics\n%s", diff)
+			}
+		})
+	}
+}
+
 func createDiagnostics(populate func(diags tfdiags.Diagnostics) tfdiags.Diagnostics) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 	diags = populate(diags)
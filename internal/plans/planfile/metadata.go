@@ -0,0 +1,107 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package planfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"time"
+)
+
+const metadataFilename = "tfplan-metadata.json"
+
+// metadataFormatVersion is the version of the format of the metadata file
+// embedded in plan files. It must be updated whenever the format changes in
+// a way that older versions of OpenTofu can't read.
+const metadataFormatVersion = "1.0"
+
+// CreatorEnvVar is the environment variable that, if set, overrides the
+// identity recorded as the creator of new plan files.
+const CreatorEnvVar = "TF_PLAN_CREATOR"
+
+// Metadata describes how and when a plan file was created. It is recorded in
+// plan files alongside the plan itself, so that it can be reviewed before the
+// plan is applied.
+//
+// Plan files created by earlier versions of OpenTofu don't have metadata.
+type Metadata struct {
+	// Creator identifies who created the plan, as given by CurrentCreator.
+	Creator string `json:"creator,omitempty"`
+
+	// CreatedAt is the time at which the plan file was written.
+	CreatedAt time.Time `json:"created_at"`
+
+	// TofuVersion is the version of OpenTofu that created the plan.
+	TofuVersion string `json:"tofu_version"`
+
+	// Backend and Workspace are the type of the backend and the name of the
+	// workspace that the plan's state belongs to.
+	Backend   string `json:"backend,omitempty"`
+	Workspace string `json:"workspace,omitempty"`
+
+	// StateLineage and StateSerial identify the state snapshot the plan was
+	// created from. The plan can only be applied to that same snapshot.
+	StateLineage string `json:"state_lineage,omitempty"`
+	StateSerial  uint64 `json:"state_serial"`
+}
+
+type metadataFile struct {
+	FormatVersion string `json:"format_version"`
+	*Metadata
+}
+
+// CurrentCreator returns the identity to record as the creator of new plan
+// files, which is the value of CreatorEnvVar if set, or otherwise the name
+// of the current user and host, like "user@host".
+func CurrentCreator() string {
+	if creator := os.Getenv(CreatorEnvVar); creator != "" {
+		return creator
+	}
+
+	var name string
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+	}
+	host, _ := os.Hostname()
+	switch {
+	case name != "" && host != "":
+		return name + "@" + host
+	case name != "":
+		return name
+	default:
+		return host
+	}
+}
+
+func writeMetadata(meta *Metadata, w io.Writer) error {
+	src, err := json.MarshalIndent(metadataFile{
+		FormatVersion: metadataFormatVersion,
+		Metadata:      meta,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(src)
+	return err
+}
+
+func readMetadata(r io.Reader) (*Metadata, error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	file := metadataFile{Metadata: &Metadata{}}
+	if err := json.Unmarshal(src, &file); err != nil {
+		return nil, fmt.Errorf("invalid plan metadata: %w", err)
+	}
+	if file.FormatVersion != metadataFormatVersion {
+		return nil, fmt.Errorf("unsupported plan metadata format version %q", file.FormatVersion)
+	}
+	return file.Metadata, nil
+}
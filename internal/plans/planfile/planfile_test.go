@@ -6,10 +6,15 @@
 package planfile
 
 import (
+	"bytes"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
 	"github.com/google/go-cmp/cmp"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -209,3 +214,140 @@ func TestWrappedCloud(t *testing.T) {
 		t.Fatalf("wrapped plan claims to be both kinds of plan at once")
 	}
 }
+
+func TestCreate_metadataAndSignature(t *testing.T) {
+	privateKey, publicKey := testSigningKeys(t, "Plan Signer <signer@example.com>")
+	_, otherPublicKey := testSigningKeys(t, "Someone Else <else@example.com>")
+
+	signingKey, err := ParseSigningKey(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseSigningKey(publicKey); err == nil {
+		t.Fatal("public key accepted as a signing key")
+	}
+
+	stateFile := &statefile.File{
+		TerraformVersion: tfversion.SemVer,
+		Serial:           7,
+		Lineage:          "abc123",
+		State:            states.NewState(),
+	}
+	args := CreateArgs{
+		ConfigSnapshot:       configload.NewEmptySnapshot(),
+		PreviousRunStateFile: stateFile,
+		StateFile:            stateFile,
+		Plan: &plans.Plan{
+			Changes: plans.NewChanges(),
+			Backend: plans.Backend{
+				Type:      "local",
+				Config:    plans.DynamicValue([]byte("config placeholder")),
+				Workspace: "staging",
+			},
+		},
+		Creator: "alice@example",
+	}
+
+	unsignedFn := filepath.Join(t.TempDir(), "tfplan")
+	if err := Create(unsignedFn, args, encryption.PlanEncryptionDisabled()); err != nil {
+		t.Fatalf("failed to create plan file: %s", err)
+	}
+	args.SigningKey = signingKey
+	signedFn := filepath.Join(t.TempDir(), "tfplan")
+	if err := Create(signedFn, args, encryption.PlanEncryptionDisabled()); err != nil {
+		t.Fatalf("failed to create plan file: %s", err)
+	}
+
+	unsigned, err := Open(unsignedFn, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed, err := Open(signedFn, encryption.PlanEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	meta, err := signed.ReadMetadata()
+	if err != nil {
+		t.Fatalf("failed to read metadata: %s", err)
+	}
+	if meta.CreatedAt.IsZero() {
+		t.Error("missing creation time")
+	}
+	meta.CreatedAt = time.Time{}
+	want := &Metadata{
+		Creator:      "alice@example",
+		TofuVersion:  tfversion.String(),
+		Backend:      "local",
+		Workspace:    "staging",
+		StateLineage: "abc123",
+		StateSerial:  7,
+	}
+	if diff := cmp.Diff(want, meta); diff != "" {
+		t.Errorf("wrong metadata\n%s", diff)
+	}
+
+	keys, err := ParseVerifyingKeys(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKeys, err := ParseVerifyingKeys(otherPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if unsigned.Signed() || !signed.Signed() {
+		t.Fatalf("wrong signed status")
+	}
+	signer, err := signed.VerifySignature(keys)
+	if err != nil {
+		t.Fatalf("failed to verify signature: %s", err)
+	}
+	if !strings.Contains(signer, "Plan Signer <signer@example.com>") {
+		t.Errorf("wrong signer %q", signer)
+	}
+	if _, err := signed.VerifySignature(otherKeys); err == nil {
+		t.Error("signature verified with the wrong key")
+	}
+	if _, err := unsigned.VerifySignature(keys); err != ErrUnsigned {
+		t.Errorf("wrong error for unsigned plan: %v", err)
+	}
+
+	// The signed plan must still be readable as usual.
+	if _, err := signed.ReadPlan(); err != nil {
+		t.Errorf("failed to read signed plan: %s", err)
+	}
+}
+
+// testSigningKeys generates a new OpenPGP key with the given identity,
+// returning its ASCII-armored private and public keys.
+func testSigningKeys(t *testing.T, identity string) (private, public []byte) {
+	t.Helper()
+
+	name, email, _ := strings.Cut(strings.TrimSuffix(identity, ">"), " <")
+	entity, err := openpgp.NewEntity(name, "", email, &packet.Config{
+		Algorithm: packet.PubKeyAlgoEdDSA,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var privBuf, pubBuf bytes.Buffer
+	w, err := armor.Encode(&privBuf, openpgp.PrivateKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.SerializePrivate(w, nil); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	w, err = armor.Encode(&pubBuf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	return privBuf.Bytes(), pubBuf.Bytes()
+}
@@ -11,6 +11,10 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
 
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configload"
@@ -277,3 +281,86 @@ func (r *Reader) ReadDependencyLocks() (*depsfile.Locks, tfdiags.Diagnostics) {
 	))
 	return nil, diags
 }
+
+// ReadMetadata reads the metadata recorded in the plan file about how it was
+// created.
+//
+// Plan files created by earlier versions of OpenTofu have no metadata, in
+// which case ReadMetadata returns nil and no error.
+func (r *Reader) ReadMetadata() (*Metadata, error) {
+	for _, file := range r.zip.File {
+		if file.Name == metadataFilename {
+			r, err := file.Open()
+			if err != nil {
+				return nil, errUnusable(fmt.Errorf("failed to extract metadata from plan file: %w", err))
+			}
+			defer r.Close()
+			meta, err := readMetadata(r)
+			if err != nil {
+				return nil, errUnusable(err)
+			}
+			return meta, nil
+		}
+	}
+	return nil, nil
+}
+
+// Signed returns true if the plan file contains a signature.
+func (r *Reader) Signed() bool {
+	for _, file := range r.zip.File {
+		if file.Name == signatureFilename {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifySignature checks that the plan file was signed with one of the given
+// keys, and that its content hasn't changed since. It returns a description
+// of the key that signed it.
+//
+// If the plan file isn't signed, the returned error is ErrUnsigned.
+func (r *Reader) VerifySignature(keys *VerifyingKeys) (string, error) {
+	var sigFile *zip.File
+	for _, file := range r.zip.File {
+		if file.Name == signatureFilename {
+			sigFile = file
+			break
+		}
+	}
+	if sigFile == nil {
+		return "", ErrUnsigned
+	}
+
+	sr, err := sigFile.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to extract signature from plan file: %w", err)
+	}
+	defer sr.Close()
+	content, err := signedContent(r.zip.File)
+	if err != nil {
+		return "", err
+	}
+
+	entity, err := openpgp.CheckDetachedSignature(keys.keyring, bytes.NewReader(content), sr, nil)
+	if err != nil {
+		return "", fmt.Errorf("the plan file signature is not valid: %w", err)
+	}
+	return signerString(entity), nil
+}
+
+// signerString describes the given key by its ID and identity names.
+func signerString(entity *openpgp.Entity) string {
+	if entity == nil || entity.PrimaryKey == nil {
+		return ""
+	}
+	var names []string
+	for name := range entity.Identities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		return entity.PrimaryKey.KeyIdString()
+	}
+	return fmt.Sprintf("%s (%s)", entity.PrimaryKey.KeyIdString(), strings.Join(names, ", "))
+}
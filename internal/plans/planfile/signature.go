@@ -0,0 +1,131 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package planfile
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+)
+
+const signatureFilename = "tfplan.sig"
+
+// ErrUnsigned is returned when verifying the signature of a plan file that
+// was not signed.
+var ErrUnsigned = errors.New("the plan file is not signed")
+
+// SigningKey is an OpenPGP private key used to sign new plan files.
+type SigningKey struct {
+	entity *openpgp.Entity
+}
+
+// ParseSigningKey parses an ASCII-armored OpenPGP private key for signing
+// plan files. Keys protected by a passphrase are not supported.
+func ParseSigningKey(src []byte) (*SigningKey, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+	for _, entity := range keyring {
+		if entity.PrivateKey == nil {
+			continue
+		}
+		if entity.PrivateKey.Encrypted {
+			return nil, fmt.Errorf("the signing key is protected by a passphrase, which is not supported")
+		}
+		return &SigningKey{entity: entity}, nil
+	}
+	return nil, fmt.Errorf("the signing key file does not contain a private key")
+}
+
+// VerifyingKeys are the OpenPGP public keys that a plan file may be signed
+// with.
+type VerifyingKeys struct {
+	keyring openpgp.EntityList
+}
+
+// ParseVerifyingKeys parses one or more ASCII-armored OpenPGP public keys
+// for verifying the signatures of plan files.
+func ParseVerifyingKeys(src []byte) (*VerifyingKeys, error) {
+	keyring, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("invalid verification key: %w", err)
+	}
+	return &VerifyingKeys{keyring: keyring}, nil
+}
+
+// signedContent returns the content that the signature of a plan file
+// covers, which lists the SHA256 checksum of each of the given files in the
+// same format as a SHA256SUMS file. The signature file itself is excluded.
+func signedContent(files []*zip.File) ([]byte, error) {
+	var lines []string
+	for _, file := range files {
+		if file.Name == signatureFilename {
+			continue
+		}
+		r, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, r)
+		r.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", file.Name, err)
+		}
+		lines = append(lines, fmt.Sprintf("%x  %s\n", h.Sum(nil), file.Name))
+	}
+	sort.Strings(lines)
+	return []byte(strings.Join(lines, "")), nil
+}
+
+// signZip returns a copy of the given zip archive with an added detached
+// signature of its content, made with the given key.
+func signZip(src []byte, key *SigningKey) ([]byte, error) {
+	r, err := zip.NewReader(bytes.NewReader(src), int64(len(src)))
+	if err != nil {
+		return nil, err
+	}
+	content, err := signedContent(r.File)
+	if err != nil {
+		return nil, err
+	}
+	var sig bytes.Buffer
+	if err := openpgp.DetachSign(&sig, key.entity, bytes.NewReader(content), nil); err != nil {
+		return nil, fmt.Errorf("failed to sign plan: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+	for _, file := range r.File {
+		if err := zw.Copy(file); err != nil {
+			return nil, err
+		}
+	}
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     signatureFilename,
+		Method:   zip.Store,
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signature file: %w", err)
+	}
+	if _, err := w.Write(sig.Bytes()); err != nil {
+		return nil, fmt.Errorf("failed to write signature: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/states/statefile"
+	"github.com/opentofu/opentofu/version"
 )
 
 type CreateArgs struct {
@@ -46,6 +47,15 @@ type CreateArgs struct {
 	// checked prior to creating the plan, so we can make sure that all of the
 	// same dependencies are still available when applying the plan.
 	DependencyLocks *depsfile.Locks
+
+	// Creator identifies who is creating the plan, to record in the plan
+	// file's metadata. Callers should usually set this to the result of
+	// CurrentCreator.
+	Creator string
+
+	// SigningKey, if set, is used to add a detached signature of the content
+	// of the plan file, which can be verified before applying it.
+	SigningKey *SigningKey
 }
 
 // Create creates a new plan file with the given filename, overwriting any
@@ -136,10 +146,52 @@ func Create(filename string, args CreateArgs, enc encryption.PlanEncryption) err
 		}
 	}
 
+	// tfplan-metadata.json file, describing how the plan was created
+	{
+		meta := &Metadata{
+			Creator:     args.Creator,
+			CreatedAt:   time.Now().UTC(),
+			TofuVersion: version.String(),
+		}
+		if args.Plan != nil {
+			meta.Backend = args.Plan.Backend.Type
+			meta.Workspace = args.Plan.Backend.Workspace
+		}
+		if args.StateFile != nil {
+			meta.StateLineage = args.StateFile.Lineage
+			meta.StateSerial = args.StateFile.Serial
+		}
+
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     metadataFilename,
+			Method:   zip.Deflate,
+			Modified: time.Now(),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create plan metadata file: %w", err)
+		}
+		err = writeMetadata(meta, w)
+		if err != nil {
+			return fmt.Errorf("failed to write plan metadata: %w", err)
+		}
+	}
+
 	// Finish zip file
 	zw.Close()
+	payload := buff.Bytes()
+
+	// The signature covers everything else in the zip file, so it's added
+	// once the rest is complete.
+	if args.SigningKey != nil {
+		var err error
+		payload, err = signZip(payload, args.SigningKey)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Encrypt payload
-	encrypted, err := enc.EncryptPlan(buff.Bytes())
+	encrypted, err := enc.EncryptPlan(payload)
 	if err != nil {
 		return err
 	}
@@ -74,9 +74,20 @@ type GRPCProvider struct {
 	// schema stores the schema for this provider. This is used to properly
 	// serialize the requests for schemas.
 	schema providers.GetProviderSchemaResponse
+
+	// priorities stores the resource priorities suggested by this provider,
+	// which are requested only once.
+	priorities map[string]int
 }
 
 var _ providers.Interface = new(GRPCProvider)
+var _ providers.BulkResourceReader = new(GRPCProvider)
+var _ providers.ResourcePriorityHinter = new(GRPCProvider)
+var _ providers.ResourceFinder = new(GRPCProvider)
+var _ providers.ResourceReadinessChecker = new(GRPCProvider)
+var _ providers.ResourceLister = new(GRPCProvider)
+var _ providers.ActionInvoker = new(GRPCProvider)
+var _ providers.HealthChecker = new(GRPCProvider)
 
 func (p *GRPCProvider) GetProviderSchema() (resp providers.GetProviderSchemaResponse) {
 	logger.Trace("GRPCProvider: GetProviderSchema")
@@ -154,6 +165,13 @@ func (p *GRPCProvider) GetProviderSchema() (resp providers.GetProviderSchemaResp
 	if protoResp.ServerCapabilities != nil {
 		resp.ServerCapabilities.PlanDestroy = protoResp.ServerCapabilities.PlanDestroy
 		resp.ServerCapabilities.GetProviderSchemaOptional = protoResp.ServerCapabilities.GetProviderSchemaOptional
+		resp.ServerCapabilities.ReadResources = protoResp.ServerCapabilities.ReadResources
+		resp.ServerCapabilities.ResourcePriorities = protoResp.ServerCapabilities.ResourcePriorities
+		resp.ServerCapabilities.FindResource = protoResp.ServerCapabilities.FindResource
+		resp.ServerCapabilities.CheckResourceReadiness = protoResp.ServerCapabilities.CheckResourceReadiness
+		resp.ServerCapabilities.ListResources = protoResp.ServerCapabilities.ListResources
+		resp.ServerCapabilities.Actions = protoResp.ServerCapabilities.Actions
+		resp.ServerCapabilities.CheckHealth = protoResp.ServerCapabilities.CheckHealth
 	}
 
 	// Set the global provider cache so that future calls to this provider can use the cached value.
@@ -176,6 +194,12 @@ func (p *GRPCProvider) GetProviderSchema() (resp providers.GetProviderSchemaResp
 	return resp
 }
 
+// AnnouncedCapabilities returns the server capabilities from the provider's
+// schema, which determine the optional provider interfaces it supports.
+func (p *GRPCProvider) AnnouncedCapabilities() providers.ServerCapabilities {
+	return p.GetProviderSchema().ServerCapabilities
+}
+
 func (p *GRPCProvider) ValidateProviderConfig(r providers.ValidateProviderConfigRequest) (resp providers.ValidateProviderConfigResponse) {
 	logger.Trace("GRPCProvider: ValidateProviderConfig")
 
@@ -332,6 +356,37 @@ func (p *GRPCProvider) UpgradeResourceState(r providers.UpgradeResourceStateRequ
 	return resp
 }
 
+// ResourcePriorities returns the default scheduling priorities of the
+// provider's resource types. Failing to get them only affects the order of
+// operations, so errors are logged rather than returned.
+func (p *GRPCProvider) ResourcePriorities() map[string]int {
+	logger.Trace("GRPCProvider: ResourcePriorities")
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.priorities != nil {
+		return p.priorities
+	}
+	p.priorities = make(map[string]int)
+
+	protoResp, err := p.client.GetResourcePriorities(p.ctx, new(proto.GetResourcePriorities_Request))
+	if err != nil {
+		logger.Warn("GRPCProvider: failed to get resource priorities", "error", grpcErr(err).Err())
+		return p.priorities
+	}
+	diags := convert.ProtoToDiagnostics(protoResp.Diagnostics)
+	if diags.HasErrors() {
+		logger.Warn("GRPCProvider: failed to get resource priorities", "error", diags.Err())
+		return p.priorities
+	}
+
+	for typeName, priority := range protoResp.Priorities {
+		p.priorities[typeName] = int(priority)
+	}
+	return p.priorities
+}
+
 func (p *GRPCProvider) ConfigureProvider(r providers.ConfigureProviderRequest) (resp providers.ConfigureProviderResponse) {
 	logger.Trace("GRPCProvider: ConfigureProvider")
 
@@ -436,6 +491,82 @@ func (p *GRPCProvider) ReadResource(r providers.ReadResourceRequest) (resp provi
 	return resp
 }
 
+func (p *GRPCProvider) ReadResources(r providers.ReadResourcesRequest) (resp providers.ReadResourcesResponse) {
+	logger.Trace("GRPCProvider: ReadResources")
+
+	schema := p.GetProviderSchema()
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = schema.Diagnostics
+		return resp
+	}
+
+	metaSchema := schema.ProviderMeta
+
+	protoReq := &proto.ReadResources_Request{
+		Requests: make([]*proto.ReadResource_Request, len(r.Requests)),
+	}
+	types := make([]cty.Type, len(r.Requests))
+	for i, req := range r.Requests {
+		resSchema, ok := schema.ResourceTypes[req.TypeName]
+		if !ok {
+			resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown resource type " + req.TypeName))
+			return resp
+		}
+		types[i] = resSchema.Block.ImpliedType()
+
+		mp, err := msgpack.Marshal(req.PriorState, types[i])
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(err)
+			return resp
+		}
+
+		protoReq.Requests[i] = &proto.ReadResource_Request{
+			TypeName:     req.TypeName,
+			CurrentState: &proto.DynamicValue{Msgpack: mp},
+			Private:      req.Private,
+		}
+
+		if metaSchema.Block != nil {
+			metaMP, err := msgpack.Marshal(req.ProviderMeta, metaSchema.Block.ImpliedType())
+			if err != nil {
+				resp.Diagnostics = resp.Diagnostics.Append(err)
+				return resp
+			}
+			protoReq.Requests[i].ProviderMeta = &proto.DynamicValue{Msgpack: metaMP}
+		}
+	}
+
+	protoResp, err := p.client.ReadResources(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+	if resp.Diagnostics.HasErrors() {
+		return resp
+	}
+	if len(protoResp.Responses) != len(r.Requests) {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("provider returned %d results for a bulk read of %d resource instances", len(protoResp.Responses), len(r.Requests)))
+		return resp
+	}
+
+	resp.Responses = make([]providers.ReadResourceResponse, len(protoResp.Responses))
+	for i, protoRead := range protoResp.Responses {
+		read := &resp.Responses[i]
+		read.Diagnostics = read.Diagnostics.Append(convert.ProtoToDiagnostics(protoRead.Diagnostics))
+
+		state, err := decodeDynamicValue(protoRead.NewState, types[i])
+		if err != nil {
+			read.Diagnostics = read.Diagnostics.Append(err)
+			continue
+		}
+		read.NewState = state
+		read.Private = protoRead.Private
+	}
+
+	return resp
+}
+
 func (p *GRPCProvider) PlanResourceChange(r providers.PlanResourceChangeRequest) (resp providers.PlanResourceChangeResponse) {
 	logger.Trace("GRPCProvider: PlanResourceChange")
 
@@ -638,6 +769,193 @@ func (p *GRPCProvider) ImportResourceState(r providers.ImportResourceStateReques
 	return resp
 }
 
+func (p *GRPCProvider) FindResource(r providers.FindResourceRequest) (resp providers.FindResourceResponse) {
+	logger.Trace("GRPCProvider: FindResource")
+
+	schema := p.GetProviderSchema()
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = schema.Diagnostics
+		return resp
+	}
+
+	resSchema, ok := schema.ResourceTypes[r.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown resource type %q", r.TypeName))
+		return resp
+	}
+
+	mp, err := msgpack.Marshal(r.Config, resSchema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+
+	protoReq := &proto.FindResource_Request{
+		TypeName: r.TypeName,
+		Config:   &proto.DynamicValue{Msgpack: mp},
+	}
+
+	protoResp, err := p.client.FindResource(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+	resp.ID = protoResp.Id
+	return resp
+}
+
+func (p *GRPCProvider) CheckResourceReadiness(r providers.CheckResourceReadinessRequest) (resp providers.CheckResourceReadinessResponse) {
+	logger.Trace("GRPCProvider: CheckResourceReadiness")
+
+	schema := p.GetProviderSchema()
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = schema.Diagnostics
+		return resp
+	}
+
+	resSchema, ok := schema.ResourceTypes[r.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown resource type %q", r.TypeName))
+		return resp
+	}
+
+	mp, err := msgpack.Marshal(r.State, resSchema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+
+	protoReq := &proto.CheckResourceReadiness_Request{
+		TypeName: r.TypeName,
+		State:    &proto.DynamicValue{Msgpack: mp},
+	}
+
+	protoResp, err := p.client.CheckResourceReadiness(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+	resp.Ready = protoResp.Ready
+	return resp
+}
+
+func (p *GRPCProvider) ListResources(r providers.ListResourcesRequest) (resp providers.ListResourcesResponse) {
+	logger.Trace("GRPCProvider: ListResources")
+
+	schema := p.GetProviderSchema()
+	if schema.Diagnostics.HasErrors() {
+		resp.Diagnostics = schema.Diagnostics
+		return resp
+	}
+
+	resSchema, ok := schema.ResourceTypes[r.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown resource type %q", r.TypeName))
+		return resp
+	}
+
+	protoReq := &proto.ListResources_Request{
+		TypeName: r.TypeName,
+		Filters:  r.Filters,
+	}
+
+	protoResp, err := p.client.ListResources(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+
+	for _, res := range protoResp.Resources {
+		listed := providers.ListedResource{
+			ID:   res.Id,
+			Name: res.Name,
+		}
+		if res.State != nil {
+			listed.State, err = decodeDynamicValue(res.State, resSchema.Block.ImpliedType())
+			if err != nil {
+				resp.Diagnostics = resp.Diagnostics.Append(err)
+				return resp
+			}
+		}
+		resp.Resources = append(resp.Resources, listed)
+	}
+	return resp
+}
+
+func (p *GRPCProvider) GetActionSchemas() (resp providers.GetActionSchemasResponse) {
+	logger.Trace("GRPCProvider: GetActionSchemas")
+
+	protoResp, err := p.client.GetActionSchemas(p.ctx, new(proto.GetActionSchemas_Request))
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+
+	resp.Actions = make(map[string]providers.Schema, len(protoResp.Actions))
+	for name, schema := range protoResp.Actions {
+		resp.Actions[name] = convert.ProtoToProviderSchema(schema)
+	}
+	return resp
+}
+
+func (p *GRPCProvider) InvokeAction(r providers.InvokeActionRequest) (resp providers.InvokeActionResponse) {
+	logger.Trace("GRPCProvider: InvokeAction")
+
+	schemas := p.GetActionSchemas()
+	if schemas.Diagnostics.HasErrors() {
+		resp.Diagnostics = schemas.Diagnostics
+		return resp
+	}
+
+	actionSchema, ok := schemas.Actions[r.TypeName]
+	if !ok {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf("unknown action type %q", r.TypeName))
+		return resp
+	}
+
+	mp, err := msgpack.Marshal(r.Config, actionSchema.Block.ImpliedType())
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(err)
+		return resp
+	}
+
+	protoReq := &proto.InvokeAction_Request{
+		TypeName: r.TypeName,
+		Config:   &proto.DynamicValue{Msgpack: mp},
+	}
+
+	protoResp, err := p.client.InvokeAction(p.ctx, protoReq)
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+
+	if protoResp.Result != nil {
+		resp.Result, err = decodeDynamicValue(protoResp.Result, cty.DynamicPseudoType)
+		if err != nil {
+			resp.Diagnostics = resp.Diagnostics.Append(err)
+		}
+	}
+	return resp
+}
+
+func (p *GRPCProvider) CheckHealth(r providers.CheckHealthRequest) (resp providers.CheckHealthResponse) {
+	logger.Trace("GRPCProvider: CheckHealth")
+
+	protoResp, err := p.client.CheckHealth(p.ctx, new(proto.CheckHealth_Request))
+	if err != nil {
+		resp.Diagnostics = resp.Diagnostics.Append(grpcErr(err))
+		return resp
+	}
+	resp.Diagnostics = resp.Diagnostics.Append(convert.ProtoToDiagnostics(protoResp.Diagnostics))
+	return resp
+}
+
 func (p *GRPCProvider) ReadDataSource(r providers.ReadDataSourceRequest) (resp providers.ReadDataSourceResponse) {
 	logger.Trace("GRPCProvider: ReadDataSource")
 
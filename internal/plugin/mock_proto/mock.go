@@ -77,6 +77,46 @@ func (mr *MockProviderClientMockRecorder) CallFunction(arg0, arg1 interface{}, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CallFunction", reflect.TypeOf((*MockProviderClient)(nil).CallFunction), varargs...)
 }
 
+// CheckHealth mocks base method.
+func (m *MockProviderClient) CheckHealth(arg0 context.Context, arg1 *tfplugin5.CheckHealth_Request, arg2 ...grpc.CallOption) (*tfplugin5.CheckHealth_Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckHealth", varargs...)
+	ret0, _ := ret[0].(*tfplugin5.CheckHealth_Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckHealth indicates an expected call of CheckHealth.
+func (mr *MockProviderClientMockRecorder) CheckHealth(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckHealth", reflect.TypeOf((*MockProviderClient)(nil).CheckHealth), varargs...)
+}
+
+// CheckResourceReadiness mocks base method.
+func (m *MockProviderClient) CheckResourceReadiness(arg0 context.Context, arg1 *tfplugin5.CheckResourceReadiness_Request, arg2 ...grpc.CallOption) (*tfplugin5.CheckResourceReadiness_Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "CheckResourceReadiness", varargs...)
+	ret0, _ := ret[0].(*tfplugin5.CheckResourceReadiness_Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckResourceReadiness indicates an expected call of CheckResourceReadiness.
+func (mr *MockProviderClientMockRecorder) CheckResourceReadiness(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckResourceReadiness", reflect.TypeOf((*MockProviderClient)(nil).CheckResourceReadiness), varargs...)
+}
+
 // Configure mocks base method.
 func (m *MockProviderClient) Configure(arg0 context.Context, arg1 *tfplugin5.Configure_Request, arg2 ...grpc.CallOption) (*tfplugin5.Configure_Response, error) {
 	m.ctrl.T.Helper()
@@ -97,6 +137,46 @@ func (mr *MockProviderClientMockRecorder) Configure(arg0, arg1 interface{}, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Configure", reflect.TypeOf((*MockProviderClient)(nil).Configure), varargs...)
 }
 
+// FindResource mocks base method.
+func (m *MockProviderClient) FindResource(arg0 context.Context, arg1 *tfplugin5.FindResource_Request, arg2 ...grpc.CallOption) (*tfplugin5.FindResource_Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "FindResource", varargs...)
+	ret0, _ := ret[0].(*tfplugin5.FindResource_Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindResource indicates an expected call of FindResource.
+func (mr *MockProviderClientMockRecorder) FindResource(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindResource", reflect.TypeOf((*MockProviderClient)(nil).FindResource), varargs...)
+}
+
+// GetActionSchemas mocks base method.
+func (m *MockProviderClient) GetActionSchemas(arg0 context.Context, arg1 *tfplugin5.GetActionSchemas_Request, arg2 ...grpc.CallOption) (*tfplugin5.GetActionSchemas_Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetActionSchemas", varargs...)
+	ret0, _ := ret[0].(*tfplugin5.GetActionSchemas_Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActionSchemas indicates an expected call of GetActionSchemas.
+func (mr *MockProviderClientMockRecorder) GetActionSchemas(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActionSchemas", reflect.TypeOf((*MockProviderClient)(nil).GetActionSchemas), varargs...)
+}
+
 // GetFunctions mocks base method.
 func (m *MockProviderClient) GetFunctions(arg0 context.Context, arg1 *tfplugin5.GetFunctions_Request, arg2 ...grpc.CallOption) (*tfplugin5.GetFunctions_Response, error) {
 	m.ctrl.T.Helper()
@@ -137,6 +217,26 @@ func (mr *MockProviderClientMockRecorder) GetMetadata(arg0, arg1 interface{}, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMetadata", reflect.TypeOf((*MockProviderClient)(nil).GetMetadata), varargs...)
 }
 
+// GetResourcePriorities mocks base method.
+func (m *MockProviderClient) GetResourcePriorities(arg0 context.Context, arg1 *tfplugin5.GetResourcePriorities_Request, arg2 ...grpc.CallOption) (*tfplugin5.GetResourcePriorities_Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetResourcePriorities", varargs...)
+	ret0, _ := ret[0].(*tfplugin5.GetResourcePriorities_Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResourcePriorities indicates an expected call of GetResourcePriorities.
+func (mr *MockProviderClientMockRecorder) GetResourcePriorities(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResourcePriorities", reflect.TypeOf((*MockProviderClient)(nil).GetResourcePriorities), varargs...)
+}
+
 // GetSchema mocks base method.
 func (m *MockProviderClient) GetSchema(arg0 context.Context, arg1 *tfplugin5.GetProviderSchema_Request, arg2 ...grpc.CallOption) (*tfplugin5.GetProviderSchema_Response, error) {
 	m.ctrl.T.Helper()
@@ -177,6 +277,46 @@ func (mr *MockProviderClientMockRecorder) ImportResourceState(arg0, arg1 interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportResourceState", reflect.TypeOf((*MockProviderClient)(nil).ImportResourceState), varargs...)
 }
 
+// InvokeAction mocks base method.
+func (m *MockProviderClient) InvokeAction(arg0 context.Context, arg1 *tfplugin5.InvokeAction_Request, arg2 ...grpc.CallOption) (*tfplugin5.InvokeAction_Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "InvokeAction", varargs...)
+	ret0, _ := ret[0].(*tfplugin5.InvokeAction_Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InvokeAction indicates an expected call of InvokeAction.
+func (mr *MockProviderClientMockRecorder) InvokeAction(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvokeAction", reflect.TypeOf((*MockProviderClient)(nil).InvokeAction), varargs...)
+}
+
+// ListResources mocks base method.
+func (m *MockProviderClient) ListResources(arg0 context.Context, arg1 *tfplugin5.ListResources_Request, arg2 ...grpc.CallOption) (*tfplugin5.ListResources_Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ListResources", varargs...)
+	ret0, _ := ret[0].(*tfplugin5.ListResources_Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListResources indicates an expected call of ListResources.
+func (mr *MockProviderClientMockRecorder) ListResources(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListResources", reflect.TypeOf((*MockProviderClient)(nil).ListResources), varargs...)
+}
+
 // MoveResourceState mocks base method.
 func (m *MockProviderClient) MoveResourceState(arg0 context.Context, arg1 *tfplugin5.MoveResourceState_Request, arg2 ...grpc.CallOption) (*tfplugin5.MoveResourceState_Response, error) {
 	m.ctrl.T.Helper()
@@ -277,6 +417,26 @@ func (mr *MockProviderClientMockRecorder) ReadResource(arg0, arg1 interface{}, a
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadResource", reflect.TypeOf((*MockProviderClient)(nil).ReadResource), varargs...)
 }
 
+// ReadResources mocks base method.
+func (m *MockProviderClient) ReadResources(arg0 context.Context, arg1 *tfplugin5.ReadResources_Request, arg2 ...grpc.CallOption) (*tfplugin5.ReadResources_Response, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ReadResources", varargs...)
+	ret0, _ := ret[0].(*tfplugin5.ReadResources_Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReadResources indicates an expected call of ReadResources.
+func (mr *MockProviderClientMockRecorder) ReadResources(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadResources", reflect.TypeOf((*MockProviderClient)(nil).ReadResources), varargs...)
+}
+
 // Stop mocks base method.
 func (m *MockProviderClient) Stop(arg0 context.Context, arg1 *tfplugin5.Stop_Request, arg2 ...grpc.CallOption) (*tfplugin5.Stop_Response, error) {
 	m.ctrl.T.Helper()
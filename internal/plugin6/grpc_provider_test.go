@@ -19,6 +19,7 @@ import (
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/msgpack"
 
 	mockproto "github.com/opentofu/opentofu/internal/plugin6/mock_proto"
 	proto "github.com/opentofu/opentofu/internal/tfplugin6"
@@ -433,6 +434,271 @@ func TestGRPCProvider_Stop(t *testing.T) {
 	}
 }
 
+func TestGRPCProvider_announcedCapabilities(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+	if _, ok := providers.AsBulkResourceReader(p); ok {
+		t.Error("provider supports bulk reads, but didn't announce them")
+	}
+
+	ctrl := gomock.NewController(t)
+	client = mockproto.NewMockProviderClient(ctrl)
+	schema := providerProtoSchema()
+	schema.ServerCapabilities = &proto.ServerCapabilities{
+		ReadResources: true,
+	}
+	client.EXPECT().GetProviderSchema(
+		gomock.Any(),
+		gomock.Any(),
+		gomock.Any(),
+	).Return(schema, nil)
+	p = &GRPCProvider{
+		client: client,
+	}
+	if _, ok := providers.AsBulkResourceReader(p); !ok {
+		t.Error("provider doesn't support bulk reads, but announced them")
+	}
+}
+
+func TestGRPCProvider_ResourcePriorities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mockproto.NewMockProviderClient(ctrl)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().GetResourcePriorities(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.GetResourcePriorities_Response{
+		Priorities: map[string]int64{
+			"resource": 10,
+		},
+	}, nil).Times(1)
+
+	for i := 0; i < 2; i++ {
+		got := p.ResourcePriorities()
+		want := map[string]int{
+			"resource": 10,
+		}
+		if !cmp.Equal(want, got) {
+			t.Fatal(cmp.Diff(want, got))
+		}
+	}
+}
+
+func TestGRPCProvider_FindResource(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().FindResource(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ interface{}, req *proto.FindResource_Request, _ ...interface{}) (*proto.FindResource_Response, error) {
+		if req.TypeName != "resource" {
+			t.Errorf("wrong type name %q", req.TypeName)
+		}
+		// The configuration can include unknown values.
+		if got, want := req.Config.Msgpack, []byte("\x81\xa4attr\xd4\x00\x00"); !bytes.Equal(got, want) {
+			t.Errorf("wrong config %q; want %q", got, want)
+		}
+		return &proto.FindResource_Response{
+			Id: "existing-id",
+		}, nil
+	})
+
+	resp := p.FindResource(providers.FindResourceRequest{
+		TypeName: "resource",
+		Config: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.UnknownVal(cty.String),
+		}),
+	})
+
+	checkDiags(t, resp.Diagnostics)
+	if resp.ID != "existing-id" {
+		t.Errorf("wrong ID %q", resp.ID)
+	}
+}
+
+func TestGRPCProvider_CheckResourceReadiness(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().CheckResourceReadiness(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ interface{}, req *proto.CheckResourceReadiness_Request, _ ...interface{}) (*proto.CheckResourceReadiness_Response, error) {
+		if req.TypeName != "resource" {
+			t.Errorf("wrong type name %q", req.TypeName)
+		}
+		if got, want := req.State.Msgpack, []byte("\x81\xa4attr\xa3bar"); !bytes.Equal(got, want) {
+			t.Errorf("wrong state %q; want %q", got, want)
+		}
+		return &proto.CheckResourceReadiness_Response{
+			Ready: true,
+		}, nil
+	})
+
+	resp := p.CheckResourceReadiness(providers.CheckResourceReadinessRequest{
+		TypeName: "resource",
+		State: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal("bar"),
+		}),
+	})
+
+	checkDiags(t, resp.Diagnostics)
+	if !resp.Ready {
+		t.Error("object not reported as ready")
+	}
+}
+
+func TestGRPCProvider_ListResources(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().ListResources(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ interface{}, req *proto.ListResources_Request, _ ...interface{}) (*proto.ListResources_Response, error) {
+		if req.TypeName != "resource" {
+			t.Errorf("wrong type name %q", req.TypeName)
+		}
+		if got, want := req.Filters, map[string]string{"tag": "web"}; !cmp.Equal(got, want) {
+			t.Errorf("wrong filters %#v; want %#v", got, want)
+		}
+		return &proto.ListResources_Response{
+			Resources: []*proto.ListResources_Resource{
+				{
+					Id:    "abc",
+					Name:  "web",
+					State: &proto.DynamicValue{Msgpack: []byte("\x81\xa4attr\xa3bar")},
+				},
+				{
+					Id: "def",
+				},
+			},
+		}, nil
+	})
+
+	resp := p.ListResources(providers.ListResourcesRequest{
+		TypeName: "resource",
+		Filters:  map[string]string{"tag": "web"},
+	})
+
+	checkDiags(t, resp.Diagnostics)
+	want := []providers.ListedResource{
+		{
+			ID:   "abc",
+			Name: "web",
+			State: cty.ObjectVal(map[string]cty.Value{
+				"attr": cty.StringVal("bar"),
+			}),
+		},
+		{
+			ID: "def",
+		},
+	}
+	if !cmp.Equal(resp.Resources, want, typeComparer, valueComparer, equateEmpty) {
+		t.Error(cmp.Diff(resp.Resources, want, typeComparer, valueComparer, equateEmpty))
+	}
+}
+
+func TestGRPCProvider_InvokeAction(t *testing.T) {
+	// Actions have their own schemas, so the provider schema isn't needed.
+	ctrl := gomock.NewController(t)
+	client := mockproto.NewMockProviderClient(ctrl)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().GetActionSchemas(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.GetActionSchemas_Response{
+		Actions: map[string]*proto.Schema{
+			"action": {
+				Block: &proto.Schema_Block{
+					Attributes: []*proto.Schema_Attribute{
+						{
+							Name:     "attr",
+							Type:     []byte(`"string"`),
+							Required: true,
+						},
+					},
+				},
+			},
+		},
+	}, nil)
+
+	result, err := msgpack.Marshal(cty.StringVal("done"), cty.DynamicPseudoType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.EXPECT().InvokeAction(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ interface{}, req *proto.InvokeAction_Request, _ ...interface{}) (*proto.InvokeAction_Response, error) {
+		if req.TypeName != "action" {
+			t.Errorf("wrong type name %q", req.TypeName)
+		}
+		if got, want := req.Config.Msgpack, []byte("\x81\xa4attr\xa3bar"); !bytes.Equal(got, want) {
+			t.Errorf("wrong config %q; want %q", got, want)
+		}
+		return &proto.InvokeAction_Response{
+			Result: &proto.DynamicValue{Msgpack: result},
+		}, nil
+	})
+
+	resp := p.InvokeAction(providers.InvokeActionRequest{
+		TypeName: "action",
+		Config: cty.ObjectVal(map[string]cty.Value{
+			"attr": cty.StringVal("bar"),
+		}),
+	})
+
+	checkDiags(t, resp.Diagnostics)
+	if want := cty.StringVal("done"); !resp.Result.RawEquals(want) {
+		t.Errorf("wrong result %#v; want %#v", resp.Result, want)
+	}
+}
+
+func TestGRPCProvider_CheckHealth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mockproto.NewMockProviderClient(ctrl)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().CheckHealth(
+		gomock.Any(),
+		gomock.Any(),
+	).Return(&proto.CheckHealth_Response{
+		Diagnostics: []*proto.Diagnostic{
+			{
+				Severity: proto.Diagnostic_ERROR,
+				Summary:  "Invalid credentials",
+			},
+		},
+	}, nil)
+
+	resp := p.CheckHealth(providers.CheckHealthRequest{})
+
+	if !resp.Diagnostics.HasErrors() {
+		t.Fatal("expected the check to fail")
+	}
+	if got, want := resp.Diagnostics[0].Description().Summary, "Invalid credentials"; got != want {
+		t.Errorf("wrong summary %q; want %q", got, want)
+	}
+}
+
 func TestGRPCProvider_ReadResource(t *testing.T) {
 	client := mockProviderClient(t)
 	p := &GRPCProvider{
@@ -466,6 +732,69 @@ func TestGRPCProvider_ReadResource(t *testing.T) {
 	}
 }
 
+func TestGRPCProvider_ReadResources(t *testing.T) {
+	client := mockProviderClient(t)
+	p := &GRPCProvider{
+		client: client,
+	}
+
+	client.EXPECT().ReadResources(
+		gomock.Any(),
+		gomock.Any(),
+	).DoAndReturn(func(_ interface{}, req *proto.ReadResources_Request, _ ...interface{}) (*proto.ReadResources_Response, error) {
+		if len(req.Requests) != 2 {
+			t.Fatalf("wrong number of requests %d", len(req.Requests))
+		}
+		return &proto.ReadResources_Response{
+			Responses: []*proto.ReadResource_Response{
+				{
+					NewState: &proto.DynamicValue{
+						Msgpack: []byte("\x81\xa4attr\xa3bar"),
+					},
+				},
+				{
+					Diagnostics: []*proto.Diagnostic{
+						{
+							Severity: proto.Diagnostic_ERROR,
+							Summary:  "not found",
+						},
+					},
+				},
+			},
+		}, nil
+	})
+
+	resp := p.ReadResources(providers.ReadResourcesRequest{
+		Requests: []providers.ReadResourceRequest{
+			{
+				TypeName: "resource",
+				PriorState: cty.ObjectVal(map[string]cty.Value{
+					"attr": cty.StringVal("foo"),
+				}),
+			},
+			{
+				TypeName: "resource",
+				PriorState: cty.ObjectVal(map[string]cty.Value{
+					"attr": cty.StringVal("baz"),
+				}),
+			},
+		},
+	})
+
+	checkDiags(t, resp.Diagnostics)
+	if len(resp.Responses) != 2 {
+		t.Fatalf("wrong number of responses %d", len(resp.Responses))
+	}
+
+	expected := cty.ObjectVal(map[string]cty.Value{
+		"attr": cty.StringVal("bar"),
+	})
+	if !cmp.Equal(expected, resp.Responses[0].NewState, typeComparer, valueComparer, equateEmpty) {
+		t.Fatal(cmp.Diff(expected, resp.Responses[0].NewState, typeComparer, valueComparer, equateEmpty))
+	}
+	checkDiagsHasError(t, resp.Responses[1].Diagnostics)
+}
+
 func TestGRPCProvider_ReadResourceJSON(t *testing.T) {
 	client := mockProviderClient(t)
 	p := &GRPCProvider{
@@ -0,0 +1,138 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultQuery is the document whose rules are evaluated if OPA.Query is
+// not set.
+const DefaultQuery = "data.tofu"
+
+// DefaultCommand is the OPA executable that is run if OPA.Command is not
+// set, which is looked up in the PATH.
+const DefaultCommand = "opa"
+
+// OPA is a Checker that evaluates Rego policies with the plan as their
+// input. The document selected by the query may define a "deny" rule and a
+// "warn" rule, each a set or array of messages, which become verdicts with
+// error and warning severity.
+type OPA struct {
+	// Paths are the policy and data files, or directories of them, that
+	// are loaded by OPA.
+	Paths []string
+
+	// Query is the reference to the document that defines the deny and
+	// warn rules.
+	Query string
+
+	// Command is the path of the OPA executable.
+	Command string
+}
+
+var _ Checker = (*OPA)(nil)
+
+// Check implements Checker.
+//
+// As well as when OPA fails, an error is returned if the query is
+// undefined, which usually means that the policy files don't define the
+// expected package.
+func (o *OPA) Check(ctx context.Context, req *Request) (*Result, error) {
+	command := o.Command
+	if command == "" {
+		command = DefaultCommand
+	}
+	query := o.Query
+	if query == "" {
+		query = DefaultQuery
+	}
+
+	args := []string{"eval", "--format", "json", "--stdin-input"}
+	for _, path := range o.Paths {
+		args = append(args, "--data", path)
+	}
+	args = append(args, query)
+
+	out, err := run(ctx, command, args, req.Plan)
+	if err != nil {
+		return nil, err
+	}
+	return decodeOPAResult(out, query)
+}
+
+// evalOutput is the relevant part of the JSON output of "opa eval".
+type evalOutput struct {
+	Result []struct {
+		Expressions []struct {
+			Value json.RawMessage `json:"value"`
+		} `json:"expressions"`
+	} `json:"result"`
+}
+
+func decodeOPAResult(raw []byte, query string) (*Result, error) {
+	var out evalOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, fmt.Errorf("invalid output from OPA: %w", err)
+	}
+	if len(out.Result) == 0 || len(out.Result[0].Expressions) == 0 {
+		return nil, fmt.Errorf("the query %s is undefined; check that the policies define that package", query)
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(out.Result[0].Expressions[0].Value, &doc); err != nil {
+		return nil, fmt.Errorf("the query %s must refer to a document with deny and warn rules", query)
+	}
+
+	deny, err := decodeMessages(doc["deny"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid deny rule in %s: %w", query, err)
+	}
+	warn, err := decodeMessages(doc["warn"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid warn rule in %s: %w", query, err)
+	}
+
+	ret := &Result{}
+	for _, msg := range deny {
+		ret.Verdicts = append(ret.Verdicts, Verdict{
+			Severity: SeverityError,
+			Summary:  "Plan denied by policy",
+			Detail:   msg,
+		})
+	}
+	for _, msg := range warn {
+		ret.Verdicts = append(ret.Verdicts, Verdict{
+			Severity: SeverityWarning,
+			Summary:  "Policy warning",
+			Detail:   msg,
+		})
+	}
+	return ret, nil
+}
+
+// decodeMessages decodes the value of a deny or warn rule. The messages are
+// usually strings, but other values are accepted and shown as JSON.
+func decodeMessages(raw json.RawMessage) ([]string, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+	var values []json.RawMessage
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("must be a set or array of messages")
+	}
+	ret := make([]string, 0, len(values))
+	for _, v := range values {
+		var msg string
+		if err := json.Unmarshal(v, &msg); err != nil {
+			msg = string(v)
+		}
+		ret = append(ret, msg)
+	}
+	return ret, nil
+}
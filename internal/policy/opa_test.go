@@ -0,0 +1,145 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package policy
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDecodeOPAResult(t *testing.T) {
+	tests := map[string]struct {
+		output  string
+		want    *Result
+		wantErr string
+	}{
+		"deny and warn": {
+			`{"result":[{"expressions":[{"value":{"deny":["no public buckets"],"warn":["missing tags",{"resource":"aws_instance.a"}]}}]}]}`,
+			&Result{
+				Verdicts: []Verdict{
+					{Severity: SeverityError, Summary: "Plan denied by policy", Detail: "no public buckets"},
+					{Severity: SeverityWarning, Summary: "Policy warning", Detail: "missing tags"},
+					{Severity: SeverityWarning, Summary: "Policy warning", Detail: `{"resource":"aws_instance.a"}`},
+				},
+			},
+			"",
+		},
+		"no rules": {
+			`{"result":[{"expressions":[{"value":{}}]}]}`,
+			&Result{},
+			"",
+		},
+		"undefined": {
+			`{}`,
+			nil,
+			"the query data.tofu is undefined",
+		},
+		"not a document": {
+			`{"result":[{"expressions":[{"value":true}]}]}`,
+			nil,
+			"must refer to a document with deny and warn rules",
+		},
+		"invalid deny": {
+			`{"result":[{"expressions":[{"value":{"deny":"nope"}}]}]}`,
+			nil,
+			"invalid deny rule in data.tofu",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := decodeOPAResult([]byte(test.output), "data.tofu")
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("wrong error\ngot:  %v\nwant: %s", err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if diff := cmp.Diff(test.want, got); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOPACheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of OPA")
+	}
+
+	// The script records its arguments and input, and then responds in the
+	// same way as OPA would.
+	dir := t.TempDir()
+	command := filepath.Join(dir, "opa")
+	script := `#!/bin/sh
+echo "$@" > "$0.args"
+cat > "$0.input"
+echo '{"result":[{"expressions":[{"value":{"deny":["denied"]}}]}]}'
+`
+	if err := os.WriteFile(command, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	opa := &OPA{
+		Paths:   []string{"policy", "data.json"},
+		Command: command,
+	}
+	got, err := opa.Check(context.Background(), &Request{Stage: StageApply, Plan: []byte(`{"format_version":"1.2"}`)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := &Result{
+		Verdicts: []Verdict{
+			{Severity: SeverityError, Summary: "Plan denied by policy", Detail: "denied"},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	args, err := os.ReadFile(command + ".args")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(string(args)), "eval --format json --stdin-input --data policy --data data.json data.tofu"; got != want {
+		t.Errorf("wrong arguments\ngot:  %s\nwant: %s", got, want)
+	}
+	input, err := os.ReadFile(command + ".input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(input), `{"format_version":"1.2"}`; got != want {
+		t.Errorf("wrong input\ngot:  %s\nwant: %s", got, want)
+	}
+}
+
+func TestOPACheck_failure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script in place of OPA")
+	}
+
+	dir := t.TempDir()
+	command := filepath.Join(dir, "opa")
+	script := "#!/bin/sh\necho 'rego_parse_error: unexpected eof token' >&2\nexit 1\n"
+	if err := os.WriteFile(command, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	opa := &OPA{Command: command}
+	_, err := opa.Check(context.Background(), &Request{Stage: StageApply, Plan: []byte(`{}`)})
+	if err == nil || !strings.Contains(err.Error(), "rego_parse_error") {
+		t.Fatalf("wrong error: %v", err)
+	}
+}
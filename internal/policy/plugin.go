@@ -0,0 +1,120 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ProtocolVersion is the version of the policy check protocol implemented
+// by Plugin.
+//
+// A policy check plugin is an executable that is run once for each plan
+// that is checked. It reads a single JSON object from stdin:
+//
+//	{
+//	  "protocol_version": 1,
+//	  "stage": "plan",
+//	  "plan": { ... }
+//	}
+//
+// The stage is either "plan" or "apply", and the plan is the JSON
+// representation of the plan produced by "tofu show -json". The plugin then
+// writes a single JSON object to stdout, and exits with status zero:
+//
+//	{
+//	  "verdicts": [
+//	    {
+//	      "severity": "error",
+//	      "summary": "Monthly cost exceeds budget",
+//	      "detail": "The planned changes add $420 per month.",
+//	      "address": "aws_instance.web"
+//	    }
+//	  ]
+//	}
+//
+// The severity is either "error" or "warning", and the summary is required.
+// If the plugin exits with a non-zero status, or its output doesn't follow
+// the protocol, the plan is treated as though it had been denied. Anything
+// the plugin writes to stderr is included in the error.
+const ProtocolVersion = 1
+
+// Plugin is a Checker that runs an external program that implements the
+// policy check protocol.
+type Plugin struct {
+	// Name identifies the plugin in errors.
+	Name string
+
+	Command string
+	Args    []string
+}
+
+var _ Checker = (*Plugin)(nil)
+
+type pluginRequest struct {
+	ProtocolVersion int             `json:"protocol_version"`
+	Stage           Stage           `json:"stage"`
+	Plan            json.RawMessage `json:"plan"`
+}
+
+type pluginResponse struct {
+	Verdicts []struct {
+		Severity Severity `json:"severity"`
+		Summary  string   `json:"summary"`
+		Detail   string   `json:"detail"`
+		Address  string   `json:"address"`
+	} `json:"verdicts"`
+}
+
+// Check implements Checker.
+func (p *Plugin) Check(ctx context.Context, req *Request) (*Result, error) {
+	input, err := json.Marshal(pluginRequest{
+		ProtocolVersion: ProtocolVersion,
+		Stage:           req.Stage,
+		Plan:            req.Plan,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("policy check %q: %w", p.Name, err)
+	}
+
+	out, err := run(ctx, p.Command, p.Args, input)
+	if err != nil {
+		return nil, fmt.Errorf("policy check %q: %w", p.Name, err)
+	}
+	ret, err := decodePluginResult(out)
+	if err != nil {
+		return nil, fmt.Errorf("policy check %q: %w", p.Name, err)
+	}
+	return ret, nil
+}
+
+func decodePluginResult(raw []byte) (*Result, error) {
+	var resp pluginResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+
+	ret := &Result{}
+	for i, v := range resp.Verdicts {
+		switch v.Severity {
+		case SeverityError, SeverityWarning:
+		default:
+			return nil, fmt.Errorf("invalid response: verdict %d has unsupported severity %q", i, v.Severity)
+		}
+		if v.Summary == "" {
+			return nil, fmt.Errorf("invalid response: verdict %d has no summary", i)
+		}
+		ret.Verdicts = append(ret.Verdicts, Verdict{
+			Severity: v.Severity,
+			Summary:  v.Summary,
+			Detail:   v.Detail,
+			Address:  v.Address,
+		})
+	}
+	return ret, nil
+}
@@ -0,0 +1,141 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package policy
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPluginCheck(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the plugin")
+	}
+
+	// The script records its arguments and request, and then responds with
+	// a fixed verdict.
+	dir := t.TempDir()
+	command := filepath.Join(dir, "cost-gate")
+	script := `#!/bin/sh
+echo "$@" > "$0.args"
+cat > "$0.input"
+echo '{"verdicts":[{"severity":"error","summary":"Over budget","detail":"Adds $420 per month.","address":"aws_instance.web"}]}'
+`
+	if err := os.WriteFile(command, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	plugin := &Plugin{
+		Name:    "cost",
+		Command: command,
+		Args:    []string{"--max", "100"},
+	}
+	got, err := plugin.Check(context.Background(), &Request{Stage: StagePlan, Plan: []byte(`{"format_version":"1.2"}`)})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	want := &Result{
+		Verdicts: []Verdict{
+			{
+				Severity: SeverityError,
+				Summary:  "Over budget",
+				Detail:   "Adds $420 per month.",
+				Address:  "aws_instance.web",
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+
+	args, err := os.ReadFile(command + ".args")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := strings.TrimSpace(string(args)), "--max 100"; got != want {
+		t.Errorf("wrong arguments\ngot:  %s\nwant: %s", got, want)
+	}
+	input, err := os.ReadFile(command + ".input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotReq, wantReq interface{}
+	if err := json.Unmarshal(input, &gotReq); err != nil {
+		t.Fatalf("invalid request: %s", err)
+	}
+	json.Unmarshal([]byte(`{"protocol_version":1,"stage":"plan","plan":{"format_version":"1.2"}}`), &wantReq)
+	if diff := cmp.Diff(wantReq, gotReq); diff != "" {
+		t.Errorf("wrong request\n%s", diff)
+	}
+}
+
+func TestPluginCheck_failure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the plugin")
+	}
+
+	dir := t.TempDir()
+	command := filepath.Join(dir, "cost-gate")
+	script := "#!/bin/sh\necho 'pricing API unavailable' >&2\nexit 2\n"
+	if err := os.WriteFile(command, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	plugin := &Plugin{Name: "cost", Command: command}
+	_, err := plugin.Check(context.Background(), &Request{Stage: StageApply, Plan: []byte(`{}`)})
+	if err == nil {
+		t.Fatal("succeeded; want error")
+	}
+	if got := err.Error(); !strings.Contains(got, `policy check "cost"`) || !strings.Contains(got, "pricing API unavailable") {
+		t.Fatalf("wrong error: %s", got)
+	}
+}
+
+func TestDecodePluginResult(t *testing.T) {
+	tests := map[string]struct {
+		output  string
+		wantErr string
+	}{
+		"no verdicts": {
+			`{"verdicts":[]}`,
+			"",
+		},
+		"not JSON": {
+			`ok`,
+			"invalid response",
+		},
+		"bad severity": {
+			`{"verdicts":[{"severity":"fatal","summary":"x"}]}`,
+			`verdict 0 has unsupported severity "fatal"`,
+		},
+		"no summary": {
+			`{"verdicts":[{"severity":"warning","summary":"x"},{"severity":"warning"}]}`,
+			"verdict 1 has no summary",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := decodePluginResult([]byte(test.output))
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("wrong error\ngot:  %v\nwant: %s", err, test.wantErr)
+			}
+		})
+	}
+}
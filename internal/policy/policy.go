@@ -0,0 +1,129 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package policy checks plans against an organization's policies before
+// they are applied.
+//
+// Each Checker is given the JSON representation of the plan, as produced by
+// "tofu show -json", and returns verdicts about it. A verdict with error
+// severity prevents the plan from being applied, while warnings are only
+// reported. OPA evaluates Rego policies using the Open Policy Agent
+// executable, and Plugin runs any other program that implements the
+// protocol described in plugin.go.
+package policy
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// Checker checks a plan against some policies.
+//
+// An error is returned if the policies can't be checked at all. Callers
+// should treat errors in the same way as a verdict with error severity, so
+// that a plan is never applied without having been checked.
+type Checker interface {
+	Check(ctx context.Context, req *Request) (*Result, error)
+}
+
+// Stage is the operation that a plan is being checked for.
+type Stage string
+
+const (
+	// StagePlan is the creation of a plan by "tofu plan", which can be
+	// saved and applied later.
+	StagePlan Stage = "plan"
+
+	// StageApply is the application of a plan by "tofu apply", which is
+	// checked once more before any changes are made.
+	StageApply Stage = "apply"
+)
+
+// Request is what a Checker checks.
+type Request struct {
+	Stage Stage
+
+	// Plan is the JSON representation of the plan, including the prior
+	// state and the configuration.
+	Plan []byte
+}
+
+// Severity is how a Verdict affects the plan.
+type Severity string
+
+const (
+	// SeverityError prevents the plan from being applied.
+	SeverityError Severity = "error"
+
+	// SeverityWarning is only reported.
+	SeverityWarning Severity = "warning"
+)
+
+// Verdict is a single finding about a plan.
+type Verdict struct {
+	Severity Severity
+	Summary  string
+	Detail   string
+
+	// Address is the address of the resource instance or other object that
+	// the verdict is about, if any.
+	Address string
+}
+
+// Result is the outcome of checking a plan.
+type Result struct {
+	Verdicts []Verdict
+}
+
+// Diagnostics returns a diagnostic of the matching severity for each
+// verdict.
+func (r *Result) Diagnostics() tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	for _, v := range r.Verdicts {
+		severity := tfdiags.Warning
+		if v.Severity == SeverityError {
+			severity = tfdiags.Error
+		}
+		detail := v.Detail
+		if v.Address != "" {
+			if detail != "" {
+				detail = v.Address + ": " + detail
+			} else {
+				detail = "Reported for " + v.Address + "."
+			}
+		}
+		diags = diags.Append(tfdiags.Sourceless(severity, v.Summary, detail))
+	}
+	return diags
+}
+
+// run runs the given command with the given input, returning its output. If
+// it fails then the error includes whatever it wrote to stderr, or failing
+// that to stdout.
+func run(ctx context.Context, command string, args []string, input []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			// OPA reports errors in the policies on stdout when using the
+			// JSON format.
+			msg = strings.TrimSpace(stdout.String())
+		}
+		if msg != "" {
+			return nil, fmt.Errorf("%s: %w\n%s", command, err, msg)
+		}
+		return nil, fmt.Errorf("%s: %w", command, err)
+	}
+	return stdout.Bytes(), nil
+}
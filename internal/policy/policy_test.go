@@ -0,0 +1,43 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package policy
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+func TestResultDiagnostics(t *testing.T) {
+	result := &Result{
+		Verdicts: []Verdict{
+			{Severity: SeverityError, Summary: "Plan denied by policy", Detail: "no public buckets"},
+			{Severity: SeverityWarning, Summary: "Missing tags", Address: "aws_instance.a"},
+			{Severity: SeverityWarning, Summary: "Over budget", Detail: "Adds $10 per month.", Address: "aws_instance.b"},
+		},
+	}
+	diags := result.Diagnostics()
+
+	type diag struct {
+		Severity        tfdiags.Severity
+		Summary, Detail string
+	}
+	var got []diag
+	for _, d := range diags {
+		desc := d.Description()
+		got = append(got, diag{d.Severity(), desc.Summary, desc.Detail})
+	}
+	want := []diag{
+		{tfdiags.Error, "Plan denied by policy", "no public buckets"},
+		{tfdiags.Warning, "Missing tags", "Reported for aws_instance.a."},
+		{tfdiags.Warning, "Over budget", "aws_instance.b: Adds $10 per month."},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong diagnostics\n%s", diff)
+	}
+}
@@ -7,6 +7,9 @@ package providercache
 
 import (
 	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -146,3 +149,66 @@ func TestLinkFromOtherCache(t *testing.T) {
 		t.Errorf("wrong cache contents after link\n%s", diff)
 	}
 }
+
+func TestInstallPackage_httpProgress(t *testing.T) {
+	tmpDirPath, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const archivePath = "testdata/provider-null_2.1.0_linux_amd64.zip"
+	archive, err := os.ReadFile(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		http.ServeFile(resp, req, archivePath)
+	}))
+	defer server.Close()
+
+	linuxPlatform := getproviders.Platform{
+		OS:   "linux",
+		Arch: "amd64",
+	}
+	nullProvider := addrs.NewProvider(
+		addrs.DefaultProviderRegistryHost, "hashicorp", "null",
+	)
+
+	tmpDir := NewDirWithPlatform(tmpDirPath, linuxPlatform)
+
+	meta := getproviders.PackageMeta{
+		Provider: nullProvider,
+		Version:  versions.MustParseVersion("2.1.0"),
+
+		ProtocolVersions: getproviders.VersionList{versions.MustParseVersion("5.0.0")},
+		TargetPlatform:   linuxPlatform,
+
+		Filename: "provider-null_2.1.0_linux_amd64.zip",
+		Location: getproviders.PackageHTTPURL(server.URL + "/provider-null_2.1.0_linux_amd64.zip"),
+	}
+
+	var received, total int64
+	calls := 0
+	evts := &InstallerEvents{
+		FetchPackageProgress: func(provider addrs.Provider, version getproviders.Version, gotReceived, gotTotal int64) {
+			if provider != nullProvider || version != meta.Version {
+				t.Errorf("progress reported for wrong package %s v%s", provider, version)
+			}
+			if gotReceived < received {
+				t.Errorf("received bytes went backwards from %d to %d", received, gotReceived)
+			}
+			received, total = gotReceived, gotTotal
+			calls++
+		},
+	}
+
+	if _, err := tmpDir.InstallPackage(evts.OnContext(context.Background()), meta, nil); err != nil {
+		t.Fatalf("InstallPackage failed: %s", err)
+	}
+	if calls == 0 {
+		t.Fatal("FetchPackageProgress was not called")
+	}
+	if want := int64(len(archive)); received != want || total != want {
+		t.Errorf("wrong final progress %d of %d; want %d of %d", received, total, want, want)
+	}
+}
@@ -111,6 +111,14 @@ type InstallerEvents struct {
 	FetchPackageSuccess func(provider addrs.Provider, version getproviders.Version, localDir string, authResult *getproviders.PackageAuthenticationResult)
 	FetchPackageFailure func(provider addrs.Provider, version getproviders.Version, err error)
 
+	// FetchPackageProgress is called repeatedly while a package is being
+	// downloaded from a network location, between FetchPackageBegin and
+	// FetchPackageSuccess or FetchPackageFailure, with the number of bytes
+	// received so far. total is the size of the package, or -1 if the size
+	// isn't known in advance. It is not called for packages that are
+	// installed from a local directory or archive.
+	FetchPackageProgress func(provider addrs.Provider, version getproviders.Version, received, total int64)
+
 	// The ProvidersLockUpdated event is called whenever the lock file will be
 	// updated. It provides the following information:
 	//
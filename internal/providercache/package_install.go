@@ -8,6 +8,7 @@ package providercache
 import (
 	"context"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -63,6 +64,23 @@ func requestLogHook(logger retryablehttp.Logger, req *http.Request, i int) {
 	}
 }
 
+// progressReader is an io.Reader that reports the total number of bytes read
+// so far after each read from the underlying reader.
+type progressReader struct {
+	r        io.Reader
+	received int64
+	report   func(received int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.received += int64(n)
+		r.report(r.received)
+	}
+	return n, err
+}
+
 func installFromHTTPURL(ctx context.Context, meta getproviders.PackageMeta, targetDir string, allowedHashes []getproviders.Hash) (*getproviders.PackageAuthenticationResult, error) {
 	url := meta.Location.String()
 
@@ -106,9 +124,19 @@ func installFromHTTPURL(ctx context.Context, meta getproviders.PackageMeta, targ
 	defer f.Close()
 	defer os.Remove(f.Name())
 
+	var body io.Reader = resp.Body
+	if cb := installerEventsForContext(ctx).FetchPackageProgress; cb != nil {
+		body = &progressReader{
+			r: resp.Body,
+			report: func(received int64) {
+				cb(meta.Provider, meta.Version, received, resp.ContentLength)
+			},
+		}
+	}
+
 	// We'll borrow go-getter's "cancelable copy" implementation here so that
 	// the download can potentially be interrupted partway through.
-	n, err := getter.Copy(ctx, f, resp.Body)
+	n, err := getter.Copy(ctx, f, body)
 	if err == nil && n < resp.ContentLength {
 		err = fmt.Errorf("incorrect response size: expected %d bytes, but got %d bytes", resp.ContentLength, n)
 	}
@@ -0,0 +1,63 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package providers
+
+import (
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ActionInvoker is an optional interface that a provider implementation can
+// implement in addition to Interface to signal that it offers actions:
+// imperative operations on existing infrastructure, such as rotating a key
+// or rebooting an instance, which don't correspond to a change of any
+// resource's configuration.
+//
+// Actions are invoked with "tofu invoke", or by invoke blocks in the run
+// blocks of test files.
+//
+// Provider plugins implement it with the GetActionSchemas and InvokeAction
+// RPCs, if they announce the actions server capability. Use AsActionInvoker
+// to check whether a provider supports it.
+type ActionInvoker interface {
+	// GetActionSchemas returns the schemas of the arguments of the actions
+	// that the provider offers, by action type name. Like resource types,
+	// the names of action types start with the provider's type name.
+	GetActionSchemas() GetActionSchemasResponse
+
+	// InvokeAction runs an action. The provider must be configured before
+	// this is called.
+	InvokeAction(InvokeActionRequest) InvokeActionResponse
+}
+
+type GetActionSchemasResponse struct {
+	// Actions maps the name of each action type to the schema of its
+	// arguments.
+	Actions map[string]Schema
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
+type InvokeActionRequest struct {
+	// TypeName is the name of the action type to invoke.
+	TypeName string
+
+	// Config is the value of the arguments, conforming to the schema of the
+	// action type. It is always wholly known.
+	Config cty.Value
+}
+
+type InvokeActionResponse struct {
+	// Result is an optional value describing the outcome of the action,
+	// such as the identifier of a new key, which is shown to the user. It
+	// is cty.NilVal if the action has no result.
+	Result cty.Value
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
@@ -0,0 +1,47 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package providers
+
+import (
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// BulkResourceReader is an optional interface that a provider implementation
+// can implement in addition to Interface to signal that it's able to read
+// the current state of many remote objects in a single call.
+//
+// When a provider implements this interface, OpenTofu may group the
+// ReadResource calls it would otherwise make while refreshing and send them
+// together using ReadResources instead. Providers that don't implement it
+// continue to receive individual ReadResource calls. Provider plugins
+// implement it with the ReadResources RPC, if they announce the
+// read_resources server capability.
+//
+// Use AsBulkResourceReader rather than a type assertion to check whether a
+// provider supports it.
+type BulkResourceReader interface {
+	// ReadResources refreshes the state of multiple resource instances at
+	// once. The response must contain exactly one element in Responses for
+	// each element of the request's Requests, in the same order.
+	ReadResources(ReadResourcesRequest) ReadResourcesResponse
+}
+
+type ReadResourcesRequest struct {
+	// Requests are the individual read requests to process, which may be for
+	// a mixture of different resource types belonging to the same provider.
+	Requests []ReadResourceRequest
+}
+
+type ReadResourcesResponse struct {
+	// Responses contains the result of each of the requested reads, in the
+	// same order as the corresponding requests.
+	Responses []ReadResourceResponse
+
+	// Diagnostics contains any warnings or errors that apply to the bulk
+	// call as a whole, rather than to any specific request. If this contains
+	// errors then the individual responses are ignored.
+	Diagnostics tfdiags.Diagnostics
+}
@@ -0,0 +1,39 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package providers
+
+import (
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// HealthChecker is an optional interface that a provider implementation can
+// implement in addition to Interface to signal that it can check, cheaply,
+// whether its configuration works, for example by checking that its
+// credentials are accepted by the remote API it manages.
+//
+// It's used by "tofu providers check" to report misconfigured providers
+// before they are used to plan. Providers that don't implement it are only
+// checked by configuring them.
+//
+// Provider plugins implement it with the CheckHealth RPC, if they announce
+// the check_health server capability. Use AsHealthChecker to check whether a
+// provider supports it.
+type HealthChecker interface {
+	// CheckHealth checks that the provider's configuration works, without
+	// changing any remote objects. The provider must be configured before
+	// this is called.
+	CheckHealth(CheckHealthRequest) CheckHealthResponse
+}
+
+type CheckHealthRequest struct{}
+
+type CheckHealthResponse struct {
+	// Diagnostics contains any warnings or errors from the method call. Any
+	// errors mean that the check failed, and should describe what's wrong
+	// with the configuration, such as invalid credentials or an unreachable
+	// endpoint.
+	Diagnostics tfdiags.Diagnostics
+}
@@ -0,0 +1,92 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package providers
+
+// CapabilityAnnouncer is implemented by provider implementations that
+// implement every optional interface in this package, but support only the
+// ones they announce in their server capabilities. This is the case for the
+// clients of provider plugins, where each optional interface corresponds to
+// an RPC that older plugins don't implement.
+type CapabilityAnnouncer interface {
+	// AnnouncedCapabilities returns the server capabilities from the
+	// provider's schema.
+	AnnouncedCapabilities() ServerCapabilities
+}
+
+// Wrapper is implemented by provider implementations that delegate to
+// another provider, such as one that manages the lifetime of a plugin
+// process. The AsX functions in this package look through wrappers to find
+// the optional interfaces of the underlying provider.
+type Wrapper interface {
+	// UnwrapProvider returns the provider that calls are delegated to, or
+	// nil if it isn't available.
+	UnwrapProvider() Interface
+}
+
+// AsBulkResourceReader returns the given provider as a BulkResourceReader, if
+// it supports bulk reads.
+func AsBulkResourceReader(p Interface) (BulkResourceReader, bool) {
+	return asOptional[BulkResourceReader](p, func(c ServerCapabilities) bool { return c.ReadResources })
+}
+
+// AsResourcePriorityHinter returns the given provider as a
+// ResourcePriorityHinter, if it suggests priorities for its resource types.
+func AsResourcePriorityHinter(p Interface) (ResourcePriorityHinter, bool) {
+	return asOptional[ResourcePriorityHinter](p, func(c ServerCapabilities) bool { return c.ResourcePriorities })
+}
+
+// AsResourceFinder returns the given provider as a ResourceFinder, if it can
+// search for existing remote objects.
+func AsResourceFinder(p Interface) (ResourceFinder, bool) {
+	return asOptional[ResourceFinder](p, func(c ServerCapabilities) bool { return c.FindResource })
+}
+
+// AsResourceReadinessChecker returns the given provider as a
+// ResourceReadinessChecker, if it can report whether new objects are ready.
+func AsResourceReadinessChecker(p Interface) (ResourceReadinessChecker, bool) {
+	return asOptional[ResourceReadinessChecker](p, func(c ServerCapabilities) bool { return c.CheckResourceReadiness })
+}
+
+// AsResourceLister returns the given provider as a ResourceLister, if it can
+// list the existing remote objects of its resource types.
+func AsResourceLister(p Interface) (ResourceLister, bool) {
+	return asOptional[ResourceLister](p, func(c ServerCapabilities) bool { return c.ListResources })
+}
+
+// AsActionInvoker returns the given provider as an ActionInvoker, if it
+// offers actions.
+func AsActionInvoker(p Interface) (ActionInvoker, bool) {
+	return asOptional[ActionInvoker](p, func(c ServerCapabilities) bool { return c.Actions })
+}
+
+// AsHealthChecker returns the given provider as a HealthChecker, if it can
+// check whether its configuration works.
+func AsHealthChecker(p Interface) (HealthChecker, bool) {
+	return asOptional[HealthChecker](p, func(c ServerCapabilities) bool { return c.CheckHealth })
+}
+
+// asOptional returns the given provider, or the provider it wraps, as the
+// optional interface T, if it implements that interface and supported returns
+// true for the capabilities it announces.
+func asOptional[T any](p Interface, supported func(ServerCapabilities) bool) (T, bool) {
+	var zero T
+	for p != nil {
+		ret, ok := p.(T)
+		if !ok {
+			wrapper, ok := p.(Wrapper)
+			if !ok {
+				return zero, false
+			}
+			p = wrapper.UnwrapProvider()
+			continue
+		}
+		if announcer, ok := p.(CapabilityAnnouncer); ok && !supported(announcer.AnnouncedCapabilities()) {
+			return zero, false
+		}
+		return ret, true
+	}
+	return zero, false
+}
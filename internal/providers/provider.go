@@ -143,6 +143,29 @@ type ServerCapabilities struct {
 	// In other words, the providers for which GetProviderSchemaOptional is false
 	// require their schema to be read after EVERY instantiation to function normally.
 	GetProviderSchemaOptional bool
+
+	// ReadResources signals that this provider implements BulkResourceReader.
+	ReadResources bool
+
+	// ResourcePriorities signals that this provider implements
+	// ResourcePriorityHinter.
+	ResourcePriorities bool
+
+	// FindResource signals that this provider implements ResourceFinder.
+	FindResource bool
+
+	// CheckResourceReadiness signals that this provider implements
+	// ResourceReadinessChecker.
+	CheckResourceReadiness bool
+
+	// ListResources signals that this provider implements ResourceLister.
+	ListResources bool
+
+	// Actions signals that this provider implements ActionInvoker.
+	Actions bool
+
+	// CheckHealth signals that this provider implements HealthChecker.
+	CheckHealth bool
 }
 
 type FunctionSpec struct {
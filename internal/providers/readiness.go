@@ -0,0 +1,49 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package providers
+
+import (
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ResourceReadinessChecker is an optional interface that a provider
+// implementation can implement in addition to Interface to report whether a
+// newly-created remote object is ready to take over from the object it
+// replaces.
+//
+// OpenTofu uses this for resources with two_phase_replace set in their
+// lifecycle block: after creating the replacement object, it waits until the
+// provider reports that the object is ready before asking for confirmation
+// to destroy the previous object.
+//
+// Provider plugins implement it with the CheckResourceReadiness RPC, if they
+// announce the check_resource_readiness server capability. Use
+// AsResourceReadinessChecker to check whether a provider supports it.
+type ResourceReadinessChecker interface {
+	// CheckResourceReadiness reports whether the given object is ready.
+	// OpenTofu calls it repeatedly until it returns Ready or an error.
+	CheckResourceReadiness(CheckResourceReadinessRequest) CheckResourceReadinessResponse
+}
+
+type CheckResourceReadinessRequest struct {
+	// TypeName is the name of the resource type of the object.
+	TypeName string
+
+	// State is the current state of the object, as returned when it was
+	// created.
+	State cty.Value
+}
+
+type CheckResourceReadinessResponse struct {
+	// Ready is true if the object is ready to be used.
+	Ready bool
+
+	// Diagnostics contains any warnings or errors from the method call. An
+	// error means that the object will never become ready.
+	Diagnostics tfdiags.Diagnostics
+}
@@ -0,0 +1,51 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package providers
+
+import (
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ResourceFinder is an optional interface that a provider implementation can
+// implement in addition to Interface to signal that it's able to search for
+// an existing remote object matching a resource configuration.
+//
+// OpenTofu uses this when planning with the option to adopt existing
+// objects: if a resource instance has no object in the state but the
+// provider finds a matching remote object, OpenTofu plans to import that
+// object instead of planning to create a new one.
+//
+// Provider plugins implement it with the FindResource RPC, if they announce
+// the find_resource server capability. Use AsResourceFinder to check whether
+// a provider supports it.
+type ResourceFinder interface {
+	// FindResource searches for a single existing remote object that matches
+	// the given configuration, using whatever heuristic is appropriate for
+	// the resource type, such as a unique name or a set of identifying tags.
+	FindResource(FindResourceRequest) FindResourceResponse
+}
+
+type FindResourceRequest struct {
+	// TypeName is the name of the resource type to search for.
+	TypeName string
+
+	// Config is the configuration value for the resource instance, which
+	// may contain unknown values.
+	Config cty.Value
+}
+
+type FindResourceResponse struct {
+	// ID is the import ID of the matching object, suitable for use with
+	// ImportResourceState, or the empty string if no matching object exists.
+	ID string
+
+	// Diagnostics contains any warnings or errors from the method call. In
+	// particular, providers should return an error if more than one object
+	// matches, rather than choosing one arbitrarily.
+	Diagnostics tfdiags.Diagnostics
+}
@@ -0,0 +1,66 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package providers
+
+import (
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ResourceLister is an optional interface that a provider implementation can
+// implement in addition to Interface to signal that it's able to list the
+// existing remote objects of a resource type.
+//
+// OpenTofu uses this in "tofu import discover" to generate import blocks and
+// configuration for existing infrastructure that it doesn't yet manage.
+//
+// Provider plugins implement it with the ListResources RPC, if they announce
+// the list_resources server capability. Use AsResourceLister to check
+// whether a provider supports it.
+type ResourceLister interface {
+	// ListResources returns the existing remote objects of the requested
+	// resource type that match the given filters. The provider must be
+	// configured before this is called.
+	ListResources(ListResourcesRequest) ListResourcesResponse
+}
+
+type ListResourcesRequest struct {
+	// TypeName is the name of the resource type to list.
+	TypeName string
+
+	// Filters narrow down the objects to list, with a meaning that depends
+	// on the provider and the resource type, such as tags that the objects
+	// must have. Providers should return an error for filters that they
+	// don't support rather than ignoring them.
+	Filters map[string]string
+}
+
+type ListResourcesResponse struct {
+	// Resources are the matching remote objects.
+	Resources []ListedResource
+
+	// Diagnostics contains any warnings or errors from the method call.
+	Diagnostics tfdiags.Diagnostics
+}
+
+// ListedResource is a remote object returned by ResourceLister.
+type ListedResource struct {
+	// ID is the import ID of the object, suitable for use with
+	// ImportResourceState.
+	ID string
+
+	// Name is a suggested name for the resource in the configuration, such
+	// as the name or a name tag of the object. It may be empty, and need not
+	// be a valid identifier.
+	Name string
+
+	// State is the current state of the object, conforming to the schema of
+	// the resource type, which is used to generate its configuration. It may
+	// be cty.NilVal if the provider can't cheaply read the objects it lists,
+	// in which case only a skeleton of the configuration is generated.
+	State cty.Value
+}
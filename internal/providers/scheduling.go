@@ -0,0 +1,26 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package providers
+
+// ResourcePriorityHinter is an optional interface that a provider
+// implementation can implement in addition to Interface to suggest default
+// scheduling priorities for its resource types.
+//
+// OpenTofu starts work on resource instances with a higher priority before
+// those with a lower priority whenever more instances are ready than the
+// walk parallelism allows. Providers can use this to ask for resource types
+// whose operations are known to take a long time, such as databases or
+// clusters, to be started as early as possible. A "priority" argument in a
+// resource's lifecycle block overrides the provider's default.
+//
+// Provider plugins implement it with the GetResourcePriorities RPC, if they
+// announce the resource_priorities server capability. Use
+// AsResourcePriorityHinter to check whether a provider supports it.
+type ResourcePriorityHinter interface {
+	// ResourcePriorities returns the default priority for each resource type
+	// that should have one. Resource types not included have priority zero.
+	ResourcePriorities() map[string]int
+}
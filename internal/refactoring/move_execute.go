@@ -229,6 +229,23 @@ func buildMoveStatementGraph(stmts []MoveStatement) *dag.AcyclicGraph {
 // statementDependsOn returns true if statement a depends on statement b;
 // i.e. statement b must be executed before statement a.
 func statementDependsOn(a, b *MoveStatement) bool {
+	if statementChainsOrNests(a, b) {
+		return true
+	}
+
+	// If statements declared in different module packages both move the
+	// same object then the one from the outer package runs first, so that it
+	// takes precedence and the other finds nothing left to move. This lets
+	// the consumer of a module override a move its author declared, such as
+	// when a new version of the module reorganizes objects that the consumer
+	// has already moved elsewhere. We skip this if the statements are already
+	// ordered the other way, to avoid introducing a cycle.
+	return a.From.CanChainFrom(b.From) && b.inOuterPackage(a) && !statementChainsOrNests(b, a)
+}
+
+// statementChainsOrNests returns true if statement a must be executed after
+// statement b because a chains from b or either is nested within the other.
+func statementChainsOrNests(a, b *MoveStatement) bool {
 	// chain-able moves are simple, as on the destination of one move could be
 	// equal to the source of another.
 	if a.From.CanChainFrom(b.To) {
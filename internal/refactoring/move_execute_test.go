@@ -332,6 +332,67 @@ func TestApplyMoves(t *testing.T) {
 			},
 		},
 
+		"chained moves declared in different module packages": {
+			[]MoveStatement{
+				// The statements are deliberately out of order, because the
+				// statement graph must decide the order in which they apply.
+				testMoveStatement(t, "shim.inner", "foo.from", "foo.to"),
+				testMoveStatement(t, "shim", "foo.from", "module.inner.foo.from"),
+				testMoveStatement(t, "", "module.orig", "module.shim"),
+			},
+			states.BuildState(func(s *states.SyncState) {
+				s.SetResourceInstanceCurrent(
+					mustParseInstAddr("module.orig.foo.from"),
+					&states.ResourceInstanceObjectSrc{
+						Status:    states.ObjectReady,
+						AttrsJSON: []byte(`{}`),
+					},
+					providerAddr,
+				)
+			}),
+			MoveResults{
+				Changes: addrs.MakeMap(
+					addrs.MakeMapElem(mustParseInstAddr("module.shim.module.inner.foo.to"), MoveSuccess{
+						From: mustParseInstAddr("module.orig.foo.from"),
+						To:   mustParseInstAddr("module.shim.module.inner.foo.to"),
+					}),
+				),
+				Blocked: emptyResults.Blocked,
+			},
+			[]string{
+				`module.shim.module.inner.foo.to`,
+			},
+		},
+		"moves of the same object declared in different module packages": {
+			[]MoveStatement{
+				// The statement from the outer package takes precedence
+				// regardless of the order the statements are declared in.
+				testPackageMoveStatement(t, "pkg", "pkg", "foo.old", "foo.new"),
+				testMoveStatement(t, "", "module.pkg.foo.old", "foo.consumer"),
+			},
+			states.BuildState(func(s *states.SyncState) {
+				s.SetResourceInstanceCurrent(
+					mustParseInstAddr("module.pkg.foo.old"),
+					&states.ResourceInstanceObjectSrc{
+						Status:    states.ObjectReady,
+						AttrsJSON: []byte(`{}`),
+					},
+					providerAddr,
+				)
+			}),
+			MoveResults{
+				Changes: addrs.MakeMap(
+					addrs.MakeMapElem(mustParseInstAddr("foo.consumer"), MoveSuccess{
+						From: mustParseInstAddr("module.pkg.foo.old"),
+						To:   mustParseInstAddr("foo.consumer"),
+					}),
+				),
+				Blocked: emptyResults.Blocked,
+			},
+			[]string{
+				`foo.consumer`,
+			},
+		},
 		"move module instance to already-existing module instance": {
 			[]MoveStatement{
 				testMoveStatement(t, "", "module.bar[0]", "module.boo"),
@@ -693,3 +754,10 @@ func allResourceInstanceAddrsInState(state *states.State) []string {
 	sort.Strings(ret)
 	return ret
 }
+
+func testPackageMoveStatement(t *testing.T, pkg string, module string, from string, to string) MoveStatement {
+	t.Helper()
+	ret := testMoveStatement(t, module, from, to)
+	ret.Package = addrs.Module(strings.Split(pkg, "."))
+	return ret
+}
@@ -30,16 +30,27 @@ type MoveStatement struct {
 	// user, e.g. in an error message, without clearly mentioning that it's
 	// related to an implied move statement.
 	Implied bool
+
+	// Package is the path of the module where the module package containing
+	// the statement begins. It is the root module for statements declared in
+	// the root module or in any local child module of it, and the path of the
+	// module call that installed the package for statements declared in a
+	// module from a registry or other remote source.
+	//
+	// When statements declared in different packages move the same object,
+	// the one declared in the outer package takes precedence, so that the
+	// consumer of a module can override a move declared by its author.
+	Package addrs.Module
 }
 
 // FindMoveStatements recurses through the modules of the given configuration
 // and returns a flat set of all "moved" blocks defined within, in a
 // deterministic but undefined order.
 func FindMoveStatements(rootCfg *configs.Config) []MoveStatement {
-	return findMoveStatements(rootCfg, nil)
+	return findMoveStatements(rootCfg, addrs.RootModule, nil)
 }
 
-func findMoveStatements(cfg *configs.Config, into []MoveStatement) []MoveStatement {
+func findMoveStatements(cfg *configs.Config, pkg addrs.Module, into []MoveStatement) []MoveStatement {
 	modAddr := cfg.Path
 	for _, mc := range cfg.Module.Moved {
 		fromAddr, toAddr := addrs.UnifyMoveEndpoints(modAddr, mc.From, mc.To)
@@ -54,11 +65,16 @@ func findMoveStatements(cfg *configs.Config, into []MoveStatement) []MoveStateme
 			To:        toAddr,
 			DeclRange: tfdiags.SourceRangeFromHCL(mc.DeclRange),
 			Implied:   false,
+			Package:   pkg,
 		})
 	}
 
 	for _, childCfg := range cfg.Children {
-		into = findMoveStatements(childCfg, into)
+		childPkg := pkg
+		if childCfg.EntersNewPackage() {
+			childPkg = childCfg.Path
+		}
+		into = findMoveStatements(childCfg, childPkg, into)
 	}
 
 	return into
@@ -172,6 +188,12 @@ func (s *MoveStatement) Name() string {
 	return fmt.Sprintf("%s->%s", s.From, s.To)
 }
 
+// inOuterPackage returns true if the receiver was declared in a module package
+// that contains the package where the other given statement was declared.
+func (s *MoveStatement) inOuterPackage(other *MoveStatement) bool {
+	return !s.Package.Equal(other.Package) && s.Package.TargetContains(other.Package)
+}
+
 func haveMoveStatementForResource(addr addrs.AbsResource, stmts []MoveStatement) bool {
 	// This is not a particularly optimal way to answer this question,
 	// particularly since our caller calls this function in a loop already,
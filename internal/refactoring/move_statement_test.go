@@ -193,3 +193,21 @@ func TestImpliedMoveStatements(t *testing.T) {
 		t.Errorf("wrong result\n%s", diff)
 	}
 }
+
+func TestFindMoveStatements_packages(t *testing.T) {
+	rootCfg, _ := loadRefactoringFixture(t, "testdata/move-statement-packages")
+
+	got := make(map[string]string)
+	for _, stmt := range FindMoveStatements(rootCfg) {
+		got[stmt.DeclRange.Filename] = stmt.Package.String()
+	}
+	want := map[string]string{
+		"testdata/move-statement-packages/main.tf":                 "",
+		"testdata/move-statement-packages/local/main.tf":           "",
+		"testdata/move-statement-packages/external/main.tf":        "module.fake_external",
+		"testdata/move-statement-packages/external/nested/main.tf": "module.fake_external",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong packages\n%s", diff)
+	}
+}
@@ -49,11 +49,14 @@ func ValidateMoves(stmts []MoveStatement, rootCfg *configs.Config, declaredInsts
 	type AbsMoveEndpoint struct {
 		Other     addrs.AbsMoveable
 		StmtRange tfdiags.SourceRange
+		Stmt      *MoveStatement
 	}
 	stmtFrom := addrs.MakeMap[addrs.AbsMoveable, AbsMoveEndpoint]()
 	stmtTo := addrs.MakeMap[addrs.AbsMoveable, AbsMoveEndpoint]()
 
-	for _, stmt := range stmts {
+	for i := range stmts {
+		stmt := &stmts[i]
+
 		// Earlier code that constructs MoveStatement values should ensure that
 		// both stmt.From and stmt.To always belong to the same statement.
 		fromMod, _ := stmt.From.ModuleCallTraversals()
@@ -119,9 +122,20 @@ func ValidateMoves(stmts []MoveStatement, rootCfg *configs.Config, declaredInsts
 				})
 			}
 
-			// There can only be one destination for each source address.
+			// There can only be one destination for each source address,
+			// unless the statements were declared in different module
+			// packages, in which case the outer package takes precedence.
 			if existing, exists := stmtFrom.GetOk(absFrom); exists {
-				if !addrs.Equivalent(existing.Other, absTo) {
+				switch {
+				case existing.Stmt.inOuterPackage(stmt):
+					// The existing statement wins, so this one has no effect.
+				case stmt.inOuterPackage(existing.Stmt):
+					stmtFrom.Put(absFrom, AbsMoveEndpoint{
+						Other:     absTo,
+						StmtRange: stmt.DeclRange,
+						Stmt:      stmt,
+					})
+				case !addrs.Equivalent(existing.Other, absTo):
 					diags = diags.Append(&hcl.Diagnostic{
 						Severity: hcl.DiagError,
 						Summary:  "Ambiguous move statements",
@@ -137,6 +151,7 @@ func ValidateMoves(stmts []MoveStatement, rootCfg *configs.Config, declaredInsts
 				stmtFrom.Put(absFrom, AbsMoveEndpoint{
 					Other:     absTo,
 					StmtRange: stmt.DeclRange,
+					Stmt:      stmt,
 				})
 			}
 
@@ -158,6 +173,7 @@ func ValidateMoves(stmts []MoveStatement, rootCfg *configs.Config, declaredInsts
 				stmtTo.Put(absTo, AbsMoveEndpoint{
 					Other:     absFrom,
 					StmtRange: stmt.DeclRange,
+					Stmt:      stmt,
 				})
 			}
 
@@ -436,6 +436,80 @@ Each resource can have moved from only one source resource.`,
 			},
 			WantError: ``, // This is okay because the call itself is not considered to be inside the package it refers to
 		},
+		"chained moves across module packages": {
+			Statements: []MoveStatement{
+				makeTestMoveStmt(t,
+					``,
+					`test.thing`,
+					`module.fake_external.test.thing`,
+				),
+				makeTestMoveStmt(t,
+					`fake_external`,
+					`test.thing`,
+					`test.single`,
+				),
+			},
+			WantError: ``,
+		},
+		"moves of the same object declared in different module packages": {
+			Statements: []MoveStatement{
+				makeTestPackageMoveStmt(t,
+					`fake_external`,
+					`fake_external`,
+					`test.nonexist1`,
+					`test.target1`,
+				),
+				makeTestMoveStmt(t,
+					``,
+					`module.fake_external.test.nonexist1`,
+					`test.target1`,
+				),
+			},
+			WantError: ``, // The statement in the root module's package takes precedence
+		},
+		"moves to the same object declared in different module packages": {
+			Statements: []MoveStatement{
+				makeTestMoveStmt(t,
+					``,
+					`test.nonexist1`,
+					`module.fake_external.test.target1`,
+				),
+				makeTestPackageMoveStmt(t,
+					`fake_external`,
+					`fake_external`,
+					`test.nonexist2`,
+					`test.target1`,
+				),
+			},
+			WantError: `Ambiguous move statements: A statement at test:1,1 declared that test.nonexist1 moved to module.fake_external.test.target1, but this statement instead declares that module.fake_external.test.nonexist2 moved there.
+
+Each resource can have moved from only one source resource.`,
+		},
+		"cyclic chain across module packages": {
+			Statements: []MoveStatement{
+				makeTestMoveStmt(t,
+					``,
+					`test.nonexist1`,
+					`module.fake_external.test.nonexist1`,
+				),
+				makeTestMoveStmt(t,
+					`fake_external`,
+					`test.nonexist1`,
+					`module.nonexist.test.nonexist1`,
+				),
+				makeTestMoveStmt(t,
+					``,
+					`module.fake_external.module.nonexist.test.nonexist1`,
+					`test.nonexist1`,
+				),
+			},
+			WantError: `Cyclic dependency in move statements: The following chained move statements form a cycle, and so there is no final location to move objects to:
+  - test:1,1: module.fake_external.module.nonexist.test.nonexist1[*] → test.nonexist1[*]
+  - test:1,1: module.fake_external[*].test.nonexist1[*] → module.fake_external[*].module.nonexist.test.nonexist1[*]
+  - test:1,1: test.nonexist1[*] → module.fake_external.test.nonexist1[*]
+
+A chain of move statements must end with an address that doesn't appear in any other statements, and which typically also refers to an object still declared in the configuration.`,
+		},
 		"resource type mismatch": {
 			Statements: []MoveStatement{
 				makeTestMoveStmt(t, ``,
@@ -707,6 +781,13 @@ func makeTestImpliedMoveStmt(t *testing.T, moduleStr, fromStr, toStr string) Mov
 	return ret
 }
 
+func makeTestPackageMoveStmt(t *testing.T, packageStr, moduleStr, fromStr, toStr string) MoveStatement {
+	t.Helper()
+	ret := makeTestMoveStmt(t, moduleStr, fromStr, toStr)
+	ret.Package = addrs.Module(strings.Split(packageStr, "."))
+	return ret
+}
+
 var fakeExternalModuleSource = addrs.ModuleSourceRemote{
 	Package: addrs.ModulePackage("fake-external:///"),
 }
@@ -17,22 +17,51 @@ import (
 type RemoveStatement struct {
 	From      addrs.ConfigRemovable
 	DeclRange tfdiags.SourceRange
+
+	// Destroy is true if the objects should be destroyed rather than only
+	// removed from the state.
+	Destroy bool
+
+	// Provisioners are the destroy-time provisioners to run before
+	// destroying each instance of the resource that From refers to.
+	Provisioners []*configs.Provisioner
 }
 
 // GetEndpointsToRemove recurses through the modules of the given configuration
-// and returns an array of all "removed" addresses within, in a
-// deterministic but undefined order.
+// and returns an array of all "removed" addresses within whose objects must
+// only be removed from the state, in a deterministic but undefined order.
+// The objects of "removed" blocks that set destroy = true are instead planned
+// for destruction as usual, so their addresses are not included.
 // We also validate that the removed modules/resources configuration blocks were removed.
 func GetEndpointsToRemove(rootCfg *configs.Config) ([]addrs.ConfigRemovable, tfdiags.Diagnostics) {
 	rm := findRemoveStatements(rootCfg, nil)
 	diags := validateRemoveStatements(rootCfg, rm)
-	removedAddresses := make([]addrs.ConfigRemovable, len(rm))
-	for i, rs := range rm {
-		removedAddresses[i] = rs.From
+	removedAddresses := make([]addrs.ConfigRemovable, 0, len(rm))
+	for _, rs := range rm {
+		if rs.Destroy {
+			continue
+		}
+		removedAddresses = append(removedAddresses, rs.From)
 	}
 	return removedAddresses, diags
 }
 
+// FindRemovedProvisioners recurses through the modules of the given
+// configuration and returns the destroy-time provisioners declared in
+// "removed" blocks, keyed by the absolute address of the resource that
+// each block refers to.
+func FindRemovedProvisioners(rootCfg *configs.Config) map[string][]*configs.Provisioner {
+	ret := make(map[string][]*configs.Provisioner)
+	for _, rs := range findRemoveStatements(rootCfg, nil) {
+		if !rs.Destroy || len(rs.Provisioners) == 0 {
+			continue
+		}
+		key := rs.From.String()
+		ret[key] = append(ret[key], rs.Provisioners...)
+	}
+	return ret
+}
+
 func findRemoveStatements(cfg *configs.Config, into []*RemoveStatement) []*RemoveStatement {
 	modAddr := cfg.Path
 
@@ -51,7 +80,12 @@ func findRemoveStatements(cfg *configs.Config, into []*RemoveStatement) []*Remov
 				Module:   absModule,
 			}
 
-			removedEndpoint = &RemoveStatement{From: absConfigResource, DeclRange: tfdiags.SourceRangeFromHCL(rc.DeclRange)}
+			removedEndpoint = &RemoveStatement{
+				From:         absConfigResource,
+				DeclRange:    tfdiags.SourceRangeFromHCL(rc.DeclRange),
+				Destroy:      rc.Destroy,
+				Provisioners: rc.Provisioners,
+			}
 
 		case addrs.Module:
 			// Get the absolute address of the module by appending the module config address
@@ -59,7 +93,11 @@ func findRemoveStatements(cfg *configs.Config, into []*RemoveStatement) []*Remov
 			var absModule = make(addrs.Module, 0, len(modAddr)+len(FromAddress))
 			absModule = append(absModule, modAddr...)
 			absModule = append(absModule, FromAddress...)
-			removedEndpoint = &RemoveStatement{From: absModule, DeclRange: tfdiags.SourceRangeFromHCL(rc.DeclRange)}
+			removedEndpoint = &RemoveStatement{
+				From:      absModule,
+				DeclRange: tfdiags.SourceRangeFromHCL(rc.DeclRange),
+				Destroy:   rc.Destroy,
+			}
 
 		default:
 			panic(fmt.Sprintf("unhandled address type %T", FromAddress))
@@ -0,0 +1,11 @@
+moved {
+  from = test.old
+  to   = test.new
+}
+
+resource "test" "new" {
+}
+
+module "nested" {
+  source = "./nested"
+}
@@ -0,0 +1,7 @@
+moved {
+  from = test.a
+  to   = test.b
+}
+
+resource "test" "b" {
+}
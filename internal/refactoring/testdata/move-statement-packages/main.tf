@@ -0,0 +1,17 @@
+moved {
+  from = module.fake_external.test.old
+  to   = test.consumer
+}
+
+resource "test" "consumer" {
+}
+
+module "local" {
+  source = "./local"
+}
+
+module "fake_external" {
+  # The fixture loader treats a module call named "fake_external" as
+  # entering a separate module package.
+  source = "./external"
+}
@@ -0,0 +1,151 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+
+	"github.com/hashicorp/go-retryablehttp"
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/registry/regsrc"
+)
+
+const (
+	publishServiceID = "publish.v1"
+
+	// xChecksumSHA256 is the header in which the publish API requests carry
+	// the hex-encoded SHA256 checksum of the uploaded content, so that the
+	// registry can verify it.
+	xChecksumSHA256 = "X-Checksum-SHA256"
+)
+
+// ProviderVersionPublication describes a provider version whose files have
+// been uploaded to a registry with PublishProviderFile, so that the registry
+// can make the version available.
+type ProviderVersionPublication struct {
+	Protocols []string `json:"protocols"`
+
+	Platforms []ProviderPlatformPublication `json:"platforms"`
+
+	// SHASumsFilename and SHASumsSignatureFilename are the names of the
+	// uploaded files that list the checksums of the packages and sign that
+	// list, respectively.
+	SHASumsFilename          string `json:"shasums_filename"`
+	SHASumsSignatureFilename string `json:"shasums_signature_filename"`
+
+	// SigningKey is the ASCII-armored public part of the GPG key that made
+	// the signature, and KeyID is its ID.
+	SigningKey struct {
+		KeyID      string `json:"key_id"`
+		ASCIIArmor string `json:"ascii_armor"`
+	} `json:"signing_key"`
+}
+
+// ProviderPlatformPublication describes the package of a provider version
+// for a single platform.
+type ProviderPlatformPublication struct {
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Filename string `json:"filename"`
+	SHASum   string `json:"shasum"`
+}
+
+// PublishModule uploads the given archive of a module package to the
+// registry as the given version of the module.
+//
+// The archive must be a gzip-compressed tar archive of the package, and
+// sha256 is the hex-encoded SHA256 checksum of the archive.
+func (c *Client) PublishModule(ctx context.Context, module *regsrc.Module, version string, archive []byte, sha256 string) error {
+	host, err := module.SvcHost()
+	if err != nil {
+		return err
+	}
+
+	return c.publish(ctx, host, http.MethodPut, path.Join("modules", module.Module(), version), "application/gzip", archive, sha256)
+}
+
+// PublishProviderFile uploads a single file of a provider version to the
+// registry, such as the package for one platform or the checksums of the
+// packages.
+//
+// Once all of the files are uploaded, PublishProviderVersion makes the
+// version available.
+func (c *Client) PublishProviderFile(ctx context.Context, provider addrs.Provider, version string, filename string, content []byte, sha256 string) error {
+	return c.publish(ctx, provider.Hostname, http.MethodPut, path.Join("providers", provider.Namespace, provider.Type, version, filename), "application/octet-stream", content, sha256)
+}
+
+// PublishProviderVersion makes a provider version available in the registry,
+// once all of the files the given publication refers to have been uploaded
+// with PublishProviderFile.
+func (c *Client) PublishProviderVersion(ctx context.Context, provider addrs.Provider, version string, publication *ProviderVersionPublication) error {
+	body, err := json.Marshal(publication)
+	if err != nil {
+		return err
+	}
+	return c.publish(ctx, provider.Hostname, http.MethodPost, path.Join("providers", provider.Namespace, provider.Type, version), "application/json", body, "")
+}
+
+// publish makes a request to the publish API of the registry at the given
+// host, with the given path relative to the base URL of the API.
+func (c *Client) publish(ctx context.Context, host svchost.Hostname, method string, reqPath string, contentType string, body []byte, sha256 string) error {
+	service, err := c.Discover(host, publishServiceID)
+	if err != nil {
+		return err
+	}
+
+	p, err := url.Parse(reqPath)
+	if err != nil {
+		return err
+	}
+	service = service.ResolveReference(p)
+
+	log.Printf("[DEBUG] publishing to %q", service)
+
+	req, err := retryablehttp.NewRequest(method, service.String(), body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	c.addRequestCreds(host, req.Request)
+	req.Header.Set(xTerraformVersion, tfVersion)
+	req.Header.Set("Content-Type", contentType)
+	if sha256 != "" {
+		req.Header.Set(xChecksumSHA256, sha256)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated, http.StatusNoContent:
+		return nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("the registry at %s rejected the request (%s); run \"tofu login %s\" to log in with an account that can publish", host.ForDisplay(), resp.Status, host.ForDisplay())
+	case http.StatusConflict:
+		return fmt.Errorf("the registry at %s already has this version", host.ForDisplay())
+	default:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		msg = bytes.TrimSpace(msg)
+		if len(msg) > 0 {
+			return fmt.Errorf("the registry at %s returned %s: %s", host.ForDisplay(), resp.Status, msg)
+		}
+		return fmt.Errorf("the registry at %s returned %s", host.ForDisplay(), resp.Status)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+	"github.com/hashicorp/terraform-svchost/disco"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/registry/regsrc"
+)
+
+func testPublishServer(t *testing.T, handler http.HandlerFunc) *disco.Disco {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	services := disco.New()
+	services.ForceHostServices(svchost.Hostname("example.com"), map[string]interface{}{
+		"publish.v1": server.URL + "/v1/publish/",
+	})
+	return services
+}
+
+func TestPublishModule(t *testing.T) {
+	var gotMethod, gotPath, gotSum, gotBody string
+	services := testPublishServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotMethod, gotPath, gotSum, gotBody = r.Method, r.URL.Path, r.Header.Get(xChecksumSHA256), string(body)
+		w.WriteHeader(http.StatusCreated)
+	})
+	client := NewClient(services, nil)
+
+	module, err := regsrc.ParseModuleSource("example.com/acme/network/aws")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.PublishModule(context.Background(), module, "1.2.3", []byte("archive"), "abc123"); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotMethod != http.MethodPut {
+		t.Errorf("wrong method %s", gotMethod)
+	}
+	if want := "/v1/publish/modules/acme/network/aws/1.2.3"; gotPath != want {
+		t.Errorf("wrong path %s; want %s", gotPath, want)
+	}
+	if gotSum != "abc123" {
+		t.Errorf("wrong checksum header %q", gotSum)
+	}
+	if gotBody != "archive" {
+		t.Errorf("wrong body %q", gotBody)
+	}
+}
+
+func TestPublishProviderVersion(t *testing.T) {
+	var gotPaths []string
+	var gotPublication ProviderVersionPublication
+	services := testPublishServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPost {
+			if err := json.NewDecoder(r.Body).Decode(&gotPublication); err != nil {
+				t.Errorf("invalid publication: %s", err)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	client := NewClient(services, nil)
+
+	provider := addrs.NewProvider(svchost.Hostname("example.com"), "acme", "widgets")
+	if err := client.PublishProviderFile(context.Background(), provider, "0.1.0", "SHA256SUMS", []byte("sums"), ""); err != nil {
+		t.Fatal(err)
+	}
+	publication := &ProviderVersionPublication{
+		Protocols:       []string{"5.0"},
+		SHASumsFilename: "SHA256SUMS",
+	}
+	if err := client.PublishProviderVersion(context.Background(), provider, "0.1.0", publication); err != nil {
+		t.Fatal(err)
+	}
+
+	wantPaths := []string{
+		"PUT /v1/publish/providers/acme/widgets/0.1.0/SHA256SUMS",
+		"POST /v1/publish/providers/acme/widgets/0.1.0",
+	}
+	if got, want := strings.Join(gotPaths, "\n"), strings.Join(wantPaths, "\n"); got != want {
+		t.Errorf("wrong requests\ngot:\n%s\nwant:\n%s", got, want)
+	}
+	if gotPublication.SHASumsFilename != "SHA256SUMS" {
+		t.Errorf("wrong publication %#v", gotPublication)
+	}
+}
+
+func TestPublish_errors(t *testing.T) {
+	tests := map[string]struct {
+		status  int
+		body    string
+		wantErr string
+	}{
+		"unauthorized": {http.StatusUnauthorized, "", `run "tofu login example.com"`},
+		"conflict":     {http.StatusConflict, "", "already has this version"},
+		"bad request":  {http.StatusBadRequest, "invalid archive\n", "400 Bad Request: invalid archive"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			services := testPublishServer(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.status)
+				io.WriteString(w, test.body)
+			})
+			client := NewClient(services, nil)
+
+			module, err := regsrc.ParseModuleSource("example.com/acme/network/aws")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = client.PublishModule(context.Background(), module, "1.2.3", []byte("archive"), "")
+			if err == nil {
+				t.Fatal("succeeded; want error")
+			}
+			if !strings.Contains(err.Error(), test.wantErr) {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package response
+
+// Provider is a provider in the results of a search of a provider registry.
+type Provider struct {
+	ID          string `json:"id"`
+	Namespace   string `json:"namespace"`
+	Name        string `json:"name"`
+	Version     string `json:"version"`
+	Description string `json:"description"`
+	Source      string `json:"source"`
+	Downloads   int    `json:"downloads"`
+	Verified    bool   `json:"verified"`
+}
+
+// ProviderList is the response structure for a pageable list of Providers.
+type ProviderList struct {
+	Meta      PaginationMeta `json:"meta"`
+	Providers []*Provider    `json:"providers"`
+}
@@ -0,0 +1,91 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/go-retryablehttp"
+	svchost "github.com/hashicorp/terraform-svchost"
+
+	"github.com/opentofu/opentofu/internal/registry/response"
+)
+
+// ErrSearchUnsupported is returned by SearchModules and SearchProviders when
+// the registry doesn't offer a search endpoint, which is optional.
+var ErrSearchUnsupported = errors.New("the registry doesn't support searching")
+
+// SearchModules queries the module registry at the given host for modules
+// matching the given query, returning at most limit of them.
+func (c *Client) SearchModules(ctx context.Context, host svchost.Hostname, query string, limit int) (*response.ModuleList, error) {
+	var list response.ModuleList
+	if err := c.search(ctx, host, modulesServiceID, query, limit, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// SearchProviders queries the provider registry at the given host for
+// providers matching the given query, returning at most limit of them.
+func (c *Client) SearchProviders(ctx context.Context, host svchost.Hostname, query string, limit int) (*response.ProviderList, error) {
+	var list response.ProviderList
+	if err := c.search(ctx, host, providersServiceID, query, limit, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// search makes a request to the search endpoint of the given registry
+// service, which is at "search" relative to the base URL of the service, and
+// decodes the response into result.
+func (c *Client) search(ctx context.Context, host svchost.Hostname, serviceID string, query string, limit int, result interface{}) error {
+	service, err := c.Discover(host, serviceID)
+	if err != nil {
+		return err
+	}
+
+	q := url.Values{}
+	q.Set("q", query)
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	service = service.ResolveReference(&url.URL{Path: "search", RawQuery: q.Encode()})
+
+	log.Printf("[DEBUG] searching %q", service)
+
+	req, err := retryablehttp.NewRequest("GET", service.String(), nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+
+	c.addRequestCreds(host, req.Request)
+	req.Header.Set(xTerraformVersion, tfVersion)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// OK
+	case http.StatusNotFound, http.StatusMethodNotAllowed, http.StatusNotImplemented:
+		return ErrSearchUnsupported
+	default:
+		return fmt.Errorf("error searching the registry: %s", resp.Status)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(result)
+}
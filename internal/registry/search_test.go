@@ -0,0 +1,87 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	svchost "github.com/hashicorp/terraform-svchost"
+	"github.com/hashicorp/terraform-svchost/disco"
+)
+
+func testSearchServer(t *testing.T, handler http.HandlerFunc) *disco.Disco {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	services := disco.New()
+	services.ForceHostServices(svchost.Hostname("example.com"), map[string]interface{}{
+		"modules.v1":   server.URL + "/v1/modules/",
+		"providers.v1": server.URL + "/v1/providers/",
+	})
+	return services
+}
+
+func TestSearchModules(t *testing.T) {
+	var gotPath, gotQuery, gotLimit string
+	services := testSearchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery, gotLimit = r.URL.Path, r.URL.Query().Get("q"), r.URL.Query().Get("limit")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"modules":[{"namespace":"acme","name":"network","provider":"aws","version":"1.2.0","verified":true}]}`))
+	})
+	client := NewClient(services, nil)
+
+	list, err := client.SearchModules(context.Background(), svchost.Hostname("example.com"), "network vpc", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/v1/modules/search"; gotPath != want {
+		t.Errorf("wrong path %s; want %s", gotPath, want)
+	}
+	if gotQuery != "network vpc" || gotLimit != "5" {
+		t.Errorf("wrong query %q and limit %q", gotQuery, gotLimit)
+	}
+	if len(list.Modules) != 1 || list.Modules[0].Name != "network" || !list.Modules[0].Verified {
+		t.Errorf("wrong modules %#v", list.Modules)
+	}
+}
+
+func TestSearchProviders(t *testing.T) {
+	var gotPath string
+	services := testSearchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"providers":[{"namespace":"acme","name":"widgets","version":"2.0.0","description":"Widgets"}]}`))
+	})
+	client := NewClient(services, nil)
+
+	list, err := client.SearchProviders(context.Background(), svchost.Hostname("example.com"), "widgets", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "/v1/providers/search"; gotPath != want {
+		t.Errorf("wrong path %s; want %s", gotPath, want)
+	}
+	if len(list.Providers) != 1 || list.Providers[0].Description != "Widgets" {
+		t.Errorf("wrong providers %#v", list.Providers)
+	}
+}
+
+func TestSearch_unsupported(t *testing.T) {
+	services := testSearchServer(t, func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	client := NewClient(services, nil)
+
+	_, err := client.SearchModules(context.Background(), svchost.Hostname("example.com"), "network", 0)
+	if !errors.Is(err, ErrSearchUnsupported) {
+		t.Errorf("wrong error %v", err)
+	}
+}
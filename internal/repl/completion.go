@@ -0,0 +1,216 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repl
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/zclconf/go-cty/cty/function"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+// Completer suggests completions for partially-typed console input, based on
+// the names that an expression in the root module could refer to.
+type Completer struct {
+	// candidates are all of the complete references and function calls
+	// that the completer knows about, such as "var.foo",
+	// "aws_instance.example.id" and "upper(".
+	candidates []string
+}
+
+// NewCompleter returns a completer for references to the objects declared
+// in the root module of the given configuration, the resource instances in
+// the given state, and the given functions.
+//
+// The schemas are used to suggest the attributes of resources. Any of the
+// arguments may be nil, in which case the completer just won't suggest the
+// corresponding names.
+func NewCompleter(config *configs.Config, state *states.State, schemas *tofu.Schemas, funcs map[string]function.Function) *Completer {
+	seen := make(map[string]bool)
+	var candidates []string
+	add := func(s string) {
+		if !seen[s] {
+			seen[s] = true
+			candidates = append(candidates, s)
+		}
+	}
+	addResource := func(addr string, provider addrs.Provider, mode addrs.ResourceMode, typeName string) {
+		add(addr)
+		if schemas == nil {
+			return
+		}
+		schema, _ := schemas.ResourceTypeConfig(provider, mode, typeName)
+		for _, name := range schemaNames(schema) {
+			add(addr + "." + name)
+		}
+	}
+
+	for name := range funcs {
+		add(name + "(")
+	}
+
+	add("path.cwd")
+	add("path.module")
+	add("path.root")
+	add("terraform.workspace")
+
+	if config != nil && config.Module != nil {
+		mod := config.Module
+		for name := range mod.Variables {
+			add("var." + name)
+		}
+		for name := range mod.Locals {
+			add("local." + name)
+		}
+		for _, r := range mod.ManagedResources {
+			addResource(r.Addr().String(), r.Provider, r.Mode, r.Type)
+		}
+		for _, r := range mod.DataResources {
+			addResource(r.Addr().String(), r.Provider, r.Mode, r.Type)
+		}
+		for name := range mod.ModuleCalls {
+			add("module." + name)
+			if child := config.Children[name]; child != nil && child.Module != nil {
+				for outputName := range child.Module.Outputs {
+					add("module." + name + "." + outputName)
+				}
+			}
+		}
+	}
+
+	if state != nil {
+		if ms := state.Module(addrs.RootModuleInstance); ms != nil {
+			for _, rs := range ms.Resources {
+				for key := range rs.Instances {
+					if key == addrs.NoKey {
+						continue
+					}
+					addr := rs.Addr.Resource.Instance(key)
+					addResource(addr.String(), rs.ProviderConfig.Provider, addr.Resource.Mode, addr.Resource.Type)
+				}
+			}
+		}
+	}
+
+	sort.Strings(candidates)
+	return &Completer{
+		candidates: candidates,
+	}
+}
+
+// schemaNames returns the names of the attributes and nested blocks of the
+// given schema, which are the attributes of the corresponding object.
+func schemaNames(schema *configschema.Block) []string {
+	if schema == nil {
+		return nil
+	}
+	names := make([]string, 0, len(schema.Attributes)+len(schema.BlockTypes))
+	for name := range schema.Attributes {
+		names = append(names, name)
+	}
+	for name := range schema.BlockTypes {
+		names = append(names, name)
+	}
+	return names
+}
+
+// Complete returns the candidate completions for the partial word that ends
+// at the given position in the given line, along with the length of that
+// partial word. Each candidate is a full replacement for the partial word.
+//
+// Candidates are only extended up to the end of the next step of a
+// reference, so completing "aws_" might produce "aws_instance." and
+// completing that produces the names of the instances.
+func (c *Completer) Complete(line string, pos int) ([]string, int) {
+	if pos > len(line) {
+		pos = len(line)
+	}
+	prefix := line[completionWordStart(line, pos):pos]
+
+	var ret []string
+	seen := make(map[string]bool)
+	for _, candidate := range c.candidates {
+		if !strings.HasPrefix(candidate, prefix) {
+			continue
+		}
+		if end := nextStepEnd(candidate, len(prefix)); end < len(candidate) {
+			candidate = candidate[:end+1]
+		}
+		if !seen[candidate] {
+			seen[candidate] = true
+			ret = append(ret, candidate)
+		}
+	}
+	return ret, len(prefix)
+}
+
+// completionWordStart returns the index where the reference or function name
+// that ends at the given position starts.
+func completionWordStart(line string, pos int) int {
+	depth := 0
+	for i := pos - 1; i >= 0; i-- {
+		ch := line[i]
+		switch {
+		case ch == ']':
+			depth++
+		case ch == '[':
+			if depth > 0 {
+				depth--
+				continue
+			}
+			// An open bracket directly before the cursor is the start of
+			// an instance key that is still being typed, but any other
+			// open bracket is the start of an index expression that the
+			// word is inside.
+			if i != pos-1 {
+				return i + 1
+			}
+		case depth > 0:
+			// Anything can appear in an instance key.
+		case isReferenceChar(ch):
+		default:
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func isReferenceChar(ch byte) bool {
+	switch {
+	case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		return true
+	case ch == '_' || ch == '-' || ch == '.' || ch == ':':
+		return true
+	default:
+		return false
+	}
+}
+
+// nextStepEnd returns the index of the first period at or after the given
+// index that isn't inside an instance key, or the length of the string if
+// there is no such period.
+func nextStepEnd(s string, from int) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 && i >= from {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,152 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package repl
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/initwd"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tofu"
+)
+
+func TestCompleter(t *testing.T) {
+	config, _, cleanup, configDiags := initwd.LoadConfigForTests(t, "testdata/config-fixture", "tests")
+	defer cleanup()
+	if configDiags.HasErrors() {
+		t.Fatalf("unexpected problems loading config: %s", configDiags.Err())
+	}
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			addrs.Resource{
+				Mode: addrs.ManagedResourceMode,
+				Type: "test_instance",
+				Name: "counted",
+			}.Instance(addrs.IntKey(0)).Absolute(addrs.RootModuleInstance),
+			&states.ResourceInstanceObjectSrc{
+				Status:    states.ObjectReady,
+				AttrsJSON: []byte(`{"id":"bar"}`),
+			},
+			addrs.AbsProviderConfig{
+				Provider: addrs.NewDefaultProvider("test"),
+				Module:   addrs.RootModule,
+			},
+		)
+	})
+
+	schemas := &tofu.Schemas{
+		Providers: map[addrs.Provider]providers.ProviderSchema{
+			addrs.NewDefaultProvider("test"): {
+				ResourceTypes: map[string]providers.Schema{
+					"test_instance": {
+						Block: &configschema.Block{
+							Attributes: map[string]*configschema.Attribute{
+								"id":  {Type: cty.String, Computed: true},
+								"ami": {Type: cty.String, Optional: true},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	funcs := map[string]function.Function{
+		"upper":   stdlib.UpperFunc,
+		"uuid":    stdlib.UpperFunc, // only the name matters here
+		"element": stdlib.ElementFunc,
+	}
+
+	c := NewCompleter(config, state, schemas, funcs)
+
+	tests := []struct {
+		Line   string
+		Want   []string
+		Length int
+	}{
+		{
+			Line:   "up",
+			Want:   []string{"upper("},
+			Length: 2,
+		},
+		{
+			Line:   "u",
+			Want:   []string{"upper(", "uuid("},
+			Length: 1,
+		},
+		{
+			Line:   "upper(mod",
+			Want:   []string{"module."},
+			Length: 3,
+		},
+		{
+			Line:   "module.",
+			Want:   []string{"module.module"},
+			Length: 7,
+		},
+		{
+			Line:   "test_",
+			Want:   []string{"test_instance."},
+			Length: 5,
+		},
+		{
+			Line:   "test_instance.",
+			Want:   []string{"test_instance.counted[0]", "test_instance.counted[0].", "test_instance.foo", "test_instance.foo."},
+			Length: 14,
+		},
+		{
+			Line:   "test_instance.foo.",
+			Want:   []string{"test_instance.foo.ami", "test_instance.foo.id"},
+			Length: 18,
+		},
+		{
+			Line:   "test_instance.counted[",
+			Want:   []string{"test_instance.counted[0]", "test_instance.counted[0]."},
+			Length: 22,
+		},
+		{
+			Line:   "local.list[test_",
+			Want:   []string{"test_instance."},
+			Length: 5,
+		},
+		{
+			Line:   "1 + ",
+			Want:   nil,
+			Length: 0,
+		},
+		{
+			Line:   "nope",
+			Want:   nil,
+			Length: 4,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Line, func(t *testing.T) {
+			got, length := c.Complete(test.Line, len(test.Line))
+			if length != test.Length {
+				t.Errorf("wrong length %d; want %d", length, test.Length)
+			}
+			// Completing an empty word would offer everything, so we only
+			// check that case by length.
+			if test.Length == 0 {
+				return
+			}
+			if diff := cmp.Diff(test.Want, got); diff != "" {
+				t.Errorf("wrong candidates\n%s", diff)
+			}
+		})
+	}
+}
@@ -6,11 +6,13 @@
 package repl
 
 import (
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -41,25 +43,55 @@ func (s *Session) Handle(line string) (string, bool, tfdiags.Diagnostics) {
 	case strings.TrimSpace(line) == "help":
 		ret, diags := s.handleHelp()
 		return ret, false, diags
+	case isConsoleCommand(line, typeCommand):
+		ret, diags := s.handleType(strings.TrimPrefix(strings.TrimSpace(line), typeCommand))
+		return ret, false, diags
 	default:
 		ret, diags := s.handleEval(line)
 		return ret, false, diags
 	}
 }
 
-func (s *Session) handleEval(line string) (string, tfdiags.Diagnostics) {
-	var diags tfdiags.Diagnostics
+// typeCommand is the console command that shows the type of the result of
+// an expression rather than its value.
+const typeCommand = ":type"
 
-	// Parse the given line as an expression
-	expr, parseDiags := hclsyntax.ParseExpression([]byte(line), "<console-input>", hcl.Pos{Line: 1, Column: 1})
-	diags = diags.Append(parseDiags)
-	if parseDiags.HasErrors() {
-		return "", diags
+// isConsoleCommand returns true if the given line is a use of the given
+// console command, with or without an argument.
+func isConsoleCommand(line, command string) bool {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, command) {
+		return false
 	}
+	rest := line[len(command):]
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
 
-	val, valDiags := s.Scope.EvalExpr(expr, cty.DynamicPseudoType)
-	diags = diags.Append(valDiags)
-	if valDiags.HasErrors() {
+// IncompleteInput returns true if the given input is the start of an
+// expression that continues on later lines, because it has brackets,
+// template sequences or heredocs that are not yet closed.
+//
+// The interactive console uses this to allow entering expressions over
+// multiple lines.
+func IncompleteInput(input string) bool {
+	tokens, _ := hclsyntax.LexExpression([]byte(input+"\n"), "<console-input>", hcl.Pos{Line: 1, Column: 1})
+	depth := 0
+	for _, tok := range tokens {
+		switch tok.Type {
+		case hclsyntax.TokenOBrace, hclsyntax.TokenOBrack, hclsyntax.TokenOParen,
+			hclsyntax.TokenTemplateInterp, hclsyntax.TokenTemplateControl, hclsyntax.TokenOHeredoc:
+			depth++
+		case hclsyntax.TokenCBrace, hclsyntax.TokenCBrack, hclsyntax.TokenCParen,
+			hclsyntax.TokenTemplateSeqEnd, hclsyntax.TokenCHeredoc:
+			depth--
+		}
+	}
+	return depth > 0
+}
+
+func (s *Session) handleEval(line string) (string, tfdiags.Diagnostics) {
+	val, diags := s.eval(line)
+	if diags.HasErrors() {
 		return "", diags
 	}
 
@@ -88,6 +120,117 @@ func (s *Session) handleEval(line string) (string, tfdiags.Diagnostics) {
 	return FormatValue(val, 0), diags
 }
 
+// jsonResult is the JSON representation of the result of an expression
+// returned by HandleJSON, which matches the representation of each output
+// value in "tofu output -json".
+type jsonResult struct {
+	Sensitive bool            `json:"sensitive"`
+	Type      json.RawMessage `json:"type"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// HandleJSON evaluates the given expression and returns a JSON object
+// describing its result, for scripts that run the console non-interactively.
+//
+// Unlike Handle, HandleJSON does not support console commands, and returns
+// an error if the result is not yet known because JSON has no way to
+// represent unknown values.
+func (s *Session) HandleJSON(line string) ([]byte, tfdiags.Diagnostics) {
+	val, diags := s.eval(line)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	if marks.Contains(val, marks.TypeType) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid use of type function",
+			"The console-only \"type\" function cannot be used with JSON output.",
+		))
+		return nil, diags
+	}
+	if !val.IsWhollyKnown() {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Result is not yet known",
+			"The result of the expression depends on values that OpenTofu will not know until it applies a plan, so it has no JSON representation.",
+		))
+		return nil, diags
+	}
+
+	sensitive := marks.Contains(val, marks.Sensitive)
+	val, _ = val.UnmarkDeep()
+
+	valJSON, err := ctyjson.Marshal(val, val.Type())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to serialize result",
+			err.Error(),
+		))
+		return nil, diags
+	}
+	typeJSON, err := ctyjson.MarshalType(val.Type())
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to serialize result type",
+			err.Error(),
+		))
+		return nil, diags
+	}
+
+	ret, err := json.MarshalIndent(jsonResult{
+		Sensitive: sensitive,
+		Type:      json.RawMessage(typeJSON),
+		Value:     json.RawMessage(valJSON),
+	}, "", "  ")
+	if err != nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Failed to serialize result",
+			err.Error(),
+		))
+		return nil, diags
+	}
+	return ret, diags
+}
+
+func (s *Session) handleType(line string) (string, tfdiags.Diagnostics) {
+	if strings.TrimSpace(line) == "" {
+		var diags tfdiags.Diagnostics
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Missing expression",
+			"The :type command requires an expression whose type to show, like \":type var.example\".",
+		))
+		return "", diags
+	}
+
+	val, diags := s.eval(line)
+	if diags.HasErrors() {
+		return "", diags
+	}
+	val, _ = val.UnmarkDeep()
+	return typeString(val.Type()), diags
+}
+
+// eval parses and evaluates the given console input as an expression.
+func (s *Session) eval(line string) (cty.Value, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	// Parse the given line as an expression
+	expr, parseDiags := hclsyntax.ParseExpression([]byte(line), "<console-input>", hcl.Pos{Line: 1, Column: 1})
+	diags = diags.Append(parseDiags)
+	if parseDiags.HasErrors() {
+		return cty.DynamicVal, diags
+	}
+
+	val, valDiags := s.Scope.EvalExpr(expr, cty.DynamicPseudoType)
+	diags = diags.Append(valDiags)
+	return val, diags
+}
+
 func (s *Session) handleHelp() (string, tfdiags.Diagnostics) {
 	text := `
 The OpenTofu console allows you to experiment with OpenTofu interpolations.
@@ -95,7 +238,13 @@ You may access resources in the state (if you have one) just as you would
 from a configuration. For example: "aws_instance.foo.id" would evaluate
 to the ID of "aws_instance.foo" if it exists in your state.
 
-Type in the interpolation to test and hit <enter> to see the result.
+Type in the interpolation to test and hit <enter> to see the result. An
+expression with unclosed brackets continues on the following lines. Use
+<tab> to complete the names of functions and of objects in the configuration
+and state.
+
+Type ":type" followed by an expression to see the type of its result rather
+than its value.
 
 To exit the console, type "exit" and hit <enter>, or use Control-C or
 Control-D.
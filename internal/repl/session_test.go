@@ -121,7 +121,7 @@ func TestSession_basicState(t *testing.T) {
 				{
 					Input:         "module.module.foo",
 					Error:         true,
-					ErrorContains: `Unsupported attribute: This object does not have an attribute named "foo"`,
+					ErrorContains: `Reference to undeclared output value: The module module.module has no output value named "foo".`,
 				},
 			},
 		})
@@ -258,6 +258,151 @@ func TestSession_stateless(t *testing.T) {
 			},
 		})
 	})
+
+	t.Run("type command", func(t *testing.T) {
+		testSession(t, testSessionTest{
+			Inputs: []testSessionInput{
+				{
+					Input:  `:type {name = "foo", tags = ["a"]}`,
+					Output: "object({\n    name: string,\n    tags: tuple([\n        string,\n    ]),\n})",
+				},
+				{
+					Input:  `:type tolist([1, 2])`,
+					Output: "list(number)",
+				},
+			},
+		})
+	})
+
+	t.Run("type command without expression", func(t *testing.T) {
+		testSession(t, testSessionTest{
+			Inputs: []testSessionInput{
+				{
+					Input:         `:type`,
+					Error:         true,
+					ErrorContains: "The :type command requires an expression",
+				},
+			},
+		})
+	})
+
+	t.Run("multi-line expression", func(t *testing.T) {
+		testSession(t, testSessionTest{
+			Inputs: []testSessionInput{
+				{
+					Input:  "[\n  1,\n  2,\n]",
+					Output: "[\n  1,\n  2,\n]",
+				},
+			},
+		})
+	})
+}
+
+func TestSession_handleJSON(t *testing.T) {
+	config, _, cleanup, configDiags := initwd.LoadConfigForTests(t, "testdata/config-fixture", "tests")
+	defer cleanup()
+	if configDiags.HasErrors() {
+		t.Fatalf("unexpected problems loading config: %s", configDiags.Err())
+	}
+
+	p := &tofu.MockProvider{}
+	p.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		ResourceTypes: map[string]providers.Schema{
+			"test_instance": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id": {Type: cty.String, Computed: true},
+					},
+				},
+			},
+		},
+	}
+	ctx, diags := tofu.NewContext(&tofu.ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): providers.FactoryFixed(p),
+		},
+	})
+	if diags.HasErrors() {
+		t.Fatalf("failed to create context: %s", diags.Err())
+	}
+	scope, diags := ctx.Eval(config, states.NewState(), addrs.RootModuleInstance, &tofu.EvalOpts{})
+	if diags.HasErrors() {
+		t.Fatalf("failed to create scope: %s", diags.Err())
+	}
+	scope.ConsoleMode = true
+	session := &Session{
+		Scope: scope,
+	}
+
+	tests := map[string]struct {
+		Input string
+		Want  string
+		Error string
+	}{
+		"object": {
+			Input: `{ a = 1 }`,
+			Want:  "{\n  \"sensitive\": false,\n  \"type\": [\n    \"object\",\n    {\n      \"a\": \"number\"\n    }\n  ],\n  \"value\": {\n    \"a\": 1\n  }\n}",
+		},
+		"sensitive": {
+			Input: `sensitive("secret")`,
+			Want:  "{\n  \"sensitive\": true,\n  \"type\": \"string\",\n  \"value\": \"secret\"\n}",
+		},
+		"unknown": {
+			Input: `test_instance.foo.id`,
+			Error: "Result is not yet known",
+		},
+		"type function": {
+			Input: `type("a")`,
+			Error: "Invalid use of type function",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, diags := session.HandleJSON(test.Input)
+			if test.Error != "" {
+				if !diags.HasErrors() {
+					t.Fatalf("succeeded; want error")
+				}
+				if gotErr := diags.Err().Error(); !strings.Contains(gotErr, test.Error) {
+					t.Fatalf("wrong error\ngot:  %s\nwant: %s", gotErr, test.Error)
+				}
+				return
+			}
+			if diags.HasErrors() {
+				t.Fatalf("unexpected error: %s", diags.Err())
+			}
+			if diff := cmp.Diff(test.Want, string(got)); diff != "" {
+				t.Errorf("wrong result\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIncompleteInput(t *testing.T) {
+	tests := map[string]bool{
+		``:                           false,
+		`1 + 2`:                      false,
+		`[`:                          true,
+		"[\n  1,":                    true,
+		"[\n  1,\n]":                 false,
+		`{ for k, v in var.m : k =>`: true,
+		`{ for k, v in var.m :`:      true,
+		`upper(`:                     true,
+		`"${`:                        true,
+		`"${1}"`:                     false,
+		`<<EOT`:                      true,
+		"<<EOT\nhello\nEOT":          false,
+		`]`:                          false,
+	}
+
+	for input, want := range tests {
+		t.Run(input, func(t *testing.T) {
+			if got := IncompleteInput(input); got != want {
+				t.Errorf("wrong result for %q: got %t, want %t", input, got, want)
+			}
+		})
+	}
 }
 
 func testSession(t *testing.T, test testSessionTest) {
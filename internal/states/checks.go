@@ -77,6 +77,13 @@ type CheckResultObject struct {
 	// (checks.StatusError problems get reported as normal diagnostics during
 	// evaluation instead, and so will not appear here.)
 	FailureMessages []string
+
+	// Rules is the result of each of the individual checks for the object,
+	// along with where each of them was declared.
+	//
+	// This is not preserved in state snapshots or plan files, so it's
+	// populated only for results produced by the current OpenTofu run.
+	Rules []checks.RuleResult
 }
 
 // NewCheckResults constructs a new states.CheckResults object that is a
@@ -99,6 +106,7 @@ func NewCheckResults(source *checks.State) *CheckResults {
 			obj := &CheckResultObject{
 				Status:          source.ObjectCheckStatus(objectAddr),
 				FailureMessages: source.ObjectFailureMessages(objectAddr),
+				Rules:           source.ObjectCheckRuleResults(objectAddr),
 			}
 			aggr.ObjectResults.Put(objectAddr, obj)
 		}
@@ -157,9 +165,10 @@ func (r *CheckResults) DeepCopy() *CheckResults {
 				result := &CheckResultObject{
 					Status: objectElem.Value.Status,
 
-					// NOTE: We don't deep-copy this slice because it's
+					// NOTE: We don't deep-copy these slices because they're
 					// immutable once constructed by convention.
 					FailureMessages: objectElem.Value.FailureMessages,
+					Rules:           objectElem.Value.Rules,
 				}
 				aggr.ObjectResults.Put(objectElem.Key, result)
 			}
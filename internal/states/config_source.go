@@ -0,0 +1,32 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package states
+
+// ConfigSource records where the configuration that last planned or applied
+// changes to a state came from, when it was fetched from a remote source
+// with the -config-ref option rather than read from the working directory.
+type ConfigSource struct {
+	// Address is the source address that the configuration was fetched
+	// from, including the ref.
+	Address string
+
+	// Commit is the full ID of the commit that the ref resolved to.
+	Commit string
+
+	// Verified is true if the signature of the tag that the ref names was
+	// verified before the configuration was used.
+	Verified bool
+}
+
+// DeepCopy returns a new ConfigSource that contains equivalent data to the
+// receiver but shares no backing memory in common.
+func (s *ConfigSource) DeepCopy() *ConfigSource {
+	if s == nil {
+		return nil
+	}
+	ret := *s
+	return &ret
+}
@@ -64,6 +64,11 @@ type ResourceInstanceObjectSrc struct {
 	Status              ObjectStatus
 	Dependencies        []addrs.ConfigResource
 	CreateBeforeDestroy bool
+
+	// ProviderKey is the instance key of the provider configuration instance
+	// that manages the object, if its provider configuration has for_each.
+	// It is addrs.NoKey otherwise.
+	ProviderKey addrs.InstanceKey
 }
 
 // Decode unmarshals the raw representation of the object attributes. Pass the
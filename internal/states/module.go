@@ -258,6 +258,12 @@ func (ms *Module) maybeRestoreResourceInstanceDeposed(addr addrs.ResourceInstanc
 // SetOutputValue writes an output value into the state, overwriting any
 // existing value of the same name.
 func (ms *Module) SetOutputValue(name string, value cty.Value, sensitive bool) *OutputValue {
+	return ms.SetSharedOutputValue(name, value, sensitive, nil)
+}
+
+// SetSharedOutputValue is like SetOutputValue, but also records the
+// consumers that the output value is shared with.
+func (ms *Module) SetSharedOutputValue(name string, value cty.Value, sensitive bool, sharedWith []string) *OutputValue {
 	os := &OutputValue{
 		Addr: addrs.AbsOutputValue{
 			Module: ms.Addr,
@@ -265,8 +271,9 @@ func (ms *Module) SetOutputValue(name string, value cty.Value, sensitive bool) *
 				Name: name,
 			},
 		},
-		Value:     value,
-		Sensitive: sensitive,
+		Value:      value,
+		Sensitive:  sensitive,
+		SharedWith: sharedWith,
 	}
 	ms.OutputValues[name] = os
 	return os
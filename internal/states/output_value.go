@@ -6,6 +6,8 @@
 package states
 
 import (
+	"path"
+
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -18,4 +20,20 @@ type OutputValue struct {
 	Addr      addrs.AbsOutputValue
 	Value     cty.Value
 	Sensitive bool
+
+	// SharedWith lists the consumers that can read the output value with the
+	// terraform_shared_outputs data source, as names or path.Match patterns
+	// of names. It is nil if the output value isn't shared.
+	SharedWith []string
+}
+
+// IsSharedWith returns true if the given consumer can read the output value
+// with the terraform_shared_outputs data source.
+func (os *OutputValue) IsSharedWith(consumer string) bool {
+	for _, pattern := range os.SharedWith {
+		if matched, _ := path.Match(pattern, consumer); matched {
+			return true
+		}
+	}
+	return false
 }
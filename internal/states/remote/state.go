@@ -13,6 +13,7 @@ import (
 
 	uuid "github.com/hashicorp/go-uuid"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/backend/local"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
@@ -45,6 +46,10 @@ type State struct {
 	state, readState     *states.State
 	disableLocks         bool
 
+	// partial is set when the state was read by RefreshStatePartial, and so
+	// doesn't contain all of the resources in the remote state.
+	partial bool
+
 	// If this is set then the state manager will decline to store intermediate
 	// state snapshots created while a OpenTofu Core apply operation is in
 	// progress. Otherwise (by default) it will accept persistent snapshots
@@ -93,7 +98,9 @@ func (s *State) StateForMigration() *statefile.File {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	return statefile.New(s.state.DeepCopy(), s.lineage, s.serial)
+	f := statefile.New(s.state.DeepCopy(), s.lineage, s.serial)
+	f.Partial = s.partial
+	return f
 }
 
 // statemgr.Writer impl.
@@ -143,13 +150,21 @@ func (s *State) WriteStateForMigration(f *statefile.File, force bool) error {
 func (s *State) RefreshState() error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	return s.refreshState()
+	return s.refreshState(nil)
+}
+
+// RefreshStatePartial is an implementation of statemgr.PartialRefresher.
+func (s *State) RefreshStatePartial(keep func(addrs.AbsResource) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.refreshState(keep)
 }
 
 // refreshState is the main implementation of RefreshState, but split out so
 // that we can make internal calls to it from methods that are already holding
-// the s.mu lock.
-func (s *State) refreshState() error {
+// the s.mu lock. If keep isn't nil then only the resources for which it
+// returns true are read.
+func (s *State) refreshState(keep func(addrs.AbsResource) bool) error {
 	payload, err := s.Client.Get()
 	if err != nil {
 		return err
@@ -160,13 +175,20 @@ func (s *State) refreshState() error {
 		s.readState = nil
 		s.lineage = ""
 		s.serial = 0
+		s.partial = false
 		return nil
 	}
 
-	stateFile, err := statefile.Read(bytes.NewReader(payload.Data), s.encryption)
+	var stateFile *statefile.File
+	if keep != nil {
+		stateFile, err = statefile.ReadPartial(bytes.NewReader(payload.Data), s.encryption, keep)
+	} else {
+		stateFile, err = statefile.Read(bytes.NewReader(payload.Data), s.encryption)
+	}
 	if err != nil {
 		return err
 	}
+	s.partial = stateFile.Partial
 
 	s.lineage = stateFile.Lineage
 	s.serial = stateFile.Serial
@@ -185,6 +207,10 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if s.partial {
+		return statefile.ErrPartialState
+	}
+
 	log.Printf("[DEBUG] states/remote: state read serial is: %d; serial is: %d", s.readSerial, s.serial)
 	log.Printf("[DEBUG] states/remote: state read lineage is: %s; lineage is: %s", s.readLineage, s.lineage)
 
@@ -201,7 +227,7 @@ func (s *State) PersistState(schemas *tofu.Schemas) error {
 		// We might be writing a new state altogether, but before we do that
 		// we'll check to make sure there isn't already a snapshot present
 		// that we ought to be updating.
-		err := s.refreshState()
+		err := s.refreshState(nil)
 		if err != nil {
 			return fmt.Errorf("failed checking for existing remote state: %w", err)
 		}
@@ -0,0 +1,280 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package snapshot implements the snapshots of state that OpenTofu saves in
+// the working directory just before it applies changes, so that "tofu
+// rollback" can later propose returning the infrastructure to how it was
+// before a particular apply.
+//
+// Each snapshot consists of two files in the snapshot directory: a copy of
+// the prior state, written using the normal state file format and encryption,
+// and a JSON metadata file describing the state serial and the changes that
+// were about to be applied.
+package snapshot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+// MaxSnapshots is the number of snapshots retained in each snapshot
+// directory. Saving a new snapshot deletes the oldest ones beyond this limit.
+const MaxSnapshots = 10
+
+const (
+	metaSuffix  = ".json"
+	stateSuffix = ".tfstate"
+)
+
+var idRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// Snapshot describes a snapshot of state saved before an apply.
+type Snapshot struct {
+	// ID uniquely identifies the snapshot within its directory. It is made
+	// of the time the snapshot was saved, the state serial, and a random
+	// suffix so that snapshots saved within the same second, such as by
+	// concurrent applies to different states, never overwrite each other.
+	ID string `json:"id"`
+
+	// CreatedAt is the time the snapshot was saved.
+	CreatedAt time.Time `json:"created_at"`
+
+	// Lineage and Serial identify the state snapshot that was current
+	// before the apply.
+	Lineage string `json:"lineage"`
+	Serial  uint64 `json:"serial"`
+
+	// PlanMode is the planning mode of the plan that was applied.
+	PlanMode string `json:"plan_mode"`
+
+	// Changes summarizes the resource instance changes in the plan that
+	// was applied.
+	Changes []Change `json:"changes"`
+
+	dir string
+}
+
+// Change summarizes a single planned resource instance change.
+type Change struct {
+	Address string `json:"address"`
+	Action  string `json:"action"`
+}
+
+// Save writes a snapshot of the given state file to dir, along with metadata
+// describing the given plan, and then deletes any snapshots in dir beyond
+// the most recent MaxSnapshots.
+func Save(dir string, file *statefile.File, plan *plans.Plan, enc encryption.StateEncryption, now time.Time) (*Snapshot, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot directory: %w", err)
+	}
+
+	snap := &Snapshot{
+		ID:        fmt.Sprintf("%s-%d-%08x", now.UTC().Format("20060102T150405Z"), file.Serial, idRand.Uint32()),
+		CreatedAt: now.UTC(),
+		Lineage:   file.Lineage,
+		Serial:    file.Serial,
+		PlanMode:  plan.UIMode.String(),
+		dir:       dir,
+	}
+	for _, change := range plan.Changes.Resources {
+		if change.Action == plans.NoOp && change.Importing == nil {
+			continue
+		}
+		addr := change.Addr.String()
+		if change.DeposedKey != states.NotDeposed {
+			addr = fmt.Sprintf("%s (deposed object %s)", addr, change.DeposedKey)
+		}
+		snap.Changes = append(snap.Changes, Change{
+			Address: addr,
+			Action:  change.Action.String(),
+		})
+	}
+	sort.Slice(snap.Changes, func(i, j int) bool {
+		return snap.Changes[i].Address < snap.Changes[j].Address
+	})
+
+	var buf bytes.Buffer
+	if err := statefile.Write(file, &buf, enc); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot state: %w", err)
+	}
+	if err := os.WriteFile(snap.path(stateSuffix), buf.Bytes(), 0600); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot state: %w", err)
+	}
+
+	meta, err := json.MarshalIndent(snap, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode snapshot metadata: %w", err)
+	}
+	if err := os.WriteFile(snap.path(metaSuffix), meta, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write snapshot metadata: %w", err)
+	}
+
+	all, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, old := range all[min(len(all), MaxSnapshots):] {
+		if err := old.remove(); err != nil {
+			return nil, err
+		}
+	}
+
+	return snap, nil
+}
+
+// List returns all of the snapshots in the given directory, newest first.
+// A directory that doesn't exist contains no snapshots.
+func List(dir string) ([]*Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot directory: %w", err)
+	}
+
+	var ret []*Snapshot
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), metaSuffix) {
+			continue
+		}
+		src, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot metadata: %w", err)
+		}
+		snap := &Snapshot{dir: dir}
+		if err := json.Unmarshal(src, snap); err != nil {
+			return nil, fmt.Errorf("invalid snapshot metadata in %s: %w", entry.Name(), err)
+		}
+		ret = append(ret, snap)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if !ret[i].CreatedAt.Equal(ret[j].CreatedAt) {
+			return ret[i].CreatedAt.After(ret[j].CreatedAt)
+		}
+		return ret[i].ID > ret[j].ID
+	})
+	return ret, nil
+}
+
+// Find returns the snapshot with the given ID from the given directory, or
+// the most recent snapshot if id is empty.
+func Find(dir, id string) (*Snapshot, error) {
+	all, err := List(dir)
+	if err != nil {
+		return nil, err
+	}
+	if len(all) == 0 {
+		return nil, fmt.Errorf("there are no saved snapshots")
+	}
+	if id == "" {
+		return all[0], nil
+	}
+	for _, snap := range all {
+		if snap.ID == id {
+			return snap, nil
+		}
+	}
+	return nil, fmt.Errorf("there is no snapshot with the id %q", id)
+}
+
+// State reads the state saved in the snapshot.
+func (s *Snapshot) State(enc encryption.StateEncryption) (*statefile.File, error) {
+	f, err := os.Open(s.path(stateSuffix))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot state: %w", err)
+	}
+	defer f.Close()
+	return statefile.Read(f, enc)
+}
+
+func (s *Snapshot) path(suffix string) string {
+	return filepath.Join(s.dir, s.ID+suffix)
+}
+
+func (s *Snapshot) remove() error {
+	for _, suffix := range []string{stateSuffix, metaSuffix} {
+		if err := os.Remove(s.path(suffix)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove old snapshot %s: %w", s.ID, err)
+		}
+	}
+	return nil
+}
+
+// ManualStep describes a difference between a snapshot and the current state
+// that OpenTofu can't reverse automatically.
+type ManualStep struct {
+	Addr   addrs.AbsResourceInstance
+	Reason string
+}
+
+// Diff compares the state saved in a snapshot with the current state.
+//
+// It returns the managed resource instances that were created since the
+// snapshot was taken, which can be rolled back by destroying them, and a
+// description of each other difference, which must be rolled back manually.
+// Rollback is create-only: the snapshot records the prior state but not the
+// configuration that produced it, so there is no way to plan an update or
+// recreation that returns an object to its earlier settings.
+func Diff(snapshot, current *states.State) ([]addrs.AbsResourceInstance, []ManualStep) {
+	var created []addrs.AbsResourceInstance
+	var manual []ManualStep
+
+	for _, addr := range managedInstances(current) {
+		if currentObject(snapshot, addr) == nil {
+			created = append(created, addr)
+		}
+	}
+
+	for _, addr := range managedInstances(snapshot) {
+		prev := currentObject(snapshot, addr)
+		now := currentObject(current, addr)
+		switch {
+		case now == nil:
+			manual = append(manual, ManualStep{
+				Addr:   addr,
+				Reason: "was destroyed after the snapshot was taken, and OpenTofu cannot recreate the original object",
+			})
+		case !bytes.Equal(prev.AttrsJSON, now.AttrsJSON):
+			manual = append(manual, ManualStep{
+				Addr:   addr,
+				Reason: "was changed after the snapshot was taken; apply the configuration that was in use at that time to restore it",
+			})
+		}
+	}
+
+	return created, manual
+}
+
+func managedInstances(state *states.State) []addrs.AbsResourceInstance {
+	var ret []addrs.AbsResourceInstance
+	for _, obj := range state.AllResourceInstanceObjectAddrs() {
+		if obj.DeposedKey == states.NotDeposed {
+			ret = append(ret, obj.Instance)
+		}
+	}
+	return ret
+}
+
+func currentObject(state *states.State, addr addrs.AbsResourceInstance) *states.ResourceInstanceObjectSrc {
+	is := state.ResourceInstance(addr)
+	if is == nil {
+		return nil
+	}
+	return is.Current
+}
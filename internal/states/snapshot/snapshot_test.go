@@ -0,0 +1,174 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package snapshot
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/states/statefile"
+)
+
+func TestSave(t *testing.T) {
+	dir := t.TempDir()
+	enc := encryption.StateEncryptionDisabled()
+	addr := mustResourceInstanceAddr("test_thing.a")
+
+	state := states.BuildState(func(s *states.SyncState) {
+		setObject(s, addr, `{"id":"a"}`)
+	})
+	file := statefile.New(state, "lineage", 4)
+	plan := &plans.Plan{
+		UIMode: plans.NormalMode,
+		Changes: &plans.Changes{
+			Resources: []*plans.ResourceInstanceChangeSrc{
+				{
+					Addr:      addr,
+					ChangeSrc: plans.ChangeSrc{Action: plans.Update},
+				},
+				{
+					Addr:      mustResourceInstanceAddr("test_thing.b"),
+					ChangeSrc: plans.ChangeSrc{Action: plans.NoOp},
+				},
+			},
+		},
+	}
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < MaxSnapshots+2; i++ {
+		file.Serial = uint64(i)
+		if _, err := Save(dir, file, plan, enc, start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(all), MaxSnapshots; got != want {
+		t.Fatalf("wrong number of snapshots %d; want %d", got, want)
+	}
+	if got, want := all[0].ID, "20240102T031505Z-11-"; !strings.HasPrefix(got, want) {
+		t.Errorf("wrong newest snapshot %q; want prefix %q", got, want)
+	}
+	if got, want := all[len(all)-1].ID, "20240102T030605Z-2-"; !strings.HasPrefix(got, want) {
+		t.Errorf("wrong oldest snapshot %q; want prefix %q", got, want)
+	}
+
+	snap, err := Find(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snap.Serial != 11 || snap.Lineage != "lineage" || snap.PlanMode != "NormalMode" {
+		t.Errorf("wrong metadata %#v", snap)
+	}
+	if len(snap.Changes) != 1 || snap.Changes[0] != (Change{Address: "test_thing.a", Action: "Update"}) {
+		t.Errorf("wrong changes %#v", snap.Changes)
+	}
+
+	got, err := snap.State(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !statefile.StatesMarshalEqual(got.State, state) {
+		t.Errorf("wrong state\n%s", got.State)
+	}
+
+	if _, err := Find(dir, all[len(all)-1].ID); err != nil {
+		t.Errorf("unexpected error finding oldest snapshot: %s", err)
+	}
+	if _, err := Find(dir, "20240102T030405Z-0"); err == nil {
+		t.Errorf("expected pruned snapshot to be missing")
+	}
+	if _, err := Find(t.TempDir(), ""); err == nil {
+		t.Errorf("expected error for empty snapshot directory")
+	}
+}
+
+func TestSave_sameSecond(t *testing.T) {
+	dir := t.TempDir()
+	enc := encryption.StateEncryptionDisabled()
+	file := statefile.New(states.NewState(), "lineage", 1)
+	plan := &plans.Plan{UIMode: plans.NormalMode, Changes: plans.NewChanges()}
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	first, err := Save(dir, file, plan, enc, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := Save(dir, file, plan, enc, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("snapshots saved in the same second have the same id %q", first.ID)
+	}
+
+	all, err := List(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("wrong number of snapshots %d; want 2", len(all))
+	}
+}
+
+func TestDiff(t *testing.T) {
+	unchanged := mustResourceInstanceAddr("test_thing.unchanged")
+	updated := mustResourceInstanceAddr("test_thing.updated")
+	destroyed := mustResourceInstanceAddr("test_thing.destroyed")
+	created := mustResourceInstanceAddr("test_thing.created")
+
+	snapshot := states.BuildState(func(s *states.SyncState) {
+		setObject(s, unchanged, `{"id":"u"}`)
+		setObject(s, updated, `{"id":"v1"}`)
+		setObject(s, destroyed, `{"id":"d"}`)
+	})
+	current := states.BuildState(func(s *states.SyncState) {
+		setObject(s, unchanged, `{"id":"u"}`)
+		setObject(s, updated, `{"id":"v2"}`)
+		setObject(s, created, `{"id":"c"}`)
+	})
+
+	gotCreated, gotManual := Diff(snapshot, current)
+	if len(gotCreated) != 1 || !gotCreated[0].Equal(created) {
+		t.Errorf("wrong created instances %s", gotCreated)
+	}
+	if len(gotManual) != 2 {
+		t.Fatalf("wrong number of manual steps %d; want 2", len(gotManual))
+	}
+	if !gotManual[0].Addr.Equal(destroyed) || !gotManual[1].Addr.Equal(updated) {
+		t.Errorf("wrong manual steps %#v", gotManual)
+	}
+}
+
+func setObject(s *states.SyncState, addr addrs.AbsResourceInstance, attrs string) {
+	s.SetResourceInstanceCurrent(
+		addr,
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(attrs),
+		},
+		addrs.AbsProviderConfig{
+			Provider: addrs.NewDefaultProvider("test"),
+			Module:   addrs.RootModule,
+		},
+	)
+}
+
+func mustResourceInstanceAddr(s string) addrs.AbsResourceInstance {
+	addr, diags := addrs.ParseAbsResourceInstanceStr(s)
+	if diags.HasErrors() {
+		panic(diags.Err())
+	}
+	return addr
+}
@@ -43,6 +43,19 @@ type State struct {
 	// created by a version of OpenTofu that didn't yet support checks
 	// then this field will be nil.
 	CheckResults *CheckResults
+
+	// WorkspaceMeta is the metadata of the workspace this state belongs to,
+	// or nil if none has been set.
+	WorkspaceMeta *WorkspaceMeta
+
+	// WorkspaceVariables are the values for root module input variables
+	// stored with the workspace this state belongs to, by variable name.
+	WorkspaceVariables map[string]*WorkspaceVariable
+
+	// ConfigSource records where the configuration that produced this
+	// state was fetched from, or is nil if it was read from the working
+	// directory.
+	ConfigSource *ConfigSource
 }
 
 // NewState constructs a minimal empty state, containing an empty root module.
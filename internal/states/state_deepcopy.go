@@ -33,9 +33,19 @@ func (s *State) DeepCopy() *State {
 	for k, m := range s.Modules {
 		modules[k] = m.DeepCopy()
 	}
+	var workspaceVariables map[string]*WorkspaceVariable
+	if s.WorkspaceVariables != nil {
+		workspaceVariables = make(map[string]*WorkspaceVariable, len(s.WorkspaceVariables))
+		for k, v := range s.WorkspaceVariables {
+			workspaceVariables[k] = v.DeepCopy()
+		}
+	}
 	return &State{
-		Modules:      modules,
-		CheckResults: s.CheckResults.DeepCopy(),
+		Modules:            modules,
+		CheckResults:       s.CheckResults.DeepCopy(),
+		WorkspaceMeta:      s.WorkspaceMeta.DeepCopy(),
+		WorkspaceVariables: workspaceVariables,
+		ConfigSource:       s.ConfigSource.DeepCopy(),
 	}
 }
 
@@ -179,6 +189,7 @@ func (os *ResourceInstanceObjectSrc) DeepCopy() *ResourceInstanceObjectSrc {
 		AttrSensitivePaths:  attrPaths,
 		Dependencies:        dependencies,
 		CreateBeforeDestroy: os.CreateBeforeDestroy,
+		ProviderKey:         os.ProviderKey,
 	}
 }
 
@@ -231,9 +242,16 @@ func (os *OutputValue) DeepCopy() *OutputValue {
 		return nil
 	}
 
+	var sharedWith []string
+	if os.SharedWith != nil {
+		sharedWith = make([]string, len(os.SharedWith))
+		copy(sharedWith, os.SharedWith)
+	}
+
 	return &OutputValue{
-		Addr:      os.Addr,
-		Value:     os.Value,
-		Sensitive: os.Sensitive,
+		Addr:       os.Addr,
+		Value:      os.Value,
+		Sensitive:  os.Sensitive,
+		SharedWith: sharedWith,
 	}
 }
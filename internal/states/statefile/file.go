@@ -34,6 +34,11 @@ type File struct {
 
 	// State is the actual state represented by this file.
 	State *states.State
+
+	// Partial is set for a file read by ReadPartial, whose State contains
+	// only some of the resources in the file. A partial file can't be
+	// written, because that would lose the resources that weren't read.
+	Partial bool
 }
 
 func New(state *states.State, lineage string, serial uint64) *File {
@@ -63,5 +68,6 @@ func (f *File) DeepCopy() *File {
 		Serial:           f.Serial,
 		Lineage:          f.Lineage,
 		State:            f.State.DeepCopy(),
+		Partial:          f.Partial,
 	}
 }
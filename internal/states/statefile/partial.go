@@ -0,0 +1,197 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statefile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/encryption"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// ErrPartialState is returned when attempting to write a state file that was
+// read by ReadPartial.
+var ErrPartialState = errors.New("cannot write a state that was only partially read")
+
+// ReadPartial is like Read, but the resulting state contains only the
+// resources for which the given function returns true.
+//
+// The instances of the other resources are skipped without being decoded,
+// so that reading a few resources from a large state uses memory in
+// proportion to the resources that are kept rather than to the whole state.
+// Root module outputs and check results are always read.
+//
+// The result has Partial set, and so can't be written back out again. This
+// is intended only for callers that read the state without changing it.
+func ReadPartial(r io.Reader, enc encryption.StateEncryption, keep func(addrs.AbsResource) bool) (*File, error) {
+	return read(r, enc, keep)
+}
+
+// partialResourceStateV4 is the part of resourceStateV4 that identifies the
+// resource, with the instances left undecoded.
+type partialResourceStateV4 struct {
+	Module string `json:"module,omitempty"`
+	Mode   string `json:"mode"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+}
+
+// readStateV4Partial is like readStateV4, but fully decodes only the
+// resources for which keep returns true.
+//
+// The resources array is read one element at a time, so that the skipped
+// resources never all need to be in memory at once.
+func readStateV4Partial(src []byte, keep func(addrs.AbsResource) bool) (*File, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	var resources []resourceStateV4
+	others := make(map[string]json.RawMessage)
+
+	dec := json.NewDecoder(bytes.NewReader(src))
+	err := expectJSONDelim(dec, '{')
+	for err == nil && dec.More() {
+		var tok json.Token
+		tok, err = dec.Token()
+		if err != nil {
+			break
+		}
+		key, _ := tok.(string)
+		if key != "resources" {
+			var raw json.RawMessage
+			err = dec.Decode(&raw)
+			others[key] = raw
+			continue
+		}
+
+		tok, err = dec.Token()
+		if err != nil || tok == nil {
+			// A null resources property is the same as an empty one.
+			continue
+		}
+		if tok != json.Delim('[') {
+			err = jsonDelimError(dec, tok)
+			break
+		}
+		for err == nil && dec.More() {
+			var raw json.RawMessage
+			if err = dec.Decode(&raw); err != nil {
+				break
+			}
+			var header partialResourceStateV4
+			if err = json.Unmarshal(raw, &header); err != nil {
+				break
+			}
+			if addr, ok := header.addr(); ok && !keep(addr) {
+				continue
+			}
+			// Resources with invalid addresses are kept, so that
+			// prepareStateV4 reports the same errors as for a full read.
+			var rs resourceStateV4
+			if err = json.Unmarshal(raw, &rs); err != nil {
+				break
+			}
+			resources = append(resources, rs)
+		}
+		if err == nil {
+			err = expectJSONDelim(dec, ']')
+		}
+	}
+	if err != nil {
+		diags = diags.Append(jsonUnmarshalDiags(err))
+		return nil, diags
+	}
+
+	// The remaining top-level properties are small compared to the
+	// resources, so we decode them by putting them back together.
+	rest, err := json.Marshal(others)
+	if err != nil {
+		diags = diags.Append(jsonUnmarshalDiags(err))
+		return nil, diags
+	}
+	sV4 := &stateV4{}
+	if err := json.Unmarshal(rest, sV4); err != nil {
+		diags = diags.Append(jsonUnmarshalDiags(err))
+		return nil, diags
+	}
+	sV4.Resources = resources
+
+	file, prepDiags := prepareStateV4(sV4)
+	diags = diags.Append(prepDiags)
+	if file != nil {
+		file.Partial = true
+	}
+	return file, diags
+}
+
+// addr returns the address of the resource, if it's valid.
+func (rs *partialResourceStateV4) addr() (addrs.AbsResource, bool) {
+	ret := addrs.AbsResource{
+		Module: addrs.RootModuleInstance,
+		Resource: addrs.Resource{
+			Type: rs.Type,
+			Name: rs.Name,
+		},
+	}
+	switch rs.Mode {
+	case "managed":
+		ret.Resource.Mode = addrs.ManagedResourceMode
+	case "data":
+		ret.Resource.Mode = addrs.DataResourceMode
+	default:
+		return ret, false
+	}
+	if rs.Module != "" {
+		module, diags := addrs.ParseModuleInstanceStr(rs.Module)
+		if diags.HasErrors() {
+			return ret, false
+		}
+		ret.Module = module
+	}
+	return ret, true
+}
+
+func expectJSONDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return jsonDelimError(dec, tok)
+	}
+	return nil
+}
+
+// jsonDelimError returns an error for an unexpected token where the start
+// or end of an object or array is expected.
+func jsonDelimError(dec *json.Decoder, tok json.Token) error {
+	return &json.UnmarshalTypeError{
+		Value:  fmt.Sprintf("%v", tok),
+		Offset: dec.InputOffset(),
+	}
+}
+
+// filterResources removes the resources for which keep returns false from
+// the given file's state, and marks it as partial.
+func filterResources(file *File, keep func(addrs.AbsResource) bool) {
+	for _, ms := range file.State.Modules {
+		for _, rs := range ms.Resources {
+			if !keep(rs.Addr) {
+				ms.RemoveResource(rs.Addr.Resource)
+			}
+		}
+		// As in a partial read of the current format, only modules that
+		// contain some of the kept resources remain.
+		if !ms.Addr.IsRoot() && len(ms.Resources) == 0 {
+			file.State.RemoveModule(ms.Addr)
+		}
+	}
+	file.Partial = true
+}
@@ -0,0 +1,114 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statefile
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/encryption"
+)
+
+func TestReadPartial(t *testing.T) {
+	tests := map[string]struct {
+		keep func(addrs.AbsResource) bool
+		want []string
+	}{
+		"module": {
+			keep: func(rs addrs.AbsResource) bool {
+				return rs.Module.String() == "module.modB"
+			},
+			want: []string{"module.modB.null_resource.bar"},
+		},
+		"root resource": {
+			keep: func(rs addrs.AbsResource) bool {
+				return rs.String() == "null_resource.bar"
+			},
+			want: []string{"null_resource.bar"},
+		},
+		"nothing": {
+			keep: func(addrs.AbsResource) bool {
+				return false
+			},
+			want: nil,
+		},
+	}
+
+	src, err := os.ReadFile("testdata/roundtrip/v4-modules.in.tfstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	full, err := Read(bytes.NewReader(src), encryption.StateEncryptionDisabled())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ReadPartial(bytes.NewReader(src), encryption.StateEncryptionDisabled(), test.keep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Partial {
+				t.Error("result is not marked as partial")
+			}
+			if got.Lineage != full.Lineage || got.Serial != full.Serial {
+				t.Errorf("wrong metadata %s %d", got.Lineage, got.Serial)
+			}
+			if len(got.State.RootModule().OutputValues) != 1 {
+				t.Error("root module outputs were not read")
+			}
+
+			var addrs []string
+			for _, ms := range got.State.Modules {
+				for _, rs := range ms.Resources {
+					addrs = append(addrs, rs.Addr.String())
+					// The resources that are kept are read in full.
+					if diff := cmp.Diff(full.State.Resource(rs.Addr), rs); diff != "" {
+						t.Errorf("wrong resource %s\n%s", rs.Addr, diff)
+					}
+				}
+			}
+			if diff := cmp.Diff(test.want, addrs); diff != "" {
+				t.Errorf("wrong resources\n%s", diff)
+			}
+
+			var buf bytes.Buffer
+			if err := Write(got, &buf, encryption.StateEncryptionDisabled()); !errors.Is(err, ErrPartialState) {
+				t.Errorf("wrong error writing partial state: %v", err)
+			}
+		})
+	}
+}
+
+func TestReadPartial_legacy(t *testing.T) {
+	f, err := os.Open("testdata/roundtrip/v3-grabbag.in.tfstate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	got, err := ReadPartial(f, encryption.StateEncryptionDisabled(), func(rs addrs.AbsResource) bool {
+		return rs.String() == "null_resource.baz"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Partial {
+		t.Error("result is not marked as partial")
+	}
+	if len(got.State.Modules) != 1 {
+		t.Errorf("wrong number of modules %d", len(got.State.Modules))
+	}
+	if rs := got.State.RootModule().Resources; len(rs) != 1 || rs["null_resource.baz"] == nil {
+		t.Errorf("wrong resources %#v", rs)
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	version "github.com/hashicorp/go-version"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	tfversion "github.com/opentofu/opentofu/version"
@@ -51,6 +52,10 @@ func (e *ErrUnusableState) Unwrap() error {
 // Otherwise, the returned error might be a wrapper around tfdiags.Diagnostics
 // potentially describing multiple errors.
 func Read(r io.Reader, enc encryption.StateEncryption) (*File, error) {
+	return read(r, enc, nil)
+}
+
+func read(r io.Reader, enc encryption.StateEncryption, keep func(addrs.AbsResource) bool) (*File, error) {
 	// Some callers provide us a "typed nil" *os.File here, which would
 	// cause us to panic below if we tried to use it.
 	if f, ok := r.(*os.File); ok && f == nil {
@@ -81,7 +86,7 @@ func Read(r io.Reader, enc encryption.StateEncryption) (*File, error) {
 		return nil, err
 	}
 
-	state, err := readState(decrypted)
+	state, err := readState(decrypted, keep)
 	if err != nil {
 		return nil, err
 	}
@@ -94,7 +99,9 @@ func Read(r io.Reader, enc encryption.StateEncryption) (*File, error) {
 	return state, diags.Err()
 }
 
-func readState(src []byte) (*File, error) {
+// readState reads the given state source, keeping only the resources for
+// which keep returns true if it's not nil.
+func readState(src []byte, keep func(addrs.AbsResource) bool) (*File, error) {
 	var diags tfdiags.Diagnostics
 
 	if looksLikeVersion0(src) {
@@ -134,7 +141,11 @@ func readState(src []byte) (*File, error) {
 	case 3:
 		result, diags = readStateV3(src)
 	case 4:
-		result, diags = readStateV4(src)
+		if keep != nil {
+			result, diags = readStateV4Partial(src, keep)
+		} else {
+			result, diags = readStateV4(src)
+		}
 	default:
 		thisVersion := tfversion.SemVer.String()
 		creatingVersion := sniffJSONStateTerraformVersion(src)
@@ -158,6 +169,12 @@ func readState(src []byte) (*File, error) {
 		err = errUnusable(diags.Err())
 	}
 
+	// The legacy formats are always read in full, so the resources that
+	// aren't wanted are only removed afterwards.
+	if err == nil && keep != nil && !result.Partial {
+		filterResources(result, keep)
+	}
+
 	return result, err
 }
 
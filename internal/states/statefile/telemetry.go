@@ -0,0 +1,43 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package statefile
+
+import (
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var stateSize metric.Int64Histogram
+
+func init() {
+	meter := otel.Meter("github.com/opentofu/opentofu/internal/states/statefile")
+	stateSize, _ = meter.Int64Histogram(
+		"tofu.state.size",
+		metric.WithDescription("Size of each state snapshot written."),
+		metric.WithUnit("By"),
+		metric.WithExplicitBucketBoundaries(1<<10, 10<<10, 100<<10, 1<<20, 10<<20, 100<<20),
+	)
+}
+
+// countingWriter counts the bytes written through it, so that the size of
+// the serialized state can be recorded.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+func recordStateSize(size int64) {
+	stateSize.Record(context.Background(), size)
+}
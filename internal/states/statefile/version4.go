@@ -143,6 +143,9 @@ func prepareStateV4(sV4 *stateV4) (*File, tfdiags.Diagnostics) {
 				SchemaVersion:       isV4.SchemaVersion,
 				CreateBeforeDestroy: isV4.CreateBeforeDestroy,
 			}
+			if isV4.ProviderKey != "" {
+				obj.ProviderKey = addrs.StringKey(isV4.ProviderKey)
+			}
 
 			{
 				// Instance attributes
@@ -273,6 +276,7 @@ func prepareStateV4(sV4 *stateV4) (*File, tfdiags.Diagnostics) {
 				},
 			}
 			os.Sensitive = fos.Sensitive
+			os.SharedWith = fos.SharedWith
 
 			ty, err := ctyjson.UnmarshalType([]byte(fos.ValueTypeRaw))
 			if err != nil {
@@ -310,6 +314,31 @@ func prepareStateV4(sV4 *stateV4) (*File, tfdiags.Diagnostics) {
 		diags = diags.Append(moreDiags)
 	}
 
+	if sV4.WorkspaceMeta != nil {
+		state.WorkspaceMeta = &states.WorkspaceMeta{
+			Description: sV4.WorkspaceMeta.Description,
+			Tags:        sV4.WorkspaceMeta.Tags,
+		}
+	}
+
+	if len(sV4.WorkspaceVariables) != 0 {
+		state.WorkspaceVariables = make(map[string]*states.WorkspaceVariable, len(sV4.WorkspaceVariables))
+		for name, v := range sV4.WorkspaceVariables {
+			state.WorkspaceVariables[name] = &states.WorkspaceVariable{
+				Value:     v.Value,
+				Sensitive: v.Sensitive,
+			}
+		}
+	}
+
+	if sV4.ConfigSource != nil {
+		state.ConfigSource = &states.ConfigSource{
+			Address:  sV4.ConfigSource.Address,
+			Commit:   sV4.ConfigSource.Commit,
+			Verified: sV4.ConfigSource.Verified,
+		}
+	}
+
 	file.State = state
 	return file, diags
 }
@@ -364,6 +393,7 @@ func writeStateV4(file *File, w io.Writer, enc encryption.StateEncryption) tfdia
 
 		sV4.RootOutputs[name] = outputStateV4{
 			Sensitive:    os.Sensitive,
+			SharedWith:   os.SharedWith,
 			ValueRaw:     json.RawMessage(src),
 			ValueTypeRaw: json.RawMessage(typeSrc),
 		}
@@ -422,6 +452,31 @@ func writeStateV4(file *File, w io.Writer, enc encryption.StateEncryption) tfdia
 
 	sV4.CheckResults = encodeCheckResultsV4(file.State.CheckResults)
 
+	if meta := file.State.WorkspaceMeta; !meta.Empty() {
+		sV4.WorkspaceMeta = &workspaceMetaV4{
+			Description: meta.Description,
+			Tags:        meta.Tags,
+		}
+	}
+
+	if len(file.State.WorkspaceVariables) != 0 {
+		sV4.WorkspaceVariables = make(map[string]workspaceVariableV4, len(file.State.WorkspaceVariables))
+		for name, v := range file.State.WorkspaceVariables {
+			sV4.WorkspaceVariables[name] = workspaceVariableV4{
+				Value:     v.Value,
+				Sensitive: v.Sensitive,
+			}
+		}
+	}
+
+	if src := file.State.ConfigSource; src != nil {
+		sV4.ConfigSource = &configSourceV4{
+			Address:  src.Address,
+			Commit:   src.Commit,
+			Verified: src.Verified,
+		}
+	}
+
 	sV4.normalize()
 
 	src, err := json.Marshal(sV4)
@@ -495,6 +550,13 @@ func appendInstanceObjectStateV4(rs *states.Resource, is *states.ResourceInstanc
 		}
 	}
 
+	// Provider configuration instance keys are always strings, because
+	// provider configurations support only for_each.
+	var providerKey string
+	if tk, ok := obj.ProviderKey.(addrs.StringKey); ok {
+		providerKey = string(tk)
+	}
+
 	// Extract paths from path value marks
 	var paths []cty.Path
 	for _, vm := range obj.AttrSensitivePaths {
@@ -516,6 +578,7 @@ func appendInstanceObjectStateV4(rs *states.Resource, is *states.ResourceInstanc
 		PrivateRaw:              privateRaw,
 		Dependencies:            deps,
 		CreateBeforeDestroy:     obj.CreateBeforeDestroy,
+		ProviderKey:             providerKey,
 	}), diags
 }
 
@@ -679,13 +742,16 @@ func encodeCheckableObjectKindV4(in addrs.CheckableKind) string {
 }
 
 type stateV4 struct {
-	Version          stateVersionV4           `json:"version"`
-	TerraformVersion string                   `json:"terraform_version"`
-	Serial           uint64                   `json:"serial"`
-	Lineage          string                   `json:"lineage"`
-	RootOutputs      map[string]outputStateV4 `json:"outputs"`
-	Resources        []resourceStateV4        `json:"resources"`
-	CheckResults     []checkResultsV4         `json:"check_results"`
+	Version            stateVersionV4                 `json:"version"`
+	TerraformVersion   string                         `json:"terraform_version"`
+	Serial             uint64                         `json:"serial"`
+	Lineage            string                         `json:"lineage"`
+	RootOutputs        map[string]outputStateV4       `json:"outputs"`
+	Resources          []resourceStateV4              `json:"resources"`
+	CheckResults       []checkResultsV4               `json:"check_results"`
+	WorkspaceMeta      *workspaceMetaV4               `json:"workspace_meta,omitempty"`
+	WorkspaceVariables map[string]workspaceVariableV4 `json:"workspace_variables,omitempty"`
+	ConfigSource       *configSourceV4                `json:"config_source,omitempty"`
 }
 
 // normalize makes some in-place changes to normalize the way items are
@@ -702,6 +768,7 @@ type outputStateV4 struct {
 	ValueRaw     json.RawMessage `json:"value"`
 	ValueTypeRaw json.RawMessage `json:"type"`
 	Sensitive    bool            `json:"sensitive,omitempty"`
+	SharedWith   []string        `json:"shared_with,omitempty"`
 }
 
 type resourceStateV4 struct {
@@ -729,6 +796,24 @@ type instanceObjectStateV4 struct {
 	Dependencies []string `json:"dependencies,omitempty"`
 
 	CreateBeforeDestroy bool `json:"create_before_destroy,omitempty"`
+
+	ProviderKey string `json:"provider_key,omitempty"`
+}
+
+type workspaceMetaV4 struct {
+	Description string            `json:"description,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+type workspaceVariableV4 struct {
+	Value     string `json:"value"`
+	Sensitive bool   `json:"sensitive,omitempty"`
+}
+
+type configSourceV4 struct {
+	Address  string `json:"address"`
+	Commit   string `json:"commit,omitempty"`
+	Verified bool   `json:"verified,omitempty"`
 }
 
 type checkResultsV4 struct {
@@ -14,11 +14,21 @@ import (
 
 // Write writes the given state to the given writer in the current state
 // serialization format.
+//
+// A file read by ReadPartial can't be written, and returns ErrPartialState.
 func Write(s *File, w io.Writer, enc encryption.StateEncryption) error {
+	if s.Partial {
+		return ErrPartialState
+	}
+
 	// Always record the current tofu version in the state.
 	s.TerraformVersion = tfversion.SemVer
 
-	diags := writeStateV4(s, w, enc)
+	cw := &countingWriter{w: w}
+	diags := writeStateV4(s, cw, enc)
+	if !diags.HasErrors() {
+		recordStateSize(cw.n)
+	}
 	return diags.Err()
 }
 
@@ -18,6 +18,7 @@ import (
 
 	multierror "github.com/hashicorp/go-multierror"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/states/statefile"
@@ -130,7 +131,7 @@ func (s *Filesystem) WriteState(state *states.State) error {
 	defer s.mutex()()
 
 	if s.readFile == nil {
-		err := s.refreshState()
+		err := s.refreshState(nil)
 		if err != nil {
 			return err
 		}
@@ -164,6 +165,12 @@ func (s *Filesystem) PersistState(schemas *tofu.Schemas) error {
 }
 
 func (s *Filesystem) persistState(schemas *tofu.Schemas) error {
+	// Check this before the output file is truncated below, so that the
+	// existing snapshot is left intact.
+	if s.file != nil && s.file.Partial {
+		return statefile.ErrPartialState
+	}
+
 	// TODO: this should use a more robust method of writing state, by first
 	// writing to a temp file on the same filesystem, and renaming the file over
 	// the original.
@@ -246,7 +253,13 @@ func (s *Filesystem) persistState(schemas *tofu.Schemas) error {
 // RefreshState is an implementation of Refresher.
 func (s *Filesystem) RefreshState() error {
 	defer s.mutex()()
-	return s.refreshState()
+	return s.refreshState(nil)
+}
+
+// RefreshStatePartial is an implementation of PartialRefresher.
+func (s *Filesystem) RefreshStatePartial(keep func(addrs.AbsResource) bool) error {
+	defer s.mutex()()
+	return s.refreshState(keep)
 }
 
 func (s *Filesystem) GetRootOutputValues() (map[string]*states.OutputValue, error) {
@@ -263,7 +276,9 @@ func (s *Filesystem) GetRootOutputValues() (map[string]*states.OutputValue, erro
 	return state.RootModule().OutputValues, nil
 }
 
-func (s *Filesystem) refreshState() error {
+// refreshState reads the latest snapshot, keeping only the resources for
+// which keep returns true if it's not nil.
+func (s *Filesystem) refreshState(keep func(addrs.AbsResource) bool) error {
 	var reader io.Reader
 
 	// The s.readPath file is only OK to read if we have not written any state out
@@ -306,7 +321,13 @@ func (s *Filesystem) refreshState() error {
 		reader = s.stateFileOut
 	}
 
-	f, err := statefile.Read(reader, s.encryption)
+	var f *statefile.File
+	var err error
+	if keep != nil {
+		f, err = statefile.ReadPartial(reader, s.encryption, keep)
+	} else {
+		f, err = statefile.Read(reader, s.encryption)
+	}
 	// if there's no state then a nil file is fine
 	if err != nil {
 		if err != statefile.ErrNoState {
@@ -436,7 +457,7 @@ func (s *Filesystem) WriteStateForMigration(f *statefile.File, force bool) error
 	defer s.mutex()()
 
 	if s.readFile == nil {
-		err := s.refreshState()
+		err := s.refreshState(nil)
 		if err != nil {
 			return err
 		}
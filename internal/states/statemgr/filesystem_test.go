@@ -6,6 +6,7 @@
 package statemgr
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -441,6 +442,39 @@ func TestFilesystem_GetRootOutputValues(t *testing.T) {
 	}
 }
 
+func TestFilesystem_refreshStatePartial(t *testing.T) {
+	defer testOverrideVersion(t, "1.2.3")()
+	fs := testFilesystem(t)
+	defer os.Remove(fs.readPath)
+
+	if err := fs.RefreshStatePartial(func(addrs.AbsResource) bool { return false }); err != nil {
+		t.Fatal(err)
+	}
+	state := fs.State()
+	if len(state.Modules) != 1 || len(state.RootModule().OutputValues) != 2 {
+		t.Fatalf("wrong partial state\n%s", state)
+	}
+
+	// A partially-read state can't be persisted, and the existing snapshot
+	// is left intact.
+	if err := fs.WriteState(state); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.PersistState(nil); !errors.Is(err, statefile.ErrPartialState) {
+		t.Fatalf("wrong error persisting partial state: %v", err)
+	}
+
+	if err := fs.RefreshState(); err != nil {
+		t.Fatal(err)
+	}
+	if len(fs.State().Modules) != 2 {
+		t.Fatalf("wrong state after full refresh\n%s", fs.State())
+	}
+	if err := fs.PersistState(nil); err != nil {
+		t.Fatalf("failed to persist state after full refresh: %s", err)
+	}
+}
+
 func testOverrideVersion(t *testing.T, v string) func() {
 	oldVersionStr := tfversion.Version
 	oldPrereleaseStr := tfversion.Prerelease
@@ -115,6 +115,31 @@ func LockWithContext(ctx context.Context, s Locker, info *LockInfo) (string, err
 	}
 }
 
+// InspectLock returns the information about the lock currently held on the
+// given state manager, or nil if the state isn't locked.
+//
+// Since Locker has no way to read the current lock directly, InspectLock
+// attempts to obtain a lock and reports the information returned in the
+// resulting LockError. If the lock is obtained then it's released again
+// immediately.
+func InspectLock(s Locker) (*LockInfo, error) {
+	info := NewLockInfo()
+	info.Operation = "InspectLock"
+	id, err := s.Lock(info)
+	if err == nil {
+		if err := s.Unlock(id); err != nil {
+			return nil, fmt.Errorf("failed to release the lock obtained while inspecting it: %w", err)
+		}
+		return nil, nil
+	}
+
+	var le *LockError
+	if errors.As(err, &le) && le.Info != nil && le.Info.ID != "" {
+		return le.Info, nil
+	}
+	return nil, err
+}
+
 // LockInfo stores lock metadata.
 //
 // Only Operation and Info are required to be set by the caller of Lock.
@@ -8,6 +8,7 @@ package statemgr
 import (
 	version "github.com/hashicorp/go-version"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tofu"
 )
@@ -66,6 +67,27 @@ type Refresher interface {
 	RefreshState() error
 }
 
+// PartialRefresher is an optional extension to Refresher for managers that
+// can read only some of the resources from the latest persistent snapshot,
+// for callers that only read a few resources from a large state.
+//
+// After a call to RefreshStatePartial, the manager's State contains only the
+// resources for which keep returned true, and PersistState returns an error
+// until RefreshState is called again, so that the resources that weren't
+// read can't be lost.
+type PartialRefresher interface {
+	RefreshStatePartial(keep func(addrs.AbsResource) bool) error
+}
+
+// RefreshPartial calls RefreshStatePartial on the given manager if it
+// implements PartialRefresher, or RefreshState otherwise.
+func RefreshPartial(mgr Refresher, keep func(addrs.AbsResource) bool) error {
+	if pr, ok := mgr.(PartialRefresher); ok {
+		return pr.RefreshStatePartial(keep)
+	}
+	return mgr.RefreshState()
+}
+
 // Persister is the interface for managers that can write snapshots to
 // persistent storage.
 //
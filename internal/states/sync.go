@@ -89,17 +89,32 @@ func (s *SyncState) OutputValue(addr addrs.AbsOutputValue) *OutputValue {
 	return ret
 }
 
+// WorkspaceMeta returns a snapshot of the metadata of the workspace the
+// state belongs to, or nil if none has been set.
+func (s *SyncState) WorkspaceMeta() *WorkspaceMeta {
+	s.lock.RLock()
+	ret := s.state.WorkspaceMeta.DeepCopy()
+	s.lock.RUnlock()
+	return ret
+}
+
 // SetOutputValue writes a given output value into the state, overwriting
 // any existing value of the same name.
 //
 // If the module containing the output is not yet tracked in state then it
 // be added as a side-effect.
 func (s *SyncState) SetOutputValue(addr addrs.AbsOutputValue, value cty.Value, sensitive bool) {
+	s.SetSharedOutputValue(addr, value, sensitive, nil)
+}
+
+// SetSharedOutputValue is like SetOutputValue, but also records the
+// consumers that the output value is shared with.
+func (s *SyncState) SetSharedOutputValue(addr addrs.AbsOutputValue, value cty.Value, sensitive bool, sharedWith []string) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 
 	ms := s.state.EnsureModule(addr.Module)
-	ms.SetOutputValue(addr.OutputValue.Name, value, sensitive)
+	ms.SetSharedOutputValue(addr.OutputValue.Name, value, sensitive, sharedWith)
 }
 
 // RemoveOutputValue removes the stored value for the output value with the
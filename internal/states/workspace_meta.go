@@ -0,0 +1,65 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package states
+
+import (
+	"github.com/zclconf/go-cty/cty"
+)
+
+// WorkspaceMeta is descriptive metadata about the workspace that a state
+// belongs to, which users set using the "tofu workspace" commands and can
+// then refer to in the configuration as tofu.workspace_meta.
+type WorkspaceMeta struct {
+	Description string
+	Tags        map[string]string
+}
+
+// DeepCopy returns a new WorkspaceMeta that contains equivalent data to the
+// receiver but shares no backing memory in common.
+func (m *WorkspaceMeta) DeepCopy() *WorkspaceMeta {
+	if m == nil {
+		return nil
+	}
+
+	var tags map[string]string
+	if m.Tags != nil {
+		tags = make(map[string]string, len(m.Tags))
+		for k, v := range m.Tags {
+			tags[k] = v
+		}
+	}
+	return &WorkspaceMeta{
+		Description: m.Description,
+		Tags:        tags,
+	}
+}
+
+// Empty returns true if the receiver has neither a description nor any tags.
+func (m *WorkspaceMeta) Empty() bool {
+	return m == nil || (m.Description == "" && len(m.Tags) == 0)
+}
+
+// Value returns the metadata as an object value, in the form exposed to the
+// configuration. A nil receiver produces an object with an empty
+// description and no tags.
+func (m *WorkspaceMeta) Value() cty.Value {
+	description := ""
+	tags := map[string]cty.Value{}
+	if m != nil {
+		description = m.Description
+		for k, v := range m.Tags {
+			tags[k] = cty.StringVal(v)
+		}
+	}
+	tagsVal := cty.MapValEmpty(cty.String)
+	if len(tags) != 0 {
+		tagsVal = cty.MapVal(tags)
+	}
+	return cty.ObjectVal(map[string]cty.Value{
+		"description": cty.StringVal(description),
+		"tags":        tagsVal,
+	})
+}
@@ -0,0 +1,30 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package states
+
+// WorkspaceVariable is a value for a root module input variable that users
+// store with the workspace using the "tofu workspace vars" commands, so that
+// it's used by every operation in the workspace without distributing
+// .tfvars files.
+type WorkspaceVariable struct {
+	// Value is the raw value, which is interpreted in the same way as a
+	// value given with the -var command line option: as a literal string
+	// for variables of type string, or as an expression otherwise.
+	Value string
+
+	// Sensitive values are never shown by "tofu workspace vars list".
+	Sensitive bool
+}
+
+// DeepCopy returns a new WorkspaceVariable that contains equivalent data to
+// the receiver but shares no backing memory in common.
+func (v *WorkspaceVariable) DeepCopy() *WorkspaceVariable {
+	if v == nil {
+		return nil
+	}
+	ret := *v
+	return &ret
+}
@@ -0,0 +1,106 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package telemetry contains OpenTofu's support for exporting OpenTelemetry
+// traces and metrics over OTLP.
+package telemetry
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// OTLPConfig holds the settings for the OTLP exporters that come from the
+// CLI configuration.
+//
+// The exporters are primarily configured by the same standard environment
+// variables as other OpenTelemetry exporters:
+//
+//	https://opentelemetry.io/docs/specs/otel/protocol/exporter/#configuration-options
+//
+// Each of the settings here is only used when the corresponding environment
+// variable isn't set, so that the environment can still override the CLI
+// configuration for a particular run. The headers are merged, with those
+// from the environment taking precedence.
+type OTLPConfig struct {
+	// Endpoint is the base URL of the collector, like
+	// OTEL_EXPORTER_OTLP_ENDPOINT.
+	Endpoint string
+
+	// Protocol is either "grpc" or "http/protobuf", like
+	// OTEL_EXPORTER_OTLP_PROTOCOL.
+	Protocol string
+
+	// Headers are sent with each export request, like
+	// OTEL_EXPORTER_OTLP_HEADERS.
+	Headers map[string]string
+}
+
+// otlpEnv returns the value of the signal-specific variant of the given
+// OTLP exporter environment variable if it's set, such as
+// OTEL_EXPORTER_OTLP_METRICS_HEADERS, or of the general one otherwise.
+func otlpEnv(signal, name string) string {
+	if v := os.Getenv("OTEL_EXPORTER_OTLP_" + signal + "_" + name); v != "" {
+		return v
+	}
+	return os.Getenv("OTEL_EXPORTER_OTLP_" + name)
+}
+
+func (c OTLPConfig) protocol(signal string) (string, error) {
+	protocol := otlpEnv(signal, "PROTOCOL")
+	if protocol == "" {
+		protocol = c.Protocol
+	}
+	switch protocol {
+	case "":
+		return "grpc", nil
+	case "grpc", "http/protobuf":
+		return protocol, nil
+	default:
+		return "", fmt.Errorf("invalid OTLP protocol %q: must be either \"grpc\" or \"http/protobuf\"", protocol)
+	}
+}
+
+func (c OTLPConfig) headers(signal string) (map[string]string, error) {
+	ret := make(map[string]string, len(c.Headers))
+	for k, v := range c.Headers {
+		ret[k] = v
+	}
+	env, err := parseHeaders(otlpEnv(signal, "HEADERS"))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range env {
+		ret[k] = v
+	}
+	return ret, nil
+}
+
+// parseHeaders parses headers in the W3C baggage-like format used by
+// OTEL_EXPORTER_OTLP_HEADERS, such as "api-key=secret,tenant=example".
+func parseHeaders(raw string) (map[string]string, error) {
+	ret := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid OTLP header %q: must be in the form key=value", pair)
+		}
+		key, err := url.PathUnescape(strings.TrimSpace(k))
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP header %q: %w", pair, err)
+		}
+		value, err := url.PathUnescape(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("invalid OTLP header %q: %w", pair, err)
+		}
+		ret[key] = value
+	}
+	return ret, nil
+}
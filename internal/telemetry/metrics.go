@@ -0,0 +1,70 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package telemetry
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// NewOTLPMetricExporter returns an exporter that sends metrics to an OTLP
+// endpoint, configured by the standard environment variables and then by
+// the given CLI configuration.
+func NewOTLPMetricExporter(ctx context.Context, cfg OTLPConfig) (sdkmetric.Exporter, error) {
+	protocol, err := cfg.protocol("METRICS")
+	if err != nil {
+		return nil, err
+	}
+	headers, err := cfg.headers("METRICS")
+	if err != nil {
+		return nil, err
+	}
+
+	// The OTLP metric exporters read the environment variables themselves,
+	// so we only need to pass the endpoint when it comes from the CLI
+	// configuration.
+	var endpoint *url.URL
+	if otlpEnv("METRICS", "ENDPOINT") == "" && cfg.Endpoint != "" {
+		endpoint, err = url.Parse(cfg.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if protocol == "http/protobuf" {
+		var opts []otlpmetrichttp.Option
+		if len(headers) > 0 {
+			opts = append(opts, otlpmetrichttp.WithHeaders(headers))
+		}
+		if endpoint != nil {
+			opts = append(opts,
+				otlpmetrichttp.WithEndpoint(endpoint.Host),
+				otlpmetrichttp.WithURLPath(strings.TrimSuffix(endpoint.Path, "/")+"/v1/metrics"),
+			)
+			if endpoint.Scheme == "http" {
+				opts = append(opts, otlpmetrichttp.WithInsecure())
+			}
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	}
+
+	var opts []otlpmetricgrpc.Option
+	if len(headers) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithHeaders(headers))
+	}
+	if endpoint != nil {
+		opts = append(opts, otlpmetricgrpc.WithEndpoint(endpoint.Host))
+		if endpoint.Scheme == "http" {
+			opts = append(opts, otlpmetricgrpc.WithInsecure())
+		}
+	}
+	return otlpmetricgrpc.New(ctx, opts...)
+}
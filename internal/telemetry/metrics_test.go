@@ -0,0 +1,115 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewOTLPMetricExporter_config(t *testing.T) {
+	var gotPath string
+	var gotHeaders http.Header
+	var got colmetricspb.ExportMetricsServiceRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeaders = r.Header
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+		}
+		if err := proto.Unmarshal(body, &got); err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		resp, _ := proto.Marshal(&colmetricspb.ExportMetricsServiceResponse{})
+		w.Write(resp)
+	}))
+	defer server.Close()
+
+	// The settings from the CLI configuration are used when the environment
+	// variables aren't set, and the headers are merged.
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "api-key=from-env")
+	exp, err := NewOTLPMetricExporter(context.Background(), OTLPConfig{
+		Endpoint: server.URL + "/otlp/",
+		Protocol: "http/protobuf",
+		Headers: map[string]string{
+			"api-key": "from-config",
+			"tenant":  "example",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp)))
+	counter, err := provider.Meter("example").Int64Counter("calls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Add(context.Background(), 2)
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotPath != "/otlp/v1/metrics" {
+		t.Errorf("wrong path %q", gotPath)
+	}
+	if got := gotHeaders.Get("Api-Key"); got != "from-env" {
+		t.Errorf("wrong api-key header %q", got)
+	}
+	if got := gotHeaders.Get("Tenant"); got != "example" {
+		t.Errorf("wrong tenant header %q", got)
+	}
+	if len(got.ResourceMetrics) != 1 || len(got.ResourceMetrics[0].ScopeMetrics) != 1 {
+		t.Fatalf("wrong metrics exported: %v", &got)
+	}
+	metrics := got.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(metrics) != 1 || metrics[0].Name != "calls" {
+		t.Fatalf("wrong metrics exported: %v", metrics)
+	}
+	if got := metrics[0].GetSum().DataPoints[0].GetAsInt(); got != 2 {
+		t.Errorf("wrong value %d; want 2", got)
+	}
+}
+
+func TestNewOTLPMetricExporter_invalid(t *testing.T) {
+	tests := map[string]struct {
+		env  map[string]string
+		want string
+	}{
+		"protocol": {
+			map[string]string{"OTEL_EXPORTER_OTLP_METRICS_PROTOCOL": "http/json"},
+			`invalid OTLP protocol "http/json": must be either "grpc" or "http/protobuf"`,
+		},
+		"headers": {
+			map[string]string{"OTEL_EXPORTER_OTLP_HEADERS": "api-key"},
+			`invalid OTLP header "api-key": must be in the form key=value`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			for k, v := range test.env {
+				t.Setenv(k, v)
+			}
+			_, err := NewOTLPMetricExporter(context.Background(), OTLPConfig{})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := err.Error(); got != test.want {
+				t.Errorf("wrong error\ngot:  %s\nwant: %s", got, test.want)
+			}
+		})
+	}
+}
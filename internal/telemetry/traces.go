@@ -0,0 +1,70 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package telemetry
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// NewOTLPSpanExporter returns an exporter that sends traces to an OTLP
+// endpoint, configured by the standard environment variables and then by
+// the given CLI configuration.
+func NewOTLPSpanExporter(ctx context.Context, cfg OTLPConfig) (sdktrace.SpanExporter, error) {
+	protocol, err := cfg.protocol("TRACES")
+	if err != nil {
+		return nil, err
+	}
+	headers, err := cfg.headers("TRACES")
+	if err != nil {
+		return nil, err
+	}
+
+	// The OTLP trace exporters read the environment variables themselves,
+	// so we only need to pass the endpoint when it comes from the CLI
+	// configuration.
+	var endpoint *url.URL
+	if otlpEnv("TRACES", "ENDPOINT") == "" && cfg.Endpoint != "" {
+		endpoint, err = url.Parse(cfg.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if protocol == "http/protobuf" {
+		var opts []otlptracehttp.Option
+		if len(headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(headers))
+		}
+		if endpoint != nil {
+			opts = append(opts,
+				otlptracehttp.WithEndpoint(endpoint.Host),
+				otlptracehttp.WithURLPath(strings.TrimSuffix(endpoint.Path, "/")+"/v1/traces"),
+			)
+			if endpoint.Scheme == "http" {
+				opts = append(opts, otlptracehttp.WithInsecure())
+			}
+		}
+		return otlptracehttp.New(ctx, opts...)
+	}
+
+	var opts []otlptracegrpc.Option
+	if len(headers) > 0 {
+		opts = append(opts, otlptracegrpc.WithHeaders(headers))
+	}
+	if endpoint != nil {
+		opts = append(opts, otlptracegrpc.WithEndpoint(endpoint.Host))
+		if endpoint.Scheme == "http" {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+	}
+	return otlptracegrpc.New(ctx, opts...)
+}
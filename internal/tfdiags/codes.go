@@ -0,0 +1,258 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfdiags
+
+import (
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/i18n"
+)
+
+// Code is a stable identifier for a kind of diagnostic, which users can use
+// to search for more information about it and which tools can use to
+// recognize it without matching on its summary text.
+//
+// A Code can be used directly as the Extra value of an hcl.Diagnostic to give
+// the diagnostic that code. Use ExtraWithCode if the diagnostic also needs
+// some other extra information, or WithCode to add a code to a Diagnostic
+// that has already been constructed.
+//
+// Once assigned, a code must never be reused for a different kind of
+// diagnostic, even if the original kind of diagnostic is no longer reported.
+type Code string
+
+const (
+	// Codes 1xxx are for problems detected while loading the configuration.
+	CodeDuplicateVariable      Code = "OTF1001"
+	CodeDuplicateLocal         Code = "OTF1002"
+	CodeDuplicateOutput        Code = "OTF1003"
+	CodeDuplicateResource      Code = "OTF1004"
+	CodeMissingOverrideTarget  Code = "OTF1005"
+	CodeUnsupportedCoreVersion Code = "OTF1006"
+
+	// Codes 2xxx are for problems detected by OpenTofu Core while
+	// validating, planning or applying.
+	CodeUndeclaredVariable Code = "OTF2001"
+	CodeUndeclaredLocal    Code = "OTF2002"
+	CodeUndeclaredModule   Code = "OTF2003"
+	CodeUndeclaredResource Code = "OTF2004"
+	CodeUndeclaredOutput   Code = "OTF2005"
+	CodeUnknownCount       Code = "OTF2006"
+	CodeUnknownForEach     Code = "OTF2007"
+
+	// Codes 3xxx are for problems with backends and the state they store.
+	CodeBackendInitRequired Code = "OTF3001"
+	CodeStateLock           Code = "OTF3002"
+)
+
+// CodeInfo describes a diagnostic code, for "tofu explain".
+type CodeInfo struct {
+	Code Code
+
+	// Summary is a short description of the kind of diagnostic that has
+	// the code, which is usually similar to the summary of the diagnostics
+	// themselves.
+	Summary string
+
+	// Explanation is extended guidance about why OpenTofu reports the
+	// diagnostic and what to do about it, as one or more paragraphs
+	// separated by blank lines.
+	Explanation string
+}
+
+// Codes are all of the diagnostic codes, in order.
+var Codes = []CodeInfo{
+	{
+		Code:    CodeDuplicateVariable,
+		Summary: "Duplicate variable declaration",
+		Explanation: `A module declares more than one input variable with the same name. Each input variable name must be unique within a module, across all of its configuration files.
+
+Remove or rename one of the declarations. If you intended to change an existing declaration from another file, use an override file instead.`,
+	},
+	{
+		Code:    CodeDuplicateLocal,
+		Summary: "Duplicate local value definition",
+		Explanation: `A module defines more than one local value with the same name. Each local value name must be unique within a module, even when the definitions are in different "locals" blocks or different files.
+
+Remove or rename one of the definitions.`,
+	},
+	{
+		Code:    CodeDuplicateOutput,
+		Summary: "Duplicate output definition",
+		Explanation: `A module defines more than one output value with the same name. Each output value name must be unique within a module, across all of its configuration files.
+
+Remove or rename one of the definitions. If you intended to change an existing definition from another file, use an override file instead.`,
+	},
+	{
+		Code:    CodeDuplicateResource,
+		Summary: "Duplicate resource configuration",
+		Explanation: `A module declares more than one resource or data resource with the same type and name. The type and name together form the address of the resource, so they must be unique within a module.
+
+Rename one of the blocks. If you rename a managed resource that already has objects in the state, add a "moved" block so that OpenTofu doesn't plan to destroy and recreate them.`,
+	},
+	{
+		Code:    CodeMissingOverrideTarget,
+		Summary: "Missing block to override",
+		Explanation: `An override file contains a block that doesn't correspond to any block in the primary configuration files of the module. Override files can only change blocks that are already declared elsewhere; they can't declare new ones.
+
+Check the type and name of the block in the override file for typos, or move the block into a primary configuration file if you intended to declare something new.`,
+	},
+	{
+		Code:    CodeUnsupportedCoreVersion,
+		Summary: "Unsupported OpenTofu Core version",
+		Explanation: `A module has a "required_version" constraint in its "terraform" block that doesn't match the version of OpenTofu that you are running.
+
+Use a version of OpenTofu that matches the constraint, or, if you maintain the module and it works with this version of OpenTofu, change the constraint.`,
+	},
+	{
+		Code:    CodeUndeclaredVariable,
+		Summary: "Reference to undeclared input variable",
+		Explanation: `An expression refers to an input variable that the module doesn't declare. Each module can only refer to its own input variables, using the "var." prefix.
+
+Check the name for typos, or declare the variable with a "variable" block. To use a value from another module, pass it in as an argument in the module block.`,
+	},
+	{
+		Code:    CodeUndeclaredLocal,
+		Summary: "Reference to undeclared local value",
+		Explanation: `An expression refers to a local value that the module doesn't define. Each module can only refer to its own local values, using the "local." prefix.
+
+Check the name for typos, or define the value in a "locals" block. Note that the block is named "locals" but references use the singular "local." prefix.`,
+	},
+	{
+		Code:    CodeUndeclaredModule,
+		Summary: "Reference to undeclared module",
+		Explanation: `An expression refers to a module call that the module doesn't declare. References using the "module." prefix refer to the "module" blocks of the current module, not to modules elsewhere in the configuration.
+
+Check the name for typos. To use a value from a module that isn't a direct child of the current module, pass it through output values and input variables.`,
+	},
+	{
+		Code:    CodeUndeclaredResource,
+		Summary: "Reference to undeclared resource",
+		Explanation: `An expression refers to a resource that the module doesn't declare. References to managed resources are written as TYPE.NAME, and references to data resources as data.TYPE.NAME.
+
+Check the type and name for typos, and check that you included the "data." prefix when referring to a data resource. Modules can only refer to their own resources; to use a resource from another module, export it as an output value.`,
+	},
+	{
+		Code:    CodeUndeclaredOutput,
+		Summary: "Reference to undeclared output value",
+		Explanation: `An expression refers to an output value that the relevant module doesn't declare.
+
+Check the name for typos, or add an "output" block to the module that should export the value. After changing a called module, run "tofu init" or "tofu get" if the module is installed from a remote source.`,
+	},
+	{
+		Code:    CodeUnknownCount,
+		Summary: "Invalid count argument",
+		Explanation: `The value of a "count" argument depends on something that OpenTofu can't know until it applies the plan, such as an attribute of a resource that doesn't exist yet. OpenTofu needs to know how many instances to plan for, so it can't create a plan.
+
+Change the expression to use only values that are known during planning, such as input variables and attributes that the provider knows in advance. Alternatively, use the -target option to apply the objects that the count depends on first, and then plan again without -target.`,
+	},
+	{
+		Code:    CodeUnknownForEach,
+		Summary: "Invalid for_each argument",
+		Explanation: `The keys of a "for_each" argument depend on something that OpenTofu can't know until it applies the plan, such as an attribute of a resource that doesn't exist yet. OpenTofu uses the keys to identify the instances, so it can't create a plan. The values of a map may be unknown, but its keys must be known.
+
+Change the expression so that the keys are built only from values that are known during planning, such as input variables and static names. Alternatively, use the -target option to apply the objects that the for_each depends on first, and then plan again without -target.`,
+	},
+	{
+		Code:    CodeBackendInitRequired,
+		Summary: "Backend initialization required",
+		Explanation: `The backend configuration of the working directory has changed since it was last initialized, or the working directory was never initialized. OpenTofu won't use a backend until "tofu init" has confirmed the change, because the change might mean the state needs to move.
+
+Run "tofu init" to initialize the backend. If the state should move to the new backend, use "tofu init -migrate-state"; to start using the new backend without moving the state, use "tofu init -reconfigure".`,
+	},
+	{
+		Code:    CodeStateLock,
+		Summary: "Error acquiring the state lock",
+		Explanation: `OpenTofu couldn't lock the state before an operation that could change it. Usually this means that another OpenTofu process is running against the same state, but it can also mean that an earlier process stopped without releasing its lock.
+
+Wait for the other operation to finish, or use the -lock-timeout option to wait automatically. If you are sure that no other operation is running, release the lock with "tofu force-unlock" and the lock ID from the error message.`,
+	},
+}
+
+// LookupCode returns the information about the given diagnostic code, or
+// false if there is no such code. The code is not case-sensitive.
+func LookupCode(code string) (CodeInfo, bool) {
+	for _, info := range Codes {
+		if strings.EqualFold(string(info.Code), code) {
+			return info, true
+		}
+	}
+	return CodeInfo{}, false
+}
+
+// Localized returns the information translated into the language selected
+// with the TF_LANG environment variable, where translations are available.
+func (info CodeInfo) Localized() CodeInfo {
+	info.Summary = i18n.T(info.Summary)
+	info.Explanation = i18n.T(info.Explanation)
+	return info
+}
+
+// DiagnosticCode implements DiagnosticExtraCode.
+func (c Code) DiagnosticCode() Code {
+	return c
+}
+
+// DiagnosticExtraCode is an interface implemented by values in the Extra
+// field of Diagnostic when the diagnostic has a code.
+type DiagnosticExtraCode interface {
+	// DiagnosticCode returns the code of the associated diagnostic.
+	DiagnosticCode() Code
+}
+
+// DiagnosticCode returns the code of the given diagnostic, or an empty string
+// if it has no code.
+func DiagnosticCode(diag Diagnostic) Code {
+	maybe := ExtraInfo[DiagnosticExtraCode](diag)
+	if maybe == nil {
+		return ""
+	}
+	return maybe.DiagnosticCode()
+}
+
+// ExtraWithCode returns a value for the Extra field of an hcl.Diagnostic
+// that gives the diagnostic the given code in addition to the given extra
+// information.
+func ExtraWithCode(code Code, extra interface{}) interface{} {
+	return &codedExtra{
+		code:  code,
+		inner: extra,
+	}
+}
+
+// WithCode returns a diagnostic that is the same as the given one except
+// that it has the given code.
+func WithCode(diag Diagnostic, code Code) Diagnostic {
+	return codedDiagnostic{
+		Diagnostic: diag,
+		extra:      ExtraWithCode(code, diag.ExtraInfo()),
+	}
+}
+
+type codedExtra struct {
+	code  Code
+	inner interface{}
+}
+
+var _ DiagnosticExtraCode = (*codedExtra)(nil)
+var _ DiagnosticExtraUnwrapper = (*codedExtra)(nil)
+
+func (e *codedExtra) DiagnosticCode() Code {
+	return e.code
+}
+
+func (e *codedExtra) UnwrapDiagnosticExtra() interface{} {
+	return e.inner
+}
+
+type codedDiagnostic struct {
+	Diagnostic
+	extra interface{}
+}
+
+func (d codedDiagnostic) ExtraInfo() interface{} {
+	return d.extra
+}
@@ -0,0 +1,72 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfdiags
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestDiagnosticCode(t *testing.T) {
+	var diags Diagnostics
+	diags = diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Coded",
+		Extra:    CodeUndeclaredResource,
+	})
+	diags = diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Coded with category",
+		Extra:    ExtraWithCode(CodeDuplicateOutput, WarningDeprecation),
+	})
+	diags = diags.Append(WithCode(Sourceless(Error, "Sourceless", ""), CodeStateLock))
+	diags = diags.Append(Sourceless(Error, "Uncoded", ""))
+
+	want := []Code{CodeUndeclaredResource, CodeDuplicateOutput, CodeStateLock, ""}
+	for i, diag := range diags {
+		if got := DiagnosticCode(diag); got != want[i] {
+			t.Errorf("wrong code for %q: got %q, want %q", diag.Description().Summary, got, want[i])
+		}
+	}
+
+	// Adding a code must not hide the other extra information.
+	if got, want := DiagnosticWarningCategory(diags[1]), WarningDeprecation; got != want {
+		t.Errorf("wrong category %q; want %q", got, want)
+	}
+	if got, want := DiagnosticCode(diags.Strict([]WarningCategory{WarningDeprecation})[1]), CodeDuplicateOutput; got != want {
+		t.Errorf("wrong code after strict %q; want %q", got, want)
+	}
+}
+
+func TestCodes(t *testing.T) {
+	valid := regexp.MustCompile(`^OTF[1-9][0-9]{3}$`)
+	seen := make(map[Code]bool)
+	for _, info := range Codes {
+		if !valid.MatchString(string(info.Code)) {
+			t.Errorf("invalid code %q", info.Code)
+		}
+		if seen[info.Code] {
+			t.Errorf("duplicate code %q", info.Code)
+		}
+		seen[info.Code] = true
+		if info.Summary == "" || info.Explanation == "" {
+			t.Errorf("code %q has no summary or explanation", info.Code)
+		}
+	}
+
+	info, ok := LookupCode("otf2004")
+	if !ok {
+		t.Fatal("code OTF2004 not found")
+	}
+	if got, want := info.Code, CodeUndeclaredResource; got != want {
+		t.Errorf("wrong code %q; want %q", got, want)
+	}
+	if _, ok := LookupCode("OTF0000"); ok {
+		t.Error("found unexpected code OTF0000")
+	}
+}
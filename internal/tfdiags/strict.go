@@ -0,0 +1,98 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfdiags
+
+// WarningCategory identifies a kind of warning that strict mode can treat
+// as an error instead.
+//
+// A WarningCategory can be used directly as the Extra value of an
+// hcl.Diagnostic to mark the warning as belonging to that category.
+type WarningCategory string
+
+const (
+	// WarningDeprecation is the category of warnings about the use of
+	// deprecated language features, input variables, output values and
+	// resource attributes.
+	WarningDeprecation WarningCategory = "deprecations"
+
+	// WarningImplicitProviderInheritance is the category of warnings about
+	// provider configurations that a module receives implicitly, rather than
+	// through an explicit declaration.
+	WarningImplicitProviderInheritance WarningCategory = "implicit_provider_inheritance"
+
+	// WarningUndeclaredVarFile is the category of warnings about values in
+	// variable definitions files for variables that the root module does
+	// not declare.
+	WarningUndeclaredVarFile WarningCategory = "undeclared_var_files"
+)
+
+// WarningCategories are all of the warning categories, in the order they
+// should be presented to users.
+var WarningCategories = []WarningCategory{
+	WarningDeprecation,
+	WarningImplicitProviderInheritance,
+	WarningUndeclaredVarFile,
+}
+
+// ValidWarningCategory returns true if the given name is one of the
+// WarningCategories.
+func ValidWarningCategory(name string) bool {
+	for _, category := range WarningCategories {
+		if string(category) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DiagnosticWarningCategory implements DiagnosticExtraWarningCategory.
+func (c WarningCategory) DiagnosticWarningCategory() WarningCategory {
+	return c
+}
+
+// DiagnosticExtraWarningCategory is an interface implemented by values in
+// the Extra field of Diagnostic when the diagnostic is a warning in one of
+// the WarningCategories.
+type DiagnosticExtraWarningCategory interface {
+	// DiagnosticWarningCategory returns the category of the associated
+	// warning.
+	DiagnosticWarningCategory() WarningCategory
+}
+
+// DiagnosticWarningCategory returns the category of the given diagnostic,
+// or an empty string if it doesn't belong to any category.
+func DiagnosticWarningCategory(diag Diagnostic) WarningCategory {
+	maybe := ExtraInfo[DiagnosticExtraWarningCategory](diag)
+	if maybe == nil {
+		return ""
+	}
+	return maybe.DiagnosticWarningCategory()
+}
+
+// Strict returns a new diagnostics with any warnings in the given categories
+// changed into errors, so that they block the operation that produced them.
+//
+// The returned slice always has a separate backing array from the receiver,
+// but the other diagnostic values themselves are shared.
+func (diags Diagnostics) Strict(categories []WarningCategory) Diagnostics {
+	if len(diags) == 0 {
+		return nil
+	}
+
+	strict := make(map[WarningCategory]bool, len(categories))
+	for _, category := range categories {
+		strict[category] = true
+	}
+
+	newDiags := make(Diagnostics, 0, len(diags))
+	for _, diag := range diags {
+		if diag.Severity() == Warning && strict[DiagnosticWarningCategory(diag)] {
+			diag = Override(diag, Error, nil)
+		}
+		newDiags = newDiags.Append(diag)
+	}
+	return newDiags
+}
@@ -0,0 +1,50 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tfdiags
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+func TestDiagnosticsStrict(t *testing.T) {
+	var diags Diagnostics
+	diags = diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Deprecated thing",
+		Extra:    WarningDeprecation,
+	})
+	diags = diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Undeclared variable",
+		Extra:    WarningUndeclaredVarFile,
+	})
+	diags = diags.Append(&hcl.Diagnostic{
+		Severity: hcl.DiagWarning,
+		Summary:  "Uncategorized",
+	})
+
+	got := diags.Strict([]WarningCategory{WarningDeprecation})
+
+	want := []Severity{Error, Warning, Warning}
+	if len(got) != len(want) {
+		t.Fatalf("wrong number of diagnostics %d; want %d", len(got), len(want))
+	}
+	for i, diag := range got {
+		if diag.Severity() != want[i] {
+			t.Errorf("wrong severity for %q: got %s, want %s", diag.Description().Summary, diag.Severity(), want[i])
+		}
+	}
+	if got, want := DiagnosticWarningCategory(got[0]), WarningDeprecation; got != want {
+		t.Errorf("wrong category %q; want %q", got, want)
+	}
+
+	// The original diagnostics must be unchanged.
+	if diags[0].Severity() != Warning {
+		t.Errorf("original diagnostic was modified")
+	}
+}
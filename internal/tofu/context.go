@@ -11,6 +11,7 @@ import (
 	"log"
 	"sort"
 	"sync"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
@@ -47,6 +48,24 @@ type ContextOpts struct {
 	Encryption   encryption.Encryption
 
 	UIInput UIInput
+
+	// AutoApprove is set when the user has approved the plan in advance,
+	// such as with the -auto-approve option, so that an apply never stops
+	// to ask for confirmation of individual changes.
+	AutoApprove bool
+
+	// NodeTimings, if set, records the time spent executing each graph node.
+	NodeTimings *NodeTimings
+
+	// PlanCache, if set, reuses the responses of providers from an earlier
+	// plan when planning changes to resource instances.
+	PlanCache *PlanCache
+
+	// OperationTimeout, if set, is the default limit on how long each call
+	// to a provider to read, create, update or delete a resource instance
+	// may take, for resources without the operation_timeout lifecycle
+	// argument.
+	OperationTimeout time.Duration
 }
 
 // ContextMeta is metadata about the running context. This is information
@@ -85,7 +104,9 @@ type Context struct {
 	sh      *stopHook
 	uiInput UIInput
 
-	parallelSem         Semaphore
+	autoApprove bool
+
+	parallelSem         *prioritySemaphore
 	l                   sync.Mutex // Lock acquired during any task
 	providerInputConfig map[string]map[string]cty.Value
 	runCond             *sync.Cond
@@ -93,6 +114,11 @@ type Context struct {
 	runContextCancel    context.CancelFunc
 
 	encryption encryption.Encryption
+
+	nodeTimings *NodeTimings
+	planCache   *PlanCache
+
+	operationTimeout time.Duration
 }
 
 // (additional methods on Context can be found in context_*.go files.)
@@ -143,13 +169,19 @@ func NewContext(opts *ContextOpts) (*Context, tfdiags.Diagnostics) {
 		meta:    opts.Meta,
 		uiInput: opts.UIInput,
 
+		autoApprove: opts.AutoApprove,
+
 		plugins: plugins,
 
-		parallelSem:         NewSemaphore(par),
+		parallelSem:         newPrioritySemaphore(par),
 		providerInputConfig: make(map[string]map[string]cty.Value),
 		sh:                  sh,
 
-		encryption: opts.Encryption,
+		encryption:  opts.Encryption,
+		nodeTimings: opts.NodeTimings,
+		planCache:   opts.PlanCache,
+
+		operationTimeout: opts.OperationTimeout,
 	}, diags
 }
 
@@ -394,27 +426,32 @@ func (c *Context) checkConfigDependencies(config *configs.Config) tfdiags.Diagno
 		if modCfg == nil || modCfg.Module == nil {
 			return // should not happen, but we'll be robust
 		}
+		var provs []*configs.Provisioner
 		for _, rc := range modCfg.Module.ManagedResources {
 			if rc.Managed == nil {
 				continue // should not happen, but we'll be robust
 			}
-			for _, pc := range rc.Managed.Provisioners {
-				if !c.plugins.HasProvisioner(pc.Type) {
-					// This is not a very high-quality error, because really
-					// the caller of tofu.NewContext should've already
-					// done equivalent checks when doing plugin discovery.
-					// This is just to make sure we return a predictable
-					// error in a central place, rather than failing somewhere
-					// later in the non-deterministically-ordered graph walk.
-					diags = diags.Append(tfdiags.Sourceless(
-						tfdiags.Error,
-						"Missing required provisioner plugin",
-						fmt.Sprintf(
-							"This configuration requires provisioner plugin %q, which isn't available. If you're intending to use an external provisioner plugin, you must install it manually into one of the plugin search directories before running OpenTofu.",
-							pc.Type,
-						),
-					))
-				}
+			provs = append(provs, rc.Managed.Provisioners...)
+		}
+		for _, rc := range modCfg.Module.Removed {
+			provs = append(provs, rc.Provisioners...)
+		}
+		for _, pc := range provs {
+			if !c.plugins.HasProvisioner(pc.Type) {
+				// This is not a very high-quality error, because really
+				// the caller of tofu.NewContext should've already
+				// done equivalent checks when doing plugin discovery.
+				// This is just to make sure we return a predictable
+				// error in a central place, rather than failing somewhere
+				// later in the non-deterministically-ordered graph walk.
+				diags = diags.Append(tfdiags.Sourceless(
+					tfdiags.Error,
+					"Missing required provisioner plugin",
+					fmt.Sprintf(
+						"This configuration requires provisioner plugin %q, which isn't available. If you're intending to use an external provisioner plugin, you must install it manually into one of the plugin search directories before running OpenTofu.",
+						pc.Type,
+					),
+				))
 			}
 		}
 	})
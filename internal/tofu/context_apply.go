@@ -180,6 +180,7 @@ func (c *Context) applyGraph(plan *plans.Plan, config *configs.Config, validate
 		ForceReplace:       plan.ForceReplaceAddrs,
 		Operation:          operation,
 		ExternalReferences: plan.ExternalReferences,
+		AutoApprove:        c.autoApprove,
 	}).Build(addrs.RootModuleInstance)
 	diags = diags.Append(moreDiags)
 	if moreDiags.HasErrors() {
@@ -9,6 +9,8 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -24,6 +26,7 @@ import (
 	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/provisioners"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 )
@@ -2307,3 +2310,610 @@ func TestContext2Apply_forgetOrphanAndDeposed(t *testing.T) {
 		t.Fatalf("PostApply hook should not be called as part of forget")
 	}
 }
+
+func TestContext2Apply_removedModuleDestroyAllInstances(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			removed {
+				from = module.mod
+
+				lifecycle {
+					destroy = true
+				}
+			}
+		`,
+	})
+	p := testProvider("aws")
+	p.PlanResourceChangeFn = testDiffFn
+	state := states.BuildState(func(s *states.SyncState) {
+		for _, addr := range []string{`module.mod[0].aws_instance.foo`, `module.mod["b"].aws_instance.foo`} {
+			s.SetResourceInstanceCurrent(
+				mustResourceInstanceAddr(addr),
+				&states.ResourceInstanceObjectSrc{
+					Status:    states.ObjectReady,
+					AttrsJSON: []byte(`{"id":"bar"}`),
+				},
+				mustProviderConfig(`provider["registry.opentofu.org/hashicorp/aws"]`),
+			)
+		}
+	})
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	for _, change := range plan.Changes.Resources {
+		if got, want := change.Action, plans.Delete; got != want {
+			t.Errorf("wrong action for %s\ngot:  %s\nwant: %s", change.Addr, got, want)
+		}
+	}
+	if got, want := len(plan.Changes.Resources), 2; got != want {
+		t.Fatalf("wrong number of planned changes %d; want %d", got, want)
+	}
+
+	s, diags := ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	if !s.Empty() {
+		t.Fatalf("State should be empty")
+	}
+	if !p.ApplyResourceChangeCalled {
+		t.Fatalf("objects should have been destroyed by the provider")
+	}
+}
+
+func TestContext2Apply_removedResourceDestroyProvisioner(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			removed {
+				from = aws_instance.foo
+
+				lifecycle {
+					destroy = true
+				}
+
+				provisioner "shell" {
+					when    = destroy
+					command = "destroy ${self.id}"
+				}
+			}
+		`,
+	})
+	p := testProvider("aws")
+	p.PlanResourceChangeFn = testDiffFn
+	pr := testProvisioner()
+	var commands []string
+	pr.ProvisionResourceFn = func(req provisioners.ProvisionResourceRequest) (resp provisioners.ProvisionResourceResponse) {
+		commands = append(commands, req.Config.GetAttr("command").AsString())
+		return
+	}
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr(`aws_instance.foo[0]`),
+			&states.ResourceInstanceObjectSrc{
+				Status:    states.ObjectReady,
+				AttrsJSON: []byte(`{"id":"bar"}`),
+			},
+			mustProviderConfig(`provider["registry.opentofu.org/hashicorp/aws"]`),
+		)
+	})
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("aws"): testProviderFuncFixed(p),
+		},
+		Provisioners: map[string]provisioners.Factory{
+			"shell": testProvisionerFuncFixed(pr),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	s, diags := ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	if !s.Empty() {
+		t.Fatalf("State should be empty")
+	}
+	if diff := cmp.Diff([]string{"destroy bar"}, commands); diff != "" {
+		t.Errorf("wrong provisioner commands\n%s", diff)
+	}
+}
+
+// readinessProvider is a MockProvider that reports objects as ready only
+// after a number of readiness checks.
+type readinessProvider struct {
+	*MockProvider
+
+	mu         sync.Mutex
+	readyAfter int
+	checks     int
+}
+
+func (p *readinessProvider) CheckResourceReadiness(req providers.CheckResourceReadinessRequest) providers.CheckResourceReadinessResponse {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.checks++
+	return providers.CheckResourceReadinessResponse{Ready: p.checks > p.readyAfter}
+}
+
+func TestContext2Apply_twoPhaseReplace(t *testing.T) {
+	addr := mustResourceInstanceAddr("test_object.a")
+
+	defer func(interval time.Duration) {
+		twoPhaseReplaceReadyInterval = interval
+	}(twoPhaseReplaceReadyInterval)
+	twoPhaseReplaceReadyInterval = time.Millisecond
+
+	tests := map[string]struct {
+		check       string
+		answer      string
+		noInput     bool
+		autoApprove bool
+		wantDeposed bool
+		wantErr     string
+		wantWarning string
+	}{
+		"confirmed": {
+			answer: "yes",
+		},
+		"auto-approved": {
+			autoApprove: true,
+		},
+		"input disabled": {
+			noInput:     true,
+			wantDeposed: true,
+			wantWarning: "input is disabled",
+		},
+		"not confirmed": {
+			answer:      "no",
+			wantDeposed: true,
+			wantWarning: "destroying it was not confirmed",
+		},
+		"passing health check": {
+			check:  `test_object.a.test_string == "new"`,
+			answer: "yes",
+		},
+		"failing health check": {
+			check:       `test_object.a.test_string == "other"`,
+			answer:      "yes",
+			wantDeposed: true,
+			wantErr:     "Replacement object is not healthy",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := `
+resource "test_object" "a" {
+  test_string = "new"
+  lifecycle {
+    create_before_destroy = true
+    two_phase_replace     = true
+  }
+}
+`
+			if test.check != "" {
+				config += fmt.Sprintf(`
+check "health" {
+  assert {
+    condition     = %s
+    error_message = "Unhealthy."
+  }
+}
+`, test.check)
+			}
+			m := testModuleInline(t, map[string]string{"main.tf": config})
+
+			p := &readinessProvider{
+				MockProvider: simpleMockProvider(),
+				readyAfter:   2,
+			}
+			input := &MockUIInput{
+				InputFn: func(opts *InputOpts) (string, error) {
+					if !strings.HasPrefix(opts.Id, "two-phase-replace.test_object.a.") {
+						t.Errorf("unexpected input id %q", opts.Id)
+					}
+					return test.answer, nil
+				},
+			}
+			opts := &ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+				},
+				AutoApprove: test.autoApprove,
+			}
+			if !test.noInput {
+				opts.UIInput = input
+			}
+			ctx := testContext2(t, opts)
+
+			state := states.BuildState(func(s *states.SyncState) {
+				s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+					Status:    states.ObjectReady,
+					AttrsJSON: []byte(`{"test_string":"old"}`),
+				}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+			})
+
+			plan, diags := ctx.Plan(m, state, &PlanOpts{
+				Mode:         plans.NormalMode,
+				ForceReplace: []addrs.AbsResourceInstance{addr},
+			})
+			assertNoErrors(t, diags)
+			if got, want := plan.Changes.ResourceInstance(addr).Action, plans.CreateThenDelete; got != want {
+				t.Fatalf("wrong planned action %s; want %s", got, want)
+			}
+
+			newState, diags := ctx.Apply(plan, m)
+			if test.wantErr != "" {
+				if !diags.HasErrors() || !strings.Contains(diags.Err().Error(), test.wantErr) {
+					t.Fatalf("wrong errors\ngot:  %s\nwant: %s", diags.Err(), test.wantErr)
+				}
+			} else {
+				assertNoErrors(t, diags)
+			}
+			if test.wantWarning != "" {
+				if got := diags.ErrWithWarnings(); got == nil || !strings.Contains(got.Error(), test.wantWarning) {
+					t.Errorf("wrong warnings\ngot:  %s\nwant: %s", got, test.wantWarning)
+				}
+			}
+
+			is := newState.ResourceInstance(addr)
+			if is == nil || is.Current == nil {
+				t.Fatalf("missing current object for %s", addr)
+			}
+			if got, want := len(is.Deposed) != 0, test.wantDeposed; got != want {
+				t.Errorf("wrong deposed objects %#v; want deposed %t", is.Deposed, want)
+			}
+			if test.wantErr == "" && p.checks != p.readyAfter+1 {
+				t.Errorf("wrong number of readiness checks %d; want %d", p.checks, p.readyAfter+1)
+			}
+			wantInput := test.wantErr == "" && !test.autoApprove && !test.noInput
+			if got := input.InputCalled; got != wantInput {
+				t.Errorf("wrong confirmation request %t; want %t", got, wantInput)
+			}
+		})
+	}
+}
+
+func TestContext2Apply_twoPhaseReplaceTimeout(t *testing.T) {
+	addr := mustResourceInstanceAddr("test_object.a")
+
+	defer func(interval time.Duration) {
+		twoPhaseReplaceReadyInterval = interval
+	}(twoPhaseReplaceReadyInterval)
+	twoPhaseReplaceReadyInterval = time.Millisecond
+
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "new"
+  lifecycle {
+    create_before_destroy     = true
+    two_phase_replace         = true
+    two_phase_replace_timeout = "20ms"
+  }
+}
+`,
+	})
+
+	p := &readinessProvider{
+		MockProvider: simpleMockProvider(),
+		readyAfter:   math.MaxInt,
+	}
+	input := &MockUIInput{InputReturnString: "yes"}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		UIInput: input,
+	})
+
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(addr, &states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"test_string":"old"}`),
+		}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+	})
+
+	plan, diags := ctx.Plan(m, state, &PlanOpts{
+		Mode:         plans.NormalMode,
+		ForceReplace: []addrs.AbsResourceInstance{addr},
+	})
+	assertNoErrors(t, diags)
+
+	newState, diags := ctx.Apply(plan, m)
+	if want := "did not become ready within 20ms"; !diags.HasErrors() || !strings.Contains(diags.Err().Error(), want) {
+		t.Fatalf("wrong errors\ngot:  %s\nwant: %s", diags.Err(), want)
+	}
+	if is := newState.ResourceInstance(addr); is == nil || len(is.Deposed) != 1 {
+		t.Fatalf("previous object is not deposed")
+	}
+	if input.InputCalled {
+		t.Fatal("asked for confirmation even though the replacement isn't ready")
+	}
+}
+
+func TestContext2Apply_canary(t *testing.T) {
+	tests := map[string]struct {
+		expect      string
+		wantApplied []string
+		wantErr     string
+	}{
+		"passing checks": {
+			expect:      "v0",
+			wantApplied: []string{"v0", "v1", "v2"},
+		},
+		"failing checks": {
+			expect:      "other",
+			wantApplied: []string{"v0"},
+			wantErr:     "Canary checks failed",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := testModuleInline(t, map[string]string{
+				"main.tf": fmt.Sprintf(`
+resource "test_object" "a" {
+  count       = 3
+  test_string = "v${count.index}"
+  lifecycle {
+    canary = [0]
+  }
+}
+
+check "canary" {
+  assert {
+    condition     = test_object.a[0].test_string == %q
+    error_message = "Canary is unhealthy."
+  }
+}
+`, test.expect),
+			})
+
+			var mu sync.Mutex
+			var applied []string
+			p := simpleMockProvider()
+			p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+				mu.Lock()
+				defer mu.Unlock()
+				applied = append(applied, req.PlannedState.GetAttr("test_string").AsString())
+				return providers.ApplyResourceChangeResponse{NewState: req.PlannedState}
+			}
+			ctx := testContext2(t, &ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+				},
+			})
+
+			plan, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+			assertNoErrors(t, diags)
+
+			_, diags = ctx.Apply(plan, m)
+			if test.wantErr != "" {
+				if !diags.HasErrors() || !strings.Contains(diags.Err().Error(), test.wantErr) {
+					t.Fatalf("wrong errors\ngot:  %s\nwant: %s", diags.Err(), test.wantErr)
+				}
+			} else {
+				assertNoErrors(t, diags)
+			}
+
+			if len(applied) == 0 || applied[0] != "v0" {
+				t.Fatalf("canary instance was not applied first: %v", applied)
+			}
+			sort.Strings(applied)
+			if diff := cmp.Diff(test.wantApplied, applied); diff != "" {
+				t.Errorf("wrong applied instances\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestContext2Apply_providerCallHook(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "foo"
+}
+`,
+	})
+	addr := mustResourceInstanceAddr("test_object.a")
+
+	hook := &providerCallHook{}
+	ctx := testContext2(t, &ContextOpts{
+		Hooks: []Hook{hook},
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(simpleMockProvider()),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	_, diags = ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	want := []string{
+		"test_object.a PlanResourceChange",
+		"test_object.a PlanResourceChange",
+		"test_object.a ApplyResourceChange",
+	}
+	if diff := cmp.Diff(want, hook.calls); diff != "" {
+		t.Errorf("wrong provider calls for %s\n%s", addr, diff)
+	}
+}
+
+type providerCallHook struct {
+	NilHook
+
+	mu    sync.Mutex
+	calls []string
+}
+
+func (h *providerCallHook) ProviderCall(addr addrs.AbsResourceInstance, method string, elapsed time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls = append(h.calls, addr.String()+" "+method)
+}
+
+func TestContext2Apply_providerForEach(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+locals {
+  regions = toset(["eu", "us"])
+}
+
+provider "test" {
+  alias    = "by_region"
+  for_each = local.regions
+  region   = each.key
+}
+
+resource "test_instance" "a" {
+  for_each = local.regions
+  provider = test.by_region[each.key]
+  ami      = each.key
+}
+`,
+	})
+
+	// Each provider instance records which objects it created and destroyed
+	// under the region it was configured with.
+	var mu sync.Mutex
+	applied := make(map[string]string)
+	factory := func() (providers.Interface, error) {
+		p := testProvider("test")
+		var region string
+		p.ConfigureProviderFn = func(req providers.ConfigureProviderRequest) (resp providers.ConfigureProviderResponse) {
+			region = req.Config.GetAttr("region").AsString()
+			return resp
+		}
+		p.PlanResourceChangeFn = testDiffFn
+		p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+			mu.Lock()
+			defer mu.Unlock()
+			if req.PlannedState.IsNull() {
+				applied["destroy "+req.PriorState.GetAttr("ami").AsString()] = region
+			} else {
+				applied["create "+req.PlannedState.GetAttr("ami").AsString()] = region
+			}
+			return testApplyFn(req)
+		}
+		return p, nil
+	}
+	ps := map[addrs.Provider]providers.Factory{
+		addrs.NewDefaultProvider("test"): factory,
+	}
+
+	ctx := testContext2(t, &ContextOpts{Providers: ps})
+	plan, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	state, diags := ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	for _, region := range []string{"eu", "us"} {
+		addr := mustResourceInstanceAddr(fmt.Sprintf("test_instance.a[%q]", region))
+		obj := state.ResourceInstance(addr).Current
+		if got, want := obj.ProviderKey, addrs.StringKey(region); got != want {
+			t.Errorf("wrong provider key for %s: got %#v, want %#v", addr, got, want)
+		}
+	}
+
+	// Destroying the objects uses the provider instances recorded in the
+	// state, even once the resource is no longer in the configuration.
+	m = testModuleInline(t, map[string]string{
+		"main.tf": `
+provider "test" {
+  alias    = "by_region"
+  for_each = toset(["eu", "us"])
+  region   = each.key
+}
+`,
+	})
+	ctx = testContext2(t, &ContextOpts{Providers: ps})
+	plan, diags = ctx.Plan(m, state, DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	_, diags = ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	want := map[string]string{
+		"create eu":  "eu",
+		"create us":  "us",
+		"destroy eu": "eu",
+		"destroy us": "us",
+	}
+	if diff := cmp.Diff(want, applied); diff != "" {
+		t.Errorf("wrong provider instances used\n%s", diff)
+	}
+}
+
+func TestContext2Apply_sharedOutputs(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+output "vpc_id" {
+  value       = "vpc-123"
+  shared_with = ["app-*", "billing"]
+}
+
+output "private" {
+  value = "secret"
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+	plan, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+	state, diags := ctx.Apply(plan, m)
+	assertNoErrors(t, diags)
+
+	outputs := state.RootModule().OutputValues
+	if diff := cmp.Diff([]string{"app-*", "billing"}, outputs["vpc_id"].SharedWith); diff != "" {
+		t.Errorf("wrong consumers for vpc_id\n%s", diff)
+	}
+	if got := outputs["private"].SharedWith; got != nil {
+		t.Errorf("private output is shared with %#v", got)
+	}
+	if !outputs["vpc_id"].IsSharedWith("app-frontend") {
+		t.Error("vpc_id is not shared with app-frontend")
+	}
+	if outputs["vpc_id"].IsSharedWith("frontend") {
+		t.Error("vpc_id is shared with frontend")
+	}
+}
+
+func TestContext2Plan_providerForEachInvalidKey(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+provider "test" {
+  alias    = "by_region"
+  for_each = toset(["eu", "us"])
+  region   = each.key
+}
+
+resource "test_instance" "a" {
+  provider = test.by_region["ap"]
+}
+`,
+	})
+
+	p := testProvider("test")
+	p.PlanResourceChangeFn = testDiffFn
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	_, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want errors")
+	}
+	if got, want := diags.Err().Error(), "Invalid provider instance key"; !strings.Contains(got, want) {
+		t.Errorf("wrong error\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
@@ -195,8 +195,10 @@ func TestContext2Apply_stop(t *testing.T) {
 	gotEvents := hook.Calls
 	wantEvents := []*testHookCall{
 		{"PreDiff", "indefinite.foo"},
+		{"ProviderCall", "indefinite.foo"},
 		{"PostDiff", "indefinite.foo"},
 		{"PreApply", "indefinite.foo"},
+		{"ProviderCall", "indefinite.foo"},
 		{"PostApply", "indefinite.foo"},
 		{"PostStateUpdate", ""}, // State gets updated one more time to include the apply result.
 	}
@@ -1697,6 +1699,7 @@ func TestContext2Apply_destroyData(t *testing.T) {
 
 	wantHookCalls := []*testHookCall{
 		{"PreApply", "data.null_data_source.testing"},
+		{"ProviderCall", "data.null_data_source.testing"},
 		{"PostApply", "data.null_data_source.testing"},
 		{"PostStateUpdate", ""},
 	}
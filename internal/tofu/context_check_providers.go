@@ -0,0 +1,189 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/lang"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/version"
+)
+
+type CheckProvidersOpts struct {
+	SetVariables InputValues
+}
+
+// ProviderCheckResult is the result of checking a single provider
+// configuration with CheckProviders.
+type ProviderCheckResult struct {
+	Addr     addrs.LocalProviderConfig
+	Provider addrs.Provider
+
+	// Status is StatusPass if the provider was configured and passed its
+	// health check, StatusFail if either of those failed, and
+	// StatusUnknown if the configuration depends on values that won't be
+	// known until the next apply, so the provider couldn't be checked.
+	Status checks.Status
+
+	// HealthChecked is true if the provider implements
+	// providers.HealthChecker and, for a provider plugin, announced the
+	// check_health capability, and so was checked beyond just being
+	// configured.
+	HealthChecked bool
+
+	// Diagnostics are the diagnostics from configuring and checking the
+	// provider, which explain why the check failed.
+	Diagnostics tfdiags.Diagnostics
+}
+
+// CheckProviders configures each provider configuration in the root module,
+// and the default configuration of each other provider that the
+// configuration requires, and checks whether it works using the provider's
+// health check, if it has one, without planning or reading any data
+// sources.
+//
+// The returned diagnostics are only about evaluating the root module. The
+// diagnostics about each provider are in its result, so that one
+// misconfigured provider doesn't prevent checking the others.
+func (c *Context) CheckProviders(config *configs.Config, state *states.State, opts *CheckProvidersOpts) ([]*ProviderCheckResult, tfdiags.Diagnostics) {
+	// The provider configurations can refer to anything in the root module,
+	// so we first evaluate its variables, local values and output values.
+	scope, diags := c.Eval(config, state, addrs.RootModuleInstance, &EvalOpts{
+		SetVariables: opts.SetVariables,
+	})
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	defer c.acquireRun("check providers")()
+
+	type providerConfig struct {
+		addr     addrs.LocalProviderConfig
+		provider addrs.Provider
+		body     hcl.Body
+	}
+	var pcs []providerConfig
+	configured := make(map[addrs.Provider]bool)
+	for _, pc := range config.Module.ProviderConfigs {
+		provider := config.Module.ProviderForLocalConfig(pc.Addr())
+		pcs = append(pcs, providerConfig{
+			addr:     pc.Addr(),
+			provider: provider,
+			body:     pc.Config,
+		})
+		if pc.Alias == "" {
+			configured[provider] = true
+		}
+	}
+	for _, provider := range config.ProviderTypes() {
+		if configured[provider] || provider.IsBuiltIn() {
+			continue
+		}
+		// Providers without a default configuration in the root module are
+		// used with an empty one, which is where credentials from the
+		// environment usually come in.
+		pcs = append(pcs, providerConfig{
+			addr:     addrs.LocalProviderConfig{LocalName: config.Module.LocalNameForProvider(provider)},
+			provider: provider,
+			body:     hcl.EmptyBody(),
+		})
+	}
+	sort.Slice(pcs, func(i, j int) bool {
+		return pcs[i].addr.String() < pcs[j].addr.String()
+	})
+
+	results := make([]*ProviderCheckResult, 0, len(pcs))
+	for _, pc := range pcs {
+		result := &ProviderCheckResult{
+			Addr:     pc.addr,
+			Provider: pc.provider,
+		}
+		results = append(results, result)
+
+		log.Printf("[INFO] Checking provider configuration %s", pc.addr)
+		result.Status, result.HealthChecked, result.Diagnostics = c.checkProvider(scope, pc.addr, pc.provider, pc.body)
+	}
+	return results, diags
+}
+
+func (c *Context) checkProvider(scope *lang.Scope, addr addrs.LocalProviderConfig, providerAddr addrs.Provider, body hcl.Body) (checks.Status, bool, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	if !c.plugins.HasProvider(providerAddr) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Provider not installed",
+			fmt.Sprintf("The provider %s is not installed. Run \"tofu init\" to install it.", providerAddr.ForDisplay()),
+		))
+		return checks.StatusFail, false, diags
+	}
+
+	schema, err := c.plugins.ProviderConfigSchema(providerAddr)
+	if err != nil {
+		diags = diags.Append(err)
+		return checks.StatusFail, false, diags
+	}
+	if schema == nil {
+		schema = &configschema.Block{}
+	}
+	configVal, moreDiags := scope.EvalBlock(body, schema)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return checks.StatusFail, false, diags
+	}
+	configVal, _ = configVal.UnmarkDeep()
+	if !configVal.IsWhollyKnown() {
+		// We don't configure providers with unknown values here, because
+		// the result wouldn't tell whether the real configuration works.
+		return checks.StatusUnknown, false, diags
+	}
+
+	provider, err := c.plugins.NewProviderInstance(providerAddr)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("failed to instantiate provider %s: %w", providerAddr.ForDisplay(), err))
+		return checks.StatusFail, false, diags
+	}
+	defer provider.Close()
+
+	validateResp := provider.ValidateProviderConfig(providers.ValidateProviderConfigRequest{
+		Config: configVal,
+	})
+	diags = diags.Append(validateResp.Diagnostics.InConfigBody(body, addr.String()))
+	if validateResp.Diagnostics.HasErrors() {
+		return checks.StatusFail, false, diags
+	}
+
+	configureResp := provider.ConfigureProvider(providers.ConfigureProviderRequest{
+		TerraformVersion: version.String(),
+		Config:           configVal,
+	})
+	diags = diags.Append(configureResp.Diagnostics.InConfigBody(body, addr.String()))
+	if configureResp.Diagnostics.HasErrors() {
+		return checks.StatusFail, false, diags
+	}
+
+	checker, ok := providers.AsHealthChecker(provider)
+	if !ok {
+		return checks.StatusPass, false, diags
+	}
+	healthResp := checker.CheckHealth(providers.CheckHealthRequest{})
+	diags = diags.Append(healthResp.Diagnostics.InConfigBody(body, addr.String()))
+	if healthResp.Diagnostics.HasErrors() {
+		return checks.StatusFail, true, diags
+	}
+	return checks.StatusPass, true, diags
+}
@@ -0,0 +1,112 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// healthProvider is a MockProvider with a health check that fails when the
+// provider is configured with the region "invalid".
+type healthProvider struct {
+	*MockProvider
+}
+
+func (p *healthProvider) CheckHealth(providers.CheckHealthRequest) providers.CheckHealthResponse {
+	var resp providers.CheckHealthResponse
+	if region := p.ConfigureProviderRequest.Config.GetAttr("region"); region.RawEquals(cty.StringVal("invalid")) {
+		resp.Diagnostics = resp.Diagnostics.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Invalid credentials",
+			"The credentials are not valid in this region.",
+		))
+	}
+	return resp
+}
+
+func TestContextCheckProviders(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "region" {
+  type = string
+}
+
+provider "test" {
+  region = var.region
+}
+
+provider "test" {
+  alias  = "broken"
+  region = "invalid"
+}
+
+provider "test" {
+  alias  = "later"
+  region = test_instance.foo.id
+}
+
+resource "test_instance" "foo" {
+}
+
+resource "other_thing" "foo" {
+}
+`,
+	})
+
+	testFactory := func() (providers.Interface, error) {
+		return &healthProvider{MockProvider: testProvider("test")}, nil
+	}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"):  testFactory,
+			addrs.NewDefaultProvider("other"): testProviderFuncFixed(testProvider("other")),
+		},
+	})
+
+	results, diags := ctx.CheckProviders(m, states.NewState(), &CheckProvidersOpts{
+		SetVariables: InputValues{
+			"region": &InputValue{
+				Value:      cty.StringVal("west"),
+				SourceType: ValueFromCaller,
+			},
+		},
+	})
+	assertNoErrors(t, diags)
+
+	type result struct {
+		status        checks.Status
+		healthChecked bool
+		errors        bool
+	}
+	want := map[string]result{
+		"other":       {status: checks.StatusPass},
+		"test":        {status: checks.StatusPass, healthChecked: true},
+		"test.broken": {status: checks.StatusFail, healthChecked: true, errors: true},
+		"test.later":  {status: checks.StatusUnknown},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("wrong number of results %d; want %d", len(results), len(want))
+	}
+	for _, r := range results {
+		w, ok := want[r.Addr.StringCompact()]
+		if !ok {
+			t.Errorf("unexpected result for %s", r.Addr)
+			continue
+		}
+		got := result{status: r.Status, healthChecked: r.HealthChecked, errors: r.Diagnostics.HasErrors()}
+		if got != w {
+			t.Errorf("wrong result for %s: %#v; want %#v\n%s", r.Addr, got, w, r.Diagnostics.Err())
+		}
+	}
+}
@@ -20,7 +20,7 @@ import (
 
 // This builds a provider function using an EvalContext and some additional information
 // This is split out of BuiltinEvalContext for testing
-func evalContextProviderFunction(providers func(addrs.AbsProviderConfig) providers.Interface, mc *configs.Config, op walkOperation, pf addrs.ProviderFunction, rng tfdiags.SourceRange) (*function.Function, tfdiags.Diagnostics) {
+func evalContextProviderFunction(providers func(addrs.AbsProviderConfig, addrs.InstanceKey) providers.Interface, mc *configs.Config, op walkOperation, pf addrs.ProviderFunction, rng tfdiags.SourceRange) (*function.Function, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
 
 	pr, ok := mc.Module.ProviderRequirements.RequiredProviders[pf.ProviderName]
@@ -40,7 +40,7 @@ func evalContextProviderFunction(providers func(addrs.AbsProviderConfig) provide
 		Alias:    pf.ProviderAlias,
 	}
 
-	provider := providers(absPc)
+	provider := providers(absPc, addrs.NoKey)
 
 	if provider == nil {
 		// Configured provider (NodeApplyableProvider) not required via transform_provider.go.  Instead we should use the unconfigured instance (NodeEvalableProvider) in the root.
@@ -64,7 +64,7 @@ func evalContextProviderFunction(providers func(addrs.AbsProviderConfig) provide
 			}
 		}
 
-		provider = providers(addrs.AbsProviderConfig{Provider: pr.Type})
+		provider = providers(addrs.AbsProviderConfig{Provider: pr.Type}, addrs.NoKey)
 		if provider == nil {
 			// This should not be possible
 			return nil, diags.Append(&hcl.Diagnostic{
@@ -0,0 +1,153 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+	"github.com/opentofu/opentofu/version"
+)
+
+type InvokeOpts struct {
+	SetVariables InputValues
+
+	// ActionType is the name of the provider-defined action to invoke. Like
+	// a resource type, it implies the provider that offers it.
+	ActionType string
+
+	// Config is the body that sets the arguments of the action, whose
+	// expressions are evaluated in the scope of the root module and so can
+	// refer to its resources, variables and local values.
+	Config hcl.Body
+}
+
+// Invoke runs a provider-defined action, returning the result that the
+// provider reported for it, or cty.NilVal if it has none.
+//
+// The provider is configured using the default configuration for it in the
+// root module, if any, which is evaluated in the same scope as the arguments
+// of the action. Invoking an action doesn't change the state: if the action
+// changes remote objects then the next plan detects that as usual.
+func (c *Context) Invoke(config *configs.Config, state *states.State, opts *InvokeOpts) (cty.Value, tfdiags.Diagnostics) {
+	// The arguments can refer to anything in the root module, so we first
+	// evaluate its variables, local values and output values.
+	scope, diags := c.Eval(config, state, addrs.RootModuleInstance, &EvalOpts{
+		SetVariables: opts.SetVariables,
+	})
+	if diags.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	defer c.acquireRun("invoke")()
+
+	// The implied provider of an action type is found in the same way as
+	// for a resource type.
+	localName := addrs.Resource{Type: opts.ActionType}.ImpliedProvider()
+	providerAddr := config.Module.ImpliedProviderForUnqualifiedType(localName)
+	if !c.plugins.HasProvider(providerAddr) {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported action type",
+			fmt.Sprintf("The action type %q would be offered by the provider %s, which is not required by the configuration.", opts.ActionType, providerAddr.ForDisplay()),
+		))
+		return cty.NilVal, diags
+	}
+
+	provider, err := c.plugins.NewProviderInstance(providerAddr)
+	if err != nil {
+		diags = diags.Append(fmt.Errorf("failed to instantiate provider %s: %w", providerAddr.ForDisplay(), err))
+		return cty.NilVal, diags
+	}
+	defer provider.Close()
+
+	invoker, ok := providers.AsActionInvoker(provider)
+	if !ok {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported action type",
+			fmt.Sprintf("The provider %s doesn't offer any actions, so it can't invoke %q.", providerAddr.ForDisplay(), opts.ActionType),
+		))
+		return cty.NilVal, diags
+	}
+	schemasResp := invoker.GetActionSchemas()
+	diags = diags.Append(schemasResp.Diagnostics)
+	if schemasResp.Diagnostics.HasErrors() {
+		return cty.NilVal, diags
+	}
+	schema, ok := schemasResp.Actions[opts.ActionType]
+	if !ok || schema.Block == nil {
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Unsupported action type",
+			fmt.Sprintf("The provider %s doesn't offer an action named %q.", providerAddr.ForDisplay(), opts.ActionType),
+		))
+		return cty.NilVal, diags
+	}
+
+	// The arguments are checked before the provider is configured, so that
+	// we don't contact any remote API if they are invalid.
+	argsVal, moreDiags := scope.EvalBlock(opts.Config, schema.Block)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	argsVal, _ = argsVal.UnmarkDeep()
+	if !argsVal.IsWhollyKnown() {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid action arguments",
+			Detail:   "The arguments of an action must be known, but some of them depend on values that won't be known until after the next apply.",
+			Subject:  opts.Config.MissingItemRange().Ptr(),
+		})
+		return cty.NilVal, diags
+	}
+
+	providerBody := hcl.EmptyBody()
+	if pc, ok := config.Module.ProviderConfigs[localName]; ok {
+		providerBody = pc.Config
+	}
+	providerSchema, err := c.plugins.ProviderConfigSchema(providerAddr)
+	if err != nil {
+		diags = diags.Append(err)
+		return cty.NilVal, diags
+	}
+	if providerSchema == nil {
+		providerSchema = &configschema.Block{}
+	}
+	providerVal, moreDiags := scope.EvalBlock(providerBody, providerSchema)
+	diags = diags.Append(moreDiags)
+	if moreDiags.HasErrors() {
+		return cty.NilVal, diags
+	}
+	providerVal, _ = providerVal.UnmarkDeep()
+
+	configureResp := provider.ConfigureProvider(providers.ConfigureProviderRequest{
+		TerraformVersion: version.String(),
+		Config:           providerVal,
+	})
+	diags = diags.Append(configureResp.Diagnostics.InConfigBody(providerBody, providerAddr.ForDisplay()))
+	if configureResp.Diagnostics.HasErrors() {
+		return cty.NilVal, diags
+	}
+
+	log.Printf("[INFO] Invoking action %s", opts.ActionType)
+	resp := invoker.InvokeAction(providers.InvokeActionRequest{
+		TypeName: opts.ActionType,
+		Config:   argsVal,
+	})
+	diags = diags.Append(resp.Diagnostics.InConfigBody(opts.Config, opts.ActionType))
+	return resp.Result, diags
+}
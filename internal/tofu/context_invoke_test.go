@@ -0,0 +1,188 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// actionProvider is a MockProvider that offers a "test_reboot" action.
+type actionProvider struct {
+	*MockProvider
+
+	invoked *providers.InvokeActionRequest
+}
+
+func (p *actionProvider) GetActionSchemas() providers.GetActionSchemasResponse {
+	return providers.GetActionSchemasResponse{
+		Actions: map[string]providers.Schema{
+			"test_reboot": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"id":    {Type: cty.String, Required: true},
+						"force": {Type: cty.Bool, Optional: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (p *actionProvider) InvokeAction(req providers.InvokeActionRequest) providers.InvokeActionResponse {
+	p.invoked = &req
+	return providers.InvokeActionResponse{
+		Result: cty.StringVal("rebooted " + req.Config.GetAttr("id").AsString()),
+	}
+}
+
+func testInvokeBody(t *testing.T, src string) hcl.Body {
+	t.Helper()
+	f, diags := hclsyntax.ParseConfig([]byte(src), "invoke.tf", hcl.InitialPos)
+	if diags.HasErrors() {
+		t.Fatal(diags.Error())
+	}
+	return f.Body
+}
+
+func TestContextInvoke(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "region" {
+  type = string
+}
+
+provider "test" {
+  region = var.region
+}
+
+resource "test_instance" "foo" {
+}
+`,
+	})
+	state := states.BuildState(func(s *states.SyncState) {
+		s.SetResourceInstanceCurrent(
+			mustResourceInstanceAddr("test_instance.foo"),
+			&states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"id":"i-abc123"}`),
+				Status:    states.ObjectReady,
+			},
+			mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+		)
+	})
+
+	p := &actionProvider{MockProvider: testProvider("test")}
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	result, diags := ctx.Invoke(m, state, &InvokeOpts{
+		SetVariables: InputValues{
+			"region": &InputValue{
+				Value:      cty.StringVal("west"),
+				SourceType: ValueFromCaller,
+			},
+		},
+		ActionType: "test_reboot",
+		Config:     testInvokeBody(t, `id = test_instance.foo.id`),
+	})
+	assertNoErrors(t, diags)
+
+	if !p.ConfigureProviderCalled {
+		t.Fatal("provider was not configured")
+	}
+	if got, want := p.ConfigureProviderRequest.Config.GetAttr("region"), cty.StringVal("west"); !got.RawEquals(want) {
+		t.Errorf("wrong provider region %#v; want %#v", got, want)
+	}
+	if p.invoked == nil {
+		t.Fatal("action was not invoked")
+	}
+	want := cty.ObjectVal(map[string]cty.Value{
+		"id":    cty.StringVal("i-abc123"),
+		"force": cty.NullVal(cty.Bool),
+	})
+	if !p.invoked.Config.RawEquals(want) {
+		t.Errorf("wrong arguments\ngot:  %#v\nwant: %#v", p.invoked.Config, want)
+	}
+	if got, want := result, cty.StringVal("rebooted i-abc123"); !got.RawEquals(want) {
+		t.Errorf("wrong result %#v; want %#v", got, want)
+	}
+}
+
+func TestContextInvoke_errors(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_instance" "foo" {
+}
+`,
+	})
+
+	tests := map[string]struct {
+		provider   providers.Interface
+		actionType string
+		args       string
+		wantErr    string
+	}{
+		"provider without actions": {
+			provider:   testProvider("test"),
+			actionType: "test_reboot",
+			args:       `id = "foo"`,
+			wantErr:    "doesn't offer any actions",
+		},
+		"unknown action": {
+			provider:   &actionProvider{MockProvider: testProvider("test")},
+			actionType: "test_shutdown",
+			args:       `id = "foo"`,
+			wantErr:    `doesn't offer an action named "test_shutdown"`,
+		},
+		"provider not required": {
+			provider:   &actionProvider{MockProvider: testProvider("test")},
+			actionType: "other_reboot",
+			args:       `id = "foo"`,
+			wantErr:    "not required by the configuration",
+		},
+		"missing argument": {
+			provider:   &actionProvider{MockProvider: testProvider("test")},
+			actionType: "test_reboot",
+			args:       `force = true`,
+			wantErr:    `The argument "id" is required`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := testContext2(t, &ContextOpts{
+				Providers: map[addrs.Provider]providers.Factory{
+					addrs.NewDefaultProvider("test"): testProviderFuncFixed(test.provider),
+				},
+			})
+			_, diags := ctx.Invoke(m, states.NewState(), &InvokeOpts{
+				ActionType: test.actionType,
+				Config:     testInvokeBody(t, test.args),
+			})
+			if !diags.HasErrors() {
+				t.Fatal("expected errors")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, test.wantErr) {
+				t.Fatalf("wrong error\ngot:  %s\nwant: %s", got, test.wantErr)
+			}
+			if p, ok := test.provider.(*actionProvider); ok && p.invoked != nil {
+				t.Fatal("action was invoked")
+			}
+		})
+	}
+}
@@ -41,6 +41,29 @@ type PlanOpts struct {
 	// instance using its corresponding provider.
 	SkipRefresh bool
 
+	// RefreshFilter, if non-empty, restricts the refresh step to only the
+	// managed resource instances that are contained in at least one of the
+	// given addresses. All other managed resource instances are planned
+	// using their prior state as-is, as if SkipRefresh were set for them.
+	//
+	// Unlike Targets, this doesn't limit which resource instances are
+	// included in the plan; it only affects which of them are refreshed.
+	// RefreshFilter has no effect if SkipRefresh is set.
+	RefreshFilter []addrs.Targetable
+
+	// AdoptExisting, when set, asks providers that implement
+	// providers.ResourceFinder to search for an existing remote object for
+	// each resource instance that has no object in the prior state. If a
+	// matching object is found, OpenTofu plans to import it instead of
+	// planning to create a new object.
+	AdoptExisting bool
+
+	// RefreshParallelism optionally limits the number of concurrent calls
+	// made to read the current state of remote objects for each of the
+	// given providers during the refresh step. Providers not included are
+	// limited only by the overall parallelism of the graph walk.
+	RefreshParallelism map[addrs.Provider]int
+
 	// PreDestroyRefresh indicated that this is being passed to a plan used to
 	// refresh the state immediately before a destroy plan.
 	// FIXME: This is a temporary fix to allow the pre-destroy refresh to
@@ -686,11 +709,12 @@ func (c *Context) planWalk(config *configs.Config, prevRunState *states.State, o
 	// we can now walk.
 	changes := plans.NewChanges()
 	walker, walkDiags := c.walk(graph, walkOp, &graphWalkOpts{
-		Config:            config,
-		InputState:        prevRunState,
-		Changes:           changes,
-		MoveResults:       moveResults,
-		PlanTimeTimestamp: timestamp,
+		Config:             config,
+		InputState:         prevRunState,
+		Changes:            changes,
+		MoveResults:        moveResults,
+		PlanTimeTimestamp:  timestamp,
+		RefreshParallelism: opts.RefreshParallelism,
 	})
 	diags = diags.Append(walker.NonFatalDiagnostics)
 	diags = diags.Append(walkDiags)
@@ -764,6 +788,8 @@ func (c *Context) planGraph(config *configs.Config, prevRunState *states.State,
 			Plugins:            c.plugins,
 			Targets:            opts.Targets,
 			ForceReplace:       opts.ForceReplace,
+			RefreshFilter:      opts.RefreshFilter,
+			AdoptExisting:      opts.AdoptExisting,
 			skipRefresh:        opts.SkipRefresh,
 			preDestroyRefresh:  opts.PreDestroyRefresh,
 			Operation:          walkPlan,
@@ -780,6 +806,7 @@ func (c *Context) planGraph(config *configs.Config, prevRunState *states.State,
 			RootVariableValues: opts.SetVariables,
 			Plugins:            c.plugins,
 			Targets:            opts.Targets,
+			RefreshFilter:      opts.RefreshFilter,
 			skipRefresh:        opts.SkipRefresh,
 			skipPlanChanges:    true, // this activates "refresh only" mode.
 			Operation:          walkPlan,
@@ -6892,6 +6892,77 @@ func TestContext2Plan_variableCustomValidationsSensitive(t *testing.T) {
 	}
 }
 
+func TestContext2Plan_variableCustomValidationsCrossVariable(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+variable "min" {
+  type    = number
+  default = 1
+}
+
+variable "max" {
+  type = number
+
+  validation {
+    condition     = var.max >= var.min
+    error_message = "Must be at least ${var.min}."
+  }
+}
+
+module "child" {
+  source = "./child"
+  size   = var.max
+}
+`,
+		"child/main.tf": `
+variable "limit" {
+  type    = number
+  default = 5
+}
+
+variable "size" {
+  type = number
+
+  validation {
+    condition     = var.size <= var.limit && path.module != ""
+    error_message = "Must be at most ${var.limit}."
+  }
+}
+`,
+	})
+
+	tests := map[string]struct {
+		max     int
+		wantErr string
+	}{
+		"valid":       {max: 3},
+		"root fails":  {max: 0, wantErr: "Must be at least 1."},
+		"child fails": {max: 10, wantErr: "Must be at most 5."},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := testContext2(t, &ContextOpts{})
+			_, diags := ctx.Plan(m, states.NewState(), &PlanOpts{
+				Mode: plans.NormalMode,
+				SetVariables: InputValues{
+					"min": &InputValue{Value: cty.NilVal, SourceType: ValueFromConfig},
+					"max": &InputValue{Value: cty.NumberIntVal(int64(test.max)), SourceType: ValueFromCLIArg},
+				},
+			})
+			if test.wantErr == "" {
+				assertNoErrors(t, diags)
+				return
+			}
+			if !diags.HasErrors() {
+				t.Fatal("succeeded; want errors")
+			}
+			if got := diags.Err().Error(); !strings.Contains(got, test.wantErr) {
+				t.Fatalf("wrong error:\ngot:  %s\nwant: message containing %q", got, test.wantErr)
+			}
+		})
+	}
+}
+
 func TestContext2Plan_nullOutputNoOp(t *testing.T) {
 	// this should always plan a NoOp change for the output
 	m := testModuleInline(t, map[string]string{
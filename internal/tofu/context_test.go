@@ -39,6 +39,10 @@ var (
 	typeComparer  = cmp.Comparer(cty.Type.Equals)
 	valueComparer = cmp.Comparer(cty.Value.RawEquals)
 	valueTrans    = cmp.Transformer("hcl2shim", hcl2shim.ConfigValueFromHCL2)
+
+	// ignoreCheckRuleResults is for tests that are concerned only with the
+	// overall result of the checks for each object.
+	ignoreCheckRuleResults = cmpopts.IgnoreFields(states.CheckResultObject{}, "Rules")
 )
 
 func TestNewContextRequiredVersion(t *testing.T) {
@@ -6,7 +6,11 @@
 package tofu
 
 import (
+	"fmt"
 	"log"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
@@ -72,6 +76,9 @@ func (c *Context) Validate(config *configs.Config) tfdiags.Diagnostics {
 		return diags
 	}
 
+	diags = diags.Append(validateDeprecatedVariables(config))
+	diags = diags.Append(validateDeprecatedOutputs(config, graph))
+
 	walker, walkDiags := c.walk(graph, walkValidate, &graphWalkOpts{
 		Config: config,
 	})
@@ -83,3 +90,128 @@ func (c *Context) Validate(config *configs.Config) tfdiags.Diagnostics {
 
 	return diags
 }
+
+// validateDeprecatedVariables returns a warning for each module call that
+// sets an input variable that the called module declares as deprecated.
+func validateDeprecatedVariables(config *configs.Config) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	config.DeepEach(func(c *configs.Config) {
+		if c.Parent == nil {
+			return
+		}
+		call, exists := c.Parent.Module.ModuleCalls[c.Path[len(c.Path)-1]]
+		if !exists {
+			return
+		}
+
+		schema := &hcl.BodySchema{}
+		for _, v := range c.Module.Variables {
+			if v.Deprecated != "" {
+				schema.Attributes = append(schema.Attributes, hcl.AttributeSchema{Name: v.Name})
+			}
+		}
+		if len(schema.Attributes) == 0 {
+			return
+		}
+		// Errors in the call arguments are reported by the graph builder,
+		// so we ignore them here.
+		content, _, _ := call.Config.PartialContent(schema)
+		if content == nil {
+			return
+		}
+
+		names := make([]string, 0, len(content.Attributes))
+		for name := range content.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagWarning,
+				Summary:  "Deprecated variable",
+				Detail: fmt.Sprintf(
+					"The input variable %q of %s is deprecated: %s",
+					name, c.Path, c.Module.Variables[name].Deprecated,
+				),
+				Subject: content.Attributes[name].NameRange.Ptr(),
+				Extra:   tfdiags.WarningDeprecation,
+			})
+		}
+	})
+
+	return diags
+}
+
+// validateDeprecatedOutputs returns a warning for each reference in the given
+// graph to an output value that the module declaring it marks as deprecated.
+func validateDeprecatedOutputs(config *configs.Config, graph *Graph) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	type deprecatedRef struct {
+		rng    hcl.Range
+		output *configs.Output
+		module addrs.Module
+	}
+	// The same expression can be referenced by more than one node, so we
+	// report each reference only once.
+	refs := make(map[string]deprecatedRef)
+	for _, v := range graph.Vertices() {
+		rn, ok := v.(GraphNodeReferencer)
+		if !ok {
+			continue
+		}
+		if _, ok := v.(GraphNodeModulePath); !ok {
+			continue
+		}
+		path := vertexReferencePath(v)
+
+		for _, ref := range rn.References() {
+			var call addrs.ModuleCall
+			var name string
+			switch addr := ref.Subject.(type) {
+			case addrs.ModuleCallInstanceOutput:
+				call, name = addr.Call.Call, addr.Name
+			case addrs.ModuleCallOutput:
+				call, name = addr.Call, addr.Name
+			default:
+				continue
+			}
+			child := config.Descendent(path.Child(call.Name))
+			if child == nil {
+				continue
+			}
+			output, exists := child.Module.Outputs[name]
+			if !exists || output.Deprecated == "" {
+				continue
+			}
+			rng := ref.SourceRange.ToHCL()
+			refs[rng.String()] = deprecatedRef{rng: rng, output: output, module: child.Path}
+		}
+	}
+
+	sorted := make([]deprecatedRef, 0, len(refs))
+	for _, ref := range refs {
+		sorted = append(sorted, ref)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].rng.Filename != sorted[j].rng.Filename {
+			return sorted[i].rng.Filename < sorted[j].rng.Filename
+		}
+		return sorted[i].rng.Start.Byte < sorted[j].rng.Start.Byte
+	})
+	for _, ref := range sorted {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagWarning,
+			Summary:  "Deprecated output value",
+			Detail: fmt.Sprintf(
+				"The output value %q of %s is deprecated: %s",
+				ref.output.Name, ref.module, ref.output.Deprecated,
+			),
+			Subject: ref.rng.Ptr(),
+			Extra:   tfdiags.WarningDeprecation,
+		})
+	}
+
+	return diags
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -2487,3 +2488,88 @@ locals {
 		t.Fatalf("expected deprecated warning, got: %q\n", warn)
 	}
 }
+
+func TestContext2Validate_deprecatedVariablesAndOutputs(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+module "child" {
+  source = "./child"
+  count  = 2
+  old    = "a"
+  new    = "b"
+}
+
+locals {
+  old = module.child[0].old
+  new = module.child[0].new
+}
+
+output "old" {
+  value = module.child[1].old
+}
+`,
+		"child/main.tf": `
+variable "old" {
+  type       = string
+  default    = null
+  deprecated = "Use new instead."
+}
+
+variable "new" {
+  type = string
+}
+
+output "old" {
+  value      = var.old
+  deprecated = "Use the new output instead."
+}
+
+output "new" {
+  value = var.new
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+	diags := ctx.Validate(m)
+	assertNoErrors(t, diags)
+
+	var got []string
+	for _, diag := range diags {
+		desc := diag.Description()
+		got = append(got, fmt.Sprintf("%s: %s (line %d)", desc.Summary, desc.Detail, diag.Source().Subject.Start.Line))
+	}
+	want := []string{
+		`Deprecated variable: The input variable "old" of module.child is deprecated: Use new instead. (line 5)`,
+		`Deprecated output value: The output value "old" of module.child is deprecated: Use the new output instead. (line 10)`,
+		`Deprecated output value: The output value "old" of module.child is deprecated: Use the new output instead. (line 15)`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("wrong warnings\n%s", diff)
+	}
+}
+
+func TestContext2Validate_ephemeralResourceArgument(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = tls_self_signed_cert(ed25519_keypair().private_key_pem, "example.com", 1)
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	diags := ctx.Validate(m)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want errors")
+	}
+	if got, want := diags.Err().Error(), "Ephemeral value not allowed"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error:\ngot:  %s\nwant: message containing %q", got, want)
+	}
+}
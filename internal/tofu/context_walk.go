@@ -9,6 +9,7 @@ import (
 	"log"
 	"time"
 
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/instances"
@@ -44,10 +45,15 @@ type graphWalkOpts struct {
 	PlanTimeTimestamp time.Time
 
 	MoveResults refactoring.MoveResults
+
+	// RefreshParallelism optionally limits the number of concurrent calls
+	// to read the current state of remote objects for specific providers.
+	RefreshParallelism map[addrs.Provider]int
 }
 
 func (c *Context) walk(graph *Graph, operation walkOperation, opts *graphWalkOpts) (*ContextGraphWalker, tfdiags.Diagnostics) {
 	log.Printf("[DEBUG] Starting graph walk: %s", operation.String())
+	recordGraphNodes(graph, operation)
 
 	walker := c.graphWalker(operation, opts)
 
@@ -154,5 +160,7 @@ func (c *Context) graphWalker(operation walkOperation, opts *graphWalkOpts) *Con
 		StopContext:      c.runContext,
 		PlanTimestamp:    opts.PlanTimeTimestamp,
 		Encryption:       c.encryption,
+
+		RefreshParallelism: opts.RefreshParallelism,
 	}
 }
@@ -6,6 +6,8 @@
 package tofu
 
 import (
+	"time"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
@@ -38,22 +40,53 @@ type EvalContext interface {
 	// Input is the UIInput object for interacting with the UI.
 	Input() UIInput
 
-	// InitProvider initializes the provider with the given address, and returns
-	// the implementation of the resource provider or an error.
+	// WhileWaiting calls the given function without holding the slot that
+	// the calling node holds while it executes, so that waiting for the user
+	// or for a remote object doesn't stop other nodes from running. The
+	// function must only wait, and not access the state or call providers.
+	// WhileWaiting returns once the function has returned and the node holds
+	// a slot again.
+	WhileWaiting(func())
+
+	// InitProvider initializes the provider with the given address and
+	// instance key, and returns the implementation of the resource provider
+	// or an error. The instance key is addrs.NoKey unless the provider
+	// configuration has for_each.
 	//
 	// It is an error to initialize the same provider more than once. This
 	// method will panic if the module instance address of the given provider
 	// configuration does not match the Path() of the EvalContext.
-	InitProvider(addr addrs.AbsProviderConfig) (providers.Interface, error)
+	InitProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) (providers.Interface, error)
 
-	// Provider gets the provider instance with the given address (already
-	// initialized) or returns nil if the provider isn't initialized.
+	// Provider gets the provider instance with the given address and instance
+	// key (already initialized) or returns nil if the provider isn't
+	// initialized.
 	//
 	// This method expects an _absolute_ provider configuration address, since
 	// resources in one module are able to use providers from other modules.
 	// InitProvider must've been called on the EvalContext of the module
 	// that owns the given provider before calling this method.
-	Provider(addrs.AbsProviderConfig) providers.Interface
+	Provider(addrs.AbsProviderConfig, addrs.InstanceKey) providers.Interface
+
+	// ReadResource asks the given provider instance, which must belong to the
+	// provider configuration with the given address and instance key, to read
+	// the current state of a remote object. Calls made through this method
+	// honor any per-provider concurrency limits in effect for the current
+	// walk, and may be batched together for providers that support bulk
+	// reads.
+	ReadResource(addrs.AbsProviderConfig, addrs.InstanceKey, providers.Interface, providers.ReadResourceRequest) providers.ReadResourceResponse
+
+	// PlanResourceChange asks the given provider instance, which must belong
+	// to the provider configuration with the given address and instance key,
+	// to plan a change to a resource instance. The response may be reused
+	// from an earlier plan when a PlanCache is in use.
+	PlanResourceChange(addrs.AbsProviderConfig, addrs.InstanceKey, providers.Interface, providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse
+
+	// OperationTimeout returns the default limit on how long each call to a
+	// provider to read, create, update or delete a resource instance may
+	// take, for resources that don't set their own. Zero means there is no
+	// limit.
+	OperationTimeout() time.Duration
 
 	// ProviderSchema retrieves the schema for a particular provider, which
 	// must have already been initialized with InitProvider.
@@ -62,7 +95,8 @@ type EvalContext interface {
 	// resources in one module are able to use providers from other modules.
 	ProviderSchema(addrs.AbsProviderConfig) (providers.ProviderSchema, error)
 
-	// CloseProvider closes provider connections that aren't needed anymore.
+	// CloseProvider closes provider connections that aren't needed anymore,
+	// for all instances of the given provider configuration.
 	//
 	// This method will panic if the module instance address of the given
 	// provider configuration does not match the Path() of the EvalContext.
@@ -75,7 +109,7 @@ type EvalContext interface {
 	//
 	// This method will panic if the module instance address of the given
 	// provider configuration does not match the Path() of the EvalContext.
-	ConfigureProvider(addrs.AbsProviderConfig, cty.Value) tfdiags.Diagnostics
+	ConfigureProvider(addrs.AbsProviderConfig, addrs.InstanceKey, cty.Value) tfdiags.Diagnostics
 
 	// ProviderInput and SetProviderInput are used to configure providers
 	// from user input.
@@ -9,7 +9,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -38,6 +41,10 @@ type BuiltinEvalContext struct {
 	// StopContext is the context used to track whether we're complete
 	StopContext context.Context
 
+	// ParallelSem limits how many graph nodes are executed at once. The
+	// node using this context holds one of its slots.
+	ParallelSem *prioritySemaphore
+
 	// PathValue is the Path that this context is operating within.
 	PathValue addrs.ModuleInstance
 
@@ -71,6 +78,18 @@ type BuiltinEvalContext struct {
 	ProviderLock        *sync.Mutex
 	ProviderCache       map[string]providers.Interface
 	ProviderInputConfig map[string]map[string]cty.Value
+	ResourceReaders     *resourceReaders
+	PlanCache           *PlanCache
+
+	// OperationTimeoutValue is the default limit on how long each call to
+	// a provider to read, create, update or delete a resource instance may
+	// take.
+	OperationTimeoutValue time.Duration
+
+	// ExprCache is shared by all of the evaluation scopes created during a
+	// graph walk, so that identical expressions evaluated with identical
+	// inputs are only evaluated once.
+	ExprCache *lang.ExprCache
 
 	ProvisionerLock  *sync.Mutex
 	ProvisionerCache map[string]provisioners.Interface
@@ -105,6 +124,18 @@ func (ctx *BuiltinEvalContext) Stopped() <-chan struct{} {
 	return ctx.StopContext.Done()
 }
 
+func (ctx *BuiltinEvalContext) WhileWaiting(fn func()) {
+	if ctx.ParallelSem == nil {
+		fn()
+		return
+	}
+	ctx.ParallelSem.Release()
+	// A node that was already running resumes before any nodes that are
+	// still waiting to start.
+	defer ctx.ParallelSem.Acquire(math.MaxInt)
+	fn()
+}
+
 func (ctx *BuiltinEvalContext) Hook(fn func(Hook) (HookAction, error)) error {
 	for _, h := range ctx.Hooks {
 		action, err := fn(h)
@@ -129,15 +160,15 @@ func (ctx *BuiltinEvalContext) Input() UIInput {
 	return ctx.InputValue
 }
 
-func (ctx *BuiltinEvalContext) InitProvider(addr addrs.AbsProviderConfig) (providers.Interface, error) {
+func (ctx *BuiltinEvalContext) InitProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) (providers.Interface, error) {
 	ctx.ProviderLock.Lock()
 	defer ctx.ProviderLock.Unlock()
 
-	key := addr.String()
+	cacheKey := providerInstanceCacheKey(addr, key)
 
 	// If we have already initialized, it is an error
-	if _, ok := ctx.ProviderCache[key]; ok {
-		return nil, fmt.Errorf("%s is already initialized", addr)
+	if _, ok := ctx.ProviderCache[cacheKey]; ok {
+		return nil, fmt.Errorf("%s is already initialized", cacheKey)
 	}
 
 	p, err := ctx.Plugins.NewProviderInstance(addr.Provider)
@@ -145,17 +176,31 @@ func (ctx *BuiltinEvalContext) InitProvider(addr addrs.AbsProviderConfig) (provi
 		return nil, err
 	}
 
-	log.Printf("[TRACE] BuiltinEvalContext: Initialized %q provider for %s", addr.String(), addr)
-	ctx.ProviderCache[key] = p
+	log.Printf("[TRACE] BuiltinEvalContext: Initialized %q provider for %s", addr.String(), cacheKey)
+	ctx.ProviderCache[cacheKey] = p
 
 	return p, nil
 }
 
-func (ctx *BuiltinEvalContext) Provider(addr addrs.AbsProviderConfig) providers.Interface {
+func (ctx *BuiltinEvalContext) Provider(addr addrs.AbsProviderConfig, key addrs.InstanceKey) providers.Interface {
 	ctx.ProviderLock.Lock()
 	defer ctx.ProviderLock.Unlock()
 
-	return ctx.ProviderCache[addr.String()]
+	return ctx.ProviderCache[providerInstanceCacheKey(addr, key)]
+}
+
+func (ctx *BuiltinEvalContext) ReadResource(addr addrs.AbsProviderConfig, key addrs.InstanceKey, provider providers.Interface, req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	if ctx.ResourceReaders == nil {
+		return provider.ReadResource(req)
+	}
+	return ctx.ResourceReaders.ReadResource(addr, key, provider, req)
+}
+
+func (ctx *BuiltinEvalContext) PlanResourceChange(addr addrs.AbsProviderConfig, key addrs.InstanceKey, provider providers.Interface, req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	if ctx.PlanCache == nil {
+		return provider.PlanResourceChange(req)
+	}
+	return ctx.PlanCache.planResourceChange(addr, key, provider, req)
 }
 
 func (ctx *BuiltinEvalContext) ProviderSchema(addr addrs.AbsProviderConfig) (providers.ProviderSchema, error) {
@@ -166,17 +211,22 @@ func (ctx *BuiltinEvalContext) CloseProvider(addr addrs.AbsProviderConfig) error
 	ctx.ProviderLock.Lock()
 	defer ctx.ProviderLock.Unlock()
 
-	key := addr.String()
-	provider := ctx.ProviderCache[key]
-	if provider != nil {
-		delete(ctx.ProviderCache, key)
-		return provider.Close()
+	// The instances of a provider configuration with for_each are cached
+	// under the configuration address followed by their instance keys.
+	base := addr.String()
+	var diags tfdiags.Diagnostics
+	for cacheKey, provider := range ctx.ProviderCache {
+		if cacheKey != base && !strings.HasPrefix(cacheKey, base+"[") {
+			continue
+		}
+		delete(ctx.ProviderCache, cacheKey)
+		diags = diags.Append(provider.Close())
 	}
 
-	return nil
+	return diags.Err()
 }
 
-func (ctx *BuiltinEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, cfg cty.Value) tfdiags.Diagnostics {
+func (ctx *BuiltinEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, cfg cty.Value) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 	if !addr.Module.Equal(ctx.Path().Module()) {
 		// This indicates incorrect use of ConfigureProvider: it should be used
@@ -184,9 +234,9 @@ func (ctx *BuiltinEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, c
 		panic(fmt.Sprintf("%s configured by wrong module %s", addr, ctx.Path()))
 	}
 
-	p := ctx.Provider(addr)
+	p := ctx.Provider(addr, key)
 	if p == nil {
-		diags = diags.Append(fmt.Errorf("%s not initialized", addr))
+		diags = diags.Append(fmt.Errorf("%s not initialized", providerInstanceCacheKey(addr, key)))
 		return diags
 	}
 
@@ -196,6 +246,9 @@ func (ctx *BuiltinEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, c
 	}
 
 	resp := p.ConfigureProvider(req)
+	if ctx.PlanCache != nil && !resp.Diagnostics.HasErrors() {
+		ctx.PlanCache.configureProvider(addr, key, cfg)
+	}
 	return resp.Diagnostics
 }
 
@@ -318,8 +371,9 @@ func (ctx *BuiltinEvalContext) EvaluateReplaceTriggeredBy(expr hcl.Expression, r
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Reference to undeclared resource`,
-			Detail:   fmt.Sprintf(`A resource %s has not been declared in %s`, ref.Subject, moduleDisplayAddr(ctx.Path())),
+			Detail:   fmt.Sprintf(`A resource %s has not been declared in %s%s`, ref.Subject, moduleDisplayAddr(ctx.Path()), resourceSuggestion(cfg.Module, resourceAddr)),
 			Subject:  expr.Range().Ptr(),
+			Extra:    tfdiags.CodeUndeclaredResource,
 		})
 		return nil, false, diags
 	}
@@ -522,13 +576,16 @@ func (ctx *BuiltinEvalContext) EvaluationScope(self addrs.Referenceable, source
 	mc := ctx.Evaluator.Config.DescendentForInstance(ctx.PathValue)
 
 	if mc == nil || mc.Module.ProviderRequirements == nil {
-		return ctx.Evaluator.Scope(data, self, source, nil)
+		scope := ctx.Evaluator.Scope(data, self, source, nil)
+		scope.ExprCache = ctx.ExprCache
+		return scope
 	}
 
 	scope := ctx.Evaluator.Scope(data, self, source, func(pf addrs.ProviderFunction, rng tfdiags.SourceRange) (*function.Function, tfdiags.Diagnostics) {
 		return evalContextProviderFunction(ctx.Provider, mc, ctx.Evaluator.Operation, pf, rng)
 	})
 	scope.SetActiveExperiments(mc.Module.ActiveExperiments)
+	scope.ExprCache = ctx.ExprCache
 
 	return scope
 }
@@ -618,6 +675,10 @@ func (ctx *BuiltinEvalContext) ImportResolver() *ImportResolver {
 	return ctx.ImportResolverValue
 }
 
+func (ctx *BuiltinEvalContext) OperationTimeout() time.Duration {
+	return ctx.OperationTimeoutValue
+}
+
 func (ctx *BuiltinEvalContext) GetEncryption() encryption.Encryption {
 	return ctx.Encryption
 }
@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/providers"
@@ -78,11 +79,11 @@ func TestBuildingEvalContextInitProvider(t *testing.T) {
 		Alias:    "foo",
 	}
 
-	_, err := ctx.InitProvider(providerAddrDefault)
+	_, err := ctx.InitProvider(providerAddrDefault, addrs.NoKey)
 	if err != nil {
 		t.Fatalf("error initializing provider test: %s", err)
 	}
-	_, err = ctx.InitProvider(providerAddrAlias)
+	_, err = ctx.InitProvider(providerAddrAlias, addrs.NoKey)
 	if err != nil {
 		t.Fatalf("error initializing provider test.foo: %s", err)
 	}
@@ -91,3 +92,32 @@ func TestBuildingEvalContextInitProvider(t *testing.T) {
 func testBuiltinEvalContext(t *testing.T) *BuiltinEvalContext {
 	return &BuiltinEvalContext{}
 }
+
+func TestBuiltinEvalContextWhileWaiting(t *testing.T) {
+	sem := newPrioritySemaphore(1)
+	ctx := &BuiltinEvalContext{ParallelSem: sem}
+
+	// The calling node holds the only slot.
+	sem.Acquire(0)
+	ctx.WhileWaiting(func() {
+		acquired := make(chan struct{})
+		go func() {
+			sem.Acquire(0)
+			sem.Release()
+			close(acquired)
+		}()
+		select {
+		case <-acquired:
+		case <-time.After(5 * time.Second):
+			t.Fatal("another node couldn't run while waiting")
+		}
+	})
+
+	sem.mu.Lock()
+	free := sem.free
+	sem.mu.Unlock()
+	if free != 0 {
+		t.Fatalf("slot not held again after waiting; %d free", free)
+	}
+	sem.Release()
+}
@@ -6,6 +6,8 @@
 package tofu
 
 import (
+	"time"
+
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hcldec"
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -30,6 +32,8 @@ type MockEvalContext struct {
 	StoppedCalled bool
 	StoppedValue  <-chan struct{}
 
+	WhileWaitingCalled bool
+
 	HookCalled bool
 	HookHook   Hook
 	HookError  error
@@ -37,6 +41,9 @@ type MockEvalContext struct {
 	InputCalled bool
 	InputInput  UIInput
 
+	OperationTimeoutCalled bool
+	OperationTimeoutValue  time.Duration
+
 	InitProviderCalled   bool
 	InitProviderType     string
 	InitProviderAddr     addrs.AbsProviderConfig
@@ -167,6 +174,11 @@ func (c *MockEvalContext) Stopped() <-chan struct{} {
 	return c.StoppedValue
 }
 
+func (c *MockEvalContext) WhileWaiting(fn func()) {
+	c.WhileWaitingCalled = true
+	fn()
+}
+
 func (c *MockEvalContext) Hook(fn func(Hook) (HookAction, error)) error {
 	c.HookCalled = true
 	if c.HookHook != nil {
@@ -183,19 +195,27 @@ func (c *MockEvalContext) Input() UIInput {
 	return c.InputInput
 }
 
-func (c *MockEvalContext) InitProvider(addr addrs.AbsProviderConfig) (providers.Interface, error) {
+func (c *MockEvalContext) InitProvider(addr addrs.AbsProviderConfig, _ addrs.InstanceKey) (providers.Interface, error) {
 	c.InitProviderCalled = true
 	c.InitProviderType = addr.String()
 	c.InitProviderAddr = addr
 	return c.InitProviderProvider, c.InitProviderError
 }
 
-func (c *MockEvalContext) Provider(addr addrs.AbsProviderConfig) providers.Interface {
+func (c *MockEvalContext) Provider(addr addrs.AbsProviderConfig, _ addrs.InstanceKey) providers.Interface {
 	c.ProviderCalled = true
 	c.ProviderAddr = addr
 	return c.ProviderProvider
 }
 
+func (c *MockEvalContext) ReadResource(addr addrs.AbsProviderConfig, _ addrs.InstanceKey, provider providers.Interface, req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	return provider.ReadResource(req)
+}
+
+func (c *MockEvalContext) PlanResourceChange(addr addrs.AbsProviderConfig, _ addrs.InstanceKey, provider providers.Interface, req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	return provider.PlanResourceChange(req)
+}
+
 func (c *MockEvalContext) ProviderSchema(addr addrs.AbsProviderConfig) (providers.ProviderSchema, error) {
 	c.ProviderSchemaCalled = true
 	c.ProviderSchemaAddr = addr
@@ -208,7 +228,7 @@ func (c *MockEvalContext) CloseProvider(addr addrs.AbsProviderConfig) error {
 	return nil
 }
 
-func (c *MockEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, cfg cty.Value) tfdiags.Diagnostics {
+func (c *MockEvalContext) ConfigureProvider(addr addrs.AbsProviderConfig, _ addrs.InstanceKey, cfg cty.Value) tfdiags.Diagnostics {
 
 	c.ConfigureProviderCalled = true
 	c.ConfigureProviderAddr = addr
@@ -418,6 +438,11 @@ func (c *MockEvalContext) InstanceExpander() *instances.Expander {
 	return c.InstanceExpanderExpander
 }
 
+func (c *MockEvalContext) OperationTimeout() time.Duration {
+	c.OperationTimeoutCalled = true
+	return c.OperationTimeoutValue
+}
+
 func (c *MockEvalContext) GetEncryption() encryption.Encryption {
 	return encryption.Disabled()
 }
@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/lang/marks"
 	"github.com/opentofu/opentofu/internal/tfdiags"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
@@ -41,7 +42,7 @@ func evaluateCountExpression(expr hcl.Expression, ctx EvalContext) (int, tfdiags
 			// we can't easily do that right now because the hcl.EvalContext
 			// (which is not the same as the ctx we have in scope here) is
 			// hidden away inside evaluateCountExpressionValue.
-			Extra: diagnosticCausedByUnknown(true),
+			Extra: tfdiags.ExtraWithCode(tfdiags.CodeUnknownCount, diagnosticCausedByUnknown(true)),
 		})
 	}
 
@@ -69,6 +70,18 @@ func evaluateCountExpressionValue(expr hcl.Expression, ctx EvalContext) (cty.Val
 		return nullCount, diags
 	}
 
+	// Ephemeral values aren't allowed, because the number of instances is
+	// saved in the plan and state.
+	if countVal.HasMark(marks.Ephemeral) {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid count argument",
+			Detail:   "Ephemeral values, such as the results of key generation functions, cannot be used as count arguments, because OpenTofu saves the number of instances in the plan and state.",
+			Subject:  expr.Range().Ptr(),
+		})
+		return nullCount, diags
+	}
+
 	// Unmark the count value, sensitive values are allowed in count but not for_each,
 	// as using it here will not disclose the sensitive value
 	countVal, _ = countVal.Unmark()
@@ -7,6 +7,7 @@ package tofu
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -49,3 +50,20 @@ func TestEvaluateCountExpression(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateCountExpression_ephemeral(t *testing.T) {
+	ctx := &MockEvalContext{}
+	ctx.installSimpleEval()
+	expr := hcltest.MockExprLiteral(cty.NumberIntVal(2).Mark(marks.Ephemeral))
+	_, diags := evaluateCountExpression(expr, ctx)
+
+	if len(diags) != 1 {
+		t.Fatalf("got %d diagnostics; want 1", len(diags))
+	}
+	if got, want := diags[0].Description().Summary, "Invalid count argument"; got != want {
+		t.Errorf("wrong diagnostic summary\ngot:  %s\nwant: %s", got, want)
+	}
+	if got, want := diags[0].Description().Detail, "Ephemeral values"; !strings.Contains(got, want) {
+		t.Errorf("wrong diagnostic detail\ngot: %s\nwant substring: %s", got, want)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/lang/marks"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// validateResourceConfigNotEphemeral returns an error for each argument of a
+// resource's configuration whose value is ephemeral, because the arguments
+// of resources are saved in the plan and state.
+//
+// The diagnostics refer to attribute paths, so the caller must use
+// InConfigBody to associate them with the resource's configuration.
+func validateResourceConfigNotEphemeral(configVal cty.Value) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	cty.Walk(configVal, func(path cty.Path, v cty.Value) (bool, error) {
+		if !v.HasMark(marks.Ephemeral) {
+			return true, nil
+		}
+		diags = diags.Append(tfdiags.AttributeValue(
+			tfdiags.Error,
+			"Ephemeral value not allowed",
+			"This argument refers to an ephemeral value, such as the result of a key generation function. OpenTofu saves the arguments of resources in the plan and state, so they cannot refer to ephemeral values. Ephemeral values can be used in provider, provisioner and connection blocks, and in local values and module input variables that are only used there.",
+			path.Copy(),
+		))
+		// The error for this value covers anything nested inside it.
+		return false, nil
+	})
+	return diags
+}
@@ -70,8 +70,20 @@ func evaluateForEachExpressionValue(expr hcl.Expression, ctx EvalContext, allowU
 	diags = diags.Append(forEachDiags)
 
 	// If a whole map is marked, or a set contains marked values (which means the set is then marked)
-	// give an error diagnostic as this value cannot be used in for_each
-	if forEachVal.HasMark(marks.Sensitive) {
+	// give an error diagnostic as this value cannot be used in for_each.
+	// Ephemeral values are usually also sensitive, but the sensitive error
+	// would suggest that nonsensitive could fix it, so we only report the
+	// ephemeral one.
+	if forEachVal.HasMark(marks.Ephemeral) {
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity:    hcl.DiagError,
+			Summary:     "Invalid for_each argument",
+			Detail:      "Ephemeral values, such as the results of key generation functions, cannot be used as for_each arguments, because OpenTofu saves the instance keys in the plan and state.",
+			Subject:     expr.Range().Ptr(),
+			Expression:  expr,
+			EvalContext: hclCtx,
+		})
+	} else if forEachVal.HasMark(marks.Sensitive) {
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity:    hcl.DiagError,
 			Summary:     "Invalid for_each argument",
@@ -143,7 +155,7 @@ func evaluateForEachExpressionValue(expr hcl.Expression, ctx EvalContext, allowU
 				Subject:     expr.Range().Ptr(),
 				Expression:  expr,
 				EvalContext: hclCtx,
-				Extra:       diagnosticCausedByUnknown(true),
+				Extra:       tfdiags.ExtraWithCode(tfdiags.CodeUnknownForEach, diagnosticCausedByUnknown(true)),
 			})
 		}
 		// ensure that we have a map, and not a DynamicValue
@@ -167,7 +179,7 @@ func evaluateForEachExpressionValue(expr hcl.Expression, ctx EvalContext, allowU
 					Subject:     expr.Range().Ptr(),
 					Expression:  expr,
 					EvalContext: hclCtx,
-					Extra:       diagnosticCausedByUnknown(true),
+					Extra:       tfdiags.ExtraWithCode(tfdiags.CodeUnknownForEach, diagnosticCausedByUnknown(true)),
 				})
 			}
 			return cty.UnknownVal(ty), diags
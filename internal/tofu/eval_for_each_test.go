@@ -148,6 +148,12 @@ func TestEvaluateForEachExpression_errors(t *testing.T) {
 			"Sensitive values, or values derived from sensitive values, cannot be used as for_each arguments. If used, the sensitive value could be exposed as a resource instance key.",
 			false, true,
 		},
+		"ephemeral set": {
+			hcltest.MockExprLiteral(cty.SetVal([]cty.Value{cty.StringVal("beep")}).Mark(marks.Ephemeral).Mark(marks.Sensitive)),
+			"Invalid for_each argument",
+			"Ephemeral values, such as the results of key generation functions, cannot be used as for_each arguments",
+			false, false,
+		},
 		"set containing booleans": {
 			hcltest.MockExprLiteral(cty.SetVal([]cty.Value{cty.BoolVal(true)})),
 			"Invalid for_each set argument",
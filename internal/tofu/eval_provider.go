@@ -44,15 +44,17 @@ func buildProviderConfig(ctx EvalContext, addr addrs.AbsProviderConfig, config *
 	}
 }
 
-// getProvider returns the providers.Interface and schema for a given provider.
-func getProvider(ctx EvalContext, addr addrs.AbsProviderConfig) (providers.Interface, providers.ProviderSchema, error) {
+// getProvider returns the providers.Interface and schema for a given provider
+// configuration instance. The instance key is addrs.NoKey unless the provider
+// configuration has for_each.
+func getProvider(ctx EvalContext, addr addrs.AbsProviderConfig, key addrs.InstanceKey) (providers.Interface, providers.ProviderSchema, error) {
 	if addr.Provider.Type == "" {
 		// Should never happen
 		panic("GetProvider used with uninitialized provider configuration address")
 	}
-	provider := ctx.Provider(addr)
+	provider := ctx.Provider(addr, key)
 	if provider == nil {
-		return nil, providers.ProviderSchema{}, fmt.Errorf("provider %s not initialized", addr)
+		return nil, providers.ProviderSchema{}, fmt.Errorf("provider %s not initialized", providerInstanceCacheKey(addr, key))
 	}
 	// Not all callers require a schema, so we will leave checking for a nil
 	// schema to the callers.
@@ -62,3 +64,13 @@ func getProvider(ctx EvalContext, addr addrs.AbsProviderConfig) (providers.Inter
 	}
 	return provider, schema, nil
 }
+
+// providerInstanceCacheKey returns the string that identifies the provider
+// instance for the given provider configuration instance, which is also how
+// that instance is described in messages.
+func providerInstanceCacheKey(addr addrs.AbsProviderConfig, key addrs.InstanceKey) string {
+	if key == addrs.NoKey {
+		return addr.String()
+	}
+	return addr.String() + key.String()
+}
@@ -69,6 +69,8 @@ func prepareFinalInputVariableValue(addr addrs.AbsInputVariableInstance, raw *In
 			nonFileSource = fmt.Sprintf("set using the TF_VAR_%s environment variable", addr.Variable.Name)
 		case ValueFromInput:
 			nonFileSource = "set using an interactive prompt"
+		case ValueFromWorkspace:
+			nonFileSource = "stored with the workspace"
 		default:
 			nonFileSource = "set from outside of the configuration"
 		}
@@ -222,10 +224,11 @@ func evalVariableValidations(addr addrs.AbsInputVariableInstance, config *config
 	// "module" block in the parent module.
 	//
 	// Validation expressions are statically validated (during configuration
-	// loading) to refer only to the variable being validated, so we can
-	// bypass our usual evaluation machinery here and just produce a minimal
-	// evaluation context containing just the required value, and thus avoid
-	// the problem that ctx's evaluation functions refer to the wrong module.
+	// loading) to refer only to input variables and static data about the
+	// module, so we can bypass our usual evaluation machinery here and just
+	// produce a minimal evaluation context containing just the required
+	// values, and thus avoid the problem that ctx's evaluation functions
+	// refer to the wrong module.
 	val := ctx.GetVariableValue(addr)
 	if val == cty.NilVal {
 		diags = diags.Append(&hcl.Diagnostic{
@@ -250,9 +253,15 @@ func evalVariableValidations(addr addrs.AbsInputVariableInstance, config *config
 		if diags.HasErrors() {
 			continue
 		}
-		hclCtx.Variables["var"] = cty.ObjectVal(map[string]cty.Value{
-			config.Name: val,
-		})
+		staticVals, staticDiags := variableValidationStaticValues(addr, validation, ctx)
+		diags = diags.Append(staticDiags)
+		if diags.HasErrors() {
+			continue
+		}
+		for name, v := range staticVals {
+			hclCtx.Variables[name] = v
+		}
+		hclCtx.Variables["var"] = variableValidationVariables(addr, config, val, validation, ctx)
 
 		result, ruleDiags := evalVariableValidation(validation, hclCtx, addr, config, expr, ix)
 		diags = diags.Append(ruleDiags)
@@ -268,6 +277,96 @@ func evalVariableValidations(addr addrs.AbsInputVariableInstance, config *config
 	return diags
 }
 
+// variableValidationReferences returns the references that the validation
+// rules of the given variable make to other variables of the same module.
+func variableValidationReferences(config *configs.Variable) []*addrs.Reference {
+	if config == nil {
+		return nil
+	}
+	var refs []*addrs.Reference
+	for _, validation := range config.Validations {
+		for _, expr := range []hcl.Expression{validation.Condition, validation.ErrorMessage} {
+			exprRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, expr)
+			for _, ref := range exprRefs {
+				if v, ok := ref.Subject.(addrs.InputVariable); ok && v.Name != config.Name {
+					refs = append(refs, ref)
+				}
+			}
+		}
+	}
+	return refs
+}
+
+// variableValidationVariables returns the "var" object for evaluating the
+// given validation rule, containing the value of the variable being validated
+// along with the values of any other variables of the same module that the
+// rule refers to.
+func variableValidationVariables(addr addrs.AbsInputVariableInstance, config *configs.Variable, val cty.Value, validation *configs.CheckRule, ctx EvalContext) cty.Value {
+	vals := map[string]cty.Value{
+		config.Name: val,
+	}
+	for _, expr := range []hcl.Expression{validation.Condition, validation.ErrorMessage} {
+		refs, _ := lang.ReferencesInExpr(addrs.ParseRef, expr)
+		for _, ref := range refs {
+			v, ok := ref.Subject.(addrs.InputVariable)
+			if !ok {
+				continue
+			}
+			if _, exists := vals[v.Name]; exists {
+				continue
+			}
+			other := ctx.GetVariableValue(addrs.AbsInputVariableInstance{
+				Module:   addr.Module,
+				Variable: v,
+			})
+			if other == cty.NilVal {
+				// The graph should ensure that the other variables are
+				// evaluated first, so this should arise only when the
+				// variable isn't declared, which configuration loading
+				// already reports.
+				other = cty.DynamicVal
+			}
+			vals[v.Name] = other
+		}
+	}
+	return cty.ObjectVal(vals)
+}
+
+// variableValidationStaticValues returns the values of any references from
+// the given validation rule to static data about the module where the
+// variable is declared, such as path.module, keyed by the name of the
+// top-level object in the evaluation context.
+func variableValidationStaticValues(addr addrs.AbsInputVariableInstance, validation *configs.CheckRule, ctx EvalContext) (map[string]cty.Value, tfdiags.Diagnostics) {
+	var refs []*addrs.Reference
+	for _, expr := range []hcl.Expression{validation.Condition, validation.ErrorMessage} {
+		exprRefs, _ := lang.ReferencesInExpr(addrs.ParseRef, expr)
+		for _, ref := range exprRefs {
+			switch ref.Subject.(type) {
+			case addrs.PathAttr, addrs.TerraformAttr:
+				refs = append(refs, ref)
+			}
+		}
+	}
+	if len(refs) == 0 {
+		return nil, nil
+	}
+
+	// Unlike the variable values, these must be evaluated in the module where
+	// the variable is declared rather than the one that sets its value.
+	hclCtx, diags := ctx.WithPath(addr.Module).EvaluationScope(nil, nil, EvalDataForNoInstanceKey).EvalContext(refs)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	ret := make(map[string]cty.Value, len(hclCtx.Variables))
+	for name, v := range hclCtx.Variables {
+		if name == "var" {
+			continue
+		}
+		ret[name] = v
+	}
+	return ret, diags
+}
+
 func evalVariableValidation(validation *configs.CheckRule, hclCtx *hcl.EvalContext, addr addrs.AbsInputVariableInstance, config *configs.Variable, expr hcl.Expression, ix int) (checkResult, tfdiags.Diagnostics) {
 	const errInvalidCondition = "Invalid variable validation result"
 	const errInvalidValue = "Invalid value for variable"
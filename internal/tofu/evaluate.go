@@ -10,6 +10,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"sync"
 	"time"
 
@@ -164,10 +165,14 @@ func (d *evaluationStateData) GetCountAttr(addr addrs.CountAttr, rng tfdiags.Sou
 		return idxVal, diags
 
 	default:
+		suggestion := didyoumean.NameSuggestion(addr.Name, []string{"index"})
+		if suggestion != "" {
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
+		}
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Invalid "count" attribute`,
-			Detail:   fmt.Sprintf(`The "count" object does not have an attribute named %q. The only supported attribute is count.index, which is the index of each instance of a resource block that has the "count" argument set.`, addr.Name),
+			Detail:   fmt.Sprintf(`The "count" object does not have an attribute named %q. The only supported attribute is count.index, which is the index of each instance of a resource block that has the "count" argument set.%s`, addr.Name, suggestion),
 			Subject:  rng.ToHCL().Ptr(),
 		})
 		return cty.DynamicVal, diags
@@ -194,10 +199,14 @@ func (d *evaluationStateData) GetForEachAttr(addr addrs.ForEachAttr, rng tfdiags
 			return cty.UnknownVal(cty.DynamicPseudoType), diags
 		}
 	default:
+		suggestion := didyoumean.NameSuggestion(addr.Name, []string{"key", "value"})
+		if suggestion != "" {
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
+		}
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Invalid "each" attribute`,
-			Detail:   fmt.Sprintf(`The "each" object does not have an attribute named %q. The supported attributes are each.key and each.value, the current key and value pair of the "for_each" attribute set.`, addr.Name),
+			Detail:   fmt.Sprintf(`The "each" object does not have an attribute named %q. The supported attributes are each.key and each.value, the current key and value pair of the "for_each" attribute set.%s`, addr.Name, suggestion),
 			Subject:  rng.ToHCL().Ptr(),
 		})
 		return cty.DynamicVal, diags
@@ -235,7 +244,7 @@ func (d *evaluationStateData) GetInputVariable(addr addrs.InputVariable, rng tfd
 		}
 		suggestion := didyoumean.NameSuggestion(addr.Name, suggestions)
 		if suggestion != "" {
-			suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
 		} else {
 			suggestion = fmt.Sprintf(" This variable can be declared with a variable %q {} block.", addr.Name)
 		}
@@ -245,6 +254,7 @@ func (d *evaluationStateData) GetInputVariable(addr addrs.InputVariable, rng tfd
 			Summary:  `Reference to undeclared input variable`,
 			Detail:   fmt.Sprintf(`An input variable with the name %q has not been declared.%s`, addr.Name, suggestion),
 			Subject:  rng.ToHCL().Ptr(),
+			Extra:    tfdiags.CodeUndeclaredVariable,
 		})
 		return cty.DynamicVal, diags
 	}
@@ -331,7 +341,7 @@ func (d *evaluationStateData) GetLocalValue(addr addrs.LocalValue, rng tfdiags.S
 		}
 		suggestion := didyoumean.NameSuggestion(addr.Name, suggestions)
 		if suggestion != "" {
-			suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
 		}
 
 		diags = diags.Append(&hcl.Diagnostic{
@@ -339,6 +349,7 @@ func (d *evaluationStateData) GetLocalValue(addr addrs.LocalValue, rng tfdiags.S
 			Summary:  `Reference to undeclared local value`,
 			Detail:   fmt.Sprintf(`A local value with the name %q has not been declared.%s`, addr.Name, suggestion),
 			Subject:  rng.ToHCL().Ptr(),
+			Extra:    tfdiags.CodeUndeclaredLocal,
 		})
 		return cty.DynamicVal, diags
 	}
@@ -362,11 +373,22 @@ func (d *evaluationStateData) GetModule(addr addrs.ModuleCall, rng tfdiags.Sourc
 	parentCfg := d.Evaluator.Config.DescendentForInstance(d.ModulePath)
 	callConfig, ok := parentCfg.Module.ModuleCalls[addr.Name]
 	if !ok {
+		var suggestions []string
+		for k := range parentCfg.Module.ModuleCalls {
+			suggestions = append(suggestions, k)
+		}
+		sort.Strings(suggestions)
+		suggestion := didyoumean.NameSuggestion(addr.Name, suggestions)
+		if suggestion != "" {
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
+		}
+
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Reference to undeclared module`,
-			Detail:   fmt.Sprintf(`The configuration contains no %s.`, moduleAddr),
+			Detail:   fmt.Sprintf(`The configuration contains no %s.%s`, moduleAddr, suggestion),
 			Subject:  rng.ToHCL().Ptr(),
+			Extra:    tfdiags.CodeUndeclaredModule,
 		})
 		return cty.DynamicVal, diags
 	}
@@ -630,7 +652,7 @@ func (d *evaluationStateData) GetPathAttr(addr addrs.PathAttr, rng tfdiags.Sourc
 	default:
 		suggestion := didyoumean.NameSuggestion(addr.Name, []string{"cwd", "module", "root"})
 		if suggestion != "" {
-			suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
 		}
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
@@ -659,8 +681,9 @@ func (d *evaluationStateData) GetResource(addr addrs.Resource, rng tfdiags.Sourc
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Reference to undeclared resource`,
-			Detail:   fmt.Sprintf(`A resource %q %q has not been declared in %s`, addr.Type, addr.Name, moduleDisplayAddr(moduleAddr)),
+			Detail:   fmt.Sprintf(`A resource %q %q has not been declared in %s%s`, addr.Type, addr.Name, moduleDisplayAddr(moduleAddr), resourceSuggestion(moduleConfig.Module, addr)),
 			Subject:  rng.ToHCL().Ptr(),
+			Extra:    tfdiags.CodeUndeclaredResource,
 		})
 		return cty.DynamicVal, diags
 	}
@@ -921,6 +944,13 @@ func (d *evaluationStateData) GetTerraformAttr(addr addrs.TerraformAttr, rng tfd
 		workspaceName := d.Evaluator.Meta.Env
 		return cty.StringVal(workspaceName), diags
 
+	case "workspace_meta":
+		var meta *states.WorkspaceMeta
+		if d.Evaluator.State != nil {
+			meta = d.Evaluator.State.WorkspaceMeta()
+		}
+		return meta.Value(), diags
+
 	case "env":
 		// Prior to Terraform 0.12 there was an attribute "env", which was
 		// an alias name for "workspace". This was deprecated and is now
@@ -934,10 +964,14 @@ func (d *evaluationStateData) GetTerraformAttr(addr addrs.TerraformAttr, rng tfd
 		return cty.DynamicVal, diags
 
 	default:
+		suggestion := didyoumean.NameSuggestion(addr.Name, []string{"workspace", "workspace_meta"})
+		if suggestion != "" {
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
+		}
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Invalid "terraform" attribute`,
-			Detail:   fmt.Sprintf(`The "terraform" object does not have an attribute named %q. The only supported attribute is terraform.workspace, the name of the currently-selected workspace.`, addr.Name),
+			Detail:   fmt.Sprintf(`The "terraform" object does not have an attribute named %q. The only supported attributes are terraform.workspace, the name of the currently-selected workspace, and terraform.workspace_meta, its description and tags.%s`, addr.Name, suggestion),
 			Subject:  rng.ToHCL().Ptr(),
 		})
 		return cty.DynamicVal, diags
@@ -964,7 +998,7 @@ func (d *evaluationStateData) GetOutput(addr addrs.OutputValue, rng tfdiags.Sour
 		}
 		suggestion := didyoumean.NameSuggestion(addr.Name, suggestions)
 		if suggestion != "" {
-			suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
 		}
 
 		diags = diags.Append(&hcl.Diagnostic{
@@ -972,6 +1006,7 @@ func (d *evaluationStateData) GetOutput(addr addrs.OutputValue, rng tfdiags.Sour
 			Summary:  `Reference to undeclared output value`,
 			Detail:   fmt.Sprintf(`An output value with the name %q has not been declared.%s`, addr.Name, suggestion),
 			Subject:  rng.ToHCL().Ptr(),
+			Extra:    tfdiags.CodeUndeclaredOutput,
 		})
 		return cty.DynamicVal, diags
 	}
@@ -1028,3 +1063,33 @@ func moduleDisplayAddr(addr addrs.ModuleInstance) string {
 		return addr.String()
 	}
 }
+
+// resourceSuggestion returns a sentence suggesting a resource declared in
+// the given module whose address is similar to the given one, or an empty
+// string if there is no such resource.
+//
+// A common mistake is omitting the data. prefix when trying to refer to a
+// data resource, so we check for that before looking for similar addresses.
+func resourceSuggestion(module *configs.Module, addr addrs.Resource) string {
+	if addr.Mode == addrs.ManagedResourceMode {
+		candidateAddr := addr // not a pointer, so this is a copy
+		candidateAddr.Mode = addrs.DataResourceMode
+		if module.ResourceByAddr(candidateAddr) != nil {
+			return fmt.Sprintf("\n\nDid you mean the data resource %s?", candidateAddr)
+		}
+	}
+
+	resources := module.ManagedResources
+	if addr.Mode == addrs.DataResourceMode {
+		resources = module.DataResources
+	}
+	suggestions := make([]string, 0, len(resources))
+	for _, rc := range resources {
+		suggestions = append(suggestions, rc.Addr().String())
+	}
+	sort.Strings(suggestions)
+	if suggestion := didyoumean.NameSuggestion(addr.String(), suggestions); suggestion != "" {
+		return fmt.Sprintf("\n\nDid you mean %s?", suggestion)
+	}
+	return ""
+}
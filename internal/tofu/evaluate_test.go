@@ -6,6 +6,7 @@
 package tofu
 
 import (
+	"strings"
 	"sync"
 	"testing"
 
@@ -23,10 +24,18 @@ import (
 )
 
 func TestEvaluatorGetTerraformAttr(t *testing.T) {
+	state := states.NewState()
+	state.WorkspaceMeta = &states.WorkspaceMeta{
+		Description: "Production environment",
+		Tags: map[string]string{
+			"env": "prod",
+		},
+	}
 	evaluator := &Evaluator{
 		Meta: &ContextMeta{
 			Env: "foo",
 		},
+		State: state.SyncWrapper(),
 	}
 	data := &evaluationStateData{
 		Evaluator: evaluator,
@@ -45,6 +54,36 @@ func TestEvaluatorGetTerraformAttr(t *testing.T) {
 			t.Errorf("wrong result %q; want %q", got, want)
 		}
 	})
+
+	t.Run("workspace_meta", func(t *testing.T) {
+		want := cty.ObjectVal(map[string]cty.Value{
+			"description": cty.StringVal("Production environment"),
+			"tags": cty.MapVal(map[string]cty.Value{
+				"env": cty.StringVal("prod"),
+			}),
+		})
+		got, diags := scope.Data.GetTerraformAttr(addrs.TerraformAttr{
+			Name: "workspace_meta",
+		}, tfdiags.SourceRange{})
+		if len(diags) != 0 {
+			t.Errorf("unexpected diagnostics %s", spew.Sdump(diags))
+		}
+		if !got.RawEquals(want) {
+			t.Errorf("wrong result %#v; want %#v", got, want)
+		}
+	})
+
+	t.Run("misspelled", func(t *testing.T) {
+		_, diags := scope.Data.GetTerraformAttr(addrs.TerraformAttr{
+			Name: "worksapce",
+		}, tfdiags.SourceRange{})
+		if !diags.HasErrors() {
+			t.Fatal("succeeded; want error")
+		}
+		if got, want := diags.Err().Error(), `Did you mean "workspace"?`; !strings.Contains(got, want) {
+			t.Errorf("wrong error\ngot:  %s\nwant substring: %s", got, want)
+		}
+	})
 }
 
 func TestEvaluatorGetPathAttr(t *testing.T) {
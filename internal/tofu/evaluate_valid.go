@@ -208,22 +208,13 @@ func (d *evaluationStateData) staticValidateResourceReference(modCfg *configs.Co
 
 	cfg := modCfg.Module.ResourceByAddr(addr)
 	if cfg == nil {
-		var suggestion string
-		// A common mistake is omitting the data. prefix when trying to refer
-		// to a data resource, so we'll add a special hint for that.
-		if addr.Mode == addrs.ManagedResourceMode {
-			candidateAddr := addr // not a pointer, so this is a copy
-			candidateAddr.Mode = addrs.DataResourceMode
-			if candidateCfg := modCfg.Module.ResourceByAddr(candidateAddr); candidateCfg != nil {
-				suggestion = fmt.Sprintf("\n\nDid you mean the data resource %s?", candidateAddr)
-			}
-		}
-
+		suggestion := resourceSuggestion(modCfg.Module, addr)
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  `Reference to undeclared resource`,
 			Detail:   fmt.Sprintf(`A %s resource %q %q has not been declared in %s.%s`, modeAdjective, addr.Type, addr.Name, moduleConfigDisplayAddr(modCfg.Path), suggestion),
 			Subject:  rng.ToHCL().Ptr(),
+			Extra:    tfdiags.CodeUndeclaredResource,
 		})
 		return diags
 	}
@@ -292,8 +283,10 @@ func (d *evaluationStateData) staticValidateModuleCallReference(modCfg *configs.
 	var diags tfdiags.Diagnostics
 
 	// For now, our focus here is just in testing that the referenced module
-	// call exists. All other validation is deferred until evaluation time.
-	_, exists := modCfg.Module.ModuleCalls[addr.Name]
+	// call exists and, for a module call with only one instance, that it
+	// declares the referenced output value. All other validation is deferred
+	// until evaluation time.
+	callCfg, exists := modCfg.Module.ModuleCalls[addr.Name]
 	if !exists {
 		var suggestions []string
 		for name := range modCfg.Module.ModuleCalls {
@@ -302,7 +295,7 @@ func (d *evaluationStateData) staticValidateModuleCallReference(modCfg *configs.
 		sort.Strings(suggestions)
 		suggestion := didyoumean.NameSuggestion(addr.Name, suggestions)
 		if suggestion != "" {
-			suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
 		}
 
 		diags = diags.Append(&hcl.Diagnostic{
@@ -310,10 +303,39 @@ func (d *evaluationStateData) staticValidateModuleCallReference(modCfg *configs.
 			Summary:  `Reference to undeclared module`,
 			Detail:   fmt.Sprintf(`No module call named %q is declared in %s.%s`, addr.Name, moduleConfigDisplayAddr(modCfg.Path), suggestion),
 			Subject:  rng.ToHCL().Ptr(),
+			Extra:    tfdiags.CodeUndeclaredModule,
 		})
 		return diags
 	}
 
+	childCfg := modCfg.Children[addr.Name]
+	if childCfg == nil || callCfg.Count != nil || callCfg.ForEach != nil || len(remain) == 0 {
+		return diags
+	}
+	step, ok := remain[0].(hcl.TraverseAttr)
+	if !ok {
+		return diags
+	}
+	if _, exists := childCfg.Module.Outputs[step.Name]; !exists {
+		var suggestions []string
+		for name := range childCfg.Module.Outputs {
+			suggestions = append(suggestions, name)
+		}
+		sort.Strings(suggestions)
+		suggestion := didyoumean.NameSuggestion(step.Name, suggestions)
+		if suggestion != "" {
+			suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
+		}
+
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  `Reference to undeclared output value`,
+			Detail:   fmt.Sprintf(`The module %s has no output value named %q.%s`, addr, step.Name, suggestion),
+			Subject:  step.SrcRange.Ptr(),
+			Extra:    tfdiags.CodeUndeclaredOutput,
+		})
+	}
+
 	return diags
 }
 
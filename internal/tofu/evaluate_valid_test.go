@@ -46,6 +46,32 @@ func TestStaticValidateReferences(t *testing.T) {
 
 Did you mean the data resource data.beep.boop?`,
 		},
+		{
+			Ref: "aws_instance.no_cont",
+			WantErr: `Reference to undeclared resource: A managed resource "aws_instance" "no_cont" has not been declared in the root module.
+
+Did you mean aws_instance.no_count?`,
+		},
+		{
+			Ref:     "module.child.instance_id",
+			WantErr: ``,
+		},
+		{
+			Ref: "module.child.instance_ids",
+			WantErr: `Reference to undeclared output value: The module module.child has no output value named "instance_ids".
+
+Did you mean "instance_id"?`,
+		},
+		{
+			Ref: "module.chlid.instance_id",
+			WantErr: `Reference to undeclared module: No module call named "chlid" is declared in the root module.
+
+Did you mean "child"?`,
+		},
+		{
+			Ref:     "module.children[0].instance_id",
+			WantErr: ``,
+		},
 		{
 			Ref:     "aws_instance.no_count[0]",
 			WantErr: `Unexpected resource instance key: Because aws_instance.no_count does not have "count" or "for_each" set, references to it must not include an index key. Remove the bracketed index to refer to the single instance of this resource.`,
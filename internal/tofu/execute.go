@@ -12,3 +12,11 @@ import "github.com/opentofu/opentofu/internal/tfdiags"
 type GraphNodeExecutable interface {
 	Execute(EvalContext, walkOperation) tfdiags.Diagnostics
 }
+
+// GraphNodeSchedulingPriority is an optional interface for executable graph
+// nodes that should be scheduled ahead of, or behind, other nodes that are
+// ready to execute at the same time. Nodes that don't implement it have
+// priority zero.
+type GraphNodeSchedulingPriority interface {
+	SchedulingPriority(EvalContext) int
+}
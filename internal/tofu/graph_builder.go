@@ -62,7 +62,14 @@ func (b *BasicGraphBuilder) Build(path addrs.ModuleInstance) (*Graph, tfdiags.Di
 
 	if err := g.Validate(); err != nil {
 		log.Printf("[ERROR] Graph validation failed. Graph:\n\n%s", g.String())
-		diags = diags.Append(err)
+		if cycles := g.Cycles(); len(cycles) > 0 {
+			// Cycles are the most common reason for an invalid graph and
+			// are usually caused by the configuration, so we describe
+			// them in more detail than the graph itself can.
+			diags = diags.Append(graphCycleDiagnostics(g, cycles))
+		} else {
+			diags = diags.Append(err)
+		}
 		return nil, diags
 	}
 
@@ -59,6 +59,11 @@ type ApplyGraphBuilder struct {
 	// nodes that should not be pruned even if they are not referenced within
 	// the actual graph.
 	ExternalReferences []*addrs.Reference
+
+	// AutoApprove is set when the plan was approved in advance, in which
+	// case the previous objects of two-phase replacements are destroyed
+	// without asking for confirmation once the new objects are ready.
+	AutoApprove bool
 }
 
 // See GraphBuilder
@@ -127,6 +132,14 @@ func (b *ApplyGraphBuilder) Steps() []GraphTransformer {
 			Operation: b.Operation,
 		},
 
+		// Apply the changes to the canary instances of resources first, and
+		// the changes to their other instances only once the check blocks
+		// that refer to them pass.
+		&canaryTransformer{
+			Config:    b.Config,
+			Operation: b.Operation,
+		},
+
 		// Attach the state
 		&AttachStateTransformer{State: b.State},
 
@@ -184,6 +197,10 @@ func (b *ApplyGraphBuilder) Steps() []GraphTransformer {
 			State:  b.State,
 		},
 
+		// Previous objects of two-phase replacements must wait for any check
+		// blocks that act as health checks for their replacement objects.
+		&twoPhaseReplaceTransformer{autoApprove: b.AutoApprove},
+
 		// We need to remove configuration nodes that are not used at all, as
 		// they may not be able to evaluate, especially during destroy.
 		// These include variables, locals, and instance expanders.
@@ -51,6 +51,15 @@ type PlanGraphBuilder struct {
 	// action instead. Create and Delete actions are not affected.
 	ForceReplace []addrs.AbsResourceInstance
 
+	// RefreshFilter, if non-empty, limits refreshing to only the managed
+	// resource instances contained in at least one of these addresses.
+	RefreshFilter []addrs.Targetable
+
+	// AdoptExisting, if set, plans to import existing remote objects found
+	// by providers for resource instances that have no prior state, instead
+	// of planning to create them.
+	AdoptExisting bool
+
 	// skipRefresh indicates that we should skip refreshing managed resources
 	skipRefresh bool
 
@@ -270,13 +279,15 @@ func (b *PlanGraphBuilder) initPlan() {
 			skipPlanChanges:      b.skipPlanChanges,
 			preDestroyRefresh:    b.preDestroyRefresh,
 			forceReplace:         b.ForceReplace,
+			refreshFilter:        b.RefreshFilter,
+			adoptExisting:        b.AdoptExisting,
 		}
 	}
 
 	b.ConcreteResourceOrphan = func(a *NodeAbstractResourceInstance) dag.Vertex {
 		return &NodePlannableResourceInstanceOrphan{
 			NodeAbstractResourceInstance: a,
-			skipRefresh:                  b.skipRefresh,
+			skipRefresh:                  skipRefreshForInstance(b.skipRefresh, b.RefreshFilter, a.Addr),
 			skipPlanChanges:              b.skipPlanChanges,
 			EndpointsToRemove:            b.EndpointsToRemove,
 		}
@@ -287,7 +298,7 @@ func (b *PlanGraphBuilder) initPlan() {
 			NodeAbstractResourceInstance: a,
 			DeposedKey:                   key,
 
-			skipRefresh:       b.skipRefresh,
+			skipRefresh:       skipRefreshForInstance(b.skipRefresh, b.RefreshFilter, a.Addr),
 			skipPlanChanges:   b.skipPlanChanges,
 			EndpointsToRemove: b.EndpointsToRemove,
 		}
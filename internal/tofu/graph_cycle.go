@@ -0,0 +1,134 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// DiagnosticExtraGraphCycle provides an interface for diagnostic ExtraInfo to
+// retrieve the cycle that caused a graph to be rejected.
+type DiagnosticExtraGraphCycle interface {
+	// GraphCycle returns a graph containing only the vertices of the cycle
+	// and the edges between them.
+	GraphCycle() *Graph
+}
+
+// GraphCycles returns a graph containing all of the cycles reported by the
+// given diagnostics, or nil if none of them report a cycle. This is used by
+// "tofu graph -graph-cycles" to render just the problematic parts of a graph.
+func GraphCycles(diags tfdiags.Diagnostics) *Graph {
+	var ret *Graph
+	for _, diag := range diags {
+		extra := tfdiags.ExtraInfo[DiagnosticExtraGraphCycle](diag)
+		if extra == nil {
+			continue
+		}
+		if ret == nil {
+			ret = &Graph{}
+		}
+		cycle := extra.GraphCycle()
+		for _, v := range cycle.Vertices() {
+			ret.Add(v)
+		}
+		for _, e := range cycle.Edges() {
+			ret.Connect(e)
+		}
+	}
+	return ret
+}
+
+// graphCycleExtra is the implementation of DiagnosticExtraGraphCycle
+// attached to the diagnostics returned by graphCycleDiagnostics.
+type graphCycleExtra struct {
+	cycle *Graph
+}
+
+var _ DiagnosticExtraGraphCycle = graphCycleExtra{}
+
+func (e graphCycleExtra) GraphCycle() *Graph {
+	return e.cycle
+}
+
+// graphCycleDiagnostics returns an error diagnostic for each of the given
+// cycles in the graph, as returned by its Cycles method.
+//
+// Each diagnostic describes one path around the cycle as a numbered chain
+// of the objects involved, along with the references in the configuration
+// that create each dependency, so that it's clear which reference to remove
+// to break the cycle.
+func graphCycleDiagnostics(g *Graph, cycles [][]dag.Vertex) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+
+	for _, component := range cycles {
+		names := make([]string, len(component))
+		for i, v := range component {
+			names[i] = dag.VertexName(v)
+		}
+
+		path := g.CyclePath(component)
+		if len(path) == 0 {
+			// Should never happen, but we'll still report the cycle in
+			// the same way as the graph itself would.
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				fmt.Sprintf("Cycle: %s", strings.Join(names, ", ")),
+				"OpenTofu can't determine the order in which to process these objects, because they depend on each other.",
+			))
+			continue
+		}
+
+		// The references that create the edges of the cycle can only
+		// refer to other vertices in the same cycle.
+		refMap := NewReferenceMap(component)
+
+		cycle := &Graph{Path: g.Path}
+		var subject *hcl.Range
+		var buf strings.Builder
+		buf.WriteString("OpenTofu can't determine the order in which to process these objects, because each of them depends on the next:\n")
+		for i, v := range path {
+			next := path[(i+1)%len(path)]
+			cycle.Add(v)
+			cycle.Add(next)
+			cycle.Connect(dag.BasicEdge(v, next))
+
+			fmt.Fprintf(&buf, "\n  %d. %s\n", i+1, dag.VertexName(v))
+			refs := refMap.referencesTo(v, next)
+			if len(refs) == 0 {
+				// Some edges are added by OpenTofu itself rather than
+				// by references in the configuration, such as those
+				// between resources and their providers.
+				fmt.Fprintf(&buf, "     depends on %s\n", dag.VertexName(next))
+				continue
+			}
+			for _, ref := range refs {
+				rng := ref.SourceRange.ToHCL()
+				if subject == nil {
+					subject = rng.Ptr()
+				}
+				fmt.Fprintf(&buf, "     refers to %s at %s\n", ref.DisplayString(), rng)
+			}
+		}
+		fmt.Fprintf(&buf, "\n  %d. back to %s\n", len(path)+1, dag.VertexName(path[0]))
+		buf.WriteString("\nTo break the cycle, remove one of these references. Run \"tofu graph -graph-cycles\" to render just this cycle in the DOT format.")
+
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  fmt.Sprintf("Cycle: %s", strings.Join(names, ", ")),
+			Detail:   buf.String(),
+			Subject:  subject,
+			Extra:    graphCycleExtra{cycle: cycle},
+		})
+	}
+
+	return diags
+}
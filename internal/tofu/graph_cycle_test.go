@@ -0,0 +1,84 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/dag"
+)
+
+func TestGraphCycleDiagnostics(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+locals {
+  a = local.b
+  b = local.c
+  c = "${local.a}-c"
+}
+
+output "out" {
+  value = local.a
+}
+`,
+	})
+
+	ctx := testContext2(t, &ContextOpts{})
+	diags := ctx.Validate(m)
+	if !diags.HasErrors() {
+		t.Fatal("succeeded; want cycle error")
+	}
+	if len(diags) != 1 {
+		t.Fatalf("wrong number of diagnostics: %s", diags.ErrWithWarnings())
+	}
+
+	subject := diags[0].Source().Subject
+	if subject == nil || subject.Start.Line != 3 {
+		t.Fatalf("wrong subject %#v; want the first reference in the cycle", subject)
+	}
+
+	desc := diags[0].Description()
+	// The ranges in the detail use the full path of the temporary
+	// directory that holds the configuration.
+	desc.Detail = strings.ReplaceAll(desc.Detail, filepath.Dir(subject.Filename)+string(filepath.Separator), "")
+	if got, want := desc.Summary[:len("Cycle: ")], "Cycle: "; got != want {
+		t.Errorf("wrong summary %q; want prefix %q", desc.Summary, want)
+	}
+	wantDetail := `OpenTofu can't determine the order in which to process these objects, because each of them depends on the next:
+
+  1. local.a (expand)
+     refers to local.b at main.tf:3,7-14
+
+  2. local.b (expand)
+     refers to local.c at main.tf:4,7-14
+
+  3. local.c (expand)
+     refers to local.a at main.tf:5,10-17
+
+  4. back to local.a (expand)
+
+To break the cycle, remove one of these references. Run "tofu graph -graph-cycles" to render just this cycle in the DOT format.`
+	if desc.Detail != wantDetail {
+		t.Errorf("wrong detail\ngot:\n%s\n\nwant:\n%s", desc.Detail, wantDetail)
+	}
+
+	cycle := GraphCycles(diags)
+	if cycle == nil {
+		t.Fatal("no cycle graph in diagnostics")
+	}
+	var names []string
+	for _, e := range cycle.Edges() {
+		names = append(names, dag.VertexName(e.Source())+" -> "+dag.VertexName(e.Target()))
+	}
+	if got, want := len(names), 3; got != want {
+		t.Errorf("wrong number of edges in cycle graph %q; want %d", names, want)
+	}
+	if got := len(cycle.Vertices()); got != 3 {
+		t.Errorf("wrong number of vertices in cycle graph: %d", got)
+	}
+}
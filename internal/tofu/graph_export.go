@@ -0,0 +1,370 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+// GraphExportOpts are the options for the graph formats other than DOT.
+type GraphExportOpts struct {
+	// Verbose includes the nodes that only show themselves when the user
+	// has requested the "verbose" graph, as for dag.DotOpts.
+	Verbose bool
+
+	// ResourcesOnly includes only the nodes that represent resources or
+	// resource instances. Dependencies through the other nodes become
+	// direct dependencies between the resources.
+	ResourcesOnly bool
+
+	// Changes, if set, are the planned changes used to annotate each
+	// resource instance node with the action planned for it.
+	Changes *plans.Changes
+}
+
+// GraphMermaid returns a Mermaid flowchart representing the given OpenTofu
+// graph, with the nodes of each module grouped into a subgraph.
+func GraphMermaid(g *Graph, opts *GraphExportOpts) (string, error) {
+	e := newGraphExport(g, opts)
+
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	var writeModule func(mod addrs.Module, indent string)
+	writeModule = func(mod addrs.Module, indent string) {
+		for _, n := range e.nodesIn(mod) {
+			fmt.Fprintf(&b, "%s%s[\"%s\"]\n", indent, n.ID, mermaidEscape(n.Label))
+		}
+		for _, child := range e.childModules(mod) {
+			fmt.Fprintf(&b, "%ssubgraph %s[\"%s\"]\n", indent, e.moduleID(child), mermaidEscape(child.String()))
+			writeModule(child, indent+"  ")
+			fmt.Fprintf(&b, "%send\n", indent)
+		}
+	}
+	writeModule(addrs.RootModule, "  ")
+
+	for _, edge := range e.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", edge.Source, edge.Target)
+	}
+
+	for _, action := range graphExportActions {
+		var ids []string
+		for _, n := range e.Nodes {
+			if n.Action == action.name {
+				ids = append(ids, n.ID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "  classDef %s fill:%s,stroke:%s\n", mermaidClassName(action.name), action.fill, action.stroke)
+		fmt.Fprintf(&b, "  class %s %s\n", strings.Join(ids, ","), mermaidClassName(action.name))
+	}
+
+	return b.String(), nil
+}
+
+// GraphD2 returns a D2 diagram representing the given OpenTofu graph, with
+// the nodes of each module grouped into a container.
+func GraphD2(g *Graph, opts *GraphExportOpts) (string, error) {
+	e := newGraphExport(g, opts)
+
+	// D2 refers to shapes inside containers by their full path, so we
+	// need to know the container of each node to write the edges.
+	paths := make(map[string]string, len(e.Nodes))
+
+	var b strings.Builder
+	b.WriteString("direction: right\n")
+	var writeModule func(mod addrs.Module, prefix, indent string)
+	writeModule = func(mod addrs.Module, prefix, indent string) {
+		for _, n := range e.nodesIn(mod) {
+			paths[n.ID] = prefix + n.ID
+			if action := graphExportActionByName(n.Action); action != nil {
+				fmt.Fprintf(&b, "%s%s: %s {\n", indent, n.ID, d2Quote(n.Label))
+				fmt.Fprintf(&b, "%s  style.fill: %s\n", indent, d2Quote(action.fill))
+				fmt.Fprintf(&b, "%s  style.stroke: %s\n", indent, d2Quote(action.stroke))
+				fmt.Fprintf(&b, "%s}\n", indent)
+			} else {
+				fmt.Fprintf(&b, "%s%s: %s\n", indent, n.ID, d2Quote(n.Label))
+			}
+		}
+		for _, child := range e.childModules(mod) {
+			id := e.moduleID(child)
+			fmt.Fprintf(&b, "%s%s: %s {\n", indent, id, d2Quote(child.String()))
+			writeModule(child, prefix+id+".", indent+"  ")
+			fmt.Fprintf(&b, "%s}\n", indent)
+		}
+	}
+	writeModule(addrs.RootModule, "", "")
+
+	for _, edge := range e.Edges {
+		fmt.Fprintf(&b, "%s -> %s\n", paths[edge.Source], paths[edge.Target])
+	}
+
+	return b.String(), nil
+}
+
+// GraphJSON returns a JSON representation of the given OpenTofu graph,
+// for tools that want to render the graph in some other way.
+func GraphJSON(g *Graph, opts *GraphExportOpts) (string, error) {
+	e := newGraphExport(g, opts)
+	src, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(src) + "\n", nil
+}
+
+// graphExportFormatVersion is the version of the JSON format produced by
+// GraphJSON, which will be incremented for any change to this format that
+// requires changes to a consuming parser.
+const graphExportFormatVersion = "1.0"
+
+type graphExport struct {
+	FormatVersion string             `json:"format_version"`
+	Nodes         []*graphExportNode `json:"nodes"`
+	Edges         []graphExportEdge  `json:"edges"`
+
+	modules   map[string]addrs.Module
+	moduleIDs map[string]string
+}
+
+type graphExportNode struct {
+	ID     string `json:"id"`
+	Label  string `json:"label"`
+	Module string `json:"module,omitempty"`
+	Action string `json:"action,omitempty"`
+
+	module addrs.Module
+}
+
+type graphExportEdge struct {
+	// Source depends on Target, as in the DOT output.
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+type graphExportAction struct {
+	name   string
+	fill   string
+	stroke string
+}
+
+// graphExportActions are the names of the planned actions used in the
+// exported graphs and the colors that represent them, in the order that the
+// Mermaid class definitions are written.
+var graphExportActions = []graphExportAction{
+	{name: "create", fill: "#d7f5dd", stroke: "#2e7d32"},
+	{name: "read", fill: "#dbeafe", stroke: "#1d4ed8"},
+	{name: "update", fill: "#fef3c7", stroke: "#b45309"},
+	{name: "replace", fill: "#ede9fe", stroke: "#6d28d9"},
+	{name: "delete", fill: "#fee2e2", stroke: "#b91c1c"},
+	{name: "forget", fill: "#e5e7eb", stroke: "#4b5563"},
+}
+
+func graphExportActionByName(name string) *graphExportAction {
+	for i := range graphExportActions {
+		if graphExportActions[i].name == name {
+			return &graphExportActions[i]
+		}
+	}
+	return nil
+}
+
+func graphExportActionName(action plans.Action) string {
+	switch {
+	case action == plans.NoOp:
+		return "no-op"
+	case action == plans.Create:
+		return "create"
+	case action == plans.Read:
+		return "read"
+	case action == plans.Update:
+		return "update"
+	case action.IsReplace():
+		return "replace"
+	case action == plans.Delete:
+		return "delete"
+	case action == plans.Forget:
+		return "forget"
+	default:
+		return ""
+	}
+}
+
+func newGraphExport(g *Graph, opts *GraphExportOpts) *graphExport {
+	if opts == nil {
+		opts = &GraphExportOpts{}
+	}
+	dotOpts := &dag.DotOpts{
+		Verbose:  opts.Verbose,
+		MaxDepth: -1,
+	}
+
+	e := &graphExport{
+		FormatVersion: graphExportFormatVersion,
+		Nodes:         []*graphExportNode{},
+		Edges:         []graphExportEdge{},
+		modules:       make(map[string]addrs.Module),
+		moduleIDs:     make(map[string]string),
+	}
+
+	// We include the same nodes as the DOT output, which are the ones that
+	// describe themselves for it, so that all of the formats agree.
+	included := make(map[dag.Vertex]*graphExportNode)
+	for _, v := range g.Vertices() {
+		dotter, ok := v.(dag.GraphNodeDotter)
+		if !ok {
+			continue
+		}
+		dn := dotter.DotNode(dag.VertexName(v), dotOpts)
+		if dn == nil {
+			continue
+		}
+		if opts.ResourcesOnly {
+			_, isResource := v.(GraphNodeConfigResource)
+			_, isInstance := v.(GraphNodeResourceInstance)
+			if !(isResource || isInstance) {
+				continue
+			}
+		}
+
+		n := &graphExportNode{
+			Label:  dn.Name,
+			module: addrs.RootModule,
+		}
+		if label, ok := dn.Attrs["label"]; ok {
+			n.Label = label
+		}
+		if mp, ok := v.(GraphNodeModulePath); ok {
+			n.module = mp.ModulePath()
+			n.Module = n.module.String()
+		}
+		if ri, ok := v.(GraphNodeResourceInstance); ok && opts.Changes != nil {
+			if change := opts.Changes.ResourceInstance(ri.ResourceInstanceAddr()); change != nil {
+				n.Action = graphExportActionName(change.Action)
+			}
+		}
+		included[v] = n
+		e.Nodes = append(e.Nodes, n)
+	}
+
+	sort.SliceStable(e.Nodes, func(i, j int) bool {
+		if e.Nodes[i].Module != e.Nodes[j].Module {
+			return e.Nodes[i].Module < e.Nodes[j].Module
+		}
+		return e.Nodes[i].Label < e.Nodes[j].Label
+	})
+	for i, n := range e.Nodes {
+		n.ID = fmt.Sprintf("n%d", i)
+		for mod := n.module; !mod.IsRoot(); mod = mod.Parent() {
+			e.modules[mod.String()] = mod
+		}
+	}
+
+	moduleKeys := make([]string, 0, len(e.modules))
+	for key := range e.modules {
+		moduleKeys = append(moduleKeys, key)
+	}
+	sort.Strings(moduleKeys)
+	for i, key := range moduleKeys {
+		e.moduleIDs[key] = fmt.Sprintf("m%d", i)
+	}
+
+	// Dependencies through nodes that we don't include become direct
+	// dependencies on the nearest included nodes, so that hiding a node
+	// doesn't hide the relationships that pass through it.
+	for v, n := range included {
+		seen := make(map[dag.Vertex]bool)
+		targets := make(map[string]bool)
+		stack := g.DownEdges(v).List()
+		for len(stack) > 0 {
+			next := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if seen[next] {
+				continue
+			}
+			seen[next] = true
+			if target, ok := included[next]; ok {
+				targets[target.ID] = true
+				continue
+			}
+			stack = append(stack, g.DownEdges(next).List()...)
+		}
+		for target := range targets {
+			e.Edges = append(e.Edges, graphExportEdge{
+				Source: n.ID,
+				Target: target,
+			})
+		}
+	}
+	sort.Slice(e.Edges, func(i, j int) bool {
+		if e.Edges[i].Source != e.Edges[j].Source {
+			return graphExportIDLess(e.Edges[i].Source, e.Edges[j].Source)
+		}
+		return graphExportIDLess(e.Edges[i].Target, e.Edges[j].Target)
+	})
+
+	return e
+}
+
+// nodesIn returns the nodes that belong directly to the given module.
+func (e *graphExport) nodesIn(mod addrs.Module) []*graphExportNode {
+	var ret []*graphExportNode
+	for _, n := range e.Nodes {
+		if n.module.Equal(mod) {
+			ret = append(ret, n)
+		}
+	}
+	return ret
+}
+
+// childModules returns the modules that contain nodes and are direct
+// children of the given module, or are ancestors of modules that contain
+// nodes.
+func (e *graphExport) childModules(mod addrs.Module) []addrs.Module {
+	var ret []addrs.Module
+	for _, candidate := range e.modules {
+		if len(candidate) == len(mod)+1 && candidate.Parent().Equal(mod) {
+			ret = append(ret, candidate)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].String() < ret[j].String()
+	})
+	return ret
+}
+
+func (e *graphExport) moduleID(mod addrs.Module) string {
+	return e.moduleIDs[mod.String()]
+}
+
+// graphExportIDLess compares node IDs numerically, so that n2 sorts before
+// n10.
+func graphExportIDLess(a, b string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return a < b
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
+
+func mermaidClassName(action string) string {
+	return "action_" + strings.ReplaceAll(action, "-", "_")
+}
+
+func d2Quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,163 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/plans"
+)
+
+func testGraphExport() (*Graph, *plans.Changes) {
+	rootAddr := mustResourceInstanceAddr("aws_instance.a")
+	childAddr := mustResourceInstanceAddr("module.child.aws_instance.b")
+
+	root := NewNodeAbstractResourceInstance(rootAddr)
+	child := NewNodeAbstractResourceInstance(childAddr)
+	out := &NodeApplyableOutput{
+		Addr: addrs.OutputValue{Name: "id"}.Absolute(addrs.RootModuleInstance.Child("child", addrs.NoKey)),
+	}
+
+	var g Graph
+	g.Add(root)
+	g.Add(child)
+	g.Add(out)
+	g.Connect(dag.BasicEdge(out, child))
+	g.Connect(dag.BasicEdge(child, root))
+
+	changes := plans.NewChanges()
+	changes.SyncWrapper().AppendResourceInstanceChange(&plans.ResourceInstanceChangeSrc{
+		Addr:        rootAddr,
+		PrevRunAddr: rootAddr,
+		ChangeSrc:   plans.ChangeSrc{Action: plans.Create},
+	})
+	changes.SyncWrapper().AppendResourceInstanceChange(&plans.ResourceInstanceChangeSrc{
+		Addr:        childAddr,
+		PrevRunAddr: childAddr,
+		ChangeSrc:   plans.ChangeSrc{Action: plans.DeleteThenCreate},
+	})
+
+	return &g, changes
+}
+
+func TestGraphMermaid(t *testing.T) {
+	g, changes := testGraphExport()
+
+	got, err := GraphMermaid(g, &GraphExportOpts{Changes: changes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.TrimSpace(`
+flowchart LR
+  n0["aws_instance.a"]
+  subgraph m0["module.child"]
+    n1["module.child.aws_instance.b"]
+    n2["module.child.output.id"]
+  end
+  n1 --> n0
+  n2 --> n1
+  classDef action_create fill:#d7f5dd,stroke:#2e7d32
+  class n0 action_create
+  classDef action_replace fill:#ede9fe,stroke:#6d28d9
+  class n1 action_replace
+`) + "\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestGraphD2(t *testing.T) {
+	g, changes := testGraphExport()
+
+	got, err := GraphD2(g, &GraphExportOpts{Changes: changes})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.TrimSpace(`
+direction: right
+n0: "aws_instance.a" {
+  style.fill: "#d7f5dd"
+  style.stroke: "#2e7d32"
+}
+m0: "module.child" {
+  n1: "module.child.aws_instance.b" {
+    style.fill: "#ede9fe"
+    style.stroke: "#6d28d9"
+  }
+  n2: "module.child.output.id"
+}
+m0.n1 -> n0
+m0.n2 -> m0.n1
+`) + "\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestGraphJSON(t *testing.T) {
+	g, _ := testGraphExport()
+
+	// The output value is only connected to the root resource through the
+	// resource in the child module, so when showing only resources the
+	// output value and its edge disappear.
+	got, err := GraphJSON(g, &GraphExportOpts{ResourcesOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.TrimSpace(`
+{
+  "format_version": "1.0",
+  "nodes": [
+    {
+      "id": "n0",
+      "label": "aws_instance.a"
+    },
+    {
+      "id": "n1",
+      "label": "module.child.aws_instance.b",
+      "module": "module.child"
+    }
+  ],
+  "edges": [
+    {
+      "source": "n1",
+      "target": "n0"
+    }
+  ]
+}
+`) + "\n"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+}
+
+func TestGraphJSON_resourcesOnlyPassThrough(t *testing.T) {
+	rootAddr := mustResourceInstanceAddr("aws_instance.a")
+	otherAddr := mustResourceInstanceAddr("aws_instance.b")
+	a := NewNodeAbstractResourceInstance(rootAddr)
+	b := NewNodeAbstractResourceInstance(otherAddr)
+	out := &NodeApplyableOutput{
+		Addr: addrs.OutputValue{Name: "id"}.Absolute(addrs.RootModuleInstance),
+	}
+
+	var g Graph
+	g.Add(a)
+	g.Add(b)
+	g.Add(out)
+	g.Connect(dag.BasicEdge(b, out))
+	g.Connect(dag.BasicEdge(out, a))
+
+	e := newGraphExport(&g, &GraphExportOpts{ResourcesOnly: true})
+	want := []graphExportEdge{{Source: "n1", Target: "n0"}}
+	if diff := cmp.Diff(want, e.Edges); diff != "" {
+		t.Errorf("wrong edges\n%s", diff)
+	}
+}
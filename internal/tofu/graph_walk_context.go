@@ -7,6 +7,7 @@ package tofu
 
 import (
 	"context"
+	"runtime/pprof"
 	"sync"
 	"time"
 
@@ -15,8 +16,10 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/checks"
 	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/dag"
 	"github.com/opentofu/opentofu/internal/encryption"
 	"github.com/opentofu/opentofu/internal/instances"
+	"github.com/opentofu/opentofu/internal/lang"
 	"github.com/opentofu/opentofu/internal/plans"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/provisioners"
@@ -47,6 +50,10 @@ type ContextGraphWalker struct {
 	PlanTimestamp      time.Time
 	Encryption         encryption.Encryption
 
+	// RefreshParallelism optionally limits the number of concurrent calls
+	// to read the current state of remote objects for specific providers.
+	RefreshParallelism map[addrs.Provider]int
+
 	// This is an output. Do not set this, nor read it while a graph walk
 	// is in progress.
 	NonFatalDiagnostics tfdiags.Diagnostics
@@ -61,6 +68,9 @@ type ContextGraphWalker struct {
 	providerLock  sync.Mutex
 	providerCache map[string]providers.Interface
 
+	resourceReaders *resourceReaders
+	exprCache       *lang.ExprCache
+
 	provisionerLock  sync.Mutex
 	provisionerCache map[string]provisioners.Interface
 }
@@ -100,6 +110,7 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 
 	ctx := &BuiltinEvalContext{
 		StopContext:           w.StopContext,
+		ParallelSem:           w.Context.parallelSem,
 		Hooks:                 w.Context.hooks,
 		InputValue:            w.Context.uiInput,
 		InstanceExpanderValue: w.InstanceExpander,
@@ -109,6 +120,9 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		ProviderCache:         w.providerCache,
 		ProviderInputConfig:   w.Context.providerInputConfig,
 		ProviderLock:          &w.providerLock,
+		ResourceReaders:       w.resourceReaders,
+		OperationTimeoutValue: w.Context.operationTimeout,
+		ExprCache:             w.exprCache,
 		ProvisionerCache:      w.provisionerCache,
 		ProvisionerLock:       &w.provisionerLock,
 		ChangesValue:          w.Changes,
@@ -121,6 +135,9 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 		VariableValuesLock:    &w.variableValuesLock,
 		Encryption:            w.Encryption,
 	}
+	if w.Operation == walkPlan || w.Operation == walkPlanDestroy {
+		ctx.PlanCache = w.Context.planCache
+	}
 
 	return ctx
 }
@@ -128,6 +145,8 @@ func (w *ContextGraphWalker) EvalContext() EvalContext {
 func (w *ContextGraphWalker) init() {
 	w.contexts = make(map[string]*BuiltinEvalContext)
 	w.providerCache = make(map[string]providers.Interface)
+	w.resourceReaders = newResourceReaders(w.RefreshParallelism)
+	w.exprCache = lang.NewExprCache()
 	w.provisionerCache = make(map[string]provisioners.Interface)
 	w.variableValues = make(map[string]map[string]cty.Value)
 
@@ -140,9 +159,27 @@ func (w *ContextGraphWalker) init() {
 }
 
 func (w *ContextGraphWalker) Execute(ctx EvalContext, n GraphNodeExecutable) tfdiags.Diagnostics {
+	priority := 0
+	if pn, ok := n.(GraphNodeSchedulingPriority); ok {
+		priority = pn.SchedulingPriority(ctx)
+	}
+
 	// Acquire a lock on the semaphore
-	w.Context.parallelSem.Acquire()
+	w.Context.parallelSem.Acquire(priority)
 	defer w.Context.parallelSem.Release()
 
+	if timings := w.Context.nodeTimings; timings != nil {
+		// The node's name is also set as a profiler label, so that a CPU
+		// profile can be broken down by node.
+		name := dag.VertexName(n)
+		var diags tfdiags.Diagnostics
+		start := time.Now()
+		pprof.Do(context.Background(), pprof.Labels("node", name), func(context.Context) {
+			diags = n.Execute(ctx, w.Operation)
+		})
+		timings.record(name, time.Since(start))
+		return diags
+	}
+
 	return n.Execute(ctx, w.Operation)
 }
@@ -6,6 +6,8 @@
 package tofu
 
 import (
+	"time"
+
 	"github.com/zclconf/go-cty/cty"
 
 	"github.com/opentofu/opentofu/internal/addrs"
@@ -76,6 +78,12 @@ type Hook interface {
 	PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error)
 	PostRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value, newState cty.Value) (HookAction, error)
 
+	// ProviderCall is called after each request that OpenTofu sends to a
+	// provider on behalf of a single resource instance, with the name of
+	// the provider method that was called and how long the call took. It
+	// cannot control whether OpenTofu continues.
+	ProviderCall(addr addrs.AbsResourceInstance, method string, elapsed time.Duration)
+
 	// PreImportState and PostImportState are called before and after
 	// (respectively) each state import operation for a given resource address when
 	// using the legacy import command.
@@ -164,6 +172,9 @@ func (*NilHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Generatio
 	return HookActionContinue, nil
 }
 
+func (*NilHook) ProviderCall(addr addrs.AbsResourceInstance, method string, elapsed time.Duration) {
+}
+
 func (*NilHook) PreImportState(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	return HookActionContinue, nil
 }
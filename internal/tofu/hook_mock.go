@@ -7,6 +7,7 @@ package tofu
 
 import (
 	"sync"
+	"time"
 
 	"github.com/zclconf/go-cty/cty"
 
@@ -101,6 +102,11 @@ type MockHook struct {
 	PostRefreshReturn     HookAction
 	PostRefreshError      error
 
+	ProviderCallCalled  bool
+	ProviderCallAddr    addrs.AbsResourceInstance
+	ProviderCallMethod  string
+	ProviderCallElapsed time.Duration
+
 	PreImportStateCalled bool
 	PreImportStateAddr   addrs.AbsResourceInstance
 	PreImportStateID     string
@@ -271,6 +277,16 @@ func (h *MockHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Genera
 	return h.PostRefreshReturn, h.PostRefreshError
 }
 
+func (h *MockHook) ProviderCall(addr addrs.AbsResourceInstance, method string, elapsed time.Duration) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.ProviderCallCalled = true
+	h.ProviderCallAddr = addr
+	h.ProviderCallMethod = method
+	h.ProviderCallElapsed = elapsed
+}
+
 func (h *MockHook) PreImportState(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	h.Lock()
 	defer h.Unlock()
@@ -8,6 +8,7 @@ package tofu
 import (
 	"errors"
 	"sync/atomic"
+	"time"
 
 	"github.com/zclconf/go-cty/cty"
 
@@ -68,6 +69,9 @@ func (h *stopHook) PostRefresh(addr addrs.AbsResourceInstance, gen states.Genera
 	return h.hook()
 }
 
+func (h *stopHook) ProviderCall(addr addrs.AbsResourceInstance, method string, elapsed time.Duration) {
+}
+
 func (h *stopHook) PreImportState(addr addrs.AbsResourceInstance, importID string) (HookAction, error) {
 	return h.hook()
 }
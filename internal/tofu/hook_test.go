@@ -8,6 +8,7 @@ package tofu
 import (
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/zclconf/go-cty/cty"
 
@@ -101,6 +102,12 @@ func (h *testHook) ProvisionOutput(addr addrs.AbsResourceInstance, typeName stri
 	h.Calls = append(h.Calls, &testHookCall{"ProvisionOutput", addr.String()})
 }
 
+func (h *testHook) ProviderCall(addr addrs.AbsResourceInstance, method string, elapsed time.Duration) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.Calls = append(h.Calls, &testHookCall{"ProviderCall", addr.String()})
+}
+
 func (h *testHook) PreRefresh(addr addrs.AbsResourceInstance, gen states.Generation, priorState cty.Value) (HookAction, error) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
@@ -30,12 +30,13 @@ type nodeExpandModuleVariable struct {
 }
 
 var (
-	_ GraphNodeDynamicExpandable = (*nodeExpandModuleVariable)(nil)
-	_ GraphNodeReferenceOutside  = (*nodeExpandModuleVariable)(nil)
-	_ GraphNodeReferenceable     = (*nodeExpandModuleVariable)(nil)
-	_ GraphNodeReferencer        = (*nodeExpandModuleVariable)(nil)
-	_ graphNodeTemporaryValue    = (*nodeExpandModuleVariable)(nil)
-	_ graphNodeExpandsInstances  = (*nodeExpandModuleVariable)(nil)
+	_ GraphNodeDynamicExpandable    = (*nodeExpandModuleVariable)(nil)
+	_ GraphNodeReferenceOutside     = (*nodeExpandModuleVariable)(nil)
+	_ GraphNodeReferenceable        = (*nodeExpandModuleVariable)(nil)
+	_ GraphNodeReferencer           = (*nodeExpandModuleVariable)(nil)
+	_ GraphNodeSelfModuleReferencer = (*nodeExpandModuleVariable)(nil)
+	_ graphNodeTemporaryValue       = (*nodeExpandModuleVariable)(nil)
+	_ graphNodeExpandsInstances     = (*nodeExpandModuleVariable)(nil)
 )
 
 func (n *nodeExpandModuleVariable) expandsInstances() {}
@@ -117,6 +118,13 @@ func (n *nodeExpandModuleVariable) References() []*addrs.Reference {
 	return refs
 }
 
+// GraphNodeSelfModuleReferencer
+func (n *nodeExpandModuleVariable) SelfModuleReferences() []*addrs.Reference {
+	// The validation rules can refer to other variables of the module where
+	// this variable is declared, so we must wait for those to be evaluated.
+	return variableValidationReferences(n.Config)
+}
+
 // GraphNodeReferenceOutside implementation
 func (n *nodeExpandModuleVariable) ReferenceOutside() (selfPath, referencePath addrs.Module) {
 	return n.Module, n.Module.Parent()
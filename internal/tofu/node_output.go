@@ -385,6 +385,17 @@ If you do intend to export this data, annotate the output value as sensitive by
 					Subject: n.Config.DeclRange.Ptr(),
 				})
 			}
+
+			// Root module output values are saved in the state, so they
+			// can never refer to ephemeral values.
+			if marks.Contains(val, marks.Ephemeral) {
+				diags = diags.Append(&hcl.Diagnostic{
+					Severity: hcl.DiagError,
+					Summary:  "Output refers to ephemeral values",
+					Detail:   "OpenTofu saves the values of root module outputs in the state, so they cannot refer to ephemeral values, such as the result of a key generation function.",
+					Subject:  n.Config.DeclRange.Ptr(),
+				})
+			}
 		}
 	}
 
@@ -618,6 +629,11 @@ func (n *NodeApplyableOutput) setValue(state *states.SyncState, changes *plans.C
 	if n.Addr.Module.IsRoot() {
 		val, _ = val.UnmarkDeep()
 		val = cty.UnknownAsNull(val)
+
+		// Only root outputs can be shared with other workspaces, because
+		// they are the only ones persisted in the state.
+		state.SetSharedOutputValue(n.Addr, val, n.Config.Sensitive, n.Config.SharedWith)
+		return
 	}
 
 	state.SetOutputValue(n.Addr, val, n.Config.Sensitive)
@@ -10,6 +10,7 @@ import (
 	"log"
 
 	"github.com/hashicorp/hcl/v2"
+	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
 	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/tfdiags"
@@ -27,12 +28,29 @@ var (
 
 // GraphNodeExecutable
 func (n *NodeApplyableProvider) Execute(ctx EvalContext, op walkOperation) (diags tfdiags.Diagnostics) {
-	_, err := ctx.InitProvider(n.Addr)
+	// A provider configuration with for_each has a separate provider
+	// instance for each of its instance keys.
+	for _, key := range n.instanceKeys() {
+		diags = diags.Append(n.executeInstance(ctx, op, key))
+	}
+
+	// Resources are validated only once for all of the instances of the
+	// provider configuration they use, so they need an unconfigured provider
+	// instance that isn't any one of them.
+	if op == walkValidate && n.Config != nil && n.Config.ForEach != nil {
+		_, err := ctx.InitProvider(n.Addr, addrs.NoKey)
+		diags = diags.Append(err)
+	}
+	return diags
+}
+
+func (n *NodeApplyableProvider) executeInstance(ctx EvalContext, op walkOperation, key addrs.InstanceKey) (diags tfdiags.Diagnostics) {
+	_, err := ctx.InitProvider(n.Addr, key)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
 		return diags
 	}
-	provider, _, err := getProvider(ctx, n.Addr)
+	provider, _, err := getProvider(ctx, n.Addr, key)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
 		return diags
@@ -40,19 +58,23 @@ func (n *NodeApplyableProvider) Execute(ctx EvalContext, op walkOperation) (diag
 
 	switch op {
 	case walkValidate:
-		log.Printf("[TRACE] NodeApplyableProvider: validating configuration for %s", n.Addr)
-		return diags.Append(n.ValidateProvider(ctx, provider))
+		log.Printf("[TRACE] NodeApplyableProvider: validating configuration for %s", providerInstanceCacheKey(n.Addr, key))
+		return diags.Append(n.validateProviderInstance(ctx, provider, key))
 	case walkPlan, walkPlanDestroy, walkApply, walkDestroy:
-		log.Printf("[TRACE] NodeApplyableProvider: configuring %s", n.Addr)
-		return diags.Append(n.ConfigureProvider(ctx, provider, false))
+		log.Printf("[TRACE] NodeApplyableProvider: configuring %s", providerInstanceCacheKey(n.Addr, key))
+		return diags.Append(n.configureProviderInstance(ctx, provider, key, false))
 	case walkImport:
-		log.Printf("[TRACE] NodeApplyableProvider: configuring %s (requiring that configuration is wholly known)", n.Addr)
-		return diags.Append(n.ConfigureProvider(ctx, provider, true))
+		log.Printf("[TRACE] NodeApplyableProvider: configuring %s (requiring that configuration is wholly known)", providerInstanceCacheKey(n.Addr, key))
+		return diags.Append(n.configureProviderInstance(ctx, provider, key, true))
 	}
 	return diags
 }
 
 func (n *NodeApplyableProvider) ValidateProvider(ctx EvalContext, provider providers.Interface) (diags tfdiags.Diagnostics) {
+	return n.validateProviderInstance(ctx, provider, addrs.NoKey)
+}
+
+func (n *NodeApplyableProvider) validateProviderInstance(ctx EvalContext, provider providers.Interface, key addrs.InstanceKey) (diags tfdiags.Diagnostics) {
 
 	configBody := buildProviderConfig(ctx, n.Addr, n.ProviderConfig())
 
@@ -78,7 +100,7 @@ func (n *NodeApplyableProvider) ValidateProvider(ctx EvalContext, provider provi
 		configSchema = &configschema.Block{}
 	}
 
-	configVal, _, evalDiags := ctx.EvaluateBlock(configBody, configSchema, nil, EvalDataForNoInstanceKey)
+	configVal, _, evalDiags := ctx.EvaluateBlock(configBody, configSchema, nil, n.instanceKeyData(key))
 	if evalDiags.HasErrors() {
 		return diags.Append(evalDiags)
 	}
@@ -102,6 +124,10 @@ func (n *NodeApplyableProvider) ValidateProvider(ctx EvalContext, provider provi
 // If verifyConfigIsKnown is true, ConfigureProvider will return an error if the
 // provider configVal is not wholly known and is meant only for use during import.
 func (n *NodeApplyableProvider) ConfigureProvider(ctx EvalContext, provider providers.Interface, verifyConfigIsKnown bool) (diags tfdiags.Diagnostics) {
+	return n.configureProviderInstance(ctx, provider, addrs.NoKey, verifyConfigIsKnown)
+}
+
+func (n *NodeApplyableProvider) configureProviderInstance(ctx EvalContext, provider providers.Interface, key addrs.InstanceKey, verifyConfigIsKnown bool) (diags tfdiags.Diagnostics) {
 	config := n.ProviderConfig()
 
 	configBody := buildProviderConfig(ctx, n.Addr, config)
@@ -113,7 +139,7 @@ func (n *NodeApplyableProvider) ConfigureProvider(ctx EvalContext, provider prov
 	}
 
 	configSchema := resp.Provider.Block
-	configVal, configBody, evalDiags := ctx.EvaluateBlock(configBody, configSchema, nil, EvalDataForNoInstanceKey)
+	configVal, configBody, evalDiags := ctx.EvaluateBlock(configBody, configSchema, nil, n.instanceKeyData(key))
 	diags = diags.Append(evalDiags)
 	if evalDiags.HasErrors() {
 		return diags
@@ -123,7 +149,7 @@ func (n *NodeApplyableProvider) ConfigureProvider(ctx EvalContext, provider prov
 		diags = diags.Append(&hcl.Diagnostic{
 			Severity: hcl.DiagError,
 			Summary:  "Invalid provider configuration",
-			Detail:   fmt.Sprintf("The configuration for %s depends on values that cannot be determined until apply.", n.Addr),
+			Detail:   fmt.Sprintf("The configuration for %s depends on values that cannot be determined until apply.", providerInstanceCacheKey(n.Addr, key)),
 			Subject:  &config.DeclRange,
 		})
 		return diags
@@ -165,7 +191,7 @@ func (n *NodeApplyableProvider) ConfigureProvider(ctx EvalContext, provider prov
 		log.Printf("[WARN] ValidateProviderConfig from %q changed the config value, but that value is unused", n.Addr)
 	}
 
-	configDiags := ctx.ConfigureProvider(n.Addr, unmarkedConfigVal)
+	configDiags := ctx.ConfigureProvider(n.Addr, key, unmarkedConfigVal)
 	diags = diags.Append(configDiags.InConfigBody(configBody, n.Addr.String()))
 	if diags.HasErrors() && config == nil {
 		// If there isn't an explicit "provider" block in the configuration,
@@ -78,6 +78,24 @@ func (n *NodeAbstractProvider) ProviderConfig() *configs.Provider {
 	return n.Config
 }
 
+// instanceKeys returns the instance keys of the provider configuration, which
+// is a single addrs.NoKey unless the configuration has for_each.
+func (n *NodeAbstractProvider) instanceKeys() []addrs.InstanceKey {
+	if n.Config == nil {
+		return []addrs.InstanceKey{addrs.NoKey}
+	}
+	return n.Config.InstanceKeys()
+}
+
+// instanceKeyData returns the values of each.key and each.value for the
+// provider configuration instance with the given key.
+func (n *NodeAbstractProvider) instanceKeyData(key addrs.InstanceKey) InstanceKeyEvalData {
+	if n.Config == nil || key == addrs.NoKey {
+		return EvalDataForNoInstanceKey
+	}
+	return n.Config.Instances[key]
+}
+
 // GraphNodeAttachProvider
 func (n *NodeAbstractProvider) AttachProvider(c *configs.Provider) {
 	n.Config = c
@@ -19,6 +19,9 @@ var _ GraphNodeExecutable = (*NodeEvalableProvider)(nil)
 
 // GraphNodeExecutable
 func (n *NodeEvalableProvider) Execute(ctx EvalContext, op walkOperation) (diags tfdiags.Diagnostics) {
-	_, err := ctx.InitProvider(n.Addr)
-	return diags.Append(err)
+	for _, key := range n.instanceKeys() {
+		_, err := ctx.InitProvider(n.Addr, key)
+		diags = diags.Append(err)
+	}
+	return diags
 }
@@ -66,6 +66,11 @@ type NodeAbstractResource struct {
 
 	ProvisionerSchemas map[string]*configschema.Block
 
+	// RemovedProvisioners are the destroy-time provisioners declared in a
+	// "removed" block for this resource, which are used only when the
+	// resource has no configuration.
+	RemovedProvisioners []*configs.Provisioner
+
 	// Set from GraphNodeTargetable
 	Targets []addrs.Targetable
 
@@ -75,6 +80,11 @@ type NodeAbstractResource struct {
 
 	// The address of the provider this resource will use
 	ResolvedProvider addrs.AbsProviderConfig
+	// ResolvedProviderKey is the instance key of the instance of
+	// ResolvedProvider that a resource instance uses, when the provider
+	// configuration has for_each. Resource instance nodes set it before they
+	// use the provider, and it is addrs.NoKey otherwise.
+	ResolvedProviderKey addrs.InstanceKey
 	// storedProviderConfig is the provider address retrieved from the
 	// state. This is defined here for access within the ProvidedBy method, but
 	// will be set from the embedding instance type when the state is attached.
@@ -95,6 +105,7 @@ var (
 	_ GraphNodeProvisionerConsumer         = (*NodeAbstractResource)(nil)
 	_ GraphNodeConfigResource              = (*NodeAbstractResource)(nil)
 	_ GraphNodeAttachResourceConfig        = (*NodeAbstractResource)(nil)
+	_ GraphNodeAttachRemovedProvisioners   = (*NodeAbstractResource)(nil)
 	_ GraphNodeAttachResourceSchema        = (*NodeAbstractResource)(nil)
 	_ GraphNodeAttachProvisionerSchema     = (*NodeAbstractResource)(nil)
 	_ GraphNodeAttachProviderMetaConfigs   = (*NodeAbstractResource)(nil)
@@ -161,6 +172,11 @@ func (n *NodeAbstractResource) References() []*addrs.Reference {
 		refs, _ = lang.ReferencesInExpr(addrs.ParseRef, c.ForEach)
 		result = append(result, refs...)
 
+		if c.ProviderConfigRef != nil {
+			refs, _ = lang.ReferencesInExpr(addrs.ParseRef, c.ProviderConfigRef.KeyExpression)
+			result = append(result, refs...)
+		}
+
 		for _, expr := range c.TriggersReplacement {
 			refs, _ = lang.ReferencesInExpr(addrs.ParseRef, expr)
 			result = append(result, refs...)
@@ -363,21 +379,30 @@ func (n *NodeAbstractResource) Provider() addrs.Provider {
 
 // GraphNodeProvisionerConsumer
 func (n *NodeAbstractResource) ProvisionedBy() []string {
-	// If we have no configuration, then we have no provisioners
-	if n.Config == nil || n.Config.Managed == nil {
-		return nil
-	}
+	provs := n.provisioners()
 
 	// Build the list of provisioners we need based on the configuration.
 	// It is okay to have duplicates here.
-	result := make([]string, len(n.Config.Managed.Provisioners))
-	for i, p := range n.Config.Managed.Provisioners {
+	result := make([]string, len(provs))
+	for i, p := range provs {
 		result[i] = p.Type
 	}
 
 	return result
 }
 
+// provisioners returns the provisioners declared for this resource, which
+// come from the "removed" block for the resource if it has no configuration.
+func (n *NodeAbstractResource) provisioners() []*configs.Provisioner {
+	if n.Config == nil {
+		return n.RemovedProvisioners
+	}
+	if n.Config.Managed == nil {
+		return nil
+	}
+	return n.Config.Managed.Provisioners
+}
+
 // GraphNodeProvisionerConsumer
 func (n *NodeAbstractResource) AttachProvisionerSchema(name string, schema *configschema.Block) {
 	if n.ProvisionerSchemas == nil {
@@ -407,6 +432,11 @@ func (n *NodeAbstractResource) AttachResourceConfig(c *configs.Resource) {
 	n.Config = c
 }
 
+// GraphNodeAttachRemovedProvisioners
+func (n *NodeAbstractResource) AttachRemovedProvisioners(provs []*configs.Provisioner) {
+	n.RemovedProvisioners = provs
+}
+
 // GraphNodeAttachResourceSchema impl
 func (n *NodeAbstractResource) AttachResourceSchema(schema *configschema.Block, version uint64) {
 	n.Schema = schema
@@ -481,7 +511,7 @@ func (n *NodeAbstractResource) writeResourceState(ctx EvalContext, addr addrs.Ab
 // the state.
 func (n *NodeAbstractResource) readResourceInstanceState(ctx EvalContext, addr addrs.AbsResourceInstance) (*states.ResourceInstanceObject, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
-	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		diags = diags.Append(err)
 		return nil, diags
@@ -522,7 +552,7 @@ func (n *NodeAbstractResource) readResourceInstanceState(ctx EvalContext, addr a
 // instance in the state.
 func (n *NodeAbstractResource) readResourceInstanceStateDeposed(ctx EvalContext, addr addrs.AbsResourceInstance, key states.DeposedKey) (*states.ResourceInstanceObject, tfdiags.Diagnostics) {
 	var diags tfdiags.Diagnostics
-	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		diags = diags.Append(err)
 		return nil, diags
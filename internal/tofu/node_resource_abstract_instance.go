@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/zclconf/go-cty/cty"
@@ -127,6 +128,22 @@ func (n *NodeAbstractResourceInstance) ResourceInstanceAddr() addrs.AbsResourceI
 	return n.Addr
 }
 
+// GraphNodeSchedulingPriority
+func (n *NodeAbstractResourceInstance) SchedulingPriority(ctx EvalContext) int {
+	if n.Addr.Resource.Resource.Mode != addrs.ManagedResourceMode {
+		return 0
+	}
+	if n.Config != nil && n.Config.Managed != nil && n.Config.Managed.PrioritySet {
+		return n.Config.Managed.Priority
+	}
+
+	provider := ctx.Provider(n.ResolvedProvider, n.ResolvedProviderKey)
+	if hinter, ok := providers.AsResourcePriorityHinter(provider); ok {
+		return hinter.ResourcePriorities()[n.Addr.Resource.Resource.Type]
+	}
+	return 0
+}
+
 // GraphNodeAttachResourceState
 func (n *NodeAbstractResourceInstance) AttachResourceState(s *states.Resource) {
 	if s == nil {
@@ -345,6 +362,7 @@ func (n *NodeAbstractResourceInstance) writeResourceInstanceStateImpl(ctx EvalCo
 		return fmt.Errorf("failed to encode %s in state: %w", absAddr, err)
 	}
 
+	src.ProviderKey = n.ResolvedProviderKey
 	write(src)
 	return nil
 }
@@ -429,7 +447,8 @@ func (n *NodeAbstractResourceInstance) planDestroy(ctx EvalContext, currentState
 
 	// Allow the provider to check the destroy plan, and insert any necessary
 	// private data.
-	resp := provider.PlanResourceChange(providers.PlanResourceChangeRequest{
+	callStart := time.Now()
+	resp := ctx.PlanResourceChange(n.ResolvedProvider, n.ResolvedProviderKey, provider, providers.PlanResourceChangeRequest{
 		TypeName:         n.Addr.Resource.Resource.Type,
 		Config:           nullVal,
 		PriorState:       unmarkedPriorVal,
@@ -437,6 +456,7 @@ func (n *NodeAbstractResourceInstance) planDestroy(ctx EvalContext, currentState
 		PriorPrivate:     currentState.Private,
 		ProviderMeta:     metaConfigVal,
 	})
+	n.providerCallDone(ctx, "PlanResourceChange", callStart)
 
 	// We may not have a config for all destroys, but we want to reference it in
 	// the diagnostics if we do.
@@ -598,7 +618,19 @@ func (n *NodeAbstractResourceInstance) refresh(ctx EvalContext, deposedKey state
 		ProviderMeta: metaConfigVal,
 	}
 
-	resp := provider.ReadResource(providerReq)
+	callStart := time.Now()
+	timeout := n.operationTimeout(ctx)
+	resp, timedOut := callWithOperationTimeout(timeout, func() providers.ReadResourceResponse {
+		return ctx.ReadResource(n.ResolvedProvider, n.ResolvedProviderKey, provider, providerReq)
+	})
+	n.providerCallDone(ctx, "ReadResource", callStart)
+	if timedOut {
+		resp.Diagnostics = resp.Diagnostics.Append(n.operationTimeoutDiag(
+			"reading the remote object",
+			timeout,
+			"OpenTofu has kept the previous state of this object.",
+		))
+	}
 	if n.Config != nil {
 		resp.Diagnostics = resp.Diagnostics.InConfigBody(n.Config.Config, n.Addr.String())
 	}
@@ -747,6 +779,10 @@ func (n *NodeAbstractResourceInstance) plan(
 	if configDiags.HasErrors() {
 		return nil, nil, keyData, diags
 	}
+	diags = diags.Append(validateResourceConfigNotEphemeral(origConfigVal).InConfigBody(config.Config, n.Addr.String()))
+	if diags.HasErrors() {
+		return nil, nil, keyData, diags
+	}
 
 	metaConfigVal, metaDiags := n.providerMetas(ctx)
 	diags = diags.Append(metaDiags)
@@ -826,7 +862,8 @@ func (n *NodeAbstractResourceInstance) plan(
 		return nil, nil, keyData, diags
 	}
 
-	resp := provider.PlanResourceChange(providers.PlanResourceChangeRequest{
+	callStart := time.Now()
+	resp := ctx.PlanResourceChange(n.ResolvedProvider, n.ResolvedProviderKey, provider, providers.PlanResourceChangeRequest{
 		TypeName:         n.Addr.Resource.Resource.Type,
 		Config:           unmarkedConfigVal,
 		PriorState:       unmarkedPriorVal,
@@ -834,6 +871,7 @@ func (n *NodeAbstractResourceInstance) plan(
 		PriorPrivate:     priorPrivate,
 		ProviderMeta:     metaConfigVal,
 	})
+	n.providerCallDone(ctx, "PlanResourceChange", callStart)
 
 	diags = diags.Append(resp.Diagnostics.InConfigBody(config.Config, n.Addr.String()))
 	if diags.HasErrors() {
@@ -1061,7 +1099,8 @@ func (n *NodeAbstractResourceInstance) plan(
 		// create a new proposed value from the null state and the config
 		proposedNewVal = objchange.ProposedNew(schema, nullPriorVal, unmarkedConfigVal)
 
-		resp = provider.PlanResourceChange(providers.PlanResourceChangeRequest{
+		callStart := time.Now()
+		resp = ctx.PlanResourceChange(n.ResolvedProvider, n.ResolvedProviderKey, provider, providers.PlanResourceChangeRequest{
 			TypeName:         n.Addr.Resource.Resource.Type,
 			Config:           unmarkedConfigVal,
 			PriorState:       nullPriorVal,
@@ -1069,6 +1108,7 @@ func (n *NodeAbstractResourceInstance) plan(
 			PriorPrivate:     plannedPrivate,
 			ProviderMeta:     metaConfigVal,
 		})
+		n.providerCallDone(ctx, "PlanResourceChange", callStart)
 		// We need to tread carefully here, since if there are any warnings
 		// in here they probably also came out of our previous call to
 		// PlanResourceChange above, and so we don't want to repeat them.
@@ -1485,12 +1525,22 @@ func (n *NodeAbstractResourceInstance) readDataSource(ctx EvalContext, configVal
 		Config:       configVal,
 		ProviderMeta: metaConfigVal,
 	}
-	var resp providers.ReadDataSourceResponse
-	if tfp, ok := provider.(ProviderWithEncryption); ok {
-		// Special case for terraform_remote_state
-		resp = tfp.ReadDataSourceEncrypted(req, n.Addr, ctx.GetEncryption())
-	} else {
-		resp = provider.ReadDataSource(req)
+	callStart := time.Now()
+	timeout := n.operationTimeout(ctx)
+	resp, timedOut := callWithOperationTimeout(timeout, func() providers.ReadDataSourceResponse {
+		if tfp, ok := provider.(ProviderWithEncryption); ok {
+			// Special case for terraform_remote_state
+			return tfp.ReadDataSourceEncrypted(req, n.Addr, ctx.GetEncryption())
+		}
+		return provider.ReadDataSource(req)
+	})
+	n.providerCallDone(ctx, "ReadDataSource", callStart)
+	if timedOut {
+		resp.Diagnostics = resp.Diagnostics.Append(n.operationTimeoutDiag(
+			"reading the data source",
+			timeout,
+			"No result is available for this data source.",
+		))
 	}
 	diags = diags.Append(resp.Diagnostics.InConfigBody(config.Config, n.Addr.String()))
 	if diags.HasErrors() {
@@ -1636,6 +1686,10 @@ func (n *NodeAbstractResourceInstance) planDataSource(ctx EvalContext, checkRule
 	if configDiags.HasErrors() {
 		return nil, nil, keyData, diags
 	}
+	diags = diags.Append(validateResourceConfigNotEphemeral(configVal).InConfigBody(config.Config, n.Addr.String()))
+	if diags.HasErrors() {
+		return nil, nil, keyData, diags
+	}
 
 	check, nested := n.nestedInCheckBlock()
 	if nested {
@@ -1990,7 +2044,7 @@ func (n *NodeAbstractResourceInstance) evalApplyProvisioners(ctx EvalContext, st
 		return nil
 	}
 
-	provs := filterProvisioners(n.Config, when)
+	provs := filterProvisioners(n.provisioners(), when)
 	if len(provs) == 0 {
 		// We have no provisioners, so don't do anything
 		return nil
@@ -2018,20 +2072,16 @@ func (n *NodeAbstractResourceInstance) evalApplyProvisioners(ctx EvalContext, st
 	}))
 }
 
-// filterProvisioners filters the provisioners on the resource to only
+// filterProvisioners filters the given provisioners of a resource to only
 // the provisioners specified by the "when" option.
-func filterProvisioners(config *configs.Resource, when configs.ProvisionerWhen) []*configs.Provisioner {
+func filterProvisioners(provs []*configs.Provisioner, when configs.ProvisionerWhen) []*configs.Provisioner {
 	// Fast path the zero case
-	if config == nil || config.Managed == nil {
-		return nil
-	}
-
-	if len(config.Managed.Provisioners) == 0 {
+	if len(provs) == 0 {
 		return nil
 	}
 
-	result := make([]*configs.Provisioner, 0, len(config.Managed.Provisioners))
-	for _, p := range config.Managed.Provisioners {
+	result := make([]*configs.Provisioner, 0, len(provs))
+	for _, p := range provs {
 		if p.When == when {
 			result = append(result, p)
 		}
@@ -2060,7 +2110,7 @@ func (n *NodeAbstractResourceInstance) applyProvisioners(ctx EvalContext, state
 	// then it'll serve as a base connection configuration for all of the
 	// provisioners.
 	var baseConn hcl.Body
-	if n.Config.Managed != nil && n.Config.Managed.Connection != nil {
+	if n.Config != nil && n.Config.Managed != nil && n.Config.Managed.Connection != nil {
 		baseConn = n.Config.Managed.Connection.Config
 	}
 
@@ -2333,14 +2383,38 @@ func (n *NodeAbstractResourceInstance) apply(
 		return newState, diags
 	}
 
-	resp := provider.ApplyResourceChange(providers.ApplyResourceChangeRequest{
-		TypeName:       n.Addr.Resource.Resource.Type,
-		PriorState:     unmarkedBefore,
-		Config:         unmarkedConfigVal,
-		PlannedState:   unmarkedAfter,
-		PlannedPrivate: change.Private,
-		ProviderMeta:   metaConfigVal,
+	callStart := time.Now()
+	timeout := n.operationTimeout(ctx)
+	resp, timedOut := callWithOperationTimeout(timeout, func() providers.ApplyResourceChangeResponse {
+		return provider.ApplyResourceChange(providers.ApplyResourceChangeRequest{
+			TypeName:       n.Addr.Resource.Resource.Type,
+			PriorState:     unmarkedBefore,
+			Config:         unmarkedConfigVal,
+			PlannedState:   unmarkedAfter,
+			PlannedPrivate: change.Private,
+			ProviderMeta:   metaConfigVal,
+		})
 	})
+	n.providerCallDone(ctx, "ApplyResourceChange", callStart)
+	if timedOut {
+		// We don't know whether the provider made any changes to the
+		// object, so the prior state (set below, because there's no new
+		// state) is the only safe thing to keep: it's still an object the
+		// provider can read and change, and the change will be planned
+		// again.
+		operation := "updating the remote object"
+		switch change.Action {
+		case plans.Create:
+			operation = "creating the remote object"
+		case plans.Delete:
+			operation = "deleting the remote object"
+		}
+		resp.Diagnostics = resp.Diagnostics.Append(n.operationTimeoutDiag(
+			operation,
+			timeout,
+			"OpenTofu has kept the previous state of this object, if any, because it can't tell whether the provider made any changes. The operation may still complete in the background, so check the remote object before applying again: an object that was being created may need to be imported, and one that was being deleted may already be gone.",
+		))
+	}
 
 	applyDiags := resp.Diagnostics
 	if applyConfig != nil {
@@ -2370,7 +2444,9 @@ func (n *NodeAbstractResourceInstance) apply(
 		// As a special case, we'll set the new value to null if it looks like
 		// we were trying to execute a delete, because the provider in this case
 		// probably left the newVal unset intending it to be interpreted as "null".
-		if change.After.IsNull() {
+		// That's not true if the delete timed out, in which case the object
+		// may still exist.
+		if change.After.IsNull() && !timedOut {
 			newVal = cty.NullVal(schema.ImpliedType())
 		}
 
@@ -2572,12 +2648,74 @@ func resourceInstancePrevRunAddr(ctx EvalContext, currentAddr addrs.AbsResourceI
 	return table.OldAddr(currentAddr)
 }
 
+// resolveProviderKey sets n.ResolvedProviderKey to the instance key of the
+// instance of a provider configuration with for_each that this resource
+// instance uses. If fromConfig is true the key comes from the provider
+// argument in the resource configuration, and otherwise it comes from the
+// object of the given generation in the state, which is what destroying that
+// object requires.
+func (n *NodeAbstractResourceInstance) resolveProviderKey(ctx EvalContext, fromConfig bool, gen states.Generation) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	n.ResolvedProviderKey = addrs.NoKey
+
+	if !fromConfig || n.Config == nil {
+		if obj := ctx.State().ResourceInstanceObject(n.Addr, gen); obj != nil {
+			n.ResolvedProviderKey = obj.ProviderKey
+		}
+		return diags
+	}
+
+	if n.Config.ProviderConfigRef == nil || n.Config.ProviderConfigRef.KeyExpression == nil {
+		return diags
+	}
+	expr := n.Config.ProviderConfigRef.KeyExpression
+
+	forEach, _ := evaluateForEachExpression(n.Config.ForEach, ctx)
+	keyData := EvalDataForInstanceKey(n.ResourceInstanceAddr().Resource.Key, forEach)
+	val, valDiags := ctx.EvaluationScope(nil, nil, keyData).EvalExpr(expr, cty.String)
+	diags = diags.Append(valDiags)
+	if diags.HasErrors() {
+		return diags
+	}
+	val, _ = val.Unmark()
+
+	switch {
+	case val.IsNull():
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider instance key",
+			Detail:   "The instance key of a provider configuration must not be null.",
+			Subject:  expr.Range().Ptr(),
+		})
+	case !val.IsKnown():
+		diags = diags.Append(&hcl.Diagnostic{
+			Severity: hcl.DiagError,
+			Summary:  "Invalid provider instance key",
+			Detail:   "The instance key of a provider configuration must be known during planning, so it can't depend on resource attributes that are known only after apply.",
+			Subject:  expr.Range().Ptr(),
+		})
+	default:
+		key := addrs.StringKey(val.AsString())
+		if ctx.Provider(n.ResolvedProvider, key) == nil {
+			diags = diags.Append(&hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Invalid provider instance key",
+				Detail:   fmt.Sprintf("There is no instance of %s with the key %s.", n.ResolvedProvider, key),
+				Subject:  expr.Range().Ptr(),
+			})
+			return diags
+		}
+		n.ResolvedProviderKey = key
+	}
+	return diags
+}
+
 func (n *NodeAbstractResourceInstance) getProvider(ctx EvalContext, addr addrs.AbsProviderConfig) (providers.Interface, providers.ProviderSchema, error) {
 	return n.getProviderWithPlannedChange(ctx, addr, nil)
 }
 
 func (n *NodeAbstractResourceInstance) getProviderWithPlannedChange(ctx EvalContext, addr addrs.AbsProviderConfig, plannedChange *plans.ResourceInstanceChange) (providers.Interface, providers.ProviderSchema, error) {
-	underlyingProvider, schema, err := getProvider(ctx, addr)
+	underlyingProvider, schema, err := getProvider(ctx, addr, n.ResolvedProviderKey)
 	if err != nil {
 		return nil, providers.ProviderSchema{}, err
 	}
@@ -2596,5 +2734,19 @@ func (n *NodeAbstractResourceInstance) getProviderWithPlannedChange(ctx EvalCont
 		providerForTest.plannedChange = &plannedChange.After
 	}
 
+	if generators := n.Config.OverrideGenerators; generators != nil {
+		providerForTest.generate = generators.ForInstance(n.Addr.String(), n.Addr.Resource.Resource.Type)
+	}
+
 	return providerForTest, schema, nil
 }
+
+// providerCallDone reports to the hooks how long a call to the given provider
+// method, which began at start, took on behalf of this resource instance.
+func (n *NodeAbstractResourceInstance) providerCallDone(ctx EvalContext, method string, start time.Time) {
+	elapsed := time.Since(start)
+	_ = ctx.Hook(func(h Hook) (HookAction, error) {
+		h.ProviderCall(n.Addr, method, elapsed)
+		return HookActionContinue, nil
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/providers"
 	"github.com/opentofu/opentofu/internal/states"
 	"github.com/zclconf/go-cty/cty"
 )
@@ -186,3 +187,76 @@ aws_instance.foo:
   provider = provider["registry.opentofu.org/hashicorp/aws"]
 	`)
 }
+
+// priorityHintProvider is a MockProvider that suggests default scheduling
+// priorities for its resource types.
+type priorityHintProvider struct {
+	*MockProvider
+
+	priorities map[string]int
+}
+
+func (p *priorityHintProvider) ResourcePriorities() map[string]int {
+	return p.priorities
+}
+
+func TestNodeAbstractResourceInstance_SchedulingPriority(t *testing.T) {
+	provider := &priorityHintProvider{
+		MockProvider: &MockProvider{},
+		priorities:   map[string]int{"aws_db_instance": 10},
+	}
+
+	tests := map[string]struct {
+		addr     string
+		config   *configs.Resource
+		provider providers.Interface
+		want     int
+	}{
+		"no hints": {
+			addr:     "aws_db_instance.foo",
+			provider: &MockProvider{},
+			want:     0,
+		},
+		"provider default": {
+			addr:     "aws_db_instance.foo",
+			provider: provider,
+			want:     10,
+		},
+		"provider default for another type": {
+			addr:     "aws_instance.foo",
+			provider: provider,
+			want:     0,
+		},
+		"lifecycle priority overrides provider default": {
+			addr: "aws_db_instance.foo",
+			config: &configs.Resource{
+				Mode:    addrs.ManagedResourceMode,
+				Managed: &configs.ManagedResource{Priority: -5, PrioritySet: true},
+			},
+			provider: provider,
+			want:     -5,
+		},
+		"data resources are not prioritized": {
+			addr:     "data.aws_db_instance.foo",
+			provider: provider,
+			want:     0,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := new(MockEvalContext)
+			ctx.ProviderProvider = test.provider
+			node := &NodeAbstractResourceInstance{
+				Addr: mustResourceInstanceAddr(test.addr),
+				NodeAbstractResource: NodeAbstractResource{
+					Config:           test.config,
+					ResolvedProvider: mustProviderConfig(`provider["registry.opentofu.org/hashicorp/aws"]`),
+				},
+			}
+			if got := node.SchedulingPriority(ctx); got != test.want {
+				t.Errorf("wrong priority %d; want %d", got, test.want)
+			}
+		})
+	}
+}
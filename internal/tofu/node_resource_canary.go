@@ -0,0 +1,201 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/configs"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+var (
+	_ GraphNodeModulePath = (*nodeCanaryGate)(nil)
+	_ GraphNodeReferencer = (*nodeCanaryGate)(nil)
+	_ GraphNodeExecutable = (*nodeCanaryGate)(nil)
+)
+
+// nodeCanaryGate separates the canary instances of a resource from its other
+// instances during apply. It runs once the changes to the canary instances
+// have been applied, and evaluates the assertions of the check blocks that
+// refer to the resource. The changes to the other instances depend on this
+// node, so they are applied only if all of those assertions pass.
+type nodeCanaryGate struct {
+	addr   addrs.ConfigResource
+	keys   []addrs.InstanceKey
+	checks []*configs.Check
+}
+
+func (n *nodeCanaryGate) Name() string {
+	return n.addr.String() + " (canary)"
+}
+
+func (n *nodeCanaryGate) ModulePath() addrs.Module {
+	return n.addr.Module
+}
+
+// References returns the references of the check assertions, other than those
+// to the resource itself, so that the gate also runs after anything else the
+// checks depend on. The references to the resource are satisfied by the
+// explicit edges to the canary instances instead.
+func (n *nodeCanaryGate) References() []*addrs.Reference {
+	var refs []*addrs.Reference
+	for _, check := range n.checks {
+		check := &nodeExpandCheck{config: check}
+		for _, ref := range check.References() {
+			if referencesResource([]*addrs.Reference{ref}, n.addr.Resource) {
+				continue
+			}
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func (n *nodeCanaryGate) Execute(ctx EvalContext, _ walkOperation) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	var failed []string
+
+	for _, modAddr := range ctx.InstanceExpander().ExpandModule(n.addr.Module) {
+		modCtx := ctx.WithPath(modAddr)
+		for _, check := range n.checks {
+			checkAddr := check.Addr().Absolute(modAddr)
+			for ix, assert := range check.Asserts {
+				ruleAddr := addrs.NewCheckRule(checkAddr, addrs.CheckAssertion, ix)
+				result, ruleDiags := evalCheckRule(ruleAddr, assert, modCtx, EvalDataForNoInstanceKey, hcl.DiagError)
+				switch result.Status {
+				case checks.StatusUnknown:
+					// The assertion depends on something that isn't known
+					// until the other instances are applied, so it can't
+					// hold them back.
+					log.Printf("[TRACE] nodeCanaryGate: %s is not known yet, so not waiting for it", ruleAddr)
+				case checks.StatusFail, checks.StatusError:
+					diags = diags.Append(ruleDiags)
+					failed = append(failed, checkAddr.String())
+				default:
+					diags = diags.Append(ruleDiags)
+				}
+			}
+		}
+	}
+
+	if len(failed) != 0 {
+		keys := make([]string, len(n.keys))
+		for i, key := range n.keys {
+			keys[i] = key.String()
+		}
+		diags = diags.Append(tfdiags.Sourceless(
+			tfdiags.Error,
+			"Canary checks failed",
+			fmt.Sprintf(
+				"OpenTofu applied the changes to the canary instances %s of %s, but %s did not pass, so the changes to the other instances of %s were not applied.",
+				strings.Join(keys, ", "), n.addr, strings.Join(failed, ", "), n.addr,
+			),
+		))
+	}
+	return diags
+}
+
+// canaryTransformer adds a nodeCanaryGate for each resource that declares
+// canary instance keys, between the nodes that apply the changes to its
+// canary instances and the nodes that apply the changes to its other
+// instances.
+type canaryTransformer struct {
+	Config *configs.Config
+
+	// Operation is the current operation this transformer is part of.
+	Operation walkOperation
+}
+
+var _ GraphTransformer = (*canaryTransformer)(nil)
+
+func (t *canaryTransformer) Transform(g *Graph) error {
+	if t.Operation != walkApply {
+		return nil
+	}
+
+	gates := make(map[string]*nodeCanaryGate)
+	for _, v := range g.Vertices() {
+		n, ok := v.(*NodeApplyableResourceInstance)
+		if !ok {
+			continue
+		}
+		addr := n.ResourceInstanceAddr()
+		configAddr := addr.ContainingResource().Config()
+
+		gate, exists := gates[configAddr.String()]
+		if !exists {
+			gate = t.gateFor(configAddr)
+			if gate != nil {
+				log.Printf("[TRACE] canaryTransformer: adding %s", dag.VertexName(gate))
+				g.Add(gate)
+			}
+			gates[configAddr.String()] = gate
+		}
+		if gate == nil {
+			continue
+		}
+
+		if isCanaryKey(gate.keys, addr.Resource.Key) {
+			g.Connect(dag.BasicEdge(gate, n))
+		} else {
+			g.Connect(dag.BasicEdge(n, gate))
+		}
+	}
+	return nil
+}
+
+// gateFor returns a new gate for the given resource, or nil if the resource
+// doesn't declare any canary instance keys.
+func (t *canaryTransformer) gateFor(addr addrs.ConfigResource) *nodeCanaryGate {
+	modCfg := t.Config.Descendent(addr.Module)
+	if modCfg == nil {
+		return nil
+	}
+	rc := modCfg.Module.ResourceByAddr(addr.Resource)
+	if rc == nil || rc.Managed == nil || len(rc.Managed.Canary) == 0 {
+		return nil
+	}
+
+	gate := &nodeCanaryGate{
+		addr: addr,
+		keys: rc.Managed.Canary,
+	}
+	names := make([]string, 0, len(modCfg.Module.Checks))
+	for name := range modCfg.Module.Checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		check := modCfg.Module.Checks[name]
+		// Nested data blocks are read only after all other changes have
+		// been applied, so checks using them can't gate the other instances.
+		if check.DataResource != nil {
+			continue
+		}
+		if referencesResource((&nodeExpandCheck{config: check}).References(), addr.Resource) {
+			gate.checks = append(gate.checks, check)
+		}
+	}
+	return gate
+}
+
+func isCanaryKey(keys []addrs.InstanceKey, key addrs.InstanceKey) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
@@ -87,6 +87,11 @@ func (n *NodePlanDeposedResourceInstanceObject) References() []*addrs.Reference
 func (n *NodePlanDeposedResourceInstanceObject) Execute(ctx EvalContext, op walkOperation) (diags tfdiags.Diagnostics) {
 	log.Printf("[TRACE] NodePlanDeposedResourceInstanceObject: planning %s deposed object %s", n.Addr, n.DeposedKey)
 
+	diags = diags.Append(n.resolveProviderKey(ctx, false, n.DeposedKey))
+	if diags.HasErrors() {
+		return diags
+	}
+
 	// Read the state for the deposed resource instance
 	state, err := n.readResourceInstanceStateDeposed(ctx, n.Addr, n.DeposedKey)
 	diags = diags.Append(err)
@@ -190,6 +195,19 @@ func (n *NodePlanDeposedResourceInstanceObject) Execute(ctx EvalContext, op walk
 type NodeDestroyDeposedResourceInstanceObject struct {
 	*NodeAbstractResourceInstance
 	DeposedKey states.DeposedKey
+
+	// replacement is set if the object will be deposed during this same
+	// apply by a create_before_destroy replacement, rather than having been
+	// left deposed by an earlier run.
+	replacement bool
+
+	// healthChecks are the check blocks that must pass before the object is
+	// destroyed, if its resource uses two-phase replacement.
+	healthChecks []addrs.ConfigCheck
+
+	// autoApprove is set if the object can be destroyed without asking for
+	// confirmation once its two-phase replacement is ready and healthy.
+	autoApprove bool
 }
 
 var (
@@ -252,6 +270,11 @@ func (n *NodeDestroyDeposedResourceInstanceObject) ModifyCreateBeforeDestroy(v b
 func (n *NodeDestroyDeposedResourceInstanceObject) Execute(ctx EvalContext, op walkOperation) (diags tfdiags.Diagnostics) {
 	var change *plans.ResourceInstanceChange
 
+	diags = diags.Append(n.resolveProviderKey(ctx, false, n.DeposedKey))
+	if diags.HasErrors() {
+		return diags
+	}
+
 	// Read the state for the deposed resource instance
 	state, err := n.readResourceInstanceStateDeposed(ctx, n.Addr, n.DeposedKey)
 	if err != nil {
@@ -263,6 +286,16 @@ func (n *NodeDestroyDeposedResourceInstanceObject) Execute(ctx EvalContext, op w
 		return diags
 	}
 
+	if n.twoPhaseReplace() {
+		confirmed, confirmDiags := n.confirmTwoPhaseReplace(ctx)
+		diags = diags.Append(confirmDiags)
+		if !confirmed || diags.HasErrors() {
+			// The object remains deposed, and so will be destroyed by the
+			// next plan and apply.
+			return diags
+		}
+	}
+
 	change, destroyPlanDiags := n.planDestroy(ctx, state, n.DeposedKey)
 	diags = diags.Append(destroyPlanDiags)
 	if diags.HasErrors() {
@@ -333,7 +366,7 @@ func (n *NodeDestroyDeposedResourceInstanceObject) writeResourceInstanceState(ct
 		return nil
 	}
 
-	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	if err != nil {
 		return err
 	}
@@ -350,6 +383,8 @@ func (n *NodeDestroyDeposedResourceInstanceObject) writeResourceInstanceState(ct
 		return fmt.Errorf("failed to encode %s in state: %w", absAddr, err)
 	}
 
+	src.ProviderKey = n.ResolvedProviderKey
+
 	log.Printf("[TRACE] writeResourceInstanceStateDeposed: writing state object for %s deposed %s", absAddr, key)
 	state.SetResourceInstanceDeposed(absAddr, key, src, n.ResolvedProvider)
 	return nil
@@ -399,6 +434,11 @@ func (n *NodeForgetDeposedResourceInstanceObject) References() []*addrs.Referenc
 
 // GraphNodeExecutable impl.
 func (n *NodeForgetDeposedResourceInstanceObject) Execute(ctx EvalContext, op walkOperation) (diags tfdiags.Diagnostics) {
+
+	diags = diags.Append(n.resolveProviderKey(ctx, false, n.DeposedKey))
+	if diags.HasErrors() {
+		return diags
+	}
 	// Read the state for the deposed resource instance
 	state, err := n.readResourceInstanceStateDeposed(ctx, n.Addr, n.DeposedKey)
 	if err != nil {
@@ -47,6 +47,11 @@ func (n *NodeForgetResourceInstance) Name() string {
 func (n *NodeForgetResourceInstance) Execute(ctx EvalContext, op walkOperation) (diags tfdiags.Diagnostics) {
 	addr := n.ResourceInstanceAddr()
 
+	diags = diags.Append(n.resolveProviderKey(ctx, false, states.CurrentGen))
+	if diags.HasErrors() {
+		return diags
+	}
+
 	// Get our state
 	is := n.instanceState
 	if is == nil {
@@ -23,6 +23,10 @@ type graphNodeImportState struct {
 	ProviderAddr     addrs.AbsProviderConfig   // Provider address given by the user, or implied by the resource type
 	ResolvedProvider addrs.AbsProviderConfig   // provider node address after resolution
 
+	// ResolvedProviderKey is the instance key of the instance of
+	// ResolvedProvider to import with, when it has for_each.
+	ResolvedProviderKey addrs.InstanceKey
+
 	Schema        *configschema.Block // Schema for processing the configuration body
 	SchemaVersion uint64              // Schema version of "Schema", as decided by the provider
 	Config        *configs.Resource   // Config is the resource in the config
@@ -81,7 +85,20 @@ func (n *graphNodeImportState) Execute(ctx EvalContext, op walkOperation) (diags
 	// Reset our states
 	n.states = nil
 
-	provider, _, err := getProvider(ctx, n.ResolvedProvider)
+	riNode := &NodeAbstractResourceInstance{
+		Addr: n.Addr,
+		NodeAbstractResource: NodeAbstractResource{
+			Config:           n.Config,
+			ResolvedProvider: n.ResolvedProvider,
+		},
+	}
+	diags = diags.Append(riNode.resolveProviderKey(ctx, true, states.CurrentGen))
+	if diags.HasErrors() {
+		return diags
+	}
+	n.ResolvedProviderKey = riNode.ResolvedProviderKey
+
+	provider, _, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
 		return diags
@@ -181,12 +198,13 @@ func (n *graphNodeImportState) DynamicExpand(ctx EvalContext) (*Graph, error) {
 	// safe.
 	for i, state := range n.states {
 		g.Add(&graphNodeImportStateSub{
-			TargetAddr:       addrs[i],
-			State:            state,
-			ResolvedProvider: n.ResolvedProvider,
-			Schema:           n.Schema,
-			SchemaVersion:    n.SchemaVersion,
-			Config:           n.Config,
+			TargetAddr:          addrs[i],
+			State:               state,
+			ResolvedProvider:    n.ResolvedProvider,
+			ResolvedProviderKey: n.ResolvedProviderKey,
+			Schema:              n.Schema,
+			SchemaVersion:       n.SchemaVersion,
+			Config:              n.Config,
 		})
 	}
 
@@ -200,9 +218,10 @@ func (n *graphNodeImportState) DynamicExpand(ctx EvalContext) (*Graph, error) {
 // and is part of the subgraph. This node is responsible for refreshing
 // and adding a resource to the state once it is imported.
 type graphNodeImportStateSub struct {
-	TargetAddr       addrs.AbsResourceInstance
-	State            providers.ImportedResource
-	ResolvedProvider addrs.AbsProviderConfig
+	TargetAddr          addrs.AbsResourceInstance
+	State               providers.ImportedResource
+	ResolvedProvider    addrs.AbsProviderConfig
+	ResolvedProviderKey addrs.InstanceKey
 
 	Schema        *configschema.Block // Schema for processing the configuration body
 	SchemaVersion uint64              // Schema version of "Schema", as decided by the provider
@@ -237,7 +256,8 @@ func (n *graphNodeImportStateSub) Execute(ctx EvalContext, op walkOperation) (di
 	riNode := &NodeAbstractResourceInstance{
 		Addr: n.TargetAddr,
 		NodeAbstractResource: NodeAbstractResource{
-			ResolvedProvider: n.ResolvedProvider,
+			ResolvedProvider:    n.ResolvedProvider,
+			ResolvedProviderKey: n.ResolvedProviderKey,
 		},
 	}
 	state, refreshDiags := riNode.refresh(ctx, states.NotDeposed, state)
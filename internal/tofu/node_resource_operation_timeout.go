@@ -0,0 +1,77 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+)
+
+// operationTimeout returns the limit on how long each call to the provider
+// to read, create, update or delete this resource instance may take, from
+// the operation_timeout lifecycle argument or else the default for the
+// whole operation. Zero means there is no limit.
+func (n *NodeAbstractResourceInstance) operationTimeout(ctx EvalContext) time.Duration {
+	if n.Config != nil && n.Config.OperationTimeoutSet {
+		return n.Config.OperationTimeout
+	}
+	return ctx.OperationTimeout()
+}
+
+// callWithOperationTimeout calls the given function, which makes a call to
+// a provider, and waits for at most the given timeout for it to return. A
+// zero timeout waits indefinitely.
+//
+// The second result is true if the call timed out, in which case the first
+// result is the zero value. The call continues in the background, because
+// the provider protocol has no way to cancel a single operation, and its
+// result is discarded when it eventually returns.
+func callWithOperationTimeout[T any](timeout time.Duration, call func() T) (T, bool) {
+	if timeout <= 0 {
+		return call(), false
+	}
+
+	// The channel is buffered so that the goroutine can always exit, even
+	// after we've stopped waiting for it.
+	done := make(chan T, 1)
+	go func() {
+		done <- call()
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case resp := <-done:
+		return resp, false
+	case <-timer.C:
+		var zero T
+		return zero, true
+	}
+}
+
+// operationTimeoutDiag returns the error reported when the provider doesn't
+// complete the given operation on this resource instance within the timeout.
+// The consequence describes what OpenTofu has done with the state of the
+// object as a result.
+func (n *NodeAbstractResourceInstance) operationTimeoutDiag(operation string, timeout time.Duration, consequence string) *hcl.Diagnostic {
+	log.Printf("[ERROR] %s: provider did not complete %s within %s", n.Addr, operation, timeout)
+
+	diag := &hcl.Diagnostic{
+		Severity: hcl.DiagError,
+		Summary:  "Provider operation timed out",
+		Detail: fmt.Sprintf(
+			"Provider %q did not finish %s for %s within the operation timeout of %s.\n\n%s\n\nTo allow more time, increase the operation_timeout argument in the lifecycle block of this resource, or the operation_timeout setting in the CLI configuration.",
+			n.ResolvedProvider.Provider, operation, n.Addr, timeout, consequence,
+		),
+	}
+	if n.Config != nil {
+		diag.Subject = n.Config.DeclRange.Ptr()
+	}
+	return diag
+}
@@ -0,0 +1,208 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+// slowProviderCall is how long the provider takes to respond in the tests
+// below, which is much longer than the operation timeouts they use.
+const slowProviderCall = 200 * time.Millisecond
+
+func TestCallWithOperationTimeout(t *testing.T) {
+	got, timedOut := callWithOperationTimeout(0, func() string {
+		return "unlimited"
+	})
+	if timedOut || got != "unlimited" {
+		t.Errorf("wrong result without timeout: %q, %t", got, timedOut)
+	}
+
+	got, timedOut = callWithOperationTimeout(time.Minute, func() string {
+		return "fast"
+	})
+	if timedOut || got != "fast" {
+		t.Errorf("wrong result within timeout: %q, %t", got, timedOut)
+	}
+
+	got, timedOut = callWithOperationTimeout(time.Millisecond, func() string {
+		time.Sleep(slowProviderCall)
+		return "slow"
+	})
+	if !timedOut || got != "" {
+		t.Errorf("wrong result after timeout: %q, %t", got, timedOut)
+	}
+}
+
+func TestContext2Apply_operationTimeoutCreate(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "ok"
+
+  lifecycle {
+    operation_timeout = "10ms"
+  }
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		time.Sleep(slowProviderCall)
+		return providers.ApplyResourceChangeResponse{NewState: req.PlannedState}
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+	})
+
+	plan, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+
+	state, diags := ctx.Apply(plan, m)
+	if !diags.HasErrors() {
+		t.Fatal("apply succeeded; want timeout error")
+	}
+	if got, want := diags.Err().Error(), "Provider operation timed out"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: error containing %q", got, want)
+	}
+	if got, want := diags.Err().Error(), "creating the remote object for test_object.a within the operation timeout of 10ms"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: error containing %q", got, want)
+	}
+
+	if rs := state.ResourceInstance(mustResourceInstanceAddr("test_object.a")); rs != nil && rs.Current != nil {
+		t.Fatalf("unexpected object in state after the create timed out: %s", rs.Current.AttrsJSON)
+	}
+}
+
+func TestContext2Apply_operationTimeoutDestroy(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+resource "test_object" "a" {
+  test_string = "ok"
+}
+`,
+	})
+
+	state := states.NewState()
+	root := state.EnsureModule(addrs.RootModuleInstance)
+	root.SetResourceInstanceCurrent(
+		mustResourceInstanceAddr("test_object.a").Resource,
+		&states.ResourceInstanceObjectSrc{
+			Status:    states.ObjectReady,
+			AttrsJSON: []byte(`{"test_string":"ok"}`),
+		},
+		mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`),
+	)
+
+	p := simpleMockProvider()
+	p.ApplyResourceChangeFn = func(req providers.ApplyResourceChangeRequest) providers.ApplyResourceChangeResponse {
+		time.Sleep(slowProviderCall)
+		return providers.ApplyResourceChangeResponse{NewState: req.PlannedState}
+	}
+
+	// The resource has no operation_timeout of its own, so the default
+	// for the whole operation applies.
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		OperationTimeout: 10 * time.Millisecond,
+	})
+
+	plan, diags := ctx.Plan(m, state, &PlanOpts{
+		Mode: plans.DestroyMode,
+	})
+	assertNoErrors(t, diags)
+
+	state, diags = ctx.Apply(plan, m)
+	if !diags.HasErrors() {
+		t.Fatal("apply succeeded; want timeout error")
+	}
+	if got, want := diags.Err().Error(), "deleting the remote object for test_object.a"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: error containing %q", got, want)
+	}
+
+	// The object may still exist, so it must still be tracked.
+	rs := state.ResourceInstance(mustResourceInstanceAddr("test_object.a"))
+	if rs == nil || rs.Current == nil {
+		t.Fatal("object was removed from the state after the delete timed out")
+	}
+	if got, want := string(rs.Current.AttrsJSON), `"test_string":"ok"`; !strings.Contains(got, want) {
+		t.Fatalf("wrong object in state\ngot:  %s\nwant: object containing %s", got, want)
+	}
+}
+
+func TestContext2Plan_operationTimeoutDataSource(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+data "test_object" "a" {
+  test_string = "ok"
+
+  lifecycle {
+    operation_timeout = "10ms"
+  }
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	p.ReadDataSourceFn = func(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+		time.Sleep(slowProviderCall)
+		return providers.ReadDataSourceResponse{State: req.Config}
+	}
+
+	// The resource's own timeout takes precedence over the default.
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		OperationTimeout: time.Hour,
+	})
+
+	_, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	if !diags.HasErrors() {
+		t.Fatal("plan succeeded; want timeout error")
+	}
+	if got, want := diags.Err().Error(), "reading the data source for data.test_object.a"; !strings.Contains(got, want) {
+		t.Fatalf("wrong error\ngot:  %s\nwant: error containing %q", got, want)
+	}
+}
+
+func TestContext2Plan_operationTimeoutNotReached(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+data "test_object" "a" {
+  test_string = "ok"
+}
+`,
+	})
+
+	p := simpleMockProvider()
+	p.ReadDataSourceFn = func(req providers.ReadDataSourceRequest) providers.ReadDataSourceResponse {
+		return providers.ReadDataSourceResponse{State: req.Config}
+	}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+		},
+		OperationTimeout: time.Minute,
+	})
+
+	_, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+	assertNoErrors(t, diags)
+}
@@ -41,6 +41,15 @@ type nodeExpandPlannableResource struct {
 	// that this node represents, which the node itself must therefore ignore.
 	forceReplace []addrs.AbsResourceInstance
 
+	// refreshFilter, if non-empty, restricts refreshing to only the instances
+	// contained in at least one of these addresses. Like forceReplace, this
+	// set isn't pre-filtered for the resource this node represents.
+	refreshFilter []addrs.Targetable
+
+	// adoptExisting, if set, allows instances with no prior state to adopt
+	// a matching existing remote object found by the provider.
+	adoptExisting bool
+
 	// We attach dependencies to the Resource during refresh, since the
 	// instances are instantiated during DynamicExpand.
 	// FIXME: These would be better off converted to a generic Set data
@@ -135,7 +144,7 @@ func (n *nodeExpandPlannableResource) DynamicExpand(ctx EvalContext) (*Graph, er
 
 		return &NodePlannableResourceInstanceOrphan{
 			NodeAbstractResourceInstance: a,
-			skipRefresh:                  n.skipRefresh,
+			skipRefresh:                  skipRefreshForInstance(n.skipRefresh, n.refreshFilter, a.Addr),
 			skipPlanChanges:              n.skipPlanChanges,
 		}
 	}
@@ -362,9 +371,10 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext,
 			// to force on CreateBeforeDestroy due to dependencies on other
 			// nodes that have it.
 			ForceCreateBeforeDestroy: n.CreateBeforeDestroy(),
-			skipRefresh:              n.skipRefresh,
+			skipRefresh:              skipRefreshForInstance(n.skipRefresh, n.refreshFilter, a.Addr),
 			skipPlanChanges:          n.skipPlanChanges,
 			forceReplace:             n.forceReplace,
+			adoptExisting:            n.adoptExisting,
 		}
 
 		resolvedImportTarget := ctx.ImportResolver().GetImport(a.Addr)
@@ -386,7 +396,7 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext,
 
 		return &NodePlannableResourceInstanceOrphan{
 			NodeAbstractResourceInstance: a,
-			skipRefresh:                  n.skipRefresh,
+			skipRefresh:                  skipRefreshForInstance(n.skipRefresh, n.refreshFilter, a.Addr),
 			skipPlanChanges:              n.skipPlanChanges,
 		}
 	}
@@ -430,3 +440,20 @@ func (n *nodeExpandPlannableResource) resourceInstanceSubgraph(ctx EvalContext,
 	graph, diags := b.Build(addr.Module)
 	return graph, diags.ErrWithWarnings()
 }
+
+// skipRefreshForInstance decides whether refreshing should be skipped for
+// the resource instance with the given address, taking into account both
+// the global skipRefresh setting and the user's requested refresh filter.
+//
+// An empty filter means that all instances are eligible for refreshing.
+func skipRefreshForInstance(skipRefresh bool, filter []addrs.Targetable, addr addrs.AbsResourceInstance) bool {
+	if skipRefresh || len(filter) == 0 {
+		return skipRefresh
+	}
+	for _, candidate := range filter {
+		if candidate.TargetContains(addr) {
+			return false
+		}
+	}
+	return true
+}
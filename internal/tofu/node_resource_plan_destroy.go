@@ -47,6 +47,11 @@ func (n *NodePlanDestroyableResourceInstance) DestroyAddr() *addrs.AbsResourceIn
 func (n *NodePlanDestroyableResourceInstance) Execute(ctx EvalContext, op walkOperation) (diags tfdiags.Diagnostics) {
 	addr := n.ResourceInstanceAddr()
 
+	diags = diags.Append(n.resolveProviderKey(ctx, false, states.CurrentGen))
+	if diags.HasErrors() {
+		return diags
+	}
+
 	switch addr.Resource.Resource.Mode {
 	case addrs.ManagedResourceMode:
 		return n.managedResourceExecute(ctx, op)
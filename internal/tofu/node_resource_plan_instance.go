@@ -10,6 +10,7 @@ import (
 	"log"
 	"path/filepath"
 	"sort"
+	"time"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclsyntax"
@@ -53,6 +54,11 @@ type NodePlannableResourceInstance struct {
 	// importTarget, if populated, contains the information necessary to plan
 	// an import of this resource.
 	importTarget EvaluatedConfigImportTarget
+
+	// adoptExisting, if set, asks the provider to search for an existing
+	// remote object to import if this instance has no prior state and no
+	// import target.
+	adoptExisting bool
 }
 
 // EvaluatedConfigImportTarget is a target that we need to import. It's created when an import target originated from
@@ -86,6 +92,10 @@ var (
 func (n *NodePlannableResourceInstance) Execute(ctx EvalContext, op walkOperation) tfdiags.Diagnostics {
 	addr := n.ResourceInstanceAddr()
 
+	if diags := n.resolveProviderKey(ctx, true, states.CurrentGen); diags.HasErrors() {
+		return diags
+	}
+
 	// Eval info is different depending on what kind of resource this is
 	switch addr.Resource.Resource.Mode {
 	case addrs.ManagedResourceMode:
@@ -103,7 +113,7 @@ func (n *NodePlannableResourceInstance) dataResourceExecute(ctx EvalContext) (di
 
 	var change *plans.ResourceInstanceChange
 
-	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	_, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
 		return diags
@@ -164,7 +174,7 @@ func (n *NodePlannableResourceInstance) managedResourceExecute(ctx EvalContext)
 		checkRuleSeverity = tfdiags.Warning
 	}
 
-	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
 		return diags
@@ -177,6 +187,21 @@ func (n *NodePlannableResourceInstance) managedResourceExecute(ctx EvalContext)
 		}
 	}
 
+	if n.adoptExisting && n.importTarget.ID == "" && config != nil && ctx.State().ResourceInstance(addr) == nil {
+		id, findDiags := n.findExisting(ctx, provider, providerSchema)
+		diags = diags.Append(findDiags)
+		if diags.HasErrors() {
+			return diags
+		}
+		if id != "" {
+			log.Printf("[DEBUG] NodePlannableResourceInstance: adopting existing object %q for %s", id, addr)
+			n.importTarget = EvaluatedConfigImportTarget{
+				Addr: addr,
+				ID:   id,
+			}
+		}
+	}
+
 	importing := n.shouldImport(ctx)
 
 	if importing && n.Config == nil && len(n.generateConfigPath) == 0 {
@@ -465,10 +490,12 @@ func (n *NodePlannableResourceInstance) importState(ctx EvalContext, addr addrs.
 		return nil, diags
 	}
 
+	callStart := time.Now()
 	resp := provider.ImportResourceState(providers.ImportResourceStateRequest{
 		TypeName: addr.Resource.Resource.Type,
 		ID:       importId,
 	})
+	n.providerCallDone(ctx, "ImportResourceState", callStart)
 	diags = diags.Append(resp.Diagnostics)
 	if diags.HasErrors() {
 		return nil, diags
@@ -635,6 +662,41 @@ func (n *NodePlannableResourceInstance) importState(ctx EvalContext, addr addrs.
 	return instanceRefreshState, diags
 }
 
+// findExisting asks the provider, if it supports it, for the import ID of an
+// existing remote object matching the configuration of this resource
+// instance, returning the empty string if there is no such object.
+func (n *NodePlannableResourceInstance) findExisting(ctx EvalContext, provider providers.Interface, providerSchema providers.ProviderSchema) (string, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+
+	finder, ok := providers.AsResourceFinder(provider)
+	if !ok {
+		return "", diags
+	}
+
+	schema, _ := providerSchema.SchemaForResourceAddr(n.Addr.Resource.Resource)
+	if schema == nil {
+		// Should be caught during validation, so we don't bother with a pretty error here
+		diags = diags.Append(fmt.Errorf("provider does not support resource type for %q", n.Addr))
+		return "", diags
+	}
+
+	forEach, _ := evaluateForEachExpression(n.Config.ForEach, ctx)
+	keyData := EvalDataForInstanceKey(n.ResourceInstanceAddr().Resource.Key, forEach)
+	configVal, _, configDiags := ctx.EvaluateBlock(n.Config.Config, schema, nil, keyData)
+	diags = diags.Append(configDiags)
+	if configDiags.HasErrors() {
+		return "", diags
+	}
+	unmarkedConfigVal, _ := configVal.UnmarkDeep()
+
+	resp := finder.FindResource(providers.FindResourceRequest{
+		TypeName: n.Addr.Resource.Resource.Type,
+		Config:   unmarkedConfigVal,
+	})
+	diags = diags.Append(resp.Diagnostics.InConfigBody(n.Config.Config, n.Addr.String()))
+	return resp.ID, diags
+}
+
 func (n *NodePlannableResourceInstance) shouldImport(ctx EvalContext) bool {
 	if n.importTarget.ID == "" {
 		return false
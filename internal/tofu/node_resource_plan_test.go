@@ -0,0 +1,105 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"testing"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+)
+
+func TestSkipRefreshForInstance(t *testing.T) {
+	mustTarget := func(s string) addrs.Targetable {
+		target, diags := addrs.ParseTargetStr(s)
+		if diags.HasErrors() {
+			t.Fatalf("invalid target %q: %s", s, diags.Err())
+		}
+		return target.Subject
+	}
+
+	tests := map[string]struct {
+		skipRefresh bool
+		filter      []string
+		addr        string
+		want        bool
+	}{
+		"no filter": {
+			addr: "test_object.a",
+			want: false,
+		},
+		"no filter, refresh disabled": {
+			skipRefresh: true,
+			addr:        "test_object.a",
+			want:        true,
+		},
+		"refresh disabled overrides matching filter": {
+			skipRefresh: true,
+			filter:      []string{"test_object.a"},
+			addr:        "test_object.a",
+			want:        true,
+		},
+		"resource filter matches instance": {
+			filter: []string{"test_object.a"},
+			addr:   `test_object.a["foo"]`,
+			want:   false,
+		},
+		"resource filter excludes other resource": {
+			filter: []string{"test_object.a"},
+			addr:   "test_object.b",
+			want:   true,
+		},
+		"instance filter matches same instance": {
+			filter: []string{"test_object.a[1]"},
+			addr:   "test_object.a[1]",
+			want:   false,
+		},
+		"instance filter excludes sibling instance": {
+			filter: []string{"test_object.a[1]"},
+			addr:   "test_object.a[0]",
+			want:   true,
+		},
+		"module filter matches nested resource": {
+			filter: []string{"module.child"},
+			addr:   "module.child.module.grandchild.test_object.a",
+			want:   false,
+		},
+		"module filter matches all module instances": {
+			filter: []string{"module.child"},
+			addr:   `module.child["x"].test_object.a`,
+			want:   false,
+		},
+		"module instance filter excludes other module instance": {
+			filter: []string{`module.child["x"]`},
+			addr:   `module.child["y"].test_object.a`,
+			want:   true,
+		},
+		"module filter excludes root resource": {
+			filter: []string{"module.child"},
+			addr:   "test_object.a",
+			want:   true,
+		},
+		"any of several filters": {
+			filter: []string{"test_object.b", "module.child"},
+			addr:   "module.child.test_object.a",
+			want:   false,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var filter []addrs.Targetable
+			for _, s := range test.filter {
+				filter = append(filter, mustTarget(s))
+			}
+			addr := mustResourceInstanceAddr(test.addr)
+
+			got := skipRefreshForInstance(test.skipRefresh, filter, addr)
+			if got != test.want {
+				t.Errorf("wrong result for %s\ngot:  %t\nwant: %t", addr, got, test.want)
+			}
+		})
+	}
+}
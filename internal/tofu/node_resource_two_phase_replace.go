@@ -0,0 +1,251 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/checks"
+	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// These control how often we ask the provider whether the new object of a
+// two-phase replacement is ready, and how long we wait for it unless the
+// resource sets two_phase_replace_timeout. They are variables only so that
+// tests can override them.
+var (
+	twoPhaseReplaceReadyInterval = 5 * time.Second
+	twoPhaseReplaceReadyTimeout  = 10 * time.Minute
+)
+
+// twoPhaseReplace returns true if destroying this object must wait for the
+// replacement object to be healthy and for the user to confirm.
+func (n *NodeDestroyDeposedResourceInstanceObject) twoPhaseReplace() bool {
+	return n.replacement && n.Config != nil && n.Config.Managed != nil && n.Config.Managed.TwoPhaseReplace
+}
+
+// confirmTwoPhaseReplace waits for the object that replaced this deposed
+// object to become ready, checks that any check blocks referring to the
+// resource passed, and then asks the user to confirm destroying the deposed
+// object, unless the plan was approved in advance. It returns true only if
+// the deposed object should be destroyed now.
+func (n *NodeDestroyDeposedResourceInstanceObject) confirmTwoPhaseReplace(ctx EvalContext) (bool, tfdiags.Diagnostics) {
+	var diags tfdiags.Diagnostics
+	addr := n.ResourceInstanceAddr()
+
+	if ctx.State().ResourceInstanceObject(addr, states.CurrentGen) == nil {
+		// The replacement object wasn't created, so there's nothing to
+		// take over from the previous object.
+		log.Printf("[TRACE] NodeDestroyDeposedResourceInstanceObject: %s has no replacement object, so keeping deposed object %s", addr, n.DeposedKey)
+		return false, diags
+	}
+
+	diags = diags.Append(n.waitReplacementReady(ctx))
+	if diags.HasErrors() {
+		return false, diags
+	}
+
+	diags = diags.Append(n.replacementHealthChecks(ctx))
+	if diags.HasErrors() {
+		return false, diags
+	}
+
+	notDestroyed := func(reason string) tfdiags.Diagnostic {
+		return tfdiags.Sourceless(
+			tfdiags.Warning,
+			"Previous object not destroyed",
+			fmt.Sprintf("The previous object for %s was not destroyed, because %s. It remains in the state as deposed object %s, and OpenTofu will propose destroying it in the next plan.", addr, reason, n.DeposedKey),
+		)
+	}
+
+	if n.autoApprove {
+		log.Printf("[TRACE] NodeDestroyDeposedResourceInstanceObject: the plan was approved in advance, so destroying deposed object %s for %s without confirmation", n.DeposedKey, addr)
+		return true, diags
+	}
+	input := ctx.Input()
+	if input == nil {
+		diags = diags.Append(notDestroyed("input is disabled, so OpenTofu cannot ask for confirmation"))
+		return false, diags
+	}
+	// Other nodes can run while we wait for an answer.
+	var v string
+	var err error
+	ctx.WhileWaiting(func() {
+		v, err = input.Input(context.TODO(), &InputOpts{
+			Id:    fmt.Sprintf("two-phase-replace.%s.%s", addr, n.DeposedKey),
+			Query: fmt.Sprintf("Destroy the previous object for %s?", addr),
+			Description: "OpenTofu has created the replacement object and it is ready.\n" +
+				"Only 'yes' will be accepted to destroy the previous object.",
+		})
+	})
+	if err != nil {
+		diags = diags.Append(notDestroyed(fmt.Sprintf("OpenTofu could not ask for confirmation: %s", err)))
+		return false, diags
+	}
+	if v != "yes" {
+		diags = diags.Append(notDestroyed("destroying it was not confirmed"))
+		return false, diags
+	}
+	return true, diags
+}
+
+// waitReplacementReady polls the provider, if it supports readiness checks,
+// until it reports that the current object of the resource instance is ready.
+func (n *NodeDestroyDeposedResourceInstanceObject) waitReplacementReady(ctx EvalContext) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	addr := n.ResourceInstanceAddr()
+
+	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider, n.ResolvedProviderKey)
+	if err != nil {
+		return diags.Append(err)
+	}
+	checker, ok := providers.AsResourceReadinessChecker(provider)
+	if !ok {
+		return diags
+	}
+
+	schema, _ := providerSchema.SchemaForResourceAddr(addr.Resource.Resource)
+	if schema == nil {
+		// Should be caught during validation, so we don't bother with a pretty error here
+		return diags.Append(fmt.Errorf("provider does not support resource type for %q", addr))
+	}
+	src := ctx.State().ResourceInstanceObject(addr, states.CurrentGen)
+	obj, err := src.Decode(schema.ImpliedType())
+	if err != nil {
+		return diags.Append(err)
+	}
+	val, _ := obj.Value.UnmarkDeep()
+
+	timeout := twoPhaseReplaceReadyTimeout
+	if n.Config.Managed.TwoPhaseReplaceTimeoutSet {
+		timeout = n.Config.Managed.TwoPhaseReplaceTimeout
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		resp := checker.CheckResourceReadiness(providers.CheckResourceReadinessRequest{
+			TypeName: addr.Resource.Resource.Type,
+			State:    val,
+		})
+		diags = diags.Append(resp.Diagnostics.InConfigBody(n.Config.Config, addr.String()))
+		if resp.Ready || diags.HasErrors() {
+			return diags
+		}
+		if time.Now().After(deadline) {
+			return diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Replacement object not ready",
+				fmt.Sprintf("The replacement object for %s did not become ready within %s, so OpenTofu has not destroyed the previous object. It remains in the state as deposed object %s.", addr, timeout, n.DeposedKey),
+			))
+		}
+		log.Printf("[TRACE] NodeDestroyDeposedResourceInstanceObject: waiting for replacement object for %s to become ready", addr)
+		// Other nodes can run while we wait to check again.
+		stopped := false
+		ctx.WhileWaiting(func() {
+			select {
+			case <-time.After(twoPhaseReplaceReadyInterval):
+			case <-ctx.Stopped():
+				stopped = true
+			}
+		})
+		if stopped {
+			return diags.Append(fmt.Errorf("stopped while waiting for the replacement object for %s to become ready", addr))
+		}
+	}
+}
+
+// replacementHealthChecks returns an error if any of the check blocks that
+// refer to the resource failed.
+func (n *NodeDestroyDeposedResourceInstanceObject) replacementHealthChecks(ctx EvalContext) tfdiags.Diagnostics {
+	var diags tfdiags.Diagnostics
+	checkState := ctx.Checks()
+
+	for _, configAddr := range n.healthChecks {
+		if !checkState.ConfigHasChecks(configAddr) {
+			continue
+		}
+		switch checkState.AggregateCheckStatus(configAddr) {
+		case checks.StatusFail, checks.StatusError:
+			var msgs []string
+			for _, objAddr := range checkState.ObjectAddrs(configAddr) {
+				msgs = append(msgs, checkState.ObjectFailureMessages(objAddr)...)
+			}
+			detail := ""
+			if len(msgs) != 0 {
+				detail = ":\n  - " + strings.Join(msgs, "\n  - ")
+			}
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Replacement object is not healthy",
+				fmt.Sprintf("OpenTofu has not destroyed the previous object for %s, because %s failed%s\n\nThe previous object remains in the state as deposed object %s.", n.Addr, configAddr, detail, n.DeposedKey),
+			))
+		}
+	}
+	return diags
+}
+
+// twoPhaseReplaceTransformer makes the destroy node for the previous object of
+// each two-phase replacement depend on any check blocks that refer to the
+// replaced resource, so that those checks can confirm that the new object is
+// healthy before the previous object is destroyed.
+//
+// It also records on each of those destroy nodes whether the plan was
+// approved in advance, so that they don't ask for confirmation.
+type twoPhaseReplaceTransformer struct {
+	autoApprove bool
+}
+
+var _ GraphTransformer = (*twoPhaseReplaceTransformer)(nil)
+
+func (t *twoPhaseReplaceTransformer) Transform(g *Graph) error {
+	var checkNodes []*nodeExpandCheck
+	for _, v := range g.Vertices() {
+		if n, ok := v.(*nodeExpandCheck); ok {
+			checkNodes = append(checkNodes, n)
+		}
+	}
+
+	for _, v := range g.Vertices() {
+		n, ok := v.(*NodeDestroyDeposedResourceInstanceObject)
+		if !ok || !n.twoPhaseReplace() {
+			continue
+		}
+		n.autoApprove = t.autoApprove
+		resourceAddr := n.ResourceAddr()
+		for _, check := range checkNodes {
+			if !check.ModulePath().Equal(resourceAddr.Module) || !referencesResource(check.References(), resourceAddr.Resource) {
+				continue
+			}
+			log.Printf("[TRACE] twoPhaseReplaceTransformer: %s waits for %s", dag.VertexName(n), check.addr)
+			g.Connect(dag.BasicEdge(n, check))
+			n.healthChecks = append(n.healthChecks, check.addr)
+		}
+	}
+	return nil
+}
+
+func referencesResource(refs []*addrs.Reference, addr addrs.Resource) bool {
+	for _, ref := range refs {
+		switch subject := ref.Subject.(type) {
+		case addrs.Resource:
+			if subject.Equal(addr) {
+				return true
+			}
+		case addrs.ResourceInstance:
+			if subject.Resource.Equal(addr) {
+				return true
+			}
+		}
+	}
+	return false
+}
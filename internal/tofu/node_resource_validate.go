@@ -282,7 +282,9 @@ var connectionBlockSupersetSchema = &configschema.Block{
 func (n *NodeValidatableResource) validateResource(ctx EvalContext) tfdiags.Diagnostics {
 	var diags tfdiags.Diagnostics
 
-	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider)
+	// Resources are validated with the unconfigured provider instance that
+	// doesn't belong to any instance of a provider configuration with for_each.
+	provider, providerSchema, err := getProvider(ctx, n.ResolvedProvider, addrs.NoKey)
 	diags = diags.Append(err)
 	if diags.HasErrors() {
 		return diags
@@ -367,7 +369,7 @@ func (n *NodeValidatableResource) validateResource(ctx EvalContext) tfdiags.Diag
 					suggestions = append(suggestions, name)
 				}
 				if suggestion = didyoumean.NameSuggestion(n.Config.Type, suggestions); suggestion != "" {
-					suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+					suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
 				}
 			}
 
@@ -385,6 +387,7 @@ func (n *NodeValidatableResource) validateResource(ctx EvalContext) tfdiags.Diag
 		if valDiags.HasErrors() {
 			return diags
 		}
+		diags = diags.Append(validateResourceConfigNotEphemeral(configVal).InConfigBody(n.Config.Config, n.Addr.String()))
 
 		if n.Config.Managed != nil { // can be nil only in tests with poorly-configured mocks
 			for _, traversal := range n.Config.Managed.IgnoreChanges {
@@ -441,7 +444,7 @@ func (n *NodeValidatableResource) validateResource(ctx EvalContext) tfdiags.Diag
 					suggestions = append(suggestions, name)
 				}
 				if suggestion = didyoumean.NameSuggestion(n.Config.Type, suggestions); suggestion != "" {
-					suggestion = fmt.Sprintf(" Did you mean %q?", suggestion)
+					suggestion = fmt.Sprintf("\n\nDid you mean %q?", suggestion)
 				}
 			}
 
@@ -459,6 +462,7 @@ func (n *NodeValidatableResource) validateResource(ctx EvalContext) tfdiags.Diag
 		if valDiags.HasErrors() {
 			return diags
 		}
+		diags = diags.Append(validateResourceConfigNotEphemeral(configVal).InConfigBody(n.Config.Config, n.Addr.String()))
 
 		// Use unmarked value for validate request
 		unmarkedConfigVal, _ := configVal.UnmarkDeep()
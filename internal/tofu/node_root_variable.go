@@ -67,6 +67,10 @@ func (n *NodeRootVariable) References() []*addrs.Reference {
 		}
 	}
 
+	// Root module variables are evaluated in the root module, so the
+	// references to other variables can be included directly.
+	refs = append(refs, variableValidationReferences(n.Config)...)
+
 	return refs
 }
 
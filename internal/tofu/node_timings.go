@@ -0,0 +1,76 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// NodeTimings records the wall time spent executing each graph node, across
+// all of the graph walks of the contexts that it's given to in ContextOpts,
+// so that the slowest nodes can be reported when profiling.
+//
+// The time a node spends waiting for the parallelism semaphore isn't
+// included.
+type NodeTimings struct {
+	mu    sync.Mutex
+	nodes map[string]*NodeTiming
+}
+
+// NodeTiming is the total time spent executing the graph nodes with a
+// particular name.
+type NodeTiming struct {
+	Name     string
+	Duration time.Duration
+
+	// Count is the number of times a node with this name was executed,
+	// which can be more than one if there are several graph walks.
+	Count int
+}
+
+// NewNodeTimings returns an empty NodeTimings.
+func NewNodeTimings() *NodeTimings {
+	return &NodeTimings{
+		nodes: make(map[string]*NodeTiming),
+	}
+}
+
+func (t *NodeTimings) record(name string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	node, ok := t.nodes[name]
+	if !ok {
+		node = &NodeTiming{Name: name}
+		t.nodes[name] = node
+	}
+	node.Duration += d
+	node.Count++
+}
+
+// Top returns the n nodes with the longest total wall time, in descending
+// order, or all of them if n is zero or negative.
+func (t *NodeTimings) Top(n int) []NodeTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ret := make([]NodeTiming, 0, len(t.nodes))
+	for _, node := range t.nodes {
+		ret = append(ret, *node)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Duration != ret[j].Duration {
+			return ret[i].Duration > ret[j].Duration
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	if n > 0 && len(ret) > n {
+		ret = ret[:n]
+	}
+	return ret
+}
@@ -0,0 +1,32 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNodeTimings_Top(t *testing.T) {
+	timings := NewNodeTimings()
+	timings.record("a", time.Second)
+	timings.record("b", 3*time.Second)
+	timings.record("a", 3*time.Second)
+	timings.record("c", 2*time.Second)
+
+	want := []NodeTiming{
+		{Name: "a", Duration: 4 * time.Second, Count: 2},
+		{Name: "b", Duration: 3 * time.Second, Count: 1},
+	}
+	if diff := cmp.Diff(want, timings.Top(2)); diff != "" {
+		t.Errorf("wrong result\n%s", diff)
+	}
+	if got := len(timings.Top(0)); got != 3 {
+		t.Errorf("wrong number of nodes %d, want 3", got)
+	}
+}
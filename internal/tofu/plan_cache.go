@@ -0,0 +1,279 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	ctymsgpack "github.com/zclconf/go-cty/cty/msgpack"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/providers"
+)
+
+// PlanCache is a cache of the responses of providers to PlanResourceChange
+// requests, kept from an earlier plan so that identical requests in a later
+// plan can be answered without calling the provider again. It doesn't prune
+// anything from the graph walk: every node is still visited, and only the
+// call to the provider is skipped.
+//
+// A response is only reused for a request that is identical in every way to
+// the one that produced it: the same provider, configured in the same way,
+// the same resource type, and the same configuration, prior state, proposed
+// new state, private data and provider_meta. The configuration and proposed
+// new state include the values of any upstream resources that the resource
+// instance refers to, so a change upstream also leads to a new request.
+// Expressions are still evaluated in full, and the state of each remote
+// object is still refreshed unless refreshing is disabled.
+//
+// This assumes that providers plan deterministically, which is true of most
+// but not all of them, and so a PlanCache is only used when requested.
+type PlanCache struct {
+	mu sync.Mutex
+
+	// entries are the responses read from an earlier plan, and used are
+	// those that were reused or newly recorded in this one, which are the
+	// only ones that are written out again.
+	entries map[string]*planCacheEntry
+	used    map[string]*planCacheEntry
+
+	// providerConfigs are the digests of the configuration of each provider
+	// instance, by the key from providerInstanceCacheKey.
+	providerConfigs map[string]string
+
+	hits, misses int
+}
+
+type planCacheEntry struct {
+	Type             json.RawMessage `json:"type"`
+	PlannedState     []byte          `json:"planned_state"`
+	PlannedPrivate   []byte          `json:"planned_private,omitempty"`
+	LegacyTypeSystem bool            `json:"legacy_type_system,omitempty"`
+}
+
+// planCacheFile is the JSON representation of a PlanCache.
+type planCacheFile struct {
+	Version     int                        `json:"version"`
+	Fingerprint string                     `json:"fingerprint"`
+	Entries     map[string]*planCacheEntry `json:"entries"`
+}
+
+const planCacheFormatVersion = 1
+
+// NewPlanCache returns an empty PlanCache.
+func NewPlanCache() *PlanCache {
+	return &PlanCache{
+		entries:         make(map[string]*planCacheEntry),
+		used:            make(map[string]*planCacheEntry),
+		providerConfigs: make(map[string]string),
+	}
+}
+
+// ReadPlanCache reads a PlanCache that was written by PlanCache.Write.
+//
+// The fingerprint identifies everything outside of the requests themselves
+// that could change how providers respond, such as the versions of
+// OpenTofu and of the providers. If it doesn't match the one the cache was
+// written with then an empty PlanCache is returned instead.
+func ReadPlanCache(r io.Reader, fingerprint string) (*PlanCache, error) {
+	var file planCacheFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid plan cache: %w", err)
+	}
+
+	ret := NewPlanCache()
+	if file.Version != planCacheFormatVersion || file.Fingerprint != fingerprint {
+		log.Printf("[TRACE] PlanCache: discarding cache from a different version or with different providers")
+		return ret, nil
+	}
+	for key, entry := range file.Entries {
+		if entry != nil {
+			ret.entries[key] = entry
+		}
+	}
+	return ret, nil
+}
+
+// Write writes the responses that were reused or recorded since the cache
+// was created, along with the given fingerprint.
+func (c *PlanCache) Write(w io.Writer, fingerprint string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	enc := json.NewEncoder(w)
+	return enc.Encode(&planCacheFile{
+		Version:     planCacheFormatVersion,
+		Fingerprint: fingerprint,
+		Entries:     c.used,
+	})
+}
+
+// Stats returns the number of requests that were answered from the cache
+// and the number that had to be sent to providers.
+func (c *PlanCache) Stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// configureProvider records the configuration of the given provider
+// instance, which is part of the key of each of its requests.
+func (c *PlanCache) configureProvider(addr addrs.AbsProviderConfig, key addrs.InstanceKey, cfg cty.Value) {
+	if !cfg.IsWhollyKnown() {
+		// Requests to this provider instance won't be cached.
+		return
+	}
+	h := sha256.New()
+	if err := writePlanCacheValue(h, cfg); err != nil {
+		// Requests to this provider instance won't be cached.
+		log.Printf("[TRACE] PlanCache: can't cache requests to %s: %s", providerInstanceCacheKey(addr, key), err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.providerConfigs[providerInstanceCacheKey(addr, key)] = hex.EncodeToString(h.Sum(nil))
+}
+
+// planResourceChange returns the cached response to the given request if
+// there is one, or otherwise sends it to the given provider and caches the
+// response if possible.
+func (c *PlanCache) planResourceChange(addr addrs.AbsProviderConfig, key addrs.InstanceKey, provider providers.Interface, req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+	cacheKey, ok := c.requestKey(addr, key, req)
+	if !ok {
+		return provider.PlanResourceChange(req)
+	}
+
+	c.mu.Lock()
+	entry, ok := c.used[cacheKey]
+	if !ok {
+		entry, ok = c.entries[cacheKey]
+	}
+	c.mu.Unlock()
+
+	if ok {
+		resp, err := entry.response()
+		if err == nil {
+			c.mu.Lock()
+			c.hits++
+			c.used[cacheKey] = entry
+			c.mu.Unlock()
+			return resp
+		}
+		log.Printf("[WARN] PlanCache: ignoring invalid entry for %s: %s", req.TypeName, err)
+	}
+
+	resp := provider.PlanResourceChange(req)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.misses++
+	// Responses with diagnostics or that require replacement are not
+	// cached, so that their diagnostics and the paths that require
+	// replacement don't need to be preserved.
+	if len(resp.Diagnostics) > 0 || len(resp.RequiresReplace) > 0 || resp.PlannedState == cty.NilVal {
+		return resp
+	}
+	entry, err := newPlanCacheEntry(resp)
+	if err != nil {
+		log.Printf("[TRACE] PlanCache: can't cache response for %s: %s", req.TypeName, err)
+		return resp
+	}
+	c.used[cacheKey] = entry
+	return resp
+}
+
+// requestKey returns the key of the given request in the cache, or false if
+// the request can't be cached.
+//
+// Requests that include unknown values aren't cached, because the digest of
+// an unknown value doesn't include its refinements, and because they
+// usually follow from changes upstream that make reuse unlikely anyway.
+func (c *PlanCache) requestKey(addr addrs.AbsProviderConfig, key addrs.InstanceKey, req providers.PlanResourceChangeRequest) (string, bool) {
+	c.mu.Lock()
+	providerConfig, ok := c.providerConfigs[providerInstanceCacheKey(addr, key)]
+	c.mu.Unlock()
+	if !ok {
+		return "", false
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", addr.Provider, providerConfig, req.TypeName)
+	for _, v := range []cty.Value{req.Config, req.PriorState, req.ProposedNewState, req.ProviderMeta} {
+		if v != cty.NilVal && !v.IsWhollyKnown() {
+			return "", false
+		}
+		if err := writePlanCacheValue(h, v); err != nil {
+			return "", false
+		}
+	}
+	fmt.Fprintf(h, "%d\x00", len(req.PriorPrivate))
+	h.Write(req.PriorPrivate)
+	return hex.EncodeToString(h.Sum(nil)), true
+}
+
+// writePlanCacheValue writes an unambiguous representation of the given
+// value, including its type, to the given hash.
+func writePlanCacheValue(h hash.Hash, v cty.Value) error {
+	if v == cty.NilVal {
+		h.Write([]byte("nil\x00"))
+		return nil
+	}
+	ty, err := ctyjson.MarshalType(v.Type())
+	if err != nil {
+		return err
+	}
+	raw, err := ctymsgpack.Marshal(v, v.Type())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(h, "%d\x00", len(ty))
+	h.Write(ty)
+	fmt.Fprintf(h, "%d\x00", len(raw))
+	h.Write(raw)
+	return nil
+}
+
+func newPlanCacheEntry(resp providers.PlanResourceChangeResponse) (*planCacheEntry, error) {
+	ty, err := ctyjson.MarshalType(resp.PlannedState.Type())
+	if err != nil {
+		return nil, err
+	}
+	raw, err := ctymsgpack.Marshal(resp.PlannedState, resp.PlannedState.Type())
+	if err != nil {
+		return nil, err
+	}
+	return &planCacheEntry{
+		Type:             ty,
+		PlannedState:     raw,
+		PlannedPrivate:   resp.PlannedPrivate,
+		LegacyTypeSystem: resp.LegacyTypeSystem,
+	}, nil
+}
+
+func (e *planCacheEntry) response() (providers.PlanResourceChangeResponse, error) {
+	var resp providers.PlanResourceChangeResponse
+	ty, err := ctyjson.UnmarshalType(e.Type)
+	if err != nil {
+		return resp, err
+	}
+	resp.PlannedState, err = ctymsgpack.Unmarshal(e.PlannedState, ty)
+	if err != nil {
+		return resp, err
+	}
+	resp.PlannedPrivate = e.PlannedPrivate
+	resp.LegacyTypeSystem = e.LegacyTypeSystem
+	return resp, nil
+}
@@ -0,0 +1,103 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+)
+
+func TestContext2Plan_planCache(t *testing.T) {
+	p := simpleMockProvider()
+	var mu sync.Mutex
+	var planned []string
+	p.PlanResourceChangeFn = func(req providers.PlanResourceChangeRequest) providers.PlanResourceChangeResponse {
+		mu.Lock()
+		defer mu.Unlock()
+		planned = append(planned, req.Config.GetAttr("test_string").AsString())
+		return providers.PlanResourceChangeResponse{PlannedState: req.ProposedNewState}
+	}
+
+	plan := func(t *testing.T, cache *PlanCache, b string) *plans.Plan {
+		t.Helper()
+		m := testModuleInline(t, map[string]string{
+			"main.tf": `
+resource "test_object" "a" {
+  test_string = "a"
+}
+
+resource "test_object" "b" {
+  test_string = "` + b + `"
+}
+`,
+		})
+		ctx := testContext2(t, &ContextOpts{
+			Providers: map[addrs.Provider]providers.Factory{
+				addrs.NewDefaultProvider("test"): testProviderFuncFixed(p),
+			},
+			PlanCache: cache,
+		})
+		plan, diags := ctx.Plan(m, states.NewState(), DefaultPlanOpts)
+		assertNoErrors(t, diags)
+		return plan
+	}
+
+	cache := NewPlanCache()
+	plan(t, cache, "b")
+	if hits, misses := cache.Stats(); hits != 0 || misses != 2 {
+		t.Fatalf("wrong stats for first plan: %d hits, %d misses", hits, misses)
+	}
+
+	var buf bytes.Buffer
+	if err := cache.Write(&buf, "v1"); err != nil {
+		t.Fatal(err)
+	}
+	cache, err := ReadPlanCache(bytes.NewReader(buf.Bytes()), "v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	planned = nil
+	got := plan(t, cache, "c")
+	if hits, misses := cache.Stats(); hits != 1 || misses != 1 {
+		t.Fatalf("wrong stats for second plan: %d hits, %d misses", hits, misses)
+	}
+	if len(planned) != 1 || planned[0] != "c" {
+		t.Fatalf("wrong requests sent to the provider: %q", planned)
+	}
+	for _, change := range got.Changes.Resources {
+		want := cty.StringVal(change.Addr.Resource.Resource.Name)
+		if change.Addr.Resource.Resource.Name == "b" {
+			want = cty.StringVal("c")
+		}
+		after, err := change.After.Decode(simpleTestSchema().ImpliedType())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !after.GetAttr("test_string").RawEquals(want) {
+			t.Errorf("wrong planned value for %s: %#v", change.Addr, after.GetAttr("test_string"))
+		}
+	}
+
+	// A cache written with a different fingerprint is discarded.
+	cache, err = ReadPlanCache(bytes.NewReader(buf.Bytes()), "v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	planned = nil
+	plan(t, cache, "b")
+	if len(planned) != 2 {
+		t.Fatalf("wrong requests sent to the provider: %q", planned)
+	}
+}
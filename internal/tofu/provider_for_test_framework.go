@@ -31,6 +31,10 @@ type providerForTest struct {
 
 	overrideValues map[string]cty.Value
 	plannedChange  *cty.Value
+
+	// generate generates the computed values of the resource instance that
+	// are not in overrideValues, if the override has generators.
+	generate hcl2shim.MockValueGenerateFunc
 }
 
 func (p providerForTest) ReadResource(r providers.ReadResourceRequest) providers.ReadResourceResponse {
@@ -38,7 +42,7 @@ func (p providerForTest) ReadResource(r providers.ReadResourceRequest) providers
 
 	resSchema, _ := p.schema.SchemaForResourceType(addrs.ManagedResourceMode, r.TypeName)
 
-	resp.NewState, resp.Diagnostics = hcl2shim.ComposeMockValueBySchema(resSchema, r.ProviderMeta, p.overrideValues)
+	resp.NewState, resp.Diagnostics = hcl2shim.ComposeMockValueBySchemaWithGenerator(resSchema, r.ProviderMeta, p.overrideValues, p.generate)
 	return resp
 }
 
@@ -59,7 +63,7 @@ func (p providerForTest) PlanResourceChange(r providers.PlanResourceChangeReques
 
 	var resp providers.PlanResourceChangeResponse
 
-	resp.PlannedState, resp.Diagnostics = hcl2shim.ComposeMockValueBySchema(resSchema, r.Config, p.overrideValues)
+	resp.PlannedState, resp.Diagnostics = hcl2shim.ComposeMockValueBySchemaWithGenerator(resSchema, r.Config, p.overrideValues, p.generate)
 
 	return resp
 }
@@ -75,7 +79,7 @@ func (p providerForTest) ReadDataSource(r providers.ReadDataSourceRequest) provi
 
 	var resp providers.ReadDataSourceResponse
 
-	resp.State, resp.Diagnostics = hcl2shim.ComposeMockValueBySchema(resSchema, r.Config, p.overrideValues)
+	resp.State, resp.Diagnostics = hcl2shim.ComposeMockValueBySchemaWithGenerator(resSchema, r.Config, p.overrideValues, p.generate)
 
 	return resp
 }
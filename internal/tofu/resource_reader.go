@@ -0,0 +1,219 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+const (
+	// defaultReadBatchWindow is how long a bulk read batch stays open to
+	// collect more requests after its first request arrives.
+	defaultReadBatchWindow = 10 * time.Millisecond
+
+	// defaultReadBatchSize is the maximum number of requests sent to a
+	// provider in a single bulk read call.
+	defaultReadBatchSize = 100
+)
+
+// resourceReaders coordinates the ReadResource calls made while refreshing
+// resource instances during a graph walk.
+//
+// Calls are grouped by provider so that an optional per-provider concurrency
+// limit can be enforced independently of the global graph walk parallelism,
+// and so that calls for providers implementing providers.BulkResourceReader
+// can be sent together in batches.
+type resourceReaders struct {
+	// limits are the maximum number of concurrent read calls for each
+	// provider, across all configurations of that provider. Providers that
+	// are not included are limited only by the overall walk parallelism.
+	limits map[addrs.Provider]int
+
+	batchWindow time.Duration
+	batchSize   int
+
+	mu         sync.Mutex
+	semaphores map[addrs.Provider]Semaphore
+	batchers   map[string]*readBatcher
+}
+
+func newResourceReaders(limits map[addrs.Provider]int) *resourceReaders {
+	return &resourceReaders{
+		limits:      limits,
+		batchWindow: defaultReadBatchWindow,
+		batchSize:   defaultReadBatchSize,
+		semaphores:  make(map[addrs.Provider]Semaphore),
+		batchers:    make(map[string]*readBatcher),
+	}
+}
+
+// ReadResource sends the given request to the given provider, which must be
+// the provider instance for the provider configuration with the given address
+// and instance key.
+func (r *resourceReaders) ReadResource(addr addrs.AbsProviderConfig, key addrs.InstanceKey, provider providers.Interface, req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	sem := r.semaphore(addr.Provider)
+
+	if bulk, ok := providers.AsBulkResourceReader(provider); ok {
+		return r.batcher(addr, key, bulk, sem).read(req)
+	}
+
+	if sem != nil {
+		sem.Acquire()
+		defer sem.Release()
+	}
+	return provider.ReadResource(req)
+}
+
+func (r *resourceReaders) semaphore(provider addrs.Provider) Semaphore {
+	limit := r.limits[provider]
+	if limit <= 0 {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sem, ok := r.semaphores[provider]
+	if !ok {
+		sem = NewSemaphore(limit)
+		r.semaphores[provider] = sem
+	}
+	return sem
+}
+
+func (r *resourceReaders) batcher(addr addrs.AbsProviderConfig, instanceKey addrs.InstanceKey, provider providers.BulkResourceReader, sem Semaphore) *readBatcher {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Each instance of a provider configuration has its own provider
+	// instance, so batches are per configuration instance rather than per
+	// provider.
+	key := providerInstanceCacheKey(addr, instanceKey)
+	b, ok := r.batchers[key]
+	if !ok {
+		b = &readBatcher{
+			addr:     addr,
+			provider: provider,
+			sem:      sem,
+			window:   r.batchWindow,
+			size:     r.batchSize,
+		}
+		r.batchers[key] = b
+	}
+	return b
+}
+
+// readBatcher collects concurrent read requests for a single provider
+// instance and sends them to the provider using bulk read calls.
+type readBatcher struct {
+	addr     addrs.AbsProviderConfig
+	provider providers.BulkResourceReader
+	sem      Semaphore
+	window   time.Duration
+	size     int
+
+	mu      sync.Mutex
+	pending []*pendingRead
+}
+
+type pendingRead struct {
+	req  providers.ReadResourceRequest
+	resp chan providers.ReadResourceResponse
+}
+
+func (b *readBatcher) read(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	p := &pendingRead{
+		req:  req,
+		resp: make(chan providers.ReadResourceResponse, 1),
+	}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, p)
+	switch {
+	case len(b.pending) >= b.size:
+		batch := b.pending
+		b.pending = nil
+		b.mu.Unlock()
+		go b.send(batch)
+	case len(b.pending) == 1:
+		// This is the first request of a new batch, so we'll wait a short
+		// time to give other concurrent reads a chance to join it.
+		b.mu.Unlock()
+		time.AfterFunc(b.window, b.flush)
+	default:
+		b.mu.Unlock()
+	}
+
+	return <-p.resp
+}
+
+func (b *readBatcher) flush() {
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.mu.Unlock()
+
+	if len(batch) != 0 {
+		b.send(batch)
+	}
+}
+
+func (b *readBatcher) send(batch []*pendingRead) {
+	if b.sem != nil {
+		b.sem.Acquire()
+		defer b.sem.Release()
+	}
+
+	req := providers.ReadResourcesRequest{
+		Requests: make([]providers.ReadResourceRequest, len(batch)),
+	}
+	for i, p := range batch {
+		req.Requests[i] = p.req
+	}
+
+	log.Printf("[TRACE] readBatcher: reading %d resource instances in bulk from %s", len(batch), b.addr)
+	resp := b.provider.ReadResources(req)
+
+	if !resp.Diagnostics.HasErrors() && len(resp.Responses) != len(batch) {
+		resp.Diagnostics = resp.Diagnostics.Append(fmt.Errorf(
+			"provider %s returned %d results for a bulk read of %d resource instances; this is a bug in the provider, which should be reported in the provider's own issue tracker",
+			b.addr.Provider, len(resp.Responses), len(batch),
+		))
+	}
+
+	if resp.Diagnostics.HasErrors() {
+		// The errors are about the bulk call as a whole, so we report them
+		// only once, alongside the first request in the batch. The other
+		// requests just need to fail, without repeating them.
+		batch[0].resp <- providers.ReadResourceResponse{Diagnostics: resp.Diagnostics}
+		for _, p := range batch[1:] {
+			var diags tfdiags.Diagnostics
+			diags = diags.Append(tfdiags.Sourceless(
+				tfdiags.Error,
+				"Failed to read resource instance",
+				fmt.Sprintf("This resource instance was read from %s together with %d others in a single request, which failed with the error reported separately.", b.addr, len(batch)-1),
+			))
+			p.resp <- providers.ReadResourceResponse{Diagnostics: diags}
+		}
+		return
+	}
+
+	for i, p := range batch {
+		r := resp.Responses[i]
+		// Warnings about the bulk call as a whole are reported only once,
+		// alongside the first request in the batch.
+		if i == 0 {
+			r.Diagnostics = resp.Diagnostics.Append(r.Diagnostics)
+		}
+		p.resp <- r
+	}
+}
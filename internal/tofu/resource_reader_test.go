@@ -0,0 +1,282 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/opentofu/opentofu/internal/addrs"
+	"github.com/opentofu/opentofu/internal/configs/configschema"
+	"github.com/opentofu/opentofu/internal/plans"
+	"github.com/opentofu/opentofu/internal/providers"
+	"github.com/opentofu/opentofu/internal/states"
+	"github.com/opentofu/opentofu/internal/tfdiags"
+)
+
+// bulkReadMockProvider is a MockProvider that also supports bulk reads,
+// recording the size of each bulk read call it receives.
+type bulkReadMockProvider struct {
+	*MockProvider
+
+	mu         sync.Mutex
+	batchSizes []int
+	bulkDiags  tfdiags.Diagnostics
+}
+
+var _ providers.BulkResourceReader = (*bulkReadMockProvider)(nil)
+
+func (p *bulkReadMockProvider) ReadResources(req providers.ReadResourcesRequest) providers.ReadResourcesResponse {
+	p.mu.Lock()
+	p.batchSizes = append(p.batchSizes, len(req.Requests))
+	p.mu.Unlock()
+
+	var resp providers.ReadResourcesResponse
+	if p.bulkDiags.HasErrors() {
+		resp.Diagnostics = p.bulkDiags
+		return resp
+	}
+	for _, r := range req.Requests {
+		resp.Responses = append(resp.Responses, providers.ReadResourceResponse{
+			NewState: r.PriorState,
+		})
+	}
+	return resp
+}
+
+// concurrentReadProvider is a MockProvider whose ReadResource method can be
+// called concurrently.
+type concurrentReadProvider struct {
+	*MockProvider
+
+	readFn func(providers.ReadResourceRequest) providers.ReadResourceResponse
+}
+
+func (p *concurrentReadProvider) ReadResource(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+	return p.readFn(req)
+}
+
+func TestResourceReaders_concurrencyLimit(t *testing.T) {
+	providerAddr := mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`)
+	readers := newResourceReaders(map[addrs.Provider]int{
+		providerAddr.Provider: 2,
+	})
+
+	var mu sync.Mutex
+	var current, highest int
+	// MockProvider serializes all of its calls, so we override ReadResource
+	// to be able to observe concurrent calls.
+	p := &concurrentReadProvider{MockProvider: &MockProvider{}}
+	p.readFn = func(req providers.ReadResourceRequest) providers.ReadResourceResponse {
+		mu.Lock()
+		current++
+		if current > highest {
+			highest = current
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		current--
+		mu.Unlock()
+		return providers.ReadResourceResponse{NewState: req.PriorState}
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			readers.ReadResource(providerAddr, addrs.NoKey, p, providers.ReadResourceRequest{
+				TypeName:   "test_object",
+				PriorState: cty.EmptyObjectVal,
+			})
+		}()
+	}
+	wg.Wait()
+
+	if highest > 2 {
+		t.Errorf("provider received %d concurrent reads; want at most 2", highest)
+	}
+	if highest == 0 {
+		t.Errorf("provider received no reads")
+	}
+}
+
+func TestResourceReaders_bulkRead(t *testing.T) {
+	providerAddr := mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`)
+	readers := newResourceReaders(nil)
+	// Use a generous window so that all of the concurrent requests below
+	// land in the same batch even on a slow machine.
+	readers.batchWindow = 100 * time.Millisecond
+
+	p := &bulkReadMockProvider{MockProvider: &MockProvider{}}
+
+	var wg sync.WaitGroup
+	results := make([]providers.ReadResourceResponse, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = readers.ReadResource(providerAddr, addrs.NoKey, p, providers.ReadResourceRequest{
+				TypeName:   "test_object",
+				PriorState: cty.ObjectVal(map[string]cty.Value{"index": cty.NumberIntVal(int64(i))}),
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if p.ReadResourceCalled {
+		t.Errorf("provider's ReadResource was called; all reads should've used ReadResources")
+	}
+	total := 0
+	for _, size := range p.batchSizes {
+		total += size
+	}
+	if total != 5 {
+		t.Errorf("provider received %d requests in bulk; want 5", total)
+	}
+	if len(p.batchSizes) != 1 {
+		t.Errorf("provider received %d bulk read calls; want 1", len(p.batchSizes))
+	}
+	for i, resp := range results {
+		got := resp.NewState.GetAttr("index")
+		if !got.RawEquals(cty.NumberIntVal(int64(i))) {
+			t.Errorf("wrong result for request %d: %#v", i, got)
+		}
+	}
+}
+
+func TestResourceReaders_bulkReadBatchSize(t *testing.T) {
+	providerAddr := mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`)
+	readers := newResourceReaders(nil)
+	readers.batchSize = 1
+
+	p := &bulkReadMockProvider{MockProvider: &MockProvider{}}
+	for i := 0; i < 3; i++ {
+		readers.ReadResource(providerAddr, addrs.NoKey, p, providers.ReadResourceRequest{
+			TypeName:   "test_object",
+			PriorState: cty.EmptyObjectVal,
+		})
+	}
+
+	if got, want := len(p.batchSizes), 3; got != want {
+		t.Errorf("wrong number of bulk read calls %d; want %d", got, want)
+	}
+}
+
+func TestResourceReaders_bulkReadError(t *testing.T) {
+	providerAddr := mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`)
+	readers := newResourceReaders(nil)
+
+	readers.batchWindow = 100 * time.Millisecond
+
+	p := &bulkReadMockProvider{MockProvider: &MockProvider{}}
+	p.bulkDiags = p.bulkDiags.Append(errors.New("bulk read failed"))
+
+	var wg sync.WaitGroup
+	results := make([]providers.ReadResourceResponse, 3)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = readers.ReadResource(providerAddr, addrs.NoKey, p, providers.ReadResourceRequest{
+				TypeName:   "test_object",
+				PriorState: cty.EmptyObjectVal,
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if len(p.batchSizes) != 1 {
+		t.Fatalf("provider received %d bulk read calls; want 1", len(p.batchSizes))
+	}
+	reported := 0
+	for i, resp := range results {
+		if !resp.Diagnostics.HasErrors() {
+			t.Errorf("read %d succeeded; want an error", i)
+			continue
+		}
+		if strings.Contains(resp.Diagnostics.Err().Error(), "bulk read failed") {
+			reported++
+		}
+	}
+	if reported != 1 {
+		t.Errorf("the bulk read error was reported %d times; want once", reported)
+	}
+}
+
+func TestContext2Plan_refreshBulkRead(t *testing.T) {
+	m := testModuleInline(t, map[string]string{
+		"main.tf": `
+			resource "test_object" "a" {
+				count = 4
+				arg   = "a"
+			}
+		`,
+	})
+	state := states.BuildState(func(s *states.SyncState) {
+		for i := 0; i < 4; i++ {
+			s.SetResourceInstanceCurrent(mustResourceInstanceAddr("test_object.a").ContainingResource().Instance(addrs.IntKey(i)), &states.ResourceInstanceObjectSrc{
+				AttrsJSON: []byte(`{"arg":"a"}`),
+				Status:    states.ObjectReady,
+			}, mustProviderConfig(`provider["registry.opentofu.org/hashicorp/test"]`))
+		}
+	})
+
+	mock := simpleMockProvider()
+	mock.GetProviderSchemaResponse = &providers.GetProviderSchemaResponse{
+		Provider: providers.Schema{Block: simpleTestSchema()},
+		ResourceTypes: map[string]providers.Schema{
+			"test_object": {
+				Block: &configschema.Block{
+					Attributes: map[string]*configschema.Attribute{
+						"arg": {Type: cty.String, Optional: true},
+					},
+				},
+			},
+		},
+	}
+	p := &bulkReadMockProvider{MockProvider: mock}
+
+	ctx := testContext2(t, &ContextOpts{
+		Providers: map[addrs.Provider]providers.Factory{
+			addrs.NewDefaultProvider("test"): func() (providers.Interface, error) {
+				return p, nil
+			},
+		},
+	})
+
+	plan, diags := ctx.Plan(m, state, &PlanOpts{
+		Mode: plans.NormalMode,
+		RefreshParallelism: map[addrs.Provider]int{
+			addrs.NewDefaultProvider("test"): 1,
+		},
+	})
+	assertNoErrors(t, diags)
+
+	if mock.ReadResourceCalled {
+		t.Errorf("provider's ReadResource was called; all reads should've used ReadResources")
+	}
+	total := 0
+	for _, size := range p.batchSizes {
+		total += size
+	}
+	if total != 4 {
+		t.Errorf("provider received %d requests in bulk; want 4", total)
+	}
+	for _, rc := range plan.Changes.Resources {
+		if rc.Action != plans.NoOp {
+			t.Errorf("unexpected %s change for %s", rc.Action, rc.Addr)
+		}
+	}
+}
@@ -167,6 +167,11 @@ func loadProvisionerSchemas(schemas map[string]*configschema.Block, config *conf
 				ensure(pc.Type)
 			}
 		}
+		for _, rc := range config.Module.Removed {
+			for _, pc := range rc.Provisioners {
+				ensure(pc.Type)
+			}
+		}
 
 		// Must also visit our child modules, recursively.
 		for _, cc := range config.Children {
@@ -0,0 +1,35 @@
+// Copyright (c) The OpenTofu Authors
+// SPDX-License-Identifier: MPL-2.0
+// Copyright (c) 2023 HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package tofu
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+var graphNodes metric.Int64Histogram
+
+func init() {
+	meter := otel.Meter("github.com/opentofu/opentofu/internal/tofu")
+	graphNodes, _ = meter.Int64Histogram(
+		"tofu.graph.nodes",
+		metric.WithDescription("Number of nodes in each graph that is walked."),
+		metric.WithUnit("{node}"),
+		metric.WithExplicitBucketBoundaries(10, 50, 100, 250, 500, 1000, 2500, 5000, 10000, 25000, 50000),
+	)
+}
+
+// recordGraphNodes records the size of a graph about to be walked for the
+// given operation.
+func recordGraphNodes(graph *Graph, operation walkOperation) {
+	graphNodes.Record(context.Background(), int64(len(graph.Vertices())), metric.WithAttributes(
+		attribute.String("operation", strings.ToLower(strings.TrimPrefix(operation.String(), "walk"))),
+	))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/opentofu/opentofu/internal/addrs"
 	"github.com/opentofu/opentofu/internal/configs"
 	"github.com/opentofu/opentofu/internal/dag"
+	"github.com/opentofu/opentofu/internal/refactoring"
 )
 
 // GraphNodeAttachResourceConfig is an interface that must be implemented by nodes
@@ -22,10 +23,24 @@ type GraphNodeAttachResourceConfig interface {
 	AttachResourceConfig(*configs.Resource)
 }
 
+// GraphNodeAttachRemovedProvisioners is an interface that must be implemented
+// by nodes that want the destroy-time provisioners from a "removed" block
+// attached when their resource has no configuration.
+type GraphNodeAttachRemovedProvisioners interface {
+	GraphNodeConfigResource
+
+	// Sets the provisioners
+	AttachRemovedProvisioners([]*configs.Provisioner)
+}
+
 // AttachResourceConfigTransformer goes through the graph and attaches
 // resource configuration structures to nodes that implement
 // GraphNodeAttachManagedResourceConfig or GraphNodeAttachDataResourceConfig.
 //
+// Nodes for resources that have no configuration but are the subject of a
+// "removed" block with provisioners instead have those provisioners attached,
+// if they implement GraphNodeAttachRemovedProvisioners.
+//
 // The attached configuration structures are directly from the configuration.
 // If they're going to be modified, a copy should be made.
 type AttachResourceConfigTransformer struct {
@@ -33,6 +48,7 @@ type AttachResourceConfigTransformer struct {
 }
 
 func (t *AttachResourceConfigTransformer) Transform(g *Graph) error {
+	removedProvisioners := refactoring.FindRemovedProvisioners(t.Config)
 
 	// Go through and find GraphNodeAttachResource
 	for _, v := range g.Vertices() {
@@ -45,6 +61,13 @@ func (t *AttachResourceConfigTransformer) Transform(g *Graph) error {
 		// Determine what we're looking for
 		addr := arn.ResourceAddr()
 
+		if provs, ok := removedProvisioners[addr.String()]; ok {
+			if rpn, ok := v.(GraphNodeAttachRemovedProvisioners); ok {
+				log.Printf("[TRACE] AttachResourceConfigTransformer: attaching provisioners from removed block to %q (%T)", dag.VertexName(v), v)
+				rpn.AttachRemovedProvisioners(provs)
+			}
+		}
+
 		// Get the configuration.
 		config := t.Config.Descendent(addr.Module)
 		if config == nil {
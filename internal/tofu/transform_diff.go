@@ -203,6 +203,7 @@ func (t *DiffTransformer) Transform(g *Graph) error {
 				node = &NodeDestroyDeposedResourceInstanceObject{
 					NodeAbstractResourceInstance: abstract,
 					DeposedKey:                   dk,
+					replacement:                  createBeforeDestroy,
 				}
 			}
 			if dk == states.NotDeposed {
@@ -52,6 +52,17 @@ type GraphNodeRootReferencer interface {
 	RootReferences() []*addrs.Reference
 }
 
+// GraphNodeSelfModuleReferencer is implemented by nodes that make references
+// within the module where they are declared, in addition to the references
+// returned by References that GraphNodeReferenceOutside causes to be
+// interpreted in another module. For example, the validation rules of a
+// module input variable can refer to the other variables of its module.
+type GraphNodeSelfModuleReferencer interface {
+	GraphNodeReferencer
+
+	SelfModuleReferences() []*addrs.Reference
+}
+
 type GraphNodeAttachDependencies interface {
 	GraphNodeConfigResource
 	AttachDependencies([]addrs.ConfigResource)
@@ -311,6 +322,12 @@ func (m ReferenceMap) References(v dag.Vertex) []dag.Vertex {
 		}
 	}
 
+	if srn, ok := rn.(GraphNodeSelfModuleReferencer); ok {
+		for _, ref := range srn.SelfModuleReferences() {
+			matches = append(matches, m.addReference(rn.ModulePath(), v, ref)...)
+		}
+	}
+
 	for _, ref := range rn.References() {
 		matches = append(matches, m.addReference(vertexReferencePath(v), v, ref)...)
 	}
@@ -318,6 +335,50 @@ func (m ReferenceMap) References(v dag.Vertex) []dag.Vertex {
 	return matches
 }
 
+// referencesTo returns the references made by the given vertex, including
+// those in depends_on, which resolve to the target vertex. This allows
+// explaining an edge between two vertices in terms of the configuration.
+func (m ReferenceMap) referencesTo(v, target dag.Vertex) []*addrs.Reference {
+	rn, ok := v.(GraphNodeReferencer)
+	if !ok {
+		return nil
+	}
+
+	var refs []*addrs.Reference
+	add := func(ref *addrs.Reference, vertices []dag.Vertex) {
+		for _, rv := range vertices {
+			if rv == target {
+				refs = append(refs, ref)
+				return
+			}
+		}
+	}
+
+	if rrn, ok := rn.(GraphNodeRootReferencer); ok {
+		for _, ref := range rrn.RootReferences() {
+			add(ref, m.addReference(addrs.RootModule, v, ref))
+		}
+	}
+
+	if srn, ok := rn.(GraphNodeSelfModuleReferencer); ok {
+		for _, ref := range srn.SelfModuleReferences() {
+			add(ref, m.addReference(rn.ModulePath(), v, ref))
+		}
+	}
+
+	for _, ref := range rn.References() {
+		add(ref, m.addReference(vertexReferencePath(v), v, ref))
+	}
+
+	if depender, ok := rn.(graphNodeDependsOn); ok {
+		for _, ref := range depender.DependsOn() {
+			add(ref, m[m.referenceMapKey(depender, ref.Subject)])
+		}
+	}
+
+	return refs
+}
+
 // addReferences returns the set of vertices that the given reference requires
 // within a given module.  It additionally excludes the current vertex.
 func (m ReferenceMap) addReference(path addrs.Module, current dag.Vertex, ref *addrs.Reference) []dag.Vertex {
@@ -5,6 +5,11 @@
 
 package tofu
 
+import (
+	"container/heap"
+	"sync"
+)
+
 // Semaphore is a wrapper around a channel to provide
 // utility methods to clarify that we are treating the
 // channel as a semaphore
@@ -46,3 +51,85 @@ func (s Semaphore) Release() {
 		panic("release without an acquire")
 	}
 }
+
+// prioritySemaphore is a semaphore whose waiters are granted slots in order
+// of priority, highest first, and then in the order they started waiting.
+type prioritySemaphore struct {
+	mu      sync.Mutex
+	free    int
+	seq     uint64
+	waiters priorityWaiters
+}
+
+func newPrioritySemaphore(n int) *prioritySemaphore {
+	if n <= 0 {
+		panic("semaphore with limit <=0")
+	}
+	return &prioritySemaphore{free: n}
+}
+
+// Acquire is used to acquire an available slot, blocking until one is
+// available and no waiter with a higher priority remains.
+func (s *prioritySemaphore) Acquire(priority int) {
+	s.mu.Lock()
+	if s.free > 0 && len(s.waiters) == 0 {
+		s.free--
+		s.mu.Unlock()
+		return
+	}
+	w := &priorityWaiter{
+		priority: priority,
+		seq:      s.seq,
+		ready:    make(chan struct{}),
+	}
+	s.seq++
+	heap.Push(&s.waiters, w)
+	s.mu.Unlock()
+
+	<-w.ready
+}
+
+// Release is used to return a slot, passing it directly to the waiter with
+// the highest priority if there is one. Acquire must be called as a
+// pre-condition.
+func (s *prioritySemaphore) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.waiters) != 0 {
+		w := heap.Pop(&s.waiters).(*priorityWaiter)
+		close(w.ready)
+		return
+	}
+	s.free++
+}
+
+type priorityWaiter struct {
+	priority int
+	seq      uint64
+	ready    chan struct{}
+}
+
+// priorityWaiters implements heap.Interface.
+type priorityWaiters []*priorityWaiter
+
+func (ws priorityWaiters) Len() int { return len(ws) }
+
+func (ws priorityWaiters) Less(i, j int) bool {
+	if ws[i].priority != ws[j].priority {
+		return ws[i].priority > ws[j].priority
+	}
+	return ws[i].seq < ws[j].seq
+}
+
+func (ws priorityWaiters) Swap(i, j int) { ws[i], ws[j] = ws[j], ws[i] }
+
+func (ws *priorityWaiters) Push(x any) { *ws = append(*ws, x.(*priorityWaiter)) }
+
+func (ws *priorityWaiters) Pop() any {
+	old := *ws
+	n := len(old)
+	w := old[n-1]
+	old[n-1] = nil
+	*ws = old[:n-1]
+	return w
+}
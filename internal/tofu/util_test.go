@@ -6,6 +6,8 @@
 package tofu
 
 import (
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 )
@@ -36,3 +38,52 @@ func TestSemaphore(t *testing.T) {
 	}()
 	s.Release()
 }
+
+func TestPrioritySemaphore(t *testing.T) {
+	s := newPrioritySemaphore(1)
+	timer := time.AfterFunc(5*time.Second, func() {
+		panic("deadlock")
+	})
+	defer timer.Stop()
+
+	s.Acquire(0)
+
+	var mu sync.Mutex
+	var order []int
+	var wg sync.WaitGroup
+	for i, priority := range []int{1, 3, 2, 3} {
+		wg.Add(1)
+		go func(priority int) {
+			defer wg.Done()
+			s.Acquire(priority)
+			mu.Lock()
+			order = append(order, priority)
+			mu.Unlock()
+			s.Release()
+		}(priority)
+
+		// Wait for the goroutine to start waiting, so that the waiters are
+		// queued in a known order.
+		for {
+			s.mu.Lock()
+			n := len(s.waiters)
+			s.mu.Unlock()
+			if n == i+1 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	s.Release()
+	wg.Wait()
+
+	want := []int{3, 3, 2, 1}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("wrong order %v; want %v", order, want)
+	}
+
+	// All of the slots are free again, so this should not block.
+	s.Acquire(0)
+	s.Release()
+}
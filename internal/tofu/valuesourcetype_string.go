@@ -17,6 +17,7 @@ func _() {
 	_ = x[ValueFromInput-73]
 	_ = x[ValueFromPlan-80]
 	_ = x[ValueFromCaller-83]
+	_ = x[ValueFromWorkspace-87]
 }
 
 const (
@@ -28,6 +29,7 @@ const (
 	_ValueSourceType_name_5 = "ValueFromNamedFile"
 	_ValueSourceType_name_6 = "ValueFromPlan"
 	_ValueSourceType_name_7 = "ValueFromCaller"
+	_ValueSourceType_name_8 = "ValueFromWorkspace"
 )
 
 var (
@@ -53,6 +55,8 @@ func (i ValueSourceType) String() string {
 		return _ValueSourceType_name_6
 	case i == 83:
 		return _ValueSourceType_name_7
+	case i == 87:
+		return _ValueSourceType_name_8
 	default:
 		return "ValueSourceType(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
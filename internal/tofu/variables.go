@@ -107,6 +107,10 @@ const (
 	// ValueFromCaller indicates that the value was explicitly overridden by
 	// a caller to Context.SetVariable after the context was constructed.
 	ValueFromCaller ValueSourceType = 'S'
+
+	// ValueFromWorkspace indicates that the value was stored with the
+	// workspace using "tofu workspace vars set".
+	ValueFromWorkspace ValueSourceType = 'W'
 )
 
 func (v *InputValue) GoString() string {
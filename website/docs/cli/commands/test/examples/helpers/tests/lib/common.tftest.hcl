@@ -0,0 +1,12 @@
+# Variables, overrides, and providers declared here apply to every test file
+# that imports this helper.
+variables {
+  name = "OpenTofu"
+}
+
+assertions "greeting_is_friendly" {
+  assert {
+    condition     = startswith(output.greeting, "Hello ")
+    error_message = "Unfriendly greeting: ${output.greeting}"
+  }
+}
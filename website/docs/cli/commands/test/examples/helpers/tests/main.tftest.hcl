@@ -0,0 +1,19 @@
+imports = ["common"]
+
+# The before_each block applies to every run block below.
+before_each {
+  assertions = ["greeting_is_friendly"]
+}
+
+run "imported_name" {
+  assert {
+    condition     = output.greeting == "Hello OpenTofu!"
+    error_message = "Incorrect greeting: ${output.greeting}"
+  }
+}
+
+run "own_name" {
+  variables {
+    name = "OpenTofu user"
+  }
+}